@@ -1,9 +1,10 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	osuser "os/user"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -14,7 +15,9 @@ import (
 	"github.com/abbott/hardn/pkg/infrastructure"
 	"github.com/abbott/hardn/pkg/interfaces"
 	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/menu"
 	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/session"
 	"github.com/abbott/hardn/pkg/style"
 	"github.com/abbott/hardn/pkg/version"
 )
@@ -33,6 +36,7 @@ var (
 	dryRun              bool
 	createUser          bool
 	disableRootSSH      bool
+	removeInsecure      bool
 	installLinux        bool
 	installPython       bool
 	installAll          bool
@@ -46,14 +50,24 @@ var (
 	debugUpdates        bool
 	testUpdateAvailable bool
 	testSecurityUpdate  bool
+	overrideWindow      bool
+	forceUnsupported    bool
+	watchdog            bool
+	offline             bool
+	recordSession       string
+	logLevel            string
 	cfg                 *config.Config
 )
 
+// exitDeferred is returned when changes are deferred to the next maintenance
+// window (EX_TEMPFAIL), so schedulers can retry later
+const exitDeferred = 75
+
 // Create provider as a global for dependency injection
 var provider = interfaces.NewProvider()
 
 func main() {
-	logging.InitLogging("/var/log/hardn.log")
+	logging.InitLogging(config.DefaultLogLocation())
 
 	// Ensure config directory and example config exist
 	if err := config.EnsureExampleConfigExists(); err != nil {
@@ -62,7 +76,15 @@ func main() {
 	}
 
 	// Execute command
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	if err == nil && watchdog {
+		err = cmd.GuardRun(configFile)
+	}
+	if confirmErr := cmd.ConfirmSSHChanges(); confirmErr != nil {
+		err = errors.Join(err, confirmErr)
+	}
+	cmd.ReportRun(err)
+	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -71,21 +93,61 @@ func main() {
 func init() {
 	// Set version for help output
 	rootCmd.Version = Version
+	cmd.SetVersion(Version)
 
 	// if rootCmd.Version != "" {
 	// 	logging.LogInfo("Current version :::: : %s", rootCmd.Version)
 	// }
 
 	// Setup color processing before command execution
-	cobra.OnInitialize(initializeColor)
+	cobra.OnInitialize(initializeColor, initializeLogLevel, initializeDebugModules, initializeOSDetection, initializeNetwork)
 
 	rootCmd.AddCommand(setupSudoEnvCmd)
 	rootCmd.AddCommand(cmd.SystemDetailsCmd())
+	rootCmd.AddCommand(cmd.WSLCmd())
+	rootCmd.AddCommand(cmd.ProxmoxCmd())
+	rootCmd.AddCommand(cmd.BootCmd())
+	rootCmd.AddCommand(cmd.TimeSyncCmd())
+	rootCmd.AddCommand(cmd.LogShippingCmd())
+	rootCmd.AddCommand(cmd.RollbackCmd())
+	rootCmd.AddCommand(cmd.ReportCmd())
+	rootCmd.AddCommand(cmd.PolicyCmd())
+	rootCmd.AddCommand(cmd.AuditCmd(Version))
+	rootCmd.AddCommand(cmd.ComplianceCmd())
+	rootCmd.AddCommand(cmd.EvidenceCmd(Version))
+	rootCmd.AddCommand(cmd.ImportCmd())
+	rootCmd.AddCommand(cmd.ProfileCmd())
+	rootCmd.AddCommand(cmd.ApplyCmd())
+	rootCmd.AddCommand(cmd.FactsCmd())
+	rootCmd.AddCommand(cmd.StatusCmd())
+	rootCmd.AddCommand(cmd.HistoryCmd())
+	rootCmd.AddCommand(cmd.SummaryCmd())
+	rootCmd.AddCommand(cmd.RemoteCmd())
+	rootCmd.AddCommand(cmd.ExplainCmd())
+	rootCmd.AddCommand(cmd.SchemaCmd())
+	rootCmd.AddCommand(cmd.SSHCmd())
+	rootCmd.AddCommand(cmd.FirewallCmd())
+	rootCmd.AddCommand(cmd.TCPWrappersCmd())
+	rootCmd.AddCommand(cmd.BannerCmd())
+	rootCmd.AddCommand(cmd.BackupCmd())
+	rootCmd.AddCommand(cmd.ConfigCmd())
+	rootCmd.AddCommand(cmd.UserCmd())
+	rootCmd.AddCommand(cmd.PasswordPolicyCmd())
+	rootCmd.AddCommand(cmd.ModulesCmd())
+	rootCmd.AddCommand(cmd.DNSCmd())
+	rootCmd.AddCommand(cmd.MACCmd())
+	rootCmd.AddCommand(cmd.SourcesCmd())
+	rootCmd.AddCommand(cmd.ScheduleCmd())
+	rootCmd.AddCommand(cmd.SelfTestCmd())
+	rootCmd.AddCommand(cmd.ReplayCmd(Version))
+	rootCmd.AddCommand(cmd.CheckUpdateCmd(version.NewService(Version, BuildDate, GitCommit)))
+	rootCmd.AddCommand(cmd.SelfUpdateCmd(version.NewService(Version, BuildDate, GitCommit)))
 
 	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "f", "", "Specify configuration file path")
 	rootCmd.PersistentFlags().StringVarP(&username, "username", "u", "", "Specify username to create")
 	rootCmd.PersistentFlags().BoolVarP(&createUser, "create-user", "c", false, "Create non-root user with sudo access")
 	rootCmd.PersistentFlags().BoolVarP(&disableRootSSH, "disable-root", "d", false, "Disable root SSH access")
+	rootCmd.PersistentFlags().BoolVar(&removeInsecure, "remove-insecure-packages", false, "Remove the insecure packages of debloat.packages and disable their services")
 	// rootCmd.PersistentFlags().BoolVarP(&installLinux, "install-linux", "l", false, "Install Linux packages")
 	// rootCmd.PersistentFlags().BoolVarP(&installPython, "install-python", "i", false, "Install Python packages")
 	// rootCmd.PersistentFlags().BoolVarP(&installAll, "install-all", "a", false, "Install all packages")
@@ -98,6 +160,12 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&showVersion, "version", "v", false, "Show version information")
 	rootCmd.PersistentFlags().BoolVarP(&setupSudoEnv, "setup-sudo-env", "e", false, "Configure sudoers to preserve HARDN_CONFIG environment variable")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "Log level: debug, info, warn or error (overrides logging.level)")
+	rootCmd.PersistentFlags().BoolVar(&overrideWindow, "override-window", false, "Apply changes outside the configured maintenance windows")
+	rootCmd.PersistentFlags().BoolVar(&forceUnsupported, "force-unsupported", false, "Run on unsupported distributions in degraded mode (SSH, users, DNS and SELinux only)")
+	rootCmd.PersistentFlags().BoolVar(&watchdog, "watchdog", false, "Roll back the changes if SSH stops answering after they change sshd or the firewall")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "Make no calls to the internet: no update check, key imports or advisory downloads")
+	rootCmd.Flags().StringVar(&recordSession, "record-session", "", "Record menu choices and the resulting operations to a sanitized transcript")
 	rootCmd.PersistentFlags().BoolVar(&debugUpdates, "debug-updates", false, "Enable debugging for update checks")
 	rootCmd.PersistentFlags().BoolVar(&testUpdateAvailable, "test-update", false, "Force update notification for testing")
 	rootCmd.PersistentFlags().BoolVar(&testSecurityUpdate, "test-security-update", false, "Test security update notification")
@@ -113,11 +181,49 @@ func initializeColor() {
 	}
 }
 
+// initializeLogLevel applies --log-level ahead of the configuration file,
+// so it also covers the messages logged while the file is loaded
+func initializeLogLevel() {
+	if logLevel == "" {
+		return
+	}
+	level, err := logging.ParseLevel(logLevel)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	logging.ForceLevel(level)
+}
+
+// initializeDebugModules enables the debug entries of the modules listed
+// in HARDN_DEBUG, leaving the other modules at the configured level.
+// --debug-updates enables the version module.
+func initializeDebugModules() {
+	modules, err := logging.ParseDebugModules(os.Getenv(logging.DebugEnv))
+	if err != nil {
+		fmt.Printf("Warning: %s: %v\n", logging.DebugEnv, err)
+	}
+	if debugUpdates {
+		modules = append(modules, logging.ModuleVersion)
+	}
+	logging.SetDebugModules(modules)
+}
+
+func initializeOSDetection() {
+	osdetect.AllowUnsupported(forceUnsupported)
+}
+
+// initializeNetwork applies --offline; offline: true in the configuration
+// turns offline mode on once the file is loaded
+func initializeNetwork() {
+	interfaces.SetOffline(offline)
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "hardn",
 	Short: "Linux hardening tool",
-	Long:  `A simple hardening tool for Debian, Ubuntu, Proxmox and Alpine Linux.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Long:  `A simple hardening tool for Debian, Ubuntu, Proxmox, Alpine Linux, Rocky Linux, AlmaLinux and Fedora.`,
+	Run: func(_ *cobra.Command, args []string) {
 		// Create version service
 		versionService := version.NewService(Version, BuildDate, GitCommit)
 
@@ -127,26 +233,33 @@ var rootCmd = &cobra.Command{
 			return
 		}
 
-		// Check if running as root
-		currentUser, err := osuser.Current()
-		if err != nil {
-			logging.LogError("Failed to get current user: %v", err)
-			os.Exit(1)
-		}
-
-		if currentUser.Uid != "0" {
-			logging.LogError("This script needs to be run as root.")
-			fmt.Println("For Ubuntu/Debian run: `sudo hardn` or switch to root `sudo -i`")
-			fmt.Println("For Alpine run: `sudo hardn` or switch to root `su`")
-			os.Exit(1)
+		// Run as a normal user, privileged commands and file changes are
+		// escalated with sudo, asking for the password once
+		if !interfaces.IsRoot() {
+			if err := interfaces.EnableSudo(); err != nil {
+				logging.LogError("%v", err)
+				fmt.Println("For Ubuntu/Debian run: `sudo hardn` or switch to root `sudo -i`")
+				fmt.Println("For Alpine run: `sudo hardn` or switch to root `su`")
+				fmt.Println("For Rocky Linux, AlmaLinux and Fedora run: `sudo hardn` or switch to root `sudo -i`")
+				os.Exit(1)
+			}
+			provider = interfaces.NewProvider()
 		}
 
 		// Load configuration (will check both command-line flag and environment variable)
+		var err error
 		cfg, err = config.LoadConfig(configFile)
 		if err != nil {
 			logging.LogError("Failed to load configuration: %v", err)
 			os.Exit(1)
 		}
+		cfg.ApplyLogging()
+
+		// Commands run with a sanitized environment; only a configured proxy is passed on
+		interfaces.SetCommandProxy(cfg.HttpProxy)
+		if cfg.Offline {
+			interfaces.SetOffline(true)
+		}
 
 		// Set dry run mode from flag
 		cfg.DryRun = dryRun
@@ -169,18 +282,90 @@ var rootCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		// Dry runs show each file change as a diff and skip commands that
+		// change the system; the menu can toggle dry-run mode at any time
+		previewing := func(p *interfaces.Provider) *interfaces.Provider {
+			return interfaces.DryRunProvider(p, func() bool { return cfg.DryRun }, os.Stdout, cfg.BackupPath)
+		}
+
 		// Create service factory
-		serviceFactory := infrastructure.NewServiceFactory(provider, osInfo)
+		serviceFactory := infrastructure.NewServiceFactory(previewing(provider), osInfo)
 		serviceFactory.SetConfig(cfg)
 
-		// If no specific flags provided, show the interactive menu
-		if !createUser && !disableRootSSH && !installLinux && !installPython &&
+		interactive := !createUser && !disableRootSSH && !installLinux && !installPython &&
 			!installAll && !configureUfw && !configureDns && !runAll &&
-			!updateSources && !printLogs && !setupSudoEnv {
+			!updateSources && !printLogs && !setupSudoEnv && !removeInsecure
+
+		// Enforce maintenance windows before anything changes the system
+		maintenanceManager, err := serviceFactory.CreateMaintenanceManager()
+		if err != nil {
+			logging.LogError("Invalid maintenance window configuration: %v", err)
+			os.Exit(1)
+		}
+
+		// The menu can turn dry-run mode off, so interactive sessions are
+		// checked even when they start in dry-run mode
+		mutating := interactive || (!cfg.DryRun && (createUser || disableRootSSH || installLinux ||
+			installPython || installAll || configureUfw || configureDns || runAll ||
+			updateSources || setupSudoEnv || removeInsecure))
+
+		if mutating && maintenanceManager.HasWindows() {
+			if err := maintenanceManager.CheckMutation(overrideWindow); err != nil {
+				if interactive {
+					// Keep the menu usable for review, but only simulate changes
+					logging.LogWarning("%v; changes are only simulated (use --override-window to apply changes)", err)
+					cfg.DryRun = true
+					cfg.MaintenanceLocked = true
+				} else {
+					logging.LogWarning("Deferring changes: %v", err)
+					logging.LogInfo("Use --override-window to apply changes outside the maintenance window")
+					os.Exit(exitDeferred)
+				}
+			} else if !maintenanceManager.InWindow() {
+				logging.LogWarning("Applying changes outside the maintenance window (--override-window)")
+			}
+		}
+
+		// Record what this run changes so 'hardn rollback' can revert it.
+		// Simulated changes never reach the journal, so an interactive
+		// session starts the run in dry-run mode too: changes made after the
+		// menu turns dry-run off are recorded and guarded by the SSH timer.
+		if mutating && (interactive || !cfg.DryRun) {
+			description := strings.Join(append([]string{"hardn"}, os.Args[1:]...), " ")
+			if interactive {
+				description = "interactive menu"
+			}
+			provider = cmd.StartRun(provider, description, cfg, osInfo)
+			serviceFactory = infrastructure.NewServiceFactory(previewing(provider), osInfo)
+			serviceFactory.SetConfig(cfg)
+		}
+
+		// If no specific flags provided, show the interactive menu
+		if interactive {
+			// Record the session through a provider that notes every change
+			if recordSession != "" {
+				recorder, err := cmd.StartSessionRecording(recordSession, Version, cfg, osInfo)
+				if err != nil {
+					logging.LogError("Failed to start session recording: %v", err)
+					os.Exit(1)
+				}
+				defer func() {
+					if err := recorder.Close(); err != nil {
+						logging.LogError("Session transcript is incomplete: %v", err)
+						return
+					}
+					fmt.Printf("Session recorded to %s\n", recordSession)
+				}()
+
+				serviceFactory = infrastructure.NewServiceFactory(previewing(session.RecordProvider(provider, recorder)), osInfo)
+				serviceFactory.SetConfig(cfg)
+				menu.SetSessionRecorder(recorder)
+			}
 
 			// Create menu factory and main menu with version service
 			menuFactory := infrastructure.NewMenuFactory(serviceFactory, cfg, osInfo)
 			mainMenu := menuFactory.CreateMainMenu(versionService)
+			mainMenu.SetChangeCheck(cmd.ConfirmSSHChanges)
 
 			// Handle test flags
 			if testUpdateAvailable {
@@ -224,37 +409,179 @@ var rootCmd = &cobra.Command{
 		if runAll {
 			logging.LogInfo("Running complete system hardening...")
 
+			// Snapshot the root filesystem first so the whole run can be reverted
+			if cfg.SnapshotBeforeRunAll && !cfg.DryRun {
+				snapshot, err := serviceFactory.CreateSnapshotManager().CreateSnapshot()
+				if err != nil {
+					logging.LogError("Failed to create snapshot before hardening: %v", err)
+					os.Exit(1)
+				}
+				logging.LogSuccess("Snapshot %s created; revert with 'hardn rollback --snapshot %s'", snapshot.Name, snapshot.Name)
+			}
+
 			// Create a comprehensive hardening configuration
 			hardeningConfig := &model.HardeningConfig{
-				CreateUser:         cfg.Username != "",
-				Username:           cfg.Username,
-				SudoNoPassword:     cfg.SudoNoPassword,
-				SshKeys:            cfg.SshKeys,
-				SshPort:            cfg.SshPort,
-				SshListenAddresses: []string{cfg.SshListenAddress},
-				SshAllowedUsers:    cfg.SshAllowedUsers,
-				EnableFirewall:     cfg.EnableUfwSshPolicy,
-				AllowedPorts:       []int{},
-				FirewallProfiles:   []model.FirewallProfile{},
-				ConfigureDns:       cfg.ConfigureDns,
-				Nameservers:        cfg.Nameservers,
-				EnableAppArmor:     cfg.EnableAppArmor,
-				EnableLynis:        cfg.EnableLynis,
-				// EnableUnattendedUpgrades: cfg.EnableUnattendedUpgrades,
-			}
-
-			// Run all hardening steps
-			if err := menuManager.HardenSystem(hardeningConfig); err != nil {
+				CreateUser:               cfg.Username != "",
+				Username:                 cfg.Username,
+				SudoNoPassword:           cfg.SudoNoPassword,
+				SshKeys:                  cfg.SshKeys,
+				SshPort:                  cfg.SshPort,
+				SshListenAddresses:       cfg.SshListenAddresses,
+				SshAllowedUsers:          cfg.SshAllowedUsers,
+				SshAllowAllUsers:         cfg.SshAllowAllUsers,
+				SshProfile:               cfg.SshProfile,
+				SshConsolidateDropIns:    cfg.SshDropInConflicts == model.SSHConflictsConsolidate,
+				EnableFirewall:           cfg.EnableUfwSshPolicy,
+				IPv6:                     cfg.IPv6.Policy(),
+				MirrorFirewallStacks:     cfg.MirrorFirewallStacks,
+				AllowedPorts:             []int{},
+				FirewallRules:            cfg.CustomFirewallRules(),
+				SshRateLimit:             cfg.SshRateLimit,
+				PortKnocking:             cfg.SSHFirewallPolicy().Knocking,
+				FirewallProfiles:         []model.FirewallProfile{},
+				ConfigureDns:             cfg.ConfigureDns,
+				Nameservers:              cfg.Nameservers,
+				EnableAppArmor:           cfg.EnableAppArmor,
+				EnableSELinux:            cfg.EnableSELinux,
+				EnableLynis:              cfg.EnableLynis,
+				EnableUnattendedUpgrades: cfg.EnableUnattendedUpgrades,
+				AutoUpdates:              cfg.AutoUpdates.Policy(),
+				NeedrestartMode:          cfg.NeedrestartMode,
+				EnableWebServerTLS:       cfg.EnableWebServerTls,
+			}
+			if cfg.DatabaseHardening.Enabled {
+				hardeningConfig.DatabaseHardening = &model.DatabaseHardening{
+					BindAddresses:  cfg.DatabaseHardening.BindAddresses,
+					AllowedSubnets: cfg.DatabaseHardening.AllowedSubnets,
+				}
+			}
+			if cfg.Banner.Enabled {
+				policy := cfg.Banner.Policy()
+				hardeningConfig.Banner = &policy
+			}
+			if cfg.PasswordPolicy.Enabled {
+				policy := cfg.PasswordPolicy.Policy()
+				hardeningConfig.PasswordPolicy = &policy
+			}
+			if cfg.ModuleBlacklist.Enabled {
+				policy := cfg.ModuleBlacklist.Policy()
+				hardeningConfig.ModuleBlacklist = &policy
+			}
+			if cfg.BootHardening.Enabled {
+				policy := cfg.BootHardening.Policy()
+				hardeningConfig.BootHardening = &policy
+			}
+			if cfg.TimeSync.Enabled {
+				policy := cfg.TimeSync.Policy()
+				hardeningConfig.TimeSync = &policy
+			}
+			if cfg.LogShipping.Enabled {
+				policy := cfg.LogShipping.Policy()
+				hardeningConfig.LogShipping = &policy
+			}
+
+			// Only the distribution-agnostic steps run in degraded mode
+			if osInfo.Degraded {
+				if skipped := hardeningConfig.RestrictToDistributionAgnostic(); len(skipped) > 0 {
+					logging.LogWarning("Skipping on unsupported OS: %s", strings.Join(skipped, ", "))
+				}
+			}
+			// Containers share the host's kernel and often its firewall
+			if env, tuned := model.ParseEnvironmentPlan(cfg.EnvironmentPlan, osInfo.Environment); tuned {
+				if skipped := hardeningConfig.ApplyEnvironmentPlan(env); len(skipped) > 0 {
+					logging.LogWarning("Skipping in %s: %s", env, strings.Join(skipped, ", "))
+				}
+			}
+			if osInfo.IsWSL {
+				if skipped := hardeningConfig.RestrictForWSL(osInfo.Systemd, osInfo.WSLResolvConf); len(skipped) > 0 {
+					logging.LogWarning("Skipping on WSL: %s (see hardn wsl for what to do in Windows instead)", strings.Join(skipped, ", "))
+				}
+			}
+
+			// Summarize real runs to the configured email and webhooks
+			notificationManager := serviceFactory.CreateNotificationManager()
+			notify := notificationManager.Enabled() && !cfg.DryRun
+			var summary model.RunSummary
+			if notify {
+				summary = cmd.BeginRunSummary(model.SummaryRunAll, cfg, osInfo)
+			}
+
+			// Run all hardening steps. Real runs show their progress as a
+			// checklist, with the details in the log file only; dry runs keep
+			// printing what they would do.
+			failures := &model.FailureRecorder{ProgressReporter: model.NoProgress{}}
+			if !cfg.DryRun {
+				failures.ProgressReporter = style.NewProgressChecklist("Run All")
+				logging.SetSilentMode(true)
+			}
+			menuManager.SetProgressReporter(cmd.TrackRunAll(failures))
+			err := menuManager.HardenSystem(hardeningConfig)
+			logging.SetSilentMode(false)
+			if err != nil {
 				logging.LogError("Failed to complete system hardening: %v", err)
 			} else {
 				logging.LogSuccess("System hardening completed successfully!")
-				fmt.Printf("Check the log file at %s for details.\n", cfg.LogFile)
+				fmt.Printf("Check the log file at %s for details.\n", cfg.LoggingOptions().Path)
+			}
+
+			// Point out sshd settings other files override, unless run-all
+			// already disabled them
+			if !hardeningConfig.SshConsolidateDropIns {
+				cmd.WarnSSHConflicts(serviceFactory)
+			}
+
+			// Show this run in the MOTD where it is not rendered at login
+			if hardeningConfig.Banner != nil && !cfg.DryRun {
+				cmd.RefreshMotd(serviceFactory, cfg, osInfo)
+			}
+
+			// Mirror the backups, this run's included, to the remote
+			// target; a failed sync fails the run summary
+			if cfg.BackupRemote.URL != "" && !cfg.DryRun {
+				if _, syncErr := cmd.SyncBackupRemote(serviceFactory, cfg); syncErr != nil {
+					failures.Failed = append(failures.Failed, syncErr.Error())
+					if err == nil {
+						err = syncErr
+					}
+				}
+			}
+
+			if notify {
+				summary.Failed = err != nil
+				summary.FailedSteps = failures.Failed
+				cmd.SendRunSummary(notificationManager, summary, cfg, osInfo)
 			}
 			return
 		}
 
 		// Handle individual operations based on flags
 
+		// Distribution-specific operations are skipped in degraded mode
+		if osInfo.Degraded {
+			for _, skip := range []struct {
+				requested bool
+				name      string
+			}{
+				{updateSources, "package sources"},
+				{installLinux || installAll, "Linux packages"},
+				{installPython || installAll, "Python packages"},
+				{configureUfw, "firewall"},
+				{removeInsecure, "insecure packages"},
+			} {
+				if skip.requested {
+					logging.LogWarning("Skipping %s: not available on unsupported OS %s", skip.name, osInfo.OsType)
+				}
+			}
+			updateSources, installLinux, installPython, installAll, configureUfw = false, false, false, false, false
+			removeInsecure = false
+		}
+
+		// Windows filters inbound traffic to WSL, not ufw
+		if osInfo.IsWSL && configureUfw {
+			logging.LogWarning("Skipping firewall: inbound traffic to WSL is filtered by Windows Defender Firewall (see hardn wsl)")
+			configureUfw = false
+		}
+
 		// Update package sources
 		if updateSources {
 			if err := packageManager.UpdatePackageSources(); err != nil {
@@ -282,6 +609,26 @@ var rootCmd = &cobra.Command{
 			}
 		}
 
+		// Remove insecure packages, or disable their services when other
+		// packages depend on them
+		if removeInsecure {
+			findings, failed, err := serviceFactory.CreateDebloatManager().RemoveInsecurePackages(cfg.Debloat.Items())
+			if err != nil {
+				logging.LogError("Failed to check for insecure packages: %v", err)
+			} else if len(findings) == 0 {
+				logging.LogSuccess("No insecure packages installed")
+			}
+			for _, finding := range findings {
+				if err, ok := failed[finding.Item.Name]; ok {
+					logging.LogWarning("Skipped %s: %v", finding.Item.Name, err)
+				} else if finding.Action() == model.DebloatDisable {
+					logging.LogSuccess("Disabled %s (%s)", finding.Item.Name, strings.Join(finding.Running, ", "))
+				} else {
+					logging.LogSuccess("Removed %s (%s)", finding.Item.Name, strings.Join(finding.Installed, ", "))
+				}
+			}
+		}
+
 		// Install Linux packages
 		if installLinux || installAll {
 			logging.LogInfo("Installing Linux packages...")
@@ -301,6 +648,14 @@ var rootCmd = &cobra.Command{
 					} else {
 						logging.LogSuccess("Alpine core packages installed successfully")
 					}
+				} else if model.IsRHELFamily(osInfo.OsType) {
+					if len(cfg.RhelCorePackages) > 0 {
+						if err := packageManager.InstallLinuxPackages(cfg.RhelCorePackages, "core"); err != nil {
+							logging.LogError("Failed to install RHEL core packages: %v", err)
+						} else {
+							logging.LogSuccess("RHEL core packages installed successfully")
+						}
+					}
 				} else if len(cfg.LinuxCorePackages) > 0 {
 					if err := packageManager.InstallLinuxPackages(cfg.LinuxCorePackages, "core"); err != nil {
 						logging.LogError("Failed to install Linux core packages: %v", err)
@@ -334,6 +689,16 @@ var rootCmd = &cobra.Command{
 					} else {
 						logging.LogSuccess("Alpine Python packages installed successfully")
 					}
+				} else if model.IsRHELFamily(osInfo.OsType) {
+					if err := packageManager.InstallPythonPackages(
+						cfg.RhelPythonPackages,
+						cfg.PythonPipPackages,
+						cfg.UseUvPackageManager,
+					); err != nil {
+						logging.LogError("Failed to install RHEL Python packages: %v", err)
+					} else {
+						logging.LogSuccess("RHEL Python packages installed successfully")
+					}
 				} else {
 					// For Debian/Ubuntu
 					pythonPackages := cfg.PythonPackages
@@ -357,6 +722,11 @@ var rootCmd = &cobra.Command{
 
 		// Create user
 		if createUser {
+			// A local account with a directory user's name shadows the directory account
+			if isDirectoryUser, err := userManager.IsDirectoryUser(cfg.Username); err == nil && isDirectoryUser {
+				logging.LogWarning("User '%s' is a directory (LDAP/SSSD) account; a local account will shadow it", cfg.Username)
+			}
+
 			if err := userManager.CreateUser(cfg.Username, true, cfg.SudoNoPassword, cfg.SshKeys); err != nil {
 				logging.LogError("Failed to create user: %v", err)
 			} else {
@@ -367,10 +737,12 @@ var rootCmd = &cobra.Command{
 			// TODO: This might need to be refactored to avoid duplicating the SSH configuration
 			if err := sshManager.ConfigureSSH(
 				cfg.SshPort,
-				[]string{cfg.SshListenAddress},
+				cfg.SshListenAddresses,
 				cfg.PermitRootLogin,
 				cfg.SshAllowedUsers,
 				[]string{cfg.SshKeyPath},
+				cfg.Username,
+				cfg.SshAllowAllUsers,
 			); err != nil {
 				logging.LogError("Failed to configure SSH: %v", err)
 			}
@@ -378,7 +750,7 @@ var rootCmd = &cobra.Command{
 
 		// Configure firewall
 		if configureUfw {
-			if err := firewallManager.ConfigureSecureFirewall(cfg.SshPort, []int{}, []model.FirewallProfile{}); err != nil {
+			if err := firewallManager.ConfigureSecureFirewall(cfg.SSHFirewallPolicy(), cfg.IPv6.Policy(), []int{}, cfg.CustomFirewallRules(), []model.FirewallProfile{}); err != nil {
 				logging.LogError("Failed to configure firewall: %v", err)
 			} else {
 				logging.LogSuccess("Firewall configured successfully")
@@ -396,7 +768,7 @@ var rootCmd = &cobra.Command{
 
 		// Print logs
 		if printLogs {
-			logging.PrintLogs(cfg.LogFile)
+			logging.PrintLogs(cfg.LoggingOptions().Path)
 		}
 
 		// Setting up sudo environment preservation
@@ -411,7 +783,7 @@ var rootCmd = &cobra.Command{
 
 		// Output completion message for operations other than the all-in-one run
 		if createUser || disableRootSSH || installLinux || installPython ||
-			installAll || configureUfw || configureDns || updateSources {
+			installAll || configureUfw || configureDns || updateSources || removeInsecure {
 			logging.LogSuccess("Script completed selected hardening operations.")
 		}
 	},
@@ -437,14 +809,18 @@ Example:
 			os.Exit(1)
 		}
 
-		// Create service factory
-		serviceFactory := infrastructure.NewServiceFactory(provider, osInfo)
+		// Create service factory; with --dry-run the sudoers change is only shown
+		preview := interfaces.DryRunProvider(provider, func() bool { return dryRun }, os.Stdout)
+		serviceFactory := infrastructure.NewServiceFactory(preview, osInfo)
 		environmentManager := serviceFactory.CreateEnvironmentManager()
 
 		if err := environmentManager.SetupSudoPreservation(); err != nil {
 			logging.LogError("Failed to configure sudoers: %v", err)
 			os.Exit(1)
 		}
+		if dryRun {
+			return
+		}
 		logging.LogSuccess("Sudo environment configured to preserve HARDN_CONFIG")
 	},
 }
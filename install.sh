@@ -17,12 +17,20 @@ case "$OS" in
       x86_64)
         ASSET="hardn-linux-amd64"
         ;;
-      armv7l)
+      armv7l|armv7|armv8l|armhf)
+        # 32-bit ARM builds target ARMv7
         ASSET="hardn-linux-arm"
         ;;
-      aarch64)
+      aarch64|arm64)
         ASSET="hardn-linux-arm64"
         ;;
+      riscv64)
+        ASSET="hardn-linux-riscv64"
+        ;;
+      armv6l|armv5tel)
+        echo "Unsupported architecture: $ARCH (32-bit ARM builds need ARMv7 or newer)" >&2
+        exit 1
+        ;;
       *)
         echo "Unsupported architecture: $ARCH" >&2
         exit 1
@@ -0,0 +1,235 @@
+// pkg/adapter/secondary/backup_remote_target.go
+package secondary
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+)
+
+// backupDatePattern matches the date directories of the backup directory
+var backupDatePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// remoteTestName is the file written to a remote target to test it
+const remoteTestName = ".hardn-remote-test"
+
+// backupTarget is a remote place the backup directory is mirrored to
+type backupTarget interface {
+	// list returns the date directories of the target
+	list() ([]string, error)
+
+	// upload copies a local date directory to the target
+	upload(localDir string, date string) error
+
+	// remove deletes a date directory of the target
+	remove(date string) error
+
+	// check writes a local file to the target and removes it again
+	check(localFile string) error
+}
+
+// newBackupTarget picks the target of a remote from its URL scheme
+func newBackupTarget(commander interfaces.Commander, remote model.BackupRemote) (backupTarget, error) {
+	u, err := url.Parse(remote.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup target %q: %w", remote.URL, err)
+	}
+
+	switch u.Scheme {
+	case model.BackupTargetS3:
+		if u.Host == "" {
+			return nil, fmt.Errorf("backup target %q has no bucket", remote.URL)
+		}
+		target := &s3Target{
+			commander: commander,
+			base:      "s3://" + u.Host + "/" + strings.Trim(u.Path, "/"),
+		}
+		if remote.Endpoint != "" {
+			target.options = append(target.options, "--endpoint-url", remote.Endpoint)
+		}
+		if remote.Profile != "" {
+			target.options = append(target.options, "--profile", remote.Profile)
+		}
+		return target, nil
+
+	case model.BackupTargetSFTP:
+		if u.Hostname() == "" || u.Path == "" || u.Path == "/" {
+			return nil, fmt.Errorf("backup target %q needs a host and a directory", remote.URL)
+		}
+		target := &sftpTarget{
+			commander:   commander,
+			destination: u.Hostname(),
+			dir:         strings.TrimSuffix(u.Path, "/"),
+			options:     []string{"-b", "-", "-o", "BatchMode=yes"},
+		}
+		if u.User != nil {
+			target.destination = u.User.Username() + "@" + target.destination
+		}
+		if u.Port() != "" {
+			target.options = append(target.options, "-P", u.Port())
+		}
+		if remote.IdentityFile != "" {
+			target.options = append(target.options, "-i", remote.IdentityFile)
+		}
+		return target, nil
+	}
+	return nil, fmt.Errorf("unsupported backup target %q (use s3:// or sftp://)", remote.URL)
+}
+
+// s3Target mirrors backups to an S3-compatible bucket with the AWS CLI
+type s3Target struct {
+	commander interfaces.Commander
+	base      string   // s3://bucket/prefix, without a trailing slash
+	options   []string // endpoint and profile
+}
+
+// aws runs an aws s3 subcommand with the target's options
+func (t *s3Target) aws(args ...string) ([]byte, error) {
+	args = append(append([]string{"s3"}, args...), t.options...)
+	output, err := t.commander.Execute("aws", args...)
+	if err != nil {
+		return output, fmt.Errorf("aws s3 %s failed: %w\nOutput: %s", args[1], err, strings.TrimSpace(string(output)))
+	}
+	return output, nil
+}
+
+// prefix returns the key of an object or directory under the target
+func (t *s3Target) prefix(name string) string {
+	return strings.TrimSuffix(t.base, "/") + "/" + name
+}
+
+func (t *s3Target) list() ([]string, error) {
+	output, err := t.commander.Execute("aws", append([]string{"s3", "ls", t.prefix("")}, t.options...)...)
+	if err != nil {
+		// aws s3 ls fails silently on an empty prefix
+		if strings.TrimSpace(string(output)) == "" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("aws s3 ls failed: %w\nOutput: %s", err, strings.TrimSpace(string(output)))
+	}
+	return ParseS3Dates(string(output)), nil
+}
+
+func (t *s3Target) upload(localDir string, date string) error {
+	_, err := t.aws("sync", localDir, t.prefix(date)+"/", "--only-show-errors")
+	return err
+}
+
+func (t *s3Target) remove(date string) error {
+	_, err := t.aws("rm", t.prefix(date)+"/", "--recursive", "--only-show-errors")
+	return err
+}
+
+func (t *s3Target) check(localFile string) error {
+	if _, err := t.commander.Execute("which", "aws"); err != nil {
+		return fmt.Errorf("the AWS CLI (aws) is required for S3 backup targets")
+	}
+	if _, err := t.aws("cp", localFile, t.prefix(remoteTestName), "--only-show-errors"); err != nil {
+		return err
+	}
+	_, err := t.aws("rm", t.prefix(remoteTestName), "--only-show-errors")
+	return err
+}
+
+// sftpTarget mirrors backups to a directory of an SFTP host with batch
+// mode sftp, which needs key authentication
+type sftpTarget struct {
+	commander   interfaces.Commander
+	destination string // [user@]host
+	dir         string
+	options     []string // batch mode, port and identity
+}
+
+// sftp runs batch commands; those starting with "-" may fail without
+// failing the batch
+func (t *sftpTarget) sftp(commands ...string) ([]byte, error) {
+	args := append(append([]string{}, t.options...), t.destination)
+	output, err := t.commander.ExecuteWithInput(strings.Join(commands, "\n")+"\n", "sftp", args...)
+	if err != nil {
+		return output, fmt.Errorf("sftp to %s failed: %w\nOutput: %s", t.destination, err, strings.TrimSpace(string(output)))
+	}
+	return output, nil
+}
+
+func (t *sftpTarget) list() ([]string, error) {
+	output, err := t.sftp("-ls -1 " + quoteSFTP(t.dir))
+	if err != nil {
+		return nil, err
+	}
+	return ParseSFTPDates(string(output)), nil
+}
+
+// upload puts the date directory into the target directory, which sftp
+// creates only when its parent exists
+func (t *sftpTarget) upload(localDir string, date string) error {
+	_, err := t.sftp(
+		"-mkdir "+quoteSFTP(t.dir),
+		"put -r "+quoteSFTP(localDir)+" "+quoteSFTP(t.dir),
+	)
+	return err
+}
+
+func (t *sftpTarget) remove(date string) error {
+	dir := path.Join(t.dir, date)
+	_, err := t.sftp(
+		"-rm "+quoteSFTP(dir+"/*"),
+		"rmdir "+quoteSFTP(dir),
+	)
+	return err
+}
+
+func (t *sftpTarget) check(localFile string) error {
+	if _, err := t.commander.Execute("which", "sftp"); err != nil {
+		return fmt.Errorf("sftp is required for SFTP backup targets")
+	}
+	remote := path.Join(t.dir, remoteTestName)
+	_, err := t.sftp(
+		"-mkdir "+quoteSFTP(t.dir),
+		"put "+quoteSFTP(localFile)+" "+quoteSFTP(remote),
+		"rm "+quoteSFTP(remote),
+	)
+	return err
+}
+
+// quoteSFTP quotes a path for an sftp batch file
+func quoteSFTP(p string) string {
+	return `"` + strings.ReplaceAll(p, `"`, `\"`) + `"`
+}
+
+// ParseS3Dates returns the date directories of "aws s3 ls" output, whose
+// directories are lines like "PRE 2024-05-01/"
+func ParseS3Dates(output string) []string {
+	var dates []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != "PRE" {
+			continue
+		}
+		if date := strings.TrimSuffix(fields[1], "/"); backupDatePattern.MatchString(date) {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+// ParseSFTPDates returns the date directories of "ls -1" output of a
+// batch sftp session, which also echoes the commands run
+func ParseSFTPDates(output string) []string {
+	var dates []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "sftp>") {
+			continue
+		}
+		if date := filepath.Base(line); backupDatePattern.MatchString(date) {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
@@ -0,0 +1,374 @@
+// pkg/adapter/secondary/command_output.go
+package secondary
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Machine-readable sources used instead of human, locale-dependent output
+const (
+	// UFWConfPath holds ENABLED=yes|no
+	UFWConfPath = "/etc/ufw/ufw.conf"
+
+	// UFWDefaultsPath holds the DEFAULT_*_POLICY settings and IPV6=yes|no
+	UFWDefaultsPath = "/etc/default/ufw"
+
+	// UFWUserRulesPath and UFWUser6RulesPath hold the IPv4 and IPv6 rules
+	// added with ufw, each with a "### tuple ###" summary line
+	UFWUserRulesPath  = "/etc/ufw/user.rules"
+	UFWUser6RulesPath = "/etc/ufw/user6.rules"
+
+	// IfInet6Path lists the host's IPv6 addresses, one per line with the
+	// interface name last
+	IfInet6Path = "/proc/net/if_inet6"
+
+	// ProcNetRoutePath lists the IPv4 routes, the default one with the
+	// destination 00000000
+	ProcNetRoutePath = "/proc/net/route"
+
+	// ProcIPv6DisablePath holds 1 when IPv6 is turned off with sysctl; it
+	// is missing when the kernel command line turns IPv6 off
+	ProcIPv6DisablePath = "/proc/sys/net/ipv6/conf/all/disable_ipv6"
+
+	// ProcCmdlinePath holds the command line the kernel booted with
+	ProcCmdlinePath = "/proc/cmdline"
+
+	// AppArmorProfilesPath lists loaded profiles as "name (mode)"
+	AppArmorProfilesPath = "/sys/kernel/security/apparmor/profiles"
+
+	// LSMListPath lists the active Linux security modules, comma separated
+	LSMListPath = "/sys/kernel/security/lsm"
+
+	// SELinuxEnforcePath holds 1 when SELinux enforces and 0 when permissive
+	SELinuxEnforcePath = "/sys/fs/selinux/enforce"
+
+	// SELinuxConfigPath holds the SELINUX= mode and SELINUXTYPE= policy used at boot
+	SELinuxConfigPath = "/etc/selinux/config"
+
+	// DpkgStatusFormat makes dpkg-query print the raw status triple
+	DpkgStatusFormat = "${Status}\n"
+
+	// RebootRequiredPath is created by Debian/Ubuntu packages that need a reboot
+	RebootRequiredPath = "/var/run/reboot-required"
+)
+
+// parseShellAssignments reads KEY=value lines as written by ufw and friends
+func parseShellAssignments(data []byte) map[string]string {
+	values := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		values[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return values
+}
+
+// ParseUFWEnabled reports whether ufw.conf enables the firewall
+func ParseUFWEnabled(conf []byte) bool {
+	return strings.EqualFold(parseShellAssignments(conf)["ENABLED"], "yes")
+}
+
+// ParseUFWDefaultPolicies returns the incoming and outgoing policies from
+// /etc/default/ufw in ufw's own terms (allow, deny, reject)
+func ParseUFWDefaultPolicies(defaults []byte) (incoming string, outgoing string) {
+	values := parseShellAssignments(defaults)
+	return ufwPolicy(values["DEFAULT_INPUT_POLICY"]), ufwPolicy(values["DEFAULT_OUTPUT_POLICY"])
+}
+
+// ufwPolicy maps iptables targets to ufw policy names
+func ufwPolicy(target string) string {
+	switch strings.ToUpper(target) {
+	case "ACCEPT":
+		return "allow"
+	case "DROP":
+		return "deny"
+	case "REJECT":
+		return "reject"
+	}
+	return ""
+}
+
+// ParseUFWAddedRules extracts rules from `ufw show added`. The header is
+// translated but each rule is printed as a "ufw ..." command.
+func ParseUFWAddedRules(output []byte) []string {
+	var rules []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if rule, ok := strings.CutPrefix(strings.TrimSpace(line), "ufw "); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// ParseUFWIPv6 reports whether /etc/default/ufw has ufw manage IPv6
+func ParseUFWIPv6(defaults []byte) bool {
+	return strings.EqualFold(parseShellAssignments(defaults)["IPV6"], "yes")
+}
+
+// ParseUFWTuples returns the incoming allow and limit rules of a ufw rules
+// file, as ufw commands like "allow 22/tcp" or "allow 5432/tcp from
+// 10.0.0.0/8". Each rule is read from its tuple line:
+//
+//	### tuple ### ACTION PROTO DPORT DST SPORT SRC [DAPP SAPP] DIRECTION
+func ParseUFWTuples(data []byte) []string {
+	return parseUFWTuples(data, func(action string) bool {
+		return action == "allow" || action == "limit"
+	})
+}
+
+// ParseUFWRuleTuples returns every incoming rule of a ufw rules file,
+// whatever its action, in the form of ParseUFWTuples
+func ParseUFWRuleTuples(data []byte) []string {
+	return parseUFWTuples(data, func(action string) bool { return true })
+}
+
+// parseUFWTuples returns the incoming rules of a ufw rules file whose
+// action keep accepts
+func parseUFWTuples(data []byte, keep func(action string) bool) []string {
+	var rules []string
+	for _, line := range strings.Split(string(data), "\n") {
+		tuple, ok := strings.CutPrefix(strings.TrimSpace(line), "### tuple ###")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(tuple)
+		if n := len(fields); n > 0 && strings.HasPrefix(fields[n-1], "comment=") {
+			fields = fields[:n-1]
+		}
+		if len(fields) != 7 && len(fields) != 9 {
+			continue
+		}
+
+		// Logging rules are written as allow_log; route rules are skipped
+		action, _, _ := strings.Cut(fields[0], "_")
+		direction := fields[len(fields)-1]
+		if !keep(action) || !strings.HasPrefix(direction, "in") {
+			continue
+		}
+
+		rule := action + " " + fields[2]
+		if fields[1] != "any" {
+			rule += "/" + fields[1]
+		}
+		if !ufwAnyAddress(fields[5]) {
+			rule += " from " + fields[5]
+		}
+		if !ufwAnyAddress(fields[3]) {
+			rule += " to " + fields[3]
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// ufwAnyAddress reports whether a tuple address matches every address
+func ufwAnyAddress(address string) bool {
+	return address == "any" || address == "0.0.0.0/0" || address == "::/0"
+}
+
+// ParseIfInet6 reports whether the host has an IPv6 address outside the
+// loopback interface
+func ParseIfInet6(data []byte) bool {
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[len(fields)-1] != "lo" {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseDefaultRouteInterface returns the interface of the IPv4 default
+// route in /proc/net/route, or "" without one
+func ParseDefaultRouteInterface(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 1 && fields[1] == "00000000" {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+// ParseFirewalldTarget maps `firewall-cmd --get-target` output to ufw's
+// policy names; firewalld's "default" target rejects
+func ParseFirewalldTarget(output []byte) string {
+	switch strings.TrimSpace(string(output)) {
+	case "ACCEPT":
+		return "allow"
+	case "DROP":
+		return "deny"
+	}
+	return "reject"
+}
+
+var (
+	richRuleSource  = regexp.MustCompile(`\bsource address="([^"]+)"`)
+	richRulePort    = regexp.MustCompile(`\bport port="([^"]+)" protocol="([^"]+)"`)
+	richRuleAction  = regexp.MustCompile(`\b(accept|reject|drop)\b`)
+	richRuleFamily  = regexp.MustCompile(`\bfamily="(ipv4|ipv6)"`)
+	richRuleLimit   = regexp.MustCompile(`\baccept limit value="[^"]+"`)
+	richRuleService = regexp.MustCompile(`\bservice name="([^"]+)"`)
+)
+
+// ParseFirewalldRichRuleFamily returns the family a rich rule is limited
+// to, or "" when it applies to both
+func ParseFirewalldRichRuleFamily(line string) string {
+	if family := richRuleFamily.FindStringSubmatch(line); family != nil {
+		return family[1]
+	}
+	return ""
+}
+
+// ParseFirewalldRichRuleService returns the service a rich rule accepts,
+// e.g. one limiting a profile's service to a family, or "" for none
+func ParseFirewalldRichRuleService(line string) string {
+	service := richRuleService.FindStringSubmatch(line)
+	action := richRuleAction.FindAllStringSubmatch(line, -1)
+	if service == nil || action == nil || action[len(action)-1][1] != "accept" || strings.Contains(line, "source NOT") {
+		return ""
+	}
+	return service[1]
+}
+
+// ParseFirewalldRichRule translates a rich rule from `firewall-cmd
+// --list-rich-rules` into a ufw rule, e.g. "allow 22/tcp from 10.0.0.0/8";
+// an accept with a rate limit becomes "limit".
+// Rules without a port, or with an inverted source, are skipped.
+func ParseFirewalldRichRule(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.Contains(line, "source NOT") {
+		return "", false
+	}
+
+	port := richRulePort.FindStringSubmatch(line)
+	action := richRuleAction.FindAllStringSubmatch(line, -1)
+	if port == nil || action == nil {
+		return "", false
+	}
+
+	verb := "deny"
+	switch action[len(action)-1][1] {
+	case "accept":
+		verb = "allow"
+		if richRuleLimit.MatchString(line) {
+			verb = "limit"
+		}
+	case "reject":
+		verb = "reject"
+	}
+
+	rule := verb + " " + strings.ReplaceAll(port[1], "-", ":") + "/" + port[2]
+	if source := richRuleSource.FindStringSubmatch(line); source != nil {
+		rule += " from " + source[1]
+	}
+	return rule, true
+}
+
+// ParseDpkgInstalled reports whether dpkg-query -W -f='${Status}' output
+// describes an installed package ("install ok installed")
+func ParseDpkgInstalled(output []byte) bool {
+	fields := strings.Fields(string(output))
+	return len(fields) == 3 && fields[2] == "installed"
+}
+
+// ParseIptablesSave returns the chain policies and appended rules from
+// iptables-save output
+func ParseIptablesSave(output []byte) (policies map[string]string, rules []string) {
+	policies = make(map[string]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, ":"):
+			// :INPUT DROP [0:0]
+			fields := strings.Fields(strings.TrimPrefix(line, ":"))
+			if len(fields) >= 2 {
+				policies[fields[0]] = fields[1]
+			}
+		case strings.HasPrefix(line, "-A "):
+			rules = append(rules, line)
+		}
+	}
+	return policies, rules
+}
+
+// ParseAppArmorProfiles counts loaded profiles by mode from the kernel's
+// profile list
+func ParseAppArmorProfiles(data []byte) (enforce int, complain int) {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasSuffix(line, "(enforce)"):
+			enforce++
+		case strings.HasSuffix(line, "(complain)"):
+			complain++
+		}
+	}
+	return enforce, complain
+}
+
+// ParseSELinuxConfig returns the boot mode and policy type from
+// /etc/selinux/config
+func ParseSELinuxConfig(data []byte) (mode string, policy string) {
+	values := parseShellAssignments(data)
+	return strings.ToLower(values["SELINUX"]), values["SELINUXTYPE"]
+}
+
+// ParseSELinuxPorts returns the TCP ports labeled with portType in
+// `semanage port -l -n` output, e.g. "ssh_port_t  tcp  2222, 22"
+func ParseSELinuxPorts(output []byte, portType string) []string {
+	var ports []string
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != portType || fields[1] != "tcp" {
+			continue
+		}
+		for _, port := range fields[2:] {
+			ports = append(ports, strings.TrimSuffix(port, ","))
+		}
+	}
+	return ports
+}
+
+// ParseNeedrestartBatch reads `needrestart -b` output, returning services
+// waiting on a restart and whether the running kernel is outdated
+func ParseNeedrestartBatch(output []byte) (services []string, rebootRequired bool) {
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "NEEDRESTART-SVC":
+			services = append(services, value)
+		case "NEEDRESTART-KSTA":
+			// 0 unknown, 1 current, 2 ABI compatible upgrade, 3 version upgrade
+			rebootRequired = value == "2" || value == "3"
+		}
+	}
+	return services, rebootRequired
+}
+
+// ParseSSHDTest reads the output of sshd -T into the values of each
+// directive, keyed by lowercase name; directives such as listenaddress
+// appear once per value
+func ParseSSHDTest(output []byte) map[string][]string {
+	settings := make(map[string][]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if key == "" {
+			continue
+		}
+		key = strings.ToLower(key)
+		settings[key] = append(settings[key], strings.TrimSpace(value))
+	}
+	return settings
+}
@@ -0,0 +1,67 @@
+// pkg/adapter/secondary/file_ansible_facts_repository.go
+package secondary
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// ansibleFactsMarker identifies the facts script hardn wrote
+const ansibleFactsMarker = "# Managed by hardn: Ansible local facts"
+
+// FileAnsibleFactsRepository implements AnsibleFactsRepository with a
+// script in /etc/ansible/facts.d
+type FileAnsibleFactsRepository struct {
+	fs interfaces.FileSystem
+}
+
+// NewFileAnsibleFactsRepository creates a new FileAnsibleFactsRepository
+func NewFileAnsibleFactsRepository(fs interfaces.FileSystem) secondary.AnsibleFactsRepository {
+	return &FileAnsibleFactsRepository{
+		fs: fs,
+	}
+}
+
+// InstallFactsScript writes a script Ansible runs while gathering facts.
+// Ansible runs executable .fact files and reads JSON from their output, so
+// errors are dropped to keep a failing check from breaking fact gathering.
+func (r *FileAnsibleFactsRepository) InstallFactsScript(binary string) error {
+	if data, err := r.fs.ReadFile(model.AnsibleFactsPath); err == nil && !strings.Contains(string(data), ansibleFactsMarker) {
+		return fmt.Errorf("%s exists and was not written by hardn", model.AnsibleFactsPath)
+	}
+
+	script := fmt.Sprintf("#!/bin/sh\n%s\nexec %s facts 2>/dev/null </dev/null\n", ansibleFactsMarker, binary)
+	if err := r.fs.MkdirAll(model.AnsibleFactsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", model.AnsibleFactsDir, err)
+	}
+	if err := r.fs.WriteFile(model.AnsibleFactsPath, []byte(script), 0755); err != nil {
+		return fmt.Errorf("failed to write %s: %w", model.AnsibleFactsPath, err)
+	}
+	return nil
+}
+
+// RemoveFactsScript removes the facts script, leaving one hardn did not
+// write in place
+func (r *FileAnsibleFactsRepository) RemoveFactsScript() error {
+	data, err := r.fs.ReadFile(model.AnsibleFactsPath)
+	if err != nil {
+		return nil
+	}
+	if !strings.Contains(string(data), ansibleFactsMarker) {
+		return fmt.Errorf("%s was not written by hardn", model.AnsibleFactsPath)
+	}
+	if err := r.fs.Remove(model.AnsibleFactsPath); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", model.AnsibleFactsPath, err)
+	}
+	return nil
+}
+
+// FactsScriptInstalled reports whether hardn's facts script is installed
+func (r *FileAnsibleFactsRepository) FactsScriptInstalled() bool {
+	data, err := r.fs.ReadFile(model.AnsibleFactsPath)
+	return err == nil && strings.Contains(string(data), ansibleFactsMarker)
+}
@@ -0,0 +1,57 @@
+// pkg/adapter/secondary/file_audit_repository.go
+package secondary
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// FileAuditRepository implements AuditRepository using JSON files
+type FileAuditRepository struct {
+	fs interfaces.FileSystem
+}
+
+// NewFileAuditRepository creates a new FileAuditRepository
+func NewFileAuditRepository(fs interfaces.FileSystem) secondary.AuditRepository {
+	return &FileAuditRepository{
+		fs: fs,
+	}
+}
+
+// LoadReport reads an audit report from path
+func (r *FileAuditRepository) LoadReport(path string) (*model.AuditReport, error) {
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit report %s: %w", path, err)
+	}
+
+	var report model.AuditReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse audit report %s: %w", path, err)
+	}
+
+	if report.Format > model.AuditReportFormat {
+		return nil, fmt.Errorf("audit report %s uses format %d; this hardn supports up to %d",
+			path, report.Format, model.AuditReportFormat)
+	}
+
+	return &report, nil
+}
+
+// SaveReport writes an audit report to path
+func (r *FileAuditRepository) SaveReport(path string, report *model.AuditReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode audit report: %w", err)
+	}
+
+	if err := r.fs.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write audit report %s: %w", path, err)
+	}
+
+	return nil
+}
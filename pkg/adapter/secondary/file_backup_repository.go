@@ -2,9 +2,14 @@
 package secondary
 
 import (
+	"bytes"
+	"compress/gzip"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/abbott/hardn/pkg/domain/model"
@@ -12,6 +17,13 @@ import (
 	"github.com/abbott/hardn/pkg/port/secondary"
 )
 
+// backupIndexName is the file of each date directory recording where its
+// backups were taken from
+const backupIndexName = ".hardn-index"
+
+// backupKeyTest is the text encrypted and decrypted to test the keys
+const backupKeyTest = "hardn backup key test\n"
+
 // FileBackupRepository implements BackupRepository using file operations
 type FileBackupRepository struct {
 	fs        interfaces.FileSystem
@@ -23,20 +35,17 @@ type FileBackupRepository struct {
 func NewFileBackupRepository(
 	fs interfaces.FileSystem,
 	commander interfaces.Commander,
-	backupDir string,
-	enabled bool,
+	config model.BackupConfig,
 ) secondary.BackupRepository {
 	return &FileBackupRepository{
 		fs:        fs,
 		commander: commander,
-		config: &model.BackupConfig{
-			Enabled:   enabled,
-			BackupDir: backupDir,
-		},
+		config:    &config,
 	}
 }
 
-// BackupFile backs up a file with a timestamp
+// BackupFile backs up a file with a timestamp, compressed and encrypted
+// as configured
 func (r *FileBackupRepository) BackupFile(filePath string) error {
 	if !r.config.Enabled {
 		return nil // Backups disabled, silently succeed
@@ -58,7 +67,7 @@ func (r *FileBackupRepository) BackupFile(filePath string) error {
 	}
 
 	// Create backup with timestamp
-	backupFile := filepath.Join(backupDir, fmt.Sprintf("%s.%s.bak", fileName, time.Now().Format("150405")))
+	backupFile := filepath.Join(backupDir, fmt.Sprintf("%s.%s%s", fileName, time.Now().Format("150405"), r.config.Extension()))
 
 	// Read original file
 	data, err := r.fs.ReadFile(filePath)
@@ -66,14 +75,112 @@ func (r *FileBackupRepository) BackupFile(filePath string) error {
 		return fmt.Errorf("failed to read file %s for backup: %w", filePath, err)
 	}
 
-	// Write backup file
-	if err := r.fs.WriteFile(backupFile, data, 0644); err != nil {
+	data, err = r.compress(data)
+	if err != nil {
+		return fmt.Errorf("failed to compress %s: %w", filePath, err)
+	}
+
+	// The encryption tool writes the backup itself, so the file never
+	// holds the data unencrypted
+	if r.config.Encryption != "" {
+		if err := r.encrypt(data, backupFile); err != nil {
+			return fmt.Errorf("failed to encrypt the backup of %s: %w", filePath, err)
+		}
+	} else if err := r.fs.WriteFile(backupFile, data, 0644); err != nil {
 		return fmt.Errorf("failed to write backup file %s: %w", backupFile, err)
 	}
 
+	return r.recordOriginal(backupFile, filePath)
+}
+
+// recordOriginal adds a backup to the index of its date directory, which
+// maps backup names to the paths they were taken from
+func (r *FileBackupRepository) recordOriginal(backupFile, originalPath string) error {
+	index := filepath.Join(filepath.Dir(backupFile), backupIndexName)
+	data, _ := r.fs.ReadFile(index)
+	data = append(data, []byte(filepath.Base(backupFile)+"\t"+originalPath+"\n")...)
+	if err := r.fs.WriteFile(index, data, 0600); err != nil {
+		return fmt.Errorf("failed to write backup index %s: %w", index, err)
+	}
 	return nil
 }
 
+// readIndex returns the original paths of the backups of a date directory
+func (r *FileBackupRepository) readIndex(dir string) map[string]string {
+	originals := make(map[string]string)
+	data, err := r.fs.ReadFile(filepath.Join(dir, backupIndexName))
+	if err != nil {
+		return originals
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if name, path, ok := strings.Cut(line, "\t"); ok {
+			originals[name] = path
+		}
+	}
+	return originals
+}
+
+// ListAllBackups returns every backup of the backup directory, with the
+// path it was taken from where the index records it
+func (r *FileBackupRepository) ListAllBackups() ([]model.BackupFile, error) {
+	if _, err := r.fs.Stat(r.config.BackupDir); err != nil {
+		return nil, nil
+	}
+	output, err := r.commander.Execute("find", r.config.BackupDir, "-type", "f", "-name", "*.bak*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups in %s: %w", r.config.BackupDir, err)
+	}
+
+	indexes := make(map[string]map[string]string)
+	var backups []model.BackupFile
+	for _, path := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if path == "" {
+			continue
+		}
+		info, err := r.fs.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		dir := filepath.Dir(path)
+		if _, ok := indexes[dir]; !ok {
+			indexes[dir] = r.readIndex(dir)
+		}
+		created, ok := BackupCreated(path)
+		if !ok {
+			created = info.ModTime()
+		}
+		compression, encryption := BackupLayers(path)
+		backups = append(backups, model.BackupFile{
+			OriginalPath: indexes[dir][filepath.Base(path)],
+			BackupPath:   path,
+			Created:      created,
+			Size:         info.Size(),
+			Compression:  compression,
+			Encryption:   encryption,
+		})
+	}
+	return backups, nil
+}
+
+// CompareBackup reads a backup, decrypted and decompressed, and the live
+// file it would replace
+func (r *FileBackupRepository) CompareBackup(backupPath, originalPath string) (*model.BackupComparison, error) {
+	data, err := r.readBackup(backupPath)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &model.BackupComparison{BackupPath: backupPath, OriginalPath: originalPath, Backup: data}
+	if current, err := r.fs.ReadFile(originalPath); err == nil {
+		comparison.Current = current
+		comparison.CurrentExists = true
+	} else if _, statErr := r.fs.Stat(originalPath); statErr == nil {
+		return nil, fmt.Errorf("failed to read %s: %w", originalPath, err)
+	}
+	return comparison, nil
+}
+
 // ListBackups returns a list of all backups for a specific file
 func (r *FileBackupRepository) ListBackups(filePath string) ([]model.BackupFile, error) {
 	var backups []model.BackupFile
@@ -93,14 +200,17 @@ func (r *FileBackupRepository) ListBackups(filePath string) ([]model.BackupFile,
 		}
 
 		// Check if this is a backup of our file
-		if matched, err := filepath.Match(fmt.Sprintf("%s.*.bak", fileName), info.Name()); err != nil {
+		if matched, err := filepath.Match(fmt.Sprintf("%s.*.bak*", fileName), info.Name()); err != nil {
 			return fmt.Errorf("error matching pattern for file %s: %w", info.Name(), err)
 		} else if matched {
+			compression, encryption := BackupLayers(path)
 			backup := model.BackupFile{
 				OriginalPath: filePath,
 				BackupPath:   path,
 				Created:      info.ModTime(),
 				Size:         info.Size(),
+				Compression:  compression,
+				Encryption:   encryption,
 			}
 			backups = append(backups, backup)
 		}
@@ -113,7 +223,8 @@ func (r *FileBackupRepository) ListBackups(filePath string) ([]model.BackupFile,
 	return backups, nil
 }
 
-// RestoreBackup restores a file from backup
+// RestoreBackup restores a file from backup, decrypting and decompressing
+// it as its extension tells
 func (r *FileBackupRepository) RestoreBackup(backupPath, originalPath string) error {
 	// Check if backup exists
 	fileInfo, err := r.fs.Stat(backupPath)
@@ -129,26 +240,51 @@ func (r *FileBackupRepository) RestoreBackup(backupPath, originalPath string) er
 		return fmt.Errorf("backup path %s is a directory, not a file", backupPath)
 	}
 
-	// Read backup file
-	data, err := r.fs.ReadFile(backupPath)
-	if err != nil {
-		return fmt.Errorf("failed to read backup file %s: %w", backupPath, err)
-	}
-
 	// Create directory for restored file if needed
 	targetDir := filepath.Dir(originalPath)
 	if err := r.fs.MkdirAll(targetDir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory %s for restored file: %w", targetDir, err)
 	}
 
-	// Write restored file
-	if err := r.fs.WriteFile(originalPath, data, 0644); err != nil {
+	data, err := r.readBackup(backupPath)
+	if err != nil {
+		return err
+	}
+
+	// Write restored file, keeping the mode of the file it replaces
+	mode := os.FileMode(0644)
+	if info, err := r.fs.Stat(originalPath); err == nil && info.Mode().Perm() != 0 {
+		mode = info.Mode().Perm()
+	}
+	if err := r.fs.WriteFile(originalPath, data, mode); err != nil {
 		return fmt.Errorf("failed to write restored file %s: %w", originalPath, err)
 	}
 
 	return nil
 }
 
+// readBackup reads a backup, decrypting and decompressing it as its
+// extension tells
+func (r *FileBackupRepository) readBackup(backupPath string) ([]byte, error) {
+	compression, encryption := BackupLayers(backupPath)
+	var data []byte
+	var err error
+	if encryption != "" {
+		data, err = r.decrypt(backupPath, encryption)
+	} else {
+		data, err = r.fs.ReadFile(backupPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup file %s: %w", backupPath, err)
+	}
+
+	data, err = decompress(r.commander, data, compression)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress backup file %s: %w", backupPath, err)
+	}
+	return data, nil
+}
+
 // CleanupOldBackups removes backups older than specified date
 func (r *FileBackupRepository) CleanupOldBackups(before time.Time) error {
 	// Check if backup directory exists
@@ -247,8 +383,7 @@ func (r *FileBackupRepository) GetBackupConfig() (*model.BackupConfig, error) {
 // SetBackupConfig updates the backup configuration
 func (r *FileBackupRepository) SetBackupConfig(config model.BackupConfig) error {
 	// Update the configuration
-	r.config.Enabled = config.Enabled
-	r.config.BackupDir = config.BackupDir
+	*r.config = config
 
 	// If enabling backups, verify the directory exists and is writable
 	if r.config.Enabled {
@@ -257,3 +392,271 @@ func (r *FileBackupRepository) SetBackupConfig(config model.BackupConfig) error
 
 	return nil
 }
+
+// CheckBackupKeys encrypts a test file for the recipient and decrypts it
+// again, as a backup and its restore would
+func (r *FileBackupRepository) CheckBackupKeys() (*model.BackupKeyStatus, error) {
+	status := &model.BackupKeyStatus{Tool: r.config.Encryption, Recipient: r.config.Recipient}
+	if _, err := r.commander.Execute("which", status.Tool); err != nil {
+		status.Problem = fmt.Sprintf("%s is not installed", status.Tool)
+		return status, nil
+	}
+	status.Installed = true
+
+	if err := r.fs.MkdirAll(r.config.BackupDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory %s: %w", r.config.BackupDir, err)
+	}
+	testFile := filepath.Join(r.config.BackupDir, ".key_test."+status.Tool)
+	defer func() { _ = r.fs.Remove(testFile) }()
+
+	if err := r.encrypt([]byte(backupKeyTest), testFile); err != nil {
+		status.Problem = err.Error()
+		return status, nil
+	}
+	status.CanEncrypt = true
+
+	data, err := r.decrypt(testFile, status.Tool)
+	if err != nil {
+		status.Problem = err.Error()
+		return status, nil
+	}
+	if string(data) != backupKeyTest {
+		status.Problem = "the decrypted test file differs from the one encrypted"
+		return status, nil
+	}
+	status.CanDecrypt = true
+	return status, nil
+}
+
+// SyncRemote copies the date directories of the backup directory to the
+// remote target and removes remote ones past the retention. Only date
+// directories within the retention, or the last 90 days without one, are
+// copied.
+func (r *FileBackupRepository) SyncRemote() (*model.BackupSyncResult, error) {
+	remote := r.config.Remote
+	target, err := newBackupTarget(r.commander, remote)
+	if err != nil {
+		return nil, err
+	}
+	result := &model.BackupSyncResult{Target: remote.URL}
+
+	days := 90
+	if remote.RetentionDays > 0 {
+		days = remote.RetentionDays
+	}
+	for i := days - 1; i >= 0; i-- {
+		date := time.Now().AddDate(0, 0, -i).Format("2006-01-02")
+		dir := filepath.Join(r.config.BackupDir, date)
+		if info, err := r.fs.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		if err := target.upload(dir, date); err != nil {
+			return result, fmt.Errorf("failed to upload %s: %w", dir, err)
+		}
+		result.Uploaded = append(result.Uploaded, date)
+	}
+
+	if remote.RetentionDays <= 0 {
+		return result, nil
+	}
+	dates, err := target.list()
+	if err != nil {
+		return result, fmt.Errorf("failed to list %s: %w", remote.URL, err)
+	}
+	cutoff := time.Now().AddDate(0, 0, -remote.RetentionDays)
+	for _, date := range dates {
+		created, err := time.Parse("2006-01-02", date)
+		if err != nil || !created.Before(cutoff) {
+			continue
+		}
+		if err := target.remove(date); err != nil {
+			return result, fmt.Errorf("failed to remove %s from %s: %w", date, remote.URL, err)
+		}
+		result.Removed = append(result.Removed, date)
+	}
+	return result, nil
+}
+
+// CheckRemote writes a test file to the remote target and removes it, to
+// show the target is reachable and writable with the configured credentials
+func (r *FileBackupRepository) CheckRemote() error {
+	target, err := newBackupTarget(r.commander, r.config.Remote)
+	if err != nil {
+		return err
+	}
+
+	if err := r.fs.MkdirAll(r.config.BackupDir, 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory %s: %w", r.config.BackupDir, err)
+	}
+	testFile := filepath.Join(r.config.BackupDir, remoteTestName)
+	if err := r.fs.WriteFile(testFile, []byte("hardn remote backup test\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", testFile, err)
+	}
+	defer func() { _ = r.fs.Remove(testFile) }()
+
+	return target.check(testFile)
+}
+
+// compress compresses a backup with the configured format
+func (r *FileBackupRepository) compress(data []byte) ([]byte, error) {
+	switch r.config.Compression {
+	case model.BackupGzip:
+		level := gzip.DefaultCompression
+		if r.config.CompressionLevel != 0 {
+			level = r.config.CompressionLevel
+		}
+		var buf bytes.Buffer
+		gz, err := gzip.NewWriterLevel(&buf, level)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := gz.Write(data); err != nil {
+			return nil, err
+		}
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+
+	case model.BackupZstd:
+		args := []string{"-q", "-c"}
+		if r.config.CompressionLevel != 0 {
+			args = append(args, "-"+strconv.Itoa(r.config.CompressionLevel))
+		}
+		output, err := r.commander.ExecuteWithInput(string(data), "zstd", args...)
+		if err != nil {
+			return nil, fmt.Errorf("zstd failed: %w", err)
+		}
+		return output, nil
+	}
+	return data, nil
+}
+
+// decompress undoes the compression of a backup
+func decompress(commander interfaces.Commander, data []byte, compression string) ([]byte, error) {
+	switch compression {
+	case model.BackupGzip:
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		return io.ReadAll(gz)
+
+	case model.BackupZstd:
+		output, err := commander.ExecuteWithInput(string(data), "zstd", "-q", "-d", "-c")
+		if err != nil {
+			return nil, fmt.Errorf("zstd failed: %w", err)
+		}
+		return output, nil
+	}
+	return data, nil
+}
+
+// encrypt encrypts data for the configured recipient into path. An age
+// recipient starting with a slash is a recipients file.
+func (r *FileBackupRepository) encrypt(data []byte, path string) error {
+	tool := r.config.Encryption
+	if _, err := r.commander.Execute("which", tool); err != nil {
+		return fmt.Errorf("%s is required to encrypt backups", tool)
+	}
+
+	var args []string
+	switch tool {
+	case model.BackupAge:
+		flag := "-r"
+		if strings.HasPrefix(r.config.Recipient, "/") {
+			flag = "-R"
+		}
+		args = []string{flag, r.config.Recipient, "-o", path}
+	case model.BackupGPG:
+		args = []string{"--batch", "--yes", "--trust-model", "always",
+			"--recipient", r.config.Recipient, "--output", path, "--encrypt"}
+	default:
+		return fmt.Errorf("unknown backup encryption %q", tool)
+	}
+
+	if output, err := r.commander.ExecuteWithInput(string(data), tool, args...); err != nil {
+		// A backup meant to be encrypted is not left behind half written
+		_ = r.fs.Remove(path)
+		return fmt.Errorf("%s failed for %s: %w\nOutput: %s", tool, r.config.Recipient, err, string(output))
+	}
+	return nil
+}
+
+// decrypt decrypts an encrypted file to memory. The tool writes the
+// plaintext to its standard output, so it never reaches the disk and a
+// dry-run preview can decrypt too. age needs the configured identity file;
+// GnuPG uses the secret keys of root's keyring, and its messages go to
+// /dev/null so they do not mix with the plaintext.
+func (r *FileBackupRepository) decrypt(path string, tool string) ([]byte, error) {
+	if _, err := r.commander.Execute("which", tool); err != nil {
+		return nil, fmt.Errorf("%s is required to decrypt %s", tool, path)
+	}
+
+	var args []string
+	switch tool {
+	case model.BackupAge:
+		if r.config.Identity == "" {
+			return nil, fmt.Errorf("an age identity file is needed to decrypt %s; set backupEncryption.identity", path)
+		}
+		args = []string{"-d", "-i", r.config.Identity, path}
+	case model.BackupGPG:
+		args = []string{"--batch", "--quiet", "--log-file", "/dev/null", "--decrypt", path}
+	}
+
+	output, err := r.commander.Execute(tool, args...)
+	if err != nil {
+		// Plaintext written before the tool failed stays out of the error
+		return nil, fmt.Errorf("%s could not decrypt %s: %w%s", tool, path, err, toolMessages(output, tool))
+	}
+	return output, nil
+}
+
+// toolMessages returns the lines of output a tool printed about itself,
+// such as "age: error: no identity matched any of the recipients", each
+// starting with a newline
+func toolMessages(output []byte, tool string) string {
+	var messages strings.Builder
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, tool+": ") {
+			messages.WriteString("\n" + line)
+		}
+	}
+	return messages.String()
+}
+
+// BackupLayers returns the compression and encryption of a backup from
+// its extensions, such as ".bak.zst.age"
+func BackupLayers(path string) (compression string, encryption string) {
+	name := filepath.Base(path)
+	for _, tool := range []string{model.BackupAge, model.BackupGPG} {
+		if trimmed, ok := strings.CutSuffix(name, "."+tool); ok {
+			encryption, name = tool, trimmed
+			break
+		}
+	}
+	switch {
+	case strings.HasSuffix(name, ".bak.gz"):
+		compression = model.BackupGzip
+	case strings.HasSuffix(name, ".bak.zst"):
+		compression = model.BackupZstd
+	}
+	return compression, encryption
+}
+
+// BackupCreated returns when a backup was made from its date directory
+// and the time in its name, such as 2024-05-01/sshd_config.101500.bak
+func BackupCreated(path string) (time.Time, bool) {
+	name := filepath.Base(path)
+	bak := strings.Index(name, ".bak")
+	if bak < 7 || name[bak-7] != '.' {
+		return time.Time{}, false
+	}
+	created, err := time.ParseInLocation("2006-01-02 150405",
+		filepath.Base(filepath.Dir(path))+" "+name[bak-6:bak], time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return created, true
+}
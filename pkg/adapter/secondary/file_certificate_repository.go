@@ -0,0 +1,110 @@
+// pkg/adapter/secondary/file_certificate_repository.go
+package secondary
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// certificateSearchDepth limits how far certificate directories are searched;
+// Let's Encrypt keeps certificates two levels below /etc/letsencrypt/live
+const certificateSearchDepth = "3"
+
+// FileCertificateRepository implements CertificateRepository for PEM files
+type FileCertificateRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+}
+
+// NewFileCertificateRepository creates a new FileCertificateRepository
+func NewFileCertificateRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+) secondary.CertificateRepository {
+	return &FileCertificateRepository{
+		fs:        fs,
+		commander: commander,
+	}
+}
+
+// FindCertificateFiles expands paths into certificate files; directories
+// are searched and missing paths are skipped
+func (r *FileCertificateRepository) FindCertificateFiles(paths []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(file string) {
+		if file != "" && !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+
+	for _, path := range paths {
+		info, err := r.fs.Stat(path)
+		if err != nil {
+			continue
+		}
+		if !info.IsDir() {
+			add(path)
+			continue
+		}
+
+		// -L follows the symlinks Let's Encrypt uses for live certificates
+		output, err := r.commander.Execute("find", "-L", path, "-maxdepth", certificateSearchDepth,
+			"-type", "f", "(", "-name", "*.pem", "-o", "-name", "*.crt", "-o", "-name", "*.cer", ")")
+		if err != nil {
+			return nil, fmt.Errorf("failed to search %s for certificates: %w", path, err)
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			add(strings.TrimSpace(line))
+		}
+	}
+
+	return files, nil
+}
+
+// ReadCertificate returns the first certificate in a PEM file
+func (r *FileCertificateRepository) ReadCertificate(path string) (*model.Certificate, error) {
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("no certificate found in %s", path)
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate in %s: %w", path, err)
+		}
+
+		subject := cert.Subject.CommonName
+		if subject == "" && len(cert.DNSNames) > 0 {
+			subject = cert.DNSNames[0]
+		}
+		if subject == "" {
+			subject = cert.Subject.String()
+		}
+
+		return &model.Certificate{
+			Path:     path,
+			Subject:  subject,
+			Issuer:   cert.Issuer.String(),
+			Serial:   cert.SerialNumber.Text(16),
+			NotAfter: cert.NotAfter,
+		}, nil
+	}
+}
@@ -15,6 +15,7 @@ import (
 type FileDNSRepository struct {
 	fs        interfaces.FileSystem
 	commander interfaces.Commander
+	services  secondary.ServiceManager
 	osType    string
 }
 
@@ -27,42 +28,59 @@ func NewFileDNSRepository(
 	return &FileDNSRepository{
 		fs:        fs,
 		commander: commander,
+		services:  NewServiceManager(commander, osType),
 		osType:    osType,
 	}
 }
 
+// DNS resolver backends detected by the repository
+const (
+	dnsImplSystemdResolved = "systemd-resolved"
+	dnsImplResolvconf      = "resolvconf"
+	dnsImplDirect          = "direct (/etc/resolv.conf)"
+)
+
 // SaveDNSConfig persists the DNS configuration
 func (r *FileDNSRepository) SaveDNSConfig(config model.DNSConfig) error {
-	// Check if systemd-resolved is active
-	systemdActive := false
-	if _, err := r.commander.Execute("systemctl", "is-active", "systemd-resolved"); err == nil {
-		systemdActive = true
-	}
-
-	// Check if resolvconf is installed
-	resolvconfInstalled := false
-	if _, err := r.commander.Execute("which", "resolvconf"); err == nil {
-		resolvconfInstalled = true
-	}
-
-	if systemdActive {
+	switch r.detectImplementation() {
+	case dnsImplSystemdResolved:
 		return r.configureSystemdResolved(config)
-	} else if resolvconfInstalled {
+	case dnsImplResolvconf:
 		return r.configureResolvconf(config)
-	} else {
+	default:
 		return r.configureDirectResolv(config)
 	}
 }
 
 // GetDNSConfig retrieves the current DNS configuration
 func (r *FileDNSRepository) GetDNSConfig() (*model.DNSConfig, error) {
+	config := model.DNSConfig{
+		Implementation: r.detectImplementation(),
+	}
+
+	// Ask systemd-resolved for upstream servers; resolv.conf only lists the stub
+	if config.Implementation == dnsImplSystemdResolved {
+		if output, err := r.commander.Execute("resolvectl", "dns"); err == nil {
+			lines := strings.Split(string(output), "\n")
+			for _, line := range lines {
+				parts := strings.SplitN(line, ":", 2)
+				if len(parts) == 2 {
+					config.Nameservers = append(config.Nameservers, strings.Fields(parts[1])...)
+				}
+			}
+		}
+	}
+
 	// Read /etc/resolv.conf to get current configuration
 	data, err := r.fs.ReadFile("/etc/resolv.conf")
 	if err != nil {
+		if len(config.Nameservers) > 0 {
+			return &config, nil
+		}
 		return nil, fmt.Errorf("failed to read resolv.conf: %w", err)
 	}
 
-	config := model.DNSConfig{}
+	fromResolved := len(config.Nameservers) > 0
 
 	// Parse file
 	lines := strings.Split(string(data), "\n")
@@ -83,7 +101,9 @@ func (r *FileDNSRepository) GetDNSConfig() (*model.DNSConfig, error) {
 
 		switch directive {
 		case "nameserver":
-			config.Nameservers = append(config.Nameservers, value)
+			if !fromResolved {
+				config.Nameservers = append(config.Nameservers, value)
+			}
 		case "domain":
 			config.Domain = value
 		case "search":
@@ -94,6 +114,21 @@ func (r *FileDNSRepository) GetDNSConfig() (*model.DNSConfig, error) {
 	return &config, nil
 }
 
+// detectImplementation determines which resolver backend manages DNS
+func (r *FileDNSRepository) detectImplementation() string {
+	// Check if systemd-resolved is active; it never is on OpenRC
+	if resolved, err := r.services.Status("systemd-resolved"); err == nil && resolved.Active {
+		return dnsImplSystemdResolved
+	}
+
+	// Check if resolvconf is installed
+	if _, err := r.commander.Execute("which", "resolvconf"); err == nil {
+		return dnsImplResolvconf
+	}
+
+	return dnsImplDirect
+}
+
 // configureSystemdResolved configures DNS using systemd-resolved
 func (r *FileDNSRepository) configureSystemdResolved(config model.DNSConfig) error {
 	// Create resolved.conf content
@@ -112,7 +147,7 @@ func (r *FileDNSRepository) configureSystemdResolved(config model.DNSConfig) err
 	}
 
 	// Restart systemd-resolved
-	if _, err := r.commander.Execute("systemctl", "restart", "systemd-resolved"); err != nil {
+	if err := r.services.Restart("systemd-resolved"); err != nil {
 		return fmt.Errorf("failed to restart systemd-resolved: %w", err)
 	}
 
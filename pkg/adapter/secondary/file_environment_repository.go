@@ -68,30 +68,12 @@ func (r *FileEnvironmentRepository) SetupSudoPreservation(username string) error
 	// env_keep directive
 	content += fmt.Sprintf("Defaults:%s env_keep += \"HARDN_CONFIG\"\n", username)
 
-	// Create a temporary file for validation
-	tempDir := os.TempDir()
-	tempFile := filepath.Join(tempDir, "hardn_sudoers_temp")
-	if err := r.fs.WriteFile(tempFile, []byte(content), 0440); err != nil {
-		return fmt.Errorf("failed to create temporary sudoers file at %s: %w", tempFile, err)
-	}
-
-	// Validate the sudoers file
-	_, err = r.commander.Execute("visudo", "-c", "-f", tempFile)
+	// Validate the sudoers file, read by visudo from stdin
+	_, err = r.commander.ExecuteWithInput(content, "visudo", "-c", "-f", "-")
 	if err != nil {
-		// Clean up temp file
-		if err := r.fs.Remove(tempFile); err != nil {
-			// Log warning but don't fail the operation since this is just cleanup
-			fmt.Printf("Warning: Failed to remove test file %s: %v\n", tempFile, err)
-		}
 		return fmt.Errorf("invalid sudoers configuration: %w", err)
 	}
 
-	// Clean up temp file
-	if err := r.fs.Remove(tempFile); err != nil {
-		// Log warning but don't fail the operation since this is just cleanup
-		fmt.Printf("Warning: Failed to remove test file %s: %v\n", tempFile, err)
-	}
-
 	// Write the validated content to the actual sudoers file
 	if err := r.fs.WriteFile(sudoersFile, []byte(content), 0440); err != nil {
 		return fmt.Errorf("failed to write sudoers file %s: %w", sudoersFile, err)
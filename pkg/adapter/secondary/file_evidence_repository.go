@@ -0,0 +1,157 @@
+// pkg/adapter/secondary/file_evidence_repository.go
+package secondary
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// FileEvidenceRepository implements EvidenceRepository using file operations
+type FileEvidenceRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	now       func() time.Time
+}
+
+// NewFileEvidenceRepository creates a new FileEvidenceRepository
+func NewFileEvidenceRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+) secondary.EvidenceRepository {
+	return &FileEvidenceRepository{
+		fs:        fs,
+		commander: commander,
+		now:       time.Now,
+	}
+}
+
+// ReadFile returns a file's contents, or nil when it does not exist
+func (r *FileEvidenceRepository) ReadFile(path string) ([]byte, error) {
+	if _, err := r.fs.Stat(path); err != nil {
+		return nil, nil
+	}
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// ListBackups lists the files under a backup directory with their
+// checksums; the backups themselves stay out of the bundle
+func (r *FileEvidenceRepository) ListBackups(dir string) ([]model.EvidenceBackup, error) {
+	if _, err := r.fs.Stat(dir); err != nil {
+		return nil, nil
+	}
+	output, err := r.commander.Execute("find", dir, "-type", "f")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups in %s: %w", dir, err)
+	}
+
+	var backups []model.EvidenceBackup
+	for _, path := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if path == "" {
+			continue
+		}
+		info, err := r.fs.Stat(path)
+		if err != nil {
+			continue
+		}
+		data, err := r.fs.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup %s: %w", path, err)
+		}
+		sum := sha256.Sum256(data)
+		backups = append(backups, model.EvidenceBackup{
+			Path:     path,
+			Size:     int64(len(data)),
+			Modified: info.ModTime(),
+			SHA256:   hex.EncodeToString(sum[:]),
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].Path < backups[j].Path })
+	return backups, nil
+}
+
+// LoadTemplate reads an evidence template from a YAML file
+func (r *FileEvidenceRepository) LoadTemplate(path string) (model.EvidenceTemplate, error) {
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return model.EvidenceTemplate{}, fmt.Errorf("failed to read evidence template %s: %w", path, err)
+	}
+	var template model.EvidenceTemplate
+	if err := yaml.Unmarshal(data, &template); err != nil {
+		return model.EvidenceTemplate{}, fmt.Errorf("failed to parse evidence template %s: %w", path, err)
+	}
+	return template, nil
+}
+
+// WriteBundle writes the items to a gzip-compressed tar archive readable
+// only by root, since it holds copies of sensitive configuration
+func (r *FileEvidenceRepository) WriteBundle(path string, items []model.EvidenceItem) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	modified := r.now()
+	for _, item := range items {
+		header := &tar.Header{
+			Name:    item.Path,
+			Mode:    0600,
+			Size:    int64(len(item.Data)),
+			ModTime: modified,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to add %s to the bundle: %w", item.Path, err)
+		}
+		if _, err := tw.Write(item.Data); err != nil {
+			return fmt.Errorf("failed to add %s to the bundle: %w", item.Path, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write the bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write the bundle: %w", err)
+	}
+	if err := r.fs.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// EncryptBundle encrypts a bundle for a GnuPG recipient, whose public key
+// must be in root's keyring. The unencrypted bundle is removed either way.
+func (r *FileEvidenceRepository) EncryptBundle(path string, recipient string) (string, error) {
+	if _, err := r.commander.Execute("which", "gpg"); err != nil {
+		return "", fmt.Errorf("gpg is required to encrypt the bundle")
+	}
+
+	encrypted := path + ".gpg"
+	output, err := r.commander.Execute("gpg", "--batch", "--yes", "--trust-model", "always",
+		"--recipient", recipient, "--output", encrypted, "--encrypt", path)
+	if err != nil {
+		// A bundle meant to be encrypted is not left behind in the clear
+		_ = r.fs.Remove(path)
+		return "", fmt.Errorf("failed to encrypt the bundle for %s: %w\nOutput: %s", recipient, err, string(output))
+	}
+	if err := r.fs.Remove(path); err != nil {
+		return "", fmt.Errorf("failed to remove the unencrypted bundle %s: %w", path, err)
+	}
+	return encrypted, nil
+}
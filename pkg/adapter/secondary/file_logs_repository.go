@@ -8,6 +8,7 @@ import (
 
 	"github.com/abbott/hardn/pkg/domain/model"
 	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/logging"
 	"github.com/abbott/hardn/pkg/port/secondary"
 )
 
@@ -48,23 +49,16 @@ func (r *FileLogsRepository) GetLogs() ([]model.LogEntry, error) {
 	for scanner.Scan() {
 		line := scanner.Text()
 
-		// Parse log line (assuming a simple format of "TIME LEVEL: MESSAGE")
-		parts := strings.SplitN(line, " ", 3)
-		if len(parts) >= 3 {
-			// Extract time and message parts
-			timeStr := parts[0]
-			levelStr := strings.TrimSuffix(parts[1], ":")
-			messageStr := parts[2]
-
-			// Create log entry
-			entry := model.LogEntry{
-				Time:    timeStr,
-				Level:   levelStr,
-				Message: messageStr,
-			}
-
-			entries = append(entries, entry)
+		// Lines are text or JSON, depending on the configured log format
+		parsed, ok := logging.ParseEntry(line)
+		if !ok {
+			continue
 		}
+		entries = append(entries, model.LogEntry{
+			Time:    parsed.Time.Format("2006-01-02 15:04:05"),
+			Level:   parsed.Label,
+			Message: parsed.Message,
+		})
 	}
 
 	return entries, nil
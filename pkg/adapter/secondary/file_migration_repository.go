@@ -0,0 +1,54 @@
+// pkg/adapter/secondary/file_migration_repository.go
+package secondary
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// FileMigrationRepository implements MigrationRepository using file operations
+type FileMigrationRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+}
+
+// NewFileMigrationRepository creates a new FileMigrationRepository
+func NewFileMigrationRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+) secondary.MigrationRepository {
+	return &FileMigrationRepository{
+		fs:        fs,
+		commander: commander,
+	}
+}
+
+// ReadArtifact returns a file, or nil when it does not exist
+func (r *FileMigrationRepository) ReadArtifact(path string) ([]byte, error) {
+	if _, err := r.fs.Stat(path); err != nil {
+		return nil, nil
+	}
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// ListArtifacts returns the files directly inside dir, sorted
+func (r *FileMigrationRepository) ListArtifacts(dir string) ([]string, error) {
+	if _, err := r.fs.Stat(dir); err != nil {
+		return nil, nil
+	}
+	output, err := r.commander.Execute("find", dir, "-mindepth", "1", "-maxdepth", "1", "-type", "f")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	paths := strings.Fields(string(output))
+	sort.Strings(paths)
+	return paths, nil
+}
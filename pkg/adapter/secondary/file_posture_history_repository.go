@@ -0,0 +1,89 @@
+// pkg/adapter/secondary/file_posture_history_repository.go
+package secondary
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// FilePostureHistoryRepository implements PostureHistoryRepository with a
+// JSON lines file
+type FilePostureHistoryRepository struct {
+	fs   interfaces.FileSystem
+	path string
+}
+
+// NewFilePostureHistoryRepository creates a new FilePostureHistoryRepository
+func NewFilePostureHistoryRepository(
+	fs interfaces.FileSystem,
+	path string,
+) secondary.PostureHistoryRepository {
+	return &FilePostureHistoryRepository{
+		fs:   fs,
+		path: path,
+	}
+}
+
+// ListSnapshots returns the snapshots in the history, oldest first; lines
+// that do not parse, such as one cut short by a full disk, are skipped
+func (r *FilePostureHistoryRepository) ListSnapshots() ([]model.PostureSnapshot, error) {
+	if _, err := r.fs.Stat(r.path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	data, err := r.fs.ReadFile(r.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read posture history: %w", err)
+	}
+
+	var snapshots []model.PostureSnapshot
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var snapshot model.PostureSnapshot
+		if err := json.Unmarshal(line, &snapshot); err != nil {
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// AppendSnapshot adds a snapshot to the history. The FileSystem interface
+// cannot append, so the file is rewritten, which also drops the snapshots
+// beyond keep.
+func (r *FilePostureHistoryRepository) AppendSnapshot(snapshot model.PostureSnapshot, keep int) error {
+	snapshots, err := r.ListSnapshots()
+	if err != nil {
+		return err
+	}
+	snapshots = append(snapshots, snapshot)
+	if keep > 0 && len(snapshots) > keep {
+		snapshots = snapshots[len(snapshots)-keep:]
+	}
+
+	var buf bytes.Buffer
+	for _, s := range snapshots {
+		line, err := json.Marshal(s)
+		if err != nil {
+			return fmt.Errorf("failed to encode posture snapshot: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	if err := r.fs.MkdirAll(filepath.Dir(r.path), 0700); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", r.path, err)
+	}
+	if err := r.fs.WriteFile(r.path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write posture history: %w", err)
+	}
+	return nil
+}
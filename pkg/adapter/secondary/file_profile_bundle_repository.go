@@ -0,0 +1,192 @@
+// pkg/adapter/secondary/file_profile_bundle_repository.go
+package secondary
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// FileProfileBundleRepository implements ProfileBundleRepository with YAML
+// files signed by GnuPG
+type FileProfileBundleRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+}
+
+// NewFileProfileBundleRepository creates a new FileProfileBundleRepository
+func NewFileProfileBundleRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+) secondary.ProfileBundleRepository {
+	return &FileProfileBundleRepository{
+		fs:        fs,
+		commander: commander,
+	}
+}
+
+// ReadFile returns a file's contents, or nil when it does not exist
+func (r *FileProfileBundleRepository) ReadFile(path string) ([]byte, error) {
+	if _, err := r.fs.Stat(path); err != nil {
+		return nil, nil
+	}
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// WriteFile writes a file, creating its directory
+func (r *FileProfileBundleRepository) WriteFile(path string, data []byte) error {
+	if err := r.fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := r.fs.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ListSysctlFiles lists the regular .conf files of /etc/sysctl.d. Symbolic
+// links, such as Debian's 99-sysctl.conf, are left out.
+func (r *FileProfileBundleRepository) ListSysctlFiles() ([]string, error) {
+	if _, err := r.fs.Stat(model.SysctlDropInDir); err != nil {
+		return nil, nil
+	}
+	output, err := r.commander.Execute("find", model.SysctlDropInDir, "-maxdepth", "1", "-type", "f", "-name", "*.conf")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", model.SysctlDropInDir, err)
+	}
+
+	var files []string
+	for _, path := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if path != "" {
+			files = append(files, path)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ReloadSysctl applies the sysctl configuration files
+func (r *FileProfileBundleRepository) ReloadSysctl() error {
+	if output, err := r.commander.Execute("sysctl", "--system"); err != nil {
+		return fmt.Errorf("failed to apply the sysctl settings: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// ParseBundle parses a profile bundle, rejecting unknown fields
+func (r *FileProfileBundleRepository) ParseBundle(path string, data []byte) (*model.ProfileBundle, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var bundle model.ProfileBundle
+	if err := decoder.Decode(&bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse profile bundle %s: %w", path, err)
+	}
+	return &bundle, nil
+}
+
+// WriteBundle writes a profile bundle readable only by root, since hardn's
+// configuration may hold notification credentials
+func (r *FileProfileBundleRepository) WriteBundle(path string, bundle *model.ProfileBundle) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(bundle); err != nil {
+		return fmt.Errorf("failed to encode the profile bundle: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode the profile bundle: %w", err)
+	}
+
+	if err := r.fs.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// SignBundle writes an ASCII-armored detached signature next to a bundle
+// with a secret key of root's keyring
+func (r *FileProfileBundleRepository) SignBundle(path string, key string) (string, error) {
+	if _, err := r.commander.Execute("which", "gpg"); err != nil {
+		return "", fmt.Errorf("gpg is required to sign the bundle")
+	}
+
+	signature := path + model.ProfileSignatureSuffix
+	output, err := r.commander.Execute("gpg", "--batch", "--yes", "--armor", "--local-user", key,
+		"--output", signature, "--detach-sign", path)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign the bundle with %s: %w\nOutput: %s", key, err, string(output))
+	}
+	return signature, nil
+}
+
+// VerifyBundle checks the detached signature next to a bundle against the
+// bundle's data, passed to gpg on stdin. gpg accepts a signature by any key
+// of root's keyring, so the signing key must also be one of signers.
+func (r *FileProfileBundleRepository) VerifyBundle(path string, data []byte, signers []string) (string, error) {
+	signature := path + model.ProfileSignatureSuffix
+	if _, err := r.fs.Stat(signature); err != nil {
+		return "", nil
+	}
+	if len(signers) == 0 {
+		return "", fmt.Errorf("%s is signed, but no profileSigners are configured to check the signer against", path)
+	}
+	if _, err := r.commander.Execute("which", "gpg"); err != nil {
+		return "", fmt.Errorf("gpg is required to verify %s", signature)
+	}
+
+	output, err := r.commander.ExecuteWithInput(string(data), "gpg", "--batch", "--status-fd", "1", "--verify", signature, "-")
+	if err != nil {
+		return "", fmt.Errorf("the signature %s does not verify: %w\nOutput: %s", signature, err, string(output))
+	}
+	signer, ok := GPGValidSigner(output, signers)
+	if !ok {
+		return "", fmt.Errorf("the signature %s was not made by a key of profileSigners", signature)
+	}
+	return signer, nil
+}
+
+// GPGValidSigner returns the fingerprint of signers a good signature in gpg
+// --status-fd output was made with. VALIDSIG carries the fingerprint of the
+// signing key and, last, that of its primary key; either may be pinned.
+func GPGValidSigner(output []byte, signers []string) (string, bool) {
+	good := false
+	var valid []string
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != "[GNUPG:]" {
+			continue
+		}
+		switch fields[1] {
+		case "GOODSIG":
+			good = true
+		case "VALIDSIG":
+			valid = append(valid, fields[2])
+			if len(fields) > 11 {
+				valid = append(valid, fields[11])
+			}
+		}
+	}
+	if !good {
+		return "", false
+	}
+	for _, fingerprint := range valid {
+		for _, signer := range signers {
+			if model.NormalizeFingerprint(signer) == model.NormalizeFingerprint(fingerprint) {
+				return model.NormalizeFingerprint(fingerprint), true
+			}
+		}
+	}
+	return "", false
+}
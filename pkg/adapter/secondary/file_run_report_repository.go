@@ -0,0 +1,212 @@
+// pkg/adapter/secondary/file_run_report_repository.go
+package secondary
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/port/secondary"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// maxReportDiffInput is the largest file content diffed in a report; the
+// diff of larger files would be too slow to compute and too long to read
+const maxReportDiffInput = 64 * 1024
+
+// FileRunReportRepository implements RunReportRepository with a JSON file
+// per report and an index, since the FileSystem interface cannot list
+// directories
+type FileRunReportRepository struct {
+	fs         interfaces.FileSystem
+	reportsDir string
+	runsDir    string
+}
+
+// NewFileRunReportRepository creates a new FileRunReportRepository. The
+// changes of runs are read from runsDir.
+func NewFileRunReportRepository(
+	fs interfaces.FileSystem,
+	reportsDir string,
+	runsDir string,
+) secondary.RunReportRepository {
+	return &FileRunReportRepository{
+		fs:         fs,
+		reportsDir: reportsDir,
+		runsDir:    runsDir,
+	}
+}
+
+// SaveReport writes the report and adds it to the index, removing the
+// oldest reports beyond keep
+func (r *FileRunReportRepository) SaveReport(report model.RunReport, keep int) error {
+	if report.ID == "" || filepath.Base(report.ID) != report.ID {
+		return fmt.Errorf("invalid report ID %q", report.ID)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report %s: %w", report.ID, err)
+	}
+	if err := r.fs.MkdirAll(r.reportsDir, 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", r.reportsDir, err)
+	}
+	if err := r.fs.WriteFile(r.reportPath(report.ID), data, 0600); err != nil {
+		return fmt.Errorf("failed to write report %s: %w", report.ID, err)
+	}
+
+	ids, err := r.readIndex()
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(ids, report.ID) {
+		ids = append(ids, report.ID)
+	}
+	if keep > 0 && len(ids) > keep {
+		for _, old := range ids[:len(ids)-keep] {
+			if err := r.fs.Remove(r.reportPath(old)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove report %s: %w", old, err)
+			}
+		}
+		ids = ids[len(ids)-keep:]
+	}
+
+	data, err = json.MarshalIndent(ids, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := r.fs.WriteFile(filepath.Join(r.reportsDir, runIndexFile), data, 0600); err != nil {
+		return fmt.Errorf("failed to update report index: %w", err)
+	}
+	return nil
+}
+
+// GetReport reads the report with the given ID
+func (r *FileRunReportRepository) GetReport(id string) (*model.RunReport, error) {
+	if id == "" || filepath.Base(id) != id {
+		return nil, fmt.Errorf("invalid report ID %q", id)
+	}
+
+	path := r.reportPath(id)
+	if _, err := r.fs.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("report %s not found", id)
+	}
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report %s: %w", id, err)
+	}
+
+	var report model.RunReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("invalid report %s: %w", id, err)
+	}
+	return &report, nil
+}
+
+// ListReports returns the reports in the index, oldest first; reports
+// whose file is missing are skipped
+func (r *FileRunReportRepository) ListReports() ([]model.RunReport, error) {
+	ids, err := r.readIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []model.RunReport
+	for _, id := range ids {
+		report, err := r.GetReport(id)
+		if err != nil {
+			continue
+		}
+		reports = append(reports, *report)
+	}
+	return reports, nil
+}
+
+// RunChanges lists the changes of a run from its manifest. Files are
+// diffed against their saved copy unless they hold secrets, are binary or
+// are too large.
+func (r *FileRunReportRepository) RunChanges(runID string) ([]model.RunReportChange, error) {
+	run, err := NewFileRunRepository(r.fs, nil, r.runsDir).GetRun(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]model.RunReportChange, 0, len(run.Changes))
+	for _, change := range run.Changes {
+		entry := model.RunReportChange{
+			Kind:     change.Kind,
+			Subject:  change.Name,
+			Summary:  change.Summary,
+			Reverted: change.IsReverted(),
+		}
+		if change.Kind == model.RunChangeFile {
+			entry.Subject = change.Path
+			entry.Diff, entry.Note = r.fileDiff(runID, change)
+		}
+		changes = append(changes, entry)
+	}
+	return changes, nil
+}
+
+// fileDiff returns the diff of a file the run changed, or a note saying
+// why it is left out
+func (r *FileRunReportRepository) fileDiff(runID string, change model.RunChange) (string, string) {
+	if logging.SensitiveFile(change.Path) {
+		return "", "contents not shown, the file holds secrets"
+	}
+
+	var before, after []byte
+	if change.Existed {
+		data, err := r.fs.ReadFile(filepath.Join(r.runsDir, runID, change.Backup))
+		if err != nil {
+			return "", "saved copy not readable"
+		}
+		before = data
+	}
+	if _, err := r.fs.Stat(change.Path); err == nil {
+		data, err := r.fs.ReadFile(change.Path)
+		if err != nil {
+			return "", "file not readable"
+		}
+		after = data
+	}
+
+	switch {
+	case len(before) > maxReportDiffInput || len(after) > maxReportDiffInput:
+		return "", "file too large to diff"
+	case bytes.IndexByte(before, 0) >= 0 || bytes.IndexByte(after, 0) >= 0:
+		return "", "binary file"
+	case logging.SensitiveFile(change.Path, before, after):
+		return "", "contents not shown, the file holds secrets"
+	}
+	return style.PlainUnifiedDiff(change.Path, before, after), ""
+}
+
+// readIndex returns the IDs of the saved reports, oldest first
+func (r *FileRunReportRepository) readIndex() ([]string, error) {
+	path := filepath.Join(r.reportsDir, runIndexFile)
+	if _, err := r.fs.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report index: %w", err)
+	}
+
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, fmt.Errorf("invalid report index: %w", err)
+	}
+	return ids, nil
+}
+
+// reportPath returns the file of a report
+func (r *FileRunReportRepository) reportPath(id string) string {
+	return filepath.Join(r.reportsDir, id+".json")
+}
@@ -0,0 +1,196 @@
+// pkg/adapter/secondary/file_run_repository.go
+package secondary
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// Files in the runs directory. The index lists run IDs in the order the
+// runs started, since the FileSystem interface cannot list directories.
+const (
+	runIndexFile    = "index.json"
+	runManifestFile = "manifest.json"
+	runFilesDir     = "files"
+)
+
+// FileRunRepository implements RunRepository with manifests on disk
+type FileRunRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	runsDir   string
+}
+
+// NewFileRunRepository creates a new FileRunRepository
+func NewFileRunRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	runsDir string,
+) secondary.RunRepository {
+	return &FileRunRepository{
+		fs:        fs,
+		commander: commander,
+		runsDir:   runsDir,
+	}
+}
+
+// ListRuns returns the runs in the index, oldest first; runs whose
+// manifest is missing are skipped
+func (r *FileRunRepository) ListRuns() ([]model.RunManifest, error) {
+	ids, err := readRunIndex(r.fs, r.runsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []model.RunManifest
+	for _, id := range ids {
+		run, err := r.GetRun(id)
+		if err != nil {
+			continue
+		}
+		runs = append(runs, *run)
+	}
+	return runs, nil
+}
+
+// GetRun reads the manifest of a run
+func (r *FileRunRepository) GetRun(id string) (*model.RunManifest, error) {
+	if id == "" || filepath.Base(id) != id {
+		return nil, fmt.Errorf("invalid run ID %q", id)
+	}
+
+	path := filepath.Join(r.runsDir, id, runManifestFile)
+	if _, err := r.fs.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("run %s not found", id)
+	}
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run %s: %w", id, err)
+	}
+
+	var run model.RunManifest
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("invalid manifest for run %s: %w", id, err)
+	}
+	return &run, nil
+}
+
+// SaveRun writes the manifest of a run
+func (r *FileRunRepository) SaveRun(run model.RunManifest) error {
+	return writeRunManifest(r.fs, r.runsDir, run)
+}
+
+// RevertChange restores the state from before a change
+func (r *FileRunRepository) RevertChange(runID string, change model.RunChange, force bool) error {
+	if change.Kind == model.RunChangeFile {
+		return r.restoreFile(runID, change, force)
+	}
+
+	for _, command := range change.Undo {
+		if len(command) == 0 {
+			continue
+		}
+		if output, err := r.commander.Execute(command[0], command[1:]...); err != nil {
+			return fmt.Errorf("failed to run %s: %w\nOutput: %s", strings.Join(command, " "), err, string(output))
+		}
+	}
+	return nil
+}
+
+// restoreFile puts back the file as it was before the run, or removes it
+// if the run created it
+func (r *FileRunRepository) restoreFile(runID string, change model.RunChange, force bool) error {
+	if !force && !change.External {
+		current, err := fileChecksum(r.fs, change.Path)
+		if err != nil {
+			return err
+		}
+		if current != change.Checksum {
+			return fmt.Errorf("%s was modified after the run", change.Path)
+		}
+	}
+
+	if !change.Existed {
+		if err := r.fs.Remove(change.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", change.Path, err)
+		}
+		return nil
+	}
+
+	data, err := r.fs.ReadFile(filepath.Join(r.runsDir, runID, change.Backup))
+	if err != nil {
+		return fmt.Errorf("failed to read saved copy of %s: %w", change.Path, err)
+	}
+	if err := r.fs.MkdirAll(filepath.Dir(change.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", change.Path, err)
+	}
+	if err := r.fs.WriteFile(change.Path, data, os.FileMode(change.Mode)); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", change.Path, err)
+	}
+	return nil
+}
+
+// readRunIndex returns the IDs of the recorded runs, oldest first
+func readRunIndex(fs interfaces.FileSystem, runsDir string) ([]string, error) {
+	path := filepath.Join(runsDir, runIndexFile)
+	if _, err := fs.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run index: %w", err)
+	}
+
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, fmt.Errorf("invalid run index: %w", err)
+	}
+	return ids, nil
+}
+
+// writeRunIndex replaces the list of recorded runs
+func writeRunIndex(fs interfaces.FileSystem, runsDir string, ids []string) error {
+	data, err := json.MarshalIndent(ids, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fs.WriteFile(filepath.Join(runsDir, runIndexFile), data, 0600)
+}
+
+// writeRunManifest writes the manifest into the run's directory
+func writeRunManifest(fs interfaces.FileSystem, runsDir string, run model.RunManifest) error {
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := fs.WriteFile(filepath.Join(runsDir, run.ID, runManifestFile), data, 0600); err != nil {
+		return fmt.Errorf("failed to write manifest for run %s: %w", run.ID, err)
+	}
+	return nil
+}
+
+// fileChecksum returns the sha256 of a file, or "" if it does not exist
+func fileChecksum(fs interfaces.FileSystem, path string) (string, error) {
+	if _, err := fs.Stat(path); os.IsNotExist(err) {
+		return "", nil
+	}
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return dataChecksum(data), nil
+}
+
+func dataChecksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,168 @@
+// pkg/adapter/secondary/file_share_repository.go
+package secondary
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// FileShareRepository implements ShareRepository by reading the NFS and
+// Samba configuration files
+type FileShareRepository struct {
+	fs interfaces.FileSystem
+}
+
+// NewFileShareRepository creates a new FileShareRepository
+func NewFileShareRepository(fs interfaces.FileSystem) secondary.ShareRepository {
+	return &FileShareRepository{
+		fs: fs,
+	}
+}
+
+// GetNFSExports returns the exports in /etc/exports; nil when absent
+func (r *FileShareRepository) GetNFSExports() ([]model.NFSExport, error) {
+	if _, err := r.fs.Stat(model.NFSExportsPath); err != nil {
+		return nil, nil
+	}
+
+	data, err := r.fs.ReadFile(model.NFSExportsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", model.NFSExportsPath, err)
+	}
+
+	return ParseNFSExports(data), nil
+}
+
+// GetSambaConfig returns the parsed smb.conf; nil when absent
+func (r *FileShareRepository) GetSambaConfig() (*model.SambaConfig, error) {
+	if _, err := r.fs.Stat(model.SambaConfPath); err != nil {
+		return nil, nil
+	}
+
+	data, err := r.fs.ReadFile(model.SambaConfPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", model.SambaConfPath, err)
+	}
+
+	return ParseSambaConfig(data), nil
+}
+
+// ParseNFSExports parses exports(5) lines of the form
+//
+//	/srv/data -ro 10.0.0.0/24(rw,root_squash) *.lan
+//
+// A "-options" word sets defaults for the clients that follow it, and a
+// client written as just "(options)" exports to everyone.
+func ParseNFSExports(data []byte) []model.NFSExport {
+	var exports []model.NFSExport
+
+	for _, line := range joinContinuations(string(data)) {
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		export := model.NFSExport{Path: strings.Trim(fields[0], `"`)}
+		var defaults []string
+		for _, field := range fields[1:] {
+			if strings.HasPrefix(field, "-") {
+				defaults = splitOptions(field[1:])
+				continue
+			}
+
+			client := model.NFSClient{Host: field}
+			if open := strings.Index(field, "("); open >= 0 {
+				client.Host = field[:open]
+				client.Options = splitOptions(strings.TrimSuffix(field[open+1:], ")"))
+			}
+			client.Options = append(append([]string{}, defaults...), client.Options...)
+			export.Clients = append(export.Clients, client)
+		}
+
+		// A path without clients is exported to every host
+		if len(export.Clients) == 0 {
+			export.Clients = []model.NFSClient{{Options: defaults}}
+		}
+
+		exports = append(exports, export)
+	}
+
+	return exports
+}
+
+// ParseSambaConfig parses smb.conf into its [global] settings and share
+// sections. Keys are lower-cased with whitespace collapsed so that
+// "Guest OK" and "guest  ok" compare equal.
+func ParseSambaConfig(data []byte) *model.SambaConfig {
+	config := &model.SambaConfig{Global: map[string]string{}}
+	var current map[string]string
+
+	for _, line := range joinContinuations(string(data)) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if strings.EqualFold(name, "global") {
+				current = config.Global
+				continue
+			}
+			config.Shares = append(config.Shares, model.SambaShare{Name: name, Settings: map[string]string{}})
+			current = config.Shares[len(config.Shares)-1].Settings
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found || current == nil {
+			continue
+		}
+		key = strings.ToLower(strings.Join(strings.Fields(key), " "))
+		current[key] = strings.TrimSpace(value)
+	}
+
+	return config
+}
+
+// joinContinuations splits data into lines, joining lines that end in a
+// backslash with the line that follows
+func joinContinuations(data string) []string {
+	var lines []string
+	var pending strings.Builder
+
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if strings.HasSuffix(line, `\`) {
+			pending.WriteString(strings.TrimSuffix(line, `\`))
+			pending.WriteString(" ")
+			continue
+		}
+		pending.WriteString(line)
+		lines = append(lines, pending.String())
+		pending.Reset()
+	}
+	if pending.Len() > 0 {
+		lines = append(lines, pending.String())
+	}
+
+	return lines
+}
+
+// splitOptions splits a comma separated option list
+func splitOptions(options string) []string {
+	var result []string
+	for _, option := range strings.Split(options, ",") {
+		if option = strings.TrimSpace(option); option != "" {
+			result = append(result, option)
+		}
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+// pkg/adapter/secondary/file_ssh_key_expiry_repository.go
+package secondary
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// FileSSHKeyExpiryRepository implements SSHKeyExpiryRepository with a JSON
+// file in hardn's state directory
+type FileSSHKeyExpiryRepository struct {
+	fs   interfaces.FileSystem
+	path string
+}
+
+// NewFileSSHKeyExpiryRepository creates a new FileSSHKeyExpiryRepository
+func NewFileSSHKeyExpiryRepository(
+	fs interfaces.FileSystem,
+	path string,
+) secondary.SSHKeyExpiryRepository {
+	return &FileSSHKeyExpiryRepository{
+		fs:   fs,
+		path: path,
+	}
+}
+
+// LoadKeyExpiries reads the tagged keys
+func (r *FileSSHKeyExpiryRepository) LoadKeyExpiries() ([]model.SSHKeyExpiry, error) {
+	if _, err := r.fs.Stat(r.path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	data, err := r.fs.ReadFile(r.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", r.path, err)
+	}
+
+	var expiries []model.SSHKeyExpiry
+	if err := json.Unmarshal(data, &expiries); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", r.path, err)
+	}
+	return expiries, nil
+}
+
+// SaveKeyExpiries writes the tagged keys
+func (r *FileSSHKeyExpiryRepository) SaveKeyExpiries(expiries []model.SSHKeyExpiry) error {
+	if expiries == nil {
+		expiries = []model.SSHKeyExpiry{}
+	}
+	data, err := json.MarshalIndent(expiries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode SSH key expiries: %w", err)
+	}
+
+	if err := r.fs.MkdirAll(filepath.Dir(r.path), 0700); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", r.path, err)
+	}
+	if err := r.fs.WriteFile(r.path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", r.path, err)
+	}
+	return nil
+}
@@ -4,6 +4,9 @@ package secondary
 import (
 	"fmt"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/abbott/hardn/pkg/domain/model"
@@ -11,10 +14,18 @@ import (
 	"github.com/abbott/hardn/pkg/port/secondary"
 )
 
+// sshdIncludeDepth limits nested Include directives, as sshd does
+const sshdIncludeDepth = 16
+
+// sshProfileMarker precedes the profile directives so the profile in effect
+// can be read back
+const sshProfileMarker = "# Hardening profile: "
+
 // FileSSHRepository implements SSHRepository using file operations
 type FileSSHRepository struct {
 	fs        interfaces.FileSystem
 	commander interfaces.Commander
+	services  secondary.ServiceManager
 	osType    string
 }
 
@@ -27,6 +38,7 @@ func NewFileSSHRepository(
 	return &FileSSHRepository{
 		fs:        fs,
 		commander: commander,
+		services:  NewServiceManager(commander, osType),
 		osType:    osType,
 	}
 }
@@ -36,11 +48,7 @@ func (r *FileSSHRepository) SaveSSHConfig(config model.SSHConfig) error {
 	// Determine config file path based on OS type
 	configFile := config.ConfigFilePath
 	if configFile == "" {
-		if r.osType == "alpine" {
-			configFile = "/etc/ssh/sshd_config"
-		} else {
-			configFile = "/etc/ssh/sshd_config.d/hardn.conf"
-		}
+		configFile = model.SSHManagedConfigPath(r.osType)
 	}
 
 	// Format SSH configuration content
@@ -93,6 +101,43 @@ func (r *FileSSHRepository) SaveSSHConfig(config model.SSHConfig) error {
 		content.WriteString("AuthorizedKeysFile .ssh/authorized_keys\n")
 	}
 
+	// Directory key lookup (e.g. SSSD serving sshPublicKey from LDAP)
+	if config.AuthorizedKeysCommand != "" {
+		runAs := config.AuthorizedKeysCommandUser
+		if runAs == "" {
+			runAs = model.DefaultAuthorizedKeysCommandUser
+		}
+		content.WriteString(fmt.Sprintf("AuthorizedKeysCommand %s\n", config.AuthorizedKeysCommand))
+		content.WriteString(fmt.Sprintf("AuthorizedKeysCommandUser %s\n", runAs))
+	}
+
+	// Pre-login banner
+	if config.Banner != "" {
+		content.WriteString(fmt.Sprintf("Banner %s\n", config.Banner))
+	}
+
+	// Hardening profile directives
+	if config.Profile != "" {
+		profile, ok := model.LookupSSHProfile(config.Profile)
+		if !ok {
+			return fmt.Errorf("unknown SSH hardening profile: %s", config.Profile)
+		}
+		content.WriteString(fmt.Sprintf("\n%s%s\n", sshProfileMarker, config.Profile))
+		content.WriteString(fmt.Sprintf("MaxAuthTries %d\n", profile.MaxAuthTries))
+		content.WriteString(fmt.Sprintf("LoginGraceTime %d\n", profile.LoginGraceTime))
+		content.WriteString(fmt.Sprintf("ClientAliveInterval %d\n", profile.ClientAliveInterval))
+		content.WriteString(fmt.Sprintf("ClientAliveCountMax %d\n", profile.ClientAliveCountMax))
+		x11Value := "no"
+		if profile.X11Forwarding {
+			x11Value = "yes"
+		}
+		content.WriteString(fmt.Sprintf("X11Forwarding %s\n", x11Value))
+		content.WriteString(fmt.Sprintf("AllowTcpForwarding %s\n", profile.AllowTcpForwarding))
+		content.WriteString(fmt.Sprintf("Ciphers %s\n", strings.Join(profile.Ciphers, ",")))
+		content.WriteString(fmt.Sprintf("MACs %s\n", strings.Join(profile.MACs, ",")))
+		content.WriteString(fmt.Sprintf("KexAlgorithms %s\n", strings.Join(profile.KexAlgorithms, ",")))
+	}
+
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(configFile)
 	if err := r.fs.MkdirAll(dir, 0755); err != nil {
@@ -104,19 +149,18 @@ func (r *FileSSHRepository) SaveSSHConfig(config model.SSHConfig) error {
 		return fmt.Errorf("failed to write SSH config file: %w", err)
 	}
 
-	// Restart SSH service based on OS type
-	var cmd string
-	var args []string
+	return r.restartSSH()
+}
 
-	if r.osType == "alpine" {
-		cmd = "rc-service"
-		args = []string{"sshd", "restart"}
-	} else {
-		cmd = "systemctl"
-		args = []string{"restart", "ssh"}
+// restartSSH restarts the SSH service under its name on this OS
+func (r *FileSSHRepository) restartSSH() error {
+	// The service is sshd on Alpine and the RHEL family, ssh on Debian/Ubuntu
+	name := "ssh"
+	if r.osType == "alpine" || model.IsRHELFamily(r.osType) {
+		name = "sshd"
 	}
 
-	if _, err := r.commander.Execute(cmd, args...); err != nil {
+	if err := r.services.Restart(name); err != nil {
 		return fmt.Errorf("failed to restart SSH service: %w", err)
 	}
 
@@ -125,9 +169,94 @@ func (r *FileSSHRepository) SaveSSHConfig(config model.SSHConfig) error {
 
 // GetSSHConfig reads the current SSH configuration
 func (r *FileSSHRepository) GetSSHConfig() (*model.SSHConfig, error) {
-	// Implementation to parse SSH config file and return configuration
-	// ... (implementation details omitted for brevity)
-	return &model.SSHConfig{Port: 22}, nil
+	// Start from sshd defaults; root login is treated as enabled unless disabled
+	config := &model.SSHConfig{
+		Port:            22,
+		PermitRootLogin: true,
+	}
+
+	// Check the hardn-managed file first, then the main config.
+	// Like sshd, the first value found for a directive wins.
+	configPaths := []string{model.SSHMainConfigPath}
+	if r.osType != "alpine" {
+		configPaths = []string{model.SSHDropInConfigPath, model.SSHMainConfigPath}
+	}
+
+	seen := make(map[string]bool)
+	listenSource := ""
+	for _, configPath := range configPaths {
+		data, err := r.fs.ReadFile(configPath)
+		if err != nil {
+			continue // Try next config file if this one can't be read
+		}
+
+		lines := strings.Split(string(data), "\n")
+		for _, line := range lines {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, sshProfileMarker) && config.Profile == "" {
+				config.Profile = strings.TrimSpace(strings.TrimPrefix(line, sshProfileMarker))
+				continue
+			}
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			fields := strings.Fields(line)
+			if len(fields) < 2 {
+				continue
+			}
+
+			directive := strings.ToLower(fields[0])
+
+			// A Match block runs to the end of the file and only applies to
+			// the connections it matches, so it says nothing about the defaults
+			if directive == "match" {
+				break
+			}
+
+			// ListenAddress accumulates within the first file that sets it
+			if directive == "listenaddress" {
+				if listenSource != "" && listenSource != configPath {
+					continue
+				}
+				listenSource = configPath
+				config.ListenAddresses = append(config.ListenAddresses, fields[1])
+				continue
+			}
+
+			if seen[directive] {
+				continue
+			}
+			seen[directive] = true
+
+			switch directive {
+			case "port":
+				if port, err := strconv.Atoi(fields[1]); err == nil {
+					config.Port = port
+				}
+			case "permitrootlogin":
+				config.PermitRootLogin = strings.ToLower(fields[1]) != "no"
+			case "allowusers":
+				config.AllowedUsers = fields[1:]
+			case "authenticationmethods":
+				config.AuthMethods = strings.Split(fields[1], ",")
+			case "authorizedkeysfile":
+				config.KeyPaths = fields[1:]
+			case "authorizedkeyscommand":
+				if strings.ToLower(fields[1]) != "none" {
+					config.AuthorizedKeysCommand = strings.Join(fields[1:], " ")
+				}
+			case "authorizedkeyscommanduser":
+				config.AuthorizedKeysCommandUser = fields[1]
+			case "banner":
+				if strings.ToLower(fields[1]) != "none" {
+					config.Banner = fields[1]
+				}
+			}
+		}
+	}
+
+	return config, nil
 }
 
 // DisableRootSSH disables SSH access for the root user
@@ -148,6 +277,16 @@ func (r *FileSSHRepository) DisableRootSSH() error {
 			newAllowedUsers = append(newAllowedUsers, user)
 		}
 	}
+
+	// An AllowUsers list emptied by dropping root would let every account
+	// log in; a file without AllowUsers is left without one
+	if len(config.AllowedUsers) > 0 {
+		allowedUsers, err := model.ResolveAllowedUsers(newAllowedUsers, config.ManagedUser, slices.Contains(newAllowedUsers, "*"))
+		if err != nil {
+			return fmt.Errorf("root is the only user allowed to log in over SSH: %w", err)
+		}
+		newAllowedUsers = allowedUsers
+	}
 	config.AllowedUsers = newAllowedUsers
 
 	// Save the modified configuration
@@ -215,3 +354,256 @@ func (r *FileSSHRepository) AddAuthorizedKey(username string, publicKey string)
 
 	return nil
 }
+
+// GetEffectiveSSHConfig returns the configuration sshd would run with, after
+// includes and defaults, as reported by sshd -T
+func (r *FileSSHRepository) GetEffectiveSSHConfig() (map[string][]string, error) {
+	if _, err := r.commander.Execute("which", "sshd"); err != nil {
+		return nil, nil
+	}
+	output, err := r.commander.Execute("sshd", "-T")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the effective sshd configuration: %w\nOutput: %s", err, string(output))
+	}
+	return ParseSSHDTest(output), nil
+}
+
+// GetSSHConfigSources reads sshd_config and the files it includes the way
+// sshd does, recording where each global directive is set. Match blocks
+// only apply to some connections and are skipped.
+func (r *FileSSHRepository) GetSSHConfigSources() ([]model.SSHDirectiveSource, error) {
+	var sources []model.SSHDirectiveSource
+	if err := r.readSSHConfigSources(model.SSHMainConfigPath, 0, &sources); err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+// readSSHConfigSources appends the directives of one configuration file,
+// descending into its Include directives in place
+func (r *FileSSHRepository) readSSHConfigSources(path string, depth int, sources *[]model.SSHDirectiveSource) error {
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		if depth == 0 {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return nil // sshd skips included files that cannot be read
+	}
+
+	for n, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		directive, value := line, ""
+		if i := strings.IndexAny(line, " \t="); i >= 0 {
+			directive, value = line[:i], strings.TrimSpace(strings.TrimLeft(line[i:], " \t="))
+		}
+		directive = strings.ToLower(directive)
+
+		switch directive {
+		case "match":
+			// A Match block runs to the end of the file
+			return nil
+		case "include":
+			if depth >= sshdIncludeDepth {
+				return fmt.Errorf("too many nested Include directives in %s", path)
+			}
+			for _, pattern := range strings.Fields(value) {
+				paths, err := r.expandSSHInclude(pattern)
+				if err != nil {
+					return err
+				}
+				for _, included := range paths {
+					if err := r.readSSHConfigSources(included, depth+1, sources); err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
+
+		*sources = append(*sources, model.SSHDirectiveSource{
+			Directive: directive,
+			Value:     value,
+			File:      path,
+			Line:      n + 1,
+		})
+	}
+	return nil
+}
+
+// expandSSHInclude resolves an Include pattern: relative paths are under
+// /etc/ssh and the files a glob matches are read in lexical order
+func (r *FileSSHRepository) expandSSHInclude(pattern string) ([]string, error) {
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(filepath.Dir(model.SSHMainConfigPath), pattern)
+	}
+	if !strings.ContainsAny(pattern, "*?[") {
+		return []string{pattern}, nil
+	}
+
+	dir := filepath.Dir(pattern)
+	if _, err := r.fs.Stat(dir); err != nil {
+		return nil, nil
+	}
+	output, err := r.commander.Execute("find", dir, "-mindepth", "1", "-maxdepth", "1", "-type", "f")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+
+	var matches []string
+	for _, path := range strings.Fields(string(output)) {
+		if ok, _ := filepath.Match(filepath.Base(pattern), filepath.Base(path)); ok {
+			matches = append(matches, path)
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// sshDisabledPrefix replaces the start of a configuration line disabled in
+// favor of hardn's file
+const sshDisabledPrefix = "# Disabled by hardn in favor of %s: "
+
+// DisableSSHDirectives comments out the given lines, checks the result
+// with sshd -t and restarts sshd
+func (r *FileSSHRepository) DisableSSHDirectives(sources []model.SSHDirectiveSource, managedFile string) error {
+	if len(sources) == 0 {
+		return nil
+	}
+
+	byFile := make(map[string][]model.SSHDirectiveSource)
+	var files []string
+	for _, source := range sources {
+		if source.File == managedFile {
+			return fmt.Errorf("refusing to disable %s in hardn's own file %s", source.Directive, managedFile)
+		}
+		if _, ok := byFile[source.File]; !ok {
+			files = append(files, source.File)
+		}
+		byFile[source.File] = append(byFile[source.File], source)
+	}
+
+	originals := make(map[string][]byte)
+	restore := func() {
+		for path, data := range originals {
+			_ = r.fs.WriteFile(path, data, 0644)
+		}
+	}
+
+	for _, path := range files {
+		data, err := r.fs.ReadFile(path)
+		if err != nil {
+			restore()
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		originals[path] = data
+
+		lines := strings.Split(string(data), "\n")
+		for _, source := range byFile[path] {
+			i := source.Line - 1
+			fields := strings.Fields(strings.ReplaceAll(lineOrEmpty(lines, i), "=", " "))
+			if len(fields) == 0 || strings.ToLower(fields[0]) != source.Directive {
+				restore()
+				return fmt.Errorf("%s:%d no longer sets %s", path, source.Line, source.Directive)
+			}
+			lines[i] = fmt.Sprintf(sshDisabledPrefix, managedFile) + strings.TrimSpace(lines[i])
+		}
+
+		if err := r.fs.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+			restore()
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	if output, err := r.commander.Execute("sshd", "-t"); err != nil {
+		restore()
+		return fmt.Errorf("sshd rejected the consolidated configuration, files restored: %w\nOutput: %s", err, string(output))
+	}
+	return r.restartSSH()
+}
+
+// ListSSHDaemons finds the sshd and dropbear processes that accept
+// connections; their per-connection children are left out
+func (r *FileSSHRepository) ListSSHDaemons() ([]model.SSHDaemon, error) {
+	output, err := r.commander.Execute("ps", "-eo", "pid=,ppid=,args=")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+	return ParseSSHDaemons(string(output)), nil
+}
+
+// ParseSSHDaemons reads SSH servers from ps -eo pid=,ppid=,args= output. A
+// server process whose parent is not a server itself listens for
+// connections. OpenSSH renames its listener "sshd: /usr/sbin/sshd -D
+// [listener] ...", and its sessions "sshd: user [priv]".
+func ParseSSHDaemons(output string) []model.SSHDaemon {
+	type process struct {
+		pid, ppid int
+		program   string
+		command   string
+	}
+
+	var servers []process
+	serverPIDs := make(map[int]bool)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		args := fields[2:]
+		if args[0] == "sshd:" && len(args) > 1 {
+			args = args[1:]
+		}
+		program := filepath.Base(args[0])
+		if program != "sshd" && program != "dropbear" {
+			continue
+		}
+
+		var command []string
+		for _, arg := range args {
+			if strings.HasPrefix(arg, "[") {
+				break // process title after the command line
+			}
+			command = append(command, arg)
+		}
+
+		servers = append(servers, process{pid, ppid, program, strings.Join(command, " ")})
+		serverPIDs[pid] = true
+	}
+
+	var daemons []model.SSHDaemon
+	for _, server := range servers {
+		if serverPIDs[server.ppid] {
+			continue
+		}
+		daemon := model.SSHDaemon{PID: server.pid, Program: server.program, Command: server.command}
+		if server.program == "sshd" {
+			daemon.ConfigFile = model.SSHMainConfigPath
+			fields := strings.Fields(server.command)
+			for i, field := range fields {
+				if field == "-f" && i+1 < len(fields) {
+					daemon.ConfigFile = fields[i+1]
+				}
+			}
+		}
+		daemons = append(daemons, daemon)
+	}
+	return daemons
+}
+
+// lineOrEmpty returns line i, or "" when the file has fewer lines
+func lineOrEmpty(lines []string, i int) string {
+	if i < 0 || i >= len(lines) {
+		return ""
+	}
+	return lines[i]
+}
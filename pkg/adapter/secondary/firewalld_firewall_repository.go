@@ -0,0 +1,599 @@
+// pkg/adapter/secondary/firewalld_firewall_repository.go
+package secondary
+
+import (
+	"fmt"
+	"html"
+	"path/filepath"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// FirewalldServicesDir holds the service definitions hardn writes for
+// application profiles
+const FirewalldServicesDir = "/etc/firewalld/services"
+
+// firewalldProfilePrefix marks the services hardn created
+const firewalldProfilePrefix = "hardn-"
+
+// firewalldLimitRate caps the new connections a "limit" rule accepts. Rich
+// rules limit all addresses together, unlike ufw's limit of 6 connections
+// in 30 seconds per address.
+const firewalldLimitRate = "10/m"
+
+// FirewalldFirewallRepository implements FirewallRepository using firewalld,
+// the firewall on Rocky Linux, AlmaLinux and Fedora. Rules go to the
+// permanent configuration of the default zone and are reported in ufw's
+// terms, e.g. "allow 22/tcp", so callers read them the same on every
+// distribution.
+type FirewalldFirewallRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	services  secondary.ServiceManager
+	osType    string
+}
+
+// NewFirewalldFirewallRepository creates a new FirewalldFirewallRepository
+func NewFirewalldFirewallRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	osType string,
+) secondary.FirewallRepository {
+	return &FirewalldFirewallRepository{
+		fs:        fs,
+		commander: commander,
+		services:  NewServiceManager(commander, osType),
+		osType:    osType,
+	}
+}
+
+// NewFirewallRepository returns the distribution's firewall: firewalld on
+// the RHEL family and ufw elsewhere
+func NewFirewallRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	osType string,
+) secondary.FirewallRepository {
+	if model.IsRHELFamily(osType) {
+		return NewFirewalldFirewallRepository(fs, commander, osType)
+	}
+	return NewUFWFirewallRepository(fs, commander, osType)
+}
+
+// IsFirewalldInstalled checks if firewalld is installed
+func (r *FirewalldFirewallRepository) IsFirewalldInstalled() bool {
+	_, err := r.commander.Execute("which", "firewall-cmd")
+	return err == nil
+}
+
+// isRunning reports whether the firewalld daemon is running
+func (r *FirewalldFirewallRepository) isRunning() bool {
+	_, err := r.commander.Execute("firewall-cmd", "--state")
+	return err == nil
+}
+
+// firewallCmd changes or queries the permanent configuration, through the
+// daemon when it runs and with firewall-offline-cmd when it does not
+func (r *FirewalldFirewallRepository) firewallCmd(args ...string) ([]byte, error) {
+	if r.isRunning() {
+		return r.commander.Execute("firewall-cmd", append([]string{"--permanent"}, args...)...)
+	}
+	return r.commander.Execute("firewall-offline-cmd", args...)
+}
+
+// reload applies the permanent configuration to a running daemon
+func (r *FirewalldFirewallRepository) reload() error {
+	if !r.isRunning() {
+		return nil
+	}
+	if output, err := r.commander.Execute("firewall-cmd", "--reload"); err != nil {
+		return fmt.Errorf("failed to reload firewalld: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// defaultZone returns the zone interfaces without an explicit zone use
+func (r *FirewalldFirewallRepository) defaultZone() string {
+	output, err := r.firewallCmd("--get-default-zone")
+	if zone := strings.TrimSpace(string(output)); err == nil && zone != "" {
+		return zone
+	}
+	return "public"
+}
+
+// zoneList runs a --list-* query on the default zone and splits the result
+func (r *FirewalldFirewallRepository) zoneList(zone, query string) []string {
+	output, err := r.firewallCmd("--zone="+zone, query)
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(output))
+}
+
+// GetFirewallStatus retrieves the current status of the firewall
+func (r *FirewalldFirewallRepository) GetFirewallStatus() (bool, bool, bool, []string, error) {
+	isInstalled := r.IsFirewalldInstalled()
+	if !isInstalled {
+		return false, false, false, nil, nil
+	}
+
+	isEnabled := r.isRunning()
+
+	zone := r.defaultZone()
+	return isInstalled, isEnabled, r.zoneFilters(zone), r.rules(zone, ""), nil
+}
+
+// zoneFilters reports whether the zone turns away traffic no rule matched.
+// The zone's target handles that traffic; "default" rejects it, ACCEPT
+// lets it through.
+func (r *FirewalldFirewallRepository) zoneFilters(zone string) bool {
+	target, err := r.firewallCmd("--zone="+zone, "--get-target")
+	return err == nil && zone != "trusted" && ParseFirewalldTarget(target) != "allow"
+}
+
+// rules lists the zone's open ports, services and rich rules as ufw rules.
+// Given a family, rich rules limited to the other family are left out.
+func (r *FirewalldFirewallRepository) rules(zone string, family string) []string {
+	var rules []string
+	for _, port := range r.zoneList(zone, "--list-ports") {
+		rules = append(rules, "allow "+port)
+	}
+
+	// Services stand for their ports, e.g. ssh for 22/tcp
+	for _, service := range r.zoneList(zone, "--list-services") {
+		output, err := r.firewallCmd("--service="+service, "--get-ports")
+		if err != nil {
+			continue
+		}
+		for _, port := range strings.Fields(string(output)) {
+			rules = append(rules, "allow "+port)
+		}
+	}
+
+	output, err := r.firewallCmd("--zone="+zone, "--list-rich-rules")
+	if err == nil {
+		for _, line := range strings.Split(string(output), "\n") {
+			limited := ParseFirewalldRichRuleFamily(line)
+			if family != "" && limited != "" && limited != family {
+				continue
+			}
+			if rule, ok := ParseFirewalldRichRule(line); ok {
+				rules = append(rules, rule)
+			}
+			rules = append(rules, r.richRuleServicePorts(line)...)
+		}
+	}
+	return rules
+}
+
+// GetStackRules returns the default zone's rules for each family. A zone
+// filters IPv4 and IPv6 alike; only rich rules can differ between them.
+func (r *FirewalldFirewallRepository) GetStackRules() (model.StackRules, model.StackRules, error) {
+	ipv4 := model.StackRules{Family: model.FamilyIPv4, Active: true}
+	ipv6 := model.StackRules{Family: model.FamilyIPv6, Active: hostHasIPv6(r.fs)}
+
+	if !r.IsFirewalldInstalled() || !r.isRunning() {
+		return ipv4, ipv6, nil
+	}
+
+	zone := r.defaultZone()
+	filtered := r.zoneFilters(zone)
+	for _, stack := range []*model.StackRules{&ipv4, &ipv6} {
+		stack.Filtered = filtered
+		stack.Allowed = r.rules(zone, stack.Family)
+	}
+	return ipv4, ipv6, nil
+}
+
+// GetRules lists the default zone's ports, hardn's services and rich rules.
+// Services the distribution enabled, such as ssh and cockpit, are left
+// out, as configuring the firewall leaves them alone too.
+func (r *FirewalldFirewallRepository) GetRules() ([]string, error) {
+	if !r.IsFirewalldInstalled() {
+		return nil, fmt.Errorf("firewalld is not installed")
+	}
+	zone := r.defaultZone()
+
+	var ports []string
+	ports = append(ports, r.zoneList(zone, "--list-ports")...)
+	for _, service := range r.zoneList(zone, "--list-services") {
+		if !strings.HasPrefix(service, firewalldProfilePrefix) {
+			continue
+		}
+		if output, err := r.firewallCmd("--service="+service, "--get-ports"); err == nil {
+			ports = append(ports, strings.Fields(string(output))...)
+		}
+	}
+
+	var rules []string
+	seen := make(map[string]bool)
+	add := func(rule string) {
+		if !seen[rule] {
+			seen[rule] = true
+			rules = append(rules, rule)
+		}
+	}
+	for _, port := range ports {
+		add("allow " + strings.ReplaceAll(port, "-", ":"))
+	}
+	if output, err := r.firewallCmd("--zone="+zone, "--list-rich-rules"); err == nil {
+		for _, line := range strings.Split(string(output), "\n") {
+			if rule, ok := ParseFirewalldRichRule(line); ok {
+				add(rule)
+			}
+			for _, rule := range r.richRuleServicePorts(line) {
+				add(rule)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// richRuleServicePorts lists the ports of the service a rich rule
+// accepts as ufw rules, with the rule's source
+func (r *FirewalldFirewallRepository) richRuleServicePorts(line string) []string {
+	service := ParseFirewalldRichRuleService(line)
+	if service == "" {
+		return nil
+	}
+	output, err := r.firewallCmd("--service="+service, "--get-ports")
+	if err != nil {
+		return nil
+	}
+
+	suffix := ""
+	if source := richRuleSource.FindStringSubmatch(line); source != nil {
+		suffix = " from " + source[1]
+	}
+	var rules []string
+	for _, port := range strings.Fields(string(output)) {
+		rules = append(rules, "allow "+strings.ReplaceAll(port, "-", ":")+suffix)
+	}
+	return rules
+}
+
+// ConfigurePortKnocking has knockd allow the SSH port to an address that
+// knocks, with a runtime rich rule removed again after the open timeout
+func (r *FirewalldFirewallRepository) ConfigurePortKnocking(sshPort int, knocking *model.PortKnocking) error {
+	rule := "'" + FirewalldRichRule(model.FirewallRule{Action: "allow", Protocol: "tcp", Port: sshPort, SourceIP: "%IP%"}) + "'"
+	return configureKnockd(r.fs, r.commander, r.osType, knocking,
+		"/usr/bin/firewall-cmd --add-rich-rule="+rule, "/usr/bin/firewall-cmd --remove-rich-rule="+rule)
+}
+
+// PortKnockingActive reports whether knockd runs with hardn's sequence
+func (r *FirewalldFirewallRepository) PortKnockingActive() bool {
+	return knockdActive(r.fs, r.commander, r.osType)
+}
+
+// GetIPv6Kernel reports whether the kernel runs IPv6 and whether its
+// command line turns IPv6 off
+func (r *FirewalldFirewallRepository) GetIPv6Kernel() (bool, bool) {
+	return ipv6Kernel(r.fs)
+}
+
+// SetIPv6Kernel turns IPv6 off in the kernel, or undoes what hardn did.
+// firewalld copes without IPv6 and needs no change.
+func (r *FirewalldFirewallRepository) SetIPv6Kernel(disabled bool, bootParameter bool) error {
+	return setIPv6Kernel(r.fs, r.commander, r.osType, disabled, bootParameter)
+}
+
+// FilterFamily always fails: firewalld filters both families or neither
+func (r *FirewalldFirewallRepository) FilterFamily(family string) error {
+	return fmt.Errorf("firewalld filters IPv4 and IPv6 together; %s can not be filtered on its own", model.FamilyName(family))
+}
+
+// MirrorRule adds a rich rule limited to family for a rule such as
+// "allow 22/tcp"
+func (r *FirewalldFirewallRepository) MirrorRule(rule string, family string) error {
+	fields := strings.Fields(rule)
+	if len(fields) != 2 {
+		return fmt.Errorf("rule %q can not be mirrored", rule)
+	}
+	port, protocol, found := strings.Cut(fields[1], "/")
+	if !found {
+		return fmt.Errorf("rule %q has no protocol", rule)
+	}
+
+	richRule := fmt.Sprintf("rule family=%q port port=%q protocol=%q accept",
+		family, strings.ReplaceAll(port, ":", "-"), protocol)
+	if output, err := r.firewallCmd("--zone="+r.defaultZone(), "--add-rich-rule="+richRule); err != nil {
+		return fmt.Errorf("failed to add %s to %s: %w\nOutput: %s", rule, model.FamilyName(family), err, string(output))
+	}
+	return r.reload()
+}
+
+// SaveFirewallConfig applies the specified firewall configuration
+func (r *FirewalldFirewallRepository) SaveFirewallConfig(config model.FirewallConfig) error {
+	if !r.IsFirewalldInstalled() {
+		return fmt.Errorf("firewalld is not installed")
+	}
+
+	// firewalld zones only filter incoming traffic
+	if config.DefaultOutgoing != "" && config.DefaultOutgoing != "allow" {
+		return fmt.Errorf("firewalld does not support a default outgoing policy of %s", config.DefaultOutgoing)
+	}
+
+	zone := r.defaultZone()
+	if _, err := r.firewallCmd("--zone="+zone, "--set-target="+firewalldTarget(config.DefaultIncoming)); err != nil {
+		return fmt.Errorf("failed to set incoming policy: %w", err)
+	}
+
+	// Reset the ports, rich rules and hardn's services; services the
+	// distribution enabled, such as ssh and cockpit, are left alone
+	for _, port := range r.zoneList(zone, "--list-ports") {
+		if _, err := r.firewallCmd("--zone="+zone, "--remove-port="+port); err != nil {
+			return fmt.Errorf("failed to remove port %s: %w", port, err)
+		}
+	}
+	output, _ := r.firewallCmd("--zone="+zone, "--list-rich-rules")
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		if _, err := r.firewallCmd("--zone="+zone, "--remove-rich-rule="+line); err != nil {
+			return fmt.Errorf("failed to remove rich rule %s: %w", line, err)
+		}
+	}
+	for _, service := range r.zoneList(zone, "--list-services") {
+		if !strings.HasPrefix(service, firewalldProfilePrefix) {
+			continue
+		}
+		if _, err := r.firewallCmd("--zone="+zone, "--remove-service="+service); err != nil {
+			return fmt.Errorf("failed to remove service %s: %w", service, err)
+		}
+	}
+
+	// Profiles apply to IPv4 alone when IPv6 is denied
+	profileFamily := ""
+	if config.IPv6 == model.IPv6Deny {
+		profileFamily = model.FamilyIPv4
+	}
+	if err := r.applyAppProfiles(config.ApplicationProfiles, profileFamily); err != nil {
+		return err
+	}
+
+	for _, rule := range config.Rules {
+		if err := r.addRule(zone, rule); err != nil {
+			return err
+		}
+	}
+
+	if config.Enabled {
+		return r.EnableFirewall()
+	}
+	return r.reload()
+}
+
+// GetFirewallConfig retrieves the current firewall configuration
+func (r *FirewalldFirewallRepository) GetFirewallConfig() (*model.FirewallConfig, error) {
+	return &model.FirewallConfig{
+		Enabled:         true,
+		DefaultIncoming: "deny",
+		DefaultOutgoing: "allow",
+	}, nil
+}
+
+// AddRule adds a firewall rule
+func (r *FirewalldFirewallRepository) AddRule(rule model.FirewallRule) error {
+	if err := r.addRule(r.defaultZone(), rule); err != nil {
+		return err
+	}
+	return r.reload()
+}
+
+func (r *FirewalldFirewallRepository) addRule(zone string, rule model.FirewallRule) error {
+	if output, err := r.firewallCmd("--zone="+zone, firewalldRuleArg("add", rule)); err != nil {
+		return fmt.Errorf("failed to add rule %s %s/%s: %w\nOutput: %s", rule.Action, rule.Ports("-"), rule.Protocol, err, string(output))
+	}
+	return nil
+}
+
+// RemoveRule removes a firewall rule
+func (r *FirewalldFirewallRepository) RemoveRule(rule model.FirewallRule) error {
+	if output, err := r.firewallCmd("--zone="+r.defaultZone(), firewalldRuleArg("remove", rule)); err != nil {
+		return fmt.Errorf("failed to remove rule %s %s/%s: %w\nOutput: %s", rule.Action, rule.Ports("-"), rule.Protocol, err, string(output))
+	}
+	return r.reload()
+}
+
+// RuleCommand returns the firewall-cmd command AddRule runs for a rule
+func (r *FirewalldFirewallRepository) RuleCommand(rule model.FirewallRule) string {
+	args := []string{"--permanent", "--zone=" + r.defaultZone(), firewalldRuleArg("add", rule)}
+	if !r.isRunning() {
+		return commandLine("firewall-offline-cmd", args[1:])
+	}
+	return commandLine("firewall-cmd", args)
+}
+
+// firewalldRuleArg returns the firewall-cmd option that adds or removes a
+// rule: a port when it lets everyone in over both families, a rich rule
+// otherwise. firewalld rules have no comment.
+func firewalldRuleArg(operation string, rule model.FirewallRule) string {
+	if rule.SourceIP != "" || rule.Family != "" || rule.Action != "allow" {
+		return "--" + operation + "-rich-rule=" + FirewalldRichRule(rule)
+	}
+	return "--" + operation + "-port=" + rule.Ports("-") + "/" + rule.Protocol
+}
+
+// AddProfile adds a firewall application profile
+func (r *FirewalldFirewallRepository) AddProfile(profile model.FirewallProfile) error {
+	return r.ApplyProfiles([]model.FirewallProfile{profile})
+}
+
+// ApplyProfiles writes and enables firewall application profiles
+func (r *FirewalldFirewallRepository) ApplyProfiles(profiles []model.FirewallProfile) error {
+	if !r.IsFirewalldInstalled() {
+		return fmt.Errorf("firewalld is not installed")
+	}
+
+	if err := r.applyAppProfiles(profiles, ""); err != nil {
+		return err
+	}
+	return r.reload()
+}
+
+// applyAppProfiles writes each profile as a firewalld service named
+// hardn-<name> and adds it to the default zone. Given a family, a rich rule
+// accepts the service for that family alone.
+func (r *FirewalldFirewallRepository) applyAppProfiles(profiles []model.FirewallProfile, family string) error {
+	if len(profiles) == 0 {
+		return nil
+	}
+
+	if err := r.fs.MkdirAll(FirewalldServicesDir, 0755); err != nil {
+		return fmt.Errorf("failed to create firewalld services directory: %w", err)
+	}
+
+	for _, profile := range profiles {
+		path := filepath.Join(FirewalldServicesDir, FirewalldServiceName(profile.Name)+".xml")
+		if err := r.fs.WriteFile(path, FirewalldServiceXML(profile), 0644); err != nil {
+			return fmt.Errorf("failed to write firewalld service %s: %w", profile.Name, err)
+		}
+	}
+
+	// A running daemon only sees new service files after a reload
+	if err := r.reload(); err != nil {
+		return err
+	}
+
+	zone := r.defaultZone()
+	for _, profile := range profiles {
+		arg := "--add-service=" + FirewalldServiceName(profile.Name)
+		if family != "" {
+			arg = fmt.Sprintf("--add-rich-rule=rule family=%q service name=%q accept", family, FirewalldServiceName(profile.Name))
+		}
+		if _, err := r.firewallCmd("--zone="+zone, arg); err != nil {
+			return fmt.Errorf("failed to apply profile %s: %w", profile.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// EnableFirewall starts firewalld now and at boot
+func (r *FirewalldFirewallRepository) EnableFirewall() error {
+	if err := r.services.Enable("firewalld"); err != nil {
+		return fmt.Errorf("failed to enable firewalld: %w", err)
+	}
+
+	// The daemon may have been running with rules not yet reloaded
+	return r.reload()
+}
+
+// DisableFirewall stops firewalld now and at boot
+func (r *FirewalldFirewallRepository) DisableFirewall() error {
+	if err := r.services.Disable("firewalld"); err != nil {
+		return fmt.Errorf("failed to disable firewalld: %w", err)
+	}
+
+	return nil
+}
+
+// InstallFirewall installs the firewalld package
+func (r *FirewalldFirewallRepository) InstallFirewall() error {
+	if r.IsFirewalldInstalled() {
+		return nil
+	}
+
+	manager := "dnf"
+	if _, err := r.commander.Execute("which", "dnf"); err != nil {
+		manager = "yum"
+	}
+
+	if output, err := r.commander.Execute(manager, "install", "-y", "firewalld"); err != nil {
+		return fmt.Errorf("failed to install firewalld: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// firewalldTarget maps a ufw default policy to a zone target. deny keeps
+// the zone's default target, which rejects unmatched traffic but still
+// answers ICMP, like ufw's deny.
+func firewalldTarget(policy string) string {
+	switch policy {
+	case "allow":
+		return "ACCEPT"
+	case "reject":
+		return "%%REJECT%%"
+	}
+	return "default"
+}
+
+// FirewalldServiceName returns the firewalld service for a profile,
+// e.g. hardn-openssh for OpenSSH
+func FirewalldServiceName(profile string) string {
+	name := strings.ToLower(strings.TrimSpace(profile))
+	name = strings.Map(func(c rune) rune {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_' {
+			return c
+		}
+		return '-'
+	}, name)
+	return firewalldProfilePrefix + name
+}
+
+// FirewalldServiceXML renders a profile as a firewalld service definition.
+// Ports are written as in ufw profiles: "80/tcp", "80,443/tcp" or
+// "6000:6007/tcp"; a port without a protocol opens both tcp and udp.
+func FirewalldServiceXML(profile model.FirewallProfile) []byte {
+	var content strings.Builder
+	content.WriteString("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n")
+	content.WriteString("<service>\n")
+	content.WriteString(fmt.Sprintf("  <short>%s</short>\n", html.EscapeString(profile.Title)))
+	content.WriteString(fmt.Sprintf("  <description>%s</description>\n", html.EscapeString(profile.Description)))
+
+	for _, spec := range profile.Ports {
+		ports, protocol, found := strings.Cut(strings.TrimSpace(spec), "/")
+		protocols := []string{protocol}
+		if !found {
+			protocols = []string{"tcp", "udp"}
+		}
+		for _, port := range strings.Split(ports, ",") {
+			port = strings.ReplaceAll(strings.TrimSpace(port), ":", "-")
+			if port == "" {
+				continue
+			}
+			for _, protocol := range protocols {
+				content.WriteString(fmt.Sprintf("  <port protocol=\"%s\" port=\"%s\"/>\n",
+					html.EscapeString(protocol), html.EscapeString(port)))
+			}
+		}
+	}
+
+	content.WriteString("</service>\n")
+	return []byte(content.String())
+}
+
+// FirewalldRichRule renders a rule with a source or a family, or one that
+// does not allow, as a firewalld rich rule; limit rules accept at
+// firewalldLimitRate
+func FirewalldRichRule(rule model.FirewallRule) string {
+	var parts []string
+	parts = append(parts, "rule")
+
+	if family := rule.AddressFamily(); family != "" {
+		parts = append(parts, fmt.Sprintf("family=%q", family))
+	}
+	if rule.SourceIP != "" {
+		parts = append(parts, fmt.Sprintf("source address=%q", rule.SourceIP))
+	}
+
+	parts = append(parts, fmt.Sprintf("port port=%q protocol=%q", rule.Ports("-"), rule.Protocol))
+
+	switch rule.Action {
+	case "allow":
+		parts = append(parts, "accept")
+	case "limit":
+		parts = append(parts, "accept", fmt.Sprintf("limit value=%q", firewalldLimitRate))
+	case "reject":
+		parts = append(parts, "reject")
+	default:
+		parts = append(parts, "drop")
+	}
+
+	return strings.Join(parts, " ")
+}
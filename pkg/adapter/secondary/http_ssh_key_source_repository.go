@@ -0,0 +1,88 @@
+// pkg/adapter/secondary/http_ssh_key_source_repository.go
+package secondary
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// maxPublishedKeysSize caps the response read from a provider
+const maxPublishedKeysSize = 256 * 1024
+
+// defaultSSHKeySourceURLs are the endpoints serving an account's public keys
+// in authorized_keys format; %s is the account name
+var defaultSSHKeySourceURLs = map[string]string{
+	model.SSHKeyProviderGitHub: "https://github.com/%s.keys",
+	model.SSHKeyProviderGitLab: "https://gitlab.com/%s.keys",
+}
+
+// HTTPSSHKeySourceRepository implements SSHKeySourceRepository over HTTPS
+type HTTPSSHKeySourceRepository struct {
+	client *http.Client
+	urls   map[string]string
+}
+
+// NewHTTPSSHKeySourceRepository creates a new HTTPSSHKeySourceRepository
+func NewHTTPSSHKeySourceRepository() secondary.SSHKeySourceRepository {
+	return NewHTTPSSHKeySourceRepositoryWithURLs(defaultSSHKeySourceURLs)
+}
+
+// NewHTTPSSHKeySourceRepositoryWithURLs creates an HTTPSSHKeySourceRepository
+// that fetches from the given per-provider URL templates
+func NewHTTPSSHKeySourceRepositoryWithURLs(urls map[string]string) secondary.SSHKeySourceRepository {
+	return &HTTPSSHKeySourceRepository{
+		client: &http.Client{Timeout: 10 * time.Second},
+		urls:   urls,
+	}
+}
+
+// FetchPublicKeys downloads the keys an account publishes
+func (r *HTTPSSHKeySourceRepository) FetchPublicKeys(provider string, account string) ([]string, error) {
+	template, ok := r.urls[provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported key provider: %s", provider)
+	}
+	url := fmt.Sprintf(template, account)
+	if err := interfaces.RequireNetwork("fetch keys from " + provider); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "hardn-key-import")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch keys from %s: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%s user %s not found", provider, account)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to fetch keys from %s: %s", provider, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPublishedKeysSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keys from %s: %w", provider, err)
+	}
+
+	var keys []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			keys = append(keys, line)
+		}
+	}
+	return keys, nil
+}
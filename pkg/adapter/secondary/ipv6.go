@@ -0,0 +1,164 @@
+// pkg/adapter/secondary/ipv6.go
+package secondary
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+)
+
+const (
+	// IPv6SysctlPath is the sysctl file hardn turns IPv6 off with
+	IPv6SysctlPath = model.SysctlDropInDir + "/60-hardn-disable-ipv6.conf"
+
+	// GrubDefaultsPath holds the kernel command line GRUB boots with
+	GrubDefaultsPath = "/etc/default/grub"
+
+	// IPv6BootParameter keeps the kernel from starting IPv6
+	IPv6BootParameter = "ipv6.disable=1"
+)
+
+// ipv6SysctlKeys turn IPv6 off on every interface, present and future
+var ipv6SysctlKeys = []string{
+	"net.ipv6.conf.all.disable_ipv6",
+	"net.ipv6.conf.default.disable_ipv6",
+	"net.ipv6.conf.lo.disable_ipv6",
+}
+
+// ipv6Kernel reports whether the kernel runs IPv6, and whether its
+// command line turns IPv6 off
+func ipv6Kernel(fs interfaces.FileSystem) (bool, bool) {
+	bootDisabled := false
+	if cmdline, err := fs.ReadFile(ProcCmdlinePath); err == nil {
+		bootDisabled = slices.Contains(strings.Fields(string(cmdline)), IPv6BootParameter)
+	}
+	disabled, err := fs.ReadFile(ProcIPv6DisablePath)
+	return err == nil && strings.TrimSpace(string(disabled)) == "0", bootDisabled
+}
+
+// setIPv6Kernel turns IPv6 off at once with sysctl, and for later boots
+// with a sysctl file or, given bootParameter, on the kernel command line.
+// Turning IPv6 on only undoes what hardn did.
+func setIPv6Kernel(fs interfaces.FileSystem, commander interfaces.Commander, osType string, disabled bool, bootParameter bool) error {
+	_, sysctlErr := fs.Stat(IPv6SysctlPath)
+	sysctlManaged := sysctlErr == nil
+	_, runningErr := fs.Stat(ProcIPv6DisablePath)
+	running := runningErr == nil
+
+	if !disabled {
+		if sysctlManaged {
+			if err := fs.Remove(IPv6SysctlPath); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", IPv6SysctlPath, err)
+			}
+			if running {
+				if err := setIPv6Sysctl(commander, "0"); err != nil {
+					return err
+				}
+			}
+		}
+		return setGrubIPv6(fs, commander, osType, false)
+	}
+
+	if running {
+		if err := setIPv6Sysctl(commander, "1"); err != nil {
+			return err
+		}
+	}
+
+	// Keys of a sysctl file must exist, which they do not once the kernel
+	// command line turns IPv6 off, so only one of the two is kept
+	if bootParameter {
+		if sysctlManaged {
+			if err := fs.Remove(IPv6SysctlPath); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", IPv6SysctlPath, err)
+			}
+		}
+		return setGrubIPv6(fs, commander, osType, true)
+	}
+
+	var content strings.Builder
+	content.WriteString("# Managed by hardn: IPv6 turned off\n")
+	for _, key := range ipv6SysctlKeys {
+		fmt.Fprintf(&content, "%s = 1\n", key)
+	}
+	if err := fs.WriteFile(IPv6SysctlPath, []byte(content.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", IPv6SysctlPath, err)
+	}
+	return setGrubIPv6(fs, commander, osType, false)
+}
+
+// setIPv6Sysctl sets disable_ipv6 on every interface of the running kernel
+func setIPv6Sysctl(commander interfaces.Commander, value string) error {
+	for _, key := range ipv6SysctlKeys {
+		if output, err := commander.Execute("sysctl", "-w", key+"="+value); err != nil {
+			return fmt.Errorf("failed to set %s: %w\nOutput: %s", key, err, string(output))
+		}
+	}
+	return nil
+}
+
+// setGrubIPv6 adds IPv6BootParameter to GRUB's kernel command line, or
+// removes it, and updates the boot entries. grubby updates the entries of
+// the installed kernels on the RHEL family, update-grub elsewhere.
+func setGrubIPv6(fs interfaces.FileSystem, commander interfaces.Commander, osType string, present bool) error {
+	defaults, err := fs.ReadFile(GrubDefaultsPath)
+	if err != nil {
+		if !present {
+			return nil
+		}
+		return fmt.Errorf("no %s to turn IPv6 off at boot; only GRUB is supported", GrubDefaultsPath)
+	}
+
+	updated, changed := SetGrubCmdlineParameter(string(defaults), IPv6BootParameter, present)
+	if !changed {
+		return nil
+	}
+	if err := fs.WriteFile(GrubDefaultsPath, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", GrubDefaultsPath, err)
+	}
+
+	var output []byte
+	switch {
+	case model.IsRHELFamily(osType) && present:
+		output, err = commander.Execute("grubby", "--update-kernel=ALL", "--args="+IPv6BootParameter)
+	case model.IsRHELFamily(osType):
+		output, err = commander.Execute("grubby", "--update-kernel=ALL", "--remove-args="+IPv6BootParameter)
+	default:
+		output, err = commander.Execute("update-grub")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update the boot entries: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// SetGrubCmdlineParameter adds a parameter to GRUB_CMDLINE_LINUX in
+// /etc/default/grub, or removes it, and reports whether that changed it
+func SetGrubCmdlineParameter(defaults string, parameter string, present bool) (string, bool) {
+	lines := strings.Split(strings.TrimRight(defaults, "\n"), "\n")
+	for i, line := range lines {
+		value, found := strings.CutPrefix(strings.TrimSpace(line), "GRUB_CMDLINE_LINUX=")
+		if !found {
+			continue
+		}
+		params := strings.Fields(strings.Trim(value, `"'`))
+		if slices.Contains(params, parameter) == present {
+			return defaults, false
+		}
+		if present {
+			params = append(params, parameter)
+		} else {
+			params = slices.DeleteFunc(params, func(p string) bool { return p == parameter })
+		}
+		lines[i] = `GRUB_CMDLINE_LINUX="` + strings.Join(params, " ") + `"`
+		return strings.Join(lines, "\n") + "\n", true
+	}
+
+	if !present {
+		return defaults, false
+	}
+	return strings.Join(append(lines, `GRUB_CMDLINE_LINUX="`+parameter+`"`), "\n") + "\n", true
+}
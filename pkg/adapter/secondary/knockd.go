@@ -0,0 +1,144 @@
+// pkg/adapter/secondary/knockd.go
+package secondary
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+)
+
+const (
+	// KnockdConfPath holds knockd's options and knock sequences
+	KnockdConfPath = "/etc/knockd.conf"
+
+	// KnockdDefaultsPath starts knockd and passes its options on Debian
+	// and Ubuntu
+	KnockdDefaultsPath = "/etc/default/knockd"
+
+	// knockdHeader marks a knockd configuration written by hardn
+	knockdHeader = "# Managed by hardn: SSH stays closed until the knock sequence"
+)
+
+// KnockdConfig returns a knockd configuration running startCommand when
+// an address knocks the sequence, and stopCommand once the port has been
+// open for OpenTimeout seconds. knockd replaces %IP% with the address.
+func KnockdConfig(knocking model.PortKnocking, iface string, startCommand string, stopCommand string) string {
+	var sequence []string
+	for _, port := range knocking.Sequence {
+		sequence = append(sequence, strconv.Itoa(port))
+	}
+
+	var content strings.Builder
+	content.WriteString(knockdHeader + "\n")
+	content.WriteString("[options]\n")
+	content.WriteString("\tUseSyslog\n")
+	fmt.Fprintf(&content, "\tInterface = %s\n\n", iface)
+	content.WriteString("[openSSH]\n")
+	fmt.Fprintf(&content, "\tsequence      = %s\n", strings.Join(sequence, ","))
+	fmt.Fprintf(&content, "\tseq_timeout   = %d\n", knocking.SeqTimeout)
+	content.WriteString("\ttcpflags      = syn\n")
+	fmt.Fprintf(&content, "\tstart_command = %s\n", startCommand)
+	fmt.Fprintf(&content, "\tcmd_timeout   = %d\n", knocking.OpenTimeout)
+	fmt.Fprintf(&content, "\tstop_command  = %s\n", stopCommand)
+	return content.String()
+}
+
+// configureKnockd installs knockd, writes its configuration and starts it.
+// Without knocking, a knockd started by hardn is stopped and disabled.
+func configureKnockd(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	osType string,
+	knocking *model.PortKnocking,
+	startCommand string,
+	stopCommand string,
+) error {
+	if knocking == nil {
+		if !knockdManaged(fs) {
+			return nil
+		}
+		if err := NewServiceManager(commander, osType).Disable("knockd"); err != nil {
+			return fmt.Errorf("failed to stop knockd: %w", err)
+		}
+		return nil
+	}
+
+	iface := knocking.Interface
+	if iface == "" {
+		if data, err := fs.ReadFile(ProcNetRoutePath); err == nil {
+			iface = ParseDefaultRouteInterface(data)
+		}
+		if iface == "" {
+			return fmt.Errorf("no default route to find knockd's interface; set portKnocking.interface")
+		}
+	}
+
+	if err := installKnockd(commander, osType); err != nil {
+		return err
+	}
+
+	config := KnockdConfig(*knocking, iface, startCommand, stopCommand)
+	if err := fs.WriteFile(KnockdConfPath, []byte(config), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", KnockdConfPath, err)
+	}
+	if !model.IsRHELFamily(osType) {
+		defaults := fmt.Sprintf("# Managed by hardn\nSTART_KNOCKD=1\nKNOCKD_OPTS=\"-i %s\"\n", iface)
+		if err := fs.WriteFile(KnockdDefaultsPath, []byte(defaults), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", KnockdDefaultsPath, err)
+		}
+	}
+
+	// Restarted, so a running knockd reads the new configuration
+	services := NewServiceManager(commander, osType)
+	if err := services.Enable("knockd"); err != nil {
+		return fmt.Errorf("failed to enable knockd: %w", err)
+	}
+	if err := services.Restart("knockd"); err != nil {
+		return fmt.Errorf("failed to start knockd: %w", err)
+	}
+	return nil
+}
+
+// installKnockd installs knockd: the knockd package on Debian and Ubuntu,
+// knock-server on the RHEL family (from EPEL on RHEL and its rebuilds)
+func installKnockd(commander interfaces.Commander, osType string) error {
+	if _, err := commander.Execute("which", "knockd"); err == nil {
+		return nil
+	}
+
+	var err error
+	switch {
+	case osType == "alpine":
+		return fmt.Errorf("port knocking is not available on Alpine Linux")
+	case model.IsRHELFamily(osType):
+		manager := "dnf"
+		if _, whichErr := commander.Execute("which", "dnf"); whichErr != nil {
+			manager = "yum"
+		}
+		_, err = commander.Execute(manager, "install", "-y", "knock-server")
+	default:
+		_, err = commander.Execute("apt-get", "install", "-y", "knockd")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to install knockd: %w", err)
+	}
+	return nil
+}
+
+// knockdManaged reports whether knockd's configuration was written by hardn
+func knockdManaged(fs interfaces.FileSystem) bool {
+	data, err := fs.ReadFile(KnockdConfPath)
+	return err == nil && strings.HasPrefix(string(data), knockdHeader)
+}
+
+// knockdActive reports whether knockd runs with hardn's configuration
+func knockdActive(fs interfaces.FileSystem, commander interfaces.Commander, osType string) bool {
+	if !knockdManaged(fs) {
+		return false
+	}
+	status, err := NewServiceManager(commander, osType).Status("knockd")
+	return err == nil && status.Active
+}
@@ -3,12 +3,12 @@ package secondary
 
 import (
 	"fmt"
-	"os/exec"
 	"regexp"
 	"strings"
 	"time"
 
 	domainports "github.com/abbott/hardn/pkg/domain/ports/secondary"
+	"github.com/abbott/hardn/pkg/interfaces"
 )
 
 // LastlogCommandAdapter implements UserLoginPort using the 'lastlog' command
@@ -22,7 +22,7 @@ func NewLastlogCommandAdapter() domainports.UserLoginPort {
 
 // GetLastLoginTime implements UserLoginPort.GetLastLoginTime
 func (a *LastlogCommandAdapter) GetLastLoginTime(username string) (time.Time, error) {
-	cmd := exec.Command("lastlog", "-u", username)
+	cmd := interfaces.Command("lastlog", "-u", username)
 	output, err := cmd.Output()
 	if err != nil {
 		return time.Time{}, fmt.Errorf("failed to execute lastlog command: %w", err)
@@ -33,7 +33,7 @@ func (a *LastlogCommandAdapter) GetLastLoginTime(username string) (time.Time, er
 
 // GetLastLoginInfo implements UserLoginPort.GetLastLoginInfo
 func (a *LastlogCommandAdapter) GetLastLoginInfo(username string) (time.Time, string, error) {
-	cmd := exec.Command("lastlog", "-u", username)
+	cmd := interfaces.Command("lastlog", "-u", username)
 	output, err := cmd.Output()
 	if err != nil {
 		return time.Time{}, "", fmt.Errorf("failed to execute lastlog command: %w", err)
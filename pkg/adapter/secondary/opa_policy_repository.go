@@ -0,0 +1,149 @@
+// pkg/adapter/secondary/opa_policy_repository.go
+package secondary
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// OPAPolicyRepository implements PolicyRepository using the opa command line tool
+type OPAPolicyRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+}
+
+// NewOPAPolicyRepository creates a new OPAPolicyRepository
+func NewOPAPolicyRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+) secondary.PolicyRepository {
+	return &OPAPolicyRepository{
+		fs:        fs,
+		commander: commander,
+	}
+}
+
+// EvaluatePolicies runs `opa eval` over the rego files in policyDir with the
+// facts document as input and collects the results of data.hardn.deny
+func (r *OPAPolicyRepository) EvaluatePolicies(policyDir string, facts *model.Facts) ([]model.PolicyViolation, error) {
+	if _, err := r.fs.Stat(policyDir); err != nil {
+		return nil, fmt.Errorf("policy directory %s not found: %w", policyDir, err)
+	}
+
+	if _, err := r.commander.Execute("which", "opa"); err != nil {
+		return nil, fmt.Errorf("opa not found in PATH; install Open Policy Agent to evaluate policies")
+	}
+
+	input, err := json.Marshal(facts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode facts: %w", err)
+	}
+
+	output, err := r.commander.ExecuteWithInput(string(input), "opa", "eval",
+		"--format", "json", "--data", policyDir, "--stdin-input", model.PolicyQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate policies: %w\nOutput: %s", err, string(output))
+	}
+
+	return ParseOPAViolations(output)
+}
+
+// ParseOPAViolations converts `opa eval --format json` output into
+// violations. A rule may produce strings, objects with msg/rule/severity
+// fields, or a map of rule name to message.
+func ParseOPAViolations(output []byte) ([]model.PolicyViolation, error) {
+	var result struct {
+		Result []struct {
+			Expressions []struct {
+				Value json.RawMessage `json:"value"`
+			} `json:"expressions"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse opa output: %w", err)
+	}
+
+	var violations []model.PolicyViolation
+	for _, res := range result.Result {
+		for _, expr := range res.Expressions {
+			parsed, err := parseOPAValue(expr.Value)
+			if err != nil {
+				return nil, err
+			}
+			violations = append(violations, parsed...)
+		}
+	}
+
+	return violations, nil
+}
+
+// parseOPAValue handles the set (array) and object forms of a deny rule
+func parseOPAValue(value json.RawMessage) ([]model.PolicyViolation, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(value, &items); err == nil {
+		var violations []model.PolicyViolation
+		for _, item := range items {
+			violations = append(violations, parseOPAItem(item, ""))
+		}
+		return violations, nil
+	}
+
+	var keyed map[string]json.RawMessage
+	if err := json.Unmarshal(value, &keyed); err != nil {
+		return nil, fmt.Errorf("unexpected policy result: %s", string(value))
+	}
+
+	keys := make([]string, 0, len(keyed))
+	for key := range keyed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var violations []model.PolicyViolation
+	for _, key := range keys {
+		violations = append(violations, parseOPAItem(keyed[key], key))
+	}
+	return violations, nil
+}
+
+// parseOPAItem reads a single violation, either a message or an object
+func parseOPAItem(item json.RawMessage, rule string) model.PolicyViolation {
+	var message string
+	if err := json.Unmarshal(item, &message); err == nil {
+		return model.PolicyViolation{Rule: rule, Message: message}
+	}
+
+	var fields struct {
+		Msg      string `json:"msg"`
+		Message  string `json:"message"`
+		Rule     string `json:"rule"`
+		ID       string `json:"id"`
+		Severity string `json:"severity"`
+	}
+	if err := json.Unmarshal(item, &fields); err != nil {
+		return model.PolicyViolation{Rule: rule, Message: string(item)}
+	}
+
+	violation := model.PolicyViolation{
+		Rule:     rule,
+		Severity: fields.Severity,
+		Message:  fields.Msg,
+	}
+	if violation.Message == "" {
+		violation.Message = fields.Message
+	}
+	if fields.Rule != "" {
+		violation.Rule = fields.Rule
+	} else if fields.ID != "" {
+		violation.Rule = fields.ID
+	}
+	if violation.Message == "" {
+		violation.Message = string(item)
+	}
+	return violation
+}
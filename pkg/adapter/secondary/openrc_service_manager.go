@@ -0,0 +1,98 @@
+// pkg/adapter/secondary/openrc_service_manager.go
+package secondary
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// OpenRCServiceManager implements ServiceManager with rc-service and
+// rc-update, enabling services in the default runlevel
+type OpenRCServiceManager struct {
+	commander interfaces.Commander
+}
+
+// NewOpenRCServiceManager creates a new OpenRCServiceManager
+func NewOpenRCServiceManager(commander interfaces.Commander) secondary.ServiceManager {
+	return &OpenRCServiceManager{commander: commander}
+}
+
+// InitSystem returns openrc
+func (m *OpenRCServiceManager) InitSystem() string {
+	return model.InitOpenRC
+}
+
+// Enable adds a service to the default runlevel and starts it
+func (m *OpenRCServiceManager) Enable(name string) error {
+	if output, err := m.commander.Execute("rc-update", "add", name, model.OpenRCRunlevel); err != nil {
+		return fmt.Errorf("failed to add %s to the %s runlevel: %w\nOutput: %s", name, model.OpenRCRunlevel, err, string(output))
+	}
+	return m.Start(name)
+}
+
+// Disable stops a service and removes it from the default runlevel; a
+// service that is already stopped or not in the runlevel is left alone
+func (m *OpenRCServiceManager) Disable(name string) error {
+	status, _ := m.Status(name)
+	if status.Active {
+		if err := m.Stop(name); err != nil {
+			return err
+		}
+	}
+	if status.Enabled {
+		if output, err := m.commander.Execute("rc-update", "del", name, model.OpenRCRunlevel); err != nil {
+			return fmt.Errorf("failed to remove %s from the %s runlevel: %w\nOutput: %s", name, model.OpenRCRunlevel, err, string(output))
+		}
+	}
+	return nil
+}
+
+// Start starts a service
+func (m *OpenRCServiceManager) Start(name string) error {
+	return m.rcService(name, "start")
+}
+
+// Stop stops a service
+func (m *OpenRCServiceManager) Stop(name string) error {
+	return m.rcService(name, "stop")
+}
+
+// Restart restarts a service
+func (m *OpenRCServiceManager) Restart(name string) error {
+	return m.rcService(name, "restart")
+}
+
+// Status reports whether a service is started and in the default runlevel
+func (m *OpenRCServiceManager) Status(name string) (*model.ServiceStatus, error) {
+	status := &model.ServiceStatus{Name: name}
+	_, err := m.commander.Execute("rc-service", name, "status")
+	status.Active = err == nil
+
+	if output, err := m.commander.Execute("rc-update", "show", model.OpenRCRunlevel); err == nil {
+		status.Enabled = RunlevelHasService(string(output), name)
+	}
+	return status, nil
+}
+
+// rcService runs an rc-service action on a service
+func (m *OpenRCServiceManager) rcService(name string, action string) error {
+	if output, err := m.commander.Execute("rc-service", name, action); err != nil {
+		return fmt.Errorf("rc-service %s %s failed: %w\nOutput: %s", name, action, err, string(output))
+	}
+	return nil
+}
+
+// RunlevelHasService reports whether "rc-update show" output, with lines
+// like " sshd | default", lists a service
+func RunlevelHasService(output string, name string) bool {
+	for _, line := range strings.Split(output, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 && fields[0] == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+// pkg/adapter/secondary/os_banner_repository.go
+package secondary
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// bannerMarker identifies the update-motd script written by hardn
+const bannerMarker = "Managed by hardn"
+
+// motdScript prints the hardn MOTD. pam_motd runs it as root at each login,
+// so a slow risk check is cut short rather than holding up the login.
+var motdScript = fmt.Sprintf(`#!/bin/sh
+# %s: message of the day
+HARDN=$(command -v hardn) || exit 0
+exec timeout 10 "$HARDN" banner motd 2>/dev/null
+`, bannerMarker)
+
+// motdNewsEnabled matches the line of /etc/default/motd-news that turns it on
+var motdNewsEnabled = regexp.MustCompile(`(?m)^ENABLED=.*$`)
+
+// OSBannerRepository implements BannerRepository with the banner files and
+// the update-motd scripts
+type OSBannerRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	osType    string
+}
+
+// NewOSBannerRepository creates a new OSBannerRepository
+func NewOSBannerRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	osType string,
+) secondary.BannerRepository {
+	return &OSBannerRepository{
+		fs:        fs,
+		commander: commander,
+		osType:    osType,
+	}
+}
+
+// WriteBanner replaces a banner file
+func (r *OSBannerRepository) WriteBanner(path string, content string) error {
+	if err := r.fs.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// InstallMotdScript adds the update-motd script that runs "hardn banner motd"
+func (r *OSBannerRepository) InstallMotdScript() error {
+	if err := r.fs.WriteFile(model.UpdateMotdScriptPath, []byte(motdScript), 0755); err != nil {
+		return fmt.Errorf("failed to write %s: %w", model.UpdateMotdScriptPath, err)
+	}
+	return nil
+}
+
+// DisableMotdAds removes the execute bit of Ubuntu's advertising scripts,
+// which is how update-motd expects scripts to be turned off, and disables
+// the motd-news download
+func (r *OSBannerRepository) DisableMotdAds() ([]string, error) {
+	var disabled []string
+	for _, name := range r.enabledMotdAds() {
+		path := filepath.Join(model.UpdateMotdDir, name)
+		if output, err := r.commander.Execute("chmod", "-x", path); err != nil {
+			return disabled, fmt.Errorf("failed to disable %s: %w\nOutput: %s", path, err, string(output))
+		}
+		disabled = append(disabled, name)
+	}
+
+	data, err := r.fs.ReadFile(model.UbuntuMotdNewsPath)
+	if err != nil {
+		return disabled, nil // motd-news is not installed
+	}
+	content := string(data)
+	if motdNewsEnabled.MatchString(content) {
+		content = motdNewsEnabled.ReplaceAllString(content, "ENABLED=0")
+	} else {
+		content = strings.TrimRight(content, "\n") + "\nENABLED=0\n"
+	}
+	if content != string(data) {
+		if err := r.fs.WriteFile(model.UbuntuMotdNewsPath, []byte(content), 0644); err != nil {
+			return disabled, fmt.Errorf("failed to write %s: %w", model.UbuntuMotdNewsPath, err)
+		}
+	}
+	return disabled, nil
+}
+
+// Hostname returns the name of the host
+func (r *OSBannerRepository) Hostname() (string, error) {
+	return os.Hostname()
+}
+
+// PendingUpdates counts the packages with an upgrade available, without
+// refreshing the package lists
+func (r *OSBannerRepository) PendingUpdates() (int, error) {
+	switch {
+	case r.osType == "debian" || r.osType == "ubuntu":
+		output, err := r.commander.Execute("apt-get", "-s", "-o", "Debug::NoLocking=true", "upgrade")
+		if err != nil {
+			return -1, fmt.Errorf("failed to list upgrades: %w", err)
+		}
+		return CountAptUpgrades(output), nil
+	case model.IsRHELFamily(r.osType):
+		// check-update exits with 100 when updates are available
+		output, err := r.commander.Execute("dnf", "-q", "check-update")
+		count := CountDnfUpdates(output)
+		if err != nil && count == 0 {
+			return -1, fmt.Errorf("failed to list upgrades: %w", err)
+		}
+		return count, nil
+	case r.osType == "alpine":
+		output, err := r.commander.Execute("apk", "list", "-u")
+		if err != nil {
+			return -1, fmt.Errorf("failed to list upgrades: %w", err)
+		}
+		count := 0
+		for _, line := range strings.Split(string(output), "\n") {
+			if strings.TrimSpace(line) != "" {
+				count++
+			}
+		}
+		return count, nil
+	}
+	return -1, fmt.Errorf("counting upgrades is not supported on %s", r.osType)
+}
+
+// GetBannerStatus reports the banner files, the hardn update-motd script
+// and the advertising scripts still enabled
+func (r *OSBannerRepository) GetBannerStatus() (*model.BannerStatus, error) {
+	status := &model.BannerStatus{}
+
+	if _, err := r.fs.Stat(model.IssuePath); err == nil {
+		status.Issue = true
+	}
+	if _, err := r.fs.Stat(model.IssueNetPath); err == nil {
+		status.IssueNet = true
+	}
+	if info, err := r.fs.Stat(model.UpdateMotdDir); err == nil && info.IsDir() {
+		status.UpdateMotd = true
+	}
+	if data, err := r.fs.ReadFile(model.UpdateMotdScriptPath); err == nil {
+		status.DynamicMotd = strings.Contains(string(data), bannerMarker)
+	}
+	status.MotdAds = r.enabledMotdAds()
+	return status, nil
+}
+
+// enabledMotdAds lists the advertising scripts update-motd would run
+func (r *OSBannerRepository) enabledMotdAds() []string {
+	var enabled []string
+	for _, name := range model.UbuntuMotdAdScripts {
+		info, err := r.fs.Stat(filepath.Join(model.UpdateMotdDir, name))
+		if err == nil && info.Mode()&0111 != 0 {
+			enabled = append(enabled, name)
+		}
+	}
+	return enabled
+}
+
+// CountAptUpgrades counts the packages a simulated apt-get upgrade would
+// install
+func CountAptUpgrades(output []byte) int {
+	count := 0
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "Inst ") {
+			count++
+		}
+	}
+	return count
+}
+
+// CountDnfUpdates counts the packages listed by dnf check-update, leaving
+// out the obsoleted packages that follow the updates
+func CountDnfUpdates(output []byte) int {
+	count := 0
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "Obsoleting") {
+			break
+		}
+		if fields := strings.Fields(line); len(fields) == 3 && !strings.HasPrefix(line, " ") {
+			count++
+		}
+	}
+	return count
+}
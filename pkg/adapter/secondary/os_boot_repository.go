@@ -0,0 +1,293 @@
+// pkg/adapter/secondary/os_boot_repository.go
+package secondary
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// grubUnrestrictedClass is the option 10_linux gives its entries so they
+// boot without the superuser's password
+const grubUnrestrictedClass = "--unrestricted"
+
+// OSBootRepository implements BootRepository for GRUB. The RHEL family
+// keeps the kernel command line in its boot entries, which grubby edits;
+// elsewhere grub-mkconfig generates grub.cfg from /etc/default/grub.
+type OSBootRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	osType    string
+}
+
+// NewOSBootRepository creates a new OSBootRepository
+func NewOSBootRepository(fs interfaces.FileSystem, commander interfaces.Commander, osType string) secondary.BootRepository {
+	return &OSBootRepository{
+		fs:        fs,
+		commander: commander,
+		osType:    osType,
+	}
+}
+
+// readGrubDefaults reads /etc/default/grub, which both families have
+func (r *OSBootRepository) readGrubDefaults() (string, error) {
+	defaults, err := r.fs.ReadFile(GrubDefaultsPath)
+	if err != nil {
+		return "", fmt.Errorf("no %s; only GRUB is supported", GrubDefaultsPath)
+	}
+	return string(defaults), nil
+}
+
+// GrubCmdlineParameters returns the parameters of GRUB_CMDLINE_LINUX and
+// GRUB_CMDLINE_LINUX_DEFAULT in /etc/default/grub
+func GrubCmdlineParameters(defaults string) []string {
+	var params []string
+	for _, line := range strings.Split(defaults, "\n") {
+		line = strings.TrimSpace(line)
+		for _, key := range []string{"GRUB_CMDLINE_LINUX=", "GRUB_CMDLINE_LINUX_DEFAULT="} {
+			if value, found := strings.CutPrefix(line, key); found {
+				params = append(params, strings.Fields(strings.Trim(value, `"'`))...)
+			}
+		}
+	}
+	return params
+}
+
+// SetGrubCmdlineParameters adds parameters to GRUB_CMDLINE_LINUX in
+// /etc/default/grub, replacing those with the same name, and reports
+// whether that changed it
+func SetGrubCmdlineParameters(defaults string, parameters []string) (string, bool) {
+	updated, changed := defaults, false
+	for _, parameter := range parameters {
+		name := model.BootParameterName(parameter)
+		lines := strings.Split(strings.TrimRight(updated, "\n"), "\n")
+		found := false
+		for i, line := range lines {
+			value, ok := strings.CutPrefix(strings.TrimSpace(line), "GRUB_CMDLINE_LINUX=")
+			if !ok {
+				continue
+			}
+			found = true
+			params := strings.Fields(strings.Trim(value, `"'`))
+			if slices.Contains(params, parameter) {
+				break
+			}
+			params = slices.DeleteFunc(params, func(p string) bool { return model.BootParameterName(p) == name })
+			lines[i] = `GRUB_CMDLINE_LINUX="` + strings.Join(append(params, parameter), " ") + `"`
+			updated, changed = strings.Join(lines, "\n")+"\n", true
+			break
+		}
+		if !found {
+			updated = strings.Join(append(lines, `GRUB_CMDLINE_LINUX="`+parameter+`"`), "\n") + "\n"
+			changed = true
+		}
+	}
+	return updated, changed
+}
+
+func (r *OSBootRepository) GetConfiguredParameters() ([]string, error) {
+	if model.IsRHELFamily(r.osType) {
+		output, err := r.commander.Execute("grubby", "--info=DEFAULT")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the default boot entry: %w\nOutput: %s", err, string(output))
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			if value, found := strings.CutPrefix(strings.TrimSpace(line), "args="); found {
+				return strings.Fields(strings.Trim(value, `"`)), nil
+			}
+		}
+		return nil, nil
+	}
+
+	defaults, err := r.readGrubDefaults()
+	if err != nil {
+		return nil, err
+	}
+	return GrubCmdlineParameters(defaults), nil
+}
+
+func (r *OSBootRepository) GetRunningParameters() ([]string, error) {
+	cmdline, err := r.fs.ReadFile(ProcCmdlinePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ProcCmdlinePath, err)
+	}
+	return strings.Fields(string(cmdline)), nil
+}
+
+func (r *OSBootRepository) SetParameters(parameters []string) error {
+	defaults, err := r.readGrubDefaults()
+	if err != nil {
+		return err
+	}
+
+	// /etc/default/grub is kept in step on the RHEL family too, where
+	// kernels installed later take their arguments from it
+	updated, changed := SetGrubCmdlineParameters(defaults, parameters)
+	if changed {
+		if err := r.fs.WriteFile(GrubDefaultsPath, []byte(updated), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", GrubDefaultsPath, err)
+		}
+	}
+
+	if model.IsRHELFamily(r.osType) {
+		if output, err := r.commander.Execute("grubby", "--update-kernel=ALL", "--args="+strings.Join(parameters, " ")); err != nil {
+			return fmt.Errorf("failed to update the boot entries: %w\nOutput: %s", err, string(output))
+		}
+		return nil
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := r.regenerateGrubConfig(); err != nil {
+		// Leave /etc/default/grub as it was, so the next update-grub does
+		// not pick up the change
+		if restoreErr := r.fs.WriteFile(GrubDefaultsPath, []byte(defaults), 0644); restoreErr != nil {
+			return fmt.Errorf("%w; restoring %s also failed: %v", err, GrubDefaultsPath, restoreErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// regenerateGrubConfig generates grub.cfg next to the current one and
+// only moves it into place once grub-script-check accepts it, so a failed
+// run never leaves the host with a broken boot loader configuration
+func (r *OSBootRepository) regenerateGrubConfig() error {
+	staged := model.GrubConfigPath + ".hardn-new"
+	if output, err := r.commander.Execute("grub-mkconfig", "-o", staged); err != nil {
+		_ = r.fs.Remove(staged)
+		return fmt.Errorf("failed to generate %s: %w\nOutput: %s", model.GrubConfigPath, err, string(output))
+	}
+	if output, err := r.commander.Execute("grub-script-check", staged); err != nil {
+		_ = r.fs.Remove(staged)
+		return fmt.Errorf("generated GRUB configuration is invalid, %s left unchanged: %w\nOutput: %s",
+			model.GrubConfigPath, err, string(output))
+	}
+
+	// Nothing is generated in dry-run mode
+	if _, err := r.fs.Stat(staged); err != nil {
+		return nil
+	}
+	if err := r.fs.Rename(staged, model.GrubConfigPath); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", model.GrubConfigPath, err)
+	}
+	return nil
+}
+
+// ParseGrubPasswordHash returns the hash grub-mkpasswd-pbkdf2 printed
+func ParseGrubPasswordHash(output string) (string, error) {
+	for _, field := range strings.Fields(output) {
+		if strings.HasPrefix(field, model.GrubPasswordHashPrefix) {
+			return field, nil
+		}
+	}
+	return "", fmt.Errorf("no PBKDF2 hash in the output of grub-mkpasswd-pbkdf2")
+}
+
+func (r *OSBootRepository) HashPassword(password string) (string, error) {
+	command := "grub-mkpasswd-pbkdf2"
+	if model.IsRHELFamily(r.osType) {
+		command = "grub2-mkpasswd-pbkdf2"
+	}
+	// The password is asked for twice
+	output, err := r.commander.ExecuteWithInput(password+"\n"+password+"\n", command)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash the GRUB password: %w", err)
+	}
+	return ParseGrubPasswordHash(string(output))
+}
+
+// MarkGrubEntriesUnrestricted adds --unrestricted to the class of the
+// entries 10_linux generates, and reports whether that changed it
+func MarkGrubEntriesUnrestricted(script string) (string, bool) {
+	lines := strings.Split(script, "\n")
+	changed := false
+	for i, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), `CLASS="`) || strings.Contains(line, grubUnrestrictedClass) {
+			continue
+		}
+		lines[i] = strings.Replace(line, `CLASS="`, `CLASS="`+grubUnrestrictedClass+" ", 1)
+		changed = true
+	}
+	return strings.Join(lines, "\n"), changed
+}
+
+func (r *OSBootRepository) SetPassword(hash string) error {
+	if !strings.HasPrefix(hash, model.GrubPasswordHashPrefix) {
+		return fmt.Errorf("the GRUB password must be a %s hash", model.GrubPasswordHashPrefix)
+	}
+
+	// grub2-setpassword writes the same file
+	if model.IsRHELFamily(r.osType) {
+		if err := r.fs.WriteFile(model.GrubUserConfigPath, []byte("GRUB2_PASSWORD="+hash+"\n"), 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", model.GrubUserConfigPath, err)
+		}
+		return nil
+	}
+
+	if _, err := r.readGrubDefaults(); err != nil {
+		return err
+	}
+	script := fmt.Sprintf(`#!/bin/sh
+# Managed by hardn: GRUB asks for this password to edit entries or use its shell
+cat <<'EOF'
+set superusers="%s"
+password_pbkdf2 %s %s
+EOF
+`, model.GrubSuperuser, model.GrubSuperuser, hash)
+	if err := r.fs.WriteFile(model.GrubPasswordScriptPath, []byte(script), 0700); err != nil {
+		return fmt.Errorf("failed to write %s: %w", model.GrubPasswordScriptPath, err)
+	}
+
+	// Without --unrestricted every entry would need the password to boot
+	linux, err := r.fs.ReadFile(model.GrubLinuxScriptPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", model.GrubLinuxScriptPath, err)
+	}
+	if updated, changed := MarkGrubEntriesUnrestricted(string(linux)); changed {
+		if err := r.fs.WriteFile(model.GrubLinuxScriptPath, []byte(updated), 0755); err != nil {
+			return fmt.Errorf("failed to write %s: %w", model.GrubLinuxScriptPath, err)
+		}
+	}
+
+	if err := r.regenerateGrubConfig(); err != nil {
+		_ = r.fs.Remove(model.GrubPasswordScriptPath)
+		return err
+	}
+	return nil
+}
+
+func (r *OSBootRepository) PasswordSet() (bool, error) {
+	path := model.GrubPasswordScriptPath
+	if model.IsRHELFamily(r.osType) {
+		path = model.GrubUserConfigPath
+	}
+	if _, err := r.fs.Stat(path); err != nil {
+		return false, nil
+	}
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return strings.Contains(string(data), model.GrubPasswordHashPrefix), nil
+}
+
+func (r *OSBootRepository) ConfigFiles() []string {
+	candidates := []string{GrubDefaultsPath, model.GrubConfigPath, model.GrubLinuxScriptPath, model.GrubPasswordScriptPath}
+	if model.IsRHELFamily(r.osType) {
+		candidates = []string{GrubDefaultsPath, model.GrubUserConfigPath}
+	}
+
+	var files []string
+	for _, path := range candidates {
+		if _, err := r.fs.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	return files
+}
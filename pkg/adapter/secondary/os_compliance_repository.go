@@ -0,0 +1,93 @@
+// pkg/adapter/secondary/os_compliance_repository.go
+package secondary
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+const (
+	sudoersPath = "/etc/sudoers"
+	sudoersDir  = "/etc/sudoers.d"
+)
+
+// OSComplianceRepository implements ComplianceRepository using OS operations
+type OSComplianceRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+}
+
+// NewOSComplianceRepository creates a new OSComplianceRepository
+func NewOSComplianceRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+) secondary.ComplianceRepository {
+	return &OSComplianceRepository{
+		fs:        fs,
+		commander: commander,
+	}
+}
+
+// CommandInstalled reports whether a command is on the PATH
+func (r *OSComplianceRepository) CommandInstalled(name string) bool {
+	_, err := r.commander.Execute("which", name)
+	return err == nil
+}
+
+// SSHEffectiveConfig returns the configuration sshd would run with, after
+// includes and defaults, as reported by sshd -T
+func (r *OSComplianceRepository) SSHEffectiveConfig() (map[string][]string, error) {
+	if !r.CommandInstalled("sshd") {
+		return nil, nil
+	}
+	output, err := r.commander.Execute("sshd", "-T")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the effective sshd configuration: %w\nOutput: %s", err, string(output))
+	}
+	return ParseSSHDTest(output), nil
+}
+
+// FileMode returns the permission bits of a file
+func (r *OSComplianceRepository) FileMode(path string) (fs.FileMode, error) {
+	info, err := r.fs.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return info.Mode().Perm(), nil
+}
+
+// ReadSudoers returns the files sudo reads: /etc/sudoers and the files in
+// /etc/sudoers.d whose names have no dot and do not end in ~
+func (r *OSComplianceRepository) ReadSudoers() (map[string][]byte, error) {
+	paths := []string{sudoersPath}
+	if _, err := r.fs.Stat(sudoersDir); err == nil {
+		output, err := r.commander.Execute("find", sudoersDir, "-mindepth", "1", "-maxdepth", "1", "-type", "f")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", sudoersDir, err)
+		}
+		for _, path := range strings.Fields(string(output)) {
+			name := filepath.Base(path)
+			if !strings.Contains(name, ".") && !strings.HasSuffix(name, "~") {
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	files := make(map[string][]byte)
+	for _, path := range paths {
+		if _, err := r.fs.Stat(path); err != nil {
+			continue
+		}
+		data, err := r.fs.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		files[path] = data
+	}
+	return files, nil
+}
@@ -0,0 +1,256 @@
+// pkg/adapter/secondary/os_database_repository.go
+package secondary
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// mysqlConfigDirs are searched in order for the directory read last by the
+// server, so the drop-in overrides distribution defaults such as bind-address
+var mysqlConfigDirs = []string{
+	"/etc/mysql/mariadb.conf.d",
+	"/etc/mysql/mysql.conf.d",
+	"/etc/my.cnf.d", // Alpine and the RHEL family
+	"/etc/mysql/conf.d",
+}
+
+const (
+	mysqlDropInName    = "99-hardn.cnf"
+	postgresDropInName = "hardn.conf"
+	mysqlDefaultPort   = 3306
+
+	// postgresSettingErrors lists configuration lines PostgreSQL cannot apply
+	postgresSettingErrors = "SELECT sourcefile || ':' || sourceline || ': ' || error " +
+		"FROM pg_file_settings WHERE error IS NOT NULL"
+)
+
+// OSDatabaseRepository implements DatabaseRepository for PostgreSQL and MySQL/MariaDB
+type OSDatabaseRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	services  secondary.ServiceManager
+	osType    string
+}
+
+// NewOSDatabaseRepository creates a new OSDatabaseRepository
+func NewOSDatabaseRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	osType string,
+) secondary.DatabaseRepository {
+	return &OSDatabaseRepository{
+		fs:        fs,
+		commander: commander,
+		services:  NewServiceManager(commander, osType),
+		osType:    osType,
+	}
+}
+
+// DetectDatabases returns the supported database servers on the host
+func (r *OSDatabaseRepository) DetectDatabases() ([]model.DatabaseServer, error) {
+	var servers []model.DatabaseServer
+
+	// PostgreSQL clusters are managed through postgresql-common on Debian/Ubuntu
+	if r.osType != "alpine" {
+		if output, err := r.commander.Execute("pg_lsclusters", "--no-header"); err == nil {
+			for _, cluster := range ParsePgLsclusters(output) {
+				cluster.BaselineApplied = r.isManaged(cluster.ConfigPath)
+				servers = append(servers, cluster)
+			}
+		}
+	}
+
+	if binary := r.mysqlBinary(); binary != "" {
+		flavor := "mysql"
+		if output, err := r.commander.Execute(binary, "--version"); err == nil &&
+			strings.Contains(string(output), "MariaDB") {
+			flavor = "mariadb"
+		}
+
+		configPath := filepath.Join(r.mysqlConfigDir(), mysqlDropInName)
+		servers = append(servers, model.DatabaseServer{
+			Name:            model.DatabaseMySQL,
+			Flavor:          flavor,
+			Port:            mysqlDefaultPort,
+			ConfigPath:      configPath,
+			BaselineApplied: r.isManaged(configPath),
+		})
+	}
+
+	return servers, nil
+}
+
+// WriteDatabaseBaseline installs the managed configuration drop-in
+func (r *OSDatabaseRepository) WriteDatabaseBaseline(server model.DatabaseServer, settings model.DatabaseHardening) error {
+	var content string
+	switch server.Name {
+	case model.DatabasePostgreSQL:
+		content = postgresBaseline(settings)
+	case model.DatabaseMySQL:
+		content = mysqlBaseline(server.Flavor, settings)
+	default:
+		return fmt.Errorf("unsupported database server: %s", server.Name)
+	}
+
+	if _, err := r.fs.Stat(server.ConfigPath); err == nil && !r.isManaged(server.ConfigPath) {
+		return fmt.Errorf("%s was not created by hardn; remove it first", server.ConfigPath)
+	}
+
+	if err := r.fs.MkdirAll(filepath.Dir(server.ConfigPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(server.ConfigPath), err)
+	}
+
+	if err := r.fs.WriteFile(server.ConfigPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", server.ConfigPath, err)
+	}
+
+	return nil
+}
+
+// RemoveDatabaseBaseline removes the managed configuration drop-in
+func (r *OSDatabaseRepository) RemoveDatabaseBaseline(server model.DatabaseServer) error {
+	if !r.isManaged(server.ConfigPath) {
+		return nil
+	}
+	if err := r.fs.Remove(server.ConfigPath); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", server.ConfigPath, err)
+	}
+	return nil
+}
+
+// ValidateDatabaseConfig checks the configuration without restarting
+func (r *OSDatabaseRepository) ValidateDatabaseConfig(server model.DatabaseServer) (string, error) {
+	switch server.Name {
+	case model.DatabasePostgreSQL:
+		// The running cluster re-reads its files for pg_file_settings
+		output, err := r.commander.Execute("runuser", "-u", "postgres", "--",
+			"psql", "-p", strconv.Itoa(server.Port), "-XAtc", postgresSettingErrors)
+		if err != nil {
+			return string(output), err
+		}
+		if strings.TrimSpace(string(output)) != "" {
+			return string(output), fmt.Errorf("invalid settings")
+		}
+		return "", nil
+
+	case model.DatabaseMySQL:
+		// Option parsing fails on unknown or malformed settings
+		output, err := r.commander.Execute(r.mysqlBinary(), "--verbose", "--help")
+		if err != nil {
+			return string(output), err
+		}
+		return "", nil
+	}
+
+	return "", fmt.Errorf("unsupported database server: %s", server.Name)
+}
+
+// RestartDatabase restarts the server so listen settings take effect
+func (r *OSDatabaseRepository) RestartDatabase(server model.DatabaseServer) error {
+	switch server.Name {
+	case model.DatabasePostgreSQL:
+		version, cluster, _ := strings.Cut(server.Instance, "/")
+		if output, err := r.commander.Execute("pg_ctlcluster", version, cluster, "restart"); err != nil {
+			return fmt.Errorf("%w\nOutput: %s", err, string(output))
+		}
+		return nil
+	case model.DatabaseMySQL:
+		name := server.Flavor
+		if r.osType == "alpine" {
+			name = "mariadb"
+		} else if model.IsRHELFamily(r.osType) && server.Flavor == "mysql" {
+			// MySQL's unit is mysqld on the RHEL family
+			name = "mysqld"
+		}
+		return r.services.Restart(name)
+	}
+	return fmt.Errorf("unsupported database server: %s", server.Name)
+}
+
+// ParsePgLsclusters reads `pg_lsclusters --no-header` output:
+// version, cluster, port, status, owner, data directory, log file
+func ParsePgLsclusters(output []byte) []model.DatabaseServer {
+	var servers []model.DatabaseServer
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		port, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+		servers = append(servers, model.DatabaseServer{
+			Name:       model.DatabasePostgreSQL,
+			Instance:   fields[0] + "/" + fields[1],
+			Port:       port,
+			ConfigPath: filepath.Join("/etc/postgresql", fields[0], fields[1], "conf.d", postgresDropInName),
+		})
+	}
+	return servers
+}
+
+// postgresBaseline renders the PostgreSQL drop-in
+func postgresBaseline(settings model.DatabaseHardening) string {
+	return model.DatabaseBaselineMarker + `
+listen_addresses = '` + strings.Join(settings.BindAddresses, ",") + `'
+password_encryption = 'scram-sha-256'
+log_connections = on
+log_disconnections = on
+log_line_prefix = '%m [%p] %q%u@%d from %h '
+`
+}
+
+// mysqlBaseline renders the MySQL or MariaDB drop-in; password plugin
+// settings use the loose- prefix so a missing plugin only warns
+func mysqlBaseline(flavor string, settings model.DatabaseHardening) string {
+	content := model.DatabaseBaselineMarker + `
+[mysqld]
+bind-address = ` + strings.Join(settings.BindAddresses, ",") + `
+local-infile = 0
+`
+	if flavor == "mariadb" {
+		return content + `log_warnings = 2
+plugin-load-add = simple_password_check
+loose-simple_password_check_minimal_length = 12
+`
+	}
+	return content + `log_error_verbosity = 3
+plugin-load-add = validate_password.so
+loose-validate_password_policy = MEDIUM
+loose-validate_password_length = 12
+`
+}
+
+// mysqlBinary returns the MariaDB or MySQL server binary, if installed
+func (r *OSDatabaseRepository) mysqlBinary() string {
+	for _, binary := range []string{"mariadbd", "mysqld"} {
+		if _, err := r.commander.Execute("which", binary); err == nil {
+			return binary
+		}
+	}
+	return ""
+}
+
+// mysqlConfigDir returns the drop-in directory read last by the server
+func (r *OSDatabaseRepository) mysqlConfigDir() string {
+	for _, dir := range mysqlConfigDirs {
+		if _, err := r.fs.Stat(dir); err == nil {
+			return dir
+		}
+	}
+	return mysqlConfigDirs[len(mysqlConfigDirs)-1]
+}
+
+// isManaged checks whether path is a drop-in written by hardn
+func (r *OSDatabaseRepository) isManaged(path string) bool {
+	data, err := r.fs.ReadFile(path)
+	return err == nil && strings.HasPrefix(string(data), model.DatabaseBaselineMarker)
+}
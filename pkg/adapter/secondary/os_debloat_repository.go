@@ -0,0 +1,158 @@
+// pkg/adapter/secondary/os_debloat_repository.go
+package secondary
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// OSDebloatRepository implements DebloatRepository with the package
+// manager and the init system
+type OSDebloatRepository struct {
+	commander interfaces.Commander
+	services  secondary.ServiceManager
+	osType    string
+}
+
+// NewOSDebloatRepository creates a new OSDebloatRepository
+func NewOSDebloatRepository(
+	commander interfaces.Commander,
+	osType string,
+) secondary.DebloatRepository {
+	return &OSDebloatRepository{
+		commander: commander,
+		services:  NewServiceManager(commander, osType),
+		osType:    osType,
+	}
+}
+
+// InstalledPackages returns the packages of names that are installed
+func (r *OSDebloatRepository) InstalledPackages(names []string) ([]string, error) {
+	var installed []string
+	for _, name := range names {
+		switch {
+		case r.osType == "alpine":
+			if _, err := r.commander.Execute("apk", "info", "-e", name); err == nil {
+				installed = append(installed, name)
+			}
+		case model.IsRHELFamily(r.osType):
+			if _, err := r.commander.Execute("rpm", "-q", name); err == nil {
+				installed = append(installed, name)
+			}
+		default:
+			// dpkg-query also lists packages removed with their configuration kept
+			output, err := r.commander.Execute("dpkg-query", "-W", "-f="+DpkgStatusFormat, name)
+			if err == nil && ParseDpkgInstalled(output) {
+				installed = append(installed, name)
+			}
+		}
+	}
+	return installed, nil
+}
+
+// RunningServices returns the services of names that are running or
+// enabled at boot
+func (r *OSDebloatRepository) RunningServices(names []string) ([]string, error) {
+	var running []string
+	for _, name := range names {
+		status, err := r.services.Status(name)
+		if err != nil {
+			return nil, err
+		}
+		if status.Active || status.Enabled {
+			running = append(running, name)
+		}
+	}
+	return running, nil
+}
+
+// RemovalDependents asks the package manager what removing packages would
+// take along: apt-get simulates the removal and rpm tests it. apk del never
+// removes a package others depend on, so Alpine has no dependents.
+func (r *OSDebloatRepository) RemovalDependents(packages []string) ([]string, error) {
+	requested := make(map[string]bool)
+	for _, name := range packages {
+		requested[name] = true
+	}
+	dependents := make(map[string]bool)
+
+	switch {
+	case r.osType == "alpine":
+		return nil, nil
+
+	case model.IsRHELFamily(r.osType):
+		// rpm -e --test fails listing "X is needed by (installed) NEVRA"
+		output, err := r.commander.Execute("rpm", append([]string{"-e", "--test"}, packages...)...)
+		if err == nil {
+			return nil, nil
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			_, nevra, ok := strings.Cut(line, "is needed by (installed) ")
+			if !ok {
+				continue
+			}
+			name, _, parsed := splitNEVRA(strings.TrimSpace(nevra))
+			if !parsed {
+				name = strings.TrimSpace(nevra)
+			}
+			if !requested[name] {
+				dependents[name] = true
+			}
+		}
+		if len(dependents) == 0 {
+			return nil, fmt.Errorf("rpm cannot remove %s: %w\nOutput: %s", strings.Join(packages, " "), err, string(output))
+		}
+
+	default:
+		// apt-get -s prints "Remv name [version]" for each package removed
+		output, err := r.commander.Execute("apt-get", append([]string{"-s", "remove"}, packages...)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to simulate removing %s: %w\nOutput: %s", strings.Join(packages, " "), err, string(output))
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) < 2 || fields[0] != "Remv" {
+				continue
+			}
+			name, _, _ := strings.Cut(fields[1], ":")
+			if !requested[name] {
+				dependents[name] = true
+			}
+		}
+	}
+
+	var names []string
+	for name := range dependents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// RemovePackages removes packages, keeping their configuration files
+func (r *OSDebloatRepository) RemovePackages(packages []string) error {
+	var output []byte
+	var err error
+	switch {
+	case r.osType == "alpine":
+		output, err = r.commander.Execute("apk", append([]string{"del"}, packages...)...)
+	case model.IsRHELFamily(r.osType):
+		output, err = r.commander.Execute("dnf", append([]string{"remove", "-y"}, packages...)...)
+	default:
+		output, err = r.commander.Execute("apt-get", append([]string{"remove", "--yes"}, packages...)...)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove %s: %w\nOutput: %s", strings.Join(packages, " "), err, string(output))
+	}
+	return nil
+}
+
+// DisableService stops a service and keeps it from starting at boot
+func (r *OSDebloatRepository) DisableService(name string) error {
+	return r.services.Disable(name)
+}
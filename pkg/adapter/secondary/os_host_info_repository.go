@@ -90,6 +90,11 @@ func (r *OSHostInfoRepository) GetHostInfo() (*model.HostInfo, error) {
 		info.KernelInfo = kernel
 	}
 
+	machine, err := r.getMachine()
+	if err == nil {
+		info.Arch = machine
+	}
+
 	// Get additional information
 	cpuInfo, err := r.getCPUInfo()
 	if err == nil {
@@ -354,6 +359,15 @@ func (r *OSHostInfoRepository) getKernelInfo() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// getMachine retrieves the hardware architecture name
+func (r *OSHostInfoRepository) getMachine() (string, error) {
+	output, err := r.commander.Execute("uname", "-m")
+	if err != nil {
+		return "", fmt.Errorf("failed to get machine architecture: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 // getCPUInfo retrieves CPU information
 func (r *OSHostInfoRepository) getCPUInfo() (string, error) {
 	// Try to read /proc/cpuinfo
@@ -0,0 +1,478 @@
+// pkg/adapter/secondary/os_log_shipping_repository.go
+package secondary
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// logShippingMarker identifies the forwarding configuration written by hardn
+const logShippingMarker = "# Managed by hardn"
+
+// logShippingDialTimeout bounds the connection check
+const logShippingDialTimeout = 10 * time.Second
+
+// Copies of the certificates in LogShippingCertDir
+var (
+	logShippingCAPath   = filepath.Join(model.LogShippingCertDir, "ca.pem")
+	logShippingCertPath = filepath.Join(model.LogShippingCertDir, "client.pem")
+	logShippingKeyPath  = filepath.Join(model.LogShippingCertDir, "client.key")
+)
+
+// rsyslogTargetPattern and rsyslogPortPattern read the collector back from
+// the omfwd action
+var (
+	rsyslogTargetPattern = regexp.MustCompile(`(?m)^\s*target="([^"]+)"`)
+	rsyslogPortPattern   = regexp.MustCompile(`(?m)^\s*port="([0-9]+)"`)
+)
+
+// logShippingServices are the services forwarding the logs of each method
+var logShippingServices = map[string]string{
+	model.LogShippingRsyslog:  "rsyslog",
+	model.LogShippingJournald: "systemd-journal-upload",
+}
+
+// OSLogShippingRepository implements LogShippingRepository with rsyslog
+// and systemd-journal-upload
+type OSLogShippingRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	services  secondary.ServiceManager
+	osType    string
+}
+
+// NewOSLogShippingRepository creates a new OSLogShippingRepository
+func NewOSLogShippingRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	osType string,
+) secondary.LogShippingRepository {
+	return &OSLogShippingRepository{
+		fs:        fs,
+		commander: commander,
+		services:  NewServiceManager(commander, osType),
+		osType:    osType,
+	}
+}
+
+// logShippingCertificates are the PEM files of a policy
+type logShippingCertificates struct {
+	ca, cert, key []byte
+}
+
+// readCertificates reads and checks the certificates of the policy
+func (r *OSLogShippingRepository) readCertificates(policy model.LogShippingPolicy) (*logShippingCertificates, *tls.Config, error) {
+	certs := &logShippingCertificates{}
+	read := func(path string) ([]byte, error) {
+		if path == "" {
+			return nil, nil
+		}
+		data, err := r.fs.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return data, nil
+	}
+
+	var err error
+	if certs.ca, err = read(policy.CAFile); err != nil {
+		return nil, nil, err
+	}
+	if certs.cert, err = read(policy.CertFile); err != nil {
+		return nil, nil, err
+	}
+	if certs.key, err = read(policy.KeyFile); err != nil {
+		return nil, nil, err
+	}
+
+	config, err := LogShippingTLSConfig(policy.Host, certs.ca, certs.cert, certs.key, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	return certs, config, nil
+}
+
+// LogShippingTLSConfig checks the PEM certificates of a policy at now and
+// returns the TLS configuration that trusts the CA for host and presents
+// the client certificate, if there is one
+func LogShippingTLSConfig(host string, ca, cert, key []byte, now time.Time) (*tls.Config, error) {
+	pool := x509.NewCertPool()
+	found := 0
+	for rest := ca; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		parsed, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the CA certificate: %w", err)
+		}
+		if now.After(parsed.NotAfter) {
+			return nil, fmt.Errorf("the CA certificate %s expired on %s", parsed.Subject.CommonName, parsed.NotAfter.Format("2006-01-02"))
+		}
+		pool.AddCert(parsed)
+		found++
+	}
+	if found == 0 {
+		return nil, fmt.Errorf("no CA certificate found")
+	}
+
+	config := &tls.Config{
+		ServerName: host,
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+	if len(cert) == 0 && len(key) == 0 {
+		return config, nil
+	}
+
+	pair, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate or key: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the client certificate: %w", err)
+	}
+	switch {
+	case now.After(leaf.NotAfter):
+		return nil, fmt.Errorf("the client certificate expired on %s", leaf.NotAfter.Format("2006-01-02"))
+	case now.Before(leaf.NotBefore):
+		return nil, fmt.Errorf("the client certificate is not valid before %s", leaf.NotBefore.Format("2006-01-02"))
+	}
+	config.Certificates = []tls.Certificate{pair}
+	return config, nil
+}
+
+// CheckConnection completes a TLS handshake with the collector, which
+// proves it is reachable and presents a certificate the CA signed for its
+// name. With TLS 1.3 the collector judges the client certificate after
+// the handshake, so a rejected one only shows in its log.
+func (r *OSLogShippingRepository) CheckConnection(policy model.LogShippingPolicy) error {
+	_, config, err := r.readCertificates(policy)
+	if err != nil {
+		return err
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: logShippingDialTimeout},
+		Config:    config,
+	}
+	conn, err := dialer.Dial("tcp", policy.Target())
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", policy.Target(), err)
+	}
+	return conn.Close()
+}
+
+// ConfigureLogShipping installs the certificates and the forwarding daemon
+// and points it at the collector; a forwarding of the other method written
+// by an earlier run is removed
+func (r *OSLogShippingRepository) ConfigureLogShipping(policy model.LogShippingPolicy) error {
+	certs, _, err := r.readCertificates(policy)
+	if err != nil {
+		return err
+	}
+
+	switch policy.Method {
+	case model.LogShippingRsyslog:
+		if err := r.installPackages(r.rsyslogPackages()...); err != nil {
+			return err
+		}
+	case model.LogShippingJournald:
+		if r.osType == "alpine" {
+			return fmt.Errorf("systemd-journal-upload needs systemd, which Alpine Linux does not run")
+		}
+		if err := r.installPackages("systemd-journal-remote"); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown log shipping method %q", policy.Method)
+	}
+
+	if err := r.installCertificates(policy.Method, certs); err != nil {
+		return err
+	}
+
+	if policy.Method == model.LogShippingRsyslog {
+		if err := r.writeRsyslogForward(policy, len(certs.cert) > 0); err != nil {
+			return err
+		}
+		if err := r.removeJournalUpload(); err != nil {
+			return err
+		}
+		// Busybox syslogd would keep the local messages from rsyslog
+		if r.osType == "alpine" {
+			if status, err := r.services.Status("syslog"); err == nil && (status.Active || status.Enabled) {
+				if err := r.services.Disable("syslog"); err != nil {
+					return fmt.Errorf("failed to stop busybox syslogd: %w", err)
+				}
+			}
+		}
+	} else {
+		if err := r.fs.MkdirAll(filepath.Dir(model.JournalUploadDropInPath), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(model.JournalUploadDropInPath), err)
+		}
+		drop := []byte(JournalUploadDropIn(policy.Target()))
+		if err := r.fs.WriteFile(model.JournalUploadDropInPath, drop, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", model.JournalUploadDropInPath, err)
+		}
+		if err := r.removeRsyslogForward(); err != nil {
+			return err
+		}
+	}
+
+	service := logShippingServices[policy.Method]
+	if err := r.services.Enable(service); err != nil {
+		return fmt.Errorf("failed to enable %s: %w", service, err)
+	}
+	if err := r.services.Restart(service); err != nil {
+		return fmt.Errorf("failed to restart %s: %w", service, err)
+	}
+	return nil
+}
+
+// rsyslogPackages returns rsyslog and the package of its TLS driver
+func (r *OSLogShippingRepository) rsyslogPackages() []string {
+	if r.osType == "alpine" {
+		return []string{"rsyslog", "rsyslog-tls"}
+	}
+	return []string{"rsyslog", "rsyslog-gnutls"}
+}
+
+// installPackages installs the packages that are not installed
+func (r *OSLogShippingRepository) installPackages(names ...string) error {
+	for _, name := range names {
+		var output []byte
+		var err error
+		switch {
+		case r.osType == "alpine":
+			if _, err := r.commander.Execute("apk", "info", "-e", name); err == nil {
+				continue
+			}
+			output, err = r.commander.Execute("apk", "add", name)
+		case model.IsRHELFamily(r.osType):
+			if _, err := r.commander.Execute("rpm", "-q", name); err == nil {
+				continue
+			}
+			output, err = r.commander.Execute("dnf", "install", "-y", name)
+		default:
+			if status, _ := r.commander.Execute("dpkg-query", "-W", "-f="+DpkgStatusFormat, name); ParseDpkgInstalled(status) {
+				continue
+			}
+			output, err = r.commander.Execute("apt-get", "install", "-y", name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to install %s: %w\nOutput: %s", name, err, string(output))
+		}
+	}
+	return nil
+}
+
+// keyGroup returns the group that must read the client key: rsyslog runs
+// as syslog on Ubuntu, and systemd-journal-upload gets systemd-journal as a
+// supplementary group. Elsewhere rsyslog runs as root.
+func (r *OSLogShippingRepository) keyGroup(method string) string {
+	switch {
+	case method == model.LogShippingJournald:
+		return "systemd-journal"
+	case r.osType == "ubuntu":
+		return "syslog"
+	}
+	return ""
+}
+
+// installCertificates copies the certificates to LogShippingCertDir, with
+// the key readable by the forwarding daemon only
+func (r *OSLogShippingRepository) installCertificates(method string, certs *logShippingCertificates) error {
+	if err := r.fs.MkdirAll(model.LogShippingCertDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", model.LogShippingCertDir, err)
+	}
+	if err := r.fs.WriteFile(logShippingCAPath, certs.ca, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", logShippingCAPath, err)
+	}
+	if len(certs.cert) == 0 {
+		return nil
+	}
+
+	if err := r.fs.WriteFile(logShippingCertPath, certs.cert, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", logShippingCertPath, err)
+	}
+	group := r.keyGroup(method)
+	mode := os.FileMode(0600)
+	if group != "" {
+		mode = 0640
+	}
+	if err := r.fs.WriteFile(logShippingKeyPath, certs.key, mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", logShippingKeyPath, err)
+	}
+	if group != "" {
+		if output, err := r.commander.Execute("chgrp", group, logShippingKeyPath); err != nil {
+			return fmt.Errorf("failed to give %s the client key: %w\nOutput: %s", group, err, string(output))
+		}
+	}
+	return nil
+}
+
+// writeRsyslogForward writes the omfwd action and keeps it only if
+// rsyslogd accepts the configuration
+func (r *OSLogShippingRepository) writeRsyslogForward(policy model.LogShippingPolicy, clientCert bool) error {
+	previous, readErr := r.fs.ReadFile(model.RsyslogForwardConfigPath)
+
+	conf := RsyslogForwardConfig(policy.Host, policy.Port, clientCert)
+	if err := r.fs.WriteFile(model.RsyslogForwardConfigPath, []byte(conf), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", model.RsyslogForwardConfigPath, err)
+	}
+
+	output, err := r.commander.Execute("rsyslogd", "-N1")
+	if err == nil {
+		return nil
+	}
+	if readErr == nil {
+		_ = r.fs.WriteFile(model.RsyslogForwardConfigPath, previous, 0644)
+	} else {
+		_ = r.fs.Remove(model.RsyslogForwardConfigPath)
+	}
+	return fmt.Errorf("rsyslogd rejected the forwarding configuration: %w\nOutput: %s", err, string(output))
+}
+
+// removeRsyslogForward removes a forwarding written by hardn and restarts
+// rsyslog without it
+func (r *OSLogShippingRepository) removeRsyslogForward() error {
+	data, err := r.fs.ReadFile(model.RsyslogForwardConfigPath)
+	if err != nil || !strings.HasPrefix(string(data), logShippingMarker) {
+		return nil
+	}
+	if err := r.fs.Remove(model.RsyslogForwardConfigPath); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", model.RsyslogForwardConfigPath, err)
+	}
+	if err := r.services.Restart("rsyslog"); err != nil {
+		return fmt.Errorf("failed to restart rsyslog: %w", err)
+	}
+	return nil
+}
+
+// removeJournalUpload removes an upload written by hardn and stops
+// systemd-journal-upload
+func (r *OSLogShippingRepository) removeJournalUpload() error {
+	data, err := r.fs.ReadFile(model.JournalUploadDropInPath)
+	if err != nil || !strings.HasPrefix(string(data), logShippingMarker) {
+		return nil
+	}
+	if err := r.fs.Remove(model.JournalUploadDropInPath); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", model.JournalUploadDropInPath, err)
+	}
+	if err := r.services.Disable("systemd-journal-upload"); err != nil {
+		return fmt.Errorf("failed to stop systemd-journal-upload: %w", err)
+	}
+	return nil
+}
+
+// RsyslogForwardConfig returns the rsyslog configuration that forwards
+// every message to the collector over TLS, checking that its certificate
+// names host, and presenting the client certificate if there is one.
+// Messages are queued on disk while the collector is unreachable.
+func RsyslogForwardConfig(host string, port int, clientCert bool) string {
+	var b strings.Builder
+	b.WriteString(logShippingMarker + "\n")
+	b.WriteString("global(\n")
+	b.WriteString("  DefaultNetstreamDriver=\"gtls\"\n")
+	fmt.Fprintf(&b, "  DefaultNetstreamDriverCAFile=%q\n", logShippingCAPath)
+	if clientCert {
+		fmt.Fprintf(&b, "  DefaultNetstreamDriverCertFile=%q\n", logShippingCertPath)
+		fmt.Fprintf(&b, "  DefaultNetstreamDriverKeyFile=%q\n", logShippingKeyPath)
+	}
+	b.WriteString(")\n\n")
+	b.WriteString("*.* action(\n")
+	b.WriteString("  type=\"omfwd\"\n")
+	fmt.Fprintf(&b, "  target=%q\n", host)
+	fmt.Fprintf(&b, "  port=\"%d\"\n", port)
+	b.WriteString("  protocol=\"tcp\"\n")
+	b.WriteString("  StreamDriver=\"gtls\"\n")
+	b.WriteString("  StreamDriverMode=\"1\"\n")
+	b.WriteString("  StreamDriverAuthMode=\"x509/name\"\n")
+	fmt.Fprintf(&b, "  StreamDriverPermittedPeers=%q\n", host)
+	b.WriteString("  queue.type=\"LinkedList\"\n")
+	b.WriteString("  queue.filename=\"hardn_forward\"\n")
+	b.WriteString("  queue.maxDiskSpace=\"1g\"\n")
+	b.WriteString("  queue.saveOnShutdown=\"on\"\n")
+	b.WriteString("  action.resumeRetryCount=\"-1\"\n")
+	b.WriteString(")\n")
+	return b.String()
+}
+
+// ParseRsyslogForward returns the collector of an omfwd action as host:port
+func ParseRsyslogForward(conf string) string {
+	target := rsyslogTargetPattern.FindStringSubmatch(conf)
+	port := rsyslogPortPattern.FindStringSubmatch(conf)
+	if target == nil || port == nil {
+		return ""
+	}
+	return net.JoinHostPort(target[1], port[1])
+}
+
+// JournalUploadDropIn returns the journal-upload.conf drop-in that uploads
+// the journal to the collector at target over HTTPS
+func JournalUploadDropIn(target string) string {
+	return logShippingMarker + "\n[Upload]\n" +
+		"URL=https://" + target + "\n" +
+		"ServerKeyFile=" + logShippingKeyPath + "\n" +
+		"ServerCertificateFile=" + logShippingCertPath + "\n" +
+		"TrustedCertificateFile=" + logShippingCAPath + "\n"
+}
+
+// ParseJournalUploadTarget returns the collector of a journal-upload.conf
+// drop-in as host:port
+func ParseJournalUploadTarget(conf string) string {
+	for _, line := range strings.Split(conf, "\n") {
+		if url, found := strings.CutPrefix(strings.TrimSpace(line), "URL="); found {
+			target := strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://")
+			return strings.TrimSuffix(target, "/")
+		}
+	}
+	return ""
+}
+
+// GetLogShippingStatus reports the forwarding hardn configured, whether
+// its daemon runs and whether it holds a connection to the collector
+func (r *OSLogShippingRepository) GetLogShippingStatus() (*model.LogShippingStatus, error) {
+	status := &model.LogShippingStatus{}
+	if data, err := r.fs.ReadFile(model.RsyslogForwardConfigPath); err == nil && strings.HasPrefix(string(data), logShippingMarker) {
+		status.Method = model.LogShippingRsyslog
+		status.Target = ParseRsyslogForward(string(data))
+	} else if data, err := r.fs.ReadFile(model.JournalUploadDropInPath); err == nil && strings.HasPrefix(string(data), logShippingMarker) {
+		status.Method = model.LogShippingJournald
+		status.Target = ParseJournalUploadTarget(string(data))
+	} else {
+		return status, nil
+	}
+
+	if service, err := r.services.Status(logShippingServices[status.Method]); err == nil {
+		status.Active = service.Active
+	}
+
+	if _, port, err := net.SplitHostPort(status.Target); err == nil && status.Active {
+		if _, err := strconv.Atoi(port); err == nil {
+			output, err := r.commander.Execute("ss", "-Htn", "state", "established", "dport", "=", ":"+port)
+			status.Connected = err == nil && strings.TrimSpace(string(output)) != ""
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,60 @@
+// pkg/adapter/secondary/os_lynis_repository.go
+package secondary
+
+import (
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// OSLynisRepository implements LynisRepository using the lynis command
+type OSLynisRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+}
+
+// NewOSLynisRepository creates a new OSLynisRepository
+func NewOSLynisRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+) secondary.LynisRepository {
+	return &OSLynisRepository{
+		fs:        fs,
+		commander: commander,
+	}
+}
+
+// IsInstalled reports whether the lynis command is available
+func (r *OSLynisRepository) IsInstalled() bool {
+	_, err := r.commander.Execute("which", "lynis")
+	return err == nil
+}
+
+// RunAudit runs a non-interactive scan of the system. Lynis exits with a
+// non-zero status when it finds warnings, so only a missing report counts
+// as a failure.
+func (r *OSLynisRepository) RunAudit() error {
+	// A report left by an earlier scan must not pass for this one
+	_ = r.fs.Remove(model.LynisReportPath)
+
+	output, err := r.commander.Execute("lynis", "audit", "system",
+		"--quiet", "--no-colors", "--report-file", model.LynisReportPath)
+	if _, statErr := r.fs.Stat(model.LynisReportPath); err != nil && statErr != nil {
+		return fmt.Errorf("failed to run Lynis audit: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// ReadReport returns the report of the last scan, or nil when there is none
+func (r *OSLynisRepository) ReadReport() ([]byte, error) {
+	if _, err := r.fs.Stat(model.LynisReportPath); err != nil {
+		return nil, nil
+	}
+	data, err := r.fs.ReadFile(model.LynisReportPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", model.LynisReportPath, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,345 @@
+// pkg/adapter/secondary/os_mac_repository.go
+package secondary
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+const (
+	appArmorEnabledPath = "/sys/module/apparmor/parameters/enabled"
+	appArmorProfilesDir = "/etc/apparmor.d"
+
+	// autorelabelPath makes the next boot relabel the filesystem for SELinux
+	autorelabelPath = "/.autorelabel"
+
+	sshPortType = "ssh_port_t"
+)
+
+// macPackages are the packages each framework needs, by package manager
+var macPackages = map[string]map[string][]string{
+	model.MACAppArmor: {
+		"apt-get": {"apparmor", "apparmor-utils", "apparmor-profiles"},
+		"apk":     {"apparmor", "apparmor-utils", "apparmor-profiles"},
+	},
+	model.MACSELinux: {
+		"apt-get": {"selinux-basics", "selinux-policy-default", "auditd", "policycoreutils-python-utils"},
+		"dnf":     {"selinux-policy-targeted", "policycoreutils", "policycoreutils-python-utils"},
+	},
+}
+
+// OSMACRepository implements MACRepository using OS operations
+type OSMACRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	services  secondary.ServiceManager
+	osType    string
+}
+
+// NewOSMACRepository creates a new OSMACRepository
+func NewOSMACRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	osType string,
+) secondary.MACRepository {
+	return &OSMACRepository{
+		fs:        fs,
+		commander: commander,
+		services:  NewServiceManager(commander, osType),
+		osType:    osType,
+	}
+}
+
+// GetMACStatus reports the active framework. SELinux wins when the kernel
+// runs both, since AppArmor then only stacks on top of it.
+func (r *OSMACRepository) GetMACStatus() (*model.MACStatus, error) {
+	status := &model.MACStatus{Mode: model.MACModeDisabled}
+	lsms := r.activeLSMs()
+
+	switch {
+	case lsms[model.MACSELinux] || r.exists(SELinuxEnforcePath):
+		status.Framework = model.MACSELinux
+		r.selinuxStatus(status)
+	case lsms[model.MACAppArmor] || r.readTrimmed(appArmorEnabledPath) == "Y":
+		status.Framework = model.MACAppArmor
+		r.appArmorStatus(status)
+	case r.exists(SELinuxConfigPath):
+		// Installed but not running; it starts after a reboot if configured
+		status.Framework = model.MACSELinux
+		r.selinuxStatus(status)
+	}
+
+	return status, nil
+}
+
+// activeLSMs reads the security modules the kernel runs
+func (r *OSMACRepository) activeLSMs() map[string]bool {
+	lsms := make(map[string]bool)
+	for _, name := range strings.Split(r.readTrimmed(LSMListPath), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			lsms[name] = true
+		}
+	}
+	return lsms
+}
+
+// selinuxStatus fills in the SELinux mode, preferring getenforce over the
+// selinuxfs flag
+func (r *OSMACRepository) selinuxStatus(status *model.MACStatus) {
+	if output, err := r.commander.Execute("getenforce"); err == nil {
+		status.Installed = true
+		if mode := strings.ToLower(strings.TrimSpace(string(output))); mode != "" {
+			status.Mode = mode
+		}
+	} else {
+		switch r.readTrimmed(SELinuxEnforcePath) {
+		case "1":
+			status.Mode = model.MACModeEnforcing
+		case "0":
+			status.Mode = model.MACModePermissive
+		}
+	}
+
+	if r.exists(SELinuxConfigPath) {
+		data, err := r.fs.ReadFile(SELinuxConfigPath)
+		if err == nil {
+			status.ConfiguredMode, status.Policy = ParseSELinuxConfig(data)
+		}
+	}
+	status.RebootRequired = status.ConfiguredMode != "" && status.ConfiguredMode != status.Mode
+}
+
+// appArmorStatus counts the loaded profiles by mode, preferring the
+// kernel's profile list over aa-status
+func (r *OSMACRepository) appArmorStatus(status *model.MACStatus) {
+	status.Installed = r.packageInstalled("apparmor")
+
+	if r.exists(AppArmorProfilesPath) {
+		if data, err := r.fs.ReadFile(AppArmorProfilesPath); err == nil {
+			status.EnforcedProfiles, status.ComplainProfiles = ParseAppArmorProfiles(data)
+		}
+	} else if output, err := r.commander.Execute("aa-status", "--enforced"); err == nil {
+		// aa-status --enforced prints just the count
+		status.EnforcedProfiles, _ = strconv.Atoi(strings.TrimSpace(string(output)))
+	}
+
+	// On Alpine AppArmor only loads at boot from the default runlevel
+	if r.osType == "alpine" {
+		output, err := r.commander.Execute("rc-status", "default")
+		if err != nil || !strings.Contains(string(output), "apparmor") {
+			return
+		}
+	}
+
+	switch {
+	case status.EnforcedProfiles > 0:
+		status.Mode = model.MACModeEnforcing
+	case status.ComplainProfiles > 0:
+		status.Mode = model.MACModePermissive
+	}
+}
+
+// InstallMAC installs the framework's tools and policy packages
+func (r *OSMACRepository) InstallMAC(framework string) error {
+	manager := r.packageManager()
+	packages := macPackages[framework][manager]
+	if len(packages) == 0 {
+		return fmt.Errorf("%s packages are not available on %s", model.FrameworkName(framework), r.osType)
+	}
+
+	var args []string
+	switch manager {
+	case "apk":
+		args = append([]string{"add", "--no-cache"}, packages...)
+	default:
+		args = append([]string{"install", "-y"}, packages...)
+	}
+	if output, err := r.commander.Execute(manager, args...); err != nil {
+		return fmt.Errorf("failed to install %s packages: %w\nOutput: %s", model.FrameworkName(framework), err, string(output))
+	}
+	return nil
+}
+
+// EnforceMAC enforces the framework now where possible and at boot
+func (r *OSMACRepository) EnforceMAC(framework string) error {
+	switch framework {
+	case model.MACSELinux:
+		return r.enforceSELinux()
+	case model.MACAppArmor:
+		return r.enforceAppArmor()
+	}
+	return fmt.Errorf("unknown MAC framework: %s", framework)
+}
+
+// enforceSELinux sets SELINUX=enforcing for the next boot and switches a
+// permissive kernel to enforcing right away. A kernel booted without
+// SELinux is prepared for it and needs a reboot, which relabels the
+// filesystem first.
+func (r *OSMACRepository) enforceSELinux() error {
+	if err := r.writeSELinuxMode(model.MACModeEnforcing); err != nil {
+		return err
+	}
+
+	if !r.exists(SELinuxEnforcePath) {
+		// Debian and Ubuntu boot without SELinux until it is activated
+		if r.packageManager() == "apt-get" {
+			if output, err := r.commander.Execute("selinux-activate"); err != nil {
+				return fmt.Errorf("failed to activate SELinux: %w\nOutput: %s", err, string(output))
+			}
+		}
+		if err := r.fs.WriteFile(autorelabelPath, []byte{}, 0644); err != nil {
+			return fmt.Errorf("failed to schedule SELinux relabeling: %w", err)
+		}
+		return nil
+	}
+
+	if output, err := r.commander.Execute("setenforce", "1"); err != nil {
+		return fmt.Errorf("failed to set SELinux to enforcing: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// writeSELinuxMode replaces the SELINUX= line of /etc/selinux/config
+func (r *OSMACRepository) writeSELinuxMode(mode string) error {
+	var lines []string
+	if r.exists(SELinuxConfigPath) {
+		data, err := r.fs.ReadFile(SELinuxConfigPath)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", SELinuxConfigPath, err)
+		}
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	}
+
+	replaced := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "SELINUX=") {
+			lines[i] = "SELINUX=" + mode
+			replaced = true
+		}
+	}
+	if !replaced {
+		lines = append(lines, "SELINUX="+mode)
+	}
+
+	if err := r.fs.MkdirAll("/etc/selinux", 0755); err != nil {
+		return fmt.Errorf("failed to create /etc/selinux: %w", err)
+	}
+	if err := r.fs.WriteFile(SELinuxConfigPath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", SELinuxConfigPath, err)
+	}
+	return nil
+}
+
+// enforceAppArmor loads AppArmor at boot and puts its profiles in enforce
+// mode, one aa-enforce call per profile so a dry run lists each. Every
+// profile is tried; the ones that fail are reported together.
+func (r *OSMACRepository) enforceAppArmor() error {
+	if err := r.services.Enable("apparmor"); err != nil {
+		return fmt.Errorf("failed to enable AppArmor: %w", err)
+	}
+
+	profiles, err := r.appArmorProfiles()
+	if err != nil {
+		return err
+	}
+
+	var failures []error
+	for _, profile := range profiles {
+		if output, err := r.commander.Execute("aa-enforce", profile); err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w\nOutput: %s", filepath.Base(profile), err, string(output)))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to enforce %d of %d AppArmor profile(s): %w", len(failures), len(profiles), errors.Join(failures...))
+	}
+	return nil
+}
+
+// appArmorProfiles returns the profiles in the top level of
+// appArmorProfilesDir; abstractions and tunables live in subdirectories
+func (r *OSMACRepository) appArmorProfiles() ([]string, error) {
+	if _, err := r.fs.Stat(appArmorProfilesDir); err != nil {
+		return nil, nil
+	}
+	output, err := r.commander.Execute("find", appArmorProfilesDir, "-mindepth", "1", "-maxdepth", "1", "-type", "f")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", appArmorProfilesDir, err)
+	}
+
+	profiles := strings.Fields(string(output))
+	sort.Strings(profiles)
+	return profiles, nil
+}
+
+// LabelSSHPort adds port to the ports sshd may bind under SELinux
+func (r *OSMACRepository) LabelSSHPort(port int) error {
+	output, err := r.commander.Execute("semanage", "port", "-l", "-n")
+	if err != nil {
+		return fmt.Errorf("failed to list SELinux port labels: %w", err)
+	}
+	for _, labeled := range ParseSELinuxPorts(output, sshPortType) {
+		if labeled == strconv.Itoa(port) {
+			return nil
+		}
+	}
+
+	// -a fails when another type already owns the port; -m relabels it
+	args := []string{"-t", sshPortType, "-p", "tcp", strconv.Itoa(port)}
+	if _, err := r.commander.Execute("semanage", append([]string{"port", "-a"}, args...)...); err != nil {
+		if output, err := r.commander.Execute("semanage", append([]string{"port", "-m"}, args...)...); err != nil {
+			return fmt.Errorf("failed to label port %d for sshd: %w\nOutput: %s", port, err, string(output))
+		}
+	}
+	return nil
+}
+
+// packageManager returns the package manager for the distribution
+func (r *OSMACRepository) packageManager() string {
+	switch r.osType {
+	case "alpine":
+		return "apk"
+	case "debian", "ubuntu":
+		return "apt-get"
+	}
+	if model.IsRHELFamily(r.osType) {
+		return "dnf"
+	}
+	if _, err := r.commander.Execute("which", "dnf"); err == nil {
+		return "dnf"
+	}
+	return ""
+}
+
+// packageInstalled reports whether a package is installed
+func (r *OSMACRepository) packageInstalled(name string) bool {
+	if r.osType == "alpine" {
+		_, err := r.commander.Execute("apk", "info", "-e", name)
+		return err == nil
+	}
+	output, err := r.commander.Execute("dpkg-query", "-W", "-f="+DpkgStatusFormat, name)
+	return err == nil && ParseDpkgInstalled(output)
+}
+
+func (r *OSMACRepository) exists(path string) bool {
+	_, err := r.fs.Stat(path)
+	return err == nil
+}
+
+func (r *OSMACRepository) readTrimmed(path string) string {
+	if !r.exists(path) {
+		return ""
+	}
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
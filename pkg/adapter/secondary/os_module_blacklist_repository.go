@@ -0,0 +1,126 @@
+// pkg/adapter/secondary/os_module_blacklist_repository.go
+package secondary
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// snapdPath is the snapd daemon; its snaps are squashfs images
+const snapdPath = "/usr/lib/snapd/snapd"
+
+// OSModuleBlacklistRepository implements ModuleBlacklistRepository with a
+// modprobe.d file
+type OSModuleBlacklistRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	osType    string
+}
+
+// NewOSModuleBlacklistRepository creates a new OSModuleBlacklistRepository
+func NewOSModuleBlacklistRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	osType string,
+) secondary.ModuleBlacklistRepository {
+	return &OSModuleBlacklistRepository{
+		fs:        fs,
+		commander: commander,
+		osType:    osType,
+	}
+}
+
+// WriteBlacklist writes an install rule, which also stops explicit
+// modprobe calls, and a blacklist rule, which stops loading by alias, for
+// each module. An empty list removes the file.
+func (r *OSModuleBlacklistRepository) WriteBlacklist(modules []string) error {
+	if len(modules) == 0 {
+		if _, err := r.fs.Stat(model.ModuleBlacklistPath); err != nil {
+			return nil
+		}
+		if err := r.fs.Remove(model.ModuleBlacklistPath); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", model.ModuleBlacklistPath, err)
+		}
+		return nil
+	}
+
+	var content strings.Builder
+	content.WriteString("# Managed by hardn: kernel modules kept from loading\n")
+	for _, module := range modules {
+		fmt.Fprintf(&content, "install %s /bin/false\nblacklist %s\n", module, module)
+	}
+
+	if err := r.fs.MkdirAll(filepath.Dir(model.ModuleBlacklistPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(model.ModuleBlacklistPath), err)
+	}
+	if err := r.fs.WriteFile(model.ModuleBlacklistPath, []byte(content.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", model.ModuleBlacklistPath, err)
+	}
+	return nil
+}
+
+// GetDisabledModules reads the modprobe configuration of every modprobe.d
+// file. Without kmod's modprobe, only hardn's file is read.
+func (r *OSModuleBlacklistRepository) GetDisabledModules() ([]string, error) {
+	output, err := r.commander.Execute("modprobe", "--showconfig")
+	if err != nil {
+		data, readErr := r.fs.ReadFile(model.ModuleBlacklistPath)
+		if readErr != nil {
+			return nil, nil
+		}
+		output = data
+	}
+	return ParseDisabledModules(string(output)), nil
+}
+
+// GetLoadedModules reads the loaded modules from /proc/modules
+func (r *OSModuleBlacklistRepository) GetLoadedModules() ([]string, error) {
+	data, err := r.fs.ReadFile(model.ProcModulesPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", model.ProcModulesPath, err)
+	}
+
+	var loaded []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			loaded = append(loaded, fields[0])
+		}
+	}
+	return loaded, nil
+}
+
+// SnapdInstalled reports whether the snapd daemon is installed
+func (r *OSModuleBlacklistRepository) SnapdInstalled() bool {
+	_, err := r.fs.Stat(snapdPath)
+	return err == nil
+}
+
+// ParseDisabledModules returns the modules that modprobe configuration
+// replaces with a command that loads nothing. A blacklist rule alone is
+// not enough: it only stops loading by alias, not "modprobe NAME".
+func ParseDisabledModules(config string) []string {
+	var disabled []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(config, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[0] != "install" {
+			continue
+		}
+		switch filepath.Base(fields[2]) {
+		case "false", "true":
+		default:
+			continue
+		}
+		module := model.NormalizeModuleName(fields[1])
+		if !seen[module] {
+			seen[module] = true
+			disabled = append(disabled, module)
+		}
+	}
+	return disabled
+}
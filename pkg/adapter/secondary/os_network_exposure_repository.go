@@ -0,0 +1,235 @@
+// pkg/adapter/secondary/os_network_exposure_repository.go
+package secondary
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// ssProcessPattern matches the first ("name",pid=N,fd=M) entry of an ss
+// users:(...) column
+var ssProcessPattern = regexp.MustCompile(`\("([^"]*)",pid=(\d+)`)
+
+// OSNetworkExposureRepository implements NetworkExposureRepository with ss,
+// falling back to netstat, and the distribution's package database
+type OSNetworkExposureRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	osType    string
+}
+
+// NewOSNetworkExposureRepository creates a new OSNetworkExposureRepository
+func NewOSNetworkExposureRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	osType string,
+) secondary.NetworkExposureRepository {
+	return &OSNetworkExposureRepository{
+		fs:        fs,
+		commander: commander,
+		osType:    osType,
+	}
+}
+
+// GetListeningSockets lists listening TCP and UDP sockets with the owning
+// process and package where they can be found
+func (r *OSNetworkExposureRepository) GetListeningSockets() ([]model.ListeningSocket, error) {
+	var sockets []model.ListeningSocket
+	if output, err := r.commander.Execute("ss", "-H", "-tulpn"); err == nil {
+		sockets = ParseSSListening(output)
+	} else if output, err := r.commander.Execute("netstat", "-tulpn"); err == nil {
+		sockets = ParseNetstatListening(output)
+	} else {
+		return nil, fmt.Errorf("failed to list listening sockets: neither ss nor netstat is available")
+	}
+
+	// Look each process up once; sshd and web servers hold several sockets
+	executables := make(map[int]string)
+	packages := make(map[string]string)
+	for i := range sockets {
+		pid := sockets[i].PID
+		if pid == 0 {
+			continue
+		}
+
+		executable, seen := executables[pid]
+		if !seen {
+			executable = r.executable(pid)
+			executables[pid] = executable
+		}
+		if executable == "" {
+			continue
+		}
+		sockets[i].Executable = executable
+
+		pkg, seen := packages[executable]
+		if !seen {
+			pkg = r.owningPackage(executable)
+			packages[executable] = pkg
+		}
+		sockets[i].Package = pkg
+	}
+
+	return sockets, nil
+}
+
+// executable resolves the program a process runs
+func (r *OSNetworkExposureRepository) executable(pid int) string {
+	output, err := r.commander.Execute("readlink", "-f", fmt.Sprintf("/proc/%d/exe", pid))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// owningPackage asks the package database which package installed path
+func (r *OSNetworkExposureRepository) owningPackage(path string) string {
+	switch {
+	case r.osType == "alpine":
+		output, err := r.commander.Execute("apk", "info", "--who-owns", path)
+		if err != nil {
+			return ""
+		}
+		return ParseApkWhoOwns(output)
+	case model.IsRHELFamily(r.osType):
+		output, err := r.commander.Execute("rpm", "-qf", "--queryformat", "%{NAME}\n", path)
+		if err != nil {
+			return ""
+		}
+		return firstLine(output)
+	default:
+		output, err := r.commander.Execute("dpkg-query", "-S", path)
+		if err != nil {
+			return ""
+		}
+		return ParseDpkgSearch(output)
+	}
+}
+
+// ParseSSListening parses `ss -H -tulpn` lines of the form
+//
+//	tcp LISTEN 0 128 0.0.0.0:22 0.0.0.0:* users:(("sshd",pid=812,fd=3))
+func ParseSSListening(output []byte) []model.ListeningSocket {
+	var sockets []model.ListeningSocket
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			continue
+		}
+
+		protocol := strings.ToLower(fields[0])
+		if protocol != "tcp" && protocol != "udp" {
+			continue
+		}
+		address, port, ok := splitListenAddress(fields[4])
+		if !ok {
+			continue
+		}
+
+		socket := model.ListeningSocket{Protocol: protocol, Address: address, Port: port}
+		if match := ssProcessPattern.FindStringSubmatch(line); match != nil {
+			socket.Process = match[1]
+			socket.PID, _ = strconv.Atoi(match[2])
+		}
+		sockets = append(sockets, socket)
+	}
+	return sockets
+}
+
+// ParseNetstatListening parses `netstat -tulpn` lines of the form
+//
+//	tcp6  0  0 :::22  :::*  LISTEN  812/sshd
+//
+// UDP lines have no state column.
+func ParseNetstatListening(output []byte) []model.ListeningSocket {
+	var sockets []model.ListeningSocket
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			continue
+		}
+
+		protocol := strings.TrimSuffix(strings.ToLower(fields[0]), "6")
+		if protocol != "tcp" && protocol != "udp" {
+			continue
+		}
+		address, port, ok := splitListenAddress(fields[3])
+		if !ok {
+			continue
+		}
+
+		socket := model.ListeningSocket{Protocol: protocol, Address: address, Port: port}
+		if pid, name, found := strings.Cut(fields[len(fields)-1], "/"); found {
+			if n, err := strconv.Atoi(pid); err == nil {
+				socket.PID = n
+				socket.Process = name
+			}
+		}
+		sockets = append(sockets, socket)
+	}
+	return sockets
+}
+
+// splitListenAddress splits "0.0.0.0:22", "[::]:22", ":::22",
+// "*:80" or "127.0.0.53%lo:53" into the address and port
+func splitListenAddress(value string) (string, int, bool) {
+	i := strings.LastIndex(value, ":")
+	if i < 0 {
+		return "", 0, false
+	}
+	port, err := strconv.Atoi(value[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+
+	address := strings.Trim(value[:i], "[]")
+	address, _, _ = strings.Cut(address, "%")
+	if address == "" || address == "::" {
+		address = "::"
+	} else if ip := net.ParseIP(address); ip != nil && ip.To4() != nil && strings.Contains(address, ":") {
+		// netstat prints IPv4-mapped addresses as ::ffff:10.0.0.1
+		address = ip.String()
+	}
+	return address, port, true
+}
+
+// ParseDpkgSearch returns the package of the first `dpkg-query -S` line,
+// "openssh-server: /usr/sbin/sshd"
+func ParseDpkgSearch(output []byte) string {
+	name, path, found := strings.Cut(firstLine(output), ": ")
+	if !found || !strings.HasPrefix(path, "/") {
+		return ""
+	}
+	// Multi-arch packages are listed as name:arch
+	name, _, _ = strings.Cut(name, ":")
+	return strings.TrimSpace(name)
+}
+
+// ParseApkWhoOwns returns the package named by `apk info --who-owns`,
+// "/usr/sbin/sshd is owned by openssh-server-9.7_p1-r4", without its version
+func ParseApkWhoOwns(output []byte) string {
+	_, owner, found := strings.Cut(firstLine(output), " is owned by ")
+	if !found {
+		return ""
+	}
+	// Versions start at the first "-" followed by a digit
+	for i := 0; i < len(owner)-1; i++ {
+		if owner[i] == '-' && owner[i+1] >= '0' && owner[i+1] <= '9' {
+			return owner[:i]
+		}
+	}
+	return owner
+}
+
+// firstLine returns the first line of output, trimmed
+func firstLine(output []byte) string {
+	line, _, _ := strings.Cut(string(output), "\n")
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,370 @@
+// pkg/adapter/secondary/os_package_audit_repository.go
+package secondary
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// OSPackageAuditRepository implements PackageAuditRepository with the
+// package manager and the distribution's advisory tools
+type OSPackageAuditRepository struct {
+	fs         interfaces.FileSystem
+	commander  interfaces.Commander
+	osType     string
+	osVersion  string
+	osCodename string
+}
+
+// NewOSPackageAuditRepository creates a new OSPackageAuditRepository
+func NewOSPackageAuditRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	osType string,
+	osVersion string,
+	osCodename string,
+) secondary.PackageAuditRepository {
+	return &OSPackageAuditRepository{
+		fs:         fs,
+		commander:  commander,
+		osType:     osType,
+		osVersion:  osVersion,
+		osCodename: osCodename,
+	}
+}
+
+// alpineSecDBURL is the Alpine security database, per release and repository
+const alpineSecDBURL = "https://secdb.alpinelinux.org/%s/%s.json"
+
+// alpineSecDBRepos are the repositories whose advisories are checked
+var alpineSecDBRepos = []string{"main", "community"}
+
+// apkPackagePattern splits "name-1.2.3-r4" as printed by apk info -v
+var apkPackagePattern = regexp.MustCompile(`^(.+)-([0-9][^-]*-r[0-9]+)$`)
+
+// InstalledPackages lists the installed packages with dpkg-query, apk or rpm
+func (r *OSPackageAuditRepository) InstalledPackages() ([]model.InstalledPackage, error) {
+	var packages []model.InstalledPackage
+
+	switch {
+	case r.osType == "alpine":
+		output, err := r.commander.Execute("apk", "info", "-v")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list installed packages: %w", err)
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			if match := apkPackagePattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+				packages = append(packages, model.InstalledPackage{Name: match[1], Version: match[2]})
+			}
+		}
+
+	case model.IsRHELFamily(r.osType):
+		output, err := r.commander.Execute("rpm", "-qa", "--qf", `%{NAME}\t%{VERSION}-%{RELEASE}\n`)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list installed packages: %w", err)
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			if name, version, ok := strings.Cut(strings.TrimSpace(line), "\t"); ok {
+				packages = append(packages, model.InstalledPackage{Name: name, Version: version})
+			}
+		}
+
+	default:
+		output, err := r.commander.Execute("dpkg-query", "-W", `-f=${db:Status-Abbrev}\t${Package}\t${Version}\n`)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list installed packages: %w", err)
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			// Removed packages whose configuration is kept are listed as rc
+			fields := strings.Split(line, "\t")
+			if len(fields) == 3 && strings.HasPrefix(fields[0], "ii") {
+				packages = append(packages, model.InstalledPackage{Name: fields[1], Version: fields[2]})
+			}
+		}
+	}
+
+	return packages, nil
+}
+
+// AdvisorySource names where advisories come from on this distribution
+func (r *OSPackageAuditRepository) AdvisorySource() string {
+	switch {
+	case r.osType == "alpine":
+		return "Alpine secdb"
+	case model.IsRHELFamily(r.osType):
+		return "dnf updateinfo"
+	case r.osType == "debian" || r.osType == "ubuntu":
+		return "debsecan"
+	}
+	return ""
+}
+
+// CheckAdvisories returns the advisories affecting the packages. Offline,
+// dnf reads the repository metadata it has cached; the other sources are
+// downloaded and are not available.
+func (r *OSPackageAuditRepository) CheckAdvisories(packages []model.InstalledPackage) ([]model.PackageVulnerability, error) {
+	if model.IsRHELFamily(r.osType) {
+		return r.checkDnfUpdateinfo()
+	}
+	if err := interfaces.RequireNetwork("download security advisories"); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case r.osType == "alpine":
+		return r.checkAlpineSecDB(packages)
+	case r.osType == "debian" || r.osType == "ubuntu":
+		return r.checkDebsecan()
+	}
+	return nil, fmt.Errorf("no security advisory source for %s", r.osType)
+}
+
+// checkDebsecan reads the summary of debsecan, which compares the installed
+// packages with the Debian security tracker. Only Debian releases are known
+// to the tracker; elsewhere debsecan reports every open issue.
+func (r *OSPackageAuditRepository) checkDebsecan() ([]model.PackageVulnerability, error) {
+	if _, err := r.commander.Execute("which", "debsecan"); err != nil {
+		return nil, fmt.Errorf("debsecan is not installed; install the debsecan package")
+	}
+
+	args := []string{"--format", "summary"}
+	if r.osType == "debian" && r.osCodename != "" {
+		args = append(args, "--suite", r.osCodename)
+	}
+	output, err := r.commander.Execute("debsecan", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run debsecan: %w\nOutput: %s", err, string(output))
+	}
+
+	// CVE-2024-1234 openssl (remotely exploitable, high urgency, fixed)
+	var vulnerabilities []model.PackageVulnerability
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		vulnerability := model.PackageVulnerability{
+			ID:       fields[0],
+			Package:  fields[1],
+			Severity: model.SeverityUnknown,
+		}
+		notes := strings.Trim(strings.Join(fields[2:], " "), "()")
+		for _, note := range strings.Split(notes, ",") {
+			switch note = strings.TrimSpace(note); {
+			case note == "fixed":
+				vulnerability.FixAvailable = true
+			case note == "remotely exploitable":
+				vulnerability.Remote = true
+			case strings.HasSuffix(note, " urgency"):
+				vulnerability.Severity = debsecanSeverity(strings.TrimSuffix(note, " urgency"))
+			}
+		}
+		vulnerabilities = append(vulnerabilities, vulnerability)
+	}
+	return vulnerabilities, nil
+}
+
+// debsecanSeverity maps a security tracker urgency to a severity
+func debsecanSeverity(urgency string) string {
+	switch urgency {
+	case "high":
+		return model.SeverityHigh
+	case "medium":
+		return model.SeverityMedium
+	case "low", "unimportant":
+		return model.SeverityLow
+	}
+	return model.SeverityUnknown
+}
+
+// checkDnfUpdateinfo lists the security advisories of available updates.
+// Advisories without an update are not known to dnf.
+func (r *OSPackageAuditRepository) checkDnfUpdateinfo() ([]model.PackageVulnerability, error) {
+	args := []string{"-q", "updateinfo", "list", "--security", "--with-cve"}
+	if interfaces.Offline() {
+		args = append([]string{"--cacheonly"}, args...)
+	}
+	output, err := r.commander.Execute("dnf", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list security advisories: %w\nOutput: %s", err, string(output))
+	}
+
+	// CVE-2024-1234 Important/Sec. openssl-libs-1:3.0.7-27.el9.x86_64
+	var vulnerabilities []model.PackageVulnerability
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || !strings.HasPrefix(fields[0], "CVE-") {
+			continue
+		}
+		rating, _, _ := strings.Cut(fields[1], "/")
+		name, fixed, ok := splitNEVRA(fields[2])
+		if !ok {
+			name = fields[2]
+		}
+		if _, afterEpoch, found := strings.Cut(fixed, ":"); found {
+			fixed = afterEpoch
+		}
+		vulnerabilities = append(vulnerabilities, model.PackageVulnerability{
+			ID:           fields[0],
+			Package:      name,
+			Severity:     dnfSeverity(rating),
+			FixAvailable: true,
+			FixedVersion: fixed,
+		})
+	}
+	return vulnerabilities, nil
+}
+
+// dnfSeverity maps a Red Hat advisory rating to a severity
+func dnfSeverity(rating string) string {
+	switch strings.ToLower(rating) {
+	case "critical":
+		return model.SeverityCritical
+	case "important":
+		return model.SeverityHigh
+	case "moderate":
+		return model.SeverityMedium
+	case "low":
+		return model.SeverityLow
+	}
+	return model.SeverityUnknown
+}
+
+// alpineSecDB is the part of a secdb file hardn reads; secfixes maps the
+// version fixing them to the advisories, with "0" for those that never
+// affected Alpine's package
+type alpineSecDB struct {
+	Packages []struct {
+		Pkg struct {
+			Name     string              `json:"name"`
+			Secfixes map[string][]string `json:"secfixes"`
+		} `json:"pkg"`
+	} `json:"packages"`
+}
+
+// checkAlpineSecDB compares the installed packages with the fixed versions
+// of the Alpine security database for this release. apk audit only checks
+// installed files against the package database, so it says nothing about
+// advisories.
+func (r *OSPackageAuditRepository) checkAlpineSecDB(packages []model.InstalledPackage) ([]model.PackageVulnerability, error) {
+	release, err := alpineRelease(r.osVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	installed := make(map[string]string)
+	for _, pkg := range packages {
+		installed[pkg.Name] = pkg.Version
+	}
+
+	var vulnerabilities []model.PackageVulnerability
+	for _, repo := range alpineSecDBRepos {
+		url := fmt.Sprintf(alpineSecDBURL, release, repo)
+		output, err := r.commander.Execute("wget", "-qO-", url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", url, err)
+		}
+		var secdb alpineSecDB
+		if err := json.Unmarshal(output, &secdb); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", url, err)
+		}
+
+		for _, entry := range secdb.Packages {
+			version, ok := installed[entry.Pkg.Name]
+			if !ok {
+				continue
+			}
+			for fixed, ids := range entry.Pkg.Secfixes {
+				if fixed == "0" || !r.apkOlder(version, fixed) {
+					continue
+				}
+				for _, id := range ids {
+					vulnerabilities = append(vulnerabilities, model.PackageVulnerability{
+						ID:           alpineAdvisoryID(id),
+						Package:      entry.Pkg.Name,
+						Version:      version,
+						Severity:     model.SeverityUnknown,
+						FixAvailable: true,
+						FixedVersion: fixed,
+					})
+				}
+			}
+		}
+	}
+	return vulnerabilities, nil
+}
+
+// apkOlder reports whether apk orders version before fixed
+func (r *OSPackageAuditRepository) apkOlder(version, fixed string) bool {
+	output, err := r.commander.Execute("apk", "version", "-t", version, fixed)
+	return err == nil && strings.TrimSpace(string(output)) == "<"
+}
+
+// alpineRelease returns the secdb directory of an Alpine version, such as
+// v3.19 for 3.19.1
+func alpineRelease(version string) (string, error) {
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("unknown Alpine release %q", version)
+	}
+	if strings.Contains(version, "_alpha") {
+		return "edge", nil
+	}
+	return "v" + parts[0] + "." + parts[1], nil
+}
+
+// alpineAdvisoryID picks the CVE out of a secfixes entry, which may list
+// other IDs or a note after it
+func alpineAdvisoryID(entry string) string {
+	fields := strings.Fields(entry)
+	for _, field := range fields {
+		if strings.HasPrefix(field, "CVE-") {
+			return field
+		}
+	}
+	if len(fields) > 0 {
+		return fields[0]
+	}
+	return entry
+}
+
+// LoadReport returns the last check, or nil when there is none
+func (r *OSPackageAuditRepository) LoadReport() (*model.PackageAuditReport, error) {
+	if _, err := r.fs.Stat(model.PackageAuditPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+	data, err := r.fs.ReadFile(model.PackageAuditPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", model.PackageAuditPath, err)
+	}
+
+	var report model.PackageAuditReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", model.PackageAuditPath, err)
+	}
+	return &report, nil
+}
+
+// SaveReport keeps a check for the status display
+func (r *OSPackageAuditRepository) SaveReport(report *model.PackageAuditReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode package audit: %w", err)
+	}
+
+	if err := r.fs.MkdirAll(filepath.Dir(model.PackageAuditPath), 0700); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", model.PackageAuditPath, err)
+	}
+	if err := r.fs.WriteFile(model.PackageAuditPath, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", model.PackageAuditPath, err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package secondary
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/abbott/hardn/pkg/domain/model"
@@ -14,13 +15,24 @@ import (
 type OSPackageRepository struct {
 	fs         interfaces.FileSystem
 	commander  interfaces.Commander
+	services   secondary.ServiceManager
 	osType     string
 	osVersion  string
 	osCodename string
+	arch       string
 	isProxmox  bool
 	config     *model.PackageSources
 }
 
+// ubuntuPortsArchs are the architectures Ubuntu serves from ports.ubuntu.com
+// instead of archive.ubuntu.com and security.ubuntu.com
+var ubuntuPortsArchs = map[string]bool{
+	"arm64": true, "arm": true, "riscv64": true, "ppc64le": true, "s390x": true,
+}
+
+// ubuntuPortsMirror replaces the x86 archive and security mirrors
+const ubuntuPortsMirror = "ports.ubuntu.com/ubuntu-ports"
+
 // NewOSPackageRepository creates a new OSPackageRepository
 func NewOSPackageRepository(
 	fs interfaces.FileSystem,
@@ -28,15 +40,18 @@ func NewOSPackageRepository(
 	osType string,
 	osVersion string,
 	osCodename string,
+	arch string,
 	isProxmox bool,
 	config *model.PackageSources,
 ) secondary.PackageRepository {
 	return &OSPackageRepository{
 		fs:         fs,
 		commander:  commander,
+		services:   NewServiceManager(commander, osType),
 		osType:     osType,
 		osVersion:  osVersion,
 		osCodename: osCodename,
+		arch:       arch,
 		isProxmox:  isProxmox,
 		config:     config,
 	}
@@ -61,6 +76,12 @@ func (r *OSPackageRepository) InstallPackages(request model.PackageInstallReques
 		if err != nil {
 			return fmt.Errorf("failed to install Alpine packages: %w", err)
 		}
+	} else if model.IsRHELFamily(r.osType) {
+		// dnf refreshes expired metadata itself
+		args = append([]string{"install", "-y"}, request.Packages...)
+		if output, err := r.commander.Execute(r.rpmPackageManager(), args...); err != nil {
+			return fmt.Errorf("failed to install %s packages: %w\nOutput: %s", r.osType, err, string(output))
+		}
 	} else {
 		// Hold Proxmox packages if necessary
 		if r.isProxmox {
@@ -115,6 +136,13 @@ func (r *OSPackageRepository) installPythonPackages(request model.PackageInstall
 				return fmt.Errorf("failed to install Alpine Python packages: %w", err)
 			}
 		}
+	} else if model.IsRHELFamily(r.osType) {
+		if len(request.Packages) > 0 {
+			args := append([]string{"install", "-y"}, request.Packages...)
+			if _, err := r.commander.Execute(r.rpmPackageManager(), args...); err != nil {
+				return fmt.Errorf("failed to install Python system packages: %w", err)
+			}
+		}
 	} else {
 		// For Debian/Ubuntu systems
 		if len(request.Packages) > 0 {
@@ -169,6 +197,10 @@ func (r *OSPackageRepository) UpdatePackageSources(sources model.PackageSources)
 	if r.osType == "alpine" {
 		return r.updateAlpineSources(sources)
 	}
+	if model.IsRHELFamily(r.osType) {
+		return fmt.Errorf("package sources on %s come from the .repo files in %s and are not managed by hardn",
+			r.osType, model.YumReposDir)
+	}
 
 	// Debian/Ubuntu
 	return r.updateDebianSources(sources)
@@ -217,15 +249,23 @@ https://dl-cdn.alpinelinux.org/alpine/edge/testing
 // updateDebianSources updates Debian/Ubuntu repository configuration
 func (r *OSPackageRepository) updateDebianSources(sources model.PackageSources) error {
 	// Prepare content by replacing CODENAME placeholder
-	var content strings.Builder
+	repos := make([]string, 0, len(sources.DebianRepos))
 	for _, repo := range sources.DebianRepos {
-		content.WriteString(strings.ReplaceAll(repo, "CODENAME", r.osCodename))
+		repos = append(repos, r.archRepo(strings.ReplaceAll(repo, "CODENAME", r.osCodename)))
+	}
+	if r.SourcesFormat(sources.SourcesFormat) == model.SourcesFormatDeb822 {
+		return r.writeReleaseSources(repos)
+	}
+
+	var content strings.Builder
+	for _, repo := range repos {
+		content.WriteString(repo)
 		content.WriteString("\n")
 	}
 
 	// Backup original file
-	backupFile := "/etc/apt/sources.list.bak"
-	originalData, err := r.fs.ReadFile("/etc/apt/sources.list")
+	backupFile := model.AptSourcesListPath + ".bak"
+	originalData, err := r.fs.ReadFile(model.AptSourcesListPath)
 	if err == nil {
 		if err := r.fs.WriteFile(backupFile, originalData, 0644); err != nil {
 			fmt.Printf("Warning: Failed to create backup of sources.list: %v\n", err)
@@ -233,13 +273,147 @@ func (r *OSPackageRepository) updateDebianSources(sources model.PackageSources)
 	}
 
 	// Write the file
-	if err := r.fs.WriteFile("/etc/apt/sources.list", []byte(content.String()), 0644); err != nil {
+	if err := r.fs.WriteFile(model.AptSourcesListPath, []byte(content.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write Debian/Ubuntu sources list: %w", err)
+	}
+
+	return r.retireAptList(model.AptReleaseSourcesPath(r.osType))
+}
+
+// writeReleaseSources writes the repositories to the release .sources
+// file, such as debian.sources, and empties sources.list so they are not
+// listed twice. Both files are backed up first.
+func (r *OSPackageRepository) writeReleaseSources(repos []string) error {
+	content, err := deb822Content(repos, "Managed by hardn: repositories from debianRepos")
+	if err != nil {
+		return err
+	}
+
+	path := model.AptReleaseSourcesPath(r.osType)
+	if originalData, err := r.fs.ReadFile(path); err == nil {
+		if err := r.fs.WriteFile(path+".bak", originalData, 0644); err != nil {
+			fmt.Printf("Warning: Failed to create backup of %s: %v\n", path, err)
+		}
+	}
+	if err := r.fs.MkdirAll(model.AptSourcesDir, 0755); err != nil {
+		return fmt.Errorf("failed to create sources.list.d directory: %w", err)
+	}
+	if err := r.fs.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write Debian/Ubuntu sources: %w", err)
+	}
+
+	return r.emptySourcesList(path)
+}
+
+// emptySourcesList backs up sources.list and leaves a comment pointing to
+// the file that replaced it, as the Ubuntu 24.04 installer does. A list
+// without entries is left alone.
+func (r *OSPackageRepository) emptySourcesList(replacement string) error {
+	data, err := r.fs.ReadFile(model.AptSourcesListPath)
+	if err != nil || len(model.ParseAptSourcesFile(model.AptFile{Path: model.AptSourcesListPath, Data: data}, false)) == 0 {
+		return nil
+	}
+	if err := r.fs.WriteFile(model.AptSourcesListPath+".bak", data, 0644); err != nil {
+		return fmt.Errorf("failed to back up sources.list: %w", err)
+	}
+	content := fmt.Sprintf("# Repositories are listed in %s\n", replacement)
+	if err := r.fs.WriteFile(model.AptSourcesListPath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write Debian/Ubuntu sources list: %w", err)
 	}
+	return nil
+}
 
+// retireAptList renames a list hardn no longer writes to a .bak file,
+// which APT ignores, so its repositories are not listed twice
+func (r *OSPackageRepository) retireAptList(path string) error {
+	if _, err := r.fs.Stat(path); err != nil {
+		return nil
+	}
+	if err := r.fs.Rename(path, path+".bak"); err != nil {
+		return fmt.Errorf("failed to move %s aside: %w", path, err)
+	}
 	return nil
 }
 
+// deb822Content converts configured one-line entries to deb822 stanzas.
+// Commented-out entries become disabled stanzas.
+func deb822Content(repos []string, header string) (string, error) {
+	var sources []model.AptSource
+	for _, repo := range repos {
+		source, err := model.ParseAptSourceEntry(repo)
+		if err != nil {
+			return "", err
+		}
+		if source != nil {
+			sources = append(sources, *source)
+		}
+	}
+	return model.FormatAptSources(sources, model.SourcesFormatDeb822, header)
+}
+
+// SourcesFormat resolves a sourcesFormat setting. Auto follows the host:
+// deb822 when the release repositories are in a .sources file, as on
+// Debian 13 and Ubuntu 24.04, and one-line entries otherwise.
+func (r *OSPackageRepository) SourcesFormat(setting string) string {
+	format := model.ParseSourcesFormat(setting)
+	if format != model.SourcesFormatAuto {
+		return format
+	}
+	if _, err := r.fs.Stat(model.AptReleaseSourcesPath(r.osType)); err == nil {
+		return model.SourcesFormatDeb822
+	}
+	return model.SourcesFormatList
+}
+
+// ReadAptSources reads sources.list and the lists in sources.list.d
+func (r *OSPackageRepository) ReadAptSources() ([]model.AptFile, error) {
+	return readAptSources(r.fs, r.commander)
+}
+
+// ReadSourceFile reads a repository list
+func (r *OSPackageRepository) ReadSourceFile(path string) ([]byte, error) {
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// ReplaceAptSources writes a converted list, then moves the list it
+// replaces aside. sources.list stays with a comment pointing to the new
+// file; other lists are renamed to .bak.
+func (r *OSPackageRepository) ReplaceAptSources(from, to string, data []byte) error {
+	if err := r.fs.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(to), err)
+	}
+	if err := r.fs.WriteFile(to, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", to, err)
+	}
+	if from == model.AptSourcesListPath {
+		return r.emptySourcesList(to)
+	}
+	return r.retireAptList(from)
+}
+
+// archRepo points Ubuntu repository lines at the ports mirror on
+// architectures the x86 mirrors do not carry
+func (r *OSPackageRepository) archRepo(repo string) string {
+	if r.osType != "ubuntu" || !ubuntuPortsArchs[r.arch] {
+		return repo
+	}
+	for _, mirror := range []string{"archive.ubuntu.com/ubuntu", "security.ubuntu.com/ubuntu"} {
+		// Country mirrors such as us.archive.ubuntu.com are x86-only too
+		if i := strings.Index(repo, mirror); i >= 0 && !strings.HasPrefix(repo[i+len(mirror):], "-ports") {
+			start := strings.LastIndex(repo[:i], "//") + 2
+			if start < 2 {
+				start = i
+			}
+			repo = repo[:start] + ubuntuPortsMirror + repo[i+len(mirror):]
+		}
+	}
+	return repo
+}
+
 // UpdateProxmoxSources updates Proxmox-specific sources
 func (r *OSPackageRepository) UpdateProxmoxSources(sources model.PackageSources) error {
 	if !r.isProxmox {
@@ -247,33 +421,47 @@ func (r *OSPackageRepository) UpdateProxmoxSources(sources model.PackageSources)
 	}
 
 	// Create directory if it doesn't exist
-	if err := r.fs.MkdirAll("/etc/apt/sources.list.d", 0755); err != nil {
+	if err := r.fs.MkdirAll(model.AptSourcesDir, 0755); err != nil {
 		return fmt.Errorf("failed to create sources.list.d directory: %w", err)
 	}
 
-	// Write Ceph repository
-	var cephContent strings.Builder
-	for _, repo := range sources.ProxmoxCephRepo {
-		cephContent.WriteString(strings.ReplaceAll(repo, "CODENAME", r.osCodename))
-		cephContent.WriteString("\n")
-	}
-
-	if err := r.fs.WriteFile("/etc/apt/sources.list.d/ceph.list", []byte(cephContent.String()), 0644); err != nil {
+	format := r.SourcesFormat(sources.SourcesFormat)
+	if err := r.writeProxmoxList("ceph", "proxmoxCephRepo", sources.ProxmoxCephRepo, format); err != nil {
 		return fmt.Errorf("failed to write Proxmox Ceph repository: %w", err)
 	}
+	if err := r.writeProxmoxList("pve-enterprise", "proxmoxEnterpriseRepo", sources.ProxmoxEnterpriseRepo, format); err != nil {
+		return fmt.Errorf("failed to write Proxmox Enterprise repository: %w", err)
+	}
 
-	// Write Enterprise repository
-	var enterpriseContent strings.Builder
-	for _, repo := range sources.ProxmoxEnterpriseRepo {
-		enterpriseContent.WriteString(strings.ReplaceAll(repo, "CODENAME", r.osCodename))
-		enterpriseContent.WriteString("\n")
+	return nil
+}
+
+// writeProxmoxList writes a Proxmox list such as ceph.list or ceph.sources
+// and moves the list in the other format aside. The enterprise repository
+// is usually commented out, which deb822 writes as a disabled stanza.
+func (r *OSPackageRepository) writeProxmoxList(name, setting string, repos []string, format string) error {
+	lines := make([]string, 0, len(repos))
+	var list strings.Builder
+	for _, repo := range repos {
+		line := strings.ReplaceAll(repo, "CODENAME", r.osCodename)
+		lines = append(lines, line)
+		list.WriteString(line + "\n")
 	}
 
-	if err := r.fs.WriteFile("/etc/apt/sources.list.d/pve-enterprise.list", []byte(enterpriseContent.String()), 0644); err != nil {
-		return fmt.Errorf("failed to write Proxmox Enterprise repository: %w", err)
+	content := list.String()
+	other := model.AptListPath(name, model.SourcesFormatDeb822)
+	if format == model.SourcesFormatDeb822 {
+		var err error
+		if content, err = deb822Content(lines, "Managed by hardn: repositories from "+setting); err != nil {
+			return err
+		}
+		other = model.AptListPath(name, model.SourcesFormatList)
 	}
 
-	return nil
+	if err := r.fs.WriteFile(model.AptListPath(name, format), []byte(content), 0644); err != nil {
+		return err
+	}
+	return r.retireAptList(other)
 }
 
 // IsPackageInstalled checks if a package is installed
@@ -285,14 +473,194 @@ func (r *OSPackageRepository) IsPackageInstalled(packageName string) (bool, erro
 			return false, nil // Package not installed
 		}
 		return true, nil
+	} else if model.IsRHELFamily(r.osType) {
+		// rpm -q exits non-zero for packages that are not installed
+		_, err := r.commander.Execute("rpm", "-q", packageName)
+		return err == nil, nil
 	} else {
-		// Debian/Ubuntu method
-		_, err := r.commander.Execute("dpkg", "-l", packageName)
+		// Debian/Ubuntu method; dpkg -l also succeeds for removed packages
+		output, err := r.commander.Execute("dpkg-query", "-W", "-f="+DpkgStatusFormat, packageName)
 		if err != nil {
 			return false, nil // Package not installed
 		}
-		return true, nil
+		return ParseDpkgInstalled(output), nil
+	}
+}
+
+// ConfigureAutoUpgrades enables or disables dnf-automatic on RHEL-family
+// distributions; Debian, Ubuntu and Alpine are handled by the updates repository
+func (r *OSPackageRepository) ConfigureAutoUpgrades(enable bool) error {
+	if !model.IsRHELFamily(r.osType) {
+		return fmt.Errorf("dnf-automatic is only supported on RHEL-family distributions")
+	}
+	return r.configureDnfAutomatic(enable)
+}
+
+// IsAutoUpgradeEnabled checks if the dnf-automatic timer is enabled
+func (r *OSPackageRepository) IsAutoUpgradeEnabled() (bool, error) {
+	if !model.IsRHELFamily(r.osType) {
+		return false, nil
+	}
+	timer, err := r.services.Status(model.DnfAutomaticTimer)
+	if err != nil {
+		return false, err
+	}
+	return timer.Enabled, nil
+}
+
+// configureDnfAutomatic installs dnf-automatic, makes it apply security
+// updates rather than only download them, and enables its timer
+func (r *OSPackageRepository) configureDnfAutomatic(enable bool) error {
+	if !enable {
+		if err := r.services.Disable(model.DnfAutomaticTimer); err != nil {
+			return fmt.Errorf("failed to disable %s: %w", model.DnfAutomaticTimer, err)
+		}
+		return nil
+	}
+
+	if installed, _ := r.IsPackageInstalled("dnf-automatic"); !installed {
+		if output, err := r.commander.Execute(r.rpmPackageManager(), "install", "-y", "dnf-automatic"); err != nil {
+			return fmt.Errorf("failed to install dnf-automatic: %w\nOutput: %s", err, string(output))
+		}
+	}
+
+	if err := r.setDnfAutomaticOptions(map[string]string{
+		"upgrade_type":  "security",
+		"apply_updates": "yes",
+	}); err != nil {
+		return err
+	}
+
+	if err := r.services.Enable(model.DnfAutomaticTimer); err != nil {
+		return fmt.Errorf("failed to enable %s: %w", model.DnfAutomaticTimer, err)
+	}
+	return nil
+}
+
+// setDnfAutomaticOptions sets options in the [commands] section of
+// automatic.conf, keeping the rest of the file
+func (r *OSPackageRepository) setDnfAutomaticOptions(options map[string]string) error {
+	var lines []string
+	if _, err := r.fs.Stat(model.DnfAutomaticConfigPath); err == nil {
+		data, err := r.fs.ReadFile(model.DnfAutomaticConfigPath)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", model.DnfAutomaticConfigPath, err)
+		}
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	}
+
+	section, commandsAt := "", -1
+	set := make(map[string]bool)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			section = strings.Trim(trimmed, "[]")
+			if section == "commands" {
+				commandsAt = i
+			}
+			continue
+		}
+		key, _, found := strings.Cut(trimmed, "=")
+		key = strings.TrimSpace(key)
+		if value, ok := options[key]; found && ok && section == "commands" {
+			lines[i] = key + " = " + value
+			set[key] = true
+		}
+	}
+
+	// Options missing from the file go at the top of [commands]
+	var missing []string
+	for _, key := range []string{"upgrade_type", "apply_updates"} {
+		if value, ok := options[key]; ok && !set[key] {
+			missing = append(missing, key+" = "+value)
+		}
+	}
+	if commandsAt < 0 {
+		lines = append(lines, "[commands]")
+		commandsAt = len(lines) - 1
 	}
+	lines = append(lines[:commandsAt+1], append(missing, lines[commandsAt+1:]...)...)
+
+	if err := r.fs.WriteFile(model.DnfAutomaticConfigPath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", model.DnfAutomaticConfigPath, err)
+	}
+	return nil
+}
+
+// rpmPackageManager returns dnf, or yum on hosts without it
+func (r *OSPackageRepository) rpmPackageManager() string {
+	if _, err := r.commander.Execute("which", "dnf"); err != nil {
+		if _, err := r.commander.Execute("which", "yum"); err == nil {
+			return "yum"
+		}
+	}
+	return "dnf"
+}
+
+// ConfigureNeedrestart installs needrestart and writes the restart mode drop-in
+func (r *OSPackageRepository) ConfigureNeedrestart(mode string) error {
+	if r.osType == "alpine" {
+		return fmt.Errorf("needrestart is not available on Alpine")
+	}
+
+	value, ok := model.NeedrestartModes[mode]
+	if !ok {
+		return fmt.Errorf("invalid needrestart mode: %s", mode)
+	}
+
+	if installed, _ := r.IsPackageInstalled("needrestart"); !installed {
+		if output, err := r.commander.Execute("apt-get", "install", "--yes", "needrestart"); err != nil {
+			return fmt.Errorf("failed to install needrestart: %w\nOutput: %s", err, string(output))
+		}
+	}
+
+	if err := r.fs.MkdirAll(filepath.Dir(model.NeedrestartConfigPath), 0755); err != nil {
+		return fmt.Errorf("failed to create needrestart config directory: %w", err)
+	}
+
+	content := fmt.Sprintf("# Managed by hardn: restart mode %s\n$nrconf{restart} = '%s';\n", mode, value)
+	if err := r.fs.WriteFile(model.NeedrestartConfigPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write needrestart configuration: %w", err)
+	}
+
+	return nil
+}
+
+// GetNeedrestartStatus reads the configured mode and asks needrestart, in
+// batch mode, which services and kernel are waiting on a restart
+func (r *OSPackageRepository) GetNeedrestartStatus() (*model.NeedrestartStatus, error) {
+	status := &model.NeedrestartStatus{}
+
+	if _, err := r.fs.Stat(RebootRequiredPath); err == nil {
+		status.RebootRequired = true
+	}
+
+	installed, _ := r.IsPackageInstalled("needrestart")
+	if !installed {
+		return status, nil
+	}
+	status.Installed = true
+
+	if data, err := r.fs.ReadFile(model.NeedrestartConfigPath); err == nil {
+		for name, value := range model.NeedrestartModes {
+			if strings.Contains(string(data), "$nrconf{restart} = '"+value+"';") {
+				status.Mode = name
+				break
+			}
+		}
+	}
+
+	// -b prints machine-readable NEEDRESTART-* lines; -r l never restarts anything
+	output, err := r.commander.Execute("needrestart", "-b", "-r", "l")
+	if err != nil {
+		return nil, fmt.Errorf("failed to run needrestart: %w", err)
+	}
+
+	services, kernelOutdated := ParseNeedrestartBatch(output)
+	status.PendingServices = services
+	status.RebootRequired = status.RebootRequired || kernelOutdated
+
+	return status, nil
 }
 
 // GetPackageSources retrieves the current package sources configuration
@@ -0,0 +1,399 @@
+// pkg/adapter/secondary/os_password_policy_repository.go
+package secondary
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// passwordPolicyMarker precedes the settings hardn adds to a file that
+// did not have them
+const passwordPolicyMarker = "# Managed by hardn: password policy"
+
+// PAM stacks that include pam_pwquality and pam_faillock
+const (
+	debianPamAuthPath     = "/etc/pam.d/common-auth"
+	debianPamPasswordPath = "/etc/pam.d/common-password"
+	rhelPamSystemAuthPath = "/etc/pam.d/system-auth"
+)
+
+// pwqualityDefaultMinLength is the minimum length pam_pwquality applies
+// when none is configured
+const pwqualityDefaultMinLength = 8
+
+// faillockDefaultDeny is the number of failures pam_faillock locks an
+// account after when none is configured
+const faillockDefaultDeny = 3
+
+// faillockPamProfile counts a failed login once pam_unix rejects it
+const faillockPamProfile = `Name: Lock accounts after failed logins (hardn)
+Default: yes
+Priority: 0
+Auth-Type: Primary
+Auth:
+	[default=die] pam_faillock.so authfail
+`
+
+// faillockNotifyPamProfile refuses locked accounts before the password is
+// checked and resets the count after a successful login
+const faillockNotifyPamProfile = `Name: Check and reset failed login counts (hardn)
+Default: yes
+Priority: 1024
+Auth-Type: Primary
+Auth:
+	requisite pam_faillock.so preauth
+Account-Type: Primary
+Account:
+	required pam_faillock.so
+`
+
+// pamModuleDirs are where Debian and Ubuntu install PAM modules
+var pamModuleDirs = []string{
+	"/usr/lib/x86_64-linux-gnu/security",
+	"/usr/lib/aarch64-linux-gnu/security",
+	"/usr/lib/arm-linux-gnueabihf/security",
+	"/usr/lib/i386-linux-gnu/security",
+	"/lib/x86_64-linux-gnu/security",
+	"/lib/aarch64-linux-gnu/security",
+	"/lib/arm-linux-gnueabihf/security",
+	"/lib/i386-linux-gnu/security",
+}
+
+// configSetting is a key of a configuration file and the value hardn sets
+type configSetting struct {
+	key   string
+	value string
+}
+
+// OSPasswordPolicyRepository implements PasswordPolicyRepository with
+// pam_pwquality, login.defs and pam_faillock
+type OSPasswordPolicyRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	osType    string
+}
+
+// NewOSPasswordPolicyRepository creates a new OSPasswordPolicyRepository
+func NewOSPasswordPolicyRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	osType string,
+) secondary.PasswordPolicyRepository {
+	return &OSPasswordPolicyRepository{
+		fs:        fs,
+		commander: commander,
+		osType:    osType,
+	}
+}
+
+// ConfigurePasswordPolicy writes the strength, aging and lockout settings
+func (r *OSPasswordPolicyRepository) ConfigurePasswordPolicy(policy model.PasswordPolicy) error {
+	if r.osType == "alpine" {
+		return r.configureAlpineAging(policy)
+	}
+
+	if err := r.installPwquality(); err != nil {
+		return err
+	}
+	if err := r.setSettings(model.PwqualityConfigPath, " = ", []configSetting{
+		{"minlen", strconv.Itoa(policy.MinLength)},
+		{"dcredit", strconv.Itoa(policy.DigitCredit)},
+		{"ucredit", strconv.Itoa(policy.UpperCredit)},
+		{"lcredit", strconv.Itoa(policy.LowerCredit)},
+		{"ocredit", strconv.Itoa(policy.OtherCredit)},
+		{"retry", strconv.Itoa(policy.Retry)},
+	}); err != nil {
+		return err
+	}
+	if err := r.setSettings(model.LoginDefsPath, "\t", loginDefsSettings(policy)); err != nil {
+		return err
+	}
+
+	if model.IsRHELFamily(r.osType) {
+		return r.configureAuthselectLockout(policy)
+	}
+	return r.configurePamAuthUpdateLockout(policy)
+}
+
+// GetPasswordPolicyStatus reads the policy from the PAM stack and the
+// configuration files
+func (r *OSPasswordPolicyRepository) GetPasswordPolicyStatus() (*model.PasswordPolicyStatus, error) {
+	status := &model.PasswordPolicyStatus{}
+
+	defs := r.readFile(model.LoginDefsPath)
+	status.MaxDays, _ = strconv.Atoi(parseConfigValue(defs, "PASS_MAX_DAYS"))
+	status.MinDays, _ = strconv.Atoi(parseConfigValue(defs, "PASS_MIN_DAYS"))
+	status.WarnAge, _ = strconv.Atoi(parseConfigValue(defs, "PASS_WARN_AGE"))
+
+	authPath, passwordPath := debianPamAuthPath, debianPamPasswordPath
+	if model.IsRHELFamily(r.osType) {
+		authPath, passwordPath = rhelPamSystemAuthPath, rhelPamSystemAuthPath
+	}
+
+	if options, ok := pamModuleOptions(r.readFile(passwordPath), "pam_pwquality.so"); ok {
+		status.Complexity = true
+		status.MinLength = pwqualityDefaultMinLength
+		if value := parseConfigValue(r.readFile(model.PwqualityConfigPath), "minlen"); value != "" {
+			status.MinLength, _ = strconv.Atoi(value)
+		}
+		if value, ok := options["minlen"]; ok {
+			status.MinLength, _ = strconv.Atoi(value)
+		}
+	}
+
+	if options, ok := pamModuleOptions(r.readFile(authPath), "pam_faillock.so"); ok {
+		status.Lockout = true
+		status.LockoutAttempts = faillockDefaultDeny
+		if value := parseConfigValue(r.readFile(model.FaillockConfigPath), "deny"); value != "" {
+			status.LockoutAttempts, _ = strconv.Atoi(value)
+		}
+		if value, ok := options["deny"]; ok {
+			status.LockoutAttempts, _ = strconv.Atoi(value)
+		}
+	}
+
+	return status, nil
+}
+
+// configureAlpineAging writes the aging settings to login.defs, which is
+// part of the shadow package; busybox's own login and passwd ignore it
+func (r *OSPasswordPolicyRepository) configureAlpineAging(policy model.PasswordPolicy) error {
+	if _, err := r.fs.Stat(model.LoginDefsPath); err != nil {
+		if output, err := r.commander.Execute("apk", "add", "shadow"); err != nil {
+			return fmt.Errorf("failed to install shadow: %w\nOutput: %s", err, string(output))
+		}
+	}
+	return r.setSettings(model.LoginDefsPath, "\t", loginDefsSettings(policy))
+}
+
+// installPwquality installs the pam_pwquality module; on Debian and Ubuntu
+// the package adds it to common-password
+func (r *OSPasswordPolicyRepository) installPwquality() error {
+	if model.IsRHELFamily(r.osType) {
+		if _, err := r.commander.Execute("rpm", "-q", "libpwquality"); err == nil {
+			return nil
+		}
+		if output, err := r.commander.Execute("dnf", "install", "-y", "libpwquality"); err != nil {
+			return fmt.Errorf("failed to install libpwquality: %w\nOutput: %s", err, string(output))
+		}
+		return nil
+	}
+
+	output, _ := r.commander.Execute("dpkg-query", "-W", "-f="+DpkgStatusFormat, "libpam-pwquality")
+	if ParseDpkgInstalled(output) {
+		return nil
+	}
+	if output, err := r.commander.Execute("apt-get", "install", "-y", "libpam-pwquality"); err != nil {
+		return fmt.Errorf("failed to install libpam-pwquality: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// configurePamAuthUpdateLockout adds or removes the pam-auth-update
+// profiles that put pam_faillock around pam_unix
+func (r *OSPasswordPolicyRepository) configurePamAuthUpdateLockout(policy model.PasswordPolicy) error {
+	profile := filepath.Join(model.PamConfigsDir, model.FaillockPamProfile)
+	notifyProfile := filepath.Join(model.PamConfigsDir, model.FaillockNotifyPamProfile)
+
+	if policy.LockoutAttempts == 0 {
+		if _, err := r.fs.Stat(profile); err != nil {
+			return nil // lockout was never turned on by hardn
+		}
+		if output, err := r.commander.Execute("pam-auth-update", "--package", "--remove",
+			model.FaillockPamProfile, model.FaillockNotifyPamProfile); err != nil {
+			return fmt.Errorf("failed to remove pam_faillock: %w\nOutput: %s", err, string(output))
+		}
+		for _, path := range []string{profile, notifyProfile} {
+			if err := r.fs.Remove(path); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", path, err)
+			}
+		}
+		return nil
+	}
+
+	// A profile naming a missing module would fail every login
+	if !r.pamModuleInstalled("pam_faillock.so") {
+		return fmt.Errorf("pam_faillock is not installed; account lockout needs PAM 1.4 or newer (Debian 11, Ubuntu 22.04)")
+	}
+
+	if err := r.setSettings(model.FaillockConfigPath, " = ", faillockSettings(policy)); err != nil {
+		return err
+	}
+	for _, file := range []struct{ path, content string }{
+		{profile, faillockPamProfile},
+		{notifyProfile, faillockNotifyPamProfile},
+	} {
+		if err := r.fs.WriteFile(file.path, []byte(file.content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.path, err)
+		}
+	}
+	if output, err := r.commander.Execute("pam-auth-update", "--package", "--enable",
+		model.FaillockPamProfile, model.FaillockNotifyPamProfile); err != nil {
+		return fmt.Errorf("failed to enable pam_faillock: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// configureAuthselectLockout turns the with-faillock feature of the
+// authselect profile on or off
+func (r *OSPasswordPolicyRepository) configureAuthselectLockout(policy model.PasswordPolicy) error {
+	if _, err := r.commander.Execute("authselect", "current"); err != nil {
+		return fmt.Errorf("the PAM configuration is not managed by authselect; configure pam_faillock manually")
+	}
+
+	if policy.LockoutAttempts == 0 {
+		if output, err := r.commander.Execute("authselect", "disable-feature", "with-faillock"); err != nil {
+			return fmt.Errorf("failed to disable pam_faillock: %w\nOutput: %s", err, string(output))
+		}
+		return nil
+	}
+
+	if err := r.setSettings(model.FaillockConfigPath, " = ", faillockSettings(policy)); err != nil {
+		return err
+	}
+	if output, err := r.commander.Execute("authselect", "enable-feature", "with-faillock"); err != nil {
+		return fmt.Errorf("failed to enable pam_faillock: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// pamModuleInstalled reports whether a PAM module is in one of the module
+// directories
+func (r *OSPasswordPolicyRepository) pamModuleInstalled(module string) bool {
+	for _, dir := range pamModuleDirs {
+		if _, err := r.fs.Stat(filepath.Join(dir, module)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// setSettings sets keys in a configuration file, keeping its other lines
+func (r *OSPasswordPolicyRepository) setSettings(path, separator string, settings []configSetting) error {
+	content := setConfigValues(r.readFile(path), separator, settings)
+	if err := r.fs.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func (r *OSPasswordPolicyRepository) readFile(path string) string {
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// loginDefsSettings are the aging settings of login.defs
+func loginDefsSettings(policy model.PasswordPolicy) []configSetting {
+	return []configSetting{
+		{"PASS_MAX_DAYS", strconv.Itoa(policy.MaxDays)},
+		{"PASS_MIN_DAYS", strconv.Itoa(policy.MinDays)},
+		{"PASS_WARN_AGE", strconv.Itoa(policy.WarnAge)},
+	}
+}
+
+// faillockSettings are the lockout settings of faillock.conf
+func faillockSettings(policy model.PasswordPolicy) []configSetting {
+	return []configSetting{
+		{"deny", strconv.Itoa(policy.LockoutAttempts)},
+		{"unlock_time", strconv.Itoa(policy.LockoutUnlockTime)},
+	}
+}
+
+// setConfigValues sets keys in the content of a "key value" or
+// "key = value" file. The line that sets a key is replaced; keys the file
+// does not set are added at the end after a marker comment.
+func setConfigValues(content, separator string, settings []configSetting) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if content == "" {
+		lines = nil
+	}
+
+	var missing []string
+	for _, setting := range settings {
+		line := setting.key + separator + setting.value
+		found := false
+		for i, existing := range lines {
+			if key, _ := splitConfigLine(existing); key == setting.key {
+				lines[i] = line
+				found = true
+			}
+		}
+		if !found {
+			missing = append(missing, line)
+		}
+	}
+
+	if len(missing) > 0 {
+		marked := false
+		for _, line := range lines {
+			marked = marked || line == passwordPolicyMarker
+		}
+		if !marked {
+			lines = append(lines, passwordPolicyMarker)
+		}
+		lines = append(lines, missing...)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// parseConfigValue returns the value a "key value" or "key = value" file
+// sets for key, or "" when it sets none
+func parseConfigValue(content, key string) string {
+	value := ""
+	for _, line := range strings.Split(content, "\n") {
+		if k, v := splitConfigLine(line); k == key {
+			value = v
+		}
+	}
+	return value
+}
+
+// splitConfigLine returns the key and value of an active configuration line
+func splitConfigLine(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", ""
+	}
+	if key, value, found := strings.Cut(line, "="); found && !strings.ContainsAny(strings.TrimSpace(key), " \t") {
+		return strings.TrimSpace(key), strings.TrimSpace(value)
+	}
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return fields[0], ""
+	}
+	return fields[0], fields[1]
+}
+
+// pamModuleOptions returns the key=value options of the first active line
+// of a PAM file that uses module, and whether there is one
+func pamModuleOptions(content, module string) (map[string]string, bool) {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		for i, field := range fields {
+			if filepath.Base(field) != module {
+				continue
+			}
+			options := make(map[string]string)
+			for _, option := range fields[i+1:] {
+				if key, value, found := strings.Cut(option, "="); found {
+					options[key] = value
+				}
+			}
+			return options, true
+		}
+	}
+	return nil, false
+}
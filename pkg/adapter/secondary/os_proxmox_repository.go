@@ -0,0 +1,231 @@
+// pkg/adapter/secondary/os_proxmox_repository.go
+package secondary
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// subscriptionCheckPattern matches the line of proxmoxlib.js that tests the
+// subscription status before showing the dialog
+var subscriptionCheckPattern = regexp.MustCompile(`data\.status.*\{`)
+
+// subscriptionNagMarker is in proxmoxlib.js once the dialog is patched out
+const subscriptionNagMarker = "NoMoreNagging"
+
+// subscriptionNagSed patches proxmoxlib.js as PatchSubscriptionCheck does;
+// the apt hook runs it when dpkg reports the file as unmodified, i.e. after
+// proxmox-widget-toolkit was upgraded
+const subscriptionNagSed = `/data\.status.*{/{s/\!//;s/active/` + subscriptionNagMarker + `/}`
+
+// pveproxyVariablePattern matches a variable assignment of /etc/default/pveproxy
+var pveproxyVariablePattern = regexp.MustCompile(`^\s*([A-Z_][A-Z0-9_]*)=(.*)$`)
+
+// OSProxmoxRepository implements ProxmoxRepository with the files and
+// commands of a Proxmox VE host
+type OSProxmoxRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	services  secondary.ServiceManager
+}
+
+// NewOSProxmoxRepository creates a new OSProxmoxRepository
+func NewOSProxmoxRepository(fs interfaces.FileSystem, commander interfaces.Commander, osType string) secondary.ProxmoxRepository {
+	return &OSProxmoxRepository{
+		fs:        fs,
+		commander: commander,
+		services:  NewServiceManager(commander, osType),
+	}
+}
+
+// PatchSubscriptionCheck turns the subscription test of proxmoxlib.js into
+// one that never holds, so the dialog is not shown. It reports false when
+// the file is already patched or has no such test.
+func PatchSubscriptionCheck(script string) (string, bool) {
+	if strings.Contains(script, subscriptionNagMarker) {
+		return script, false
+	}
+	lines := strings.Split(script, "\n")
+	patched := false
+	for i, line := range lines {
+		if !subscriptionCheckPattern.MatchString(line) || !strings.Contains(line, "active") {
+			continue
+		}
+		line = strings.Replace(line, "!", "", 1)
+		lines[i] = strings.Replace(line, "active", subscriptionNagMarker, 1)
+		patched = true
+	}
+	return strings.Join(lines, "\n"), patched
+}
+
+func (r *OSProxmoxRepository) DisableSubscriptionNag() error {
+	data, err := r.fs.ReadFile(model.ProxmoxWidgetToolkitPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", model.ProxmoxWidgetToolkitPath, err)
+	}
+	if patched, changed := PatchSubscriptionCheck(string(data)); changed {
+		if err := r.fs.WriteFile(model.ProxmoxWidgetToolkitPath, []byte(patched), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", model.ProxmoxWidgetToolkitPath, err)
+		}
+	} else if !strings.Contains(string(data), subscriptionNagMarker) {
+		return fmt.Errorf("no subscription check found in %s; this Proxmox VE version is not supported", model.ProxmoxWidgetToolkitPath)
+	}
+
+	// Upgrades of proxmox-widget-toolkit restore the file
+	hook := fmt.Sprintf(`// Managed by hardn: keep the subscription dialog out of the Proxmox VE web UI
+DPkg::Post-Invoke { "if ! dpkg -V proxmox-widget-toolkit | grep -q '/proxmoxlib\.js$'; then sed -i '%s' %s; fi"; };
+`, subscriptionNagSed, model.ProxmoxWidgetToolkitPath)
+	if err := r.fs.MkdirAll(filepath.Dir(model.ProxmoxNagHookPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(model.ProxmoxNagHookPath), err)
+	}
+	if err := r.fs.WriteFile(model.ProxmoxNagHookPath, []byte(hook), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", model.ProxmoxNagHookPath, err)
+	}
+	return nil
+}
+
+func (r *OSProxmoxRepository) SubscriptionNagDisabled() (bool, error) {
+	data, err := r.fs.ReadFile(model.ProxmoxWidgetToolkitPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", model.ProxmoxWidgetToolkitPath, err)
+	}
+	return strings.Contains(string(data), subscriptionNagMarker), nil
+}
+
+// ParsePveproxyDefaults reads the variables of /etc/default/pveproxy, with
+// the quotes around their values removed
+func ParsePveproxyDefaults(content string) map[string]string {
+	settings := make(map[string]string)
+	for _, line := range strings.Split(content, "\n") {
+		match := pveproxyVariablePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		settings[match[1]] = strings.Trim(strings.TrimSpace(match[2]), `"'`)
+	}
+	return settings
+}
+
+func (r *OSProxmoxRepository) ReadPveproxyDefaults() (map[string]string, error) {
+	data, err := r.fs.ReadFile(model.PveproxyDefaultsPath)
+	if err != nil {
+		if _, statErr := r.fs.Stat(model.PveproxyDefaultsPath); statErr != nil {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", model.PveproxyDefaultsPath, err)
+	}
+	return ParsePveproxyDefaults(string(data)), nil
+}
+
+// UpdatePveproxyDefaults sets variables in the content of
+// /etc/default/pveproxy: existing assignments are replaced in place, new
+// ones appended, and those with an empty value removed
+func UpdatePveproxyDefaults(content string, settings map[string]string) string {
+	pending := make(map[string]string, len(settings))
+	for key, value := range settings {
+		pending[key] = value
+	}
+
+	var lines []string
+	if content != "" {
+		lines = strings.Split(strings.TrimRight(content, "\n"), "\n")
+	}
+	var kept []string
+	for _, line := range lines {
+		match := pveproxyVariablePattern.FindStringSubmatch(line)
+		if match != nil {
+			if value, ok := pending[match[1]]; ok {
+				delete(pending, match[1])
+				if value != "" {
+					kept = append(kept, fmt.Sprintf("%s=%q", match[1], value))
+				}
+				continue
+			}
+		}
+		kept = append(kept, line)
+	}
+
+	keys := make([]string, 0, len(pending))
+	for key, value := range pending {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		kept = append(kept, "# Managed by hardn")
+		for _, key := range keys {
+			kept = append(kept, fmt.Sprintf("%s=%q", key, pending[key]))
+		}
+	}
+	return strings.Join(kept, "\n") + "\n"
+}
+
+func (r *OSProxmoxRepository) WritePveproxyDefaults(settings map[string]string) error {
+	var content string
+	if data, err := r.fs.ReadFile(model.PveproxyDefaultsPath); err == nil {
+		content = string(data)
+	}
+	if err := r.fs.WriteFile(model.PveproxyDefaultsPath, []byte(UpdatePveproxyDefaults(content, settings)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", model.PveproxyDefaultsPath, err)
+	}
+	return nil
+}
+
+func (r *OSProxmoxRepository) RestartPveproxy() error {
+	if err := r.services.Restart("pveproxy"); err != nil {
+		return fmt.Errorf("failed to restart pveproxy: %w", err)
+	}
+	return nil
+}
+
+// proxmoxRealm is an entry of pveum realm list
+type proxmoxRealm struct {
+	Realm string `json:"realm"`
+	TFA   string `json:"tfa"`
+}
+
+// ParseRealmTwoFactor returns the second factor a realm requires from the
+// JSON output of pveum realm list, or "" when it requires none
+func ParseRealmTwoFactor(output []byte, realm string) (string, error) {
+	var realms []proxmoxRealm
+	if err := json.Unmarshal(output, &realms); err != nil {
+		return "", fmt.Errorf("failed to parse the realm list: %w", err)
+	}
+	for _, entry := range realms {
+		if entry.Realm != realm {
+			continue
+		}
+		// tfa is a property string such as "type=oath,step=30"
+		for _, property := range strings.Split(entry.TFA, ",") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(property), "type="); ok {
+				return value, nil
+			}
+		}
+		return "", nil
+	}
+	return "", fmt.Errorf("realm %s not found", realm)
+}
+
+func (r *OSProxmoxRepository) GetRealmTwoFactor(realm string) (string, error) {
+	output, err := r.commander.Execute("pveum", "realm", "list", "--output-format", "json")
+	if err != nil {
+		return "", fmt.Errorf("failed to list realms: %w\nOutput: %s", err, string(output))
+	}
+	return ParseRealmTwoFactor(output, realm)
+}
+
+func (r *OSProxmoxRepository) SetRealmTwoFactor(realm, tfaType string) error {
+	if output, err := r.commander.Execute("pveum", "realm", "modify", realm, "--tfa", "type="+tfaType); err != nil {
+		return fmt.Errorf("failed to require %s for realm %s: %w\nOutput: %s", tfaType, realm, err, string(output))
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// pkg/adapter/secondary/os_reachability_repository.go
+package secondary
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// OSReachabilityRepository implements ReachabilityRepository with
+// ssh-keyscan, which completes the SSH key exchange without logging in
+type OSReachabilityRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+}
+
+// NewOSReachabilityRepository creates a new OSReachabilityRepository
+func NewOSReachabilityRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+) secondary.ReachabilityRepository {
+	return &OSReachabilityRepository{
+		fs:        fs,
+		commander: commander,
+	}
+}
+
+// ProbeSSH checks that the server at address and port hands out a host key
+func (r *OSReachabilityRepository) ProbeSSH(helper string, address string, port int, timeout time.Duration) error {
+	seconds := strconv.Itoa(max(int(timeout.Seconds()), 1))
+	keyscan := []string{"ssh-keyscan", "-T", seconds, "-p", strconv.Itoa(port), address}
+
+	command := keyscan
+	if helper != "" {
+		command = append([]string{"ssh", "-o", "BatchMode=yes", "-o", "ConnectTimeout=" + seconds, helper}, keyscan...)
+	}
+
+	output, err := r.commander.Execute(command[0], command[1:]...)
+	if err != nil {
+		return fmt.Errorf("%s failed: %w\nOutput: %s", strings.Join(command, " "), err, string(output))
+	}
+
+	// Host keys are listed as "host type key"; comments and errors go to
+	// stderr, which the output includes
+	for _, line := range strings.Split(string(output), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 3 && !strings.HasPrefix(fields[0], "#") {
+			return nil
+		}
+	}
+	return fmt.Errorf("no SSH server answered on %s port %d", address, port)
+}
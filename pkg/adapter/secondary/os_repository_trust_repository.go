@@ -0,0 +1,252 @@
+// pkg/adapter/secondary/os_repository_trust_repository.go
+package secondary
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// maxRepositoryKeySize caps a downloaded key; keyrings are a few kilobytes
+const maxRepositoryKeySize = 1024 * 1024
+
+// aptIgnoredFile matches the names APT skips in its .d directories, such as
+// backups and files left by dpkg and ucf
+var aptIgnoredFile = regexp.MustCompile(`(~|\.disabled|\.bak|\.dpkg-[a-z]+|\.ucf-[a-z]+|\.save|\.orig|\.distUpgrade)$`)
+
+// aptConfName matches the files APT reads from apt.conf.d
+var aptConfName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// OSRepositoryTrustRepository implements RepositoryTrustRepository using OS operations
+type OSRepositoryTrustRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	client    *http.Client
+	osType    string
+}
+
+// NewOSRepositoryTrustRepository creates a new OSRepositoryTrustRepository
+func NewOSRepositoryTrustRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	osType string,
+) secondary.RepositoryTrustRepository {
+	return &OSRepositoryTrustRepository{
+		fs:        fs,
+		commander: commander,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		osType:    osType,
+	}
+}
+
+// ReadSources reads sources.list, then the .list and .sources files of
+// sources.list.d in sorted order
+func (r *OSRepositoryTrustRepository) ReadSources() ([]model.AptFile, error) {
+	return readAptSources(r.fs, r.commander)
+}
+
+// ReadAptConfig reads apt.conf, then the files of apt.conf.d in sorted order
+func (r *OSRepositoryTrustRepository) ReadAptConfig() ([]model.AptFile, error) {
+	paths, err := listAptDir(r.fs, r.commander, model.AptConfDir, aptConfName.MatchString)
+	if err != nil {
+		return nil, err
+	}
+	return readAptFiles(r.fs, append([]string{model.AptConfPath}, paths...))
+}
+
+// TrustedKeys lists the keys trusted for every repository with the package
+// that installed each one. An empty apt-key keyring is left out, since
+// older releases create it without keys.
+func (r *OSRepositoryTrustRepository) TrustedKeys() ([]model.TrustedKey, error) {
+	var keys []model.TrustedKey
+
+	if r.osType == "alpine" {
+		paths, err := listAptDir(r.fs, r.commander, model.ApkKeysDir, func(string) bool { return true })
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			key := model.TrustedKey{Path: path}
+			// apk prints "PATH is owned by alpine-keys-2.4-r1"
+			if output, err := r.commander.Execute("apk", "info", "--who-owns", path); err == nil {
+				if _, owner, ok := strings.Cut(strings.TrimSpace(string(output)), " is owned by "); ok {
+					key.Owner = owner
+				}
+			}
+			keys = append(keys, key)
+		}
+		return keys, nil
+	}
+
+	if data, err := r.fs.ReadFile(model.AptLegacyKeyringPath); err == nil && len(data) > 0 {
+		keys = append(keys, model.TrustedKey{Path: model.AptLegacyKeyringPath})
+	}
+	paths, err := listAptDir(r.fs, r.commander, model.AptTrustedDir, func(name string) bool {
+		return strings.HasSuffix(name, ".gpg") || strings.HasSuffix(name, ".asc")
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		key := model.TrustedKey{Path: path}
+		// dpkg-query prints "debian-archive-keyring: PATH" and fails for files no package owns
+		if output, err := r.commander.Execute("dpkg-query", "-S", path); err == nil {
+			if owner, _, ok := strings.Cut(string(output), ": "); ok {
+				key.Owner = strings.TrimSpace(owner)
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// DownloadKey fetches a key over HTTPS
+func (r *OSRepositoryTrustRepository) DownloadKey(url string) ([]byte, error) {
+	if err := interfaces.RequireNetwork("download " + url); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "hardn-repository-keys")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRepositoryKeySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return data, nil
+}
+
+// KeyFingerprints asks gpg for the fingerprints without importing the key
+func (r *OSRepositoryTrustRepository) KeyFingerprints(key []byte) ([]string, error) {
+	if _, err := r.commander.Execute("which", "gpg"); err != nil {
+		return nil, fmt.Errorf("gpg is needed to check repository keys; install the gnupg package")
+	}
+	output, err := r.commander.ExecuteWithInput(string(key), "gpg", "--show-keys", "--with-colons")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the key with gpg: %w\nOutput: %s", err, string(output))
+	}
+	return ParseGPGFingerprints(output), nil
+}
+
+// ParseGPGFingerprints returns the fingerprint of each primary key in gpg
+// --with-colons output, where an fpr record follows each pub and sub record
+func ParseGPGFingerprints(output []byte) []string {
+	var fingerprints []string
+	primary := false
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Split(line, ":")
+		switch {
+		case fields[0] == "pub":
+			primary = true
+		case fields[0] == "sub":
+			primary = false
+		case fields[0] == "fpr" && primary && len(fields) > 9:
+			fingerprints = append(fingerprints, fields[9])
+			primary = false
+		}
+	}
+	return fingerprints
+}
+
+// InstallKey writes a key file, creating its directory
+func (r *OSRepositoryTrustRepository) InstallKey(path string, key []byte) error {
+	if err := r.fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := r.fs.WriteFile(path, key, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// WriteSources writes a repository list
+func (r *OSRepositoryTrustRepository) WriteSources(path string, data []byte) error {
+	if err := r.fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := r.fs.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// RemoveSources removes a repository list when it exists
+func (r *OSRepositoryTrustRepository) RemoveSources(path string) (bool, error) {
+	if _, err := r.fs.Stat(path); err != nil {
+		return false, nil
+	}
+	if err := r.fs.Remove(path); err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// readAptSources reads sources.list, then the .list and .sources files of
+// sources.list.d in sorted order, as APT does
+func readAptSources(fs interfaces.FileSystem, commander interfaces.Commander) ([]model.AptFile, error) {
+	paths, err := listAptDir(fs, commander, model.AptSourcesDir, func(name string) bool {
+		return strings.HasSuffix(name, ".list") || strings.HasSuffix(name, ".sources")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return readAptFiles(fs, append([]string{model.AptSourcesListPath}, paths...))
+}
+
+// listAptDir returns the sorted paths in dir whose names keep accepts,
+// leaving out the names APT ignores
+func listAptDir(fs interfaces.FileSystem, commander interfaces.Commander, dir string, keep func(name string) bool) ([]string, error) {
+	if _, err := fs.Stat(dir); err != nil {
+		return nil, nil
+	}
+	output, err := commander.Execute("find", dir, "-mindepth", "1", "-maxdepth", "1")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+
+	var paths []string
+	for _, path := range strings.Fields(string(output)) {
+		if name := filepath.Base(path); keep(name) && !aptIgnoredFile.MatchString(name) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// readAptFiles reads the files that exist
+func readAptFiles(fs interfaces.FileSystem, paths []string) ([]model.AptFile, error) {
+	var files []model.AptFile
+	for _, path := range paths {
+		if _, err := fs.Stat(path); err != nil {
+			continue
+		}
+		data, err := fs.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		files = append(files, model.AptFile{Path: path, Data: data})
+	}
+	return files, nil
+}
@@ -0,0 +1,139 @@
+// pkg/adapter/secondary/os_selftest_repository.go
+package secondary
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+const (
+	sysctlConfPath = "/etc/sysctl.conf"
+	sysctlConfDir  = "/etc/sysctl.d"
+	procSysDir     = "/proc/sys"
+)
+
+// OSSelfTestRepository implements SelfTestRepository using OS operations
+type OSSelfTestRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+}
+
+// NewOSSelfTestRepository creates a new OSSelfTestRepository
+func NewOSSelfTestRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+) secondary.SelfTestRepository {
+	return &OSSelfTestRepository{
+		fs:        fs,
+		commander: commander,
+	}
+}
+
+// ValidateSudoers checks /etc/sudoers and its drop-ins with visudo
+func (r *OSSelfTestRepository) ValidateSudoers() model.SelfTestCheck {
+	return r.validate("Sudoers", "/etc/sudoers", "visudo", "-c")
+}
+
+// ValidateSSHConfig checks the sshd configuration with sshd -t
+func (r *OSSelfTestRepository) ValidateSSHConfig() model.SelfTestCheck {
+	return r.validate("SSH", "/etc/ssh/sshd_config", "sshd", "-t")
+}
+
+// ValidateFirewall asks the installed firewall to load its rules: ufw
+// reports broken rule files from ufw status, firewalld checks its zones
+// with --check-config
+func (r *OSSelfTestRepository) ValidateFirewall() model.SelfTestCheck {
+	if r.installed("firewall-cmd") {
+		return r.validate("Firewall", "/etc/firewalld", "firewall-cmd", "--check-config")
+	}
+	return r.validate("Firewall", "/etc/ufw", "ufw", "status")
+}
+
+// ReadResolvConf returns /etc/resolv.conf, or nil when it does not exist
+func (r *OSSelfTestRepository) ReadResolvConf() ([]byte, error) {
+	if _, err := r.fs.Stat("/etc/resolv.conf"); err != nil {
+		return nil, nil
+	}
+	data, err := r.fs.ReadFile("/etc/resolv.conf")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read /etc/resolv.conf: %w", err)
+	}
+	return data, nil
+}
+
+// ReadSysctlConfigs returns /etc/sysctl.conf and the files of /etc/sysctl.d
+func (r *OSSelfTestRepository) ReadSysctlConfigs() (map[string][]byte, error) {
+	paths := []string{sysctlConfPath}
+	if output, err := r.commander.Execute("find", sysctlConfDir, "-maxdepth", "1", "-name", "*.conf"); err == nil {
+		paths = append(paths, strings.Fields(string(output))...)
+	}
+
+	configs := make(map[string][]byte)
+	for _, path := range paths {
+		if _, err := r.fs.Stat(path); err != nil {
+			continue
+		}
+		data, err := r.fs.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		configs[path] = data
+	}
+	return configs, nil
+}
+
+// SysctlKeyExists checks /proc/sys for a key. As in sysctl.d(5), dots
+// separate the path unless the key starts with a slash-separated part.
+func (r *OSSelfTestRepository) SysctlKeyExists(key string) bool {
+	_, err := r.fs.Stat(sysctlPath(key))
+	return err == nil
+}
+
+// sysctlPath returns the file of /proc/sys holding a key
+func sysctlPath(key string) string {
+	path := key
+	if i := strings.IndexAny(key, "./"); i >= 0 && key[i] == '.' {
+		path = strings.ReplaceAll(key, ".", "/")
+	}
+	return procSysDir + "/" + path
+}
+
+// validate runs a validator, skipping the check when it is not installed
+func (r *OSSelfTestRepository) validate(name, target, command string, args ...string) model.SelfTestCheck {
+	check := model.SelfTestCheck{Name: name, Target: target}
+	if !r.installed(command) {
+		check.Status = model.SelfTestSkipped
+		check.Detail = command + " is not installed"
+		return check
+	}
+
+	output, err := r.commander.Execute(command, args...)
+	if err != nil {
+		check.Status = model.SelfTestFailed
+		check.Detail = validatorComplaint(string(output), err)
+		return check
+	}
+	check.Status = model.SelfTestPassed
+	return check
+}
+
+func (r *OSSelfTestRepository) installed(command string) bool {
+	_, err := r.commander.Execute("which", command)
+	return err == nil
+}
+
+// validatorComplaint returns the first line of a validator's output that
+// says what is wrong, falling back to the error itself
+func validatorComplaint(output string, err error) string {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasSuffix(line, "parsed OK") {
+			return line
+		}
+	}
+	return err.Error()
+}
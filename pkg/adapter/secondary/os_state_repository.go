@@ -0,0 +1,114 @@
+// pkg/adapter/secondary/os_state_repository.go
+package secondary
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// OSStateRepository implements StateRepository with YAML state files and
+// the sysctl settings of /proc/sys and model.StateSysctlPath
+type OSStateRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+}
+
+// NewOSStateRepository creates a new OSStateRepository
+func NewOSStateRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+) secondary.StateRepository {
+	return &OSStateRepository{
+		fs:        fs,
+		commander: commander,
+	}
+}
+
+// ReadState parses a state file, rejecting unknown fields so a misspelled
+// setting is not silently ignored
+func (r *OSStateRepository) ReadState(path string) (*model.DesiredState, error) {
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file %s: %w", path, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var state model.DesiredState
+	if err := decoder.Decode(&state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// GetSysctl reads a key from /proc/sys
+func (r *OSStateRepository) GetSysctl(key string) (string, bool) {
+	data, err := r.fs.ReadFile(sysctlPath(key))
+	if err != nil {
+		return "", false
+	}
+	return model.NormalizeSysctlValue(string(data)), true
+}
+
+// SetSysctl sets a key in the running kernel with sysctl -w
+func (r *OSStateRepository) SetSysctl(key, value string) error {
+	if output, err := r.commander.Execute("sysctl", "-w", key+"="+value); err != nil {
+		return fmt.Errorf("failed to set %s: %w\nOutput: %s", key, err, string(output))
+	}
+	return nil
+}
+
+// ReadStateSysctl reads the "key = value" lines of model.StateSysctlPath
+func (r *OSStateRepository) ReadStateSysctl() (map[string]string, error) {
+	values := make(map[string]string)
+	if _, err := r.fs.Stat(model.StateSysctlPath); err != nil {
+		return values, nil
+	}
+	data, err := r.fs.ReadFile(model.StateSysctlPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", model.StateSysctlPath, err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			values[strings.TrimSpace(key)] = model.NormalizeSysctlValue(value)
+		}
+	}
+	return values, nil
+}
+
+// WriteStateSysctl replaces model.StateSysctlPath, so the settings are
+// applied again at boot
+func (r *OSStateRepository) WriteStateSysctl(values map[string]string) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var content strings.Builder
+	content.WriteString("# Managed by hardn: sysctl settings of the state applied with 'hardn apply'\n")
+	for _, key := range keys {
+		fmt.Fprintf(&content, "%s = %s\n", key, values[key])
+	}
+
+	if err := r.fs.MkdirAll(model.SysctlDropInDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", model.SysctlDropInDir, err)
+	}
+	if err := r.fs.WriteFile(model.StateSysctlPath, []byte(content.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", model.StateSysctlPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// pkg/adapter/secondary/os_sudoers_repository.go
+package secondary
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// maxSudoersIncludeDepth bounds how deep includes are followed, in case
+// two files include each other through different paths
+const maxSudoersIncludeDepth = 8
+
+// OSSudoersRepository implements SudoersRepository using OS operations
+type OSSudoersRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	osType    string
+}
+
+// NewOSSudoersRepository creates a new OSSudoersRepository
+func NewOSSudoersRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	osType string,
+) secondary.SudoersRepository {
+	return &OSSudoersRepository{
+		fs:        fs,
+		commander: commander,
+		osType:    osType,
+	}
+}
+
+// ReadSudoers follows the include directives of /etc/sudoers the way sudo
+// does: relative paths are relative to the including file, and a directory
+// contributes its files whose names have no dot and do not end in ~, in
+// sorted order. Without /etc/sudoers no files are returned.
+func (r *OSSudoersRepository) ReadSudoers() ([]model.SudoersFile, error) {
+	var files []model.SudoersFile
+	seen := make(map[string]bool)
+	if err := r.readSudoersFile(model.SudoersPath, 0, seen, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// readSudoersFile reads one file and then the files it includes
+func (r *OSSudoersRepository) readSudoersFile(path string, depth int, seen map[string]bool, files *[]model.SudoersFile) error {
+	if seen[path] || depth > maxSudoersIncludeDepth {
+		return nil
+	}
+	seen[path] = true
+
+	if _, err := r.fs.Stat(path); err != nil {
+		return nil
+	}
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	*files = append(*files, model.SudoersFile{Path: path, Data: data})
+
+	includes, dirs := model.SudoIncludes(data)
+	for _, dir := range dirs {
+		paths, err := r.listSudoersDir(resolveSudoersInclude(path, dir))
+		if err != nil {
+			return err
+		}
+		includes = append(includes, paths...)
+	}
+	for _, include := range includes {
+		if err := r.readSudoersFile(resolveSudoersInclude(path, include), depth+1, seen, files); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listSudoersDir returns the files sudo reads from an include directory
+func (r *OSSudoersRepository) listSudoersDir(dir string) ([]string, error) {
+	if _, err := r.fs.Stat(dir); err != nil {
+		return nil, nil
+	}
+	output, err := r.commander.Execute("find", dir, "-mindepth", "1", "-maxdepth", "1", "-type", "f")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+
+	var paths []string
+	for _, path := range strings.Fields(string(output)) {
+		name := filepath.Base(path)
+		if !strings.Contains(name, ".") && !strings.HasSuffix(name, "~") {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// resolveSudoersInclude makes an include path absolute
+func resolveSudoersInclude(from, include string) string {
+	if filepath.IsAbs(include) {
+		return include
+	}
+	return filepath.Join(filepath.Dir(from), include)
+}
+
+// ReadUserSudoers reads /etc/sudoers.d/<username>
+func (r *OSSudoersRepository) ReadUserSudoers(username string) ([]byte, error) {
+	path := filepath.Join(model.SudoersDir, username)
+	if _, err := r.fs.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sudoers file %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// WriteUserSudoers has visudo check the content first, so a mistake never
+// reaches /etc/sudoers.d where it would break sudo for every user. visudo
+// reads it from stdin, which leaves no temporary file to plant a link at.
+func (r *OSSudoersRepository) WriteUserSudoers(username string, content []byte) error {
+	if err := r.fs.MkdirAll(model.SudoersDir, 0755); err != nil {
+		return fmt.Errorf("failed to create sudoers directory: %w", err)
+	}
+
+	output, err := r.commander.ExecuteWithInput(string(content), "visudo", "-c", "-f", "-")
+	if err != nil {
+		return fmt.Errorf("invalid sudoers configuration: %w\nOutput: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	sudoersFile := filepath.Join(model.SudoersDir, username)
+	if err := r.fs.WriteFile(sudoersFile, content, 0440); err != nil {
+		return fmt.Errorf("failed to write sudoers file %s: %w", sudoersFile, err)
+	}
+	return nil
+}
+
+// InstalledCommands keeps the paths that exist
+func (r *OSSudoersRepository) InstalledCommands(paths []string) []string {
+	var installed []string
+	for _, path := range paths {
+		if _, err := r.fs.Stat(path); err == nil {
+			installed = append(installed, path)
+		}
+	}
+	return installed
+}
+
+// UserGroups lists the user's groups with id -nG
+func (r *OSSudoersRepository) UserGroups(username string) ([]string, error) {
+	output, err := r.commander.Execute("id", "-nG", username)
+	if err != nil {
+		return nil, fmt.Errorf("user %s does not exist: %w", username, err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// RemoveFromGroup uses delgroup on Alpine and gpasswd elsewhere
+func (r *OSSudoersRepository) RemoveFromGroup(username, group string) error {
+	var err error
+	if r.osType == "alpine" {
+		_, err = r.commander.Execute("delgroup", username, group)
+	} else {
+		_, err = r.commander.Execute("gpasswd", "-d", username, group)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove user %s from group %s: %w", username, group, err)
+	}
+	return nil
+}
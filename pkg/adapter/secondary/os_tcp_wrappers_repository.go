@@ -0,0 +1,180 @@
+// pkg/adapter/secondary/os_tcp_wrappers_repository.go
+package secondary
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+const (
+	// HostsAllowPath and HostsDenyPath are the TCP wrappers access files
+	HostsAllowPath = "/etc/hosts.allow"
+	HostsDenyPath  = "/etc/hosts.deny"
+
+	hostsAccessDisabledHeader = "# Rules disabled by hardn after moving them to the firewall"
+)
+
+// OSTCPWrappersRepository implements TCPWrappersRepository using OS operations
+type OSTCPWrappersRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+}
+
+// NewOSTCPWrappersRepository creates a new OSTCPWrappersRepository
+func NewOSTCPWrappersRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+) secondary.TCPWrappersRepository {
+	return &OSTCPWrappersRepository{
+		fs:        fs,
+		commander: commander,
+	}
+}
+
+// GetHostsAccessRules reads the rules of /etc/hosts.allow and /etc/hosts.deny
+func (r *OSTCPWrappersRepository) GetHostsAccessRules() ([]model.HostsAccessRule, []model.HostsAccessRule, error) {
+	allow, err := r.readRules(HostsAllowPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	deny, err := r.readRules(HostsDenyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return allow, deny, nil
+}
+
+func (r *OSTCPWrappersRepository) readRules(path string) ([]model.HostsAccessRule, error) {
+	if _, err := r.fs.Stat(path); err != nil {
+		return nil, nil
+	}
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return ParseHostsAccess(data, path), nil
+}
+
+// DaemonUsesLibwrap checks the daemon's shared libraries for libwrap.
+// OpenSSH dropped TCP wrappers support in 6.7, so sshd rules are often
+// ignored without notice.
+func (r *OSTCPWrappersRepository) DaemonUsesLibwrap(daemon string) (bool, bool) {
+	path, err := r.commander.Execute("which", daemon)
+	if err != nil || strings.TrimSpace(string(path)) == "" {
+		return false, false
+	}
+
+	// ldd fails on static binaries, which can not use libwrap either
+	output, err := r.commander.Execute("ldd", strings.TrimSpace(string(path)))
+	if err != nil {
+		return false, true
+	}
+	return strings.Contains(string(output), "libwrap.so"), true
+}
+
+// DisableHostsAccess comments out every rule, keeping the files in place
+// for the packages that own them
+func (r *OSTCPWrappersRepository) DisableHostsAccess() error {
+	for _, path := range []string{HostsAllowPath, HostsDenyPath} {
+		if _, err := r.fs.Stat(path); err != nil {
+			continue
+		}
+		data, err := r.fs.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+		changed := false
+		for i, line := range lines {
+			trimmed := strings.TrimSpace(line)
+			if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+				lines[i] = "# " + line
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		content := hostsAccessDisabledHeader + "\n" + strings.Join(lines, "\n") + "\n"
+		if err := r.fs.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// ParseHostsAccess reads the rules of a hosts_access(5) file, written as
+//
+//	daemon_list : client_list [ : option ... ]
+//
+// Lines ending in a backslash continue on the next line. Line numbers are
+// those of each rule's first line.
+func ParseHostsAccess(data []byte, file string) []model.HostsAccessRule {
+	var rules []model.HostsAccessRule
+	lines := strings.Split(string(data), "\n")
+	for i := 0; i < len(lines); i++ {
+		number := i + 1
+		line := lines[i]
+		for strings.HasSuffix(line, "\\") && i+1 < len(lines) {
+			i++
+			line = strings.TrimSuffix(line, "\\") + " " + lines[i]
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := splitHostsAccessFields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		rule := model.HostsAccessRule{
+			File:    file,
+			Line:    number,
+			Daemons: hostsAccessList(fields[0]),
+			Clients: hostsAccessList(fields[1]),
+		}
+		if len(fields) > 2 {
+			rule.Options = strings.TrimSpace(strings.Join(fields[2:], ":"))
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// splitHostsAccessFields splits a rule at colons outside the brackets of
+// IPv6 addresses
+func splitHostsAccessFields(line string) []string {
+	var fields []string
+	depth, start := 0, 0
+	for i, c := range line {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ':':
+			if depth == 0 {
+				fields = append(fields, line[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(fields, line[start:])
+}
+
+// hostsAccessList splits a daemon or client list, separated by commas or
+// whitespace
+func hostsAccessList(list string) []string {
+	return strings.FieldsFunc(list, func(c rune) bool {
+		return c == ',' || unicode.IsSpace(c)
+	})
+}
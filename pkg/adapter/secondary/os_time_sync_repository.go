@@ -0,0 +1,376 @@
+// pkg/adapter/secondary/os_time_sync_repository.go
+package secondary
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// timeSyncMarker identifies the time server configuration written by hardn
+const timeSyncMarker = "Managed by hardn"
+
+// chronySourcesMarker starts the block of chrony.conf hardn writes; the
+// block ends at the next blank line
+const chronySourcesMarker = "# " + timeSyncMarker + ": time sources"
+
+// chronySourceDirectives are the chrony.conf directives that add time
+// sources, commented out so only the configured servers are used
+var chronySourceDirectives = []string{"server", "pool", "peer", "sourcedir"}
+
+// timeSyncServices are the services of each daemon, in the order they are
+// looked for. ntp and ntpsec are the reference ntpd on Debian and Ubuntu.
+var timeSyncServices = map[string][]string{
+	model.TimeSyncChrony:    {"chronyd", "chrony"},
+	model.TimeSyncTimesyncd: {"systemd-timesyncd"},
+	model.TimeSyncNTPD:      {"ntpd", "ntp", "ntpsec"},
+}
+
+// OSTimeSyncRepository implements TimeSyncRepository with chrony,
+// systemd-timesyncd and busybox ntpd
+type OSTimeSyncRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	services  secondary.ServiceManager
+	osType    string
+}
+
+// NewOSTimeSyncRepository creates a new OSTimeSyncRepository
+func NewOSTimeSyncRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	osType string,
+) secondary.TimeSyncRepository {
+	return &OSTimeSyncRepository{
+		fs:        fs,
+		commander: commander,
+		services:  NewServiceManager(commander, osType),
+		osType:    osType,
+	}
+}
+
+// chronyConfigPath returns where chrony reads its configuration
+func (r *OSTimeSyncRepository) chronyConfigPath() string {
+	if model.IsRHELFamily(r.osType) {
+		return model.ChronyRHELConfigPath
+	}
+	return model.ChronyConfigPath
+}
+
+// chronyService returns the name of chrony's service: chrony on Debian and
+// Ubuntu, chronyd elsewhere
+func (r *OSTimeSyncRepository) chronyService() string {
+	if r.osType == "debian" || r.osType == "ubuntu" {
+		return "chrony"
+	}
+	return "chronyd"
+}
+
+// ConfigureTimeSync installs and configures the daemon of the policy,
+// stops the others and restarts it so the servers take effect
+func (r *OSTimeSyncRepository) ConfigureTimeSync(policy model.TimeSyncPolicy) error {
+	var service string
+	switch policy.Daemon {
+	case model.TimeSyncChrony:
+		if err := r.installPackage("chrony"); err != nil {
+			return err
+		}
+		path := r.chronyConfigPath()
+		conf, err := r.fs.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		updated := SetChronySources(string(conf), policy.Servers, policy.NTS)
+		if err := r.fs.WriteFile(path, []byte(updated), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		service = r.chronyService()
+
+	case model.TimeSyncTimesyncd:
+		// systemd-timesyncd is its own package since Debian 11 and Ubuntu 20.10
+		if r.osType == "debian" || r.osType == "ubuntu" {
+			if err := r.installPackage("systemd-timesyncd"); err != nil {
+				return err
+			}
+		}
+		if err := r.fs.MkdirAll("/etc/systemd/timesyncd.conf.d", 0755); err != nil {
+			return fmt.Errorf("failed to create timesyncd.conf.d: %w", err)
+		}
+		if err := r.fs.WriteFile(model.TimesyncdDropInPath, []byte(TimesyncdDropIn(policy.Servers)), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", model.TimesyncdDropInPath, err)
+		}
+		service = "systemd-timesyncd"
+
+	case model.TimeSyncNTPD:
+		if r.osType != "alpine" {
+			return fmt.Errorf("hardn configures ntpd on Alpine Linux only")
+		}
+		if err := r.fs.WriteFile(model.BusyboxNTPDConfPath, []byte(BusyboxNTPDConf(policy.Servers)), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", model.BusyboxNTPDConfPath, err)
+		}
+		service = "ntpd"
+
+	default:
+		return fmt.Errorf("unknown time synchronization daemon %q", policy.Daemon)
+	}
+
+	// Two daemons would fight over the clock
+	for _, daemon := range model.TimeSyncDaemons {
+		if daemon == policy.Daemon {
+			continue
+		}
+		for _, name := range timeSyncServices[daemon] {
+			if status, err := r.services.Status(name); err == nil && (status.Active || status.Enabled) {
+				if err := r.services.Disable(name); err != nil {
+					return fmt.Errorf("failed to stop %s: %w", name, err)
+				}
+			}
+		}
+	}
+
+	if err := r.services.Enable(service); err != nil {
+		return fmt.Errorf("failed to enable %s: %w", service, err)
+	}
+	if err := r.services.Restart(service); err != nil {
+		return fmt.Errorf("failed to restart %s: %w", service, err)
+	}
+	return nil
+}
+
+// installPackage installs a package unless it is installed
+func (r *OSTimeSyncRepository) installPackage(name string) error {
+	var output []byte
+	var err error
+	switch {
+	case r.osType == "alpine":
+		if _, err := r.commander.Execute("apk", "info", "-e", name); err == nil {
+			return nil
+		}
+		output, err = r.commander.Execute("apk", "add", name)
+	case model.IsRHELFamily(r.osType):
+		if _, err := r.commander.Execute("rpm", "-q", name); err == nil {
+			return nil
+		}
+		output, err = r.commander.Execute("dnf", "install", "-y", name)
+	default:
+		if status, _ := r.commander.Execute("dpkg-query", "-W", "-f="+DpkgStatusFormat, name); ParseDpkgInstalled(status) {
+			return nil
+		}
+		output, err = r.commander.Execute("apt-get", "install", "-y", name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to install %s: %w\nOutput: %s", name, err, string(output))
+	}
+	return nil
+}
+
+// SetChronySources comments out the time sources of chrony.conf and adds
+// the servers in a block of its own, replacing the block of an earlier run
+func SetChronySources(conf string, servers []string, nts bool) string {
+	var lines []string
+	inBlock := false
+	for _, line := range strings.Split(strings.TrimRight(conf, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == chronySourcesMarker {
+			inBlock = true
+			continue
+		}
+		if inBlock {
+			if trimmed == "" {
+				inBlock = false
+			}
+			continue
+		}
+		if fields := strings.Fields(trimmed); len(fields) > 0 && slices.Contains(chronySourceDirectives, fields[0]) {
+			line = "# " + line
+		}
+		lines = append(lines, line)
+	}
+
+	// Drop the blank lines the removed block leaves at the end
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	lines = append(lines, "", chronySourcesMarker)
+	for _, server := range servers {
+		line := "server " + server + " iburst"
+		if nts {
+			line += " nts"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// ParseChronySources returns the servers and pools chrony.conf uses and
+// whether all of them are authenticated with NTS
+func ParseChronySources(conf string) ([]string, bool) {
+	var servers []string
+	nts := true
+	for _, line := range strings.Split(conf, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || (fields[0] != "server" && fields[0] != "pool") {
+			continue
+		}
+		servers = append(servers, fields[1])
+		nts = nts && slices.Contains(fields[2:], "nts")
+	}
+	return servers, nts && len(servers) > 0
+}
+
+// TimesyncdDropIn returns the timesyncd.conf drop-in that sets the
+// servers; the empty FallbackNTP keeps the distribution's pool unused
+func TimesyncdDropIn(servers []string) string {
+	return "# " + timeSyncMarker + "\n[Time]\nNTP=" + strings.Join(servers, " ") + "\nFallbackNTP=\n"
+}
+
+// BusyboxNTPDConf returns the /etc/conf.d/ntpd that points busybox ntpd
+// at the servers
+func BusyboxNTPDConf(servers []string) string {
+	opts := []string{"-N"}
+	for _, server := range servers {
+		opts = append(opts, "-p", server)
+	}
+	return "# " + timeSyncMarker + "\nNTPD_OPTS=\"" + strings.Join(opts, " ") + "\"\n"
+}
+
+// ParseChronyTracking reads the output of chronyc tracking into status:
+// the source, stratum, offset and whether the clock is synchronized
+func ParseChronyTracking(output string, status *model.TimeSyncStatus) {
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "Reference ID":
+			// A29FC801 (time.cloudflare.com)
+			if _, name, ok := strings.Cut(value, "("); ok {
+				status.Source = strings.TrimSuffix(name, ")")
+			}
+		case "Stratum":
+			status.Stratum, _ = strconv.Atoi(value)
+		case "System time":
+			// 0.000012345 seconds fast of NTP time
+			fields := strings.Fields(value)
+			if len(fields) >= 3 {
+				offset, _ := strconv.ParseFloat(fields[0], 64)
+				if fields[2] == "slow" {
+					offset = -offset
+				}
+				status.OffsetSeconds = offset
+			}
+		case "Leap status":
+			status.Synchronized = value != "Not synchronised"
+		}
+	}
+}
+
+// ParseTimesyncStatus reads the output of timedatectl timesync-status into
+// status: the server, stratum and offset
+func ParseTimesyncStatus(output string, status *model.TimeSyncStatus) {
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "Server":
+			// 162.159.200.1 (time.cloudflare.com)
+			if _, name, ok := strings.Cut(value, "("); ok {
+				status.Source = strings.TrimSuffix(name, ")")
+			} else {
+				status.Source = value
+			}
+		case "Stratum":
+			status.Stratum, _ = strconv.Atoi(value)
+		case "Offset":
+			// -1.234ms
+			if offset, err := time.ParseDuration(value); err == nil {
+				status.OffsetSeconds = offset.Seconds()
+			}
+		}
+	}
+}
+
+// ParseAdjtimexSynchronized reports whether busybox adjtimex says the
+// kernel clock is synchronized
+func ParseAdjtimexSynchronized(output string) bool {
+	return strings.Contains(output, "clock synchronized")
+}
+
+// GetTimeSyncStatus reports the running daemon, its servers and the clock
+func (r *OSTimeSyncRepository) GetTimeSyncStatus() (*model.TimeSyncStatus, error) {
+	status := &model.TimeSyncStatus{}
+	for _, daemon := range model.TimeSyncDaemons {
+		for _, name := range timeSyncServices[daemon] {
+			if s, err := r.services.Status(name); err == nil && s.Active {
+				status.Daemon = daemon
+				break
+			}
+		}
+		if status.Daemon != "" {
+			break
+		}
+	}
+
+	switch status.Daemon {
+	case "":
+		return status, nil
+
+	case model.TimeSyncChrony:
+		if conf, err := r.fs.ReadFile(r.chronyConfigPath()); err == nil {
+			status.Servers, status.NTS = ParseChronySources(string(conf))
+			status.Managed = strings.Contains(string(conf), chronySourcesMarker)
+		}
+		output, err := r.commander.Execute("chronyc", "tracking")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chrony's tracking: %w\nOutput: %s", err, string(output))
+		}
+		ParseChronyTracking(string(output), status)
+
+	case model.TimeSyncTimesyncd:
+		if conf, err := r.fs.ReadFile(model.TimesyncdDropInPath); err == nil {
+			status.Managed = strings.Contains(string(conf), timeSyncMarker)
+			for _, line := range strings.Split(string(conf), "\n") {
+				if servers, found := strings.CutPrefix(line, "NTP="); found {
+					status.Servers = strings.Fields(servers)
+				}
+			}
+		}
+		synchronized, _ := r.commander.Execute("timedatectl", "show", "--property=NTPSynchronized", "--value")
+		status.Synchronized = strings.TrimSpace(string(synchronized)) == "yes"
+		if output, err := r.commander.Execute("timedatectl", "timesync-status"); err == nil {
+			ParseTimesyncStatus(string(output), status)
+		}
+
+	case model.TimeSyncNTPD:
+		if r.osType != "alpine" {
+			// The kernel's view, as timedatectl reports it
+			synchronized, _ := r.commander.Execute("timedatectl", "show", "--property=NTPSynchronized", "--value")
+			status.Synchronized = strings.TrimSpace(string(synchronized)) == "yes"
+			return status, nil
+		}
+		if conf, err := r.fs.ReadFile(model.BusyboxNTPDConfPath); err == nil {
+			status.Managed = strings.Contains(string(conf), timeSyncMarker)
+			fields := strings.Fields(parseShellAssignments(conf)["NTPD_OPTS"])
+			for i, field := range fields {
+				if field == "-p" && i+1 < len(fields) {
+					status.Servers = append(status.Servers, fields[i+1])
+				}
+			}
+		}
+		output, _ := r.commander.Execute("adjtimex")
+		status.Synchronized = ParseAdjtimexSynchronized(string(output))
+	}
+	return status, nil
+}
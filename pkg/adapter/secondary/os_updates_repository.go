@@ -0,0 +1,419 @@
+// pkg/adapter/secondary/os_updates_repository.go
+package secondary
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// updatesMarker identifies the upgrade configuration written by hardn
+const updatesMarker = "Managed by hardn"
+
+// Periodic options of 20auto-upgrades
+const (
+	aptPeriodicUpdateLists = "APT::Periodic::Update-Package-Lists"
+	aptPeriodicUpgrade     = "APT::Periodic::Unattended-Upgrade"
+)
+
+// Services that run the automatic upgrades
+const (
+	aptUpgradeTimer = "apt-daily-upgrade.timer"
+	alpineCrond     = "crond"
+)
+
+// OSUpdatesRepository implements UpdatesRepository with unattended-upgrades
+// on Debian and Ubuntu and a periodic script on Alpine
+type OSUpdatesRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	services  secondary.ServiceManager
+	osType    string
+}
+
+// NewOSUpdatesRepository creates a new OSUpdatesRepository
+func NewOSUpdatesRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	osType string,
+) secondary.UpdatesRepository {
+	return &OSUpdatesRepository{
+		fs:        fs,
+		commander: commander,
+		services:  NewServiceManager(commander, osType),
+		osType:    osType,
+	}
+}
+
+// ConfigureUpdates writes the upgrade policy and enables automatic upgrades
+func (r *OSUpdatesRepository) ConfigureUpdates(policy model.UpdatesPolicy) error {
+	if r.osType == "alpine" {
+		return r.writeAlpineScript(policy)
+	}
+
+	output, _ := r.commander.Execute("dpkg-query", "-W", "-f="+DpkgStatusFormat, "unattended-upgrades")
+	if !ParseDpkgInstalled(output) {
+		if output, err := r.commander.Execute("apt-get", "install", "-y", "unattended-upgrades"); err != nil {
+			return fmt.Errorf("failed to install unattended-upgrades: %w\nOutput: %s", err, string(output))
+		}
+	}
+
+	if err := r.fs.WriteFile(model.AptUnattendedUpgradesPath, []byte(UnattendedUpgradesConfig(policy)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", model.AptUnattendedUpgradesPath, err)
+	}
+	return r.writeAutoUpgrades(true)
+}
+
+// DisableUpdates turns off the daily unattended-upgrades run, keeping its
+// policy, or removes the Alpine periodic script
+func (r *OSUpdatesRepository) DisableUpdates() error {
+	if r.osType != "alpine" {
+		return r.writeAutoUpgrades(false)
+	}
+
+	data, err := r.fs.ReadFile(model.AlpineAutoUpgradeScriptPath)
+	if err != nil {
+		return nil // Nothing to remove
+	}
+	if !strings.Contains(string(data), updatesMarker) {
+		return fmt.Errorf("%s was not created by hardn; remove it manually", model.AlpineAutoUpgradeScriptPath)
+	}
+	if err := r.fs.Remove(model.AlpineAutoUpgradeScriptPath); err != nil {
+		return fmt.Errorf("failed to remove upgrade script: %w", err)
+	}
+	return nil
+}
+
+// GetUpdatesStatus reports whether automatic upgrades are enabled and
+// whether hardn wrote their configuration
+func (r *OSUpdatesRepository) GetUpdatesStatus() (*model.UpdatesStatus, error) {
+	status := &model.UpdatesStatus{}
+
+	if r.osType == "alpine" {
+		data, err := r.fs.ReadFile(model.AlpineAutoUpgradeScriptPath)
+		if err != nil {
+			return status, nil
+		}
+		// The script only runs while crond is in the default runlevel
+		crond, _ := r.services.Status(alpineCrond)
+		status.Enabled = crond.Enabled
+		status.Managed = strings.Contains(string(data), updatesMarker)
+		status.Files = []string{model.AlpineAutoUpgradeScriptPath}
+		return status, nil
+	}
+
+	if data, err := r.fs.ReadFile(model.AptUnattendedUpgradesPath); err == nil {
+		status.Files = append(status.Files, model.AptUnattendedUpgradesPath)
+		status.Managed = strings.Contains(string(data), updatesMarker)
+	}
+	data, err := r.fs.ReadFile(model.AptAutoUpgradesPath)
+	if err != nil {
+		return status, nil
+	}
+	status.Files = append(status.Files, model.AptAutoUpgradesPath)
+
+	output, _ := r.commander.Execute("dpkg-query", "-W", "-f="+DpkgStatusFormat, "unattended-upgrades")
+	interval := ParseAptPeriodic(data, aptPeriodicUpgrade)
+	// apt-daily-upgrade.timer starts the unattended upgrades
+	timer, _ := r.services.Status(aptUpgradeTimer)
+	status.Enabled = ParseDpkgInstalled(output) && interval != "" && interval != "0" && timer.Enabled
+	return status, nil
+}
+
+// AppliedUpdates reads the upgrades since the given time from apt's
+// history, including last month's rotated log, from the log of the Alpine
+// upgrade script, or from dnf's transaction log and its last rotation. A
+// missing log holds no upgrades.
+func (r *OSUpdatesRepository) AppliedUpdates(since time.Time) ([]model.AppliedUpdate, error) {
+	var updates []model.AppliedUpdate
+	switch {
+	case r.osType == "alpine":
+		data, err := r.fs.ReadFile(model.AlpineAutoUpgradeLogPath)
+		if err != nil {
+			return nil, nil
+		}
+		updates = ParseApkUpgradeLog(data)
+	case r.osType == "debian" || r.osType == "ubuntu":
+		if compressed, err := r.fs.ReadFile(model.AptHistoryLogPath + ".1.gz"); err == nil {
+			reader, err := gzip.NewReader(bytes.NewReader(compressed))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s.1.gz: %w", model.AptHistoryLogPath, err)
+			}
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s.1.gz: %w", model.AptHistoryLogPath, err)
+			}
+			updates = append(updates, ParseAptHistory(data)...)
+		}
+		if data, err := r.fs.ReadFile(model.AptHistoryLogPath); err == nil {
+			updates = append(updates, ParseAptHistory(data)...)
+		}
+	case model.IsRHELFamily(r.osType):
+		// dnf rotates its logs itself, without compressing them
+		for _, path := range []string{model.DnfRPMLogPath + ".1", model.DnfRPMLogPath} {
+			if data, err := r.fs.ReadFile(path); err == nil {
+				updates = append(updates, ParseDnfRPMLog(data)...)
+			}
+		}
+	default:
+		return nil, nil
+	}
+
+	var applied []model.AppliedUpdate
+	for _, update := range updates {
+		if !update.Time.Before(since) {
+			applied = append(applied, update)
+		}
+	}
+	return applied, nil
+}
+
+// writeAutoUpgrades turns the daily package list refresh and upgrade on or off
+func (r *OSUpdatesRepository) writeAutoUpgrades(enable bool) error {
+	value := "0"
+	if enable {
+		value = "1"
+	}
+	content := fmt.Sprintf("// %s: automatic upgrades\n%s \"%s\";\n%s \"%s\";\n",
+		updatesMarker, aptPeriodicUpdateLists, value, aptPeriodicUpgrade, value)
+	if err := r.fs.WriteFile(model.AptAutoUpgradesPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", model.AptAutoUpgradesPath, err)
+	}
+	return nil
+}
+
+// writeAlpineScript installs the daily periodic upgrade script; crond runs
+// the scripts in /etc/periodic/daily
+func (r *OSUpdatesRepository) writeAlpineScript(policy model.UpdatesPolicy) error {
+	if err := r.fs.MkdirAll(filepath.Dir(model.AlpineAutoUpgradeScriptPath), 0755); err != nil {
+		return fmt.Errorf("failed to create periodic directory: %w", err)
+	}
+	if err := r.fs.WriteFile(model.AlpineAutoUpgradeScriptPath, []byte(AlpineUpgradeScript(policy)), 0755); err != nil {
+		return fmt.Errorf("failed to write upgrade script: %w", err)
+	}
+
+	if err := r.services.Enable(alpineCrond); err != nil {
+		return fmt.Errorf("failed to enable crond: %w", err)
+	}
+	return nil
+}
+
+// UnattendedUpgradesConfig renders 50unattended-upgrades for a policy
+func UnattendedUpgradesConfig(policy model.UpdatesPolicy) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s: automatic upgrade policy\n", updatesMarker)
+
+	b.WriteString("Unattended-Upgrade::Origins-Pattern {\n")
+	for _, origin := range policy.Origins {
+		fmt.Fprintf(&b, "        \"%s\";\n", origin)
+	}
+	b.WriteString("};\n")
+
+	b.WriteString("Unattended-Upgrade::Package-Blacklist {\n")
+	for _, name := range policy.Blacklist {
+		fmt.Fprintf(&b, "        \"%s\";\n", name)
+	}
+	b.WriteString("};\n")
+
+	fmt.Fprintf(&b, "Unattended-Upgrade::Automatic-Reboot \"%t\";\n", policy.Reboot)
+	if policy.Reboot {
+		fmt.Fprintf(&b, "Unattended-Upgrade::Automatic-Reboot-Time \"%s\";\n", policy.RebootTime)
+	}
+	if policy.Mail != "" {
+		report := "on-change"
+		if policy.MailOnlyOnError {
+			report = "only-on-error"
+		}
+		fmt.Fprintf(&b, "Unattended-Upgrade::Mail \"%s\";\n", policy.Mail)
+		fmt.Fprintf(&b, "Unattended-Upgrade::MailReport \"%s\";\n", report)
+	}
+	b.WriteString("Unattended-Upgrade::Remove-Unused-Kernel-Packages \"true\";\n")
+	return b.String()
+}
+
+// alpineUpgradeBody upgrades the packages not held back, skipping the run
+// if a previous upgrade is still going. Each run is logged, mailed if
+// requested, and followed by a reboot when the running kernel's modules
+// were replaced.
+const alpineUpgradeBody = `
+if ! mkdir "$LOCK" 2>/dev/null; then
+	echo "$(date '+%Y-%m-%d %H:%M:%S') previous upgrade still running, skipping" >>"$LOG"
+	exit 0
+fi
+trap 'rmdir "$LOCK"' EXIT
+
+REPORT=$(mktemp)
+{
+	echo "=== $(date '+%Y-%m-%d %H:%M:%S') apk upgrade ==="
+	if [ -n "$HOLD" ]; then
+		apk update && apk upgrade --no-cache --ignore $HOLD
+	else
+		apk update && apk upgrade --no-cache
+	fi
+	STATUS=$?
+	echo "exit status: $STATUS"
+} >"$REPORT" 2>&1
+cat "$REPORT" >>"$LOG"
+
+if [ -n "$MAIL" ] && { [ "$STATUS" -ne 0 ] || [ "$MAIL_ONLY_ON_ERROR" != "yes" ]; }; then
+	{
+		echo "Subject: apk upgrade on $(hostname): exit status $STATUS"
+		echo
+		cat "$REPORT"
+	} | sendmail "$MAIL"
+fi
+rm -f "$REPORT"
+
+# A kernel upgrade removes the modules of the running kernel
+if [ "$REBOOT" = "yes" ] && [ "$STATUS" -eq 0 ] && [ ! -d "/lib/modules/$(uname -r)" ]; then
+	echo "$(date '+%Y-%m-%d %H:%M:%S') rebooting into the upgraded kernel" >>"$LOG"
+	reboot
+fi
+`
+
+// AlpineUpgradeScript renders the daily periodic upgrade script for a policy
+func AlpineUpgradeScript(policy model.UpdatesPolicy) string {
+	yesNo := func(value bool) string {
+		if value {
+			return "yes"
+		}
+		return "no"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "#!/bin/sh\n# %s: daily unattended package upgrades\n", updatesMarker)
+	b.WriteString("LOCK=/run/apk-upgrade.lock\n")
+	fmt.Fprintf(&b, "LOG=%s\n", model.AlpineAutoUpgradeLogPath)
+	fmt.Fprintf(&b, "HOLD=\"%s\"\n", strings.Join(policy.Blacklist, " "))
+	fmt.Fprintf(&b, "MAIL=\"%s\"\n", policy.Mail)
+	fmt.Fprintf(&b, "MAIL_ONLY_ON_ERROR=\"%s\"\n", yesNo(policy.MailOnlyOnError))
+	fmt.Fprintf(&b, "REBOOT=\"%s\"\n", yesNo(policy.Reboot))
+	b.WriteString(alpineUpgradeBody)
+	return b.String()
+}
+
+// ParseAptPeriodic returns the value an apt.conf file sets for key, e.g.
+// "1" for APT::Periodic::Unattended-Upgrade "1";
+func ParseAptPeriodic(data []byte, key string) string {
+	value := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "//") || !strings.HasPrefix(line, key+" ") {
+			continue
+		}
+		value = strings.Trim(strings.TrimSuffix(strings.TrimSpace(strings.TrimPrefix(line, key)), ";"), "\"")
+	}
+	return value
+}
+
+// Upgrades as apt's history, apk and dnf list them: "libssl3:amd64
+// (3.0.11-1, 3.0.11-2)", "(1/3) Upgrading musl (1.2.4-r1 -> 1.2.4-r2)" and
+// "2026-10-12T06:25:03+0000 SUBDEBUG Upgrade: openssl-1:3.0.7-27.el9.x86_64"
+var (
+	aptUpgradePattern = regexp.MustCompile(`([^\s,()]+) \(([^,()]+), ([^,()]+)\)`)
+	apkUpgradePattern = regexp.MustCompile(`^\(\d+/\d+\) Upgrading (\S+) \((\S+) -> (\S+)\)`)
+	dnfUpgradePattern = regexp.MustCompile(`^(\S+) SUBDEBUG (Upgrade|Upgraded): (\S+)$`)
+)
+
+// ParseAptHistory returns the upgrades of an apt history log, dated by
+// the start of the apt run that installed them
+func ParseAptHistory(data []byte) []model.AppliedUpdate {
+	var updates []model.AppliedUpdate
+	var started time.Time
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(line, "Start-Date:"); ok {
+			started, _ = time.ParseInLocation("2006-01-02 15:04:05", strings.Join(strings.Fields(value), " "), time.Local)
+			continue
+		}
+		value, ok := strings.CutPrefix(line, "Upgrade:")
+		if !ok || started.IsZero() {
+			continue
+		}
+		for _, match := range aptUpgradePattern.FindAllStringSubmatch(value, -1) {
+			name, _, _ := strings.Cut(match[1], ":")
+			updates = append(updates, model.AppliedUpdate{Time: started, Package: name, From: match[2], To: match[3]})
+		}
+	}
+	return updates
+}
+
+// ParseApkUpgradeLog returns the upgrades of the Alpine upgrade script's
+// log, dated by the header of the run that installed them
+func ParseApkUpgradeLog(data []byte) []model.AppliedUpdate {
+	var updates []model.AppliedUpdate
+	var started time.Time
+	for _, line := range strings.Split(string(data), "\n") {
+		if header, ok := strings.CutPrefix(line, "=== "); ok {
+			header = strings.TrimSuffix(header, " apk upgrade ===")
+			started, _ = time.ParseInLocation("2006-01-02 15:04:05", header, time.Local)
+			continue
+		}
+		match := apkUpgradePattern.FindStringSubmatch(line)
+		if match == nil || started.IsZero() {
+			continue
+		}
+		updates = append(updates, model.AppliedUpdate{Time: started, Package: match[1], From: match[2], To: match[3]})
+	}
+	return updates
+}
+
+// ParseDnfRPMLog returns the upgrades of dnf's transaction log. dnf logs
+// the package it installs ("Upgrade") before the one it replaces
+// ("Upgraded"); the pair is dated by the second. The action names are
+// not translated, whatever the locale.
+func ParseDnfRPMLog(data []byte) []model.AppliedUpdate {
+	var updates []model.AppliedUpdate
+	pending := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		match := dnfUpgradePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		name, version, ok := splitNEVRA(match[3])
+		if !ok {
+			continue
+		}
+		if match[2] == "Upgrade" {
+			pending[name] = version
+			continue
+		}
+		to, ok := pending[name]
+		if !ok {
+			continue
+		}
+		delete(pending, name)
+		logged, err := time.Parse("2006-01-02T15:04:05-0700", match[1])
+		if err != nil {
+			continue
+		}
+		updates = append(updates, model.AppliedUpdate{Time: logged, Package: name, From: version, To: to})
+	}
+	return updates
+}
+
+// splitNEVRA splits an RPM package name such as
+// "openssl-libs-1:3.0.7-27.el9.x86_64" into its name and its
+// epoch:version-release
+func splitNEVRA(nevra string) (string, string, bool) {
+	if dot := strings.LastIndex(nevra, "."); dot > 0 {
+		nevra = nevra[:dot]
+	}
+	release := strings.LastIndex(nevra, "-")
+	if release <= 0 {
+		return "", "", false
+	}
+	version := strings.LastIndex(nevra[:release], "-")
+	if version <= 0 {
+		return "", "", false
+	}
+	return nevra[:version], nevra[version+1:], true
+}
@@ -5,6 +5,7 @@ import (
 	"bufio"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -37,6 +38,7 @@ func getConfigForSSHKeyPath() *config.Config {
 type OSUserRepository struct {
 	fs            interfaces.FileSystem
 	commander     interfaces.Commander
+	services      portsecondary.ServiceManager
 	osType        string
 	userLoginPort secondary.UserLoginPort
 }
@@ -50,6 +52,7 @@ func NewOSUserRepository(
 	return &OSUserRepository{
 		fs:            fs,
 		commander:     commander,
+		services:      NewServiceManager(commander, osType),
 		osType:        osType,
 		userLoginPort: NewLastCommandAdapter(commander),
 	}
@@ -77,6 +80,9 @@ func (r *OSUserRepository) CreateUser(user model.User) error {
 	if exists {
 		// Configure sudo if needed
 		if user.HasSudo {
+			if err := r.addToSudoGroup(user.Username); err != nil {
+				return err
+			}
 			if err := r.ConfigureSudo(user.Username, user.SudoNoPassword); err != nil {
 				return err
 			}
@@ -107,6 +113,21 @@ func (r *OSUserRepository) CreateUser(user model.User) error {
 				return fmt.Errorf("failed to add user %s to wheel group: %w", user.Username, err)
 			}
 		}
+	} else if model.IsRHELFamily(r.osType) {
+		// adduser is a link to useradd here and takes none of the Debian
+		// flags; the account is created without a password either way
+		_, err := r.commander.Execute("useradd", "-m", user.Username)
+		if err != nil {
+			return fmt.Errorf("failed to create user %s on %s: %w", user.Username, r.osType, err)
+		}
+
+		// Add to wheel group for sudo
+		if user.HasSudo {
+			_, err := r.commander.Execute("usermod", "-aG", "wheel", user.Username)
+			if err != nil {
+				return fmt.Errorf("failed to add user %s to wheel group: %w", user.Username, err)
+			}
+		}
 	} else {
 		// Debian/Ubuntu user creation
 		_, err := r.commander.Execute("adduser", "--disabled-password", "--gecos", "", user.Username)
@@ -140,6 +161,31 @@ func (r *OSUserRepository) CreateUser(user model.User) error {
 	return nil
 }
 
+// addToSudoGroup adds an existing user to the group that grants sudo,
+// unless the user is already a member
+func (r *OSUserRepository) addToSudoGroup(username string) error {
+	sudoGroup := "sudo"
+	if r.osType == "alpine" || model.IsRHELFamily(r.osType) {
+		sudoGroup = "wheel"
+	}
+
+	if output, err := r.commander.Execute("groups", username); err == nil &&
+		slices.Contains(strings.Fields(string(output)), sudoGroup) {
+		return nil
+	}
+
+	var err error
+	if r.osType == "alpine" {
+		_, err = r.commander.Execute("addgroup", username, sudoGroup)
+	} else {
+		_, err = r.commander.Execute("usermod", "-aG", sudoGroup, username)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add user %s to %s group: %w", username, sudoGroup, err)
+	}
+	return nil
+}
+
 // GetUser retrieves basic user information
 func (r *OSUserRepository) GetUser(username string) (*model.User, error) {
 	exists, err := r.UserExists(username)
@@ -155,80 +201,142 @@ func (r *OSUserRepository) GetUser(username string) (*model.User, error) {
 
 // AddSSHKey adds an SSH key for a user
 func (r *OSUserRepository) AddSSHKey(username, publicKey string) error {
-	// Common path for SSH keys
-	var sshDir string
-	var homePath string
+	publicKey = strings.TrimSpace(publicKey)
 
-	if r.osType == "alpine" {
-		homePath = fmt.Sprintf("/home/%s", username)
-		sshDir = filepath.Join(homePath, ".ssh")
+	lines, err := r.readAuthorizedKeys(username)
+	if err != nil {
+		return err
+	}
 
-		// Create .ssh directory if it doesn't exist
-		if err := r.fs.MkdirAll(sshDir, 0700); err != nil {
-			return fmt.Errorf("failed to create SSH directory for user %s: %w", username, err)
+	// Skip keys that are already present
+	for _, line := range lines {
+		if sameSSHKey(line, publicKey) {
+			return nil
 		}
+	}
 
-		// Create authorized_keys file if it doesn't exist
-		authKeysPath := filepath.Join(sshDir, "authorized_keys")
-		authKeysExists := false
-		_, err := r.fs.Stat(authKeysPath)
-		if err == nil {
-			authKeysExists = true
-		}
+	lines = append(lines, publicKey)
+	if err := r.writeAuthorizedKeys(username, lines); err != nil {
+		return fmt.Errorf("failed to add SSH key for user %s: %w", username, err)
+	}
 
-		if authKeysExists {
-			// Read existing keys
-			existingContent, err := r.fs.ReadFile(authKeysPath)
-			if err != nil {
-				return fmt.Errorf("failed to read authorized_keys: %w", err)
-			}
+	return nil
+}
 
-			// Append new key if not already present
-			if !strings.Contains(string(existingContent), publicKey) {
-				newContent := string(existingContent)
-				if !strings.HasSuffix(newContent, "\n") {
-					newContent += "\n"
-				}
-				newContent += publicKey + "\n"
+// RemoveSSHKey removes the key with the given fingerprint from a user's authorized_keys
+func (r *OSUserRepository) RemoveSSHKey(username, fingerprint string) error {
+	lines, err := r.readAuthorizedKeys(username)
+	if err != nil {
+		return err
+	}
 
-				if err := r.fs.WriteFile(authKeysPath, []byte(newContent), 0600); err != nil {
-					return fmt.Errorf("failed to update authorized_keys: %w", err)
-				}
-			}
-		} else {
-			// Create new file
-			if err := r.fs.WriteFile(authKeysPath, []byte(publicKey+"\n"), 0600); err != nil {
-				return fmt.Errorf("failed to create authorized_keys: %w", err)
-			}
+	var kept []string
+	removed := false
+	for _, line := range lines {
+		keyFingerprint, err := model.SSHKeyFingerprint(line)
+		if err == nil && keyFingerprint == fingerprint {
+			removed = true
+			continue
 		}
+		kept = append(kept, line)
+	}
 
-		// Set correct ownership
-		_, err = r.commander.Execute("chown", "-R", fmt.Sprintf("%s:%s", username, username), sshDir)
-		if err != nil {
-			return fmt.Errorf("failed to set ownership for SSH directory: %w", err)
-		}
-	} else {
-		// Debian/Ubuntu - use su to run commands as the user
-		_, err := r.commander.Execute("su", "-", username, "-c", "mkdir -p ~/.ssh && chmod 700 ~/.ssh")
-		if err != nil {
-			return fmt.Errorf("failed to create SSH directory for user %s: %w", username, err)
-		}
+	if !removed {
+		return fmt.Errorf("no SSH key with fingerprint %s found for user %s", fingerprint, username)
+	}
 
-		// Add the key using a here-document style input
-		_, err = r.commander.ExecuteWithInput(publicKey+"\n", "su", "-", username, "-c", "cat >> ~/.ssh/authorized_keys")
-		if err != nil {
-			return fmt.Errorf("failed to add SSH key for user %s: %w", username, err)
+	if err := r.writeAuthorizedKeys(username, kept); err != nil {
+		return fmt.Errorf("failed to remove SSH key for user %s: %w", username, err)
+	}
+
+	return nil
+}
+
+// ReadImportFile reads a CSV file of users to import
+func (r *OSUserRepository) ReadImportFile(path string) ([]byte, error) {
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// GetAuthorizedKeys returns the entries of a user's authorized_keys file,
+// skipping comments
+func (r *OSUserRepository) GetAuthorizedKeys(username string) ([]string, error) {
+	lines, err := r.readAuthorizedKeys(username)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			keys = append(keys, line)
 		}
+	}
 
-		_, err = r.commander.Execute("su", "-", username, "-c", "chmod 600 ~/.ssh/authorized_keys")
-		if err != nil {
-			return fmt.Errorf("failed to set permissions for authorized_keys: %w", err)
+	return keys, nil
+}
+
+// The user owns ~/.ssh and can plant symlinks in it, so authorized_keys is
+// only read and written with the user's own permissions. A symlink then
+// reaches no further than the user could already. The new file is written
+// to a fresh temporary name and renamed over authorized_keys, which
+// replaces a symlink rather than following it.
+const (
+	readAuthorizedKeysScript = `f="$HOME/.ssh/authorized_keys"; [ -e "$f" ] || exit 0; cat "$f"`
+
+	writeAuthorizedKeysScript = `umask 077
+mkdir -p "$HOME/.ssh" && chmod 700 "$HOME/.ssh" || exit 1
+tmp=$(mktemp "$HOME/.ssh/authorized_keys.XXXXXX") || exit 1
+cat > "$tmp" && mv -f "$tmp" "$HOME/.ssh/authorized_keys" || { rm -f "$tmp"; exit 1; }`
+)
+
+// readAuthorizedKeys returns the lines of a user's authorized_keys file; a
+// missing file has none
+func (r *OSUserRepository) readAuthorizedKeys(username string) ([]string, error) {
+	data, err := r.commander.Execute("su", "-s", "/bin/sh", username, "-c", readAuthorizedKeysScript)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorized_keys of %s: %w", username, err)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
 		}
 	}
 
+	return lines, nil
+}
+
+// writeAuthorizedKeys replaces a user's authorized_keys file atomically, as
+// the user
+func (r *OSUserRepository) writeAuthorizedKeys(username string, lines []string) error {
+	content := strings.Join(lines, "\n")
+	if content != "" {
+		content += "\n"
+	}
+
+	if output, err := r.commander.ExecuteWithInput(content, "su", "-s", "/bin/sh", username, "-c", writeAuthorizedKeysScript); err != nil {
+		return fmt.Errorf("failed to replace authorized_keys: %w\nOutput: %s", err, string(output))
+	}
+
 	return nil
 }
 
+// sameSSHKey reports whether two authorized_keys entries hold the same key
+func sameSSHKey(a, b string) bool {
+	fingerprintA, errA := model.SSHKeyFingerprint(a)
+	fingerprintB, errB := model.SSHKeyFingerprint(b)
+	if errA == nil && errB == nil {
+		return fingerprintA == fingerprintB
+	}
+
+	return strings.TrimSpace(a) == strings.TrimSpace(b)
+}
+
 // Configure sudo access for a user
 func (r *OSUserRepository) ConfigureSudo(username string, noPassword bool) error {
 	// First check if the user exists
@@ -263,73 +371,156 @@ func (r *OSUserRepository) ConfigureSudo(username string, noPassword bool) error
 	return nil
 }
 
+// LockUser locks a user's password and, where shadow tools are available,
+// expires the account so SSH keys stop working as well
+func (r *OSUserRepository) LockUser(username string) error {
+	exists, err := r.UserExists(username)
+	if err != nil {
+		return fmt.Errorf("error checking user existence: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("user %s does not exist", username)
+	}
+
+	if r.osType == "alpine" {
+		// BusyBox has no usermod; OpenSSH refuses keys for locked accounts
+		if _, err := r.commander.Execute("passwd", "-l", username); err != nil {
+			return fmt.Errorf("failed to lock user %s: %w", username, err)
+		}
+		return nil
+	}
+
+	// An expiry date in 1970 disables the account for every login method
+	if _, err := r.commander.Execute("usermod", "--lock", "--expiredate", "1", username); err != nil {
+		return fmt.Errorf("failed to lock user %s: %w", username, err)
+	}
+	return nil
+}
+
 // GetNonSystemUsers retrieves non-system users on the system
 func (r *OSUserRepository) GetNonSystemUsers() ([]model.User, error) {
-	var users []model.User
+	page, err := r.ListNonSystemUsers(model.UserListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return page.Users, nil
+}
 
-	// Try to read /etc/passwd
-	data, err := r.fs.ReadFile("/etc/passwd")
+// ListNonSystemUsers enumerates non-system users through NSS (getent passwd),
+// so accounts from LDAP/SSSD are included, and returns the requested page.
+// Sudo access is only resolved for users on the returned page.
+func (r *OSUserRepository) ListNonSystemUsers(opts model.UserListOptions) (*model.UserPage, error) {
+	localData, err := r.fs.ReadFile("/etc/passwd")
 	if err != nil {
 		// Try with command if file can't be read
 		output, cmdErr := r.commander.Execute("cat", "/etc/passwd")
 		if cmdErr != nil {
 			return nil, fmt.Errorf("failed to read user information: %w", err)
 		}
-		data = output
+		localData = output
 	}
 
-	// Parse user entries
-	scanner := bufio.NewScanner(strings.NewReader(string(data)))
-	for scanner.Scan() {
-		line := scanner.Text()
-		fields := strings.Split(line, ":")
-		if len(fields) >= 7 {
-			username := fields[0]
-			uid, err := strconv.Atoi(fields[2])
-			if err != nil {
-				continue
-			}
+	localUsers := make(map[string]bool)
+	for _, fields := range parsePasswd(localData) {
+		localUsers[fields[0]] = true
+	}
 
-			// Skip system users (UID < 1000 on most systems)
-			if uid < 1000 {
-				continue
-			}
+	data := localData
+	if !opts.LocalOnly {
+		// getent walks every NSS source; fall back to the local file if it fails
+		output, err := r.commander.Execute("getent", "passwd")
+		if err == nil && len(output) > 0 {
+			data = output
+		}
+	}
 
-			// Skip system service users
-			if strings.HasSuffix(fields[6], "/nologin") ||
-				strings.HasSuffix(fields[6], "/false") ||
-				strings.HasSuffix(fields[6], "/null") {
-				continue
-			}
+	var matched []model.User
+	seen := make(map[string]bool)
+	for _, fields := range parsePasswd(data) {
+		username := fields[0]
 
-			user := model.User{
-				Username: username,
-			}
+		// NSS may return the same account from several sources
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
 
-			// Check if user has sudo access
-			hasSudo, err := r.checkUserSudo(username)
-			if err == nil {
-				user.HasSudo = hasSudo
-			}
+		uid, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+
+		// Skip system users (UID < 1000 on most systems)
+		if uid < 1000 {
+			continue
+		}
 
-			users = append(users, user)
+		// Skip system service users
+		if strings.HasSuffix(fields[6], "/nologin") ||
+			strings.HasSuffix(fields[6], "/false") ||
+			strings.HasSuffix(fields[6], "/null") {
+			continue
 		}
+
+		matched = append(matched, model.User{
+			Username: username,
+			Local:    localUsers[username],
+		})
 	}
 
-	return users, nil
+	page := &model.UserPage{
+		Offset: opts.Offset,
+		Total:  len(matched),
+	}
+
+	if opts.Offset >= len(matched) {
+		return page, nil
+	}
+	matched = matched[opts.Offset:]
+	if opts.Limit > 0 && opts.Limit < len(matched) {
+		matched = matched[:opts.Limit]
+	}
+
+	// Load group and sudoers data once for the whole page
+	sudo := r.loadSudoIndex()
+	for i := range matched {
+		matched[i].HasSudo = sudo.hasSudo(r.fs, matched[i].Username)
+	}
+	page.Users = matched
+
+	return page, nil
 }
 
-// checkUserSudo checks if a user has sudo access
-func (r *OSUserRepository) checkUserSudo(username string) (bool, error) {
-	// Check if user is in sudo or wheel group
-	groupData, err := r.fs.ReadFile("/etc/group")
-	if err != nil {
-		// Try command if file can't be read
-		output, cmdErr := r.commander.Execute("cat", "/etc/group")
-		if cmdErr != nil {
-			return false, fmt.Errorf("failed to read group information: %w", err)
+// parsePasswd splits passwd(5) formatted data into field slices
+func parsePasswd(data []byte) [][]string {
+	var entries [][]string
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) >= 7 {
+			entries = append(entries, fields)
+		}
+	}
+	return entries
+}
+
+// sudoIndex caches the data needed to decide sudo access for many users
+type sudoIndex struct {
+	groupMembers map[string]bool
+	sudoers      string
+}
+
+// loadSudoIndex reads sudo/wheel group membership and the main sudoers file once
+func (r *OSUserRepository) loadSudoIndex() *sudoIndex {
+	idx := &sudoIndex{groupMembers: make(map[string]bool)}
+
+	// getent resolves directory-backed groups; fall back to /etc/group
+	groupData, err := r.commander.Execute("getent", "group", "sudo", "wheel")
+	if err != nil || len(groupData) == 0 {
+		groupData, err = r.fs.ReadFile("/etc/group")
+		if err != nil {
+			groupData, _ = r.commander.Execute("cat", "/etc/group")
 		}
-		groupData = output
 	}
 
 	scanner := bufio.NewScanner(strings.NewReader(string(groupData)))
@@ -338,30 +529,47 @@ func (r *OSUserRepository) checkUserSudo(username string) (bool, error) {
 		if strings.HasPrefix(line, "sudo:") || strings.HasPrefix(line, "wheel:") {
 			fields := strings.Split(line, ":")
 			if len(fields) >= 4 {
-				users := strings.Split(fields[3], ",")
-				for _, user := range users {
-					if user == username {
-						return true, nil
+				for _, user := range strings.Split(fields[3], ",") {
+					if user != "" {
+						idx.groupMembers[user] = true
 					}
 				}
 			}
 		}
 	}
 
-	// Check sudoers file
-	sudoersFile := filepath.Join("/etc/sudoers.d", username)
-	_, err = r.fs.Stat(sudoersFile)
-	if err == nil {
-		return true, nil
+	if data, err := r.fs.ReadFile("/etc/sudoers"); err == nil {
+		idx.sudoers = string(data)
+	}
+
+	return idx
+}
+
+// hasSudo reports whether the user has sudo access according to the index
+func (idx *sudoIndex) hasSudo(fs interfaces.FileSystem, username string) bool {
+	if idx.groupMembers[username] {
+		return true
 	}
 
-	// Check main sudoers file
-	output, err := r.commander.Execute("grep", username, "/etc/sudoers")
-	if err == nil && len(output) > 0 {
-		return true, nil
+	// Check sudoers drop-in file
+	if _, err := fs.Stat(filepath.Join("/etc/sudoers.d", username)); err == nil {
+		return true
+	}
+
+	// Check main sudoers file for a rule naming the user
+	scanner := bufio.NewScanner(strings.NewReader(idx.sudoers))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == username {
+			return true
+		}
 	}
 
-	return false, nil
+	return false
 }
 
 // GetNonSystemGroups retrieves non-system groups on the system
@@ -515,7 +723,7 @@ func (r *OSUserRepository) GetExtendedUserInfo(username string) (*model.User, er
 
 	// Check sudo group membership
 	sudoGroup := "sudo"
-	if r.osType == "alpine" {
+	if r.osType == "alpine" || model.IsRHELFamily(r.osType) {
 		sudoGroup = "wheel"
 	}
 
@@ -599,3 +807,74 @@ func (r *OSUserRepository) GetExtendedUserInfo(username string) (*model.User, er
 
 	return user, nil
 }
+
+// directoryNSSSources are nsswitch.conf passwd sources backed by a directory service
+var directoryNSSSources = map[string]bool{
+	"sss":     true,
+	"ldap":    true,
+	"winbind": true,
+}
+
+// GetDirectoryAuthStatus reads the passwd line of /etc/nsswitch.conf to detect
+// LDAP/SSSD-backed accounts and checks whether sssd is running
+func (r *OSUserRepository) GetDirectoryAuthStatus() (*model.DirectoryAuthStatus, error) {
+	status := &model.DirectoryAuthStatus{}
+
+	data, err := r.fs.ReadFile("/etc/nsswitch.conf")
+	if err != nil {
+		// No nsswitch.conf (e.g. musl on Alpine) means files-only lookup
+		return status, nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "passwd:") {
+			continue
+		}
+
+		for _, source := range strings.Fields(strings.TrimPrefix(line, "passwd:")) {
+			// Skip action items such as [NOTFOUND=return]
+			if strings.HasPrefix(source, "[") {
+				continue
+			}
+			if directoryNSSSources[source] {
+				status.Sources = append(status.Sources, source)
+			}
+		}
+	}
+
+	status.Enabled = len(status.Sources) > 0
+	if !status.Enabled {
+		return status, nil
+	}
+
+	if sssd, err := r.services.Status("sssd"); err == nil {
+		status.SSSDActive = sssd.Active
+	}
+
+	return status, nil
+}
+
+// IsDirectoryUser reports whether NSS resolves the user but /etc/passwd does not
+// define it, meaning the account comes from a directory service
+func (r *OSUserRepository) IsDirectoryUser(username string) (bool, error) {
+	output, err := r.commander.Execute("getent", "passwd", username)
+	if err != nil || len(output) == 0 {
+		// getent exits non-zero when the user is unknown
+		return false, nil
+	}
+
+	data, err := r.fs.ReadFile("/etc/passwd")
+	if err != nil {
+		return false, fmt.Errorf("failed to read user information: %w", err)
+	}
+
+	for _, fields := range parsePasswd(data) {
+		if fields[0] == username {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
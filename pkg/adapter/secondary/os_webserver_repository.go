@@ -0,0 +1,403 @@
+// pkg/adapter/secondary/os_webserver_repository.go
+package secondary
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+const (
+	nginxConfPath = "/etc/nginx/nginx.conf"
+	nginxConfDir  = "/etc/nginx/conf.d"
+	nginxHTTPDir  = "/etc/nginx/http.d" // Alpine
+
+	// nginxDisabledPrefix comments out http-level directives the snippet replaces
+	nginxDisabledPrefix = "#hardn# "
+
+	apacheDebianConfName      = "hardn-tls"
+	apacheDebianConfAvailable = "/etc/apache2/conf-available/hardn-tls.conf"
+	apacheDebianConfEnabled   = "/etc/apache2/conf-enabled/hardn-tls.conf"
+	apacheAlpineConfPath      = "/etc/apache2/conf.d/hardn-tls.conf"
+	apacheRHELConfPath        = "/etc/httpd/conf.d/hardn-tls.conf"
+
+	caddyfilePath    = "/etc/caddy/Caddyfile"
+	caddySnippetPath = "/etc/caddy/hardn-tls.caddy"
+	caddyImportLine  = "import " + caddySnippetPath
+)
+
+// nginxTLSDirectives are set by the snippet; nginx rejects a second copy at
+// the same level, so matching lines in the http block are commented out
+var nginxTLSDirectives = []string{
+	"ssl_protocols", "ssl_ciphers", "ssl_prefer_server_ciphers",
+	"ssl_session_timeout", "ssl_session_cache", "ssl_session_tickets",
+	"ssl_stapling", "ssl_stapling_verify",
+}
+
+var nginxTLSSnippet = model.WebServerTLSMarker + `
+ssl_protocols TLSv1.2 TLSv1.3;
+ssl_ciphers ` + model.TLSBaselineCiphers + `;
+ssl_prefer_server_ciphers off;
+ssl_session_timeout 1d;
+ssl_session_cache shared:HardnTLS:10m;
+ssl_session_tickets off;
+ssl_stapling on;
+ssl_stapling_verify on;
+add_header Strict-Transport-Security "max-age=` + fmt.Sprint(model.TLSBaselineHSTSMaxAge) + `" always;
+`
+
+var apacheTLSSnippet = model.WebServerTLSMarker + `
+<IfModule mod_ssl.c>
+    SSLProtocol -all +TLSv1.2 +TLSv1.3
+    SSLCipherSuite ` + model.TLSBaselineCiphers + `
+    SSLHonorCipherOrder off
+    SSLSessionTickets off
+    SSLUseStapling On
+    SSLStaplingCache "shmcb:/run/apache2/ssl_stapling(32768)"
+</IfModule>
+<IfModule mod_headers.c>
+    Header always set Strict-Transport-Security "max-age=` + fmt.Sprint(model.TLSBaselineHSTSMaxAge) + `" "expr=%{HTTPS} == 'on'"
+</IfModule>
+`
+
+// Caddy already staples OCSP and prefers modern ciphers; sites opt in to
+// the protocol floor and HSTS with "import hardn_tls"
+var caddyTLSSnippet = model.WebServerTLSMarker + `
+(hardn_tls) {
+	tls {
+		protocols tls1.2 tls1.3
+	}
+	header Strict-Transport-Security "max-age=` + fmt.Sprint(model.TLSBaselineHSTSMaxAge) + `"
+}
+`
+
+// OSWebServerRepository implements WebServerRepository for nginx, Apache and Caddy
+type OSWebServerRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	osType    string
+}
+
+// NewOSWebServerRepository creates a new OSWebServerRepository
+func NewOSWebServerRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	osType string,
+) secondary.WebServerRepository {
+	return &OSWebServerRepository{
+		fs:        fs,
+		commander: commander,
+		osType:    osType,
+	}
+}
+
+// DetectWebServers returns the supported web servers installed on the host
+func (r *OSWebServerRepository) DetectWebServers() ([]model.WebServer, error) {
+	var servers []model.WebServer
+
+	if r.installed("nginx") {
+		snippet := filepath.Join(r.nginxSnippetDir(), "hardn-tls.conf")
+		servers = append(servers, model.WebServer{
+			Name:            model.WebServerNginx,
+			SnippetPath:     snippet,
+			BaselineApplied: r.isManaged(snippet),
+		})
+	}
+
+	if r.installed(r.apacheCtl()) {
+		server := model.WebServer{Name: model.WebServerApache, SnippetPath: r.apacheSnippetPath()}
+		server.BaselineApplied = r.isManaged(server.SnippetPath)
+		if r.apacheUsesConfEnabled() {
+			_, err := r.fs.Stat(apacheDebianConfEnabled)
+			server.BaselineApplied = server.BaselineApplied && err == nil
+		}
+		servers = append(servers, server)
+	}
+
+	if r.installed("caddy") {
+		caddyfile, _ := r.fs.ReadFile(caddyfilePath)
+		servers = append(servers, model.WebServer{
+			Name:            model.WebServerCaddy,
+			SnippetPath:     caddySnippetPath,
+			BaselineApplied: r.isManaged(caddySnippetPath) && hasLine(string(caddyfile), caddyImportLine),
+		})
+	}
+
+	return servers, nil
+}
+
+// WriteTLSBaseline installs the managed TLS snippet for a web server
+func (r *OSWebServerRepository) WriteTLSBaseline(server model.WebServer) error {
+	switch server.Name {
+	case model.WebServerNginx:
+		if err := r.writeSnippet(server.SnippetPath, nginxTLSSnippet); err != nil {
+			return err
+		}
+		return r.rewriteNginxConf(disableNginxTLSDirectives)
+
+	case model.WebServerApache:
+		snippet := apacheTLSSnippet
+		if model.IsRHELFamily(r.osType) {
+			snippet = strings.Replace(snippet, "/run/apache2/", "/run/httpd/", 1)
+		}
+		if err := r.writeSnippet(server.SnippetPath, snippet); err != nil {
+			return err
+		}
+		if r.apacheUsesConfEnabled() {
+			if output, err := r.commander.Execute("a2enconf", apacheDebianConfName); err != nil {
+				return fmt.Errorf("failed to enable %s: %w\nOutput: %s", apacheDebianConfName, err, string(output))
+			}
+		}
+		return nil
+
+	case model.WebServerCaddy:
+		if err := r.writeSnippet(server.SnippetPath, caddyTLSSnippet); err != nil {
+			return err
+		}
+		data, err := r.fs.ReadFile(caddyfilePath)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", caddyfilePath, err)
+		}
+		if hasLine(string(data), caddyImportLine) {
+			return nil
+		}
+		// Snippets must be defined before the sites that import them
+		content := caddyImportLine + "\n\n" + string(data)
+		if err := r.fs.WriteFile(caddyfilePath, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to update %s: %w", caddyfilePath, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unsupported web server: %s", server.Name)
+}
+
+// RemoveTLSBaseline removes the managed TLS snippet for a web server
+func (r *OSWebServerRepository) RemoveTLSBaseline(server model.WebServer) error {
+	switch server.Name {
+	case model.WebServerNginx:
+		if err := r.removeSnippet(server.SnippetPath); err != nil {
+			return err
+		}
+		return r.rewriteNginxConf(restoreNginxTLSDirectives)
+
+	case model.WebServerApache:
+		if r.apacheUsesConfEnabled() {
+			if output, err := r.commander.Execute("a2disconf", apacheDebianConfName); err != nil {
+				return fmt.Errorf("failed to disable %s: %w\nOutput: %s", apacheDebianConfName, err, string(output))
+			}
+		}
+		return r.removeSnippet(server.SnippetPath)
+
+	case model.WebServerCaddy:
+		if data, err := r.fs.ReadFile(caddyfilePath); err == nil && hasLine(string(data), caddyImportLine) {
+			content := strings.Replace(string(data), caddyImportLine+"\n\n", "", 1)
+			content = strings.Replace(content, caddyImportLine+"\n", "", 1)
+			if err := r.fs.WriteFile(caddyfilePath, []byte(content), 0644); err != nil {
+				return fmt.Errorf("failed to update %s: %w", caddyfilePath, err)
+			}
+		}
+		return r.removeSnippet(server.SnippetPath)
+	}
+
+	return fmt.Errorf("unsupported web server: %s", server.Name)
+}
+
+// ValidateConfig checks the web server configuration
+func (r *OSWebServerRepository) ValidateConfig(server model.WebServer) (string, error) {
+	var output []byte
+	var err error
+
+	switch server.Name {
+	case model.WebServerNginx:
+		output, err = r.commander.Execute("nginx", "-t")
+	case model.WebServerApache:
+		output, err = r.commander.Execute(r.apacheCtl(), "configtest")
+	case model.WebServerCaddy:
+		output, err = r.commander.Execute("caddy", "validate", "--config", caddyfilePath, "--adapter", "caddyfile")
+	default:
+		return "", fmt.Errorf("unsupported web server: %s", server.Name)
+	}
+
+	return string(output), err
+}
+
+// ReloadWebServer reloads the web server configuration
+func (r *OSWebServerRepository) ReloadWebServer(server model.WebServer) error {
+	var output []byte
+	var err error
+
+	switch server.Name {
+	case model.WebServerNginx:
+		output, err = r.commander.Execute("nginx", "-s", "reload")
+	case model.WebServerApache:
+		output, err = r.commander.Execute(r.apacheCtl(), "graceful")
+	case model.WebServerCaddy:
+		output, err = r.commander.Execute("caddy", "reload", "--config", caddyfilePath, "--adapter", "caddyfile")
+	default:
+		return fmt.Errorf("unsupported web server: %s", server.Name)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// installed checks whether a binary is on PATH
+func (r *OSWebServerRepository) installed(binary string) bool {
+	_, err := r.commander.Execute("which", binary)
+	return err == nil
+}
+
+// apacheCtl returns Apache's control program for the distribution
+func (r *OSWebServerRepository) apacheCtl() string {
+	if r.osType == "alpine" || model.IsRHELFamily(r.osType) {
+		return "apachectl"
+	}
+	return "apache2ctl"
+}
+
+// apacheSnippetPath returns where the Apache TLS snippet is written
+func (r *OSWebServerRepository) apacheSnippetPath() string {
+	switch {
+	case r.osType == "alpine":
+		return apacheAlpineConfPath
+	case model.IsRHELFamily(r.osType):
+		return apacheRHELConfPath
+	}
+	return apacheDebianConfAvailable
+}
+
+// apacheUsesConfEnabled reports whether snippets are enabled with
+// a2enconf; Alpine and the RHEL family include conf.d directly
+func (r *OSWebServerRepository) apacheUsesConfEnabled() bool {
+	return r.osType != "alpine" && !model.IsRHELFamily(r.osType)
+}
+
+// nginxSnippetDir returns the directory included in nginx's http block
+func (r *OSWebServerRepository) nginxSnippetDir() string {
+	if _, err := r.fs.Stat(nginxHTTPDir); err == nil {
+		return nginxHTTPDir
+	}
+	return nginxConfDir
+}
+
+// isManaged checks whether path is a snippet written by hardn
+func (r *OSWebServerRepository) isManaged(path string) bool {
+	data, err := r.fs.ReadFile(path)
+	return err == nil && strings.HasPrefix(string(data), model.WebServerTLSMarker)
+}
+
+// writeSnippet writes a managed snippet, refusing to replace a file hardn
+// did not create
+func (r *OSWebServerRepository) writeSnippet(path, content string) error {
+	if _, err := r.fs.Stat(path); err == nil && !r.isManaged(path) {
+		return fmt.Errorf("%s was not created by hardn; remove it first", path)
+	}
+
+	if err := r.fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	if err := r.fs.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// removeSnippet removes a snippet if hardn created it
+func (r *OSWebServerRepository) removeSnippet(path string) error {
+	if !r.isManaged(path) {
+		return nil
+	}
+	if err := r.fs.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	return nil
+}
+
+// rewriteNginxConf applies edit to nginx.conf, writing only on change
+func (r *OSWebServerRepository) rewriteNginxConf(edit func(string) string) error {
+	data, err := r.fs.ReadFile(nginxConfPath)
+	if err != nil {
+		return nil // Nothing to reconcile with
+	}
+
+	updated := edit(string(data))
+	if updated == string(data) {
+		return nil
+	}
+
+	if err := r.fs.WriteFile(nginxConfPath, []byte(updated), 0644); err != nil {
+		return fmt.Errorf("failed to update %s: %w", nginxConfPath, err)
+	}
+	return nil
+}
+
+// disableNginxTLSDirectives comments out TLS directives set directly in the
+// http block; server and location blocks keep their own settings
+func disableNginxTLSDirectives(conf string) string {
+	lines := strings.Split(conf, "\n")
+	var blocks []string
+
+	for i, line := range lines {
+		code := line
+		if idx := strings.Index(code, "#"); idx >= 0 {
+			code = code[:idx]
+		}
+		fields := strings.Fields(code)
+
+		if len(blocks) == 1 && blocks[0] == "http" && len(fields) > 0 && isNginxTLSDirective(fields[0]) {
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			lines[i] = indent + nginxDisabledPrefix + strings.TrimLeft(line, " \t")
+			continue
+		}
+
+		for _, char := range code {
+			switch char {
+			case '{':
+				name := ""
+				if len(fields) > 0 {
+					name = fields[0]
+				}
+				blocks = append(blocks, name)
+			case '}':
+				if len(blocks) > 0 {
+					blocks = blocks[:len(blocks)-1]
+				}
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// restoreNginxTLSDirectives undoes disableNginxTLSDirectives
+func restoreNginxTLSDirectives(conf string) string {
+	return strings.ReplaceAll(conf, nginxDisabledPrefix, "")
+}
+
+// isNginxTLSDirective checks whether name is set by the TLS snippet
+func isNginxTLSDirective(name string) bool {
+	for _, directive := range nginxTLSDirectives {
+		if name == directive {
+			return true
+		}
+	}
+	return false
+}
+
+// hasLine checks whether content contains line on its own
+func hasLine(content, line string) bool {
+	for _, existing := range strings.Split(content, "\n") {
+		if strings.TrimSpace(existing) == line {
+			return true
+		}
+	}
+	return false
+}
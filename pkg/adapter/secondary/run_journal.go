@@ -0,0 +1,672 @@
+// pkg/adapter/secondary/run_journal.go
+package secondary
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+)
+
+// ufwStateFiles are rewritten by ufw itself, so they are saved before the
+// first ufw command of a run rather than when hardn writes them
+var ufwStateFiles = []string{
+	UFWUserRulesPath,
+	UFWUser6RulesPath,
+	UFWConfPath,
+	UFWDefaultsPath,
+}
+
+// firewalldZonesDir holds the permanent zone configuration firewall-cmd
+// writes; a zone without a file here uses the distribution's default
+const firewalldZonesDir = "/etc/firewalld/zones"
+
+// systemdActions are the systemctl verbs that change a unit's state
+var systemdActions = map[string]bool{
+	"start": true, "stop": true, "restart": true, "reload": true,
+	"try-restart": true, "reload-or-restart": true,
+	"enable": true, "disable": true, "mask": true, "unmask": true,
+}
+
+// systemdEnableStates are the is-enabled states a rollback can restore
+var systemdEnableStates = map[string]string{
+	"enabled":  "enable",
+	"disabled": "disable",
+	"masked":   "mask",
+}
+
+// RunJournal records what a hardening run changes through a FileSystem
+// and Commander in a run manifest so the run can be rolled back. Files are
+// saved before their first change; packages, services, users and ufw rules
+// are recorded with the commands that undo them. Nothing is written until
+// the run changes something.
+type RunJournal struct {
+	mu        sync.Mutex
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	runsDir   string
+	exclude   []string
+	now       func() time.Time
+	manifest  model.RunManifest
+	files     map[string]int  // path to its change index
+	recorded  map[string]bool // services, users and ufw already recorded
+	err       error
+
+	// guard is called before changes to the guarded areas
+	guard      func(runID string)
+	guardAreas []string
+}
+
+// NewRunJournal creates a journal for a run. Changes below runsDir and the
+// exclude directories, such as the backup directory, are not recorded.
+func NewRunJournal(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	runsDir string,
+	description string,
+	exclude ...string,
+) *RunJournal {
+	return &RunJournal{
+		fs:        fs,
+		commander: commander,
+		runsDir:   runsDir,
+		exclude:   append([]string{runsDir}, exclude...),
+		now:       time.Now,
+		manifest:  model.RunManifest{Description: description},
+		files:     make(map[string]int),
+		recorded:  make(map[string]bool),
+	}
+}
+
+// FileSystem returns a FileSystem whose changes are recorded in the run
+func (j *RunJournal) FileSystem() interfaces.FileSystem {
+	return &journalFileSystem{FileSystem: j.fs, journal: j}
+}
+
+// Commander returns a Commander whose changes are recorded in the run
+func (j *RunJournal) Commander() interfaces.Commander {
+	return &journalCommander{Commander: j.commander, journal: j}
+}
+
+// SetVersion records the hardn version making the run
+func (j *RunJournal) SetVersion(version string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.manifest.Version = version
+}
+
+// GuardChanges calls guard with the run's ID before each change to one of
+// the areas, once the change is recorded. The guard must not make changes
+// through the journal.
+func (j *RunJournal) GuardChanges(guard func(runID string), areas ...string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.guard = guard
+	j.guardAreas = areas
+}
+
+// RunID returns the ID of the run, or "" if nothing has changed yet
+func (j *RunJournal) RunID() string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.manifest.ID
+}
+
+// Err returns the first error saving the run; changes after it may be
+// missing from the manifest
+func (j *RunJournal) Err() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.err
+}
+
+// start creates the run directory and adds the run to the index
+func (j *RunJournal) start() error {
+	if j.manifest.ID != "" {
+		return nil
+	}
+
+	started := j.now()
+	base := started.Format(model.RunIDLayout)
+	id := base
+	for n := 2; ; n++ {
+		if _, err := j.fs.Stat(filepath.Join(j.runsDir, id)); os.IsNotExist(err) {
+			break
+		}
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+
+	if err := j.fs.MkdirAll(filepath.Join(j.runsDir, id, runFilesDir), 0700); err != nil {
+		return fmt.Errorf("failed to create run directory: %w", err)
+	}
+	ids, err := readRunIndex(j.fs, j.runsDir)
+	if err != nil {
+		return err
+	}
+	if err := writeRunIndex(j.fs, j.runsDir, append(ids, id)); err != nil {
+		return fmt.Errorf("failed to update run index: %w", err)
+	}
+
+	j.manifest.ID = id
+	j.manifest.Started = started
+	return nil
+}
+
+// record adds a change to the run and saves the manifest, returning the
+// index of the change or -1 if it could not be recorded
+func (j *RunJournal) record(change model.RunChange) int {
+	if err := j.start(); err != nil {
+		j.fail(err)
+		return -1
+	}
+	j.manifest.Changes = append(j.manifest.Changes, change)
+	j.save()
+	return len(j.manifest.Changes) - 1
+}
+
+// save writes the manifest after a change
+func (j *RunJournal) save() {
+	j.manifest.Updated = j.now()
+	if err := writeRunManifest(j.fs, j.runsDir, j.manifest); err != nil {
+		j.fail(err)
+	}
+}
+
+func (j *RunJournal) fail(err error) {
+	if j.err == nil {
+		j.err = err
+	}
+}
+
+// guardChange calls the guard before a change to area
+func (j *RunJournal) guardChange(area string) {
+	if area == "" {
+		return
+	}
+	j.mu.Lock()
+	guard, id := j.guard, j.manifest.ID
+	guarded := slices.Contains(j.guardAreas, area)
+	j.mu.Unlock()
+	if guard != nil && guarded && id != "" {
+		guard(id)
+	}
+}
+
+// guardFile calls the guard before a change to path
+func (j *RunJournal) guardFile(path string) {
+	if j.excluded(path) {
+		return
+	}
+	j.guardChange(model.RunChange{Kind: model.RunChangeFile, Path: path}.Area())
+}
+
+// excluded reports whether path is below a directory that is not recorded
+func (j *RunJournal) excluded(path string) bool {
+	for _, dir := range j.exclude {
+		if dir != "" && (path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, "/")+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// captureFile saves a file before its first change in the run and returns
+// the index of its change, or -1 if the file is not recorded
+func (j *RunJournal) captureFile(path string, external bool) int {
+	path = filepath.Clean(path)
+	if j.excluded(path) {
+		return -1
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if index, ok := j.files[path]; ok {
+		return index
+	}
+
+	change := model.RunChange{Kind: model.RunChangeFile, Path: path, Summary: "created", External: external}
+	info, err := j.fs.Stat(path)
+	switch {
+	case err == nil && info.IsDir():
+		return -1
+	case err == nil:
+		data, err := j.fs.ReadFile(path)
+		if err != nil {
+			j.fail(fmt.Errorf("failed to save %s before changing it: %w", path, err))
+			return -1
+		}
+		if err := j.start(); err != nil {
+			j.fail(err)
+			return -1
+		}
+		change.Existed = true
+		change.Summary = "modified"
+		change.Mode = uint32(info.Mode().Perm())
+		if change.Mode == 0 {
+			change.Mode = 0644
+		}
+		change.Checksum = dataChecksum(data)
+		change.Backup = filepath.Join(runFilesDir, strconv.Itoa(len(j.manifest.Changes)+1))
+		if err := j.fs.WriteFile(filepath.Join(j.runsDir, j.manifest.ID, change.Backup), data, 0600); err != nil {
+			j.fail(fmt.Errorf("failed to save %s before changing it: %w", path, err))
+			return -1
+		}
+	case !os.IsNotExist(err):
+		return -1
+	}
+
+	index := j.record(change)
+	if index >= 0 {
+		j.files[path] = index
+	}
+	return index
+}
+
+// fileChanged notes what a file holds after a successful change; checksum
+// is empty when the file was removed
+func (j *RunJournal) fileChanged(index int, checksum string) {
+	if index < 0 {
+		return
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	change := &j.manifest.Changes[index]
+	change.Checksum = checksum
+	switch {
+	case checksum == "" && change.Existed:
+		change.Summary = "removed"
+	case change.Existed:
+		change.Summary = "modified"
+	default:
+		change.Summary = "created"
+	}
+	j.save()
+}
+
+// recordOnce records a change the first time key is seen in the run
+func (j *RunJournal) recordOnce(key string, change func() *model.RunChange) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.recorded[key] {
+		return
+	}
+	j.recorded[key] = true
+	if c := change(); c != nil {
+		j.record(*c)
+	}
+}
+
+// beforeCommand records the state a command is about to change and returns
+// a function to call with the command's result
+func (j *RunJournal) beforeCommand(command string, args []string) func(error) {
+	words := nonFlagArgs(args)
+	verb := ""
+	if len(words) > 0 {
+		verb = words[0]
+	}
+
+	switch {
+	case (command == "apt-get" || command == "apt") && verb == "install":
+		return j.packagesInstalled(words[1:], func(name string) bool {
+			output, err := j.commander.Execute("dpkg-query", "-W", "-f="+DpkgStatusFormat, name)
+			return err == nil && strings.Contains(string(output), "install ok installed")
+		}, "apt-get", "remove", "--yes")
+
+	case (command == "dnf" || command == "yum") && verb == "install":
+		return j.packagesInstalled(words[1:], func(name string) bool {
+			_, err := j.commander.Execute("rpm", "-q", name)
+			return err == nil
+		}, command, "remove", "-y")
+
+	case command == "apk" && verb == "add":
+		return j.packagesInstalled(words[1:], func(name string) bool {
+			_, err := j.commander.Execute("apk", "info", "-e", name)
+			return err == nil
+		}, "apk", "del")
+
+	case command == "systemctl" && systemdActions[verb]:
+		for _, unit := range words[1:] {
+			j.recordSystemdUnit(unit)
+		}
+
+	case command == "rc-service" && len(words) >= 2:
+		j.recordOpenRCService(words[0])
+
+	case command == "rc-update" && len(words) >= 2 && (verb == "add" || verb == "del"):
+		j.recordRunlevel(verb, words[1:])
+
+	case command == "adduser" || command == "useradd":
+		if len(words) > 0 {
+			return j.userCreated(command, words[len(words)-1])
+		}
+
+	case command == "ufw" && verb != "status" && verb != "version" && verb != "show" && verb != "app":
+		j.captureFirewall()
+
+	case (command == "firewall-cmd" || command == "firewall-offline-cmd") && firewalldChange(args):
+		j.captureFirewalld(args)
+
+	case command == "sh" && len(args) >= 2 && args[0] == "-c" &&
+		strings.Contains(args[1], "ufw ") && !strings.Contains(args[1], "ufw status"):
+		j.captureFirewall()
+	}
+
+	return func(error) {}
+}
+
+// commandArea returns the area a command changes, or "" if it changes
+// none, mirroring what beforeCommand records
+func commandArea(command string, args []string) string {
+	words := nonFlagArgs(args)
+	verb := ""
+	if len(words) > 0 {
+		verb = words[0]
+	}
+
+	switch {
+	case command == "ufw" && verb != "status" && verb != "version" && verb != "show" && verb != "app":
+		return model.AreaFirewall
+	case (command == "firewall-cmd" || command == "firewall-offline-cmd") && firewalldChange(args):
+		return model.AreaFirewall
+	case command == "sh" && len(args) >= 2 && args[0] == "-c" &&
+		strings.Contains(args[1], "ufw ") && !strings.Contains(args[1], "ufw status"):
+		return model.AreaFirewall
+	case command == "systemctl" && systemdActions[verb]:
+		for _, unit := range words[1:] {
+			service := model.RunChange{Kind: model.RunChangeService, Name: strings.TrimSuffix(unit, ".service")}
+			if area := service.Area(); area != "" {
+				return area
+			}
+		}
+	case command == "rc-service" && len(words) >= 2:
+		return model.RunChange{Kind: model.RunChangeService, Name: words[0]}.Area()
+	}
+	return ""
+}
+
+// packagesInstalled records the packages a successful install added
+func (j *RunJournal) packagesInstalled(names []string, installed func(string) bool, undo ...string) func(error) {
+	var added []string
+	for _, name := range names {
+		name = strings.SplitN(name, "=", 2)[0]
+		if !installed(name) {
+			added = append(added, name)
+		}
+	}
+
+	return func(err error) {
+		if err != nil {
+			return
+		}
+		for _, name := range added {
+			j.recordOnce("package:"+name, func() *model.RunChange {
+				return &model.RunChange{
+					Kind:    model.RunChangePackage,
+					Name:    name,
+					Summary: "installed",
+					Undo:    [][]string{append(append([]string{}, undo...), name)},
+				}
+			})
+		}
+	}
+}
+
+// recordSystemdUnit records whether a unit was active and enabled
+func (j *RunJournal) recordSystemdUnit(unit string) {
+	j.recordOnce("systemd:"+unit, func() *model.RunChange {
+		output, _ := j.commander.Execute("systemctl", "is-enabled", unit)
+		state := ""
+		if fields := strings.Fields(string(output)); len(fields) > 0 {
+			state = fields[0]
+		}
+		_, err := j.commander.Execute("systemctl", "is-active", "--quiet", unit)
+		active := err == nil
+
+		change := &model.RunChange{Kind: model.RunChangeService, Name: unit}
+		verb, known := systemdEnableStates[state]
+		if !known && !active {
+			// The run created the unit; it goes away with its files
+			change.Summary = "added"
+			return change
+		}
+
+		change.Undo = [][]string{{"systemctl", "daemon-reload"}}
+		if known {
+			change.Undo = append(change.Undo, []string{"systemctl", verb, unit})
+		}
+		if active {
+			change.Summary = "was running"
+			change.Undo = append(change.Undo, []string{"systemctl", "restart", unit})
+		} else {
+			change.Summary = "was stopped"
+			change.Undo = append(change.Undo, []string{"systemctl", "stop", unit})
+		}
+		if state != "" {
+			change.Summary += ", " + state
+		}
+		return change
+	})
+}
+
+// recordOpenRCService records whether an OpenRC service was running
+func (j *RunJournal) recordOpenRCService(service string) {
+	j.recordOnce("openrc:"+service, func() *model.RunChange {
+		change := &model.RunChange{Kind: model.RunChangeService, Name: service}
+		if _, err := j.commander.Execute("rc-service", service, "status"); err == nil {
+			change.Summary = "was running"
+			change.Undo = [][]string{{"rc-service", service, "restart"}}
+		} else {
+			change.Summary = "was stopped"
+			change.Undo = [][]string{{"rc-service", service, "stop"}}
+		}
+		return change
+	})
+}
+
+// recordRunlevel records a service added to or removed from a runlevel
+func (j *RunJournal) recordRunlevel(verb string, words []string) {
+	service, level := words[0], "default"
+	if len(words) > 1 {
+		level = words[1]
+	}
+
+	j.recordOnce("runlevel:"+service+":"+level, func() *model.RunChange {
+		output, _ := j.commander.Execute("rc-update", "show", level)
+		present := RunlevelHasService(string(output), service)
+
+		change := &model.RunChange{Kind: model.RunChangeService, Name: service}
+		switch {
+		case verb == "add" && !present:
+			change.Summary = "added to runlevel " + level
+			change.Undo = [][]string{{"rc-update", "del", service, level}}
+		case verb == "del" && present:
+			change.Summary = "removed from runlevel " + level
+			change.Undo = [][]string{{"rc-update", "add", service, level}}
+		default:
+			return nil
+		}
+		return change
+	})
+}
+
+// userCreated records a user account added by a successful command
+func (j *RunJournal) userCreated(command string, username string) func(error) {
+	if _, err := j.commander.Execute("id", "-u", username); err == nil {
+		return func(error) {}
+	}
+
+	undo := []string{"deluser", username}
+	if command == "useradd" {
+		undo = []string{"userdel", username}
+	}
+	return func(err error) {
+		if err != nil {
+			return
+		}
+		j.recordOnce("user:"+username, func() *model.RunChange {
+			return &model.RunChange{
+				Kind:    model.RunChangeUser,
+				Name:    username,
+				Summary: "created; the home directory is kept on rollback",
+				Undo:    [][]string{undo},
+			}
+		})
+	}
+}
+
+// captureFirewall saves the ufw rules before the run first changes them
+func (j *RunJournal) captureFirewall() {
+	j.mu.Lock()
+	done := j.recorded["ufw"]
+	j.recorded["ufw"] = true
+	j.mu.Unlock()
+	if done {
+		return
+	}
+
+	// Without ufw there is nothing to save; installing it is recorded
+	output, err := j.commander.Execute("ufw", "status")
+	if err != nil {
+		return
+	}
+
+	for _, path := range ufwStateFiles {
+		j.captureFile(path, true)
+	}
+
+	change := model.RunChange{Kind: model.RunChangeFirewall, Name: "ufw"}
+	if strings.Contains(string(output), "Status: active") {
+		change.Summary = "rules changed; was active"
+		change.Undo = [][]string{{"ufw", "reload"}}
+	} else {
+		change.Summary = "rules changed; was inactive"
+		change.Undo = [][]string{{"ufw", "--force", "disable"}}
+	}
+	j.mu.Lock()
+	j.record(change)
+	j.mu.Unlock()
+}
+
+// captureFirewalld saves the zone a firewall-cmd change is about to
+// rewrite. Undoing restarts a running firewalld so it reloads the restored
+// zone.
+func (j *RunJournal) captureFirewalld(args []string) {
+	zone := "public"
+	for _, arg := range args {
+		if value, ok := strings.CutPrefix(arg, "--zone="); ok {
+			zone = value
+		}
+	}
+	j.captureFile(filepath.Join(firewalldZonesDir, zone+".xml"), true)
+
+	j.recordOnce("firewalld", func() *model.RunChange {
+		return &model.RunChange{
+			Kind:    model.RunChangeFirewall,
+			Name:    "firewalld",
+			Summary: "rules changed",
+			Undo:    [][]string{{"systemctl", "try-restart", "firewalld"}},
+		}
+	})
+}
+
+// firewalldChange reports whether firewall-cmd arguments change the
+// permanent configuration rather than query it
+func firewalldChange(args []string) bool {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--add-") || strings.HasPrefix(arg, "--remove-") || strings.HasPrefix(arg, "--set-") {
+			return true
+		}
+	}
+	return false
+}
+
+// nonFlagArgs returns the arguments that are not options
+func nonFlagArgs(args []string) []string {
+	var words []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			words = append(words, arg)
+		}
+	}
+	return words
+}
+
+// journalFileSystem records file changes in a run
+type journalFileSystem struct {
+	interfaces.FileSystem
+	journal *RunJournal
+}
+
+func (f *journalFileSystem) WriteFile(filename string, data []byte, perm fs.FileMode) error {
+	index := f.journal.captureFile(filename, false)
+	f.journal.guardFile(filename)
+	err := f.FileSystem.WriteFile(filename, data, perm)
+	if err == nil {
+		f.journal.fileChanged(index, dataChecksum(data))
+	}
+	return err
+}
+
+func (f *journalFileSystem) Remove(name string) error {
+	index := f.journal.captureFile(name, false)
+	f.journal.guardFile(name)
+	err := f.FileSystem.Remove(name)
+	if err == nil {
+		f.journal.fileChanged(index, "")
+	}
+	return err
+}
+
+// RemoveAll records single files; directory trees are not saved
+func (f *journalFileSystem) RemoveAll(path string) error {
+	if info, err := f.FileSystem.Stat(path); err == nil && !info.IsDir() {
+		return f.Remove(path)
+	}
+	return f.FileSystem.RemoveAll(path)
+}
+
+func (f *journalFileSystem) Rename(oldpath, newpath string) error {
+	oldIndex := f.journal.captureFile(oldpath, false)
+	newIndex := f.journal.captureFile(newpath, false)
+	f.journal.guardFile(oldpath)
+	f.journal.guardFile(newpath)
+	err := f.FileSystem.Rename(oldpath, newpath)
+	if err == nil {
+		f.journal.fileChanged(oldIndex, "")
+		if checksum, err := fileChecksum(f.FileSystem, newpath); err == nil {
+			f.journal.fileChanged(newIndex, checksum)
+		}
+	}
+	return err
+}
+
+// journalCommander records the changes commands make in a run
+type journalCommander struct {
+	interfaces.Commander
+	journal *RunJournal
+}
+
+func (c *journalCommander) Execute(command string, args ...string) ([]byte, error) {
+	done := c.journal.beforeCommand(command, args)
+	c.journal.guardChange(commandArea(command, args))
+	output, err := c.Commander.Execute(command, args...)
+	done(err)
+	return output, err
+}
+
+func (c *journalCommander) ExecuteWithInput(input string, command string, args ...string) ([]byte, error) {
+	done := c.journal.beforeCommand(command, args)
+	c.journal.guardChange(commandArea(command, args))
+	output, err := c.Commander.ExecuteWithInput(input, command, args...)
+	done(err)
+	return output, err
+}
@@ -0,0 +1,77 @@
+// pkg/adapter/secondary/smtp_notifier.go
+package secondary
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// defaultSMTPPort is the submission port, which upgrades to TLS with STARTTLS
+const defaultSMTPPort = 587
+
+// SMTPNotifier implements Notifier by mailing the summary
+type SMTPNotifier struct {
+	settings model.EmailSettings
+	sendMail func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPNotifier creates a new SMTPNotifier
+func NewSMTPNotifier(settings model.EmailSettings) secondary.Notifier {
+	return &SMTPNotifier{
+		settings: settings,
+		sendMail: smtp.SendMail,
+	}
+}
+
+// Name identifies the notifier
+func (n *SMTPNotifier) Name() string {
+	return "email"
+}
+
+// Send mails the summary to every recipient. The connection is upgraded
+// with STARTTLS when the server offers it; credentials are only sent over
+// TLS or to localhost.
+func (n *SMTPNotifier) Send(summary model.RunSummary) error {
+	port := n.settings.Port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	addr := net.JoinHostPort(n.settings.Host, strconv.Itoa(port))
+
+	var auth smtp.Auth
+	if n.settings.Username != "" {
+		auth = smtp.PlainAuth("", n.settings.Username, n.settings.Password, n.settings.Host)
+	}
+
+	from := n.settings.From
+	if from == "" {
+		from = "hardn@" + summary.Hostname
+	}
+
+	message := BuildEmailMessage(from, n.settings.To, summary, time.Now())
+	if err := n.sendMail(addr, auth, from, n.settings.To, message); err != nil {
+		return fmt.Errorf("failed to send mail through %s: %w", addr, err)
+	}
+	return nil
+}
+
+// BuildEmailMessage formats the summary as a plain text mail
+func BuildEmailMessage(from string, to []string, summary model.RunSummary, date time.Time) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", summary.Subject())
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(summary.Text(), "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,172 @@
+// pkg/adapter/secondary/ssh_remote_repository.go
+package secondary
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// ssh exits with 255 when it fails itself, and a remote shell with 127
+// when the command is not found
+const (
+	sshFailureExitCode      = 255
+	commandNotFoundExitCode = 127
+)
+
+// SSHRemoteRepository implements RemoteRepository with the OpenSSH client.
+// Hosts must be in known_hosts and accept a key without a passphrase
+// prompt, from ssh-agent or an identity file.
+type SSHRemoteRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+}
+
+// NewSSHRemoteRepository creates a new SSHRemoteRepository
+func NewSSHRemoteRepository(fs interfaces.FileSystem, commander interfaces.Commander) secondary.RemoteRepository {
+	return &SSHRemoteRepository{
+		fs:        fs,
+		commander: commander,
+	}
+}
+
+// LoadInventory reads an inventory from a YAML file
+func (r *SSHRemoteRepository) LoadInventory(path string) (*model.RemoteInventory, error) {
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inventory %s: %w", path, err)
+	}
+	var inventory model.RemoteInventory
+	if err := yaml.Unmarshal(data, &inventory); err != nil {
+		return nil, fmt.Errorf("failed to parse inventory %s: %w", path, err)
+	}
+	return &inventory, nil
+}
+
+// PushBinary checks that the host runs on this host's architecture and
+// copies the binary into a directory made by mktemp, so no other user can
+// replace it before it runs
+func (r *SSHRemoteRepository) PushBinary(host model.RemoteHost, binary string) (string, error) {
+	output, err := r.ssh(host, "uname -m && mktemp -d")
+	if err != nil {
+		return "", fmt.Errorf("failed to prepare %s: %w", host.Name, err)
+	}
+	lines := strings.Fields(string(output))
+	if len(lines) != 2 {
+		return "", fmt.Errorf("unexpected output preparing %s: %s", host.Name, strings.TrimSpace(string(output)))
+	}
+	machine, dir := lines[0], lines[1]
+	if arch := osdetect.NormalizeArch(machine); arch != runtime.GOARCH {
+		_ = r.RemoveBinary(host, dir+"/hardn")
+		return "", fmt.Errorf("%s runs on %s; this hardn is built for %s", host.Name, arch, runtime.GOARCH)
+	}
+
+	remote := dir + "/hardn"
+	args := append([]string{"-q", "-p"}, r.sshOptions(host, "-P")...)
+	args = append(args, binary, scpTarget(host)+":"+remote)
+	if output, err := r.commander.Execute("scp", args...); err != nil {
+		_ = r.RemoveBinary(host, remote)
+		return "", fmt.Errorf("failed to copy hardn to %s: %w\nOutput: %s", host.Name, err, string(output))
+	}
+	return remote, nil
+}
+
+// RemoveBinary removes a pushed binary and the directory made for it
+func (r *SSHRemoteRepository) RemoveBinary(host model.RemoteHost, path string) error {
+	dir := path[:strings.LastIndex(path, "/")+1]
+	if _, err := r.ssh(host, "rm -rf "+shellQuote(dir)); err != nil {
+		return fmt.Errorf("failed to remove %s from %s: %w", dir, host.Name, err)
+	}
+	return nil
+}
+
+// RunHardn runs hardn on a host, with sudo -n for users other than root
+// so a missing sudo rule fails instead of waiting for a password
+func (r *SSHRemoteRepository) RunHardn(host model.RemoteHost, hardn string, args []string) ([]byte, int, error) {
+	words := []string{shellQuote(hardn)}
+	if host.UseSudo() {
+		words = append([]string{"sudo", "-n"}, words...)
+	}
+	for _, arg := range args {
+		words = append(words, shellQuote(arg))
+	}
+
+	output, err := r.ssh(host, strings.Join(words, " "))
+	if err == nil {
+		return output, 0, nil
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return output, -1, err
+	}
+	switch exitErr.ExitCode() {
+	case sshFailureExitCode:
+		return output, sshFailureExitCode, fmt.Errorf("ssh to %s failed: %s", host.Target(), lastLine(output))
+	case commandNotFoundExitCode:
+		return output, commandNotFoundExitCode, fmt.Errorf("%s is not installed on %s; use --push to copy this one", hardn, host.Name)
+	}
+	return output, exitErr.ExitCode(), nil
+}
+
+// ssh runs a shell command on a host
+func (r *SSHRemoteRepository) ssh(host model.RemoteHost, command string) ([]byte, error) {
+	args := append(r.sshOptions(host, "-p"), "--", host.Target(), command)
+	return r.commander.Execute("ssh", args...)
+}
+
+// sshOptions returns the options ssh and scp share; they differ only in
+// the port flag
+func (r *SSHRemoteRepository) sshOptions(host model.RemoteHost, portFlag string) []string {
+	options := []string{"-o", "BatchMode=yes", "-o", "ConnectTimeout=10", portFlag, strconv.Itoa(host.Port)}
+	if host.IdentityFile != "" {
+		options = append(options, "-i", host.IdentityFile)
+	}
+	return options
+}
+
+// scpTarget is the host as scp addresses it; IPv6 addresses need brackets
+func scpTarget(host model.RemoteHost) string {
+	address := host.Address
+	if strings.Contains(address, ":") {
+		address = "[" + address + "]"
+	}
+	if host.User == "" {
+		return address
+	}
+	return host.User + "@" + address
+}
+
+// shellQuote quotes a word for a POSIX shell
+func shellQuote(word string) string {
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+// commandLine shows a command as it would be typed, quoting the
+// arguments a shell would split or expand
+func commandLine(command string, args []string) string {
+	words := []string{command}
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"\\$`;&|<>()*?[]#~!{}") {
+			arg = shellQuote(arg)
+		}
+		words = append(words, arg)
+	}
+	return strings.Join(words, " ")
+}
+
+// lastLine returns the last non-empty line of output
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,123 @@
+// pkg/adapter/secondary/system_revert_timer_repository.go
+package secondary
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// revertWatcherScript runs the rollback command, the arguments after $2,
+// once the marker $1 is older than $2 seconds. It exits when the marker is
+// removed.
+const revertWatcherScript = `marker=$1 after=$2
+shift 2
+while [ -e "$marker" ]; do
+	if [ $(( $(date +%s) - $(date -r "$marker" +%s) )) -ge "$after" ]; then
+		rm -f "$marker" && exec "$@"
+	fi
+	sleep 5
+done`
+
+// SystemRevertTimerRepository implements RevertTimerRepository with a
+// watcher script and a marker file per run. The watcher runs as a
+// transient systemd unit, or as a detached process on Alpine.
+type SystemRevertTimerRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	markerDir string
+	osType    string
+}
+
+// NewSystemRevertTimerRepository creates a new SystemRevertTimerRepository
+func NewSystemRevertTimerRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	markerDir string,
+	osType string,
+) secondary.RevertTimerRepository {
+	return &SystemRevertTimerRepository{
+		fs:        fs,
+		commander: commander,
+		markerDir: markerDir,
+		osType:    osType,
+	}
+}
+
+// ArmRevert writes the run's marker and starts its watcher
+func (r *SystemRevertTimerRepository) ArmRevert(runID string, rollback []string, after time.Duration) error {
+	if runID == "" || filepath.Base(runID) != runID {
+		return fmt.Errorf("invalid run ID %q", runID)
+	}
+	if len(rollback) == 0 {
+		return fmt.Errorf("no rollback command for run %s", runID)
+	}
+
+	marker := r.markerPath(runID)
+	if err := r.fs.MkdirAll(r.markerDir, 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", r.markerDir, err)
+	}
+	if err := r.fs.WriteFile(marker, []byte(runID+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", marker, err)
+	}
+
+	seconds := strconv.Itoa(max(int(after.Seconds()), 1))
+	watcher := append([]string{"sh", "-c", revertWatcherScript, "hardn-revert", marker, seconds}, rollback...)
+
+	var output []byte
+	var err error
+	if r.osType == "alpine" {
+		// Detach the watcher from the session so a hangup does not kill it
+		output, err = r.commander.Execute("sh", append([]string{"-c",
+			`setsid "$@" </dev/null >/dev/null 2>&1 &`, "hardn-revert"}, watcher...)...)
+	} else {
+		output, err = r.commander.Execute("systemd-run", append([]string{
+			"--unit=" + model.SafetyUnitPrefix + runID,
+			"--description=Roll back hardn run " + runID + " unless confirmed",
+			"--collect", "--quiet",
+		}, watcher...)...)
+	}
+	if err != nil {
+		_ = r.fs.Remove(marker)
+		return fmt.Errorf("failed to start the revert timer of run %s: %w\nOutput: %s", runID, err, string(output))
+	}
+	return nil
+}
+
+// ExtendRevert rewrites the marker, which restarts the watcher's delay
+func (r *SystemRevertTimerRepository) ExtendRevert(runID string) error {
+	marker := r.markerPath(runID)
+	if _, err := r.fs.Stat(marker); os.IsNotExist(err) {
+		return fmt.Errorf("the revert timer of run %s is not armed", runID)
+	}
+	if err := r.fs.WriteFile(marker, []byte(runID+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", marker, err)
+	}
+	return nil
+}
+
+// DisarmRevert removes the marker; the watcher exits within seconds
+func (r *SystemRevertTimerRepository) DisarmRevert(runID string) (bool, error) {
+	marker := r.markerPath(runID)
+	if _, err := r.fs.Stat(marker); os.IsNotExist(err) {
+		return false, nil
+	}
+	if err := r.fs.Remove(marker); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to disarm the revert timer of run %s: %w", runID, err)
+	}
+	return true, nil
+}
+
+// markerPath returns the marker of a run's timer
+func (r *SystemRevertTimerRepository) markerPath(runID string) string {
+	return filepath.Join(r.markerDir, runID)
+}
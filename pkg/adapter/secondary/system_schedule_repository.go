@@ -0,0 +1,435 @@
+// pkg/adapter/secondary/system_schedule_repository.go
+package secondary
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// scheduleMarker identifies schedule files and crontab entries managed by hardn
+const scheduleMarker = "# Managed by hardn: interval="
+
+// scheduleCronEnd closes the hardn block in the crontab
+const scheduleCronEnd = "# End of hardn schedule"
+
+// scheduleSummaryMarker precedes the posture summary's entry in the
+// crontab block, naming its cron expression
+const scheduleSummaryMarker = "# hardn posture summary: "
+
+// scheduleCronUpdatePath tells busybox crond to reload the crontabs
+const scheduleCronUpdatePath = "/etc/crontabs/cron.update"
+
+// scheduleUnitPaths are the systemd units of the schedule and of the
+// posture summary it sends
+var scheduleUnitPaths = []string{
+	model.ScheduleServicePath,
+	model.ScheduleTimerPath,
+	model.SummaryServicePath,
+	model.SummaryTimerPath,
+}
+
+// SystemScheduleRepository implements ScheduleRepository with a systemd
+// timer, or a root crontab entry on Alpine
+type SystemScheduleRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+	services  secondary.ServiceManager
+	osType    string
+}
+
+// NewSystemScheduleRepository creates a new SystemScheduleRepository
+func NewSystemScheduleRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+	osType string,
+) secondary.ScheduleRepository {
+	return &SystemScheduleRepository{
+		fs:        fs,
+		commander: commander,
+		services:  NewServiceManager(commander, osType),
+		osType:    osType,
+	}
+}
+
+// useCron reports whether the schedule is a crontab entry rather than a timer
+func (r *SystemScheduleRepository) useCron() bool {
+	return r.osType == "alpine"
+}
+
+// InstallSchedule writes and enables the schedule, replacing any previous one
+func (r *SystemScheduleRepository) InstallSchedule(schedule model.Schedule) error {
+	if r.useCron() {
+		return r.installCron(schedule)
+	}
+	return r.installSystemd(schedule)
+}
+
+// RemoveSchedule disables and deletes the installed schedule
+func (r *SystemScheduleRepository) RemoveSchedule() error {
+	if r.useCron() {
+		return r.removeCron()
+	}
+	return r.removeSystemd()
+}
+
+// GetScheduleStatus reads the installed schedule
+func (r *SystemScheduleRepository) GetScheduleStatus() (*model.ScheduleStatus, error) {
+	if r.useCron() {
+		return r.cronStatus()
+	}
+	return r.systemdStatus()
+}
+
+func (r *SystemScheduleRepository) installSystemd(schedule model.Schedule) error {
+	for _, path := range scheduleUnitPaths {
+		if err := r.checkManaged(path); err != nil {
+			return err
+		}
+	}
+
+	var service strings.Builder
+	service.WriteString(scheduleMarker + schedule.Interval.Name + "\n")
+	service.WriteString("[Unit]\n")
+	service.WriteString("Description=hardn scheduled run\n")
+	service.WriteString("Wants=network-online.target\n")
+	service.WriteString("After=network-online.target\n\n")
+	service.WriteString("[Service]\n")
+	service.WriteString("Type=oneshot\n")
+	for _, command := range schedule.Commands {
+		service.WriteString("ExecStart=" + joinCommand(command) + "\n")
+	}
+
+	timer := scheduleMarker + schedule.Interval.Name + "\n" +
+		"[Unit]\n" +
+		"Description=Run hardn " + schedule.Interval.Name + "\n\n" +
+		"[Timer]\n" +
+		"OnCalendar=" + schedule.Interval.OnCalendar + "\n" +
+		"RandomizedDelaySec=15m\n" +
+		"Persistent=true\n\n" +
+		"[Install]\n" +
+		"WantedBy=timers.target\n"
+
+	if err := r.fs.WriteFile(model.ScheduleServicePath, []byte(service.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", model.ScheduleServicePath, err)
+	}
+	if err := r.fs.WriteFile(model.ScheduleTimerPath, []byte(timer), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", model.ScheduleTimerPath, err)
+	}
+
+	// A schedule without a summary replaces one that sent it
+	if schedule.Summary != nil {
+		if err := r.writeSummaryUnits(schedule.Summary); err != nil {
+			return err
+		}
+	} else if err := r.removeSummaryUnits(); err != nil {
+		return err
+	}
+
+	if output, err := r.commander.Execute("systemctl", "daemon-reload"); err != nil {
+		return fmt.Errorf("failed to reload systemd: %w\nOutput: %s", err, string(output))
+	}
+	if err := r.services.Enable(model.ScheduleUnitName + ".timer"); err != nil {
+		return fmt.Errorf("failed to enable %s.timer: %w", model.ScheduleUnitName, err)
+	}
+	if schedule.Summary != nil {
+		if err := r.services.Enable(model.SummaryUnitName + ".timer"); err != nil {
+			return fmt.Errorf("failed to enable %s.timer: %w", model.SummaryUnitName, err)
+		}
+	}
+	return nil
+}
+
+// writeSummaryUnits writes the service sending the posture summary and the
+// timer starting it when the summary's cron expression matches
+func (r *SystemScheduleRepository) writeSummaryUnits(summary *model.SummarySchedule) error {
+	service := scheduleMarker + summary.Cron + "\n" +
+		"[Unit]\n" +
+		"Description=hardn posture summary\n" +
+		"Wants=network-online.target\n" +
+		"After=network-online.target\n\n" +
+		"[Service]\n" +
+		"Type=oneshot\n" +
+		"ExecStart=" + joinCommand(summary.Command) + "\n"
+
+	timer := scheduleMarker + summary.Cron + "\n" +
+		"[Unit]\n" +
+		"Description=Send the hardn posture summary\n\n" +
+		"[Timer]\n" +
+		"OnCalendar=" + summary.OnCalendar + "\n" +
+		"Persistent=true\n\n" +
+		"[Install]\n" +
+		"WantedBy=timers.target\n"
+
+	if err := r.fs.WriteFile(model.SummaryServicePath, []byte(service), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", model.SummaryServicePath, err)
+	}
+	if err := r.fs.WriteFile(model.SummaryTimerPath, []byte(timer), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", model.SummaryTimerPath, err)
+	}
+	return nil
+}
+
+// removeSummaryUnits disables and deletes the posture summary's timer and
+// service, if installed
+func (r *SystemScheduleRepository) removeSummaryUnits() error {
+	if !r.anyExists(model.SummaryServicePath, model.SummaryTimerPath) {
+		return nil
+	}
+
+	// The timer may already be stopped; removing the files is what matters
+	_ = r.services.Disable(model.SummaryUnitName + ".timer")
+
+	for _, path := range []string{model.SummaryTimerPath, model.SummaryServicePath} {
+		if err := r.fs.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func (r *SystemScheduleRepository) removeSystemd() error {
+	if !r.anyExists(scheduleUnitPaths...) {
+		return nil // Nothing to remove
+	}
+	for _, path := range scheduleUnitPaths {
+		if err := r.checkManaged(path); err != nil {
+			return err
+		}
+	}
+
+	// The timer may already be stopped; removing the files is what matters
+	_ = r.services.Disable(model.ScheduleUnitName + ".timer")
+
+	for _, path := range []string{model.ScheduleTimerPath, model.ScheduleServicePath} {
+		if err := r.fs.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+	if err := r.removeSummaryUnits(); err != nil {
+		return err
+	}
+
+	if output, err := r.commander.Execute("systemctl", "daemon-reload"); err != nil {
+		return fmt.Errorf("failed to reload systemd: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+func (r *SystemScheduleRepository) systemdStatus() (*model.ScheduleStatus, error) {
+	status := &model.ScheduleStatus{Backend: model.ScheduleBackendSystemd}
+
+	timer, err := r.fs.ReadFile(model.ScheduleTimerPath)
+	if err != nil {
+		return status, nil
+	}
+	service, err := r.fs.ReadFile(model.ScheduleServicePath)
+	if err != nil {
+		return status, nil
+	}
+
+	status.Installed = true
+	status.Interval = scheduleInterval(string(timer))
+	for _, line := range strings.Split(string(service), "\n") {
+		if command, ok := strings.CutPrefix(strings.TrimSpace(line), "ExecStart="); ok {
+			status.Commands = append(status.Commands, command)
+		}
+	}
+
+	unit := model.ScheduleUnitName + ".timer"
+	if output, err := r.commander.Execute("systemctl", "is-active", unit); err == nil {
+		status.Active = strings.TrimSpace(string(output)) == "active"
+	}
+	if output, err := r.commander.Execute("systemctl", "show", unit, "--property=NextElapseUSecRealtime", "--value"); err == nil {
+		status.NextRun = strings.TrimSpace(string(output))
+	}
+
+	if timer, err := r.fs.ReadFile(model.SummaryTimerPath); err == nil {
+		status.Summary = scheduleInterval(string(timer))
+		service, _ := r.fs.ReadFile(model.SummaryServicePath)
+		for _, line := range strings.Split(string(service), "\n") {
+			if command, ok := strings.CutPrefix(strings.TrimSpace(line), "ExecStart="); ok {
+				status.SummaryCommand = command
+			}
+		}
+	}
+	return status, nil
+}
+
+func (r *SystemScheduleRepository) installCron(schedule model.Schedule) error {
+	crontab, err := r.readCrontab()
+	if err != nil {
+		return err
+	}
+
+	// Run the commands in order and append their output to the log
+	commands := make([]string, len(schedule.Commands))
+	for i, command := range schedule.Commands {
+		commands[i] = joinCommand(command)
+	}
+	entry := fmt.Sprintf("%s { %s; } >>%s 2>&1",
+		schedule.Interval.Cron, strings.Join(commands, " && "), model.ScheduleLogPath)
+
+	lines, _ := splitCronBlock(crontab)
+	lines = append(lines, scheduleMarker+schedule.Interval.Name, entry)
+	if summary := schedule.Summary; summary != nil {
+		lines = append(lines, scheduleSummaryMarker+summary.Cron, fmt.Sprintf("%s %s >>%s 2>&1",
+			summary.Cron, joinCommand(summary.Command), model.ScheduleLogPath))
+	}
+	lines = append(lines, scheduleCronEnd)
+
+	if err := r.writeCrontab(lines); err != nil {
+		return err
+	}
+
+	// Crontabs are run by crond
+	if err := r.services.Enable("crond"); err != nil {
+		return fmt.Errorf("failed to enable crond: %w", err)
+	}
+	return nil
+}
+
+func (r *SystemScheduleRepository) removeCron() error {
+	crontab, err := r.readCrontab()
+	if err != nil {
+		return err
+	}
+
+	lines, block := splitCronBlock(crontab)
+	if len(block) == 0 {
+		return nil // Nothing to remove
+	}
+	return r.writeCrontab(lines)
+}
+
+func (r *SystemScheduleRepository) cronStatus() (*model.ScheduleStatus, error) {
+	status := &model.ScheduleStatus{Backend: model.ScheduleBackendCron}
+
+	crontab, err := r.readCrontab()
+	if err != nil {
+		return nil, err
+	}
+
+	_, block := splitCronBlock(crontab)
+	if len(block) == 0 {
+		return status, nil
+	}
+
+	status.Installed = true
+	status.Interval = scheduleInterval(block[0])
+	for i := 1; i < len(block); i++ {
+		cron, ok := strings.CutPrefix(block[i], scheduleSummaryMarker)
+		if !ok {
+			status.Commands = append(status.Commands, block[i])
+			continue
+		}
+		status.Summary = cron
+		if i+1 < len(block) {
+			i++
+			status.SummaryCommand = block[i]
+		}
+	}
+	return status, nil
+}
+
+// readCrontab returns the root crontab, or "" if there is none
+func (r *SystemScheduleRepository) readCrontab() (string, error) {
+	data, err := r.fs.ReadFile(model.ScheduleCrontabPath)
+	if err != nil {
+		if _, statErr := r.fs.Stat(model.ScheduleCrontabPath); statErr != nil {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read %s: %w", model.ScheduleCrontabPath, err)
+	}
+	return string(data), nil
+}
+
+// writeCrontab replaces the root crontab and asks crond to reload it
+func (r *SystemScheduleRepository) writeCrontab(lines []string) error {
+	if err := r.fs.MkdirAll(filepath.Dir(model.ScheduleCrontabPath), 0755); err != nil {
+		return fmt.Errorf("failed to create crontab directory: %w", err)
+	}
+
+	content := strings.Join(lines, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	if err := r.fs.WriteFile(model.ScheduleCrontabPath, []byte(content), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", model.ScheduleCrontabPath, err)
+	}
+	if err := r.fs.WriteFile(scheduleCronUpdatePath, []byte("root\n"), 0600); err != nil {
+		return fmt.Errorf("failed to signal crond: %w", err)
+	}
+	return nil
+}
+
+// anyExists reports whether any of the paths exists
+func (r *SystemScheduleRepository) anyExists(paths ...string) bool {
+	for _, path := range paths {
+		if _, err := r.fs.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkManaged refuses to overwrite a file hardn did not create
+func (r *SystemScheduleRepository) checkManaged(path string) error {
+	data, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil // Missing files are fine
+	}
+	if !strings.Contains(string(data), scheduleMarker) {
+		return fmt.Errorf("%s was not created by hardn; remove it manually", path)
+	}
+	return nil
+}
+
+// splitCronBlock separates the hardn block from the rest of a crontab,
+// returning the remaining lines and the block without its end marker
+func splitCronBlock(crontab string) ([]string, []string) {
+	var lines, block []string
+	inBlock := false
+	for _, line := range strings.Split(strings.TrimRight(crontab, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, scheduleMarker):
+			inBlock = true
+			block = append(block, line)
+		case inBlock && line == scheduleCronEnd:
+			inBlock = false
+		case inBlock:
+			block = append(block, line)
+		case line != "" || len(lines) > 0:
+			lines = append(lines, line)
+		}
+	}
+	return lines, block
+}
+
+// scheduleInterval reads the interval name from the marker line
+func scheduleInterval(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if name, ok := strings.CutPrefix(line, scheduleMarker); ok {
+			return strings.TrimSpace(name)
+		}
+	}
+	return ""
+}
+
+// joinCommand renders a command line, quoting arguments the shell and
+// systemd would otherwise split
+func joinCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'\\$;&|<>(){}*?`#") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
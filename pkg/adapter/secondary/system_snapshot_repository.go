@@ -0,0 +1,302 @@
+// pkg/adapter/secondary/system_snapshot_repository.go
+package secondary
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// btrfsSnapshotDir holds writable btrfs snapshots of the root subvolume
+const btrfsSnapshotDir = "/.snapshots"
+
+// SystemSnapshotRepository implements SnapshotRepository using LVM, ZFS and btrfs tools
+type SystemSnapshotRepository struct {
+	fs        interfaces.FileSystem
+	commander interfaces.Commander
+}
+
+// NewSystemSnapshotRepository creates a new SystemSnapshotRepository
+func NewSystemSnapshotRepository(
+	fs interfaces.FileSystem,
+	commander interfaces.Commander,
+) secondary.SnapshotRepository {
+	return &SystemSnapshotRepository{
+		fs:        fs,
+		commander: commander,
+	}
+}
+
+// DetectSnapshotSupport inspects the filesystem mounted at /
+func (r *SystemSnapshotRepository) DetectSnapshotSupport() (*model.SnapshotSupport, error) {
+	support := &model.SnapshotSupport{}
+
+	// Proxmox nodes keep their cluster config under /etc/pve
+	if _, err := r.fs.Stat("/etc/pve"); err == nil {
+		support.Proxmox = true
+	}
+
+	output, err := r.commander.Execute("findmnt", "-n", "-o", "FSTYPE,SOURCE", "/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect root filesystem: %w", err)
+	}
+
+	fields := strings.Fields(string(output))
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("unexpected findmnt output: %q", strings.TrimSpace(string(output)))
+	}
+	fsType, source := fields[0], fields[1]
+
+	switch fsType {
+	case "zfs":
+		support.Supported = true
+		support.Backend = model.SnapshotBackendZFS
+		support.Target = source
+
+	case "btrfs":
+		// findmnt reports the mounted subvolume as /dev/sdX[/@]
+		subvolume := "/"
+		if start := strings.Index(source, "["); start >= 0 && strings.HasSuffix(source, "]") {
+			subvolume = source[start+1 : len(source)-1]
+		}
+		// Rollback makes the snapshot the default subvolume, which the
+		// kernel ignores when the root mount names its subvolume, as
+		// Ubuntu and Fedora installs do
+		if where := r.explicitRootSubvolume(); where != "" {
+			support.Reason = fmt.Sprintf("root is mounted from btrfs subvolume %s named in %s, so a snapshot cannot be booted by making it the default subvolume", subvolume, where)
+			return support, nil
+		}
+		support.Supported = true
+		support.Backend = model.SnapshotBackendBtrfs
+		support.Target = subvolume
+		support.RollbackNeedsReboot = true
+
+	default:
+		// Anything else can only be snapshotted through a thin logical volume
+		lvOutput, err := r.commander.Execute("lvs", "--noheadings", "-o", "vg_name,lv_name,pool_lv", source)
+		if err != nil {
+			support.Reason = fmt.Sprintf("root filesystem (%s on %s) does not support snapshots", fsType, source)
+			return support, nil
+		}
+
+		lv := strings.Fields(string(lvOutput))
+		if len(lv) < 3 {
+			support.Reason = fmt.Sprintf("root logical volume %s is not thin-provisioned", source)
+			return support, nil
+		}
+
+		support.Supported = true
+		support.Backend = model.SnapshotBackendLVMThin
+		support.Target = lv[0] + "/" + lv[1]
+		support.RollbackNeedsReboot = true
+	}
+
+	return support, nil
+}
+
+// explicitRootSubvolume returns where the root mount names its btrfs
+// subvolume, /etc/fstab or the kernel command line, or "" if it mounts the
+// default subvolume
+func (r *SystemSnapshotRepository) explicitRootSubvolume() string {
+	if data, err := r.fs.ReadFile("/etc/fstab"); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) >= 4 && !strings.HasPrefix(fields[0], "#") && fields[1] == "/" && namesSubvolume(fields[3]) {
+				return "/etc/fstab"
+			}
+		}
+	}
+	if data, err := r.fs.ReadFile("/proc/cmdline"); err == nil {
+		for _, param := range strings.Fields(string(data)) {
+			if flags, ok := strings.CutPrefix(param, "rootflags="); ok && namesSubvolume(flags) {
+				return "the kernel command line"
+			}
+		}
+	}
+	return ""
+}
+
+// namesSubvolume reports whether btrfs mount options select a subvolume
+func namesSubvolume(options string) bool {
+	for _, option := range strings.Split(options, ",") {
+		if strings.HasPrefix(option, "subvol=") || strings.HasPrefix(option, "subvolid=") {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateSnapshot snapshots the root filesystem under the given name
+func (r *SystemSnapshotRepository) CreateSnapshot(name string) (*model.Snapshot, error) {
+	support, err := r.DetectSnapshotSupport()
+	if err != nil {
+		return nil, err
+	}
+	if !support.Supported {
+		return nil, fmt.Errorf("snapshots unavailable: %s", support.Reason)
+	}
+
+	snapshot := &model.Snapshot{
+		Name:    name,
+		Backend: support.Backend,
+		Target:  support.Target,
+		Created: time.Now(),
+	}
+
+	var cmdErr error
+	var output []byte
+	switch support.Backend {
+	case model.SnapshotBackendZFS:
+		snapshot.Path = support.Target + "@" + name
+		output, cmdErr = r.commander.Execute("zfs", "snapshot", snapshot.Path)
+
+	case model.SnapshotBackendBtrfs:
+		if err := r.fs.MkdirAll(btrfsSnapshotDir, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+		}
+		// Writable so it can become the default subvolume on rollback
+		snapshot.Path = filepath.Join(btrfsSnapshotDir, name)
+		output, cmdErr = r.commander.Execute("btrfs", "subvolume", "snapshot", "/", snapshot.Path)
+
+	case model.SnapshotBackendLVMThin:
+		vg := strings.SplitN(support.Target, "/", 2)[0]
+		snapshot.Path = vg + "/" + name
+		output, cmdErr = r.commander.Execute("lvcreate", "-s", "-n", name, support.Target)
+	}
+
+	if cmdErr != nil {
+		return nil, fmt.Errorf("failed to create %s snapshot: %w\nOutput: %s", support.Backend, cmdErr, string(output))
+	}
+
+	return snapshot, nil
+}
+
+// ListSnapshots returns snapshots created by hardn, oldest first
+func (r *SystemSnapshotRepository) ListSnapshots() ([]model.Snapshot, error) {
+	support, err := r.DetectSnapshotSupport()
+	if err != nil {
+		return nil, err
+	}
+	if !support.Supported {
+		return nil, nil
+	}
+
+	var names []string
+	switch support.Backend {
+	case model.SnapshotBackendZFS:
+		output, err := r.commander.Execute("zfs", "list", "-H", "-t", "snapshot", "-o", "name", "-d", "1", support.Target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ZFS snapshots: %w", err)
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			if _, snap, ok := strings.Cut(strings.TrimSpace(line), "@"); ok {
+				names = append(names, snap)
+			}
+		}
+
+	case model.SnapshotBackendBtrfs:
+		output, err := r.commander.Execute("ls", "-1", btrfsSnapshotDir)
+		if err != nil {
+			// No snapshot directory yet means no snapshots
+			return nil, nil
+		}
+		names = strings.Fields(string(output))
+
+	case model.SnapshotBackendLVMThin:
+		vg := strings.SplitN(support.Target, "/", 2)[0]
+		output, err := r.commander.Execute("lvs", "--noheadings", "-o", "lv_name", vg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list LVM snapshots: %w", err)
+		}
+		names = strings.Fields(string(output))
+	}
+
+	var snapshots []model.Snapshot
+	for _, name := range names {
+		if !strings.HasPrefix(name, model.SnapshotNamePrefix) {
+			continue
+		}
+
+		snapshot := model.Snapshot{
+			Name:    name,
+			Backend: support.Backend,
+			Target:  support.Target,
+		}
+		if created, err := time.ParseInLocation(model.SnapshotTimeLayout,
+			strings.TrimPrefix(name, model.SnapshotNamePrefix), time.Local); err == nil {
+			snapshot.Created = created
+		}
+
+		switch support.Backend {
+		case model.SnapshotBackendZFS:
+			snapshot.Path = support.Target + "@" + name
+		case model.SnapshotBackendBtrfs:
+			snapshot.Path = filepath.Join(btrfsSnapshotDir, name)
+		case model.SnapshotBackendLVMThin:
+			snapshot.Path = strings.SplitN(support.Target, "/", 2)[0] + "/" + name
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.Before(snapshots[j].Created)
+	})
+
+	return snapshots, nil
+}
+
+// RollbackSnapshot reverts the root filesystem to the snapshot
+func (r *SystemSnapshotRepository) RollbackSnapshot(snapshot model.Snapshot) error {
+	var output []byte
+	var err error
+
+	switch snapshot.Backend {
+	case model.SnapshotBackendZFS:
+		// -r discards snapshots newer than the target
+		output, err = r.commander.Execute("zfs", "rollback", "-r", snapshot.Path)
+
+	case model.SnapshotBackendBtrfs:
+		// Boot into the snapshot by making it the default subvolume
+		if where := r.explicitRootSubvolume(); where != "" {
+			return fmt.Errorf("cannot roll back to %s: %s names the root subvolume, which overrides the default subvolume", snapshot.Name, where)
+		}
+		var show []byte
+		show, err = r.commander.Execute("btrfs", "subvolume", "show", snapshot.Path)
+		if err != nil {
+			return fmt.Errorf("failed to inspect btrfs snapshot %s: %w", snapshot.Path, err)
+		}
+
+		id := ""
+		for _, line := range strings.Split(string(show), "\n") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(line), "Subvolume ID:"); ok {
+				id = strings.TrimSpace(value)
+				break
+			}
+		}
+		if id == "" {
+			return fmt.Errorf("could not determine subvolume ID of %s", snapshot.Path)
+		}
+
+		output, err = r.commander.Execute("btrfs", "subvolume", "set-default", id, "/")
+
+	case model.SnapshotBackendLVMThin:
+		// The merge completes when the origin is next activated (reboot)
+		output, err = r.commander.Execute("lvconvert", "--merge", snapshot.Path)
+
+	default:
+		return fmt.Errorf("unsupported snapshot backend: %s", snapshot.Backend)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to roll back to %s: %w\nOutput: %s", snapshot.Name, err, string(output))
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+// pkg/adapter/secondary/systemd_service_manager.go
+package secondary
+
+import (
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// SystemdServiceManager implements ServiceManager with systemctl
+type SystemdServiceManager struct {
+	commander interfaces.Commander
+}
+
+// NewServiceManager returns the service manager of the distribution's init
+// system: OpenRC on Alpine Linux, systemd elsewhere, or the service command
+// where systemd does not run
+func NewServiceManager(commander interfaces.Commander, osType string) secondary.ServiceManager {
+	switch model.InitSystemFor(osType) {
+	case model.InitOpenRC:
+		return NewOpenRCServiceManager(commander)
+	case model.InitSysV:
+		return NewSysVServiceManager(commander)
+	}
+	return NewSystemdServiceManager(commander)
+}
+
+// NewSystemdServiceManager creates a new SystemdServiceManager
+func NewSystemdServiceManager(commander interfaces.Commander) secondary.ServiceManager {
+	return &SystemdServiceManager{commander: commander}
+}
+
+// InitSystem returns systemd
+func (m *SystemdServiceManager) InitSystem() string {
+	return model.InitSystemd
+}
+
+// Enable starts a unit now and at boot
+func (m *SystemdServiceManager) Enable(name string) error {
+	return m.systemctl("enable", "--now", name)
+}
+
+// Disable stops a unit now and at boot
+func (m *SystemdServiceManager) Disable(name string) error {
+	return m.systemctl("disable", "--now", name)
+}
+
+// Start starts a unit
+func (m *SystemdServiceManager) Start(name string) error {
+	return m.systemctl("start", name)
+}
+
+// Stop stops a unit
+func (m *SystemdServiceManager) Stop(name string) error {
+	return m.systemctl("stop", name)
+}
+
+// Restart restarts a unit
+func (m *SystemdServiceManager) Restart(name string) error {
+	return m.systemctl("restart", name)
+}
+
+// Status reports whether a unit is active and enabled
+func (m *SystemdServiceManager) Status(name string) (*model.ServiceStatus, error) {
+	_, activeErr := m.commander.Execute("systemctl", "is-active", "--quiet", name)
+	_, enabledErr := m.commander.Execute("systemctl", "is-enabled", "--quiet", name)
+	return &model.ServiceStatus{
+		Name:    name,
+		Active:  activeErr == nil,
+		Enabled: enabledErr == nil,
+	}, nil
+}
+
+// systemctl runs a systemctl verb on a unit
+func (m *SystemdServiceManager) systemctl(verb string, args ...string) error {
+	if output, err := m.commander.Execute("systemctl", append([]string{verb}, args...)...); err != nil {
+		return fmt.Errorf("systemctl %s %s failed: %w\nOutput: %s", verb, args[len(args)-1], err, string(output))
+	}
+	return nil
+}
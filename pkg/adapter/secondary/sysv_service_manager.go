@@ -0,0 +1,72 @@
+// pkg/adapter/secondary/sysv_service_manager.go
+package secondary
+
+import (
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// SysVServiceManager implements ServiceManager with the service command,
+// for hosts where systemd is installed but not running, such as WSL
+// without systemd. Nothing starts services at boot there, so Enable and
+// Disable only start and stop them.
+type SysVServiceManager struct {
+	commander interfaces.Commander
+}
+
+// NewSysVServiceManager creates a new SysVServiceManager
+func NewSysVServiceManager(commander interfaces.Commander) secondary.ServiceManager {
+	return &SysVServiceManager{commander: commander}
+}
+
+// InitSystem returns sysv
+func (m *SysVServiceManager) InitSystem() string {
+	return model.InitSysV
+}
+
+// Enable starts a service
+func (m *SysVServiceManager) Enable(name string) error {
+	return m.Start(name)
+}
+
+// Disable stops a service that is running
+func (m *SysVServiceManager) Disable(name string) error {
+	status, _ := m.Status(name)
+	if !status.Active {
+		return nil
+	}
+	return m.Stop(name)
+}
+
+// Start starts a service
+func (m *SysVServiceManager) Start(name string) error {
+	return m.service(name, "start")
+}
+
+// Stop stops a service
+func (m *SysVServiceManager) Stop(name string) error {
+	return m.service(name, "stop")
+}
+
+// Restart restarts a service
+func (m *SysVServiceManager) Restart(name string) error {
+	return m.service(name, "restart")
+}
+
+// Status reports whether a service is running; services are never
+// enabled at boot
+func (m *SysVServiceManager) Status(name string) (*model.ServiceStatus, error) {
+	_, err := m.commander.Execute("service", name, "status")
+	return &model.ServiceStatus{Name: name, Active: err == nil}, nil
+}
+
+// service runs the service command on a service
+func (m *SysVServiceManager) service(name, verb string) error {
+	if output, err := m.commander.Execute("service", name, verb); err != nil {
+		return fmt.Errorf("service %s %s failed: %w\nOutput: %s", name, verb, err, string(output))
+	}
+	return nil
+}
@@ -15,16 +15,19 @@ import (
 type UFWFirewallRepository struct {
 	fs        interfaces.FileSystem
 	commander interfaces.Commander
+	osType    string
 }
 
 // NewUFWFirewallRepository creates a new UFWFirewallRepository
 func NewUFWFirewallRepository(
 	fs interfaces.FileSystem,
 	commander interfaces.Commander,
+	osType string,
 ) secondary.FirewallRepository {
 	return &UFWFirewallRepository{
 		fs:        fs,
 		commander: commander,
+		osType:    osType,
 	}
 }
 
@@ -46,53 +49,166 @@ func (r *UFWFirewallRepository) GetFirewallStatus() (bool, bool, bool, []string,
 	var rules []string
 
 	if isInstalled {
-		// Check if UFW is enabled
-		statusOutput, err := r.commander.Execute("ufw", "status")
-		if err == nil {
-			statusText := string(statusOutput)
-			isEnabled = strings.Contains(statusText, "Status: active")
-
-			// Extract rules (skip header lines)
-			lines := strings.Split(statusText, "\n")
-			ruleSection := false
-			for _, line := range lines {
-				line = strings.TrimSpace(line)
-
-				// Skip empty lines
-				if line == "" {
-					continue
-				}
-
-				// Skip header lines
-				if strings.Contains(line, "Status:") ||
-					strings.Contains(line, "Logging:") ||
-					strings.Contains(line, "Default:") ||
-					strings.Contains(line, "New profiles:") ||
-					strings.Contains(line, "To             Action      From") {
-					continue
-				}
-
-				// Check if we've reached the rule section
-				if strings.Contains(line, "--") {
-					ruleSection = true
-					continue
-				}
-
-				// Add rule lines
-				if ruleSection && line != "" {
-					rules = append(rules, line)
-				}
-			}
+		// Read ufw's own settings rather than the translated `ufw status` text
+		if conf, err := r.fs.ReadFile(UFWConfPath); err == nil {
+			isEnabled = ParseUFWEnabled(conf)
+		}
+
+		// Check if we have default policies configured
+		if defaults, err := r.fs.ReadFile(UFWDefaultsPath); err == nil {
+			incoming, outgoing := ParseUFWDefaultPolicies(defaults)
+			isConfigured = incoming == "deny" && outgoing == "allow"
+		}
 
-			// Check if we have default policies configured
-			isConfigured = strings.Contains(statusText, "deny (incoming)") &&
-				strings.Contains(statusText, "allow (outgoing)")
+		// Rules are listed as ufw commands, e.g. "allow 22/tcp"
+		if addedOutput, err := r.commander.Execute("ufw", "show", "added"); err == nil {
+			rules = ParseUFWAddedRules(addedOutput)
 		}
 	}
 
 	return isInstalled, isEnabled, isConfigured, rules, nil
 }
 
+// GetStackRules reads the rules ufw keeps for each family. With IPV6=no in
+// /etc/default/ufw ufw leaves IPv6 alone, so IPv6 is only filtered when
+// something else set a DROP policy with ip6tables.
+func (r *UFWFirewallRepository) GetStackRules() (model.StackRules, model.StackRules, error) {
+	ipv4 := model.StackRules{Family: model.FamilyIPv4, Active: true}
+	ipv6 := model.StackRules{Family: model.FamilyIPv6, Active: hostHasIPv6(r.fs)}
+	if !r.IsUFWInstalled() {
+		return ipv4, ipv6, nil
+	}
+
+	enabled := false
+	if conf, err := r.fs.ReadFile(UFWConfPath); err == nil {
+		enabled = ParseUFWEnabled(conf)
+	}
+	defaults, _ := r.fs.ReadFile(UFWDefaultsPath)
+	incoming, _ := ParseUFWDefaultPolicies(defaults)
+	filtered := enabled && (incoming == "deny" || incoming == "reject")
+
+	ipv4.Filtered = filtered
+	if data, err := r.fs.ReadFile(UFWUserRulesPath); err == nil {
+		ipv4.Allowed = ParseUFWTuples(data)
+	}
+
+	if ParseUFWIPv6(defaults) {
+		ipv6.Filtered = filtered
+		if data, err := r.fs.ReadFile(UFWUser6RulesPath); err == nil {
+			ipv6.Allowed = ParseUFWTuples(data)
+		}
+	} else if output, err := r.commander.Execute("ip6tables-save"); err == nil {
+		policies, _ := ParseIptablesSave(output)
+		ipv6.Filtered = policies["INPUT"] == "DROP"
+	}
+
+	return ipv4, ipv6, nil
+}
+
+// GetRules reads the rules of both families from ufw's rules files; a rule
+// ufw keeps for IPv4 and IPv6 is listed once
+func (r *UFWFirewallRepository) GetRules() ([]string, error) {
+	var rules []string
+	seen := make(map[string]bool)
+	for _, path := range []string{UFWUserRulesPath, UFWUser6RulesPath} {
+		data, err := r.fs.ReadFile(path)
+		if err != nil {
+			if path == UFWUserRulesPath {
+				return nil, fmt.Errorf("failed to read %s: %w", path, err)
+			}
+			continue
+		}
+		for _, rule := range ParseUFWRuleTuples(data) {
+			if !seen[rule] {
+				seen[rule] = true
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// ConfigurePortKnocking has knockd allow the SSH port to an address that
+// knocks, with a ufw rule deleted again after the open timeout
+func (r *UFWFirewallRepository) ConfigurePortKnocking(sshPort int, knocking *model.PortKnocking) error {
+	rule := fmt.Sprintf("allow from %%IP%% to any port %d proto tcp", sshPort)
+	return configureKnockd(r.fs, r.commander, r.osType, knocking,
+		"/usr/sbin/ufw "+rule, "/usr/sbin/ufw delete "+rule)
+}
+
+// PortKnockingActive reports whether knockd runs with hardn's sequence
+func (r *UFWFirewallRepository) PortKnockingActive() bool {
+	return knockdActive(r.fs, r.commander, r.osType)
+}
+
+// FilterFamily sets IPV6=yes so ufw filters IPv6 as well. IPv4 is always
+// filtered once ufw is enabled.
+func (r *UFWFirewallRepository) FilterFamily(family string) error {
+	if family != model.FamilyIPv6 {
+		return fmt.Errorf("ufw filters %s whenever it is enabled; enable it with 'hardn firewall enable'", model.FamilyName(family))
+	}
+
+	if err := r.setIPv6("yes"); err != nil {
+		return err
+	}
+	if output, err := r.commander.Execute("ufw", "reload"); err != nil {
+		return fmt.Errorf("failed to reload UFW: %w\nOutput: %s", err, string(output))
+	}
+	return nil
+}
+
+// setIPv6 sets IPV6 in /etc/default/ufw, which decides whether ufw
+// manages IPv6 at all
+func (r *UFWFirewallRepository) setIPv6(value string) error {
+	defaults, err := r.fs.ReadFile(UFWDefaultsPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", UFWDefaultsPath, err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(defaults), "\n"), "\n")
+	replaced := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "IPV6=") {
+			lines[i] = "IPV6=" + value
+			replaced = true
+		}
+	}
+	if !replaced {
+		lines = append(lines, "IPV6="+value)
+	}
+	if err := r.fs.WriteFile(UFWDefaultsPath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", UFWDefaultsPath, err)
+	}
+	return nil
+}
+
+// GetIPv6Kernel reports whether the kernel runs IPv6 and whether its
+// command line turns IPv6 off
+func (r *UFWFirewallRepository) GetIPv6Kernel() (bool, bool) {
+	return ipv6Kernel(r.fs)
+}
+
+// SetIPv6Kernel turns IPv6 off in the kernel, or undoes what hardn did
+func (r *UFWFirewallRepository) SetIPv6Kernel(disabled bool, bootParameter bool) error {
+	return setIPv6Kernel(r.fs, r.commander, r.osType, disabled, bootParameter)
+}
+
+// MirrorRule adds the rule again; ufw adds it to every family it manages
+// and skips those that already have it
+func (r *UFWFirewallRepository) MirrorRule(rule string, family string) error {
+	if output, err := r.commander.Execute("ufw", strings.Fields(rule)...); err != nil {
+		return fmt.Errorf("failed to add %s to %s: %w\nOutput: %s", rule, model.FamilyName(family), err, string(output))
+	}
+	return nil
+}
+
+// hostHasIPv6 reports whether the host has an IPv6 address outside the
+// loopback interface
+func hostHasIPv6(fs interfaces.FileSystem) bool {
+	data, err := fs.ReadFile(IfInet6Path)
+	return err == nil && ParseIfInet6(data)
+}
+
 // SaveFirewallConfig applies the specified firewall configuration
 func (r *UFWFirewallRepository) SaveFirewallConfig(config model.FirewallConfig) error {
 	// Ensure UFW is installed
@@ -100,6 +216,18 @@ func (r *UFWFirewallRepository) SaveFirewallConfig(config model.FirewallConfig)
 		return fmt.Errorf("UFW firewall is not installed")
 	}
 
+	// ufw only manages IPv6 with IPV6=yes, and can not once the kernel
+	// has IPv6 turned off
+	if config.IPv6 != "" {
+		ipv6 := "yes"
+		if config.IPv6 == model.IPv6Disable {
+			ipv6 = "no"
+		}
+		if err := r.setIPv6(ipv6); err != nil {
+			return err
+		}
+	}
+
 	// Set default policies
 	if _, err := r.commander.Execute("ufw", "default", config.DefaultIncoming, "incoming"); err != nil {
 		return fmt.Errorf("failed to set incoming policy: %w", err)
@@ -119,8 +247,12 @@ func (r *UFWFirewallRepository) SaveFirewallConfig(config model.FirewallConfig)
 		return fmt.Errorf("failed to reset UFW rules: %w", err)
 	}
 
-	// Apply application profiles
-	if err := r.applyAppProfiles(config.ApplicationProfiles); err != nil {
+	// Apply application profiles, to IPv4 alone when IPv6 is denied
+	profileFamily := ""
+	if config.IPv6 == model.IPv6Deny {
+		profileFamily = model.FamilyIPv4
+	}
+	if err := r.applyAppProfiles(config.ApplicationProfiles, profileFamily); err != nil {
 		return err
 	}
 
@@ -153,65 +285,83 @@ func (r *UFWFirewallRepository) GetFirewallConfig() (*model.FirewallConfig, erro
 
 // AddRule adds a firewall rule
 func (r *UFWFirewallRepository) AddRule(rule model.FirewallRule) error {
-	var args []string
-
-	// Build command arguments
-	args = append(args, rule.Action)
-
-	// Add port specification
-	portSpec := fmt.Sprintf("%d/%s", rule.Port, rule.Protocol)
-	args = append(args, portSpec)
-
-	// Add source IP if specified
-	if rule.SourceIP != "" {
-		args = append(args, "from", rule.SourceIP)
+	if _, err := r.commander.Execute("ufw", ufwRuleArgs(rule)...); err != nil {
+		return fmt.Errorf("failed to add rule %s %s/%s: %w", rule.Action, rule.Ports(":"), rule.Protocol, err)
 	}
 
-	// Add description if specified
-	if rule.Description != "" {
-		args = append(args, "comment", rule.Description)
-	}
+	return nil
+}
+
+// RemoveRule removes a firewall rule
+func (r *UFWFirewallRepository) RemoveRule(rule model.FirewallRule) error {
+	// ufw matches the rule without its comment
+	rule.Description = ""
+	args := append([]string{"delete"}, ufwRuleArgs(rule)...)
 
-	// Execute command
 	if _, err := r.commander.Execute("ufw", args...); err != nil {
-		return fmt.Errorf("failed to add rule %s %s: %w", rule.Action, portSpec, err)
+		return fmt.Errorf("failed to remove rule %s %s/%s: %w", rule.Action, rule.Ports(":"), rule.Protocol, err)
 	}
 
 	return nil
 }
 
-// RemoveRule removes a firewall rule
-func (r *UFWFirewallRepository) RemoveRule(rule model.FirewallRule) error {
-	var args []string
-
-	// Build command arguments
-	args = append(args, "delete", rule.Action)
+// RuleCommand returns the ufw command AddRule runs for a rule
+func (r *UFWFirewallRepository) RuleCommand(rule model.FirewallRule) string {
+	return commandLine("ufw", ufwRuleArgs(rule))
+}
 
-	// Add port specification
-	portSpec := fmt.Sprintf("%d/%s", rule.Port, rule.Protocol)
-	args = append(args, portSpec)
+// ufwRuleArgs returns ufw's arguments for a rule. A rule limited to a
+// source or a family needs ufw's full syntax; "allow 22/tcp from ..." is
+// not accepted.
+func ufwRuleArgs(rule model.FirewallRule) []string {
+	source := rule.SourceIP
+	if source == "" && rule.Family != "" {
+		source = ufwFamilySource(rule.Family)
+	}
 
-	// Add source IP if specified
-	if rule.SourceIP != "" {
-		args = append(args, "from", rule.SourceIP)
+	var args []string
+	if source == "" {
+		args = []string{rule.Action, rule.Ports(":") + "/" + rule.Protocol}
+	} else {
+		args = []string{rule.Action, "from", source, "to", "any", "port", rule.Ports(":"), "proto", rule.Protocol}
 	}
 
-	// Execute command
-	if _, err := r.commander.Execute("ufw", args...); err != nil {
-		return fmt.Errorf("failed to remove rule %s %s: %w", rule.Action, portSpec, err)
+	if rule.Description != "" {
+		args = append(args, "comment", rule.Description)
 	}
+	return args
+}
 
-	return nil
+// ufwFamilySource returns the source matching every address of a family,
+// which keeps a rule to that family, or "any" for both
+func ufwFamilySource(family string) string {
+	switch family {
+	case model.FamilyIPv4:
+		return "0.0.0.0/0"
+	case model.FamilyIPv6:
+		return "::/0"
+	}
+	return "any"
 }
 
 // AddProfile adds a firewall application profile
 func (r *UFWFirewallRepository) AddProfile(profile model.FirewallProfile) error {
 	// Apply a single profile
-	return r.applyAppProfiles([]model.FirewallProfile{profile})
+	return r.applyAppProfiles([]model.FirewallProfile{profile}, "")
 }
 
-// applyAppProfiles applies firewall application profiles
-func (r *UFWFirewallRepository) applyAppProfiles(profiles []model.FirewallProfile) error {
+// ApplyProfiles writes and enables firewall application profiles
+func (r *UFWFirewallRepository) ApplyProfiles(profiles []model.FirewallProfile) error {
+	if !r.IsUFWInstalled() {
+		return fmt.Errorf("UFW firewall is not installed")
+	}
+
+	return r.applyAppProfiles(profiles, "")
+}
+
+// applyAppProfiles applies firewall application profiles, to one family
+// when family is set
+func (r *UFWFirewallRepository) applyAppProfiles(profiles []model.FirewallProfile, family string) error {
 	if len(profiles) == 0 {
 		return nil
 	}
@@ -240,7 +390,7 @@ func (r *UFWFirewallRepository) applyAppProfiles(profiles []model.FirewallProfil
 
 	// Apply each profile
 	for _, profile := range profiles {
-		args := []string{"allow", "from", "any", "to", "any", "app", profile.Name}
+		args := []string{"allow", "from", ufwFamilySource(family), "to", "any", "app", profile.Name}
 		if _, err := r.commander.Execute("ufw", args...); err != nil {
 			return fmt.Errorf("failed to apply profile %s: %w", profile.Name, err)
 		}
@@ -268,3 +418,23 @@ func (r *UFWFirewallRepository) DisableFirewall() error {
 
 	return nil
 }
+
+// InstallFirewall installs the UFW package
+func (r *UFWFirewallRepository) InstallFirewall() error {
+	if r.IsUFWInstalled() {
+		return nil
+	}
+
+	var err error
+	if r.osType == "alpine" {
+		_, err = r.commander.Execute("apk", "add", "--no-cache", "ufw")
+	} else {
+		_, err = r.commander.Execute("apt-get", "install", "-y", "ufw")
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to install UFW: %w", err)
+	}
+
+	return nil
+}
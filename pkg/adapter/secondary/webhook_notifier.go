@@ -0,0 +1,85 @@
+// pkg/adapter/secondary/webhook_notifier.go
+package secondary
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/port/secondary"
+)
+
+// maxWebhookContent is Discord's limit on the message content
+const maxWebhookContent = 2000
+
+// WebhookPayload is the JSON posted to a webhook. Slack and Teams show
+// text, Discord shows content, and other receivers can read the summary.
+type WebhookPayload struct {
+	Text    string           `json:"text"`
+	Content string           `json:"content"`
+	Summary model.RunSummary `json:"hardn"`
+}
+
+// WebhookNotifier implements Notifier by posting the summary as JSON
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a new WebhookNotifier
+func NewWebhookNotifier(endpoint string) secondary.Notifier {
+	return &WebhookNotifier{
+		url:    endpoint,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name identifies the webhook by its host, leaving out the secret path
+func (n *WebhookNotifier) Name() string {
+	if parsed, err := url.Parse(n.url); err == nil && parsed.Host != "" {
+		return "webhook " + parsed.Host
+	}
+	return "webhook"
+}
+
+// Send posts the summary
+func (n *WebhookNotifier) Send(summary model.RunSummary) error {
+	body, err := json.Marshal(NewWebhookPayload(summary))
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "hardn-notifier")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to %s: %w", n.Name(), err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s returned %s", n.Name(), resp.Status)
+	}
+	return nil
+}
+
+// NewWebhookPayload builds the payload for summary
+func NewWebhookPayload(summary model.RunSummary) WebhookPayload {
+	text := summary.Text()
+	content := text
+	if len(content) > maxWebhookContent {
+		content = content[:maxWebhookContent-4] + "\n..."
+	}
+	return WebhookPayload{Text: text, Content: content, Summary: summary}
+}
@@ -0,0 +1,32 @@
+// pkg/application/ansible_facts_manager.go
+package application
+
+import "github.com/abbott/hardn/pkg/domain/service"
+
+// AnsibleFactsManager is an application service for the Ansible local
+// facts script
+type AnsibleFactsManager struct {
+	factsService service.AnsibleFactsService
+}
+
+// NewAnsibleFactsManager creates a new AnsibleFactsManager
+func NewAnsibleFactsManager(factsService service.AnsibleFactsService) *AnsibleFactsManager {
+	return &AnsibleFactsManager{
+		factsService: factsService,
+	}
+}
+
+// InstallFactsScript installs a facts script running the given hardn binary
+func (m *AnsibleFactsManager) InstallFactsScript(binary string) error {
+	return m.factsService.InstallFactsScript(binary)
+}
+
+// RemoveFactsScript removes hardn's facts script
+func (m *AnsibleFactsManager) RemoveFactsScript() error {
+	return m.factsService.RemoveFactsScript()
+}
+
+// FactsScriptInstalled reports whether hardn's facts script is installed
+func (m *AnsibleFactsManager) FactsScriptInstalled() bool {
+	return m.factsService.FactsScriptInstalled()
+}
@@ -0,0 +1,29 @@
+// pkg/application/audit_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// AuditManager is an application service for audit reports
+type AuditManager struct {
+	auditService service.AuditService
+}
+
+// NewAuditManager creates a new AuditManager
+func NewAuditManager(auditService service.AuditService) *AuditManager {
+	return &AuditManager{
+		auditService: auditService,
+	}
+}
+
+// SaveReport writes an audit report to path
+func (m *AuditManager) SaveReport(path string, report *model.AuditReport) error {
+	return m.auditService.SaveReport(path, report)
+}
+
+// CompareReports loads two audit reports and compares them
+func (m *AuditManager) CompareReports(beforePath, afterPath string) (*model.AuditDiff, error) {
+	return m.auditService.CompareReports(beforePath, afterPath)
+}
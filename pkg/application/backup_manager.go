@@ -27,6 +27,22 @@ func (m *BackupManager) BackupFile(filePath string) error {
 	return m.backupService.BackupFile(filePath)
 }
 
+// ListBackupGroups returns every backup grouped by the file it was taken
+// from
+func (m *BackupManager) ListBackupGroups() ([]model.BackupGroup, error) {
+	return m.backupService.ListBackupGroups()
+}
+
+// CompareBackup reads a backup and the live file it would replace
+func (m *BackupManager) CompareBackup(backupPath, originalPath string) (*model.BackupComparison, error) {
+	return m.backupService.CompareBackup(backupPath, originalPath)
+}
+
+// RestoreBackup restores a file from a backup
+func (m *BackupManager) RestoreBackup(backupPath, originalPath string) error {
+	return m.backupService.RestoreBackup(backupPath, originalPath)
+}
+
 // GetBackupConfig retrieves the current backup configuration
 func (m *BackupManager) GetBackupConfig() (*model.BackupConfig, error) {
 	return m.backupService.GetBackupConfig()
@@ -55,6 +71,22 @@ func (m *BackupManager) SetBackupDirectory(directory string) error {
 	return m.backupService.SetBackupDirectory(directory)
 }
 
+// CheckBackupKeys tests that backups can be encrypted for the configured
+// recipient and decrypted on this host
+func (m *BackupManager) CheckBackupKeys() (*model.BackupKeyStatus, error) {
+	return m.backupService.CheckBackupKeys()
+}
+
+// SyncRemote mirrors the backups to the configured remote target
+func (m *BackupManager) SyncRemote() (*model.BackupSyncResult, error) {
+	return m.backupService.SyncRemote()
+}
+
+// CheckRemote tests that the remote target is reachable and writable
+func (m *BackupManager) CheckRemote() error {
+	return m.backupService.CheckRemote()
+}
+
 // VerifyBackupDirectory ensures the backup directory exists and is writable
 func (m *BackupManager) VerifyBackupDirectory() error {
 	return m.backupService.VerifyBackupDirectory()
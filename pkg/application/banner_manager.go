@@ -0,0 +1,70 @@
+// pkg/application/banner_manager.go
+package application
+
+import (
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// BannerManager is an application service for login banners and the MOTD
+type BannerManager struct {
+	bannerService service.BannerService
+	sshManager    *SSHManager
+}
+
+// NewBannerManager creates a new BannerManager
+func NewBannerManager(bannerService service.BannerService, sshManager *SSHManager) *BannerManager {
+	return &BannerManager{
+		bannerService: bannerService,
+		sshManager:    sshManager,
+	}
+}
+
+// EffectivePolicy fills in the default texts of a policy
+func (m *BannerManager) EffectivePolicy(policy model.BannerPolicy) model.BannerPolicy {
+	return m.bannerService.EffectivePolicy(policy)
+}
+
+// ApplyBanners writes the login banners and installs the dynamic MOTD,
+// then points sshd's Banner at /etc/issue.net if the policy asks for it.
+// sshd is only reconfigured, and restarted, when its banner changes.
+func (m *BannerManager) ApplyBanners(policy model.BannerPolicy) error {
+	if err := m.bannerService.ApplyBanners(policy); err != nil {
+		return err
+	}
+	if !policy.SSHBanner {
+		return nil
+	}
+
+	current, err := m.sshManager.GetBanner()
+	if err == nil && current == model.IssueNetPath {
+		return nil
+	}
+	return m.sshManager.ConfigureBanner(model.IssueNetPath)
+}
+
+// RefreshMotd rewrites /etc/motd with the last run and risk level on hosts
+// that cannot render the MOTD at login
+func (m *BannerManager) RefreshMotd(policy model.BannerPolicy, lastRun time.Time, riskLevel string) error {
+	return m.bannerService.RefreshMotd(policy, m.bannerService.MotdFacts(lastRun, riskLevel))
+}
+
+// RenderMotd returns the MOTD of the policy for the last run and risk level
+func (m *BannerManager) RenderMotd(policy model.BannerPolicy, lastRun time.Time, riskLevel string) string {
+	policy = m.bannerService.EffectivePolicy(policy)
+	return m.bannerService.MotdFacts(lastRun, riskLevel).Replace(policy.Motd)
+}
+
+// GetBannerStatus reports the login banners and the banner sshd shows
+func (m *BannerManager) GetBannerStatus() (*model.BannerStatus, error) {
+	status, err := m.bannerService.GetBannerStatus()
+	if err != nil {
+		return nil, err
+	}
+	if banner, err := m.sshManager.GetBanner(); err == nil {
+		status.SSHBanner = banner
+	}
+	return status, nil
+}
@@ -0,0 +1,47 @@
+// pkg/application/boot_hardening_manager.go
+package application
+
+import (
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// BootHardeningManager is an application service for the kernel command
+// line and the GRUB password
+type BootHardeningManager struct {
+	bootService   service.BootHardeningService
+	backupService service.BackupService
+}
+
+// NewBootHardeningManager creates a new BootHardeningManager
+func NewBootHardeningManager(bootService service.BootHardeningService, backupService service.BackupService) *BootHardeningManager {
+	return &BootHardeningManager{
+		bootService:   bootService,
+		backupService: backupService,
+	}
+}
+
+// ApplyHardening backs up the boot loader configuration, then sets the
+// parameters and password of the policy. The parameters take effect at
+// the next boot; GetStatus reports whether a reboot is pending.
+func (m *BootHardeningManager) ApplyHardening(policy model.BootHardeningPolicy) error {
+	for _, path := range m.bootService.ConfigFiles() {
+		if err := m.backupService.BackupFile(path); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+	}
+	return m.bootService.ApplyHardening(policy)
+}
+
+// GetStatus compares the policy with the boot loader configuration and
+// the parameters the kernel booted with
+func (m *BootHardeningManager) GetStatus(policy model.BootHardeningPolicy) (*model.BootHardeningStatus, error) {
+	return m.bootService.GetStatus(policy)
+}
+
+// ValidateParameter checks a kernel parameter
+func (m *BootHardeningManager) ValidateParameter(parameter string) error {
+	return service.ValidateBootParameter(parameter)
+}
@@ -0,0 +1,25 @@
+// pkg/application/compliance_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// ComplianceManager is an application service for CIS benchmark reports
+type ComplianceManager struct {
+	complianceService service.ComplianceService
+}
+
+// NewComplianceManager creates a new ComplianceManager
+func NewComplianceManager(complianceService service.ComplianceService) *ComplianceManager {
+	return &ComplianceManager{
+		complianceService: complianceService,
+	}
+}
+
+// RunBenchmark checks the host against the CIS recommendations hardn
+// covers and scores it
+func (m *ComplianceManager) RunBenchmark(request model.ComplianceRequest) (*model.ComplianceReport, error) {
+	return m.complianceService.RunBenchmark(request)
+}
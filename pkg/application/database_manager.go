@@ -0,0 +1,29 @@
+// pkg/application/database_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// DatabaseManager is an application service for database server hardening
+type DatabaseManager struct {
+	databaseService service.DatabaseService
+}
+
+// NewDatabaseManager creates a new DatabaseManager
+func NewDatabaseManager(databaseService service.DatabaseService) *DatabaseManager {
+	return &DatabaseManager{
+		databaseService: databaseService,
+	}
+}
+
+// DetectDatabases returns the supported database servers on the host
+func (m *DatabaseManager) DetectDatabases() ([]model.DatabaseServer, error) {
+	return m.databaseService.DetectDatabases()
+}
+
+// HardenDatabases applies the baseline to every detected database server
+func (m *DatabaseManager) HardenDatabases(settings model.DatabaseHardening) ([]model.DatabaseServer, error) {
+	return m.databaseService.HardenDatabases(settings)
+}
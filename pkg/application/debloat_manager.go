@@ -0,0 +1,61 @@
+// pkg/application/debloat_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// DebloatManager is an application service for removing insecure or
+// unneeded packages and services
+type DebloatManager struct {
+	debloatService service.DebloatService
+}
+
+// NewDebloatManager creates a new DebloatManager
+func NewDebloatManager(debloatService service.DebloatService) *DebloatManager {
+	return &DebloatManager{
+		debloatService: debloatService,
+	}
+}
+
+// FindInsecurePackages returns the configured items installed or running here
+func (m *DebloatManager) FindInsecurePackages(items []model.DebloatItem) ([]model.DebloatFinding, error) {
+	return m.debloatService.FindInsecurePackages(items)
+}
+
+// RemoveInsecurePackage removes one finding's packages, or disables its
+// services when other packages depend on them
+func (m *DebloatManager) RemoveInsecurePackage(finding model.DebloatFinding) error {
+	return m.debloatService.RemoveInsecurePackage(finding)
+}
+
+// RemoveInsecurePackages removes every finding it can. Findings left to
+// the administrator are returned with the error for each.
+func (m *DebloatManager) RemoveInsecurePackages(items []model.DebloatItem) ([]model.DebloatFinding, map[string]error, error) {
+	findings, err := m.debloatService.FindInsecurePackages(items)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	failed := make(map[string]error)
+	for _, finding := range findings {
+		if err := m.debloatService.RemoveInsecurePackage(finding); err != nil {
+			failed[finding.Item.Name] = err
+		}
+	}
+	return findings, failed, nil
+}
+
+// AuditFindings returns the insecure packages for inclusion in an audit
+func (m *DebloatManager) AuditFindings(items []model.DebloatItem) ([]model.PolicyViolation, error) {
+	findings, err := m.debloatService.FindInsecurePackages(items)
+	if err != nil {
+		return nil, err
+	}
+	violations := make([]model.PolicyViolation, 0, len(findings))
+	for _, finding := range findings {
+		violations = append(violations, finding.Violation())
+	}
+	return violations, nil
+}
@@ -38,6 +38,11 @@ func (m *DNSManager) ConfigureSecureDNS() error {
 	return m.ConfigureDNS(cloudflareNameservers, "lan")
 }
 
+// ValidateNameserver checks that a nameserver is an IP address
+func (m *DNSManager) ValidateNameserver(addr string) error {
+	return service.ValidateNameserver(addr)
+}
+
 // GetCurrentConfig retrieves the current DNS configuration
 func (m *DNSManager) GetCurrentConfig() (*model.DNSConfig, error) {
 	return m.dnsService.GetCurrentConfig()
@@ -0,0 +1,39 @@
+// pkg/application/evidence_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// EvidenceManager is an application service for exporting evidence
+// bundles for auditors
+type EvidenceManager struct {
+	evidenceService service.EvidenceService
+	runService      service.RunService
+}
+
+// NewEvidenceManager creates a new EvidenceManager
+func NewEvidenceManager(evidenceService service.EvidenceService, runService service.RunService) *EvidenceManager {
+	return &EvidenceManager{
+		evidenceService: evidenceService,
+		runService:      runService,
+	}
+}
+
+// LoadTemplate returns a built-in template by name, or reads one from a
+// YAML file
+func (m *EvidenceManager) LoadTemplate(nameOrPath string) (model.EvidenceTemplate, error) {
+	return m.evidenceService.LoadTemplate(nameOrPath)
+}
+
+// CreateBundle writes an evidence bundle, adding the recorded hardening
+// runs to the request
+func (m *EvidenceManager) CreateBundle(request model.EvidenceRequest) (*model.EvidenceBundle, error) {
+	runs, err := m.runService.ListRuns()
+	if err != nil {
+		return nil, err
+	}
+	request.Runs = runs
+	return m.evidenceService.CreateBundle(request)
+}
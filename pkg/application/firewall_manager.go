@@ -2,6 +2,8 @@
 package application
 
 import (
+	"fmt"
+
 	"github.com/abbott/hardn/pkg/domain/model"
 	"github.com/abbott/hardn/pkg/domain/service"
 )
@@ -36,21 +38,73 @@ func (m *FirewallManager) ConfigureFirewall(
 	return m.firewallService.ConfigureFirewall(config)
 }
 
-// ConfigureSecureFirewall sets up a firewall with secure defaults
-func (m *FirewallManager) ConfigureSecureFirewall(sshPort int, allowedPorts []int, profiles []model.FirewallProfile) error {
-	// Create default SSH rule
-	sshRule := model.FirewallRule{
-		Action:      "allow",
-		Protocol:    "tcp",
-		Port:        sshPort,
-		SourceIP:    "",
-		Description: "SSH access",
+// ConfigureSecureFirewall sets up a firewall with secure defaults, the SSH
+// rule, the allowed ports and the rules saved by the rule builder, treating
+// IPv6 as the policy asks. With port knocking, knockd is set up first,
+// since the SSH port is closed.
+func (m *FirewallManager) ConfigureSecureFirewall(ssh model.SSHFirewallPolicy, ipv6 model.IPv6Policy, allowedPorts []int, customRules []model.FirewallRule, profiles []model.FirewallProfile) error {
+	config, err := secureFirewallConfig(ssh, ipv6, allowedPorts, customRules, profiles)
+	if err != nil {
+		return err
+	}
+	if ssh.Knocking != nil {
+		if err := m.firewallService.ConfigurePortKnocking(ssh.Port, ssh.Knocking); err != nil {
+			return err
+		}
 	}
 
-	// Create additional rules for allowed ports
+	// IPv6 comes back before the firewall filters it, and goes only once
+	// the firewall has stopped managing it
+	if ipv6.Mode != model.IPv6Disable {
+		if err := m.firewallService.ApplyIPv6Policy(ipv6); err != nil {
+			return err
+		}
+	}
+	if err := m.firewallService.ConfigureFirewall(config); err != nil {
+		return err
+	}
+	if ipv6.Mode == model.IPv6Disable {
+		return m.firewallService.ApplyIPv6Policy(ipv6)
+	}
+	return nil
+}
+
+// CheckDrift compares the firewall ConfigureSecureFirewall sets up with
+// the live ruleset
+func (m *FirewallManager) CheckDrift(ssh model.SSHFirewallPolicy, ipv6 model.IPv6Policy, allowedPorts []int, customRules []model.FirewallRule, profiles []model.FirewallProfile) (*model.FirewallDrift, error) {
+	config, err := secureFirewallConfig(ssh, ipv6, allowedPorts, customRules, profiles)
+	if err != nil {
+		return nil, err
+	}
+	return m.firewallService.CheckDrift(config)
+}
+
+// CheckIPv6 reports how other hosts reach this one over IPv6, compared with
+// the firewall ConfigureSecureFirewall sets up
+func (m *FirewallManager) CheckIPv6(ssh model.SSHFirewallPolicy, ipv6 model.IPv6Policy, allowedPorts []int, customRules []model.FirewallRule, profiles []model.FirewallProfile) (*model.IPv6Exposure, error) {
+	config, err := secureFirewallConfig(ssh, ipv6, allowedPorts, customRules, profiles)
+	if err != nil {
+		return nil, err
+	}
+	return m.firewallService.CheckIPv6(config)
+}
+
+// secureFirewallConfig builds the configuration ConfigureSecureFirewall
+// applies
+func secureFirewallConfig(ssh model.SSHFirewallPolicy, ipv6 model.IPv6Policy, allowedPorts []int, customRules []model.FirewallRule, profiles []model.FirewallProfile) (model.FirewallConfig, error) {
+	if err := service.ValidateIPv6Policy(ipv6); err != nil {
+		return model.FirewallConfig{}, err
+	}
+
+	// Create the SSH rule, unless knockd opens the port. It applies to both
+	// families even when IPv6 is denied, so no session is locked out.
 	var rules []model.FirewallRule
-	rules = append(rules, sshRule)
+	if sshRule, ok := ssh.Rule(); ok {
+		rules = append(rules, sshRule)
+	}
 
+	// Create additional rules for allowed ports
+	var other []model.FirewallRule
 	for _, port := range allowedPorts {
 		rule := model.FirewallRule{
 			Action:      "allow",
@@ -59,34 +113,132 @@ func (m *FirewallManager) ConfigureSecureFirewall(sshPort int, allowedPorts []in
 			SourceIP:    "",
 			Description: "Custom allowed port",
 		}
-		rules = append(rules, rule)
+		other = append(other, rule)
+	}
+
+	// The firewall is reset first, so a bad rule must not stop it halfway
+	for _, rule := range customRules {
+		if err := service.ValidateRule(rule); err != nil {
+			return model.FirewallConfig{}, fmt.Errorf("invalid firewall rule %s %s/%s: %w", rule.Action, rule.Ports("-"), rule.Protocol, err)
+		}
+	}
+	other = append(other, customRules...)
+
+	for _, rule := range other {
+		if rule, ok := ipv6.Rule(rule); ok {
+			rules = append(rules, rule)
+		}
 	}
 
 	// Create default configuration
-	config := model.FirewallConfig{
+	return model.FirewallConfig{
 		Enabled:             true,
 		DefaultIncoming:     "deny",
 		DefaultOutgoing:     "allow",
 		Rules:               rules,
 		ApplicationProfiles: profiles, // Use the profiles parameter here
+		IPv6:                ipv6.Mode,
+	}, nil
+}
+
+// AddSSHRule adds the rule letting SSH in, rate-limited when the policy
+// asks for it. With port knocking there is no rule to add.
+func (m *FirewallManager) AddSSHRule(ssh model.SSHFirewallPolicy) error {
+	rule, ok := ssh.Rule()
+	if !ok {
+		return nil
 	}
+	return m.firewallService.AddRule(rule)
+}
 
-	return m.firewallService.ConfigureFirewall(config)
+// ConfigurePortKnocking sets up knockd for the SSH port, or stops it when
+// the policy has no port knocking
+func (m *FirewallManager) ConfigurePortKnocking(ssh model.SSHFirewallPolicy) error {
+	return m.firewallService.ConfigurePortKnocking(ssh.Port, ssh.Knocking)
+}
+
+// GetSSHAccess reports whether the firewall rate-limits SSH and whether
+// knockd guards it
+func (m *FirewallManager) GetSSHAccess(sshPort int) (*model.SSHAccess, error) {
+	return m.firewallService.GetSSHAccess(sshPort)
 }
 
-// AddSSHRule adds a rule to allow SSH access
-func (m *FirewallManager) AddSSHRule(port int) error {
+// AllowPortFrom allows TCP traffic to port from a single address or network
+func (m *FirewallManager) AllowPortFrom(port int, source string, description string) error {
 	rule := model.FirewallRule{
 		Action:      "allow",
 		Protocol:    "tcp",
 		Port:        port,
-		SourceIP:    "",
-		Description: "SSH access",
+		SourceIP:    source,
+		Description: description,
 	}
 
 	return m.firewallService.AddRule(rule)
 }
 
+// AllowPort allows traffic to port, optionally only from source
+func (m *FirewallManager) AllowPort(port int, protocol string, source string, description string) error {
+	return m.addPortRule("allow", port, protocol, source, description)
+}
+
+// DenyPort denies traffic to port, optionally only from source
+func (m *FirewallManager) DenyPort(port int, protocol string, source string, description string) error {
+	return m.addPortRule("deny", port, protocol, source, description)
+}
+
+// addPortRule adds a rule for port after checking the source
+func (m *FirewallManager) addPortRule(action string, port int, protocol string, source string, description string) error {
+	if source != "" {
+		if err := service.ValidateRuleSource(source); err != nil {
+			return err
+		}
+	}
+
+	rule := model.FirewallRule{
+		Action:      action,
+		Protocol:    protocol,
+		Port:        port,
+		SourceIP:    source,
+		Description: description,
+	}
+
+	return m.firewallService.AddRule(rule)
+}
+
+// PreviewRule checks a rule and returns the command that adds it
+func (m *FirewallManager) PreviewRule(rule model.FirewallRule) (string, error) {
+	return m.firewallService.PreviewRule(rule)
+}
+
+// AddRule adds a rule after checking it
+func (m *FirewallManager) AddRule(rule model.FirewallRule) error {
+	if err := service.ValidateRule(rule); err != nil {
+		return err
+	}
+	return m.firewallService.AddRule(rule)
+}
+
+// RemoveRule removes a rule
+func (m *FirewallManager) RemoveRule(rule model.FirewallRule) error {
+	return m.firewallService.RemoveRule(rule)
+}
+
+// ParsePortRange parses a port written as "8080" or a range written as
+// "6000-6007"
+func (m *FirewallManager) ParsePortRange(spec string) (int, int, error) {
+	return service.ParsePortRange(spec)
+}
+
+// ValidatePortKnocking checks a knock sequence for the SSH port
+func (m *FirewallManager) ValidatePortKnocking(sshPort int, knocking model.PortKnocking) error {
+	return service.ValidatePortKnocking(sshPort, knocking)
+}
+
+// ParsePortSpec parses a port written as "8080" or "53/udp"
+func (m *FirewallManager) ParsePortSpec(spec string) (int, string, error) {
+	return service.ParsePortSpec(spec)
+}
+
 // EnableFirewall enables the firewall
 func (m *FirewallManager) EnableFirewall() error {
 	return m.firewallService.EnableFirewall()
@@ -97,7 +249,28 @@ func (m *FirewallManager) DisableFirewall() error {
 	return m.firewallService.DisableFirewall()
 }
 
+// InstallFirewall installs the firewall package
+func (m *FirewallManager) InstallFirewall() error {
+	return m.firewallService.InstallFirewall()
+}
+
+// ApplyProfiles writes and enables application profiles
+func (m *FirewallManager) ApplyProfiles(profiles []model.FirewallProfile) error {
+	return m.firewallService.ApplyProfiles(profiles)
+}
+
 // GetFirewallStatus retrieves the current status of the firewall
 func (m *FirewallManager) GetFirewallStatus() (bool, bool, bool, []string, error) {
 	return m.firewallService.GetFirewallStatus()
 }
+
+// CheckDualStack compares the firewall's IPv4 and IPv6 rules
+func (m *FirewallManager) CheckDualStack(ipv6Mode string) (*model.DualStackReport, error) {
+	return m.firewallService.CheckDualStack(ipv6Mode)
+}
+
+// MirrorDualStack copies rules between IPv4 and IPv6 until both let in
+// the same traffic, as far as rules limited to an address allow
+func (m *FirewallManager) MirrorDualStack(ipv6Mode string) (*model.DualStackReport, error) {
+	return m.firewallService.MirrorDualStack(ipv6Mode)
+}
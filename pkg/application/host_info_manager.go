@@ -46,6 +46,11 @@ func (m *HostInfoManager) GetNonSystemUsers() ([]model.User, error) {
 	return m.hostInfoService.GetNonSystemUsers()
 }
 
+// ListNonSystemUsers retrieves a page of non-system users
+func (m *HostInfoManager) ListNonSystemUsers(opts model.UserListOptions) (*model.UserPage, error) {
+	return m.hostInfoService.ListNonSystemUsers(opts)
+}
+
 // GetNonSystemGroups retrieves non-system groups on the system
 func (m *HostInfoManager) GetNonSystemGroups() ([]string, error) {
 	return m.hostInfoService.GetNonSystemGroups()
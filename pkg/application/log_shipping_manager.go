@@ -0,0 +1,40 @@
+// pkg/application/log_shipping_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// LogShippingManager is an application service for forwarding logs to a collector
+type LogShippingManager struct {
+	logShippingService service.LogShippingService
+}
+
+// NewLogShippingManager creates a new LogShippingManager
+func NewLogShippingManager(logShippingService service.LogShippingService) *LogShippingManager {
+	return &LogShippingManager{
+		logShippingService: logShippingService,
+	}
+}
+
+// EffectivePolicy checks the policy and fills in the default port
+func (m *LogShippingManager) EffectivePolicy(policy model.LogShippingPolicy) (model.LogShippingPolicy, error) {
+	return m.logShippingService.EffectivePolicy(policy)
+}
+
+// CheckConnection completes a TLS handshake with the collector
+func (m *LogShippingManager) CheckConnection(policy model.LogShippingPolicy) error {
+	return m.logShippingService.CheckConnection(policy)
+}
+
+// ApplyLogShipping installs the certificates and points rsyslog or
+// systemd-journal-upload at the collector
+func (m *LogShippingManager) ApplyLogShipping(policy model.LogShippingPolicy) error {
+	return m.logShippingService.ApplyLogShipping(policy)
+}
+
+// GetLogShippingStatus reports whether logs are being shipped off the host
+func (m *LogShippingManager) GetLogShippingStatus() (*model.LogShippingStatus, error) {
+	return m.logShippingService.GetLogShippingStatus()
+}
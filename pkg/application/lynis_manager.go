@@ -0,0 +1,31 @@
+// pkg/application/lynis_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// LynisManager is an application service for Lynis security scans
+type LynisManager struct {
+	lynisService service.LynisService
+}
+
+// NewLynisManager creates a new LynisManager
+func NewLynisManager(lynisService service.LynisService) *LynisManager {
+	return &LynisManager{
+		lynisService: lynisService,
+	}
+}
+
+// RunScan runs "lynis audit system" and returns the hardening index,
+// warnings and suggestions it reported
+func (m *LynisManager) RunScan() (*model.LynisReport, error) {
+	return m.lynisService.RunScan()
+}
+
+// LastReport returns the results of the last scan, or nil when Lynis has
+// not run yet
+func (m *LynisManager) LastReport() (*model.LynisReport, error) {
+	return m.lynisService.LastReport()
+}
@@ -0,0 +1,30 @@
+// pkg/application/mac_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// MACManager is an application service for mandatory access control
+type MACManager struct {
+	macService service.MACService
+}
+
+// NewMACManager creates a new MACManager
+func NewMACManager(macService service.MACService) *MACManager {
+	return &MACManager{
+		macService: macService,
+	}
+}
+
+// GetStatus returns the active framework and its mode
+func (m *MACManager) GetStatus() (*model.MACStatus, error) {
+	return m.macService.GetStatus()
+}
+
+// Enforce installs a framework and puts it in enforcing mode; an empty
+// framework picks the active one or the distribution's default
+func (m *MACManager) Enforce(framework string, sshPort int) (*model.MACStatus, error) {
+	return m.macService.Enforce(framework, sshPort)
+}
@@ -0,0 +1,43 @@
+// pkg/application/maintenance_manager.go
+package application
+
+import (
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// MaintenanceManager is an application service for maintenance window enforcement
+type MaintenanceManager struct {
+	maintenanceService service.MaintenanceService
+	now                func() time.Time
+}
+
+// NewMaintenanceManager creates a new MaintenanceManager
+func NewMaintenanceManager(maintenanceService service.MaintenanceService) *MaintenanceManager {
+	return &MaintenanceManager{
+		maintenanceService: maintenanceService,
+		now:                time.Now,
+	}
+}
+
+// HasWindows reports whether maintenance windows are configured
+func (m *MaintenanceManager) HasWindows() bool {
+	return m.maintenanceService.HasWindows()
+}
+
+// InWindow reports whether changes may be applied now
+func (m *MaintenanceManager) InWindow() bool {
+	return m.maintenanceService.InWindow(m.now())
+}
+
+// NextWindow returns when the next maintenance window opens
+func (m *MaintenanceManager) NextWindow() (time.Time, bool) {
+	return m.maintenanceService.NextWindow(m.now())
+}
+
+// CheckMutation returns an error when changes may not be applied now and
+// override is not set
+func (m *MaintenanceManager) CheckMutation(override bool) error {
+	return m.maintenanceService.CheckMutation(m.now(), override)
+}
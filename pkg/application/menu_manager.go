@@ -2,7 +2,6 @@
 package application
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/abbott/hardn/pkg/domain/model"
@@ -20,6 +19,18 @@ type MenuManager struct {
 	environmentManager *EnvironmentManager
 	logsManager        *LogsManager
 	hostInfoManager    *HostInfoManager
+	snapshotManager    *SnapshotManager
+	scheduleManager    *ScheduleManager
+	runManager         *RunManager
+	lynisManager       *LynisManager
+	updatesManager     *UpdatesManager
+	exposureManager    *NetworkExposureManager
+	proxmoxManager     *ProxmoxManager
+	bootManager        *BootHardeningManager
+	timeSyncManager    *TimeSyncManager
+	historyManager     *PostureHistoryManager
+	sudoersManager     *SudoersManager
+	debloatManager     *DebloatManager
 }
 
 // In the struct definition:
@@ -34,6 +45,18 @@ func NewMenuManager(
 	environmentManager *EnvironmentManager,
 	logsManager *LogsManager,
 	hostInfoManager *HostInfoManager,
+	snapshotManager *SnapshotManager,
+	scheduleManager *ScheduleManager,
+	runManager *RunManager,
+	lynisManager *LynisManager,
+	updatesManager *UpdatesManager,
+	exposureManager *NetworkExposureManager,
+	proxmoxManager *ProxmoxManager,
+	bootManager *BootHardeningManager,
+	timeSyncManager *TimeSyncManager,
+	historyManager *PostureHistoryManager,
+	sudoersManager *SudoersManager,
+	debloatManager *DebloatManager,
 ) *MenuManager {
 	return &MenuManager{
 		userManager:        userManager,
@@ -46,30 +69,65 @@ func NewMenuManager(
 		environmentManager: environmentManager,
 		logsManager:        logsManager,
 		hostInfoManager:    hostInfoManager,
+		snapshotManager:    snapshotManager,
+		scheduleManager:    scheduleManager,
+		runManager:         runManager,
+		lynisManager:       lynisManager,
+		updatesManager:     updatesManager,
+		exposureManager:    exposureManager,
+		proxmoxManager:     proxmoxManager,
+		bootManager:        bootManager,
+		timeSyncManager:    timeSyncManager,
+		historyManager:     historyManager,
+		sudoersManager:     sudoersManager,
+		debloatManager:     debloatManager,
 	}
 }
 
 // create a user with the specified settings
 func (m *MenuManager) CreateUser(username string, hasSudo bool, sudoNoPassword bool, sshKeys []string) error {
-	// Create the user
-	err := m.userManager.CreateUser(username, hasSudo, sudoNoPassword, sshKeys)
-	if err != nil {
-		return err
-	}
+	// The user repository also installs the provided SSH keys
+	return m.userManager.CreateUser(username, hasSudo, sudoNoPassword, sshKeys)
+}
 
-	// If SSH keys are provided, ensure they're added
-	for _, key := range sshKeys {
-		if err := m.sshManager.AddSSHKey(username, key); err != nil {
-			return fmt.Errorf("error adding SSH key: %w", err)
-		}
-	}
+// check the users of an import file without creating any
+func (m *MenuManager) PlanUserImport(path string, defaultNoPassword bool) (*model.UserImportPlan, error) {
+	return m.userManager.PlanUserImport(path, defaultNoPassword)
+}
+
+// create the users of a checked import plan
+func (m *MenuManager) ImportUsers(plan *model.UserImportPlan) ([]model.UserImportResult, error) {
+	return m.userManager.ImportUsers(plan)
+}
+
+// check what restricting a user to a sudo template changes
+func (m *MenuManager) PlanSudoTemplate(username, template string, noPassword bool) (*model.SudoTemplatePlan, error) {
+	return m.sudoersManager.PlanSudoTemplate(username, template, noPassword)
+}
 
-	return nil
+// restrict a user to the commands of a checked sudo template plan
+func (m *MenuManager) ApplySudoTemplate(plan *model.SudoTemplatePlan) error {
+	return m.sudoersManager.ApplySudoTemplate(plan)
 }
 
 // add an SSH key for the specified user
 func (m *MenuManager) AddSSHKey(username, publicKey string) error {
-	return m.sshManager.AddSSHKey(username, publicKey)
+	return m.userManager.AddSSHKey(username, publicKey)
+}
+
+// import the SSH keys a GitHub or GitLab account publishes for the specified user
+func (m *MenuManager) ImportSSHKeysFromProvider(username, provider, account string, dryRun bool) (*model.SSHKeyImport, error) {
+	return m.userManager.ImportSSHKeysFromProvider(username, provider, account, dryRun)
+}
+
+// remove the SSH key with the given fingerprint from the specified user
+func (m *MenuManager) RemoveSSHKey(username, fingerprint string) error {
+	return m.userManager.RemoveSSHKey(username, fingerprint)
+}
+
+// compute the SHA256 fingerprint of an SSH public key
+func (m *MenuManager) SSHKeyFingerprint(publicKey string) (string, error) {
+	return model.SSHKeyFingerprint(publicKey)
 }
 
 // disable SSH access for the root user
@@ -77,19 +135,159 @@ func (m *MenuManager) DisableRootSSH() error {
 	return m.sshManager.DisableRootSSH()
 }
 
+// configure SSH with the specified settings
+func (m *MenuManager) ConfigureSSH(port int, listenAddresses []string, permitRootLogin bool, allowedUsers []string, keyPaths []string, managedUser string, allowAllUsers bool) error {
+	return m.sshManager.ConfigureSSH(port, listenAddresses, permitRootLogin, allowedUsers, keyPaths, managedUser, allowAllUsers)
+}
+
+// apply a named SSH hardening profile
+func (m *MenuManager) ApplySSHProfile(profile string) error {
+	return m.sshManager.ApplyProfile(profile)
+}
+
+// compare the configured SSH policy with the settings sshd runs with
+func (m *MenuManager) CompareEffectiveSSHConfig(port int, listenAddresses []string, allowedUsers []string, managedUser string, allowAllUsers bool, profile string) (*model.SSHEffectiveReport, error) {
+	return m.sshManager.CompareEffectiveConfig(port, listenAddresses, allowedUsers, managedUser, allowAllUsers, profile)
+}
+
+// get the SSH hardening profile in effect
+func (m *MenuManager) GetSSHProfile() (string, error) {
+	return m.sshManager.GetProfile()
+}
+
+// resolve the SSH AllowUsers list that would be written
+func (m *MenuManager) ResolveSSHAllowedUsers(allowedUsers []string, managedUser string, allowAllUsers bool) ([]string, error) {
+	return m.sshManager.ResolveAllowedUsers(allowedUsers, managedUser, allowAllUsers)
+}
+
+// validate an SSH listen address
+func (m *MenuManager) ValidateSSHListenAddress(addr string) error {
+	return m.sshManager.ValidateListenAddress(addr)
+}
+
+// check whether an SSH listen address binds every interface
+func (m *MenuManager) IsWildcardSSHListenAddress(addr string) bool {
+	return m.sshManager.IsWildcardListenAddress(addr)
+}
+
+// check whether SSH login is permitted for the root user
+func (m *MenuManager) IsRootLoginEnabled() (bool, error) {
+	return m.sshManager.IsRootLoginEnabled()
+}
+
+// enable or disable SSH key lookup for directory users
+func (m *MenuManager) ConfigureAuthorizedKeysCommand(command string, runAsUser string) error {
+	return m.sshManager.ConfigureAuthorizedKeysCommand(command, runAsUser)
+}
+
+// retrieve the configured AuthorizedKeysCommand
+func (m *MenuManager) GetAuthorizedKeysCommand() (string, error) {
+	return m.sshManager.GetAuthorizedKeysCommand()
+}
+
+// report whether users are resolved from a directory service
+func (m *MenuManager) GetDirectoryAuthStatus() (*model.DirectoryAuthStatus, error) {
+	return m.userManager.GetDirectoryAuthStatus()
+}
+
+// report whether a user is provided by a directory service
+func (m *MenuManager) IsDirectoryUser(username string) (bool, error) {
+	return m.userManager.IsDirectoryUser(username)
+}
+
 // apply comprehensive system hardening
 func (m *MenuManager) HardenSystem(config *model.HardeningConfig) error {
 	return m.securityManager.HardenSystem(config)
 }
 
+// list the steps HardenSystem runs for config
+func (m *MenuManager) HardeningSteps(config *model.HardeningConfig) []string {
+	return m.securityManager.HardeningSteps(config)
+}
+
+// set where system hardening reports its steps
+func (m *MenuManager) SetProgressReporter(progress model.ProgressReporter) {
+	m.securityManager.SetProgressReporter(progress)
+}
+
 // configure DNS with the specified nameservers
 func (m *MenuManager) ConfigureDNS(nameservers []string, domain string) error {
 	return m.dnsManager.ConfigureDNS(nameservers, domain)
 }
 
+// retrieve the current DNS configuration
+func (m *MenuManager) GetDNSConfig() (*model.DNSConfig, error) {
+	return m.dnsManager.GetCurrentConfig()
+}
+
 // configure the firewall with secure settings
-func (m *MenuManager) ConfigureSecureFirewall(sshPort int, allowedPorts []int, profiles []model.FirewallProfile) error {
-	return m.firewallManager.ConfigureSecureFirewall(sshPort, allowedPorts, profiles)
+func (m *MenuManager) ConfigureSecureFirewall(ssh model.SSHFirewallPolicy, ipv6 model.IPv6Policy, allowedPorts []int, rules []model.FirewallRule, profiles []model.FirewallProfile) error {
+	return m.firewallManager.ConfigureSecureFirewall(ssh, ipv6, allowedPorts, rules, profiles)
+}
+
+// compare the firewall ConfigureSecureFirewall sets up with the live ruleset
+func (m *MenuManager) CheckFirewallDrift(ssh model.SSHFirewallPolicy, ipv6 model.IPv6Policy, allowedPorts []int, rules []model.FirewallRule, profiles []model.FirewallProfile) (*model.FirewallDrift, error) {
+	return m.firewallManager.CheckDrift(ssh, ipv6, allowedPorts, rules, profiles)
+}
+
+// report how other hosts reach this one over IPv6
+func (m *MenuManager) CheckIPv6Exposure(ssh model.SSHFirewallPolicy, ipv6 model.IPv6Policy, allowedPorts []int, rules []model.FirewallRule, profiles []model.FirewallProfile) (*model.IPv6Exposure, error) {
+	return m.firewallManager.CheckIPv6(ssh, ipv6, allowedPorts, rules, profiles)
+}
+
+// set up knockd for the SSH port, or stop it without port knocking
+func (m *MenuManager) ConfigurePortKnocking(ssh model.SSHFirewallPolicy) error {
+	return m.firewallManager.ConfigurePortKnocking(ssh)
+}
+
+// report whether the firewall rate-limits SSH and whether knockd guards it
+func (m *MenuManager) GetSSHAccess(sshPort int) (*model.SSHAccess, error) {
+	return m.firewallManager.GetSSHAccess(sshPort)
+}
+
+// check a firewall rule and return the command that adds it
+func (m *MenuManager) PreviewFirewallRule(rule model.FirewallRule) (string, error) {
+	return m.firewallManager.PreviewRule(rule)
+}
+
+// add a firewall rule
+func (m *MenuManager) AddFirewallRule(rule model.FirewallRule) error {
+	return m.firewallManager.AddRule(rule)
+}
+
+// remove a firewall rule
+func (m *MenuManager) RemoveFirewallRule(rule model.FirewallRule) error {
+	return m.firewallManager.RemoveRule(rule)
+}
+
+// parse a port or port range
+func (m *MenuManager) ParsePortRange(spec string) (int, int, error) {
+	return m.firewallManager.ParsePortRange(spec)
+}
+
+// check a knock sequence for the SSH port
+func (m *MenuManager) ValidatePortKnocking(sshPort int, knocking model.PortKnocking) error {
+	return m.firewallManager.ValidatePortKnocking(sshPort, knocking)
+}
+
+// install the firewall package
+func (m *MenuManager) InstallFirewall() error {
+	return m.firewallManager.InstallFirewall()
+}
+
+// enable the firewall
+func (m *MenuManager) EnableFirewall() error {
+	return m.firewallManager.EnableFirewall()
+}
+
+// disable the firewall
+func (m *MenuManager) DisableFirewall() error {
+	return m.firewallManager.DisableFirewall()
+}
+
+// write and enable firewall application profiles
+func (m *MenuManager) ApplyFirewallProfiles(profiles []model.FirewallProfile) error {
+	return m.firewallManager.ApplyProfiles(profiles)
 }
 
 // install Linux packages based on the specified type
@@ -112,6 +310,71 @@ func (m *MenuManager) UpdateProxmoxSources() error {
 	return m.packageManager.UpdateProxmoxSources()
 }
 
+// return the format repositories are written in, list or deb822
+func (m *MenuManager) SourcesFormat() (string, error) {
+	return m.packageManager.SourcesFormat()
+}
+
+// read a repository list, such as an APT list or /etc/apk/repositories
+func (m *MenuManager) ReadSourceFile(path string) ([]byte, error) {
+	return m.packageManager.ReadSourceFile(path)
+}
+
+// report whether hardn manages automatic upgrades on this OS
+func (m *MenuManager) AutoUpgradesSupported() bool {
+	return m.packageManager.AutoUpgradesSupported()
+}
+
+// enable or disable scheduled package upgrades
+func (m *MenuManager) ConfigureAutoUpgrades(enable bool) error {
+	return m.packageManager.ConfigureAutoUpgrades(enable)
+}
+
+// check if scheduled package upgrades are enabled
+func (m *MenuManager) IsAutoUpgradeEnabled() (bool, error) {
+	return m.packageManager.IsAutoUpgradeEnabled()
+}
+
+// report whether hardn writes the automatic upgrade policy on this OS
+func (m *MenuManager) UpdatesSupported() bool {
+	return m.updatesManager.UpdatesSupported()
+}
+
+// fill in the defaults of an upgrade policy
+func (m *MenuManager) EffectiveUpdatesPolicy(policy model.UpdatesPolicy) model.UpdatesPolicy {
+	return m.updatesManager.EffectivePolicy(policy)
+}
+
+// write the upgrade policy and enable automatic upgrades
+func (m *MenuManager) ConfigureUpdates(policy model.UpdatesPolicy) error {
+	return m.updatesManager.ConfigureUpdates(policy)
+}
+
+// stop automatic upgrades
+func (m *MenuManager) DisableUpdates() error {
+	return m.updatesManager.DisableUpdates()
+}
+
+// report whether automatic upgrades are enabled
+func (m *MenuManager) GetUpdatesStatus() (*model.UpdatesStatus, error) {
+	return m.updatesManager.GetUpdatesStatus()
+}
+
+// report whether needrestart is available on this OS
+func (m *MenuManager) NeedrestartSupported() bool {
+	return m.packageManager.NeedrestartSupported()
+}
+
+// install needrestart with the given restart mode
+func (m *MenuManager) ConfigureNeedrestart(mode string) error {
+	return m.packageManager.ConfigureNeedrestart(mode)
+}
+
+// report services and kernel waiting on a restart
+func (m *MenuManager) GetNeedrestartStatus() (*model.NeedrestartStatus, error) {
+	return m.packageManager.GetNeedrestartStatus()
+}
+
 // retrieve the current status of the firewall
 func (m *MenuManager) GetFirewallStatus() (bool, bool, bool, []string, error) {
 	return m.firewallManager.GetFirewallStatus()
@@ -127,6 +390,41 @@ func (m *MenuManager) VerifyBackupPath() (bool, error) {
 	return m.backupManager.VerifyBackupPath()
 }
 
+// test the keys that encrypt and decrypt backups
+func (m *MenuManager) CheckBackupKeys() (*model.BackupKeyStatus, error) {
+	return m.backupManager.CheckBackupKeys()
+}
+
+// list every backup grouped by the file it was taken from
+func (m *MenuManager) ListBackupGroups() ([]model.BackupGroup, error) {
+	return m.backupManager.ListBackupGroups()
+}
+
+// read a backup and the live file it would replace
+func (m *MenuManager) CompareBackup(backupPath, originalPath string) (*model.BackupComparison, error) {
+	return m.backupManager.CompareBackup(backupPath, originalPath)
+}
+
+// back up a file before it is changed
+func (m *MenuManager) BackupFile(filePath string) error {
+	return m.backupManager.BackupFile(filePath)
+}
+
+// restore a file from a backup
+func (m *MenuManager) RestoreBackup(backupPath, originalPath string) error {
+	return m.backupManager.RestoreBackup(backupPath, originalPath)
+}
+
+// test that the remote backup target is reachable and writable
+func (m *MenuManager) CheckBackupRemote() error {
+	return m.backupManager.CheckRemote()
+}
+
+// mirror the backups to the remote backup target
+func (m *MenuManager) SyncBackupRemote() (*model.BackupSyncResult, error) {
+	return m.backupManager.SyncRemote()
+}
+
 // enable or disables backups
 func (m *MenuManager) ToggleBackups() error {
 	return m.backupManager.ToggleBackups()
@@ -192,6 +490,15 @@ func (m *MenuManager) GetNonSystemUsers() ([]model.User, error) {
 	return m.hostInfoManager.GetNonSystemUsers()
 }
 
+// retrieve a page of non-system users, optionally restricted to local accounts
+func (m *MenuManager) ListNonSystemUsers(localOnly bool, offset, limit int) (*model.UserPage, error) {
+	return m.hostInfoManager.ListNonSystemUsers(model.UserListOptions{
+		LocalOnly: localOnly,
+		Offset:    offset,
+		Limit:     limit,
+	})
+}
+
 // retrieve non-system groups
 func (m *MenuManager) GetNonSystemGroups() ([]string, error) {
 	return m.hostInfoManager.GetNonSystemGroups()
@@ -221,3 +528,93 @@ func (m *MenuManager) FormatBytes(bytes int64) string {
 func (m *MenuManager) GetHostInfoManager() *HostInfoManager {
 	return m.hostInfoManager
 }
+
+// GetNetworkExposureManager returns the network exposure manager
+func (m *MenuManager) GetNetworkExposureManager() *NetworkExposureManager {
+	return m.exposureManager
+}
+
+// GetProxmoxManager returns the Proxmox VE manager
+func (m *MenuManager) GetProxmoxManager() *ProxmoxManager {
+	return m.proxmoxManager
+}
+
+// GetBootHardeningManager returns the boot hardening manager
+func (m *MenuManager) GetBootHardeningManager() *BootHardeningManager {
+	return m.bootManager
+}
+
+// GetTimeSyncManager returns the time synchronization manager
+func (m *MenuManager) GetTimeSyncManager() *TimeSyncManager {
+	return m.timeSyncManager
+}
+
+// GetPostureHistoryManager returns the security posture history manager
+func (m *MenuManager) GetPostureHistoryManager() *PostureHistoryManager {
+	return m.historyManager
+}
+
+// report whether the root filesystem can be snapshotted
+func (m *MenuManager) GetSnapshotSupport() (*model.SnapshotSupport, error) {
+	return m.snapshotManager.GetSnapshotSupport()
+}
+
+// snapshot the root filesystem
+func (m *MenuManager) CreateSnapshot() (*model.Snapshot, error) {
+	return m.snapshotManager.CreateSnapshot()
+}
+
+// build the schedule that would be installed from the configured interval and tasks
+func (m *MenuManager) PlanSchedule(interval string, tasks []string, configFile string, watchdog bool, summaryCron string) (model.Schedule, error) {
+	return m.scheduleManager.PlanSchedule(interval, tasks, configFile, watchdog, summaryCron)
+}
+
+// install a timer or cron entry that re-runs hardn on an interval
+func (m *MenuManager) InstallSchedule(schedule model.Schedule) error {
+	return m.scheduleManager.InstallSchedule(schedule)
+}
+
+// remove the installed schedule
+func (m *MenuManager) RemoveSchedule() error {
+	return m.scheduleManager.RemoveSchedule()
+}
+
+// report the installed schedule
+func (m *MenuManager) GetScheduleStatus() (*model.ScheduleStatus, error) {
+	return m.scheduleManager.GetScheduleStatus()
+}
+
+// run a Lynis scan and return its results
+func (m *MenuManager) RunLynisScan() (*model.LynisReport, error) {
+	return m.lynisManager.RunScan()
+}
+
+// return the results of the last Lynis scan, or nil when Lynis has not run
+func (m *MenuManager) LastLynisReport() (*model.LynisReport, error) {
+	return m.lynisManager.LastReport()
+}
+
+// find the configured insecure packages installed or running here
+func (m *MenuManager) FindInsecurePackages(items []model.DebloatItem) ([]model.DebloatFinding, error) {
+	return m.debloatManager.FindInsecurePackages(items)
+}
+
+// remove one insecure package, or disable its services when others depend on it
+func (m *MenuManager) RemoveInsecurePackage(finding model.DebloatFinding) error {
+	return m.debloatManager.RemoveInsecurePackage(finding)
+}
+
+// list the recorded hardening runs, oldest first
+func (m *MenuManager) ListRuns() ([]model.RunManifest, error) {
+	return m.runManager.ListRuns()
+}
+
+// roll back the selected changes of a run, or all of them if none are selected
+func (m *MenuManager) RollbackRun(id string, selected []int, force bool) (*model.RunRollback, error) {
+	return m.runManager.Rollback(id, selected, force)
+}
+
+// report the latest run to change each hardening area
+func (m *MenuManager) LastApplied() (map[string]model.AreaState, error) {
+	return m.runManager.LastApplied()
+}
@@ -0,0 +1,26 @@
+// pkg/application/migration_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// MigrationManager is an application service for adopting hosts hardened
+// by other tools
+type MigrationManager struct {
+	migrationService service.MigrationService
+}
+
+// NewMigrationManager creates a new MigrationManager
+func NewMigrationManager(migrationService service.MigrationService) *MigrationManager {
+	return &MigrationManager{
+		migrationService: migrationService,
+	}
+}
+
+// ImportHost reads the ufw rules, sshd_config, sudoers.d entries and
+// fail2ban jails on the host and returns the equivalent hardn settings
+func (m *MigrationManager) ImportHost() (*model.MigrationResult, error) {
+	return m.migrationService.ImportHost()
+}
@@ -0,0 +1,36 @@
+// pkg/application/module_blacklist_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// ModuleBlacklistManager is an application service for kernel module blacklists
+type ModuleBlacklistManager struct {
+	moduleBlacklistService service.ModuleBlacklistService
+}
+
+// NewModuleBlacklistManager creates a new ModuleBlacklistManager
+func NewModuleBlacklistManager(moduleBlacklistService service.ModuleBlacklistService) *ModuleBlacklistManager {
+	return &ModuleBlacklistManager{
+		moduleBlacklistService: moduleBlacklistService,
+	}
+}
+
+// EffectiveModules lists the modules the policy blacklists on this host
+func (m *ModuleBlacklistManager) EffectiveModules(policy model.ModuleBlacklistPolicy) []string {
+	return m.moduleBlacklistService.EffectiveModules(policy)
+}
+
+// ApplyBlacklist writes the blacklist of the policy. Modules already
+// loaded stay loaded until they are removed or the host reboots.
+func (m *ModuleBlacklistManager) ApplyBlacklist(policy model.ModuleBlacklistPolicy) error {
+	return m.moduleBlacklistService.ApplyBlacklist(policy)
+}
+
+// GetBlacklistStatus compares the modprobe configuration and the loaded
+// modules with the policy
+func (m *ModuleBlacklistManager) GetBlacklistStatus(policy model.ModuleBlacklistPolicy) (*model.ModuleBlacklistStatus, error) {
+	return m.moduleBlacklistService.GetBlacklistStatus(policy)
+}
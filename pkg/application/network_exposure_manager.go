@@ -0,0 +1,30 @@
+// pkg/application/network_exposure_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// NetworkExposureManager is an application service for inventorying
+// listening services and their firewall exposure
+type NetworkExposureManager struct {
+	exposureService service.NetworkExposureService
+}
+
+// NewNetworkExposureManager creates a new NetworkExposureManager
+func NewNetworkExposureManager(exposureService service.NetworkExposureService) *NetworkExposureManager {
+	return &NetworkExposureManager{
+		exposureService: exposureService,
+	}
+}
+
+// GetNetworkExposure lists the listening sockets and how far each is reachable
+func (m *NetworkExposureManager) GetNetworkExposure() (*model.NetworkExposure, error) {
+	return m.exposureService.GetNetworkExposure()
+}
+
+// AuditExposure reports services reachable without a firewall rule deciding about them
+func (m *NetworkExposureManager) AuditExposure() ([]model.PolicyViolation, error) {
+	return m.exposureService.AuditExposure()
+}
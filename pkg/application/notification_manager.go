@@ -0,0 +1,29 @@
+// pkg/application/notification_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// NotificationManager is an application service for sending run summaries
+type NotificationManager struct {
+	notificationService service.NotificationService
+}
+
+// NewNotificationManager creates a new NotificationManager
+func NewNotificationManager(notificationService service.NotificationService) *NotificationManager {
+	return &NotificationManager{
+		notificationService: notificationService,
+	}
+}
+
+// Notify sends the summary through every configured notifier
+func (m *NotificationManager) Notify(summary model.RunSummary) error {
+	return m.notificationService.Notify(summary)
+}
+
+// Enabled reports whether any notifier is configured
+func (m *NotificationManager) Enabled() bool {
+	return m.notificationService.Enabled()
+}
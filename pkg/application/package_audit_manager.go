@@ -0,0 +1,41 @@
+// pkg/application/package_audit_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// PackageAuditManager is an application service for the package inventory
+// and its security advisories
+type PackageAuditManager struct {
+	packageAuditService service.PackageAuditService
+}
+
+// NewPackageAuditManager creates a new PackageAuditManager
+func NewPackageAuditManager(packageAuditService service.PackageAuditService) *PackageAuditManager {
+	return &PackageAuditManager{
+		packageAuditService: packageAuditService,
+	}
+}
+
+// InstalledPackages lists the installed packages with their versions
+func (m *PackageAuditManager) InstalledPackages() ([]model.InstalledPackage, error) {
+	return m.packageAuditService.InstalledPackages()
+}
+
+// CheckPackages checks the installed packages against the distribution's
+// security advisories and keeps the result for the status display
+func (m *PackageAuditManager) CheckPackages() (*model.PackageAuditReport, error) {
+	return m.packageAuditService.CheckPackages()
+}
+
+// LastSummary returns the advisory counts of the last check, or nil when
+// packages have not been checked yet
+func (m *PackageAuditManager) LastSummary() (*model.PackageAuditSummary, error) {
+	report, err := m.packageAuditService.LastReport()
+	if err != nil || report == nil {
+		return nil, err
+	}
+	return report.Summary(), nil
+}
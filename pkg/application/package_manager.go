@@ -2,6 +2,7 @@
 package application
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/abbott/hardn/pkg/domain/model"
@@ -9,7 +10,6 @@ import (
 	"github.com/abbott/hardn/pkg/interfaces"
 )
 
-// PackageManager is an application service for package management
 // PackageManager is an application service for package management
 type PackageManager struct {
 	packageService service.PackageService
@@ -17,6 +17,7 @@ type PackageManager struct {
 	osInfo         *model.OSInfo
 	networkOps     interfaces.NetworkOperations
 	dmzSubnet      string
+	progress       model.ProgressReporter
 }
 
 // NewPackageManager creates a new PackageManager
@@ -33,9 +34,18 @@ func NewPackageManager(
 		osInfo:         osInfo,
 		networkOps:     networkOps,
 		dmzSubnet:      dmzSubnet,
+		progress:       model.NoProgress{},
 	}
 }
 
+// SetProgressReporter sets where package installs report their progress
+func (m *PackageManager) SetProgressReporter(progress model.ProgressReporter) {
+	if progress == nil {
+		progress = model.NoProgress{}
+	}
+	m.progress = progress
+}
+
 // InstallLinuxPackages installs system packages based on the specified type
 func (m *PackageManager) InstallLinuxPackages(packages []string, packageType string) error {
 	// Create a package installation request
@@ -77,6 +87,57 @@ func (m *PackageManager) UpdateProxmoxSources() error {
 	return m.packageService.UpdateProxmoxSources()
 }
 
+// SourcesFormat returns the format repositories are written in, list or deb822
+func (m *PackageManager) SourcesFormat() (string, error) {
+	return m.packageService.SourcesFormat()
+}
+
+// ReadSourceFile reads a repository list, such as an APT list or /etc/apk/repositories
+func (m *PackageManager) ReadSourceFile(path string) ([]byte, error) {
+	return m.packageService.ReadSourceFile(path)
+}
+
+// MigrateSources converts the repository lists on the host to list or deb822
+func (m *PackageManager) MigrateSources(format string) ([]model.SourcesMigration, error) {
+	return m.packageService.MigrateSources(format)
+}
+
+// ValidateDebianRepo checks that a configured repository is a sources.list entry
+func (m *PackageManager) ValidateDebianRepo(line string) error {
+	return service.ValidateDebianRepo(line)
+}
+
+// AutoUpgradesSupported reports whether hardn manages automatic upgrades on this OS
+func (m *PackageManager) AutoUpgradesSupported() bool {
+	return m.packageService.AutoUpgradesSupported()
+}
+
+// ConfigureAutoUpgrades enables or disables scheduled package upgrades
+func (m *PackageManager) ConfigureAutoUpgrades(enable bool) error {
+	return m.packageService.ConfigureAutoUpgrades(enable)
+}
+
+// IsAutoUpgradeEnabled checks if scheduled package upgrades are enabled
+func (m *PackageManager) IsAutoUpgradeEnabled() (bool, error) {
+	return m.packageService.IsAutoUpgradeEnabled()
+}
+
+// NeedrestartSupported reports whether needrestart is available on this OS
+func (m *PackageManager) NeedrestartSupported() bool {
+	return m.packageService.NeedrestartSupported()
+}
+
+// ConfigureNeedrestart installs needrestart with the given restart mode
+func (m *PackageManager) ConfigureNeedrestart(mode string) error {
+	m.progress.StepProgress(0, "installing needrestart")
+	return m.packageService.ConfigureNeedrestart(mode)
+}
+
+// GetNeedrestartStatus reports services and kernel waiting on a restart
+func (m *PackageManager) GetNeedrestartStatus() (*model.NeedrestartStatus, error) {
+	return m.packageService.GetNeedrestartStatus()
+}
+
 // InstallAllLinuxPackages installs all appropriate packages based on OS type and environment
 func (m *PackageManager) InstallAllLinuxPackages() error {
 	// Check if we're in a DMZ subnet
@@ -92,6 +153,13 @@ func (m *PackageManager) InstallAllLinuxPackages() error {
 			dmzPackages = m.config.AlpineDmzPackages
 			labPackages = m.config.AlpineLabPackages
 		}
+	} else if model.IsRHELFamily(m.osInfo.Type) {
+		// Get Rocky, AlmaLinux and Fedora packages from the configuration
+		if m.config != nil {
+			corePackages = m.config.RhelCorePackages
+			dmzPackages = m.config.RhelDmzPackages
+			labPackages = m.config.RhelLabPackages
+		}
 	} else {
 		// Get Debian/Ubuntu packages from the configuration
 		if m.config != nil {
@@ -101,30 +169,40 @@ func (m *PackageManager) InstallAllLinuxPackages() error {
 		}
 	}
 
-	// Install core packages
-	if len(corePackages) > 0 {
-		if err := m.InstallLinuxPackages(corePackages, "core"); err != nil {
-			return err
-		}
+	// Lab packages are only installed outside the DMZ
+	groups := []packageGroup{{"core", corePackages}, {"dmz", dmzPackages}}
+	if !isDMZ {
+		groups = append(groups, packageGroup{"lab", labPackages})
 	}
 
-	// Install DMZ packages
-	if len(dmzPackages) > 0 {
-		if err := m.InstallLinuxPackages(dmzPackages, "dmz"); err != nil {
-			return err
+	var pending []packageGroup
+	for _, group := range groups {
+		if len(group.packages) > 0 {
+			pending = append(pending, group)
 		}
 	}
 
-	// Install lab packages if not in DMZ
-	if !isDMZ && len(labPackages) > 0 {
-		if err := m.InstallLinuxPackages(labPackages, "lab"); err != nil {
+	// Each group is one install; progress moves on as each one finishes
+	for i, group := range pending {
+		m.progress.StepProgress(i*100/len(pending),
+			fmt.Sprintf("installing %d %s packages", len(group.packages), group.packageType))
+		if err := m.InstallLinuxPackages(group.packages, group.packageType); err != nil {
 			return err
 		}
 	}
+	if len(pending) > 0 {
+		m.progress.StepProgress(100, "")
+	}
 
 	return nil
 }
 
+// packageGroup is a set of packages installed together
+type packageGroup struct {
+	packageType string
+	packages    []string
+}
+
 // InstallAllPythonPackages installs all appropriate Python packages based on OS type
 func (m *PackageManager) InstallAllPythonPackages(useUv bool) error {
 	var systemPackages []string
@@ -135,6 +213,12 @@ func (m *PackageManager) InstallAllPythonPackages(useUv bool) error {
 		if m.config != nil {
 			systemPackages = m.config.AlpinePythonPackages
 		}
+	} else if model.IsRHELFamily(m.osInfo.Type) {
+		// Get Rocky, AlmaLinux and Fedora Python packages
+		if m.config != nil {
+			systemPackages = m.config.RhelPythonPackages
+			pipPackages = m.config.PythonPipPackages
+		}
 	} else {
 		// Get Debian/Ubuntu Python packages
 		if m.config != nil {
@@ -151,7 +235,11 @@ func (m *PackageManager) InstallAllPythonPackages(useUv bool) error {
 
 	// Install Python packages
 	if len(systemPackages) > 0 || len(pipPackages) > 0 {
-		return m.InstallPythonPackages(systemPackages, pipPackages, useUv)
+		m.progress.StepProgress(0, fmt.Sprintf("installing %d Python packages", len(systemPackages)+len(pipPackages)))
+		if err := m.InstallPythonPackages(systemPackages, pipPackages, useUv); err != nil {
+			return err
+		}
+		m.progress.StepProgress(100, "")
 	}
 
 	return nil
@@ -0,0 +1,37 @@
+// pkg/application/password_policy_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// PasswordPolicyManager is an application service for password strength,
+// aging and account lockout
+type PasswordPolicyManager struct {
+	passwordPolicyService service.PasswordPolicyService
+}
+
+// NewPasswordPolicyManager creates a new PasswordPolicyManager
+func NewPasswordPolicyManager(passwordPolicyService service.PasswordPolicyService) *PasswordPolicyManager {
+	return &PasswordPolicyManager{
+		passwordPolicyService: passwordPolicyService,
+	}
+}
+
+// ConfigurePasswordPolicy applies the password policy. On hosts without
+// PAM only the password aging is applied.
+func (m *PasswordPolicyManager) ConfigurePasswordPolicy(policy model.PasswordPolicy) error {
+	return m.passwordPolicyService.ConfigurePasswordPolicy(policy)
+}
+
+// PamSupported reports whether the complexity and lockout rules can be
+// applied on this host
+func (m *PasswordPolicyManager) PamSupported() bool {
+	return m.passwordPolicyService.PamSupported()
+}
+
+// GetPasswordPolicyStatus reports the password policy in effect
+func (m *PasswordPolicyManager) GetPasswordPolicyStatus() (*model.PasswordPolicyStatus, error) {
+	return m.passwordPolicyService.GetPasswordPolicyStatus()
+}
@@ -0,0 +1,24 @@
+// pkg/application/policy_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// PolicyManager is an application service for evaluating operator policies
+type PolicyManager struct {
+	policyService service.PolicyService
+}
+
+// NewPolicyManager creates a new PolicyManager
+func NewPolicyManager(policyService service.PolicyService) *PolicyManager {
+	return &PolicyManager{
+		policyService: policyService,
+	}
+}
+
+// Evaluate evaluates the policies in policyDir against the facts document
+func (m *PolicyManager) Evaluate(policyDir string, facts *model.Facts) (*model.PolicyReport, error) {
+	return m.policyService.Evaluate(policyDir, facts)
+}
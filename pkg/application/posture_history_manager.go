@@ -0,0 +1,36 @@
+// pkg/application/posture_history_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// PostureHistoryManager is an application service for the security
+// posture history
+type PostureHistoryManager struct {
+	historyService service.PostureHistoryService
+}
+
+// NewPostureHistoryManager creates a new PostureHistoryManager
+func NewPostureHistoryManager(historyService service.PostureHistoryService) *PostureHistoryManager {
+	return &PostureHistoryManager{
+		historyService: historyService,
+	}
+}
+
+// RecordSnapshot adds a snapshot to the history and returns the one
+// before it, or nil if it is the first
+func (m *PostureHistoryManager) RecordSnapshot(snapshot model.PostureSnapshot) (*model.PostureSnapshot, error) {
+	return m.historyService.RecordSnapshot(snapshot)
+}
+
+// ListSnapshots returns the latest limit snapshots, oldest first
+func (m *PostureHistoryManager) ListSnapshots(limit int) ([]model.PostureSnapshot, error) {
+	return m.historyService.ListSnapshots(limit)
+}
+
+// LastSnapshot returns the latest snapshot, or nil if there is none
+func (m *PostureHistoryManager) LastSnapshot() (*model.PostureSnapshot, error) {
+	return m.historyService.LastSnapshot()
+}
@@ -0,0 +1,33 @@
+// pkg/application/posture_summary_manager.go
+package application
+
+import (
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// PostureSummaryManager is an application service for the periodic
+// posture summary
+type PostureSummaryManager struct {
+	summaryService service.PostureSummaryService
+}
+
+// NewPostureSummaryManager creates a new PostureSummaryManager
+func NewPostureSummaryManager(summaryService service.PostureSummaryService) *PostureSummaryManager {
+	return &PostureSummaryManager{
+		summaryService: summaryService,
+	}
+}
+
+// BuildSummary sums up the posture of the days before until
+func (m *PostureSummaryManager) BuildSummary(until time.Time, days int) (*model.PostureSummary, error) {
+	return m.summaryService.BuildSummary(until, days)
+}
+
+// FleetSummary collects the posture summaries the hosts of an inventory
+// printed for the days before until
+func (m *PostureSummaryManager) FleetSummary(until time.Time, days int, results []model.RemoteResult) *model.FleetPostureSummary {
+	return service.FleetSummary(until, days, results)
+}
@@ -0,0 +1,40 @@
+// pkg/application/profile_bundle_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// ProfileBundleManager is an application service for exporting and
+// importing hardening profile bundles
+type ProfileBundleManager struct {
+	bundleService service.ProfileBundleService
+}
+
+// NewProfileBundleManager creates a new ProfileBundleManager
+func NewProfileBundleManager(bundleService service.ProfileBundleService) *ProfileBundleManager {
+	return &ProfileBundleManager{
+		bundleService: bundleService,
+	}
+}
+
+// ExportBundle writes this host's profile to a bundle
+func (m *ProfileBundleManager) ExportBundle(request model.ProfileExportRequest) (*model.ProfileExport, error) {
+	return m.bundleService.ExportBundle(request)
+}
+
+// LoadBundle reads and checks a bundle and its signature
+func (m *ProfileBundleManager) LoadBundle(path string, requireSignature bool, signers []string) (*model.ProfileImport, error) {
+	return m.bundleService.LoadBundle(path, requireSignature, signers)
+}
+
+// PlanImport lists the files importing a bundle changes
+func (m *ProfileBundleManager) PlanImport(bundle *model.ProfileBundle, configPath string) ([]model.ProfileChange, error) {
+	return m.bundleService.PlanImport(bundle, configPath)
+}
+
+// ApplyImport writes the planned files and applies the sysctl settings
+func (m *ProfileBundleManager) ApplyImport(changes []model.ProfileChange) error {
+	return m.bundleService.ApplyImport(changes)
+}
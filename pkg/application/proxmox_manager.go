@@ -0,0 +1,66 @@
+// pkg/application/proxmox_manager.go
+package application
+
+import (
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// ProxmoxManager is an application service for hardening a Proxmox VE host
+type ProxmoxManager struct {
+	proxmoxService  service.ProxmoxService
+	firewallService service.FirewallService
+}
+
+// NewProxmoxManager creates a new ProxmoxManager
+func NewProxmoxManager(proxmoxService service.ProxmoxService, firewallService service.FirewallService) *ProxmoxManager {
+	return &ProxmoxManager{
+		proxmoxService:  proxmoxService,
+		firewallService: firewallService,
+	}
+}
+
+// ApplyHardening applies a Proxmox VE policy. With an allowlist, the web UI
+// port is opened to each source in the firewall before its open rule is
+// removed, so the current session is not cut off.
+func (m *ProxmoxManager) ApplyHardening(policy model.ProxmoxHardening) error {
+	if err := service.ValidateProxmoxAllowlist(policy.WebUIAllowlist); err != nil {
+		return err
+	}
+
+	if len(policy.WebUIAllowlist) > 0 {
+		installed, enabled, _, _, err := m.firewallService.GetFirewallStatus()
+		if err != nil {
+			return fmt.Errorf("failed to get firewall status: %w", err)
+		}
+		if installed && enabled {
+			for _, source := range policy.WebUIAllowlist {
+				rule := model.FirewallRule{
+					Action:      "allow",
+					Protocol:    "tcp",
+					Port:        model.ProxmoxWebUIPort,
+					SourceIP:    source,
+					Description: "Proxmox VE web UI (hardn)",
+				}
+				if err := m.firewallService.AddRule(rule); err != nil {
+					return err
+				}
+			}
+			// The port may never have been open to everyone
+			_ = m.firewallService.RemoveRule(model.FirewallRule{
+				Action:   "allow",
+				Protocol: "tcp",
+				Port:     model.ProxmoxWebUIPort,
+			})
+		}
+	}
+
+	return m.proxmoxService.ApplyHardening(policy)
+}
+
+// GetStatus reports the Proxmox VE hardening in effect
+func (m *ProxmoxManager) GetStatus() (*model.ProxmoxStatus, error) {
+	return m.proxmoxService.GetStatus()
+}
@@ -0,0 +1,34 @@
+// pkg/application/remote_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// RemoteManager is an application service for running hardn on other hosts
+type RemoteManager struct {
+	remoteService service.RemoteService
+}
+
+// NewRemoteManager creates a new RemoteManager
+func NewRemoteManager(remoteService service.RemoteService) *RemoteManager {
+	return &RemoteManager{
+		remoteService: remoteService,
+	}
+}
+
+// LoadInventory reads an inventory from a YAML file
+func (m *RemoteManager) LoadInventory(path string) (*model.RemoteInventory, error) {
+	return m.remoteService.LoadInventory(path)
+}
+
+// SelectHosts picks the hosts of the inventory matching names or groups
+func (m *RemoteManager) SelectHosts(inventory *model.RemoteInventory, patterns []string) ([]model.RemoteHost, error) {
+	return m.remoteService.SelectHosts(inventory, patterns)
+}
+
+// Run runs hardn on the hosts of the request, one result per host
+func (m *RemoteManager) Run(request model.RemoteRequest) []model.RemoteResult {
+	return m.remoteService.Run(request)
+}
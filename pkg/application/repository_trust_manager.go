@@ -0,0 +1,43 @@
+// pkg/application/repository_trust_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// RepositoryTrustManager is an application service for checking how
+// packages are verified and installing repository signing keys
+type RepositoryTrustManager struct {
+	trustService service.RepositoryTrustService
+}
+
+// NewRepositoryTrustManager creates a new RepositoryTrustManager
+func NewRepositoryTrustManager(trustService service.RepositoryTrustService) *RepositoryTrustManager {
+	return &RepositoryTrustManager{
+		trustService: trustService,
+	}
+}
+
+// CheckTrust reports global keys, trusted repositories and insecure APT options
+func (m *RepositoryTrustManager) CheckTrust() ([]model.RepoTrustFinding, error) {
+	return m.trustService.CheckTrust()
+}
+
+// AuditFindings returns the repository trust findings for inclusion in an audit
+func (m *RepositoryTrustManager) AuditFindings() ([]model.PolicyViolation, error) {
+	findings, err := m.trustService.CheckTrust()
+	if err != nil {
+		return nil, err
+	}
+	violations := make([]model.PolicyViolation, 0, len(findings))
+	for _, finding := range findings {
+		violations = append(violations, finding.Violation())
+	}
+	return violations, nil
+}
+
+// InstallKey installs a checked repository key and the repositories it signs
+func (m *RepositoryTrustManager) InstallKey(key model.RepositoryKey) (*model.RepositoryKeyResult, error) {
+	return m.trustService.InstallKey(key)
+}
@@ -0,0 +1,40 @@
+// pkg/application/run_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// RunManager is an application service for rolling back hardening runs
+type RunManager struct {
+	runService service.RunService
+}
+
+// NewRunManager creates a new RunManager
+func NewRunManager(runService service.RunService) *RunManager {
+	return &RunManager{
+		runService: runService,
+	}
+}
+
+// ListRuns returns the recorded runs, oldest first
+func (m *RunManager) ListRuns() ([]model.RunManifest, error) {
+	return m.runService.ListRuns()
+}
+
+// GetRun returns the run with the given ID
+func (m *RunManager) GetRun(id string) (*model.RunManifest, error) {
+	return m.runService.GetRun(id)
+}
+
+// Rollback reverts the selected changes of a run, or all of them if none
+// are selected
+func (m *RunManager) Rollback(id string, selected []int, force bool) (*model.RunRollback, error) {
+	return m.runService.Rollback(id, selected, force)
+}
+
+// LastApplied returns the latest run to change each hardening area
+func (m *RunManager) LastApplied() (map[string]model.AreaState, error) {
+	return m.runService.LastApplied()
+}
@@ -0,0 +1,40 @@
+// pkg/application/run_report_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// RunReportManager is an application service for hardening run reports
+type RunReportManager struct {
+	reportService service.RunReportService
+}
+
+// NewRunReportManager creates a new RunReportManager
+func NewRunReportManager(reportService service.RunReportService) *RunReportManager {
+	return &RunReportManager{
+		reportService: reportService,
+	}
+}
+
+// SaveReport completes a report with the changes of run runID, if it
+// changed anything, and its outcome, then saves it
+func (m *RunReportManager) SaveReport(report model.RunReport, runID string) (*model.RunReport, error) {
+	return m.reportService.SaveReport(report, runID)
+}
+
+// GetReport returns the report with the given ID
+func (m *RunReportManager) GetReport(id string) (*model.RunReport, error) {
+	return m.reportService.GetReport(id)
+}
+
+// LastReport returns the latest report
+func (m *RunReportManager) LastReport() (*model.RunReport, error) {
+	return m.reportService.LastReport()
+}
+
+// ListReports returns the latest limit reports, oldest first
+func (m *RunReportManager) ListReports(limit int) ([]model.RunReport, error) {
+	return m.reportService.ListReports(limit)
+}
@@ -0,0 +1,66 @@
+// pkg/application/schedule_manager.go
+package application
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// ScheduleManager is an application service for scheduled hardn runs
+type ScheduleManager struct {
+	scheduleService service.ScheduleService
+	executable      func() (string, error)
+}
+
+// NewScheduleManager creates a new ScheduleManager
+func NewScheduleManager(scheduleService service.ScheduleService) *ScheduleManager {
+	return &ScheduleManager{
+		scheduleService: scheduleService,
+		executable:      os.Executable,
+	}
+}
+
+// PlanSchedule builds the schedule that would be installed, running this
+// hardn binary; an empty interval or task list uses the defaults. With a
+// summary cron expression, the schedule also sends the posture summary.
+func (m *ScheduleManager) PlanSchedule(interval string, tasks []string, configFile string, watchdog bool, summaryCron string) (model.Schedule, error) {
+	if interval == "" {
+		interval = model.DefaultScheduleInterval
+	}
+	if len(tasks) == 0 {
+		tasks = model.DefaultScheduleTasks
+	}
+
+	binary, err := m.executable()
+	if err != nil {
+		return model.Schedule{}, fmt.Errorf("failed to locate hardn binary: %w", err)
+	}
+
+	schedule, err := service.BuildSchedule(interval, tasks, binary, configFile, watchdog)
+	if err != nil || summaryCron == "" {
+		return schedule, err
+	}
+	schedule.Summary, err = service.BuildSummarySchedule(summaryCron, binary, configFile)
+	if err != nil {
+		return model.Schedule{}, fmt.Errorf("schedule.summary.cron: %w", err)
+	}
+	return schedule, nil
+}
+
+// InstallSchedule installs the schedule, replacing any existing one
+func (m *ScheduleManager) InstallSchedule(schedule model.Schedule) error {
+	return m.scheduleService.InstallSchedule(schedule)
+}
+
+// RemoveSchedule removes the installed schedule
+func (m *ScheduleManager) RemoveSchedule() error {
+	return m.scheduleService.RemoveSchedule()
+}
+
+// GetScheduleStatus reports the installed schedule
+func (m *ScheduleManager) GetScheduleStatus() (*model.ScheduleStatus, error) {
+	return m.scheduleService.GetScheduleStatus()
+}
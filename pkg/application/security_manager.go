@@ -7,10 +7,22 @@ import (
 
 // SecurityManager provides high-level security operations combining multiple services
 type SecurityManager struct {
-	userManager     *UserManager
-	sshManager      *SSHManager
-	firewallManager *FirewallManager
-	dnsManager      *DNSManager
+	userManager      *UserManager
+	sshManager       *SSHManager
+	firewallManager  *FirewallManager
+	dnsManager       *DNSManager
+	packageManager   *PackageManager
+	webServerManager *WebServerManager
+	databaseManager  *DatabaseManager
+	macManager       *MACManager
+	updatesManager   *UpdatesManager
+	bannerManager    *BannerManager
+	passwordManager  *PasswordPolicyManager
+	moduleManager    *ModuleBlacklistManager
+	bootManager      *BootHardeningManager
+	timeSyncManager  *TimeSyncManager
+	shippingManager  *LogShippingManager
+	progress         model.ProgressReporter
 }
 
 // NewSecurityManager creates a new SecurityManager
@@ -19,60 +31,268 @@ func NewSecurityManager(
 	sshManager *SSHManager,
 	firewallManager *FirewallManager,
 	dnsManager *DNSManager,
+	packageManager *PackageManager,
+	webServerManager *WebServerManager,
+	databaseManager *DatabaseManager,
+	macManager *MACManager,
+	updatesManager *UpdatesManager,
+	bannerManager *BannerManager,
+	passwordManager *PasswordPolicyManager,
+	moduleManager *ModuleBlacklistManager,
+	bootManager *BootHardeningManager,
+	timeSyncManager *TimeSyncManager,
+	shippingManager *LogShippingManager,
 ) *SecurityManager {
 	return &SecurityManager{
-		userManager:     userManager,
-		sshManager:      sshManager,
-		firewallManager: firewallManager,
-		dnsManager:      dnsManager,
+		userManager:      userManager,
+		sshManager:       sshManager,
+		firewallManager:  firewallManager,
+		dnsManager:       dnsManager,
+		packageManager:   packageManager,
+		webServerManager: webServerManager,
+		databaseManager:  databaseManager,
+		macManager:       macManager,
+		updatesManager:   updatesManager,
+		bannerManager:    bannerManager,
+		passwordManager:  passwordManager,
+		moduleManager:    moduleManager,
+		bootManager:      bootManager,
+		timeSyncManager:  timeSyncManager,
+		shippingManager:  shippingManager,
+		progress:         model.NoProgress{},
 	}
 }
 
-// HardenSystem applies comprehensive system hardening
+// SetProgressReporter sets where HardenSystem reports its steps; the
+// package manager reports the progress of package installs there too
+func (m *SecurityManager) SetProgressReporter(progress model.ProgressReporter) {
+	if progress == nil {
+		progress = model.NoProgress{}
+	}
+	m.progress = progress
+	m.packageManager.SetProgressReporter(progress)
+}
+
+// hardeningStep is one step of HardenSystem
+type hardeningStep struct {
+	name string
+	run  func() error
+}
+
+// HardeningSteps returns the names of the steps HardenSystem runs for config
+func (m *SecurityManager) HardeningSteps(config *model.HardeningConfig) []string {
+	var names []string
+	for _, step := range m.hardeningSteps(config) {
+		names = append(names, step.name)
+	}
+	return names
+}
+
+// HardenSystem applies comprehensive system hardening, stopping at the
+// first step that fails
 func (m *SecurityManager) HardenSystem(config *model.HardeningConfig) error {
-	// Create non-root user if requested
-	if config.CreateUser && config.Username != "" {
-		if err := m.userManager.CreateUser(
-			config.Username,
-			true,
-			config.SudoNoPassword,
-			config.SshKeys,
-		); err != nil {
+	steps := m.hardeningSteps(config)
+
+	var names []string
+	for _, step := range steps {
+		names = append(names, step.name)
+	}
+	m.progress.Plan(names)
+
+	for _, step := range steps {
+		m.progress.StepStarted(step.name)
+		err := step.run()
+		m.progress.StepCompleted(step.name, err)
+		if err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
-	// Configure SSH with secure settings
-	if err := m.sshManager.ConfigureSSH(
-		config.SshPort,
-		config.SshListenAddresses,
-		false, // Never allow root login
-		config.SshAllowedUsers,
-		config.SshKeyPaths,
-	); err != nil {
-		return err
+// hardeningSteps lists the steps config enables, in the order they run
+func (m *SecurityManager) hardeningSteps(config *model.HardeningConfig) []hardeningStep {
+	var steps []hardeningStep
+
+	// Write the password policy before the user is created so that the
+	// aging in login.defs applies to it
+	if config.PasswordPolicy != nil {
+		steps = append(steps, hardeningStep{"Password policy", func() error {
+			return m.passwordManager.ConfigurePasswordPolicy(*config.PasswordPolicy)
+		}})
 	}
 
-	// Configure firewall
-	if config.EnableFirewall {
-		if err := m.firewallManager.ConfigureSecureFirewall(
+	// Create non-root user if requested
+	if config.CreateUser && config.Username != "" {
+		steps = append(steps, hardeningStep{"User account " + config.Username, func() error {
+			return m.userManager.CreateUser(
+				config.Username,
+				true,
+				config.SudoNoPassword,
+				config.SshKeys,
+			)
+		}})
+	}
+
+	// Enforce mandatory access control before sshd moves to its new port;
+	// SELinux must allow the port first
+	if config.EnableAppArmor {
+		steps = append(steps, hardeningStep{"AppArmor", func() error {
+			_, err := m.macManager.Enforce(model.MACAppArmor, config.SshPort)
+			return err
+		}})
+	}
+	if config.EnableSELinux {
+		steps = append(steps, hardeningStep{"SELinux", func() error {
+			_, err := m.macManager.Enforce(model.MACSELinux, config.SshPort)
+			return err
+		}})
+	}
+
+	// Configure SSH with secure settings, then apply the SSH hardening
+	// profile on top of the base settings
+	steps = append(steps, hardeningStep{"SSH configuration", func() error {
+		if err := m.sshManager.ConfigureSSH(
 			config.SshPort,
-			config.AllowedPorts,
-			config.FirewallProfiles,
+			config.SshListenAddresses,
+			false, // Never allow root login
+			config.SshAllowedUsers,
+			config.SshKeyPaths,
+			config.Username,
+			config.SshAllowAllUsers,
 		); err != nil {
 			return err
 		}
+		if config.SshProfile != "" {
+			if err := m.sshManager.ApplyProfile(config.SshProfile); err != nil {
+				return err
+			}
+		}
+		if config.SshConsolidateDropIns {
+			_, err := m.sshManager.ConsolidateConfig()
+			return err
+		}
+		return nil
+	}})
+
+	// Configure firewall
+	if config.EnableFirewall {
+		steps = append(steps, hardeningStep{"Firewall", func() error {
+			if err := m.firewallManager.ConfigureSecureFirewall(
+				model.SSHFirewallPolicy{
+					Port:      config.SshPort,
+					RateLimit: config.SshRateLimit,
+					Knocking:  config.PortKnocking,
+				},
+				config.IPv6,
+				config.AllowedPorts,
+				config.FirewallRules,
+				config.FirewallProfiles,
+			); err != nil {
+				return err
+			}
+			if config.MirrorFirewallStacks {
+				if _, err := m.firewallManager.MirrorDualStack(config.IPv6.Mode); err != nil {
+					return err
+				}
+			}
+			return nil
+		}})
 	}
 
 	// Configure DNS if enabled
 	if config.ConfigureDns {
-		if err := m.dnsManager.ConfigureDNS(
-			config.Nameservers,
-			"lan",
-		); err != nil {
+		steps = append(steps, hardeningStep{"DNS", func() error {
+			return m.dnsManager.ConfigureDNS(
+				config.Nameservers,
+				"lan",
+			)
+		}})
+	}
+
+	// Write the upgrade policy (Debian, Ubuntu and Alpine) or enable
+	// dnf-automatic (RHEL family)
+	if config.EnableUnattendedUpgrades && m.updatesManager.UpdatesSupported() {
+		steps = append(steps, hardeningStep{"Automatic updates", func() error {
+			return m.updatesManager.ConfigureUpdates(config.AutoUpdates)
+		}})
+	} else if config.EnableUnattendedUpgrades && m.packageManager.AutoUpgradesSupported() {
+		steps = append(steps, hardeningStep{"Automatic updates", func() error {
+			return m.packageManager.ConfigureAutoUpgrades(true)
+		}})
+	}
+
+	// Restart services after library upgrades (Debian/Ubuntu)
+	if config.NeedrestartMode != "" && m.packageManager.NeedrestartSupported() {
+		steps = append(steps, hardeningStep{"Service restarts", func() error {
+			return m.packageManager.ConfigureNeedrestart(config.NeedrestartMode)
+		}})
+	}
+
+	// Apply the TLS baseline to any installed web servers
+	if config.EnableWebServerTLS {
+		steps = append(steps, hardeningStep{"Web server TLS baseline", func() error {
+			_, err := m.webServerManager.ApplyTLSBaseline()
 			return err
-		}
+		}})
 	}
 
-	return nil
+	// Apply the database baseline and open the database ports to allowed networks
+	if config.DatabaseHardening != nil {
+		steps = append(steps, hardeningStep{"Database baseline", func() error {
+			databases, err := m.databaseManager.HardenDatabases(*config.DatabaseHardening)
+			if err != nil {
+				return err
+			}
+			for _, database := range databases {
+				for _, subnet := range config.DatabaseHardening.AllowedSubnets {
+					if err := m.firewallManager.AllowPortFrom(database.Port, subnet, database.Name+" (hardn)"); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}})
+	}
+
+	// Keep uncommon filesystems and, if configured, USB storage from loading
+	if config.ModuleBlacklist != nil {
+		steps = append(steps, hardeningStep{"Kernel module blacklist", func() error {
+			return m.moduleManager.ApplyBlacklist(*config.ModuleBlacklist)
+		}})
+	}
+
+	// Set the kernel command line and GRUB password; they take effect at
+	// the next boot
+	if config.BootHardening != nil {
+		steps = append(steps, hardeningStep{"Boot hardening", func() error {
+			return m.bootManager.ApplyHardening(*config.BootHardening)
+		}})
+	}
+
+	// Keep the clock synchronized, with authenticated servers where the
+	// daemon supports NTS
+	if config.TimeSync != nil {
+		steps = append(steps, hardeningStep{"Time synchronization", func() error {
+			return m.timeSyncManager.ApplyTimeSync(*config.TimeSync)
+		}})
+	}
+
+	// Forward the logs to the collector, so they survive a compromise of
+	// the host
+	if config.LogShipping != nil {
+		steps = append(steps, hardeningStep{"Log shipping", func() error {
+			return m.shippingManager.ApplyLogShipping(*config.LogShipping)
+		}})
+	}
+
+	// Write the login banners last; the banner step points sshd at
+	// /etc/issue.net after the SSH configuration is written
+	if config.Banner != nil {
+		steps = append(steps, hardeningStep{"Login banners", func() error {
+			return m.bannerManager.ApplyBanners(*config.Banner)
+		}})
+	}
+
+	return steps
 }
@@ -0,0 +1,26 @@
+// pkg/application/selftest_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// SelfTestManager is an application service for validating the
+// configuration hardn manages
+type SelfTestManager struct {
+	selfTestService service.SelfTestService
+}
+
+// NewSelfTestManager creates a new SelfTestManager
+func NewSelfTestManager(selfTestService service.SelfTestService) *SelfTestManager {
+	return &SelfTestManager{
+		selfTestService: selfTestService,
+	}
+}
+
+// RunSelfTest validates each managed artifact, catching configuration that
+// manual edits broke since hardn wrote it
+func (m *SelfTestManager) RunSelfTest() (*model.SelfTestReport, error) {
+	return m.selfTestService.RunSelfTest()
+}
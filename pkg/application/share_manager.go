@@ -0,0 +1,24 @@
+// pkg/application/share_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// ShareManager is an application service for auditing NFS and Samba shares
+type ShareManager struct {
+	shareService service.ShareService
+}
+
+// NewShareManager creates a new ShareManager
+func NewShareManager(shareService service.ShareService) *ShareManager {
+	return &ShareManager{
+		shareService: shareService,
+	}
+}
+
+// AuditShares reports world-accessible shares and insecure share options
+func (m *ShareManager) AuditShares() ([]model.PolicyViolation, error) {
+	return m.shareService.AuditShares()
+}
@@ -0,0 +1,39 @@
+// pkg/application/snapshot_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// SnapshotManager is an application service for root filesystem snapshots
+type SnapshotManager struct {
+	snapshotService service.SnapshotService
+}
+
+// NewSnapshotManager creates a new SnapshotManager
+func NewSnapshotManager(snapshotService service.SnapshotService) *SnapshotManager {
+	return &SnapshotManager{
+		snapshotService: snapshotService,
+	}
+}
+
+// GetSnapshotSupport reports whether the root filesystem can be snapshotted
+func (m *SnapshotManager) GetSnapshotSupport() (*model.SnapshotSupport, error) {
+	return m.snapshotService.GetSnapshotSupport()
+}
+
+// CreateSnapshot takes a snapshot of the root filesystem
+func (m *SnapshotManager) CreateSnapshot() (*model.Snapshot, error) {
+	return m.snapshotService.CreateSnapshot()
+}
+
+// ListSnapshots returns snapshots created by hardn
+func (m *SnapshotManager) ListSnapshots() ([]model.Snapshot, error) {
+	return m.snapshotService.ListSnapshots()
+}
+
+// Rollback reverts to the named snapshot, or the newest if name is empty
+func (m *SnapshotManager) Rollback(name string) (*model.Snapshot, error) {
+	return m.snapshotService.Rollback(name)
+}
@@ -0,0 +1,66 @@
+// pkg/application/ssh_key_inventory_manager.go
+package application
+
+import (
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// SSHKeyInventoryManager is an application service for the inventory of
+// authorized SSH keys and their expiry dates
+type SSHKeyInventoryManager struct {
+	inventoryService service.SSHKeyInventoryService
+	now              func() time.Time
+}
+
+// NewSSHKeyInventoryManager creates a new SSHKeyInventoryManager
+func NewSSHKeyInventoryManager(inventoryService service.SSHKeyInventoryService) *SSHKeyInventoryManager {
+	return &SSHKeyInventoryManager{
+		inventoryService: inventoryService,
+		now:              time.Now,
+	}
+}
+
+// Inventory lists every authorized key with its strength and expiry
+func (m *SSHKeyInventoryManager) Inventory() ([]model.SSHKeyInventoryEntry, error) {
+	return m.inventoryService.Inventory(m.now())
+}
+
+// SetExpiry tags a user's key to expire at the given date (2026-12-31) or
+// after a number of days (90d), and returns the expiry time
+func (m *SSHKeyInventoryManager) SetExpiry(username, fingerprint, expiry string) (time.Time, error) {
+	expires, err := model.ParseSSHKeyExpiry(expiry, m.now())
+	if err != nil {
+		return time.Time{}, err
+	}
+	return expires, m.inventoryService.SetExpiry(username, fingerprint, expires)
+}
+
+// ClearExpiry removes a key's expiry, reporting false when it had none
+func (m *SSHKeyInventoryManager) ClearExpiry(username, fingerprint string) (bool, error) {
+	return m.inventoryService.ClearExpiry(username, fingerprint)
+}
+
+// ExpiredKeyFindings reports each expired key as an SSH key audit finding
+func (m *SSHKeyInventoryManager) ExpiredKeyFindings() ([]model.SSHKeyFinding, error) {
+	entries, err := m.Inventory()
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []model.SSHKeyFinding
+	for _, entry := range entries {
+		if entry.Expired {
+			findings = append(findings, entry.Finding())
+		}
+	}
+	return findings, nil
+}
+
+// RemoveExpired removes the expired keys and returns them; dryRun only
+// reports what would be removed
+func (m *SSHKeyInventoryManager) RemoveExpired(dryRun bool) ([]model.SSHKeyInventoryEntry, error) {
+	return m.inventoryService.RemoveExpired(m.now(), dryRun)
+}
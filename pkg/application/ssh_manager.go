@@ -25,6 +25,8 @@ func (m *SSHManager) ConfigureSSH(
 	permitRootLogin bool,
 	allowedUsers []string,
 	keyPaths []string,
+	managedUser string,
+	allowAllUsers bool,
 ) error {
 	// Create SSH config object
 	config := model.SSHConfig{
@@ -34,8 +36,14 @@ func (m *SSHManager) ConfigureSSH(
 		AllowedUsers:    allowedUsers,
 		KeyPaths:        keyPaths,
 		AuthMethods:     []string{"publickey"},
+		ManagedUser:     managedUser,
+		AllowAllUsers:   allowAllUsers,
 	}
 
+	// Carry over directory key lookup and the hardening profile so rewriting
+	// the managed file keeps them
+	m.preserveManagedSettings(&config)
+
 	// Call domain service
 	return m.sshService.ConfigureSSH(config)
 }
@@ -51,6 +59,7 @@ func (m *SSHManager) SecureSSH(port int, allowedUsers []string) error {
 		AuthMethods:     []string{"publickey"},
 		KeyPaths:        []string{".ssh/authorized_keys"},
 	}
+	m.preserveManagedSettings(&config)
 
 	// Apply the configuration
 	return m.sshService.ConfigureSSH(config)
@@ -61,6 +70,135 @@ func (m *SSHManager) DisableRootSSH() error {
 	return m.sshService.DisableRootSSH()
 }
 
+// ValidateListenAddress checks that an address can be used as an SSH listen address
+func (m *SSHManager) ValidateListenAddress(addr string) error {
+	return service.ValidateListenAddress(addr)
+}
+
+// IsWildcardListenAddress reports whether an address exposes SSH on all interfaces
+func (m *SSHManager) IsWildcardListenAddress(addr string) bool {
+	return service.IsWildcardListenAddress(addr)
+}
+
+// ResolveAllowedUsers returns the AllowUsers list that ConfigureSSH would write
+func (m *SSHManager) ResolveAllowedUsers(allowedUsers []string, managedUser string, allowAllUsers bool) ([]string, error) {
+	return service.ResolveAllowedUsers(allowedUsers, managedUser, allowAllUsers)
+}
+
+// IsRootLoginEnabled reports whether SSH login is permitted for the root user
+func (m *SSHManager) IsRootLoginEnabled() (bool, error) {
+	return m.sshService.IsRootLoginEnabled()
+}
+
+// ConfigureAuthorizedKeysCommand sets or clears directory key lookup (AuthorizedKeysCommand)
+func (m *SSHManager) ConfigureAuthorizedKeysCommand(command string, runAsUser string) error {
+	return m.sshService.ConfigureAuthorizedKeysCommand(command, runAsUser)
+}
+
+// GetAuthorizedKeysCommand returns the configured AuthorizedKeysCommand, if any
+func (m *SSHManager) GetAuthorizedKeysCommand() (string, error) {
+	config, err := m.sshService.GetCurrentConfig()
+	if err != nil {
+		return "", err
+	}
+	return config.AuthorizedKeysCommand, nil
+}
+
+// ApplyProfile applies a named SSH hardening profile (baseline, strict, paranoid)
+func (m *SSHManager) ApplyProfile(profile string) error {
+	return m.sshService.ApplyProfile(profile)
+}
+
+// ConfigureBanner sets or clears the banner file sshd shows before authentication
+func (m *SSHManager) ConfigureBanner(path string) error {
+	return m.sshService.ConfigureBanner(path)
+}
+
+// GetBanner returns the banner file sshd shows, or "" when it shows none
+func (m *SSHManager) GetBanner() (string, error) {
+	config, err := m.sshService.GetCurrentConfig()
+	if err != nil {
+		return "", err
+	}
+	return config.Banner, nil
+}
+
+// GetProfile returns the hardening profile in effect, or "" when none is
+func (m *SSHManager) GetProfile() (string, error) {
+	config, err := m.sshService.GetCurrentConfig()
+	if err != nil {
+		return "", err
+	}
+	return config.Profile, nil
+}
+
+// GetPort returns the port sshd is configured to listen on
+func (m *SSHManager) GetPort() (int, error) {
+	config, err := m.sshService.GetCurrentConfig()
+	if err != nil {
+		return 0, err
+	}
+	return config.Port, nil
+}
+
+// ValidateProfile checks that a name is a known SSH hardening profile
+func (m *SSHManager) ValidateProfile(profile string) error {
+	return service.ValidateSSHProfile(profile)
+}
+
+// CompareEffectiveConfig compares the SSH policy of the configuration, as
+// run-all would write it, with the settings sshd runs with. An empty
+// profile keeps the one currently applied.
+func (m *SSHManager) CompareEffectiveConfig(
+	port int,
+	listenAddresses []string,
+	allowedUsers []string,
+	managedUser string,
+	allowAllUsers bool,
+	profile string,
+) (*model.SSHEffectiveReport, error) {
+	config := model.SSHConfig{
+		Port:            port,
+		ListenAddresses: listenAddresses,
+		PermitRootLogin: false,
+		AllowedUsers:    allowedUsers,
+		AuthMethods:     []string{"publickey"},
+		ManagedUser:     managedUser,
+		AllowAllUsers:   allowAllUsers,
+	}
+	m.preserveManagedSettings(&config)
+	if profile != "" {
+		config.Profile = profile
+	}
+
+	return m.sshService.CompareEffectiveConfig(config)
+}
+
+// FindConfigConflicts lists the SSH servers running and the directives the
+// sshd configuration files set to different values
+func (m *SSHManager) FindConfigConflicts() (*model.SSHConflictReport, error) {
+	return m.sshService.FindConfigConflicts()
+}
+
+// ConsolidateConfig comments out the lines of other sshd configuration
+// files that contradict hardn's file and returns them
+func (m *SSHManager) ConsolidateConfig() ([]model.SSHDirectiveSource, error) {
+	return m.sshService.ConsolidateConfig()
+}
+
+// preserveManagedSettings copies the current AuthorizedKeysCommand, banner
+// and hardening profile into config
+func (m *SSHManager) preserveManagedSettings(config *model.SSHConfig) {
+	current, err := m.sshService.GetCurrentConfig()
+	if err != nil || current == nil {
+		return
+	}
+	config.AuthorizedKeysCommand = current.AuthorizedKeysCommand
+	config.AuthorizedKeysCommandUser = current.AuthorizedKeysCommandUser
+	config.Banner = current.Banner
+	config.Profile = current.Profile
+}
+
 // add an SSH public key for a user
 func (m *SSHManager) AddSSHKey(username string, publicKey string) error {
 	return m.sshService.AddAuthorizedKey(username, publicKey)
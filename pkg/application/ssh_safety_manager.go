@@ -0,0 +1,97 @@
+// pkg/application/ssh_safety_manager.go
+package application
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// SSHSafetyManager is an application service that keeps changes to sshd
+// and the firewall only once SSH is confirmed to accept new connections
+type SSHSafetyManager struct {
+	safetyService   service.SSHSafetyService
+	watchdogService service.WatchdogService
+	runService      service.RunService
+	executable      func() (string, error)
+}
+
+// NewSSHSafetyManager creates a new SSHSafetyManager
+func NewSSHSafetyManager(
+	safetyService service.SSHSafetyService,
+	watchdogService service.WatchdogService,
+	runService service.RunService,
+) *SSHSafetyManager {
+	return &SSHSafetyManager{
+		safetyService:   safetyService,
+		watchdogService: watchdogService,
+		runService:      runService,
+		executable:      os.Executable,
+	}
+}
+
+// Arm starts the timer rolling the run back with this hardn binary unless
+// it is confirmed within revertAfter, and returns the delay used
+func (m *SSHSafetyManager) Arm(runID string, revertAfter time.Duration) (time.Duration, error) {
+	binary, err := m.executable()
+	if err != nil {
+		return 0, fmt.Errorf("failed to locate hardn binary: %w", err)
+	}
+	return m.safetyService.Arm(runID, binary, revertAfter)
+}
+
+// KeepAlive restarts the timer's delay while the run is still going
+func (m *SSHSafetyManager) KeepAlive(runID string) error {
+	return m.safetyService.KeepAlive(runID)
+}
+
+// Verify probes SSH once the run is done. A run left without pending
+// changes to sshd or the firewall disarms the timer and returns a nil
+// result. When SSH does not answer, the run is rolled back right away.
+func (m *SSHSafetyManager) Verify(runID string, watchdog model.SSHWatchdog) (*model.WatchdogResult, error) {
+	run, err := m.runService.GetRun(runID)
+	if err != nil {
+		return nil, err
+	}
+	if !run.Touches(model.AreaSSH, model.AreaFirewall) {
+		_, err := m.safetyService.Disarm(runID)
+		return nil, err
+	}
+
+	result, err := m.watchdogService.VerifySSH(watchdog)
+	if err != nil || result.Reachable {
+		return result, err
+	}
+
+	result.Rollback, err = m.Revert(runID)
+	return result, err
+}
+
+// Confirm keeps the changes of the run by disarming its timer
+func (m *SSHSafetyManager) Confirm(runID string) error {
+	disarmed, err := m.safetyService.Disarm(runID)
+	if err != nil {
+		return err
+	}
+	if !disarmed {
+		return fmt.Errorf("the revert timer of run %s fired before the changes were confirmed", runID)
+	}
+	return nil
+}
+
+// Revert disarms the timer and rolls the run back now. A timer that has
+// already fired is rolling the run back itself, which is reported as an
+// error.
+func (m *SSHSafetyManager) Revert(runID string) (*model.RunRollback, error) {
+	disarmed, err := m.safetyService.Disarm(runID)
+	if err != nil {
+		return nil, err
+	}
+	if !disarmed {
+		return nil, fmt.Errorf("the revert timer of run %s has already fired", runID)
+	}
+	return m.runService.Rollback(runID, nil, false)
+}
@@ -0,0 +1,36 @@
+// pkg/application/state_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// StateManager is an application service for converging the host to a
+// declarative state file
+type StateManager struct {
+	stateService service.StateService
+}
+
+// NewStateManager creates a new StateManager
+func NewStateManager(stateService service.StateService) *StateManager {
+	return &StateManager{
+		stateService: stateService,
+	}
+}
+
+// LoadState reads and checks a state file
+func (m *StateManager) LoadState(path string) (*model.DesiredState, error) {
+	return m.stateService.LoadState(path)
+}
+
+// PlanState lists the changes applying a state makes, without changing
+// anything
+func (m *StateManager) PlanState(state *model.DesiredState) (*model.StatePlan, error) {
+	return m.stateService.Plan(state)
+}
+
+// ApplyState makes the changes of a valid plan
+func (m *StateManager) ApplyState(plan *model.StatePlan) ([]model.StateChangeResult, error) {
+	return m.stateService.Apply(plan)
+}
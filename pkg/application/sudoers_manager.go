@@ -0,0 +1,48 @@
+// pkg/application/sudoers_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// SudoersManager is an application service for auditing the sudo policy
+// and restricting users to command templates
+type SudoersManager struct {
+	sudoersService service.SudoersService
+}
+
+// NewSudoersManager creates a new SudoersManager
+func NewSudoersManager(sudoersService service.SudoersService) *SudoersManager {
+	return &SudoersManager{
+		sudoersService: sudoersService,
+	}
+}
+
+// AuditSudoers reports overly broad sudoers rules
+func (m *SudoersManager) AuditSudoers() ([]model.SudoersFinding, error) {
+	return m.sudoersService.AuditSudoers()
+}
+
+// AuditFindings returns the sudoers findings for inclusion in an audit
+func (m *SudoersManager) AuditFindings() ([]model.PolicyViolation, error) {
+	findings, err := m.sudoersService.AuditSudoers()
+	if err != nil {
+		return nil, err
+	}
+	violations := make([]model.PolicyViolation, 0, len(findings))
+	for _, finding := range findings {
+		violations = append(violations, finding.Violation())
+	}
+	return violations, nil
+}
+
+// PlanSudoTemplate shows what restricting a user to a template changes
+func (m *SudoersManager) PlanSudoTemplate(username, template string, noPassword bool) (*model.SudoTemplatePlan, error) {
+	return m.sudoersService.PlanTemplate(username, template, noPassword)
+}
+
+// ApplySudoTemplate restricts a user to the commands of a checked plan
+func (m *SudoersManager) ApplySudoTemplate(plan *model.SudoTemplatePlan) error {
+	return m.sudoersService.ApplyTemplate(plan)
+}
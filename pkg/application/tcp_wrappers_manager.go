@@ -0,0 +1,76 @@
+// pkg/application/tcp_wrappers_manager.go
+package application
+
+import (
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// TCPWrappersManager is an application service for moving the legacy TCP
+// wrappers rules into the firewall
+type TCPWrappersManager struct {
+	tcpWrappersService service.TCPWrappersService
+	firewallService    service.FirewallService
+}
+
+// NewTCPWrappersManager creates a new TCPWrappersManager
+func NewTCPWrappersManager(tcpWrappersService service.TCPWrappersService, firewallService service.FirewallService) *TCPWrappersManager {
+	return &TCPWrappersManager{
+		tcpWrappersService: tcpWrappersService,
+		firewallService:    firewallService,
+	}
+}
+
+// Check compares the TCP wrappers rules with the firewall and plans the
+// migration
+func (m *TCPWrappersManager) Check(sshPort int) (*model.TCPWrappersReport, error) {
+	_, _, _, rules, err := m.firewallService.GetFirewallStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get firewall status: %w", err)
+	}
+	return m.tcpWrappersService.Check(rules, sshPort)
+}
+
+// Migrate adds the planned firewall rules, closes the ports the TCP
+// wrappers rules narrowed down, then comments out the rules. Rules without
+// a firewall equivalent stop the migration unless force is set, since
+// their intent would be lost.
+func (m *TCPWrappersManager) Migrate(sshPort int, force bool) (*model.TCPWrappersReport, error) {
+	installed, enabled, _, _, err := m.firewallService.GetFirewallStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get firewall status: %w", err)
+	}
+	if !installed || !enabled {
+		return nil, fmt.Errorf("the firewall is not enabled; enable it with 'hardn firewall enable' first")
+	}
+
+	report, err := m.Check(sshPort)
+	if err != nil {
+		return nil, err
+	}
+	if !report.InUse() {
+		return report, nil
+	}
+	if len(report.Skipped) > 0 && !force {
+		return report, fmt.Errorf("%d TCP wrappers rules have no firewall equivalent; review them or use --force", len(report.Skipped))
+	}
+
+	// Add the narrower rules before closing the ports they replace
+	for _, rule := range report.Add {
+		if err := m.firewallService.AddRule(rule); err != nil {
+			return report, err
+		}
+	}
+	for _, rule := range report.Close {
+		if err := m.firewallService.RemoveRule(rule); err != nil {
+			return report, err
+		}
+	}
+
+	if err := m.tcpWrappersService.Disable(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
@@ -0,0 +1,37 @@
+// pkg/application/time_sync_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// TimeSyncManager is an application service for time synchronization
+type TimeSyncManager struct {
+	timeSyncService service.TimeSyncService
+}
+
+// NewTimeSyncManager creates a new TimeSyncManager
+func NewTimeSyncManager(timeSyncService service.TimeSyncService) *TimeSyncManager {
+	return &TimeSyncManager{
+		timeSyncService: timeSyncService,
+	}
+}
+
+// EffectivePolicy picks the daemon for this OS and fills in the default
+// NTS servers
+func (m *TimeSyncManager) EffectivePolicy(policy model.TimeSyncPolicy) (model.TimeSyncPolicy, error) {
+	return m.timeSyncService.EffectivePolicy(policy)
+}
+
+// ApplyTimeSync installs and configures the daemon of the policy and
+// stops the others
+func (m *TimeSyncManager) ApplyTimeSync(policy model.TimeSyncPolicy) error {
+	return m.timeSyncService.ApplyTimeSync(policy)
+}
+
+// GetTimeSyncStatus reports the daemon running, whether the clock is
+// synchronized and how far it is from its source
+func (m *TimeSyncManager) GetTimeSyncStatus() (*model.TimeSyncStatus, error) {
+	return m.timeSyncService.GetTimeSyncStatus()
+}
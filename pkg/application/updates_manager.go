@@ -0,0 +1,45 @@
+// pkg/application/updates_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// UpdatesManager is an application service for the automatic upgrade policy
+type UpdatesManager struct {
+	updatesService service.UpdatesService
+}
+
+// NewUpdatesManager creates a new UpdatesManager
+func NewUpdatesManager(updatesService service.UpdatesService) *UpdatesManager {
+	return &UpdatesManager{
+		updatesService: updatesService,
+	}
+}
+
+// UpdatesSupported reports whether hardn writes the upgrade policy on this OS
+func (m *UpdatesManager) UpdatesSupported() bool {
+	return m.updatesService.UpdatesSupported()
+}
+
+// EffectivePolicy fills in the defaults of a policy, such as the security
+// origins of the distribution
+func (m *UpdatesManager) EffectivePolicy(policy model.UpdatesPolicy) model.UpdatesPolicy {
+	return m.updatesService.EffectivePolicy(policy)
+}
+
+// ConfigureUpdates writes the upgrade policy and enables automatic upgrades
+func (m *UpdatesManager) ConfigureUpdates(policy model.UpdatesPolicy) error {
+	return m.updatesService.ConfigureUpdates(policy)
+}
+
+// DisableUpdates stops automatic upgrades
+func (m *UpdatesManager) DisableUpdates() error {
+	return m.updatesService.DisableUpdates()
+}
+
+// GetUpdatesStatus reports whether automatic upgrades are enabled
+func (m *UpdatesManager) GetUpdatesStatus() (*model.UpdatesStatus, error) {
+	return m.updatesService.GetUpdatesStatus()
+}
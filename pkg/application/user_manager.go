@@ -2,19 +2,31 @@
 package application
 
 import (
+	"fmt"
+
 	"github.com/abbott/hardn/pkg/domain/model"
 	"github.com/abbott/hardn/pkg/domain/service"
 )
 
 // UserManager is an application service for user management
 type UserManager struct {
-	userService service.UserService
+	userService      service.UserService
+	keyImportService service.SSHKeyImportService
+	importService    service.UserImportService
 }
 
-// NewUserManager creates a new UserManager
-func NewUserManager(userService service.UserService) *UserManager {
+// NewUserManager creates a new UserManager; keyImportService and
+// importService may be nil when importing keys from GitHub or GitLab or
+// users from a file is not needed
+func NewUserManager(
+	userService service.UserService,
+	keyImportService service.SSHKeyImportService,
+	importService service.UserImportService,
+) *UserManager {
 	return &UserManager{
-		userService: userService,
+		userService:      userService,
+		keyImportService: keyImportService,
+		importService:    importService,
 	}
 }
 
@@ -30,11 +42,72 @@ func (m *UserManager) CreateUser(username string, hasSudo bool, sudoNoPassword b
 	return m.userService.CreateUser(user)
 }
 
+// PlanUserImport checks the users of an import file without creating any;
+// entries that leave nopasswd empty use defaultNoPassword
+func (m *UserManager) PlanUserImport(path string, defaultNoPassword bool) (*model.UserImportPlan, error) {
+	if m.importService == nil {
+		return nil, fmt.Errorf("user import is not available")
+	}
+	return m.importService.PlanFile(path, defaultNoPassword)
+}
+
+// ImportUsers creates the users of a plan without problems and reports the
+// outcome for each
+func (m *UserManager) ImportUsers(plan *model.UserImportPlan) ([]model.UserImportResult, error) {
+	if m.importService == nil {
+		return nil, fmt.Errorf("user import is not available")
+	}
+	return m.importService.Import(plan)
+}
+
+// LockUser locks a user account
+func (m *UserManager) LockUser(username string) error {
+	return m.userService.LockUser(username)
+}
+
+// ListUsers returns a page of non-system users
+func (m *UserManager) ListUsers(localOnly bool, offset, limit int) (*model.UserPage, error) {
+	return m.userService.ListUsers(model.UserListOptions{
+		LocalOnly: localOnly,
+		Offset:    offset,
+		Limit:     limit,
+	})
+}
+
 // add an SSH key to an existing user
 func (m *UserManager) AddSSHKey(username string, publicKey string) error {
 	return m.userService.AddSSHKey(username, publicKey)
 }
 
+// ImportSSHKeysFromProvider authorizes the SSH keys a GitHub or GitLab account
+// publishes for a local user, like ssh-import-id; dryRun only previews
+func (m *UserManager) ImportSSHKeysFromProvider(username, provider, account string, dryRun bool) (*model.SSHKeyImport, error) {
+	if m.keyImportService == nil {
+		return nil, fmt.Errorf("SSH key import is not available")
+	}
+	return m.keyImportService.ImportKeys(username, provider, account, dryRun)
+}
+
+// remove the SSH key with the given fingerprint from a user
+func (m *UserManager) RemoveSSHKey(username string, fingerprint string) error {
+	return m.userService.RemoveSSHKey(username, fingerprint)
+}
+
+// AuditSSHKeys reports weak, duplicated and over-privileged authorized keys
+func (m *UserManager) AuditSSHKeys() ([]model.SSHKeyFinding, error) {
+	return m.userService.AuditSSHKeys()
+}
+
+// GetDirectoryAuthStatus reports whether users are resolved from LDAP/SSSD
+func (m *UserManager) GetDirectoryAuthStatus() (*model.DirectoryAuthStatus, error) {
+	return m.userService.GetDirectoryAuthStatus()
+}
+
+// IsDirectoryUser reports whether a user comes from a directory service
+func (m *UserManager) IsDirectoryUser(username string) (bool, error) {
+	return m.userService.IsDirectoryUser(username)
+}
+
 // GetExtendedUserInfo retrieves comprehensive information about a user
 func (m *UserManager) GetExtendedUserInfo(username string) (*model.User, error) {
 	return m.userService.GetExtendedUserInfo(username)
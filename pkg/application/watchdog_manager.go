@@ -0,0 +1,43 @@
+// pkg/application/watchdog_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// WatchdogManager is an application service that rolls back unattended
+// runs which leave SSH unreachable
+type WatchdogManager struct {
+	watchdogService service.WatchdogService
+	runService      service.RunService
+}
+
+// NewWatchdogManager creates a new WatchdogManager
+func NewWatchdogManager(watchdogService service.WatchdogService, runService service.RunService) *WatchdogManager {
+	return &WatchdogManager{
+		watchdogService: watchdogService,
+		runService:      runService,
+	}
+}
+
+// GuardRun verifies SSH after a run that changed sshd or the firewall and
+// rolls the run back if SSH does not answer in time. Runs that changed
+// neither are not probed, and a nil result is returned.
+func (m *WatchdogManager) GuardRun(runID string, watchdog model.SSHWatchdog) (*model.WatchdogResult, error) {
+	run, err := m.runService.GetRun(runID)
+	if err != nil {
+		return nil, err
+	}
+	if !run.Touches(model.AreaSSH, model.AreaFirewall) {
+		return nil, nil
+	}
+
+	result, err := m.watchdogService.VerifySSH(watchdog)
+	if err != nil || result.Reachable {
+		return result, err
+	}
+
+	result.Rollback, err = m.runService.Rollback(runID, nil, false)
+	return result, err
+}
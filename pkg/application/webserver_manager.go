@@ -0,0 +1,29 @@
+// pkg/application/webserver_manager.go
+package application
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+)
+
+// WebServerManager is an application service for web server TLS hardening
+type WebServerManager struct {
+	webServerService service.WebServerService
+}
+
+// NewWebServerManager creates a new WebServerManager
+func NewWebServerManager(webServerService service.WebServerService) *WebServerManager {
+	return &WebServerManager{
+		webServerService: webServerService,
+	}
+}
+
+// DetectWebServers returns the supported web servers installed on the host
+func (m *WebServerManager) DetectWebServers() ([]model.WebServer, error) {
+	return m.webServerService.DetectWebServers()
+}
+
+// ApplyTLSBaseline applies the TLS baseline to every detected web server
+func (m *WebServerManager) ApplyTLSBaseline() ([]model.WebServer, error) {
+	return m.webServerService.ApplyTLSBaseline()
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/style"
+	"github.com/spf13/cobra"
+)
+
+var applyAssumeYes bool
+
+// ApplyCmd returns the apply command
+func ApplyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply FILE",
+		Short: "Converge this host to the posture a state file declares",
+		Long: `Read a YAML state file describing the desired posture, compare it with
+this host, print the plan and, after confirmation, make the changes. A
+state that already matches the host changes nothing, so apply can run
+again and again.
+
+A state file declares:
+
+  users      accounts to create, with sudo access and SSH keys to grant
+  ssh        the port, listen addresses, root login and allowed users
+  firewall   rules to add, written as the firewallRules setting
+  sysctl     kernel settings, kept in ` + model.StateSysctlPath + `
+  packages   packages to install and to remove
+
+Apply adds and changes what the file lists and leaves the rest alone:
+accounts, keys and firewall rules the file does not mention are kept,
+sudo is never taken away, and packages are only removed when listed under
+packages.remove. The plan is checked first: if it finds a problem, such as
+an invalid name, a weak key or an SSH port the firewall would block,
+nothing is changed.
+
+Example state.yml:
+  kind: hardn-state
+  users:
+    - name: george
+      sudo: true
+      keys:
+        - ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAI... george@laptop
+  ssh:
+    port: 2222
+    permitRootLogin: false
+    allowUsers: [george]
+  firewall:
+    rules:
+      - {action: allow, protocol: tcp, port: 2222}
+  sysctl:
+    net.ipv4.conf.all.accept_redirects: 0
+  packages:
+    install: [fail2ban]
+    remove: [telnet]
+
+Examples:
+  sudo hardn apply state.yml --dry-run
+  sudo hardn apply state.yml --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runApply(cmd, args[0])
+		},
+	}
+	cmd.Flags().BoolVar(&applyAssumeYes, "yes", false, "Do not ask for confirmation")
+	return cmd
+}
+
+// runApply executes the apply command
+func runApply(cmd *cobra.Command, path string) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	manager := ctx.serviceFactory().CreateStateManager()
+
+	state, err := manager.LoadState(path)
+	if err != nil {
+		return err
+	}
+	plan, err := manager.PlanState(state)
+	if err != nil {
+		return fmt.Errorf("failed to plan %s: %w", path, err)
+	}
+
+	if !plan.Valid() {
+		fmt.Printf("%s has %d problem(s):\n", path, len(plan.Problems))
+		for _, problem := range plan.Problems {
+			fmt.Printf("  problem: %s\n", problem)
+		}
+		return fmt.Errorf("%s has %d problem(s); nothing was changed", path, len(plan.Problems))
+	}
+	if plan.Empty() {
+		fmt.Printf("This host already matches %s\n", path)
+		return nil
+	}
+	printStatePlan(path, plan)
+
+	if ctx.dryRun {
+		fmt.Printf("[DRY-RUN] Would make %d change(s)\n", len(plan.Changes))
+		return nil
+	}
+	if !applyAssumeYes {
+		fmt.Printf("Make %d change(s)? [y/N]: ", len(plan.Changes))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer = strings.TrimSpace(strings.ToLower(answer)); answer != "y" && answer != "yes" {
+			fmt.Println("Apply cancelled.")
+			return nil
+		}
+	}
+
+	results, err := manager.ApplyState(plan)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			fmt.Printf("FAILED   %s: %v\n", result.Change.Describe(), result.Err)
+			continue
+		}
+		logging.LogSuccess("State %s: %s", path, result.Change.Describe())
+		fmt.Printf("DONE     %s\n", result.Change.Describe())
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d change(s) failed; run 'hardn apply %s' again once fixed", failed, len(results), path)
+	}
+	fmt.Printf("\nApply complete: %d change(s) made\n", len(results))
+	return nil
+}
+
+// printStatePlan prints the changes of a plan as terraform does, marking
+// additions with +, changes with ~ and removals with -
+func printStatePlan(path string, plan *model.StatePlan) {
+	fmt.Printf("Applying %s makes these changes:\n\n", path)
+	for _, change := range plan.Changes {
+		symbol := change.Symbol()
+		switch change.Action {
+		case model.StateCreate:
+			symbol = style.Colored(style.Green, symbol)
+		case model.StateDelete:
+			symbol = style.Colored(style.Red, symbol)
+		default:
+			symbol = style.Colored(style.Yellow, symbol)
+		}
+		fmt.Printf("  %s %s %s\n", symbol, change.Resource, change.Name)
+		for _, detail := range change.Details {
+			fmt.Printf("      %s\n", detail)
+		}
+	}
+	fmt.Printf("\n%s\n\n", plan.Summary())
+}
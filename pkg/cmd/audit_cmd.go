@@ -0,0 +1,953 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/security"
+	"github.com/spf13/cobra"
+)
+
+var (
+	auditOutput    string
+	auditPolicyDir string
+	auditFormat    string
+	auditRemove    bool
+	auditFailOn    string
+	auditWithLynis bool
+	auditPackages  bool
+)
+
+// AuditCmd returns the audit command
+func AuditCmd(hardnVersion string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Check the host and record and compare audit reports",
+		Long: `Run every security check without the interactive menu: the status checks
+shown in the main menu, SSH keys, sudoers rules, NFS and Samba shares,
+certificates, the insecure packages of debloat.packages and the signing
+keys package repositories are trusted with.
+
+The command exits with status 1 when the overall risk level or any finding
+is at or above the --fail-on level, so it can gate CI images and
+configuration management runs. Levels are low, moderate, high and critical;
+finding severities count as low, moderate (medium) or high. Use
+--fail-on none to only report.
+
+With --with-lynis a Lynis scan runs as well, and its hardening index,
+warnings and suggestions follow the audit. They are informational and do
+not change the exit status.
+
+With --packages the installed packages are checked against the
+distribution's security advisories: debsecan on Debian and Ubuntu, dnf
+updateinfo on the RHEL family and the Alpine security database on Alpine.
+Every advisory is listed after the audit, and the counts per severity are
+kept for the status display. Advisories count toward --fail-on like
+findings: critical, high, medium (moderate) and low. Advisories of unknown
+severity, including every Alpine advisory, are only reported.
+
+Examples:
+  sudo hardn audit
+  sudo hardn audit --fail-on moderate
+  sudo hardn audit --format json
+  sudo hardn audit --with-lynis
+  sudo hardn audit --packages`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configFile := ""
+			if flag := cmd.Flag("config"); flag != nil {
+				configFile = flag.Value.String()
+			}
+			return runAuditCheck(configFile, flagEnabled(cmd, "dry-run"))
+		},
+	}
+	cmd.Flags().StringVar(&auditFailOn, "fail-on", "high", "Lowest level that fails the audit (low, moderate, high, critical, none)")
+	cmd.Flags().StringVarP(&auditFormat, "format", "O", "text", "Output format (text, json)")
+	cmd.Flags().BoolVar(&auditWithLynis, "with-lynis", false, "Also run a Lynis scan and show its hardening index, warnings and suggestions")
+	cmd.Flags().BoolVar(&auditPackages, "packages", false, "Also check installed packages against security advisories and list the CVEs found")
+
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Write an audit report of the current hardening state",
+		Long: `Record the state of each hardening control, exposed NFS and Samba
+shares, and any policy violations as a JSON audit report that can later be
+compared with "hardn audit diff".
+
+Examples:
+  sudo hardn audit report --output before.json
+  sudo hardn audit report --output - | jq .controls
+  sudo hardn audit report --output after.json --policy /etc/hardn/policies`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configFile := ""
+			if flag := cmd.Flag("config"); flag != nil {
+				configFile = flag.Value.String()
+			}
+			return runAuditReport(configFile, hardnVersion)
+		},
+	}
+	reportCmd.Flags().StringVarP(&auditOutput, "output", "o", "", "File to write the report to, or - for stdout")
+	reportCmd.Flags().StringVarP(&auditPolicyDir, "policy", "P", "", "Directory of rego policies to include as findings")
+	_ = reportCmd.MarkFlagRequired("output")
+
+	diffCmd := &cobra.Command{
+		Use:   "diff BEFORE AFTER",
+		Short: "Compare two audit reports",
+		Long: `Show which controls changed state between two audit reports, along with
+new and resolved findings. The command exits with status 1 when a control
+stopped passing or a new finding appeared.
+
+Example:
+  hardn audit diff before.json after.json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAuditDiff(args[0], args[1])
+		},
+	}
+	diffCmd.Flags().StringVarP(&auditFormat, "format", "O", "text", "Output format (text, json)")
+
+	sharesCmd := &cobra.Command{
+		Use:   "shares",
+		Short: "Check NFS exports and Samba shares for exposure",
+		Long: `Check /etc/exports and smb.conf for shares open to any host, guest
+access and insecure options such as no_root_squash, taking the firewall
+rules for the NFS and Samba ports into account. Each finding comes with a
+suggested fix. The command exits with status 1 when anything is found.
+
+These checks are also included in "hardn audit report".
+
+Example:
+  sudo hardn audit shares`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAuditShares()
+		},
+	}
+	sharesCmd.Flags().StringVarP(&auditFormat, "format", "O", "text", "Output format (text, json)")
+
+	sshKeysCmd := &cobra.Command{
+		Use:   "ssh-keys",
+		Short: "Check authorized SSH keys for weak, shared, unsafe and expired keys",
+		Long: `Scan the authorized_keys of root and every local user for RSA keys
+smaller than 2048 bits, DSA keys, the same key authorized for several
+accounts, options such as environment= or tunnel= that widen what a key
+may do, and keys past the expiry date set with "hardn ssh keys expire".
+The command exits with status 1 when anything is found.
+
+With --remove each flagged key is offered for removal in turn. Make sure
+another way to log in remains before removing a key.
+
+These checks are also included in "hardn audit report".
+
+Examples:
+  sudo hardn audit ssh-keys
+  sudo hardn audit ssh-keys --remove`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAuditSSHKeys(flagEnabled(cmd, "dry-run"))
+		},
+	}
+	sshKeysCmd.Flags().StringVarP(&auditFormat, "format", "O", "text", "Output format (text, json)")
+	sshKeysCmd.Flags().BoolVar(&auditRemove, "remove", false, "Offer to remove each flagged key")
+
+	sudoersCmd := &cobra.Command{
+		Use:   "sudoers",
+		Short: "Check sudoers rules for overly broad grants",
+		Long: `Parse /etc/sudoers and the files it includes, such as those in
+/etc/sudoers.d, and report rules that grant every command without a
+password (ALL=(ALL) NOPASSWD: ALL), rules that grant every command to a
+user or group other than the sudo, wheel or admin group, and Defaults
+that turn off authentication. Rules for root alone are not reported.
+The command exits with status 1 when anything is found.
+
+Broad grants can be narrowed with "hardn user sudo-template" or from the
+user menu.
+
+These checks are also included in "hardn audit report".
+
+Example:
+  sudo hardn audit sudoers`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAuditSudoers()
+		},
+	}
+	sudoersCmd.Flags().StringVarP(&auditFormat, "format", "O", "text", "Output format (text, json)")
+
+	sourcesCmd := &cobra.Command{
+		Use:   "sources",
+		Short: "Check that package repositories are signed with their own keys",
+		Long: `Report what lets packages in without a signature from the key meant for
+their repository: keys added with apt-key to /etc/apt/trusted.gpg, key files
+in /etc/apt/trusted.gpg.d that no package installed, repositories marked
+trusted=yes or allow-insecure=yes, and APT::Get::AllowUnauthenticated or
+Acquire::AllowInsecureRepositories in the APT configuration. On Alpine, keys
+in /etc/apk/keys that no package installed are reported. The command exits
+with status 1 when anything is found.
+
+Keys for third-party repositories can be installed with "hardn sources keys".
+
+These checks are also included in "hardn audit report".
+
+Example:
+  sudo hardn audit sources`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAuditSources()
+		},
+	}
+	sourcesCmd.Flags().StringVarP(&auditFormat, "format", "O", "text", "Output format (text, json)")
+
+	cmd.AddCommand(reportCmd)
+	cmd.AddCommand(diffCmd)
+	cmd.AddCommand(sharesCmd)
+	cmd.AddCommand(sshKeysCmd)
+	cmd.AddCommand(sudoersCmd)
+	cmd.AddCommand(sourcesCmd)
+	return cmd
+}
+
+// newAuditManager wires the audit manager for the audit commands
+func newAuditManager() *application.AuditManager {
+	provider := interfaces.NewProvider()
+	auditRepo := secondary.NewFileAuditRepository(provider.FS)
+	auditService := service.NewAuditServiceImpl(auditRepo)
+	return application.NewAuditManager(auditService)
+}
+
+// newLynisManager wires the Lynis manager for the audit command
+func newLynisManager() *application.LynisManager {
+	provider := interfaces.NewProvider()
+	lynisRepo := secondary.NewOSLynisRepository(provider.FS, provider.Commander)
+	lynisService := service.NewLynisServiceImpl(lynisRepo)
+	return application.NewLynisManager(lynisService)
+}
+
+// newPackageAuditManager wires the package audit manager for the audit command
+func newPackageAuditManager(host model.FactsOS) *application.PackageAuditManager {
+	provider := interfaces.NewProvider()
+	packageAuditRepo := secondary.NewOSPackageAuditRepository(provider.FS, provider.Commander, host.Type, host.Version, host.Codename)
+	return application.NewPackageAuditManager(service.NewPackageAuditServiceImpl(packageAuditRepo))
+}
+
+// newShareManager wires the share manager for the audit commands
+func newShareManager(osType string) *application.ShareManager {
+	provider := interfaces.NewProvider()
+	shareRepo := secondary.NewFileShareRepository(provider.FS)
+	firewallRepo := secondary.NewFirewallRepository(provider.FS, provider.Commander, osType)
+	shareService := service.NewShareServiceImpl(shareRepo, firewallRepo)
+	return application.NewShareManager(shareService)
+}
+
+// newNetworkExposureManager wires the network exposure manager for the audit commands
+func newNetworkExposureManager(osType string) *application.NetworkExposureManager {
+	provider := interfaces.NewProvider()
+	exposureRepo := secondary.NewOSNetworkExposureRepository(provider.FS, provider.Commander, osType)
+	firewallRepo := secondary.NewFirewallRepository(provider.FS, provider.Commander, osType)
+	exposureService := service.NewNetworkExposureServiceImpl(exposureRepo, firewallRepo)
+	return application.NewNetworkExposureManager(exposureService)
+}
+
+// newUserManager wires the user manager for the audit commands
+func newUserManager(osType string) *application.UserManager {
+	provider := interfaces.NewProvider()
+	userRepo := secondary.NewOSUserRepository(provider.FS, provider.Commander, osType)
+	userService := service.NewUserServiceImpl(userRepo)
+	keyImportService := service.NewSSHKeyImportServiceImpl(secondary.NewHTTPSSHKeySourceRepository(), userRepo)
+	return application.NewUserManager(userService, keyImportService, nil)
+}
+
+// newSudoersManager wires the sudoers manager for the commands that only
+// read the sudo policy
+func newSudoersManager(osType string) *application.SudoersManager {
+	provider := interfaces.NewProvider()
+	sudoersRepo := secondary.NewOSSudoersRepository(provider.FS, provider.Commander, osType)
+	return application.NewSudoersManager(service.NewSudoersServiceImpl(sudoersRepo))
+}
+
+// newDebloatManager wires the debloat manager for the audit commands
+func newDebloatManager(osType string) *application.DebloatManager {
+	provider := interfaces.NewProvider()
+	debloatRepo := secondary.NewOSDebloatRepository(provider.Commander, osType)
+	return application.NewDebloatManager(service.NewDebloatServiceImpl(debloatRepo))
+}
+
+// newRepositoryTrustManager wires the repository trust manager for the audit commands
+func newRepositoryTrustManager(osType string) *application.RepositoryTrustManager {
+	provider := interfaces.NewProvider()
+	trustRepo := secondary.NewOSRepositoryTrustRepository(provider.FS, provider.Commander, osType)
+	return application.NewRepositoryTrustManager(service.NewRepositoryTrustServiceImpl(trustRepo, model.OSInfo{Type: osType}))
+}
+
+// collectAuditFindings gathers the certificate, firewall, TCP wrappers, share, listening service,
+// SSH key, sudoers, insecure package and repository trust findings for a checked host
+func collectAuditFindings(cfg *config.Config, status *security.SecurityStatus, osType string) ([]model.PolicyViolation, error) {
+	findings := append([]model.PolicyViolation{}, security.BuildCertificateFindings(status)...)
+	findings = append(findings, security.BuildFirewallStackFindings(status)...)
+	findings = append(findings, security.BuildIPv6Findings(status)...)
+	findings = append(findings, security.BuildTCPWrappersFindings(status)...)
+	findings = append(findings, security.BuildTimeSyncFindings(status)...)
+
+	shareFindings, err := newShareManager(osType).AuditShares()
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, shareFindings...)
+
+	// Hosts without ss or netstat are audited without the listener findings
+	if exposureFindings, err := newNetworkExposureManager(osType).AuditExposure(); err == nil {
+		findings = append(findings, exposureFindings...)
+	}
+
+	keyFindings, err := auditSSHKeys(newUserManager(osType), osType)
+	if err != nil {
+		return nil, err
+	}
+	for _, finding := range keyFindings {
+		findings = append(findings, finding.Violation())
+	}
+
+	sudoersFindings, err := newSudoersManager(osType).AuditFindings()
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, sudoersFindings...)
+
+	debloatFindings, err := newDebloatManager(osType).AuditFindings(cfg.Debloat.Items())
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, debloatFindings...)
+
+	trustFindings, err := newRepositoryTrustManager(osType).AuditFindings()
+	if err != nil {
+		return nil, err
+	}
+	findings = append(findings, trustFindings...)
+
+	return findings, nil
+}
+
+// auditCheckResult is the outcome of "hardn audit"
+type auditCheckResult struct {
+	Hostname        string                    `json:"hostname"`
+	RiskLevel       string                    `json:"risk_level"`
+	RiskDescription string                    `json:"risk_description"`
+	FailOn          string                    `json:"fail_on"`
+	Failed          bool                      `json:"failed"`
+	Controls        []model.AuditControl      `json:"controls"`
+	Findings        []model.PolicyViolation   `json:"findings"`
+	Network         *model.NetworkExposure    `json:"network,omitempty"`
+	Lynis           *model.LynisReport        `json:"lynis,omitempty"`
+	Packages        *model.PackageAuditReport `json:"packages,omitempty"`
+}
+
+// runAuditCheck executes the audit command and exits with status 1 when the
+// risk level, a finding or a package advisory reaches the --fail-on level. Outside dry runs the
+// result is added to the posture history and sent to the configured
+// notifiers.
+func runAuditCheck(configFile string, dryRun bool) error {
+	if auditFormat != "text" && auditFormat != "json" {
+		return fmt.Errorf("unsupported format: %s (use text or json)", auditFormat)
+	}
+
+	threshold, err := security.ParseFailOn(auditFailOn)
+	if err != nil {
+		return err
+	}
+
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	started := time.Now()
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	cfg.ApplyLogging()
+	applyNetworkConfig(cfg)
+
+	status, facts, err := hostFacts(cfg)
+	if err != nil {
+		return err
+	}
+
+	findings, err := collectAuditFindings(cfg, status, facts.OS.Type)
+	if err != nil {
+		return err
+	}
+	if !dryRun {
+		recordPosture(status, model.PostureSourceAudit)
+	}
+
+	riskLevel, description, _ := security.GetSecurityRiskLevel(status)
+	result := &auditCheckResult{
+		Hostname:        facts.Hostname,
+		RiskLevel:       riskLevel,
+		RiskDescription: description,
+		FailOn:          strings.ToLower(auditFailOn),
+		Controls:        security.BuildAuditControls(status),
+		Findings:        findings,
+	}
+	if auditFormat == "json" {
+		result.Network, _ = newNetworkExposureManager(facts.OS.Type).GetNetworkExposure()
+	}
+
+	if auditWithLynis {
+		if auditFormat == "text" {
+			fmt.Fprintln(os.Stderr, "Running Lynis scan, this takes a few minutes...")
+		}
+		if result.Lynis, err = newLynisManager().RunScan(); err != nil {
+			return err
+		}
+	}
+
+	if auditPackages {
+		if auditFormat == "text" {
+			fmt.Fprintln(os.Stderr, "Checking installed packages against security advisories...")
+		}
+		if result.Packages, err = newPackageAuditManager(facts.OS).CheckPackages(); err != nil {
+			return err
+		}
+	}
+
+	// Decided once every collector has run, so package advisories count
+	result.Failed = security.AuditFailed(threshold, riskLevel, findings, result.Packages)
+
+	if auditFormat == "json" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode audit result: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		printAuditCheck(result)
+	}
+
+	if notificationManager := newNotificationManager(cfg); notificationManager.Enabled() && !dryRun {
+		sendSummary(notificationManager, auditSummary(result, started))
+	}
+
+	if code := security.AuditExitCode(result.Failed); code != 0 {
+		os.Exit(code)
+	}
+	return nil
+}
+
+// auditSummary summarizes an audit result for notifications
+func auditSummary(result *auditCheckResult, started time.Time) model.RunSummary {
+	summary := model.RunSummary{
+		Hostname:   result.Hostname,
+		Operation:  model.SummaryAudit,
+		StartedAt:  started,
+		FinishedAt: time.Now(),
+		Failed:     result.Failed,
+		RiskAfter:  result.RiskLevel,
+	}
+	for _, control := range result.Controls {
+		if control.Status != model.ControlPass {
+			summary.FailedChecks = append(summary.FailedChecks, control.ID+": "+control.Title)
+		}
+	}
+	for _, finding := range result.Findings {
+		summary.FailedChecks = append(summary.FailedChecks,
+			fmt.Sprintf("[%s] %s: %s", finding.Severity, finding.Rule, finding.Message))
+	}
+	return summary
+}
+
+// printAuditCheck prints the audit result as plain text
+func printAuditCheck(result *auditCheckResult) {
+	fmt.Printf("Host:       %s\n", result.Hostname)
+	fmt.Printf("Risk level: %s (%s)\n", result.RiskLevel, result.RiskDescription)
+
+	var failing []model.AuditControl
+	for _, control := range result.Controls {
+		if control.Status != model.ControlPass {
+			failing = append(failing, control)
+		}
+	}
+	if len(failing) > 0 {
+		fmt.Println("\nFailing controls:")
+		for _, control := range failing {
+			fmt.Printf("  - %-32s %s\n", control.ID, control.Title)
+		}
+	}
+
+	if len(result.Findings) > 0 {
+		fmt.Printf("\n%d finding(s):\n", len(result.Findings))
+		for _, finding := range result.Findings {
+			fmt.Printf("  [%s] %s: %s\n", finding.Severity, finding.Rule, finding.Message)
+		}
+	}
+
+	if result.Lynis != nil {
+		printLynisReport(result.Lynis)
+	}
+	if result.Packages != nil {
+		printPackageAudit(result.Packages)
+	}
+
+	switch {
+	case result.FailOn == "none":
+		fmt.Println("\nResult: reported only (--fail-on none)")
+	case result.Failed:
+		fmt.Printf("\nResult: FAIL (risk level, a finding or an advisory at or above %s)\n", result.FailOn)
+	default:
+		fmt.Printf("\nResult: PASS (nothing at or above %s)\n", result.FailOn)
+	}
+}
+
+// printLynisReport prints the hardening index and the findings of a Lynis scan
+func printLynisReport(report *model.LynisReport) {
+	fmt.Printf("\nLynis hardening index: %d/100 (%d tests)\n", report.HardeningIndex, report.TestsPerformed)
+	for _, group := range []struct {
+		title    string
+		findings []model.LynisFinding
+	}{
+		{"warning(s)", report.Warnings},
+		{"suggestion(s)", report.Suggestions},
+	} {
+		if len(group.findings) == 0 {
+			continue
+		}
+		fmt.Printf("\n%d Lynis %s:\n", len(group.findings), group.title)
+		for _, finding := range group.findings {
+			line := fmt.Sprintf("  [%s] %s", finding.TestID, finding.Message)
+			if finding.Details != "" {
+				line += " (" + finding.Details + ")"
+			}
+			fmt.Println(line)
+		}
+	}
+}
+
+// printPackageAudit prints every advisory affecting the installed packages
+func printPackageAudit(report *model.PackageAuditReport) {
+	summary := report.Summary()
+	if summary.Total() == 0 {
+		fmt.Printf("\nPackages: no known CVEs in %d packages (%s)\n", summary.Packages, report.Source)
+		return
+	}
+
+	fmt.Printf("\nPackages: %d CVE(s) in %d packages (%s): %s\n",
+		summary.Total(), summary.Packages, report.Source, summary.Describe())
+	for _, vulnerability := range report.Vulnerabilities {
+		line := fmt.Sprintf("  [%s] %s %s", vulnerability.Severity, vulnerability.ID, vulnerability.Package)
+		if vulnerability.Version != "" {
+			line += " " + vulnerability.Version
+		}
+		var notes []string
+		switch {
+		case vulnerability.FixedVersion != "":
+			notes = append(notes, "fixed in "+vulnerability.FixedVersion)
+		case vulnerability.FixAvailable:
+			notes = append(notes, "fix available")
+		}
+		if vulnerability.Remote {
+			notes = append(notes, "remotely exploitable")
+		}
+		if len(notes) > 0 {
+			line += " (" + strings.Join(notes, ", ") + ")"
+		}
+		fmt.Println(line)
+	}
+}
+
+// runAuditReport executes the audit report command
+func runAuditReport(configFile, hardnVersion string) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	cfg.ApplyLogging()
+	applyNetworkConfig(cfg)
+
+	report, err := buildAuditReport(cfg, hardnVersion, auditPolicyDir)
+	if err != nil {
+		return err
+	}
+
+	// "-" sends the report to stdout for collection pipelines
+	if auditOutput == "-" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode audit report: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if err := newAuditManager().SaveReport(auditOutput, report); err != nil {
+		return err
+	}
+
+	passed := 0
+	for _, control := range report.Controls {
+		if control.Status == model.ControlPass {
+			passed++
+		}
+	}
+	fmt.Printf("Audit report written to %s (%d/%d controls passing, %d findings)\n",
+		auditOutput, passed, len(report.Controls), len(report.Findings))
+	return nil
+}
+
+// buildAuditReport checks the host and builds an audit report, with the
+// violations of the rego policies in policyDir when it is set
+func buildAuditReport(cfg *config.Config, hardnVersion, policyDir string) (*model.AuditReport, error) {
+	status, facts, err := hostFacts(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	findings, err := collectAuditFindings(cfg, status, facts.OS.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.AuditReport{
+		Format:       model.AuditReportFormat,
+		HardnVersion: hardnVersion,
+		Hostname:     facts.Hostname,
+		GeneratedAt:  time.Now(),
+		Controls:     security.BuildAuditControls(status),
+		Findings:     findings,
+	}
+
+	if policyDir != "" {
+		policyReport, err := evaluatePolicies(policyDir, facts)
+		if err != nil {
+			return nil, err
+		}
+		report.Findings = append(report.Findings, policyReport.Violations...)
+	}
+	return report, nil
+}
+
+// runAuditDiff executes the audit diff command
+func runAuditDiff(beforePath, afterPath string) error {
+	if auditFormat != "text" && auditFormat != "json" {
+		return fmt.Errorf("unsupported format: %s (use text or json)", auditFormat)
+	}
+
+	diff, err := newAuditManager().CompareReports(beforePath, afterPath)
+	if err != nil {
+		return err
+	}
+
+	if auditFormat == "json" {
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode diff: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		printAuditDiff(diff)
+	}
+
+	if diff.HasRegressions() {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// runAuditShares executes the audit shares command
+func runAuditShares() error {
+	if auditFormat != "text" && auditFormat != "json" {
+		return fmt.Errorf("unsupported format: %s (use text or json)", auditFormat)
+	}
+
+	osInfo, err := osdetect.DetectOS()
+	if err != nil {
+		return fmt.Errorf("failed to detect OS: %w", err)
+	}
+
+	findings, err := newShareManager(osInfo.OsType).AuditShares()
+	if err != nil {
+		return err
+	}
+
+	if auditFormat == "json" {
+		if findings == nil {
+			findings = []model.PolicyViolation{}
+		}
+		data, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode findings: %w", err)
+		}
+		fmt.Println(string(data))
+	} else if len(findings) == 0 {
+		fmt.Println("No exposed NFS or Samba shares found")
+	} else {
+		fmt.Printf("%d share finding(s):\n", len(findings))
+		for _, finding := range findings {
+			fmt.Printf("\n  [%s] %s: %s\n", finding.Severity, finding.Rule, finding.Message)
+			fmt.Printf("    Fix: %s\n", finding.Remediation)
+		}
+	}
+
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// runAuditSSHKeys executes the audit ssh-keys command
+func runAuditSSHKeys(dryRun bool) error {
+	if auditFormat != "text" && auditFormat != "json" {
+		return fmt.Errorf("unsupported format: %s (use text or json)", auditFormat)
+	}
+	if auditRemove && auditFormat == "json" {
+		return fmt.Errorf("--remove cannot be combined with --format json")
+	}
+
+	osInfo, err := osdetect.DetectOS()
+	if err != nil {
+		return fmt.Errorf("failed to detect OS: %w", err)
+	}
+
+	userManager := newUserManager(osInfo.OsType)
+	findings, err := auditSSHKeys(userManager, osInfo.OsType)
+	if err != nil {
+		return err
+	}
+
+	if auditFormat == "json" {
+		if findings == nil {
+			findings = []model.SSHKeyFinding{}
+		}
+		data, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode findings: %w", err)
+		}
+		fmt.Println(string(data))
+	} else if len(findings) == 0 {
+		fmt.Println("No weak, shared, unsafe or expired SSH keys found")
+	} else {
+		printSSHKeyFindings(findings)
+		if auditRemove {
+			removeSSHKeys(userManager, findings, dryRun)
+		}
+	}
+
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// runAuditSudoers executes the audit sudoers command
+func runAuditSudoers() error {
+	if auditFormat != "text" && auditFormat != "json" {
+		return fmt.Errorf("unsupported format: %s (use text or json)", auditFormat)
+	}
+
+	osInfo, err := osdetect.DetectOS()
+	if err != nil {
+		return fmt.Errorf("failed to detect OS: %w", err)
+	}
+
+	findings, err := newSudoersManager(osInfo.OsType).AuditSudoers()
+	if err != nil {
+		return err
+	}
+
+	if auditFormat == "json" {
+		if findings == nil {
+			findings = []model.SudoersFinding{}
+		}
+		data, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode findings: %w", err)
+		}
+		fmt.Println(string(data))
+	} else if len(findings) == 0 {
+		fmt.Println("No overly broad sudoers rules found")
+	} else {
+		fmt.Printf("%d sudoers finding(s):\n", len(findings))
+		for _, finding := range findings {
+			violation := finding.Violation()
+			fmt.Printf("\n  [%s] %s:%d: %s\n", finding.Severity, finding.File, finding.Line, finding.Detail)
+			fmt.Printf("    %s\n", finding.Text)
+			fmt.Printf("    Fix: %s\n", violation.Remediation)
+		}
+	}
+
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// runAuditSources executes the audit sources command
+func runAuditSources() error {
+	if auditFormat != "text" && auditFormat != "json" {
+		return fmt.Errorf("unsupported format: %s (use text or json)", auditFormat)
+	}
+
+	osInfo, err := osdetect.DetectOS()
+	if err != nil {
+		return fmt.Errorf("failed to detect OS: %w", err)
+	}
+
+	findings, err := newRepositoryTrustManager(osInfo.OsType).CheckTrust()
+	if err != nil {
+		return err
+	}
+
+	if auditFormat == "json" {
+		if findings == nil {
+			findings = []model.RepoTrustFinding{}
+		}
+		data, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode findings: %w", err)
+		}
+		fmt.Println(string(data))
+	} else if len(findings) == 0 {
+		fmt.Println("Every repository is checked against its signing key")
+	} else {
+		fmt.Printf("%d repository trust finding(s):\n", len(findings))
+		for _, finding := range findings {
+			violation := finding.Violation()
+			fmt.Printf("\n  [%s] %s: %s\n", finding.Severity, finding.File, finding.Detail)
+			fmt.Printf("    Fix: %s\n", violation.Remediation)
+		}
+	}
+
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// auditSSHKeys returns the weak, shared and unsafe keys followed by the
+// keys past their expiry date
+func auditSSHKeys(userManager *application.UserManager, osType string) ([]model.SSHKeyFinding, error) {
+	findings, err := userManager.AuditSSHKeys()
+	if err != nil {
+		return nil, err
+	}
+	expired, err := newSSHKeyInventoryManager(osType).ExpiredKeyFindings()
+	if err != nil {
+		return nil, err
+	}
+	return append(findings, expired...), nil
+}
+
+// printSSHKeyFindings prints findings grouped by user
+func printSSHKeyFindings(findings []model.SSHKeyFinding) {
+	fmt.Printf("%d SSH key finding(s):\n", len(findings))
+
+	current := ""
+	for _, finding := range findings {
+		if finding.Username != current {
+			current = finding.Username
+			fmt.Printf("\n%s:\n", current)
+		}
+
+		key := finding.Fingerprint
+		if finding.Comment != "" {
+			key += " (" + finding.Comment + ")"
+		}
+		fmt.Printf("  [%s] %s %s\n", finding.Severity, key, finding.Detail)
+	}
+}
+
+// removeSSHKeys offers each flagged key for removal, once per user
+func removeSSHKeys(userManager *application.UserManager, findings []model.SSHKeyFinding, dryRun bool) {
+	reader := bufio.NewReader(os.Stdin)
+	offered := make(map[string]bool)
+
+	fmt.Println()
+	for _, finding := range findings {
+		id := finding.Username + " " + finding.Fingerprint
+		if offered[id] {
+			continue
+		}
+		offered[id] = true
+
+		key := finding.Fingerprint
+		if finding.Comment != "" {
+			key += " (" + finding.Comment + ")"
+		}
+
+		fmt.Printf("Remove %s key %s from %s? [y/N]: ", finding.KeyType, key, finding.Username)
+		answer, _ := reader.ReadString('\n')
+		if answer = strings.TrimSpace(strings.ToLower(answer)); answer != "y" && answer != "yes" {
+			continue
+		}
+
+		if dryRun {
+			fmt.Printf("  [DRY-RUN] Would remove %s from %s\n", finding.Fingerprint, finding.Username)
+			continue
+		}
+		if err := userManager.RemoveSSHKey(finding.Username, finding.Fingerprint); err != nil {
+			fmt.Printf("  Failed to remove key: %v\n", err)
+			continue
+		}
+		fmt.Printf("  Removed %s from %s\n", finding.Fingerprint, finding.Username)
+	}
+}
+
+// printAuditDiff prints an audit diff as plain text
+func printAuditDiff(diff *model.AuditDiff) {
+	fmt.Printf("Before: %s (hardn %s)\n", diff.Before.GeneratedAt.Format(time.RFC3339), reportVersion(diff.Before))
+	fmt.Printf("After:  %s (hardn %s)\n", diff.After.GeneratedAt.Format(time.RFC3339), reportVersion(diff.After))
+
+	if len(diff.ChangedControls) == 0 && len(diff.NewFindings) == 0 && len(diff.ResolvedFindings) == 0 {
+		fmt.Println("\nNo changes between reports")
+		return
+	}
+
+	if len(diff.ChangedControls) > 0 {
+		fmt.Println("\nChanged controls:")
+		for _, change := range diff.ChangedControls {
+			marker := "~"
+			if change.Regressed() {
+				marker = "-"
+			} else if change.After == model.ControlPass {
+				marker = "+"
+			}
+			fmt.Printf("  %s %-32s %s -> %s\n", marker, change.ID, change.Before, change.After)
+		}
+	}
+
+	printFindings("New findings:", "+", diff.NewFindings)
+	printFindings("Resolved findings:", "-", diff.ResolvedFindings)
+}
+
+// printFindings prints a titled list of findings, if any
+func printFindings(title, marker string, findings []model.PolicyViolation) {
+	if len(findings) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s\n", title)
+	for _, finding := range findings {
+		if finding.Rule != "" {
+			fmt.Printf("  %s %s: %s\n", marker, finding.Rule, finding.Message)
+		} else {
+			fmt.Printf("  %s %s\n", marker, finding.Message)
+		}
+	}
+}
+
+// reportVersion returns the hardn version recorded in a report
+func reportVersion(report *model.AuditReport) string {
+	if report.HardnVersion == "" {
+		return "unknown"
+	}
+	return report.HardnVersion
+}
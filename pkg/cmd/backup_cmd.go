@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/infrastructure"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/style"
+	"github.com/spf13/cobra"
+)
+
+var backupJSON bool
+
+// BackupCmd returns the backup command
+func BackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Mirror the file backups to an S3 bucket or SFTP host",
+		Long: `Copy the backups hardn makes before changing a file to the remote target of
+the backupRemote section of the configuration:
+
+  s3://bucket/prefix              an S3 bucket, or an S3-compatible service
+                                  with backupRemote.endpoint (AWS CLI)
+  sftp://user@host[:port]/path    a directory of an SSH host, reached with
+                                  key authentication (OpenSSH sftp)
+
+run-all syncs the backups after each run when a target is set. Backups are
+copied as they are on disk, so encrypted backups stay encrypted remotely.`,
+	}
+
+	syncCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Copy the backups to the remote target",
+		Long: `Copy the date directories of the backup directory to the remote target, then
+remove remote date directories older than backupRemote.retentionDays. With
+a retention, only the backups within it are copied; without one, those of
+the last 90 days are. A failed sync is sent to the configured email
+address and webhooks.
+
+Examples:
+  sudo hardn backup sync
+  sudo hardn backup sync --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBackupSync(cmd)
+		},
+	}
+	syncCmd.Flags().BoolVar(&backupJSON, "json", false, "Output in JSON format")
+
+	testCmd := &cobra.Command{
+		Use:   "test",
+		Short: "Check that the remote target is reachable and writable",
+		Long: `Write a test file to the remote target with the configured credentials and
+remove it again.
+
+Examples:
+  sudo hardn backup test`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBackupTest(cmd)
+		},
+	}
+
+	cmd.AddCommand(syncCmd)
+	cmd.AddCommand(testCmd)
+	return cmd
+}
+
+// runBackupSync executes the backup sync command
+func runBackupSync(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if ctx.dryRun {
+		fmt.Printf("[DRY-RUN] Would copy the backups of %s to %s\n", ctx.cfg.BackupPath, ctx.cfg.BackupRemote.URL)
+		return nil
+	}
+
+	factory := ctx.serviceFactory()
+	started := time.Now()
+	result, err := SyncBackupRemote(factory, ctx.cfg)
+	if err != nil {
+		notifyBackupSyncFailure(factory, ctx.cfg, started, err)
+		return err
+	}
+
+	if backupJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode the sync result: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	printBackupSync(result)
+	return nil
+}
+
+// runBackupTest executes the backup test command
+func runBackupTest(cmd *cobra.Command) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.serviceFactory().CreateBackupManager().CheckRemote(); err != nil {
+		fmt.Printf("%s %s is not usable\n", style.Colored(style.Red, style.SymCrossMark), ctx.cfg.BackupRemote.URL)
+		return err
+	}
+	fmt.Printf("%s %s is reachable and writable\n", style.Colored(style.Green, style.SymCheckMark), ctx.cfg.BackupRemote.URL)
+	return nil
+}
+
+// SyncBackupRemote copies the backups to the remote target and logs the
+// outcome
+func SyncBackupRemote(factory *infrastructure.ServiceFactory, cfg *config.Config) (*model.BackupSyncResult, error) {
+	result, err := factory.CreateBackupManager().SyncRemote()
+	if err != nil {
+		logging.LogError("Failed to sync backups to %s: %v", cfg.BackupRemote.URL, err)
+		return nil, fmt.Errorf("failed to sync backups: %w", err)
+	}
+	logging.LogSuccess("Backups synced to %s (%d uploaded, %d removed)",
+		result.Target, len(result.Uploaded), len(result.Removed))
+	return result, nil
+}
+
+// notifyBackupSyncFailure sends a failed sync to the configured email
+// address and webhooks
+func notifyBackupSyncFailure(factory *infrastructure.ServiceFactory, cfg *config.Config, started time.Time, err error) {
+	manager := factory.CreateNotificationManager()
+	if !manager.Enabled() {
+		return
+	}
+	hostname, _ := os.Hostname()
+	sendSummary(manager, model.RunSummary{
+		Hostname:    hostname,
+		Operation:   model.SummaryBackupSync,
+		StartedAt:   started,
+		FinishedAt:  time.Now(),
+		Failed:      true,
+		FailedSteps: []string{fmt.Sprintf("sync to %s: %v", cfg.BackupRemote.URL, err)},
+	})
+}
+
+// printBackupSync prints the date directories a sync copied and removed
+func printBackupSync(result *model.BackupSyncResult) {
+	list := func(dates []string) string {
+		if len(dates) == 0 {
+			return "none"
+		}
+		return strings.Join(dates, ", ")
+	}
+
+	fmt.Printf("%-10s %s\n", "Target", result.Target)
+	fmt.Printf("%-10s %s\n", "Uploaded", list(result.Uploaded))
+	fmt.Printf("%-10s %s\n", "Removed", list(result.Removed))
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/infrastructure"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/spf13/cobra"
+)
+
+var bannerJSON bool
+
+// BannerCmd returns the banner command
+func BannerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "banner",
+		Short: "Manage the login banners and the message of the day",
+		Long: `Write the pre-login warning banners, /etc/issue for local consoles and
+/etc/issue.net for SSH, and a message of the day showing the last hardening
+run, the risk level and the pending updates. The texts come from the banner
+section of the configuration.`,
+	}
+
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Write the banners and install the dynamic MOTD",
+		Long: `Write /etc/issue and /etc/issue.net and point the sshd Banner directive at
+/etc/issue.net. On Debian and Ubuntu the MOTD is rendered at each login by
+an update-motd script; Ubuntu's news and advertising scripts are turned off.
+Elsewhere /etc/motd is rewritten now and after each run-all.
+
+Examples:
+  sudo hardn banner apply --dry-run
+  sudo hardn banner apply`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBannerApply(cmd)
+		},
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the banners and MOTD scripts in place",
+		Long: `Show which banner files exist, whether sshd shows a banner, whether the
+dynamic MOTD is installed and which of Ubuntu's advertising MOTD scripts are
+still enabled.
+
+Examples:
+  hardn banner status
+  hardn banner status --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBannerStatus(cmd)
+		},
+	}
+	statusCmd.Flags().BoolVar(&bannerJSON, "json", false, "Output in JSON format")
+
+	motdCmd := &cobra.Command{
+		Use:   "motd",
+		Short: "Print the message of the day",
+		Long: `Print the MOTD template of the configuration with the last hardening run,
+the risk level and the number of pending updates filled in. The update-motd
+script installed by 'hardn banner apply' runs this at each login.
+
+Examples:
+  sudo hardn banner motd`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBannerMotd(cmd)
+		},
+	}
+
+	cmd.AddCommand(applyCmd)
+	cmd.AddCommand(statusCmd)
+	cmd.AddCommand(motdCmd)
+	return cmd
+}
+
+// runBannerApply executes the banner apply command
+func runBannerApply(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	factory := ctx.serviceFactory()
+	manager := factory.CreateBannerManager()
+	policy := ctx.cfg.Banner.Policy()
+
+	if err := manager.ApplyBanners(policy); err != nil {
+		return fmt.Errorf("failed to write the login banners: %w", err)
+	}
+	if err := manager.RefreshMotd(policy, lastHardeningRun(factory), riskLevel(ctx.cfg, ctx.osInfo)); err != nil {
+		return fmt.Errorf("failed to write the MOTD: %w", err)
+	}
+
+	if !ctx.dryRun {
+		logging.LogSuccess("Login banners written")
+	}
+	return nil
+}
+
+// runBannerStatus executes the banner status command
+func runBannerStatus(cmd *cobra.Command) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	status, err := ctx.serviceFactory().CreateBannerManager().GetBannerStatus()
+	if err != nil {
+		return err
+	}
+
+	if bannerJSON {
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode banner status: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printBannerStatus(status)
+	return nil
+}
+
+// runBannerMotd executes the banner motd command. It runs at each login,
+// so it prints nothing but the MOTD.
+func runBannerMotd(cmd *cobra.Command) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	factory := ctx.serviceFactory()
+
+	motd := factory.CreateBannerManager().RenderMotd(
+		ctx.cfg.Banner.Policy(), lastHardeningRun(factory), riskLevel(ctx.cfg, ctx.osInfo))
+	fmt.Print(strings.TrimRight(motd, "\n") + "\n")
+	return nil
+}
+
+// RefreshMotd rewrites /etc/motd after a hardening run on hosts that cannot
+// render the MOTD at login. Failures are reported but do not fail the run.
+func RefreshMotd(factory *infrastructure.ServiceFactory, cfg *config.Config, osInfo *osdetect.OSInfo) {
+	manager := factory.CreateBannerManager()
+	if err := manager.RefreshMotd(cfg.Banner.Policy(), time.Now(), riskLevel(cfg, osInfo)); err != nil {
+		logging.LogWarning("Failed to update the MOTD: %v", err)
+	}
+}
+
+// lastHardeningRun returns when the latest recorded run started, or the
+// zero time when none is recorded
+func lastHardeningRun(factory *infrastructure.ServiceFactory) time.Time {
+	runs, err := factory.CreateRunManager().ListRuns()
+	if err != nil || len(runs) == 0 {
+		return time.Time{}
+	}
+	return runs[len(runs)-1].Started
+}
+
+// printBannerStatus prints the banner files and MOTD scripts
+func printBannerStatus(status *model.BannerStatus) {
+	present := func(exists bool) string {
+		if exists {
+			return "present"
+		}
+		return "missing"
+	}
+
+	fmt.Printf("%-16s %s\n", model.IssuePath, present(status.Issue))
+	fmt.Printf("%-16s %s\n", model.IssueNetPath, present(status.IssueNet))
+
+	sshBanner := status.SSHBanner
+	if sshBanner == "" {
+		sshBanner = "none"
+	}
+	fmt.Printf("%-16s %s\n", "SSH banner", sshBanner)
+
+	switch {
+	case status.DynamicMotd:
+		fmt.Printf("%-16s rendered at login by %s\n", "MOTD", model.UpdateMotdScriptPath)
+	case status.UpdateMotd:
+		fmt.Printf("%-16s update-motd available; run 'hardn banner apply' to install\n", "MOTD")
+	default:
+		fmt.Printf("%-16s %s\n", "MOTD", model.MotdPath)
+	}
+
+	if len(status.MotdAds) > 0 {
+		fmt.Printf("%-16s %s\n", "MOTD ads", strings.Join(status.MotdAds, ", "))
+	}
+}
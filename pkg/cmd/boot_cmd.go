@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var bootJSON bool
+
+// BootCmd returns the boot command
+func BootCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "boot",
+		Short: "Harden the kernel command line and the GRUB boot loader",
+		Long: `Manage the bootHardening section of the configuration: kernel parameters
+set on the kernel command line and the password GRUB asks for before its
+entries can be edited.`,
+	}
+
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Set the configured kernel parameters and GRUB password",
+		Long: `Apply the bootHardening section of the configuration:
+
+  parameters  add these to the kernel command line, replacing parameters
+              with the same name, e.g. lockdown=confidentiality
+  password    require this password, or grub.pbkdf2 hash, to edit boot
+              entries or use the GRUB shell; entries still boot without it
+
+The boot loader files are backed up first. On Debian and Ubuntu grub.cfg
+is generated next to the current one and only replaces it once
+grub-script-check accepts it; the RHEL family updates its boot entries
+with grubby. Parameters take effect at the next boot.
+
+Examples:
+  sudo hardn boot apply --dry-run
+  sudo hardn boot apply`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBootApply(cmd)
+		},
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the boot hardening in effect",
+		Long: `Show which configured kernel parameters are set in the boot loader and
+in effect, whether a reboot is required, and whether GRUB has a password.
+
+Examples:
+  sudo hardn boot status
+  sudo hardn boot status --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBootStatus(cmd)
+		},
+	}
+	statusCmd.Flags().BoolVar(&bootJSON, "json", false, "Output in JSON format")
+
+	cmd.AddCommand(applyCmd)
+	cmd.AddCommand(statusCmd)
+	return cmd
+}
+
+// runBootApply executes the boot apply command
+func runBootApply(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireBootedKernel(); err != nil {
+		return err
+	}
+
+	policy := ctx.cfg.BootHardening.Policy()
+	if len(policy.Parameters) == 0 && policy.Password == "" {
+		return fmt.Errorf("no kernel parameters or GRUB password configured (bootHardening in the configuration)")
+	}
+
+	bootManager := ctx.serviceFactory().CreateBootHardeningManager()
+	if err := bootManager.ApplyHardening(policy); err != nil {
+		return fmt.Errorf("failed to apply boot hardening: %w", err)
+	}
+	if ctx.dryRun {
+		return nil
+	}
+
+	if len(policy.Parameters) > 0 {
+		logging.LogSuccess("Kernel parameters set: %s", strings.Join(policy.Parameters, " "))
+	}
+	if policy.Password != "" {
+		logging.LogSuccess("GRUB password set for %s", model.GrubSuperuser)
+	}
+
+	status, err := bootManager.GetStatus(policy)
+	if err != nil {
+		return err
+	}
+	if status.RebootRequired {
+		logging.LogWarning("Reboot to apply: %s", strings.Join(status.Pending, " "))
+	}
+	return nil
+}
+
+// runBootStatus executes the boot status command
+func runBootStatus(cmd *cobra.Command) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireBootedKernel(); err != nil {
+		return err
+	}
+
+	status, err := ctx.serviceFactory().CreateBootHardeningManager().GetStatus(ctx.cfg.BootHardening.Policy())
+	if err != nil {
+		return err
+	}
+
+	if bootJSON {
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode boot hardening status: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printBootStatus(status)
+	return nil
+}
+
+// printBootStatus prints the boot hardening in effect
+func printBootStatus(status *model.BootHardeningStatus) {
+	list := func(params []string) string {
+		if len(params) == 0 {
+			return "none"
+		}
+		return strings.Join(params, " ")
+	}
+	password := "not set"
+	if status.PasswordSet {
+		password = "set"
+	}
+	reboot := "no"
+	if status.RebootRequired {
+		reboot = "yes"
+	}
+
+	fmt.Printf("%-18s %s\n", "Configured", list(status.Configured))
+	fmt.Printf("%-18s %s\n", "Missing", list(status.Missing))
+	fmt.Printf("%-18s %s\n", "Pending reboot", list(status.Pending))
+	fmt.Printf("%-18s %s\n", "GRUB password", password)
+	fmt.Printf("%-18s %s\n", "Reboot required", reboot)
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/version"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes reported by check-update
+const (
+	ExitUpToDate       = 0
+	ExitUpdate         = 1
+	ExitSecurityUpdate = 2
+	ExitCheckFailed    = 3
+)
+
+var (
+	quietCheck   bool
+	downloadPath string
+)
+
+// CheckUpdateCmd returns the check-update command
+func CheckUpdateCmd(versionService *version.Service) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check-update",
+		Short: "Check whether a newer hardn release is available",
+		Long: `Check GitHub for a newer hardn release without starting the menu.
+
+Exit status:
+  0  hardn is up to date
+  1  an update is available
+  2  a security update is available
+  3  the check failed
+
+An available release is verified before it is recommended: its published
+checksum for this OS and architecture is downloaded and, when the checksum
+file is signed and cosign or minisign is installed, the signature is
+checked. A release that fails verification is reported with status 3.
+
+With --download the release binary for this OS and architecture is saved
+to the given path and checked against the release checksum when one is
+published.
+
+The result is cached for updateCheckHours (a day by default). With
+--offline, or offline: true in the configuration, GitHub is not asked:
+the last cached result is shown however old, and without one the check
+is skipped with status 3. When GitHub cannot be reached the last cached
+result is shown too.
+
+Examples:
+  hardn check-update --quiet || echo "hardn update available"   # MOTD or cron
+  hardn check-update --download /tmp/hardn`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, err := loadUpdateConfig(cmd)
+			if err != nil {
+				if !quietCheck {
+					fmt.Fprintf(os.Stderr, "Update check failed: %v\n", err)
+				}
+				os.Exit(ExitCheckFailed)
+			}
+			options := &version.UpdateOptions{
+				Debug:    logging.ModuleDebugEnabled(logging.ModuleVersion),
+				CacheTTL: cfg.UpdateCheckTTL(),
+				Offline:  interfaces.Offline(),
+			}
+
+			// Reuse the root test flags to exercise the exit codes
+			if flagEnabled(cmd, "test-update") || flagEnabled(cmd, "test-security-update") {
+				options.ForceUpdate = true
+				options.ForcedVersion = "99.0.0"
+				options.ForceSecurityUpdate = flagEnabled(cmd, "test-security-update")
+			}
+
+			code := RunCheckUpdate(versionService, options, quietCheck)
+			if downloadPath != "" && (code == ExitUpdate || code == ExitSecurityUpdate) && !options.ForceUpdate {
+				if err := downloadRelease(versionService, options, downloadPath); err != nil {
+					fmt.Fprintf(os.Stderr, "Download failed: %v\n", err)
+					code = ExitCheckFailed
+				}
+			}
+			os.Exit(code)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&quietCheck, "quiet", "q", false, "Print nothing; report through the exit status only")
+	cmd.Flags().StringVar(&downloadPath, "download", "", "Save the new release binary to this path")
+
+	return cmd
+}
+
+// loadUpdateConfig loads the configuration for its update check and
+// offline settings, or uses the defaults when there is no file, since
+// update checks run from cron and MOTD scripts where the offer to create
+// one would wait for an answer
+func loadUpdateConfig(cmd *cobra.Command) (*config.Config, error) {
+	configFile := ""
+	if flag := cmd.Flag("config"); flag != nil {
+		configFile = flag.Value.String()
+	}
+
+	cfg := config.DefaultConfig()
+	if _, found := config.FindConfigFile(configFile); found {
+		var err error
+		if cfg, err = config.LoadConfig(configFile); err != nil {
+			return nil, fmt.Errorf("failed to load configuration: %w", err)
+		}
+	}
+	applyNetworkConfig(cfg)
+	return cfg, nil
+}
+
+// flagEnabled reads an inherited boolean flag by name
+func flagEnabled(cmd *cobra.Command, name string) bool {
+	flag := cmd.Flag(name)
+	return flag != nil && flag.Value.String() == "true"
+}
+
+// RunCheckUpdate performs the check and returns the exit status
+func RunCheckUpdate(versionService *version.Service, options *version.UpdateOptions, quiet bool) int {
+	result := versionService.CheckForUpdates(options)
+
+	// Development builds carry no version to compare against
+	if result.CurrentVersion == "" && result.Error == nil && !options.ForceUpdate {
+		result.Error = fmt.Errorf("this build has no version information")
+	}
+
+	if errors.Is(result.Error, version.ErrOffline) {
+		if !quiet {
+			fmt.Println("Update check skipped: offline mode is on and no earlier result is cached")
+		}
+		return ExitCheckFailed
+	}
+	if result.Error != nil {
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Update check failed: %v\n", result.Error)
+		}
+		return ExitCheckFailed
+	}
+
+	if result.UpdateAvailable && result.Verification.Failed() {
+		if !quiet {
+			fmt.Fprintf(os.Stderr, "Release %s failed verification: %s\n", result.LatestVersion, result.Verification.Detail)
+		}
+		return ExitCheckFailed
+	}
+
+	status := "up to date"
+	code := ExitUpToDate
+	switch {
+	case result.SecurityUpdateAvailable:
+		status = "security update available"
+		code = ExitSecurityUpdate
+	case result.UpdateAvailable:
+		status = "update available"
+		code = ExitUpdate
+	}
+
+	if quiet {
+		return code
+	}
+
+	latest := result.LatestVersion
+	if latest == "" {
+		latest = "unknown"
+	}
+
+	fmt.Printf("Current version: %s\n", result.CurrentVersion)
+	fmt.Printf("Latest version:  %s\n", latest)
+	fmt.Printf("Status:          %s\n", status)
+	if result.Stale {
+		fmt.Printf("Checked:         %s (GitHub not reached; last cached result)\n", result.CheckedAt.Format("2006-01-02 15:04"))
+	} else if !result.CheckedAt.IsZero() {
+		fmt.Printf("Checked:         %s\n", result.CheckedAt.Format("2006-01-02 15:04"))
+	}
+	if code != ExitUpToDate {
+		if result.SecurityUpdateDetails != "" {
+			fmt.Printf("Details:         %s\n", result.SecurityUpdateDetails)
+		}
+		if result.ReleaseURL != "" {
+			fmt.Printf("Release:         %s\n", result.ReleaseURL)
+		}
+		if result.Assets.BinaryURL != "" {
+			fmt.Printf("Binary:          %s\n", result.Assets.BinaryURL)
+		} else if result.Assets.BinaryName != "" {
+			fmt.Printf("Binary:          no %s build in this release\n", result.Assets.BinaryName)
+		}
+		if result.Assets.ChecksumURL != "" {
+			fmt.Printf("Checksum:        %s\n", result.Assets.ChecksumURL)
+		}
+		if result.Verification.Status != "" {
+			fmt.Printf("Verified:        %s\n", verificationText(result.Verification))
+		}
+		if result.InstallURL != "" {
+			fmt.Printf("Install:         %s\n", result.InstallURL)
+		}
+	}
+
+	return code
+}
+
+// downloadRelease saves the latest release binary for this platform to path
+func downloadRelease(versionService *version.Service, options *version.UpdateOptions, path string) error {
+	if err := interfaces.RequireNetwork("download the release"); err != nil {
+		return err
+	}
+
+	// The result comes from the cache written by the check just made
+	result := versionService.CheckForUpdates(options)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	checksum, err := version.DownloadBinary(context.Background(), result.Assets, path)
+	if err != nil {
+		return err
+	}
+
+	if !quietCheck {
+		fmt.Printf("Downloaded %s to %s\n", result.Assets.BinaryName, path)
+		if result.Assets.ChecksumURL != "" {
+			fmt.Printf("SHA-256:         %s (verified)\n", checksum)
+		} else {
+			fmt.Printf("SHA-256:         %s (no checksum published)\n", checksum)
+		}
+	}
+	return nil
+}
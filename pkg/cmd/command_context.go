@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+	"github.com/abbott/hardn/pkg/infrastructure"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/spf13/cobra"
+)
+
+// commandContext holds what the subcommands that change the system share
+type commandContext struct {
+	configFile string
+	cfg        *config.Config
+	osInfo     *osdetect.OSInfo
+	provider   *interfaces.Provider
+	dryRun     bool
+}
+
+// currentRun records the changes of this invocation, once one has started
+var currentRun *secondary.RunJournal
+
+// hardnVersion is recorded with each run
+var hardnVersion string
+
+// SetVersion sets the hardn version recorded with the runs this process
+// starts
+func SetVersion(version string) {
+	hardnVersion = version
+}
+
+// newCommandContext prepares a command that changes the system, refusing
+// to continue outside a maintenance window unless overridden. Its changes
+// are recorded as a run that 'hardn rollback' can revert. Run as a normal
+// user, it asks for the sudo password once.
+func newCommandContext(cmd *cobra.Command) (*commandContext, error) {
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dry runs escalate too, so they read what only root can read
+	if err := escalate(); err != nil {
+		return nil, err
+	}
+	ctx.provider = interfaces.NewProvider()
+
+	if ctx.dryRun {
+		ctx.provider = dryRunProvider(ctx.provider, ctx.cfg)
+		return ctx, nil
+	}
+
+	if err := checkMaintenanceWindow(ctx.cfg, flagEnabled(cmd, "override-window")); err != nil {
+		return nil, err
+	}
+	ctx.provider = StartRun(ctx.provider, cmd.CommandPath(), ctx.cfg, ctx.osInfo)
+	return ctx, nil
+}
+
+// escalate makes the providers created from here on run privileged
+// commands and file changes with sudo when hardn does not run as root,
+// asking for the password once
+func escalate() error {
+	if interfaces.IsRoot() {
+		return nil
+	}
+	return interfaces.EnableSudo()
+}
+
+// dryRunProvider returns a provider that prints each file change as a diff
+// and the commands that would change the system, instead of making them
+func dryRunProvider(provider *interfaces.Provider, cfg *config.Config) *interfaces.Provider {
+	return interfaces.DryRunProvider(provider, func() bool { return true }, os.Stdout, cfg.BackupPath)
+}
+
+// StartRun returns a provider that records the changes made through it as
+// a run of hardn, described by description, and starts the run's report
+func StartRun(provider *interfaces.Provider, description string, cfg *config.Config, osInfo *osdetect.OSInfo) *interfaces.Provider {
+	journaled, journal := infrastructure.JournalProvider(provider, description, cfg)
+	journal.SetVersion(hardnVersion)
+	currentRun = journal
+	guardSSHChanges(journal, cfg, osInfo)
+	beginRunReport(journal, description, cfg, osInfo)
+	return journaled
+}
+
+// ReportRun tells the user how to roll back the changes this invocation
+// made, if it made any, and saves the report of the run, which ended with
+// err
+func ReportRun(err error) {
+	if currentRun != nil {
+		if err := currentRun.Err(); err != nil {
+			logging.LogWarning("Not every change was recorded for rollback: %v", err)
+		}
+		if id := currentRun.RunID(); id != "" {
+			logging.LogInfo("Changes were recorded as run %s; undo them with 'sudo hardn rollback %s'", id, id)
+		}
+	}
+	saveRunReport(err)
+}
+
+// loadCommandContext loads the configuration and detects the OS
+func loadCommandContext(cmd *cobra.Command) (*commandContext, error) {
+	configFile := ""
+	if flag := cmd.Flag("config"); flag != nil {
+		configFile = flag.Value.String()
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	cfg.ApplyLogging()
+	applyNetworkConfig(cfg)
+
+	osInfo, err := osdetect.DetectOS()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect OS: %w", err)
+	}
+
+	return &commandContext{
+		configFile: configFile,
+		cfg:        cfg,
+		osInfo:     osInfo,
+		provider:   interfaces.NewProvider(),
+		dryRun:     flagEnabled(cmd, "dry-run"),
+	}, nil
+}
+
+// applyNetworkConfig passes the configured proxy to external commands and
+// turns on offline mode when the configuration asks for it; --offline has
+// already turned it on for every command
+func applyNetworkConfig(cfg *config.Config) {
+	interfaces.SetCommandProxy(cfg.HttpProxy)
+	if cfg.Offline {
+		interfaces.SetOffline(true)
+	}
+}
+
+// domainOSInfo converts the detected OS for the domain services
+func domainOSInfo(osInfo *osdetect.OSInfo) model.OSInfo {
+	return model.OSInfo{
+		Type:      osInfo.OsType,
+		Codename:  osInfo.OsCodename,
+		Version:   osInfo.OsVersion,
+		IsProxmox: osInfo.IsProxmox,
+		Arch:      osInfo.Arch,
+	}
+}
+
+// newFirewallManager wires a FirewallManager for the detected OS
+func newFirewallManager(provider *interfaces.Provider, osInfo *osdetect.OSInfo) *application.FirewallManager {
+	provider = interfaces.DebugProvider(provider, logging.ModuleFirewall)
+	firewallRepo := secondary.NewFirewallRepository(provider.FS, provider.Commander, osInfo.OsType)
+	firewallService := service.NewFirewallServiceImpl(firewallRepo, domainOSInfo(osInfo))
+	return application.NewFirewallManager(firewallService)
+}
+
+// checkMaintenanceWindow returns an error when changes are not allowed now
+func checkMaintenanceWindow(cfg *config.Config, override bool) error {
+	windows, err := service.ParseMaintenanceWindows(cfg.MaintenanceWindows)
+	if err != nil {
+		return fmt.Errorf("invalid maintenance window configuration: %w", err)
+	}
+
+	maintenanceManager := application.NewMaintenanceManager(service.NewMaintenanceServiceImpl(windows))
+	if err := maintenanceManager.CheckMutation(override); err != nil {
+		return fmt.Errorf("%w; use --override-window to apply changes now", err)
+	}
+	return nil
+}
+
+// portAllowed reports whether the ufw rules allow TCP port from anywhere.
+// Rules are listed as ufw commands, e.g. "allow 22/tcp" or "limit 22/tcp".
+func portAllowed(rules []string, port int) bool {
+	for _, rule := range rules {
+		fields := strings.Fields(rule)
+		if len(fields) == 2 && (fields[0] == "allow" || fields[0] == "limit") &&
+			(fields[1] == strconv.Itoa(port)+"/tcp" || fields[1] == strconv.Itoa(port)) {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceFactory returns a ServiceFactory for managers that need the full
+// configuration, such as the package manager
+func (c *commandContext) serviceFactory() *infrastructure.ServiceFactory {
+	factory := infrastructure.NewServiceFactory(c.provider, c.osInfo)
+	factory.SetConfig(c.cfg)
+	return factory
+}
+
+// requireSupportedOS refuses distribution-specific modules in degraded mode
+func (c *commandContext) requireSupportedOS(module string) error {
+	if c.osInfo.Degraded {
+		return fmt.Errorf("%s is not available on unsupported OS %s (degraded mode supports %s only)",
+			module, c.osInfo.OsType, strings.Join(osdetect.DegradedModules, ", "))
+	}
+	return nil
+}
+
+// requireOutsideWSL refuses modules hardn skips on WSL, named as in
+// osdetect.WSLSkippedModules
+func (c *commandContext) requireOutsideWSL(module string) error {
+	for _, skipped := range c.osInfo.WSLSkipped() {
+		if skipped == module {
+			return fmt.Errorf("%s is not available on WSL %d; run 'hardn wsl' for what to do in Windows or /etc/wsl.conf instead",
+				module, c.osInfo.WSLVersion)
+		}
+	}
+	return nil
+}
+
+// requireBootedKernel refuses boot hardening where hardn does not manage
+// the boot loader
+func (c *commandContext) requireBootedKernel() error {
+	if err := c.requireSupportedOS("boot hardening"); err != nil {
+		return err
+	}
+	if err := c.requireOutsideWSL("boot hardening"); err != nil {
+		return err
+	}
+	if c.osInfo.Environment.IsContainer() {
+		return fmt.Errorf("boot hardening is not available in a container; containers share the host's kernel")
+	}
+	return nil
+}
+
+// requireProxmox refuses the Proxmox VE settings on other hosts
+func (c *commandContext) requireProxmox() error {
+	if !c.osInfo.IsProxmox {
+		return fmt.Errorf("this host is not a Proxmox VE host")
+	}
+	return nil
+}
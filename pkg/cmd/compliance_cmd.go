@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/security"
+	"github.com/abbott/hardn/pkg/style"
+	"github.com/spf13/cobra"
+)
+
+var (
+	complianceFormat string
+	complianceOutput string
+)
+
+// ComplianceCmd returns the compliance command
+func ComplianceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compliance",
+		Short: "Score the host against the CIS Debian and Ubuntu benchmarks",
+		Long: `Check the host against the CIS Debian Linux and Ubuntu Linux benchmark
+recommendations that hardn covers and score it: the share of applicable
+recommendations that pass. Recommendations that do not apply, such as the
+sshd checks on a host without sshd, do not count.
+
+Each recommendation maps to a hardn check, explained by "hardn explain",
+and each failure names the hardn action that remediates it.
+
+The report is a table by default; --format json and --format html export
+it, to stdout or to the --output file.
+
+Examples:
+  sudo hardn compliance
+  sudo hardn compliance --format html --output cis-report.html
+  sudo hardn compliance --format json | jq '.results[] | select(.status == "fail")'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCompliance(cmd)
+		},
+	}
+
+	cmd.Flags().StringVarP(&complianceFormat, "format", "O", "text", "Output format (text, json, html)")
+	cmd.Flags().StringVarP(&complianceOutput, "output", "o", "", "File to write the report to (default: stdout)")
+	return cmd
+}
+
+// runCompliance executes the compliance command
+func runCompliance(cmd *cobra.Command) error {
+	if complianceFormat != "text" && complianceFormat != "json" && complianceFormat != "html" {
+		return fmt.Errorf("unsupported format: %s (use text, json or html)", complianceFormat)
+	}
+
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	status, err := security.CheckSecurityStatus(ctx.cfg, ctx.osInfo)
+	if err != nil {
+		return fmt.Errorf("failed to collect security status: %w", err)
+	}
+
+	hostname, _ := os.Hostname()
+	report, err := ctx.serviceFactory().CreateComplianceManager().RunBenchmark(model.ComplianceRequest{
+		Hostname: hostname,
+		OSType:   ctx.osInfo.OsType,
+		Controls: security.BuildComplianceControls(status),
+	})
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if complianceOutput != "" {
+		file, err := os.Create(complianceOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", complianceOutput, err)
+		}
+		defer file.Close()
+		out = file
+
+		// Color codes would end up in the file
+		colors := style.UseColors
+		style.UseColors = false
+		defer func() { style.UseColors = colors }()
+	}
+
+	switch complianceFormat {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode compliance report: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	case "html":
+		if err := complianceHTML.Execute(out, report); err != nil {
+			return fmt.Errorf("failed to write compliance report: %w", err)
+		}
+	default:
+		printCompliance(out, report)
+	}
+
+	if complianceOutput != "" {
+		fmt.Printf("Compliance report written to %s (score %d%%)\n", complianceOutput, report.Score)
+	}
+	return nil
+}
+
+// printCompliance prints the recommendations as a table, then each
+// failure with its detail and remediation
+func printCompliance(out io.Writer, report *model.ComplianceReport) {
+	fmt.Fprintf(out, "%s\n", report.Benchmark)
+	fmt.Fprintf(out, "Host: %s, %s\n\n", report.Hostname, report.GeneratedAt.Format("2006-01-02 15:04"))
+
+	fmt.Fprintf(out, "  %-8s %-5s %-6s %s\n", "ID", "Level", "Status", "Recommendation")
+	for _, result := range report.Results {
+		state := style.Colored(style.Green, fmt.Sprintf("%-6s", result.Status))
+		switch result.Status {
+		case model.ComplianceFail:
+			state = style.Colored(style.Red, fmt.Sprintf("%-6s", result.Status))
+		case model.ComplianceNotApplicable:
+			state = style.Dimmed(fmt.Sprintf("%-6s", result.Status))
+		}
+		fmt.Fprintf(out, "  %-8s %-5d %s %s\n", result.ID, result.Level, state, result.Title)
+	}
+
+	fmt.Fprintf(out, "\nScore: %d%% (%d passed, %d failed, %d not applicable)\n",
+		report.Score, report.Passed, report.Failed, report.NotApplicable)
+
+	first := true
+	for _, result := range report.Results {
+		if result.Status != model.ComplianceFail {
+			continue
+		}
+		if first {
+			fmt.Fprintln(out, "\nRemediation:")
+			first = false
+		}
+		fmt.Fprintf(out, "\n  %s %s %s\n", style.Colored(style.Red, style.SymCrossMark), result.ID, result.Title)
+		if result.Detail != "" {
+			fmt.Fprintf(out, "    Found:   %s\n", result.Detail)
+		}
+		fmt.Fprintf(out, "    Fix:     %s\n", result.Remediation)
+		fmt.Fprintf(out, "    Explain: hardn explain %s\n", result.Check)
+	}
+}
+
+// complianceHTML renders a compliance report as a standalone page
+var complianceHTML = template.Must(template.New("compliance").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Benchmark}}: {{.Hostname}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ddd; padding: 0.4em 0.6em; text-align: left; vertical-align: top; }
+code { background: #f4f4f4; padding: 0 0.2em; }
+.pass { color: #1a7f37; }
+.fail { color: #cf222e; font-weight: bold; }
+.na { color: #888; }
+</style>
+</head>
+<body>
+<h1>{{.Benchmark}}</h1>
+<p>Host <strong>{{.Hostname}}</strong>, {{.GeneratedAt.Format "2006-01-02 15:04 MST"}}</p>
+<p>Score <strong>{{.Score}}%</strong>: {{.Passed}} passed, {{.Failed}} failed, {{.NotApplicable}} not applicable</p>
+<table>
+<tr><th>ID</th><th>Level</th><th>Recommendation</th><th>Status</th><th>Remediation</th></tr>
+{{range .Results}}<tr>
+<td>{{.ID}}</td>
+<td>{{.Level}}</td>
+<td>{{.Title}}{{if .Detail}}<br><small>{{.Detail}}</small>{{end}}</td>
+<td class="{{if eq .Status "pass"}}pass{{else if eq .Status "fail"}}fail{{else}}na{{end}}">{{.Status}}</td>
+<td>{{if eq .Status "fail"}}<code>{{.Remediation}}</code><br><small>hardn explain {{.Check}}</small>{{end}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	configRecipients []string
+	configIdentity   string
+	configOutput     string
+)
+
+// ConfigCmd returns the config command
+func ConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Encrypt and decrypt the secrets of the configuration",
+		Long: `Keep passwords, webhook URLs, SSH keys and tokens out of hardn.yml in plain
+text. A value can either be read from the environment when the
+configuration is loaded:
+
+  password: "${env:HARDN_SMTP_PASSWORD}"
+
+or be encrypted with age, sops style, so the file can be shared while only
+hosts with the identity can read the secrets:
+
+  password: ENC[age,YWdlLWVuY3J5cHRpb24ub3JnL3Yx...]
+
+Encrypted values are decrypted with the identity file of secrets.identity
+(HARDN_AGE_IDENTITY overrides it, ` + config.DefaultAgeIdentity + ` by default) whenever the
+configuration is loaded. Secrets are redacted from the log and from dry-run
+output, and saving the configuration from the menu writes the references,
+not the secrets.`,
+	}
+
+	encryptCmd := &cobra.Command{
+		Use:   "encrypt [FILE]",
+		Short: "Encrypt the secret values of a configuration file",
+		Long: `Encrypt the values of the keys password, webhooks and sshKeys, of keys ending
+in "token" or "secret", and of the keys listed in secrets.keys, for the age
+recipients of secrets.recipients or --recipient. Values already encrypted
+or read from the environment are left alone, and comments are kept. The
+file is rewritten unless --output is given; --output - prints it.
+
+FILE defaults to the configuration hardn loads.
+
+Examples:
+  sudo hardn config encrypt
+  sudo hardn config encrypt /etc/hardn/hardn.yml --recipient age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigSecrets(cmd, args, true)
+		},
+	}
+	encryptCmd.Flags().StringSliceVar(&configRecipients, "recipient", nil, "age recipient to encrypt for (default: secrets.recipients)")
+	encryptCmd.Flags().StringVarP(&configOutput, "output", "o", "", "Write to this file instead, - for standard output")
+
+	decryptCmd := &cobra.Command{
+		Use:   "decrypt [FILE]",
+		Short: "Print a configuration file with its secrets decrypted",
+		Long: `Decrypt the encrypted values of a configuration file with the age identity
+and print the result. --output writes it to a file instead, which may be
+the configuration file itself to stop encrypting it.
+
+FILE defaults to the configuration hardn loads.
+
+Examples:
+  sudo hardn config decrypt
+  sudo hardn config decrypt --identity ~/.config/hardn/age.key --output hardn.plain.yml`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigSecrets(cmd, args, false)
+		},
+	}
+	decryptCmd.Flags().StringVar(&configIdentity, "identity", "", "age identity file (default: secrets.identity)")
+	decryptCmd.Flags().StringVarP(&configOutput, "output", "o", "-", "Write to this file instead of standard output")
+
+	cmd.AddCommand(encryptCmd)
+	cmd.AddCommand(decryptCmd)
+	return cmd
+}
+
+// runConfigSecrets executes the config encrypt and decrypt commands
+func runConfigSecrets(cmd *cobra.Command, args []string, encrypt bool) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	path := ""
+	if len(args) == 1 {
+		path = args[0]
+	} else {
+		explicit := ""
+		if flag := cmd.Flag("config"); flag != nil {
+			explicit = flag.Value.String()
+		}
+		if path = config.AbsConfigFile(explicit); path == "" {
+			return fmt.Errorf("no hardn configuration file found")
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var settings struct {
+		Secrets config.Secrets `yaml:"secrets"`
+	}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	var result []byte
+	var count int
+	if encrypt {
+		recipients := configRecipients
+		if len(recipients) == 0 {
+			recipients = settings.Secrets.Recipients
+		}
+		result, count, err = config.EncryptSecrets(data, config.NewAgeCipher(recipients, ""), settings.Secrets.Keys)
+	} else {
+		identity := configIdentity
+		if identity == "" {
+			identity = config.SecretsIdentity(data)
+		}
+		result, count, err = config.DecryptSecrets(data, config.NewAgeCipher(nil, identity))
+	}
+	if err != nil {
+		return err
+	}
+
+	output := configOutput
+	if output == "" {
+		output = path
+	}
+	if output == "-" {
+		fmt.Print(string(result))
+		return nil
+	}
+
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(output); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(output, result, mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	verb := "Decrypted"
+	if encrypt {
+		verb = "Encrypted"
+	}
+	fmt.Printf("%s %d values of %s into %s\n", verb, count, path, output)
+	return nil
+}
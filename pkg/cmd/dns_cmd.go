@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dnsDomain string
+	dnsJSON   bool
+)
+
+// DNSCmd returns the dns command
+func DNSCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dns",
+		Short: "Configure and show DNS resolvers",
+		Long: `Set the system's nameservers without the interactive menu. The resolver
+backend (systemd-resolved, resolvconf or resolv.conf) is detected
+automatically. Use --dry-run to preview a change.`,
+	}
+
+	setCmd := &cobra.Command{
+		Use:   "set [NAMESERVER...]",
+		Short: "Set the nameservers",
+		Long: `Write the given nameservers, or the nameservers from the configuration
+when none are given. Nameservers must be IP addresses.
+
+Examples:
+  sudo hardn dns set
+  sudo hardn dns set 1.1.1.1 9.9.9.9 --domain example.lan`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDNSSet(cmd, args)
+		},
+	}
+	setCmd.Flags().StringVar(&dnsDomain, "domain", "lan", "Search domain")
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the current nameservers",
+		Long: `Show the nameservers, search domains and resolver backend in use.
+
+Examples:
+  hardn dns show
+  hardn dns show --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDNSShow(cmd)
+		},
+	}
+	showCmd.Flags().BoolVar(&dnsJSON, "json", false, "Output in JSON format")
+
+	cmd.AddCommand(setCmd)
+	cmd.AddCommand(showCmd)
+	return cmd
+}
+
+// runDNSSet executes the dns set command
+func runDNSSet(cmd *cobra.Command, args []string) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireOutsideWSL("DNS"); err != nil {
+		return err
+	}
+	dnsManager := ctx.serviceFactory().CreateDNSManager()
+
+	nameservers := args
+	if len(nameservers) == 0 {
+		nameservers = ctx.cfg.Nameservers
+	}
+	if len(nameservers) == 0 {
+		return fmt.Errorf("no nameservers given and none configured (nameservers in the configuration)")
+	}
+	for _, nameserver := range nameservers {
+		if err := dnsManager.ValidateNameserver(nameserver); err != nil {
+			return err
+		}
+	}
+
+	// In dry-run mode the provider prints the change as a diff
+	if err := dnsManager.ConfigureDNS(nameservers, dnsDomain); err != nil {
+		return fmt.Errorf("failed to configure DNS: %w", err)
+	}
+	if ctx.dryRun {
+		return nil
+	}
+
+	logging.LogSuccess("DNS configured: %s", strings.Join(nameservers, " "))
+	fmt.Printf("Nameservers set to %s\n", strings.Join(nameservers, " "))
+	return nil
+}
+
+// runDNSShow executes the dns show command
+func runDNSShow(cmd *cobra.Command) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	dnsConfig, err := ctx.serviceFactory().CreateDNSManager().GetCurrentConfig()
+	if err != nil {
+		return fmt.Errorf("failed to read DNS configuration: %w", err)
+	}
+	if dnsConfig.Nameservers == nil {
+		dnsConfig.Nameservers = []string{}
+	}
+
+	if dnsJSON {
+		data, err := json.MarshalIndent(dnsConfig, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode DNS configuration: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Resolver:    %s\n", dnsConfig.Implementation)
+	fmt.Printf("Nameservers: %s\n", strings.Join(dnsConfig.Nameservers, " "))
+	if len(dnsConfig.Search) > 0 {
+		fmt.Printf("Search:      %s\n", strings.Join(dnsConfig.Search, " "))
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/security"
+	"github.com/spf13/cobra"
+)
+
+var explainJSON bool
+
+// ExplainCmd returns the explain command
+func ExplainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain [CHECK]",
+		Short: "Explain a status or audit check",
+		Long: `Print what a check inspects, why it matters, how hardn remediates it and
+the files and commands involved. CHECK is an audit control ID such as
+ssh.root_login_disabled or webserver.nginx.tls_baseline, or a label from
+the status display such as "SSH Port". Without CHECK every check is listed.
+
+Examples:
+  hardn explain
+  hardn explain ssh.password_auth_disabled
+  hardn explain "Auto Updates" --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return runExplainList()
+			}
+			return runExplain(args[0])
+		},
+	}
+	cmd.Flags().BoolVar(&explainJSON, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// runExplainList lists every check
+func runExplainList() error {
+	if explainJSON {
+		return printExplainJSON(security.Checks)
+	}
+
+	for _, check := range security.Checks {
+		label := ""
+		if check.Label != "" {
+			label = " [" + check.Label + "]"
+		}
+		fmt.Printf("%-32s %s%s\n", check.ID, check.Title, label)
+	}
+	return nil
+}
+
+// runExplain explains the checks matching name
+func runExplain(name string) error {
+	checks := security.LookupChecks(name)
+	if len(checks) == 0 {
+		return fmt.Errorf("unknown check %q (run hardn explain to list checks)", name)
+	}
+
+	if explainJSON {
+		return printExplainJSON(checks)
+	}
+
+	for i, check := range checks {
+		if i > 0 {
+			fmt.Println()
+		}
+		printCheck(check)
+	}
+	return nil
+}
+
+// printCheck prints one check's explanation
+func printCheck(check model.CheckInfo) {
+	fmt.Printf("%s: %s\n", check.ID, check.Title)
+	if check.Label != "" {
+		fmt.Printf("Status label:  %s\n", check.Label)
+	}
+	switch {
+	case check.Audited:
+		fmt.Println("Audit control: yes")
+	case check.Label != "":
+		fmt.Println("Audit control: no (status display only)")
+	default:
+		fmt.Println("Audit control: no (compliance report only)")
+	}
+	for _, rule := range model.CISRules {
+		if rule.Check == check.ID {
+			fmt.Printf("CIS benchmark: %s %s\n", rule.ID, rule.Title)
+		}
+	}
+
+	fmt.Printf("\nInspects:\n  %s\n", check.Inspects)
+	fmt.Printf("\nWhy it matters:\n  %s\n", check.Why)
+	fmt.Printf("\nRemediation:\n  %s\n", check.Remediation)
+	if len(check.Files) > 0 {
+		fmt.Printf("\nFiles:\n  %s\n", strings.Join(check.Files, "\n  "))
+	}
+	if len(check.Commands) > 0 {
+		fmt.Printf("\nCommands:\n  %s\n", strings.Join(check.Commands, "\n  "))
+	}
+}
+
+// printExplainJSON prints checks as JSON, leaving the <placeholder> in
+// templated IDs unescaped
+func printExplainJSON(checks []model.CheckInfo) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(checks); err != nil {
+		return fmt.Errorf("failed to encode checks: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/security"
+	"github.com/spf13/cobra"
+)
+
+var (
+	factsInstall   bool
+	factsUninstall bool
+)
+
+// FactsCmd returns the facts command
+func FactsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "facts",
+		Short: "Print hardn's assessment as Ansible local facts",
+		Long: `Print the host, OS, security status, users and firewall state as one flat
+JSON object, the format Ansible reads from local facts. Nested values are
+joined with "_" (ssh_port, firewall_enabled, os_type), and the object also
+holds risk_level, risk_description and hardn_version.
+
+With --install, hardn writes ` + model.AnsibleFactsPath + `, a script that
+runs "hardn facts" whenever Ansible gathers facts, so playbooks can branch
+on ansible_local.hardn. --uninstall removes it again. hardn facts never
+asks to create a configuration file; without one the defaults are used.
+
+Examples:
+  sudo hardn facts
+  sudo hardn facts --install
+  ansible all -m setup -a filter=ansible_local`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if factsInstall || factsUninstall {
+				return runFactsInstall(cmd)
+			}
+			return runFacts(cmd)
+		},
+	}
+
+	cmd.Flags().BoolVar(&factsInstall, "install", false, "Install the facts script in "+model.AnsibleFactsDir)
+	cmd.Flags().BoolVar(&factsUninstall, "uninstall", false, "Remove the facts script")
+	cmd.MarkFlagsMutuallyExclusive("install", "uninstall")
+	return cmd
+}
+
+// runFacts executes the facts command
+func runFacts(cmd *cobra.Command) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	configFile := ""
+	if flag := cmd.Flag("config"); flag != nil {
+		configFile = flag.Value.String()
+	}
+
+	// Ansible runs the facts script without a terminal, where the offer to
+	// create a configuration would wait for an answer
+	cfg := config.DefaultConfig()
+	if _, found := config.FindConfigFile(configFile); found {
+		var err error
+		if cfg, err = config.LoadConfig(configFile); err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+	}
+	cfg.ApplyLogging()
+
+	status, facts, err := hostFacts(cfg)
+	if err != nil {
+		return err
+	}
+	document, err := security.AnsibleFacts(facts, status, hardnVersion)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode facts: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// runFactsInstall installs or removes the Ansible facts script
+func runFactsInstall(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	manager := ctx.serviceFactory().CreateAnsibleFactsManager()
+
+	if factsUninstall {
+		if !manager.FactsScriptInstalled() {
+			fmt.Println("The Ansible facts script is not installed")
+			return nil
+		}
+		if err := manager.RemoveFactsScript(); err != nil {
+			return err
+		}
+		logging.LogSuccess("Removed %s", model.AnsibleFactsPath)
+		return nil
+	}
+
+	binary, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the hardn binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(binary); err == nil {
+		binary = resolved
+	}
+
+	if err := manager.InstallFactsScript(binary); err != nil {
+		return err
+	}
+	if !ctx.dryRun {
+		logging.LogSuccess("Installed %s; Ansible shows the facts as ansible_local.hardn", model.AnsibleFactsPath)
+	}
+	return nil
+}
@@ -0,0 +1,735 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/spf13/cobra"
+)
+
+var (
+	firewallFrom    string
+	firewallApply   bool
+	firewallComment string
+	firewallForce   bool
+	firewallJSON    bool
+	firewallMirror  bool
+	firewallYes     bool
+)
+
+// FirewallCmd returns the firewall command
+func FirewallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "firewall",
+		Short: "Change and inspect the firewall",
+		Long: `Enable or disable the firewall and add individual rules without the
+interactive menu. hardn manages UFW, or firewalld on Rocky Linux, AlmaLinux
+and Fedora. Every change is written to the hardn log. Use --dry-run to preview
+a change.`,
+	}
+
+	enableCmd := &cobra.Command{
+		Use:   "enable",
+		Short: "Enable the firewall",
+		Long: `Enable the firewall. The SSH port from the configuration is allowed first when no
+rule allows it yet, so enabling the firewall does not lock out SSH.
+
+Example:
+  sudo hardn firewall enable`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFirewallEnable(cmd)
+		},
+	}
+
+	disableCmd := &cobra.Command{
+		Use:   "disable",
+		Short: "Disable the firewall",
+		Long: `Disable the firewall. Rules are kept and apply again once the firewall is enabled.
+
+Example:
+  sudo hardn firewall disable`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFirewallDisable(cmd)
+		},
+	}
+
+	allowCmd := &cobra.Command{
+		Use:   "allow PORT[/PROTO]",
+		Short: "Allow traffic to a port",
+		Long: `Add a rule allowing traffic to a port. The protocol defaults to tcp.
+
+Examples:
+  sudo hardn firewall allow 443
+  sudo hardn firewall allow 53/udp --from 10.0.0.0/24 --comment "Internal DNS"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFirewallRule(cmd, "allow", args[0])
+		},
+	}
+	allowCmd.Flags().StringVar(&firewallFrom, "from", "", "Only allow this address or network")
+	allowCmd.Flags().StringVar(&firewallComment, "comment", "", "Comment stored with the rule")
+
+	denyCmd := &cobra.Command{
+		Use:   "deny PORT[/PROTO]",
+		Short: "Deny traffic to a port",
+		Long: `Add a rule denying traffic to a port. The protocol defaults to tcp.
+
+Denying the configured SSH port is refused unless --force is given.
+
+Examples:
+  sudo hardn firewall deny 8080
+  sudo hardn firewall deny 5432 --from 192.168.1.0/24`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFirewallRule(cmd, "deny", args[0])
+		},
+	}
+	denyCmd.Flags().StringVar(&firewallFrom, "from", "", "Only deny this address or network")
+	denyCmd.Flags().StringVar(&firewallComment, "comment", "", "Comment stored with the rule")
+	denyCmd.Flags().BoolVar(&firewallForce, "force", false, "Deny the SSH port anyway")
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show whether the firewall is enabled and its rules",
+		Long: `Show whether the firewall is installed, enabled and denying incoming traffic by
+default, along with the rules hardn and others have added.
+
+Examples:
+  sudo hardn firewall status
+  sudo hardn firewall status --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFirewallStatus()
+		},
+	}
+	statusCmd.Flags().BoolVar(&firewallJSON, "json", false, "Output in JSON format")
+
+	dualStackCmd := &cobra.Command{
+		Use:   "dual-stack",
+		Short: "Compare the firewall rules for IPv4 and IPv6",
+		Long: `Compare what the firewall lets in over IPv4 and IPv6 on hosts with an IPv6
+address. UFW with IPV6=no in /etc/default/ufw filters IPv4 only, leaving every
+service open over IPv6; rules added for a single family leave a service
+reachable over one and not the other.
+
+--mirror filters a family left open and adds the missing rules to the other
+family. Rules limited to an address can not be mirrored and are listed for
+review.
+
+Examples:
+  sudo hardn firewall dual-stack
+  sudo hardn firewall dual-stack --mirror --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if firewallMirror {
+				return runFirewallMirror(cmd)
+			}
+			return runFirewallDualStack(cmd)
+		},
+	}
+	dualStackCmd.Flags().BoolVar(&firewallMirror, "mirror", false, "Filter both families and add the missing rules")
+	dualStackCmd.Flags().BoolVar(&firewallJSON, "json", false, "Output in JSON format")
+
+	syncCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Re-apply the configured rules when the firewall drifted",
+		Long: `Compare the rules the configuration declares with the live ruleset and
+report the rules missing from the firewall and those added outside hardn.
+The declared rules are the SSH port, ufwAllowedPorts, firewallRules and the
+ports of ufwAppProfiles.
+
+When they differ, the firewall is reset to the declared rules after
+confirmation; rules added outside hardn are removed. With --dry-run the
+commands are shown instead. --json only reports the drift, and exits with
+status 1 when the firewall drifted.
+
+Examples:
+  sudo hardn firewall sync --dry-run
+  sudo hardn firewall sync --yes
+  sudo hardn firewall sync --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFirewallSync(cmd)
+		},
+	}
+	syncCmd.Flags().BoolVar(&firewallYes, "yes", false, "Do not ask for confirmation")
+	syncCmd.Flags().BoolVar(&firewallJSON, "json", false, "Output the drift in JSON format")
+
+	ipv6Cmd := &cobra.Command{
+		Use:   "ipv6",
+		Short: "Show and apply the IPv6 mode",
+		Long: `Show how other hosts reach this one over IPv6: whether the kernel runs IPv6,
+whether the host has an IPv6 address, and the rules letting IPv6 traffic in,
+compared with the ipv6 mode of the configuration:
+
+  filter   IPv6 gets the same rules as IPv4 (the default)
+  deny     IPv6 is let in only to SSH and to rules with family: ipv6
+  disable  IPv6 is turned off with sysctl, or on the kernel command line
+           with ipv6.bootParameter
+
+--apply configures the firewall and the kernel for the mode after
+confirmation. A kernel command line change takes effect after a reboot.
+--json exits with status 1 when IPv6 does not match the mode.
+
+Examples:
+  hardn firewall ipv6
+  sudo hardn firewall ipv6 --apply --dry-run
+  sudo hardn firewall ipv6 --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if firewallApply {
+				return runFirewallIPv6Apply(cmd)
+			}
+			return runFirewallIPv6(cmd)
+		},
+	}
+	ipv6Cmd.Flags().BoolVar(&firewallApply, "apply", false, "Configure the firewall and the kernel for the IPv6 mode")
+	ipv6Cmd.Flags().BoolVar(&firewallYes, "yes", false, "Do not ask for confirmation")
+	ipv6Cmd.Flags().BoolVar(&firewallJSON, "json", false, "Output in JSON format")
+
+	cmd.AddCommand(enableCmd)
+	cmd.AddCommand(disableCmd)
+	cmd.AddCommand(allowCmd)
+	cmd.AddCommand(denyCmd)
+	cmd.AddCommand(statusCmd)
+	cmd.AddCommand(dualStackCmd)
+	cmd.AddCommand(syncCmd)
+	cmd.AddCommand(ipv6Cmd)
+	return cmd
+}
+
+// firewallStatus is the output of "hardn firewall status --json"
+type firewallStatus struct {
+	Installed  bool     `json:"installed"`
+	Enabled    bool     `json:"enabled"`
+	Configured bool     `json:"configured"`
+	Rules      []string `json:"rules"`
+}
+
+// runFirewallEnable executes the firewall enable command
+func runFirewallEnable(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireSupportedOS("The firewall"); err != nil {
+		return err
+	}
+	if err := ctx.requireOutsideWSL("firewall"); err != nil {
+		return err
+	}
+	firewallManager := newFirewallManager(ctx.provider, ctx.osInfo)
+
+	installed, enabled, _, rules, err := firewallManager.GetFirewallStatus()
+	if err != nil {
+		return fmt.Errorf("failed to get firewall status: %w", err)
+	}
+	if !installed {
+		return fmt.Errorf("the firewall is not installed; configure it with 'hardn -w'")
+	}
+	if enabled {
+		fmt.Println("Firewall is already enabled")
+		return nil
+	}
+
+	// With port knocking knockd opens SSH, so no rule is added
+	ssh := ctx.cfg.SSHFirewallPolicy()
+	sshRule, addSSHRule := ssh.Rule()
+	addSSHRule = addSSHRule && !portAllowed(rules, ssh.Port)
+	if ctx.dryRun {
+		if addSSHRule {
+			fmt.Printf("[DRY-RUN] Would %s %d/tcp (SSH) through the firewall\n", sshRule.Action, ssh.Port)
+		}
+		fmt.Println("[DRY-RUN] Would enable the firewall")
+		return nil
+	}
+
+	if addSSHRule {
+		if err := firewallManager.AddSSHRule(ssh); err != nil {
+			return fmt.Errorf("failed to allow SSH port %d: %w", ssh.Port, err)
+		}
+		logging.LogInfo("Added firewall rule %q before enabling the firewall", sshRule.Spec())
+		fmt.Printf("Added %s (SSH) to the firewall\n", sshRule.Spec())
+	}
+	if ssh.Knocking != nil {
+		fmt.Printf("SSH port %d stays closed until the knock sequence\n", ssh.Port)
+	}
+
+	if err := firewallManager.EnableFirewall(); err != nil {
+		return err
+	}
+	logging.LogSuccess("Firewall enabled")
+	fmt.Println("Firewall enabled")
+	return nil
+}
+
+// runFirewallDisable executes the firewall disable command
+func runFirewallDisable(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireSupportedOS("The firewall"); err != nil {
+		return err
+	}
+	if err := ctx.requireOutsideWSL("firewall"); err != nil {
+		return err
+	}
+
+	if ctx.dryRun {
+		fmt.Println("[DRY-RUN] Would disable the firewall")
+		return nil
+	}
+
+	if err := newFirewallManager(ctx.provider, ctx.osInfo).DisableFirewall(); err != nil {
+		return err
+	}
+	logging.LogWarning("Firewall disabled")
+	fmt.Println("Firewall disabled")
+	return nil
+}
+
+// runFirewallRule executes the firewall allow and deny commands
+func runFirewallRule(cmd *cobra.Command, action string, spec string) error {
+	port, protocol, err := service.ParsePortSpec(spec)
+	if err != nil {
+		return err
+	}
+	if firewallFrom != "" {
+		if err := service.ValidateRuleSource(firewallFrom); err != nil {
+			return err
+		}
+	}
+
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireSupportedOS("The firewall"); err != nil {
+		return err
+	}
+	if err := ctx.requireOutsideWSL("firewall"); err != nil {
+		return err
+	}
+
+	if action == "deny" && port == ctx.cfg.SshPort && protocol == "tcp" && !firewallForce {
+		return fmt.Errorf("port %d is the SSH port; use --force to deny it anyway", port)
+	}
+
+	description := fmt.Sprintf("%s %d/%s", action, port, protocol)
+	if firewallFrom != "" {
+		description += " from " + firewallFrom
+	}
+
+	if ctx.dryRun {
+		fmt.Printf("[DRY-RUN] Would %s\n", description)
+		return nil
+	}
+
+	firewallManager := newFirewallManager(ctx.provider, ctx.osInfo)
+	if action == "deny" {
+		err = firewallManager.DenyPort(port, protocol, firewallFrom, firewallComment)
+	} else {
+		err = firewallManager.AllowPort(port, protocol, firewallFrom, firewallComment)
+	}
+	if err != nil {
+		return err
+	}
+
+	logging.LogSuccess("Firewall rule added: %s", description)
+	fmt.Printf("Added rule: %s\n", description)
+	return nil
+}
+
+// runFirewallStatus executes the firewall status command
+func runFirewallStatus() error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	osInfo, err := osdetect.DetectOS()
+	if err != nil {
+		return fmt.Errorf("failed to detect OS: %w", err)
+	}
+
+	installed, enabled, configured, rules, err := newFirewallManager(interfaces.NewProvider(), osInfo).GetFirewallStatus()
+	if err != nil {
+		return fmt.Errorf("failed to get firewall status: %w", err)
+	}
+	status := firewallStatus{
+		Installed:  installed,
+		Enabled:    enabled,
+		Configured: configured,
+		Rules:      rules,
+	}
+	if status.Rules == nil {
+		status.Rules = []string{}
+	}
+
+	if firewallJSON {
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode firewall status: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Installed:    %s\n", yesNo(status.Installed))
+	fmt.Printf("Enabled:      %s\n", yesNo(status.Enabled))
+	fmt.Printf("Default deny: %s\n", yesNo(status.Configured))
+	if len(status.Rules) > 0 {
+		fmt.Printf("\nRules:\n  %s\n", strings.Join(status.Rules, "\n  "))
+	}
+	return nil
+}
+
+// runFirewallDualStack executes the firewall dual-stack command
+func runFirewallDualStack(cmd *cobra.Command) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	report, err := newFirewallManager(ctx.provider, ctx.osInfo).CheckDualStack(ctx.cfg.IPv6.Policy().Mode)
+	if err != nil {
+		return err
+	}
+
+	if firewallJSON {
+		for _, stack := range []*model.StackRules{&report.IPv4, &report.IPv6} {
+			if stack.Allowed == nil {
+				stack.Allowed = []string{}
+			}
+		}
+		if report.Gaps == nil {
+			report.Gaps = []model.StackGap{}
+		}
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode firewall stacks: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printDualStack(report)
+	return nil
+}
+
+// runFirewallMirror executes the firewall dual-stack --mirror command
+func runFirewallMirror(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireSupportedOS("The firewall"); err != nil {
+		return err
+	}
+	if err := ctx.requireOutsideWSL("firewall"); err != nil {
+		return err
+	}
+	firewallManager := newFirewallManager(ctx.provider, ctx.osInfo)
+
+	if ctx.dryRun {
+		report, err := firewallManager.CheckDualStack(ctx.cfg.IPv6.Policy().Mode)
+		if err != nil {
+			return err
+		}
+		if report.Synchronized() {
+			fmt.Println("IPv4 and IPv6 rules already match")
+			return nil
+		}
+		if report.Unfiltered != "" {
+			fmt.Printf("[DRY-RUN] Would filter incoming %s traffic\n", model.FamilyName(report.Unfiltered))
+		}
+		for _, gap := range report.Gaps {
+			if gap.Mirrorable {
+				fmt.Printf("[DRY-RUN] Would add %q for %s\n", gap.Rule, model.FamilyName(gap.MissingOn))
+			}
+		}
+		return nil
+	}
+
+	report, err := firewallManager.MirrorDualStack(ctx.cfg.IPv6.Policy().Mode)
+	if err != nil {
+		return err
+	}
+	logging.LogSuccess("Firewall rules mirrored between IPv4 and IPv6")
+	printDualStack(report)
+	return nil
+}
+
+// runFirewallSync executes the firewall sync command
+func runFirewallSync(cmd *cobra.Command) error {
+	if firewallJSON {
+		logging.SetSilentMode(true)
+		defer logging.SetSilentMode(false)
+	}
+
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireSupportedOS("The firewall"); err != nil {
+		return err
+	}
+	if err := ctx.requireOutsideWSL("firewall"); err != nil {
+		return err
+	}
+	firewallManager := newFirewallManager(ctx.provider, ctx.osInfo)
+	cfg := ctx.cfg
+	rules := cfg.CustomFirewallRules()
+	profiles := cfg.FirewallProfiles()
+
+	drift, err := firewallManager.CheckDrift(cfg.SSHFirewallPolicy(), cfg.IPv6.Policy(), cfg.UfwAllowedPorts, rules, profiles)
+	if err != nil {
+		return err
+	}
+
+	if firewallJSON {
+		for _, list := range []*[]string{&drift.Declared, &drift.Missing, &drift.Extra} {
+			if *list == nil {
+				*list = []string{}
+			}
+		}
+		data, err := json.MarshalIndent(drift, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode firewall drift: %w", err)
+		}
+		fmt.Println(string(data))
+		if !drift.InSync() {
+			os.Exit(1)
+		}
+		return nil
+	}
+
+	printFirewallDrift(drift)
+	if drift.InSync() {
+		return nil
+	}
+
+	if !ctx.dryRun && !firewallYes {
+		fmt.Print("\nReset the firewall to the declared rules? [y/N]: ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer = strings.TrimSpace(strings.ToLower(answer)); answer != "y" && answer != "yes" {
+			fmt.Println("Sync cancelled.")
+			return nil
+		}
+	}
+
+	fmt.Println()
+	if err := firewallManager.ConfigureSecureFirewall(cfg.SSHFirewallPolicy(), cfg.IPv6.Policy(), cfg.UfwAllowedPorts, rules, profiles); err != nil {
+		return fmt.Errorf("failed to re-apply the firewall rules: %w", err)
+	}
+	if ctx.dryRun {
+		return nil
+	}
+	logging.LogSuccess("Firewall reset to %d declared rules", len(drift.Declared))
+
+	drift, err = firewallManager.CheckDrift(cfg.SSHFirewallPolicy(), cfg.IPv6.Policy(), cfg.UfwAllowedPorts, rules, profiles)
+	if err != nil {
+		return err
+	}
+	printFirewallDrift(drift)
+	return nil
+}
+
+// runFirewallIPv6 executes the firewall ipv6 command
+func runFirewallIPv6(cmd *cobra.Command) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	cfg := ctx.cfg
+
+	exposure, err := newFirewallManager(ctx.provider, ctx.osInfo).CheckIPv6(cfg.SSHFirewallPolicy(),
+		cfg.IPv6.Policy(), cfg.UfwAllowedPorts, cfg.CustomFirewallRules(), cfg.FirewallProfiles())
+	if err != nil {
+		return err
+	}
+
+	if firewallJSON {
+		for _, list := range []*[]string{&exposure.Allowed, &exposure.Unexpected} {
+			if *list == nil {
+				*list = []string{}
+			}
+		}
+		data, err := json.MarshalIndent(exposure, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode IPv6 exposure: %w", err)
+		}
+		fmt.Println(string(data))
+		if !exposure.Compliant() {
+			os.Exit(1)
+		}
+		return nil
+	}
+
+	printIPv6Exposure(exposure)
+	return nil
+}
+
+// runFirewallIPv6Apply executes the firewall ipv6 --apply command
+func runFirewallIPv6Apply(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireSupportedOS("The firewall"); err != nil {
+		return err
+	}
+	if err := ctx.requireOutsideWSL("firewall"); err != nil {
+		return err
+	}
+	firewallManager := newFirewallManager(ctx.provider, ctx.osInfo)
+	cfg := ctx.cfg
+	ipv6 := cfg.IPv6.Policy()
+	rules := cfg.CustomFirewallRules()
+	profiles := cfg.FirewallProfiles()
+
+	exposure, err := firewallManager.CheckIPv6(cfg.SSHFirewallPolicy(), ipv6, cfg.UfwAllowedPorts, rules, profiles)
+	if err != nil {
+		return err
+	}
+	printIPv6Exposure(exposure)
+
+	if !ctx.dryRun && !firewallYes {
+		fmt.Printf("\nReset the firewall and apply the IPv6 %s mode? [y/N]: ", ipv6.Mode)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer = strings.TrimSpace(strings.ToLower(answer)); answer != "y" && answer != "yes" {
+			fmt.Println("IPv6 mode not applied.")
+			return nil
+		}
+	}
+
+	fmt.Println()
+	if err := firewallManager.ConfigureSecureFirewall(cfg.SSHFirewallPolicy(), ipv6, cfg.UfwAllowedPorts, rules, profiles); err != nil {
+		return fmt.Errorf("failed to apply the IPv6 mode: %w", err)
+	}
+	if ctx.dryRun {
+		return nil
+	}
+	logging.LogSuccess("Applied the IPv6 %s mode", ipv6.Mode)
+
+	exposure, err = firewallManager.CheckIPv6(cfg.SSHFirewallPolicy(), ipv6, cfg.UfwAllowedPorts, rules, profiles)
+	if err != nil {
+		return err
+	}
+	printIPv6Exposure(exposure)
+	if ipv6.Mode == model.IPv6Disable && ipv6.BootParameter && exposure.Enabled {
+		fmt.Println("\nIPv6 is turned off on the kernel command line; reboot to apply it")
+	}
+	return nil
+}
+
+// printIPv6Exposure prints how the kernel and the firewall treat IPv6
+func printIPv6Exposure(exposure *model.IPv6Exposure) {
+	kernel := "enabled"
+	switch {
+	case !exposure.Enabled && exposure.BootDisabled:
+		kernel = "disabled on the kernel command line"
+	case !exposure.Enabled:
+		kernel = "disabled with sysctl"
+	}
+	fmt.Printf("Mode:     %s\n", exposure.Mode)
+	fmt.Printf("Kernel:   %s\n", kernel)
+	if exposure.Enabled {
+		fmt.Printf("Address:  %s\n", yesNo(exposure.Addressed))
+		fmt.Printf("Filtered: %s\n", yesNo(exposure.Filtered))
+	}
+	if len(exposure.Allowed) > 0 {
+		fmt.Printf("\nAllowed over IPv6:\n  %s\n", strings.Join(exposure.Allowed, "\n  "))
+	}
+	if len(exposure.Unexpected) > 0 {
+		fmt.Printf("\nAllowed over IPv6 although the deny mode keeps them to IPv4:\n  %s\n",
+			strings.Join(exposure.Unexpected, "\n  "))
+	}
+
+	switch {
+	case exposure.Compliant():
+		fmt.Printf("\nIPv6 matches the %s mode\n", exposure.Mode)
+	case exposure.Exposed():
+		fmt.Println("\nThe firewall accepts all incoming IPv6 traffic")
+	default:
+		fmt.Printf("\nIPv6 does not match the %s mode\n", exposure.Mode)
+	}
+}
+
+// printFirewallDrift prints the declared rules the firewall lacks and the
+// live rules added outside hardn
+func printFirewallDrift(drift *model.FirewallDrift) {
+	if drift.InSync() {
+		fmt.Printf("Firewall matches the configuration (%d rules)\n", len(drift.Declared))
+		return
+	}
+	fmt.Printf("Enabled:      %s\n", yesNo(drift.Enabled))
+	fmt.Printf("Default deny: %s\n", yesNo(drift.DefaultDeny))
+	if len(drift.Missing) > 0 {
+		fmt.Printf("\nMissing rules:\n  %s\n", strings.Join(drift.Missing, "\n  "))
+	}
+	if len(drift.Extra) > 0 {
+		fmt.Printf("\nRules added outside hardn:\n  %s\n", strings.Join(drift.Extra, "\n  "))
+	}
+}
+
+// printDualStack prints whether each family is filtered and the rules
+// missing from one of them
+func printDualStack(report *model.DualStackReport) {
+	for _, stack := range []model.StackRules{report.IPv4, report.IPv6} {
+		state := "no addresses"
+		switch {
+		case stack.Active && stack.Filtered:
+			state = fmt.Sprintf("filtered, %d rules", len(stack.Allowed))
+		case stack.Active:
+			state = "not filtered"
+		}
+		fmt.Printf("%-5s %s\n", model.FamilyName(stack.Family)+":", state)
+	}
+
+	if !report.IPv4.Filtered && !report.IPv6.Filtered {
+		fmt.Println("\nThe firewall filters neither family")
+		return
+	}
+	if report.Synchronized() {
+		fmt.Println("\nIPv4 and IPv6 rules match")
+		return
+	}
+	if report.Unfiltered != "" {
+		fmt.Printf("\n%s accepts all incoming traffic\n", model.FamilyName(report.Unfiltered))
+	}
+	if len(report.Gaps) > 0 {
+		fmt.Println("\nMissing rules:")
+		for _, gap := range report.Gaps {
+			note := ""
+			if !gap.Mirrorable {
+				note = " (address-specific, review manually)"
+			}
+			fmt.Printf("  %s: allowed on %s, missing on %s%s\n", gap.Rule,
+				model.FamilyName(gap.AllowedOn), model.FamilyName(gap.MissingOn), note)
+		}
+	}
+}
+
+// yesNo formats a boolean for plain text output
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
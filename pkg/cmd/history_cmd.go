@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/security"
+	"github.com/abbott/hardn/pkg/style"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyLimit int
+	historyJSON  bool
+)
+
+// HistoryCmd returns the history command
+func HistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show the security posture over time",
+		Long: `Show the security posture recorded by earlier status checks, oldest
+first, and the controls that stopped passing since the check before the
+latest.
+
+A snapshot of the score, risk level and audit controls is kept in
+` + model.PostureHistoryPath + ` each time hardn status or hardn audit
+runs, and when the main menu opens. Only the latest ` + fmt.Sprint(model.MaxPostureSnapshots) + ` are kept.
+
+Examples:
+  hardn history
+  hardn history --limit 10
+  hardn history --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHistory()
+		},
+	}
+
+	cmd.Flags().IntVar(&historyLimit, "limit", 20, "Number of snapshots to show (0 for all)")
+	cmd.Flags().BoolVar(&historyJSON, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// newPostureHistoryManager wires the PostureHistoryManager for the status,
+// audit and history commands
+func newPostureHistoryManager() *application.PostureHistoryManager {
+	provider := interfaces.NewProvider()
+	historyRepo := secondary.NewFilePostureHistoryRepository(provider.FS, model.PostureHistoryPath)
+	return application.NewPostureHistoryManager(service.NewPostureHistoryServiceImpl(historyRepo))
+}
+
+// recordPosture adds the status to the posture history. Failures are
+// ignored: the history is written by root only, and a check run by another
+// user still reports the status.
+func recordPosture(status *security.SecurityStatus, source string) {
+	_, _ = newPostureHistoryManager().RecordSnapshot(security.BuildPostureSnapshot(status, time.Now(), source))
+}
+
+// runHistory executes the history command
+func runHistory() error {
+	if historyLimit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+
+	snapshots, err := newPostureHistoryManager().ListSnapshots(historyLimit)
+	if err != nil {
+		return err
+	}
+
+	if historyJSON {
+		if snapshots == nil {
+			snapshots = []model.PostureSnapshot{}
+		}
+		data, err := json.MarshalIndent(snapshots, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode posture history: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printPostureHistory(snapshots)
+	return nil
+}
+
+// printPostureHistory prints a row per snapshot and the changes between
+// the last two
+func printPostureHistory(snapshots []model.PostureSnapshot) {
+	if len(snapshots) == 0 {
+		fmt.Println("No security posture recorded yet; run hardn status or hardn audit as root.")
+		return
+	}
+
+	fmt.Printf("%-17s %-7s %-6s %-9s %s\n", "TIME", "SOURCE", "SCORE", "RISK", "CHANGE")
+	for i, snapshot := range snapshots {
+		change := ""
+		if i > 0 {
+			change = trendLabel(snapshot.Trend(snapshots[i-1]))
+		}
+		fmt.Printf("%-17s %-7s %-6s %-9s %s\n",
+			snapshot.Time.Local().Format("2006-01-02 15:04"),
+			snapshot.Source,
+			fmt.Sprintf("%d/%d", snapshot.Score, snapshot.MaxScore),
+			snapshot.RiskLevel,
+			change)
+	}
+
+	if len(snapshots) < 2 {
+		return
+	}
+	latest, previous := snapshots[len(snapshots)-1], snapshots[len(snapshots)-2]
+	regressions := latest.Regressions(previous)
+	improvements := latest.Improvements(previous)
+
+	fmt.Println()
+	if len(regressions) == 0 && len(improvements) == 0 {
+		fmt.Printf("No controls changed since %s.\n", previous.Time.Local().Format("2006-01-02 15:04"))
+		return
+	}
+	if len(regressions) > 0 {
+		fmt.Println(style.Colored(style.Red, fmt.Sprintf("Regressed since %s:", previous.Time.Local().Format("2006-01-02 15:04"))))
+		for _, id := range regressions {
+			fmt.Printf("  %s %s (%s)\n", style.Colored(style.Red, style.SymCrossMark), security.ControlTitle(id), id)
+		}
+	}
+	if len(improvements) > 0 {
+		fmt.Println(style.Colored(style.Green, fmt.Sprintf("Improved since %s:", previous.Time.Local().Format("2006-01-02 15:04"))))
+		for _, id := range improvements {
+			fmt.Printf("  %s %s (%s)\n", style.Colored(style.Green, style.SymCheckMark), security.ControlTitle(id), id)
+		}
+	}
+}
+
+// trendLabel shows the direction of the posture with an arrow
+func trendLabel(trend string) string {
+	switch trend {
+	case model.TrendImproved:
+		return style.Colored(style.Green, style.SymArrowUp+" improved")
+	case model.TrendRegressed:
+		return style.Colored(style.Red, style.SymArrowDown+" regressed")
+	}
+	return style.Dimmed(style.SymArrowRight + " steady")
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/style"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importOutput string
+	importForce  bool
+)
+
+// ImportCmd returns the import command
+func ImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Generate a hardn.yml from the hardening already on this host",
+		Long: `Read the configuration that shell scripts or other hardening tools left on
+this host and write the equivalent hardn configuration, so hardn can take
+over without undoing what is in place:
+
+  ufw        default policies, and TCP ports open to any address
+             (/etc/default/ufw, /etc/ufw/user.rules, /etc/ufw/user6.rules)
+  sshd       Port, PermitRootLogin, AllowUsers and ListenAddress, following
+             Include directives (/etc/ssh/sshd_config)
+  sudoers    the user granted every command, and whether without a password
+             (/etc/sudoers.d)
+  fail2ban   kept installed; its jails stay in /etc/fail2ban/jail.local
+
+Settings without a hardn equivalent, such as rules limited to addresses,
+group sudo rules or Match blocks, are listed so they can be carried over by
+hand. Settings that were not found keep their hardn defaults.
+
+Review the generated file and preview it with --dry-run before applying it.
+
+Examples:
+  sudo hardn import
+  sudo hardn import --output /etc/hardn/hardn.yml --force
+  sudo hardn -f hardn.yml --dry-run run-all`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImport()
+		},
+	}
+
+	cmd.Flags().StringVarP(&importOutput, "output", "o", "hardn.yml", "File to write the generated configuration to")
+	cmd.Flags().BoolVar(&importForce, "force", false, "Overwrite the output file if it exists")
+	return cmd
+}
+
+// runImport executes the import command
+func runImport() error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	if _, err := os.Stat(importOutput); err == nil && !importForce {
+		return fmt.Errorf("%s already exists; use --force to overwrite it", importOutput)
+	}
+
+	result, err := newMigrationManager().ImportHost()
+	if err != nil {
+		return err
+	}
+	if !result.Found() {
+		return fmt.Errorf("no ufw rules, sshd_config, sudoers.d entries or fail2ban jails found to import")
+	}
+	printMigration(result)
+
+	osType := ""
+	if osInfo, err := osdetect.DetectOS(); err == nil {
+		osType = osInfo.OsType
+	}
+	cfg := config.DefaultConfig()
+	applyMigration(cfg, result, osType)
+
+	if err := config.SaveConfig(cfg, importOutput); err != nil {
+		return err
+	}
+	logging.LogSuccess("Imported the existing hardening into %s", importOutput)
+
+	fmt.Printf("\n%s Configuration written to %s\n", style.Colored(style.Green, style.SymCheckMark), importOutput)
+	fmt.Printf("%s Review it, then preview the changes with: hardn -f %s --dry-run run-all\n",
+		style.BulletItem, importOutput)
+	return nil
+}
+
+// newMigrationManager wires the migration manager for the import command
+func newMigrationManager() *application.MigrationManager {
+	provider := interfaces.NewProvider()
+	migrationRepo := secondary.NewFileMigrationRepository(provider.FS, provider.Commander)
+	migrationService := service.NewMigrationServiceImpl(migrationRepo)
+	return application.NewMigrationManager(migrationService)
+}
+
+// applyMigration overrides the defaults of cfg with the imported settings
+func applyMigration(cfg *config.Config, result *model.MigrationResult, osType string) {
+	sshdFound, ufwFound := false, false
+	for _, artifact := range result.Artifacts {
+		sshdFound = sshdFound || artifact.Source == model.MigrationSourceSshd
+		ufwFound = ufwFound || artifact.Source == model.MigrationSourceUfw
+	}
+
+	if result.SshPort != 0 {
+		cfg.SshPort = result.SshPort
+	}
+	if result.PermitRootLogin != nil {
+		cfg.PermitRootLogin = *result.PermitRootLogin
+		cfg.DisableRootSSH = !*result.PermitRootLogin
+	}
+	if len(result.SshAllowedUsers) > 0 {
+		cfg.SshAllowedUsers = result.SshAllowedUsers
+	} else if sshdFound {
+		// Without AllowUsers every user may log in today
+		cfg.SshAllowAllUsers = true
+	}
+	if len(result.SshListenAddresses) > 0 {
+		cfg.SshListenAddresses = result.SshListenAddresses
+	}
+
+	cfg.Username = result.Username
+	if result.SudoNoPassword != nil {
+		cfg.SudoNoPassword = *result.SudoNoPassword
+	}
+
+	if ufwFound {
+		cfg.EnableUfwSshPolicy = true
+		cfg.UfwDefaultIncomingPolicy = result.UfwDefaultIncomingPolicy
+		cfg.UfwDefaultOutgoingPolicy = result.UfwDefaultOutgoingPolicy
+		cfg.UfwAllowedPorts = result.UfwAllowedPorts
+	}
+
+	switch {
+	case osType == "alpine":
+		cfg.AlpineCorePackages = append(cfg.AlpineCorePackages, result.Packages...)
+	case model.IsRHELFamily(osType):
+		cfg.RhelCorePackages = append(cfg.RhelCorePackages, result.Packages...)
+	default:
+		cfg.LinuxCorePackages = append(cfg.LinuxCorePackages, result.Packages...)
+	}
+}
+
+// printMigration lists what each artifact contributed and what it could not
+func printMigration(result *model.MigrationResult) {
+	for _, artifact := range result.Artifacts {
+		fmt.Printf("%s %-9s %s\n", style.Bolded(style.SymInfo, style.Blue), artifact.Source, style.Dimmed(artifact.Path))
+		for _, setting := range artifact.Imported {
+			fmt.Printf("    %s %s\n", style.Colored(style.Green, style.SymCheckMark), setting)
+		}
+		for _, setting := range artifact.Skipped {
+			fmt.Printf("    %s %s\n", style.Colored(style.Yellow, style.SymWarning), setting)
+		}
+	}
+}
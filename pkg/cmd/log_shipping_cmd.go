@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logShippingJSON      bool
+	logShippingSkipCheck bool
+)
+
+// LogShippingCmd returns the log-shipping command
+func LogShippingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "log-shipping",
+		Short: "Forward logs to a remote collector over TLS",
+		Long: `Manage the log forwarding of the logShipping section of the
+configuration. Logs kept only on the host can be erased by whoever
+compromises it; a copy on a collector survives.`,
+	}
+
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Forward logs to the configured collector",
+		Long: `Apply the logShipping section of the configuration:
+
+  method    rsyslog forwards syslog messages over TLS (RFC 5425);
+            journald uploads the journal with systemd-journal-upload
+  host      the collector; its certificate must carry this name
+  port      0 picks 6514 for rsyslog and 19532 for journald
+  caFile    the CA that signed the collector's certificate
+  certFile  the client certificate presented to the collector
+  keyFile   its key; both are required for journald
+
+The certificates are checked and copied to ` + model.LogShippingCertDir + `, and a TLS
+connection to the collector is tried before anything is changed.
+
+Examples:
+  sudo hardn log-shipping apply --dry-run
+  sudo hardn log-shipping apply
+  sudo hardn log-shipping apply --skip-check`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLogShippingApply(cmd)
+		},
+	}
+	applyCmd.Flags().BoolVar(&logShippingSkipCheck, "skip-check", false,
+		"Configure the forwarding even if the collector can not be reached")
+
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check the connection to the collector",
+		Long: `Complete a TLS handshake with the configured collector using the
+configured certificates, without changing anything.
+
+Examples:
+  hardn log-shipping check`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLogShippingCheck(cmd)
+		},
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show whether logs are being shipped off the host",
+		Long: `Show the configured forwarding, whether its daemon runs and whether it is
+connected to the collector.
+
+Examples:
+  hardn log-shipping status
+  hardn log-shipping status --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLogShippingStatus(cmd)
+		},
+	}
+	statusCmd.Flags().BoolVar(&logShippingJSON, "json", false, "Output in JSON format")
+
+	cmd.AddCommand(applyCmd)
+	cmd.AddCommand(checkCmd)
+	cmd.AddCommand(statusCmd)
+	return cmd
+}
+
+// runLogShippingApply executes the log-shipping apply command
+func runLogShippingApply(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireSupportedOS("log shipping"); err != nil {
+		return err
+	}
+
+	manager := ctx.serviceFactory().CreateLogShippingManager()
+	policy, err := manager.EffectivePolicy(ctx.cfg.LogShipping.Policy())
+	if err != nil {
+		return err
+	}
+	if !logShippingSkipCheck {
+		if err := manager.CheckConnection(policy); err != nil {
+			return fmt.Errorf("%w (use --skip-check to configure the forwarding anyway)", err)
+		}
+	}
+	if err := manager.ApplyLogShipping(policy); err != nil {
+		return fmt.Errorf("failed to set up log shipping: %w", err)
+	}
+	if ctx.dryRun {
+		return nil
+	}
+
+	logging.LogSuccess("Logs are forwarded to %s with %s", policy.Target(), policy.Method)
+	return nil
+}
+
+// runLogShippingCheck executes the log-shipping check command
+func runLogShippingCheck(cmd *cobra.Command) error {
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	manager := ctx.serviceFactory().CreateLogShippingManager()
+	policy, err := manager.EffectivePolicy(ctx.cfg.LogShipping.Policy())
+	if err != nil {
+		return err
+	}
+	if err := manager.CheckConnection(policy); err != nil {
+		return err
+	}
+
+	logging.LogSuccess("Connected to %s over TLS", policy.Target())
+	return nil
+}
+
+// runLogShippingStatus executes the log-shipping status command
+func runLogShippingStatus(cmd *cobra.Command) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	status, err := ctx.serviceFactory().CreateLogShippingManager().GetLogShippingStatus()
+	if err != nil {
+		return err
+	}
+
+	if logShippingJSON {
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode log shipping status: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printLogShippingStatus(status)
+	return nil
+}
+
+// printLogShippingStatus prints the forwarding and its connection
+func printLogShippingStatus(status *model.LogShippingStatus) {
+	if !status.Configured() {
+		fmt.Println("Logs are not shipped off the host.")
+		return
+	}
+
+	yesNo := func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "no"
+	}
+
+	fmt.Printf("%-14s %s\n", "Method", status.Method)
+	fmt.Printf("%-14s %s\n", "Collector", status.Target)
+	fmt.Printf("%-14s %s\n", "Running", yesNo(status.Active))
+	fmt.Printf("%-14s %s\n", "Connected", yesNo(status.Connected))
+}
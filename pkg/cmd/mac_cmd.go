@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	macFramework string
+	macJSON      bool
+)
+
+// MACCmd returns the mac command
+func MACCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mac",
+		Short: "Manage mandatory access control (AppArmor or SELinux)",
+		Long: `Show and enforce the host's mandatory access control framework. Debian,
+Ubuntu and Alpine use AppArmor by default; RHEL-family hosts use SELinux.
+Only one framework can be enforced at a time.`,
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the active framework and its mode",
+		Long: `Show the active framework, its current mode and, for SELinux, the mode
+and policy configured for the next boot.
+
+Examples:
+  hardn mac status
+  hardn mac status --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMACStatus(cmd)
+		},
+	}
+	statusCmd.Flags().BoolVar(&macJSON, "json", false, "Output in JSON format")
+
+	enforceCmd := &cobra.Command{
+		Use:   "enforce",
+		Short: "Install a framework and set it to enforcing",
+		Long: `Install the framework's tools and policy packages and put it in
+enforcing mode. Without --framework the active framework is used, or the
+distribution's default. Under SELinux a non-default SSH port is labeled
+ssh_port_t first so sshd can still bind it.
+
+Examples:
+  sudo hardn mac enforce
+  sudo hardn mac enforce --framework selinux --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMACEnforce(cmd)
+		},
+	}
+	enforceCmd.Flags().StringVar(&macFramework, "framework", "", "Framework to enforce (apparmor or selinux)")
+
+	cmd.AddCommand(statusCmd)
+	cmd.AddCommand(enforceCmd)
+	return cmd
+}
+
+// runMACStatus executes the mac status command
+func runMACStatus(cmd *cobra.Command) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	status, err := ctx.serviceFactory().CreateMACManager().GetStatus()
+	if err != nil {
+		return fmt.Errorf("failed to check MAC status: %w", err)
+	}
+
+	if macJSON {
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode MAC status: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("MAC: %s\n", status.Describe())
+	if status.RebootRequired {
+		fmt.Println("Reboot required for the configured mode to take effect")
+	}
+	return nil
+}
+
+// runMACEnforce executes the mac enforce command
+func runMACEnforce(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	// SELinux is managed on RHEL-family hosts in degraded mode
+	if macFramework != model.MACSELinux {
+		if err := ctx.requireSupportedOS("AppArmor"); err != nil {
+			return err
+		}
+		if err := ctx.requireOutsideWSL("AppArmor"); err != nil {
+			return err
+		}
+	}
+
+	if ctx.dryRun {
+		framework := model.FrameworkName(macFramework)
+		if framework == "" {
+			framework = "the active or default framework"
+		}
+		fmt.Printf("[DRY-RUN] Would install and enforce %s\n", framework)
+		if macFramework == model.MACSELinux && ctx.cfg.SshPort != 22 {
+			fmt.Printf("[DRY-RUN] Would label port %d as ssh_port_t\n", ctx.cfg.SshPort)
+		}
+		return nil
+	}
+
+	status, err := ctx.serviceFactory().CreateMACManager().Enforce(macFramework, ctx.cfg.SshPort)
+	if err != nil {
+		return fmt.Errorf("failed to enforce MAC: %w", err)
+	}
+
+	logging.LogSuccess("MAC enforced: %s", status.Describe())
+	fmt.Printf("MAC: %s\n", status.Describe())
+	if status.RebootRequired {
+		fmt.Println("Reboot to finish: SELinux starts enforcing after the filesystem is relabeled")
+	}
+	return nil
+}
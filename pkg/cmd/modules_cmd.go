@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var modulesJSON bool
+
+// ModulesCmd returns the modules command
+func ModulesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "modules",
+		Short: "Keep uncommon filesystems and USB storage from loading",
+		Long: `Manage the kernel modules blacklist in ` + model.ModuleBlacklistPath + `.
+The modules come from the moduleBlacklist section of the configuration:
+uncommon filesystems by default, and USB mass storage with usbStorage.`,
+	}
+
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Write the module blacklist",
+		Long: `Write an install rule and a blacklist rule for each configured module, so
+neither a device nor an explicit modprobe loads it. squashfs is left out on
+hosts with snapd, whose snaps are squashfs images. Modules already loaded
+stay loaded until they are removed with rmmod or the host reboots.
+
+With --dry-run the change to the file is shown as a diff.
+
+Examples:
+  sudo hardn modules apply --dry-run
+  sudo hardn modules apply`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runModulesApply(cmd)
+		},
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show which configured modules are blocked or loaded",
+		Long: `Compare the configured modules with the modprobe configuration of all
+modprobe.d files and with the modules loaded now.
+
+Examples:
+  hardn modules status
+  hardn modules status --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runModulesStatus(cmd)
+		},
+	}
+	statusCmd.Flags().BoolVar(&modulesJSON, "json", false, "Output in JSON format")
+
+	cmd.AddCommand(applyCmd)
+	cmd.AddCommand(statusCmd)
+	return cmd
+}
+
+// runModulesApply executes the modules apply command
+func runModulesApply(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireOutsideWSL("kernel module blacklist"); err != nil {
+		return err
+	}
+	manager := ctx.serviceFactory().CreateModuleBlacklistManager()
+	policy := ctx.cfg.ModuleBlacklist.Policy()
+
+	if err := manager.ApplyBlacklist(policy); err != nil {
+		return fmt.Errorf("failed to write the module blacklist: %w", err)
+	}
+	if ctx.dryRun {
+		return nil
+	}
+
+	logging.LogSuccess("Blocked kernel modules: %s", strings.Join(manager.EffectiveModules(policy), ", "))
+	if status, err := manager.GetBlacklistStatus(policy); err == nil && len(status.Loaded) > 0 {
+		logging.LogWarning("Still loaded until removed or rebooted: %s", strings.Join(status.Loaded, ", "))
+	}
+	return nil
+}
+
+// runModulesStatus executes the modules status command
+func runModulesStatus(cmd *cobra.Command) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	status, err := ctx.serviceFactory().CreateModuleBlacklistManager().
+		GetBlacklistStatus(ctx.cfg.ModuleBlacklist.Policy())
+	if err != nil {
+		return err
+	}
+
+	if modulesJSON {
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode module status: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printModulesStatus(status)
+	return nil
+}
+
+// printModulesStatus prints the blocked, unblocked and loaded modules
+func printModulesStatus(status *model.ModuleBlacklistStatus) {
+	list := func(modules []string) string {
+		if len(modules) == 0 {
+			return "none"
+		}
+		return strings.Join(modules, ", ")
+	}
+
+	fmt.Printf("%-12s %s\n", "Blocked", list(status.Disabled))
+	fmt.Printf("%-12s %s\n", "Not blocked", list(status.Missing))
+	fmt.Printf("%-12s %s\n", "Loaded", list(status.Loaded))
+}
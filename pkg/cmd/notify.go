@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/security"
+)
+
+// newNotificationManager wires the notification manager for the configured
+// email address and webhooks
+func newNotificationManager(cfg *config.Config) *application.NotificationManager {
+	settings := cfg.Notifications.Settings()
+
+	var notifiers []service.Notifier
+	if settings.Email.Configured() {
+		notifiers = append(notifiers, secondary.NewSMTPNotifier(settings.Email))
+	}
+	for _, url := range settings.Webhooks {
+		notifiers = append(notifiers, secondary.NewWebhookNotifier(url))
+	}
+	return application.NewNotificationManager(service.NewNotificationServiceImpl(notifiers, settings.OnlyOnFailure))
+}
+
+// BeginRunSummary starts the summary of a hardening run, recording the
+// risk level before it changes anything
+func BeginRunSummary(operation string, cfg *config.Config, osInfo *osdetect.OSInfo) model.RunSummary {
+	hostname, _ := os.Hostname()
+	return model.RunSummary{
+		Hostname:   hostname,
+		Operation:  operation,
+		StartedAt:  time.Now(),
+		RiskBefore: riskLevel(cfg, osInfo),
+	}
+}
+
+// SendRunSummary completes the summary with the risk level the run left
+// and sends it; failures to send are reported but do not fail the run
+func SendRunSummary(manager *application.NotificationManager, summary model.RunSummary, cfg *config.Config, osInfo *osdetect.OSInfo) {
+	summary.FinishedAt = time.Now()
+	summary.RiskAfter = riskLevel(cfg, osInfo)
+	sendSummary(manager, summary)
+}
+
+// sendSummary sends a completed summary
+func sendSummary(manager *application.NotificationManager, summary model.RunSummary) {
+	if err := manager.Notify(summary); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to send notification: %v\n", err)
+	}
+}
+
+// riskLevel checks the host and returns its overall risk level
+func riskLevel(cfg *config.Config, osInfo *osdetect.OSInfo) string {
+	status, err := security.CheckSecurityStatus(cfg, osInfo)
+	if err != nil {
+		return "Unknown"
+	}
+	level, _, _ := security.GetSecurityRiskLevel(status)
+	return level
+}
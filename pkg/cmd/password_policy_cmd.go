@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var passwordPolicyJSON bool
+
+// PasswordPolicyCmd returns the password-policy command
+func PasswordPolicyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "password-policy",
+		Short: "Manage password strength, aging and account lockout",
+		Long: `Enforce the password policy of the passwordPolicy section of the
+configuration: the strength pam_pwquality requires of new passwords, the
+aging of new accounts in /etc/login.defs and the lockout of accounts by
+pam_faillock after failed logins.`,
+	}
+
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply the password policy",
+		Long: `Install pam_pwquality and write /etc/security/pwquality.conf, set
+PASS_MAX_DAYS, PASS_MIN_DAYS and PASS_WARN_AGE in /etc/login.defs, and add
+pam_faillock to the PAM stack: with pam-auth-update on Debian and Ubuntu,
+with authselect on Rocky Linux, AlmaLinux and Fedora. The aging only applies
+to accounts created afterwards; use chage for existing ones.
+
+Alpine's busybox login does not use PAM, so there only the aging is written.
+
+Examples:
+  sudo hardn password-policy apply --dry-run
+  sudo hardn password-policy apply`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPasswordPolicyApply(cmd)
+		},
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the password policy in effect",
+		Long: `Show whether pam_pwquality checks new passwords and with which minimum
+length, the aging of new accounts and whether pam_faillock locks accounts.
+
+Examples:
+  hardn password-policy status
+  hardn password-policy status --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPasswordPolicyStatus(cmd)
+		},
+	}
+	statusCmd.Flags().BoolVar(&passwordPolicyJSON, "json", false, "Output in JSON format")
+
+	cmd.AddCommand(applyCmd)
+	cmd.AddCommand(statusCmd)
+	return cmd
+}
+
+// runPasswordPolicyApply executes the password-policy apply command
+func runPasswordPolicyApply(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	manager := ctx.serviceFactory().CreatePasswordPolicyManager()
+
+	if !manager.PamSupported() {
+		logging.LogWarning("busybox login does not use PAM; only the password aging is applied")
+	}
+	if err := manager.ConfigurePasswordPolicy(ctx.cfg.PasswordPolicy.Policy()); err != nil {
+		return fmt.Errorf("failed to apply the password policy: %w", err)
+	}
+
+	if !ctx.dryRun {
+		logging.LogSuccess("Password policy applied")
+	}
+	return nil
+}
+
+// runPasswordPolicyStatus executes the password-policy status command
+func runPasswordPolicyStatus(cmd *cobra.Command) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	status, err := ctx.serviceFactory().CreatePasswordPolicyManager().GetPasswordPolicyStatus()
+	if err != nil {
+		return err
+	}
+
+	if passwordPolicyJSON {
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode password policy status: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printPasswordPolicyStatus(status)
+	return nil
+}
+
+// printPasswordPolicyStatus prints the password policy in effect
+func printPasswordPolicyStatus(status *model.PasswordPolicyStatus) {
+	days := func(value int) string {
+		if value == 0 {
+			return "not set"
+		}
+		return fmt.Sprintf("%d days", value)
+	}
+
+	if status.Complexity {
+		fmt.Printf("%-16s pam_pwquality, minimum length %d\n", "Complexity", status.MinLength)
+	} else {
+		fmt.Printf("%-16s not checked\n", "Complexity")
+	}
+	fmt.Printf("%-16s %s\n", "Maximum age", days(status.MaxDays))
+	fmt.Printf("%-16s %s\n", "Minimum age", days(status.MinDays))
+	fmt.Printf("%-16s %s\n", "Expiry warning", days(status.WarnAge))
+	if status.Lockout {
+		fmt.Printf("%-16s pam_faillock, after %d failed logins\n", "Lockout", status.LockoutAttempts)
+	} else {
+		fmt.Printf("%-16s off\n", "Lockout")
+	}
+
+	if weaknesses := status.Weaknesses(); len(weaknesses) > 0 {
+		fmt.Printf("%-16s %s\n", "Weaknesses", strings.Join(weaknesses, ", "))
+	}
+}
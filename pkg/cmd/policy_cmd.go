@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/security"
+	"github.com/spf13/cobra"
+)
+
+var (
+	policyDir    string
+	policyFormat string
+	factsOutput  string
+)
+
+// PolicyCmd returns the policy command
+func PolicyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Evaluate custom compliance policies",
+	}
+
+	evalCmd := &cobra.Command{
+		Use:   "eval",
+		Short: "Evaluate rego policies against the host facts",
+		Long: `Collect the hardn facts document for this host and evaluate the rego
+policies in a directory against it using Open Policy Agent (opa must be
+installed). Policies report violations through the data.hardn.deny rule:
+
+  package hardn
+
+  deny contains msg if {
+      input.ssh.port == 22
+      msg := "SSH listens on the default port"
+  }
+
+A rule may also produce objects with msg, rule and severity fields.
+The command exits with status 1 when any violation is reported.
+
+Examples:
+  sudo hardn policy eval --policy /etc/hardn/policies
+  sudo hardn policy eval --policy ./policies --format json
+  sudo hardn policy eval --policy ./policies --facts facts.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configFile := ""
+			if flag := cmd.Flag("config"); flag != nil {
+				configFile = flag.Value.String()
+			}
+			return runPolicyEval(configFile)
+		},
+	}
+
+	evalCmd.Flags().StringVarP(&policyDir, "policy", "P", "", "Directory containing rego policies")
+	evalCmd.Flags().StringVarP(&policyFormat, "format", "O", "text", "Output format (text, json)")
+	evalCmd.Flags().StringVar(&factsOutput, "facts", "", "Also write the facts document to this file")
+	_ = evalCmd.MarkFlagRequired("policy")
+
+	cmd.AddCommand(evalCmd)
+	return cmd
+}
+
+// runPolicyEval executes the policy eval command
+func runPolicyEval(configFile string) error {
+	if policyFormat != "text" && policyFormat != "json" {
+		return fmt.Errorf("unsupported format: %s (use text or json)", policyFormat)
+	}
+
+	// Keep informational logs out of the report
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	_, facts, err := collectHostFacts(configFile)
+	if err != nil {
+		return err
+	}
+
+	if factsOutput != "" {
+		data, err := json.MarshalIndent(facts, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode facts: %w", err)
+		}
+		if err := os.WriteFile(factsOutput, append(data, '\n'), 0600); err != nil {
+			return fmt.Errorf("failed to write facts to %s: %w", factsOutput, err)
+		}
+	}
+
+	report, err := evaluatePolicies(policyDir, facts)
+	if err != nil {
+		return err
+	}
+
+	if policyFormat == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		printPolicyReport(report)
+	}
+
+	if len(report.Violations) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// collectHostFacts checks the host's security status and builds the facts
+// document from it
+func collectHostFacts(configFile string) (*security.SecurityStatus, *model.Facts, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	cfg.ApplyLogging()
+
+	return hostFacts(cfg)
+}
+
+// hostFacts collects the security status and facts with a loaded
+// configuration
+func hostFacts(cfg *config.Config) (*security.SecurityStatus, *model.Facts, error) {
+	osInfo, err := osdetect.DetectOS()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to detect OS: %w", err)
+	}
+
+	status, err := security.CheckSecurityStatus(cfg, osInfo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to collect security status: %w", err)
+	}
+
+	return status, security.BuildFacts(cfg, osInfo, status), nil
+}
+
+// evaluatePolicies evaluates the rego policies in dir against the facts
+func evaluatePolicies(dir string, facts *model.Facts) (*model.PolicyReport, error) {
+	provider := interfaces.NewProvider()
+	policyRepo := secondary.NewOPAPolicyRepository(provider.FS, provider.Commander)
+	policyService := service.NewPolicyServiceImpl(policyRepo)
+	policyManager := application.NewPolicyManager(policyService)
+
+	return policyManager.Evaluate(dir, facts)
+}
+
+// printPolicyReport prints violations as plain text
+func printPolicyReport(report *model.PolicyReport) {
+	fmt.Printf("Policies: %s\n", report.PolicyDir)
+	fmt.Printf("Host:     %s (%s %s)\n", report.Facts.Hostname, report.Facts.OS.Type, report.Facts.OS.Version)
+
+	if len(report.Violations) == 0 {
+		fmt.Println("\nNo policy violations found")
+		return
+	}
+
+	fmt.Printf("\n%d policy violation(s):\n", len(report.Violations))
+	for _, violation := range report.Violations {
+		prefix := ""
+		if violation.Severity != "" {
+			prefix = "[" + violation.Severity + "] "
+		}
+		if violation.Rule != "" {
+			prefix += violation.Rule + ": "
+		}
+		fmt.Printf("  - %s%s\n", prefix, violation.Message)
+	}
+}
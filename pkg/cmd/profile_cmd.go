@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/style"
+	"github.com/spf13/cobra"
+)
+
+var (
+	profileName             string
+	profileSignKey          string
+	profileSysctlFiles      []string
+	profileRequireSignature bool
+	profileAssumeYes        bool
+)
+
+// ProfileCmd returns the profile command
+func ProfileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Share one hardening profile across a fleet",
+		Long: `Export this host's hardening profile to a YAML bundle, and import a bundle
+made on another host. A bundle holds:
+
+  config   hardn's configuration, with the SSH hardening profile, the UFW
+           application profiles and the package lists
+  sysctl   the .conf files of ` + model.SysctlDropInDir + `
+
+Bundles can be signed with GnuPG; the signature is kept next to the
+bundle with the .asc extension.`,
+	}
+
+	exportCmd := &cobra.Command{
+		Use:   "export [FILE]",
+		Short: "Write this host's hardening profile to a bundle",
+		Long: `Write hardn's configuration and the sysctl drop-ins to a bundle readable by
+root only, as it may hold notification credentials. The bundle is named
+hardn-profile-HOSTNAME.yml unless a file is given.
+
+With --sign the bundle is signed with a secret key of root's keyring.
+--sysctl picks the sysctl files to include instead of every regular .conf
+file of ` + model.SysctlDropInDir + `; --sysctl "" includes none.
+
+Examples:
+  sudo hardn profile export
+  sudo hardn profile export web.yml --name web --sign ops@example.com
+  sudo hardn profile export --sysctl /etc/sysctl.d/60-hardening.conf`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output := ""
+			if len(args) == 1 {
+				output = args[0]
+			}
+			return runProfileExport(cmd, output)
+		},
+	}
+	exportCmd.Flags().StringVar(&profileName, "name", "", "Name of the profile")
+	exportCmd.Flags().StringVar(&profileSignKey, "sign", "", "Sign the bundle with this GnuPG key")
+	exportCmd.Flags().StringSliceVar(&profileSysctlFiles, "sysctl", nil, "sysctl files to include (default: every .conf file of "+model.SysctlDropInDir+")")
+
+	importCmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Install a hardening profile bundle on this host",
+		Long: `Check a bundle, show how it changes hardn's configuration and the sysctl
+drop-ins, and write them after confirmation. The bundle's configuration
+must parse without unknown keys, and its sysctl files may only hold
+settings of ` + model.SysctlDropInDir + `. A signature next to the bundle is
+verified with the public keys of root's keyring and must be made by a
+key listed in profileSigners; --require-signature refuses unsigned
+bundles.
+
+The sysctl settings are applied at once. The configuration takes effect
+with the next hardening run.
+
+Examples:
+  sudo hardn profile import web.yml --dry-run
+  sudo hardn profile import web.yml --require-signature
+  sudo hardn profile import web.yml --yes && sudo hardn run-all`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProfileImport(cmd, args[0])
+		},
+	}
+	importCmd.Flags().BoolVar(&profileRequireSignature, "require-signature", false, "Refuse bundles without a valid signature")
+	importCmd.Flags().BoolVar(&profileAssumeYes, "yes", false, "Do not ask for confirmation")
+
+	cmd.AddCommand(exportCmd)
+	cmd.AddCommand(importCmd)
+	return cmd
+}
+
+// runProfileExport executes the profile export command
+func runProfileExport(cmd *cobra.Command, output string) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	configPath := config.AbsConfigFile(ctx.configFile)
+	if configPath == "" {
+		return fmt.Errorf("no hardn configuration file to export")
+	}
+
+	hostname, _ := os.Hostname()
+	if output == "" {
+		output = fmt.Sprintf("hardn-profile-%s.yml", hostname)
+	}
+
+	request := model.ProfileExportRequest{
+		Name:         profileName,
+		Hostname:     hostname,
+		HardnVersion: hardnVersion,
+		ConfigFile:   configPath,
+		Output:       output,
+		SignKey:      profileSignKey,
+	}
+	if cmd.Flags().Changed("sysctl") {
+		request.SysctlFiles = []string{}
+		for _, path := range profileSysctlFiles {
+			if path != "" {
+				request.SysctlFiles = append(request.SysctlFiles, path)
+			}
+		}
+	}
+
+	export, err := ctx.serviceFactory().CreateProfileBundleManager().ExportBundle(request)
+	if err != nil {
+		return err
+	}
+	logging.LogSuccess("Profile bundle written to %s", export.Path)
+
+	fmt.Printf("Profile bundle written to %s (configuration from %s, %d sysctl files)\n",
+		export.Path, configPath, len(export.Bundle.Sysctl))
+	if export.SignaturePath != "" {
+		fmt.Printf("Signature written to %s\n", export.SignaturePath)
+	}
+	return nil
+}
+
+// runProfileImport executes the profile import command
+func runProfileImport(cmd *cobra.Command, path string) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	manager := ctx.serviceFactory().CreateProfileBundleManager()
+
+	loaded, err := manager.LoadBundle(path, profileRequireSignature, ctx.cfg.ProfileSigners)
+	if err != nil {
+		return err
+	}
+	if err := validateProfileConfig(loaded.Bundle.Config); err != nil {
+		return fmt.Errorf("%s holds an invalid configuration: %w", path, err)
+	}
+	printProfileBundle(loaded)
+
+	configPath := config.AbsConfigFile(ctx.configFile)
+	if configPath == "" {
+		configPath = ctx.configFile
+	}
+	if configPath == "" {
+		configPath = config.GetDefaultConfigLocation()
+	}
+
+	changes, err := manager.PlanImport(loaded.Bundle, configPath)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Println("This host already matches the profile")
+		return nil
+	}
+	for _, change := range changes {
+		fmt.Print(style.UnifiedDiff(change.Path, change.Before, change.After))
+	}
+
+	if ctx.dryRun {
+		fmt.Printf("[DRY-RUN] Would write %d files\n", len(changes))
+		return nil
+	}
+	if !profileAssumeYes {
+		fmt.Printf("Write %d files? [y/N]: ", len(changes))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer = strings.TrimSpace(strings.ToLower(answer)); answer != "y" && answer != "yes" {
+			fmt.Println("Import cancelled.")
+			return nil
+		}
+	}
+
+	if err := manager.ApplyImport(changes); err != nil {
+		return fmt.Errorf("failed to import the profile: %w", err)
+	}
+	logging.LogSuccess("Imported profile bundle %s into %s", path, configPath)
+	fmt.Printf("Profile imported; apply the configuration with: sudo hardn -f %s run-all\n", configPath)
+	return nil
+}
+
+// validateProfileConfig checks the configuration of a bundle before it
+// replaces this host's
+func validateProfileConfig(data string) error {
+	cfg, err := config.ParseConfig([]byte(data))
+	if err != nil {
+		return err
+	}
+	if cfg.SshPort < 1 || cfg.SshPort > 65535 {
+		return fmt.Errorf("sshPort %d is not a port", cfg.SshPort)
+	}
+	if cfg.SshProfile != "" {
+		if _, ok := model.LookupSSHProfile(cfg.SshProfile); !ok {
+			return fmt.Errorf("unknown sshProfile %q", cfg.SshProfile)
+		}
+	}
+	for _, profile := range cfg.UfwAppProfiles {
+		if profile.Name == "" || len(profile.Ports) == 0 {
+			return fmt.Errorf("UFW application profile %q needs a name and ports", profile.Name)
+		}
+	}
+	return nil
+}
+
+// printProfileBundle describes where a bundle comes from
+func printProfileBundle(loaded *model.ProfileImport) {
+	bundle := loaded.Bundle
+	name := bundle.Name
+	if name == "" {
+		name = "unnamed profile"
+	}
+	origin := bundle.CreatedAt.Format("2006-01-02 15:04 MST")
+	if bundle.Hostname != "" {
+		origin = bundle.Hostname + ", " + origin
+	}
+	if bundle.HardnVersion != "" {
+		origin += ", hardn " + bundle.HardnVersion
+	}
+	fmt.Printf("%s %s (%s)\n", style.Bolded(style.SymInfo, style.Blue), name, origin)
+	if loaded.Signed {
+		fmt.Printf("%s Signature verified (key %s)\n", style.Colored(style.Green, style.SymCheckMark), loaded.Signer)
+	} else {
+		fmt.Printf("%s Unsigned bundle\n", style.Colored(style.Yellow, style.SymWarning))
+	}
+	fmt.Println()
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var proxmoxJSON bool
+
+// ProxmoxCmd returns the proxmox command
+func ProxmoxCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proxmox",
+		Short: "Harden the web UI and realm of a Proxmox VE host",
+		Long: `Manage the Proxmox VE settings of the proxmox section of the configuration:
+the subscription dialog, the sources allowed to the web UI, the TLS ciphers
+of pveproxy and two-factor authentication for the pve realm.`,
+	}
+
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply the configured Proxmox VE settings",
+		Long: `Apply the proxmox section of the configuration:
+
+  disableSubscriptionNag  patch the "No valid subscription" dialog out of the
+                          web UI and keep it out after upgrades with an apt hook
+  webUIAllowlist          allow port ` + fmt.Sprint(model.ProxmoxWebUIPort) + ` from these sources only, in the
+                          firewall and in ` + model.PveproxyDefaultsPath + `
+  hardenCiphers           limit pveproxy to ECDHE key exchange and AEAD ciphers
+  realmTwoFactor          require TOTP for users of the pve realm
+
+Users of the pve realm without a TOTP secret cannot log in once
+realmTwoFactor is applied; enroll them first.
+
+Examples:
+  sudo hardn proxmox apply --dry-run
+  sudo hardn proxmox apply`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProxmoxApply(cmd)
+		},
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the Proxmox VE hardening in effect",
+		Long: `Show whether the subscription dialog is patched out, which sources
+pveproxy allows, whether its ciphers are hardened and the second factor the
+pve realm requires.
+
+Examples:
+  sudo hardn proxmox status
+  sudo hardn proxmox status --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProxmoxStatus(cmd)
+		},
+	}
+	statusCmd.Flags().BoolVar(&proxmoxJSON, "json", false, "Output in JSON format")
+
+	cmd.AddCommand(applyCmd)
+	cmd.AddCommand(statusCmd)
+	return cmd
+}
+
+// runProxmoxApply executes the proxmox apply command
+func runProxmoxApply(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireProxmox(); err != nil {
+		return err
+	}
+
+	policy := ctx.cfg.Proxmox.Policy()
+	if err := ctx.serviceFactory().CreateProxmoxManager().ApplyHardening(policy); err != nil {
+		return fmt.Errorf("failed to apply the Proxmox VE settings: %w", err)
+	}
+	if ctx.dryRun {
+		return nil
+	}
+
+	if policy.DisableSubscriptionNag {
+		logging.LogSuccess("Subscription dialog disabled")
+	}
+	if len(policy.WebUIAllowlist) > 0 {
+		logging.LogSuccess("Web UI allowed from %s only", strings.Join(policy.WebUIAllowlist, ", "))
+	}
+	if policy.HardenCiphers {
+		logging.LogSuccess("pveproxy ciphers hardened")
+	}
+	if policy.RealmTwoFactor {
+		logging.LogSuccess("TOTP required for the %s realm", model.ProxmoxRealm)
+	}
+	return nil
+}
+
+// runProxmoxStatus executes the proxmox status command
+func runProxmoxStatus(cmd *cobra.Command) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireProxmox(); err != nil {
+		return err
+	}
+
+	status, err := ctx.serviceFactory().CreateProxmoxManager().GetStatus()
+	if err != nil {
+		return err
+	}
+
+	if proxmoxJSON {
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode Proxmox VE status: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printProxmoxStatus(status)
+	return nil
+}
+
+// printProxmoxStatus prints the Proxmox VE hardening in effect
+func printProxmoxStatus(status *model.ProxmoxStatus) {
+	yesNo := func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "no"
+	}
+	allowlist := "everyone"
+	if len(status.WebUIAllowlist) > 0 {
+		allowlist = strings.Join(status.WebUIAllowlist, ", ")
+	}
+	dialog := "shown"
+	if status.SubscriptionNagDisabled {
+		dialog = "disabled"
+	}
+	tfa := "none"
+	if status.RealmTwoFactor != "" {
+		tfa = status.RealmTwoFactor
+	}
+
+	fmt.Printf("%-22s %s\n", "Subscription dialog", dialog)
+	fmt.Printf("%-22s %s\n", "Web UI allowed from", allowlist)
+	fmt.Printf("%-22s %s\n", "Ciphers hardened", yesNo(status.CiphersHardened))
+	fmt.Printf("%-22s %s\n", "Realm second factor", tfa)
+}
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/style"
+	"github.com/spf13/cobra"
+)
+
+var (
+	remoteInventory string
+	remoteHosts     []string
+	remotePush      bool
+	remoteParallel  int
+	remoteVerbose   bool
+)
+
+// RemoteCmd returns the remote command
+func RemoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remote",
+		Short: "Run hardn on the hosts of an inventory over SSH",
+		Long: `Run hardn commands on other hosts over SSH and sum up the result of each
+host. The hosts come from an inventory, ` + model.DefaultInventoryPath + ` by default:
+
+  defaults:
+    user: admin
+    identityFile: /root/.ssh/fleet_ed25519
+  hosts:
+    - name: web1
+      address: 192.0.2.10
+      groups: [web]
+    - name: db1
+      port: 2222
+      groups: [db]
+      push: true
+
+Hosts must be in known_hosts, accept the key without a prompt, and let
+users other than root run sudo without a password. Each host hardens with
+its own hardn configuration.`,
+	}
+	cmd.PersistentFlags().StringVar(&remoteInventory, "inventory", model.DefaultInventoryPath, "Inventory file")
+	cmd.PersistentFlags().StringSliceVar(&remoteHosts, "hosts", nil, "Hosts or groups to work on (default: all)")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the hosts of the inventory",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRemoteList(cmd)
+		},
+	}
+
+	runCmd := &cobra.Command{
+		Use:   "run -- COMMAND [ARGS...]",
+		Short: "Run a hardn command on the hosts of the inventory",
+		Long: `Run a hardn command on every selected host and print a line per host. The
+output of hosts where hardn failed is printed in full, and of every host with
+--verbose. hardn exits with status 1 when a host failed or was unreachable.
+
+Hosts run their installed hardn, or with --push (or push: true in the
+inventory) a copy of this binary, removed once the command is done.
+With --dry-run, every host runs the command with --dry-run.
+
+Examples:
+  sudo hardn remote run -- status --output json
+  sudo hardn remote run --hosts web --dry-run -- run-all
+  sudo hardn remote run --hosts db1,web2 --push -- ssh conflicts`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRemoteRun(cmd, args)
+		},
+	}
+	runCmd.Flags().BoolVar(&remotePush, "push", false, "Copy this hardn binary to every host")
+	runCmd.Flags().IntVar(&remoteParallel, "parallel", 4, "Hosts to work on at once")
+	runCmd.Flags().BoolVar(&remoteVerbose, "verbose", false, "Print the output of every host")
+
+	cmd.AddCommand(listCmd)
+	cmd.AddCommand(runCmd)
+	return cmd
+}
+
+// runRemoteList executes the remote list command
+func runRemoteList(cmd *cobra.Command) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	manager := ctx.serviceFactory().CreateRemoteManager()
+
+	inventory, err := manager.LoadInventory(remoteInventory)
+	if err != nil {
+		return err
+	}
+	hosts, err := manager.SelectHosts(inventory, remoteHosts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-20s %-32s %-6s %-5s %s\n", "HOST", "TARGET", "PORT", "PUSH", "GROUPS")
+	for _, host := range hosts {
+		fmt.Printf("%-20s %-32s %-6d %-5s %s\n", host.Name, host.Target(), host.Port,
+			yesNo(host.Push != nil && *host.Push), strings.Join(host.Groups, ","))
+	}
+	return nil
+}
+
+// runRemoteRun executes the remote run command. It runs nothing on this
+// host, so --dry-run is passed on to every host instead.
+func runRemoteRun(cmd *cobra.Command, args []string) error {
+	if remoteParallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1")
+	}
+
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	manager := ctx.serviceFactory().CreateRemoteManager()
+
+	inventory, err := manager.LoadInventory(remoteInventory)
+	if err != nil {
+		return err
+	}
+	hosts, err := manager.SelectHosts(inventory, remoteHosts)
+	if err != nil {
+		return err
+	}
+
+	request := model.RemoteRequest{
+		Hosts:    hosts,
+		Args:     args,
+		DryRun:   ctx.dryRun,
+		Push:     remotePush,
+		Parallel: remoteParallel,
+	}
+	if request.Binary, err = remoteBinary(hosts, remotePush); err != nil {
+		return err
+	}
+
+	fmt.Printf("Running 'hardn %s' on %d hosts\n\n", strings.Join(args, " "), len(hosts))
+	results := manager.Run(request)
+	printRemoteResults(results, remoteVerbose)
+
+	for _, result := range results {
+		if result.Status != model.RemoteOK {
+			os.Exit(1)
+		}
+	}
+	return nil
+}
+
+// remoteBinary returns this hardn binary when it is pushed to any of the
+// hosts, and "" when every host runs its installed hardn
+func remoteBinary(hosts []model.RemoteHost, push bool) (string, error) {
+	for _, host := range hosts {
+		if push || (host.Push != nil && *host.Push) {
+			binary, err := os.Executable()
+			if err == nil {
+				binary, err = filepath.EvalSymlinks(binary)
+			}
+			if err != nil {
+				return "", fmt.Errorf("failed to find the hardn binary to push: %w", err)
+			}
+			return binary, nil
+		}
+	}
+	return "", nil
+}
+
+// printRemoteResults prints a summary line per host, then the output of
+// the hosts where hardn failed, or of all hosts when verbose. The summary
+// already tells why a host was unreachable.
+func printRemoteResults(results []model.RemoteResult, verbose bool) {
+	width := len("HOST")
+	for _, result := range results {
+		width = max(width, len(result.Host))
+	}
+
+	fmt.Printf("%-*s  %-11s  %4s  %6s  %s\n", width, "HOST", "RESULT", "EXIT", "TIME", "SUMMARY")
+	counts := make(map[string]int)
+	for _, result := range results {
+		counts[result.Status]++
+
+		color := style.Green
+		switch result.Status {
+		case model.RemoteFailed:
+			color = style.Red
+		case model.RemoteUnreachable:
+			color = style.Yellow
+		}
+		exit := "-"
+		if result.ExitCode >= 0 {
+			exit = fmt.Sprint(result.ExitCode)
+		}
+		summary := result.Error
+		if summary == "" {
+			summary = remoteLastLine(result.Output)
+		}
+		fmt.Printf("%-*s  %s  %4s  %6s  %s\n", width, result.Host,
+			style.Colored(color, fmt.Sprintf("%-11s", result.Status)), exit,
+			result.Duration.Round(100*time.Millisecond), summary)
+	}
+	fmt.Printf("\n%d ok, %d failed, %d unreachable\n",
+		counts[model.RemoteOK], counts[model.RemoteFailed], counts[model.RemoteUnreachable])
+
+	for _, result := range results {
+		if result.Output == "" || (result.Status != model.RemoteFailed && !verbose) {
+			continue
+		}
+		fmt.Printf("\n%s\n", style.SectionDivider(result.Host, 72))
+		fmt.Print(result.Output)
+		if !strings.HasSuffix(result.Output, "\n") {
+			fmt.Println()
+		}
+	}
+}
+
+// remoteLastLine returns the last non-empty line of a host's output
+func remoteLastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
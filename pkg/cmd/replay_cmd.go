@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/infrastructure"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/menu"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/session"
+	"github.com/abbott/hardn/pkg/version"
+	"github.com/spf13/cobra"
+)
+
+// ReplayCmd returns the replay command
+func ReplayCmd(currentVersion string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "replay TRANSCRIPT",
+		Short: "Replay a recorded menu session against mock repositories",
+		Long: `Feed the menu choices of a session recorded with --record-session back
+into the interactive menu. Files and commands go to in-memory mocks, so
+nothing on this machine is changed and root is not needed.
+
+The replay uses the OS from the transcript and the configuration given
+with --config, or the defaults. Settings the menus save are written to a
+temporary directory that is removed afterwards. When it ends, the operations of the replay are compared
+with the recorded ones and any difference is listed.
+
+Example:
+  hardn replay hardn-session.jsonl --config ./reported.yml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReplay(cmd, args[0], currentVersion)
+		},
+	}
+}
+
+// StartSessionRecording creates a transcript at path and writes its header.
+// The configured username and the host name are redacted from every event.
+func StartSessionRecording(path string, currentVersion string, cfg *config.Config, osInfo *osdetect.OSInfo) (*session.Recorder, error) {
+	recorder, err := session.CreateRecorder(path, sessionSanitizer(cfg))
+	if err != nil {
+		return nil, err
+	}
+	recorder.Record(sessionHeader(currentVersion, cfg, osInfo))
+	return recorder, nil
+}
+
+// runReplay executes the replay command
+func runReplay(cmd *cobra.Command, path string, currentVersion string) error {
+	events, err := session.LoadTranscript(path)
+	if err != nil {
+		return err
+	}
+	header := events[0]
+
+	configFile := ""
+	if flag := cmd.Flag("config"); flag != nil {
+		configFile = flag.Value.String()
+	}
+	cfg := config.DefaultConfig()
+	if configFile != "" {
+		if cfg, err = config.LoadConfig(configFile); err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+	}
+	cfg.DryRun = header.DryRun
+
+	// Menus that save settings write hardn.yml to the working directory
+	workDir, err := os.MkdirTemp("", "hardn-replay-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(workDir)
+	previousDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(workDir); err != nil {
+		return err
+	}
+	defer func() { _ = os.Chdir(previousDir) }()
+
+	osInfo := &osdetect.OSInfo{
+		OsType:     header.OsType,
+		OsCodename: header.Codename,
+		OsVersion:  header.OsVersion,
+		IsProxmox:  header.IsProxmox,
+		Degraded:   header.Degraded,
+		Machine:    header.Arch,
+		Arch:       header.Arch,
+	}
+
+	// The replay is recorded the same way so it can be compared
+	var replayed bytes.Buffer
+	recorder := session.NewRecorder(&replayed, sessionSanitizer(cfg))
+	recorder.Record(sessionHeader(currentVersion, cfg, osInfo))
+
+	mockProvider := &interfaces.Provider{
+		FS:        interfaces.NewMockFileSystem(),
+		Commander: interfaces.NewMockCommander(),
+		Network:   interfaces.NewMockNetworkOperations(),
+	}
+	serviceFactory := infrastructure.NewServiceFactory(session.RecordProvider(mockProvider, recorder), osInfo)
+	serviceFactory.SetConfig(cfg)
+
+	player := session.NewPlayer(events)
+	menu.SetSessionPlayer(player)
+	menu.SetSessionRecorder(recorder)
+	defer menu.SetSessionPlayer(nil)
+	defer menu.SetSessionRecorder(nil)
+
+	// Without a current version the menu never checks for updates
+	mainMenu := infrastructure.NewMenuFactory(serviceFactory, cfg, osInfo).CreateMainMenu(version.NewService("", "", ""))
+	mainMenu.ShowMainMenu("", "", "")
+
+	if err := recorder.Close(); err != nil {
+		return err
+	}
+	replayedEvents, err := session.ReadTranscript(&replayed)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Replayed %s (hardn %s on %s %s)\n", path, header.Version, header.OsType, header.OsVersion)
+	for _, mismatch := range player.Mismatches() {
+		fmt.Printf("  %s\n", mismatch)
+	}
+	if remaining := player.Remaining(); remaining > 0 {
+		fmt.Printf("  %d recorded inputs were not used\n", remaining)
+	}
+
+	differences := session.CompareOperations(events, replayedEvents)
+	if len(differences) == 0 && len(player.Mismatches()) == 0 {
+		fmt.Println("Operations match the recording")
+		return nil
+	}
+	if len(differences) > 0 {
+		fmt.Println("Operations differ from the recording (- recorded, + replayed):")
+		for _, difference := range differences {
+			fmt.Printf("  %s\n", difference)
+		}
+	}
+	return fmt.Errorf("replay diverged from the recording")
+}
+
+// sessionSanitizer redacts what identifies the host from transcripts
+func sessionSanitizer(cfg *config.Config) *session.Sanitizer {
+	sanitizer := session.NewSanitizer()
+	sanitizer.Redact("username", cfg.Username)
+	if hostname, err := os.Hostname(); err == nil {
+		sanitizer.Redact("hostname", hostname)
+	}
+	return sanitizer
+}
+
+// sessionHeader describes the session a transcript records
+func sessionHeader(currentVersion string, cfg *config.Config, osInfo *osdetect.OSInfo) session.Event {
+	return session.Event{
+		Kind:      session.KindSession,
+		Version:   currentVersion,
+		OsType:    osInfo.OsType,
+		OsVersion: osInfo.OsVersion,
+		Codename:  osInfo.OsCodename,
+		IsProxmox: osInfo.IsProxmox,
+		Degraded:  osInfo.Degraded,
+		Arch:      osInfo.Arch,
+		DryRun:    cfg.DryRun,
+	}
+}
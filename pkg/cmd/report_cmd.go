@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/security"
+	"github.com/abbott/hardn/pkg/style"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportFormat string
+	reportOutput string
+	reportDiffs  bool
+	reportLimit  int
+)
+
+// ReportCmd returns the report command
+func ReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Show the reports of hardening runs",
+		Long: `Show what a hardening run did. Every run that changes the system, --run-all
+and the individual operations alike, writes a report to
+` + model.RunReportsDir + `: the steps it ran with their outcome and
+duration, each change with the diff of the files it wrote, the errors it
+ran into and, for --run-all, the security posture before and after.
+
+Reports of runs that changed something share the run's ID, which
+'hardn rollback' takes as well. Dry runs write no report.`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the run reports",
+		Long: `List the latest run reports, oldest first.
+
+Examples:
+  sudo hardn report list
+  sudo hardn report list --limit 50`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReportList()
+		},
+	}
+	listCmd.Flags().IntVar(&reportLimit, "limit", 20, "Number of reports to show (0 for all)")
+
+	showCmd := &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show a run report",
+		Long: `Show the report of a run as text, JSON or a standalone HTML page for
+auditors. Text leaves out the diffs unless --diff is given.
+
+Examples:
+  sudo hardn report show 20240601-020000
+  sudo hardn report show 20240601-020000 --diff
+  sudo hardn report show 20240601-020000 --format html --output run.html`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report, err := newRunReportManager().GetReport(args[0])
+			if err != nil {
+				return err
+			}
+			return writeRunReport(report)
+		},
+	}
+
+	lastCmd := &cobra.Command{
+		Use:   "last",
+		Short: "Show the report of the latest run",
+		Long: `Show the report of the latest run, with the same options as show.
+
+Examples:
+  sudo hardn report last
+  sudo hardn report last --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report, err := newRunReportManager().LastReport()
+			if err != nil {
+				return err
+			}
+			return writeRunReport(report)
+		},
+	}
+
+	for _, sub := range []*cobra.Command{showCmd, lastCmd} {
+		sub.Flags().StringVarP(&reportFormat, "format", "O", "text", "Output format (text, json, html)")
+		sub.Flags().StringVarP(&reportOutput, "output", "o", "", "Write the report to a file instead of stdout")
+		sub.Flags().BoolVar(&reportDiffs, "diff", false, "Include the diffs in text output")
+	}
+
+	cmd.AddCommand(listCmd)
+	cmd.AddCommand(showCmd)
+	cmd.AddCommand(lastCmd)
+	return cmd
+}
+
+// runReportList prints a line per report
+func runReportList() error {
+	if reportLimit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+
+	reports, err := newRunReportManager().ListReports(reportLimit)
+	if err != nil {
+		return err
+	}
+	if len(reports) == 0 {
+		fmt.Println("No run reports recorded")
+		return nil
+	}
+
+	fmt.Printf("%-20s %-9s %-8s %-8s %s\n", "ID", "STATUS", "DURATION", "CHANGES", "DESCRIPTION")
+	for _, report := range reports {
+		fmt.Printf("%-20s %-9s %-8s %-8d %s\n", report.ID, report.Status,
+			formatRunDuration(report.Duration().Seconds()), len(report.Changes), report.Description)
+	}
+	fmt.Println("\nUse 'hardn report show <id>' to see a report")
+	return nil
+}
+
+// writeRunReport writes a report in the requested format
+func writeRunReport(report *model.RunReport) error {
+	if reportFormat != "text" && reportFormat != "json" && reportFormat != "html" {
+		return fmt.Errorf("unsupported format: %s (use text, json or html)", reportFormat)
+	}
+
+	var out io.Writer = os.Stdout
+	if reportOutput != "" {
+		file, err := os.Create(reportOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", reportOutput, err)
+		}
+		defer file.Close()
+		out = file
+
+		// Color codes would end up in the file
+		colors := style.UseColors
+		style.UseColors = false
+		defer func() { style.UseColors = colors }()
+	}
+
+	switch reportFormat {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode run report: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	case "html":
+		if err := runReportHTML.Execute(out, newRunReportPage(report)); err != nil {
+			return fmt.Errorf("failed to write run report: %w", err)
+		}
+	default:
+		printRunReport(out, report, reportDiffs)
+	}
+	return nil
+}
+
+// printRunReport prints a report as text, with the diffs if diffs is set
+func printRunReport(out io.Writer, report *model.RunReport, diffs bool) {
+	status := style.Colored(style.Green, report.Status)
+	if report.Failed() {
+		status = style.Colored(style.Red, report.Status)
+	}
+
+	fmt.Fprintf(out, "Run %s: %s\n", report.ID, report.Description)
+	fmt.Fprintf(out, "  Host:     %s (%s)\n", report.Hostname, report.OS)
+	if report.Version != "" {
+		fmt.Fprintf(out, "  Version:  hardn %s\n", report.Version)
+	}
+	fmt.Fprintf(out, "  Started:  %s\n", report.Started.Local().Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(out, "  Duration: %s\n", formatRunDuration(report.Duration().Seconds()))
+	fmt.Fprintf(out, "  Status:   %s\n", status)
+	if report.Before != nil && report.After != nil {
+		fmt.Fprintf(out, "  Posture:  %s %d/%d %s %s %d/%d\n",
+			report.Before.RiskLevel, report.Before.Score, report.Before.MaxScore, style.SymArrowRight,
+			report.After.RiskLevel, report.After.Score, report.After.MaxScore)
+		for _, id := range report.After.Regressions(*report.Before) {
+			fmt.Fprintf(out, "            %s %s (%s)\n", style.Colored(style.Red, style.SymArrowDown), security.ControlTitle(id), id)
+		}
+		for _, id := range report.After.Improvements(*report.Before) {
+			fmt.Fprintf(out, "            %s %s (%s)\n", style.Colored(style.Green, style.SymArrowUp), security.ControlTitle(id), id)
+		}
+	}
+
+	fmt.Fprintln(out, "\nSteps:")
+	for _, step := range report.Steps {
+		symbol := style.Colored(style.Green, style.SymCheckMark)
+		switch step.Status {
+		case model.RunFailed:
+			symbol = style.Colored(style.Red, style.SymCrossMark)
+		case model.StepNotRun:
+			symbol = style.Dimmed(style.SymDash)
+		}
+		line := fmt.Sprintf("  %s %s", symbol, step.Name)
+		if step.Status != model.StepNotRun {
+			line += style.Dimmed(" (" + formatRunDuration(step.Duration) + ")")
+		} else {
+			line += style.Dimmed(" (not run)")
+		}
+		if step.Error != "" {
+			line += ": " + step.Error
+		}
+		fmt.Fprintln(out, line)
+	}
+
+	fmt.Fprintln(out, "\nChanges:")
+	if len(report.Changes) == 0 {
+		fmt.Fprintln(out, "  none")
+	}
+	for i, change := range report.Changes {
+		state := ""
+		if change.Reverted {
+			state = " (rolled back)"
+		}
+		fmt.Fprintf(out, "  %2d. %s %s %s%s\n", i+1, change.Kind, change.Subject, change.Summary, state)
+		if change.Note != "" {
+			fmt.Fprintf(out, "      %s\n", style.Dimmed(change.Note))
+		}
+		if diffs && change.Diff != "" {
+			for _, line := range strings.Split(strings.TrimSuffix(change.Diff, "\n"), "\n") {
+				fmt.Fprintf(out, "      %s\n", colorDiffLine(line))
+			}
+		}
+	}
+
+	if len(report.Errors) > 0 {
+		fmt.Fprintln(out, "\nErrors:")
+		for _, message := range report.Errors {
+			fmt.Fprintf(out, "  %s %s\n", style.Colored(style.Red, style.SymCrossMark), message)
+		}
+	}
+}
+
+// colorDiffLine colors a line of a saved diff as style.UnifiedDiff does
+func colorDiffLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "--- "), strings.HasPrefix(line, "+++ "):
+		return style.Colored(style.Bold, line)
+	case strings.HasPrefix(line, "@@"):
+		return style.Colored(style.Cyan, line)
+	case strings.HasPrefix(line, "-"):
+		return style.Colored(style.Red, line)
+	case strings.HasPrefix(line, "+"):
+		return style.Colored(style.Green, line)
+	}
+	return line
+}
+
+// formatRunDuration formats seconds as 0.4s, 12s or 3m05s
+func formatRunDuration(seconds float64) string {
+	switch {
+	case seconds < 10:
+		return fmt.Sprintf("%.1fs", seconds)
+	case seconds < 60:
+		return fmt.Sprintf("%.0fs", seconds)
+	}
+	total := int(seconds + 0.5)
+	return fmt.Sprintf("%dm%02ds", total/60, total%60)
+}
+
+// runReportPage is what the HTML template shows of a report
+type runReportPage struct {
+	*model.RunReport
+	Duration     string
+	Regressions  []string
+	Improvements []string
+}
+
+// newRunReportPage prepares a report for the HTML template
+func newRunReportPage(report *model.RunReport) runReportPage {
+	page := runReportPage{RunReport: report, Duration: formatRunDuration(report.Duration().Seconds())}
+	if report.Before != nil && report.After != nil {
+		for _, id := range report.After.Regressions(*report.Before) {
+			page.Regressions = append(page.Regressions, security.ControlTitle(id)+" ("+id+")")
+		}
+		for _, id := range report.After.Improvements(*report.Before) {
+			page.Improvements = append(page.Improvements, security.ControlTitle(id)+" ("+id+")")
+		}
+	}
+	return page
+}
+
+// runReportHTML renders a run report as a standalone page
+var runReportHTML = template.Must(template.New("report").Funcs(template.FuncMap{
+	"seconds": formatRunDuration,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>hardn run {{.ID}}: {{.Hostname}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ddd; padding: 0.4em 0.6em; text-align: left; vertical-align: top; }
+pre { background: #f4f4f4; padding: 0.6em; overflow-x: auto; }
+.succeeded { color: #1a7f37; }
+.failed { color: #cf222e; font-weight: bold; }
+.na { color: #888; }
+</style>
+</head>
+<body>
+<h1>hardn run {{.ID}}</h1>
+<p>{{.Description}} on <strong>{{.Hostname}}</strong> ({{.OS}}){{if .Version}}, hardn {{.Version}}{{end}}</p>
+<p>Started {{.Started.Format "2006-01-02 15:04:05 MST"}}, took {{.Duration}}: <span class="{{.Status}}">{{.Status}}</span></p>
+{{if and .Before .After}}<h2>Security posture</h2>
+<p>Before: {{.Before.RiskLevel}} ({{.Before.Score}}/{{.Before.MaxScore}}). After: {{.After.RiskLevel}} ({{.After.Score}}/{{.After.MaxScore}}).</p>
+{{if .Regressions}}<p class="failed">Regressed:</p><ul>{{range .Regressions}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{if .Improvements}}<p class="succeeded">Improved:</p><ul>{{range .Improvements}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{end}}<h2>Steps</h2>
+<table>
+<tr><th>Step</th><th>Status</th><th>Duration</th><th>Error</th></tr>
+{{range .Steps}}<tr>
+<td>{{.Name}}</td>
+<td class="{{if eq .Status "not run"}}na{{else}}{{.Status}}{{end}}">{{.Status}}</td>
+<td>{{if ne .Status "not run"}}{{seconds .Duration}}{{end}}</td>
+<td>{{.Error}}</td>
+</tr>
+{{end}}</table>
+<h2>Changes</h2>
+{{if .Changes}}<table>
+<tr><th>Kind</th><th>Subject</th><th>Change</th></tr>
+{{range .Changes}}<tr>
+<td>{{.Kind}}</td>
+<td>{{.Subject}}</td>
+<td>{{.Summary}}{{if .Reverted}} (rolled back){{end}}{{if .Note}}<br><small>{{.Note}}</small>{{end}}{{if .Diff}}<pre>{{.Diff}}</pre>{{end}}</td>
+</tr>
+{{end}}</table>{{else}}<p>No changes.</p>{{end}}
+{{if .Errors}}<h2>Errors</h2>
+<ul>{{range .Errors}}<li class="failed">{{.}}</li>{{end}}</ul>{{end}}
+</body>
+</html>
+`))
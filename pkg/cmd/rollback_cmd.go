@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rollbackSnapshot string
+	listSnapshots    bool
+	assumeYes        bool
+	rollbackChanges  []int
+	forceRollback    bool
+)
+
+// RollbackCmd returns the rollback command
+func RollbackCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback [run-id]",
+		Short: "Revert a hardening run or the root filesystem",
+		Long: `Revert the changes of a hardening run, or the root filesystem to a
+snapshot taken before run-all.
+
+Every hardn invocation that changes the system is recorded as a run: the
+files it wrote, the packages it installed, the services and users it
+touched and the ufw rules it changed. Rolling back a run restores the
+previous state. Files modified since the run are left alone unless
+--force is given. Use --change to revert only some changes, numbered as
+listed by 'hardn rollback <run-id> --list'.
+
+Snapshots are available when / is on LVM thin, ZFS or btrfs. Without a
+name the most recent hardn snapshot is used. LVM and btrfs rollbacks take
+effect on the next reboot.
+
+Examples:
+  sudo hardn rollback --list
+  sudo hardn rollback 20240601-020000 --list
+  sudo hardn rollback 20240601-020000
+  sudo hardn rollback 20240601-020000 --change 2,5
+  sudo hardn rollback --snapshot
+  sudo hardn rollback --snapshot hardn-20240601-020000`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			snapshotRequested := cmd.Flags().Changed("snapshot")
+			if len(args) == 1 {
+				if snapshotRequested {
+					return fmt.Errorf("give either a run ID or --snapshot, not both")
+				}
+				return runRunRollback(args[0], flagEnabled(cmd, "dry-run"))
+			}
+			if !snapshotRequested {
+				listRuns()
+			}
+			return runRollback(snapshotRequested, flagEnabled(cmd, "dry-run"))
+		},
+	}
+
+	cmd.Flags().StringVar(&rollbackSnapshot, "snapshot", "", "Snapshot to revert to (default: most recent)")
+	cmd.Flags().Lookup("snapshot").NoOptDefVal = "latest"
+	cmd.Flags().BoolVar(&listSnapshots, "list", false, "List runs and hardn snapshots, or the changes of a run")
+	cmd.Flags().BoolVar(&assumeYes, "yes", false, "Do not ask for confirmation")
+	cmd.Flags().IntSliceVar(&rollbackChanges, "change", nil, "Revert only these changes of the run")
+	cmd.Flags().BoolVar(&forceRollback, "force", false, "Restore files even if they were modified after the run")
+
+	return cmd
+}
+
+// newRunManager wires the RunManager without recording its own changes
+func newRunManager() *application.RunManager {
+	provider := interfaces.NewProvider()
+	runRepo := secondary.NewFileRunRepository(provider.FS, provider.Commander, model.RunsDir)
+	return application.NewRunManager(service.NewRunServiceImpl(runRepo))
+}
+
+// listRuns prints the recorded runs, newest first
+func listRuns() {
+	runs, err := newRunManager().ListRuns()
+	if err != nil {
+		fmt.Printf("Unable to read hardening runs: %v\n", err)
+		return
+	}
+	if len(runs) == 0 {
+		fmt.Println("No hardening runs recorded")
+		return
+	}
+
+	fmt.Println("Hardening runs:")
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+		status := fmt.Sprintf("%d changes", len(run.Changes))
+		if pending := run.PendingChanges(); pending == 0 {
+			status += ", rolled back"
+		} else if pending < len(run.Changes) {
+			status += fmt.Sprintf(", %d rolled back", len(run.Changes)-pending)
+		}
+		fmt.Printf("  %s\t%s\t%s\n", run.ID, run.Description, status)
+	}
+	fmt.Println("\nUse 'hardn rollback <run-id> --list' to see the changes of a run")
+	fmt.Println()
+}
+
+// printRunChanges lists the changes of a run with their numbers
+func printRunChanges(run *model.RunManifest) {
+	fmt.Printf("Run %s (%s), started %s:\n", run.ID, run.Description, run.Started.Format("2006-01-02 15:04:05"))
+	for i, change := range run.Changes {
+		state := ""
+		if change.IsReverted() {
+			state = " (rolled back)"
+		}
+		fmt.Printf("  %2d. %s%s\n", i+1, change.Describe(), state)
+	}
+}
+
+// runRunRollback reverts the changes of a recorded run
+func runRunRollback(id string, dryRun bool) error {
+	runManager := newRunManager()
+	run, err := runManager.GetRun(id)
+	if err != nil {
+		return err
+	}
+
+	if listSnapshots || dryRun {
+		printRunChanges(run)
+		if dryRun {
+			fmt.Println("[DRY-RUN] No changes rolled back")
+		}
+		return nil
+	}
+
+	if !assumeYes {
+		printRunChanges(run)
+		what := "all changes"
+		if len(rollbackChanges) > 0 {
+			numbers := make([]string, len(rollbackChanges))
+			for i, number := range rollbackChanges {
+				numbers[i] = strconv.Itoa(number)
+			}
+			what = "changes " + strings.Join(numbers, ", ")
+		}
+		fmt.Printf("Roll back %s of run %s?\n", what, run.ID)
+		fmt.Print("Type 'yes' to confirm: ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.TrimSpace(strings.ToLower(answer)) != "yes" {
+			fmt.Println("Rollback cancelled.")
+			return nil
+		}
+	}
+
+	if err := escalate(); err != nil {
+		return err
+	}
+	result, err := newRunManager().Rollback(id, rollbackChanges, forceRollback)
+	if result != nil {
+		for _, change := range result.Reverted {
+			fmt.Printf("Reverted %s\n", change.Describe())
+		}
+		for _, failure := range result.Failed {
+			fmt.Printf("Could not revert %s: %v\n", failure.Change.Describe(), failure.Err)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d of %d changes could not be rolled back", len(result.Failed), len(result.Failed)+len(result.Reverted))
+	}
+	return nil
+}
+
+// runRollback executes the rollback command
+func runRollback(snapshotRequested bool, dryRun bool) error {
+	provider := interfaces.NewProvider()
+
+	snapshotRepo := secondary.NewSystemSnapshotRepository(provider.FS, provider.Commander)
+	snapshotService := service.NewSnapshotServiceImpl(snapshotRepo)
+	snapshotManager := application.NewSnapshotManager(snapshotService)
+
+	support, err := snapshotManager.GetSnapshotSupport()
+	if err != nil {
+		return err
+	}
+	if !support.Supported {
+		if !snapshotRequested {
+			fmt.Printf("Root filesystem snapshots are not available: %s\n", support.Reason)
+			return nil
+		}
+		return fmt.Errorf("root filesystem snapshots are not supported: %s", support.Reason)
+	}
+
+	snapshots, err := snapshotManager.ListSnapshots()
+	if err != nil {
+		return err
+	}
+
+	if listSnapshots || !snapshotRequested {
+		if len(snapshots) == 0 {
+			fmt.Printf("No hardn snapshots found for %s (%s)\n", support.Target, support.Backend)
+			return nil
+		}
+		fmt.Printf("hardn snapshots of %s (%s):\n", support.Target, support.Backend)
+		for _, snapshot := range snapshots {
+			fmt.Printf("  %s\t%s\n", snapshot.Name, snapshot.Created.Format("2006-01-02 15:04:05"))
+		}
+		if !snapshotRequested {
+			fmt.Println("\nUse --snapshot [name] to revert to a snapshot")
+		}
+		return nil
+	}
+
+	// Resolve the target for confirmation before anything changes
+	target := rollbackSnapshot
+	if target == "" || target == "latest" {
+		if len(snapshots) == 0 {
+			return fmt.Errorf("no hardn snapshots found")
+		}
+		target = snapshots[len(snapshots)-1].Name
+	}
+
+	if dryRun {
+		fmt.Printf("[DRY-RUN] Would roll back %s to %s (%s)\n", support.Target, target, support.Backend)
+		return nil
+	}
+
+	if !assumeYes {
+		fmt.Printf("Roll back %s to %s? Changes made since the snapshot will be lost.\n", support.Target, target)
+		fmt.Print("Type 'yes' to confirm: ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.TrimSpace(strings.ToLower(answer)) != "yes" {
+			fmt.Println("Rollback cancelled.")
+			return nil
+		}
+	}
+
+	if err := escalate(); err != nil {
+		return err
+	}
+	provider = interfaces.NewProvider()
+	snapshotRepo = secondary.NewSystemSnapshotRepository(provider.FS, provider.Commander)
+	snapshotManager = application.NewSnapshotManager(service.NewSnapshotServiceImpl(snapshotRepo))
+	snapshot, err := snapshotManager.Rollback(target)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Rolled back %s to %s\n", snapshot.Target, snapshot.Name)
+	if support.RollbackNeedsReboot {
+		fmt.Println("Reboot to complete the rollback.")
+	}
+
+	return nil
+}
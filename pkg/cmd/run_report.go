@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/security"
+)
+
+// runReport is the report of this invocation's run, once one has started
+var runReport *pendingReport
+
+// pendingReport collects what a run does until ReportRun saves it
+type pendingReport struct {
+	report  model.RunReport
+	journal *secondary.RunJournal
+	errors  *errorSink
+	steps   *model.StepRecorder
+
+	// Run All checks the posture again when it is done
+	cfg    *config.Config
+	osInfo *osdetect.OSInfo
+}
+
+// errorSink collects the errors logged during a run
+type errorSink struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (s *errorSink) Write(entry logging.Entry) error {
+	if entry.Level >= logging.LevelError {
+		s.mu.Lock()
+		s.messages = append(s.messages, entry.Message)
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+func (s *errorSink) Close() error { return nil }
+
+func (s *errorSink) Messages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...)
+}
+
+// newRunReportManager wires the RunReportManager without recording the
+// report as a change of the run
+func newRunReportManager() *application.RunReportManager {
+	provider := interfaces.NewProvider()
+	reportRepo := secondary.NewFileRunReportRepository(provider.FS, model.RunReportsDir, model.RunsDir)
+	return application.NewRunReportManager(service.NewRunReportServiceImpl(reportRepo))
+}
+
+// beginRunReport starts the report of the run journal records
+func beginRunReport(journal *secondary.RunJournal, description string, cfg *config.Config, osInfo *osdetect.OSInfo) {
+	hostname, _ := os.Hostname()
+	pending := &pendingReport{
+		report: model.RunReport{
+			Description: description,
+			Version:     hardnVersion,
+			Hostname:    hostname,
+			Started:     time.Now(),
+		},
+		journal: journal,
+		errors:  &errorSink{},
+		cfg:     cfg,
+		osInfo:  osInfo,
+	}
+	if osInfo != nil {
+		pending.report.OS = osInfo.OsType + " " + osInfo.OsVersion
+	}
+	logging.AddSink(pending.errors)
+	runReport = pending
+}
+
+// TrackRunAll records the steps of Run All, their outcome and duration,
+// and the security posture before and after for the run report. Progress
+// is passed on to reporter.
+func TrackRunAll(reporter model.ProgressReporter) model.ProgressReporter {
+	if runReport == nil {
+		return reporter
+	}
+
+	if before := runReport.posture(); before != nil {
+		runReport.report.Before = before
+	}
+	runReport.steps = model.NewStepRecorder(reporter)
+	return runReport.steps
+}
+
+// posture checks the security posture, or returns nil if it can not
+func (p *pendingReport) posture() *model.PostureSnapshot {
+	status, err := security.CheckSecurityStatus(p.cfg, p.osInfo)
+	if err != nil {
+		return nil
+	}
+	snapshot := security.BuildPostureSnapshot(status, time.Now(), model.PostureSourceRunAll)
+	return &snapshot
+}
+
+// saveRunReport completes the report of this invocation's run with err,
+// the error it ended with, and saves it
+func saveRunReport(err error) {
+	pending := runReport
+	if pending == nil {
+		return
+	}
+	runReport = nil
+
+	// A session that stayed in dry-run mode changed nothing worth reporting
+	if pending.cfg != nil && pending.cfg.DryRun && pending.journal.RunID() == "" {
+		return
+	}
+
+	report := pending.report
+	report.Errors = pending.errors.Messages()
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+	}
+	if pending.report.Before != nil {
+		report.After = pending.posture()
+	}
+	report.Finished = time.Now()
+
+	if pending.steps != nil {
+		report.Steps = pending.steps.Steps()
+	} else {
+		step := model.RunReportStep{
+			Name:     report.Description,
+			Status:   model.RunSucceeded,
+			Started:  report.Started,
+			Duration: report.Finished.Sub(report.Started).Seconds(),
+		}
+		if len(report.Errors) > 0 {
+			step.Status = model.RunFailed
+			step.Error = report.Errors[len(report.Errors)-1]
+		}
+		report.Steps = []model.RunReportStep{step}
+	}
+
+	saved, saveErr := newRunReportManager().SaveReport(report, pending.journal.RunID())
+	if saveErr != nil {
+		logging.LogWarning("Failed to write the report of this run: %v", saveErr)
+		return
+	}
+	logging.LogInfo("Report of this run written to %s; view it with 'hardn report show %s'",
+		model.RunReportsDir, saved.ID)
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scheduleInterval string
+	scheduleTasks    []string
+)
+
+// ScheduleCmd returns the schedule command
+func ScheduleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Re-run hardening steps or the audit on an interval",
+		Long: `Install a systemd timer, or a root crontab entry on Alpine, that re-runs
+selected hardening steps or the audit check. The interval and tasks come
+from the schedule section of hardn.yml unless given as flags.
+
+Tasks are run-all, disable-root, firewall, dns, ssh-keys, selftest and
+audit. Hardening tasks run together in one hardn invocation; the removal of
+expired SSH keys, the self-test and the audit run after them.
+Scheduled changes respect the configured maintenance windows. With
+schedule.watchdog enabled, a run that leaves SSH unreachable after changing
+sshd or the firewall is rolled back. With schedule.summary.cron set, a
+posture summary is also sent through the notifications when the cron
+expression matches.`,
+	}
+
+	installCmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install or replace the schedule",
+		Long: `Install the schedule, replacing any schedule hardn installed before.
+
+Examples:
+  sudo hardn schedule install
+  sudo hardn schedule install --interval weekly --task firewall --task audit
+  sudo hardn schedule install --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runScheduleInstall(cmd)
+		},
+	}
+	installCmd.Flags().StringVar(&scheduleInterval, "interval", "", "How often to run (hourly, daily, weekly, monthly)")
+	installCmd.Flags().StringSliceVar(&scheduleTasks, "task", nil, "Task to run; repeat or separate with commas")
+
+	removeCmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove the schedule",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runScheduleRemove(cmd)
+		},
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the installed schedule",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runScheduleStatus(cmd)
+		},
+	}
+
+	cmd.AddCommand(installCmd)
+	cmd.AddCommand(removeCmd)
+	cmd.AddCommand(statusCmd)
+	return cmd
+}
+
+// runScheduleInstall executes the schedule install command
+func runScheduleInstall(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireSupportedOS("schedule"); err != nil {
+		return err
+	}
+	if err := ctx.requireOutsideWSL("schedule"); err != nil {
+		return err
+	}
+	scheduleManager := ctx.serviceFactory().CreateScheduleManager()
+
+	interval := ctx.cfg.Schedule.Interval
+	if cmd.Flags().Changed("interval") {
+		interval = scheduleInterval
+	}
+	tasks := ctx.cfg.Schedule.Tasks
+	if cmd.Flags().Changed("task") {
+		tasks = scheduleTasks
+	}
+
+	schedule, err := scheduleManager.PlanSchedule(interval, tasks, config.AbsConfigFile(ctx.configFile),
+		ctx.cfg.Schedule.Watchdog.Enabled, ctx.cfg.Schedule.Summary.Cron)
+	if err != nil {
+		return err
+	}
+	if schedule.Summary != nil && !newNotificationManager(ctx.cfg).Enabled() {
+		return fmt.Errorf("schedule.summary.cron needs notifications.email or notifications.webhooks to send the summary to")
+	}
+	for _, task := range schedule.Tasks {
+		if task == model.ScheduleTaskRunAll && ctx.cfg.Username == "" {
+			return fmt.Errorf("the run-all task needs a username in the configuration file")
+		}
+	}
+
+	if ctx.dryRun {
+		fmt.Printf("[DRY-RUN] Would install a %s schedule running:\n", schedule.Interval.Name)
+		printScheduleCommands(schedule.Commands)
+		printSummarySchedule(schedule.Summary)
+		return nil
+	}
+
+	if err := scheduleManager.InstallSchedule(schedule); err != nil {
+		return err
+	}
+
+	fmt.Printf("Installed %s schedule: %s\n", schedule.Interval.Name, strings.Join(schedule.Tasks, ", "))
+	printScheduleCommands(schedule.Commands)
+	printSummarySchedule(schedule.Summary)
+	return nil
+}
+
+// runScheduleRemove executes the schedule remove command
+func runScheduleRemove(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	scheduleManager := ctx.serviceFactory().CreateScheduleManager()
+
+	status, err := scheduleManager.GetScheduleStatus()
+	if err != nil {
+		return err
+	}
+	if !status.Installed {
+		fmt.Println("No schedule installed")
+		return nil
+	}
+
+	if ctx.dryRun {
+		fmt.Printf("[DRY-RUN] Would remove the %s schedule (%s)\n", status.Interval, status.Backend)
+		return nil
+	}
+
+	if err := scheduleManager.RemoveSchedule(); err != nil {
+		return err
+	}
+	fmt.Printf("Removed the %s schedule\n", status.Interval)
+	return nil
+}
+
+// runScheduleStatus executes the schedule status command
+func runScheduleStatus(cmd *cobra.Command) error {
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	status, err := ctx.serviceFactory().CreateScheduleManager().GetScheduleStatus()
+	if err != nil {
+		return err
+	}
+	if !status.Installed {
+		fmt.Println("No schedule installed (use 'hardn schedule install')")
+		return nil
+	}
+
+	fmt.Printf("Interval: %s (%s)\n", status.Interval, status.Backend)
+	if status.Backend == model.ScheduleBackendSystemd {
+		state := "inactive"
+		if status.Active {
+			state = "active"
+		}
+		fmt.Printf("Timer:    %s\n", state)
+		if status.NextRun != "" {
+			fmt.Printf("Next run: %s\n", status.NextRun)
+		}
+	} else {
+		fmt.Printf("Log:      %s\n", model.ScheduleLogPath)
+	}
+	fmt.Println("Commands:")
+	for _, command := range status.Commands {
+		fmt.Printf("  %s\n", command)
+	}
+	if status.Summary != "" {
+		fmt.Printf("Posture summary: %s\n", status.Summary)
+		fmt.Printf("  %s\n", status.SummaryCommand)
+	}
+	return nil
+}
+
+func printScheduleCommands(commands [][]string) {
+	for _, command := range commands {
+		fmt.Printf("  %s\n", strings.Join(command, " "))
+	}
+}
+
+func printSummarySchedule(summary *model.SummarySchedule) {
+	if summary == nil {
+		return
+	}
+	fmt.Printf("Posture summary at %q (%s):\n", summary.Cron, summary.OnCalendar)
+	fmt.Printf("  %s\n", strings.Join(summary.Command, " "))
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/schema"
+	"github.com/spf13/cobra"
+)
+
+// SchemaCmd returns the schema command
+func SchemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema [NAME]",
+		Short: "Print the JSON Schema of a machine-readable output",
+		Long: `Print the JSON Schema of one of hardn's machine-readable outputs, such as
+the audit report, the facts policies are evaluated against or the
+configuration file, so other tools can validate and generate code from
+them. Without NAME every schema is listed with the output it describes.
+
+Each schema's $id names its version. Within a version properties are only
+added; removing one, changing its type or making it optional again
+releases the next version.
+
+Examples:
+  hardn schema
+  hardn schema report > hardn-report.schema.json
+  hardn schema config`,
+		Args:      cobra.MaximumNArgs(1),
+		ValidArgs: schema.Names(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				runSchemaList()
+				return nil
+			}
+			return runSchema(args[0])
+		},
+	}
+	return cmd
+}
+
+// runSchemaList lists every published schema
+func runSchemaList() {
+	for _, name := range schema.Names() {
+		doc, _ := schema.Lookup(name)
+		fmt.Printf("%-12s v%-3d %-32s %s\n", doc.Name, doc.Version, doc.Title, doc.Output)
+	}
+}
+
+// runSchema prints one schema
+func runSchema(name string) error {
+	if _, ok := schema.Lookup(name); !ok {
+		return fmt.Errorf("unknown schema %q (one of %s)", name, strings.Join(schema.Names(), ", "))
+	}
+	data, err := schema.Published(name)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/version"
+	"github.com/spf13/cobra"
+)
+
+var (
+	updateChannel    string
+	forceUpdate      bool
+	requireSignature bool
+	rollbackUpdate   bool
+)
+
+// SelfUpdateCmd returns the self-update command
+func SelfUpdateCmd(versionService *version.Service) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "self-update",
+		Short: "Replace hardn with the latest release",
+		Long: `Download the latest hardn release for this OS and architecture and
+replace the running binary with it.
+
+The download must match the checksum published with the release. When
+cosign or minisign is installed, the signature of the checksum file and
+of the binary are verified too; pass --require-signature to refuse
+releases whose signature cannot be checked.
+The new binary is renamed over the old one in a single step, and the
+old one is kept next to it with a .old suffix. If the new binary fails to
+start, the old one is put back.
+
+The stable channel follows the latest release; the beta channel includes
+pre-releases. In offline mode only --rollback is available.
+
+Examples:
+  sudo hardn self-update
+  sudo hardn self-update --channel beta
+  sudo hardn self-update --rollback`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if rollbackUpdate {
+				return runSelfUpdateRollback(flagEnabled(cmd, "dry-run"))
+			}
+			if _, err := loadUpdateConfig(cmd); err != nil {
+				return err
+			}
+			if err := interfaces.RequireNetwork("update hardn"); err != nil {
+				return err
+			}
+			return runSelfUpdate(versionService, version.SelfUpdateOptions{
+				Channel:          updateChannel,
+				Force:            forceUpdate,
+				RequireSignature: requireSignature,
+				DryRun:           flagEnabled(cmd, "dry-run"),
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&updateChannel, "channel", version.ChannelStable, "Release channel: stable or beta")
+	cmd.Flags().BoolVar(&forceUpdate, "force", false, "Install the release even when it is not newer")
+	cmd.Flags().BoolVar(&requireSignature, "require-signature", false, "Fail when the release signature cannot be verified")
+	cmd.Flags().BoolVar(&rollbackUpdate, "rollback", false, "Restore the binary replaced by the last self-update")
+
+	return cmd
+}
+
+// runSelfUpdate installs the newest release on the chosen channel
+func runSelfUpdate(versionService *version.Service, options version.SelfUpdateOptions) error {
+	result, err := versionService.SelfUpdate(context.Background(), options)
+	if err != nil {
+		return fmt.Errorf("self-update failed: %w", err)
+	}
+
+	current := result.CurrentVersion
+	if current == "" {
+		current = "development build"
+	}
+	fmt.Printf("Current version: %s\n", current)
+	fmt.Printf("Latest version:  %s (%s channel)\n", result.LatestVersion, channelName(options.Channel))
+
+	switch {
+	case !result.UpdateAvailable:
+		fmt.Println("hardn is up to date")
+	case options.DryRun:
+		fmt.Printf("Verified:        %s\n", verificationText(result.Verification))
+		fmt.Printf("[DRY-RUN] Would replace %s with %s\n", result.BinaryPath, result.Assets.BinaryURL)
+	default:
+		fmt.Printf("Installed %s to %s\n", result.Assets.BinaryName, result.BinaryPath)
+		fmt.Printf("SHA-256:         %s (verified)\n", result.Checksum)
+		if result.SignatureVerified {
+			fmt.Println("Signature:       verified")
+		} else {
+			fmt.Println("Signature:       not checked (no signature published, or neither cosign nor minisign installed)")
+		}
+		fmt.Printf("Previous binary: %s (restore with 'hardn self-update --rollback')\n", result.PreviousPath)
+	}
+	return nil
+}
+
+// runSelfUpdateRollback restores the binary replaced by the last update
+func runSelfUpdateRollback(dryRun bool) error {
+	if dryRun {
+		fmt.Println("[DRY-RUN] Would restore the binary replaced by the last self-update")
+		return nil
+	}
+
+	kept, err := version.Rollback("")
+	if errors.Is(err, version.ErrNoPreviousBinary) {
+		return fmt.Errorf("nothing to roll back: no binary was kept by a previous self-update")
+	}
+	if err != nil {
+		return fmt.Errorf("rollback failed: %w", err)
+	}
+
+	fmt.Println("Restored the previous hardn binary")
+	fmt.Printf("The replaced binary is kept as %s\n", kept)
+	return nil
+}
+
+// verificationText describes what was verified before the download
+func verificationText(verification version.ReleaseVerification) string {
+	text := verification.Label()
+	if verification.Detail != "" {
+		text += " (" + verification.Detail + ")"
+	}
+	return text
+}
+
+// channelName returns the channel shown to the user
+func channelName(channel string) string {
+	if channel == "" {
+		return version.ChannelStable
+	}
+	return channel
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/style"
+	"github.com/spf13/cobra"
+)
+
+var selfTestJSON bool
+
+// SelfTestCmd returns the selftest command
+func SelfTestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "selftest",
+		Short: "Check that the configuration hardn manages still validates",
+		Long: `Run the validators of the configuration hardn manages, catching files that
+manual edits broke since hardn wrote them:
+
+  sudoers      visudo -c
+  ssh          sshd -t
+  firewall     ufw status, or firewall-cmd --check-config
+  dns          resolv.conf keywords and nameserver addresses
+  sysctl       every key of /etc/sysctl.conf and /etc/sysctl.d exists
+
+Validators that are not installed are skipped. The command exits with
+status 1 when a check fails, so a scheduled self-test shows up as a failed
+run; add the selftest task to the schedule to run it nightly.
+
+Examples:
+  sudo hardn selftest
+  sudo hardn selftest --json
+  sudo hardn schedule install --task selftest --task audit`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSelfTest(cmd)
+		},
+	}
+
+	cmd.Flags().BoolVar(&selfTestJSON, "json", false, "Output in JSON format")
+	return cmd
+}
+
+// runSelfTest executes the selftest command
+func runSelfTest(cmd *cobra.Command) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	report, err := ctx.serviceFactory().CreateSelfTestManager().RunSelfTest()
+	if err != nil {
+		return err
+	}
+
+	// Failures reach the log file even when nobody reads the output
+	failed := report.Failed()
+	for _, check := range failed {
+		logging.LogError("Self-test failed for %s (%s): %s", check.Name, check.Target, check.Detail)
+	}
+
+	if selfTestJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode self-test report: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		printSelfTest(report)
+	}
+
+	if len(failed) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// printSelfTest prints a line per check and a summary
+func printSelfTest(report *model.SelfTestReport) {
+	for _, check := range report.Checks {
+		symbol := style.Colored(style.Green, style.SymCheckMark)
+		switch check.Status {
+		case model.SelfTestFailed:
+			symbol = style.Colored(style.Red, style.SymCrossMark)
+		case model.SelfTestSkipped:
+			symbol = style.Dimmed(style.SymDash)
+		}
+
+		line := fmt.Sprintf("%s %-8s %s", symbol, check.Name, style.Dimmed(check.Target))
+		if check.Detail != "" {
+			line += ": " + check.Detail
+		}
+		fmt.Println(line)
+	}
+
+	if failed := report.Failed(); len(failed) > 0 {
+		fmt.Printf("\n%d of %d checks failed\n", len(failed), len(report.Checks))
+		return
+	}
+	fmt.Println("\nAll managed configuration validates")
+}
@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var sourcesMigrateTo string
+
+// SourcesCmd returns the sources command
+func SourcesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sources",
+		Short: "Show and update package sources",
+		Long: `Write the package repositories from the configuration without the
+interactive menu: debianRepos on Debian and Ubuntu, the main and community
+repositories (plus edge/testing with alpineTestingRepo) on Alpine. On
+Proxmox the Ceph and enterprise lists are updated as well. Rocky Linux,
+AlmaLinux and Fedora keep their repositories in /etc/yum.repos.d, which hardn
+leaves alone. Use --dry-run to preview a change.
+
+sourcesFormat picks one-line entries in sources.list or deb822 stanzas in
+/etc/apt/sources.list.d/debian.sources (ubuntu.sources on Ubuntu). The
+default, auto, follows the host: Debian 13 and Ubuntu 24.04 install with
+deb822 files, and "hardn sources migrate" converts an older host.`,
+	}
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the repositories that update would write",
+		Long: `Print the configured repositories with the release codename filled in,
+exactly as "hardn sources update" would write them.
+
+Example:
+  hardn sources show`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := loadCommandContext(cmd)
+			if err != nil {
+				return err
+			}
+			return printSources(ctx, "")
+		},
+	}
+
+	updateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Write the configured repositories",
+		Long: `Validate and write the configured repositories. On Debian and Ubuntu the
+previous sources.list is kept as sources.list.bak. In deb822 format the
+repositories go to the release .sources file, kept as .sources.bak, and
+sources.list is left with a comment pointing to it. The list in the other
+format is moved aside as a .bak file so no repository is listed twice.
+
+Examples:
+  sudo hardn sources update --dry-run
+  sudo hardn sources update`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSourcesUpdate(cmd)
+		},
+	}
+
+	keysCmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Install the repository keys from repositoryKeys",
+		Long: `Download each key of repositoryKeys and install it once it matches its
+fingerprint. On Debian and Ubuntu the key goes to /etc/apt/keyrings and its
+repos are written to /etc/apt/sources.list.d/NAME.sources in deb822 format
+with Signed-By naming the key, so APT trusts it for those repositories only.
+A NAME.list file from an earlier setup is removed. On Alpine the key goes to
+/etc/apk/keys and the fingerprint is the SHA-256 of the key file.
+
+Keys trusted for every repository are reported by "hardn audit sources".
+
+Examples:
+  sudo hardn sources keys --dry-run
+  sudo hardn sources keys`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSourcesKeys(cmd)
+		},
+	}
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Convert the repository lists to deb822 or one-line format",
+		Long: `Convert sources.list and the lists in sources.list.d to deb822 .sources
+files, or back to one-line entries with --to list. sources.list becomes the
+release file, such as /etc/apt/sources.list.d/debian.sources, and keeps a
+comment pointing to it; other lists keep their name, so ceph.list becomes
+ceph.sources. The lists replaced are kept as .bak files, which APT ignores.
+
+Commented-out entries, such as the Proxmox enterprise repository, become
+disabled stanzas and other comments stay in the backups. Nothing is written
+when a list cannot be converted, such as a deb822 stanza with an inline key.
+
+Examples:
+  sudo hardn sources migrate --dry-run
+  sudo hardn sources migrate
+  sudo hardn sources migrate --to list`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSourcesMigrate(cmd)
+		},
+	}
+	migrateCmd.Flags().StringVar(&sourcesMigrateTo, "to", model.SourcesFormatDeb822, "Format to convert to (deb822, list)")
+
+	cmd.AddCommand(showCmd)
+	cmd.AddCommand(updateCmd)
+	cmd.AddCommand(keysCmd)
+	cmd.AddCommand(migrateCmd)
+	return cmd
+}
+
+// printSources prints the repositories that would be written under a
+// heading naming the file, which starts with prefix
+func printSources(ctx *commandContext, prefix string) error {
+	if err := checkManagedSources(ctx); err != nil {
+		return err
+	}
+
+	if ctx.osInfo.OsType == "alpine" {
+		version := ctx.osInfo.OsVersion
+		if idx := strings.LastIndex(version, "."); idx != -1 {
+			version = version[:idx]
+		}
+		fmt.Printf("%s/etc/apk/repositories:\n", prefix)
+		fmt.Printf("  https://dl-cdn.alpinelinux.org/alpine/v%s/main\n", version)
+		fmt.Printf("  https://dl-cdn.alpinelinux.org/alpine/v%s/community\n", version)
+		if ctx.cfg.AlpineTestingRepo {
+			fmt.Println("  https://dl-cdn.alpinelinux.org/alpine/edge/testing")
+		}
+		return nil
+	}
+
+	if len(ctx.cfg.DebianRepos) == 0 {
+		return fmt.Errorf("no repositories configured (debianRepos in the configuration)")
+	}
+
+	packageManager := ctx.serviceFactory().CreatePackageManager()
+	repos := make([]string, 0, len(ctx.cfg.DebianRepos))
+	for _, repo := range ctx.cfg.DebianRepos {
+		if err := packageManager.ValidateDebianRepo(repo); err != nil {
+			return err
+		}
+		repos = append(repos, strings.ReplaceAll(repo, "CODENAME", ctx.osInfo.OsCodename))
+	}
+
+	format, err := packageManager.SourcesFormat()
+	if err != nil {
+		return err
+	}
+	if format != model.SourcesFormatDeb822 {
+		fmt.Printf("%s%s:\n", prefix, model.AptSourcesListPath)
+		for _, repo := range repos {
+			fmt.Printf("  %s\n", repo)
+		}
+		return nil
+	}
+
+	var sources []model.AptSource
+	for _, repo := range repos {
+		source, err := model.ParseAptSourceEntry(repo)
+		if err != nil {
+			return err
+		}
+		if source != nil {
+			sources = append(sources, *source)
+		}
+	}
+	content, err := model.FormatAptSources(sources, format, "")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s%s:\n", prefix, model.AptReleaseSourcesPath(ctx.osInfo.OsType))
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		if line == "" {
+			fmt.Println()
+			continue
+		}
+		fmt.Printf("  %s\n", line)
+	}
+	return nil
+}
+
+// checkManagedSources refuses distributions whose repositories hardn does
+// not write
+func checkManagedSources(ctx *commandContext) error {
+	if model.IsRHELFamily(ctx.osInfo.OsType) {
+		return fmt.Errorf("package sources on %s come from %s and are not managed by hardn",
+			ctx.osInfo.OsType, model.YumReposDir)
+	}
+	return nil
+}
+
+// runSourcesUpdate executes the sources update command
+func runSourcesUpdate(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireSupportedOS("Package source management"); err != nil {
+		return err
+	}
+	if err := checkManagedSources(ctx); err != nil {
+		return err
+	}
+
+	if ctx.dryRun {
+		if err := printSources(ctx, "[DRY-RUN] Would write "); err != nil {
+			return err
+		}
+		if ctx.osInfo.OsType != "alpine" && ctx.osInfo.IsProxmox {
+			format, err := ctx.serviceFactory().CreatePackageManager().SourcesFormat()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("[DRY-RUN] Would update the Proxmox Ceph and enterprise repositories (%s and %s)\n",
+				model.AptListPath("ceph", format), model.AptListPath("pve-enterprise", format))
+		}
+		return nil
+	}
+
+	packageManager := ctx.serviceFactory().CreatePackageManager()
+	if ctx.osInfo.OsType != "alpine" {
+		// Never replace sources.list with an empty file
+		if len(ctx.cfg.DebianRepos) == 0 {
+			return fmt.Errorf("no repositories configured (debianRepos in the configuration)")
+		}
+		for _, repo := range ctx.cfg.DebianRepos {
+			if err := packageManager.ValidateDebianRepo(repo); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := packageManager.UpdatePackageSources(); err != nil {
+		return fmt.Errorf("failed to update package sources: %w", err)
+	}
+	logging.LogSuccess("Package sources updated")
+	fmt.Println("Package sources updated")
+
+	// Proxmox keeps its Ceph and enterprise repositories in separate lists
+	if ctx.osInfo.OsType != "alpine" && ctx.osInfo.IsProxmox {
+		if err := packageManager.UpdateProxmoxSources(); err != nil {
+			return fmt.Errorf("failed to update Proxmox sources: %w", err)
+		}
+		logging.LogSuccess("Proxmox sources updated")
+		fmt.Println("Proxmox sources updated")
+	}
+
+	return nil
+}
+
+// runSourcesKeys executes the sources keys command
+func runSourcesKeys(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireSupportedOS("Package source management"); err != nil {
+		return err
+	}
+	if err := checkManagedSources(ctx); err != nil {
+		return err
+	}
+	if len(ctx.cfg.RepositoryKeys) == 0 {
+		return fmt.Errorf("no repository keys configured (repositoryKeys in the configuration)")
+	}
+
+	// Check every entry before anything is downloaded
+	for _, key := range ctx.cfg.RepositoryKeys {
+		if err := key.Key().Validate(); err != nil {
+			return err
+		}
+	}
+
+	trustManager := ctx.serviceFactory().CreateRepositoryTrustManager()
+	for _, key := range ctx.cfg.RepositoryKeys {
+		result, err := trustManager.InstallKey(key.Key())
+		if err != nil {
+			return fmt.Errorf("failed to install repository key %s: %w", key.Name, err)
+		}
+		logging.LogSuccess("Repository key %s installed in %s", key.Name, result.KeyringPath)
+		fmt.Printf("%s: %s\n", key.Name, result.KeyringPath)
+		if result.SourcesPath != "" {
+			fmt.Printf("  repositories: %s\n", result.SourcesPath)
+		}
+		if result.Replaced != "" {
+			fmt.Printf("  replaced: %s\n", result.Replaced)
+		}
+	}
+	return nil
+}
+
+// runSourcesMigrate executes the sources migrate command
+func runSourcesMigrate(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireSupportedOS("Package source management"); err != nil {
+		return err
+	}
+	if ctx.osInfo.OsType == "alpine" || model.IsRHELFamily(ctx.osInfo.OsType) {
+		return fmt.Errorf("one-line and deb822 repository lists are used by APT on Debian and Ubuntu only")
+	}
+
+	migrations, err := ctx.serviceFactory().CreatePackageManager().MigrateSources(sourcesMigrateTo)
+	for _, migration := range migrations {
+		if !ctx.dryRun {
+			logging.LogSuccess("Migrated %s to %s", migration.From, migration.To)
+		}
+		fmt.Printf("%s -> %s (%d repositories)\n", migration.From, migration.To, migration.Sources)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to migrate package sources: %w", err)
+	}
+	if len(migrations) == 0 {
+		fmt.Printf("Every repository list is already in %s format\n", sourcesMigrateTo)
+		return nil
+	}
+
+	// A forced format would write the configured repositories back in the old one
+	if format := model.ParseSourcesFormat(ctx.cfg.SourcesFormat); format != model.SourcesFormatAuto && format != sourcesMigrateTo {
+		fmt.Printf("Set sourcesFormat to %s or auto so \"hardn sources update\" keeps this format\n", sourcesMigrateTo)
+	}
+	return nil
+}
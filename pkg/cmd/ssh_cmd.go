@@ -0,0 +1,559 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+	"github.com/abbott/hardn/pkg/infrastructure"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sshSave          bool
+	sshNoFirewall    bool
+	addKeyFile       string
+	sshHardenPort    int
+	sshListen        []string
+	sshAllowUsers    []string
+	sshAllowAllUsers bool
+	sshProfile       string
+	sshConsolidate   bool
+)
+
+// SSHCmd returns the ssh command
+func SSHCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ssh",
+		Short: "Change individual SSH settings",
+		Long: `Apply a single SSH change without running the other hardening steps.
+
+Settings that are not changed by a subcommand are taken from the
+configuration file, so the managed sshd configuration stays consistent with
+what "hardn -r" would write. Use --dry-run to preview a change.`,
+	}
+
+	disableRootCmd := &cobra.Command{
+		Use:   "disable-root",
+		Short: "Disable SSH login for root",
+		Long: `Set PermitRootLogin to no and restart the SSH service.
+
+Make sure a non-root user with sudo can log in over SSH first.
+
+Example:
+  sudo hardn ssh disable-root`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSSHDisableRoot(cmd)
+		},
+	}
+
+	setPortCmd := &cobra.Command{
+		Use:   "set-port PORT",
+		Short: "Move SSH to another port",
+		Long: `Change the port SSH listens on and restart the SSH service.
+
+When the firewall is enabled the new port is allowed before SSH moves, so
+the current session's replacement can connect. The old port's rule is left
+in place; remove it once the new port is confirmed to work. Unless
+sshSafety is disabled, the change is rolled back when SSH does not answer
+on the new port or the new port is not confirmed in time. With --save the
+port is also written to the configuration file.
+
+Examples:
+  sudo hardn ssh set-port 2208
+  sudo hardn ssh set-port 2208 --save`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			port, err := strconv.Atoi(args[0])
+			if err != nil || port < 1 || port > 65535 {
+				return fmt.Errorf("invalid port: %s (use 1-65535)", args[0])
+			}
+			return runSSHSetPort(cmd, port)
+		},
+	}
+	setPortCmd.Flags().BoolVar(&sshSave, "save", false, "Write the new port to the configuration file")
+	setPortCmd.Flags().BoolVar(&sshNoFirewall, "no-firewall", false, "Do not add a firewall rule for the new port")
+
+	addKeyCmd := &cobra.Command{
+		Use:   "add-key USER [PUBLIC_KEY]",
+		Short: "Authorize a public key for a user",
+		Long: `Append a public key to a user's authorized_keys. The key is given as an
+argument or read from a file with --file; keys already present are left
+alone.
+
+Examples:
+  sudo hardn ssh add-key george "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAI... george@example.com"
+  sudo hardn ssh add-key george --file /tmp/george.pub`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAddKey(cmd, args)
+		},
+	}
+	addKeyCmd.Flags().StringVar(&addKeyFile, "file", "", "Read the public key from a file")
+
+	hardenCmd := &cobra.Command{
+		Use:   "harden",
+		Short: "Apply the hardened SSH configuration",
+		Long: `Write the hardened SSH configuration: key authentication only, no root
+login and an AllowUsers list, plus the sshProfile hardening profile. Flags
+override the port, listen addresses, allowed users and profile from the
+configuration file.
+
+Examples:
+  sudo hardn ssh harden
+  sudo hardn ssh harden --port 2208 --listen 10.0.0.5 --allow-user george --profile strict`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSSHHarden(cmd)
+		},
+	}
+	hardenCmd.Flags().IntVar(&sshHardenPort, "port", 0, "SSH port (default: sshPort from the configuration)")
+	hardenCmd.Flags().StringSliceVar(&sshListen, "listen", nil, "Address to listen on; repeat for several")
+	hardenCmd.Flags().StringSliceVar(&sshAllowUsers, "allow-user", nil, "User allowed to log in; repeat for several")
+	hardenCmd.Flags().BoolVar(&sshAllowAllUsers, "allow-all-users", false, "Do not restrict logins with AllowUsers")
+	hardenCmd.Flags().BoolVar(&sshNoFirewall, "no-firewall", false, "Do not add a firewall rule for the port")
+	hardenCmd.Flags().StringVar(&sshProfile, "profile", "", "Hardening profile: baseline, strict or paranoid (default: sshProfile from the configuration)")
+
+	profileCmd := &cobra.Command{
+		Use:   "profile NAME",
+		Short: "Apply an SSH hardening profile",
+		Long: `Apply the baseline, strict or paranoid hardening profile, which sets
+MaxAuthTries, LoginGraceTime, client keepalives, X11 and TCP forwarding and
+the allowed ciphers, MACs and key exchange algorithms. Other SSH settings
+are left as they are. With --save the profile is also written to the
+configuration file.
+
+Stricter profiles may lock out older clients; test a new connection before
+closing the current session.
+
+Examples:
+  sudo hardn ssh profile strict
+  sudo hardn ssh profile baseline --save`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSSHProfile(cmd, strings.ToLower(args[0]))
+		},
+	}
+	profileCmd.Flags().BoolVar(&sshSave, "save", false, "Write the profile to the configuration file")
+
+	conflictsCmd := &cobra.Command{
+		Use:   "conflicts",
+		Short: "Find sshd settings other configuration files override",
+		Long: `List the SSH servers running and the directives that sshd_config and the
+files it includes set to different values. sshd uses the first value it
+reads for most directives, and drop-ins in sshd_config.d are read in name
+order, so 50-cloud-init.conf can override hardn.conf. Ports, listen
+addresses and user lists add up across files instead.
+
+With --consolidate, lines of other files that contradict hardn's file are
+commented out, leaving hardn's file the only one in effect for its
+directives. The files are restored if sshd -t rejects the result.
+
+Examples:
+  sudo hardn ssh conflicts
+  sudo hardn ssh conflicts --consolidate --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSSHConflicts(cmd)
+		},
+	}
+	conflictsCmd.Flags().BoolVar(&sshConsolidate, "consolidate", false, "Disable the lines that contradict hardn's file")
+
+	cmd.AddCommand(disableRootCmd)
+	cmd.AddCommand(setPortCmd)
+	cmd.AddCommand(addKeyCmd)
+	cmd.AddCommand(hardenCmd)
+	cmd.AddCommand(profileCmd)
+	cmd.AddCommand(conflictsCmd)
+	cmd.AddCommand(sshKeysCmd())
+	return cmd
+}
+
+// sshCommandContext adds the SSH manager to the shared command context
+type sshCommandContext struct {
+	*commandContext
+	ssh *application.SSHManager
+}
+
+// newSSHCommandContext prepares the command context and wires the SSH manager
+func newSSHCommandContext(cmd *cobra.Command) (*sshCommandContext, error) {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	sshRepo := secondary.NewFileSSHRepository(ctx.provider.FS, ctx.provider.Commander, ctx.osInfo.OsType)
+	sshService := service.NewSSHServiceImpl(sshRepo, domainOSInfo(ctx.osInfo))
+
+	return &sshCommandContext{
+		commandContext: ctx,
+		ssh:            application.NewSSHManager(sshService),
+	}, nil
+}
+
+// runSSHDisableRoot executes the ssh disable-root command
+func runSSHDisableRoot(cmd *cobra.Command) error {
+	ctx, err := newSSHCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	enabled, err := ctx.ssh.IsRootLoginEnabled()
+	if err == nil && !enabled {
+		fmt.Println("Root SSH login is already disabled")
+		return nil
+	}
+
+	// In dry-run mode the provider prints the change as a diff
+	if err := ctx.ssh.DisableRootSSH(); err != nil {
+		return fmt.Errorf("failed to disable root SSH login: %w", err)
+	}
+	if !ctx.dryRun {
+		fmt.Println("Root SSH login disabled")
+	}
+	return nil
+}
+
+// runSSHSetPort executes the ssh set-port command
+func runSSHSetPort(cmd *cobra.Command, port int) error {
+	ctx, err := newSSHCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Keep the current root login setting rather than the configured one
+	permitRootLogin := ctx.cfg.PermitRootLogin
+	if enabled, err := ctx.ssh.IsRootLoginEnabled(); err == nil {
+		permitRootLogin = enabled
+	}
+
+	if err := allowSSHPort(ctx, port); err != nil {
+		return err
+	}
+
+	err = ctx.ssh.ConfigureSSH(
+		port,
+		ctx.cfg.SshListenAddresses,
+		permitRootLogin,
+		ctx.cfg.SshAllowedUsers,
+		[]string{ctx.cfg.SshKeyPath},
+		ctx.cfg.Username,
+		ctx.cfg.SshAllowAllUsers,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to change SSH port: %w", err)
+	}
+	if !ctx.dryRun {
+		fmt.Printf("SSH now listens on port %d\n", port)
+	}
+
+	if sshSave {
+		ctx.cfg.SshPort = port
+		return saveSSHConfig(ctx, fmt.Sprintf("sshPort %d", port))
+	}
+	return nil
+}
+
+// runAddKey executes the ssh add-key and user add-key commands
+func runAddKey(cmd *cobra.Command, args []string) error {
+	username := args[0]
+
+	var publicKey string
+	switch {
+	case len(args) == 2 && addKeyFile != "":
+		return fmt.Errorf("give the key as an argument or with --file, not both")
+	case len(args) == 2:
+		publicKey = args[1]
+	case addKeyFile != "":
+		data, err := os.ReadFile(addKeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", addKeyFile, err)
+		}
+		publicKey = string(data)
+	default:
+		return fmt.Errorf("no public key given; pass it as an argument or with --file")
+	}
+
+	publicKey = strings.TrimSpace(publicKey)
+	key, err := model.ParseAuthorizedKey(publicKey)
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+
+	ctx, err := newSSHCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	if ctx.dryRun {
+		fmt.Printf("[DRY-RUN] Would authorize %s key %s for %s\n", key.KeyType, key.Fingerprint, username)
+		return nil
+	}
+
+	if err := newUserManager(ctx.osInfo.OsType).AddSSHKey(username, publicKey); err != nil {
+		return err
+	}
+
+	fmt.Printf("Authorized %s key %s for %s\n", key.KeyType, key.Fingerprint, username)
+	return nil
+}
+
+// runSSHHarden executes the ssh harden command
+func runSSHHarden(cmd *cobra.Command) error {
+	ctx, err := newSSHCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	port := ctx.cfg.SshPort
+	if cmd.Flags().Changed("port") {
+		if sshHardenPort < 1 || sshHardenPort > 65535 {
+			return fmt.Errorf("invalid port: %d (use 1-65535)", sshHardenPort)
+		}
+		port = sshHardenPort
+	}
+
+	listenAddresses := ctx.cfg.SshListenAddresses
+	if len(sshListen) > 0 {
+		listenAddresses = sshListen
+	}
+	for _, addr := range listenAddresses {
+		if err := ctx.ssh.ValidateListenAddress(addr); err != nil {
+			return err
+		}
+	}
+
+	allowedUsers := ctx.cfg.SshAllowedUsers
+	if len(sshAllowUsers) > 0 {
+		allowedUsers = sshAllowUsers
+	}
+	allowAllUsers := ctx.cfg.SshAllowAllUsers || sshAllowAllUsers
+
+	profile := ctx.cfg.SshProfile
+	if cmd.Flags().Changed("profile") {
+		profile = strings.ToLower(sshProfile)
+	}
+	if err := ctx.ssh.ValidateProfile(profile); err != nil {
+		return err
+	}
+
+	// Resolve the users up front so a missing AllowUsers list fails early
+	resolvedUsers, err := ctx.ssh.ResolveAllowedUsers(allowedUsers, ctx.cfg.Username, allowAllUsers)
+	if err != nil {
+		return err
+	}
+
+	if err := allowSSHPort(ctx, port); err != nil {
+		return err
+	}
+
+	if ctx.dryRun && len(resolvedUsers) > 0 {
+		fmt.Printf("[DRY-RUN] AllowUsers resolves to %s\n", strings.Join(resolvedUsers, " "))
+	}
+
+	err = ctx.ssh.ConfigureSSH(
+		port,
+		listenAddresses,
+		false,
+		allowedUsers,
+		[]string{ctx.cfg.SshKeyPath},
+		ctx.cfg.Username,
+		allowAllUsers,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to harden SSH: %w", err)
+	}
+	if profile != "" {
+		if err := ctx.ssh.ApplyProfile(profile); err != nil {
+			return fmt.Errorf("failed to apply SSH profile: %w", err)
+		}
+		if !ctx.dryRun {
+			fmt.Printf("Applied the %s SSH hardening profile\n", profile)
+		}
+	}
+
+	if !ctx.dryRun {
+		fmt.Printf("SSH hardened on port %d\n", port)
+	}
+	return nil
+}
+
+// runSSHProfile executes the ssh profile command
+func runSSHProfile(cmd *cobra.Command, profile string) error {
+	ctx, err := newSSHCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.ssh.ValidateProfile(profile); err != nil {
+		return err
+	}
+
+	if err := ctx.ssh.ApplyProfile(profile); err != nil {
+		return fmt.Errorf("failed to apply SSH profile: %w", err)
+	}
+	if !ctx.dryRun {
+		fmt.Printf("SSH hardening profile set to %s\n", profile)
+	}
+
+	if sshSave {
+		ctx.cfg.SshProfile = profile
+		return saveSSHConfig(ctx, "sshProfile "+profile)
+	}
+	return nil
+}
+
+// runSSHConflicts executes the ssh conflicts command
+func runSSHConflicts(cmd *cobra.Command) error {
+	if sshConsolidate {
+		ctx, err := newSSHCommandContext(cmd)
+		if err != nil {
+			return err
+		}
+		disabled, err := ctx.ssh.ConsolidateConfig()
+		if err != nil {
+			return fmt.Errorf("failed to consolidate the SSH configuration: %w", err)
+		}
+		if len(disabled) == 0 {
+			fmt.Println("No lines contradict hardn's SSH configuration")
+			return nil
+		}
+		for _, source := range disabled {
+			fmt.Printf("Disabled %s:%d %s %s\n", source.File, source.Line, source.Directive, source.Value)
+		}
+		return nil
+	}
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	report, err := ctx.serviceFactory().CreateSSHManager().FindConfigConflicts()
+	if err != nil {
+		return err
+	}
+	printSSHConflicts(report)
+	return nil
+}
+
+// printSSHConflicts prints the SSH servers running and the conflicting lines
+func printSSHConflicts(report *model.SSHConflictReport) {
+	if len(report.Daemons) > 1 {
+		fmt.Printf("%d SSH servers are running:\n", len(report.Daemons))
+		for _, daemon := range report.Daemons {
+			fmt.Printf("  %-8d %s\n", daemon.PID, daemon.Command)
+		}
+		fmt.Println()
+	}
+
+	if len(report.Conflicts) == 0 {
+		fmt.Println("No conflicting sshd directives")
+		return
+	}
+
+	for _, conflict := range report.Conflicts {
+		effective := conflict.Effective
+		if effective == "" {
+			effective = "unknown"
+		}
+		fmt.Printf("%s (in effect: %s)\n", conflict.Directive, effective)
+		for _, source := range conflict.Sources {
+			marker := " "
+			if source.File == report.ManagedFile {
+				marker = "*"
+			}
+			fmt.Printf("  %s %s:%d %s\n", marker, source.File, source.Line, source.Value)
+		}
+	}
+	fmt.Printf("\n* hardn's file; 'hardn ssh conflicts --consolidate' disables the other lines\n")
+}
+
+// WarnSSHConflicts warns about lines of other sshd configuration files that
+// contradict hardn's file. Failures to read the configuration are ignored.
+func WarnSSHConflicts(factory *infrastructure.ServiceFactory) {
+	report, err := factory.CreateSSHManager().FindConfigConflicts()
+	if err != nil {
+		return
+	}
+
+	for _, conflict := range report.Conflicts {
+		if !conflict.Managed {
+			continue
+		}
+		for _, source := range conflict.Sources {
+			if source.File != report.ManagedFile {
+				logging.LogWarning("%s:%d sets %s %s, contradicting %s; see 'hardn ssh conflicts'",
+					source.File, source.Line, source.Directive, source.Value, report.ManagedFile)
+			}
+		}
+	}
+	if len(report.Daemons) > 1 {
+		logging.LogWarning("%d SSH servers are running; see 'hardn ssh conflicts'", len(report.Daemons))
+	}
+}
+
+// allowSSHPort adds a firewall rule for port when the firewall is enabled
+// and the port is not already allowed. With port knocking, knockd is moved
+// to the port instead.
+func allowSSHPort(ctx *sshCommandContext, port int) error {
+	if sshNoFirewall {
+		return nil
+	}
+
+	firewallManager := newFirewallManager(ctx.provider, ctx.osInfo)
+	_, enabled, _, rules, err := firewallManager.GetFirewallStatus()
+	if err != nil || !enabled {
+		return nil
+	}
+
+	ssh := ctx.cfg.SSHFirewallPolicy()
+	ssh.Port = port
+	if ssh.Knocking != nil {
+		if err := firewallManager.ConfigurePortKnocking(ssh); err != nil {
+			return fmt.Errorf("failed to move port knocking to port %d: %w", port, err)
+		}
+		return nil
+	}
+	if portAllowed(rules, port) {
+		return nil
+	}
+
+	rule, _ := ssh.Rule()
+	if ctx.dryRun {
+		fmt.Printf("[DRY-RUN] Would add %s to the firewall\n", rule.Spec())
+		return nil
+	}
+
+	if err := firewallManager.AddSSHRule(ssh); err != nil {
+		return fmt.Errorf("failed to allow port %d through the firewall: %w", port, err)
+	}
+	fmt.Printf("Added %s to the firewall\n", rule.Spec())
+	return nil
+}
+
+// saveSSHConfig writes the updated configuration back to the file it was
+// loaded from
+func saveSSHConfig(ctx *sshCommandContext, setting string) error {
+	path, found := config.FindConfigFile(ctx.configFile)
+	if !found {
+		return fmt.Errorf("no configuration file found to save to")
+	}
+
+	if ctx.dryRun {
+		fmt.Printf("[DRY-RUN] Would save %s to %s\n", setting, path)
+		return nil
+	}
+
+	if err := config.SaveConfig(ctx.cfg, path); err != nil {
+		return err
+	}
+	fmt.Printf("Saved %s to %s\n", setting, path)
+	return nil
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sshKeysFormat  string
+	sshKeysUser    string
+	sshKeysExpired bool
+	sshKeysClear   bool
+)
+
+// sshKeysCmd returns the ssh keys command
+func sshKeysCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "List every authorized SSH key with its strength and expiry",
+		Long: `List the keys in the authorized_keys of root and every local user with
+their type, size, fingerprint and comment. DSA keys and RSA keys smaller
+than 3072 bits are flagged as weak.
+
+Keys can be tagged with an expiry date with "hardn ssh keys expire".
+Expired keys are marked here, reported by "hardn audit", and removed by
+"hardn ssh keys prune" or the ssh-keys scheduled task.
+
+Examples:
+  sudo hardn ssh keys
+  sudo hardn ssh keys --user george --format json
+  sudo hardn ssh keys --expired`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSSHKeys()
+		},
+	}
+	cmd.Flags().StringVarP(&sshKeysFormat, "format", "O", "text", "Output format (text, json)")
+	cmd.Flags().StringVar(&sshKeysUser, "user", "", "Only list the keys of this user")
+	cmd.Flags().BoolVar(&sshKeysExpired, "expired", false, "Only list expired keys")
+
+	expireCmd := &cobra.Command{
+		Use:   "expire USER FINGERPRINT [DATE]",
+		Short: "Tag a key with an expiry date",
+		Long: `Tag one of a user's keys with the date it expires, given as YYYY-MM-DD or
+as a number of days from now such as 90d. A date expires the key at the
+start of that day. The key is found by the SHA256 fingerprint that
+"hardn ssh keys" shows. With --clear the key no longer expires.
+
+Expiry dates are kept in /var/lib/hardn/ssh-key-expiry.json; the key
+itself is not changed until it is pruned.
+
+Examples:
+  sudo hardn ssh keys expire george SHA256:5bEaQPr9gapqp0X1EFLCEhe0M15R5jNsd0XvN9kXysM 2026-12-31
+  sudo hardn ssh keys expire contractor SHA256:5bEaQPr9gapqp0X1EFLCEhe0M15R5jNsd0XvN9kXysM 90d
+  sudo hardn ssh keys expire george SHA256:5bEaQPr9gapqp0X1EFLCEhe0M15R5jNsd0XvN9kXysM --clear`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSSHKeysExpire(cmd, args)
+		},
+	}
+	expireCmd.Flags().BoolVar(&sshKeysClear, "clear", false, "Remove the key's expiry date")
+
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove expired keys from authorized_keys",
+		Long: `Remove every key past its expiry date from the user's authorized_keys.
+Make sure another way to log in remains for the users affected. The
+removal is recorded as a run that "hardn rollback" can revert. Use
+--dry-run to list the keys that would be removed.
+
+Examples:
+  sudo hardn ssh keys prune --dry-run
+  sudo hardn ssh keys prune`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSSHKeysPrune(cmd)
+		},
+	}
+
+	cmd.AddCommand(expireCmd)
+	cmd.AddCommand(pruneCmd)
+	return cmd
+}
+
+// newSSHKeyInventoryManager wires the SSH key inventory manager for the
+// commands that only read keys or hardn's state
+func newSSHKeyInventoryManager(osType string) *application.SSHKeyInventoryManager {
+	provider := interfaces.NewProvider()
+	userRepo := secondary.NewOSUserRepository(provider.FS, provider.Commander, osType)
+	expiryRepo := secondary.NewFileSSHKeyExpiryRepository(provider.FS, model.SSHKeyExpiryPath)
+	return application.NewSSHKeyInventoryManager(service.NewSSHKeyInventoryServiceImpl(userRepo, expiryRepo))
+}
+
+// runSSHKeys executes the ssh keys command
+func runSSHKeys() error {
+	if sshKeysFormat != "text" && sshKeysFormat != "json" {
+		return fmt.Errorf("unsupported format: %s (use text or json)", sshKeysFormat)
+	}
+
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	osInfo, err := osdetect.DetectOS()
+	if err != nil {
+		return fmt.Errorf("failed to detect OS: %w", err)
+	}
+
+	entries, err := newSSHKeyInventoryManager(osInfo.OsType).Inventory()
+	if err != nil {
+		return err
+	}
+
+	keys := []model.SSHKeyInventoryEntry{}
+	for _, entry := range entries {
+		if sshKeysUser != "" && entry.Username != sshKeysUser {
+			continue
+		}
+		if sshKeysExpired && !entry.Expired {
+			continue
+		}
+		keys = append(keys, entry)
+	}
+
+	if sshKeysFormat == "json" {
+		data, err := json.MarshalIndent(keys, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode keys: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(keys) == 0 {
+		fmt.Println("No authorized SSH keys found")
+		return nil
+	}
+
+	weak, expired := 0, 0
+	fmt.Printf("%-16s %-20s %-5s %-51s %-10s %s\n", "USER", "TYPE", "BITS", "FINGERPRINT", "EXPIRES", "COMMENT")
+	for _, key := range keys {
+		bits := "-"
+		if key.Bits > 0 {
+			bits = fmt.Sprint(key.Bits)
+		}
+		expires := "-"
+		if key.Expires != nil {
+			expires = key.Expires.Format(time.DateOnly)
+		}
+		fmt.Printf("%-16s %-20s %-5s %-51s %-10s %s\n", key.Username, key.KeyType, bits, key.Fingerprint, expires, key.Comment)
+
+		if key.Weak != "" {
+			weak++
+			fmt.Printf("  WEAK: %s\n", key.Weak)
+		}
+		if key.Expired {
+			expired++
+			fmt.Printf("  EXPIRED: since %s\n", key.Expires.Format(time.DateOnly))
+		}
+	}
+
+	fmt.Printf("\n%d key(s), %d weak, %d expired\n", len(keys), weak, expired)
+	return nil
+}
+
+// runSSHKeysExpire executes the ssh keys expire command
+func runSSHKeysExpire(cmd *cobra.Command, args []string) error {
+	username, fingerprint := args[0], args[1]
+	if sshKeysClear == (len(args) == 3) {
+		return fmt.Errorf("give either an expiry date or --clear")
+	}
+
+	osInfo, err := osdetect.DetectOS()
+	if err != nil {
+		return fmt.Errorf("failed to detect OS: %w", err)
+	}
+	if err := escalate(); err != nil {
+		return err
+	}
+	manager := newSSHKeyInventoryManager(osInfo.OsType)
+
+	if sshKeysClear {
+		cleared, err := manager.ClearExpiry(username, fingerprint)
+		if err != nil {
+			return err
+		}
+		if !cleared {
+			fmt.Printf("Key %s of %s has no expiry date\n", fingerprint, username)
+			return nil
+		}
+		fmt.Printf("Key %s of %s no longer expires\n", fingerprint, username)
+		return nil
+	}
+
+	if flagEnabled(cmd, "dry-run") {
+		fmt.Printf("[DRY-RUN] Would expire key %s of %s on %s\n", fingerprint, username, args[2])
+		return nil
+	}
+
+	expires, err := manager.SetExpiry(username, fingerprint, args[2])
+	if err != nil {
+		return err
+	}
+	logging.LogSuccess("Key %s of %s expires %s", fingerprint, username, expires.Format(time.RFC3339))
+	fmt.Printf("Key %s of %s expires %s\n", fingerprint, username, expires.Format("2006-01-02 15:04 MST"))
+	return nil
+}
+
+// runSSHKeysPrune executes the ssh keys prune command
+func runSSHKeysPrune(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	removed, err := ctx.serviceFactory().CreateSSHKeyInventoryManager().RemoveExpired(ctx.dryRun)
+	for _, key := range removed {
+		if ctx.dryRun {
+			fmt.Printf("[DRY-RUN] Would remove key %s (%s) from %s, expired %s\n",
+				key.Fingerprint, key.Comment, key.Username, key.Expires.Format(time.DateOnly))
+			continue
+		}
+		logging.LogSuccess("Removed expired key %s from %s", key.Fingerprint, key.Username)
+		fmt.Printf("Removed key %s (%s) from %s, expired %s\n",
+			key.Fingerprint, key.Comment, key.Username, key.Expires.Format(time.DateOnly))
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(removed) == 0 {
+		fmt.Println("No expired SSH keys")
+	}
+	return nil
+}
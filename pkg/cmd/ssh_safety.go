@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/infrastructure"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/osdetect"
+)
+
+// safety is the revert timer guarding this invocation's changes to sshd
+// and the firewall, once one is armed
+var (
+	safetyMu sync.Mutex
+	safety   *armedSafety
+)
+
+// armedSafety is a revert timer waiting for the run to be confirmed
+type armedSafety struct {
+	runID       string
+	revertAfter time.Duration
+	manager     *application.SSHSafetyManager
+	stop        chan struct{} // stops the keep-alive once the run is done
+	cfg         *config.Config
+	osInfo      *osdetect.OSInfo
+}
+
+// guardSSHChanges arms the revert timer before the run's first change to
+// sshd or the firewall, unless sshSafety is disabled
+func guardSSHChanges(journal *secondary.RunJournal, cfg *config.Config, osInfo *osdetect.OSInfo) {
+	if cfg == nil || osInfo == nil || !cfg.SshSafety.Enabled {
+		return
+	}
+
+	failed := false
+	journal.GuardChanges(func(runID string) {
+		safetyMu.Lock()
+		defer safetyMu.Unlock()
+		if safety != nil || failed {
+			return
+		}
+
+		// The timer must not be recorded as a change of the run it reverts
+		manager := infrastructure.NewServiceFactory(interfaces.NewProvider(), osInfo).CreateSSHSafetyManager()
+		revertAfter, err := manager.Arm(runID, time.Duration(cfg.SshSafety.RevertAfter)*time.Second)
+		if err != nil {
+			failed = true
+			logging.LogWarning("Could not arm the SSH safety timer: %v; changes to sshd and the firewall will not be rolled back automatically", err)
+			return
+		}
+		logging.LogInfo("Changes to sshd or the firewall follow; they are rolled back %s after the run unless SSH is confirmed to work", revertAfter)
+
+		safety = &armedSafety{
+			runID:       runID,
+			revertAfter: revertAfter,
+			manager:     manager,
+			stop:        make(chan struct{}),
+			cfg:         cfg,
+			osInfo:      osInfo,
+		}
+		go safety.keepAlive()
+	}, model.AreaSSH, model.AreaFirewall)
+}
+
+// keepAlive restarts the timer's delay until the run is done, so a long
+// run is not rolled back while it is still going. If hardn is killed, for
+// example with the session it runs in, the timer fires.
+func (s *armedSafety) keepAlive() {
+	ticker := time.NewTicker(min(s.revertAfter/4, 30*time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if err := s.manager.KeepAlive(s.runID); err != nil {
+				logging.LogDebug("Failed to extend the SSH safety timer: %v", err)
+			}
+		}
+	}
+}
+
+// ConfirmSSHChanges checks SSH after this invocation changed sshd or the
+// firewall. SSH is probed first, and the changes are rolled back right away
+// if it does not answer. On a terminal the user then confirms with a new
+// SSH session; changes not confirmed in time are rolled back. Unattended
+// runs keep the changes once a helper host reached SSH. A probe from this
+// host goes over the loopback interface, which the firewall always lets
+// through, so without a helper the revert timer is left to fire.
+func ConfirmSSHChanges() error {
+	safetyMu.Lock()
+	armed := safety
+	safety = nil
+	safetyMu.Unlock()
+	if armed == nil {
+		return nil
+	}
+	close(armed.stop)
+
+	watchdog, err := sshWatchdog(armed.cfg)
+	if err == nil {
+		// Probe the port sshd was left on, which may not be saved yet
+		factory := infrastructure.NewServiceFactory(interfaces.NewProvider(), armed.osInfo)
+		if port, err := factory.CreateSSHManager().GetPort(); err == nil && port > 0 {
+			watchdog.Port = port
+		}
+	}
+
+	var result *model.WatchdogResult
+	if err == nil {
+		result, err = armed.manager.Verify(armed.runID, watchdog)
+	}
+	if err != nil && (result == nil || result.Rollback == nil) {
+		logging.LogError("SSH could not be checked after changes to sshd or the firewall: %v", err)
+		return armed.revert()
+	}
+	if result == nil {
+		// Nothing guarded is left to confirm, for example after the watchdog
+		// rolled the run back
+		return nil
+	}
+
+	if !result.Reachable {
+		logging.LogError("SSH did not answer on port %d (%s) within %s: %v",
+			watchdog.Port, watchdog.Probe(), watchdog.Timeout, result.LastError)
+		logRollback(result.Rollback)
+		clearRun(armed.runID)
+		if err != nil {
+			return fmt.Errorf("failed to roll back run %s: %w", armed.runID, err)
+		}
+		return fmt.Errorf("run %s left SSH unreachable and was rolled back", armed.runID)
+	}
+
+	logging.LogSuccess("SSH answered on port %d (%s)", watchdog.Port, watchdog.Probe())
+	confirmed, asked := askSSHConfirmation(watchdog.Port, armed.revertAfter)
+	if asked && !confirmed {
+		logging.LogError("The changes to sshd and the firewall were not confirmed")
+		return armed.revert()
+	}
+	if !asked && watchdog.Local() {
+		return fmt.Errorf("SSH was only checked from this host, which the firewall always lets through; "+
+			"run %s is rolled back in %s (set schedule.watchdog.helper to keep unattended changes to sshd and the firewall)",
+			armed.runID, armed.revertAfter)
+	}
+	if err := armed.manager.Confirm(armed.runID); err != nil {
+		return err
+	}
+	logging.LogInfo("The changes to sshd and the firewall are kept")
+	return nil
+}
+
+// revert rolls the run back now instead of waiting for the timer
+func (s *armedSafety) revert() error {
+	result, err := s.manager.Revert(s.runID)
+	logRollback(result)
+	if result != nil {
+		clearRun(s.runID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to roll back run %s: %w", s.runID, err)
+	}
+	return fmt.Errorf("run %s was rolled back because SSH was not confirmed", s.runID)
+}
+
+// askSSHConfirmation asks the user on the terminal to confirm that a new
+// SSH session works, and reports whether they did. asked is false without
+// a terminal to ask on.
+func askSSHConfirmation(port int, timeout time.Duration) (confirmed bool, asked bool) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return false, false
+	}
+	defer tty.Close()
+
+	fmt.Fprintf(tty, "\nOpen a new SSH session to this host on port %d and check that you can log in.\n", port)
+	fmt.Fprintf(tty, "Keep the changes to sshd and the firewall? Type 'yes' within %s, or they are rolled back: ", timeout)
+
+	// The terminal is polled, so the read gives up at the deadline
+	if err := tty.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return false, false
+	}
+	answer, err := bufio.NewReader(tty).ReadString('\n')
+	if err != nil {
+		fmt.Fprintln(tty)
+		return false, true
+	}
+	return strings.TrimSpace(strings.ToLower(answer)) == "yes", true
+}
+
+// logRollback logs what a rollback reverted and what it could not
+func logRollback(result *model.RunRollback) {
+	if result == nil {
+		return
+	}
+	for _, change := range result.Reverted {
+		logging.LogInfo("Rolled back %s", change.Describe())
+	}
+	for _, failure := range result.Failed {
+		logging.LogError("Could not roll back %s: %v", failure.Change.Describe(), failure.Err)
+	}
+}
+
+// clearRun stops ReportRun from suggesting a rollback of a run that no
+// longer needs one
+func clearRun(runID string) {
+	if currentRun != nil && currentRun.RunID() == runID {
+		currentRun = nil
+	}
+}
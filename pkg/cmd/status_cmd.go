@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/menu"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/security"
+	"github.com/abbott/hardn/pkg/style"
+	"github.com/abbott/hardn/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusOutput string
+	statusJSON   bool
+	statusYAML   bool
+
+	statusWatch    bool
+	statusInterval int
+)
+
+// StatusCmd returns the status command
+func StatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the security status of this host",
+		Long: `Check the host and print the security status shown in the main menu,
+along with the overall risk level and the firewall's rules.
+
+Use --output json or yaml to feed the result into monitoring; the fields
+are stable and named in snake_case. Each check is added to the posture
+history shown by hardn history.
+
+Examples:
+  sudo hardn status
+  sudo hardn status --json
+  sudo hardn status --output yaml
+  sudo hardn status --watch --interval 10
+
+With --watch the status, the firewall's rules and the listening services
+are redrawn every --interval seconds until interrupted with Ctrl-C, for
+following changes made in another terminal.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if statusWatch {
+				return runStatusWatch(cmd)
+			}
+			configFile := ""
+			if flag := cmd.Flag("config"); flag != nil {
+				configFile = flag.Value.String()
+			}
+			return runStatus(configFile, flagEnabled(cmd, "dry-run"))
+		},
+	}
+
+	cmd.Flags().StringVarP(&statusOutput, "output", "o", "text", "Output format (text, json, yaml)")
+	cmd.Flags().BoolVar(&statusJSON, "json", false, "Output in JSON format (shorthand)")
+	cmd.Flags().BoolVar(&statusYAML, "yaml", false, "Output in YAML format (shorthand)")
+	cmd.Flags().BoolVar(&statusWatch, "watch", false, "Redraw the status until interrupted")
+	cmd.Flags().IntVar(&statusInterval, "interval", 5, "Seconds between redraws with --watch")
+
+	return cmd
+}
+
+// runStatus executes the status command. Outside dry runs the result is
+// added to the posture history.
+func runStatus(configFile string, dryRun bool) error {
+	if statusJSON {
+		statusOutput = "json"
+	} else if statusYAML {
+		statusOutput = "yaml"
+	}
+
+	// Keep informational logs out of the report
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	cfg.ApplyLogging()
+
+	formatter, err := security.NewReportFormatter(statusOutput, cfg)
+	if err != nil {
+		return err
+	}
+
+	osInfo, err := osdetect.DetectOS()
+	if err != nil {
+		return fmt.Errorf("failed to detect OS: %w", err)
+	}
+
+	status, err := security.CheckSecurityStatus(cfg, osInfo)
+	if err != nil {
+		return fmt.Errorf("failed to collect security status: %w", err)
+	}
+	if !dryRun {
+		recordPosture(status, model.PostureSourceStatus)
+	}
+
+	provider := interfaces.NewProvider()
+	firewallRepo := secondary.NewFirewallRepository(provider.FS, provider.Commander, osInfo.OsType)
+	_, _, _, rules, err := firewallRepo.GetFirewallStatus()
+	if err != nil {
+		rules = nil
+	}
+
+	return formatter.Format(os.Stdout, security.BuildStatusReport(osInfo, status, rules))
+}
+
+// runStatusWatch redraws the status, the firewall's rules and the listening
+// services every statusInterval seconds until interrupted
+func runStatusWatch(cmd *cobra.Command) error {
+	if statusJSON || statusYAML || (statusOutput != "" && statusOutput != "text") {
+		return fmt.Errorf("--watch only supports text output")
+	}
+	if statusInterval < 1 {
+		return fmt.Errorf("--interval must be at least 1 second")
+	}
+
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	factory := ctx.serviceFactory()
+	firewallManager := factory.CreateFirewallManager()
+	exposureManager := factory.CreateNetworkExposureManager()
+
+	interrupted, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Hide the cursor while redrawing
+	fmt.Print("\033[?25l")
+	defer fmt.Print("\033[?25h")
+
+	ticker := time.NewTicker(time.Duration(statusInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		// Collect everything first so the screen is blank only while drawing
+		status, statusErr := security.CheckSecurityStatus(ctx.cfg, ctx.osInfo)
+		_, firewallEnabled, _, rules, firewallErr := firewallManager.GetFirewallStatus()
+		exposure, exposureErr := exposureManager.GetNetworkExposure()
+
+		utils.ClearScreen()
+		drawStatusDashboard(ctx, status, statusErr, firewallEnabled, rules, firewallErr)
+		if exposureErr != nil {
+			fmt.Printf("\n%s Could not list listening services: %v\n",
+				style.Colored(style.Yellow, style.SymWarning), exposureErr)
+		} else {
+			fmt.Println()
+			menu.DisplayNetworkExposure(exposure)
+		}
+		fmt.Printf("\n%s\n", style.Dimmed(fmt.Sprintf("Every %ds, updated %s. Press Ctrl-C to stop.",
+			statusInterval, time.Now().Format("15:04:05"))))
+
+		select {
+		case <-interrupted.Done():
+			fmt.Println()
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// drawStatusDashboard draws the security status and the firewall's rules
+func drawStatusDashboard(ctx *commandContext, status *security.SecurityStatus, statusErr error,
+	firewallEnabled bool, rules []string, firewallErr error) {
+	hostname, _ := os.Hostname()
+	statusBox := style.NewBox(style.BoxConfig{
+		Width:          72,
+		ShowEmptyRow:   true,
+		ShowTopBorder:  true,
+		ShowLeftBorder: false,
+		Title:          fmt.Sprintf("%s (%s %s, %s)", hostname, ctx.osInfo.OsType, ctx.osInfo.OsVersion, ctx.osInfo.Environment),
+		TitleColor:     style.Bold,
+	})
+	statusBox.DrawBox(func(printLine func(string)) {
+		if statusErr != nil {
+			printLine(style.Colored(style.Red, fmt.Sprintf("Failed to collect security status: %v", statusErr)))
+			return
+		}
+		riskLevel, riskDescription, riskColor := security.GetSecurityRiskLevel(status)
+		printLine(fmt.Sprintf("%s %s %s", style.Bolded("Risk Level"),
+			style.Colored(riskColor, riskLevel), style.Dimmed(style.SymApprox+" "+riskDescription)))
+		printLine("")
+		security.DisplaySecurityStatusWithCustomPrinter(ctx.cfg, status, nil, printLine, 0)
+	})
+
+	fmt.Println()
+	firewallBox := style.NewBox(style.BoxConfig{
+		Width:          72,
+		ShowEmptyRow:   true,
+		ShowTopBorder:  true,
+		ShowLeftBorder: false,
+		Title:          "Firewall Rules",
+		TitleColor:     style.Bold,
+	})
+	firewallBox.DrawBox(func(printLine func(string)) {
+		switch {
+		case firewallErr != nil:
+			printLine(style.Colored(style.Yellow, fmt.Sprintf("Could not read the firewall: %v", firewallErr)))
+		case !firewallEnabled:
+			printLine(style.Colored(style.Red, "Firewall is not active"))
+		case len(rules) == 0:
+			printLine("No rules added")
+		default:
+			for _, rule := range rules {
+				printLine(rule)
+			}
+		}
+	})
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/security"
+	"github.com/spf13/cobra"
+)
+
+// maxSummaryUpdates caps the upgrades a posture summary lists by name
+const maxSummaryUpdates = 50
+
+var (
+	summaryDays      int
+	summaryInventory string
+	summaryHosts     []string
+	summaryJSON      bool
+)
+
+// SummaryCmd returns the summary command
+func SummaryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "summary",
+		Short: "Sum up the security posture of the past week",
+		Long: `Sum up the security posture of the past days, 7 unless schedule.summary.days
+or --days says otherwise:
+
+  risk trend       the risk level at the start and end of the period
+  drift            the checks in which controls stopped passing
+  updates applied  the packages apt, apk or dnf upgraded
+  failed runs      hardening runs that failed, from their reports
+  failed checks    the controls the latest check failed
+
+The posture comes from the history hardn status and hardn audit record, so
+schedule the audit to keep it current. With schedule.summary.cron set,
+hardn schedule install sends the summary through the configured
+notifications when the cron expression matches.
+
+With --inventory, or schedule.summary.inventory, the summary covers the
+hosts of a hardn remote inventory instead: each host's summary is collected
+over SSH and listed with its host, and the risk is that of the weakest host.
+
+Examples:
+  sudo hardn summary show
+  sudo hardn summary send --days 30
+  sudo hardn summary send --inventory /etc/hardn/inventory.yml --hosts web`,
+	}
+	cmd.PersistentFlags().IntVar(&summaryDays, "days", 0, "Days to sum up (default schedule.summary.days, or 7)")
+	cmd.PersistentFlags().StringVar(&summaryInventory, "inventory", "", "Sum up the hosts of this inventory (default schedule.summary.inventory)")
+	cmd.PersistentFlags().StringSliceVar(&summaryHosts, "hosts", nil, "Hosts or groups of the inventory to sum up (default: all)")
+
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the posture summary",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSummary(cmd, false)
+		},
+	}
+
+	sendCmd := &cobra.Command{
+		Use:   "send",
+		Short: "Send the posture summary through the configured notifications",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSummary(cmd, true)
+		},
+	}
+
+	showCmd.Flags().BoolVar(&summaryJSON, "json", false, "Output in JSON format")
+
+	cmd.AddCommand(showCmd)
+	cmd.AddCommand(sendCmd)
+	return cmd
+}
+
+// runSummary executes the summary show and send commands
+func runSummary(cmd *cobra.Command, send bool) error {
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	days := ctx.cfg.Schedule.Summary.EffectiveDays()
+	if cmd.Flags().Changed("days") {
+		days = summaryDays
+	}
+	inventory := ctx.cfg.Schedule.Summary.Inventory
+	if cmd.Flags().Changed("inventory") {
+		inventory = summaryInventory
+	}
+
+	var report any
+	var notification model.RunSummary
+	if inventory != "" {
+		fleet, err := collectFleetSummary(ctx, inventory, days)
+		if err != nil {
+			return err
+		}
+		report, notification = fleet, fleetRunSummary(fleet)
+	} else {
+		summary, err := ctx.serviceFactory().CreatePostureSummaryManager().BuildSummary(time.Now(), days)
+		if err != nil {
+			return err
+		}
+		hostname, _ := os.Hostname()
+		report, notification = summary, postureRunSummary(hostname, summary)
+	}
+
+	if !send {
+		if summaryJSON {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to encode the posture summary: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+		fmt.Print(notification.Text())
+		return nil
+	}
+
+	manager := newNotificationManager(ctx.cfg)
+	if !manager.Enabled() {
+		return fmt.Errorf("no notifications configured; set notifications.email or notifications.webhooks")
+	}
+	if ctx.dryRun {
+		fmt.Printf("[DRY-RUN] Would send: %s\n", notification.Subject())
+		return nil
+	}
+	if err := manager.Notify(notification); err != nil {
+		return fmt.Errorf("failed to send the posture summary: %w", err)
+	}
+	fmt.Printf("Sent: %s\n", notification.Subject())
+	return nil
+}
+
+// collectFleetSummary runs hardn summary show --json on the selected hosts
+// of an inventory. The command only reads, so it runs there even in
+// dry-run mode.
+func collectFleetSummary(ctx *commandContext, inventoryPath string, days int) (*model.FleetPostureSummary, error) {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	manager := ctx.serviceFactory().CreateRemoteManager()
+	inventory, err := manager.LoadInventory(inventoryPath)
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := manager.SelectHosts(inventory, summaryHosts)
+	if err != nil {
+		return nil, err
+	}
+	binary, err := remoteBinary(hosts, false)
+	if err != nil {
+		return nil, err
+	}
+
+	until := time.Now()
+	results := manager.Run(model.RemoteRequest{
+		Hosts:    hosts,
+		Args:     []string{"summary", "show", "--json", "--days", strconv.Itoa(days)},
+		Binary:   binary,
+		Parallel: 4,
+	})
+	return ctx.serviceFactory().CreatePostureSummaryManager().FleetSummary(until, days, results), nil
+}
+
+// fleetRunSummary renders the posture summaries of an inventory as one
+// summary, with each line naming its host
+func fleetRunSummary(fleet *model.FleetPostureSummary) model.RunSummary {
+	run := model.RunSummary{
+		Hostname:   fmt.Sprintf("%d hosts", len(fleet.Hosts)),
+		Operation:  model.SummaryPosture,
+		StartedAt:  fleet.Since,
+		FinishedAt: fleet.Until,
+		Failed:     fleet.Failed(),
+		RiskAfter:  "Unknown",
+		Trend:      fleet.Trend(),
+	}
+	first, latest := fleet.Weakest()
+	if first != nil {
+		run.RiskBefore = first.RiskLevel
+	}
+	if latest != nil {
+		run.RiskAfter = latest.RiskLevel
+	}
+
+	for _, host := range fleet.Hosts {
+		if host.Summary == nil {
+			run.Hosts = append(run.Hosts, fmt.Sprintf("%s: no summary: %s", host.Host, host.Error))
+			continue
+		}
+		one := postureRunSummary(host.Host, host.Summary)
+		run.Hosts = append(run.Hosts, fmt.Sprintf("%s: risk %s (%s)", host.Host, one.RiskAfter, one.Trend))
+		for _, lines := range []struct {
+			from []string
+			to   *[]string
+		}{
+			{one.Drift, &run.Drift},
+			{one.FailedRuns, &run.FailedRuns},
+			{one.UpdatesApplied, &run.UpdatesApplied},
+			{one.FailedChecks, &run.FailedChecks},
+		} {
+			for _, line := range lines.from {
+				*lines.to = append(*lines.to, host.Host+": "+line)
+			}
+		}
+	}
+	return run
+}
+
+// postureRunSummary renders the posture summary of a host as the summary
+// the notifiers send, naming controls by their titles
+func postureRunSummary(hostname string, summary *model.PostureSummary) model.RunSummary {
+	run := model.RunSummary{
+		Hostname:   hostname,
+		Operation:  model.SummaryPosture,
+		StartedAt:  summary.Since,
+		FinishedAt: summary.Until,
+		Failed:     summary.Failed(),
+		RiskAfter:  "Unknown",
+		Trend:      summary.Trend(),
+	}
+	if summary.First != nil {
+		run.RiskBefore = summary.First.RiskLevel
+	}
+	if summary.Latest != nil {
+		run.RiskAfter = summary.Latest.RiskLevel
+	}
+
+	for _, drift := range summary.Drift {
+		for _, id := range drift.Regressed {
+			run.Drift = append(run.Drift, fmt.Sprintf("%s: %s (%s) stopped passing",
+				drift.Time.Local().Format("2006-01-02 15:04"), security.ControlTitle(id), id))
+		}
+	}
+	for _, report := range summary.FailedRuns {
+		failure := fmt.Sprintf("%s: %s (run %s)", report.Started.Local().Format("2006-01-02 15:04"), report.Description, report.ID)
+		if len(report.Errors) > 0 {
+			failure += ": " + report.Errors[0]
+		}
+		run.FailedRuns = append(run.FailedRuns, failure)
+	}
+	for i, update := range summary.Updates {
+		if i == maxSummaryUpdates {
+			run.UpdatesApplied = append(run.UpdatesApplied, fmt.Sprintf("and %d more", len(summary.Updates)-i))
+			break
+		}
+		run.UpdatesApplied = append(run.UpdatesApplied, update.String())
+	}
+	for _, id := range summary.FailedChecks() {
+		run.FailedChecks = append(run.FailedChecks, id+": "+security.ControlTitle(id))
+	}
+	return run
+}
@@ -81,6 +81,7 @@ func runSystemDetails() error {
 		Version:   osInfo.OsVersion,
 		Codename:  osInfo.OsCodename,
 		IsProxmox: osInfo.IsProxmox,
+		Arch:      osInfo.Arch,
 	})
 
 	// Create application service
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tcpWrappersJSON  bool
+	tcpWrappersForce bool
+)
+
+// TCPWrappersCmd returns the tcp-wrappers command
+func TCPWrappersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tcp-wrappers",
+		Short: "Move legacy /etc/hosts.allow and /etc/hosts.deny rules to the firewall",
+		Long: `Inspect the TCP wrappers access files, /etc/hosts.allow and /etc/hosts.deny,
+and move their rules into the firewall. Few daemons still use TCP wrappers;
+OpenSSH dropped support in 6.7, so sshd rules in these files are often
+ignored. Keeping access rules in two places also makes audits unreliable.`,
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the rules and how they relate to the firewall",
+		Long: `Show the rules of /etc/hosts.allow and /etc/hosts.deny, the rules that are
+ignored or contradicted by the firewall, and the firewall rules a migration
+would add or remove.
+
+Examples:
+  hardn tcp-wrappers status
+  hardn tcp-wrappers status --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTCPWrappersStatus(cmd)
+		},
+	}
+	statusCmd.Flags().BoolVar(&tcpWrappersJSON, "json", false, "Output in JSON format")
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Add equivalent firewall rules and disable the legacy rules",
+		Long: `Add firewall rules that let in the clients hosts.allow names, remove the rules
+opening the same ports to everyone, then comment out the rules of both files.
+The SSH port is never closed; remove its open rule by hand once the migrated
+rules work. Rules for host names, wildcards or with options have no firewall
+equivalent and stop the migration unless --force is given.
+
+Examples:
+  sudo hardn tcp-wrappers migrate --dry-run
+  sudo hardn tcp-wrappers migrate`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTCPWrappersMigrate(cmd)
+		},
+	}
+	migrateCmd.Flags().BoolVar(&tcpWrappersForce, "force", false, "Migrate even when some rules have no firewall equivalent")
+
+	cmd.AddCommand(statusCmd)
+	cmd.AddCommand(migrateCmd)
+	return cmd
+}
+
+// runTCPWrappersStatus executes the tcp-wrappers status command
+func runTCPWrappersStatus(cmd *cobra.Command) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	report, err := ctx.serviceFactory().CreateTCPWrappersManager().Check(ctx.cfg.SshPort)
+	if err != nil {
+		return err
+	}
+
+	if tcpWrappersJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode TCP wrappers status: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printTCPWrappers(report)
+	return nil
+}
+
+// runTCPWrappersMigrate executes the tcp-wrappers migrate command
+func runTCPWrappersMigrate(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireSupportedOS("The firewall"); err != nil {
+		return err
+	}
+	manager := ctx.serviceFactory().CreateTCPWrappersManager()
+
+	if ctx.dryRun {
+		report, err := manager.Check(ctx.cfg.SshPort)
+		if err != nil {
+			return err
+		}
+		if !report.InUse() {
+			fmt.Println("No TCP wrappers rules to migrate")
+			return nil
+		}
+		for _, rule := range report.Add {
+			fmt.Printf("[DRY-RUN] Would add firewall rule: %s\n", formatFirewallRule(rule))
+		}
+		for _, rule := range report.Close {
+			fmt.Printf("[DRY-RUN] Would remove firewall rule: %s\n", formatFirewallRule(rule))
+		}
+		for _, issue := range report.Skipped {
+			fmt.Printf("[DRY-RUN] Cannot migrate %s:%d (%s): %s\n", issue.Rule.File, issue.Rule.Line, issue.Rule, issue.Reason)
+		}
+		fmt.Println("[DRY-RUN] Would comment out the rules of /etc/hosts.allow and /etc/hosts.deny")
+		return nil
+	}
+
+	report, err := manager.Migrate(ctx.cfg.SshPort, tcpWrappersForce)
+	if err != nil {
+		if report != nil {
+			printTCPWrappers(report)
+		}
+		return err
+	}
+	if !report.InUse() {
+		fmt.Println("No TCP wrappers rules to migrate")
+		return nil
+	}
+
+	for _, rule := range report.Add {
+		fmt.Printf("Added firewall rule: %s\n", formatFirewallRule(rule))
+	}
+	for _, rule := range report.Close {
+		fmt.Printf("Removed firewall rule: %s\n", formatFirewallRule(rule))
+	}
+	logging.LogSuccess("TCP wrappers rules moved to the firewall and disabled")
+	fmt.Println("Commented out the rules of /etc/hosts.allow and /etc/hosts.deny")
+	return nil
+}
+
+// printTCPWrappers prints the rules of both files and the migration plan
+func printTCPWrappers(report *model.TCPWrappersReport) {
+	if !report.InUse() {
+		fmt.Println("No TCP wrappers rules in /etc/hosts.allow or /etc/hosts.deny")
+		return
+	}
+
+	for _, rule := range append(append([]model.HostsAccessRule{}, report.Allow...), report.Deny...) {
+		fmt.Printf("%s:%d  %s\n", rule.File, rule.Line, rule)
+	}
+
+	printHostsAccessIssues("Conflicts", report.Conflicts)
+	printHostsAccessIssues("Cannot migrate", report.Skipped)
+
+	if len(report.Add)+len(report.Close) > 0 {
+		fmt.Println("\nMigration:")
+		for _, rule := range report.Add {
+			fmt.Printf("  add    %s\n", formatFirewallRule(rule))
+		}
+		for _, rule := range report.Close {
+			fmt.Printf("  remove %s\n", formatFirewallRule(rule))
+		}
+	}
+}
+
+func printHostsAccessIssues(title string, issues []model.HostsAccessIssue) {
+	if len(issues) == 0 {
+		return
+	}
+	fmt.Printf("\n%s:\n", title)
+	for _, issue := range issues {
+		fmt.Printf("  %s:%d: %s\n", issue.Rule.File, issue.Rule.Line, issue.Reason)
+	}
+}
+
+// formatFirewallRule formats a rule as a ufw command, e.g. "allow 22/tcp
+// from 10.0.0.0/8"
+func formatFirewallRule(rule model.FirewallRule) string {
+	text := fmt.Sprintf("%s %d/%s", rule.Action, rule.Port, rule.Protocol)
+	if rule.SourceIP != "" {
+		text += " from " + rule.SourceIP
+	}
+	return text
+}
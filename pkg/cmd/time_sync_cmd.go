@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var timeSyncJSON bool
+
+// TimeSyncCmd returns the timesync command
+func TimeSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "timesync",
+		Short: "Synchronize the clock with chrony, systemd-timesyncd or ntpd",
+		Long: `Manage the time synchronization of the timeSync section of the
+configuration: the daemon, the servers and whether they are authenticated
+with Network Time Security (NTS).`,
+	}
+
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Set up the configured time synchronization daemon and servers",
+		Long: `Apply the timeSync section of the configuration:
+
+  daemon   chrony, timesyncd or ntpd (busybox, Alpine only). Empty picks
+           chrony when nts is set or on the RHEL family, ntpd on Alpine
+           and systemd-timesyncd elsewhere
+  servers  the servers synchronized with; by default public NTS servers
+  nts      authenticate the servers with NTS; only chrony supports it
+
+The daemon is installed if needed and the other daemons are stopped, so
+only one sets the clock.
+
+Examples:
+  sudo hardn timesync apply --dry-run
+  sudo hardn timesync apply`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTimeSyncApply(cmd)
+		},
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the time synchronization daemon and clock offset",
+		Long: `Show which daemon synchronizes the clock, its servers, whether the clock
+is synchronized and how far it is from its source. The clock counts as
+drifting beyond ` + fmt.Sprint(model.MaxClockOffset) + ` seconds.
+
+Examples:
+  hardn timesync status
+  hardn timesync status --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTimeSyncStatus(cmd)
+		},
+	}
+	statusCmd.Flags().BoolVar(&timeSyncJSON, "json", false, "Output in JSON format")
+
+	cmd.AddCommand(applyCmd)
+	cmd.AddCommand(statusCmd)
+	return cmd
+}
+
+// runTimeSyncApply executes the timesync apply command
+func runTimeSyncApply(cmd *cobra.Command) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	if err := ctx.requireSupportedOS("time synchronization"); err != nil {
+		return err
+	}
+	if ctx.osInfo.Environment.IsContainer() {
+		return fmt.Errorf("time synchronization is not available in a container; containers use the host's clock")
+	}
+
+	manager := ctx.serviceFactory().CreateTimeSyncManager()
+	policy, err := manager.EffectivePolicy(ctx.cfg.TimeSync.Policy())
+	if err != nil {
+		return err
+	}
+	if err := manager.ApplyTimeSync(policy); err != nil {
+		return fmt.Errorf("failed to set up time synchronization: %w", err)
+	}
+	if ctx.dryRun {
+		return nil
+	}
+
+	servers := strings.Join(policy.Servers, ", ")
+	if policy.NTS {
+		servers += " (NTS)"
+	}
+	logging.LogSuccess("Time synchronized by %s with %s", policy.Daemon, servers)
+	return nil
+}
+
+// runTimeSyncStatus executes the timesync status command
+func runTimeSyncStatus(cmd *cobra.Command) error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	status, err := ctx.serviceFactory().CreateTimeSyncManager().GetTimeSyncStatus()
+	if err != nil {
+		return err
+	}
+
+	if timeSyncJSON {
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode time synchronization status: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printTimeSyncStatus(status)
+	return nil
+}
+
+// printTimeSyncStatus prints the time synchronization daemon and clock
+func printTimeSyncStatus(status *model.TimeSyncStatus) {
+	if status.Daemon == "" {
+		fmt.Println("No time synchronization daemon is running.")
+		return
+	}
+
+	yesNo := func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "no"
+	}
+	source := status.Source
+	if source == "" {
+		source = "-"
+	}
+	if status.Stratum > 0 {
+		source += fmt.Sprintf(" (stratum %d)", status.Stratum)
+	}
+	offset := status.Offset()
+	if status.Drifting() {
+		offset += " (drifting)"
+	}
+
+	fmt.Printf("%-14s %s\n", "Daemon", status.Daemon)
+	fmt.Printf("%-14s %s\n", "Servers", strings.Join(status.Servers, ", "))
+	fmt.Printf("%-14s %s\n", "NTS", yesNo(status.NTS))
+	fmt.Printf("%-14s %s\n", "Synchronized", yesNo(status.Synchronized))
+	fmt.Printf("%-14s %s\n", "Source", source)
+	fmt.Printf("%-14s %s\n", "Offset", offset)
+}
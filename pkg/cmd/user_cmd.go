@@ -0,0 +1,440 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/spf13/cobra"
+)
+
+var (
+	userSudo       bool
+	userNoPassword bool
+	userKeyFiles   []string
+	userLocalOnly  bool
+	userJSON       bool
+
+	sudoTemplateNoPassword bool
+)
+
+// UserCmd returns the user command
+func UserCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "user",
+		Aliases: []string{"users"},
+		Short:   "Create, import, list and lock user accounts and restrict their sudo access",
+		Long: `Manage user accounts without the interactive menu. Use --dry-run to
+preview a change.`,
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create NAME",
+		Short: "Create a user, optionally with sudo and SSH keys",
+		Long: `Create a user with a disabled password. With --sudo the user is added to
+the sudo (Debian, Ubuntu) or wheel (Alpine, Rocky Linux, AlmaLinux, Fedora)
+group and given a sudoers entry.
+Each --key file may hold several public keys, one per line.
+
+An existing user is not recreated; sudo and keys are still applied.
+
+Examples:
+  sudo hardn user create george --sudo --key george.pub
+  sudo hardn user create deploy --key deploy.pub --key ci.pub`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUserCreate(cmd, args[0])
+		},
+	}
+	createCmd.Flags().BoolVar(&userSudo, "sudo", false, "Grant sudo access")
+	createCmd.Flags().BoolVar(&userNoPassword, "nopasswd", false, "Allow sudo without a password (default: sudoNoPassword from the configuration)")
+	createCmd.Flags().StringSliceVar(&userKeyFiles, "key", nil, "File of public keys to authorize; repeat for several")
+
+	importCmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Create users with sudo and SSH keys from a CSV file",
+		Long: `Create or update several users in one pass from a CSV file with a header
+row. The columns are:
+
+  username   the login name (required)
+  sudo       yes or no; grant sudo access (default: no)
+  nopasswd   yes or no; allow sudo without a password (default:
+             sudoNoPassword from the configuration)
+  keys       public keys to authorize, separated by semicolons
+
+Every row is checked before anything changes: names must be valid and
+listed once, keys must parse and not be weak, and directory accounts are
+refused. If any row has a problem, no users are created. Existing users
+keep their account; their sudo settings and keys are still applied.
+With --dry-run the full plan is printed instead.
+
+Example users.csv:
+  username,sudo,nopasswd,keys
+  george,yes,no,ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAI... george@laptop
+  deploy,no,,ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAI... ci;ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAI... backup
+
+Examples:
+  sudo hardn users import users.csv --dry-run
+  sudo hardn users import users.csv`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUserImport(cmd, args[0])
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List non-system users",
+		Long: `List users with a UID of 1000 or more and a login shell, including
+accounts from LDAP or SSSD unless --local is given.
+
+Examples:
+  hardn user list
+  hardn user list --local --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUserList()
+		},
+	}
+	listCmd.Flags().BoolVar(&userLocalOnly, "local", false, "Only list users from /etc/passwd")
+	listCmd.Flags().BoolVar(&userJSON, "json", false, "Output in JSON format")
+
+	lockCmd := &cobra.Command{
+		Use:   "lock NAME",
+		Short: "Lock a user account",
+		Long: `Lock the user's password and expire the account so that neither
+passwords nor SSH keys can be used to log in. On Alpine only the password
+is locked, which OpenSSH also applies to key logins.
+
+Example:
+  sudo hardn user lock george`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUserLock(cmd, args[0])
+		},
+	}
+
+	addKeyCmd := &cobra.Command{
+		Use:   "add-key NAME [PUBLIC_KEY]",
+		Short: "Authorize a public key for a user",
+		Long: `Append a public key to a user's authorized_keys. The key is given as an
+argument or read from a file with --file; keys already present are left
+alone.
+
+Examples:
+  sudo hardn user add-key george "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAI... george@example.com"
+  sudo hardn user add-key george --file george.pub`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAddKey(cmd, args)
+		},
+	}
+	addKeyCmd.Flags().StringVar(&addKeyFile, "file", "", "Read the public key from a file")
+
+	sudoTemplateCmd := &cobra.Command{
+		Use:   "sudo-template NAME TEMPLATE",
+		Short: "Restrict a user's sudo access to a set of commands",
+		Long: `Replace the user's sudoers entry with one that only allows the commands
+of a template, run as root:
+
+  services   systemctl and journalctl (rc-service and rc-status on Alpine)
+  packages   apt, apt-get and dpkg, dnf and yum, or apk
+  operator   the commands of both
+
+Only the commands installed on this host are granted. sudo asks for the
+user's password unless --nopasswd is given. The entry is checked with
+visudo before it is installed in /etc/sudoers.d, and the user is removed
+from groups such as sudo or wheel that grant every command. Rules in other
+files that still grant the user every command are listed but not changed.
+
+A template limits mistakes rather than a determined user: systemctl and
+package managers can still be used to gain a root shell.
+
+Examples:
+  sudo hardn user sudo-template deploy services --dry-run
+  sudo hardn user sudo-template deploy operator --nopasswd`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUserSudoTemplate(cmd, args[0], args[1])
+		},
+	}
+	sudoTemplateCmd.Flags().BoolVar(&sudoTemplateNoPassword, "nopasswd", false, "Allow the commands without a password")
+
+	cmd.AddCommand(createCmd)
+	cmd.AddCommand(importCmd)
+	cmd.AddCommand(listCmd)
+	cmd.AddCommand(lockCmd)
+	cmd.AddCommand(addKeyCmd)
+	cmd.AddCommand(sudoTemplateCmd)
+	return cmd
+}
+
+// runUserCreate executes the user create command
+func runUserCreate(cmd *cobra.Command, username string) error {
+	var keys []string
+	for _, path := range userKeyFiles {
+		fileKeys, err := readPublicKeys(path)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, fileKeys...)
+	}
+
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	noPassword := ctx.cfg.SudoNoPassword
+	if cmd.Flags().Changed("nopasswd") {
+		noPassword = userNoPassword
+	}
+
+	if ctx.dryRun {
+		fmt.Printf("[DRY-RUN] Would create user %s\n", username)
+		if userSudo {
+			fmt.Printf("[DRY-RUN] Would grant sudo (password required: %s)\n", yesNo(!noPassword))
+		}
+		for _, key := range keys {
+			fingerprint, _ := model.SSHKeyFingerprint(key)
+			fmt.Printf("[DRY-RUN] Would authorize key %s\n", fingerprint)
+		}
+		return nil
+	}
+
+	if err := newUserManager(ctx.osInfo.OsType).CreateUser(username, userSudo, noPassword, keys); err != nil {
+		return err
+	}
+
+	logging.LogSuccess("User %s created", username)
+	fmt.Printf("User %s created", username)
+	if userSudo {
+		fmt.Print(" with sudo")
+	}
+	if len(keys) > 0 {
+		fmt.Printf(" and %d SSH key(s)", len(keys))
+	}
+	fmt.Println()
+	return nil
+}
+
+// runUserImport executes the user import command
+func runUserImport(cmd *cobra.Command, path string) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	userManager := ctx.serviceFactory().CreateUserManager()
+	plan, err := userManager.PlanUserImport(path, ctx.cfg.SudoNoPassword)
+	if err != nil {
+		return err
+	}
+
+	printUserImportPlan(path, plan, ctx.dryRun)
+	if !plan.Valid() {
+		return fmt.Errorf("%s has %d problem(s); no users were created", path, plan.ProblemCount())
+	}
+	if ctx.dryRun {
+		return nil
+	}
+
+	results, err := userManager.ImportUsers(plan)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	failed := 0
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			failed++
+			fmt.Printf("FAILED   %s: %v\n", result.Username, result.Err)
+		case result.Created:
+			logging.LogSuccess("User %s created", result.Username)
+			fmt.Printf("CREATED  %s\n", result.Username)
+		default:
+			logging.LogSuccess("User %s updated", result.Username)
+			fmt.Printf("UPDATED  %s\n", result.Username)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d user(s) could not be imported", failed, len(results))
+	}
+	fmt.Printf("\n%d user(s) imported\n", len(results))
+	return nil
+}
+
+// printUserImportPlan prints what the import does for each user and the
+// problems the pre-flight found; dryRun also lists the keys
+func printUserImportPlan(path string, plan *model.UserImportPlan, dryRun bool) {
+	prefix := "  "
+	if dryRun {
+		prefix = "[DRY-RUN] Would "
+	}
+
+	fmt.Printf("%s: %d user(s)\n", path, len(plan.Entries))
+	for _, entry := range plan.Entries {
+		if len(entry.Problems) > 0 {
+			fmt.Printf("  %s (line %d):\n", entry.User.Username, entry.Line)
+			for _, problem := range entry.Problems {
+				fmt.Printf("    problem: %s\n", problem)
+			}
+			continue
+		}
+
+		fmt.Printf("%s%s (line %d)\n", prefix, entry.Describe(), entry.Line)
+		if dryRun {
+			for _, key := range entry.User.SshKeys {
+				fingerprint, _ := model.SSHKeyFingerprint(key)
+				fmt.Printf("%sauthorize key %s for %s\n", prefix, fingerprint, entry.User.Username)
+			}
+		}
+	}
+}
+
+// readPublicKeys reads the public keys in a file, skipping blank lines and
+// comments
+func readPublicKeys(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var keys []string
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err := model.ParseAuthorizedKey(line); err != nil {
+			return nil, fmt.Errorf("%s line %d: invalid public key: %w", path, i+1, err)
+		}
+		keys = append(keys, line)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no public keys found in %s", path)
+	}
+	return keys, nil
+}
+
+// runUserList executes the user list command
+func runUserList() error {
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	osInfo, err := osdetect.DetectOS()
+	if err != nil {
+		return fmt.Errorf("failed to detect OS: %w", err)
+	}
+
+	page, err := newUserManager(osInfo.OsType).ListUsers(userLocalOnly, 0, 0)
+	if err != nil {
+		return err
+	}
+	users := page.Users
+	if users == nil {
+		users = []model.User{}
+	}
+
+	if userJSON {
+		data, err := json.MarshalIndent(users, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode users: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(users) == 0 {
+		fmt.Println("No non-system users found")
+		return nil
+	}
+
+	fmt.Printf("%-24s %-6s %s\n", "USER", "SUDO", "SOURCE")
+	for _, user := range users {
+		source := "local"
+		if !user.Local {
+			source = "directory"
+		}
+		fmt.Printf("%-24s %-6s %s\n", user.Username, yesNo(user.HasSudo), source)
+	}
+	return nil
+}
+
+// runUserLock executes the user lock command
+func runUserLock(cmd *cobra.Command, username string) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	if ctx.dryRun {
+		fmt.Printf("[DRY-RUN] Would lock user %s\n", username)
+		return nil
+	}
+
+	if err := newUserManager(ctx.osInfo.OsType).LockUser(username); err != nil {
+		return err
+	}
+
+	logging.LogSuccess("User %s locked", username)
+	fmt.Printf("User %s locked\n", username)
+	return nil
+}
+
+// runUserSudoTemplate executes the user sudo-template command
+func runUserSudoTemplate(cmd *cobra.Command, username, template string) error {
+	ctx, err := newCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	manager := ctx.serviceFactory().CreateSudoersManager()
+	plan, err := manager.PlanSudoTemplate(username, template, sudoTemplateNoPassword)
+	if err != nil {
+		return err
+	}
+
+	if ctx.dryRun {
+		fmt.Printf("[DRY-RUN] Would write %s/%s:\n", model.SudoersDir, username)
+		for _, line := range strings.Split(strings.TrimSpace(plan.Content), "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+		for _, group := range plan.LeaveGroup {
+			fmt.Printf("[DRY-RUN] Would remove %s from group %s\n", username, group)
+		}
+		printOtherSudoGrants(plan)
+		return nil
+	}
+
+	if err := manager.ApplySudoTemplate(plan); err != nil {
+		return err
+	}
+
+	logging.LogSuccess("Sudo access of %s restricted to the %s template", username, plan.Template)
+	fmt.Printf("%s may now run with sudo: %s\n", username, strings.Join(plan.Commands, ", "))
+	for _, group := range plan.LeaveGroup {
+		fmt.Printf("Removed %s from group %s\n", username, group)
+	}
+	printOtherSudoGrants(plan)
+	return nil
+}
+
+// printOtherSudoGrants warns about rules the template leaves in place
+func printOtherSudoGrants(plan *model.SudoTemplatePlan) {
+	if len(plan.OtherGrants) == 0 {
+		return
+	}
+	fmt.Printf("Warning: these rules still grant %s every command:\n", plan.Username)
+	for _, grant := range plan.OtherGrants {
+		fmt.Printf("  %s\n", grant)
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/infrastructure"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/osdetect"
+)
+
+// GuardRun checks that SSH still answers after this invocation changed
+// sshd or the firewall, and rolls the changes back if it does not. Scheduled
+// runs pass --watchdog when schedule.watchdog is enabled.
+func GuardRun(configFile string) error {
+	if currentRun == nil || currentRun.RunID() == "" {
+		return nil
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration for the SSH watchdog: %w", err)
+	}
+	watchdog, err := sshWatchdog(cfg)
+	if err != nil {
+		return err
+	}
+	osInfo, err := osdetect.DetectOS()
+	if err != nil {
+		return fmt.Errorf("failed to detect OS: %w", err)
+	}
+
+	// The rollback must not be recorded as part of the run it reverts
+	factory := infrastructure.NewServiceFactory(interfaces.NewProvider(), osInfo)
+	runID := currentRun.RunID()
+	result, err := factory.CreateWatchdogManager().GuardRun(runID, watchdog)
+	if result == nil {
+		return err
+	}
+
+	if result.Reachable {
+		logging.LogSuccess("SSH answered on port %d (%s) after %d probe(s)", watchdog.Port, watchdog.Probe(), result.Attempts)
+		if watchdog.Local() {
+			logging.LogWarning("SSH was only checked from this host, which the firewall always lets through; set schedule.watchdog.helper to catch firewall lockouts")
+		}
+		return err
+	}
+
+	logging.LogError("SSH did not answer on port %d (%s) within %s: %v",
+		watchdog.Port, watchdog.Probe(), watchdog.Timeout, result.LastError)
+	if result.Rollback != nil {
+		logRollback(result.Rollback)
+		// The run no longer needs rolling back by hand
+		currentRun = nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to roll back run %s: %w", runID, err)
+	}
+	return fmt.Errorf("run %s left SSH unreachable and was rolled back", runID)
+}
+
+// sshWatchdog builds the watchdog from the configuration. Without a helper
+// host the SSH port is probed on the first listen address, or loopback when
+// sshd listens on every address.
+func sshWatchdog(cfg *config.Config) (model.SSHWatchdog, error) {
+	settings := cfg.Schedule.Watchdog
+	watchdog := model.SSHWatchdog{
+		Port:    cfg.SshPort,
+		Helper:  settings.Helper,
+		Address: settings.Address,
+		Timeout: time.Duration(settings.Timeout) * time.Second,
+	}
+	if watchdog.Timeout <= 0 {
+		watchdog.Timeout = model.DefaultWatchdogTimeout
+	}
+
+	if watchdog.Helper != "" {
+		if watchdog.Address == "" {
+			return watchdog, fmt.Errorf("schedule.watchdog.helper needs schedule.watchdog.address, the address the helper reaches this host at")
+		}
+		return watchdog, nil
+	}
+	if watchdog.Address == "" {
+		watchdog.Address = "127.0.0.1"
+		if len(cfg.SshListenAddresses) > 0 && cfg.SshListenAddresses[0] != "0.0.0.0" && cfg.SshListenAddresses[0] != "::" {
+			watchdog.Address = cfg.SshListenAddresses[0]
+		}
+	}
+	return watchdog, nil
+}
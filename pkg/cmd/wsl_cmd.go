@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/style"
+	"github.com/spf13/cobra"
+)
+
+var wslJSON bool
+
+// wslReport is the JSON form of the wsl command
+type wslReport struct {
+	WSL               bool     `json:"wsl"`
+	Version           int      `json:"version,omitempty"`
+	Systemd           bool     `json:"systemd"`
+	ManagedResolvConf bool     `json:"managed_resolv_conf,omitempty"`
+	Skipped           []string `json:"skipped,omitempty"`
+	Guidance          []string `json:"guidance,omitempty"`
+}
+
+// WSLCmd returns the wsl command
+func WSLCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wsl",
+		Short: "Show how hardn adapts to WSL and what to harden in Windows",
+		Long: `Report whether hardn runs under Windows Subsystem for Linux, which
+hardening steps it skips there, and what to do in Windows or /etc/wsl.conf
+instead.
+
+Under WSL, Windows Defender Firewall filters inbound traffic and Microsoft's
+kernel has no AppArmor, so the firewall, AppArmor, kernel module blacklist
+and needrestart steps are skipped. DNS is skipped while WSL regenerates
+/etc/resolv.conf, and automatic updates and schedules while systemd does
+not run; services are then started with the service command.
+
+Examples:
+  hardn wsl
+  hardn wsl --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWSL(cmd)
+		},
+	}
+
+	cmd.Flags().BoolVar(&wslJSON, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// runWSL executes the wsl command
+func runWSL(cmd *cobra.Command) error {
+	ctx, err := loadCommandContext(cmd)
+	if err != nil {
+		return err
+	}
+	osInfo := ctx.osInfo
+
+	report := wslReport{
+		WSL:               osInfo.IsWSL,
+		Version:           osInfo.WSLVersion,
+		Systemd:           osInfo.Systemd,
+		ManagedResolvConf: osInfo.WSLResolvConf,
+		Skipped:           osInfo.WSLSkipped(),
+		Guidance:          osdetect.WSLGuidance(osInfo),
+	}
+	if wslJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal the WSL report: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if !osInfo.IsWSL {
+		fmt.Println("Not running under WSL; every hardening step applies.")
+		return nil
+	}
+
+	systemd := "not running (services are started with the service command)"
+	if osInfo.Systemd {
+		systemd = "running"
+	}
+	fmt.Printf("\n%s\n", style.Bolded(fmt.Sprintf("WSL %d", osInfo.WSLVersion)))
+	fmt.Printf("%s systemd: %s\n", style.BulletItem, systemd)
+	fmt.Printf("%s Skipped: %s\n", style.BulletItem, strings.Join(report.Skipped, ", "))
+
+	fmt.Printf("\n%s\n", style.Bolded("Harden in Windows and /etc/wsl.conf instead"))
+	for _, item := range report.Guidance {
+		fmt.Printf("%s %s\n", style.BulletItem, item)
+	}
+	fmt.Println(style.Dimmed("\nRun 'wsl --shutdown' in Windows after changing /etc/wsl.conf."))
+	return nil
+}
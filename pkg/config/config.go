@@ -2,14 +2,18 @@ package config
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/abbott/hardn/pkg/domain/model"
 	"github.com/abbott/hardn/pkg/logging"
 )
 
@@ -21,6 +25,413 @@ type UfwAppProfile struct {
 	Ports       []string `yaml:"ports"`
 }
 
+// FirewallRule represents a rule added by the firewall rule builder; run-all
+// and "Configure firewall" add it again after resetting the firewall
+type FirewallRule struct {
+	Action   string `yaml:"action"`   // allow, deny or reject
+	Protocol string `yaml:"protocol"` // tcp or udp
+	Port     int    `yaml:"port"`
+	EndPort  int    `yaml:"endPort,omitempty"` // last port of a range
+	Source   string `yaml:"source,omitempty"`  // IP address or network; anywhere when empty
+	Family   string `yaml:"family,omitempty"`  // ipv4 or ipv6; both when empty
+	Comment  string `yaml:"comment,omitempty"`
+}
+
+// Rule converts the settings for the firewall service
+func (r FirewallRule) Rule() model.FirewallRule {
+	return model.FirewallRule{
+		Action:      r.Action,
+		Protocol:    r.Protocol,
+		Port:        r.Port,
+		EndPort:     r.EndPort,
+		SourceIP:    r.Source,
+		Family:      r.Family,
+		Description: r.Comment,
+	}
+}
+
+// PortKnocking represents the knockd sequence that opens the SSH port; the
+// firewall keeps SSH closed to addresses that have not knocked
+type PortKnocking struct {
+	Enabled     bool   `yaml:"enabled"`
+	Sequence    []int  `yaml:"sequence"`    // TCP ports knocked in order, at least three
+	SeqTimeout  int    `yaml:"seqTimeout"`  // seconds to complete the sequence
+	OpenTimeout int    `yaml:"openTimeout"` // seconds SSH stays open to the address
+	Interface   string `yaml:"interface"`   // default: the interface of the default route
+}
+
+// Policy converts the settings for the firewall service
+func (k PortKnocking) Policy() model.PortKnocking {
+	return model.PortKnocking{
+		Sequence:    k.Sequence,
+		SeqTimeout:  k.SeqTimeout,
+		OpenTimeout: k.OpenTimeout,
+		Interface:   k.Interface,
+	}
+}
+
+// IPv6 represents how the firewall and the kernel treat IPv6
+type IPv6 struct {
+	Mode          string `yaml:"mode"`          // filter, deny or disable
+	BootParameter bool   `yaml:"bootParameter"` // disable on the kernel command line instead of with sysctl
+}
+
+// Policy converts the settings for the firewall service; IPv6 is
+// filtered unless a mode is set
+func (i IPv6) Policy() model.IPv6Policy {
+	mode := i.Mode
+	if mode == "" {
+		mode = model.IPv6Filter
+	}
+	return model.IPv6Policy{
+		Mode:          mode,
+		BootParameter: i.BootParameter,
+	}
+}
+
+// DatabaseHardening represents the opt-in PostgreSQL/MySQL baseline
+type DatabaseHardening struct {
+	Enabled        bool     `yaml:"enabled"`
+	BindAddresses  []string `yaml:"bindAddresses"`
+	AllowedSubnets []string `yaml:"allowedSubnets"`
+}
+
+// CertificateMonitoring represents the certificate expiry check
+type CertificateMonitoring struct {
+	Paths      []string `yaml:"paths"`
+	ExpiryDays int      `yaml:"expiryDays"`
+	Notify     bool     `yaml:"notify"`
+}
+
+// Logging represents the log level, log file format and rotation, and the
+// sinks entries are also shipped to
+type Logging struct {
+	Level      string   `yaml:"level"`
+	Format     string   `yaml:"format"`
+	MaxSizeMB  int      `yaml:"maxSizeMB"`
+	MaxBackups int      `yaml:"maxBackups"`
+	Sinks      []string `yaml:"sinks"`
+}
+
+// AutoUpdates represents the automatic upgrade policy written on Debian,
+// Ubuntu and Alpine when enableUnattendedUpgrades is set
+type AutoUpdates struct {
+	Origins         []string `yaml:"origins"`         // unattended-upgrades Origins-Pattern; empty for security updates
+	Blacklist       []string `yaml:"blacklist"`       // packages never upgraded automatically
+	Reboot          bool     `yaml:"reboot"`          // reboot when an upgrade needs it
+	RebootTime      string   `yaml:"rebootTime"`      // HH:MM, Debian and Ubuntu only
+	Mail            string   `yaml:"mail"`            // address sent upgrade reports
+	MailOnlyOnError bool     `yaml:"mailOnlyOnError"` // only report failed upgrades
+}
+
+// Policy converts the settings for the updates service
+func (a AutoUpdates) Policy() model.UpdatesPolicy {
+	return model.UpdatesPolicy{
+		Origins:         a.Origins,
+		Blacklist:       a.Blacklist,
+		Reboot:          a.Reboot,
+		RebootTime:      a.RebootTime,
+		Mail:            a.Mail,
+		MailOnlyOnError: a.MailOnlyOnError,
+	}
+}
+
+// Banner represents the login banners and MOTD written by run-all and
+// "hardn banner apply"; the texts may use {hostname} and {os}, and the MOTD
+// also {last_run}, {risk_level} and {pending_updates}
+type Banner struct {
+	Enabled        bool   `yaml:"enabled"`
+	Issue          string `yaml:"issue"`          // /etc/issue; empty for a standard warning
+	IssueNet       string `yaml:"issueNet"`       // /etc/issue.net; empty uses issue
+	Motd           string `yaml:"motd"`           // MOTD template; empty for the built-in one
+	DynamicMotd    bool   `yaml:"dynamicMotd"`    // show the MOTD template at login
+	DisableMotdAds bool   `yaml:"disableMotdAds"` // turn off Ubuntu's news and advertising scripts
+	SshBanner      bool   `yaml:"sshBanner"`      // show /etc/issue.net before SSH login
+}
+
+// Policy converts the settings for the banner service
+func (b Banner) Policy() model.BannerPolicy {
+	return model.BannerPolicy{
+		Issue:          b.Issue,
+		IssueNet:       b.IssueNet,
+		Motd:           b.Motd,
+		DynamicMotd:    b.DynamicMotd,
+		DisableMotdAds: b.DisableMotdAds,
+		SSHBanner:      b.SshBanner,
+	}
+}
+
+// PasswordPolicy represents the password strength, aging and lockout rules
+// applied by run-all and "hardn password-policy apply"
+type PasswordPolicy struct {
+	Enabled           bool `yaml:"enabled"`
+	MinLength         int  `yaml:"minLength"`         // shortest accepted password
+	DigitCredit       int  `yaml:"digitCredit"`       // -N requires N digits
+	UpperCredit       int  `yaml:"upperCredit"`       // -N requires N upper case letters
+	LowerCredit       int  `yaml:"lowerCredit"`       // -N requires N lower case letters
+	OtherCredit       int  `yaml:"otherCredit"`       // -N requires N symbols
+	Retry             int  `yaml:"retry"`             // prompts before passwd gives up
+	MaxDays           int  `yaml:"maxDays"`           // PASS_MAX_DAYS for new accounts
+	MinDays           int  `yaml:"minDays"`           // PASS_MIN_DAYS for new accounts
+	WarnAge           int  `yaml:"warnAge"`           // PASS_WARN_AGE for new accounts
+	LockoutAttempts   int  `yaml:"lockoutAttempts"`   // failed logins before lockout; 0 turns lockout off
+	LockoutUnlockTime int  `yaml:"lockoutUnlockTime"` // seconds; 0 locks until "faillock --reset"
+}
+
+// Policy converts the settings for the password policy service
+func (p PasswordPolicy) Policy() model.PasswordPolicy {
+	return model.PasswordPolicy{
+		MinLength:         p.MinLength,
+		DigitCredit:       p.DigitCredit,
+		UpperCredit:       p.UpperCredit,
+		LowerCredit:       p.LowerCredit,
+		OtherCredit:       p.OtherCredit,
+		Retry:             p.Retry,
+		MaxDays:           p.MaxDays,
+		MinDays:           p.MinDays,
+		WarnAge:           p.WarnAge,
+		LockoutAttempts:   p.LockoutAttempts,
+		LockoutUnlockTime: p.LockoutUnlockTime,
+	}
+}
+
+// ModuleBlacklist represents the kernel modules kept from loading by run-all
+// and "hardn modules apply"
+type ModuleBlacklist struct {
+	Enabled    bool     `yaml:"enabled"`
+	Modules    []string `yaml:"modules"`    // squashfs is kept where snapd is installed
+	UsbStorage bool     `yaml:"usbStorage"` // also block USB mass storage
+}
+
+// Policy converts the settings for the module blacklist service
+func (b ModuleBlacklist) Policy() model.ModuleBlacklistPolicy {
+	return model.ModuleBlacklistPolicy{
+		Modules:    b.Modules,
+		UsbStorage: b.UsbStorage,
+	}
+}
+
+// RepositoryKey represents the signing key of a third-party repository and
+// the repositories it signs
+type RepositoryKey struct {
+	Name        string   `yaml:"name"`        // keyring file name in /etc/apt/keyrings
+	URL         string   `yaml:"url"`         // https URL of the key
+	Fingerprint string   `yaml:"fingerprint"` // OpenPGP fingerprint; SHA-256 of the key file on Alpine
+	Repos       []string `yaml:"repos"`       // one-line entries; CODENAME is replaced with the release codename
+}
+
+// Key converts the settings for the repository trust service
+func (k RepositoryKey) Key() model.RepositoryKey {
+	return model.RepositoryKey{
+		Name:        k.Name,
+		URL:         k.URL,
+		Fingerprint: k.Fingerprint,
+		Repos:       k.Repos,
+	}
+}
+
+// Debloat represents the insecure or unneeded packages reported by the
+// audit and removed with --remove-insecure-packages
+type Debloat struct {
+	Packages []string `yaml:"packages"` // telnet, rsh, nis, talk, tftp, xinetd, avahi, cups, or any package name
+}
+
+// Items returns the catalog entries of the configured packages
+func (d Debloat) Items() []model.DebloatItem {
+	items := make([]model.DebloatItem, 0, len(d.Packages))
+	for _, name := range d.Packages {
+		items = append(items, model.FindDebloatItem(name))
+	}
+	return items
+}
+
+// BootHardening represents the kernel command line and GRUB password set
+// by run-all and "hardn boot apply"
+type BootHardening struct {
+	Enabled    bool     `yaml:"enabled"`
+	Parameters []string `yaml:"parameters"` // kernel parameters, e.g. lockdown=confidentiality
+	Password   string   `yaml:"password"`   // GRUB superuser password or grub.pbkdf2 hash; empty for none
+}
+
+// Policy converts the settings for the boot hardening service
+func (b BootHardening) Policy() model.BootHardeningPolicy {
+	return model.BootHardeningPolicy{
+		Parameters: b.Parameters,
+		Password:   b.Password,
+	}
+}
+
+// TimeSync represents the time synchronization set up by run-all and
+// "hardn timesync apply"
+type TimeSync struct {
+	Enabled bool     `yaml:"enabled"`
+	Daemon  string   `yaml:"daemon"`  // chrony, timesyncd or ntpd (Alpine); empty picks one for the OS
+	Servers []string `yaml:"servers"` // NTP servers; NTS-capable ones when nts is set
+	NTS     bool     `yaml:"nts"`     // authenticate the servers with NTS (chrony only)
+}
+
+// Policy converts the settings for the time synchronization service
+func (t TimeSync) Policy() model.TimeSyncPolicy {
+	return model.TimeSyncPolicy{
+		Daemon:  t.Daemon,
+		Servers: t.Servers,
+		NTS:     t.NTS,
+	}
+}
+
+// LogShipping represents the log forwarding set up by run-all and
+// "hardn log-shipping apply"
+type LogShipping struct {
+	Enabled  bool   `yaml:"enabled"`
+	Method   string `yaml:"method"`   // rsyslog (syslog over TLS) or journald (systemd-journal-upload)
+	Host     string `yaml:"host"`     // collector; its certificate must carry this name
+	Port     int    `yaml:"port"`     // 0 picks 6514 for rsyslog and 19532 for journald
+	CAFile   string `yaml:"caFile"`   // CA that signed the collector's certificate
+	CertFile string `yaml:"certFile"` // client certificate; required for journald
+	KeyFile  string `yaml:"keyFile"`  // client key; required for journald
+}
+
+// Policy converts the settings for the log shipping service
+func (l LogShipping) Policy() model.LogShippingPolicy {
+	return model.LogShippingPolicy{
+		Method:   l.Method,
+		Host:     l.Host,
+		Port:     l.Port,
+		CAFile:   l.CAFile,
+		CertFile: l.CertFile,
+		KeyFile:  l.KeyFile,
+	}
+}
+
+// Proxmox represents the Proxmox VE host settings applied by
+// "hardn proxmox apply" and the Proxmox menu
+type Proxmox struct {
+	DisableSubscriptionNag bool     `yaml:"disableSubscriptionNag"`
+	WebUIAllowlist         []string `yaml:"webUIAllowlist"` // addresses or networks allowed to port 8006
+	HardenCiphers          bool     `yaml:"hardenCiphers"`
+	RealmTwoFactor         bool     `yaml:"realmTwoFactor"` // require TOTP for the pve realm
+}
+
+// Policy converts the settings for the Proxmox service
+func (p Proxmox) Policy() model.ProxmoxHardening {
+	return model.ProxmoxHardening{
+		DisableSubscriptionNag: p.DisableSubscriptionNag,
+		WebUIAllowlist:         p.WebUIAllowlist,
+		HardenCiphers:          p.HardenCiphers,
+		RealmTwoFactor:         p.RealmTwoFactor,
+	}
+}
+
+// Schedule represents the timed re-runs installed by "hardn schedule"
+type Schedule struct {
+	Interval string          `yaml:"interval"`
+	Tasks    []string        `yaml:"tasks"`
+	Watchdog Watchdog        `yaml:"watchdog"`
+	Summary  ScheduleSummary `yaml:"summary"`
+}
+
+// ScheduleSummary represents the posture summary the schedule sends
+// through the notifications
+type ScheduleSummary struct {
+	Cron      string `yaml:"cron"`      // e.g. "0 8 * * 1"; empty sends no summary
+	Days      int    `yaml:"days"`      // days the summary covers; 7 when unset
+	Inventory string `yaml:"inventory"` // sum up the hosts of this inventory instead of this host
+}
+
+// EffectiveDays returns the days the posture summary covers
+func (s ScheduleSummary) EffectiveDays() int {
+	if s.Days == 0 {
+		return model.DefaultSummaryDays
+	}
+	return s.Days
+}
+
+// Notifications represents where summaries of run-all and audits are sent
+type Notifications struct {
+	OnlyOnFailure bool              `yaml:"onlyOnFailure"`
+	Email         EmailNotification `yaml:"email"`
+	Webhooks      []string          `yaml:"webhooks"` // Slack, Discord, Teams or any JSON receiver
+}
+
+// EmailNotification represents the SMTP server summaries are mailed through
+type EmailNotification struct {
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"` // 587 when unset
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"` // HARDN_SMTP_PASSWORD is used when empty
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// Settings converts the settings for the notification service
+func (n Notifications) Settings() model.NotificationSettings {
+	password := n.Email.Password
+	if password == "" {
+		password = os.Getenv("HARDN_SMTP_PASSWORD")
+	}
+	return model.NotificationSettings{
+		Email: model.EmailSettings{
+			Host:     n.Email.Host,
+			Port:     n.Email.Port,
+			Username: n.Email.Username,
+			Password: password,
+			From:     n.Email.From,
+			To:       n.Email.To,
+		},
+		Webhooks:      n.Webhooks,
+		OnlyOnFailure: n.OnlyOnFailure,
+	}
+}
+
+// BackupCompression represents how backups are compressed
+type BackupCompression struct {
+	Format string `yaml:"format"` // gzip or zstd; empty stores plain copies
+	Level  int    `yaml:"level"`  // 0 uses the format's default
+}
+
+// BackupEncryption represents how backups are encrypted
+type BackupEncryption struct {
+	Tool      string `yaml:"tool"`      // age or gpg; empty leaves backups unencrypted
+	Recipient string `yaml:"recipient"` // age public key or recipients file, or GnuPG key
+	Identity  string `yaml:"identity"`  // age identity file decrypting backups on restore
+}
+
+// BackupRemote represents the bucket or host backups are mirrored to
+type BackupRemote struct {
+	URL           string `yaml:"url"`           // s3://bucket/prefix or sftp://user@host[:port]/path
+	Endpoint      string `yaml:"endpoint"`      // S3-compatible endpoint other than AWS
+	Profile       string `yaml:"profile"`       // AWS CLI profile with the S3 credentials
+	IdentityFile  string `yaml:"identityFile"`  // SSH private key for SFTP
+	RetentionDays int    `yaml:"retentionDays"` // remote backups kept; 0 keeps all
+}
+
+// Policy converts the remote target for the backup service
+func (r BackupRemote) Policy() model.BackupRemote {
+	return model.BackupRemote{
+		URL:           r.URL,
+		Endpoint:      r.Endpoint,
+		Profile:       r.Profile,
+		IdentityFile:  r.IdentityFile,
+		RetentionDays: r.RetentionDays,
+	}
+}
+
+// Watchdog represents the SSH check after scheduled runs that change sshd
+// or the firewall; runs that leave SSH unreachable are rolled back
+type Watchdog struct {
+	Enabled bool   `yaml:"enabled"`
+	Helper  string `yaml:"helper"`  // ssh destination probing from outside; empty probes locally
+	Address string `yaml:"address"` // this host's address as the helper reaches it
+	Timeout int    `yaml:"timeout"` // seconds SSH may take to answer
+}
+
+// SSHSafety represents the check of runs that change sshd or the firewall;
+// their changes are rolled back unless SSH is confirmed to work
+type SSHSafety struct {
+	Enabled     bool `yaml:"enabled"`
+	RevertAfter int  `yaml:"revertAfter"` // seconds to confirm before the changes are rolled back
+}
+
 // Config represents the main configuration structure
 type Config struct {
 	// Basic Configuration
@@ -30,21 +441,60 @@ type Config struct {
 	EnableBackups bool   `yaml:"enableBackups"`
 	BackupPath    string `yaml:"backupPath"`
 
+	// Compression and encryption of the files backed up
+	BackupCompression BackupCompression `yaml:"backupCompression"`
+	BackupEncryption  BackupEncryption  `yaml:"backupEncryption"`
+
+	// S3 bucket or SFTP host backups are mirrored to after each run
+	BackupRemote BackupRemote `yaml:"backupRemote"`
+
+	// age keys of the encrypted secrets; values may also be ${env:NAME}
+	Secrets Secrets `yaml:"secrets"`
+
+	// Log level, file format and rotation, and syslog or journald sinks
+	Logging Logging `yaml:"logging"`
+
+	// Snapshot the root filesystem (LVM thin, ZFS, btrfs) before run-all
+	SnapshotBeforeRunAll bool `yaml:"snapshotBeforeRunAll"`
+
 	// Network Configuration
 	DmzSubnet   string   `yaml:"dmzSubnet"`
 	Nameservers []string `yaml:"nameservers"`
 
+	// Proxy passed to external commands; inherited proxy variables are dropped
+	HttpProxy string `yaml:"httpProxy"`
+
+	// Make no calls to the internet, as on air-gapped hosts (also --offline)
+	Offline bool `yaml:"offline"`
+
+	// Hours the result of the update check is reused before GitHub is asked again
+	UpdateCheckHours int `yaml:"updateCheckHours"`
+
 	// SSH Configuration
-	SshPort          int      `yaml:"sshPort"`
-	PermitRootLogin  bool     `yaml:"permitRootLogin"`
-	SshAllowedUsers  []string `yaml:"sshAllowedUsers"`
-	SshListenAddress string   `yaml:"sshListenAddress"`
-	SshKeyPath       string   `yaml:"sshKeyPath"`
-	SshConfigFile    string   `yaml:"sshConfigFile"`
+	SshPort            int      `yaml:"sshPort"`
+	PermitRootLogin    bool     `yaml:"permitRootLogin"`
+	SshAllowedUsers    []string `yaml:"sshAllowedUsers"`
+	SshAllowAllUsers   bool     `yaml:"sshAllowAllUsers"`
+	SshListenAddresses []string `yaml:"sshListenAddresses"`
+	SshKeyPath         string   `yaml:"sshKeyPath"`
+	SshConfigFile      string   `yaml:"sshConfigFile"`
+	SshProfile         string   `yaml:"sshProfile"`
+
+	// Lines of other sshd configuration files contradicting hardn's file:
+	// "warn" reports them after run-all, "consolidate" disables them
+	SshDropInConflicts string `yaml:"sshDropInConflicts"`
+
+	// Roll back changes to sshd or the firewall unless SSH is confirmed
+	SshSafety SSHSafety `yaml:"sshSafety"`
+
+	// Deprecated: single-address form kept so older config files still load;
+	// migrated into SshListenAddresses by NormalizeListenAddresses
+	SshListenAddress string `yaml:"sshListenAddress,omitempty"`
 
 	// User Configuration
 	SudoNoPassword bool     `yaml:"sudoNoPassword"`
 	SshKeys        []string `yaml:"sshKeys"`
+	LocalUsersOnly bool     `yaml:"localUsersOnly"`
 
 	// Package Configuration
 	LinuxCorePackages    []string `yaml:"linuxCorePackages"`
@@ -57,6 +507,10 @@ type Config struct {
 	AlpineDmzPackages    []string `yaml:"alpineDmzPackages"`
 	AlpineLabPackages    []string `yaml:"alpineLabPackages"`
 	AlpinePythonPackages []string `yaml:"alpinePythonPackages"`
+	RhelCorePackages     []string `yaml:"rhelCorePackages"`
+	RhelDmzPackages      []string `yaml:"rhelDmzPackages"`
+	RhelLabPackages      []string `yaml:"rhelLabPackages"`
+	RhelPythonPackages   []string `yaml:"rhelPythonPackages"`
 
 	// Repository Configuration
 	DebianRepos            []string `yaml:"debianRepos"`
@@ -65,6 +519,10 @@ type Config struct {
 	ProxmoxEnterpriseRepo  []string `yaml:"proxmoxEnterpriseRepo"`
 	ProxmoxPackagePatterns []string `yaml:"proxmoxPackagePatterns"`
 	AlpineTestingRepo      bool     `yaml:"alpineTestingRepo"`
+	SourcesFormat          string   `yaml:"sourcesFormat"` // auto, list or deb822
+
+	// Third-party repository keys and the repositories they sign
+	RepositoryKeys []RepositoryKey `yaml:"repositoryKeys"`
 
 	// Firewall Configuration
 	// UfwAppProfiles represents UFW application profiles
@@ -72,15 +530,88 @@ type Config struct {
 	UfwDefaultIncomingPolicy string          `yaml:"ufwDefaultIncomingPolicy"`
 	UfwDefaultOutgoingPolicy string          `yaml:"ufwDefaultOutgoingPolicy"`
 	UfwAllowedPorts          []int           `yaml:"ufwAllowedPorts"`
+	FirewallRules            []FirewallRule  `yaml:"firewallRules"`
+
+	// Rate-limit new SSH connections (ufw limit) instead of allowing them all
+	SshRateLimit bool `yaml:"sshRateLimit"`
+
+	// Keep SSH closed until a knock sequence; knockd opens it per address
+	PortKnocking PortKnocking `yaml:"portKnocking"`
+
+	// Filter IPv6 like IPv4, deny it but for SSH and IPv6 rules, or turn it off
+	IPv6 IPv6 `yaml:"ipv6"`
+
+	// Copy rules between IPv4 and IPv6 after run-all configures the firewall
+	MirrorFirewallStacks bool `yaml:"mirrorFirewallStacks"`
 
 	// Feature Toggles
-	UseUvPackageManager      bool `yaml:"useUvPackageManager"`
-	EnableAppArmor           bool `yaml:"enableAppArmor"`
-	EnableLynis              bool `yaml:"enableLynis"`
-	EnableUnattendedUpgrades bool `yaml:"enableUnattendedUpgrades"`
-	EnableUfwSshPolicy       bool `yaml:"enableUfwSshPolicy"`
-	ConfigureDns             bool `yaml:"configureDns"`
-	DisableRootSSH           bool `yaml:"disableRootSSH"`
+	UseUvPackageManager      bool        `yaml:"useUvPackageManager"`
+	EnableAppArmor           bool        `yaml:"enableAppArmor"`
+	EnableSELinux            bool        `yaml:"enableSELinux"`
+	EnableLynis              bool        `yaml:"enableLynis"`
+	EnableUnattendedUpgrades bool        `yaml:"enableUnattendedUpgrades"`
+	AutoUpdates              AutoUpdates `yaml:"autoUpdates"`
+	EnableUfwSshPolicy       bool        `yaml:"enableUfwSshPolicy"`
+	ConfigureDns             bool        `yaml:"configureDns"`
+	DisableRootSSH           bool        `yaml:"disableRootSSH"`
+
+	// needrestart restart mode on Debian/Ubuntu: automatic, list or interactive ("" leaves it unmanaged)
+	NeedrestartMode string `yaml:"needrestartMode"`
+
+	// Apply the TLS baseline to detected nginx, Apache and Caddy servers
+	EnableWebServerTls bool `yaml:"enableWebServerTls"`
+
+	// Baseline for detected PostgreSQL and MySQL/MariaDB servers
+	DatabaseHardening DatabaseHardening `yaml:"databaseHardening"`
+
+	// Login banners and MOTD
+	Banner Banner `yaml:"banner"`
+
+	// Password strength, aging and account lockout
+	PasswordPolicy PasswordPolicy `yaml:"passwordPolicy"`
+
+	// Uncommon filesystems and USB storage kept from loading
+	ModuleBlacklist ModuleBlacklist `yaml:"moduleBlacklist"`
+
+	// Risky or unneeded packages and their services
+	Debloat Debloat `yaml:"debloat"`
+
+	// Kernel command line parameters and the GRUB password
+	BootHardening BootHardening `yaml:"bootHardening"`
+
+	// Time synchronization daemon and servers
+	TimeSync TimeSync `yaml:"timeSync"`
+
+	// Remote log forwarding over TLS
+	LogShipping LogShipping `yaml:"logShipping"`
+
+	// Proxmox VE web UI and realm hardening
+	Proxmox Proxmox `yaml:"proxmox"`
+
+	// Environment run-all tunes its steps to: auto detects it, none runs
+	// every step, or bare-metal, vm, lxc, unprivileged-lxc, docker or container
+	EnvironmentPlan string `yaml:"environmentPlan"`
+
+	// Certificates to watch for expiry, in addition to common locations
+	CertificateMonitoring CertificateMonitoring `yaml:"certificateMonitoring"`
+
+	// Change Management
+	// Windows such as "Sat 02:00-04:00" or "Mon-Fri 22:00-23:30" (local time);
+	// outside them changes require --override-window. Empty allows changes anytime.
+	MaintenanceWindows []string `yaml:"maintenanceWindows"`
+
+	// Hardening steps and audits re-run by the installed timer or cron entry
+	Schedule Schedule `yaml:"schedule"`
+
+	// Summaries sent by email or webhook after run-all and audits
+	Notifications Notifications `yaml:"notifications"`
+
+	// Fingerprints of the GnuPG keys trusted to sign profile bundles
+	ProfileSigners []string `yaml:"profileSigners"`
+
+	// MaintenanceLocked is set at startup when outside every maintenance window
+	// without --override-window; the menu then stays in dry-run mode
+	MaintenanceLocked bool `yaml:"-"`
 
 	// Localization
 	Lang             string `yaml:"lang"`
@@ -104,18 +635,30 @@ func DefaultConfig() *Config {
 		DryRun:        false,
 		EnableBackups: true,
 		BackupPath:    "/var/backups/hardn",
+		Logging: Logging{
+			Level:      "info",
+			Format:     "text",
+			MaxSizeMB:  10,
+			MaxBackups: 3,
+		},
 
 		// Network Configuration
 		// DmzSubnet:   "192.168.4",
 		// Nameservers: []string{"1.1.1.1", "1.0.0.1"},
+		UpdateCheckHours: 24,
 
 		// SSH Configuration
 		SshPort:         22,
 		PermitRootLogin: false,
 		// SshAllowedUsers:  []string{"george"},
-		SshListenAddress: "0.0.0.0",
-		SshKeyPath:       ".ssh_%u",
-		SshConfigFile:    "/etc/ssh/sshd_config.d/hardn.conf",
+		SshListenAddresses: []string{"0.0.0.0"},
+		SshKeyPath:         ".ssh_%u",
+		SshConfigFile:      "/etc/ssh/sshd_config.d/hardn.conf",
+		SshDropInConflicts: model.SSHConflictsWarn,
+		SshSafety: SSHSafety{
+			Enabled:     true,
+			RevertAfter: int(model.DefaultSafetyRevertAfter.Seconds()),
+		},
 
 		// User Configuration
 		SudoNoPassword: true,
@@ -123,6 +666,12 @@ func DefaultConfig() *Config {
 
 		// Firewall Configuration
 		UfwAppProfiles: []UfwAppProfile{},
+		FirewallRules:  []FirewallRule{},
+		PortKnocking: PortKnocking{
+			SeqTimeout:  10,
+			OpenTimeout: 30,
+		},
+		IPv6: IPv6{Mode: model.IPv6Filter},
 		// UfwDefaultIncomingPolicy: "deny",
 		// UfwDefaultOutgoingPolicy: "allow",
 		// UfwAllowedPorts:          []int{22},
@@ -130,11 +679,49 @@ func DefaultConfig() *Config {
 		// Feature Toggles
 		UseUvPackageManager:      false,
 		EnableAppArmor:           false,
+		EnableSELinux:            false,
 		EnableLynis:              false,
 		EnableUnattendedUpgrades: false,
 		EnableUfwSshPolicy:       false,
 		ConfigureDns:             false,
 		DisableRootSSH:           false,
+		Banner: Banner{
+			DynamicMotd:    true,
+			DisableMotdAds: true,
+			SshBanner:      true,
+		},
+		PasswordPolicy: PasswordPolicy{
+			MinLength:         model.DefaultPasswordMinLength,
+			DigitCredit:       -1,
+			UpperCredit:       -1,
+			LowerCredit:       -1,
+			OtherCredit:       -1,
+			Retry:             3,
+			MaxDays:           model.DefaultPasswordMaxDays,
+			MinDays:           1,
+			WarnAge:           7,
+			LockoutAttempts:   model.DefaultLockoutAttempts,
+			LockoutUnlockTime: model.DefaultLockoutUnlockTime,
+		},
+		ModuleBlacklist: ModuleBlacklist{
+			Modules: append([]string{}, model.DefaultBlacklistedModules...),
+		},
+		Debloat: Debloat{
+			Packages: append([]string{}, model.DefaultDebloatPackages...),
+		},
+		BootHardening: BootHardening{
+			Parameters: append([]string{}, model.DefaultBootParameters...),
+		},
+		TimeSync: TimeSync{
+			Servers: append([]string{}, model.DefaultTimeServers...),
+			NTS:     true,
+		},
+		LogShipping: LogShipping{
+			Method: model.LogShippingRsyslog,
+		},
+
+		EnvironmentPlan: model.EnvironmentPlanAuto,
+		SourcesFormat:   model.SourcesFormatAuto,
 
 		// Localization
 		// Lang:             "en_US.UTF-8",
@@ -150,6 +737,9 @@ func DefaultConfig() *Config {
 		AlpineCorePackages: []string{},
 		AlpineDmzPackages:  []string{},
 		AlpineLabPackages:  []string{},
+		RhelCorePackages:   []string{},
+		RhelDmzPackages:    []string{},
+		RhelLabPackages:    []string{},
 		// LinuxCorePackages:        []string{"apt-transport-https", "dstat", "gawk", "git", "jq", "htop", "iputils-clockdiff", "sed", "strace", "sudo", "sysstat"},
 		// LinuxDmzPackages:         []string{"dnsutils", "fail2ban", "nethogs"},
 		// LinuxLabPackages:         []string{"aria2", "arping", "fping", "iperf3", "lshw",  "mosh", "net-tools", "tree"},
@@ -159,6 +749,95 @@ func DefaultConfig() *Config {
 	}
 }
 
+// CustomFirewallRules converts the rules of the firewall rule builder for
+// the firewall service
+func (c *Config) CustomFirewallRules() []model.FirewallRule {
+	var rules []model.FirewallRule
+	for _, rule := range c.FirewallRules {
+		rules = append(rules, rule.Rule())
+	}
+	return rules
+}
+
+// SSHFirewallPolicy returns how the firewall lets SSH in on the
+// configured port
+func (c *Config) SSHFirewallPolicy() model.SSHFirewallPolicy {
+	policy := model.SSHFirewallPolicy{
+		Port:      c.SshPort,
+		RateLimit: c.SshRateLimit,
+	}
+	if c.PortKnocking.Enabled {
+		knocking := c.PortKnocking.Policy()
+		policy.Knocking = &knocking
+	}
+	return policy
+}
+
+// FirewallProfiles converts the UFW application profiles for the firewall
+// service
+func (c *Config) FirewallProfiles() []model.FirewallProfile {
+	var profiles []model.FirewallProfile
+	for _, profile := range c.UfwAppProfiles {
+		profiles = append(profiles, model.FirewallProfile{
+			Name:        profile.Name,
+			Title:       profile.Title,
+			Description: profile.Description,
+			Ports:       profile.Ports,
+		})
+	}
+	return profiles
+}
+
+// BackupConfig converts the backup settings for the backup service
+func (c *Config) BackupConfig() model.BackupConfig {
+	return model.BackupConfig{
+		Enabled:          c.EnableBackups,
+		BackupDir:        c.BackupPath,
+		Compression:      c.BackupCompression.Format,
+		CompressionLevel: c.BackupCompression.Level,
+		Encryption:       c.BackupEncryption.Tool,
+		Recipient:        c.BackupEncryption.Recipient,
+		Identity:         c.BackupEncryption.Identity,
+		Remote:           c.BackupRemote.Policy(),
+	}
+}
+
+// UpdateCheckTTL is how long an update check is reused; a setting below
+// one hour uses the default of a day
+func (c *Config) UpdateCheckTTL() time.Duration {
+	if c.UpdateCheckHours < 1 {
+		return 24 * time.Hour
+	}
+	return time.Duration(c.UpdateCheckHours) * time.Hour
+}
+
+// NormalizeListenAddresses migrates the legacy sshListenAddress value into
+// SshListenAddresses, trims and de-duplicates entries, and falls back to
+// 0.0.0.0 when no address is configured
+func (c *Config) NormalizeListenAddresses() {
+	addresses := c.SshListenAddresses
+	if len(addresses) == 0 && c.SshListenAddress != "" {
+		addresses = []string{c.SshListenAddress}
+	}
+	c.SshListenAddress = ""
+
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, addr := range addresses {
+		addr = strings.TrimSpace(addr)
+		if addr == "" || seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		normalized = append(normalized, addr)
+	}
+
+	if len(normalized) == 0 {
+		normalized = []string{"0.0.0.0"}
+	}
+	c.SshListenAddresses = normalized
+}
+
 // ConfigFileSearchPath returns an ordered list of paths to search for the config file
 // Modifications for pkg/config/config.go
 
@@ -240,6 +919,7 @@ func FindConfigFile(explicitPath string) (string, bool) {
 			logging.LogInfo("Using configuration from: %s", path)
 			return path, true
 		}
+		logging.LogDebug("No configuration file at %s", path)
 	}
 
 	// No configuration file found
@@ -247,6 +927,21 @@ func FindConfigFile(explicitPath string) (string, bool) {
 	return "", false
 }
 
+// AbsConfigFile returns the absolute path of the configuration file that
+// would be loaded, or "" when there is none
+func AbsConfigFile(explicitPath string) string {
+	for _, path := range ConfigFileSearchPath(explicitPath) {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			return abs
+		}
+		return path
+	}
+	return ""
+}
+
 // helper function to use with LoadConfig
 func LoadConfigWithEnvPriority(filePath string) (*Config, error) {
 	// Start with default config
@@ -276,10 +971,37 @@ func LoadConfigWithEnvPriority(filePath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
 	}
 
-	// Parse YAML
+	// Environment references and encrypted values are replaced with the
+	// secrets they hold
+	data, err = ResolveSecrets(data, NewAgeCipher(nil, SecretsIdentity(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the secrets of config file %s: %w", configPath, err)
+	}
+
+	// Parse YAML; listen addresses come only from the file so the legacy
+	// single-address key is not shadowed by the default list
+	config.SshListenAddresses = nil
 	if err := yaml.Unmarshal(data, config); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML in config file %s: %w", configPath, err)
 	}
+	config.NormalizeListenAddresses()
+
+	return config, nil
+}
+
+// ParseConfig parses a configuration over the defaults as
+// LoadConfigWithEnvPriority does, but rejects keys hardn does not know.
+// Secret references are left as they are.
+func ParseConfig(data []byte) (*Config, error) {
+	config := DefaultConfig()
+	config.SshListenAddresses = nil
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && err != io.EOF {
+		return nil, err
+	}
+	config.NormalizeListenAddresses()
 
 	return config, nil
 }
@@ -343,6 +1065,45 @@ func LoadConfig(filePath string) (*Config, error) {
 	return cfg, nil
 }
 
+// ApplyLogging logs to the configured file and sinks from here on. A sink
+// that can not be opened is reported without stopping the command.
+func (c *Config) ApplyLogging() {
+	if err := logging.Configure(c.LoggingOptions()); err != nil {
+		logging.LogWarning("%v", err)
+	}
+}
+
+// LoggingOptions returns the logging options the configuration sets
+func (c *Config) LoggingOptions() logging.Options {
+	path := c.LogFile
+	if path == "" || path == DefaultConfig().LogFile {
+		path = DefaultLogLocation()
+	}
+	return logging.Options{
+		Path:       path,
+		Level:      c.Logging.Level,
+		Format:     c.Logging.Format,
+		MaxSize:    int64(c.Logging.MaxSizeMB) * 1024 * 1024,
+		MaxBackups: c.Logging.MaxBackups,
+		Sinks:      c.Logging.Sinks,
+	}
+}
+
+// DefaultLogLocation returns the log file used when the configuration
+// keeps the default: /var/log/hardn.log for root, and the user's state
+// directory for a normal user, who can not write /var/log
+func DefaultLogLocation() string {
+	if os.Geteuid() == 0 {
+		return DefaultConfig().LogFile
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "./hardn.log"
+	}
+	return filepath.Join(homeDir, ".local/state/hardn/hardn.log")
+}
+
 // GetDefaultConfigLocation returns the appropriate location for a new config file
 // based on whether the user is root or not
 func GetDefaultConfigLocation() string {
@@ -449,6 +1210,11 @@ func SaveConfig(config *Config, filePath string) error {
 		return fmt.Errorf("failed to marshal config to YAML: %w", err)
 	}
 
+	// Secrets loaded from references are written as the references
+	if data, err = protectSecrets(data); err != nil {
+		return fmt.Errorf("failed to marshal config to YAML: %w", err)
+	}
+
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(filePath)
 	if dir != "." {
@@ -20,6 +20,31 @@ logFile: "/var/log/hardn.log"     # Log file path
 dryRun: false                     # Preview changes without applying them
 enableBackups: true               # Backup files before modifying them
 backupPath: "/var/backups/hardn"  # Path to store backups
+backupCompression:
+  format: ""                      # gzip or zstd; empty stores plain copies
+  level: 0                        # gzip 1-9, zstd 1-19; 0 uses the default
+backupEncryption:
+  tool: ""                        # age or gpg; empty leaves backups unencrypted
+  recipient: ""                   # age public key or recipients file, or GnuPG key
+  identity: ""                    # age identity file used to decrypt on restore
+backupRemote:
+  url: ""                         # s3://bucket/prefix or sftp://user@host[:port]/path
+  endpoint: ""                    # S3-compatible endpoint, e.g. https://minio.example.com
+  profile: ""                     # AWS CLI profile with the S3 credentials
+  identityFile: ""                # SSH private key for SFTP
+  retentionDays: 0                # Remove remote backups older than this; 0 keeps all
+secrets:
+  recipients: []                  # age public keys 'hardn config encrypt' encrypts for
+  identity: ""                    # age identity decrypting ENC[age,...] values (default /etc/hardn/age.key)
+  keys: []                        # More keys to encrypt besides password, webhooks, sshKeys, *token and *secret
+snapshotBeforeRunAll: false       # Snapshot / (LVM thin, ZFS, btrfs) before --run-all
+environmentPlan: "auto"           # Tune --run-all to bare metal, VMs or containers: auto, none, or a forced environment
+logging:
+  level: "info"                   # debug, info, warn or error (--log-level overrides)
+  format: "text"                  # text or json (one object per line)
+  maxSizeMB: 10                   # Rotate the log file at this size (0 never rotates)
+  maxBackups: 3                   # Rotated files to keep (hardn.log.1, .2, ...)
+  sinks: []                       # Also send entries to syslog and/or journald
 
 #################################################
 # Network Configuration
@@ -28,6 +53,9 @@ dmzSubnet: "192.168.4"            # DMZ subnet for conditional package installat
 nameservers:                      # DNS servers to configure
   - "1.1.1.1"
   - "1.0.0.1"
+# httpProxy: "http://proxy.example.com:3128"  # Proxy for apt/apk and other commands
+offline: false                    # No update check, key imports or advisory downloads (air-gapped hosts)
+updateCheckHours: 24              # Hours between checks for a new hardn release
 
 #################################################
 # SSH Configuration
@@ -38,14 +66,22 @@ sshPort: 22                       # SSH port (this is the authoritative SSH port
 permitRootLogin: false            # Allow or deny root SSH access
 sshAllowedUsers:                  # List of users allowed to access via SSH
   - "george"
-sshListenAddress: "0.0.0.0"       # IP address to listen on
+sshAllowAllUsers: false           # Permit an empty or '*' sshAllowedUsers list (no AllowUsers restriction)
+sshListenAddresses:               # IP addresses to listen on (one ListenAddress per entry)
+  - "0.0.0.0"                     # 0.0.0.0 / :: listen on all interfaces; prefer specific IPs
 sshKeyPath: ".ssh_%u"             # Path to SSH keys (use %u for username substitution)
 sshConfigFile: "/etc/ssh/sshd_config.d/hardn.conf"  # SSH config file location
+sshProfile: "baseline"            # SSH hardening profile: baseline, strict or paranoid ("" for none)
+sshDropInConflicts: "warn"        # other sshd files contradicting hardn's: warn, or consolidate to disable them
+sshSafety:                        # guard against lockouts by changes to sshd or the firewall
+  enabled: true                   # roll the changes back unless SSH is confirmed to work
+  revertAfter: 300                # seconds to confirm with a new SSH session
 
 #################################################
 # User Configuration
 #################################################
 sudoNoPassword: true              # Whether to allow sudo without password
+localUsersOnly: false             # List only /etc/passwd users (skip LDAP/SSSD accounts via NSS)
 sshKeys:                          # SSH public keys to add for created users
   - "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAI... george@example.com"
   # Add more keys as needed
@@ -61,14 +97,200 @@ ufwAppProfiles:
     ports:
       - "30443/tcp" # non-standard 443
 
+# Rules built in the firewall menu; kept when the firewall is configured again
+# firewallRules:
+#   - action: allow               # allow, deny or reject
+#     protocol: udp               # tcp or udp
+#     port: 60000
+#     endPort: 61000              # last port of a range (optional)
+#     source: "10.0.0.0/8"        # IP address or network (optional)
+#     family: ipv4                # ipv4 or ipv6 only (optional; default both)
+#     comment: "mosh"
+
+sshRateLimit: false               # Rate-limit new SSH connections (ufw limit, or a firewalld rich rule)
+
+# Port knocking: SSH stays closed until an address knocks the sequence (knockd)
+portKnocking:
+  enabled: false
+#   sequence: [17301, 28442, 39553] # TCP ports knocked in order, at least 3
+  seqTimeout: 10                  # Seconds to knock the whole sequence
+  openTimeout: 30                 # Seconds SSH stays open to the address
+#   interface: eth0               # Interface knockd listens on (default: the default route's)
+
+# IPv6: filter (same rules as IPv4), deny (SSH and family: ipv6 rules only)
+# or disable (turned off in the kernel)
+ipv6:
+  mode: filter
+  bootParameter: false            # With disable, add ipv6.disable=1 to the kernel command line
+
 #################################################
 # Feature Toggles
 #################################################
-enableAppArmor: false             # Set up and enable AppArmor
+enableAppArmor: false             # Set up and enforce AppArmor
+enableSELinux: false              # Set SELinux to enforcing and install its policy packages
 enableLynis: false                # Install and run Lynis security audit
+enableUnattendedUpgrades: false   # Daily automatic upgrades (run-all / Auto Updates menu)
+autoUpdates:
+  origins: []                     # unattended-upgrades origins; empty for security updates only
+  blacklist: []                   # packages never upgraded automatically
+  reboot: false                   # reboot when an upgrade requires it
+  rebootTime: ""                  # HH:MM, default 02:00 (Debian/Ubuntu)
+  mail: ""                        # address upgrade reports are mailed to
+  mailOnlyOnError: false          # only mail failed upgrades
 enableUfwSshPolicy: false         # Configure UFW with SSH rules
 configureDns: false               # Configure DNS settings
 disableRootSSH: false             # Disable root SSH access
+# needrestartMode: "automatic"    # Debian/Ubuntu: automatic, list or interactive service restarts
+enableWebServerTls: false         # TLS baseline for detected nginx, Apache and Caddy
+
+# PostgreSQL and MySQL/MariaDB baseline (restarts detected database servers)
+databaseHardening:
+  enabled: false
+  bindAddresses:                  # listen addresses (default: 127.0.0.1)
+    - "127.0.0.1"
+  allowedSubnets: []              # networks allowed to the database port through UFW
+
+# Login banners and MOTD (run-all / hardn banner apply)
+banner:
+  enabled: false
+  issue: ""                       # /etc/issue text; empty for a standard warning
+  issueNet: ""                    # /etc/issue.net text; empty uses issue
+  motd: ""                        # MOTD template; placeholders {hostname}, {os},
+                                  # {last_run}, {risk_level}, {pending_updates}
+  dynamicMotd: true               # render the MOTD at login (Debian/Ubuntu), else after each run
+  disableMotdAds: true            # turn off Ubuntu's motd-news and advertising scripts
+  sshBanner: true                 # show /etc/issue.net before SSH login
+
+# Password policy (run-all / hardn password-policy apply); Alpine only applies the aging
+passwordPolicy:
+  enabled: false
+  minLength: 14                   # shortest accepted password (pam_pwquality)
+  digitCredit: -1                 # -N requires N digits
+  upperCredit: -1                 # -N requires N upper case letters
+  lowerCredit: -1                 # -N requires N lower case letters
+  otherCredit: -1                 # -N requires N symbols
+  retry: 3                        # prompts before passwd gives up
+  maxDays: 365                    # PASS_MAX_DAYS for new accounts (login.defs)
+  minDays: 1                      # PASS_MIN_DAYS for new accounts
+  warnAge: 7                      # PASS_WARN_AGE for new accounts
+  lockoutAttempts: 5              # failed logins before pam_faillock locks (0: off)
+  lockoutUnlockTime: 900          # seconds locked; 0 until "faillock --reset"
+
+# Kernel modules kept from loading (run-all / hardn modules apply)
+moduleBlacklist:
+  enabled: false
+  modules:                        # uncommon filesystems; squashfs is kept where snapd is installed
+    - cramfs
+    - freevxfs
+    - hfs
+    - hfsplus
+    - jffs2
+    - squashfs
+    - udf
+  usbStorage: false               # also block USB mass storage (usb-storage)
+
+# Insecure or unneeded packages reported by the audit (hardn --remove-insecure-packages removes them)
+debloat:
+  packages:                       # drop any the host needs, e.g. cups on a print server; other names are package names
+    - telnet
+    - rsh
+    - nis
+    - talk
+    - tftp
+    - xinetd
+    - avahi
+    - cups
+
+# Format of the repository lists hardn writes: auto follows the host (deb822 when it has
+# sources.list.d/debian.sources or ubuntu.sources), list for sources.list, deb822 for .sources files
+sourcesFormat: "auto"
+
+# Third-party repository keys (hardn sources keys); each download must match its fingerprint
+repositoryKeys: []
+#   - name: docker                # /etc/apt/keyrings/docker.asc and sources.list.d/docker.sources
+#     url: https://download.docker.com/linux/debian/gpg
+#     fingerprint: 9DC858229FC7DD38854AE2D88D81803C0EBFCD88
+#     repos:
+#       - "deb [arch=amd64] https://download.docker.com/linux/debian CODENAME stable"
+
+# Kernel command line and GRUB password (run-all / hardn boot apply); takes effect after a reboot
+bootHardening:
+  enabled: false
+  parameters:                     # replace a parameter of the same name, e.g. lockdown=integrity
+    - init_on_alloc=1             # zero memory when it is allocated
+    - page_poison=1               # poison memory when it is freed
+    - page_alloc.shuffle=1        # randomize the page allocator
+    - slab_nomerge                # keep slab caches apart
+    # - lockdown=confidentiality  # stops unsigned modules such as DKMS drivers from loading
+  password: ""                    # GRUB superuser password or grub.pbkdf2 hash; empty for none
+
+# Time synchronization (run-all / hardn timesync apply)
+timeSync:
+  enabled: false
+  daemon: ""                      # chrony, timesyncd or ntpd (Alpine); empty picks one, chrony with nts
+  servers:                        # NTS-capable public servers
+    - time.cloudflare.com
+    - nts.netnod.se
+    - ptbtime1.ptb.de
+  nts: true                       # authenticate the servers with NTS (chrony only)
+
+# Remote log forwarding over TLS (run-all / hardn log-shipping apply)
+logShipping:
+  enabled: false
+  method: rsyslog                 # rsyslog (syslog over TLS) or journald (systemd-journal-upload)
+  host: ""                        # collector; its certificate must carry this name
+  port: 0                         # 0 picks 6514 for rsyslog and 19532 for journald
+  caFile: ""                      # CA that signed the collector's certificate
+  certFile: ""                    # client certificate; required for journald
+  keyFile: ""                     # client key; required for journald
+
+# Proxmox VE hosts only (hardn proxmox apply / Proxmox menu)
+proxmox:
+  disableSubscriptionNag: false   # patch the "No valid subscription" dialog out of the web UI
+  webUIAllowlist: []              # addresses or networks allowed to the web UI (8006); empty leaves it open
+  hardenCiphers: false            # limit pveproxy to ECDHE AEAD ciphers
+  realmTwoFactor: false           # require TOTP for users of the pve realm
+
+# Certificate expiry monitoring (Let's Encrypt and web server directories are always checked)
+certificateMonitoring:
+  paths: []                       # extra certificate files or directories
+  expiryDays: 30                  # report certificates expiring within this many days
+  notify: false                   # show a notification in the main menu
+
+#################################################
+# Change Management
+#################################################
+maintenanceWindows: []            # Local-time windows for applying changes, e.g.
+  # - "Sat 02:00-04:00"           # a single day
+  # - "Mon-Fri 22:00-23:30"       # a day range; "daily" for every day
+                                  # Outside a window changes need --override-window
+
+# Timed runs installed with "hardn schedule install"
+schedule:
+  interval: "daily"               # hourly, daily, weekly or monthly
+  tasks:                          # run-all, disable-root, firewall, dns, ssh-keys, selftest, audit
+    - "audit"
+  watchdog:                       # check SSH after scheduled sshd or firewall changes
+    enabled: false                # roll the run back if SSH does not answer
+    helper: ""                    # probe from this ssh destination, e.g. "probe@bastion"
+    address: ""                   # this host's address as the helper reaches it
+    timeout: 60                   # seconds SSH may take to answer
+  summary:                        # posture summary sent through the notifications
+    cron: ""                      # when to send it, e.g. "0 8 * * 1" for Mondays at 08:00
+    days: 7                       # days the summary covers
+    inventory: ""                 # sum up the hosts of this inventory in one summary
+
+# Summaries sent after run-all and audits, including scheduled runs
+notifications:
+  onlyOnFailure: false            # skip summaries of successful runs
+  email:
+    host: ""                      # SMTP server; empty disables email
+    port: 587                     # STARTTLS is used when the server offers it
+    username: ""
+    password: ""                  # or set HARDN_SMTP_PASSWORD
+    from: ""                      # default hardn@<hostname>
+    to: []
+  webhooks: []                    # Slack, Discord or Teams incoming webhook URLs
 
 #################################################
 # Localization
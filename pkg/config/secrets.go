@@ -0,0 +1,312 @@
+// pkg/config/secrets.go
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/logging"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultAgeIdentity is the age identity decrypting the configuration's
+// secrets when neither HARDN_AGE_IDENTITY nor secrets.identity is set
+const DefaultAgeIdentity = "/etc/hardn/age.key"
+
+// envReference matches a value taken from an environment variable
+var envReference = regexp.MustCompile(`^\$\{env:([A-Za-z_][A-Za-z0-9_]*)\}$`)
+
+// encryptedValue matches a value encrypted by 'hardn config encrypt'
+var encryptedValue = regexp.MustCompile(`^ENC\[age,([A-Za-z0-9+/=]+)\]$`)
+
+// defaultSecretKeys are the keys whose values 'hardn config encrypt'
+// encrypts, besides keys ending in "token" or "secret" and secrets.keys
+var defaultSecretKeys = []string{"password", "webhooks", "sshKeys"}
+
+// Secrets represents how the sensitive values of the configuration are
+// encrypted. Any string value may also be "${env:NAME}", which is read from
+// the environment variable NAME when the configuration is loaded.
+type Secrets struct {
+	Recipients []string `yaml:"recipients"` // age public keys 'hardn config encrypt' encrypts for
+	Identity   string   `yaml:"identity"`   // age identity file; HARDN_AGE_IDENTITY overrides
+	Keys       []string `yaml:"keys"`       // more keys whose values are encrypted
+}
+
+// SecretCipher encrypts and decrypts the secret values of a configuration
+type SecretCipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// ageCipher runs age to encrypt for recipients and decrypt with an identity
+type ageCipher struct {
+	recipients []string
+	identity   string
+}
+
+// NewAgeCipher returns a cipher encrypting for the age recipients and
+// decrypting with the identity file
+func NewAgeCipher(recipients []string, identity string) SecretCipher {
+	return &ageCipher{recipients: recipients, identity: identity}
+}
+
+func (c *ageCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	if len(c.recipients) == 0 {
+		return nil, fmt.Errorf("no age recipients to encrypt for; set secrets.recipients or pass --recipient")
+	}
+	var args []string
+	for _, recipient := range c.recipients {
+		if strings.HasPrefix(recipient, "-") {
+			return nil, fmt.Errorf("invalid age recipient %q", recipient)
+		}
+		args = append(args, "-r", recipient)
+	}
+	return c.run(plaintext, args...)
+}
+
+func (c *ageCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	if _, err := os.Stat(c.identity); err != nil {
+		return nil, fmt.Errorf("age identity %s is needed to decrypt the configuration's secrets: %w", c.identity, err)
+	}
+	return c.run(ciphertext, "-d", "-i", c.identity)
+}
+
+// run runs age with input on stdin; stderr is kept out of the output
+func (c *ageCipher) run(input []byte, args ...string) ([]byte, error) {
+	if _, err := interfaces.LookPath("age"); err != nil {
+		return nil, fmt.Errorf("age is required for the configuration's encrypted secrets")
+	}
+	cmd := interfaces.Command("age", args...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("age failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
+
+// references maps the resolved secrets of the loaded configuration to the
+// references they were resolved from, so SaveConfig writes the references
+// back instead of the secrets
+var (
+	referencesMu sync.Mutex
+	references   = make(map[string]string)
+)
+
+// SecretsIdentity returns the age identity file that decrypts the secrets
+// of a configuration
+func SecretsIdentity(data []byte) string {
+	if identity := os.Getenv("HARDN_AGE_IDENTITY"); identity != "" {
+		return identity
+	}
+	var partial struct {
+		Secrets Secrets `yaml:"secrets"`
+	}
+	if err := yaml.Unmarshal(data, &partial); err == nil && partial.Secrets.Identity != "" {
+		return partial.Secrets.Identity
+	}
+	return DefaultAgeIdentity
+}
+
+// ResolveSecrets replaces environment references and encrypted values of
+// a configuration with the secrets they hold. The secrets are redacted
+// from the log and dry-run output from here on. The cipher is only used
+// when the configuration has encrypted values.
+func ResolveSecrets(data []byte, cipher SecretCipher) ([]byte, error) {
+	if !bytes.Contains(data, []byte("${env:")) && !bytes.Contains(data, []byte("ENC[")) {
+		return data, nil
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	resolved := make(map[string]string)
+	err := walkScalars(&root, "", func(key string, node *yaml.Node) error {
+		reference := node.Value
+		if match := envReference.FindStringSubmatch(reference); match != nil {
+			value, ok := os.LookupEnv(match[1])
+			if !ok {
+				return fmt.Errorf("%s refers to the unset environment variable %s", key, match[1])
+			}
+			node.Value = value
+		} else if match := encryptedValue.FindStringSubmatch(reference); match != nil {
+			ciphertext, err := base64.StdEncoding.DecodeString(match[1])
+			if err != nil {
+				return fmt.Errorf("%s holds an invalid encrypted value: %w", key, err)
+			}
+			plaintext, err := cipher.Decrypt(ciphertext)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt %s: %w", key, err)
+			}
+			node.Value = string(plaintext)
+		} else {
+			return nil
+		}
+		// Resolved again on decoding, so a reference may hold a number
+		node.Tag, node.Style = "", 0
+		resolved[node.Value] = reference
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	referencesMu.Lock()
+	for value, reference := range resolved {
+		references[value] = reference
+		logging.AddSecrets(value)
+	}
+	referencesMu.Unlock()
+
+	return yaml.Marshal(&root)
+}
+
+// protectSecrets puts the references of resolved secrets back in a
+// configuration about to be written
+func protectSecrets(data []byte) ([]byte, error) {
+	referencesMu.Lock()
+	defer referencesMu.Unlock()
+	if len(references) == 0 {
+		return data, nil
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	_ = walkScalars(&root, "", func(key string, node *yaml.Node) error {
+		if reference, ok := references[node.Value]; ok {
+			node.Value = reference
+			node.Tag, node.Style = "!!str", 0
+		}
+		return nil
+	})
+	return yaml.Marshal(&root)
+}
+
+// EncryptSecrets encrypts the values of the secret keys of a configuration
+// that are neither encrypted nor environment references, keeping its
+// comments. It returns the new configuration and how many values it
+// encrypted.
+func EncryptSecrets(data []byte, cipher SecretCipher, keys []string) ([]byte, int, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, 0, err
+	}
+
+	count := 0
+	err := walkScalars(&root, "", func(key string, node *yaml.Node) error {
+		if !IsSecretKey(key, keys) || node.Value == "" || node.Tag == "!!null" || node.Tag == "!!bool" ||
+			envReference.MatchString(node.Value) || encryptedValue.MatchString(node.Value) {
+			return nil
+		}
+		ciphertext, err := cipher.Encrypt([]byte(node.Value))
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", key, err)
+		}
+		node.Value = "ENC[age," + base64.StdEncoding.EncodeToString(ciphertext) + "]"
+		node.Tag, node.Style = "!!str", 0
+		count++
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return encodeNode(&root, data, count)
+}
+
+// DecryptSecrets turns the encrypted values of a configuration back into
+// plain text, keeping its comments. Environment references are left as
+// they are.
+func DecryptSecrets(data []byte, cipher SecretCipher) ([]byte, int, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, 0, err
+	}
+
+	count := 0
+	err := walkScalars(&root, "", func(key string, node *yaml.Node) error {
+		match := encryptedValue.FindStringSubmatch(node.Value)
+		if match == nil {
+			return nil
+		}
+		ciphertext, err := base64.StdEncoding.DecodeString(match[1])
+		if err != nil {
+			return fmt.Errorf("%s holds an invalid encrypted value: %w", key, err)
+		}
+		plaintext, err := cipher.Decrypt(ciphertext)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %s: %w", key, err)
+		}
+		node.Value = string(plaintext)
+		node.Tag, node.Style = "!!str", yaml.DoubleQuotedStyle
+		count++
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return encodeNode(&root, data, count)
+}
+
+// IsSecretKey reports whether the values of a key are secrets: password,
+// webhooks, sshKeys, keys ending in "token" or "secret", and extra keys
+func IsSecretKey(key string, extra []string) bool {
+	for _, secret := range append(append([]string{}, defaultSecretKeys...), extra...) {
+		if strings.EqualFold(key, secret) {
+			return true
+		}
+	}
+	lower := strings.ToLower(key)
+	return strings.HasSuffix(lower, "token") || strings.HasSuffix(lower, "secret")
+}
+
+// walkScalars calls visit for each scalar with the key of the mapping
+// entry it belongs to; list items belong to the list's key
+func walkScalars(node *yaml.Node, key string, visit func(key string, node *yaml.Node) error) error {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			if err := walkScalars(child, key, visit); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if err := walkScalars(node.Content[i+1], node.Content[i].Value, visit); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		return visit(key, node)
+	}
+	return nil
+}
+
+// encodeNode writes a configuration back with two-space indentation, or
+// returns it unchanged when nothing changed
+func encodeNode(root *yaml.Node, original []byte, count int) ([]byte, int, error) {
+	if count == 0 {
+		return original, 0, nil
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return nil, 0, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), count, nil
+}
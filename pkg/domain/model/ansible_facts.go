@@ -0,0 +1,9 @@
+// pkg/domain/model/ansible_facts.go
+package model
+
+// AnsibleFactsDir is where Ansible looks for local facts on managed hosts
+const AnsibleFactsDir = "/etc/ansible/facts.d"
+
+// AnsibleFactsPath is the facts script hardn installs; Ansible shows its
+// output as ansible_local.hardn
+const AnsibleFactsPath = AnsibleFactsDir + "/hardn.fact"
@@ -0,0 +1,411 @@
+// pkg/domain/model/apt_source.go
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Files APT reads its repositories from
+const (
+	AptSourcesListPath = "/etc/apt/sources.list"
+	AptSourcesDir      = "/etc/apt/sources.list.d"
+)
+
+// Formats hardn writes repositories in
+const (
+	SourcesFormatAuto   = "auto"   // follow the host: deb822 when its release file is a .sources file
+	SourcesFormatList   = "list"   // one-line entries in sources.list and .list files
+	SourcesFormatDeb822 = "deb822" // stanzas in .sources files
+)
+
+// ParseSourcesFormat returns the format a sourcesFormat setting names. An
+// empty or unknown setting is auto.
+func ParseSourcesFormat(setting string) string {
+	switch setting {
+	case SourcesFormatList, SourcesFormatDeb822:
+		return setting
+	}
+	return SourcesFormatAuto
+}
+
+// AptReleaseSourcesPath is the deb822 file holding the distribution's own
+// repositories, as written by the Debian 13 and Ubuntu 24.04 installers
+func AptReleaseSourcesPath(osType string) string {
+	return filepath.Join(AptSourcesDir, osType+".sources")
+}
+
+// AptListPath is the file of a named list in sources.list.d, such as ceph.list
+// or ceph.sources
+func AptListPath(name, format string) string {
+	if format == SourcesFormatDeb822 {
+		return filepath.Join(AptSourcesDir, name+".sources")
+	}
+	return filepath.Join(AptSourcesDir, name+".list")
+}
+
+// AptSource is a repository entry, from a one-line sources.list entry or a
+// deb822 stanza of a .sources file
+type AptSource struct {
+	Types      []string `json:"types"` // deb, deb-src
+	URIs       []string `json:"uris"`
+	Suites     []string `json:"suites"`
+	Components []string `json:"components,omitempty"`
+
+	// Options are keyed by their one-line name, such as signed-by or arch;
+	// lists are space separated as in deb822
+	Options map[string]string `json:"options,omitempty"`
+
+	Enabled bool `json:"enabled"`
+}
+
+// aptSourceFields maps one-line option names to their deb822 fields. APT
+// reads deb822 field names without regard to case, so other options are
+// written as they are.
+var aptSourceFields = map[string]string{
+	"arch":                        "Architectures",
+	"lang":                        "Languages",
+	"target":                      "Targets",
+	"pdiffs":                      "PDiffs",
+	"by-hash":                     "By-Hash",
+	"allow-insecure":              "Allow-Insecure",
+	"allow-weak":                  "Allow-Weak",
+	"allow-downgrade-to-insecure": "Allow-Downgrade-To-Insecure",
+	"trusted":                     "Trusted",
+	"signed-by":                   "Signed-By",
+	"check-valid-until":           "Check-Valid-Until",
+	"check-date":                  "Check-Date",
+}
+
+// SourcesMigration is a repository list converted to the other format
+type SourcesMigration struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Sources int    `json:"sources"`
+}
+
+// MigratedSourcesPath is the file a list moves to in format: sources.list
+// and the release .sources file swap, other lists keep their name
+func MigratedSourcesPath(path, format, osType string) string {
+	if format == SourcesFormatDeb822 {
+		if path == AptSourcesListPath {
+			return AptReleaseSourcesPath(osType)
+		}
+		return strings.TrimSuffix(path, ".list") + ".sources"
+	}
+	if path == AptReleaseSourcesPath(osType) {
+		return AptSourcesListPath
+	}
+	return strings.TrimSuffix(path, ".sources") + ".list"
+}
+
+// ParseAptSourceLine parses a one-line entry such as
+// "deb [arch=amd64 signed-by=/etc/apt/keyrings/docker.asc] https://download.docker.com/linux/debian bookworm stable".
+// Blank lines and comments return nil.
+func ParseAptSourceLine(line string) (*AptSource, error) {
+	line = strings.TrimSpace(line)
+	if i := strings.Index(line, "#"); i >= 0 {
+		line = strings.TrimSpace(line[:i])
+	}
+	if line == "" {
+		return nil, nil
+	}
+
+	kind, rest, _ := strings.Cut(line, " ")
+	if kind != "deb" && kind != "deb-src" {
+		return nil, fmt.Errorf("invalid repository %q: must start with deb or deb-src", line)
+	}
+	source := &AptSource{Types: []string{kind}, Enabled: true}
+
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, "[") {
+		end := strings.Index(rest, "]")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid repository %q: options are not closed with ]", line)
+		}
+		source.Options = make(map[string]string)
+		for _, option := range strings.Fields(rest[1:end]) {
+			key, value, _ := strings.Cut(option, "=")
+			source.Options[strings.ToLower(key)] = strings.ReplaceAll(value, ",", " ")
+		}
+		rest = rest[end+1:]
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("invalid repository %q: expected a URI and a suite", line)
+	}
+	if !strings.Contains(fields[0], "://") && !strings.HasPrefix(fields[0], "cdrom:") {
+		return nil, fmt.Errorf("invalid repository %q: %s is not a URI", line, fields[0])
+	}
+	source.URIs = []string{fields[0]}
+	source.Suites = []string{fields[1]}
+	source.Components = fields[2:]
+	return source, nil
+}
+
+// ParseAptSourceEntry parses a configured repository. Unlike
+// ParseAptSourceLine it reads a commented-out entry such as the Proxmox
+// "#deb https://enterprise.proxmox.com/debian/pve CODENAME pve-enterprise"
+// as a disabled source; other comments return nil.
+func ParseAptSourceEntry(line string) (*AptSource, error) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "#") {
+		return ParseAptSourceLine(trimmed)
+	}
+	source, err := ParseAptSourceLine(strings.TrimLeft(trimmed, "# \t"))
+	if err != nil || source == nil {
+		return nil, nil
+	}
+	source.Enabled = false
+	return source, nil
+}
+
+// ParseAptSourcesFile parses a .sources file as deb822 and any other file
+// as one-line entries. With disabled, commented-out entries are kept as
+// disabled sources; lines APT would reject are skipped.
+func ParseAptSourcesFile(file AptFile, disabled bool) []AptSource {
+	if strings.HasSuffix(file.Path, ".sources") {
+		return ParseDeb822Sources(file.Data)
+	}
+	parse := ParseAptSourceLine
+	if disabled {
+		parse = ParseAptSourceEntry
+	}
+	var sources []AptSource
+	for _, line := range strings.Split(string(file.Data), "\n") {
+		if source, err := parse(line); err == nil && source != nil {
+			sources = append(sources, *source)
+		}
+	}
+	return sources
+}
+
+// ParseDeb822Sources parses the stanzas of a .sources file. Values may
+// continue on lines starting with a space, where a lone "." is an empty
+// line, as in an inline Signed-By key.
+func ParseDeb822Sources(data []byte) []AptSource {
+	var sources []AptSource
+	fields := make(map[string]string)
+	last := ""
+
+	flush := func() {
+		if len(fields) > 0 {
+			sources = append(sources, deb822Source(fields))
+		}
+		fields = make(map[string]string)
+		last = ""
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+		case strings.HasPrefix(line, "#"):
+			continue
+		case line[0] == ' ' || line[0] == '\t':
+			if last == "" {
+				continue
+			}
+			value := strings.TrimSpace(line)
+			if value == "." {
+				value = ""
+			}
+			fields[last] += "\n" + value
+		default:
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			last = strings.ToLower(strings.TrimSpace(key))
+			fields[last] = strings.TrimSpace(value)
+		}
+	}
+	flush()
+	return sources
+}
+
+// deb822Source converts the fields of a stanza, keyed in lower case
+func deb822Source(fields map[string]string) AptSource {
+	source := AptSource{Enabled: true, Options: make(map[string]string)}
+	for key, value := range fields {
+		switch key {
+		case "types":
+			source.Types = strings.Fields(value)
+		case "uris":
+			source.URIs = strings.Fields(value)
+		case "suites":
+			source.Suites = strings.Fields(value)
+		case "components":
+			source.Components = strings.Fields(value)
+		case "enabled":
+			source.Enabled = AptBool(value)
+		default:
+			source.Options[aptSourceOption(key)] = value
+		}
+	}
+	if len(source.Options) == 0 {
+		source.Options = nil
+	}
+	return source
+}
+
+// aptSourceOption returns the one-line name of a deb822 field
+func aptSourceOption(field string) string {
+	for option, name := range aptSourceFields {
+		if strings.EqualFold(name, field) {
+			return option
+		}
+	}
+	return strings.ToLower(field)
+}
+
+// Deb822 formats the source as a deb822 stanza
+func (s AptSource) Deb822() string {
+	var b strings.Builder
+	b.WriteString("Types: " + strings.Join(s.Types, " ") + "\n")
+	b.WriteString("URIs: " + strings.Join(s.URIs, " ") + "\n")
+	b.WriteString("Suites: " + strings.Join(s.Suites, " ") + "\n")
+	if len(s.Components) > 0 {
+		b.WriteString("Components: " + strings.Join(s.Components, " ") + "\n")
+	}
+	if !s.Enabled {
+		b.WriteString("Enabled: no\n")
+	}
+
+	options := make([]string, 0, len(s.Options))
+	for option := range s.Options {
+		options = append(options, option)
+	}
+	sort.Strings(options)
+	for _, option := range options {
+		field := aptSourceFields[option]
+		if field == "" {
+			field = option
+		}
+		// Continuation lines keep multi-line values such as an inline key
+		lines := strings.Split(s.Options[option], "\n")
+		for i := 1; i < len(lines); i++ {
+			if lines[i] == "" {
+				lines[i] = "."
+			}
+		}
+		b.WriteString(field + ": " + strings.Join(lines, "\n ") + "\n")
+	}
+	return b.String()
+}
+
+// Lines formats the source as one-line entries, one for each type, URI
+// and suite, commented out when the source is disabled. Values spanning
+// lines, such as an inline Signed-By key, have no one-line form.
+func (s AptSource) Lines() ([]string, error) {
+	options := make([]string, 0, len(s.Options))
+	for option, value := range s.Options {
+		if strings.Contains(value, "\n") {
+			return nil, fmt.Errorf("%s of %s spans several lines and cannot be written as a one-line entry",
+				option, strings.Join(s.URIs, " "))
+		}
+		options = append(options, option+"="+strings.Join(strings.Fields(value), ","))
+	}
+	sort.Strings(options)
+
+	prefix := ""
+	if !s.Enabled {
+		prefix = "# "
+	}
+	var lines []string
+	for _, kind := range s.Types {
+		for _, uri := range s.URIs {
+			for _, suite := range s.Suites {
+				fields := []string{kind}
+				if len(options) > 0 {
+					fields = append(fields, "["+strings.Join(options, " ")+"]")
+				}
+				fields = append(append(fields, uri, suite), s.Components...)
+				lines = append(lines, prefix+strings.Join(fields, " "))
+			}
+		}
+	}
+	return lines, nil
+}
+
+// MergeAptSources joins sources that differ only in their type, such as a
+// deb entry and the deb-src entry after it, into one stanza
+func MergeAptSources(sources []AptSource) []AptSource {
+	var merged []AptSource
+	for _, source := range sources {
+		if n := len(merged); n > 0 && sameAptRepository(merged[n-1], source) {
+			for _, kind := range source.Types {
+				if !slices.Contains(merged[n-1].Types, kind) {
+					merged[n-1].Types = append(merged[n-1].Types, kind)
+				}
+			}
+			continue
+		}
+		source.Types = append([]string{}, source.Types...)
+		merged = append(merged, source)
+	}
+	return merged
+}
+
+// sameAptRepository compares everything but the types of two sources
+func sameAptRepository(a, b AptSource) bool {
+	if a.Enabled != b.Enabled || len(a.Options) != len(b.Options) ||
+		strings.Join(a.URIs, " ") != strings.Join(b.URIs, " ") ||
+		strings.Join(a.Suites, " ") != strings.Join(b.Suites, " ") ||
+		strings.Join(a.Components, " ") != strings.Join(b.Components, " ") {
+		return false
+	}
+	for option, value := range a.Options {
+		if other, ok := b.Options[option]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatAptSources formats sources in a file of the given format, after
+// a header comment when one is given
+func FormatAptSources(sources []AptSource, format, header string) (string, error) {
+	var b strings.Builder
+	if header != "" {
+		b.WriteString("# " + header + "\n")
+	}
+	if format == SourcesFormatDeb822 {
+		for i, source := range MergeAptSources(sources) {
+			if i > 0 || header != "" {
+				b.WriteString("\n")
+			}
+			b.WriteString(source.Deb822())
+		}
+		return b.String(), nil
+	}
+
+	for _, source := range sources {
+		lines, err := source.Lines()
+		if err != nil {
+			return "", err
+		}
+		for _, line := range lines {
+			b.WriteString(line + "\n")
+		}
+	}
+	return b.String(), nil
+}
+
+// Trusted reports whether APT skips the signature check of the source
+func (s AptSource) Trusted() bool {
+	return AptBool(s.Options["trusted"])
+}
+
+// AptBool reads a boolean as APT does
+func AptBool(value string) bool {
+	switch strings.ToLower(strings.Trim(strings.TrimSpace(value), `"`)) {
+	case "yes", "true", "with", "on", "enable", "1":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+// pkg/domain/model/audit.go
+package model
+
+import "time"
+
+// AuditReportFormat is the version of the audit report document
+const AuditReportFormat = 1
+
+// Control states recorded in audit reports
+const (
+	ControlPass   = "pass"
+	ControlFail   = "fail"
+	ControlAbsent = "absent"
+)
+
+// AuditReport is a point-in-time record of the host's hardening state
+type AuditReport struct {
+	Format       int               `json:"format"`
+	HardnVersion string            `json:"hardn_version"`
+	Hostname     string            `json:"hostname"`
+	GeneratedAt  time.Time         `json:"generated_at"`
+	Controls     []AuditControl    `json:"controls"`
+	Findings     []PolicyViolation `json:"findings"`
+}
+
+// AuditControl is the state of one hardening control
+type AuditControl struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+// AuditControlChange records a control whose state differs between reports
+type AuditControlChange struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Regressed reports whether the control went from passing to anything else
+func (c AuditControlChange) Regressed() bool {
+	return c.Before == ControlPass && c.After != ControlPass
+}
+
+// AuditDiff is the comparison of two audit reports
+type AuditDiff struct {
+	Before           *AuditReport         `json:"-"`
+	After            *AuditReport         `json:"-"`
+	ChangedControls  []AuditControlChange `json:"changed_controls"`
+	NewFindings      []PolicyViolation    `json:"new_findings"`
+	ResolvedFindings []PolicyViolation    `json:"resolved_findings"`
+}
+
+// HasRegressions reports whether a control regressed or a finding appeared
+func (d *AuditDiff) HasRegressions() bool {
+	if len(d.NewFindings) > 0 {
+		return true
+	}
+	for _, change := range d.ChangedControls {
+		if change.Regressed() {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckInfo describes a status or audit check for `hardn explain`. IDs
+// with a <placeholder> segment stand for one control per instance, e.g.
+// webserver.<server>.tls_baseline.
+type CheckInfo struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Label       string   `json:"label,omitempty"` // label in the status display
+	Inspects    string   `json:"inspects"`
+	Why         string   `json:"why"`
+	Remediation string   `json:"remediation"`
+	Files       []string `json:"files,omitempty"`
+	Commands    []string `json:"commands,omitempty"`
+	Audited     bool     `json:"audited"` // reported as an audit control
+}
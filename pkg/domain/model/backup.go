@@ -1,12 +1,78 @@
 // pkg/domain/model/backup.go
 package model
 
-import "time"
+import (
+	"bytes"
+	"time"
+)
+
+// Backup compression formats
+const (
+	BackupGzip = "gzip"
+	BackupZstd = "zstd"
+)
+
+// Backup encryption tools
+const (
+	BackupAge = "age"
+	BackupGPG = "gpg"
+)
 
 // BackupConfig represents backup configuration settings
 type BackupConfig struct {
 	Enabled   bool   // Whether backups are enabled
 	BackupDir string // Directory to store backups
+
+	Compression      string // BackupGzip or BackupZstd; empty stores plain copies
+	CompressionLevel int    // 0 uses the format's default level
+	Encryption       string // BackupAge or BackupGPG; empty leaves backups unencrypted
+	Recipient        string // age public key or recipients file, or GnuPG key ID or email
+	Identity         string // age identity file decrypting backups on restore
+
+	Remote BackupRemote // where backups are mirrored after each run
+}
+
+// Extension returns the suffix of new backups, such as ".bak.zst.age"
+func (c BackupConfig) Extension() string {
+	extension := ".bak"
+	switch c.Compression {
+	case BackupGzip:
+		extension += ".gz"
+	case BackupZstd:
+		extension += ".zst"
+	}
+	if c.Encryption != "" {
+		extension += "." + c.Encryption
+	}
+	return extension
+}
+
+// Remote backup target schemes
+const (
+	BackupTargetS3   = "s3"
+	BackupTargetSFTP = "sftp"
+)
+
+// BackupRemote is an S3-compatible bucket or SFTP host the backup
+// directory is mirrored to, one date directory at a time
+type BackupRemote struct {
+	URL           string // s3://bucket/prefix or sftp://user@host[:port]/path
+	Endpoint      string // endpoint of an S3-compatible service other than AWS
+	Profile       string // AWS CLI profile holding the S3 credentials
+	IdentityFile  string // SSH private key for SFTP
+	RetentionDays int    // remote backups older than this are removed; 0 keeps them
+}
+
+// Configured reports whether a remote target is set
+func (r BackupRemote) Configured() bool {
+	return r.URL != ""
+}
+
+// BackupSyncResult describes a sync of the backups to a remote target
+type BackupSyncResult struct {
+	Target   string   `json:"target"`
+	Uploaded []string `json:"uploaded"` // date directories copied to the target
+	Removed  []string `json:"removed"`  // remote date directories past the retention
 }
 
 // BackupFile represents information about a backed up file
@@ -15,4 +81,46 @@ type BackupFile struct {
 	BackupPath   string    // Full path to the backup
 	Created      time.Time // When the backup was created
 	Size         int64     // Size of the backup in bytes
+	Compression  string    // BackupGzip, BackupZstd or empty
+	Encryption   string    // BackupAge, BackupGPG or empty
+}
+
+// BackupGroup is the backups of one file, newest first. OriginalPath is
+// empty for backups that do not record where the file came from; Name is
+// the file's base name either way.
+type BackupGroup struct {
+	OriginalPath string
+	Name         string
+	Backups      []BackupFile
+}
+
+// BackupComparison holds a backup and the live file restoring it would
+// replace
+type BackupComparison struct {
+	BackupPath    string
+	OriginalPath  string
+	Backup        []byte // decrypted and decompressed
+	Current       []byte
+	CurrentExists bool
+}
+
+// Identical reports whether restoring the backup would change nothing
+func (c BackupComparison) Identical() bool {
+	return c.CurrentExists && bytes.Equal(c.Backup, c.Current)
+}
+
+// BackupKeyStatus reports whether backups can be encrypted for the
+// recipient and decrypted again on this host
+type BackupKeyStatus struct {
+	Tool       string // BackupAge or BackupGPG
+	Recipient  string
+	Installed  bool   // the tool is installed
+	CanEncrypt bool   // a test file was encrypted for the recipient
+	CanDecrypt bool   // the test file was decrypted with this host's keys
+	Problem    string // why the first failed step failed
+}
+
+// Ready reports whether backups can be both written and restored
+func (s BackupKeyStatus) Ready() bool {
+	return s.Installed && s.CanEncrypt && s.CanDecrypt
 }
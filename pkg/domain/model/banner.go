@@ -0,0 +1,110 @@
+// pkg/domain/model/banner.go
+package model
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Login banner files: getty shows /etc/issue on local consoles, sshd shows
+// /etc/issue.net when its Banner directive names it, and login prints
+// /etc/motd after authentication
+const (
+	IssuePath    = "/etc/issue"
+	IssueNetPath = "/etc/issue.net"
+	MotdPath     = "/etc/motd"
+)
+
+// On Debian and Ubuntu pam_motd runs the scripts in /etc/update-motd.d at
+// each login; hardn adds one that prints its MOTD template
+const (
+	UpdateMotdDir        = "/etc/update-motd.d"
+	UpdateMotdScriptPath = "/etc/update-motd.d/60-hardn"
+	UbuntuMotdNewsPath   = "/etc/default/motd-news"
+)
+
+// UbuntuMotdAdScripts are the update-motd scripts that advertise Ubuntu
+// Pro, news and help links rather than report on the host
+var UbuntuMotdAdScripts = []string{
+	"10-help-text",
+	"50-motd-news",
+	"88-esm-announce",
+	"91-contract-ua-esm-status",
+}
+
+// DefaultLoginBanner is written to /etc/issue and /etc/issue.net when no
+// text is configured
+const DefaultLoginBanner = `Authorized access only. Activity on this system is monitored and recorded.
+Disconnect now if you are not an authorized user.
+`
+
+// DefaultMotdTemplate is the MOTD shown when no template is configured
+const DefaultMotdTemplate = `{hostname} is managed by hardn
+  Last hardening run: {last_run}
+  Risk level:         {risk_level}
+  Pending updates:    {pending_updates}
+`
+
+// BannerPolicy is the text of the login banners and the MOTD. Each text may
+// use the placeholders of MotdFacts.
+type BannerPolicy struct {
+	Issue    string
+	IssueNet string // empty uses Issue
+	Motd     string
+
+	// DynamicMotd shows Motd at each login on Debian and Ubuntu; elsewhere
+	// /etc/motd is rewritten after each hardening run
+	DynamicMotd bool
+	// DisableMotdAds turns off Ubuntu's advertising MOTD scripts
+	DisableMotdAds bool
+	// SSHBanner makes sshd show /etc/issue.net before authentication
+	SSHBanner bool
+}
+
+// MotdFacts are the values substituted for the placeholders of banner and
+// MOTD templates
+type MotdFacts struct {
+	Hostname       string
+	OS             string
+	LastRun        time.Time // zero when no run is recorded
+	RiskLevel      string
+	PendingUpdates int // -1 when unknown
+}
+
+// Replace substitutes facts for the placeholders of template
+func (f MotdFacts) Replace(template string) string {
+	lastRun := "never"
+	if !f.LastRun.IsZero() {
+		lastRun = f.LastRun.Format("2006-01-02 15:04")
+	}
+	pending := "unknown"
+	if f.PendingUpdates >= 0 {
+		pending = strconv.Itoa(f.PendingUpdates)
+	}
+	risk := f.RiskLevel
+	if risk == "" {
+		risk = "Unknown"
+	}
+
+	return strings.NewReplacer(
+		"{hostname}", f.Hostname,
+		"{os}", f.OS,
+		"{last_run}", lastRun,
+		"{risk_level}", risk,
+		"{pending_updates}", pending,
+	).Replace(template)
+}
+
+// BannerStatus reports the login banners on the host
+type BannerStatus struct {
+	Issue      bool // /etc/issue exists
+	IssueNet   bool // /etc/issue.net exists
+	UpdateMotd bool // pam_motd runs /etc/update-motd.d
+	// DynamicMotd is set when the hardn update-motd script is installed
+	DynamicMotd bool
+	// MotdAds lists Ubuntu's advertising MOTD scripts still enabled
+	MotdAds []string
+	// SSHBanner is the file sshd shows before authentication, if any
+	SSHBanner string
+}
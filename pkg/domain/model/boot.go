@@ -0,0 +1,82 @@
+// pkg/domain/model/boot.go
+package model
+
+import "strings"
+
+// Files of the GRUB boot loader hardn manages besides /etc/default/grub
+const (
+	// GrubPasswordScriptPath adds the GRUB superuser on Debian and Ubuntu;
+	// grub-mkconfig runs the scripts of /etc/grub.d in order
+	GrubPasswordScriptPath = "/etc/grub.d/01_hardn_password"
+
+	// GrubLinuxScriptPath generates the Linux boot entries on Debian and
+	// Ubuntu; its entries are marked unrestricted so booting them needs no
+	// password, only editing them does
+	GrubLinuxScriptPath = "/etc/grub.d/10_linux"
+
+	// GrubConfigPath is the configuration grub-mkconfig generates on
+	// Debian and Ubuntu
+	GrubConfigPath = "/boot/grub/grub.cfg"
+
+	// GrubUserConfigPath holds the password hash grub2-setpassword writes
+	// on the RHEL family, where boot entries are unrestricted already
+	GrubUserConfigPath = "/boot/grub2/user.cfg"
+
+	// GrubSuperuser is the user the GRUB password is set for
+	GrubSuperuser = "root"
+
+	// GrubPasswordHashPrefix starts the PBKDF2 hashes grub-mkpasswd-pbkdf2
+	// prints
+	GrubPasswordHashPrefix = "grub.pbkdf2."
+)
+
+// DefaultBootParameters harden the kernel without limiting what it can
+// run: memory is zeroed when allocated and poisoned when freed, the page
+// allocator is randomized and slab caches are not merged. lockdown is
+// left to the configuration, since it keeps unsigned modules such as
+// DKMS-built drivers from loading.
+var DefaultBootParameters = []string{
+	"init_on_alloc=1",
+	"page_poison=1",
+	"page_alloc.shuffle=1",
+	"slab_nomerge",
+}
+
+// BootHardeningPolicy is what hardn sets in the boot loader
+type BootHardeningPolicy struct {
+	// Kernel command line parameters; one with the name of an existing
+	// parameter replaces it
+	Parameters []string
+
+	// GRUB superuser password, as a grub.pbkdf2 hash or in plain text to
+	// be hashed; empty leaves the boot loader without a password
+	Password string
+}
+
+// BootHardeningStatus reports the boot hardening in effect for a policy
+type BootHardeningStatus struct {
+	// Configured lists the parameters of the policy in the GRUB configuration
+	Configured []string `json:"configured"`
+	// Missing lists the parameters of the policy not in the GRUB configuration
+	Missing []string `json:"missing,omitempty"`
+	// Pending lists the configured parameters the running kernel did not
+	// boot with
+	Pending []string `json:"pending,omitempty"`
+	// PasswordSet reports whether GRUB asks for a password to edit entries
+	PasswordSet bool `json:"password_set"`
+	// RebootRequired is set while parameters are pending
+	RebootRequired bool `json:"reboot_required,omitempty"`
+}
+
+// Compliant reports whether every parameter of the policy is configured
+// and in effect
+func (s BootHardeningStatus) Compliant() bool {
+	return len(s.Missing) == 0 && len(s.Pending) == 0
+}
+
+// BootParameterName returns the name of a kernel parameter: the part
+// before "=", or the whole parameter for a flag such as slab_nomerge
+func BootParameterName(parameter string) string {
+	name, _, _ := strings.Cut(parameter, "=")
+	return name
+}
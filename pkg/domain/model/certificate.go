@@ -0,0 +1,40 @@
+// pkg/domain/model/certificate.go
+package model
+
+import "time"
+
+// DefaultCertificateExpiryDays is how close to expiry a certificate must be
+// before it is reported when no threshold is configured
+const DefaultCertificateExpiryDays = 30
+
+// CommonCertificatePaths are checked in addition to configured paths;
+// directories are searched for .pem, .crt and .cer files
+var CommonCertificatePaths = []string{
+	"/etc/letsencrypt/live",
+	"/etc/nginx/ssl",
+	"/etc/apache2/ssl",
+	"/etc/ssl/localcerts",
+	"/etc/caddy/certs",
+}
+
+// Certificate is the leaf certificate found in a PEM file
+type Certificate struct {
+	Path     string    `json:"path"`
+	Subject  string    `json:"subject"`
+	Issuer   string    `json:"issuer"`
+	Serial   string    `json:"serial"`
+	NotAfter time.Time `json:"not_after"`
+	DaysLeft int       `json:"days_left"`
+}
+
+// Expired reports whether the certificate is no longer valid
+func (c Certificate) Expired() bool {
+	return c.DaysLeft < 0
+}
+
+// CertificateExpiry is the result of checking certificates for expiry
+type CertificateExpiry struct {
+	Checked       int           `json:"checked"`
+	ThresholdDays int           `json:"threshold_days"`
+	Expiring      []Certificate `json:"expiring"`
+}
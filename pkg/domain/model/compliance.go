@@ -0,0 +1,89 @@
+// pkg/domain/model/compliance.go
+package model
+
+import "time"
+
+// Benchmarks the compliance report maps its checks to. Both editions share
+// the numbering of the recommendations below.
+const (
+	BenchmarkCISDebian = "CIS Debian Linux 12 Benchmark v1.1.0"
+	BenchmarkCISUbuntu = "CIS Ubuntu Linux 24.04 LTS Benchmark v1.0.0"
+)
+
+// States of a benchmark recommendation in a compliance report
+const (
+	CompliancePass          = "pass"
+	ComplianceFail          = "fail"
+	ComplianceNotApplicable = "n/a"
+)
+
+// ComplianceRule maps a benchmark recommendation to the hardn check that
+// verifies it and the action that remediates it
+type ComplianceRule struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Level       int    `json:"level"`
+	Check       string `json:"check"`
+	Remediation string `json:"remediation"`
+}
+
+// CISRules are the CIS Debian and Ubuntu recommendations hardn checks, in
+// benchmark order. Checks that are audit controls reuse the control's
+// result; the others are evaluated by the compliance service.
+var CISRules = []ComplianceRule{
+	{ID: "1.3.1.2", Title: "Ensure AppArmor is enabled", Level: 1,
+		Check: "apparmor.enabled", Remediation: "hardn mac enforce --framework apparmor"},
+	{ID: "2.3.1.1", Title: "Ensure a single time synchronization daemon is in use", Level: 1,
+		Check: "system.time_sync", Remediation: "apt-get install systemd-timesyncd, or chrony"},
+	{ID: "4.2.1", Title: "Ensure ufw is installed", Level: 1,
+		Check: "firewall.ufw_installed", Remediation: "hardn firewall enable"},
+	{ID: "4.2.3", Title: "Ensure ufw service is enabled", Level: 1,
+		Check: "firewall.enabled", Remediation: "hardn firewall enable"},
+	{ID: "4.2.7", Title: "Ensure ufw default deny firewall policy", Level: 1,
+		Check: "firewall.default_deny", Remediation: "hardn firewall enable"},
+	{ID: "5.1.1", Title: "Ensure permissions on /etc/ssh/sshd_config are configured", Level: 1,
+		Check: "ssh.config_permissions", Remediation: "chmod 600 /etc/ssh/sshd_config"},
+	{ID: "5.1.4", Title: "Ensure sshd access is configured", Level: 1,
+		Check: "ssh.access_limited", Remediation: "hardn ssh harden --allow-user NAME"},
+	{ID: "5.1.16", Title: "Ensure sshd MaxAuthTries is configured", Level: 1,
+		Check: "ssh.max_auth_tries", Remediation: "hardn ssh profile baseline"},
+	{ID: "5.1.19", Title: "Ensure sshd PermitEmptyPasswords is disabled", Level: 1,
+		Check: "ssh.empty_passwords_disabled", Remediation: "hardn ssh harden"},
+	{ID: "5.1.20", Title: "Ensure sshd PermitRootLogin is disabled", Level: 1,
+		Check: "ssh.root_login_disabled", Remediation: "hardn ssh disable-root"},
+	{ID: "5.1.21", Title: "Ensure sshd PermitUserEnvironment is disabled", Level: 1,
+		Check: "ssh.user_environment_disabled", Remediation: "Set PermitUserEnvironment no in /etc/ssh/sshd_config"},
+	{ID: "5.2.1", Title: "Ensure sudo is installed", Level: 1,
+		Check: "users.sudo_installed", Remediation: "apt-get install sudo, then hardn user create NAME --sudo"},
+	{ID: "5.2.4", Title: "Ensure users must provide password for privilege escalation", Level: 2,
+		Check: "users.sudo_method", Remediation: "Remove NOPASSWD from the sudoers entries listed"},
+	{ID: "5.2.5", Title: "Ensure re-authentication for privilege escalation is not disabled globally", Level: 1,
+		Check: "users.sudo_reauthentication", Remediation: "Remove !authenticate from the sudoers entries listed"},
+}
+
+// ComplianceRequest is the input of a compliance run: the host, and the
+// audit controls already evaluated for it
+type ComplianceRequest struct {
+	Hostname string
+	OSType   string
+	Controls []AuditControl
+}
+
+// ComplianceResult is the state of one recommendation on the host
+type ComplianceResult struct {
+	ComplianceRule
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ComplianceReport is a scored run of the benchmark recommendations
+type ComplianceReport struct {
+	Benchmark     string             `json:"benchmark"`
+	Hostname      string             `json:"hostname"`
+	GeneratedAt   time.Time          `json:"generated_at"`
+	Score         int                `json:"score"`
+	Passed        int                `json:"passed"`
+	Failed        int                `json:"failed"`
+	NotApplicable int                `json:"not_applicable"`
+	Results       []ComplianceResult `json:"results"`
+}
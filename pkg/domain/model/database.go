@@ -0,0 +1,31 @@
+// pkg/domain/model/database.go
+package model
+
+// Database servers hardn can apply a baseline to
+const (
+	DatabasePostgreSQL = "postgresql"
+	DatabaseMySQL      = "mysql"
+)
+
+// DatabaseBaselineMarker identifies configuration drop-ins written by hardn
+const DatabaseBaselineMarker = "# Managed by hardn: database baseline"
+
+// DatabaseServer describes an installed database server
+type DatabaseServer struct {
+	Name string `json:"name"`
+	// Flavor distinguishes MariaDB from MySQL; empty for PostgreSQL
+	Flavor string `json:"flavor,omitempty"`
+	// Instance is the PostgreSQL cluster, e.g. "15/main"
+	Instance        string `json:"instance,omitempty"`
+	Port            int    `json:"port"`
+	ConfigPath      string `json:"config_path"`
+	BaselineApplied bool   `json:"baseline_applied"`
+}
+
+// DatabaseHardening holds the operator's database baseline settings
+type DatabaseHardening struct {
+	// Addresses the server listens on; defaults to loopback only
+	BindAddresses []string
+	// Networks allowed through the firewall to the database port
+	AllowedSubnets []string
+}
@@ -0,0 +1,161 @@
+// pkg/domain/model/debloat.go
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// What removing an insecure package does on this host
+const (
+	// DebloatRemove removes the installed packages
+	DebloatRemove = "remove"
+	// DebloatDisable stops and disables the services, since removing the
+	// packages would take other packages with them
+	DebloatDisable = "disable"
+	// DebloatManual is left to the administrator: the packages have
+	// dependents and no service to disable
+	DebloatManual = "manual"
+)
+
+// DebloatItem is a package, or a family of packages, that is risky or
+// rarely needed on a server
+type DebloatItem struct {
+	Name     string   `json:"name"`
+	Reason   string   `json:"reason"`
+	Severity string   `json:"severity"`
+	Packages []string `json:"packages"` // package names across distributions
+	Services []string `json:"services"` // systemd units and OpenRC services
+}
+
+// DebloatCatalog describes the packages hardn knows to be risky. Clear
+// text remote shells rank medium; the rest widen the attack surface of a
+// server that does not need them.
+var DebloatCatalog = []DebloatItem{
+	{
+		Name:     "telnet",
+		Reason:   "sends passwords and sessions in clear text",
+		Severity: SeverityMedium,
+		Packages: []string{"telnetd", "inetutils-telnetd", "telnetd-ssl", "telnet-server", "telnet", "inetutils-telnet", "busybox-extras"},
+		Services: []string{"telnet.socket"},
+	},
+	{
+		Name:     "rsh",
+		Reason:   "rsh, rlogin and rexec send passwords in clear text and trust host names",
+		Severity: SeverityMedium,
+		Packages: []string{"rsh-server", "rsh-redone-server", "rsh-client", "rsh-redone-client", "rsh"},
+		Services: []string{"rsh.socket", "rlogin.socket", "rexec.socket"},
+	},
+	{
+		Name:     "nis",
+		Reason:   "NIS serves password hashes to any host that asks",
+		Severity: SeverityMedium,
+		Packages: []string{"nis", "ypserv", "ypbind", "ypbind-mt", "yp-tools"},
+		Services: []string{"ypserv", "ypbind", "nis"},
+	},
+	{
+		Name:     "talk",
+		Reason:   "talk accepts unauthenticated connections from the network",
+		Severity: SeverityLow,
+		Packages: []string{"talkd", "inetutils-talkd", "talk-server", "talk", "inetutils-talk"},
+		Services: []string{"ntalk.socket"},
+	},
+	{
+		Name:     "tftp",
+		Reason:   "TFTP serves files without authentication",
+		Severity: SeverityMedium,
+		Packages: []string{"tftpd-hpa", "tftpd", "atftpd", "tftp-server", "tftp-hpa", "tftp"},
+		Services: []string{"tftpd-hpa", "tftp.socket", "atftpd", "in.tftpd"},
+	},
+	{
+		Name:     "xinetd",
+		Reason:   "the super-server starts legacy network services on demand",
+		Severity: SeverityLow,
+		Packages: []string{"xinetd"},
+		Services: []string{"xinetd"},
+	},
+	{
+		Name:     "avahi",
+		Reason:   "mDNS announces the host and its services to the local network",
+		Severity: SeverityLow,
+		Packages: []string{"avahi-daemon", "avahi"},
+		Services: []string{"avahi-daemon.socket", "avahi-daemon"},
+	},
+	{
+		Name:     "cups",
+		Reason:   "a print server is rarely needed on a server and has a history of remote flaws",
+		Severity: SeverityLow,
+		Packages: []string{"cups", "cups-daemon", "cups-browsed"},
+		Services: []string{"cups-browsed", "cups.socket", "cups", "cupsd"},
+	},
+}
+
+// DefaultDebloatPackages are the catalog entries checked by default
+var DefaultDebloatPackages = []string{"telnet", "rsh", "nis", "talk", "tftp", "xinetd", "avahi", "cups"}
+
+// FindDebloatItem returns the catalog entry of a configured name. A name
+// the catalog does not know is taken as a package name.
+func FindDebloatItem(name string) DebloatItem {
+	for _, item := range DebloatCatalog {
+		if item.Name == name {
+			return item
+		}
+	}
+	return DebloatItem{
+		Name:     name,
+		Reason:   "listed in debloat.packages",
+		Severity: SeverityLow,
+		Packages: []string{name},
+	}
+}
+
+// DebloatFinding is an insecure package installed, or its service running,
+// on this host
+type DebloatFinding struct {
+	Item       DebloatItem `json:"item"`
+	Installed  []string    `json:"installed"`            // packages of the item installed
+	Running    []string    `json:"running"`              // services running or enabled at boot
+	Dependents []string    `json:"dependents,omitempty"` // other packages removing them would take along
+}
+
+// Action returns what removing the finding does: remove the packages, or
+// only disable the services when other packages depend on them
+func (f DebloatFinding) Action() string {
+	switch {
+	case len(f.Installed) > 0 && len(f.Dependents) == 0:
+		return DebloatRemove
+	case len(f.Running) > 0:
+		return DebloatDisable
+	}
+	return DebloatManual
+}
+
+// Describe summarizes what was found, such as "telnetd installed, telnet.socket running"
+func (f DebloatFinding) Describe() string {
+	var parts []string
+	if len(f.Installed) > 0 {
+		parts = append(parts, strings.Join(f.Installed, ", ")+" installed")
+	}
+	if len(f.Running) > 0 {
+		parts = append(parts, strings.Join(f.Running, ", ")+" running")
+	}
+	if len(f.Dependents) > 0 {
+		parts = append(parts, "needed by "+strings.Join(f.Dependents, ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Violation converts the finding for inclusion in an audit report
+func (f DebloatFinding) Violation() PolicyViolation {
+	remediation := "Run 'hardn --remove-insecure-packages', or drop it from debloat.packages if the host needs it"
+	if f.Action() == DebloatManual {
+		remediation = "Remove the packages depending on it first, or drop it from debloat.packages if the host needs it"
+	}
+
+	return PolicyViolation{
+		Rule:        "packages.insecure." + f.Item.Name,
+		Severity:    f.Item.Severity,
+		Message:     fmt.Sprintf("%s: %s (%s)", f.Item.Name, f.Item.Reason, f.Describe()),
+		Remediation: remediation,
+	}
+}
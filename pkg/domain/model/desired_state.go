@@ -0,0 +1,185 @@
+// pkg/domain/model/desired_state.go
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DesiredStateKind identifies a hardn state file
+const DesiredStateKind = "hardn-state"
+
+// StateSysctlPath keeps the sysctl settings of the last state applied
+// across reboots
+const StateSysctlPath = SysctlDropInDir + "/90-hardn-state.conf"
+
+// DesiredState is the posture a state file declares for 'hardn apply'.
+// Applying it only adds and changes what the file lists: users, keys and
+// firewall rules it does not mention are left alone, and packages are
+// only removed when listed under packages.remove.
+type DesiredState struct {
+	Kind     string            `yaml:"kind"`
+	Users    []StateUser       `yaml:"users,omitempty"`
+	SSH      *StateSSH         `yaml:"ssh,omitempty"`
+	Firewall StateFirewall     `yaml:"firewall,omitempty"`
+	Sysctl   map[string]string `yaml:"sysctl,omitempty"`
+	Packages StatePackages     `yaml:"packages,omitempty"`
+}
+
+// Empty reports whether the state declares nothing to converge
+func (s *DesiredState) Empty() bool {
+	return len(s.Users) == 0 && s.SSH == nil && len(s.Firewall.Rules) == 0 &&
+		len(s.Sysctl) == 0 && len(s.Packages.Install) == 0 && len(s.Packages.Remove) == 0
+}
+
+// StateUser is a local account a state file declares. Sudo is granted but
+// never taken away, and the keys are authorized next to the ones the
+// account already has.
+type StateUser struct {
+	Name       string   `yaml:"name"`
+	Sudo       bool     `yaml:"sudo,omitempty"`
+	NoPassword bool     `yaml:"nopasswd,omitempty"`
+	Keys       []string `yaml:"keys,omitempty"`
+}
+
+// User converts the entry for the user repository
+func (u StateUser) User() User {
+	return User{
+		Username:       u.Name,
+		HasSudo:        u.Sudo,
+		SudoNoPassword: u.Sudo && u.NoPassword,
+		SshKeys:        u.Keys,
+	}
+}
+
+// StateSSH holds the sshd settings a state file declares; settings left
+// out keep their current value
+type StateSSH struct {
+	Port            int      `yaml:"port,omitempty"`
+	ListenAddresses []string `yaml:"listenAddresses,omitempty"`
+	PermitRootLogin *bool    `yaml:"permitRootLogin,omitempty"`
+	AllowUsers      []string `yaml:"allowUsers,omitempty"`
+}
+
+// StateFirewall holds the firewall rules a state file declares
+type StateFirewall struct {
+	Rules []StateFirewallRule `yaml:"rules,omitempty"`
+}
+
+// StateFirewallRule is a firewall rule, written as in the firewallRules
+// setting of the configuration
+type StateFirewallRule struct {
+	Action   string `yaml:"action"`
+	Protocol string `yaml:"protocol"`
+	Port     int    `yaml:"port"`
+	EndPort  int    `yaml:"endPort,omitempty"`
+	Source   string `yaml:"source,omitempty"`
+	Family   string `yaml:"family,omitempty"`
+	Comment  string `yaml:"comment,omitempty"`
+}
+
+// Rule converts the entry for the firewall repository
+func (r StateFirewallRule) Rule() FirewallRule {
+	return FirewallRule{
+		Action:      r.Action,
+		Protocol:    r.Protocol,
+		Port:        r.Port,
+		EndPort:     r.EndPort,
+		SourceIP:    r.Source,
+		Family:      r.Family,
+		Description: r.Comment,
+	}
+}
+
+// StatePackages lists the packages a state file wants installed and removed
+type StatePackages struct {
+	Install []string `yaml:"install,omitempty"`
+	Remove  []string `yaml:"remove,omitempty"`
+}
+
+// Actions of a state change
+const (
+	StateCreate = "create"
+	StateUpdate = "update"
+	StateDelete = "delete"
+)
+
+// Resources a state file manages
+const (
+	StateResourcePackage  = "package"
+	StateResourceUser     = "user"
+	StateResourceSysctl   = "sysctl"
+	StateResourceFirewall = "firewall rule"
+	StateResourceSSH      = "ssh"
+)
+
+// StateChange is one difference between the host and a state file
+type StateChange struct {
+	Resource string
+	// Name identifies the resource: a user or package name, a sysctl key,
+	// a firewall rule as FirewallRule.Spec returns it, or "sshd"
+	Name   string
+	Action string
+	// Details say what changes, e.g. "port: 22 -> 2222"
+	Details []string
+}
+
+// Symbol marks the change in a plan as terraform does: + to add, ~ to
+// change, - to destroy
+func (c StateChange) Symbol() string {
+	switch c.Action {
+	case StateCreate:
+		return "+"
+	case StateDelete:
+		return "-"
+	}
+	return "~"
+}
+
+// Describe returns a one-line description of the change, e.g.
+// "create user george"
+func (c StateChange) Describe() string {
+	return fmt.Sprintf("%s %s %s", c.Action, c.Resource, c.Name)
+}
+
+// StatePlan is what applying a state changes on this host, in the order
+// the changes are made. Nothing is changed unless the plan is valid.
+type StatePlan struct {
+	State   *DesiredState
+	Changes []StateChange
+	// Problems are what the plan found wrong with the state file
+	Problems []string
+}
+
+// Valid reports whether the state file has no problem
+func (p *StatePlan) Valid() bool {
+	return len(p.Problems) == 0
+}
+
+// Empty reports whether the host already matches the state
+func (p *StatePlan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Summary counts the changes as terraform does, e.g.
+// "Plan: 2 to add, 1 to change, 0 to destroy."
+func (p *StatePlan) Summary() string {
+	counts := make(map[string]int)
+	for _, change := range p.Changes {
+		counts[change.Action]++
+	}
+	return fmt.Sprintf("Plan: %d to add, %d to change, %d to destroy.",
+		counts[StateCreate], counts[StateUpdate], counts[StateDelete])
+}
+
+// StateChangeResult is the outcome of one change of a plan
+type StateChangeResult struct {
+	Change StateChange
+	Err    error
+}
+
+// NormalizeSysctlValue joins the fields of a sysctl value with single
+// spaces, as the kernel separates them with tabs
+func NormalizeSysctlValue(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
@@ -3,7 +3,11 @@ package model
 
 // DNSConfig represents DNS configuration settings
 type DNSConfig struct {
-	Nameservers []string
-	Domain      string
-	Search      []string
+	Nameservers []string `json:"nameservers"`
+	Domain      string   `json:"domain,omitempty"`
+	Search      []string `json:"search,omitempty"`
+
+	// Implementation is the resolver backend detected on the system
+	// (systemd-resolved, resolvconf or direct), populated on read
+	Implementation string `json:"implementation,omitempty"`
 }
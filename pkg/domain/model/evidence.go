@@ -0,0 +1,172 @@
+// pkg/domain/model/evidence.go
+package model
+
+import "time"
+
+// EvidenceFormat is the version of the evidence bundle manifest
+const EvidenceFormat = 1
+
+// EvidenceManifestName is the manifest's path inside a bundle
+const EvidenceManifestName = "manifest.json"
+
+// Kinds of evidence a bundle holds
+const (
+	EvidenceAudit   = "audit"   // the audit report
+	EvidenceConfig  = "config"  // copies of managed configuration files
+	EvidenceBackups = "backups" // the list of file backups
+	EvidenceRuns    = "runs"    // recorded hardening runs
+)
+
+// Configuration areas that have no hardening area of their own
+const (
+	AreaUpdates = "updates"
+	AreaHardn   = "hardn"
+)
+
+// EvidenceConfigFile is a configuration file collected into bundles
+type EvidenceConfigFile struct {
+	Path string
+	Area string
+}
+
+// EvidenceConfigFiles are the configuration files hardn manages on every
+// host; the SSH drop-in, the sudoers drop-in and hardn's own configuration
+// are added per host. Files that do not exist are left out.
+var EvidenceConfigFiles = []EvidenceConfigFile{
+	{"/etc/ssh/sshd_config", AreaSSH},
+	{"/etc/default/ufw", AreaFirewall},
+	{"/etc/ufw/user.rules", AreaFirewall},
+	{"/etc/ufw/user6.rules", AreaFirewall},
+	{"/etc/ufw/applications.d/hardn", AreaFirewall},
+	{"/etc/firewalld/firewalld.conf", AreaFirewall},
+	{"/etc/firewalld/zones/public.xml", AreaFirewall},
+	{"/etc/resolv.conf", AreaDNS},
+	{"/etc/systemd/resolved.conf", AreaDNS},
+	{"/etc/sudoers", AreaUsers},
+	{AptAutoUpgradesPath, AreaUpdates},
+	{AptUnattendedUpgradesPath, AreaUpdates},
+	{AlpineAutoUpgradeScriptPath, AreaUpdates},
+	{DnfAutomaticConfigPath, AreaUpdates},
+	{NeedrestartConfigPath, AreaUpdates},
+}
+
+// EvidenceItem is a piece of evidence before it is written to a bundle
+type EvidenceItem struct {
+	// Path inside the bundle
+	Path string
+	Kind string
+	// Area of configuration files
+	Area string
+	// Source is where the evidence was collected from on the host
+	Source string
+	Data   []byte
+}
+
+// EvidenceBackup is a file backup listed in a bundle
+type EvidenceBackup struct {
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+	SHA256   string    `json:"sha256"`
+}
+
+// EvidenceTemplate selects the evidence of a bundle and groups it in
+// sections, such as the requirements of a compliance standard
+type EvidenceTemplate struct {
+	Name     string            `yaml:"name" json:"name"`
+	Sections []EvidenceSection `yaml:"sections" json:"sections"`
+}
+
+// EvidenceSection is a group of evidence in a template. Include lists
+// evidence kinds (audit, config, backups, runs); configuration files can
+// be narrowed to an area, as in "config:ssh".
+type EvidenceSection struct {
+	ID      string   `yaml:"id" json:"id"`
+	Title   string   `yaml:"title" json:"title"`
+	Include []string `yaml:"include" json:"-"`
+	// Files are the bundle paths of the section's evidence
+	Files []string `yaml:"-" json:"files"`
+}
+
+// EvidenceTemplates are the built-in templates
+var EvidenceTemplates = []EvidenceTemplate{
+	{
+		Name: "default",
+		Sections: []EvidenceSection{
+			{ID: "audit", Title: "Audit report", Include: []string{EvidenceAudit}},
+			{ID: "config", Title: "Managed configuration", Include: []string{EvidenceConfig}},
+			{ID: "changes", Title: "Backups and hardening runs", Include: []string{EvidenceBackups, EvidenceRuns}},
+		},
+	},
+	{
+		Name: "pci-dss",
+		Sections: []EvidenceSection{
+			{ID: "1.2", Title: "Network security controls are configured and maintained",
+				Include: []string{"config:" + AreaFirewall, EvidenceAudit}},
+			{ID: "2.2", Title: "System components are configured and managed securely",
+				Include: []string{"config:" + AreaSSH, "config:" + AreaDNS, "config:" + AreaHardn, EvidenceAudit}},
+			{ID: "6.3.3", Title: "Security patches are installed",
+				Include: []string{"config:" + AreaUpdates}},
+			{ID: "6.5.1", Title: "Changes to system components are managed",
+				Include: []string{EvidenceRuns, EvidenceBackups}},
+			{ID: "7.2", Title: "Access to system components is defined and assigned",
+				Include: []string{"config:" + AreaUsers}},
+			{ID: "8.3", Title: "Strong authentication is established",
+				Include: []string{"config:" + AreaSSH}},
+		},
+	},
+}
+
+// LookupEvidenceTemplate returns the named built-in template
+func LookupEvidenceTemplate(name string) (EvidenceTemplate, bool) {
+	for _, template := range EvidenceTemplates {
+		if template.Name == name {
+			return template, true
+		}
+	}
+	return EvidenceTemplate{}, false
+}
+
+// EvidenceFile describes a file of a bundle in its manifest
+type EvidenceFile struct {
+	Path   string `json:"path"`
+	Kind   string `json:"kind"`
+	Area   string `json:"area,omitempty"`
+	Source string `json:"source,omitempty"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// EvidenceManifest lists what an evidence bundle holds
+type EvidenceManifest struct {
+	Format       int               `json:"format"`
+	HardnVersion string            `json:"hardn_version"`
+	Hostname     string            `json:"hostname"`
+	GeneratedAt  time.Time         `json:"generated_at"`
+	Template     string            `json:"template"`
+	Sections     []EvidenceSection `json:"sections"`
+	Files        []EvidenceFile    `json:"files"`
+}
+
+// EvidenceBundle is a written bundle
+type EvidenceBundle struct {
+	Path      string
+	Encrypted bool
+	Manifest  EvidenceManifest
+}
+
+// EvidenceRequest is what a bundle is built from
+type EvidenceRequest struct {
+	// Output is the bundle's path
+	Output       string
+	Hostname     string
+	HardnVersion string
+	Template     EvidenceTemplate
+	// AuditReport is included as audit/report.json when set
+	AuditReport *AuditReport
+	ConfigFiles []EvidenceConfigFile
+	BackupDir   string
+	Runs        []RunManifest
+	// Recipient encrypts the bundle for a GnuPG key when set
+	Recipient string
+}
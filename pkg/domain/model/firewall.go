@@ -1,15 +1,96 @@
 // pkg/domain/model/firewall.go
 package model
 
+import (
+	"strconv"
+	"strings"
+)
+
 // FirewallRule represents a firewall rule
 type FirewallRule struct {
-	Action      string // allow, deny
-	Protocol    string // tcp, udp, icmp
+	Action      string // allow, deny, reject, limit
+	Protocol    string // tcp, udp
 	Port        int
+	EndPort     int    // last port of a range; 0 for a single port
 	SourceIP    string // source IP or subnet
+	Family      string // ipv4 or ipv6 to apply the rule to one family; both when empty
 	Description string
 }
 
+// AddressFamily returns the family a rule applies to: its own, or that of
+// its source address. It is empty for a rule applying to both.
+func (r FirewallRule) AddressFamily() string {
+	switch {
+	case r.Family != "":
+		return r.Family
+	case strings.Contains(r.SourceIP, ":"):
+		return FamilyIPv6
+	case r.SourceIP != "":
+		return FamilyIPv4
+	}
+	return ""
+}
+
+// Ports returns the rule's port, or its range with the ports joined by sep
+// (ufw writes ranges as 6000:6007, firewalld as 6000-6007)
+func (r FirewallRule) Ports(sep string) string {
+	if r.EndPort == 0 || r.EndPort == r.Port {
+		return strconv.Itoa(r.Port)
+	}
+	return strconv.Itoa(r.Port) + sep + strconv.Itoa(r.EndPort)
+}
+
+// Spec returns the rule as live rules are listed, in ufw's syntax: e.g.
+// "allow 22/tcp" or "deny 6000:6007/udp from 10.0.0.0/8". The family is
+// left out, as the live rules do not tell a rule for one family from one
+// for both.
+func (r FirewallRule) Spec() string {
+	spec := r.Action + " " + r.Ports(":") + "/" + r.Protocol
+	if r.SourceIP != "" {
+		spec += " from " + r.SourceIP
+	}
+	return spec
+}
+
+// SSHFirewallPolicy describes how the firewall lets SSH in
+type SSHFirewallPolicy struct {
+	Port      int
+	RateLimit bool          // limit new connections instead of allowing them all
+	Knocking  *PortKnocking // SSH stays closed until the knock sequence; nil when off
+}
+
+// Rule returns the rule opening the SSH port. With port knocking there is
+// none: knockd opens the port to each address that knocks.
+func (p SSHFirewallPolicy) Rule() (FirewallRule, bool) {
+	if p.Knocking != nil {
+		return FirewallRule{}, false
+	}
+	action := "allow"
+	if p.RateLimit {
+		action = "limit"
+	}
+	return FirewallRule{
+		Action:      action,
+		Protocol:    "tcp",
+		Port:        p.Port,
+		Description: "SSH access",
+	}, true
+}
+
+// PortKnocking is a knockd sequence opening the SSH port
+type PortKnocking struct {
+	Sequence    []int  // TCP ports to knock on, in order
+	SeqTimeout  int    // seconds to complete the sequence
+	OpenTimeout int    // seconds the port stays open to new connections
+	Interface   string // interface knockd listens on; the default route's when empty
+}
+
+// SSHAccess describes how the live firewall lets SSH in
+type SSHAccess struct {
+	RateLimited  bool `json:"rate_limited"`
+	PortKnocking bool `json:"port_knocking"` // knockd running with hardn's sequence
+}
+
 // FirewallProfile represents a firewall application profile
 type FirewallProfile struct {
 	Name        string
@@ -25,4 +106,73 @@ type FirewallConfig struct {
 	DefaultOutgoing     string // allow, deny
 	Rules               []FirewallRule
 	ApplicationProfiles []FirewallProfile
+	IPv6                string // IPv6Filter, IPv6Deny or IPv6Disable; IPv6 is left as is when empty
+}
+
+// FirewallDrift compares the firewall hardn's configuration declares with
+// the live ruleset. Rules are listed as FirewallRule.Spec writes them.
+type FirewallDrift struct {
+	Enabled     bool     `json:"enabled"`
+	DefaultDeny bool     `json:"default_deny"`
+	Declared    []string `json:"declared"`
+	Missing     []string `json:"missing"` // declared rules the firewall lacks
+	Extra       []string `json:"extra"`   // live rules added outside hardn
+}
+
+// InSync reports whether the live firewall matches the declared one
+func (d FirewallDrift) InSync() bool {
+	return d.Enabled && d.DefaultDeny && len(d.Missing) == 0 && len(d.Extra) == 0
+}
+
+// IP families compared by the dual-stack check
+const (
+	FamilyIPv4 = "ipv4"
+	FamilyIPv6 = "ipv6"
+)
+
+// FamilyName returns the display name of an IP family
+func FamilyName(family string) string {
+	switch family {
+	case FamilyIPv4:
+		return "IPv4"
+	case FamilyIPv6:
+		return "IPv6"
+	}
+	return family
+}
+
+// StackRules are the incoming rules the firewall applies to one IP family
+type StackRules struct {
+	Family   string   `json:"family"`
+	Active   bool     `json:"active"`   // the host has addresses in this family
+	Filtered bool     `json:"filtered"` // incoming traffic no rule matches is dropped or rejected
+	Allowed  []string `json:"allowed"`  // rules letting traffic in, as ufw commands, e.g. "allow 22/tcp"
+}
+
+// StackGap is a rule letting traffic in on one IP family that the other
+// family lacks
+type StackGap struct {
+	Rule      string `json:"rule"`
+	AllowedOn string `json:"allowed_on"`
+	MissingOn string `json:"missing_on"`
+
+	// Rules limited to an address of one family can not be mirrored
+	Mirrorable bool `json:"mirrorable"`
+}
+
+// DualStackReport compares the firewall's IPv4 and IPv6 rules
+type DualStackReport struct {
+	IPv4 StackRules `json:"ipv4"`
+	IPv6 StackRules `json:"ipv6"`
+
+	// Unfiltered is a family that accepts all incoming traffic while the
+	// other is filtered; every rule of the filtered family is then a gap
+	Unfiltered string `json:"unfiltered,omitempty"`
+
+	Gaps []StackGap `json:"gaps"`
+}
+
+// Synchronized reports whether both families let in the same traffic
+func (r DualStackReport) Synchronized() bool {
+	return r.Unfiltered == "" && len(r.Gaps) == 0
 }
@@ -13,23 +13,156 @@ type HardeningConfig struct {
 	SshPort            int
 	SshListenAddresses []string
 	SshAllowedUsers    []string
+	SshAllowAllUsers   bool
 	SshKeyPaths        []string
+	SshProfile         string
+	// Disable lines of other sshd configuration files that contradict hardn's
+	SshConsolidateDropIns bool
 
 	// Firewall settings
 	EnableFirewall   bool
 	AllowedPorts     []int
+	FirewallRules    []FirewallRule
 	FirewallProfiles []FirewallProfile
 
+	// Rate-limit SSH, or keep it closed until a knock sequence when
+	// PortKnocking is set
+	SshRateLimit bool
+	PortKnocking *PortKnocking
+
+	// How the firewall and the kernel treat IPv6
+	IPv6 IPv6Policy
+
+	// Copy rules between IPv4 and IPv6 once the firewall is configured
+	MirrorFirewallStacks bool
+
 	// DNS settings
 	ConfigureDns bool
 	Nameservers  []string
 
 	// Feature toggles
 	EnableAppArmor           bool
+	EnableSELinux            bool
 	EnableLynis              bool
 	EnableUnattendedUpgrades bool
+	AutoUpdates              UpdatesPolicy
+	NeedrestartMode          string
+	EnableWebServerTLS       bool
+
+	// Database baseline; nil leaves database servers untouched
+	DatabaseHardening *DatabaseHardening
+
+	// Login banners and MOTD; nil leaves them untouched
+	Banner *BannerPolicy
+
+	// Password strength, aging and lockout; nil leaves them untouched
+	PasswordPolicy *PasswordPolicy
+
+	// Kernel modules kept from loading; nil leaves modprobe.d untouched
+	ModuleBlacklist *ModuleBlacklistPolicy
+
+	// Kernel command line and GRUB password; nil leaves the boot loader untouched
+	BootHardening *BootHardeningPolicy
+
+	// Time synchronization daemon and servers; nil leaves them untouched
+	TimeSync *TimeSyncPolicy
+
+	// Log forwarding to a collector; nil leaves rsyslog and journald untouched
+	LogShipping *LogShippingPolicy
 
 	UseUvPackageManager bool
 	// UpdateRepositories       bool
 	InstallPackages bool
 }
+
+// RestrictToDistributionAgnostic turns off the steps that depend on the
+// distribution's packages, firewall or service layout, leaving user, SSH,
+// DNS and SELinux configuration. It returns the names of the steps it turned off.
+func (c *HardeningConfig) RestrictToDistributionAgnostic() []string {
+	var skipped []string
+	if c.EnableFirewall {
+		skipped = append(skipped, "firewall")
+		c.EnableFirewall = false
+	}
+	if c.EnableAppArmor {
+		skipped = append(skipped, "AppArmor")
+		c.EnableAppArmor = false
+	}
+	if c.EnableLynis {
+		skipped = append(skipped, "Lynis")
+		c.EnableLynis = false
+	}
+	if c.EnableUnattendedUpgrades {
+		skipped = append(skipped, "automatic updates")
+		c.EnableUnattendedUpgrades = false
+	}
+	if c.NeedrestartMode != "" {
+		skipped = append(skipped, "needrestart")
+		c.NeedrestartMode = ""
+	}
+	if c.EnableWebServerTLS {
+		skipped = append(skipped, "web server TLS")
+		c.EnableWebServerTLS = false
+	}
+	if c.DatabaseHardening != nil {
+		skipped = append(skipped, "database hardening")
+		c.DatabaseHardening = nil
+	}
+	if c.PasswordPolicy != nil {
+		skipped = append(skipped, "password policy")
+		c.PasswordPolicy = nil
+	}
+	if c.BootHardening != nil {
+		skipped = append(skipped, "boot hardening")
+		c.BootHardening = nil
+	}
+	if c.TimeSync != nil {
+		skipped = append(skipped, "time synchronization")
+		c.TimeSync = nil
+	}
+	if c.LogShipping != nil {
+		skipped = append(skipped, "log shipping")
+		c.LogShipping = nil
+	}
+	c.InstallPackages = false
+	return skipped
+}
+
+// RestrictForWSL turns off the steps that do not apply under WSL: Windows
+// filters inbound traffic, Microsoft's kernel has no AppArmor or module
+// policy to set, Windows boots the kernel without GRUB and the kernel is
+// not restarted into. DNS is skipped while
+// WSL writes resolv.conf, and automatic updates without systemd, which
+// their timer needs. It returns the names of the steps it turned off.
+func (c *HardeningConfig) RestrictForWSL(systemd, managedResolvConf bool) []string {
+	var skipped []string
+	if c.EnableFirewall {
+		skipped = append(skipped, "firewall")
+		c.EnableFirewall = false
+	}
+	if c.EnableAppArmor {
+		skipped = append(skipped, "AppArmor")
+		c.EnableAppArmor = false
+	}
+	if c.ModuleBlacklist != nil {
+		skipped = append(skipped, "kernel module blacklist")
+		c.ModuleBlacklist = nil
+	}
+	if c.BootHardening != nil {
+		skipped = append(skipped, "boot hardening")
+		c.BootHardening = nil
+	}
+	if c.NeedrestartMode != "" {
+		skipped = append(skipped, "needrestart")
+		c.NeedrestartMode = ""
+	}
+	if managedResolvConf && c.ConfigureDns {
+		skipped = append(skipped, "DNS")
+		c.ConfigureDns = false
+	}
+	if !systemd && c.EnableUnattendedUpgrades {
+		skipped = append(skipped, "automatic updates")
+		c.EnableUnattendedUpgrades = false
+	}
+	return skipped
+}
@@ -0,0 +1,160 @@
+// pkg/domain/model/host_environment.go
+package model
+
+import "strings"
+
+// Environments hardn tailors its hardening plan to
+const (
+	EnvBareMetal = "bare-metal"
+	EnvVM        = "vm"
+	EnvLXC       = "lxc"
+	EnvDocker    = "docker"
+	EnvContainer = "container" // another container manager, e.g. podman or systemd-nspawn
+	EnvWSL       = "wsl"
+)
+
+// Environment plan settings besides the environment kinds
+const (
+	EnvironmentPlanAuto         = "auto"             // tune to the detected environment
+	EnvironmentPlanNone         = "none"             // run every step
+	EnvironmentPlanUnprivileged = "unprivileged-lxc" // an unprivileged LXC container
+)
+
+// HostEnvironment is what hardn runs on: bare metal, a VM or a container
+type HostEnvironment struct {
+	Kind         string `json:"kind"`
+	Hypervisor   string `json:"hypervisor,omitempty"`    // kvm, vmware, hyperv, xen, ... for VMs
+	Unprivileged bool   `json:"unprivileged,omitempty"`  // container root is an unprivileged user on the host
+	ProxmoxGuest bool   `json:"proxmox_guest,omitempty"` // VM or container run by Proxmox VE
+}
+
+// ParseEnvironmentPlan returns the environment an environment plan setting
+// names, and whether the plan is tuned at all. Auto, or an empty or
+// unknown setting, keeps the detected environment.
+func ParseEnvironmentPlan(setting string, detected HostEnvironment) (HostEnvironment, bool) {
+	switch setting {
+	case EnvironmentPlanNone:
+		return detected, false
+	case EnvironmentPlanUnprivileged:
+		return HostEnvironment{Kind: EnvLXC, Unprivileged: true}, true
+	case EnvBareMetal, EnvVM, EnvLXC, EnvDocker, EnvContainer:
+		return HostEnvironment{Kind: setting}, true
+	}
+	return detected, true
+}
+
+// IsContainer reports whether the kernel belongs to a container host
+func (e HostEnvironment) IsContainer() bool {
+	return e.Kind == EnvLXC || e.Kind == EnvDocker || e.Kind == EnvContainer
+}
+
+// String describes the environment, e.g. "unprivileged LXC container
+// (Proxmox guest)" or "KVM virtual machine"
+func (e HostEnvironment) String() string {
+	var description string
+	switch e.Kind {
+	case EnvBareMetal:
+		description = "bare metal"
+	case EnvVM:
+		description = "virtual machine"
+		if e.Hypervisor != "" && e.Hypervisor != "other" {
+			description = hypervisorName(e.Hypervisor) + " " + description
+		}
+	case EnvLXC:
+		description = "LXC container"
+	case EnvDocker:
+		description = "Docker container"
+	case EnvContainer:
+		description = "container"
+	case EnvWSL:
+		description = "WSL"
+	default:
+		return "unknown"
+	}
+	if e.Unprivileged {
+		description = "unprivileged " + description
+	}
+	if e.ProxmoxGuest {
+		description += " (Proxmox guest)"
+	}
+	return description
+}
+
+// hypervisorName returns the display name of a hypervisor
+func hypervisorName(hypervisor string) string {
+	switch hypervisor {
+	case "kvm":
+		return "KVM"
+	case "vmware":
+		return "VMware"
+	case "hyperv":
+		return "Hyper-V"
+	case "xen":
+		return "Xen"
+	case "virtualbox":
+		return "VirtualBox"
+	}
+	return strings.ToUpper(hypervisor[:1]) + hypervisor[1:]
+}
+
+// ApplyEnvironmentPlan tunes the configuration to the environment and
+// returns the steps it turned off, each with the reason. Containers share
+// the host's kernel, so kernel modules, the kernel command line, kernel
+// restarts and the clock are the host's business; unprivileged LXC
+// containers can not load AppArmor profiles; Docker and other OCI
+// containers get their AppArmor profile, network filtering, updates and
+// log collection from the host, image and runtime.
+// VMs and bare metal get the full plan, and WSL is restricted by
+// RestrictForWSL.
+func (c *HardeningConfig) ApplyEnvironmentPlan(env HostEnvironment) []string {
+	if !env.IsContainer() {
+		return nil
+	}
+
+	var skipped []string
+	skip := func(step, reason string) {
+		skipped = append(skipped, step+" ("+reason+")")
+	}
+
+	if c.ModuleBlacklist != nil {
+		skip("kernel module blacklist", "the host's kernel")
+		c.ModuleBlacklist = nil
+	}
+	if c.IPv6.BootParameter {
+		skip("kernel command line", "containers do not boot a kernel")
+		c.IPv6.BootParameter = false
+	}
+	if c.BootHardening != nil {
+		skip("boot hardening", "containers do not boot a kernel")
+		c.BootHardening = nil
+	}
+	if c.TimeSync != nil {
+		skip("time synchronization", "the host's clock")
+		c.TimeSync = nil
+	}
+	if c.NeedrestartMode != "" {
+		skip("needrestart", "the host's kernel")
+		c.NeedrestartMode = ""
+	}
+
+	if c.EnableAppArmor && (env.Unprivileged || env.Kind != EnvLXC) {
+		skip("AppArmor", "profiles are loaded by the host")
+		c.EnableAppArmor = false
+	}
+
+	if env.Kind == EnvDocker || env.Kind == EnvContainer {
+		if c.EnableFirewall {
+			skip("firewall", "the container runtime filters traffic")
+			c.EnableFirewall = false
+		}
+		if c.EnableUnattendedUpgrades {
+			skip("automatic updates", "rebuild the image instead")
+			c.EnableUnattendedUpgrades = false
+		}
+		if c.LogShipping != nil {
+			skip("log shipping", "the container runtime collects the logs")
+			c.LogShipping = nil
+		}
+	}
+	return skipped
+}
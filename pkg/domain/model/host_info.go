@@ -22,6 +22,7 @@ type HostInfo struct {
 	OSVersion  string
 	Uptime     time.Duration
 	KernelInfo string
+	Arch       string // hardware architecture from uname -m, e.g. x86_64, armv7l, riscv64
 
 	// Additional information
 	CPUInfo     string
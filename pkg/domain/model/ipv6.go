@@ -0,0 +1,68 @@
+// pkg/domain/model/ipv6.go
+package model
+
+// IPv6 modes: how the firewall and the kernel treat IPv6
+const (
+	// IPv6Filter filters IPv6 with the same rules as IPv4
+	IPv6Filter = "filter"
+
+	// IPv6Deny lets IPv6 in only to SSH and to rules limited to IPv6;
+	// every other rule applies to IPv4 alone
+	IPv6Deny = "deny"
+
+	// IPv6Disable turns IPv6 off in the kernel
+	IPv6Disable = "disable"
+)
+
+// IPv6Policy is how hardn treats IPv6
+type IPv6Policy struct {
+	Mode string // IPv6Filter, IPv6Deny or IPv6Disable; empty leaves IPv6 as it is
+
+	// With IPv6Disable, also turn IPv6 off on the kernel command line, so
+	// the kernel never starts it, instead of with sysctl
+	BootParameter bool
+}
+
+// Rule returns a rule as the policy applies it, or false when the rule
+// is left out. With IPv6Deny a rule for both families is limited to IPv4;
+// with IPv6Disable rules for IPv6 have nothing left to filter.
+func (p IPv6Policy) Rule(rule FirewallRule) (FirewallRule, bool) {
+	switch p.Mode {
+	case IPv6Deny:
+		if rule.AddressFamily() == "" {
+			rule.Family = FamilyIPv4
+		}
+	case IPv6Disable:
+		if rule.AddressFamily() == FamilyIPv6 {
+			return rule, false
+		}
+	}
+	return rule, true
+}
+
+// IPv6Exposure describes how other hosts reach this one over IPv6
+type IPv6Exposure struct {
+	Mode         string   `json:"mode"`          // the configured IPv6 mode
+	Enabled      bool     `json:"enabled"`       // the kernel runs IPv6
+	BootDisabled bool     `json:"boot_disabled"` // ipv6.disable=1 is on the kernel command line
+	Addressed    bool     `json:"addressed"`     // the host has an IPv6 address outside loopback
+	Filtered     bool     `json:"filtered"`      // the firewall drops or rejects IPv6 no rule matches
+	Allowed      []string `json:"allowed"`       // rules letting IPv6 traffic in
+	Unexpected   []string `json:"unexpected"`    // with IPv6Deny, allowed rules the configuration does not limit to IPv6
+}
+
+// Exposed reports whether other hosts reach every service over IPv6
+func (e IPv6Exposure) Exposed() bool {
+	return e.Enabled && e.Addressed && !e.Filtered
+}
+
+// Compliant reports whether IPv6 is treated as the mode asks
+func (e IPv6Exposure) Compliant() bool {
+	switch e.Mode {
+	case IPv6Disable:
+		return !e.Enabled
+	case IPv6Deny:
+		return !e.Exposed() && len(e.Unexpected) == 0
+	}
+	return !e.Exposed()
+}
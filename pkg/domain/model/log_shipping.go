@@ -0,0 +1,86 @@
+// pkg/domain/model/log_shipping.go
+package model
+
+import (
+	"net"
+	"strconv"
+)
+
+// Ways hardn forwards logs to a collector
+const (
+	// LogShippingRsyslog forwards syslog messages with rsyslog's omfwd over
+	// TLS (RFC 5425)
+	LogShippingRsyslog = "rsyslog"
+	// LogShippingJournald uploads the journal with systemd-journal-upload
+	// to systemd-journal-remote over HTTPS
+	LogShippingJournald = "journald"
+)
+
+// LogShippingMethods are the methods logShipping.method accepts
+var LogShippingMethods = []string{LogShippingRsyslog, LogShippingJournald}
+
+// Default collector ports of each method
+const (
+	// DefaultRsyslogTLSPort is the IANA port of syslog over TLS
+	DefaultRsyslogTLSPort = 6514
+	// DefaultJournalRemotePort is the port systemd-journal-remote listens on
+	DefaultJournalRemotePort = 19532
+)
+
+// Files hardn writes for log shipping
+const (
+	// RsyslogForwardConfigPath holds the omfwd action forwarding every message
+	RsyslogForwardConfigPath = "/etc/rsyslog.d/90-hardn-forward.conf"
+	// JournalUploadDropInPath points systemd-journal-upload at the collector
+	JournalUploadDropInPath = "/etc/systemd/journal-upload.conf.d/hardn.conf"
+	// LogShippingCertDir holds the copies of the certificates the daemons read
+	LogShippingCertDir = "/etc/hardn/log-shipping"
+)
+
+// LogShippingPolicy is the log forwarding hardn sets up
+type LogShippingPolicy struct {
+	// Method is rsyslog or journald
+	Method string
+
+	// Host is the collector's name, which its certificate must carry
+	Host string
+
+	// Port is the collector's port; zero picks the method's default
+	Port int
+
+	// CAFile is the PEM file of the CA that signed the collector's certificate
+	CAFile string
+
+	// CertFile and KeyFile are the client certificate and key presented to
+	// the collector; journald needs them, rsyslog only when the collector
+	// asks for one
+	CertFile string
+	KeyFile  string
+}
+
+// Target returns the collector as host:port
+func (p LogShippingPolicy) Target() string {
+	return net.JoinHostPort(p.Host, strconv.Itoa(p.Port))
+}
+
+// LogShippingStatus reports whether logs leave the host
+type LogShippingStatus struct {
+	// Method is the forwarding hardn configured, or empty when none
+	Method string `json:"method"`
+	// Target is the collector as host:port
+	Target string `json:"target,omitempty"`
+	// Active reports whether the forwarding daemon runs
+	Active bool `json:"active"`
+	// Connected reports whether the daemon holds a connection to the collector
+	Connected bool `json:"connected"`
+}
+
+// Configured reports whether hardn set up log forwarding
+func (s LogShippingStatus) Configured() bool {
+	return s.Method != ""
+}
+
+// Shipping reports whether logs are being forwarded off the host
+func (s LogShippingStatus) Shipping() bool {
+	return s.Configured() && s.Active && s.Connected
+}
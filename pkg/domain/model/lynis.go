@@ -0,0 +1,27 @@
+// pkg/domain/model/lynis.go
+package model
+
+import "time"
+
+// LynisReportPath is where Lynis writes its report by default, and where
+// hardn's scans write it
+const LynisReportPath = "/var/log/lynis-report.dat"
+
+// LynisFinding is a warning or suggestion from a Lynis scan
+type LynisFinding struct {
+	TestID   string `json:"test_id"`
+	Message  string `json:"message"`
+	Details  string `json:"details,omitempty"`
+	Solution string `json:"solution,omitempty"`
+}
+
+// LynisReport is the summary of a Lynis scan
+type LynisReport struct {
+	Version        string         `json:"version,omitempty"`
+	Hostname       string         `json:"hostname,omitempty"`
+	Finished       time.Time      `json:"finished"`
+	HardeningIndex int            `json:"hardening_index"`
+	TestsPerformed int            `json:"tests_performed"`
+	Warnings       []LynisFinding `json:"warnings"`
+	Suggestions    []LynisFinding `json:"suggestions"`
+}
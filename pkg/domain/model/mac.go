@@ -0,0 +1,71 @@
+// pkg/domain/model/mac.go
+package model
+
+import "fmt"
+
+// Mandatory access control frameworks hardn can manage
+const (
+	MACAppArmor = "apparmor"
+	MACSELinux  = "selinux"
+)
+
+// MAC modes, shared by both frameworks. AppArmor counts as enforcing once
+// at least one profile is in enforce mode.
+const (
+	MACModeEnforcing  = "enforcing"
+	MACModePermissive = "permissive"
+	MACModeDisabled   = "disabled"
+)
+
+// MACStatus describes the mandatory access control on a host
+type MACStatus struct {
+	Framework string `json:"framework"` // apparmor, selinux, or empty when none is in use
+	Installed bool   `json:"installed"` // the framework's userland tools are installed
+	Mode      string `json:"mode"`      // current mode
+
+	// AppArmor profiles loaded in enforce and complain mode
+	EnforcedProfiles int `json:"enforced_profiles,omitempty"`
+	ComplainProfiles int `json:"complain_profiles,omitempty"`
+
+	// SELinux mode and policy set in /etc/selinux/config, applied at boot
+	ConfiguredMode string `json:"configured_mode,omitempty"`
+	Policy         string `json:"policy,omitempty"`
+
+	// The configured mode only takes effect after a reboot
+	RebootRequired bool `json:"reboot_required,omitempty"`
+}
+
+// Enforcing reports whether the framework is enforcing its policy
+func (s *MACStatus) Enforcing() bool {
+	return s != nil && s.Framework != "" && s.Mode == MACModeEnforcing
+}
+
+// FrameworkName returns the display name of a framework
+func FrameworkName(framework string) string {
+	switch framework {
+	case MACAppArmor:
+		return "AppArmor"
+	case MACSELinux:
+		return "SELinux"
+	}
+	return framework
+}
+
+// Describe returns a short description, e.g. "SELinux enforcing (targeted)"
+func (s *MACStatus) Describe() string {
+	if s == nil || s.Framework == "" {
+		return "none"
+	}
+
+	description := FrameworkName(s.Framework) + " " + s.Mode
+	switch {
+	case s.Framework == MACAppArmor && s.Mode != MACModeDisabled:
+		description += fmt.Sprintf(" (%d profiles enforced)", s.EnforcedProfiles)
+	case s.Framework == MACSELinux && s.Policy != "":
+		description += " (" + s.Policy + ")"
+	}
+	if s.RebootRequired {
+		description += ", " + s.ConfiguredMode + " after reboot"
+	}
+	return description
+}
@@ -0,0 +1,159 @@
+// pkg/domain/model/maintenance.go
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaintenanceWindow is a recurring period during which changes may be applied.
+// Windows whose end is not after their start run past midnight into the next day.
+type MaintenanceWindow struct {
+	// Days the window opens on; empty means every day
+	Days []time.Weekday
+	// Start and End are offsets from local midnight
+	Start time.Duration
+	End   time.Duration
+	// Spec is the original text the window was parsed from
+	Spec string
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseMaintenanceWindow parses "<days> HH:MM-HH:MM", where days is "daily",
+// a day ("Sat"), a range ("Mon-Fri") or a list ("Sat,Sun")
+func ParseMaintenanceWindow(spec string) (MaintenanceWindow, error) {
+	window := MaintenanceWindow{Spec: strings.TrimSpace(spec)}
+
+	fields := strings.Fields(window.Spec)
+	if len(fields) != 2 {
+		return window, fmt.Errorf("invalid maintenance window %q: expected \"<days> HH:MM-HH:MM\"", spec)
+	}
+
+	days, err := parseWeekdays(fields[0])
+	if err != nil {
+		return window, fmt.Errorf("invalid maintenance window %q: %w", spec, err)
+	}
+	window.Days = days
+
+	times := strings.Split(fields[1], "-")
+	if len(times) != 2 {
+		return window, fmt.Errorf("invalid maintenance window %q: expected time range HH:MM-HH:MM", spec)
+	}
+
+	if window.Start, err = parseClock(times[0]); err != nil {
+		return window, fmt.Errorf("invalid maintenance window %q: %w", spec, err)
+	}
+	if window.End, err = parseClock(times[1]); err != nil {
+		return window, fmt.Errorf("invalid maintenance window %q: %w", spec, err)
+	}
+	if window.Start == window.End {
+		return window, fmt.Errorf("invalid maintenance window %q: start and end are equal", spec)
+	}
+
+	return window, nil
+}
+
+// Contains reports whether t falls inside the window
+func (w MaintenanceWindow) Contains(t time.Time) bool {
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+
+	if w.End > w.Start {
+		return w.opensOn(t.Weekday()) && offset >= w.Start && offset < w.End
+	}
+
+	// Overnight window: the late part belongs to today's opening,
+	// the early part to yesterday's
+	if offset >= w.Start {
+		return w.opensOn(t.Weekday())
+	}
+	if offset < w.End {
+		return w.opensOn((t.Weekday() + 6) % 7)
+	}
+	return false
+}
+
+// NextStart returns the next time at or after t that the window opens
+func (w MaintenanceWindow) NextStart(t time.Time) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	for i := 0; i <= 7; i++ {
+		day := midnight.AddDate(0, 0, i)
+		start := day.Add(w.Start)
+		if w.opensOn(day.Weekday()) && !start.Before(t) {
+			return start
+		}
+	}
+	return time.Time{}
+}
+
+// opensOn reports whether the window opens on the given weekday
+func (w MaintenanceWindow) opensOn(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// parseWeekdays parses "daily", "Mon", "Mon-Fri" or "Sat,Sun"
+func parseWeekdays(spec string) ([]time.Weekday, error) {
+	spec = strings.ToLower(spec)
+	if spec == "daily" || spec == "*" {
+		return nil, nil
+	}
+
+	var days []time.Weekday
+	for _, part := range strings.Split(spec, ",") {
+		bounds := strings.Split(part, "-")
+		if len(bounds) > 2 {
+			return nil, fmt.Errorf("invalid day range %q", part)
+		}
+
+		first, ok := weekdayNames[bounds[0]]
+		if !ok {
+			return nil, fmt.Errorf("unknown day %q", bounds[0])
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, ok = weekdayNames[bounds[1]]; !ok {
+				return nil, fmt.Errorf("unknown day %q", bounds[1])
+			}
+		}
+
+		// Ranges may wrap the week, e.g. Fri-Mon
+		for d := first; ; d = (d + 1) % 7 {
+			days = append(days, d)
+			if d == last {
+				break
+			}
+		}
+	}
+
+	return days, nil
+}
+
+// parseClock parses HH:MM into an offset from midnight; 24:00 marks end of day
+func parseClock(value string) (time.Duration, error) {
+	var hour, minute int
+	if n, err := fmt.Sscanf(value, "%d:%d", &hour, &minute); err != nil || n != 2 {
+		return 0, fmt.Errorf("invalid time %q: expected HH:MM", value)
+	}
+	if hour < 0 || hour > 24 || minute < 0 || minute > 59 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, nil
+}
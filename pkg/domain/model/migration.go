@@ -0,0 +1,56 @@
+// pkg/domain/model/migration.go
+package model
+
+// Files "hardn import" reads its settings from
+const (
+	MigrationUfwRulesPath    = "/etc/ufw/user.rules"
+	MigrationUfw6RulesPath   = "/etc/ufw/user6.rules"
+	MigrationUfwDefaultsPath = "/etc/default/ufw"
+	MigrationSshdConfigPath  = "/etc/ssh/sshd_config"
+	MigrationSudoersDir      = "/etc/sudoers.d"
+	MigrationFail2banPath    = "/etc/fail2ban/jail.local"
+)
+
+// Kinds of artifact "hardn import" reads
+const (
+	MigrationSourceUfw      = "ufw"
+	MigrationSourceSshd     = "sshd"
+	MigrationSourceSudoers  = "sudoers"
+	MigrationSourceFail2ban = "fail2ban"
+)
+
+// MigrationArtifact records what was taken from one existing file and what
+// has no hardn equivalent
+type MigrationArtifact struct {
+	Source   string   `json:"source"`
+	Path     string   `json:"path"`
+	Imported []string `json:"imported"`
+	Skipped  []string `json:"skipped"`
+}
+
+// MigrationResult holds the settings found on a host hardened by other
+// tools. Zero values mean the setting was not found, so the hardn default
+// applies.
+type MigrationResult struct {
+	Artifacts []MigrationArtifact `json:"artifacts"`
+
+	SshPort            int      `json:"sshPort,omitempty"`
+	PermitRootLogin    *bool    `json:"permitRootLogin,omitempty"`
+	SshAllowedUsers    []string `json:"sshAllowedUsers,omitempty"`
+	SshListenAddresses []string `json:"sshListenAddresses,omitempty"`
+
+	Username       string `json:"username,omitempty"`
+	SudoNoPassword *bool  `json:"sudoNoPassword,omitempty"`
+
+	UfwDefaultIncomingPolicy string `json:"ufwDefaultIncomingPolicy,omitempty"`
+	UfwDefaultOutgoingPolicy string `json:"ufwDefaultOutgoingPolicy,omitempty"`
+	UfwAllowedPorts          []int  `json:"ufwAllowedPorts,omitempty"`
+
+	// Packages the host relies on, such as fail2ban
+	Packages []string `json:"packages,omitempty"`
+}
+
+// Found reports whether any artifact was read
+func (r *MigrationResult) Found() bool {
+	return len(r.Artifacts) > 0
+}
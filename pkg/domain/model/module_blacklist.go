@@ -0,0 +1,58 @@
+// pkg/domain/model/module_blacklist.go
+package model
+
+import "strings"
+
+// ModuleBlacklistPath is the modprobe.d file hardn keeps its blacklist in
+const ModuleBlacklistPath = "/etc/modprobe.d/hardn-blacklist.conf"
+
+// ProcModulesPath lists the kernel modules currently loaded
+const ProcModulesPath = "/proc/modules"
+
+// UsbStorageModule is the driver for USB mass storage devices
+const UsbStorageModule = "usb-storage"
+
+// SquashfsModule mounts squashfs images; snaps are squashfs images, so it
+// is kept on hosts with snapd
+const SquashfsModule = "squashfs"
+
+// DefaultBlacklistedModules are the filesystems the CIS benchmarks expect
+// servers not to mount
+var DefaultBlacklistedModules = []string{
+	"cramfs",
+	"freevxfs",
+	"hfs",
+	"hfsplus",
+	"jffs2",
+	SquashfsModule,
+	"udf",
+}
+
+// ModuleBlacklistPolicy is the set of kernel modules to keep from loading
+type ModuleBlacklistPolicy struct {
+	Modules    []string
+	UsbStorage bool
+}
+
+// ModuleBlacklistStatus reports the blacklist in effect for a policy
+type ModuleBlacklistStatus struct {
+	// Disabled lists the modules of the policy modprobe will not load
+	Disabled []string `json:"disabled"`
+	// Missing lists the modules of the policy modprobe would still load
+	Missing []string `json:"missing,omitempty"`
+	// Loaded lists the modules of the policy loaded now; they stay loaded
+	// until they are removed or the host reboots
+	Loaded []string `json:"loaded,omitempty"`
+}
+
+// Compliant reports whether every module of the policy is disabled and
+// none is loaded
+func (s ModuleBlacklistStatus) Compliant() bool {
+	return len(s.Missing) == 0 && len(s.Loaded) == 0
+}
+
+// NormalizeModuleName returns the name of a module as the kernel reports
+// it; modprobe treats dashes and underscores in module names alike
+func NormalizeModuleName(name string) string {
+	return strings.ReplaceAll(strings.TrimSpace(name), "-", "_")
+}
@@ -0,0 +1,73 @@
+// pkg/domain/model/network_exposure.go
+package model
+
+import (
+	"net"
+	"strings"
+)
+
+// How far the firewall lets other hosts reach a listening socket
+const (
+	// ExposureLocal sockets are bound to a loopback address
+	ExposureLocal = "local"
+	// ExposureOpen sockets are allowed from any source by a firewall rule
+	ExposureOpen = "open"
+	// ExposureRestricted sockets are allowed from some sources only
+	ExposureRestricted = "restricted"
+	// ExposureBlocked sockets have no rule and the default policy drops them
+	ExposureBlocked = "blocked"
+	// ExposureUnfiltered sockets are reachable because no firewall filters
+	// incoming traffic
+	ExposureUnfiltered = "unfiltered"
+)
+
+// ListeningSocket is a TCP or UDP socket accepting connections
+type ListeningSocket struct {
+	Protocol   string `json:"protocol"` // tcp or udp
+	Address    string `json:"address"`  // "0.0.0.0", "::" or a specific address
+	Port       int    `json:"port"`
+	Process    string `json:"process,omitempty"`
+	PID        int    `json:"pid,omitempty"`
+	Executable string `json:"executable,omitempty"`
+	Package    string `json:"package,omitempty"` // package owning the executable
+}
+
+// Wildcard reports whether the socket is bound to every address
+func (s ListeningSocket) Wildcard() bool {
+	return s.Address == "0.0.0.0" || s.Address == "::" || s.Address == "*"
+}
+
+// Loopback reports whether only local processes can reach the socket
+func (s ListeningSocket) Loopback() bool {
+	if strings.EqualFold(s.Address, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(s.Address)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ExposedService is a listening socket and how far it is reachable
+type ExposedService struct {
+	ListeningSocket
+	Exposure string `json:"exposure"`
+	Rule     string `json:"rule,omitempty"` // firewall rule deciding about it
+}
+
+// NetworkExposure is the inventory of listening sockets on the host
+type NetworkExposure struct {
+	FirewallEnabled   bool             `json:"firewall_enabled"`
+	FirewallFiltering bool             `json:"firewall_filtering"` // unmatched traffic is dropped
+	Services          []ExposedService `json:"services"`
+}
+
+// Unfiltered returns the services other hosts reach without any firewall
+// rule deciding about them
+func (e *NetworkExposure) Unfiltered() []ExposedService {
+	var services []ExposedService
+	for _, service := range e.Services {
+		if service.Exposure == ExposureUnfiltered {
+			services = append(services, service)
+		}
+	}
+	return services
+}
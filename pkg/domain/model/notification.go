@@ -0,0 +1,127 @@
+// pkg/domain/model/notification.go
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Operations a run summary is sent after
+const (
+	SummaryRunAll     = "run-all"
+	SummaryAudit      = "audit"
+	SummaryBackupSync = "backup-sync"
+	SummaryPosture    = "posture-summary"
+)
+
+// EmailSettings is the SMTP server and addresses run summaries are mailed with
+type EmailSettings struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// Configured reports whether enough is set to send mail
+func (e EmailSettings) Configured() bool {
+	return e.Host != "" && len(e.To) > 0
+}
+
+// NotificationSettings says where run summaries are sent
+type NotificationSettings struct {
+	Email         EmailSettings
+	Webhooks      []string
+	OnlyOnFailure bool
+}
+
+// RunSummary describes the outcome of a hardening run or audit
+type RunSummary struct {
+	Hostname   string    `json:"hostname"`
+	Operation  string    `json:"operation"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Failed     bool      `json:"failed"`
+
+	// RiskBefore is empty for audits, which change nothing, and both are
+	// empty for backup syncs
+	RiskBefore string `json:"risk_before,omitempty"`
+	RiskAfter  string `json:"risk_after"`
+
+	// FailedSteps are the hardening steps that failed, with their errors
+	FailedSteps []string `json:"failed_steps,omitempty"`
+
+	// FailedChecks are the controls and findings an audit reported
+	FailedChecks []string `json:"failed_checks,omitempty"`
+
+	// Trend, Drift, UpdatesApplied and FailedRuns are set for posture
+	// summaries, which cover StartedAt to FinishedAt
+	Trend          string   `json:"trend,omitempty"`
+	Drift          []string `json:"drift,omitempty"`
+	UpdatesApplied []string `json:"updates_applied,omitempty"`
+	FailedRuns     []string `json:"failed_runs,omitempty"`
+
+	// Hosts is set for the posture summary of an inventory: the risk and
+	// trend of each host, or why its summary is missing
+	Hosts []string `json:"hosts,omitempty"`
+}
+
+// Subject is a one-line summary, used as the mail subject
+func (s RunSummary) Subject() string {
+	if s.Operation == SummaryPosture {
+		return fmt.Sprintf("hardn posture summary for %s: risk %s (%s)", s.Hostname, s.RiskAfter, s.Trend)
+	}
+	outcome := "succeeded"
+	if s.Failed {
+		outcome = "failed"
+	}
+	if s.RiskAfter == "" {
+		return fmt.Sprintf("hardn %s on %s %s", s.Operation, s.Hostname, outcome)
+	}
+	return fmt.Sprintf("hardn %s on %s %s (risk: %s)", s.Operation, s.Hostname, outcome, s.RiskAfter)
+}
+
+// Text renders the summary as plain text
+func (s RunSummary) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", s.Subject())
+	fmt.Fprintf(&b, "Host:       %s\n", s.Hostname)
+	fmt.Fprintf(&b, "Operation:  %s\n", s.Operation)
+	if s.Operation == SummaryPosture {
+		fmt.Fprintf(&b, "Period:     %s to %s\n", s.StartedAt.Format(time.RFC3339), s.FinishedAt.Format(time.RFC3339))
+	} else {
+		fmt.Fprintf(&b, "Finished:   %s (%s)\n", s.FinishedAt.Format(time.RFC3339),
+			s.FinishedAt.Sub(s.StartedAt).Round(time.Second))
+	}
+	if s.RiskBefore != "" && s.RiskBefore != s.RiskAfter {
+		fmt.Fprintf(&b, "Risk level: %s -> %s\n", s.RiskBefore, s.RiskAfter)
+	} else if s.RiskAfter != "" {
+		fmt.Fprintf(&b, "Risk level: %s\n", s.RiskAfter)
+	}
+	if s.Trend != "" {
+		fmt.Fprintf(&b, "Trend:      %s\n", s.Trend)
+	}
+
+	for _, group := range []struct {
+		title string
+		items []string
+	}{
+		{"Hosts", s.Hosts},
+		{"Failed steps", s.FailedSteps},
+		{"Failed runs", s.FailedRuns},
+		{"Drift", s.Drift},
+		{"Updates applied", s.UpdatesApplied},
+		{"Failed checks", s.FailedChecks},
+	} {
+		if len(group.items) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s:\n", group.title)
+		for _, item := range group.items {
+			fmt.Fprintf(&b, "  - %s\n", item)
+		}
+	}
+	return b.String()
+}
@@ -1,6 +1,39 @@
 // pkg/domain/model/package.go
 package model
 
+// Alpine automatic upgrades run from a daily periodic script managed by hardn
+const (
+	AlpineAutoUpgradeScriptPath = "/etc/periodic/daily/apk-upgrade"
+	AlpineAutoUpgradeLogPath    = "/var/log/apk-upgrade.log"
+)
+
+// RHEL-family automatic upgrades run from dnf-automatic's systemd timer
+const (
+	DnfAutomaticConfigPath = "/etc/dnf/automatic.conf"
+	DnfAutomaticTimer      = "dnf-automatic.timer"
+)
+
+// YumReposDir holds the repository files of RHEL-family distributions
+const YumReposDir = "/etc/yum.repos.d"
+
+// NeedrestartConfigPath is the needrestart drop-in written by hardn
+const NeedrestartConfigPath = "/etc/needrestart/conf.d/hardn.conf"
+
+// NeedrestartModes maps configuration names to needrestart's $nrconf{restart} values
+var NeedrestartModes = map[string]string{
+	"automatic":   "a",
+	"list":        "l",
+	"interactive": "i",
+}
+
+// NeedrestartStatus reports services and kernel waiting on a restart
+type NeedrestartStatus struct {
+	Installed       bool
+	Mode            string
+	PendingServices []string
+	RebootRequired  bool
+}
+
 // PackageInstallRequest represents a request to install packages
 type PackageInstallRequest struct {
 	Packages       []string
@@ -27,6 +60,7 @@ type PackageSources struct {
 	ProxmoxCephRepo       []string
 	ProxmoxEnterpriseRepo []string
 	AlpineTestingRepo     bool
+	SourcesFormat         string // auto, list or deb822
 
 	// Package lists by OS and environment
 	DebianCorePackages []string
@@ -35,10 +69,14 @@ type PackageSources struct {
 	AlpineCorePackages []string
 	AlpineDmzPackages  []string
 	AlpineLabPackages  []string
+	RhelCorePackages   []string
+	RhelDmzPackages    []string
+	RhelLabPackages    []string
 
 	// Python packages
 	DebianPythonPackages []string
 	NonWslPythonPackages []string
 	PythonPipPackages    []string
 	AlpinePythonPackages []string
+	RhelPythonPackages   []string
 }
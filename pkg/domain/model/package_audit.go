@@ -0,0 +1,114 @@
+// pkg/domain/model/package_audit.go
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PackageAuditPath is where the last package vulnerability check is kept,
+// so the status display can show it without checking again
+const PackageAuditPath = "/var/lib/hardn/package-audit.json"
+
+// Severities of security advisories beyond those of audit findings;
+// advisories without a rating are unknown
+const (
+	SeverityCritical = "critical"
+	SeverityUnknown  = "unknown"
+)
+
+// PackageSeverities are the advisory severities, most severe first
+var PackageSeverities = []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityUnknown}
+
+// packageSeverityRank orders severities for sorting; lower is more severe
+func packageSeverityRank(severity string) int {
+	for i, known := range PackageSeverities {
+		if severity == known {
+			return i
+		}
+	}
+	return len(PackageSeverities) - 1
+}
+
+// MoreSevere reports whether severity a ranks above severity b
+func MoreSevere(a, b string) bool {
+	return packageSeverityRank(a) < packageSeverityRank(b)
+}
+
+// InstalledPackage is a package installed on the host
+type InstalledPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// PackageVulnerability is a security advisory affecting an installed package
+type PackageVulnerability struct {
+	ID           string `json:"id"` // CVE, or the advisory's own ID when it has none
+	Package      string `json:"package"`
+	Version      string `json:"version"`
+	Severity     string `json:"severity"`
+	FixAvailable bool   `json:"fix_available"`
+	FixedVersion string `json:"fixed_version,omitempty"`
+	Remote       bool   `json:"remote,omitempty"` // remotely exploitable, when the source says
+}
+
+// PackageAuditReport is the result of checking the installed packages
+// against the distribution's security advisories
+type PackageAuditReport struct {
+	CheckedAt       time.Time              `json:"checked_at"`
+	Source          string                 `json:"source"`
+	Packages        []InstalledPackage     `json:"packages"`
+	Vulnerabilities []PackageVulnerability `json:"vulnerabilities"`
+}
+
+// Summary counts the advisories of the report by severity
+func (r *PackageAuditReport) Summary() *PackageAuditSummary {
+	summary := &PackageAuditSummary{
+		CheckedAt: r.CheckedAt,
+		Source:    r.Source,
+		Packages:  len(r.Packages),
+		CVEs:      make(map[string]int),
+	}
+
+	// An advisory listed for several packages counts once, at its highest
+	// severity
+	severities := make(map[string]string)
+	for _, vulnerability := range r.Vulnerabilities {
+		if current, ok := severities[vulnerability.ID]; !ok || MoreSevere(vulnerability.Severity, current) {
+			severities[vulnerability.ID] = vulnerability.Severity
+		}
+	}
+	for _, severity := range severities {
+		summary.CVEs[severity]++
+	}
+	return summary
+}
+
+// PackageAuditSummary is the advisory count of a package check
+type PackageAuditSummary struct {
+	CheckedAt time.Time      `json:"checked_at"`
+	Source    string         `json:"source"`
+	Packages  int            `json:"packages"`
+	CVEs      map[string]int `json:"cves"` // distinct advisories by severity
+}
+
+// Total returns the number of distinct advisories
+func (s *PackageAuditSummary) Total() int {
+	total := 0
+	for _, count := range s.CVEs {
+		total += count
+	}
+	return total
+}
+
+// Describe lists the counts by severity, such as "1 critical, 4 high"
+func (s *PackageAuditSummary) Describe() string {
+	var parts []string
+	for _, severity := range PackageSeverities {
+		if count := s.CVEs[severity]; count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count, severity))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
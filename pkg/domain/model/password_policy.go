@@ -0,0 +1,108 @@
+// pkg/domain/model/password_policy.go
+package model
+
+import "fmt"
+
+// Password policy files: login.defs sets the password aging of new
+// accounts, pwquality.conf the strength pam_pwquality requires of new
+// passwords, and faillock.conf when pam_faillock locks an account
+const (
+	LoginDefsPath       = "/etc/login.defs"
+	PwqualityConfigPath = "/etc/security/pwquality.conf"
+	FaillockConfigPath  = "/etc/security/faillock.conf"
+)
+
+// On Debian and Ubuntu, pam_faillock is added to the PAM stack with two
+// pam-auth-update profiles: one before pam_unix counts the failure, one
+// after it resets the count and checks the lock
+const (
+	PamConfigsDir            = "/usr/share/pam-configs"
+	FaillockPamProfile       = "hardn-faillock"
+	FaillockNotifyPamProfile = "hardn-faillock-notify"
+)
+
+// Defaults of the password policy, following the CIS benchmarks
+const (
+	DefaultPasswordMinLength = 14
+	DefaultPasswordMaxDays   = 365
+	DefaultLockoutAttempts   = 5
+	DefaultLockoutUnlockTime = 900 // seconds
+)
+
+// PasswordPolicy is the strength, aging and lockout policy for local
+// passwords
+type PasswordPolicy struct {
+	// MinLength is the shortest password pam_pwquality accepts
+	MinLength int
+	// Credits as in pwquality.conf: a negative value requires at least
+	// that many characters of the class
+	DigitCredit int
+	UpperCredit int
+	LowerCredit int
+	OtherCredit int
+	// Retry is how often passwd asks again after a rejected password
+	Retry int
+
+	// Aging of new accounts, in days
+	MaxDays int
+	MinDays int
+	WarnAge int
+
+	// LockoutAttempts failed logins lock an account for LockoutUnlockTime
+	// seconds; 0 attempts leaves lockout off, 0 seconds locks until
+	// "faillock --reset"
+	LockoutAttempts   int
+	LockoutUnlockTime int
+}
+
+// DefaultPasswordPolicy requires 14 characters with a digit, an upper and
+// a lower case letter and a symbol, a new password each year and locks an
+// account for 15 minutes after 5 failed logins
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:         DefaultPasswordMinLength,
+		DigitCredit:       -1,
+		UpperCredit:       -1,
+		LowerCredit:       -1,
+		OtherCredit:       -1,
+		Retry:             3,
+		MaxDays:           DefaultPasswordMaxDays,
+		MinDays:           1,
+		WarnAge:           7,
+		LockoutAttempts:   DefaultLockoutAttempts,
+		LockoutUnlockTime: DefaultLockoutUnlockTime,
+	}
+}
+
+// PasswordPolicyStatus reports the password policy in effect. Values not
+// set on the host are 0.
+type PasswordPolicyStatus struct {
+	// Complexity is set when pam_pwquality checks new passwords
+	Complexity bool `json:"complexity"`
+	MinLength  int  `json:"min_length,omitempty"`
+
+	MaxDays int `json:"max_days,omitempty"`
+	MinDays int `json:"min_days,omitempty"`
+	WarnAge int `json:"warn_age,omitempty"`
+
+	// Lockout is set when pam_faillock locks accounts after failed logins
+	Lockout         bool `json:"lockout"`
+	LockoutAttempts int  `json:"lockout_attempts,omitempty"`
+}
+
+// Weaknesses lists how the policy falls short of the default policy
+func (s PasswordPolicyStatus) Weaknesses() []string {
+	var weaknesses []string
+	if !s.Complexity {
+		weaknesses = append(weaknesses, "no complexity check")
+	} else if s.MinLength < DefaultPasswordMinLength {
+		weaknesses = append(weaknesses, fmt.Sprintf("minimum length %d", s.MinLength))
+	}
+	if s.MaxDays == 0 || s.MaxDays > DefaultPasswordMaxDays {
+		weaknesses = append(weaknesses, "passwords never expire")
+	}
+	if !s.Lockout {
+		weaknesses = append(weaknesses, "no lockout")
+	}
+	return weaknesses
+}
@@ -0,0 +1,101 @@
+// pkg/domain/model/policy.go
+package model
+
+import "time"
+
+// PolicyQuery is the rego rule policies define to report violations, e.g.
+//
+//	package hardn
+//	deny contains msg if { input.ssh.port == 22; msg := "SSH on default port" }
+const PolicyQuery = "data.hardn.deny"
+
+// Facts is the host state document policies are evaluated against
+type Facts struct {
+	CollectedAt     time.Time        `json:"collected_at"`
+	Hostname        string           `json:"hostname"`
+	OS              FactsOS          `json:"os"`
+	SSH             FactsSSH         `json:"ssh"`
+	Firewall        FactsFirewall    `json:"firewall"`
+	Users           FactsUsers       `json:"users"`
+	AppArmor        bool             `json:"apparmor"` // AppArmor enforcing
+	SELinux         bool             `json:"selinux"`  // SELinux enforcing
+	MAC             *MACStatus       `json:"mac"`
+	AutoUpdates     bool             `json:"auto_updates"`
+	TimeSync        string           `json:"time_sync"` // running time service, e.g. chronyd
+	Directory       FactsDirectory   `json:"directory"`
+	PendingRestarts []string         `json:"pending_restarts"`
+	RebootRequired  bool             `json:"reboot_required"`
+	WebServers      []FactsWebServer `json:"web_servers"`
+	Databases       []FactsDatabase  `json:"databases"`
+}
+
+// FactsOS describes the operating system
+type FactsOS struct {
+	Type     string `json:"type"`
+	Version  string `json:"version"`
+	Codename string `json:"codename"`
+	Proxmox  bool   `json:"proxmox"`
+	Arch     string `json:"arch"` // GOARCH name, e.g. amd64, arm, riscv64
+
+	// Bare metal, VM or container
+	Environment HostEnvironment `json:"environment"`
+}
+
+// FactsSSH describes the SSH daemon configuration
+type FactsSSH struct {
+	Port             int      `json:"port"`
+	ListenAddresses  []string `json:"listen_addresses"`
+	RootLogin        bool     `json:"root_login"`
+	PasswordAuth     bool     `json:"password_auth"`
+	AllowedUsers     []string `json:"allowed_users"`
+	DirectoryKeyAuth bool     `json:"directory_key_auth"`
+}
+
+// FactsFirewall describes the host firewall
+type FactsFirewall struct {
+	Enabled    bool `json:"enabled"`
+	Configured bool `json:"configured"`
+}
+
+// FactsUsers describes privileged account setup
+type FactsUsers struct {
+	NonRootSudo    bool `json:"non_root_sudo"`
+	SudoConfigured bool `json:"sudo_configured"`
+}
+
+// FactsDirectory describes LDAP/SSSD account resolution
+type FactsDirectory struct {
+	Enabled bool     `json:"enabled"`
+	Sources []string `json:"sources"`
+}
+
+// FactsWebServer describes an installed web server
+type FactsWebServer struct {
+	Name        string `json:"name"`
+	TLSBaseline bool   `json:"tls_baseline"`
+}
+
+// FactsDatabase describes an installed database server
+type FactsDatabase struct {
+	Name     string `json:"name"`
+	Flavor   string `json:"flavor,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Port     int    `json:"port"`
+	Baseline bool   `json:"baseline"`
+}
+
+// PolicyViolation is one finding reported by a policy or built-in audit check
+type PolicyViolation struct {
+	Rule        string `json:"rule,omitempty"`
+	Severity    string `json:"severity,omitempty"`
+	Message     string `json:"message"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// PolicyReport is the result of evaluating a policy directory
+type PolicyReport struct {
+	PolicyDir   string            `json:"policy_dir"`
+	EvaluatedAt time.Time         `json:"evaluated_at"`
+	Facts       *Facts            `json:"facts"`
+	Violations  []PolicyViolation `json:"violations"`
+}
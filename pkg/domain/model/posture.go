@@ -0,0 +1,78 @@
+// pkg/domain/model/posture.go
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// PostureHistoryPath keeps a snapshot of each security status check, one
+// JSON document per line, oldest first
+const PostureHistoryPath = "/var/lib/hardn/posture.jsonl"
+
+// MaxPostureSnapshots is how many snapshots the history keeps; older ones
+// are dropped
+const MaxPostureSnapshots = 500
+
+// What took a posture snapshot
+const (
+	PostureSourceStatus = "status"
+	PostureSourceAudit  = "audit"
+	PostureSourceMenu   = "menu"
+	PostureSourceRunAll = "run-all" // before and after, in run reports
+)
+
+// Directions of the posture between two snapshots
+const (
+	TrendImproved  = "improved"
+	TrendRegressed = "regressed"
+	TrendSteady    = "steady"
+)
+
+// PostureSnapshot is the outcome of one security status check
+type PostureSnapshot struct {
+	Time      time.Time `json:"time"`
+	Source    string    `json:"source"`
+	Score     int       `json:"score"`
+	MaxScore  int       `json:"max_score"`
+	RiskLevel string    `json:"risk_level"`
+	// Checks maps each audit control ID to whether the host passed it
+	Checks map[string]bool `json:"checks"`
+}
+
+// Regressions returns the controls that passed in previous and fail, or
+// are gone, now
+func (s PostureSnapshot) Regressions(previous PostureSnapshot) []string {
+	var ids []string
+	for id, passed := range previous.Checks {
+		if passed && !s.Checks[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Improvements returns the controls that pass now and did not in previous
+func (s PostureSnapshot) Improvements(previous PostureSnapshot) []string {
+	var ids []string
+	for id, passed := range s.Checks {
+		if passed && !previous.Checks[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Trend compares the snapshot with previous: a lower score or a control
+// that stopped passing is a regression, even when others improved
+func (s PostureSnapshot) Trend(previous PostureSnapshot) string {
+	switch {
+	case s.Score < previous.Score || len(s.Regressions(previous)) > 0:
+		return TrendRegressed
+	case s.Score > previous.Score || len(s.Improvements(previous)) > 0:
+		return TrendImproved
+	}
+	return TrendSteady
+}
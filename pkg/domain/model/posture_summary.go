@@ -0,0 +1,158 @@
+// pkg/domain/model/posture_summary.go
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// The posture summary is sent by a systemd timer of its own, or a root
+// crontab entry on Alpine, installed with the schedule
+const (
+	SummaryUnitName    = "hardn-summary"
+	SummaryServicePath = "/etc/systemd/system/hardn-summary.service"
+	SummaryTimerPath   = "/etc/systemd/system/hardn-summary.timer"
+)
+
+// DefaultSummaryDays is how many days the posture summary covers when
+// schedule.summary.days is unset
+const DefaultSummaryDays = 7
+
+// AptHistoryLogPath is apt's log of the packages it installed and
+// upgraded; Alpine's upgrades are read from AlpineAutoUpgradeLogPath
+const AptHistoryLogPath = "/var/log/apt/history.log"
+
+// DnfRPMLogPath is dnf's log of the packages each transaction installed,
+// upgraded and removed, on Rocky Linux, AlmaLinux and Fedora
+const DnfRPMLogPath = "/var/log/dnf.rpm.log"
+
+// AppliedUpdate is a package the package manager upgraded
+type AppliedUpdate struct {
+	Time    time.Time `json:"time"`
+	Package string    `json:"package"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+}
+
+// String describes the upgrade
+func (u AppliedUpdate) String() string {
+	return fmt.Sprintf("%s %s -> %s", u.Package, u.From, u.To)
+}
+
+// PostureDrift is a snapshot in which controls stopped passing
+type PostureDrift struct {
+	Time time.Time `json:"time"`
+	// Regressed are the controls that passed in the snapshot before
+	Regressed []string `json:"regressed"`
+}
+
+// PostureSummary sums up the security posture of a period: how the risk
+// moved, the controls that drifted, the upgrades installed and the runs
+// that failed
+type PostureSummary struct {
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+
+	// First is the posture at the start of the period: the last snapshot
+	// taken before it, or else the first taken during it. Latest is the
+	// last snapshot of the period. Both are nil without snapshots.
+	First  *PostureSnapshot `json:"first,omitempty"`
+	Latest *PostureSnapshot `json:"latest,omitempty"`
+
+	Drift      []PostureDrift  `json:"drift,omitempty"`
+	Updates    []AppliedUpdate `json:"updates,omitempty"`
+	FailedRuns []RunReport     `json:"failed_runs,omitempty"`
+}
+
+// Trend compares the latest posture with the first; without two
+// snapshots to compare it is steady
+func (s PostureSummary) Trend() string {
+	if s.First == nil || s.Latest == nil {
+		return TrendSteady
+	}
+	return s.Latest.Trend(*s.First)
+}
+
+// FailedChecks returns the controls the latest snapshot failed
+func (s PostureSummary) FailedChecks() []string {
+	if s.Latest == nil {
+		return nil
+	}
+	var ids []string
+	for id, passed := range s.Latest.Checks {
+		if !passed {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Failed reports whether the period needs attention: the posture
+// regressed, a control drifted or a run failed
+func (s PostureSummary) Failed() bool {
+	return s.Trend() == TrendRegressed || len(s.Drift) > 0 || len(s.FailedRuns) > 0
+}
+
+// HostPostureSummary is the posture summary of one host of an inventory,
+// or why it could not be collected
+type HostPostureSummary struct {
+	Host    string          `json:"host"`
+	Summary *PostureSummary `json:"summary,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// FleetPostureSummary sums up the posture of the hosts of an inventory
+// over the same period
+type FleetPostureSummary struct {
+	Since time.Time            `json:"since"`
+	Until time.Time            `json:"until"`
+	Hosts []HostPostureSummary `json:"hosts"`
+}
+
+// Trend is regressed when any host regressed, improved when a host
+// improved and none regressed, and steady otherwise
+func (f FleetPostureSummary) Trend() string {
+	trend := TrendSteady
+	for _, host := range f.Hosts {
+		if host.Summary == nil {
+			continue
+		}
+		switch host.Summary.Trend() {
+		case TrendRegressed:
+			return TrendRegressed
+		case TrendImproved:
+			trend = TrendImproved
+		}
+	}
+	return trend
+}
+
+// Weakest returns the lowest scoring first and latest snapshots of any
+// host, which carry the fleet's risk level; both are nil without snapshots
+func (f FleetPostureSummary) Weakest() (first, latest *PostureSnapshot) {
+	for _, host := range f.Hosts {
+		if host.Summary == nil {
+			continue
+		}
+		if s := host.Summary.First; s != nil && (first == nil || s.Score < first.Score) {
+			first = s
+		}
+		if s := host.Summary.Latest; s != nil && (latest == nil || s.Score < latest.Score) {
+			latest = s
+		}
+	}
+	return first, latest
+}
+
+// Failed reports whether the fleet needs attention: a host's period did,
+// or a host gave no summary
+func (f FleetPostureSummary) Failed() bool {
+	for _, host := range f.Hosts {
+		if host.Summary == nil || host.Summary.Failed() {
+			return true
+		}
+	}
+	return false
+}
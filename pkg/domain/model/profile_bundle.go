@@ -0,0 +1,70 @@
+// pkg/domain/model/profile_bundle.go
+package model
+
+import "time"
+
+// ProfileBundleKind identifies a hardn profile bundle
+const ProfileBundleKind = "hardn-profile"
+
+// ProfileBundleFormat is the version of the profile bundle format
+const ProfileBundleFormat = 1
+
+// ProfileSignatureSuffix is appended to a bundle's path for its detached
+// GnuPG signature
+const ProfileSignatureSuffix = ".asc"
+
+// SysctlDropInDir holds the sysctl files a profile bundle carries
+const SysctlDropInDir = "/etc/sysctl.d"
+
+// ProfileBundle is a hardening profile shared between hosts: hardn's
+// configuration, which holds the SSH hardening profile, the UFW
+// application profiles and the package lists, and the sysctl drop-ins
+type ProfileBundle struct {
+	Kind         string        `yaml:"kind"`
+	Format       int           `yaml:"format"`
+	Name         string        `yaml:"name,omitempty"`
+	CreatedAt    time.Time     `yaml:"createdAt"`
+	Hostname     string        `yaml:"hostname,omitempty"`
+	HardnVersion string        `yaml:"hardnVersion,omitempty"`
+	Config       string        `yaml:"config"`
+	Sysctl       []ProfileFile `yaml:"sysctl,omitempty"`
+}
+
+// ProfileFile is a file carried in a profile bundle
+type ProfileFile struct {
+	Path    string `yaml:"path"`
+	Content string `yaml:"content"`
+}
+
+// ProfileExportRequest describes a profile bundle to export
+type ProfileExportRequest struct {
+	Name         string
+	Hostname     string
+	HardnVersion string
+	ConfigFile   string   // hardn configuration to include
+	SysctlFiles  []string // nil for every .conf file in SysctlDropInDir
+	Output       string
+	SignKey      string // GnuPG key to sign with; empty leaves the bundle unsigned
+}
+
+// ProfileExport is a written profile bundle
+type ProfileExport struct {
+	Path          string
+	SignaturePath string // empty when unsigned
+	Bundle        *ProfileBundle
+}
+
+// ProfileImport is a profile bundle read for import and whether a
+// signature vouches for it
+type ProfileImport struct {
+	Bundle *ProfileBundle
+	Signed bool
+	Signer string // fingerprint of the key that signed the bundle
+}
+
+// ProfileChange is a file an import writes; Before is nil for a new file
+type ProfileChange struct {
+	Path   string
+	Before []byte
+	After  []byte
+}
@@ -0,0 +1,42 @@
+// pkg/domain/model/progress.go
+package model
+
+import "fmt"
+
+// ProgressReporter follows the steps of a long operation such as Run All
+type ProgressReporter interface {
+	// Plan lists the steps about to run, in order
+	Plan(steps []string)
+
+	// StepStarted marks a step as running
+	StepStarted(step string)
+
+	// StepProgress reports how far the running step is, from 0 to 100,
+	// with a short note such as the packages being installed
+	StepProgress(percent int, detail string)
+
+	// StepCompleted marks a step as done, or as failed when err is set
+	StepCompleted(step string, err error)
+}
+
+// NoProgress is a ProgressReporter that discards progress
+type NoProgress struct{}
+
+func (NoProgress) Plan(steps []string)                     {}
+func (NoProgress) StepStarted(step string)                 {}
+func (NoProgress) StepProgress(percent int, detail string) {}
+func (NoProgress) StepCompleted(step string, err error)    {}
+
+// FailureRecorder passes progress on to a ProgressReporter and records
+// the steps that failed, with their errors
+type FailureRecorder struct {
+	ProgressReporter
+	Failed []string
+}
+
+func (r *FailureRecorder) StepCompleted(step string, err error) {
+	if err != nil {
+		r.Failed = append(r.Failed, fmt.Sprintf("%s: %v", step, err))
+	}
+	r.ProgressReporter.StepCompleted(step, err)
+}
@@ -0,0 +1,54 @@
+// pkg/domain/model/proxmox.go
+package model
+
+// Files and settings of a Proxmox VE host
+const (
+	// PveproxyDefaultsPath holds pveproxy's address and TLS settings
+	PveproxyDefaultsPath = "/etc/default/pveproxy"
+
+	// ProxmoxWidgetToolkitPath is the web UI library that shows the
+	// "No valid subscription" dialog at login
+	ProxmoxWidgetToolkitPath = "/usr/share/javascript/proxmox-widget-toolkit/proxmoxlib.js"
+
+	// ProxmoxNagHookPath re-applies the dialog patch after apt upgrades
+	// proxmox-widget-toolkit
+	ProxmoxNagHookPath = "/etc/apt/apt.conf.d/86hardn-no-subscription-nag"
+
+	// ProxmoxWebUIPort is the port pveproxy serves the web UI and API on
+	ProxmoxWebUIPort = 8006
+
+	// ProxmoxRealm is the realm of the users Proxmox VE itself stores
+	ProxmoxRealm = "pve"
+
+	// ProxmoxTwoFactorType is the second factor the realm requires: TOTP
+	ProxmoxTwoFactorType = "oath"
+)
+
+// ProxmoxCiphers are the TLS 1.2 ciphers pveproxy keeps: ECDHE key
+// exchange with AEAD ciphers only, as the TLS baseline allows
+const ProxmoxCiphers = "ECDHE-ECDSA-AES256-GCM-SHA384:ECDHE-RSA-AES256-GCM-SHA384:" +
+	"ECDHE-ECDSA-CHACHA20-POLY1305:ECDHE-RSA-CHACHA20-POLY1305:" +
+	"ECDHE-ECDSA-AES128-GCM-SHA256:ECDHE-RSA-AES128-GCM-SHA256"
+
+// ProxmoxHardening is what hardn changes on a Proxmox VE host
+type ProxmoxHardening struct {
+	// Patch the web UI so it stops asking for a subscription at login
+	DisableSubscriptionNag bool
+
+	// Addresses and subnets allowed to the web UI; empty leaves it open
+	WebUIAllowlist []string
+
+	// Limit pveproxy to ProxmoxCiphers and its own cipher order
+	HardenCiphers bool
+
+	// Require TOTP for users of the pve realm
+	RealmTwoFactor bool
+}
+
+// ProxmoxStatus reports the Proxmox VE hardening in effect
+type ProxmoxStatus struct {
+	SubscriptionNagDisabled bool     `json:"subscription_nag_disabled"`
+	WebUIAllowlist          []string `json:"web_ui_allowlist,omitempty"` // pveproxy ALLOW_FROM; empty when open
+	CiphersHardened         bool     `json:"ciphers_hardened"`
+	RealmTwoFactor          string   `json:"realm_two_factor,omitempty"` // second factor the pve realm requires
+}
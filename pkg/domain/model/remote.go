@@ -0,0 +1,95 @@
+// pkg/domain/model/remote.go
+package model
+
+import "time"
+
+// DefaultInventoryPath is the inventory hardn remote reads by default
+const DefaultInventoryPath = "/etc/hardn/inventory.yml"
+
+// Outcomes of running hardn on a remote host
+const (
+	RemoteOK          = "ok"
+	RemoteFailed      = "failed"      // hardn ran and exited with an error
+	RemoteUnreachable = "unreachable" // no SSH session, or no hardn to run
+)
+
+// RemoteInventory lists the hosts hardn remote manages. Settings a host
+// leaves empty come from Defaults.
+type RemoteInventory struct {
+	Defaults RemoteHost   `yaml:"defaults"`
+	Hosts    []RemoteHost `yaml:"hosts"`
+}
+
+// RemoteHost is a host of the inventory and how to reach it
+type RemoteHost struct {
+	Name         string   `yaml:"name"`
+	Address      string   `yaml:"address,omitempty"` // host name or IP; the name when empty
+	User         string   `yaml:"user,omitempty"`
+	Port         int      `yaml:"port,omitempty"`
+	IdentityFile string   `yaml:"identityFile,omitempty"` // empty uses ssh-agent and the ssh configuration
+	Groups       []string `yaml:"groups,omitempty"`
+	Push         *bool    `yaml:"push,omitempty"` // copy this hardn binary instead of running the installed one
+	Sudo         *bool    `yaml:"sudo,omitempty"` // run hardn with sudo; on for users other than root
+}
+
+// WithDefaults fills the settings a host leaves empty from defaults
+func (h RemoteHost) WithDefaults(defaults RemoteHost) RemoteHost {
+	if h.Address == "" {
+		h.Address = h.Name
+	}
+	if h.User == "" {
+		h.User = defaults.User
+	}
+	if h.Port == 0 {
+		h.Port = defaults.Port
+	}
+	if h.Port == 0 {
+		h.Port = 22
+	}
+	if h.IdentityFile == "" {
+		h.IdentityFile = defaults.IdentityFile
+	}
+	if h.Push == nil {
+		h.Push = defaults.Push
+	}
+	if h.Sudo == nil {
+		h.Sudo = defaults.Sudo
+	}
+	return h
+}
+
+// UseSudo reports whether hardn runs with sudo on the host
+func (h RemoteHost) UseSudo() bool {
+	if h.Sudo != nil {
+		return *h.Sudo
+	}
+	return h.User != "" && h.User != "root"
+}
+
+// Target is the host as ssh addresses it, with the user when set
+func (h RemoteHost) Target() string {
+	if h.User == "" {
+		return h.Address
+	}
+	return h.User + "@" + h.Address
+}
+
+// RemoteRequest describes a hardn command to run on hosts
+type RemoteRequest struct {
+	Hosts    []RemoteHost
+	Args     []string // hardn's arguments, e.g. ["run-all"]
+	DryRun   bool     // add --dry-run on every host
+	Push     bool     // copy Binary to every host, whatever the inventory says
+	Binary   string   // this hardn binary, for hosts it is pushed to
+	Parallel int      // hosts worked on at once
+}
+
+// RemoteResult is the outcome of a command on one host
+type RemoteResult struct {
+	Host     string
+	Status   string
+	ExitCode int
+	Output   string
+	Duration time.Duration
+	Error    string // why the host was unreachable
+}
@@ -0,0 +1,209 @@
+// pkg/domain/model/repository_trust.go
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Where APT and apk keep the keys they check repositories with
+const (
+	AptKeyringsDir       = "/etc/apt/keyrings"
+	AptLegacyKeyringPath = "/etc/apt/trusted.gpg" // written by apt-key add
+	AptTrustedDir        = "/etc/apt/trusted.gpg.d"
+	AptConfPath          = "/etc/apt/apt.conf"
+	AptConfDir           = "/etc/apt/apt.conf.d"
+	ApkKeysDir           = "/etc/apk/keys"
+)
+
+// Repository trust issues
+const (
+	RepoTrustLegacyKeyring        = "legacy_keyring"
+	RepoTrustGlobalKey            = "global_key"
+	RepoTrustTrustedRepository    = "trusted_repository"
+	RepoTrustAllowUnauthenticated = "allow_unauthenticated"
+	RepoTrustAllowInsecure        = "allow_insecure"
+)
+
+// AptInsecureOptions are the APT options that let packages through without
+// a valid signature, with the issue each one is reported as
+var AptInsecureOptions = map[string]string{
+	"APT::Get::AllowUnauthenticated":                RepoTrustAllowUnauthenticated,
+	"Acquire::AllowInsecureRepositories":            RepoTrustAllowInsecure,
+	"Acquire::AllowDowngradeToInsecureRepositories": RepoTrustAllowInsecure,
+}
+
+// AptFile is a file of the APT configuration or repository lists
+type AptFile struct {
+	Path string
+	Data []byte
+}
+
+// TrustedKey is a key file APT or apk trusts for every repository
+type TrustedKey struct {
+	Path  string
+	Owner string // package that installed it; empty when added by hand
+}
+
+// RepositoryKey is the signing key of a third-party repository and the
+// repositories it signs
+type RepositoryKey struct {
+	Name        string   `json:"name"`
+	URL         string   `json:"url"`
+	Fingerprint string   `json:"fingerprint"` // OpenPGP fingerprint; SHA-256 of the key file on Alpine
+	Repos       []string `json:"repos,omitempty"`
+}
+
+// repositoryKeyName keeps key names usable as file names
+var repositoryKeyName = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Validate checks the key's name, URL and fingerprint
+func (k RepositoryKey) Validate() error {
+	if !repositoryKeyName.MatchString(k.Name) {
+		return fmt.Errorf("invalid repository key name %q: use lower case letters, digits, '.', '_' and '-'", k.Name)
+	}
+	if !strings.HasPrefix(k.URL, "https://") {
+		return fmt.Errorf("repository key %s: url must be an https URL", k.Name)
+	}
+	if k.Fingerprint == "" {
+		return fmt.Errorf("repository key %s: a fingerprint is required to check the downloaded key", k.Name)
+	}
+	return nil
+}
+
+// NormalizeFingerprint removes the spaces and colons fingerprints are often
+// printed with and upper-cases the hex digits
+func NormalizeFingerprint(fingerprint string) string {
+	fingerprint = strings.NewReplacer(" ", "", ":", "").Replace(fingerprint)
+	return strings.ToUpper(fingerprint)
+}
+
+// AptKeyringPath is where a key is stored for signed-by; APT reads armored
+// keys from .asc files and binary keys from .gpg files
+func AptKeyringPath(name string, armored bool) string {
+	if armored {
+		return filepath.Join(AptKeyringsDir, name+".asc")
+	}
+	return filepath.Join(AptKeyringsDir, name+".gpg")
+}
+
+// ApkKeyPath is where apk finds a key; apk matches the name against the
+// signature of the index, so it keeps the name of the downloaded file
+func ApkKeyPath(url string) string {
+	return filepath.Join(ApkKeysDir, filepath.Base(url))
+}
+
+// RepositoryKeyResult is what installing a repository key wrote
+type RepositoryKeyResult struct {
+	Key         RepositoryKey
+	KeyringPath string
+	SourcesPath string // empty without repos
+	Replaced    string // one-line list removed in favor of the .sources file
+}
+
+// AptConfigOption is an option set in an APT configuration file
+type AptConfigOption struct {
+	Name  string // full name such as APT::Get::AllowUnauthenticated
+	Value string
+}
+
+// ParseAptConfig reads the options set in apt.conf syntax, both as
+// "APT::Get::AllowUnauthenticated "true";" and nested in braces. Comments
+// and directives such as #include are skipped, as are list items.
+func ParseAptConfig(data []byte) []AptConfigOption {
+	text := string(data)
+	var options []AptConfigOption
+	var scope, words []string
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '#' || strings.HasPrefix(text[i:], "//"):
+			end := strings.IndexByte(text[i:], '\n')
+			if end < 0 {
+				end = len(text) - i
+			}
+			i += end
+		case strings.HasPrefix(text[i:], "/*"):
+			end := strings.Index(text[i+2:], "*/")
+			if end < 0 {
+				end = len(text) - i - 4
+			}
+			i += end + 4
+		case c == '"':
+			end := strings.IndexByte(text[i+1:], '"')
+			if end < 0 {
+				end = len(text) - i - 1
+			}
+			words = append(words, text[i+1:i+1+end])
+			i += end + 2
+		case c == '{':
+			if len(words) > 0 {
+				scope = append(scope, words[0])
+			} else {
+				scope = append(scope, "")
+			}
+			words = nil
+			i++
+		case c == '}':
+			if len(scope) > 0 {
+				scope = scope[:len(scope)-1]
+			}
+			words = nil
+			i++
+		case c == ';':
+			if len(words) >= 2 {
+				name := strings.Join(append(append([]string{}, scope...), words[0]), "::")
+				options = append(options, AptConfigOption{Name: strings.Trim(name, ":"), Value: words[1]})
+			}
+			words = nil
+			i++
+		default:
+			end := strings.IndexAny(text[i:], " \t\r\n{};\"")
+			if end < 0 {
+				end = len(text) - i
+			}
+			words = append(words, text[i:i+end])
+			i += end
+		}
+	}
+	return options
+}
+
+// RepoTrustFinding is a repository APT or apk accepts without checking
+// who signed it
+type RepoTrustFinding struct {
+	File     string `json:"file"`
+	Issue    string `json:"issue"`
+	Severity string `json:"severity"`
+	Detail   string `json:"detail"`
+}
+
+// Violation converts the finding for inclusion in an audit report
+func (f RepoTrustFinding) Violation() PolicyViolation {
+	var remediation string
+	switch f.Issue {
+	case RepoTrustLegacyKeyring:
+		remediation = "List each repository key under repositoryKeys, run 'hardn sources keys', then remove the key with 'apt-key del'"
+	case RepoTrustGlobalKey:
+		remediation = "Move the key to /etc/apt/keyrings and name it with signed-by in the repositories it signs, or remove it if none do"
+		if strings.HasPrefix(f.File, ApkKeysDir) {
+			remediation = "Remove the key unless a repository in /etc/apk/repositories is signed with it"
+		}
+	case RepoTrustTrustedRepository:
+		remediation = "Remove trusted=yes and install the repository's key with 'hardn sources keys'"
+	default:
+		remediation = "Remove the option so APT refuses packages it cannot verify"
+	}
+
+	return PolicyViolation{
+		Rule:        "sources." + f.Issue,
+		Severity:    f.Severity,
+		Message:     fmt.Sprintf("%s: %s", f.File, f.Detail),
+		Remediation: remediation,
+	}
+}
@@ -0,0 +1,158 @@
+// pkg/domain/model/run.go
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// RunsDir holds a directory per hardening run with its manifest and the
+// previous contents of the files it changed
+const RunsDir = "/var/lib/hardn/runs"
+
+// RunIDLayout is the time layout of run IDs; a counter is appended when
+// two runs start in the same second
+const RunIDLayout = "20060102-150405"
+
+// Kinds of change recorded in a run manifest
+const (
+	RunChangeFile     = "file"     // a file written, renamed or removed
+	RunChangePackage  = "package"  // a package installed by the run
+	RunChangeService  = "service"  // a service started, stopped, enabled or disabled
+	RunChangeUser     = "user"     // a user account created by the run
+	RunChangeFirewall = "firewall" // ufw or firewalld rules changed by the run
+)
+
+// RunManifest records what a hardening run changed so it can be reverted
+type RunManifest struct {
+	ID          string      `json:"id"`
+	Description string      `json:"description"`       // what started the run, e.g. "hardn ssh set"
+	Version     string      `json:"version,omitempty"` // hardn version that made the run
+	Started     time.Time   `json:"started"`
+	Updated     time.Time   `json:"updated"` // time of the last recorded change
+	Changes     []RunChange `json:"changes"`
+}
+
+// RunChange is one change made by a run. Files are restored from their
+// saved copy; other changes are reverted by running their undo commands.
+type RunChange struct {
+	Kind    string `json:"kind"`
+	Summary string `json:"summary"`
+
+	// Files
+	Path     string `json:"path,omitempty"`
+	Existed  bool   `json:"existed,omitempty"`  // the file existed before the run
+	Mode     uint32 `json:"mode,omitempty"`     // permissions before the run
+	Backup   string `json:"backup,omitempty"`   // saved copy, relative to the run directory
+	Checksum string `json:"checksum,omitempty"` // sha256 of what the run last wrote; empty if it removed the file
+	External bool   `json:"external,omitempty"` // changed by a command, so restored without comparing checksums
+
+	// Packages, services, users and the firewall
+	Name string     `json:"name,omitempty"`
+	Undo [][]string `json:"undo,omitempty"`
+
+	Reverted *time.Time `json:"reverted,omitempty"`
+}
+
+// IsReverted reports whether the change has been rolled back
+func (c RunChange) IsReverted() bool {
+	return c.Reverted != nil
+}
+
+// Describe returns a one-line description of the change
+func (c RunChange) Describe() string {
+	subject := c.Name
+	if c.Kind == RunChangeFile {
+		subject = c.Path
+	}
+	return c.Kind + " " + subject + " " + c.Summary
+}
+
+// Hardening areas whose last applied run is shown in the menu
+const (
+	AreaSSH      = "ssh"
+	AreaFirewall = "firewall"
+	AreaDNS      = "dns"
+	AreaUsers    = "users"
+)
+
+// AreaState is the latest run with changes to a hardening area that have
+// not been rolled back
+type AreaState struct {
+	Area    string
+	RunID   string
+	Applied time.Time
+	Version string // empty for runs recorded before versions were
+}
+
+// areaPaths maps configuration files and directories to their area
+var areaPaths = []struct {
+	prefix string
+	area   string
+}{
+	{"/etc/ssh/", AreaSSH},
+	{"/etc/ufw/", AreaFirewall},
+	{"/etc/firewalld/", AreaFirewall},
+	{"/etc/default/ufw", AreaFirewall},
+	{"/etc/resolv.conf", AreaDNS},
+	{"/etc/resolvconf/", AreaDNS},
+	{"/etc/systemd/resolved.conf", AreaDNS},
+	{"/etc/sudoers", AreaUsers},
+}
+
+// areaNames maps the services and packages a run changes to their area
+var areaNames = map[string]string{
+	"ssh":              AreaSSH,
+	"sshd":             AreaSSH,
+	"ufw":              AreaFirewall,
+	"firewalld":        AreaFirewall,
+	"systemd-resolved": AreaDNS,
+	"resolvconf":       AreaDNS,
+}
+
+// Area returns the hardening area the change belongs to, or "" if it is
+// not part of one
+func (c RunChange) Area() string {
+	switch c.Kind {
+	case RunChangeFirewall:
+		return AreaFirewall
+	case RunChangeUser:
+		return AreaUsers
+	case RunChangeFile:
+		// Authorized keys are managed with the user
+		if strings.Contains(c.Path, "/.ssh/") {
+			return AreaUsers
+		}
+		for _, entry := range areaPaths {
+			if strings.HasPrefix(c.Path, entry.prefix) {
+				return entry.area
+			}
+		}
+		return ""
+	}
+	return areaNames[c.Name]
+}
+
+// PendingChanges returns the number of changes not yet rolled back
+func (m RunManifest) PendingChanges() int {
+	pending := 0
+	for _, change := range m.Changes {
+		if !change.IsReverted() {
+			pending++
+		}
+	}
+	return pending
+}
+
+// RunRollback reports the outcome of rolling back a run
+type RunRollback struct {
+	RunID    string
+	Reverted []RunChange
+	Failed   []RunChangeFailure
+}
+
+// RunChangeFailure is a change that could not be rolled back
+type RunChangeFailure struct {
+	Change RunChange
+	Err    error
+}
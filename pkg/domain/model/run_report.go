@@ -0,0 +1,139 @@
+// pkg/domain/model/run_report.go
+package model
+
+import (
+	"sync"
+	"time"
+)
+
+// RunReportsDir holds a JSON report of each hardening run, for auditors
+const RunReportsDir = "/var/log/hardn/reports"
+
+// MaxRunReports is how many reports are kept; older ones are removed
+const MaxRunReports = 200
+
+// Outcomes of a run and of its steps
+const (
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+	StepNotRun   = "not run" // planned, but an earlier step failed
+)
+
+// RunReport describes what a hardening run did: the steps it ran and how
+// long they took, what it changed and the errors it ran into. Reports of
+// runs that changed something share the run's ID, so 'hardn rollback'
+// takes the same ID.
+type RunReport struct {
+	ID          string    `json:"id"`
+	Description string    `json:"description"`
+	Version     string    `json:"version,omitempty"`
+	Hostname    string    `json:"hostname"`
+	OS          string    `json:"os"`
+	Started     time.Time `json:"started"`
+	Finished    time.Time `json:"finished"`
+	Status      string    `json:"status"`
+
+	// Steps are recorded for Run All; other operations run as one step
+	Steps   []RunReportStep   `json:"steps"`
+	Changes []RunReportChange `json:"changes"`
+	Errors  []string          `json:"errors,omitempty"`
+
+	// Before and After are the security posture around Run All
+	Before *PostureSnapshot `json:"before,omitempty"`
+	After  *PostureSnapshot `json:"after,omitempty"`
+}
+
+// RunReportStep is one step of a run
+type RunReportStep struct {
+	Name     string    `json:"name"`
+	Status   string    `json:"status"`
+	Started  time.Time `json:"started,omitempty"`
+	Duration float64   `json:"duration_seconds"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// RunReportChange is one change of a run with, for files, the diff from
+// the saved copy to what the run left
+type RunReportChange struct {
+	Kind     string `json:"kind"`
+	Subject  string `json:"subject"`
+	Summary  string `json:"summary"`
+	Diff     string `json:"diff,omitempty"`
+	Note     string `json:"note,omitempty"` // why the diff is left out or cut short
+	Reverted bool   `json:"reverted,omitempty"`
+}
+
+// Duration returns how long the run took
+func (r RunReport) Duration() time.Duration {
+	return r.Finished.Sub(r.Started)
+}
+
+// Failed reports whether the run or one of its steps failed
+func (r RunReport) Failed() bool {
+	return r.Status == RunFailed
+}
+
+// StepRecorder passes progress on to a ProgressReporter and records each
+// step with its outcome and duration for the run report
+type StepRecorder struct {
+	ProgressReporter
+
+	mu    sync.Mutex
+	now   func() time.Time
+	steps []RunReportStep
+}
+
+// NewStepRecorder creates a StepRecorder passing progress on to reporter
+func NewStepRecorder(reporter ProgressReporter) *StepRecorder {
+	return &StepRecorder{ProgressReporter: reporter, now: time.Now}
+}
+
+func (r *StepRecorder) Plan(steps []string) {
+	r.mu.Lock()
+	for _, name := range steps {
+		r.steps = append(r.steps, RunReportStep{Name: name, Status: StepNotRun})
+	}
+	r.mu.Unlock()
+	r.ProgressReporter.Plan(steps)
+}
+
+func (r *StepRecorder) StepStarted(step string) {
+	r.mu.Lock()
+	r.step(step).Started = r.now()
+	r.mu.Unlock()
+	r.ProgressReporter.StepStarted(step)
+}
+
+func (r *StepRecorder) StepCompleted(step string, err error) {
+	r.mu.Lock()
+	recorded := r.step(step)
+	if !recorded.Started.IsZero() {
+		recorded.Duration = r.now().Sub(recorded.Started).Seconds()
+	}
+	recorded.Status = RunSucceeded
+	if err != nil {
+		recorded.Status = RunFailed
+		recorded.Error = err.Error()
+	}
+	r.mu.Unlock()
+	r.ProgressReporter.StepCompleted(step, err)
+}
+
+// Steps returns the recorded steps in the order they were planned
+func (r *StepRecorder) Steps() []RunReportStep {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RunReportStep(nil), r.steps...)
+}
+
+// step returns the first planned step of that name not yet completed,
+// adding it when it was not planned
+func (r *StepRecorder) step(name string) *RunReportStep {
+	for i := range r.steps {
+		if r.steps[i].Name == name && r.steps[i].Status == StepNotRun {
+			return &r.steps[i]
+		}
+	}
+	r.steps = append(r.steps, RunReportStep{Name: name, Status: StepNotRun})
+	return &r.steps[len(r.steps)-1]
+}
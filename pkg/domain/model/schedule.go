@@ -0,0 +1,114 @@
+// pkg/domain/model/schedule.go
+package model
+
+// Scheduled runs use a systemd timer, or a root crontab entry on Alpine
+const (
+	ScheduleUnitName    = "hardn-schedule"
+	ScheduleServicePath = "/etc/systemd/system/hardn-schedule.service"
+	ScheduleTimerPath   = "/etc/systemd/system/hardn-schedule.timer"
+	ScheduleCrontabPath = "/etc/crontabs/root"
+	ScheduleLogPath     = "/var/log/hardn-schedule.log"
+)
+
+// Schedule backends
+const (
+	ScheduleBackendSystemd = "systemd"
+	ScheduleBackendCron    = "cron"
+)
+
+// Scheduled tasks
+const (
+	ScheduleTaskAudit       = "audit"
+	ScheduleTaskRunAll      = "run-all"
+	ScheduleTaskDisableRoot = "disable-root"
+	ScheduleTaskFirewall    = "firewall"
+	ScheduleTaskDNS         = "dns"
+	ScheduleTaskSSHKeys     = "ssh-keys"
+	ScheduleTaskSelfTest    = "selftest"
+)
+
+// ScheduleTasks lists the tasks a schedule can run, in run order; the
+// self-test and audit run last so they check the state the hardening
+// steps and the removal of expired SSH keys left
+var ScheduleTasks = []string{
+	ScheduleTaskRunAll,
+	ScheduleTaskDisableRoot,
+	ScheduleTaskFirewall,
+	ScheduleTaskDNS,
+	ScheduleTaskSSHKeys,
+	ScheduleTaskSelfTest,
+	ScheduleTaskAudit,
+}
+
+// Without configuration the schedule runs the audit daily
+const DefaultScheduleInterval = "daily"
+
+var DefaultScheduleTasks = []string{ScheduleTaskAudit}
+
+// ScheduleTaskFlags are the hardn flags that run each hardening task
+var ScheduleTaskFlags = map[string]string{
+	ScheduleTaskRunAll:      "--run-all",
+	ScheduleTaskDisableRoot: "--disable-root",
+	ScheduleTaskFirewall:    "--configure-ufw",
+	ScheduleTaskDNS:         "--configure-dns",
+}
+
+// ScheduleInterval is how often scheduled runs happen, as a systemd
+// OnCalendar expression and the equivalent cron expression
+type ScheduleInterval struct {
+	Name       string
+	OnCalendar string
+	Cron       string
+}
+
+// ScheduleIntervals are the supported intervals; runs other than hourly
+// start at 03:00
+var ScheduleIntervals = []ScheduleInterval{
+	{Name: "hourly", OnCalendar: "*-*-* *:00:00", Cron: "0 * * * *"},
+	{Name: "daily", OnCalendar: "*-*-* 03:00:00", Cron: "0 3 * * *"},
+	{Name: "weekly", OnCalendar: "Sun *-*-* 03:00:00", Cron: "0 3 * * 0"},
+	{Name: "monthly", OnCalendar: "*-*-01 03:00:00", Cron: "0 3 1 * *"},
+}
+
+// LookupScheduleInterval returns the named interval
+func LookupScheduleInterval(name string) (ScheduleInterval, bool) {
+	for _, interval := range ScheduleIntervals {
+		if interval.Name == name {
+			return interval, true
+		}
+	}
+	return ScheduleInterval{}, false
+}
+
+// Schedule is a set of hardn commands run on an interval
+type Schedule struct {
+	Interval ScheduleInterval
+	Tasks    []string
+	// Commands are the command lines to run, in order
+	Commands [][]string
+	// Summary sends the posture summary; nil sends none
+	Summary *SummarySchedule
+}
+
+// SummarySchedule sends the posture summary when a cron expression
+// matches, with a timer of its own on systemd hosts
+type SummarySchedule struct {
+	Cron       string
+	OnCalendar string
+	Command    []string
+}
+
+// ScheduleStatus reports the installed schedule
+type ScheduleStatus struct {
+	Installed bool
+	Backend   string
+	Interval  string
+	Commands  []string
+	// Active and NextRun are only reported for systemd timers
+	Active  bool
+	NextRun string
+	// Summary is the cron expression the posture summary is sent on, and
+	// SummaryCommand what sends it; both are empty when none is scheduled
+	Summary        string
+	SummaryCommand string
+}
@@ -0,0 +1,38 @@
+// pkg/domain/model/selftest.go
+package model
+
+import "time"
+
+// Self-test check states
+const (
+	SelfTestPassed  = "passed"
+	SelfTestFailed  = "failed"
+	SelfTestSkipped = "skipped"
+)
+
+// SelfTestCheck is the result of validating one hardn-managed artifact
+type SelfTestCheck struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+	Status string `json:"status"`
+	// Detail is the validator's complaint for failed checks and the reason
+	// for skipped ones
+	Detail string `json:"detail,omitempty"`
+}
+
+// SelfTestReport holds the results of a self-test run
+type SelfTestReport struct {
+	Time   time.Time       `json:"time"`
+	Checks []SelfTestCheck `json:"checks"`
+}
+
+// Failed returns the checks whose artifact no longer validates
+func (r *SelfTestReport) Failed() []SelfTestCheck {
+	var failed []SelfTestCheck
+	for _, check := range r.Checks {
+		if check.Status == SelfTestFailed {
+			failed = append(failed, check)
+		}
+	}
+	return failed
+}
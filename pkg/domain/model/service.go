@@ -0,0 +1,42 @@
+// pkg/domain/model/service.go
+package model
+
+// Init systems hardn manages services with
+const (
+	InitSystemd = "systemd"
+	InitOpenRC  = "openrc"
+	// The service command, where systemd is installed but not running,
+	// as on WSL without systemd
+	InitSysV = "sysv"
+)
+
+// OpenRCRunlevel is the runlevel services are enabled in on OpenRC
+const OpenRCRunlevel = "default"
+
+// ServiceStatus is the state of a system service
+type ServiceStatus struct {
+	Name    string `json:"name"`
+	Active  bool   `json:"active"`  // running now
+	Enabled bool   `json:"enabled"` // started at boot
+}
+
+// initSystemOverride replaces systemd when it is not running
+var initSystemOverride string
+
+// UseInitSystem makes InitSystemFor return init for the OS types that use
+// systemd; "" restores systemd
+func UseInitSystem(init string) {
+	initSystemOverride = init
+}
+
+// InitSystemFor returns the init system of an OS type: OpenRC on Alpine
+// Linux and systemd elsewhere, unless UseInitSystem replaced systemd
+func InitSystemFor(osType string) string {
+	if osType == "alpine" {
+		return InitOpenRC
+	}
+	if initSystemOverride != "" {
+		return initSystemOverride
+	}
+	return InitSystemd
+}
@@ -0,0 +1,40 @@
+// pkg/domain/model/share.go
+package model
+
+// Files describing network shares
+const (
+	NFSExportsPath = "/etc/exports"
+	SambaConfPath  = "/etc/samba/smb.conf"
+)
+
+// Finding severities
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+)
+
+// NFSExport is one path exported in /etc/exports
+type NFSExport struct {
+	Path    string
+	Clients []NFSClient
+}
+
+// NFSClient is a host pattern and the options it is exported with; an
+// empty or "*" host exports to everyone
+type NFSClient struct {
+	Host    string
+	Options []string
+}
+
+// SambaConfig is the parsed smb.conf; keys are lower case with single spaces
+type SambaConfig struct {
+	Global map[string]string
+	Shares []SambaShare
+}
+
+// SambaShare is one share section of smb.conf
+type SambaShare struct {
+	Name     string
+	Settings map[string]string
+}
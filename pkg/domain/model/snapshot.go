@@ -0,0 +1,38 @@
+// pkg/domain/model/snapshot.go
+package model
+
+import "time"
+
+// Snapshot backends for the root filesystem
+const (
+	SnapshotBackendLVMThin = "lvm-thin"
+	SnapshotBackendZFS     = "zfs"
+	SnapshotBackendBtrfs   = "btrfs"
+)
+
+// SnapshotNamePrefix marks snapshots created by hardn; the rest of the
+// name is the creation time in SnapshotTimeLayout
+const (
+	SnapshotNamePrefix = "hardn-"
+	SnapshotTimeLayout = "20060102-150405"
+)
+
+// SnapshotSupport describes whether the root filesystem can be snapshotted
+type SnapshotSupport struct {
+	Supported bool
+	Backend   string // lvm-thin, zfs or btrfs
+	Target    string // volume, dataset or subvolume holding /
+	Proxmox   bool   // host is a Proxmox VE node
+	Reason    string // why snapshots are unsupported, when they are
+	// RollbackNeedsReboot is true when a rollback only takes effect after a reboot
+	RollbackNeedsReboot bool
+}
+
+// Snapshot represents a root filesystem snapshot
+type Snapshot struct {
+	Name    string    // snapshot name (hardn-YYYYMMDD-HHMMSS)
+	Backend string    // backend the snapshot was taken with
+	Target  string    // volume, dataset or subvolume that was snapshotted
+	Path    string    // backend-specific identifier (vg/lv, dataset@snap, path)
+	Created time.Time // when the snapshot was taken
+}
@@ -1,6 +1,14 @@
 // pkg/domain/model/ssh_config.go
 package model
 
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+)
+
 // SSHConfig represents SSH server configuration settings
 type SSHConfig struct {
 	Port            int
@@ -10,8 +18,259 @@ type SSHConfig struct {
 	KeyPaths        []string
 	AuthMethods     []string
 	ConfigFilePath  string
+
+	// ManagedUser is added to AllowedUsers when the list is empty
+	ManagedUser string
+	// AllowAllUsers explicitly permits an empty or wildcard AllowedUsers list
+	AllowAllUsers bool
+
+	// AuthorizedKeysCommand fetches keys for directory users (e.g. from SSSD);
+	// empty leaves key lookup to AuthorizedKeysFile only
+	AuthorizedKeysCommand     string
+	AuthorizedKeysCommandUser string
+
+	// Banner is the file sshd shows before authentication; empty shows none
+	Banner string
+
+	// Profile names the hardening profile applied on top of these settings;
+	// empty writes no profile directives
+	Profile string
+}
+
+// ResolveAllowedUsers returns the AllowUsers list to write. An empty list is
+// populated with the managed user; empty or wildcard lists are refused unless
+// allowAll is set, since they leave SSH open to every account on the host.
+func ResolveAllowedUsers(allowedUsers []string, managedUser string, allowAll bool) ([]string, error) {
+	var users []string
+	for _, user := range allowedUsers {
+		user = strings.TrimSpace(user)
+		if user == "" {
+			continue
+		}
+		if user == "*" && !allowAll {
+			return nil, fmt.Errorf("refusing wildcard '*' in SSH allowed users; set sshAllowAllUsers to permit every user")
+		}
+		users = append(users, user)
+	}
+
+	if len(users) > 0 {
+		return users, nil
+	}
+
+	if managedUser != "" {
+		return []string{managedUser}, nil
+	}
+
+	if allowAll {
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("no SSH allowed users configured; add sshAllowedUsers, set a username, or set sshAllowAllUsers to permit every user")
 }
 
+// ResolveKeptAllowedUsers checks the AllowUsers list of a configuration read
+// back from sshd's files before it is written again. An empty list stays
+// empty, since the file had no AllowUsers to keep, and a wildcard already in
+// the file was permitted when it was written.
+func ResolveKeptAllowedUsers(allowedUsers []string) ([]string, error) {
+	if len(allowedUsers) == 0 {
+		return nil, nil
+	}
+	return ResolveAllowedUsers(allowedUsers, "", slices.Contains(allowedUsers, "*"))
+}
+
+// sshd configuration files; hardn writes a drop-in where sshd_config
+// includes sshd_config.d, and the main file on Alpine
+const (
+	SSHMainConfigPath   = "/etc/ssh/sshd_config"
+	SSHDropInConfigPath = "/etc/ssh/sshd_config.d/hardn.conf"
+)
+
+// SSHManagedConfigPath returns the sshd configuration file hardn writes
+func SSHManagedConfigPath(osType string) string {
+	if osType == "alpine" {
+		return SSHMainConfigPath
+	}
+	return SSHDropInConfigPath
+}
+
+// SSHDirectiveSource is a configuration line setting an sshd directive
+type SSHDirectiveSource struct {
+	Directive string // lowercase, as printed by sshd -T
+	Value     string
+	File      string
+	Line      int
+}
+
+// SSHDirectiveDiff compares the value hardn intends for a directive with
+// the one sshd runs with
+type SSHDirectiveDiff struct {
+	Directive string
+	Intended  string
+	Effective string
+	Matches   bool
+	// Sources are the lines setting the directive in the order sshd reads
+	// them; for most directives the first one wins
+	Sources []SSHDirectiveSource
+	// OverriddenBy is the line read before hardn's own that took effect
+	OverriddenBy *SSHDirectiveSource
+}
+
+// SSHEffectiveReport compares hardn's SSH policy with the configuration
+// reported by sshd -T
+type SSHEffectiveReport struct {
+	ManagedFile string
+	Directives  []SSHDirectiveDiff
+}
+
+// Mismatches returns the directives whose effective value differs from
+// the intended one
+func (c SSHEffectiveReport) Mismatches() []SSHDirectiveDiff {
+	var mismatches []SSHDirectiveDiff
+	for _, directive := range c.Directives {
+		if !directive.Matches {
+			mismatches = append(mismatches, directive)
+		}
+	}
+	return mismatches
+}
+
+// How run-all handles directives that other sshd configuration files set
+// differently from hardn's file
+const (
+	SSHConflictsWarn        = "warn"
+	SSHConflictsConsolidate = "consolidate"
+)
+
+// SSHDaemon is a running SSH server
+type SSHDaemon struct {
+	PID     int
+	Program string // sshd or dropbear
+	Command string
+	// ConfigFile is the configuration an sshd reads; empty for dropbear
+	ConfigFile string
+}
+
+// SSHDirectiveConflict is a directive the sshd configuration files set to
+// different values
+type SSHDirectiveConflict struct {
+	Directive string
+	// Effective is the value sshd -T reports; empty when sshd -T fails
+	Effective string
+	// Sources are the lines setting the directive in the order sshd reads them
+	Sources []SSHDirectiveSource
+	// Managed is set when hardn's file sets the directive, so the other
+	// lines can be disabled in its favor
+	Managed bool
+}
+
+// SSHConflictReport lists the SSH servers running and the directives the
+// sshd configuration files disagree on
+type SSHConflictReport struct {
+	ManagedFile string
+	Daemons     []SSHDaemon
+	Conflicts   []SSHDirectiveConflict
+}
+
+// SSH hardening profiles, from least to most restrictive
+const (
+	SSHProfileBaseline = "baseline"
+	SSHProfileStrict   = "strict"
+	SSHProfileParanoid = "paranoid"
+)
+
+// SSHProfileNames lists the hardening profiles in order of strictness
+var SSHProfileNames = []string{SSHProfileBaseline, SSHProfileStrict, SSHProfileParanoid}
+
+// SSHProfileSettings holds the sshd directives a hardening profile sets
+type SSHProfileSettings struct {
+	MaxAuthTries        int
+	LoginGraceTime      int // seconds
+	ClientAliveInterval int // seconds
+	ClientAliveCountMax int
+	X11Forwarding       bool
+	AllowTcpForwarding  string // yes, local or no
+	Ciphers             []string
+	MACs                []string
+	KexAlgorithms       []string
+}
+
+// Only algorithms available since OpenSSH 7.4 are listed, so every supported
+// release still starts with the strictest profile
+var sshProfiles = map[string]SSHProfileSettings{
+	SSHProfileBaseline: {
+		MaxAuthTries:        4,
+		LoginGraceTime:      60,
+		ClientAliveInterval: 300,
+		ClientAliveCountMax: 3,
+		X11Forwarding:       false,
+		AllowTcpForwarding:  "yes",
+		Ciphers: []string{
+			"chacha20-poly1305@openssh.com",
+			"aes256-gcm@openssh.com", "aes128-gcm@openssh.com",
+			"aes256-ctr", "aes192-ctr", "aes128-ctr",
+		},
+		MACs: []string{
+			"hmac-sha2-512-etm@openssh.com", "hmac-sha2-256-etm@openssh.com",
+			"umac-128-etm@openssh.com",
+			"hmac-sha2-512", "hmac-sha2-256",
+		},
+		KexAlgorithms: []string{
+			"curve25519-sha256", "curve25519-sha256@libssh.org",
+			"diffie-hellman-group16-sha512", "diffie-hellman-group18-sha512",
+			"diffie-hellman-group-exchange-sha256",
+		},
+	},
+	SSHProfileStrict: {
+		MaxAuthTries:        3,
+		LoginGraceTime:      30,
+		ClientAliveInterval: 300,
+		ClientAliveCountMax: 2,
+		X11Forwarding:       false,
+		AllowTcpForwarding:  "local",
+		Ciphers: []string{
+			"chacha20-poly1305@openssh.com",
+			"aes256-gcm@openssh.com", "aes128-gcm@openssh.com",
+		},
+		MACs: []string{
+			"hmac-sha2-512-etm@openssh.com", "hmac-sha2-256-etm@openssh.com",
+		},
+		KexAlgorithms: []string{
+			"curve25519-sha256", "curve25519-sha256@libssh.org",
+			"diffie-hellman-group16-sha512", "diffie-hellman-group18-sha512",
+		},
+	},
+	SSHProfileParanoid: {
+		MaxAuthTries:        2,
+		LoginGraceTime:      20,
+		ClientAliveInterval: 120,
+		ClientAliveCountMax: 1,
+		X11Forwarding:       false,
+		AllowTcpForwarding:  "no",
+		Ciphers: []string{
+			"chacha20-poly1305@openssh.com", "aes256-gcm@openssh.com",
+		},
+		MACs: []string{
+			"hmac-sha2-512-etm@openssh.com",
+		},
+		KexAlgorithms: []string{
+			"curve25519-sha256", "curve25519-sha256@libssh.org",
+		},
+	},
+}
+
+// LookupSSHProfile returns the settings for a named hardening profile
+func LookupSSHProfile(name string) (SSHProfileSettings, bool) {
+	settings, ok := sshProfiles[name]
+	return settings, ok
+}
+
+// SSSD helper that serves sshPublicKey attributes from the directory
+const (
+	SSSDAuthorizedKeysCommand        = "/usr/bin/sss_ssh_authorizedkeys"
+	DefaultAuthorizedKeysCommandUser = "nobody"
+)
+
 // SSHKey represents an SSH public key
 type SSHKey struct {
 	User      string
@@ -19,3 +278,27 @@ type SSHKey struct {
 	KeyType   string
 	Comment   string
 }
+
+// SSHKeyFingerprint returns the OpenSSH SHA256 fingerprint ("SHA256:...") of a
+// public key or authorized_keys line; leading key options are skipped
+func SSHKeyFingerprint(publicKey string) (string, error) {
+	fields := strings.Fields(publicKey)
+	for i := 0; i+1 < len(fields); i++ {
+		keyType := fields[i]
+		if !strings.HasPrefix(keyType, "ssh-") &&
+			!strings.HasPrefix(keyType, "ecdsa-") &&
+			!strings.HasPrefix(keyType, "sk-") {
+			continue
+		}
+
+		blob, err := base64.StdEncoding.DecodeString(fields[i+1])
+		if err != nil {
+			return "", fmt.Errorf("invalid SSH key data: %w", err)
+		}
+
+		sum := sha256.Sum256(blob)
+		return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:]), nil
+	}
+
+	return "", fmt.Errorf("unrecognized SSH public key format")
+}
@@ -0,0 +1,213 @@
+// pkg/domain/model/ssh_key_audit.go
+package model
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// MinRSAKeyBits is the smallest RSA modulus accepted by the SSH key audit
+const MinRSAKeyBits = 2048
+
+// SSH key audit issues
+const (
+	SSHKeyIssueWeakRSA       = "weak_rsa"
+	SSHKeyIssueDSA           = "dsa"
+	SSHKeyIssueDuplicate     = "duplicate"
+	SSHKeyIssueUnsafeOptions = "unsafe_options"
+	SSHKeyIssueExpired       = "expired"
+)
+
+// AuthorizedKey is a parsed authorized_keys entry
+type AuthorizedKey struct {
+	Username    string
+	KeyType     string
+	Fingerprint string
+	Bits        int
+	Options     []string
+	Comment     string
+}
+
+// SSHKeyFinding is a problem with one key in a user's authorized_keys
+type SSHKeyFinding struct {
+	Username    string `json:"username"`
+	Issue       string `json:"issue"`
+	Severity    string `json:"severity"`
+	KeyType     string `json:"key_type"`
+	Bits        int    `json:"bits,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+	Comment     string `json:"comment,omitempty"`
+	Detail      string `json:"detail"`
+}
+
+// Violation converts the finding for inclusion in an audit report
+func (f SSHKeyFinding) Violation() PolicyViolation {
+	key := f.Fingerprint
+	if f.Comment != "" {
+		key += " (" + f.Comment + ")"
+	}
+
+	remediation := "Remove the key and issue the user an ed25519 key"
+	switch f.Issue {
+	case SSHKeyIssueDuplicate:
+		remediation = "Give each account its own key and remove the shared one"
+	case SSHKeyIssueUnsafeOptions:
+		remediation = "Drop the listed options or prefix the key with restrict"
+	case SSHKeyIssueExpired:
+		remediation = "Remove the key with 'hardn ssh keys prune', or extend its expiry"
+	}
+
+	return PolicyViolation{
+		Rule:        "ssh.key." + f.Issue,
+		Severity:    f.Severity,
+		Message:     fmt.Sprintf("%s: key %s %s", f.Username, key, f.Detail),
+		Remediation: remediation,
+	}
+}
+
+// ParseAuthorizedKey parses an authorized_keys line, including any leading
+// options, and works out the key size from the public key blob
+func ParseAuthorizedKey(line string) (*AuthorizedKey, error) {
+	fields := splitAuthorizedKeyFields(strings.TrimSpace(line))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty authorized_keys entry")
+	}
+
+	key := &AuthorizedKey{}
+	if !isSSHKeyType(fields[0]) {
+		key.Options = splitKeyOptions(fields[0])
+		fields = fields[1:]
+	}
+	if len(fields) < 2 || !isSSHKeyType(fields[0]) {
+		return nil, fmt.Errorf("unrecognized SSH public key format")
+	}
+
+	key.KeyType = fields[0]
+	key.Comment = strings.Join(fields[2:], " ")
+
+	fingerprint, err := SSHKeyFingerprint(fields[0] + " " + fields[1])
+	if err != nil {
+		return nil, err
+	}
+	key.Fingerprint = fingerprint
+
+	blob, _ := base64.StdEncoding.DecodeString(fields[1])
+	key.Bits = sshKeyBits(key.KeyType, blob)
+
+	return key, nil
+}
+
+// isSSHKeyType reports whether a field names a public key algorithm
+func isSSHKeyType(field string) bool {
+	return strings.HasPrefix(field, "ssh-") ||
+		strings.HasPrefix(field, "ecdsa-") ||
+		strings.HasPrefix(field, "sk-")
+}
+
+// sshKeyBits returns the key size encoded in an SSH public key blob, or 0
+// when it cannot be read
+func sshKeyBits(keyType string, blob []byte) int {
+	switch {
+	case keyType == "ssh-rsa":
+		// string "ssh-rsa", mpint e, mpint n
+		parts := readSSHStrings(blob, 3)
+		if len(parts) == 3 {
+			return new(big.Int).SetBytes(parts[2]).BitLen()
+		}
+	case keyType == "ssh-dss":
+		// string "ssh-dss", mpint p, mpint q, mpint g, mpint y
+		parts := readSSHStrings(blob, 2)
+		if len(parts) == 2 {
+			return new(big.Int).SetBytes(parts[1]).BitLen()
+		}
+	case strings.Contains(keyType, "nistp256"):
+		return 256
+	case strings.Contains(keyType, "nistp384"):
+		return 384
+	case strings.Contains(keyType, "nistp521"):
+		return 521
+	case strings.Contains(keyType, "ed25519"):
+		return 256
+	}
+	return 0
+}
+
+// readSSHStrings reads up to count length-prefixed strings from a blob
+func readSSHStrings(blob []byte, count int) [][]byte {
+	var parts [][]byte
+	for len(parts) < count && len(blob) >= 4 {
+		length := binary.BigEndian.Uint32(blob)
+		if uint64(length) > uint64(len(blob)-4) {
+			break
+		}
+		parts = append(parts, blob[4:4+length])
+		blob = blob[4+length:]
+	}
+	return parts
+}
+
+// splitAuthorizedKeyFields splits a line on whitespace outside double quotes,
+// since option values such as command="..." may contain spaces
+func splitAuthorizedKeyFields(line string) []string {
+	var fields []string
+	var current strings.Builder
+	quoted := false
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && quoted && i+1 < len(line):
+			current.WriteByte(c)
+			i++
+			current.WriteByte(line[i])
+		case c == '"':
+			quoted = !quoted
+			current.WriteByte(c)
+		case (c == ' ' || c == '\t') && !quoted:
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+
+	return fields
+}
+
+// splitKeyOptions splits an options field on commas outside double quotes
+func splitKeyOptions(field string) []string {
+	var options []string
+	var current strings.Builder
+	quoted := false
+
+	for i := 0; i < len(field); i++ {
+		c := field[i]
+		switch {
+		case c == '\\' && quoted && i+1 < len(field):
+			current.WriteByte(c)
+			i++
+			current.WriteByte(field[i])
+		case c == '"':
+			quoted = !quoted
+			current.WriteByte(c)
+		case c == ',' && !quoted:
+			options = append(options, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if current.Len() > 0 {
+		options = append(options, current.String())
+	}
+
+	return options
+}
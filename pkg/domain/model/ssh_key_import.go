@@ -0,0 +1,30 @@
+// pkg/domain/model/ssh_key_import.go
+package model
+
+// Services that publish a user's SSH public keys
+const (
+	SSHKeyProviderGitHub = "github"
+	SSHKeyProviderGitLab = "gitlab"
+)
+
+// SSHKeyProviders lists the supported key providers
+var SSHKeyProviders = []string{SSHKeyProviderGitHub, SSHKeyProviderGitLab}
+
+// SSHKeyImport is the outcome of importing a provider account's public keys
+type SSHKeyImport struct {
+	Username string // local account the keys are authorized for
+	Provider string
+	Account  string // account name at the provider
+
+	// Added holds the keys installed, or that would be in a dry run
+	Added []AuthorizedKey
+	// Present holds keys the user already has
+	Present []AuthorizedKey
+	// Rejected holds keys refused as weak, with the reason in Detail
+	Rejected []SSHKeyFinding
+	// Invalid counts published lines that are not public keys
+	Invalid int
+
+	// Lines are the authorized_keys entries for Added
+	Lines []string
+}
@@ -0,0 +1,72 @@
+// pkg/domain/model/ssh_key_inventory.go
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SSHKeyExpiryPath holds the expiry dates keys are tagged with
+const SSHKeyExpiryPath = "/var/lib/hardn/ssh-key-expiry.json"
+
+// RecommendedRSAKeyBits is the RSA modulus below which the key inventory
+// flags a key as weak, even though the audit still accepts it
+const RecommendedRSAKeyBits = 3072
+
+// SSHKeyExpiry tags a user's authorized key with the time it stops being
+// trusted
+type SSHKeyExpiry struct {
+	Username    string    `json:"username"`
+	Fingerprint string    `json:"fingerprint"`
+	Expires     time.Time `json:"expires"`
+}
+
+// SSHKeyInventoryEntry is one key in a user's authorized_keys
+type SSHKeyInventoryEntry struct {
+	Username    string   `json:"username"`
+	KeyType     string   `json:"key_type"`
+	Bits        int      `json:"bits,omitempty"`
+	Fingerprint string   `json:"fingerprint"`
+	Comment     string   `json:"comment,omitempty"`
+	Options     []string `json:"options,omitempty"`
+	// Weak says why the key is weak, empty for a strong key
+	Weak    string     `json:"weak,omitempty"`
+	Expires *time.Time `json:"expires,omitempty"`
+	Expired bool       `json:"expired"`
+}
+
+// Finding reports an expired key as an SSH key audit finding
+func (e SSHKeyInventoryEntry) Finding() SSHKeyFinding {
+	return SSHKeyFinding{
+		Username:    e.Username,
+		Issue:       SSHKeyIssueExpired,
+		Severity:    SeverityHigh,
+		KeyType:     e.KeyType,
+		Bits:        e.Bits,
+		Fingerprint: e.Fingerprint,
+		Comment:     e.Comment,
+		Detail:      "expired on " + e.Expires.Format(time.DateOnly),
+	}
+}
+
+// ParseSSHKeyExpiry parses an expiry given as a date (2026-12-31), which
+// expires the key at the start of that day in the local time zone, or as a
+// number of days from now (90d)
+func ParseSSHKeyExpiry(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 1 {
+			return time.Time{}, fmt.Errorf("invalid expiry %q: use a date such as 2026-12-31 or a number of days such as 90d", value)
+		}
+		return now.AddDate(0, 0, n), nil
+	}
+
+	expires, err := time.ParseInLocation(time.DateOnly, value, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expiry %q: use a date such as 2026-12-31 or a number of days such as 90d", value)
+	}
+	return expires, nil
+}
@@ -0,0 +1,29 @@
+// pkg/domain/model/ssh_safety.go
+package model
+
+import "time"
+
+// DefaultSafetyRevertAfter is how long changes to sshd or the firewall
+// wait for confirmation before they are rolled back
+const DefaultSafetyRevertAfter = 5 * time.Minute
+
+// MinSafetyRevertAfter leaves time to open a second SSH session
+const MinSafetyRevertAfter = 30 * time.Second
+
+// SafetyMarkerDir holds a marker for each armed revert timer. The timer
+// rolls its run back once the marker is older than the revert delay;
+// touching the marker restarts the delay and removing it disarms the timer.
+const SafetyMarkerDir = "/run/hardn/revert"
+
+// SafetyUnitPrefix names the transient systemd units of revert timers
+const SafetyUnitPrefix = "hardn-revert-"
+
+// SSHSafety describes how a run that changes sshd or the firewall is
+// checked: SSH is probed when the run is done, and the changes are rolled
+// back unless they are confirmed within RevertAfter. The timer rolling
+// them back is armed before the first change, so it also fires when the
+// change cuts off the session running hardn.
+type SSHSafety struct {
+	Watchdog    SSHWatchdog
+	RevertAfter time.Duration
+}
@@ -0,0 +1,475 @@
+// pkg/domain/model/sudoers.go
+package model
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Files sudo reads its policy from
+const (
+	SudoersPath = "/etc/sudoers"
+	SudoersDir  = "/etc/sudoers.d"
+)
+
+// Sudoers audit issues
+const (
+	SudoersIssueNoPasswordAll  = "nopasswd_all"
+	SudoersIssueAllCommands    = "all_commands"
+	SudoersIssueNoAuthenticate = "no_authenticate"
+	SudoersIssueRootShell      = "root_shell"
+)
+
+// SudoersFile is one file of the sudo policy
+type SudoersFile struct {
+	Path string
+	Data []byte
+}
+
+// SudoRule is a user specification: who may run which commands
+type SudoRule struct {
+	File     string        `json:"file"`
+	Line     int           `json:"line"`
+	Users    []string      `json:"users"` // user names, %groups and aliases
+	Commands []SudoCommand `json:"commands"`
+	Text     string        `json:"text"`
+}
+
+// SudoCommand is one command of a rule with the tags in effect for it
+type SudoCommand struct {
+	RunAs      string `json:"run_as,omitempty"` // empty runs as root
+	Command    string `json:"command"`
+	NoPassword bool   `json:"no_password"`
+}
+
+// SudoDefaults is a Defaults line
+type SudoDefaults struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// SudoersPolicy is the parsed content of the sudoers files
+type SudoersPolicy struct {
+	Rules    []SudoRule
+	Defaults []SudoDefaults
+
+	// CmndAliases maps each Cmnd_Alias to its members
+	CmndAliases map[string][]string
+}
+
+// sudoTagPattern matches a command tag such as NOPASSWD:
+var sudoTagPattern = regexp.MustCompile(`^([A-Z_]+):\s*`)
+
+// sudoOptionPattern matches an option such as TIMEOUT=1h or CWD=*
+var sudoOptionPattern = regexp.MustCompile(`^[A-Z_]+=\S+\s+`)
+
+// Parse adds the rules, Defaults and command aliases of one file. Include
+// directives are left to the caller, which knows where the files are.
+// Lines sudo would reject are skipped.
+func (p *SudoersPolicy) Parse(path string, data []byte) {
+	if p.CmndAliases == nil {
+		p.CmndAliases = make(map[string][]string)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for i := 0; i < len(lines); i++ {
+		start := i + 1
+
+		// Join lines continued with a trailing backslash
+		text := stripSudoersComment(lines[i])
+		for strings.HasSuffix(strings.TrimRight(text, " \t"), "\\") && i+1 < len(lines) {
+			text = strings.TrimSuffix(strings.TrimRight(text, " \t"), "\\") + " "
+			i++
+			text += stripSudoersComment(lines[i])
+		}
+		text = strings.Join(strings.Fields(text), " ")
+		if text == "" || strings.HasPrefix(text, "@include") {
+			continue
+		}
+
+		// Defaults may be scoped as Defaults:user, Defaults@host and so on
+		word, _, _ := strings.Cut(strings.Fields(text)[0], ":")
+		if strings.HasPrefix(word, "Defaults") {
+			word = "Defaults"
+		}
+		switch word {
+		case "Defaults":
+			p.Defaults = append(p.Defaults, SudoDefaults{File: path, Line: start, Text: text})
+		case "Cmnd_Alias", "Cmd_Alias":
+			p.parseCmndAliases(strings.TrimSpace(strings.TrimPrefix(text, word)))
+		case "User_Alias", "Runas_Alias", "Host_Alias":
+		default:
+			if rule, ok := parseSudoRule(text); ok {
+				rule.File = path
+				rule.Line = start
+				p.Rules = append(p.Rules, rule)
+			}
+		}
+	}
+}
+
+// parseCmndAliases reads NAME = cmd, cmd : NAME2 = cmd
+func (p *SudoersPolicy) parseCmndAliases(text string) {
+	for _, definition := range strings.Split(text, ":") {
+		name, members, ok := strings.Cut(definition, "=")
+		if !ok {
+			continue
+		}
+		for _, member := range strings.Split(members, ",") {
+			if member = strings.TrimSpace(member); member != "" {
+				p.CmndAliases[strings.TrimSpace(name)] = append(p.CmndAliases[strings.TrimSpace(name)], member)
+			}
+		}
+	}
+}
+
+// GrantsAll reports whether a command allows every command, directly or
+// through a Cmnd_Alias
+func (p *SudoersPolicy) GrantsAll(command string) bool {
+	return p.grantsAll(command, make(map[string]bool))
+}
+
+func (p *SudoersPolicy) grantsAll(command string, seen map[string]bool) bool {
+	if command == "ALL" {
+		return true
+	}
+	members, ok := p.CmndAliases[command]
+	if !ok || seen[command] {
+		return false
+	}
+	seen[command] = true
+	for _, member := range members {
+		if p.grantsAll(member, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// RootShell returns the command, directly or through a Cmnd_Alias, that
+// lets whoever may run it start a root shell, or "" if there is none
+func (p *SudoersPolicy) RootShell(command string) string {
+	return p.rootShell(command, make(map[string]bool))
+}
+
+func (p *SudoersPolicy) rootShell(command string, seen map[string]bool) string {
+	members, ok := p.CmndAliases[command]
+	if !ok {
+		if sudoCommandRunsShell(command) {
+			return command
+		}
+		return ""
+	}
+	if seen[command] {
+		return ""
+	}
+	seen[command] = true
+	for _, member := range members {
+		if found := p.rootShell(member, seen); found != "" {
+			return found
+		}
+	}
+	return ""
+}
+
+// sudoCommandRunsShell reports whether a command can start a shell with
+// the arguments it allows. A command without arguments allows any; ""
+// allows none.
+func sudoCommandRunsShell(command string) bool {
+	fields := strings.Fields(command)
+	if len(fields) == 0 || fields[0] == "sudoedit" {
+		return false
+	}
+	args := fields[1:]
+	unrestricted := len(args) == 0
+	wildcard := slices.ContainsFunc(args, func(arg string) bool { return strings.Contains(arg, "*") })
+
+	switch path.Base(fields[0]) {
+	case "sh", "bash", "dash", "zsh", "ash", "su", "less", "more", "vi", "vim", "nano":
+		// A shell, or a pager or editor that runs one from its prompt
+		return true
+	case "journalctl":
+		// The pager runs a shell
+		return !slices.Contains(args, "--no-pager")
+	case "systemctl":
+		// The pager, and systemctl edit with its editor, run a shell; a
+		// wildcard is only safe after a fixed verb
+		for _, arg := range args {
+			if strings.Contains(arg, "*") {
+				return true
+			}
+			if !strings.HasPrefix(arg, "-") {
+				return false
+			}
+		}
+		return unrestricted
+	case "apt", "apt-get", "aptitude", "dpkg", "dnf", "yum", "rpm", "apk",
+		"find", "env", "awk", "perl", "python", "python3", "tee", "cp", "mv":
+		// Options, hooks and local packages run any code or write any file
+		return unrestricted || wildcard
+	}
+	return false
+}
+
+// parseSudoRule reads "users host = (runas) TAG: cmd, cmd". Only the first
+// host specification is read; sudoers files rarely use more.
+func parseSudoRule(text string) (SudoRule, bool) {
+	left, right, ok := strings.Cut(text, "=")
+	if !ok {
+		return SudoRule{}, false
+	}
+
+	// The host list is the last word before the =
+	fields := strings.Fields(left)
+	if len(fields) < 2 {
+		return SudoRule{}, false
+	}
+	rule := SudoRule{Text: text}
+	for _, user := range strings.Split(strings.Join(fields[:len(fields)-1], " "), ",") {
+		if user = strings.TrimSpace(user); user != "" {
+			rule.Users = append(rule.Users, user)
+		}
+	}
+
+	// The run-as list and tags carry over to the commands that follow them
+	runAs, noPassword := "", false
+	for _, item := range splitSudoCommands(right) {
+		item = strings.TrimSpace(item)
+		if strings.HasPrefix(item, "(") {
+			end := strings.Index(item, ")")
+			if end < 0 {
+				return SudoRule{}, false
+			}
+			runAs = strings.TrimSpace(item[1:end])
+			item = strings.TrimSpace(item[end+1:])
+		}
+		for {
+			if option := sudoOptionPattern.FindString(item); option != "" {
+				item = item[len(option):]
+				continue
+			}
+			tag := sudoTagPattern.FindStringSubmatch(item)
+			if tag == nil {
+				break
+			}
+			switch tag[1] {
+			case "NOPASSWD":
+				noPassword = true
+			case "PASSWD":
+				noPassword = false
+			}
+			item = item[len(tag[0]):]
+		}
+		item = strings.Join(strings.Fields(item), " ")
+		if item == "" {
+			continue
+		}
+		rule.Commands = append(rule.Commands, SudoCommand{RunAs: runAs, Command: item, NoPassword: noPassword})
+	}
+
+	return rule, len(rule.Commands) > 0
+}
+
+// splitSudoCommands splits a command list on commas that are not escaped
+func splitSudoCommands(text string) []string {
+	var items []string
+	var current strings.Builder
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '\\' && i+1 < len(text):
+			current.WriteByte(text[i])
+			i++
+			current.WriteByte(text[i])
+		case text[i] == ',':
+			items = append(items, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(text[i])
+		}
+	}
+	return append(items, current.String())
+}
+
+// stripSudoersComment drops a comment from a line. A # followed by a
+// digit is a uid, such as #1000, and is kept.
+func stripSudoersComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] != '#' {
+			continue
+		}
+		if i+1 < len(line) && line[i+1] >= '0' && line[i+1] <= '9' {
+			continue
+		}
+		return line[:i]
+	}
+	return line
+}
+
+// SudoIncludes returns the files and directories named by the @include,
+// @includedir, #include and #includedir directives of a sudoers file
+func SudoIncludes(data []byte) (files []string, dirs []string) {
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "@include", "#include":
+			files = append(files, fields[1])
+		case "@includedir", "#includedir":
+			dirs = append(dirs, fields[1])
+		}
+	}
+	return files, dirs
+}
+
+// SudoersFinding is a sudoers rule that grants more than it should
+type SudoersFinding struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Users    []string `json:"users"`
+	Issue    string   `json:"issue"`
+	Severity string   `json:"severity"`
+	Text     string   `json:"text"`
+	Detail   string   `json:"detail"`
+}
+
+// Violation converts the finding for inclusion in an audit report
+func (f SudoersFinding) Violation() PolicyViolation {
+	remediation := "Apply a command-restricted template with 'hardn user sudo-template'"
+	if f.Issue == SudoersIssueNoAuthenticate {
+		remediation = "Remove !authenticate so sudo asks for the user's password"
+	}
+
+	return PolicyViolation{
+		Rule:        "sudoers." + f.Issue,
+		Severity:    f.Severity,
+		Message:     fmt.Sprintf("%s:%d: %s (%s)", f.File, f.Line, f.Detail, f.Text),
+		Remediation: remediation,
+	}
+}
+
+// SudoTemplate is a set of commands a user may run with sudo instead of
+// every command
+type SudoTemplate struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Commands    []string `json:"commands"` // candidates; those whose program is installed are granted
+}
+
+// Programs returns the programs the template's commands run, once each
+func (t SudoTemplate) Programs() []string {
+	var programs []string
+	for _, command := range t.Commands {
+		if program := SudoCommandProgram(command); !slices.Contains(programs, program) {
+			programs = append(programs, program)
+		}
+	}
+	return programs
+}
+
+// CommandsFor returns the template's commands whose program is installed
+func (t SudoTemplate) CommandsFor(installed []string) []string {
+	var commands []string
+	for _, command := range t.Commands {
+		if slices.Contains(installed, SudoCommandProgram(command)) {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+// SudoCommandProgram returns the program a sudoers command runs
+func SudoCommandProgram(command string) string {
+	program, _, _ := strings.Cut(command, " ")
+	return program
+}
+
+// SudoNoExec reports whether a program a template grants runs with sudo's
+// noexec, so a pager it starts anyway cannot run a shell
+func SudoNoExec(program string) bool {
+	switch path.Base(program) {
+	case "systemctl", "journalctl":
+		return true
+	}
+	return false
+}
+
+// sudoCommands lists a command, with each set of arguments, under each of
+// its candidate paths
+func sudoCommands(paths []string, args ...string) []string {
+	var commands []string
+	for _, program := range paths {
+		for _, arg := range args {
+			commands = append(commands, program+" "+arg)
+		}
+	}
+	return commands
+}
+
+// Service commands keep the pager off: as root, less and an editor run a
+// shell. Package commands take no arguments, since options such as
+// apt-get -o APT::Update::Pre-Invoke::= and local packages run any code.
+var (
+	sudoServiceCommands = slices.Concat(
+		sudoCommands([]string{"/usr/bin/systemctl", "/bin/systemctl"},
+			"--no-pager status *", "--no-pager start *", "--no-pager stop *",
+			"--no-pager restart *", "--no-pager reload *"),
+		sudoCommands([]string{"/usr/bin/journalctl", "/bin/journalctl"}, "--no-pager *"),
+		sudoCommands([]string{"/sbin/rc-service"}, "* status", "* start", "* stop", "* restart"),
+		[]string{"/sbin/rc-status"},
+	)
+	sudoPackageCommands = slices.Concat(
+		sudoCommands([]string{"/usr/bin/apt-get"}, "update", "upgrade"),
+		sudoCommands([]string{"/usr/bin/dnf", "/usr/bin/yum"}, "check-update", "upgrade"),
+		sudoCommands([]string{"/sbin/apk"}, "update", "upgrade"),
+	)
+)
+
+// SudoTemplates are the command-restricted templates, in the order they
+// are offered
+var SudoTemplates = []SudoTemplate{
+	{
+		Name:        "services",
+		Description: "Manage services and read their logs",
+		Commands:    sudoServiceCommands,
+	},
+	{
+		Name:        "packages",
+		Description: "Update installed packages",
+		Commands:    sudoPackageCommands,
+	},
+	{
+		Name:        "operator",
+		Description: "Manage services and update packages",
+		Commands:    slices.Concat(sudoServiceCommands, sudoPackageCommands),
+	},
+}
+
+// FindSudoTemplate returns the template with the given name
+func FindSudoTemplate(name string) (SudoTemplate, bool) {
+	for _, template := range SudoTemplates {
+		if template.Name == name {
+			return template, true
+		}
+	}
+	return SudoTemplate{}, false
+}
+
+// SudoTemplatePlan is what applying a template to a user changes
+type SudoTemplatePlan struct {
+	Username   string
+	Template   string
+	Commands   []string // installed commands the user is granted
+	NoPassword bool
+	Content    string   // the user's new sudoers drop-in
+	LeaveGroup []string // groups granting every command the user is removed from
+
+	// OtherGrants are rules in other files that still grant the user every
+	// command; the template does not change them
+	OtherGrants []string
+}
@@ -7,4 +7,18 @@ type OSInfo struct {
 	Version   string // version number
 	Codename  string // release name
 	IsProxmox bool   // whether this is a Proxmox installation
+	Arch      string // GOARCH name of the machine, e.g. amd64, arm, riscv64
+}
+
+// RHELFamily are the distributions managed with dnf, firewalld and SELinux
+var RHELFamily = []string{"rocky", "almalinux", "fedora", "rhel"}
+
+// IsRHELFamily reports whether an OS type is a RHEL-family distribution
+func IsRHELFamily(osType string) bool {
+	for _, family := range RHELFamily {
+		if osType == family {
+			return true
+		}
+	}
+	return false
 }
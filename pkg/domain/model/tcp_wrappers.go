@@ -0,0 +1,54 @@
+// pkg/domain/model/tcp_wrappers.go
+package model
+
+import "strings"
+
+// HostsAccessRule is one rule of /etc/hosts.allow or /etc/hosts.deny, the
+// access control files of TCP wrappers (libwrap)
+type HostsAccessRule struct {
+	File    string   `json:"file"`
+	Line    int      `json:"line"`
+	Daemons []string `json:"daemons"`
+	Clients []string `json:"clients"`
+	Options string   `json:"options,omitempty"`
+}
+
+// String formats the rule as it is written in the file
+func (r HostsAccessRule) String() string {
+	rule := strings.Join(r.Daemons, ", ") + ": " + strings.Join(r.Clients, ", ")
+	if r.Options != "" {
+		rule += ": " + r.Options
+	}
+	return rule
+}
+
+// HostsAccessIssue explains why a rule is misleading or can not be migrated
+type HostsAccessIssue struct {
+	Rule   HostsAccessRule `json:"rule"`
+	Reason string          `json:"reason"`
+}
+
+// TCPWrappersReport compares the TCP wrappers rules with the firewall and
+// plans the firewall rules that express the same intent
+type TCPWrappersReport struct {
+	Allow []HostsAccessRule `json:"allow"`
+	Deny  []HostsAccessRule `json:"deny"`
+
+	// Conflicts are rules that do not do what they say, or that the
+	// firewall contradicts
+	Conflicts []HostsAccessIssue `json:"conflicts"`
+
+	// Add holds the firewall rules to add, and Close the rules allowing a
+	// port from anywhere that the TCP wrappers rules narrow down
+	Add   []FirewallRule `json:"add"`
+	Close []FirewallRule `json:"close"`
+
+	// Skipped are rules without a firewall equivalent, such as rules for
+	// host names or with EXCEPT lists
+	Skipped []HostsAccessIssue `json:"skipped"`
+}
+
+// InUse reports whether either file has rules
+func (r *TCPWrappersReport) InUse() bool {
+	return len(r.Allow)+len(r.Deny) > 0
+}
@@ -0,0 +1,106 @@
+// pkg/domain/model/time_sync.go
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// Time synchronization daemons hardn configures
+const (
+	// TimeSyncChrony is chrony, the only one of the three speaking NTS
+	TimeSyncChrony = "chrony"
+	// TimeSyncTimesyncd is systemd-timesyncd, an SNTP client
+	TimeSyncTimesyncd = "timesyncd"
+	// TimeSyncNTPD is busybox ntpd on Alpine Linux; on other distributions
+	// it names the reference ntpd or NTPsec found running
+	TimeSyncNTPD = "ntpd"
+)
+
+// TimeSyncDaemons are the daemons timeSync.daemon accepts
+var TimeSyncDaemons = []string{TimeSyncChrony, TimeSyncTimesyncd, TimeSyncNTPD}
+
+// Configuration files of the time synchronization daemons
+const (
+	// ChronyConfigPath is chrony's configuration on Debian, Ubuntu and Alpine
+	ChronyConfigPath = "/etc/chrony/chrony.conf"
+	// ChronyRHELConfigPath is chrony's configuration on the RHEL family
+	ChronyRHELConfigPath = "/etc/chrony.conf"
+	// TimesyncdDropInPath overrides the servers of systemd-timesyncd
+	TimesyncdDropInPath = "/etc/systemd/timesyncd.conf.d/hardn.conf"
+	// BusyboxNTPDConfPath holds the options OpenRC starts busybox ntpd with
+	BusyboxNTPDConfPath = "/etc/conf.d/ntpd"
+)
+
+// DefaultTimeServers are public servers that speak NTS (RFC 8915), so
+// chrony can authenticate the time it receives
+var DefaultTimeServers = []string{
+	"time.cloudflare.com",
+	"nts.netnod.se",
+	"ptbtime1.ptb.de",
+}
+
+// MaxClockOffset is the offset from the time source, in seconds, beyond
+// which the clock counts as drifting
+const MaxClockOffset = 0.5
+
+// TimeSyncPolicy is the time synchronization hardn sets up
+type TimeSyncPolicy struct {
+	// Daemon is chrony, timesyncd or ntpd; empty picks chrony where NTS
+	// is asked for or on the RHEL family, busybox ntpd on Alpine and
+	// systemd-timesyncd elsewhere
+	Daemon string
+
+	// Servers are the NTP servers synchronized with
+	Servers []string
+
+	// NTS authenticates the servers with Network Time Security; only
+	// chrony supports it
+	NTS bool
+}
+
+// TimeSyncStatus reports the time synchronization daemon and the clock
+type TimeSyncStatus struct {
+	// Daemon is the daemon running, or empty when none is
+	Daemon string `json:"daemon"`
+	// Synchronized reports whether the daemon or kernel considers the
+	// clock synchronized
+	Synchronized bool `json:"synchronized"`
+	// Source is the server the clock follows, when the daemon tells
+	Source string `json:"source,omitempty"`
+	// Stratum is the distance of the clock from a reference clock
+	Stratum int `json:"stratum,omitempty"`
+	// OffsetSeconds is how far the clock is ahead of the source (negative
+	// when behind); zero when the daemon does not report it
+	OffsetSeconds float64 `json:"offset_seconds"`
+	// Servers are the configured servers
+	Servers []string `json:"servers,omitempty"`
+	// NTS reports whether every configured server is authenticated with NTS
+	NTS bool `json:"nts"`
+	// Managed reports whether hardn wrote the daemon's server configuration
+	Managed bool `json:"managed"`
+}
+
+// Drifting reports whether the clock is further from its source than
+// MaxClockOffset
+func (s TimeSyncStatus) Drifting() bool {
+	return math.Abs(s.OffsetSeconds) > MaxClockOffset
+}
+
+// Compliant reports whether a daemon keeps the clock synchronized and
+// close to its source
+func (s TimeSyncStatus) Compliant() bool {
+	return s.Daemon != "" && s.Synchronized && !s.Drifting()
+}
+
+// Offset formats the offset from the source, e.g. "+1.234ms"
+func (s TimeSyncStatus) Offset() string {
+	if s.OffsetSeconds == 0 {
+		return "not reported"
+	}
+	offset := time.Duration(s.OffsetSeconds * float64(time.Second)).Round(time.Microsecond)
+	if offset >= 0 {
+		return "+" + offset.String()
+	}
+	return offset.String()
+}
@@ -0,0 +1,47 @@
+// pkg/domain/model/updates.go
+package model
+
+// Debian and Ubuntu upgrade with unattended-upgrades, run by apt's daily
+// timers when 20auto-upgrades enables it
+const (
+	AptUnattendedUpgradesPath = "/etc/apt/apt.conf.d/50unattended-upgrades"
+	AptAutoUpgradesPath       = "/etc/apt/apt.conf.d/20auto-upgrades"
+)
+
+// DefaultRebootTime is when hosts reboot after an upgrade that needs it
+const DefaultRebootTime = "02:00"
+
+// DefaultUpdateOrigins are the unattended-upgrades origin patterns used
+// when none are configured: security updates only
+var DefaultUpdateOrigins = map[string][]string{
+	"debian": {
+		"origin=Debian,codename=${distro_codename},label=Debian-Security",
+		"origin=Debian,codename=${distro_codename}-security,label=Debian-Security",
+	},
+	"ubuntu": {
+		"origin=Ubuntu,archive=${distro_codename}-security,label=Ubuntu",
+	},
+}
+
+// UpdatesPolicy is what automatic upgrades install and what happens after
+type UpdatesPolicy struct {
+	// Origins are unattended-upgrades Origins-Pattern entries; Alpine
+	// upgrades from its configured repositories
+	Origins []string
+	// Blacklist names packages that are never upgraded automatically
+	Blacklist []string
+	// Reboot restarts the host when an upgrade needs it, at RebootTime on
+	// Debian and Ubuntu and right after the upgrade on Alpine
+	Reboot     bool
+	RebootTime string
+	// Mail is the address upgrade reports are sent to; empty sends none
+	Mail            string
+	MailOnlyOnError bool
+}
+
+// UpdatesStatus reports the automatic upgrades on the host
+type UpdatesStatus struct {
+	Enabled bool
+	Managed bool     // the configuration was written by hardn
+	Files   []string // configuration files that exist
+}
@@ -1,16 +1,98 @@
 // pkg/domain/model/user.go
 package model
 
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
 // User represents a system user
 type User struct {
-	Username       string
-	HasSudo        bool
-	SshKeys        []string
-	SudoNoPassword bool
+	Username       string   `json:"username"`
+	HasSudo        bool     `json:"has_sudo"`
+	SshKeys        []string `json:"ssh_keys,omitempty"`
+	SudoNoPassword bool     `json:"sudo_no_password,omitempty"`
 	// Extended information
-	UID           string
-	GID           string
-	HomeDirectory string
-	LastLogin     string
-	LastLoginIP   string // Added field for last login IP address
+	UID           string `json:"uid,omitempty"`
+	GID           string `json:"gid,omitempty"`
+	HomeDirectory string `json:"home_directory,omitempty"`
+	LastLogin     string `json:"last_login,omitempty"`
+	LastLoginIP   string `json:"last_login_ip,omitempty"` // Added field for last login IP address
+	// Local reports whether the account is defined in /etc/passwd
+	// rather than a directory service such as LDAP or SSSD
+	Local bool `json:"local"`
+}
+
+// UserListOptions controls how non-system users are enumerated
+type UserListOptions struct {
+	// LocalOnly restricts enumeration to /etc/passwd and skips NSS sources
+	LocalOnly bool
+	// Offset is the number of matching users to skip
+	Offset int
+	// Limit caps the number of users returned; 0 returns all
+	Limit int
+}
+
+// UserPage holds one page of enumerated non-system users
+type UserPage struct {
+	Users  []User
+	Offset int
+	// Total is the number of matching users before paging
+	Total int
+}
+
+// HasMore reports whether users remain beyond this page
+func (p UserPage) HasMore() bool {
+	return p.Offset+len(p.Users) < p.Total
+}
+
+// DirectoryAuthStatus describes whether accounts are resolved from a
+// directory service (LDAP, SSSD, winbind) through NSS
+type DirectoryAuthStatus struct {
+	Enabled bool
+	// Sources lists the directory NSS sources configured for passwd
+	Sources []string
+	// SSSDActive reports whether the sssd service is running
+	SSSDActive bool
+}
+
+// usernamePattern matches lowercase letters, numbers, underscores and
+// hyphens, starting with a letter
+var usernamePattern = regexp.MustCompile("^[a-z][a-z0-9_-]*$")
+
+// reservedUsernames are system accounts that must not be created or taken
+// over as login users
+var reservedUsernames = []string{
+	"root", "admin", "administrator", "sudo", "system",
+	"daemon", "bin", "sys", "sync", "games", "man", "lp", "mail",
+	"news", "uucp", "proxy", "www-data", "backup", "list", "irc",
+	"gnats", "nobody", "systemd-network", "systemd-resolve", "messagebus",
+	"sshd", "postfix", "ntp", "_apt", "tss", "uuidd", "tcpdump",
+	"landscape", "pollinate", "syslog", "usbmux", "pulse",
+}
+
+// ValidateUsername checks that username is a valid Linux login name that is
+// not reserved for a system account
+func ValidateUsername(username string) error {
+	if username == "" {
+		return fmt.Errorf("username cannot be empty")
+	}
+	if strings.Contains(username, " ") {
+		return fmt.Errorf("username cannot contain spaces")
+	}
+	if len(username) > 32 {
+		return fmt.Errorf("username must be between 1 and 32 characters")
+	}
+
+	if !usernamePattern.MatchString(username) {
+		return fmt.Errorf("username must start with a lowercase letter and contain only lowercase letters, numbers, hyphens, and underscores")
+	}
+
+	for _, reserved := range reservedUsernames {
+		if username == reserved {
+			return fmt.Errorf("'%s' is a reserved system username and cannot be used", username)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,67 @@
+// pkg/domain/model/user_import.go
+package model
+
+import "fmt"
+
+// UserImportColumns are the columns of a user import file, a CSV file with
+// a header row. Only username is required; keys holds public keys
+// separated by semicolons.
+var UserImportColumns = []string{"username", "sudo", "nopasswd", "keys"}
+
+// UserImportEntry is one user of a batch import
+type UserImportEntry struct {
+	Line int // line of the entry in the import file
+	User User
+	// Exists reports whether the account is already on the host; its sudo
+	// settings and keys are still applied
+	Exists bool
+	// Problems are what the pre-flight found wrong with the entry
+	Problems []string
+}
+
+// Describe returns a one-line description of what the import does for the
+// entry
+func (e UserImportEntry) Describe() string {
+	action := "create"
+	if e.Exists {
+		action = "update"
+	}
+
+	sudo := "without sudo"
+	if e.User.HasSudo && e.User.SudoNoPassword {
+		sudo = "with sudo (no password)"
+	} else if e.User.HasSudo {
+		sudo = "with sudo (password required)"
+	}
+
+	return fmt.Sprintf("%s %s %s and %d SSH key(s)", action, e.User.Username, sudo, len(e.User.SshKeys))
+}
+
+// UserImportPlan is the pre-flight of a batch import: every user of the
+// file with the problems found. Nothing is created unless the plan is
+// valid.
+type UserImportPlan struct {
+	Entries []UserImportEntry
+}
+
+// Valid reports whether no entry has a problem
+func (p *UserImportPlan) Valid() bool {
+	return p.ProblemCount() == 0
+}
+
+// ProblemCount returns the number of problems found in all entries
+func (p *UserImportPlan) ProblemCount() int {
+	count := 0
+	for _, entry := range p.Entries {
+		count += len(entry.Problems)
+	}
+	return count
+}
+
+// UserImportResult is the outcome of importing one user
+type UserImportResult struct {
+	Username string
+	// Created is false when an existing account was updated
+	Created bool
+	Err     error
+}
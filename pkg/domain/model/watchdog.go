@@ -0,0 +1,59 @@
+// pkg/domain/model/watchdog.go
+package model
+
+import "time"
+
+// DefaultWatchdogTimeout is how long the watchdog waits for SSH to answer
+// after a change before rolling it back
+const DefaultWatchdogTimeout = 60 * time.Second
+
+// WatchdogInterval is the pause between probes
+const WatchdogInterval = 5 * time.Second
+
+// SSHWatchdog describes how to verify that SSH is still reachable after an
+// unattended run changed sshd or the firewall
+type SSHWatchdog struct {
+	Port    int
+	Address string        // address probed; the loopback address unless Helper is set
+	Helper  string        // ssh destination probing from outside, e.g. probe@bastion
+	Timeout time.Duration // how long SSH may take to answer
+}
+
+// Probe describes where the watchdog probes from, for messages
+func (w SSHWatchdog) Probe() string {
+	if w.Helper != "" {
+		return "via " + w.Helper
+	}
+	return "locally"
+}
+
+// Local reports whether the watchdog probes from this host. Such a probe
+// goes over the loopback interface, which firewalls let through, so it
+// cannot tell that a firewall change locked SSH out.
+func (w SSHWatchdog) Local() bool {
+	return w.Helper == ""
+}
+
+// WatchdogResult reports whether SSH answered, and what was rolled back
+// when it did not
+type WatchdogResult struct {
+	Reachable bool
+	Attempts  int
+	LastError error
+	Rollback  *RunRollback // nil unless the run was rolled back
+}
+
+// Touches reports whether the run has pending changes to any of the areas
+func (m RunManifest) Touches(areas ...string) bool {
+	for _, change := range m.Changes {
+		if change.IsReverted() {
+			continue
+		}
+		for _, area := range areas {
+			if change.Area() == area {
+				return true
+			}
+		}
+	}
+	return false
+}
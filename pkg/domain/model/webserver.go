@@ -0,0 +1,29 @@
+// pkg/domain/model/webserver.go
+package model
+
+// Web servers hardn can apply a TLS baseline to
+const (
+	WebServerNginx  = "nginx"
+	WebServerApache = "apache"
+	WebServerCaddy  = "caddy"
+)
+
+// WebServerTLSMarker identifies TLS snippets written by hardn
+const WebServerTLSMarker = "# Managed by hardn: TLS baseline"
+
+// TLSBaselineCiphers is the Mozilla "intermediate" TLS 1.2 cipher list;
+// TLS 1.3 suites are not configurable and are always enabled
+const TLSBaselineCiphers = "ECDHE-ECDSA-AES128-GCM-SHA256:ECDHE-RSA-AES128-GCM-SHA256:" +
+	"ECDHE-ECDSA-AES256-GCM-SHA384:ECDHE-RSA-AES256-GCM-SHA384:" +
+	"ECDHE-ECDSA-CHACHA20-POLY1305:ECDHE-RSA-CHACHA20-POLY1305:" +
+	"DHE-RSA-AES128-GCM-SHA256:DHE-RSA-AES256-GCM-SHA384:DHE-RSA-CHACHA20-POLY1305"
+
+// TLSBaselineHSTSMaxAge is the Strict-Transport-Security max-age (two years)
+const TLSBaselineHSTSMaxAge = 63072000
+
+// WebServer describes an installed web server and its TLS baseline
+type WebServer struct {
+	Name            string `json:"name"`
+	SnippetPath     string `json:"snippet_path"`
+	BaselineApplied bool   `json:"baseline_applied"`
+}
@@ -0,0 +1,57 @@
+// pkg/domain/service/ansible_facts_service.go
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// AnsibleFactsService defines operations for publishing hardn's assessment
+// as Ansible local facts
+type AnsibleFactsService interface {
+	// InstallFactsScript installs a facts script running the given hardn
+	// binary, which must be an absolute path
+	InstallFactsScript(binary string) error
+
+	// RemoveFactsScript removes hardn's facts script
+	RemoveFactsScript() error
+
+	// FactsScriptInstalled reports whether hardn's facts script is installed
+	FactsScriptInstalled() bool
+}
+
+// AnsibleFactsServiceImpl implements AnsibleFactsService
+type AnsibleFactsServiceImpl struct {
+	repository AnsibleFactsRepository
+}
+
+// NewAnsibleFactsServiceImpl creates a new AnsibleFactsServiceImpl
+func NewAnsibleFactsServiceImpl(repository AnsibleFactsRepository) *AnsibleFactsServiceImpl {
+	return &AnsibleFactsServiceImpl{
+		repository: repository,
+	}
+}
+
+// AnsibleFactsRepository defines the repository operations needed by AnsibleFactsService
+type AnsibleFactsRepository interface {
+	InstallFactsScript(binary string) error
+	RemoveFactsScript() error
+	FactsScriptInstalled() bool
+}
+
+func (s *AnsibleFactsServiceImpl) InstallFactsScript(binary string) error {
+	// The path goes into a shell script unquoted
+	if !filepath.IsAbs(binary) || strings.ContainsAny(binary, " \t\n'\"\\$`;&|<>()*?[]#~") {
+		return fmt.Errorf("hardn binary path %q must be absolute and free of shell characters", binary)
+	}
+	return s.repository.InstallFactsScript(binary)
+}
+
+func (s *AnsibleFactsServiceImpl) RemoveFactsScript() error {
+	return s.repository.RemoveFactsScript()
+}
+
+func (s *AnsibleFactsServiceImpl) FactsScriptInstalled() bool {
+	return s.repository.FactsScriptInstalled()
+}
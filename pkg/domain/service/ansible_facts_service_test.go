@@ -0,0 +1,59 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAnsibleFactsRepository is a mock implementation of AnsibleFactsRepository
+type MockAnsibleFactsRepository struct {
+	mock.Mock
+}
+
+func (m *MockAnsibleFactsRepository) InstallFactsScript(binary string) error {
+	args := m.Called(binary)
+	return args.Error(0)
+}
+
+func (m *MockAnsibleFactsRepository) RemoveFactsScript() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockAnsibleFactsRepository) FactsScriptInstalled() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+func TestAnsibleFactsServiceImpl_InstallFactsScript(t *testing.T) {
+	tests := []struct {
+		name          string
+		binary        string
+		expectedError bool
+	}{
+		{name: "absolute path", binary: "/usr/local/bin/hardn"},
+		{name: "relative path", binary: "bin/hardn", expectedError: true},
+		{name: "shell characters", binary: "/opt/hardn; rm -rf /", expectedError: true},
+		{name: "space", binary: "/opt/my tools/hardn", expectedError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(MockAnsibleFactsRepository)
+			repo.On("InstallFactsScript", tc.binary).Return(nil).Maybe()
+
+			service := NewAnsibleFactsServiceImpl(repo)
+			err := service.InstallFactsScript(tc.binary)
+
+			if tc.expectedError {
+				assert.Error(t, err)
+				repo.AssertNotCalled(t, "InstallFactsScript", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			repo.AssertExpectations(t)
+		})
+	}
+}
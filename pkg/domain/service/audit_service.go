@@ -0,0 +1,140 @@
+// pkg/domain/service/audit_service.go
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// AuditService defines operations for recording and comparing audit reports
+type AuditService interface {
+	// SaveReport writes an audit report to path
+	SaveReport(path string, report *model.AuditReport) error
+
+	// CompareReports loads two audit reports and compares them
+	CompareReports(beforePath, afterPath string) (*model.AuditDiff, error)
+
+	// Diff compares two audit reports
+	Diff(before, after *model.AuditReport) *model.AuditDiff
+}
+
+// AuditServiceImpl implements AuditService
+type AuditServiceImpl struct {
+	repository AuditRepository
+}
+
+// NewAuditServiceImpl creates a new AuditServiceImpl
+func NewAuditServiceImpl(repository AuditRepository) *AuditServiceImpl {
+	return &AuditServiceImpl{
+		repository: repository,
+	}
+}
+
+// AuditRepository defines the repository operations needed by AuditService
+type AuditRepository interface {
+	LoadReport(path string) (*model.AuditReport, error)
+	SaveReport(path string, report *model.AuditReport) error
+}
+
+func (s *AuditServiceImpl) SaveReport(path string, report *model.AuditReport) error {
+	if path == "" {
+		return fmt.Errorf("report path is required")
+	}
+	if report == nil {
+		return fmt.Errorf("audit report is required")
+	}
+	return s.repository.SaveReport(path, report)
+}
+
+func (s *AuditServiceImpl) CompareReports(beforePath, afterPath string) (*model.AuditDiff, error) {
+	before, err := s.repository.LoadReport(beforePath)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := s.repository.LoadReport(afterPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Diff(before, after), nil
+}
+
+func (s *AuditServiceImpl) Diff(before, after *model.AuditReport) *model.AuditDiff {
+	diff := &model.AuditDiff{
+		Before:           before,
+		After:            after,
+		ChangedControls:  []model.AuditControlChange{},
+		NewFindings:      []model.PolicyViolation{},
+		ResolvedFindings: []model.PolicyViolation{},
+	}
+
+	beforeControls := make(map[string]model.AuditControl)
+	for _, control := range before.Controls {
+		beforeControls[control.ID] = control
+	}
+	afterControls := make(map[string]model.AuditControl)
+	for _, control := range after.Controls {
+		afterControls[control.ID] = control
+	}
+
+	// Controls present in either report; a control added or dropped between
+	// versions shows up as absent on the other side
+	for id, control := range afterControls {
+		previous, ok := beforeControls[id]
+		beforeStatus := model.ControlAbsent
+		if ok {
+			beforeStatus = previous.Status
+		}
+		if beforeStatus != control.Status {
+			diff.ChangedControls = append(diff.ChangedControls, model.AuditControlChange{
+				ID:     id,
+				Title:  control.Title,
+				Before: beforeStatus,
+				After:  control.Status,
+			})
+		}
+	}
+	for id, control := range beforeControls {
+		if _, ok := afterControls[id]; !ok {
+			diff.ChangedControls = append(diff.ChangedControls, model.AuditControlChange{
+				ID:     id,
+				Title:  control.Title,
+				Before: control.Status,
+				After:  model.ControlAbsent,
+			})
+		}
+	}
+	sort.Slice(diff.ChangedControls, func(i, j int) bool {
+		return diff.ChangedControls[i].ID < diff.ChangedControls[j].ID
+	})
+
+	diff.NewFindings = append(diff.NewFindings, findingsMissingFrom(after.Findings, before.Findings)...)
+	diff.ResolvedFindings = append(diff.ResolvedFindings, findingsMissingFrom(before.Findings, after.Findings)...)
+
+	return diff
+}
+
+// findingsMissingFrom returns the findings in source that other does not contain
+func findingsMissingFrom(source, other []model.PolicyViolation) []model.PolicyViolation {
+	seen := make(map[string]bool)
+	for _, finding := range other {
+		seen[findingKey(finding)] = true
+	}
+
+	var missing []model.PolicyViolation
+	for _, finding := range source {
+		if !seen[findingKey(finding)] {
+			missing = append(missing, finding)
+		}
+	}
+	return missing
+}
+
+// findingKey identifies a finding by rule and message; severity may be
+// re-rated between versions without making it a different finding
+func findingKey(finding model.PolicyViolation) string {
+	return finding.Rule + "\x00" + finding.Message
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuditRepository is a mock implementation of AuditRepository
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) LoadReport(path string) (*model.AuditReport, error) {
+	args := m.Called(path)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.AuditReport), args.Error(1)
+}
+
+func (m *MockAuditRepository) SaveReport(path string, report *model.AuditReport) error {
+	args := m.Called(path, report)
+	return args.Error(0)
+}
+
+func TestAuditServiceImpl_Diff(t *testing.T) {
+	before := &model.AuditReport{
+		Controls: []model.AuditControl{
+			{ID: "firewall.enabled", Status: model.ControlFail},
+			{ID: "ssh.root_login_disabled", Status: model.ControlPass},
+			{ID: "updates.automatic", Status: model.ControlPass},
+			{ID: "legacy.check", Status: model.ControlFail},
+		},
+		Findings: []model.PolicyViolation{
+			{Rule: "ssh", Message: "default port"},
+			{Rule: "firewall", Severity: "high", Message: "firewall disabled"},
+		},
+	}
+	after := &model.AuditReport{
+		Controls: []model.AuditControl{
+			{ID: "firewall.enabled", Status: model.ControlPass},
+			{ID: "ssh.root_login_disabled", Status: model.ControlFail},
+			{ID: "updates.automatic", Status: model.ControlPass},
+			{ID: "system.no_pending_restarts", Status: model.ControlPass},
+		},
+		Findings: []model.PolicyViolation{
+			{Rule: "ssh", Severity: "low", Message: "default port"},
+			{Rule: "ssh", Message: "root login enabled"},
+		},
+	}
+
+	diff := NewAuditServiceImpl(new(MockAuditRepository)).Diff(before, after)
+
+	assert.Equal(t, []model.AuditControlChange{
+		{ID: "firewall.enabled", Before: model.ControlFail, After: model.ControlPass},
+		{ID: "legacy.check", Before: model.ControlFail, After: model.ControlAbsent},
+		{ID: "ssh.root_login_disabled", Before: model.ControlPass, After: model.ControlFail},
+		{ID: "system.no_pending_restarts", Before: model.ControlAbsent, After: model.ControlPass},
+	}, diff.ChangedControls)
+	assert.Equal(t, []model.PolicyViolation{{Rule: "ssh", Message: "root login enabled"}}, diff.NewFindings)
+	assert.Equal(t, []model.PolicyViolation{{Rule: "firewall", Severity: "high", Message: "firewall disabled"}}, diff.ResolvedFindings)
+	assert.True(t, diff.HasRegressions())
+
+	unchanged := NewAuditServiceImpl(new(MockAuditRepository)).Diff(after, after)
+	assert.Empty(t, unchanged.ChangedControls)
+	assert.Empty(t, unchanged.NewFindings)
+	assert.False(t, unchanged.HasRegressions())
+}
+
+func TestAuditServiceImpl_CompareReports(t *testing.T) {
+	t.Run("loads both reports", func(t *testing.T) {
+		before := &model.AuditReport{Controls: []model.AuditControl{{ID: "apparmor.enabled", Status: model.ControlFail}}}
+		after := &model.AuditReport{Controls: []model.AuditControl{{ID: "apparmor.enabled", Status: model.ControlPass}}}
+
+		mockRepo := new(MockAuditRepository)
+		mockRepo.On("LoadReport", "before.json").Return(before, nil)
+		mockRepo.On("LoadReport", "after.json").Return(after, nil)
+
+		diff, err := NewAuditServiceImpl(mockRepo).CompareReports("before.json", "after.json")
+
+		assert.NoError(t, err)
+		assert.Len(t, diff.ChangedControls, 1)
+		assert.False(t, diff.HasRegressions())
+	})
+
+	t.Run("missing report", func(t *testing.T) {
+		mockRepo := new(MockAuditRepository)
+		mockRepo.On("LoadReport", "before.json").Return(nil, errors.New("no such file"))
+
+		_, err := NewAuditServiceImpl(mockRepo).CompareReports("before.json", "after.json")
+
+		assert.Error(t, err)
+	})
+}
@@ -3,6 +3,10 @@ package service
 
 import (
 	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/abbott/hardn/pkg/domain/model"
@@ -19,6 +23,13 @@ type BackupService interface {
 	// RestoreBackup restores a file from backup
 	RestoreBackup(backupPath, originalPath string) error
 
+	// ListBackupGroups returns every backup grouped by the file it was
+	// taken from
+	ListBackupGroups() ([]model.BackupGroup, error)
+
+	// CompareBackup reads a backup and the live file it would replace
+	CompareBackup(backupPath, originalPath string) (*model.BackupComparison, error)
+
 	// CleanupOldBackups removes backups older than specified days
 	CleanupOldBackups(daysToKeep int) error
 
@@ -33,6 +44,17 @@ type BackupService interface {
 
 	// SetBackupDirectory changes the backup directory
 	SetBackupDirectory(directory string) error
+
+	// CheckBackupKeys tests that backups can be encrypted for the
+	// configured recipient and decrypted on this host
+	CheckBackupKeys() (*model.BackupKeyStatus, error)
+
+	// SyncRemote mirrors the backups to the configured remote target and
+	// removes remote backups past its retention
+	SyncRemote() (*model.BackupSyncResult, error)
+
+	// CheckRemote tests that the remote target is reachable and writable
+	CheckRemote() error
 }
 
 // BackupServiceImpl implements BackupService
@@ -52,14 +74,26 @@ type BackupRepository interface {
 	BackupFile(filePath string) error
 	ListBackups(filePath string) ([]model.BackupFile, error)
 	RestoreBackup(backupPath, originalPath string) error
+	ListAllBackups() ([]model.BackupFile, error)
+	CompareBackup(backupPath, originalPath string) (*model.BackupComparison, error)
 	CleanupOldBackups(before time.Time) error
 	VerifyBackupDirectory() error
 	GetBackupConfig() (*model.BackupConfig, error)
 	SetBackupConfig(config model.BackupConfig) error
+	CheckBackupKeys() (*model.BackupKeyStatus, error)
+	SyncRemote() (*model.BackupSyncResult, error)
+	CheckRemote() error
 }
 
 // Implementation of BackupService methods
 func (s *BackupServiceImpl) BackupFile(filePath string) error {
+	config, err := s.repository.GetBackupConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get backup config: %w", err)
+	}
+	if err := ValidateBackupConfig(*config); err != nil {
+		return err
+	}
 	return s.repository.BackupFile(filePath)
 }
 
@@ -71,6 +105,21 @@ func (s *BackupServiceImpl) RestoreBackup(backupPath, originalPath string) error
 	return s.repository.RestoreBackup(backupPath, originalPath)
 }
 
+func (s *BackupServiceImpl) ListBackupGroups() ([]model.BackupGroup, error) {
+	backups, err := s.repository.ListAllBackups()
+	if err != nil {
+		return nil, err
+	}
+	return GroupBackups(backups), nil
+}
+
+func (s *BackupServiceImpl) CompareBackup(backupPath, originalPath string) (*model.BackupComparison, error) {
+	if !filepath.IsAbs(originalPath) {
+		return nil, fmt.Errorf("restore path %q is not absolute", originalPath)
+	}
+	return s.repository.CompareBackup(backupPath, originalPath)
+}
+
 func (s *BackupServiceImpl) CleanupOldBackups(daysToKeep int) error {
 	// Convert days to a specific time
 	cutoffTime := time.Now().AddDate(0, 0, -daysToKeep)
@@ -104,3 +153,173 @@ func (s *BackupServiceImpl) SetBackupDirectory(directory string) error {
 	config.BackupDir = directory
 	return s.repository.SetBackupConfig(*config)
 }
+
+func (s *BackupServiceImpl) CheckBackupKeys() (*model.BackupKeyStatus, error) {
+	config, err := s.repository.GetBackupConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backup config: %w", err)
+	}
+	if config.Encryption == "" {
+		return nil, fmt.Errorf("backups are not encrypted; set backupEncryption.tool to age or gpg")
+	}
+	if err := ValidateBackupConfig(*config); err != nil {
+		return nil, err
+	}
+	return s.repository.CheckBackupKeys()
+}
+
+func (s *BackupServiceImpl) SyncRemote() (*model.BackupSyncResult, error) {
+	if err := s.validateRemote(); err != nil {
+		return nil, err
+	}
+	return s.repository.SyncRemote()
+}
+
+func (s *BackupServiceImpl) CheckRemote() error {
+	if err := s.validateRemote(); err != nil {
+		return err
+	}
+	return s.repository.CheckRemote()
+}
+
+// validateRemote checks the configured remote target
+func (s *BackupServiceImpl) validateRemote() error {
+	config, err := s.repository.GetBackupConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get backup config: %w", err)
+	}
+	if !config.Remote.Configured() {
+		return fmt.Errorf("no remote backup target; set backupRemote.url")
+	}
+	return ValidateBackupRemote(config.Remote)
+}
+
+// GroupBackups groups backups by the file they were taken from, sorted by
+// path with the backups newest first. Backups without a recorded path are
+// grouped by name after the others.
+func GroupBackups(backups []model.BackupFile) []model.BackupGroup {
+	var groups []model.BackupGroup
+	index := make(map[string]int)
+	for _, backup := range backups {
+		name := backupBaseName(backup.BackupPath)
+		key := backup.OriginalPath
+		if key == "" {
+			key = "\x00" + name
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, model.BackupGroup{OriginalPath: backup.OriginalPath, Name: name})
+		}
+		groups[i].Backups = append(groups[i].Backups, backup)
+	}
+
+	for _, group := range groups {
+		sort.SliceStable(group.Backups, func(i, j int) bool {
+			return group.Backups[i].Created.After(group.Backups[j].Created)
+		})
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if (a.OriginalPath == "") != (b.OriginalPath == "") {
+			return a.OriginalPath != ""
+		}
+		if a.OriginalPath != b.OriginalPath {
+			return a.OriginalPath < b.OriginalPath
+		}
+		return a.Name < b.Name
+	})
+	return groups
+}
+
+// backupBaseName returns the name of the file a backup was taken from,
+// such as sshd_config for sshd_config.101500.bak.gz
+func backupBaseName(backupPath string) string {
+	name := filepath.Base(backupPath)
+	if bak := strings.LastIndex(name, ".bak"); bak > 0 {
+		name = name[:bak]
+		if dot := strings.LastIndex(name, "."); dot > 0 {
+			name = name[:dot]
+		}
+	}
+	return name
+}
+
+// ValidateBackupConfig checks the compression and encryption of backups
+func ValidateBackupConfig(config model.BackupConfig) error {
+	maxLevel := 0
+	switch config.Compression {
+	case "":
+	case model.BackupGzip:
+		maxLevel = 9
+	case model.BackupZstd:
+		maxLevel = 19
+	default:
+		return fmt.Errorf("invalid backup compression %q (use gzip or zstd)", config.Compression)
+	}
+	if config.CompressionLevel < 0 || config.CompressionLevel > maxLevel {
+		if maxLevel == 0 {
+			return fmt.Errorf("a compression level needs a backup compression format")
+		}
+		return fmt.Errorf("%s compression level %d is not between 1 and %d", config.Compression, config.CompressionLevel, maxLevel)
+	}
+
+	switch config.Encryption {
+	case "":
+		return nil
+	case model.BackupAge, model.BackupGPG:
+	default:
+		return fmt.Errorf("invalid backup encryption %q (use age or gpg)", config.Encryption)
+	}
+	if config.Recipient == "" {
+		return fmt.Errorf("%s encryption of backups needs a recipient", config.Encryption)
+	}
+	if strings.HasPrefix(config.Recipient, "-") {
+		return fmt.Errorf("invalid backup recipient %q", config.Recipient)
+	}
+	return nil
+}
+
+// ValidateBackupRemote checks the URL and options of a remote backup target
+func ValidateBackupRemote(remote model.BackupRemote) error {
+	u, err := url.Parse(remote.URL)
+	if err != nil {
+		return fmt.Errorf("invalid backup target %q: %w", remote.URL, err)
+	}
+	if remote.RetentionDays < 0 {
+		return fmt.Errorf("backup retention of %d days is negative", remote.RetentionDays)
+	}
+
+	switch u.Scheme {
+	case model.BackupTargetS3:
+		if u.Host == "" {
+			return fmt.Errorf("backup target %q has no bucket", remote.URL)
+		}
+		if remote.IdentityFile != "" {
+			return fmt.Errorf("an SSH identity file does not apply to S3 backup targets")
+		}
+		if remote.Endpoint != "" {
+			endpoint, err := url.Parse(remote.Endpoint)
+			if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+				return fmt.Errorf("invalid S3 endpoint %q (use https://host[:port])", remote.Endpoint)
+			}
+		}
+	case model.BackupTargetSFTP:
+		if u.Hostname() == "" || strings.HasPrefix(u.Hostname(), "-") {
+			return fmt.Errorf("backup target %q has no valid host", remote.URL)
+		}
+		if u.User != nil && strings.HasPrefix(u.User.Username(), "-") {
+			return fmt.Errorf("invalid user in backup target %q", remote.URL)
+		}
+		if u.Path == "" || u.Path == "/" {
+			return fmt.Errorf("backup target %q needs a directory", remote.URL)
+		}
+		if remote.Endpoint != "" || remote.Profile != "" {
+			return fmt.Errorf("an S3 endpoint or profile does not apply to SFTP backup targets")
+		}
+	default:
+		return fmt.Errorf("unsupported backup target %q (use s3:// or sftp://)", remote.URL)
+	}
+	return nil
+}
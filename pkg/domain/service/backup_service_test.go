@@ -28,6 +28,11 @@ type MockBackupRepository struct {
 	OriginalPath        string
 	RestoreBackupError  error
 
+	// ListAllBackups and CompareBackup tracking
+	AllBackups       []model.BackupFile
+	CompareCalled    bool
+	ComparisonResult *model.BackupComparison
+
 	// CleanupOldBackups tracking
 	CleanupCalled     bool
 	CleanupBeforeTime time.Time
@@ -46,6 +51,16 @@ type MockBackupRepository struct {
 	SetConfigCalled bool
 	SetConfigValue  model.BackupConfig
 	SetConfigError  error
+
+	// CheckBackupKeys tracking
+	CheckKeysCalled bool
+	KeyStatus       *model.BackupKeyStatus
+
+	// SyncRemote and CheckRemote tracking
+	SyncRemoteCalled  bool
+	SyncResult        *model.BackupSyncResult
+	CheckRemoteCalled bool
+	RemoteError       error
 }
 
 func (m *MockBackupRepository) BackupFile(filePath string) error {
@@ -67,6 +82,15 @@ func (m *MockBackupRepository) RestoreBackup(backupPath, originalPath string) er
 	return m.RestoreBackupError
 }
 
+func (m *MockBackupRepository) ListAllBackups() ([]model.BackupFile, error) {
+	return m.AllBackups, nil
+}
+
+func (m *MockBackupRepository) CompareBackup(backupPath, originalPath string) (*model.BackupComparison, error) {
+	m.CompareCalled = true
+	return m.ComparisonResult, nil
+}
+
 func (m *MockBackupRepository) CleanupOldBackups(before time.Time) error {
 	m.CleanupCalled = true
 	m.CleanupBeforeTime = before
@@ -89,6 +113,21 @@ func (m *MockBackupRepository) SetBackupConfig(config model.BackupConfig) error
 	return m.SetConfigError
 }
 
+func (m *MockBackupRepository) CheckBackupKeys() (*model.BackupKeyStatus, error) {
+	m.CheckKeysCalled = true
+	return m.KeyStatus, nil
+}
+
+func (m *MockBackupRepository) SyncRemote() (*model.BackupSyncResult, error) {
+	m.SyncRemoteCalled = true
+	return m.SyncResult, m.RemoteError
+}
+
+func (m *MockBackupRepository) CheckRemote() error {
+	m.CheckRemoteCalled = true
+	return m.RemoteError
+}
+
 func TestNewBackupServiceImpl(t *testing.T) {
 	repo := &MockBackupRepository{}
 
@@ -107,8 +146,10 @@ func TestBackupServiceImpl_BackupFile(t *testing.T) {
 	tests := []struct {
 		name      string
 		filePath  string
+		config    model.BackupConfig
 		mockError error
 		wantErr   bool
+		skipRepo  bool
 	}{
 		{
 			name:      "successful backup",
@@ -128,13 +169,28 @@ func TestBackupServiceImpl_BackupFile(t *testing.T) {
 			mockError: errors.New("empty path"),
 			wantErr:   true,
 		},
+		{
+			name:     "compressed and encrypted",
+			filePath: "/etc/ssh/sshd_config",
+			config:   model.BackupConfig{Compression: model.BackupZstd, CompressionLevel: 19, Encryption: model.BackupAge, Recipient: "age1example"},
+		},
+		{
+			name:     "encryption without recipient",
+			filePath: "/etc/ssh/sshd_config",
+			config:   model.BackupConfig{Encryption: model.BackupGPG},
+			wantErr:  true,
+			skipRepo: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Setup
+			config := tt.config
+			config.Enabled = true
 			repo := &MockBackupRepository{
 				BackupFileError: tt.mockError,
+				BackupConfig:    &config,
 			}
 
 			service := NewBackupServiceImpl(repo)
@@ -148,6 +204,13 @@ func TestBackupServiceImpl_BackupFile(t *testing.T) {
 				return
 			}
 
+			if tt.skipRepo {
+				if repo.BackupFileCalled {
+					t.Error("Expected an invalid configuration to stop the backup")
+				}
+				return
+			}
+
 			if !repo.BackupFileCalled {
 				t.Error("Expected BackupFile to be called")
 			}
@@ -674,3 +737,160 @@ func TestBackupServiceImpl_SetBackupDirectory(t *testing.T) {
 		})
 	}
 }
+
+func TestBackupServiceImpl_CheckBackupKeys(t *testing.T) {
+	repo := &MockBackupRepository{BackupConfig: &model.BackupConfig{Enabled: true}}
+	service := NewBackupServiceImpl(repo)
+
+	if _, err := service.CheckBackupKeys(); err == nil {
+		t.Error("Expected an error for unencrypted backups")
+	}
+	if repo.CheckKeysCalled {
+		t.Error("Expected no key test without encryption")
+	}
+
+	repo.BackupConfig = &model.BackupConfig{Encryption: model.BackupGPG, Recipient: "ops@example.com"}
+	repo.KeyStatus = &model.BackupKeyStatus{Tool: model.BackupGPG, Installed: true, CanEncrypt: true}
+	status, err := service.CheckBackupKeys()
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if status.Ready() {
+		t.Error("Expected keys that can not decrypt not to be ready")
+	}
+}
+
+func TestValidateBackupConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  model.BackupConfig
+		wantErr bool
+	}{
+		{name: "plain copies", config: model.BackupConfig{}},
+		{name: "gzip default level", config: model.BackupConfig{Compression: model.BackupGzip}},
+		{name: "zstd level 19", config: model.BackupConfig{Compression: model.BackupZstd, CompressionLevel: 19}},
+		{name: "gzip level 10", config: model.BackupConfig{Compression: model.BackupGzip, CompressionLevel: 10}, wantErr: true},
+		{name: "level without format", config: model.BackupConfig{CompressionLevel: 3}, wantErr: true},
+		{name: "unknown format", config: model.BackupConfig{Compression: "xz"}, wantErr: true},
+		{name: "age recipient", config: model.BackupConfig{Encryption: model.BackupAge, Recipient: "age1example"}},
+		{name: "gpg without recipient", config: model.BackupConfig{Encryption: model.BackupGPG}, wantErr: true},
+		{name: "option as recipient", config: model.BackupConfig{Encryption: model.BackupGPG, Recipient: "--armor"}, wantErr: true},
+		{name: "unknown tool", config: model.BackupConfig{Encryption: "openssl", Recipient: "x"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBackupConfig(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBackupConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBackupServiceImpl_SyncRemote(t *testing.T) {
+	repo := &MockBackupRepository{BackupConfig: &model.BackupConfig{Enabled: true}}
+	service := NewBackupServiceImpl(repo)
+
+	if _, err := service.SyncRemote(); err == nil {
+		t.Error("Expected an error without a remote target")
+	}
+	repo.BackupConfig.Remote = model.BackupRemote{URL: "ftp://backup.example.com/hardn"}
+	if _, err := service.SyncRemote(); err == nil {
+		t.Error("Expected an error for an unsupported target")
+	}
+	if repo.SyncRemoteCalled {
+		t.Error("Expected no sync to an invalid target")
+	}
+
+	repo.BackupConfig.Remote = model.BackupRemote{URL: "s3://backups/web1", RetentionDays: 30}
+	repo.SyncResult = &model.BackupSyncResult{Target: "s3://backups/web1", Uploaded: []string{"2024-05-01"}}
+	result, err := service.SyncRemote()
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !reflect.DeepEqual(result, repo.SyncResult) {
+		t.Errorf("Expected %+v, got %+v", repo.SyncResult, result)
+	}
+
+	repo.RemoteError = errors.New("connection refused")
+	if err := service.CheckRemote(); err == nil || !repo.CheckRemoteCalled {
+		t.Error("Expected the failed remote check to be returned")
+	}
+}
+
+func TestValidateBackupRemote(t *testing.T) {
+	tests := []struct {
+		name    string
+		remote  model.BackupRemote
+		wantErr bool
+	}{
+		{name: "s3 bucket", remote: model.BackupRemote{URL: "s3://backups"}},
+		{name: "s3 compatible", remote: model.BackupRemote{URL: "s3://backups/web1", Endpoint: "https://minio.example.com:9000", Profile: "backup"}},
+		{name: "s3 without bucket", remote: model.BackupRemote{URL: "s3:///web1"}, wantErr: true},
+		{name: "s3 endpoint without scheme", remote: model.BackupRemote{URL: "s3://backups", Endpoint: "minio.example.com"}, wantErr: true},
+		{name: "s3 identity file", remote: model.BackupRemote{URL: "s3://backups", IdentityFile: "/root/.ssh/id_ed25519"}, wantErr: true},
+		{name: "sftp", remote: model.BackupRemote{URL: "sftp://backup@host.example.com:2222/srv/backups", IdentityFile: "/root/.ssh/id_ed25519", RetentionDays: 30}},
+		{name: "sftp without directory", remote: model.BackupRemote{URL: "sftp://backup@host.example.com"}, wantErr: true},
+		{name: "sftp option as host", remote: model.BackupRemote{URL: "sftp://-oProxyCommand=x/srv"}, wantErr: true},
+		{name: "sftp with profile", remote: model.BackupRemote{URL: "sftp://host.example.com/srv", Profile: "backup"}, wantErr: true},
+		{name: "negative retention", remote: model.BackupRemote{URL: "s3://backups", RetentionDays: -1}, wantErr: true},
+		{name: "unsupported scheme", remote: model.BackupRemote{URL: "rsync://host/srv"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBackupRemote(tt.remote)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBackupRemote() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBackupServiceImpl_ListBackupGroups(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	repo := &MockBackupRepository{AllBackups: []model.BackupFile{
+		{OriginalPath: "/etc/ssh/sshd_config", BackupPath: "/b/2024-05-01/sshd_config.090000.bak", Created: day.Add(9 * time.Hour)},
+		{BackupPath: "/b/2024-04-30/hosts.120000.bak.gz", Created: day.Add(-12 * time.Hour)},
+		{OriginalPath: "/etc/fstab", BackupPath: "/b/2024-05-01/fstab.080000.bak", Created: day.Add(8 * time.Hour)},
+		{OriginalPath: "/etc/ssh/sshd_config", BackupPath: "/b/2024-05-01/sshd_config.100000.bak.zst.age", Created: day.Add(10 * time.Hour)},
+	}}
+	service := NewBackupServiceImpl(repo)
+
+	groups, err := service.ListBackupGroups()
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	var titles []string
+	for _, group := range groups {
+		titles = append(titles, group.OriginalPath+"|"+group.Name)
+	}
+	expected := []string{"/etc/fstab|fstab", "/etc/ssh/sshd_config|sshd_config", "|hosts"}
+	if !reflect.DeepEqual(titles, expected) {
+		t.Errorf("Expected groups %v, got %v", expected, titles)
+	}
+	if sshd := groups[1].Backups; len(sshd) != 2 || sshd[0].BackupPath != "/b/2024-05-01/sshd_config.100000.bak.zst.age" {
+		t.Errorf("Expected the sshd_config backups newest first, got %+v", sshd)
+	}
+}
+
+func TestBackupServiceImpl_CompareBackup(t *testing.T) {
+	repo := &MockBackupRepository{ComparisonResult: &model.BackupComparison{Backup: []byte("a"), Current: []byte("a"), CurrentExists: true}}
+	service := NewBackupServiceImpl(repo)
+
+	if _, err := service.CompareBackup("/b/2024-05-01/hosts.120000.bak", "etc/hosts"); err == nil {
+		t.Error("Expected an error for a relative restore path")
+	}
+	if repo.CompareCalled {
+		t.Error("Expected no comparison with a relative restore path")
+	}
+
+	comparison, err := service.CompareBackup("/b/2024-05-01/hosts.120000.bak", "/etc/hosts")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !comparison.Identical() {
+		t.Error("Expected an identical backup")
+	}
+}
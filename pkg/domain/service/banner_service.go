@@ -0,0 +1,151 @@
+// pkg/domain/service/banner_service.go
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// BannerService defines operations for login banners and the MOTD
+type BannerService interface {
+	// EffectivePolicy fills in the default texts of a policy
+	EffectivePolicy(policy model.BannerPolicy) model.BannerPolicy
+
+	// ApplyBanners writes /etc/issue and /etc/issue.net, turns off Ubuntu's
+	// advertising MOTD scripts and installs the dynamic MOTD where
+	// update-motd runs it at each login
+	ApplyBanners(policy model.BannerPolicy) error
+
+	// RefreshMotd writes the rendered MOTD to /etc/motd on hosts without
+	// update-motd, which cannot render it at login
+	RefreshMotd(policy model.BannerPolicy, facts model.MotdFacts) error
+
+	// MotdFacts collects the values of the MOTD placeholders
+	MotdFacts(lastRun time.Time, riskLevel string) model.MotdFacts
+
+	// GetBannerStatus reports the login banners on the host
+	GetBannerStatus() (*model.BannerStatus, error)
+}
+
+// BannerServiceImpl implements BannerService
+type BannerServiceImpl struct {
+	repository BannerRepository
+	osInfo     model.OSInfo
+}
+
+// NewBannerServiceImpl creates a new BannerServiceImpl
+func NewBannerServiceImpl(repository BannerRepository, osInfo model.OSInfo) *BannerServiceImpl {
+	return &BannerServiceImpl{
+		repository: repository,
+		osInfo:     osInfo,
+	}
+}
+
+// BannerRepository defines the repository operations needed by BannerService
+type BannerRepository interface {
+	WriteBanner(path string, content string) error
+	InstallMotdScript() error
+	DisableMotdAds() ([]string, error)
+	Hostname() (string, error)
+	PendingUpdates() (int, error)
+	GetBannerStatus() (*model.BannerStatus, error)
+}
+
+func (s *BannerServiceImpl) EffectivePolicy(policy model.BannerPolicy) model.BannerPolicy {
+	if strings.TrimSpace(policy.Issue) == "" {
+		policy.Issue = model.DefaultLoginBanner
+	}
+	if strings.TrimSpace(policy.IssueNet) == "" {
+		policy.IssueNet = policy.Issue
+	}
+	if strings.TrimSpace(policy.Motd) == "" {
+		policy.Motd = model.DefaultMotdTemplate
+	}
+	return policy
+}
+
+func (s *BannerServiceImpl) ApplyBanners(policy model.BannerPolicy) error {
+	policy = s.EffectivePolicy(policy)
+	facts := s.hostFacts()
+
+	for _, banner := range []struct{ path, text string }{
+		{model.IssuePath, policy.Issue},
+		{model.IssueNetPath, policy.IssueNet},
+	} {
+		if err := s.repository.WriteBanner(banner.path, withTrailingNewline(facts.Replace(banner.text))); err != nil {
+			return err
+		}
+	}
+
+	if policy.DisableMotdAds && s.osInfo.Type == "ubuntu" {
+		if _, err := s.repository.DisableMotdAds(); err != nil {
+			return err
+		}
+	}
+
+	if !policy.DynamicMotd {
+		return nil
+	}
+	status, err := s.repository.GetBannerStatus()
+	if err != nil {
+		return err
+	}
+	if status.UpdateMotd {
+		return s.repository.InstallMotdScript()
+	}
+	return nil
+}
+
+func (s *BannerServiceImpl) RefreshMotd(policy model.BannerPolicy, facts model.MotdFacts) error {
+	if !policy.DynamicMotd {
+		return nil
+	}
+	status, err := s.repository.GetBannerStatus()
+	if err != nil {
+		return err
+	}
+	if status.UpdateMotd {
+		return nil // rendered at each login
+	}
+
+	policy = s.EffectivePolicy(policy)
+	return s.repository.WriteBanner(model.MotdPath, withTrailingNewline(facts.Replace(policy.Motd)))
+}
+
+func (s *BannerServiceImpl) MotdFacts(lastRun time.Time, riskLevel string) model.MotdFacts {
+	facts := s.hostFacts()
+	facts.LastRun = lastRun
+	facts.RiskLevel = riskLevel
+	if pending, err := s.repository.PendingUpdates(); err == nil {
+		facts.PendingUpdates = pending
+	}
+	return facts
+}
+
+func (s *BannerServiceImpl) GetBannerStatus() (*model.BannerStatus, error) {
+	return s.repository.GetBannerStatus()
+}
+
+// hostFacts returns the facts that do not change between runs, which are
+// all the static banners may use
+func (s *BannerServiceImpl) hostFacts() model.MotdFacts {
+	facts := model.MotdFacts{
+		OS:             strings.TrimSpace(s.osInfo.Type + " " + s.osInfo.Version),
+		PendingUpdates: -1,
+	}
+	if hostname, err := s.repository.Hostname(); err == nil {
+		facts.Hostname = hostname
+	}
+	return facts
+}
+
+// withTrailingNewline ends text with a newline so the login prompt or
+// shell prompt starts on a line of its own
+func withTrailingNewline(text string) string {
+	if text == "" || strings.HasSuffix(text, "\n") {
+		return text
+	}
+	return text + "\n"
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBannerRepository is a mock implementation of BannerRepository
+type MockBannerRepository struct {
+	mock.Mock
+}
+
+func (m *MockBannerRepository) WriteBanner(path string, content string) error {
+	args := m.Called(path, content)
+	return args.Error(0)
+}
+
+func (m *MockBannerRepository) InstallMotdScript() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockBannerRepository) DisableMotdAds() ([]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockBannerRepository) Hostname() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockBannerRepository) PendingUpdates() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockBannerRepository) GetBannerStatus() (*model.BannerStatus, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BannerStatus), args.Error(1)
+}
+
+func TestBannerServiceImpl_EffectivePolicy(t *testing.T) {
+	service := NewBannerServiceImpl(new(MockBannerRepository), model.OSInfo{Type: "debian", Version: "12"})
+
+	policy := service.EffectivePolicy(model.BannerPolicy{})
+	assert.Equal(t, model.DefaultLoginBanner, policy.Issue)
+	assert.Equal(t, model.DefaultLoginBanner, policy.IssueNet)
+	assert.Equal(t, model.DefaultMotdTemplate, policy.Motd)
+
+	// issue.net follows a configured issue
+	policy = service.EffectivePolicy(model.BannerPolicy{Issue: "Lab host {hostname}"})
+	assert.Equal(t, "Lab host {hostname}", policy.IssueNet)
+}
+
+func TestBannerServiceImpl_ApplyBanners(t *testing.T) {
+	tests := []struct {
+		name          string
+		osType        string
+		policy        model.BannerPolicy
+		updateMotd    bool
+		expectAds     bool
+		expectScript  bool
+		writeError    error
+		expectedError bool
+	}{
+		{
+			name:         "ubuntu with dynamic MOTD",
+			osType:       "ubuntu",
+			policy:       model.BannerPolicy{DynamicMotd: true, DisableMotdAds: true},
+			updateMotd:   true,
+			expectAds:    true,
+			expectScript: true,
+		},
+		{
+			name:       "ads are only disabled on ubuntu",
+			osType:     "debian",
+			policy:     model.BannerPolicy{DisableMotdAds: true},
+			updateMotd: true,
+		},
+		{
+			name:   "no update-motd leaves the MOTD to RefreshMotd",
+			osType: "rocky",
+			policy: model.BannerPolicy{DynamicMotd: true},
+		},
+		{
+			name:          "write error",
+			osType:        "debian",
+			writeError:    errors.New("read-only file system"),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(MockBannerRepository)
+			repo.On("Hostname").Return("web1", nil)
+			repo.On("WriteBanner", model.IssuePath, "Authorized users of web1 only\n").Return(tc.writeError)
+			repo.On("WriteBanner", model.IssueNetPath, "Authorized users of web1 only\n").Return(nil)
+			repo.On("GetBannerStatus").Return(&model.BannerStatus{UpdateMotd: tc.updateMotd}, nil).Maybe()
+			if tc.expectAds {
+				repo.On("DisableMotdAds").Return([]string{"50-motd-news"}, nil)
+			}
+			if tc.expectScript {
+				repo.On("InstallMotdScript").Return(nil)
+			}
+
+			tc.policy.Issue = "Authorized users of {hostname} only"
+			service := NewBannerServiceImpl(repo, model.OSInfo{Type: tc.osType})
+			err := service.ApplyBanners(tc.policy)
+
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			repo.AssertExpectations(t)
+			if !tc.expectAds {
+				repo.AssertNotCalled(t, "DisableMotdAds")
+			}
+			if !tc.expectScript {
+				repo.AssertNotCalled(t, "InstallMotdScript")
+			}
+		})
+	}
+}
+
+func TestBannerServiceImpl_RefreshMotd(t *testing.T) {
+	lastRun := time.Date(2026, 3, 14, 2, 30, 0, 0, time.UTC)
+	policy := model.BannerPolicy{
+		DynamicMotd: true,
+		Motd:        "{hostname} ({os}): last run {last_run}, risk {risk_level}, {pending_updates} updates",
+	}
+
+	t.Run("writes /etc/motd without update-motd", func(t *testing.T) {
+		repo := new(MockBannerRepository)
+		repo.On("GetBannerStatus").Return(&model.BannerStatus{}, nil)
+		repo.On("Hostname").Return("db1", nil)
+		repo.On("PendingUpdates").Return(3, nil)
+		repo.On("WriteBanner", model.MotdPath,
+			"db1 (rocky 9.4): last run 2026-03-14 02:30, risk Low, 3 updates\n").Return(nil)
+
+		service := NewBannerServiceImpl(repo, model.OSInfo{Type: "rocky", Version: "9.4"})
+		err := service.RefreshMotd(policy, service.MotdFacts(lastRun, "Low"))
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rendered at login with update-motd", func(t *testing.T) {
+		repo := new(MockBannerRepository)
+		repo.On("GetBannerStatus").Return(&model.BannerStatus{UpdateMotd: true}, nil)
+
+		service := NewBannerServiceImpl(repo, model.OSInfo{Type: "ubuntu", Version: "24.04"})
+		err := service.RefreshMotd(policy, model.MotdFacts{})
+
+		assert.NoError(t, err)
+		repo.AssertNotCalled(t, "WriteBanner", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown values", func(t *testing.T) {
+		repo := new(MockBannerRepository)
+		repo.On("Hostname").Return("", errors.New("no hostname"))
+		repo.On("PendingUpdates").Return(-1, errors.New("dnf failed"))
+
+		service := NewBannerServiceImpl(repo, model.OSInfo{Type: "rocky", Version: "9.4"})
+		motd := service.MotdFacts(time.Time{}, "").Replace(policy.Motd)
+
+		assert.Equal(t, " (rocky 9.4): last run never, risk Unknown, unknown updates", motd)
+	})
+}
@@ -0,0 +1,121 @@
+// pkg/domain/service/boot_hardening_service.go
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// bootParameterPattern matches a kernel parameter hardn accepts: a name,
+// optionally with a value, and nothing the shell or GRUB would interpret
+var bootParameterPattern = regexp.MustCompile(`^[a-z0-9_.-]+(=[A-Za-z0-9_.,:/-]+)?$`)
+
+// BootHardeningService defines operations for the kernel command line
+// and the boot loader password
+type BootHardeningService interface {
+	// ApplyHardening sets the parameters and password of a policy
+	ApplyHardening(policy model.BootHardeningPolicy) error
+
+	// GetStatus compares a policy with the boot loader and running kernel
+	GetStatus(policy model.BootHardeningPolicy) (*model.BootHardeningStatus, error)
+
+	// ConfigFiles returns the boot loader files ApplyHardening may change
+	ConfigFiles() []string
+}
+
+// BootHardeningServiceImpl implements BootHardeningService
+type BootHardeningServiceImpl struct {
+	repository BootRepository
+	osInfo     model.OSInfo
+}
+
+// NewBootHardeningServiceImpl creates a new BootHardeningServiceImpl
+func NewBootHardeningServiceImpl(repository BootRepository, osInfo model.OSInfo) *BootHardeningServiceImpl {
+	return &BootHardeningServiceImpl{
+		repository: repository,
+		osInfo:     osInfo,
+	}
+}
+
+// BootRepository defines the repository operations needed by BootHardeningService
+type BootRepository interface {
+	GetConfiguredParameters() ([]string, error)
+	GetRunningParameters() ([]string, error)
+	SetParameters(parameters []string) error
+	HashPassword(password string) (string, error)
+	SetPassword(hash string) error
+	PasswordSet() (bool, error)
+	ConfigFiles() []string
+}
+
+// ValidateBootParameter checks that a kernel parameter is a name with an
+// optional value
+func ValidateBootParameter(parameter string) error {
+	if !bootParameterPattern.MatchString(parameter) {
+		return fmt.Errorf("invalid kernel parameter %q", parameter)
+	}
+	return nil
+}
+
+func (s *BootHardeningServiceImpl) ApplyHardening(policy model.BootHardeningPolicy) error {
+	for _, parameter := range policy.Parameters {
+		if err := ValidateBootParameter(parameter); err != nil {
+			return err
+		}
+	}
+
+	if len(policy.Parameters) > 0 {
+		if err := s.repository.SetParameters(policy.Parameters); err != nil {
+			return err
+		}
+	}
+
+	if policy.Password == "" {
+		return nil
+	}
+	hash := policy.Password
+	if !strings.HasPrefix(hash, model.GrubPasswordHashPrefix) {
+		var err error
+		if hash, err = s.repository.HashPassword(policy.Password); err != nil {
+			return err
+		}
+	}
+	return s.repository.SetPassword(hash)
+}
+
+func (s *BootHardeningServiceImpl) GetStatus(policy model.BootHardeningPolicy) (*model.BootHardeningStatus, error) {
+	configured, err := s.repository.GetConfiguredParameters()
+	if err != nil {
+		return nil, err
+	}
+	running, err := s.repository.GetRunningParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &model.BootHardeningStatus{Configured: []string{}}
+	for _, parameter := range policy.Parameters {
+		if !slices.Contains(configured, parameter) {
+			status.Missing = append(status.Missing, parameter)
+			continue
+		}
+		status.Configured = append(status.Configured, parameter)
+		if !slices.Contains(running, parameter) {
+			status.Pending = append(status.Pending, parameter)
+		}
+	}
+	status.RebootRequired = len(status.Pending) > 0
+
+	if status.PasswordSet, err = s.repository.PasswordSet(); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (s *BootHardeningServiceImpl) ConfigFiles() []string {
+	return s.repository.ConfigFiles()
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBootRepository is a mock implementation of BootRepository
+type MockBootRepository struct {
+	mock.Mock
+}
+
+func (m *MockBootRepository) GetConfiguredParameters() ([]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockBootRepository) GetRunningParameters() ([]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockBootRepository) SetParameters(parameters []string) error {
+	args := m.Called(parameters)
+	return args.Error(0)
+}
+
+func (m *MockBootRepository) HashPassword(password string) (string, error) {
+	args := m.Called(password)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockBootRepository) SetPassword(hash string) error {
+	args := m.Called(hash)
+	return args.Error(0)
+}
+
+func (m *MockBootRepository) PasswordSet() (bool, error) {
+	args := m.Called()
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBootRepository) ConfigFiles() []string {
+	args := m.Called()
+	return args.Get(0).([]string)
+}
+
+func TestBootHardeningServiceImpl_ApplyHardening(t *testing.T) {
+	osInfo := model.OSInfo{Type: "debian", Version: "12"}
+	hash := "grub.pbkdf2.sha512.10000.AB.CD"
+
+	t.Run("parameters and plain password", func(t *testing.T) {
+		repo := new(MockBootRepository)
+		repo.On("SetParameters", []string{"init_on_alloc=1", "lockdown=confidentiality"}).Return(nil)
+		repo.On("HashPassword", "secret").Return(hash, nil)
+		repo.On("SetPassword", hash).Return(nil)
+		svc := NewBootHardeningServiceImpl(repo, osInfo)
+
+		err := svc.ApplyHardening(model.BootHardeningPolicy{
+			Parameters: []string{"init_on_alloc=1", "lockdown=confidentiality"},
+			Password:   "secret",
+		})
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("hashed password", func(t *testing.T) {
+		repo := new(MockBootRepository)
+		repo.On("SetPassword", hash).Return(nil)
+		svc := NewBootHardeningServiceImpl(repo, osInfo)
+
+		assert.NoError(t, svc.ApplyHardening(model.BootHardeningPolicy{Password: hash}))
+		repo.AssertNotCalled(t, "HashPassword", mock.Anything)
+		repo.AssertNotCalled(t, "SetParameters", mock.Anything)
+	})
+
+	t.Run("invalid parameter", func(t *testing.T) {
+		repo := new(MockBootRepository)
+		svc := NewBootHardeningServiceImpl(repo, osInfo)
+
+		err := svc.ApplyHardening(model.BootHardeningPolicy{Parameters: []string{"init=/bin/sh; reboot"}})
+		assert.ErrorContains(t, err, "invalid kernel parameter")
+		repo.AssertNotCalled(t, "SetParameters", mock.Anything)
+	})
+}
+
+func TestBootHardeningServiceImpl_GetStatus(t *testing.T) {
+	repo := new(MockBootRepository)
+	repo.On("GetConfiguredParameters").Return([]string{"quiet", "init_on_alloc=1", "slab_nomerge"}, nil)
+	repo.On("GetRunningParameters").Return([]string{"BOOT_IMAGE=/vmlinuz", "quiet", "init_on_alloc=1"}, nil)
+	repo.On("PasswordSet").Return(true, nil)
+	svc := NewBootHardeningServiceImpl(repo, model.OSInfo{Type: "ubuntu", Version: "24.04"})
+
+	status, err := svc.GetStatus(model.BootHardeningPolicy{
+		Parameters: []string{"init_on_alloc=1", "slab_nomerge", "page_poison=1"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"init_on_alloc=1", "slab_nomerge"}, status.Configured)
+	assert.Equal(t, []string{"page_poison=1"}, status.Missing)
+	assert.Equal(t, []string{"slab_nomerge"}, status.Pending)
+	assert.True(t, status.RebootRequired)
+	assert.True(t, status.PasswordSet)
+	assert.False(t, status.Compliant())
+}
@@ -0,0 +1,80 @@
+// pkg/domain/service/certificate_service.go
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// CertificateService defines operations for monitoring certificate expiry
+type CertificateService interface {
+	// CheckExpiry reads the certificates under paths and the common
+	// locations and reports those expiring within days
+	CheckExpiry(paths []string, days int) (*model.CertificateExpiry, error)
+}
+
+// CertificateServiceImpl implements CertificateService
+type CertificateServiceImpl struct {
+	repository CertificateRepository
+}
+
+// NewCertificateServiceImpl creates a new CertificateServiceImpl
+func NewCertificateServiceImpl(repository CertificateRepository) *CertificateServiceImpl {
+	return &CertificateServiceImpl{
+		repository: repository,
+	}
+}
+
+// CertificateRepository defines the repository operations needed by CertificateService
+type CertificateRepository interface {
+	FindCertificateFiles(paths []string) ([]string, error)
+	ReadCertificate(path string) (*model.Certificate, error)
+}
+
+func (s *CertificateServiceImpl) CheckExpiry(paths []string, days int) (*model.CertificateExpiry, error) {
+	if days <= 0 {
+		days = model.DefaultCertificateExpiryDays
+	}
+
+	searchPaths := append(append([]string{}, paths...), model.CommonCertificatePaths...)
+	files, err := s.repository.FindCertificateFiles(searchPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.CertificateExpiry{ThresholdDays: days}
+	now := time.Now()
+	seen := make(map[string]bool)
+
+	for _, file := range files {
+		// Key files, CSRs and unreadable files are not certificates to track
+		cert, err := s.repository.ReadCertificate(file)
+		if err != nil || cert == nil {
+			continue
+		}
+
+		// cert.pem and fullchain.pem carry the same leaf certificate
+		id := cert.Issuer + "/" + cert.Serial
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		result.Checked++
+
+		cert.DaysLeft = int(cert.NotAfter.Sub(now).Hours() / 24)
+		if cert.NotAfter.Before(now) {
+			cert.DaysLeft = -1 - int(now.Sub(cert.NotAfter).Hours()/24)
+		}
+		if cert.DaysLeft < days {
+			result.Expiring = append(result.Expiring, *cert)
+		}
+	}
+
+	sort.SliceStable(result.Expiring, func(i, j int) bool {
+		return result.Expiring[i].NotAfter.Before(result.Expiring[j].NotAfter)
+	})
+
+	return result, nil
+}
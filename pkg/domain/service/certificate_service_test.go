@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCertificateRepository is a mock implementation of CertificateRepository
+type MockCertificateRepository struct {
+	mock.Mock
+}
+
+func (m *MockCertificateRepository) FindCertificateFiles(paths []string) ([]string, error) {
+	args := m.Called(paths)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockCertificateRepository) ReadCertificate(path string) (*model.Certificate, error) {
+	args := m.Called(path)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Certificate), args.Error(1)
+}
+
+func TestCertificateServiceImpl_CheckExpiry(t *testing.T) {
+	now := time.Now()
+	live := "/etc/letsencrypt/live/example.com/"
+
+	mockRepo := new(MockCertificateRepository)
+	mockRepo.On("FindCertificateFiles", append([]string{"/opt/app/tls"}, model.CommonCertificatePaths...)).Return([]string{
+		live + "cert.pem", live + "fullchain.pem", live + "privkey.pem",
+		"/opt/app/tls/old.crt", "/opt/app/tls/new.crt",
+	}, nil)
+
+	leaf := &model.Certificate{Path: live + "cert.pem", Subject: "example.com", Issuer: "R3", Serial: "01",
+		NotAfter: now.Add(10*24*time.Hour + time.Hour)}
+	mockRepo.On("ReadCertificate", live+"cert.pem").Return(leaf, nil)
+	mockRepo.On("ReadCertificate", live+"fullchain.pem").Return(&model.Certificate{
+		Path: live + "fullchain.pem", Subject: "example.com", Issuer: "R3", Serial: "01", NotAfter: leaf.NotAfter}, nil)
+	mockRepo.On("ReadCertificate", live+"privkey.pem").Return(nil, errors.New("no certificate found"))
+	mockRepo.On("ReadCertificate", "/opt/app/tls/old.crt").Return(&model.Certificate{
+		Path: "/opt/app/tls/old.crt", Subject: "intranet", Issuer: "Internal CA", Serial: "02",
+		NotAfter: now.Add(-36 * time.Hour)}, nil)
+	mockRepo.On("ReadCertificate", "/opt/app/tls/new.crt").Return(&model.Certificate{
+		Path: "/opt/app/tls/new.crt", Subject: "intranet", Issuer: "Internal CA", Serial: "03",
+		NotAfter: now.Add(200 * 24 * time.Hour)}, nil)
+
+	expiry, err := NewCertificateServiceImpl(mockRepo).CheckExpiry([]string{"/opt/app/tls"}, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, expiry.Checked)
+	assert.Equal(t, model.DefaultCertificateExpiryDays, expiry.ThresholdDays)
+	assert.Len(t, expiry.Expiring, 2)
+
+	// Sorted by expiry, the expired certificate first
+	assert.Equal(t, "/opt/app/tls/old.crt", expiry.Expiring[0].Path)
+	assert.True(t, expiry.Expiring[0].Expired())
+	assert.Equal(t, -2, expiry.Expiring[0].DaysLeft)
+	assert.Equal(t, live+"cert.pem", expiry.Expiring[1].Path)
+	assert.Equal(t, 10, expiry.Expiring[1].DaysLeft)
+}
+
+func TestCertificateServiceImpl_CheckExpiry_Error(t *testing.T) {
+	mockRepo := new(MockCertificateRepository)
+	mockRepo.On("FindCertificateFiles", mock.Anything).Return(nil, errors.New("find failed"))
+
+	_, err := NewCertificateServiceImpl(mockRepo).CheckExpiry(nil, 14)
+
+	assert.Error(t, err)
+}
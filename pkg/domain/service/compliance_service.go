@@ -0,0 +1,214 @@
+// pkg/domain/service/compliance_service.go
+package service
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// ComplianceService defines operations for checking the host against the
+// CIS benchmark recommendations hardn covers
+type ComplianceService interface {
+	// RunBenchmark evaluates each recommendation and scores the host
+	RunBenchmark(request model.ComplianceRequest) (*model.ComplianceReport, error)
+}
+
+// ComplianceServiceImpl implements ComplianceService
+type ComplianceServiceImpl struct {
+	repository ComplianceRepository
+	now        func() time.Time
+}
+
+// NewComplianceServiceImpl creates a new ComplianceServiceImpl
+func NewComplianceServiceImpl(repository ComplianceRepository) *ComplianceServiceImpl {
+	return &ComplianceServiceImpl{
+		repository: repository,
+		now:        time.Now,
+	}
+}
+
+// ComplianceRepository defines the repository operations needed by ComplianceService
+type ComplianceRepository interface {
+	CommandInstalled(name string) bool
+	SSHEffectiveConfig() (map[string][]string, error)
+	FileMode(path string) (fs.FileMode, error)
+	ReadSudoers() (map[string][]byte, error)
+}
+
+// complianceSSHDConfigPath is the file recommendation 5.1.1 checks
+const complianceSSHDConfigPath = "/etc/ssh/sshd_config"
+
+// complianceMaxAuthTries is the highest MaxAuthTries the benchmark accepts
+const complianceMaxAuthTries = 4
+
+// complianceHost is the host state the checks beyond the audit controls read
+type complianceHost struct {
+	controls map[string]string
+	sshd     map[string][]string
+	sudoers  map[string][]byte
+}
+
+func (s *ComplianceServiceImpl) RunBenchmark(request model.ComplianceRequest) (*model.ComplianceReport, error) {
+	report := &model.ComplianceReport{
+		Hostname:    request.Hostname,
+		GeneratedAt: s.now(),
+	}
+	switch request.OSType {
+	case "debian":
+		report.Benchmark = model.BenchmarkCISDebian
+	case "ubuntu":
+		report.Benchmark = model.BenchmarkCISUbuntu
+	default:
+		return nil, fmt.Errorf("the CIS benchmark checks cover Debian and Ubuntu, not %s", request.OSType)
+	}
+
+	host := &complianceHost{controls: make(map[string]string)}
+	for _, control := range request.Controls {
+		host.controls[control.ID] = control.Status
+	}
+	var err error
+	if host.sshd, err = s.repository.SSHEffectiveConfig(); err != nil {
+		return nil, err
+	}
+	if host.sudoers, err = s.repository.ReadSudoers(); err != nil {
+		return nil, err
+	}
+
+	for _, rule := range model.CISRules {
+		result := model.ComplianceResult{ComplianceRule: rule}
+		result.Status, result.Detail = s.evaluate(host, rule.Check)
+		switch result.Status {
+		case model.CompliancePass:
+			report.Passed++
+		case model.ComplianceFail:
+			report.Failed++
+		default:
+			report.NotApplicable++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	if checked := report.Passed + report.Failed; checked > 0 {
+		report.Score = report.Passed * 100 / checked
+	}
+	return report, nil
+}
+
+// evaluate returns the state of a check and what failed. Audit controls
+// are taken as they are; a control the host does not have, such as
+// AppArmor on an SELinux host, does not apply.
+func (s *ComplianceServiceImpl) evaluate(host *complianceHost, check string) (string, string) {
+	if status, ok := host.controls[check]; ok {
+		if status == model.ControlPass {
+			return model.CompliancePass, ""
+		}
+		if status == model.ControlFail {
+			return model.ComplianceFail, ""
+		}
+		return model.ComplianceNotApplicable, ""
+	}
+
+	switch check {
+	case "firewall.ufw_installed":
+		return complianceState(s.repository.CommandInstalled("ufw"), "ufw is not installed")
+
+	case "ssh.config_permissions":
+		if host.sshd == nil {
+			return model.ComplianceNotApplicable, "sshd is not installed"
+		}
+		mode, err := s.repository.FileMode(complianceSSHDConfigPath)
+		if err != nil {
+			return model.ComplianceFail, err.Error()
+		}
+		return complianceState(mode&0077 == 0, fmt.Sprintf("%s has mode %04o", complianceSSHDConfigPath, mode))
+
+	case "ssh.access_limited":
+		if host.sshd == nil {
+			return model.ComplianceNotApplicable, "sshd is not installed"
+		}
+		for _, key := range []string{"allowusers", "allowgroups", "denyusers", "denygroups"} {
+			if len(host.sshd[key]) > 0 {
+				return model.CompliancePass, ""
+			}
+		}
+		return model.ComplianceFail, "no AllowUsers, AllowGroups, DenyUsers or DenyGroups"
+
+	case "ssh.max_auth_tries":
+		if host.sshd == nil {
+			return model.ComplianceNotApplicable, "sshd is not installed"
+		}
+		value := firstSetting(host.sshd, "maxauthtries")
+		tries, err := strconv.Atoi(value)
+		return complianceState(err == nil && tries <= complianceMaxAuthTries, "MaxAuthTries "+value)
+
+	case "ssh.empty_passwords_disabled":
+		return sshSettingIs(host.sshd, "permitemptypasswords", "no", "PermitEmptyPasswords")
+
+	case "ssh.user_environment_disabled":
+		return sshSettingIs(host.sshd, "permituserenvironment", "no", "PermitUserEnvironment")
+
+	case "users.sudo_method":
+		return sudoersWithout(host.sudoers, "NOPASSWD")
+
+	case "users.sudo_reauthentication":
+		return sudoersWithout(host.sudoers, "!authenticate")
+	}
+	return model.ComplianceNotApplicable, "not checked on this host"
+}
+
+// complianceState turns a check result into a state, with detail on failure
+func complianceState(passed bool, detail string) (string, string) {
+	if passed {
+		return model.CompliancePass, ""
+	}
+	return model.ComplianceFail, detail
+}
+
+// firstSetting returns the first value sshd -T reported for key
+func firstSetting(settings map[string][]string, key string) string {
+	if values := settings[key]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// sshSettingIs checks one effective sshd setting
+func sshSettingIs(settings map[string][]string, key, want, keyword string) (string, string) {
+	if settings == nil {
+		return model.ComplianceNotApplicable, "sshd is not installed"
+	}
+	value := firstSetting(settings, key)
+	return complianceState(value == want, keyword+" "+value)
+}
+
+// sudoersWithout fails when a sudoers line other than a comment contains
+// tag, listing each such line
+func sudoersWithout(sudoers map[string][]byte, tag string) (string, string) {
+	if len(sudoers) == 0 {
+		return model.ComplianceNotApplicable, "sudo is not configured"
+	}
+
+	paths := make([]string, 0, len(sudoers))
+	for path := range sudoers {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var offending []string
+	for _, path := range paths {
+		for _, line := range strings.Split(string(sudoers[path]), "\n") {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "#") || !strings.Contains(line, tag) {
+				continue
+			}
+			offending = append(offending, path+": "+line)
+		}
+	}
+	return complianceState(len(offending) == 0, strings.Join(offending, "; "))
+}
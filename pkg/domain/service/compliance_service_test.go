@@ -0,0 +1,148 @@
+package service
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockComplianceRepository is a mock implementation of ComplianceRepository
+type MockComplianceRepository struct {
+	mock.Mock
+}
+
+func (m *MockComplianceRepository) CommandInstalled(name string) bool {
+	return m.Called(name).Bool(0)
+}
+
+func (m *MockComplianceRepository) SSHEffectiveConfig() (map[string][]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string][]string), args.Error(1)
+}
+
+func (m *MockComplianceRepository) FileMode(path string) (fs.FileMode, error) {
+	args := m.Called(path)
+	return args.Get(0).(fs.FileMode), args.Error(1)
+}
+
+func (m *MockComplianceRepository) ReadSudoers() (map[string][]byte, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string][]byte), args.Error(1)
+}
+
+// complianceStatuses maps each recommendation ID to its state
+func complianceStatuses(report *model.ComplianceReport) map[string]string {
+	statuses := make(map[string]string)
+	for _, result := range report.Results {
+		statuses[result.ID] = result.Status
+	}
+	return statuses
+}
+
+func TestComplianceServiceImpl_RunBenchmark(t *testing.T) {
+	mockRepo := new(MockComplianceRepository)
+	mockRepo.On("SSHEffectiveConfig").Return(map[string][]string{
+		"port":                  {"22"},
+		"maxauthtries":          {"6"},
+		"permitemptypasswords":  {"no"},
+		"permituserenvironment": {"no"},
+		"allowusers":            {"george"},
+	}, nil)
+	mockRepo.On("ReadSudoers").Return(map[string][]byte{
+		"/etc/sudoers":          []byte("# Defaults !authenticate\nroot ALL=(ALL:ALL) ALL\n"),
+		"/etc/sudoers.d/deploy": []byte("deploy ALL=(ALL) NOPASSWD: ALL\n"),
+	}, nil)
+	mockRepo.On("CommandInstalled", "ufw").Return(true)
+	mockRepo.On("FileMode", "/etc/ssh/sshd_config").Return(fs.FileMode(0644), nil)
+
+	service := NewComplianceServiceImpl(mockRepo)
+	now := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	report, err := service.RunBenchmark(model.ComplianceRequest{
+		Hostname: "web1",
+		OSType:   "ubuntu",
+		Controls: []model.AuditControl{
+			{ID: "firewall.enabled", Status: model.ControlPass},
+			{ID: "firewall.default_deny", Status: model.ControlFail},
+			{ID: "ssh.root_login_disabled", Status: model.ControlPass},
+			{ID: "users.sudo_installed", Status: model.ControlPass},
+			{ID: "system.time_sync", Status: model.ControlPass},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, model.BenchmarkCISUbuntu, report.Benchmark)
+	assert.Equal(t, "web1", report.Hostname)
+	assert.Equal(t, now, report.GeneratedAt)
+	assert.Equal(t, map[string]string{
+		"1.3.1.2": model.ComplianceNotApplicable, // no AppArmor control on this host
+		"2.3.1.1": model.CompliancePass,
+		"4.2.1":   model.CompliancePass,
+		"4.2.3":   model.CompliancePass,
+		"4.2.7":   model.ComplianceFail,
+		"5.1.1":   model.ComplianceFail,
+		"5.1.4":   model.CompliancePass,
+		"5.1.16":  model.ComplianceFail,
+		"5.1.19":  model.CompliancePass,
+		"5.1.20":  model.CompliancePass,
+		"5.1.21":  model.CompliancePass,
+		"5.2.1":   model.CompliancePass,
+		"5.2.4":   model.ComplianceFail,
+		"5.2.5":   model.CompliancePass,
+	}, complianceStatuses(report))
+
+	assert.Equal(t, 9, report.Passed)
+	assert.Equal(t, 4, report.Failed)
+	assert.Equal(t, 1, report.NotApplicable)
+	assert.Equal(t, 69, report.Score)
+
+	details := make(map[string]string)
+	for _, result := range report.Results {
+		details[result.ID] = result.Detail
+	}
+	assert.Equal(t, "/etc/ssh/sshd_config has mode 0644", details["5.1.1"])
+	assert.Equal(t, "MaxAuthTries 6", details["5.1.16"])
+	assert.Equal(t, "/etc/sudoers.d/deploy: deploy ALL=(ALL) NOPASSWD: ALL", details["5.2.4"])
+	mockRepo.AssertExpectations(t)
+}
+
+func TestComplianceServiceImpl_RunBenchmark_WithoutSSHAndSudo(t *testing.T) {
+	mockRepo := new(MockComplianceRepository)
+	mockRepo.On("SSHEffectiveConfig").Return(nil, nil)
+	mockRepo.On("ReadSudoers").Return(map[string][]byte{}, nil)
+	mockRepo.On("CommandInstalled", "ufw").Return(false)
+
+	report, err := NewComplianceServiceImpl(mockRepo).RunBenchmark(model.ComplianceRequest{OSType: "debian"})
+	require.NoError(t, err)
+
+	assert.Equal(t, model.BenchmarkCISDebian, report.Benchmark)
+	statuses := complianceStatuses(report)
+	assert.Equal(t, model.ComplianceFail, statuses["4.2.1"])
+	for _, id := range []string{"5.1.1", "5.1.4", "5.1.16", "5.1.19", "5.1.21", "5.2.4", "5.2.5"} {
+		assert.Equal(t, model.ComplianceNotApplicable, statuses[id], id)
+	}
+	assert.Equal(t, 0, report.Score)
+}
+
+func TestComplianceServiceImpl_RunBenchmark_Errors(t *testing.T) {
+	_, err := NewComplianceServiceImpl(new(MockComplianceRepository)).RunBenchmark(model.ComplianceRequest{OSType: "alpine"})
+	assert.ErrorContains(t, err, "Debian and Ubuntu")
+
+	mockRepo := new(MockComplianceRepository)
+	mockRepo.On("SSHEffectiveConfig").Return(nil, errors.New("sshd -T: permission denied"))
+	_, err = NewComplianceServiceImpl(mockRepo).RunBenchmark(model.ComplianceRequest{OSType: "debian"})
+	assert.ErrorContains(t, err, "permission denied")
+}
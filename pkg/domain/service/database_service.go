@@ -0,0 +1,100 @@
+// pkg/domain/service/database_service.go
+package service
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// DatabaseService defines operations for hardening database servers
+type DatabaseService interface {
+	// DetectDatabases returns the supported database servers on the host
+	DetectDatabases() ([]model.DatabaseServer, error)
+
+	// HardenDatabases applies the baseline to every detected database server
+	HardenDatabases(settings model.DatabaseHardening) ([]model.DatabaseServer, error)
+}
+
+// DatabaseServiceImpl implements DatabaseService
+type DatabaseServiceImpl struct {
+	repository DatabaseRepository
+}
+
+// NewDatabaseServiceImpl creates a new DatabaseServiceImpl
+func NewDatabaseServiceImpl(repository DatabaseRepository) *DatabaseServiceImpl {
+	return &DatabaseServiceImpl{
+		repository: repository,
+	}
+}
+
+// DatabaseRepository defines the repository operations needed by DatabaseService
+type DatabaseRepository interface {
+	DetectDatabases() ([]model.DatabaseServer, error)
+	WriteDatabaseBaseline(server model.DatabaseServer, settings model.DatabaseHardening) error
+	RemoveDatabaseBaseline(server model.DatabaseServer) error
+	ValidateDatabaseConfig(server model.DatabaseServer) (string, error)
+	RestartDatabase(server model.DatabaseServer) error
+}
+
+func (s *DatabaseServiceImpl) DetectDatabases() ([]model.DatabaseServer, error) {
+	return s.repository.DetectDatabases()
+}
+
+func (s *DatabaseServiceImpl) HardenDatabases(settings model.DatabaseHardening) ([]model.DatabaseServer, error) {
+	if len(settings.BindAddresses) == 0 {
+		settings.BindAddresses = []string{"127.0.0.1"}
+	}
+
+	for _, address := range settings.BindAddresses {
+		if net.ParseIP(address) == nil {
+			return nil, fmt.Errorf("invalid database bind address: %s", address)
+		}
+	}
+	for _, subnet := range settings.AllowedSubnets {
+		if _, _, err := net.ParseCIDR(subnet); err != nil && net.ParseIP(subnet) == nil {
+			return nil, fmt.Errorf("invalid database subnet: %s", subnet)
+		}
+	}
+
+	servers, err := s.repository.DetectDatabases()
+	if err != nil {
+		return nil, err
+	}
+
+	var hardened []model.DatabaseServer
+	for _, server := range servers {
+		if err := s.hardenDatabase(server, settings); err != nil {
+			return hardened, err
+		}
+		server.BaselineApplied = true
+		hardened = append(hardened, server)
+	}
+
+	return hardened, nil
+}
+
+// hardenDatabase writes the drop-in and restarts the server only once the
+// configuration is accepted; a rejected drop-in is removed again
+func (s *DatabaseServiceImpl) hardenDatabase(server model.DatabaseServer, settings model.DatabaseHardening) error {
+	if err := s.repository.WriteDatabaseBaseline(server, settings); err != nil {
+		return fmt.Errorf("failed to write %s baseline: %w", server.Name, err)
+	}
+
+	if output, err := s.repository.ValidateDatabaseConfig(server); err != nil {
+		if removeErr := s.repository.RemoveDatabaseBaseline(server); removeErr != nil {
+			return fmt.Errorf("%s rejected the baseline (%v) and it could not be removed: %w",
+				server.Name, err, removeErr)
+		}
+		return fmt.Errorf("%s rejected the baseline; it was removed again: %v\n%s",
+			server.Name, err, strings.TrimSpace(output))
+	}
+
+	if err := s.repository.RestartDatabase(server); err != nil {
+		return fmt.Errorf("failed to restart %s: %w", server.Name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDatabaseRepository is a mock implementation of DatabaseRepository
+type MockDatabaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockDatabaseRepository) DetectDatabases() ([]model.DatabaseServer, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.DatabaseServer), args.Error(1)
+}
+
+func (m *MockDatabaseRepository) WriteDatabaseBaseline(server model.DatabaseServer, settings model.DatabaseHardening) error {
+	return m.Called(server, settings).Error(0)
+}
+
+func (m *MockDatabaseRepository) RemoveDatabaseBaseline(server model.DatabaseServer) error {
+	return m.Called(server).Error(0)
+}
+
+func (m *MockDatabaseRepository) ValidateDatabaseConfig(server model.DatabaseServer) (string, error) {
+	args := m.Called(server)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockDatabaseRepository) RestartDatabase(server model.DatabaseServer) error {
+	return m.Called(server).Error(0)
+}
+
+func TestDatabaseServiceImpl_HardenDatabases(t *testing.T) {
+	postgres := model.DatabaseServer{Name: model.DatabasePostgreSQL, Instance: "15/main", Port: 5432}
+	mysql := model.DatabaseServer{Name: model.DatabaseMySQL, Flavor: "mariadb", Port: 3306}
+
+	t.Run("defaults to loopback", func(t *testing.T) {
+		loopback := model.DatabaseHardening{BindAddresses: []string{"127.0.0.1"}}
+
+		mockRepo := new(MockDatabaseRepository)
+		mockRepo.On("DetectDatabases").Return([]model.DatabaseServer{postgres, mysql}, nil)
+		mockRepo.On("WriteDatabaseBaseline", mock.Anything, loopback).Return(nil)
+		mockRepo.On("ValidateDatabaseConfig", mock.Anything).Return("", nil)
+		mockRepo.On("RestartDatabase", mock.Anything).Return(nil)
+
+		hardened, err := NewDatabaseServiceImpl(mockRepo).HardenDatabases(model.DatabaseHardening{})
+
+		assert.NoError(t, err)
+		assert.Len(t, hardened, 2)
+		assert.True(t, hardened[1].BaselineApplied)
+		mockRepo.AssertNumberOfCalls(t, "RestartDatabase", 2)
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		mockRepo := new(MockDatabaseRepository)
+		databaseService := NewDatabaseServiceImpl(mockRepo)
+
+		_, err := databaseService.HardenDatabases(model.DatabaseHardening{BindAddresses: []string{"db.internal"}})
+		assert.Error(t, err)
+
+		_, err = databaseService.HardenDatabases(model.DatabaseHardening{AllowedSubnets: []string{"10.0.0.0/33"}})
+		assert.Error(t, err)
+
+		mockRepo.AssertNotCalled(t, "DetectDatabases")
+	})
+
+	t.Run("rejected drop-in is removed", func(t *testing.T) {
+		mockRepo := new(MockDatabaseRepository)
+		mockRepo.On("DetectDatabases").Return([]model.DatabaseServer{postgres}, nil)
+		mockRepo.On("WriteDatabaseBaseline", postgres, mock.Anything).Return(nil)
+		mockRepo.On("ValidateDatabaseConfig", postgres).Return("conf.d/hardn.conf:2: invalid value", errors.New("invalid settings"))
+		mockRepo.On("RemoveDatabaseBaseline", postgres).Return(nil)
+
+		_, err := NewDatabaseServiceImpl(mockRepo).HardenDatabases(model.DatabaseHardening{})
+
+		assert.Error(t, err)
+		mockRepo.AssertCalled(t, "RemoveDatabaseBaseline", postgres)
+		mockRepo.AssertNotCalled(t, "RestartDatabase", mock.Anything)
+	})
+}
@@ -0,0 +1,88 @@
+// pkg/domain/service/debloat_service.go
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// DebloatService defines operations for finding and removing insecure or
+// unneeded packages and services
+type DebloatService interface {
+	// FindInsecurePackages returns the items installed or running here
+	FindInsecurePackages(items []model.DebloatItem) ([]model.DebloatFinding, error)
+
+	// RemoveInsecurePackage removes a finding's packages, or disables its
+	// services when other packages depend on them
+	RemoveInsecurePackage(finding model.DebloatFinding) error
+}
+
+// DebloatServiceImpl implements DebloatService
+type DebloatServiceImpl struct {
+	repository DebloatRepository
+}
+
+// NewDebloatServiceImpl creates a new DebloatServiceImpl
+func NewDebloatServiceImpl(repository DebloatRepository) *DebloatServiceImpl {
+	return &DebloatServiceImpl{
+		repository: repository,
+	}
+}
+
+// DebloatRepository defines the repository operations needed by DebloatService
+type DebloatRepository interface {
+	InstalledPackages(names []string) ([]string, error)
+	RunningServices(names []string) ([]string, error)
+	RemovalDependents(packages []string) ([]string, error)
+	RemovePackages(packages []string) error
+	DisableService(name string) error
+}
+
+func (s *DebloatServiceImpl) FindInsecurePackages(items []model.DebloatItem) ([]model.DebloatFinding, error) {
+	var findings []model.DebloatFinding
+	for _, item := range items {
+		installed, err := s.repository.InstalledPackages(item.Packages)
+		if err != nil {
+			return nil, err
+		}
+		running, err := s.repository.RunningServices(item.Services)
+		if err != nil {
+			return nil, err
+		}
+		if len(installed) == 0 && len(running) == 0 {
+			continue
+		}
+
+		finding := model.DebloatFinding{Item: item, Installed: installed, Running: running}
+		if len(installed) > 0 {
+			if finding.Dependents, err = s.repository.RemovalDependents(installed); err != nil {
+				return nil, err
+			}
+		}
+		findings = append(findings, finding)
+	}
+	return findings, nil
+}
+
+// RemoveInsecurePackage stops the services before removing the packages,
+// so nothing keeps running from deleted files. A finding whose packages
+// other packages depend on, and which has no service, is refused.
+func (s *DebloatServiceImpl) RemoveInsecurePackage(finding model.DebloatFinding) error {
+	action := finding.Action()
+	if action == model.DebloatManual {
+		return fmt.Errorf("removing %s would also remove %s; remove it by hand",
+			finding.Item.Name, strings.Join(finding.Dependents, ", "))
+	}
+
+	for _, service := range finding.Running {
+		if err := s.repository.DisableService(service); err != nil {
+			return err
+		}
+	}
+	if action == model.DebloatRemove {
+		return s.repository.RemovePackages(finding.Installed)
+	}
+	return nil
+}
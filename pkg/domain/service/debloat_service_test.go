@@ -0,0 +1,110 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockDebloatRepository is a mock implementation of DebloatRepository
+type MockDebloatRepository struct {
+	mock.Mock
+}
+
+func (m *MockDebloatRepository) InstalledPackages(names []string) ([]string, error) {
+	args := m.Called(names)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockDebloatRepository) RunningServices(names []string) ([]string, error) {
+	args := m.Called(names)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockDebloatRepository) RemovalDependents(packages []string) ([]string, error) {
+	args := m.Called(packages)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockDebloatRepository) RemovePackages(packages []string) error {
+	return m.Called(packages).Error(0)
+}
+
+func (m *MockDebloatRepository) DisableService(name string) error {
+	return m.Called(name).Error(0)
+}
+
+func TestDebloatServiceImpl_FindInsecurePackages(t *testing.T) {
+	telnet := model.FindDebloatItem("telnet")
+	cups := model.FindDebloatItem("cups")
+	nis := model.FindDebloatItem("nis")
+
+	mockRepo := new(MockDebloatRepository)
+	mockRepo.On("InstalledPackages", telnet.Packages).Return([]string{"telnetd"}, nil)
+	mockRepo.On("RunningServices", telnet.Services).Return([]string{"telnet.socket"}, nil)
+	mockRepo.On("RemovalDependents", []string{"telnetd"}).Return(nil, nil)
+	mockRepo.On("InstalledPackages", cups.Packages).Return([]string{"cups", "cups-daemon"}, nil)
+	mockRepo.On("RunningServices", cups.Services).Return([]string{"cups"}, nil)
+	mockRepo.On("RemovalDependents", []string{"cups", "cups-daemon"}).Return([]string{"printer-driver-gutenprint"}, nil)
+	mockRepo.On("InstalledPackages", nis.Packages).Return(nil, nil)
+	mockRepo.On("RunningServices", nis.Services).Return(nil, nil)
+
+	service := NewDebloatServiceImpl(mockRepo)
+	findings, err := service.FindInsecurePackages([]model.DebloatItem{telnet, cups, nis})
+
+	require.NoError(t, err)
+	require.Len(t, findings, 2, "items neither installed nor running are left out")
+	assert.Equal(t, model.DebloatRemove, findings[0].Action())
+	assert.Equal(t, model.DebloatDisable, findings[1].Action(), "packages other packages need are only disabled")
+	assert.Equal(t, "packages.insecure.cups", findings[1].Violation().Rule)
+	mockRepo.AssertNotCalled(t, "RemovalDependents", []string(nil))
+}
+
+func TestDebloatServiceImpl_RemoveInsecurePackage(t *testing.T) {
+	mockRepo := new(MockDebloatRepository)
+	mockRepo.On("DisableService", "telnet.socket").Return(nil)
+	mockRepo.On("RemovePackages", []string{"telnetd"}).Return(nil)
+	service := NewDebloatServiceImpl(mockRepo)
+
+	// Services are stopped before their packages go
+	err := service.RemoveInsecurePackage(model.DebloatFinding{
+		Item:      model.FindDebloatItem("telnet"),
+		Installed: []string{"telnetd"},
+		Running:   []string{"telnet.socket"},
+	})
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	// With dependents only the services are disabled
+	mockRepo = new(MockDebloatRepository)
+	mockRepo.On("DisableService", "cups").Return(nil)
+	service = NewDebloatServiceImpl(mockRepo)
+	err = service.RemoveInsecurePackage(model.DebloatFinding{
+		Item:       model.FindDebloatItem("cups"),
+		Installed:  []string{"cups"},
+		Running:    []string{"cups"},
+		Dependents: []string{"printer-driver-gutenprint"},
+	})
+	require.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "RemovePackages", mock.Anything)
+
+	// Without a service to disable, the finding is left to the administrator
+	err = service.RemoveInsecurePackage(model.DebloatFinding{
+		Item:       model.FindDebloatItem("avahi"),
+		Installed:  []string{"avahi-daemon"},
+		Dependents: []string{"gnome-shell"},
+	})
+	assert.ErrorContains(t, err, "would also remove gnome-shell")
+}
@@ -1,7 +1,12 @@
 // pkg/domain/service/dns_service.go
 package service
 
-import "github.com/abbott/hardn/pkg/domain/model"
+import (
+	"fmt"
+	"net"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
 
 // DNSService defines operations for DNS configuration
 type DNSService interface {
@@ -40,3 +45,12 @@ func (s *DNSServiceImpl) ConfigureDNS(config model.DNSConfig) error {
 func (s *DNSServiceImpl) GetCurrentConfig() (*model.DNSConfig, error) {
 	return s.repository.GetDNSConfig()
 }
+
+// ValidateNameserver checks that a nameserver is an IPv4 or IPv6 address;
+// resolv.conf does not accept host names
+func ValidateNameserver(addr string) error {
+	if net.ParseIP(addr) == nil {
+		return fmt.Errorf("invalid nameserver %q: not an IP address", addr)
+	}
+	return nil
+}
@@ -237,3 +237,16 @@ func TestDNSServiceImpl_OSTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateNameserver(t *testing.T) {
+	for _, addr := range []string{"1.1.1.1", "2606:4700:4700::1111"} {
+		if err := ValidateNameserver(addr); err != nil {
+			t.Errorf("Expected %q to be valid, got: %v", addr, err)
+		}
+	}
+	for _, addr := range []string{"", "dns.google", "1.1.1.1:53", "300.1.1.1"} {
+		if err := ValidateNameserver(addr); err == nil {
+			t.Errorf("Expected %q to be rejected", addr)
+		}
+	}
+}
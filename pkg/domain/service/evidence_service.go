@@ -0,0 +1,263 @@
+// pkg/domain/service/evidence_service.go
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// EvidenceService defines operations for building evidence bundles
+type EvidenceService interface {
+	// LoadTemplate returns a built-in template by name, or reads one from a
+	// YAML file
+	LoadTemplate(nameOrPath string) (model.EvidenceTemplate, error)
+
+	// CreateBundle collects the evidence the request's template selects and
+	// writes it to a bundle with a manifest
+	CreateBundle(request model.EvidenceRequest) (*model.EvidenceBundle, error)
+}
+
+// EvidenceServiceImpl implements EvidenceService
+type EvidenceServiceImpl struct {
+	repository EvidenceRepository
+	now        func() time.Time
+}
+
+// NewEvidenceServiceImpl creates a new EvidenceServiceImpl
+func NewEvidenceServiceImpl(repository EvidenceRepository) *EvidenceServiceImpl {
+	return &EvidenceServiceImpl{
+		repository: repository,
+		now:        time.Now,
+	}
+}
+
+// EvidenceRepository defines the repository operations needed by EvidenceService
+type EvidenceRepository interface {
+	ReadFile(path string) ([]byte, error)
+	ListBackups(dir string) ([]model.EvidenceBackup, error)
+	LoadTemplate(path string) (model.EvidenceTemplate, error)
+	WriteBundle(path string, items []model.EvidenceItem) error
+	EncryptBundle(path string, recipient string) (string, error)
+}
+
+// evidenceKinds are the kinds a template section can include
+var evidenceKinds = map[string]bool{
+	model.EvidenceAudit:   true,
+	model.EvidenceConfig:  true,
+	model.EvidenceBackups: true,
+	model.EvidenceRuns:    true,
+}
+
+func (s *EvidenceServiceImpl) LoadTemplate(nameOrPath string) (model.EvidenceTemplate, error) {
+	template, ok := model.LookupEvidenceTemplate(nameOrPath)
+	if !ok {
+		var err error
+		if template, err = s.repository.LoadTemplate(nameOrPath); err != nil {
+			return model.EvidenceTemplate{}, err
+		}
+	}
+	if err := validateEvidenceTemplate(template); err != nil {
+		return model.EvidenceTemplate{}, err
+	}
+	return template, nil
+}
+
+// validateEvidenceTemplate checks that a template has sections and that
+// each include names a known kind; only configuration files have areas
+func validateEvidenceTemplate(template model.EvidenceTemplate) error {
+	if len(template.Sections) == 0 {
+		return fmt.Errorf("evidence template %q has no sections", template.Name)
+	}
+	for _, section := range template.Sections {
+		if section.ID == "" {
+			return fmt.Errorf("evidence template %q has a section without an id", template.Name)
+		}
+		for _, include := range section.Include {
+			kind, area := splitEvidenceSelector(include)
+			if !evidenceKinds[kind] || (area != "" && kind != model.EvidenceConfig) {
+				return fmt.Errorf("evidence template %q: section %s includes unknown evidence %q",
+					template.Name, section.ID, include)
+			}
+		}
+	}
+	return nil
+}
+
+// splitEvidenceSelector splits "config:ssh" into its kind and area
+func splitEvidenceSelector(selector string) (string, string) {
+	kind, area, _ := strings.Cut(strings.TrimSpace(selector), ":")
+	return kind, area
+}
+
+// selectsEvidence reports whether an include selects an item
+func selectsEvidence(selector string, item model.EvidenceItem) bool {
+	kind, area := splitEvidenceSelector(selector)
+	return kind == item.Kind && (area == "" || area == item.Area)
+}
+
+func (s *EvidenceServiceImpl) CreateBundle(request model.EvidenceRequest) (*model.EvidenceBundle, error) {
+	if err := validateEvidenceTemplate(request.Template); err != nil {
+		return nil, err
+	}
+
+	kinds := make(map[string]bool)
+	for _, section := range request.Template.Sections {
+		for _, include := range section.Include {
+			kind, _ := splitEvidenceSelector(include)
+			kinds[kind] = true
+		}
+	}
+
+	collected, err := s.collect(request, kinds)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sections list the evidence they select; only selected evidence is
+	// bundled, and evidence shared by sections is bundled once
+	manifest := model.EvidenceManifest{
+		Format:       model.EvidenceFormat,
+		HardnVersion: request.HardnVersion,
+		Hostname:     request.Hostname,
+		GeneratedAt:  s.now(),
+		Template:     request.Template.Name,
+	}
+	selected := make(map[string]bool)
+	for _, section := range request.Template.Sections {
+		section.Files = []string{}
+		for _, item := range collected {
+			for _, include := range section.Include {
+				if selectsEvidence(include, item) {
+					section.Files = append(section.Files, item.Path)
+					selected[item.Path] = true
+					break
+				}
+			}
+		}
+		manifest.Sections = append(manifest.Sections, section)
+	}
+
+	items := []model.EvidenceItem{}
+	for _, item := range collected {
+		if !selected[item.Path] {
+			continue
+		}
+		sum := sha256.Sum256(item.Data)
+		manifest.Files = append(manifest.Files, model.EvidenceFile{
+			Path:   item.Path,
+			Kind:   item.Kind,
+			Area:   item.Area,
+			Source: item.Source,
+			Size:   int64(len(item.Data)),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+		items = append(items, item)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode evidence manifest: %w", err)
+	}
+	items = append([]model.EvidenceItem{{Path: model.EvidenceManifestName, Data: data}}, items...)
+
+	if err := s.repository.WriteBundle(request.Output, items); err != nil {
+		return nil, err
+	}
+
+	bundle := &model.EvidenceBundle{Path: request.Output, Manifest: manifest}
+	if request.Recipient != "" {
+		encrypted, err := s.repository.EncryptBundle(request.Output, request.Recipient)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Path = encrypted
+		bundle.Encrypted = true
+	}
+	return bundle, nil
+}
+
+// collect gathers the evidence of the given kinds, in bundle order
+func (s *EvidenceServiceImpl) collect(request model.EvidenceRequest, kinds map[string]bool) ([]model.EvidenceItem, error) {
+	var items []model.EvidenceItem
+
+	if kinds[model.EvidenceAudit] && request.AuditReport != nil {
+		data, err := json.MarshalIndent(request.AuditReport, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode audit report: %w", err)
+		}
+		items = append(items, model.EvidenceItem{
+			Path: "audit/report.json",
+			Kind: model.EvidenceAudit,
+			Data: data,
+		})
+	}
+
+	if kinds[model.EvidenceConfig] {
+		seen := make(map[string]bool)
+		for _, file := range request.ConfigFiles {
+			if file.Path == "" || seen[file.Path] {
+				continue
+			}
+			seen[file.Path] = true
+
+			data, err := s.repository.ReadFile(file.Path)
+			if err != nil {
+				return nil, err
+			}
+			if data == nil {
+				continue
+			}
+			items = append(items, model.EvidenceItem{
+				Path:   path.Join(model.EvidenceConfig, path.Clean("/"+file.Path)),
+				Kind:   model.EvidenceConfig,
+				Area:   file.Area,
+				Source: file.Path,
+				Data:   data,
+			})
+		}
+	}
+
+	if kinds[model.EvidenceBackups] && request.BackupDir != "" {
+		backups, err := s.repository.ListBackups(request.BackupDir)
+		if err != nil {
+			return nil, err
+		}
+		if backups == nil {
+			backups = []model.EvidenceBackup{}
+		}
+		data, err := json.MarshalIndent(backups, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode backup list: %w", err)
+		}
+		items = append(items, model.EvidenceItem{
+			Path:   "backups/manifest.json",
+			Kind:   model.EvidenceBackups,
+			Source: request.BackupDir,
+			Data:   data,
+		})
+	}
+
+	if kinds[model.EvidenceRuns] {
+		for _, run := range request.Runs {
+			data, err := json.MarshalIndent(run, "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode run %s: %w", run.ID, err)
+			}
+			items = append(items, model.EvidenceItem{
+				Path:   path.Join(model.EvidenceRuns, run.ID+".json"),
+				Kind:   model.EvidenceRuns,
+				Source: path.Join(model.RunsDir, run.ID),
+				Data:   data,
+			})
+		}
+	}
+
+	return items, nil
+}
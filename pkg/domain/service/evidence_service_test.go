@@ -0,0 +1,171 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockEvidenceRepository is a mock implementation of EvidenceRepository
+type MockEvidenceRepository struct {
+	mock.Mock
+	written []model.EvidenceItem
+}
+
+func (m *MockEvidenceRepository) ReadFile(path string) ([]byte, error) {
+	args := m.Called(path)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockEvidenceRepository) ListBackups(dir string) ([]model.EvidenceBackup, error) {
+	args := m.Called(dir)
+	return args.Get(0).([]model.EvidenceBackup), args.Error(1)
+}
+
+func (m *MockEvidenceRepository) LoadTemplate(path string) (model.EvidenceTemplate, error) {
+	args := m.Called(path)
+	return args.Get(0).(model.EvidenceTemplate), args.Error(1)
+}
+
+func (m *MockEvidenceRepository) WriteBundle(path string, items []model.EvidenceItem) error {
+	m.written = items
+	return m.Called(path).Error(0)
+}
+
+func (m *MockEvidenceRepository) EncryptBundle(path string, recipient string) (string, error) {
+	args := m.Called(path, recipient)
+	return args.String(0), args.Error(1)
+}
+
+func newEvidenceRequest(template model.EvidenceTemplate) model.EvidenceRequest {
+	return model.EvidenceRequest{
+		Output:       "/root/evidence.tar.gz",
+		Hostname:     "web1",
+		HardnVersion: "1.2.3",
+		Template:     template,
+		AuditReport:  &model.AuditReport{Format: model.AuditReportFormat, Hostname: "web1"},
+		ConfigFiles: []model.EvidenceConfigFile{
+			{Path: "/etc/ssh/sshd_config", Area: model.AreaSSH},
+			{Path: "/etc/ufw/user.rules", Area: model.AreaFirewall},
+			{Path: "/etc/sudoers", Area: model.AreaUsers},
+			{Path: "/etc/ssh/sshd_config", Area: model.AreaSSH},
+		},
+		BackupDir: "/var/backups/hardn",
+		Runs:      []model.RunManifest{{ID: "20260101-120000", Description: "hardn ssh set"}},
+	}
+}
+
+func TestEvidenceServiceImpl_CreateBundle(t *testing.T) {
+	mockRepo := new(MockEvidenceRepository)
+	mockRepo.On("ReadFile", "/etc/ssh/sshd_config").Return([]byte("PermitRootLogin no\n"), nil).Once()
+	mockRepo.On("ReadFile", "/etc/ufw/user.rules").Return([]byte("*filter\n"), nil)
+	mockRepo.On("ReadFile", "/etc/sudoers").Return(nil, nil)
+	mockRepo.On("ListBackups", "/var/backups/hardn").Return([]model.EvidenceBackup{
+		{Path: "/var/backups/hardn/2026-01-01/sshd_config.120000.bak", Size: 10, SHA256: "abc"},
+	}, nil)
+	mockRepo.On("WriteBundle", "/root/evidence.tar.gz").Return(nil)
+
+	generated := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	evidenceService := NewEvidenceServiceImpl(mockRepo)
+	evidenceService.now = func() time.Time { return generated }
+
+	template, ok := model.LookupEvidenceTemplate("default")
+	require.True(t, ok)
+	bundle, err := evidenceService.CreateBundle(newEvidenceRequest(template))
+	require.NoError(t, err)
+
+	assert.Equal(t, "/root/evidence.tar.gz", bundle.Path)
+	assert.False(t, bundle.Encrypted)
+	assert.Equal(t, generated, bundle.Manifest.GeneratedAt)
+	assert.Equal(t, "default", bundle.Manifest.Template)
+
+	var paths []string
+	for _, item := range mockRepo.written {
+		paths = append(paths, item.Path)
+	}
+	assert.Equal(t, []string{
+		model.EvidenceManifestName,
+		"audit/report.json",
+		"config/etc/ssh/sshd_config",
+		"config/etc/ufw/user.rules",
+		"backups/manifest.json",
+		"runs/20260101-120000.json",
+	}, paths)
+
+	// The manifest in the bundle lists every other file with its checksum
+	var manifest model.EvidenceManifest
+	require.NoError(t, json.Unmarshal(mockRepo.written[0].Data, &manifest))
+	require.Len(t, manifest.Files, 5)
+	assert.Equal(t, "/etc/ssh/sshd_config", manifest.Files[1].Source)
+	assert.Equal(t, model.AreaSSH, manifest.Files[1].Area)
+	assert.Equal(t, int64(19), manifest.Files[1].Size)
+	assert.Len(t, manifest.Files[1].SHA256, 64)
+	require.Len(t, manifest.Sections, 3)
+	assert.Equal(t, []string{"backups/manifest.json", "runs/20260101-120000.json"}, manifest.Sections[2].Files)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestEvidenceServiceImpl_CreateBundle_TemplateSelectsEvidence(t *testing.T) {
+	mockRepo := new(MockEvidenceRepository)
+	mockRepo.On("ReadFile", "/etc/ssh/sshd_config").Return([]byte("PermitRootLogin no\n"), nil)
+	mockRepo.On("ReadFile", "/etc/ufw/user.rules").Return([]byte("*filter\n"), nil)
+	mockRepo.On("ReadFile", "/etc/sudoers").Return([]byte("root ALL=(ALL) ALL\n"), nil)
+	mockRepo.On("WriteBundle", "/root/evidence.tar.gz").Return(nil)
+	mockRepo.On("EncryptBundle", "/root/evidence.tar.gz", "auditor@example.com").
+		Return("/root/evidence.tar.gz.gpg", nil)
+
+	template := model.EvidenceTemplate{Name: "ssh-only", Sections: []model.EvidenceSection{
+		{ID: "8.3", Title: "Strong authentication", Include: []string{"config:ssh"}},
+		{ID: "8.3.1", Title: "Authentication factors", Include: []string{"config:ssh"}},
+	}}
+	request := newEvidenceRequest(template)
+	request.Recipient = "auditor@example.com"
+
+	bundle, err := NewEvidenceServiceImpl(mockRepo).CreateBundle(request)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/root/evidence.tar.gz.gpg", bundle.Path)
+	assert.True(t, bundle.Encrypted)
+	// Evidence shared by sections is bundled once; backups and runs are not
+	// collected at all
+	require.Len(t, mockRepo.written, 2)
+	assert.Equal(t, "config/etc/ssh/sshd_config", mockRepo.written[1].Path)
+	assert.Equal(t, []string{"config/etc/ssh/sshd_config"}, bundle.Manifest.Sections[1].Files)
+	mockRepo.AssertNotCalled(t, "ListBackups", mock.Anything)
+}
+
+func TestEvidenceServiceImpl_LoadTemplate(t *testing.T) {
+	mockRepo := new(MockEvidenceRepository)
+	mockRepo.On("LoadTemplate", "/etc/hardn/soc2.yml").Return(model.EvidenceTemplate{
+		Name:     "soc2",
+		Sections: []model.EvidenceSection{{ID: "CC6.1", Include: []string{"runs:ssh"}}},
+	}, nil)
+	mockRepo.On("LoadTemplate", "missing.yml").Return(model.EvidenceTemplate{}, errors.New("not found"))
+
+	evidenceService := NewEvidenceServiceImpl(mockRepo)
+
+	template, err := evidenceService.LoadTemplate("pci-dss")
+	require.NoError(t, err)
+	assert.Equal(t, "pci-dss", template.Name)
+
+	_, err = evidenceService.LoadTemplate("/etc/hardn/soc2.yml")
+	assert.ErrorContains(t, err, `unknown evidence "runs:ssh"`)
+
+	_, err = evidenceService.LoadTemplate("missing.yml")
+	assert.Error(t, err)
+
+	// Every built-in template is valid
+	for _, builtin := range model.EvidenceTemplates {
+		assert.NoError(t, validateEvidenceTemplate(builtin), builtin.Name)
+	}
+}
@@ -1,7 +1,27 @@
 // pkg/domain/service/firewall_service.go
 package service
 
-import "github.com/abbott/hardn/pkg/domain/model"
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// maxRuleCommentLength keeps rule comments readable in ufw's rule list
+const maxRuleCommentLength = 64
+
+// minKnockSequence is the shortest knock sequence accepted; shorter ones
+// are found by scanning
+const minKnockSequence = 3
+
+// interfaceNamePattern matches Linux network interface names
+var interfaceNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.@-]{1,15}$`)
 
 // FirewallService defines operations for firewall configuration
 type FirewallService interface {
@@ -18,6 +38,9 @@ type FirewallService interface {
 	// remove a firewall rule
 	RemoveRule(rule model.FirewallRule) error
 
+	// PreviewRule checks a rule and returns the command that adds it
+	PreviewRule(rule model.FirewallRule) (string, error)
+
 	// Add a firewall application profile
 	AddProfile(profile model.FirewallProfile) error
 
@@ -29,6 +52,41 @@ type FirewallService interface {
 
 	// disable the firewall
 	DisableFirewall() error
+
+	// install the firewall package
+	InstallFirewall() error
+
+	// write and enable firewall application profiles
+	ApplyProfiles(profiles []model.FirewallProfile) error
+
+	// CheckDualStack compares the traffic the firewall lets in over IPv4
+	// and IPv6. With the IPv6 deny mode the families differ on purpose,
+	// and only a family left unfiltered is reported.
+	CheckDualStack(ipv6Mode string) (*model.DualStackReport, error)
+
+	// MirrorDualStack filters a family the firewall leaves open and adds
+	// rules missing from one family to the other, then checks again
+	MirrorDualStack(ipv6Mode string) (*model.DualStackReport, error)
+
+	// ApplyIPv6Policy turns IPv6 off in the kernel for the disable mode,
+	// and back on for the other modes when hardn turned it off
+	ApplyIPv6Policy(policy model.IPv6Policy) error
+
+	// CheckIPv6 reports how other hosts reach this one over IPv6, compared
+	// with a declared firewall configuration
+	CheckIPv6(config model.FirewallConfig) (*model.IPv6Exposure, error)
+
+	// CheckDrift compares a declared firewall configuration with the live
+	// ruleset
+	CheckDrift(config model.FirewallConfig) (*model.FirewallDrift, error)
+
+	// ConfigurePortKnocking checks a knock sequence and has knockd open the
+	// SSH port on it; nil stops knockd
+	ConfigurePortKnocking(sshPort int, knocking *model.PortKnocking) error
+
+	// GetSSHAccess reports whether the live firewall rate-limits the SSH
+	// port and whether knockd guards it
+	GetSSHAccess(sshPort int) (*model.SSHAccess, error)
 }
 
 // implement FirewallService
@@ -52,9 +110,20 @@ type FirewallRepository interface {
 	GetFirewallConfig() (*model.FirewallConfig, error)
 	AddRule(rule model.FirewallRule) error
 	RemoveRule(rule model.FirewallRule) error
+	RuleCommand(rule model.FirewallRule) string
 	AddProfile(profile model.FirewallProfile) error
 	EnableFirewall() error
 	DisableFirewall() error
+	InstallFirewall() error
+	ApplyProfiles(profiles []model.FirewallProfile) error
+	GetStackRules() (model.StackRules, model.StackRules, error)
+	FilterFamily(family string) error
+	MirrorRule(rule string, family string) error
+	GetRules() ([]string, error)
+	ConfigurePortKnocking(sshPort int, knocking *model.PortKnocking) error
+	PortKnockingActive() bool
+	GetIPv6Kernel() (bool, bool)
+	SetIPv6Kernel(disabled bool, bootParameter bool) error
 }
 
 // GetFirewallStatus retrieves the current status of the firewall
@@ -75,6 +144,13 @@ func (s *FirewallServiceImpl) RemoveRule(rule model.FirewallRule) error {
 	return s.repository.RemoveRule(rule)
 }
 
+func (s *FirewallServiceImpl) PreviewRule(rule model.FirewallRule) (string, error) {
+	if err := ValidateRule(rule); err != nil {
+		return "", err
+	}
+	return s.repository.RuleCommand(rule), nil
+}
+
 func (s *FirewallServiceImpl) AddProfile(profile model.FirewallProfile) error {
 	return s.repository.AddProfile(profile)
 }
@@ -90,3 +166,385 @@ func (s *FirewallServiceImpl) EnableFirewall() error {
 func (s *FirewallServiceImpl) DisableFirewall() error {
 	return s.repository.DisableFirewall()
 }
+
+func (s *FirewallServiceImpl) InstallFirewall() error {
+	return s.repository.InstallFirewall()
+}
+
+func (s *FirewallServiceImpl) ApplyProfiles(profiles []model.FirewallProfile) error {
+	return s.repository.ApplyProfiles(profiles)
+}
+
+func (s *FirewallServiceImpl) CheckDualStack(ipv6Mode string) (*model.DualStackReport, error) {
+	ipv4, ipv6, err := s.repository.GetStackRules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read firewall rules: %w", err)
+	}
+	report := compareStacks(ipv4, ipv6)
+	if ipv6Mode == model.IPv6Deny {
+		report.Gaps = nil
+	}
+	return report, nil
+}
+
+func (s *FirewallServiceImpl) MirrorDualStack(ipv6Mode string) (*model.DualStackReport, error) {
+	report, err := s.CheckDualStack(ipv6Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rules only take effect on a family the firewall filters
+	if report.Unfiltered != "" {
+		if err := s.repository.FilterFamily(report.Unfiltered); err != nil {
+			return report, err
+		}
+		if report, err = s.CheckDualStack(ipv6Mode); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, gap := range report.Gaps {
+		if !gap.Mirrorable {
+			continue
+		}
+		if err := s.repository.MirrorRule(gap.Rule, gap.MissingOn); err != nil {
+			return report, err
+		}
+	}
+	return s.CheckDualStack(ipv6Mode)
+}
+
+func (s *FirewallServiceImpl) CheckDrift(config model.FirewallConfig) (*model.FirewallDrift, error) {
+	installed, enabled, configured, _, err := s.repository.GetFirewallStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get firewall status: %w", err)
+	}
+	if !installed {
+		return nil, fmt.Errorf("the firewall is not installed")
+	}
+	rules, err := s.repository.GetRules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read firewall rules: %w", err)
+	}
+	live := expandRuleSpecs(rules)
+
+	drift := &model.FirewallDrift{
+		Enabled:     enabled,
+		DefaultDeny: configured,
+		Declared:    declaredRules(config),
+	}
+	declared := make(map[string]bool)
+	for _, rule := range drift.Declared {
+		declared[rule] = true
+		if !slices.Contains(live, rule) {
+			drift.Missing = append(drift.Missing, rule)
+		}
+	}
+	for _, rule := range live {
+		if !declared[rule] {
+			drift.Extra = append(drift.Extra, rule)
+		}
+	}
+	return drift, nil
+}
+
+func (s *FirewallServiceImpl) ConfigurePortKnocking(sshPort int, knocking *model.PortKnocking) error {
+	if knocking != nil {
+		if err := ValidatePortKnocking(sshPort, *knocking); err != nil {
+			return err
+		}
+	}
+	return s.repository.ConfigurePortKnocking(sshPort, knocking)
+}
+
+func (s *FirewallServiceImpl) GetSSHAccess(sshPort int) (*model.SSHAccess, error) {
+	rules, err := s.repository.GetRules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read firewall rules: %w", err)
+	}
+	limit := fmt.Sprintf("limit %d/tcp", sshPort)
+	return &model.SSHAccess{
+		RateLimited:  slices.Contains(expandRuleSpecs(rules), limit),
+		PortKnocking: s.repository.PortKnockingActive(),
+	}, nil
+}
+
+func (s *FirewallServiceImpl) ApplyIPv6Policy(policy model.IPv6Policy) error {
+	if err := ValidateIPv6Policy(policy); err != nil {
+		return err
+	}
+	if policy.Mode == "" {
+		return nil
+	}
+	return s.repository.SetIPv6Kernel(policy.Mode == model.IPv6Disable, policy.BootParameter)
+}
+
+func (s *FirewallServiceImpl) CheckIPv6(config model.FirewallConfig) (*model.IPv6Exposure, error) {
+	_, ipv6, err := s.repository.GetStackRules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read firewall rules: %w", err)
+	}
+	enabled, bootDisabled := s.repository.GetIPv6Kernel()
+
+	exposure := &model.IPv6Exposure{
+		Mode:         config.IPv6,
+		Enabled:      enabled,
+		BootDisabled: bootDisabled,
+		Addressed:    ipv6.Active,
+		Filtered:     ipv6.Filtered,
+		Allowed:      ipv6.Allowed,
+	}
+	if config.IPv6 != model.IPv6Deny || !ipv6.Filtered {
+		return exposure, nil
+	}
+
+	// The rules the configuration keeps for IPv6 are the ones not limited
+	// to IPv4
+	var declared []string
+	for _, rule := range config.Rules {
+		if rule.AddressFamily() != model.FamilyIPv4 {
+			declared = append(declared, rule.Spec())
+		}
+	}
+	declared = expandRuleSpecs(declared)
+	for _, rule := range expandRuleSpecs(ipv6.Allowed) {
+		if !slices.Contains(declared, rule) {
+			exposure.Unexpected = append(exposure.Unexpected, rule)
+		}
+	}
+	return exposure, nil
+}
+
+// declaredRules lists the rules a configuration adds, in the form of
+// FirewallRule.Spec. Application profiles stand for their ports; a port
+// without a protocol is opened for tcp and udp.
+func declaredRules(config model.FirewallConfig) []string {
+	var specs []string
+	for _, rule := range config.Rules {
+		specs = append(specs, rule.Spec())
+	}
+	for _, profile := range config.ApplicationProfiles {
+		for _, entry := range profile.Ports {
+			specs = append(specs, "allow "+strings.ReplaceAll(strings.TrimSpace(entry), "-", ":"))
+		}
+	}
+	return expandRuleSpecs(specs)
+}
+
+// expandRuleSpecs rewrites rules such as "allow 80,443" into one rule per
+// port and protocol, "allow 80/tcp", "allow 80/udp" and so on, so rules
+// written by application profiles compare with single port rules
+func expandRuleSpecs(specs []string) []string {
+	var rules []string
+	add := func(rule string) {
+		if !slices.Contains(rules, rule) {
+			rules = append(rules, rule)
+		}
+	}
+
+	for _, spec := range specs {
+		fields := strings.Fields(spec)
+		if len(fields) < 2 {
+			add(spec)
+			continue
+		}
+		ports, protocol, found := strings.Cut(fields[1], "/")
+		protocols := []string{protocol}
+		if !found || protocol == "any" {
+			protocols = []string{"tcp", "udp"}
+		}
+		suffix := ""
+		if len(fields) > 2 {
+			suffix = " " + strings.Join(fields[2:], " ")
+		}
+		for _, port := range strings.Split(ports, ",") {
+			for _, protocol := range protocols {
+				add(fields[0] + " " + port + "/" + protocol + suffix)
+			}
+		}
+	}
+	return rules
+}
+
+// compareStacks finds the traffic one family lets in that the other does
+// not. Families are only compared on hosts with IPv6 addresses and a
+// firewall filtering at least one of them.
+func compareStacks(ipv4, ipv6 model.StackRules) *model.DualStackReport {
+	report := &model.DualStackReport{IPv4: ipv4, IPv6: ipv6}
+	if !ipv4.Active || !ipv6.Active || (!ipv4.Filtered && !ipv6.Filtered) {
+		return report
+	}
+
+	switch {
+	case !ipv6.Filtered:
+		report.Unfiltered = model.FamilyIPv6
+	case !ipv4.Filtered:
+		report.Unfiltered = model.FamilyIPv4
+	}
+
+	report.Gaps = append(stackGaps(ipv4, ipv6), stackGaps(ipv6, ipv4)...)
+	return report
+}
+
+// stackGaps returns the rules of a filtered family that other lacks. Against
+// an unfiltered family every rule is a gap, since what it limits is open
+// there. Rules limited to an address are otherwise skipped: the address
+// belongs to one family, so the other can not have the same rule.
+func stackGaps(from, other model.StackRules) []model.StackGap {
+	if !from.Filtered {
+		return nil
+	}
+
+	present := make(map[string]bool)
+	for _, rule := range other.Allowed {
+		present[rule] = true
+	}
+
+	var gaps []model.StackGap
+	seen := make(map[string]bool)
+	for _, rule := range from.Allowed {
+		addressed := strings.Contains(rule, " from ") || strings.Contains(rule, " to ")
+		if seen[rule] || (other.Filtered && (present[rule] || addressed)) {
+			continue
+		}
+		seen[rule] = true
+		gaps = append(gaps, model.StackGap{
+			Rule:       rule,
+			AllowedOn:  from.Family,
+			MissingOn:  other.Family,
+			Mirrorable: !addressed,
+		})
+	}
+	return gaps
+}
+
+// ParsePortSpec parses a port written as "8080" or "53/udp" into the port
+// number and protocol; the protocol defaults to tcp
+func ParsePortSpec(spec string) (int, string, error) {
+	portText, protocol, found := strings.Cut(strings.TrimSpace(spec), "/")
+	if !found {
+		protocol = "tcp"
+	}
+	protocol = strings.ToLower(protocol)
+	if protocol != "tcp" && protocol != "udp" {
+		return 0, "", fmt.Errorf("invalid protocol in %q (use tcp or udp)", spec)
+	}
+
+	port, err := strconv.Atoi(portText)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, "", fmt.Errorf("invalid port %q (use 1-65535)", spec)
+	}
+
+	return port, protocol, nil
+}
+
+// ParsePortRange parses a port written as "8080", or a range written as
+// "6000-6007" or "6000:6007", into its first and last port; the last port
+// is 0 for a single port
+func ParsePortRange(spec string) (int, int, error) {
+	spec = strings.TrimSpace(spec)
+	first, last, isRange := strings.Cut(strings.ReplaceAll(spec, ":", "-"), "-")
+
+	port, err := strconv.Atoi(first)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, 0, fmt.Errorf("invalid port %q (use 1-65535)", spec)
+	}
+	if !isRange {
+		return port, 0, nil
+	}
+
+	endPort, err := strconv.Atoi(last)
+	if err != nil || endPort < 1 || endPort > 65535 {
+		return 0, 0, fmt.Errorf("invalid port range %q (use 1-65535)", spec)
+	}
+	if endPort <= port {
+		return 0, 0, fmt.Errorf("invalid port range %q: the last port must be above the first", spec)
+	}
+	return port, endPort, nil
+}
+
+// ValidateRule checks every field of a rule before it is added: the
+// action, a tcp or udp port or range, the source, and a comment ufw can
+// store
+func ValidateRule(rule model.FirewallRule) error {
+	switch rule.Action {
+	case "allow", "deny", "reject":
+	default:
+		return fmt.Errorf("invalid action %q (use allow, deny or reject)", rule.Action)
+	}
+	if rule.Protocol != "tcp" && rule.Protocol != "udp" {
+		return fmt.Errorf("invalid protocol %q (use tcp or udp)", rule.Protocol)
+	}
+	if _, _, err := ParsePortRange(rule.Ports("-")); err != nil {
+		return err
+	}
+	if rule.SourceIP != "" {
+		if err := ValidateRuleSource(rule.SourceIP); err != nil {
+			return err
+		}
+	}
+	switch rule.Family {
+	case "", model.FamilyIPv4, model.FamilyIPv6:
+	default:
+		return fmt.Errorf("invalid family %q (use ipv4 or ipv6)", rule.Family)
+	}
+	if rule.Family != "" && rule.SourceIP != "" && strings.Contains(rule.SourceIP, ":") != (rule.Family == model.FamilyIPv6) {
+		return fmt.Errorf("source %s is not an %s address", rule.SourceIP, model.FamilyName(rule.Family))
+	}
+	if len(rule.Description) > maxRuleCommentLength {
+		return fmt.Errorf("comment is longer than %d characters", maxRuleCommentLength)
+	}
+	for _, r := range rule.Description {
+		if !unicode.IsPrint(r) || r == '\'' || r == '"' {
+			return fmt.Errorf("comment may not contain quotes or control characters")
+		}
+	}
+	return nil
+}
+
+// ValidatePortKnocking checks a knock sequence: at least three ports, none
+// repeated or the SSH port itself, and timeouts of a second or more
+func ValidatePortKnocking(sshPort int, knocking model.PortKnocking) error {
+	if len(knocking.Sequence) < minKnockSequence {
+		return fmt.Errorf("the knock sequence needs at least %d ports", minKnockSequence)
+	}
+	seen := make(map[int]bool)
+	for _, port := range knocking.Sequence {
+		switch {
+		case port < 1 || port > 65535:
+			return fmt.Errorf("invalid knock port %d (use 1-65535)", port)
+		case port == sshPort:
+			return fmt.Errorf("the knock sequence may not use the SSH port %d", sshPort)
+		case seen[port]:
+			return fmt.Errorf("knock port %d is used twice", port)
+		}
+		seen[port] = true
+	}
+	if knocking.SeqTimeout < 1 || knocking.OpenTimeout < 1 {
+		return fmt.Errorf("knock timeouts must be at least one second")
+	}
+	if knocking.Interface != "" && !interfaceNamePattern.MatchString(knocking.Interface) {
+		return fmt.Errorf("invalid interface name %q", knocking.Interface)
+	}
+	return nil
+}
+
+// ValidateIPv6Policy checks the IPv6 mode; an empty mode leaves IPv6 as it is
+func ValidateIPv6Policy(policy model.IPv6Policy) error {
+	switch policy.Mode {
+	case "", model.IPv6Filter, model.IPv6Deny, model.IPv6Disable:
+		return nil
+	}
+	return fmt.Errorf("invalid IPv6 mode %q (use filter, deny or disable)", policy.Mode)
+}
+
+// ValidateRuleSource checks that a rule source is an IP address or network
+func ValidateRuleSource(source string) error {
+	if net.ParseIP(source) != nil {
+		return nil
+	}
+	if _, _, err := net.ParseCIDR(source); err == nil {
+		return nil
+	}
+	return fmt.Errorf("invalid source %q: not an IP address or network", source)
+}
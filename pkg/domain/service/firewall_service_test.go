@@ -47,6 +47,35 @@ type MockFirewallRepository struct {
 
 	DisableError     error
 	DisableCallCount int
+
+	InstallError     error
+	InstallCallCount int
+
+	AppliedProfiles        []model.FirewallProfile
+	ApplyProfilesError     error
+	ApplyProfilesCallCount int
+
+	// Live rules for drift checks
+	LiveRules      []string
+	LiveRulesError error
+
+	// Port knocking
+	Knocking          *model.PortKnocking
+	KnockingCallCount int
+	KnockingActive    bool
+
+	// Dual stack; FilterFamily and MirrorRule update the stacks
+	IPv4, IPv6       model.StackRules
+	StackRulesError  error
+	FilteredFamilies []string
+	MirroredRules    []string
+
+	// IPv6 in the kernel
+	IPv6Enabled       bool
+	IPv6BootDisabled  bool
+	IPv6Disabled      bool
+	IPv6BootParameter bool
+	IPv6KernelCalls   int
 }
 
 func (m *MockFirewallRepository) GetFirewallStatus() (bool, bool, bool, []string, error) {
@@ -93,6 +122,70 @@ func (m *MockFirewallRepository) DisableFirewall() error {
 	return m.DisableError
 }
 
+func (m *MockFirewallRepository) InstallFirewall() error {
+	m.InstallCallCount++
+	return m.InstallError
+}
+
+func (m *MockFirewallRepository) ApplyProfiles(profiles []model.FirewallProfile) error {
+	m.AppliedProfiles = profiles
+	m.ApplyProfilesCallCount++
+	return m.ApplyProfilesError
+}
+
+func (m *MockFirewallRepository) GetRules() ([]string, error) {
+	return m.LiveRules, m.LiveRulesError
+}
+
+func (m *MockFirewallRepository) ConfigurePortKnocking(sshPort int, knocking *model.PortKnocking) error {
+	m.Knocking = knocking
+	m.KnockingCallCount++
+	return nil
+}
+
+func (m *MockFirewallRepository) PortKnockingActive() bool {
+	return m.KnockingActive
+}
+
+func (m *MockFirewallRepository) RuleCommand(rule model.FirewallRule) string {
+	return "ufw " + rule.Action + " " + rule.Ports(":") + "/" + rule.Protocol
+}
+
+func (m *MockFirewallRepository) GetStackRules() (model.StackRules, model.StackRules, error) {
+	return m.IPv4, m.IPv6, m.StackRulesError
+}
+
+func (m *MockFirewallRepository) FilterFamily(family string) error {
+	m.FilteredFamilies = append(m.FilteredFamilies, family)
+	m.stack(family).Filtered = true
+	return nil
+}
+
+func (m *MockFirewallRepository) MirrorRule(rule string, family string) error {
+	m.MirroredRules = append(m.MirroredRules, rule+" "+family)
+	stack := m.stack(family)
+	stack.Allowed = append(stack.Allowed, rule)
+	return nil
+}
+
+func (m *MockFirewallRepository) GetIPv6Kernel() (bool, bool) {
+	return m.IPv6Enabled, m.IPv6BootDisabled
+}
+
+func (m *MockFirewallRepository) SetIPv6Kernel(disabled bool, bootParameter bool) error {
+	m.IPv6Disabled = disabled
+	m.IPv6BootParameter = bootParameter
+	m.IPv6KernelCalls++
+	return nil
+}
+
+func (m *MockFirewallRepository) stack(family string) *model.StackRules {
+	if family == model.FamilyIPv6 {
+		return &m.IPv6
+	}
+	return &m.IPv4
+}
+
 func TestNewFirewallServiceImpl(t *testing.T) {
 	repo := &MockFirewallRepository{}
 	osInfo := model.OSInfo{Type: "debian", Version: "11", Codename: "bullseye"}
@@ -657,6 +750,351 @@ func TestFirewallServiceImpl_DisableFirewall(t *testing.T) {
 	}
 }
 
+func TestFirewallServiceImpl_InstallFirewall(t *testing.T) {
+	tests := []struct {
+		name         string
+		installError error
+		expectError  bool
+	}{
+		{
+			name:         "successful install",
+			installError: nil,
+			expectError:  false,
+		},
+		{
+			name:         "repository error",
+			installError: errors.New("mock install error"),
+			expectError:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			repo := &MockFirewallRepository{
+				InstallError: tc.installError,
+			}
+
+			osInfo := model.OSInfo{Type: "debian", Version: "11"}
+			service := NewFirewallServiceImpl(repo, osInfo)
+
+			// Execute
+			err := service.InstallFirewall()
+
+			// Verify
+			if tc.expectError && err == nil {
+				t.Error("Expected error but got nil")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+
+			if repo.InstallCallCount != 1 {
+				t.Errorf("Expected InstallFirewall to be called once, got %d", repo.InstallCallCount)
+			}
+		})
+	}
+}
+
+func TestFirewallServiceImpl_ApplyProfiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		profiles    []model.FirewallProfile
+		applyError  error
+		expectError bool
+	}{
+		{
+			name: "successful apply",
+			profiles: []model.FirewallProfile{
+				{Name: "WebServer", Title: "Web Server", Ports: []string{"80/tcp", "443/tcp"}},
+			},
+			applyError:  nil,
+			expectError: false,
+		},
+		{
+			name: "repository error",
+			profiles: []model.FirewallProfile{
+				{Name: "WebServer", Title: "Web Server", Ports: []string{"80/tcp"}},
+			},
+			applyError:  errors.New("mock apply error"),
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			repo := &MockFirewallRepository{
+				ApplyProfilesError: tc.applyError,
+			}
+
+			osInfo := model.OSInfo{Type: "debian", Version: "11"}
+			service := NewFirewallServiceImpl(repo, osInfo)
+
+			// Execute
+			err := service.ApplyProfiles(tc.profiles)
+
+			// Verify
+			if tc.expectError && err == nil {
+				t.Error("Expected error but got nil")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+
+			if repo.ApplyProfilesCallCount != 1 {
+				t.Errorf("Expected ApplyProfiles to be called once, got %d", repo.ApplyProfilesCallCount)
+			}
+
+			if !reflect.DeepEqual(repo.AppliedProfiles, tc.profiles) {
+				t.Errorf("Wrong profiles applied. Got %+v, expected %+v", repo.AppliedProfiles, tc.profiles)
+			}
+		})
+	}
+}
+
+func TestFirewallServiceImpl_CheckDualStack(t *testing.T) {
+	ipv4 := model.StackRules{Family: model.FamilyIPv4, Active: true, Filtered: true,
+		Allowed: []string{"allow 22/tcp", "allow 443/tcp", "allow 5432/tcp from 10.0.0.0/8"}}
+
+	tests := []struct {
+		name       string
+		ipv6       model.StackRules
+		unfiltered string
+		gaps       []model.StackGap
+	}{
+		{
+			name: "no ipv6 address",
+			ipv6: model.StackRules{Family: model.FamilyIPv6},
+		},
+		{
+			name: "matching rules",
+			ipv6: model.StackRules{Family: model.FamilyIPv6, Active: true, Filtered: true,
+				Allowed: []string{"allow 22/tcp", "allow 443/tcp", "allow 5432/tcp from fd00::/8"}},
+		},
+		{
+			name: "missing rules",
+			ipv6: model.StackRules{Family: model.FamilyIPv6, Active: true, Filtered: true,
+				Allowed: []string{"allow 22/tcp", "allow 8080/tcp"}},
+			gaps: []model.StackGap{
+				{Rule: "allow 443/tcp", AllowedOn: model.FamilyIPv4, MissingOn: model.FamilyIPv6, Mirrorable: true},
+				{Rule: "allow 8080/tcp", AllowedOn: model.FamilyIPv6, MissingOn: model.FamilyIPv4, Mirrorable: true},
+			},
+		},
+		{
+			name:       "unfiltered ipv6",
+			ipv6:       model.StackRules{Family: model.FamilyIPv6, Active: true},
+			unfiltered: model.FamilyIPv6,
+			gaps: []model.StackGap{
+				{Rule: "allow 22/tcp", AllowedOn: model.FamilyIPv4, MissingOn: model.FamilyIPv6, Mirrorable: true},
+				{Rule: "allow 443/tcp", AllowedOn: model.FamilyIPv4, MissingOn: model.FamilyIPv6, Mirrorable: true},
+				{Rule: "allow 5432/tcp from 10.0.0.0/8", AllowedOn: model.FamilyIPv4, MissingOn: model.FamilyIPv6},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &MockFirewallRepository{IPv4: ipv4, IPv6: tc.ipv6}
+			service := NewFirewallServiceImpl(repo, model.OSInfo{Type: "debian"})
+
+			report, err := service.CheckDualStack(model.IPv6Filter)
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if report.Unfiltered != tc.unfiltered {
+				t.Errorf("Expected unfiltered %q, got %q", tc.unfiltered, report.Unfiltered)
+			}
+			if !reflect.DeepEqual(report.Gaps, tc.gaps) {
+				t.Errorf("Wrong gaps. Got %+v, expected %+v", report.Gaps, tc.gaps)
+			}
+			if report.Synchronized() != (tc.unfiltered == "" && len(tc.gaps) == 0) {
+				t.Errorf("Synchronized() = %v for %+v", report.Synchronized(), report)
+			}
+		})
+	}
+}
+
+func TestFirewallServiceImpl_MirrorDualStack(t *testing.T) {
+	repo := &MockFirewallRepository{
+		IPv4: model.StackRules{Family: model.FamilyIPv4, Active: true, Filtered: true,
+			Allowed: []string{"allow 22/tcp", "allow 5432/tcp from 10.0.0.0/8"}},
+		IPv6: model.StackRules{Family: model.FamilyIPv6, Active: true},
+	}
+	service := NewFirewallServiceImpl(repo, model.OSInfo{Type: "ubuntu"})
+
+	report, err := service.MirrorDualStack(model.IPv6Filter)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if !reflect.DeepEqual(repo.FilteredFamilies, []string{model.FamilyIPv6}) {
+		t.Errorf("Expected IPv6 to be filtered, got %v", repo.FilteredFamilies)
+	}
+	// The addressed rule is no gap once both families are filtered
+	if !reflect.DeepEqual(repo.MirroredRules, []string{"allow 22/tcp ipv6"}) {
+		t.Errorf("Wrong rules mirrored: %v", repo.MirroredRules)
+	}
+	if !report.Synchronized() {
+		t.Errorf("Expected stacks to match after mirroring, got %+v", report)
+	}
+}
+
+func TestFirewallServiceImpl_CheckDualStackDeny(t *testing.T) {
+	repo := &MockFirewallRepository{
+		IPv4: model.StackRules{Family: model.FamilyIPv4, Active: true, Filtered: true,
+			Allowed: []string{"allow 22/tcp", "allow 443/tcp"}},
+		IPv6: model.StackRules{Family: model.FamilyIPv6, Active: true, Filtered: true,
+			Allowed: []string{"allow 22/tcp"}},
+	}
+	service := NewFirewallServiceImpl(repo, model.OSInfo{Type: "debian"})
+
+	report, err := service.CheckDualStack(model.IPv6Deny)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !report.Synchronized() {
+		t.Errorf("Expected rules kept to IPv4 not to be gaps, got %+v", report.Gaps)
+	}
+
+	if _, err := service.MirrorDualStack(model.IPv6Deny); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if len(repo.MirroredRules) != 0 {
+		t.Errorf("Expected nothing mirrored in deny mode, got %v", repo.MirroredRules)
+	}
+}
+
+func TestFirewallServiceImpl_ApplyIPv6Policy(t *testing.T) {
+	repo := &MockFirewallRepository{}
+	service := NewFirewallServiceImpl(repo, model.OSInfo{Type: "debian"})
+
+	if err := service.ApplyIPv6Policy(model.IPv6Policy{Mode: model.IPv6Disable, BootParameter: true}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !repo.IPv6Disabled || !repo.IPv6BootParameter {
+		t.Errorf("Expected IPv6 disabled on the kernel command line, got %+v", repo)
+	}
+
+	if err := service.ApplyIPv6Policy(model.IPv6Policy{Mode: model.IPv6Deny}); err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if repo.IPv6Disabled {
+		t.Error("Expected IPv6 enabled again")
+	}
+
+	if err := service.ApplyIPv6Policy(model.IPv6Policy{}); err != nil || repo.IPv6KernelCalls != 2 {
+		t.Errorf("Expected an empty mode to leave the kernel alone, got %v", err)
+	}
+	if err := service.ApplyIPv6Policy(model.IPv6Policy{Mode: "off"}); err == nil {
+		t.Error("Expected an unknown mode to be refused")
+	}
+}
+
+func TestFirewallServiceImpl_CheckIPv6(t *testing.T) {
+	config := model.FirewallConfig{
+		IPv6: model.IPv6Deny,
+		Rules: []model.FirewallRule{
+			{Action: "allow", Protocol: "tcp", Port: 22},
+			{Action: "allow", Protocol: "tcp", Port: 443, Family: model.FamilyIPv4},
+			{Action: "allow", Protocol: "udp", Port: 51820, Family: model.FamilyIPv6},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		enabled    bool
+		ipv6       model.StackRules
+		config     model.FirewallConfig
+		unexpected []string
+		exposed    bool
+		compliant  bool
+	}{
+		{
+			name:      "disabled",
+			ipv6:      model.StackRules{Family: model.FamilyIPv6},
+			config:    config,
+			compliant: true,
+		},
+		{
+			name:    "deny as declared",
+			enabled: true,
+			ipv6: model.StackRules{Family: model.FamilyIPv6, Active: true, Filtered: true,
+				Allowed: []string{"allow 22/tcp", "allow 51820/udp"}},
+			config:    config,
+			compliant: true,
+		},
+		{
+			name:    "deny with an IPv4 rule open",
+			enabled: true,
+			ipv6: model.StackRules{Family: model.FamilyIPv6, Active: true, Filtered: true,
+				Allowed: []string{"allow 22/tcp", "allow 443/tcp"}},
+			config:     config,
+			unexpected: []string{"allow 443/tcp"},
+		},
+		{
+			name:    "unfiltered",
+			enabled: true,
+			ipv6:    model.StackRules{Family: model.FamilyIPv6, Active: true},
+			config:  model.FirewallConfig{IPv6: model.IPv6Filter},
+			exposed: true,
+		},
+		{
+			name:    "disable mode still enabled",
+			enabled: true,
+			ipv6:    model.StackRules{Family: model.FamilyIPv6, Filtered: true},
+			config:  model.FirewallConfig{IPv6: model.IPv6Disable},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &MockFirewallRepository{IPv6: tc.ipv6, IPv6Enabled: tc.enabled}
+			service := NewFirewallServiceImpl(repo, model.OSInfo{Type: "ubuntu"})
+
+			exposure, err := service.CheckIPv6(tc.config)
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if !reflect.DeepEqual(exposure.Unexpected, tc.unexpected) {
+				t.Errorf("Unexpected rules %v, expected %v", exposure.Unexpected, tc.unexpected)
+			}
+			if exposure.Exposed() != tc.exposed {
+				t.Errorf("Exposed() = %v for %+v", exposure.Exposed(), exposure)
+			}
+			if exposure.Compliant() != tc.compliant {
+				t.Errorf("Compliant() = %v for %+v", exposure.Compliant(), exposure)
+			}
+		})
+	}
+}
+
+func TestValidateIPv6Policy(t *testing.T) {
+	for _, mode := range []string{"", model.IPv6Filter, model.IPv6Deny, model.IPv6Disable} {
+		if err := ValidateIPv6Policy(model.IPv6Policy{Mode: mode}); err != nil {
+			t.Errorf("Expected mode %q to be valid, got %v", mode, err)
+		}
+	}
+	if err := ValidateIPv6Policy(model.IPv6Policy{Mode: "block"}); err == nil {
+		t.Error("Expected an unknown mode to be refused")
+	}
+
+	rules := []struct {
+		rule  model.FirewallRule
+		valid bool
+	}{
+		{model.FirewallRule{Action: "allow", Protocol: "tcp", Port: 80, Family: model.FamilyIPv6}, true},
+		{model.FirewallRule{Action: "allow", Protocol: "tcp", Port: 80, Family: model.FamilyIPv6, SourceIP: "fd00::/8"}, true},
+		{model.FirewallRule{Action: "allow", Protocol: "tcp", Port: 80, Family: model.FamilyIPv6, SourceIP: "10.0.0.0/8"}, false},
+		{model.FirewallRule{Action: "allow", Protocol: "tcp", Port: 80, Family: "inet6"}, false},
+	}
+	for _, tc := range rules {
+		if err := ValidateRule(tc.rule); (err == nil) != tc.valid {
+			t.Errorf("ValidateRule(%+v) = %v", tc.rule, err)
+		}
+	}
+}
+
 func TestFirewallServiceImpl_OSTypes(t *testing.T) {
 	// Test with different OS types to ensure the service works consistently
 	osTypes := []string{"debian", "ubuntu", "alpine", "proxmox", "unknown"}
@@ -689,3 +1127,245 @@ func TestFirewallServiceImpl_OSTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePortSpec(t *testing.T) {
+	tests := []struct {
+		spec         string
+		wantPort     int
+		wantProtocol string
+		expectError  bool
+	}{
+		{spec: "22", wantPort: 22, wantProtocol: "tcp"},
+		{spec: "53/udp", wantPort: 53, wantProtocol: "udp"},
+		{spec: "443/TCP", wantPort: 443, wantProtocol: "tcp"},
+		{spec: "0", expectError: true},
+		{spec: "65536", expectError: true},
+		{spec: "http", expectError: true},
+		{spec: "80/icmp", expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.spec, func(t *testing.T) {
+			port, protocol, err := ParsePortSpec(tc.spec)
+
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %q but got nil", tc.spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if port != tc.wantPort || protocol != tc.wantProtocol {
+				t.Errorf("Got %d/%s, expected %d/%s", port, protocol, tc.wantPort, tc.wantProtocol)
+			}
+		})
+	}
+}
+
+func TestValidateRuleSource(t *testing.T) {
+	for _, source := range []string{"192.168.1.10", "10.0.0.0/8", "2001:db8::/32"} {
+		if err := ValidateRuleSource(source); err != nil {
+			t.Errorf("Expected %q to be valid, got: %v", source, err)
+		}
+	}
+	for _, source := range []string{"", "any", "10.0.0.0/33", "example.com"} {
+		if err := ValidateRuleSource(source); err == nil {
+			t.Errorf("Expected %q to be rejected", source)
+		}
+	}
+}
+
+func TestParsePortRange(t *testing.T) {
+	tests := []struct {
+		spec        string
+		wantPort    int
+		wantEnd     int
+		expectError bool
+	}{
+		{spec: "8080", wantPort: 8080},
+		{spec: "6000-6007", wantPort: 6000, wantEnd: 6007},
+		{spec: "6000:6007", wantPort: 6000, wantEnd: 6007},
+		{spec: "6007-6000", expectError: true},
+		{spec: "6000-6000", expectError: true},
+		{spec: "1-70000", expectError: true},
+		{spec: "6000-", expectError: true},
+		{spec: "http", expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.spec, func(t *testing.T) {
+			port, endPort, err := ParsePortRange(tc.spec)
+
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %q but got nil", tc.spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if port != tc.wantPort || endPort != tc.wantEnd {
+				t.Errorf("Got %d-%d, expected %d-%d", port, endPort, tc.wantPort, tc.wantEnd)
+			}
+		})
+	}
+}
+
+func TestFirewallServiceImpl_PreviewRule(t *testing.T) {
+	valid := model.FirewallRule{Action: "allow", Protocol: "udp", Port: 60000, EndPort: 61000, SourceIP: "10.0.0.0/8", Description: "mosh"}
+
+	tests := []struct {
+		name        string
+		change      func(rule *model.FirewallRule)
+		expectError bool
+	}{
+		{name: "valid", change: func(rule *model.FirewallRule) {}},
+		{name: "reject", change: func(rule *model.FirewallRule) { rule.Action = "reject" }},
+		{name: "limit", change: func(rule *model.FirewallRule) { rule.Action = "limit" }, expectError: true},
+		{name: "icmp", change: func(rule *model.FirewallRule) { rule.Protocol = "icmp" }, expectError: true},
+		{name: "reversed range", change: func(rule *model.FirewallRule) { rule.EndPort = 50000 }, expectError: true},
+		{name: "hostname source", change: func(rule *model.FirewallRule) { rule.SourceIP = "example.com" }, expectError: true},
+		{name: "quoted comment", change: func(rule *model.FirewallRule) { rule.Description = "it's" }, expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := valid
+			tc.change(&rule)
+			service := NewFirewallServiceImpl(&MockFirewallRepository{}, model.OSInfo{Type: "debian"})
+
+			command, err := service.PreviewRule(rule)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %+v but got nil", rule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if command != "ufw "+rule.Action+" 60000:61000/udp" {
+				t.Errorf("Unexpected preview %q", command)
+			}
+		})
+	}
+}
+
+func TestFirewallServiceImpl_CheckDrift(t *testing.T) {
+	config := model.FirewallConfig{
+		Rules: []model.FirewallRule{
+			{Action: "allow", Protocol: "tcp", Port: 2222, Description: "SSH access"},
+			{Action: "deny", Protocol: "udp", Port: 6000, EndPort: 6007, SourceIP: "10.0.0.0/8"},
+		},
+		ApplicationProfiles: []model.FirewallProfile{
+			{Name: "LabHTTPS", Ports: []string{"30443/tcp", "80,8080/tcp", "53"}},
+		},
+	}
+
+	repo := &MockFirewallRepository{
+		Installed:  true,
+		Enabled:    true,
+		Configured: true,
+		LiveRules: []string{
+			"allow 2222/tcp",
+			"allow 30443/tcp",
+			"allow 80,8080/tcp",
+			"allow 53",
+			"allow 3306/tcp",
+		},
+	}
+	service := NewFirewallServiceImpl(repo, model.OSInfo{Type: "ubuntu"})
+
+	drift, err := service.CheckDrift(config)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !reflect.DeepEqual(drift.Missing, []string{"deny 6000:6007/udp from 10.0.0.0/8"}) {
+		t.Errorf("Unexpected missing rules %v", drift.Missing)
+	}
+	if !reflect.DeepEqual(drift.Extra, []string{"allow 3306/tcp"}) {
+		t.Errorf("Unexpected extra rules %v", drift.Extra)
+	}
+	if drift.InSync() {
+		t.Error("Expected drift")
+	}
+
+	repo.LiveRules = append(repo.LiveRules[:4], "deny 6000:6007/udp from 10.0.0.0/8")
+	if drift, _ = service.CheckDrift(config); !drift.InSync() {
+		t.Errorf("Expected no drift, got %+v", drift)
+	}
+
+	repo.Installed = false
+	if _, err := service.CheckDrift(config); err == nil {
+		t.Error("Expected an error without a firewall")
+	}
+}
+
+func TestValidatePortKnocking(t *testing.T) {
+	valid := model.PortKnocking{Sequence: []int{7000, 8000, 9000}, SeqTimeout: 10, OpenTimeout: 30}
+
+	tests := []struct {
+		name        string
+		change      func(k *model.PortKnocking)
+		expectError bool
+	}{
+		{name: "valid", change: func(k *model.PortKnocking) {}},
+		{name: "interface", change: func(k *model.PortKnocking) { k.Interface = "enp1s0" }},
+		{name: "too short", change: func(k *model.PortKnocking) { k.Sequence = []int{7000, 8000} }, expectError: true},
+		{name: "SSH port", change: func(k *model.PortKnocking) { k.Sequence = []int{7000, 2222, 9000} }, expectError: true},
+		{name: "repeated port", change: func(k *model.PortKnocking) { k.Sequence = []int{7000, 8000, 7000} }, expectError: true},
+		{name: "port out of range", change: func(k *model.PortKnocking) { k.Sequence = []int{7000, 8000, 70000} }, expectError: true},
+		{name: "no timeout", change: func(k *model.PortKnocking) { k.OpenTimeout = 0 }, expectError: true},
+		{name: "bad interface", change: func(k *model.PortKnocking) { k.Interface = "eth0; reboot" }, expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			knocking := valid
+			knocking.Sequence = append([]int{}, valid.Sequence...)
+			tc.change(&knocking)
+
+			err := ValidatePortKnocking(2222, knocking)
+			if tc.expectError && err == nil {
+				t.Error("Expected an error")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+
+	repo := &MockFirewallRepository{}
+	service := NewFirewallServiceImpl(repo, model.OSInfo{Type: "debian"})
+	if err := service.ConfigurePortKnocking(2222, &model.PortKnocking{Sequence: []int{7000}}); err == nil {
+		t.Error("Expected an invalid sequence to be refused")
+	}
+	if repo.KnockingCallCount != 0 {
+		t.Error("Expected knockd to be left alone")
+	}
+	if err := service.ConfigurePortKnocking(2222, nil); err != nil || repo.KnockingCallCount != 1 {
+		t.Errorf("Expected knockd to be stopped, got %v", err)
+	}
+}
+
+func TestFirewallServiceImpl_GetSSHAccess(t *testing.T) {
+	repo := &MockFirewallRepository{LiveRules: []string{"limit 2222", "allow 443/tcp"}}
+	service := NewFirewallServiceImpl(repo, model.OSInfo{Type: "ubuntu"})
+
+	access, err := service.GetSSHAccess(2222)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+	if !access.RateLimited || access.PortKnocking {
+		t.Errorf("Unexpected SSH access %+v", access)
+	}
+
+	repo.LiveRules = []string{"allow 2222/tcp"}
+	repo.KnockingActive = true
+	if access, _ = service.GetSSHAccess(2222); access.RateLimited || !access.PortKnocking {
+		t.Errorf("Unexpected SSH access %+v", access)
+	}
+}
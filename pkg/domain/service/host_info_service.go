@@ -2,6 +2,7 @@
 package service
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/abbott/hardn/pkg/domain/model"
@@ -24,6 +25,9 @@ type HostInfoService interface {
 	// GetNonSystemUsers retrieves non-system users on the system
 	GetNonSystemUsers() ([]model.User, error)
 
+	// ListNonSystemUsers retrieves a page of non-system users
+	ListNonSystemUsers(opts model.UserListOptions) (*model.UserPage, error)
+
 	// GetNonSystemGroups retrieves non-system groups on the system
 	GetNonSystemGroups() ([]string, error)
 
@@ -81,6 +85,17 @@ func (s *HostInfoServiceImpl) GetNonSystemUsers() ([]model.User, error) {
 	return s.userRepo.GetNonSystemUsers()
 }
 
+// ListNonSystemUsers retrieves a page of non-system users
+func (s *HostInfoServiceImpl) ListNonSystemUsers(opts model.UserListOptions) (*model.UserPage, error) {
+	if opts.Offset < 0 {
+		return nil, fmt.Errorf("invalid offset %d: must not be negative", opts.Offset)
+	}
+	if opts.Limit < 0 {
+		return nil, fmt.Errorf("invalid limit %d: must not be negative", opts.Limit)
+	}
+	return s.userRepo.ListNonSystemUsers(opts)
+}
+
 // GetNonSystemGroups retrieves non-system groups on the system
 func (s *HostInfoServiceImpl) GetNonSystemGroups() ([]string, error) {
 	return s.userRepo.GetNonSystemGroups()
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostInfoServiceImpl_ListNonSystemUsers(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        model.UserListOptions
+		expectCall  bool
+		expectError bool
+	}{
+		{
+			name:       "first page",
+			opts:       model.UserListOptions{Limit: 2},
+			expectCall: true,
+		},
+		{
+			name:       "local users only",
+			opts:       model.UserListOptions{LocalOnly: true},
+			expectCall: true,
+		},
+		{
+			name:        "negative offset",
+			opts:        model.UserListOptions{Offset: -1},
+			expectError: true,
+		},
+		{
+			name:        "negative limit",
+			opts:        model.UserListOptions{Limit: -5},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(MockUserRepository)
+			service := NewHostInfoServiceImpl(nil, mockRepo, model.OSInfo{Type: "debian"})
+
+			page := &model.UserPage{
+				Users: []model.User{{Username: "alice", Local: true}, {Username: "bob"}},
+				Total: 3,
+			}
+			if tc.expectCall {
+				mockRepo.On("ListNonSystemUsers", tc.opts).Return(page, nil)
+			}
+
+			// Execute
+			result, err := service.ListNonSystemUsers(tc.opts)
+
+			// Assert
+			if tc.expectError {
+				assert.Error(t, err)
+				mockRepo.AssertNotCalled(t, "ListNonSystemUsers", tc.opts)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, page, result)
+			assert.True(t, result.HasMore())
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// pkg/domain/service/log_shipping_service.go
+package service
+
+import (
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// LogShippingService defines operations for forwarding logs off the host
+type LogShippingService interface {
+	// EffectivePolicy checks the policy and fills in the method's default port
+	EffectivePolicy(policy model.LogShippingPolicy) (model.LogShippingPolicy, error)
+
+	// CheckConnection connects to the collector with the policy's certificates
+	CheckConnection(policy model.LogShippingPolicy) error
+
+	// ApplyLogShipping sets up the forwarding of the policy
+	ApplyLogShipping(policy model.LogShippingPolicy) error
+
+	// GetLogShippingStatus reports whether logs are forwarded
+	GetLogShippingStatus() (*model.LogShippingStatus, error)
+}
+
+// LogShippingServiceImpl implements LogShippingService
+type LogShippingServiceImpl struct {
+	repository LogShippingRepository
+	osInfo     model.OSInfo
+}
+
+// NewLogShippingServiceImpl creates a new LogShippingServiceImpl
+func NewLogShippingServiceImpl(repository LogShippingRepository, osInfo model.OSInfo) *LogShippingServiceImpl {
+	return &LogShippingServiceImpl{
+		repository: repository,
+		osInfo:     osInfo,
+	}
+}
+
+// LogShippingRepository defines the repository operations needed by LogShippingService
+type LogShippingRepository interface {
+	CheckConnection(policy model.LogShippingPolicy) error
+	ConfigureLogShipping(policy model.LogShippingPolicy) error
+	GetLogShippingStatus() (*model.LogShippingStatus, error)
+}
+
+func (s *LogShippingServiceImpl) EffectivePolicy(policy model.LogShippingPolicy) (model.LogShippingPolicy, error) {
+	switch policy.Method {
+	case model.LogShippingRsyslog:
+		if policy.Port == 0 {
+			policy.Port = model.DefaultRsyslogTLSPort
+		}
+	case model.LogShippingJournald:
+		if s.osInfo.Type == "alpine" {
+			return policy, fmt.Errorf("journald shipping needs systemd, which Alpine Linux does not run; use rsyslog")
+		}
+		if policy.CertFile == "" || policy.KeyFile == "" {
+			return policy, fmt.Errorf("journald shipping needs a client certificate and key (certFile and keyFile)")
+		}
+		if policy.Port == 0 {
+			policy.Port = model.DefaultJournalRemotePort
+		}
+	default:
+		return policy, fmt.Errorf("unknown log shipping method %q (expected one of %v)", policy.Method, model.LogShippingMethods)
+	}
+
+	if policy.Host == "" {
+		return policy, fmt.Errorf("no log collector configured (logShipping.host)")
+	}
+	if !hostNamePattern.MatchString(policy.Host) {
+		return policy, fmt.Errorf("invalid log collector %q", policy.Host)
+	}
+	if policy.Port < 1 || policy.Port > 65535 {
+		return policy, fmt.Errorf("invalid log collector port %d", policy.Port)
+	}
+	if policy.CAFile == "" {
+		return policy, fmt.Errorf("logs are only shipped over TLS; set the collector's CA (caFile)")
+	}
+	if (policy.CertFile == "") != (policy.KeyFile == "") {
+		return policy, fmt.Errorf("certFile and keyFile go together")
+	}
+	return policy, nil
+}
+
+func (s *LogShippingServiceImpl) CheckConnection(policy model.LogShippingPolicy) error {
+	policy, err := s.EffectivePolicy(policy)
+	if err != nil {
+		return err
+	}
+	return s.repository.CheckConnection(policy)
+}
+
+func (s *LogShippingServiceImpl) ApplyLogShipping(policy model.LogShippingPolicy) error {
+	policy, err := s.EffectivePolicy(policy)
+	if err != nil {
+		return err
+	}
+	return s.repository.ConfigureLogShipping(policy)
+}
+
+func (s *LogShippingServiceImpl) GetLogShippingStatus() (*model.LogShippingStatus, error) {
+	return s.repository.GetLogShippingStatus()
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLogShippingRepository is a mock implementation of LogShippingRepository
+type MockLogShippingRepository struct {
+	mock.Mock
+}
+
+func (m *MockLogShippingRepository) CheckConnection(policy model.LogShippingPolicy) error {
+	args := m.Called(policy)
+	return args.Error(0)
+}
+
+func (m *MockLogShippingRepository) ConfigureLogShipping(policy model.LogShippingPolicy) error {
+	args := m.Called(policy)
+	return args.Error(0)
+}
+
+func (m *MockLogShippingRepository) GetLogShippingStatus() (*model.LogShippingStatus, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.LogShippingStatus), args.Error(1)
+}
+
+func TestLogShippingServiceImpl_EffectivePolicy(t *testing.T) {
+	rsyslog := model.LogShippingPolicy{Method: model.LogShippingRsyslog, Host: "logs.example.com", CAFile: "/root/ca.pem"}
+	journald := model.LogShippingPolicy{Method: model.LogShippingJournald, Host: "logs.example.com", CAFile: "/root/ca.pem",
+		CertFile: "/root/web1.pem", KeyFile: "/root/web1.key"}
+
+	tests := []struct {
+		name    string
+		osType  string
+		policy  func(p *model.LogShippingPolicy)
+		base    model.LogShippingPolicy
+		port    int
+		wantErr bool
+	}{
+		{name: "rsyslog default port", osType: "debian", base: rsyslog, port: model.DefaultRsyslogTLSPort},
+		{name: "journald default port", osType: "ubuntu", base: journald, port: model.DefaultJournalRemotePort},
+		{name: "explicit port", osType: "debian", base: rsyslog, policy: func(p *model.LogShippingPolicy) { p.Port = 10514 }, port: 10514},
+		{name: "journald on Alpine", osType: "alpine", base: journald, wantErr: true},
+		{name: "journald without client certificate", osType: "debian", base: journald,
+			policy: func(p *model.LogShippingPolicy) { p.CertFile, p.KeyFile = "", "" }, wantErr: true},
+		{name: "certificate without key", osType: "debian", base: rsyslog,
+			policy: func(p *model.LogShippingPolicy) { p.CertFile = "/root/web1.pem" }, wantErr: true},
+		{name: "no CA", osType: "debian", base: rsyslog, policy: func(p *model.LogShippingPolicy) { p.CAFile = "" }, wantErr: true},
+		{name: "no host", osType: "debian", base: rsyslog, policy: func(p *model.LogShippingPolicy) { p.Host = "" }, wantErr: true},
+		{name: "invalid host", osType: "debian", base: rsyslog,
+			policy: func(p *model.LogShippingPolicy) { p.Host = `logs" target="evil` }, wantErr: true},
+		{name: "unknown method", osType: "debian", base: rsyslog, policy: func(p *model.LogShippingPolicy) { p.Method = "syslog-ng" }, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := tc.base
+			if tc.policy != nil {
+				tc.policy(&policy)
+			}
+			service := NewLogShippingServiceImpl(new(MockLogShippingRepository), model.OSInfo{Type: tc.osType})
+			effective, err := service.EffectivePolicy(policy)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.port, effective.Port)
+		})
+	}
+}
+
+func TestLogShippingServiceImpl_ApplyLogShipping(t *testing.T) {
+	mockRepo := new(MockLogShippingRepository)
+	service := NewLogShippingServiceImpl(mockRepo, model.OSInfo{Type: "debian", Version: "12"})
+
+	policy := model.LogShippingPolicy{Method: model.LogShippingRsyslog, Host: "logs.example.com", CAFile: "/root/ca.pem"}
+	expected := policy
+	expected.Port = model.DefaultRsyslogTLSPort
+	mockRepo.On("ConfigureLogShipping", expected).Return(nil)
+
+	assert.NoError(t, service.ApplyLogShipping(policy))
+	mockRepo.AssertExpectations(t)
+
+	// An invalid policy never reaches the repository
+	policy.CAFile = ""
+	assert.Error(t, service.ApplyLogShipping(policy))
+	mockRepo.AssertNumberOfCalls(t, "ConfigureLogShipping", 1)
+}
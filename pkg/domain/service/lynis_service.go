@@ -0,0 +1,123 @@
+// pkg/domain/service/lynis_service.go
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// LynisService defines operations for Lynis security scans
+type LynisService interface {
+	// RunScan runs a Lynis scan and returns its report
+	RunScan() (*model.LynisReport, error)
+
+	// LastReport returns the report of the last scan, or nil when Lynis
+	// has not run yet
+	LastReport() (*model.LynisReport, error)
+}
+
+// LynisServiceImpl implements LynisService
+type LynisServiceImpl struct {
+	repository LynisRepository
+}
+
+// NewLynisServiceImpl creates a new LynisServiceImpl
+func NewLynisServiceImpl(repository LynisRepository) *LynisServiceImpl {
+	return &LynisServiceImpl{
+		repository: repository,
+	}
+}
+
+// LynisRepository defines the repository operations needed by LynisService
+type LynisRepository interface {
+	IsInstalled() bool
+	RunAudit() error
+	ReadReport() ([]byte, error)
+}
+
+// lynisTimeLayout is the layout of the report's timestamps
+const lynisTimeLayout = "2006-01-02 15:04:05"
+
+func (s *LynisServiceImpl) RunScan() (*model.LynisReport, error) {
+	if !s.repository.IsInstalled() {
+		return nil, fmt.Errorf("lynis is not installed; set enableLynis in the configuration or install the lynis package")
+	}
+	if err := s.repository.RunAudit(); err != nil {
+		return nil, err
+	}
+
+	report, err := s.LastReport()
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, fmt.Errorf("lynis did not write a report to %s", model.LynisReportPath)
+	}
+	return report, nil
+}
+
+func (s *LynisServiceImpl) LastReport() (*model.LynisReport, error) {
+	data, err := s.repository.ReadReport()
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return ParseLynisReport(data), nil
+}
+
+// ParseLynisReport parses the key=value lines of lynis-report.dat. Warnings
+// and suggestions are pipe-separated as TEST-ID|message|details|solution|,
+// with "-" for an empty field.
+func ParseLynisReport(data []byte) *model.LynisReport {
+	report := &model.LynisReport{
+		Warnings:    []model.LynisFinding{},
+		Suggestions: []model.LynisFinding{},
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+
+		switch key {
+		case "lynis_version":
+			report.Version = value
+		case "hostname":
+			report.Hostname = value
+		case "report_datetime_end":
+			if finished, err := time.ParseInLocation(lynisTimeLayout, value, time.Local); err == nil {
+				report.Finished = finished
+			}
+		case "hardening_index":
+			report.HardeningIndex, _ = strconv.Atoi(value)
+		case "lynis_tests_done":
+			report.TestsPerformed, _ = strconv.Atoi(value)
+		case "warning[]":
+			report.Warnings = append(report.Warnings, parseLynisFinding(value))
+		case "suggestion[]":
+			report.Suggestions = append(report.Suggestions, parseLynisFinding(value))
+		}
+	}
+	return report
+}
+
+// parseLynisFinding parses TEST-ID|message|details|solution|
+func parseLynisFinding(value string) model.LynisFinding {
+	fields := strings.Split(value, "|")
+	field := func(i int) string {
+		if i >= len(fields) || fields[i] == "-" {
+			return ""
+		}
+		return strings.TrimSpace(fields[i])
+	}
+	return model.LynisFinding{
+		TestID:   field(0),
+		Message:  field(1),
+		Details:  field(2),
+		Solution: field(3),
+	}
+}
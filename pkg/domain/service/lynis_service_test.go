@@ -0,0 +1,106 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockLynisRepository is a mock implementation of LynisRepository
+type MockLynisRepository struct {
+	mock.Mock
+}
+
+func (m *MockLynisRepository) IsInstalled() bool {
+	return m.Called().Bool(0)
+}
+
+func (m *MockLynisRepository) RunAudit() error {
+	return m.Called().Error(0)
+}
+
+func (m *MockLynisRepository) ReadReport() ([]byte, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+const lynisReport = `# Lynis Report
+report_version_major=1
+lynis_version=3.0.9
+hostname=web1
+report_datetime_start=2026-03-01 10:00:00
+report_datetime_end=2026-03-01 10:02:31
+lynis_tests_done=254
+hardening_index=67
+warning[]=SSH-7408|Root can log in over SSH|PermitRootLogin=yes|-|
+suggestion[]=ACCT-9622|Enable process accounting|-|-|
+suggestion[]=PKGS-7370|Install debsums to verify installed packages|-|apt-get install debsums|
+`
+
+func TestParseLynisReport(t *testing.T) {
+	report := ParseLynisReport([]byte(lynisReport))
+
+	assert.Equal(t, "3.0.9", report.Version)
+	assert.Equal(t, "web1", report.Hostname)
+	assert.Equal(t, time.Date(2026, 3, 1, 10, 2, 31, 0, time.Local), report.Finished)
+	assert.Equal(t, 67, report.HardeningIndex)
+	assert.Equal(t, 254, report.TestsPerformed)
+	assert.Equal(t, []model.LynisFinding{
+		{TestID: "SSH-7408", Message: "Root can log in over SSH", Details: "PermitRootLogin=yes"},
+	}, report.Warnings)
+	require.Len(t, report.Suggestions, 2)
+	assert.Equal(t, "ACCT-9622", report.Suggestions[0].TestID)
+	assert.Empty(t, report.Suggestions[0].Details)
+	assert.Equal(t, "apt-get install debsums", report.Suggestions[1].Solution)
+
+	// A report without findings still has empty lists
+	report = ParseLynisReport([]byte("hardening_index=90\n"))
+	assert.NotNil(t, report.Warnings)
+	assert.NotNil(t, report.Suggestions)
+}
+
+func TestLynisServiceImpl_RunScan(t *testing.T) {
+	mockRepo := new(MockLynisRepository)
+	mockRepo.On("IsInstalled").Return(true)
+	mockRepo.On("RunAudit").Return(nil)
+	mockRepo.On("ReadReport").Return([]byte(lynisReport), nil)
+
+	report, err := NewLynisServiceImpl(mockRepo).RunScan()
+	require.NoError(t, err)
+	assert.Equal(t, 67, report.HardeningIndex)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLynisServiceImpl_RunScan_Errors(t *testing.T) {
+	notInstalled := new(MockLynisRepository)
+	notInstalled.On("IsInstalled").Return(false)
+	_, err := NewLynisServiceImpl(notInstalled).RunScan()
+	assert.ErrorContains(t, err, "lynis is not installed")
+	notInstalled.AssertNotCalled(t, "RunAudit")
+
+	failed := new(MockLynisRepository)
+	failed.On("IsInstalled").Return(true)
+	failed.On("RunAudit").Return(errors.New("exit status 1"))
+	_, err = NewLynisServiceImpl(failed).RunScan()
+	assert.Error(t, err)
+
+	noReport := new(MockLynisRepository)
+	noReport.On("IsInstalled").Return(true)
+	noReport.On("RunAudit").Return(nil)
+	noReport.On("ReadReport").Return(nil, nil)
+	_, err = NewLynisServiceImpl(noReport).RunScan()
+	assert.ErrorContains(t, err, model.LynisReportPath)
+
+	// Without a scan there is no last report, and no error
+	report, err := NewLynisServiceImpl(noReport).LastReport()
+	assert.NoError(t, err)
+	assert.Nil(t, report)
+}
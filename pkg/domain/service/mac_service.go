@@ -0,0 +1,109 @@
+// pkg/domain/service/mac_service.go
+package service
+
+import (
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// MACService defines operations for mandatory access control
+type MACService interface {
+	// GetStatus returns the active framework and its mode
+	GetStatus() (*model.MACStatus, error)
+
+	// Enforce installs a framework and puts it in enforcing mode. An empty
+	// framework picks the active one, or the distribution's default.
+	// Under SELinux a non-default sshPort is labeled first so sshd can
+	// still bind it.
+	Enforce(framework string, sshPort int) (*model.MACStatus, error)
+}
+
+// MACServiceImpl implements MACService
+type MACServiceImpl struct {
+	repository MACRepository
+	osInfo     model.OSInfo
+}
+
+// NewMACServiceImpl creates a new MACServiceImpl
+func NewMACServiceImpl(repository MACRepository, osInfo model.OSInfo) *MACServiceImpl {
+	return &MACServiceImpl{
+		repository: repository,
+		osInfo:     osInfo,
+	}
+}
+
+// MACRepository defines the repository operations needed by MACService
+type MACRepository interface {
+	GetMACStatus() (*model.MACStatus, error)
+	InstallMAC(framework string) error
+	EnforceMAC(framework string) error
+	LabelSSHPort(port int) error
+}
+
+func (s *MACServiceImpl) GetStatus() (*model.MACStatus, error) {
+	return s.repository.GetMACStatus()
+}
+
+func (s *MACServiceImpl) Enforce(framework string, sshPort int) (*model.MACStatus, error) {
+	current, err := s.repository.GetMACStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check MAC status: %w", err)
+	}
+
+	if framework == "" {
+		framework = s.DefaultFramework(current)
+	}
+	if framework != model.MACAppArmor && framework != model.MACSELinux {
+		return nil, fmt.Errorf("unknown MAC framework %q (use %s or %s)", framework, model.MACAppArmor, model.MACSELinux)
+	}
+	if framework == model.MACSELinux && s.osInfo.Type == "alpine" {
+		return nil, fmt.Errorf("SELinux is not available on Alpine; use AppArmor")
+	}
+
+	// Switching frameworks changes the kernel's boot parameters and
+	// relabels every file; hardn leaves that to the administrator
+	if current.Framework != "" && current.Framework != framework && current.Mode != model.MACModeDisabled {
+		return nil, fmt.Errorf("%s is the active security module; %s cannot be enforced alongside it",
+			model.FrameworkName(current.Framework), model.FrameworkName(framework))
+	}
+
+	if err := s.repository.InstallMAC(framework); err != nil {
+		return nil, err
+	}
+
+	// Label the SSH port before enforcing, or sshd cannot bind it after
+	// its next restart
+	if framework == model.MACSELinux && sshPort != 0 && sshPort != 22 {
+		if err := s.repository.LabelSSHPort(sshPort); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repository.EnforceMAC(framework); err != nil {
+		return nil, err
+	}
+
+	status, err := s.repository.GetMACStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check MAC status: %w", err)
+	}
+	if framework == model.MACAppArmor && !status.Enforcing() {
+		return status, fmt.Errorf("AppArmor is installed but no profiles are enforced")
+	}
+	return status, nil
+}
+
+// DefaultFramework returns the active framework, or the one the
+// distribution ships: AppArmor on Debian, Ubuntu and Alpine, SELinux
+// elsewhere
+func (s *MACServiceImpl) DefaultFramework(current *model.MACStatus) string {
+	if current != nil && current.Framework != "" {
+		return current.Framework
+	}
+	switch s.osInfo.Type {
+	case "debian", "ubuntu", "alpine":
+		return model.MACAppArmor
+	}
+	return model.MACSELinux
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockMACRepository is a mock implementation of MACRepository
+type MockMACRepository struct {
+	mock.Mock
+}
+
+func (m *MockMACRepository) GetMACStatus() (*model.MACStatus, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.MACStatus), args.Error(1)
+}
+
+func (m *MockMACRepository) InstallMAC(framework string) error {
+	args := m.Called(framework)
+	return args.Error(0)
+}
+
+func (m *MockMACRepository) EnforceMAC(framework string) error {
+	args := m.Called(framework)
+	return args.Error(0)
+}
+
+func (m *MockMACRepository) LabelSSHPort(port int) error {
+	args := m.Called(port)
+	return args.Error(0)
+}
+
+func TestMACServiceImpl_Enforce(t *testing.T) {
+	t.Run("SELinux labels the SSH port before enforcing", func(t *testing.T) {
+		mockRepo := new(MockMACRepository)
+		permissive := &model.MACStatus{Framework: model.MACSELinux, Mode: model.MACModePermissive}
+		enforcing := &model.MACStatus{Framework: model.MACSELinux, Mode: model.MACModeEnforcing, Policy: "targeted"}
+		mockRepo.On("GetMACStatus").Return(permissive, nil).Once()
+		mockRepo.On("InstallMAC", model.MACSELinux).Return(nil)
+		mockRepo.On("LabelSSHPort", 2222).Return(nil)
+		mockRepo.On("EnforceMAC", model.MACSELinux).Return(nil)
+		mockRepo.On("GetMACStatus").Return(enforcing, nil).Once()
+
+		service := NewMACServiceImpl(mockRepo, model.OSInfo{Type: "rocky"})
+		status, err := service.Enforce("", 2222)
+
+		require.NoError(t, err)
+		assert.True(t, status.Enforcing())
+		assert.Equal(t, "SELinux enforcing (targeted)", status.Describe())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("default SSH port is not labeled", func(t *testing.T) {
+		mockRepo := new(MockMACRepository)
+		mockRepo.On("GetMACStatus").Return(&model.MACStatus{Mode: model.MACModeDisabled}, nil).Once()
+		mockRepo.On("InstallMAC", model.MACSELinux).Return(nil)
+		mockRepo.On("EnforceMAC", model.MACSELinux).Return(nil)
+		mockRepo.On("GetMACStatus").Return(&model.MACStatus{Framework: model.MACSELinux,
+			Mode: model.MACModeDisabled, ConfiguredMode: model.MACModeEnforcing, RebootRequired: true}, nil).Once()
+
+		service := NewMACServiceImpl(mockRepo, model.OSInfo{Type: "debian"})
+		status, err := service.Enforce(model.MACSELinux, 22)
+
+		require.NoError(t, err)
+		assert.True(t, status.RebootRequired)
+		mockRepo.AssertNotCalled(t, "LabelSSHPort", mock.Anything)
+	})
+
+	t.Run("AppArmor is the default on Debian", func(t *testing.T) {
+		mockRepo := new(MockMACRepository)
+		mockRepo.On("GetMACStatus").Return(&model.MACStatus{Mode: model.MACModeDisabled}, nil).Once()
+		mockRepo.On("InstallMAC", model.MACAppArmor).Return(nil)
+		mockRepo.On("EnforceMAC", model.MACAppArmor).Return(nil)
+		mockRepo.On("GetMACStatus").Return(&model.MACStatus{Framework: model.MACAppArmor,
+			Mode: model.MACModeEnforcing, EnforcedProfiles: 12}, nil).Once()
+
+		service := NewMACServiceImpl(mockRepo, model.OSInfo{Type: "debian"})
+		status, err := service.Enforce("", 2222)
+
+		require.NoError(t, err)
+		assert.Equal(t, "AppArmor enforcing (12 profiles enforced)", status.Describe())
+		mockRepo.AssertNotCalled(t, "LabelSSHPort", mock.Anything)
+	})
+
+	t.Run("AppArmor without enforced profiles", func(t *testing.T) {
+		mockRepo := new(MockMACRepository)
+		complain := &model.MACStatus{Framework: model.MACAppArmor, Mode: model.MACModePermissive, ComplainProfiles: 3}
+		mockRepo.On("GetMACStatus").Return(complain, nil)
+		mockRepo.On("InstallMAC", model.MACAppArmor).Return(nil)
+		mockRepo.On("EnforceMAC", model.MACAppArmor).Return(nil)
+
+		service := NewMACServiceImpl(mockRepo, model.OSInfo{Type: "ubuntu"})
+		status, err := service.Enforce(model.MACAppArmor, 22)
+
+		assert.ErrorContains(t, err, "no profiles are enforced")
+		assert.Equal(t, complain, status)
+	})
+
+	t.Run("refuses a second framework", func(t *testing.T) {
+		mockRepo := new(MockMACRepository)
+		mockRepo.On("GetMACStatus").Return(&model.MACStatus{Framework: model.MACAppArmor, Mode: model.MACModeEnforcing}, nil)
+
+		service := NewMACServiceImpl(mockRepo, model.OSInfo{Type: "ubuntu"})
+		_, err := service.Enforce(model.MACSELinux, 22)
+
+		assert.ErrorContains(t, err, "AppArmor is the active security module")
+		mockRepo.AssertNotCalled(t, "InstallMAC", mock.Anything)
+	})
+
+	t.Run("rejects SELinux on Alpine and unknown frameworks", func(t *testing.T) {
+		mockRepo := new(MockMACRepository)
+		mockRepo.On("GetMACStatus").Return(&model.MACStatus{Mode: model.MACModeDisabled}, nil)
+
+		service := NewMACServiceImpl(mockRepo, model.OSInfo{Type: "alpine"})
+		_, err := service.Enforce(model.MACSELinux, 22)
+		assert.ErrorContains(t, err, "not available on Alpine")
+
+		_, err = service.Enforce("smack", 22)
+		assert.ErrorContains(t, err, "unknown MAC framework")
+		mockRepo.AssertNotCalled(t, "InstallMAC", mock.Anything)
+	})
+}
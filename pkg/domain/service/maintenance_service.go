@@ -0,0 +1,93 @@
+// pkg/domain/service/maintenance_service.go
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// MaintenanceService decides whether mutating operations may run now
+type MaintenanceService interface {
+	// report whether windows are configured at all
+	HasWindows() bool
+
+	// report whether t falls inside any maintenance window
+	InWindow(t time.Time) bool
+
+	// return the next time a window opens at or after t
+	NextWindow(t time.Time) (time.Time, bool)
+
+	// return an error if a mutating operation may not run at t
+	CheckMutation(t time.Time, override bool) error
+}
+
+// MaintenanceServiceImpl implements MaintenanceService
+type MaintenanceServiceImpl struct {
+	windows []model.MaintenanceWindow
+}
+
+// NewMaintenanceServiceImpl creates a new MaintenanceServiceImpl
+func NewMaintenanceServiceImpl(windows []model.MaintenanceWindow) *MaintenanceServiceImpl {
+	return &MaintenanceServiceImpl{
+		windows: windows,
+	}
+}
+
+// ParseMaintenanceWindows parses window specs such as "Sat 02:00-04:00"
+func ParseMaintenanceWindows(specs []string) ([]model.MaintenanceWindow, error) {
+	var windows []model.MaintenanceWindow
+	for _, spec := range specs {
+		window, err := model.ParseMaintenanceWindow(spec)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+func (s *MaintenanceServiceImpl) HasWindows() bool {
+	return len(s.windows) > 0
+}
+
+// InWindow reports whether t is inside a window; with no windows configured
+// changes are always allowed
+func (s *MaintenanceServiceImpl) InWindow(t time.Time) bool {
+	if len(s.windows) == 0 {
+		return true
+	}
+	for _, window := range s.windows {
+		if window.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MaintenanceServiceImpl) NextWindow(t time.Time) (time.Time, bool) {
+	var next time.Time
+	for _, window := range s.windows {
+		start := window.NextStart(t)
+		if start.IsZero() {
+			continue
+		}
+		if next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// CheckMutation refuses changes outside every maintenance window unless overridden
+func (s *MaintenanceServiceImpl) CheckMutation(t time.Time, override bool) error {
+	if override || s.InWindow(t) {
+		return nil
+	}
+
+	if next, ok := s.NextWindow(t); ok {
+		return fmt.Errorf("outside maintenance window; next window opens %s", next.Format("Mon 2006-01-02 15:04"))
+	}
+	return fmt.Errorf("outside maintenance window")
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMaintenanceWindows(t *testing.T) {
+	tests := []struct {
+		name        string
+		specs       []string
+		expectDays  int
+		expectError bool
+	}{
+		{name: "single day", specs: []string{"Sat 02:00-04:00"}, expectDays: 1},
+		{name: "day range", specs: []string{"Mon-Fri 22:00-23:30"}, expectDays: 5},
+		{name: "wrapping range", specs: []string{"Fri-Mon 22:00-02:00"}, expectDays: 4},
+		{name: "day list", specs: []string{"sat,sun 00:00-24:00"}, expectDays: 2},
+		{name: "daily", specs: []string{"daily 01:00-03:00"}, expectDays: 0},
+		{name: "unknown day", specs: []string{"Funday 01:00-03:00"}, expectError: true},
+		{name: "missing range", specs: []string{"Sat 02:00"}, expectError: true},
+		{name: "bad time", specs: []string{"Sat 25:00-26:00"}, expectError: true},
+		{name: "empty window", specs: []string{"Sat 02:00-02:00"}, expectError: true},
+		{name: "missing days", specs: []string{"02:00-04:00"}, expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			windows, err := ParseMaintenanceWindows(tc.specs)
+
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, windows, 1)
+			assert.Len(t, windows[0].Days, tc.expectDays)
+		})
+	}
+}
+
+func TestMaintenanceServiceImpl_CheckMutation(t *testing.T) {
+	windows, err := ParseMaintenanceWindows([]string{"Sat 02:00-04:00", "Sun 23:00-01:00"})
+	assert.NoError(t, err)
+	service := NewMaintenanceServiceImpl(windows)
+
+	// 2024-06-01 is a Saturday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 6, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		override    bool
+		expectError bool
+	}{
+		{name: "inside window", now: at(1, 3, 0)},
+		{name: "window start is inclusive", now: at(1, 2, 0)},
+		{name: "window end is exclusive", now: at(1, 4, 0), expectError: true},
+		{name: "wrong day", now: at(3, 3, 0), expectError: true},
+		{name: "overnight window before midnight", now: at(2, 23, 30)},
+		{name: "overnight window after midnight", now: at(3, 0, 30)},
+		{name: "overnight window wrong day", now: at(2, 0, 30), expectError: true},
+		{name: "override outside window", now: at(4, 12, 0), override: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := service.CheckMutation(tc.now, tc.override)
+
+			if tc.expectError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "next window opens")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMaintenanceServiceImpl_NextWindow(t *testing.T) {
+	windows, err := ParseMaintenanceWindows([]string{"Sat 02:00-04:00", "Wed 22:00-23:00"})
+	assert.NoError(t, err)
+	service := NewMaintenanceServiceImpl(windows)
+
+	// Monday 2024-06-03 -> Wednesday evening comes first
+	next, ok := service.NextWindow(time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, 6, 5, 22, 0, 0, 0, time.UTC), next)
+
+	// Saturday after the window closed -> next Wednesday
+	next, ok = service.NextWindow(time.Date(2024, 6, 1, 5, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, 6, 5, 22, 0, 0, 0, time.UTC), next)
+}
+
+func TestMaintenanceServiceImpl_NoWindows(t *testing.T) {
+	service := NewMaintenanceServiceImpl(nil)
+
+	assert.False(t, service.HasWindows())
+	assert.True(t, service.InWindow(time.Now()))
+	assert.NoError(t, service.CheckMutation(time.Now(), false))
+
+	_, ok := service.NextWindow(time.Now())
+	assert.False(t, ok)
+}
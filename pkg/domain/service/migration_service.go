@@ -0,0 +1,422 @@
+// pkg/domain/service/migration_service.go
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// MigrationService defines operations for adopting hosts hardened by other
+// tools
+type MigrationService interface {
+	// ImportHost reads the ufw rules, sshd_config, sudoers.d entries and
+	// fail2ban jails on the host and returns the equivalent hardn settings
+	ImportHost() (*model.MigrationResult, error)
+}
+
+// MigrationServiceImpl implements MigrationService
+type MigrationServiceImpl struct {
+	repository MigrationRepository
+}
+
+// NewMigrationServiceImpl creates a new MigrationServiceImpl
+func NewMigrationServiceImpl(repository MigrationRepository) *MigrationServiceImpl {
+	return &MigrationServiceImpl{
+		repository: repository,
+	}
+}
+
+// MigrationRepository defines the repository operations needed by MigrationService
+type MigrationRepository interface {
+	ReadArtifact(path string) ([]byte, error)
+	ListArtifacts(dir string) ([]string, error)
+}
+
+// sshdIncludeDepth limits nested Include directives, as sshd does
+const sshdIncludeDepth = 16
+
+// ufwPolicies maps the iptables targets of /etc/default/ufw to ufw policies
+var ufwPolicies = map[string]string{
+	"DROP":   "deny",
+	"ACCEPT": "allow",
+	"REJECT": "reject",
+}
+
+func (s *MigrationServiceImpl) ImportHost() (*model.MigrationResult, error) {
+	result := &model.MigrationResult{}
+	for _, importer := range []func(*model.MigrationResult) error{
+		s.importUfw,
+		s.importSshd,
+		s.importSudoers,
+		s.importFail2ban,
+	} {
+		if err := importer(result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// importUfw takes the default policies and the ports open to any address
+func (s *MigrationServiceImpl) importUfw(result *model.MigrationResult) error {
+	defaults, err := s.repository.ReadArtifact(model.MigrationUfwDefaultsPath)
+	if err != nil {
+		return err
+	}
+	if defaults != nil {
+		artifact := model.MigrationArtifact{Source: model.MigrationSourceUfw, Path: model.MigrationUfwDefaultsPath}
+		for _, line := range strings.Split(string(defaults), "\n") {
+			key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+			if !ok {
+				continue
+			}
+			policy, known := ufwPolicies[strings.Trim(value, `"'`)]
+			switch {
+			case !known:
+				continue
+			case key == "DEFAULT_INPUT_POLICY":
+				result.UfwDefaultIncomingPolicy = policy
+				artifact.Imported = append(artifact.Imported, "ufwDefaultIncomingPolicy: "+policy)
+			case key == "DEFAULT_OUTPUT_POLICY":
+				result.UfwDefaultOutgoingPolicy = policy
+				artifact.Imported = append(artifact.Imported, "ufwDefaultOutgoingPolicy: "+policy)
+			}
+		}
+		result.Artifacts = append(result.Artifacts, artifact)
+	}
+
+	seen := make(map[int]bool)
+	for _, path := range []string{model.MigrationUfwRulesPath, model.MigrationUfw6RulesPath} {
+		data, err := s.repository.ReadArtifact(path)
+		if err != nil {
+			return err
+		}
+		if data == nil {
+			continue
+		}
+
+		artifact := model.MigrationArtifact{Source: model.MigrationSourceUfw, Path: path}
+		for _, line := range strings.Split(string(data), "\n") {
+			rule, ok := strings.CutPrefix(strings.TrimSpace(line), "### tuple ###")
+			if !ok {
+				continue
+			}
+			ports, reason := parseUfwTuple(strings.Fields(rule))
+			if reason != "" {
+				artifact.Skipped = append(artifact.Skipped, strings.TrimSpace(rule)+": "+reason)
+				continue
+			}
+			for _, port := range ports {
+				if seen[port] {
+					continue
+				}
+				seen[port] = true
+				result.UfwAllowedPorts = append(result.UfwAllowedPorts, port)
+				artifact.Imported = append(artifact.Imported, fmt.Sprintf("ufwAllowedPorts: %d", port))
+			}
+		}
+		result.Artifacts = append(result.Artifacts, artifact)
+	}
+	return nil
+}
+
+// parseUfwTuple returns the ports of a rule ufw keeps as "action proto
+// dport dst sport src [dapp sapp] direction", or why hardn cannot express
+// the rule
+func parseUfwTuple(fields []string) ([]int, string) {
+	if len(fields) < 7 {
+		return nil, "unrecognized rule"
+	}
+	action, proto, dport, dst, sport, src := fields[0], fields[1], fields[2], fields[3], fields[4], fields[5]
+
+	switch {
+	case fields[len(fields)-1] != "in":
+		return nil, "outgoing rules are not imported"
+	case action != "allow":
+		return nil, "only allow rules are imported"
+	case !isAnyAddress(src) || !isAnyAddress(dst):
+		return nil, "rules limited to addresses are not imported"
+	case sport != "any":
+		return nil, "rules on source ports are not imported"
+	case proto != "tcp" && proto != "any":
+		return nil, "only TCP ports are imported"
+	case dport == "any":
+		return nil, "rules without a port are not imported"
+	}
+
+	var ports []int
+	for _, value := range strings.Split(dport, ",") {
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 1 || port > 65535 {
+			return nil, "port ranges are not imported"
+		}
+		ports = append(ports, port)
+	}
+	return ports, ""
+}
+
+// isAnyAddress reports whether a ufw address matches every host
+func isAnyAddress(address string) bool {
+	return address == "0.0.0.0/0" || address == "::/0" || address == "any"
+}
+
+// sshdImport carries the keywords already read across included files; for
+// most keywords sshd uses the first value it reads
+type sshdImport struct {
+	result *model.MigrationResult
+	seen   map[string]bool
+}
+
+// importSshd follows sshd_config and its includes
+func (s *MigrationServiceImpl) importSshd(result *model.MigrationResult) error {
+	data, err := s.repository.ReadArtifact(model.MigrationSshdConfigPath)
+	if err != nil || data == nil {
+		return err
+	}
+	state := &sshdImport{result: result, seen: make(map[string]bool)}
+	return s.importSshdFile(state, model.MigrationSshdConfigPath, data, 0)
+}
+
+func (s *MigrationServiceImpl) importSshdFile(state *sshdImport, path string, data []byte, depth int) error {
+	result := state.result
+	index := len(result.Artifacts)
+	result.Artifacts = append(result.Artifacts, model.MigrationArtifact{Source: model.MigrationSourceSshd, Path: path})
+	// Included files append their own artifacts, so this one is looked up by index
+	note := func(imported bool, text string) {
+		if imported {
+			result.Artifacts[index].Imported = append(result.Artifacts[index].Imported, text)
+		} else {
+			result.Artifacts[index].Skipped = append(result.Artifacts[index].Skipped, text)
+		}
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value := line, ""
+		if i := strings.IndexAny(line, " \t="); i >= 0 {
+			key, value = line[:i], strings.TrimSpace(strings.TrimLeft(line[i:], " \t="))
+		}
+		key = strings.ToLower(key)
+
+		switch key {
+		case "match":
+			// A Match block runs to the end of the file
+			note(false, line+": Match blocks are not imported")
+			return nil
+
+		case "include":
+			if depth >= sshdIncludeDepth {
+				return fmt.Errorf("too many nested Include directives in %s", path)
+			}
+			if err := s.importSshdInclude(state, value, depth); err != nil {
+				return err
+			}
+
+		case "port":
+			port, err := strconv.Atoi(value)
+			switch {
+			case err != nil:
+				note(false, line+": not a port number")
+			case result.SshPort == 0:
+				result.SshPort = port
+				note(true, fmt.Sprintf("sshPort: %d", port))
+			case port != result.SshPort:
+				note(false, line+": hardn configures a single port")
+			}
+
+		case "permitrootlogin":
+			if state.seen[key] {
+				continue
+			}
+			state.seen[key] = true
+			permit := strings.ToLower(value) == "yes"
+			result.PermitRootLogin = &permit
+			note(true, fmt.Sprintf("permitRootLogin: %t", permit))
+			if !permit && strings.ToLower(value) != "no" {
+				note(false, line+": imported as no; hardn allows root logins fully or not at all")
+			}
+
+		case "allowusers":
+			users := strings.Fields(value)
+			result.SshAllowedUsers = append(result.SshAllowedUsers, users...)
+			note(true, "sshAllowedUsers: "+strings.Join(users, ", "))
+
+		case "listenaddress":
+			result.SshListenAddresses = append(result.SshListenAddresses, value)
+			note(true, "sshListenAddresses: "+value)
+
+		case "passwordauthentication":
+			if state.seen[key] {
+				continue
+			}
+			state.seen[key] = true
+			if strings.ToLower(value) == "yes" {
+				note(false, line+": hardn disables password logins; install SSH keys first")
+			}
+
+		default:
+			note(false, line+": not managed by hardn")
+		}
+	}
+	return nil
+}
+
+// importSshdInclude reads the files an Include directive names; relative
+// patterns are relative to /etc/ssh and matches are read in lexical order
+func (s *MigrationServiceImpl) importSshdInclude(state *sshdImport, value string, depth int) error {
+	for _, pattern := range strings.Fields(value) {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(model.MigrationSshdConfigPath), pattern)
+		}
+		paths, err := s.repository.ListArtifacts(filepath.Dir(pattern))
+		if err != nil {
+			return err
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			if matched, _ := filepath.Match(pattern, path); !matched {
+				continue
+			}
+			data, err := s.repository.ReadArtifact(path)
+			if err != nil {
+				return err
+			}
+			if data == nil {
+				continue
+			}
+			if err := s.importSshdFile(state, path, data, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// importSudoers takes the first user granted every command as the hardn
+// user. Like sudo, files with a dot in their name or ending in ~ are ignored.
+func (s *MigrationServiceImpl) importSudoers(result *model.MigrationResult) error {
+	paths, err := s.repository.ListArtifacts(model.MigrationSudoersDir)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		name := filepath.Base(path)
+		if strings.Contains(name, ".") || strings.HasSuffix(name, "~") || name == "README" {
+			continue
+		}
+		data, err := s.repository.ReadArtifact(path)
+		if err != nil {
+			return err
+		}
+		if data == nil {
+			continue
+		}
+
+		artifact := model.MigrationArtifact{Source: model.MigrationSourceSudoers, Path: path}
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || (strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "#include")) {
+				continue
+			}
+			if reason := importSudoersLine(result, line); reason != "" {
+				artifact.Skipped = append(artifact.Skipped, line+": "+reason)
+				continue
+			}
+			artifact.Imported = append(artifact.Imported,
+				"username: "+result.Username,
+				fmt.Sprintf("sudoNoPassword: %t", *result.SudoNoPassword))
+		}
+		result.Artifacts = append(result.Artifacts, artifact)
+	}
+	return nil
+}
+
+// importSudoersLine imports a user specification, or returns why the line
+// has no hardn equivalent
+func importSudoersLine(result *model.MigrationResult, line string) string {
+	fields := strings.Fields(line)
+	first := fields[0]
+	switch {
+	case strings.HasPrefix(first, "#include"), strings.HasPrefix(first, "@include"):
+		return "includes are not followed"
+	case strings.HasPrefix(first, "Defaults"):
+		return "not managed by hardn"
+	case strings.HasSuffix(first, "_Alias"):
+		return "aliases are not imported"
+	case strings.HasPrefix(first, "%"):
+		return "group rules are not imported"
+	case !strings.Contains(line, "="):
+		return "unrecognized entry"
+	case !strings.HasSuffix(line, "ALL"):
+		return "hardn grants every command, so rules limited to commands are not imported"
+	case first == "root":
+		return "root needs no hardn user"
+	case strings.Contains(first, ","):
+		return "rules for several users are not imported"
+	case result.Username != "":
+		return "hardn manages a single sudo user, " + result.Username
+	}
+
+	noPassword := strings.Contains(line, "NOPASSWD:")
+	result.Username = first
+	result.SudoNoPassword = &noPassword
+	return ""
+}
+
+// importFail2ban records fail2ban as a package to keep; its jails stay in
+// jail.local, which hardn does not manage
+func (s *MigrationServiceImpl) importFail2ban(result *model.MigrationResult) error {
+	data, err := s.repository.ReadArtifact(model.MigrationFail2banPath)
+	if err != nil || data == nil {
+		return err
+	}
+
+	artifact := model.MigrationArtifact{Source: model.MigrationSourceFail2ban, Path: model.MigrationFail2banPath}
+	result.Packages = append(result.Packages, "fail2ban")
+	artifact.Imported = append(artifact.Imported, "packages: fail2ban")
+
+	var jails []string
+	enabled := make(map[string]bool)
+	section := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if section != "DEFAULT" && section != "INCLUDES" {
+				jails = append(jails, section)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(key) == "enabled" {
+			enabled[section] = strings.ToLower(strings.TrimSpace(value)) == "true"
+		}
+	}
+
+	for _, jail := range jails {
+		on, set := enabled[jail]
+		if !set {
+			on = enabled["DEFAULT"]
+		}
+		state := "disabled"
+		if on {
+			state = "enabled"
+		}
+		artifact.Skipped = append(artifact.Skipped,
+			fmt.Sprintf("jail %s (%s): jails stay in jail.local", jail, state))
+	}
+	result.Artifacts = append(result.Artifacts, artifact)
+	return nil
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockMigrationRepository is a mock implementation of MigrationRepository
+type MockMigrationRepository struct {
+	mock.Mock
+}
+
+func (m *MockMigrationRepository) ReadArtifact(path string) ([]byte, error) {
+	args := m.Called(path)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockMigrationRepository) ListArtifacts(dir string) ([]string, error) {
+	args := m.Called(dir)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// newMockMigrationRepository serves files from a map of paths; every other
+// path is missing
+func newMockMigrationRepository(files map[string]string) *MockMigrationRepository {
+	repo := new(MockMigrationRepository)
+	dirs := make(map[string][]string)
+	for path, data := range files {
+		repo.On("ReadArtifact", path).Return([]byte(data), nil)
+		dirs[filepath.Dir(path)] = append(dirs[filepath.Dir(path)], path)
+	}
+	for dir, paths := range dirs {
+		sort.Strings(paths)
+		repo.On("ListArtifacts", dir).Return(paths, nil)
+	}
+	repo.On("ReadArtifact", mock.Anything).Return(nil, nil)
+	repo.On("ListArtifacts", mock.Anything).Return(nil, nil)
+	return repo
+}
+
+const ufwUserRules = `*filter
+:ufw-user-input - [0:0]
+### RULES ###
+
+### tuple ### allow tcp 2222 0.0.0.0/0 any 0.0.0.0/0 in
+-A ufw-user-input -p tcp --dport 2222 -j ACCEPT
+
+### tuple ### allow any 80,443 0.0.0.0/0 any 0.0.0.0/0 in
+### tuple ### allow tcp 5432 0.0.0.0/0 any 10.0.0.0/8 in
+### tuple ### limit tcp 22 0.0.0.0/0 any 0.0.0.0/0 in
+### tuple ### allow tcp 6000:6010 0.0.0.0/0 any 0.0.0.0/0 in
+### END RULES ###
+`
+
+func TestMigrationServiceImpl_ImportHost_Ufw(t *testing.T) {
+	repo := newMockMigrationRepository(map[string]string{
+		model.MigrationUfwDefaultsPath: "IPV6=yes\nDEFAULT_INPUT_POLICY=\"DROP\"\nDEFAULT_OUTPUT_POLICY=\"ACCEPT\"\n",
+		model.MigrationUfwRulesPath:    ufwUserRules,
+		model.MigrationUfw6RulesPath:   "### tuple ### allow tcp 2222 ::/0 any ::/0 in\n",
+	})
+
+	result, err := NewMigrationServiceImpl(repo).ImportHost()
+	require.NoError(t, err)
+
+	assert.Equal(t, "deny", result.UfwDefaultIncomingPolicy)
+	assert.Equal(t, "allow", result.UfwDefaultOutgoingPolicy)
+	// The IPv6 copy of a rule is not imported twice
+	assert.Equal(t, []int{2222, 80, 443}, result.UfwAllowedPorts)
+
+	require.Len(t, result.Artifacts, 3)
+	rules := result.Artifacts[1]
+	assert.Equal(t, model.MigrationUfwRulesPath, rules.Path)
+	assert.Equal(t, []string{
+		"allow tcp 5432 0.0.0.0/0 any 10.0.0.0/8 in: rules limited to addresses are not imported",
+		"limit tcp 22 0.0.0.0/0 any 0.0.0.0/0 in: only allow rules are imported",
+		"allow tcp 6000:6010 0.0.0.0/0 any 0.0.0.0/0 in: port ranges are not imported",
+	}, rules.Skipped)
+	assert.Empty(t, result.Artifacts[2].Imported)
+}
+
+func TestMigrationServiceImpl_ImportHost_Sshd(t *testing.T) {
+	repo := newMockMigrationRepository(map[string]string{
+		model.MigrationSshdConfigPath: `Include /etc/ssh/sshd_config.d/*.conf
+Port 22
+PermitRootLogin yes
+PasswordAuthentication yes
+X11Forwarding no
+Match User backup
+	PasswordAuthentication no
+`,
+		"/etc/ssh/sshd_config.d/10-hardening.conf": "Port=2222\nPermitRootLogin prohibit-password\nAllowUsers alice bob\nListenAddress 10.0.0.5\n",
+		"/etc/ssh/sshd_config.d/README":            "Port 2022\n",
+	})
+
+	result, err := NewMigrationServiceImpl(repo).ImportHost()
+	require.NoError(t, err)
+
+	// The included file comes first, so its values win
+	assert.Equal(t, 2222, result.SshPort)
+	require.NotNil(t, result.PermitRootLogin)
+	assert.False(t, *result.PermitRootLogin)
+	assert.Equal(t, []string{"alice", "bob"}, result.SshAllowedUsers)
+	assert.Equal(t, []string{"10.0.0.5"}, result.SshListenAddresses)
+
+	require.Len(t, result.Artifacts, 2)
+	sshdConfig, included := result.Artifacts[0], result.Artifacts[1]
+	assert.Equal(t, "/etc/ssh/sshd_config.d/10-hardening.conf", included.Path)
+	assert.Contains(t, included.Skipped, "PermitRootLogin prohibit-password: imported as no; hardn allows root logins fully or not at all")
+	assert.Equal(t, []string{
+		"Port 22: hardn configures a single port",
+		"PasswordAuthentication yes: hardn disables password logins; install SSH keys first",
+		"X11Forwarding no: not managed by hardn",
+		"Match User backup: Match blocks are not imported",
+	}, sshdConfig.Skipped)
+	assert.Empty(t, sshdConfig.Imported)
+}
+
+func TestMigrationServiceImpl_ImportHost_Sudoers(t *testing.T) {
+	repo := newMockMigrationRepository(map[string]string{
+		"/etc/sudoers.d/admins":      "Defaults:alice !requiretty\n%admins ALL=(ALL) ALL\nalice ALL=(ALL:ALL) NOPASSWD: ALL\nbob ALL=(ALL) ALL\n",
+		"/etc/sudoers.d/backup":      "backup ALL=(root) NOPASSWD: /usr/bin/rsync\n",
+		"/etc/sudoers.d/README":      "carol ALL=(ALL) ALL\n",
+		"/etc/sudoers.d/old.dpkg-bk": "dave ALL=(ALL) ALL\n",
+	})
+
+	result, err := NewMigrationServiceImpl(repo).ImportHost()
+	require.NoError(t, err)
+
+	assert.Equal(t, "alice", result.Username)
+	require.NotNil(t, result.SudoNoPassword)
+	assert.True(t, *result.SudoNoPassword)
+
+	require.Len(t, result.Artifacts, 2)
+	admins := result.Artifacts[0]
+	assert.Equal(t, []string{"username: alice", "sudoNoPassword: true"}, admins.Imported)
+	assert.Equal(t, []string{
+		"Defaults:alice !requiretty: not managed by hardn",
+		"%admins ALL=(ALL) ALL: group rules are not imported",
+		"bob ALL=(ALL) ALL: hardn manages a single sudo user, alice",
+	}, admins.Skipped)
+	assert.Equal(t, []string{
+		"backup ALL=(root) NOPASSWD: /usr/bin/rsync: hardn grants every command, so rules limited to commands are not imported",
+	}, result.Artifacts[1].Skipped)
+}
+
+func TestMigrationServiceImpl_ImportHost_Fail2ban(t *testing.T) {
+	repo := newMockMigrationRepository(map[string]string{
+		model.MigrationFail2banPath: "[DEFAULT]\nbantime = 1h\nenabled = true\n\n[sshd]\nport = 2222\n\n[nginx-http-auth]\nenabled = false\n",
+	})
+
+	result, err := NewMigrationServiceImpl(repo).ImportHost()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"fail2ban"}, result.Packages)
+	require.Len(t, result.Artifacts, 1)
+	assert.Equal(t, []string{
+		"jail sshd (enabled): jails stay in jail.local",
+		"jail nginx-http-auth (disabled): jails stay in jail.local",
+	}, result.Artifacts[0].Skipped)
+}
+
+func TestMigrationServiceImpl_ImportHost_NothingFound(t *testing.T) {
+	result, err := NewMigrationServiceImpl(newMockMigrationRepository(nil)).ImportHost()
+	require.NoError(t, err)
+	assert.False(t, result.Found())
+	assert.Zero(t, result.SshPort)
+	assert.Nil(t, result.PermitRootLogin)
+}
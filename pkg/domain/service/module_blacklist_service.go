@@ -0,0 +1,112 @@
+// pkg/domain/service/module_blacklist_service.go
+package service
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// moduleNamePattern matches kernel module names
+var moduleNamePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// ModuleBlacklistService defines operations for kernel module blacklists
+type ModuleBlacklistService interface {
+	// EffectiveModules lists the modules a policy blacklists on this host:
+	// the configured ones, without squashfs where snapd needs it, and
+	// usb-storage when the policy asks for it
+	EffectiveModules(policy model.ModuleBlacklistPolicy) []string
+
+	// ApplyBlacklist writes the blacklist of a policy
+	ApplyBlacklist(policy model.ModuleBlacklistPolicy) error
+
+	// GetBlacklistStatus compares the modprobe configuration and the loaded
+	// modules with a policy
+	GetBlacklistStatus(policy model.ModuleBlacklistPolicy) (*model.ModuleBlacklistStatus, error)
+}
+
+// ModuleBlacklistServiceImpl implements ModuleBlacklistService
+type ModuleBlacklistServiceImpl struct {
+	repository ModuleBlacklistRepository
+	osInfo     model.OSInfo
+}
+
+// NewModuleBlacklistServiceImpl creates a new ModuleBlacklistServiceImpl
+func NewModuleBlacklistServiceImpl(repository ModuleBlacklistRepository, osInfo model.OSInfo) *ModuleBlacklistServiceImpl {
+	return &ModuleBlacklistServiceImpl{
+		repository: repository,
+		osInfo:     osInfo,
+	}
+}
+
+// ModuleBlacklistRepository defines the repository operations needed by ModuleBlacklistService
+type ModuleBlacklistRepository interface {
+	WriteBlacklist(modules []string) error
+	GetDisabledModules() ([]string, error)
+	GetLoadedModules() ([]string, error)
+	SnapdInstalled() bool
+}
+
+func (s *ModuleBlacklistServiceImpl) EffectiveModules(policy model.ModuleBlacklistPolicy) []string {
+	modules := append([]string{}, policy.Modules...)
+	if policy.UsbStorage {
+		modules = append(modules, model.UsbStorageModule)
+	}
+
+	snapd := s.repository.SnapdInstalled()
+	var effective []string
+	seen := make(map[string]bool)
+	for _, module := range modules {
+		name := model.NormalizeModuleName(module)
+		if seen[name] || (snapd && name == model.SquashfsModule) {
+			continue
+		}
+		seen[name] = true
+		effective = append(effective, module)
+	}
+	return effective
+}
+
+func (s *ModuleBlacklistServiceImpl) ApplyBlacklist(policy model.ModuleBlacklistPolicy) error {
+	modules := s.EffectiveModules(policy)
+	for _, module := range modules {
+		if !moduleNamePattern.MatchString(module) {
+			return fmt.Errorf("invalid kernel module name %q", module)
+		}
+	}
+	return s.repository.WriteBlacklist(modules)
+}
+
+func (s *ModuleBlacklistServiceImpl) GetBlacklistStatus(policy model.ModuleBlacklistPolicy) (*model.ModuleBlacklistStatus, error) {
+	disabledModules, err := s.repository.GetDisabledModules()
+	if err != nil {
+		return nil, err
+	}
+	disabled := make(map[string]bool)
+	for _, module := range disabledModules {
+		disabled[model.NormalizeModuleName(module)] = true
+	}
+
+	// Without /proc/modules the blacklist can still be checked
+	loaded := make(map[string]bool)
+	if loadedModules, err := s.repository.GetLoadedModules(); err == nil {
+		for _, module := range loadedModules {
+			loaded[model.NormalizeModuleName(module)] = true
+		}
+	}
+
+	status := &model.ModuleBlacklistStatus{}
+	for _, module := range s.EffectiveModules(policy) {
+		name := model.NormalizeModuleName(module)
+		if disabled[name] {
+			status.Disabled = append(status.Disabled, module)
+		} else {
+			status.Missing = append(status.Missing, module)
+		}
+		if loaded[name] {
+			status.Loaded = append(status.Loaded, module)
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockModuleBlacklistRepository is a mock implementation of ModuleBlacklistRepository
+type MockModuleBlacklistRepository struct {
+	mock.Mock
+}
+
+func (m *MockModuleBlacklistRepository) WriteBlacklist(modules []string) error {
+	args := m.Called(modules)
+	return args.Error(0)
+}
+
+func (m *MockModuleBlacklistRepository) GetDisabledModules() ([]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockModuleBlacklistRepository) GetLoadedModules() ([]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockModuleBlacklistRepository) SnapdInstalled() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+func TestModuleBlacklistServiceImpl_ApplyBlacklist(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        model.ModuleBlacklistPolicy
+		snapd         bool
+		expected      []string
+		expectedError bool
+	}{
+		{
+			name:     "default filesystems",
+			policy:   model.ModuleBlacklistPolicy{Modules: model.DefaultBlacklistedModules},
+			expected: []string{"cramfs", "freevxfs", "hfs", "hfsplus", "jffs2", "squashfs", "udf"},
+		},
+		{
+			name:     "squashfs kept for snaps",
+			policy:   model.ModuleBlacklistPolicy{Modules: []string{"cramfs", "squashfs"}},
+			snapd:    true,
+			expected: []string{"cramfs"},
+		},
+		{
+			name:     "usb-storage added once",
+			policy:   model.ModuleBlacklistPolicy{Modules: []string{"udf", "usb_storage"}, UsbStorage: true},
+			expected: []string{"udf", "usb_storage"},
+		},
+		{
+			name:     "empty list",
+			policy:   model.ModuleBlacklistPolicy{},
+			expected: nil,
+		},
+		{
+			name:          "invalid name",
+			policy:        model.ModuleBlacklistPolicy{Modules: []string{"cramfs /bin/sh"}},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(MockModuleBlacklistRepository)
+			repo.On("SnapdInstalled").Return(tc.snapd)
+			repo.On("WriteBlacklist", tc.expected).Return(nil).Maybe()
+
+			service := NewModuleBlacklistServiceImpl(repo, model.OSInfo{Type: "ubuntu", Version: "24.04"})
+			err := service.ApplyBlacklist(tc.policy)
+
+			if tc.expectedError {
+				assert.Error(t, err)
+				repo.AssertNotCalled(t, "WriteBlacklist", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestModuleBlacklistServiceImpl_GetBlacklistStatus(t *testing.T) {
+	policy := model.ModuleBlacklistPolicy{Modules: []string{"cramfs", "udf"}, UsbStorage: true}
+
+	t.Run("missing and loaded modules", func(t *testing.T) {
+		repo := new(MockModuleBlacklistRepository)
+		repo.On("SnapdInstalled").Return(false)
+		repo.On("GetDisabledModules").Return([]string{"cramfs", "usb_storage"}, nil)
+		repo.On("GetLoadedModules").Return([]string{"ext4", "usb_storage"}, nil)
+
+		service := NewModuleBlacklistServiceImpl(repo, model.OSInfo{Type: "debian", Version: "12"})
+		status, err := service.GetBlacklistStatus(policy)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"cramfs", "usb-storage"}, status.Disabled)
+		assert.Equal(t, []string{"udf"}, status.Missing)
+		assert.Equal(t, []string{"usb-storage"}, status.Loaded)
+		assert.False(t, status.Compliant())
+	})
+
+	t.Run("unreadable loaded modules", func(t *testing.T) {
+		repo := new(MockModuleBlacklistRepository)
+		repo.On("SnapdInstalled").Return(false)
+		repo.On("GetDisabledModules").Return([]string{"cramfs", "udf", "usb_storage"}, nil)
+		repo.On("GetLoadedModules").Return(nil, errors.New("no /proc"))
+
+		service := NewModuleBlacklistServiceImpl(repo, model.OSInfo{Type: "debian", Version: "12"})
+		status, err := service.GetBlacklistStatus(policy)
+
+		assert.NoError(t, err)
+		assert.True(t, status.Compliant())
+	})
+}
@@ -0,0 +1,178 @@
+// pkg/domain/service/network_exposure_service.go
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// NetworkExposureService defines operations for inventorying listening
+// services and how far the firewall exposes them
+type NetworkExposureService interface {
+	// GetNetworkExposure lists the listening sockets and whether the
+	// firewall lets other hosts reach each of them
+	GetNetworkExposure() (*model.NetworkExposure, error)
+
+	// AuditExposure reports services other hosts reach without a firewall
+	// rule deciding about them
+	AuditExposure() ([]model.PolicyViolation, error)
+}
+
+// NetworkExposureServiceImpl implements NetworkExposureService
+type NetworkExposureServiceImpl struct {
+	exposureRepo NetworkExposureRepository
+	firewallRepo FirewallRepository
+}
+
+// NewNetworkExposureServiceImpl creates a new NetworkExposureServiceImpl
+func NewNetworkExposureServiceImpl(exposureRepo NetworkExposureRepository, firewallRepo FirewallRepository) *NetworkExposureServiceImpl {
+	return &NetworkExposureServiceImpl{
+		exposureRepo: exposureRepo,
+		firewallRepo: firewallRepo,
+	}
+}
+
+// NetworkExposureRepository defines the repository operations needed by NetworkExposureService
+type NetworkExposureRepository interface {
+	GetListeningSockets() ([]model.ListeningSocket, error)
+}
+
+func (s *NetworkExposureServiceImpl) GetNetworkExposure() (*model.NetworkExposure, error) {
+	sockets, err := s.exposureRepo.GetListeningSockets()
+	if err != nil {
+		return nil, err
+	}
+
+	// A firewall that is off or lets unmatched traffic in filters nothing
+	_, enabled, configured, rules, err := s.firewallRepo.GetFirewallStatus()
+	if err != nil {
+		enabled = false
+	}
+	filtering := enabled && configured
+
+	exposure := &model.NetworkExposure{FirewallEnabled: enabled, FirewallFiltering: filtering}
+	for _, socket := range sockets {
+		service := model.ExposedService{ListeningSocket: socket}
+		switch {
+		case socket.Loopback():
+			service.Exposure = model.ExposureLocal
+		case !enabled:
+			service.Exposure = model.ExposureUnfiltered
+		default:
+			service.Exposure, service.Rule = socketExposure(filtering, rules, socket)
+		}
+		exposure.Services = append(exposure.Services, service)
+	}
+
+	sort.SliceStable(exposure.Services, func(i, j int) bool {
+		a, b := exposure.Services[i], exposure.Services[j]
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		return a.Protocol < b.Protocol
+	})
+
+	return exposure, nil
+}
+
+func (s *NetworkExposureServiceImpl) AuditExposure() ([]model.PolicyViolation, error) {
+	exposure, err := s.GetNetworkExposure()
+	if err != nil {
+		return nil, err
+	}
+
+	detail := " and the firewall lets unmatched traffic in"
+	if !exposure.FirewallEnabled {
+		detail = " and the firewall is disabled"
+	}
+
+	// IPv4 and IPv6 sockets of one service are reported once
+	var findings []model.PolicyViolation
+	reported := make(map[string]bool)
+	for _, service := range exposure.Unfiltered() {
+		key := fmt.Sprintf("%s/%d", service.Protocol, service.Port)
+		if reported[key] {
+			continue
+		}
+		reported[key] = true
+
+		findings = append(findings, model.PolicyViolation{
+			Rule:     "network.unfiltered_listener",
+			Severity: model.SeverityMedium,
+			Message: fmt.Sprintf("%s port %d (%s) listens on %s%s",
+				service.Protocol, service.Port, describeListener(service.ListeningSocket),
+				service.Address, detail),
+			Remediation: fmt.Sprintf("Bind the service to loopback or a private address if other hosts "+
+				"do not need it, or deny incoming traffic by default and allow %d/%s only from the "+
+				"sources that use it", service.Port, service.Protocol),
+		})
+	}
+
+	return findings, nil
+}
+
+// socketExposure finds the first firewall rule deciding about the socket's
+// port, falling back to the default incoming policy
+func socketExposure(filtering bool, rules []string, socket model.ListeningSocket) (string, string) {
+	port := strconv.Itoa(socket.Port)
+	for _, rule := range rules {
+		fields := strings.Fields(strings.ToLower(rule))
+		if len(fields) == 0 || !ruleMatches(fields, []string{port}, nil) || !ruleProtocol(fields, socket.Protocol) {
+			continue
+		}
+
+		source := "any"
+		for i, field := range fields {
+			if field == "from" && i+1 < len(fields) {
+				source = fields[i+1]
+			}
+		}
+		anywhere := source == "any" || source == "0.0.0.0/0" || source == "::/0"
+
+		switch fields[0] {
+		case "allow", "limit":
+			if anywhere {
+				return model.ExposureOpen, rule
+			}
+			return model.ExposureRestricted, rule
+		case "deny", "reject":
+			if anywhere {
+				return model.ExposureBlocked, rule
+			}
+		}
+	}
+
+	if filtering {
+		return model.ExposureBlocked, ""
+	}
+	return model.ExposureUnfiltered, ""
+}
+
+// ruleProtocol reports whether a rule limited to tcp or udp applies to protocol
+func ruleProtocol(fields []string, protocol string) bool {
+	for i, field := range fields {
+		if i > 0 && fields[i-1] == "proto" {
+			return field == protocol
+		}
+		if _, proto, found := strings.Cut(field, "/"); found && (proto == "tcp" || proto == "udp") {
+			return proto == protocol
+		}
+	}
+	return true
+}
+
+// describeListener names the process and package behind a socket
+func describeListener(socket model.ListeningSocket) string {
+	name := socket.Process
+	if name == "" {
+		name = "unknown process"
+	}
+	if socket.Package != "" && socket.Package != name {
+		name += ", package " + socket.Package
+	}
+	return name
+}
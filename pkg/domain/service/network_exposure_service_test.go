@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNetworkExposureRepository is a mock implementation of NetworkExposureRepository
+type MockNetworkExposureRepository struct {
+	mock.Mock
+}
+
+func (m *MockNetworkExposureRepository) GetListeningSockets() ([]model.ListeningSocket, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ListeningSocket), args.Error(1)
+}
+
+// exposures maps protocol/address to the exposure of each service
+func exposures(exposure *model.NetworkExposure) map[string]string {
+	result := make(map[string]string)
+	for _, service := range exposure.Services {
+		result[service.Protocol+"/"+service.Address] = service.Exposure
+	}
+	return result
+}
+
+func TestNetworkExposureServiceImpl_GetNetworkExposure(t *testing.T) {
+	sockets := []model.ListeningSocket{
+		{Protocol: "tcp", Address: "0.0.0.0", Port: 5432, Process: "postgres"},
+		{Protocol: "tcp", Address: "0.0.0.0", Port: 22, Process: "sshd"},
+		{Protocol: "tcp", Address: "::", Port: 80, Process: "nginx"},
+		{Protocol: "udp", Address: "0.0.0.0", Port: 53, Process: "dnsmasq"},
+		{Protocol: "udp", Address: "127.0.0.53", Port: 53, Process: "systemd-resolve"},
+		{Protocol: "tcp", Address: "10.0.0.5", Port: 6379, Process: "redis-server"},
+	}
+
+	t.Run("default deny firewall", func(t *testing.T) {
+		exposureRepo := new(MockNetworkExposureRepository)
+		exposureRepo.On("GetListeningSockets").Return(sockets, nil)
+		firewallRepo := &MockFirewallRepository{Installed: true, Enabled: true, Configured: true,
+			Rules: []string{"allow 22/tcp", "allow 53/tcp", "allow from 10.0.0.0/24 to any port 5432", "deny 80"}}
+
+		exposure, err := NewNetworkExposureServiceImpl(exposureRepo, firewallRepo).GetNetworkExposure()
+
+		assert.NoError(t, err)
+		assert.True(t, exposure.FirewallFiltering)
+		assert.Equal(t, []int{22, 53, 53, 80, 5432, 6379}, servicePorts(exposure))
+		assert.Equal(t, model.ExposureOpen, exposure.Services[0].Exposure)
+		assert.Equal(t, "allow 22/tcp", exposure.Services[0].Rule)
+		// 53/tcp does not open the udp socket
+		assert.Equal(t, model.ExposureBlocked, exposures(exposure)["udp/0.0.0.0"])
+		assert.Equal(t, model.ExposureLocal, exposures(exposure)["udp/127.0.0.53"])
+		assert.Equal(t, model.ExposureBlocked, exposure.Services[3].Exposure)
+		assert.Equal(t, model.ExposureRestricted, exposure.Services[4].Exposure)
+		assert.Equal(t, model.ExposureBlocked, exposure.Services[5].Exposure)
+		assert.Empty(t, exposure.Unfiltered())
+	})
+
+	t.Run("firewall lets unmatched traffic in", func(t *testing.T) {
+		exposureRepo := new(MockNetworkExposureRepository)
+		exposureRepo.On("GetListeningSockets").Return(sockets, nil)
+		firewallRepo := &MockFirewallRepository{Installed: true, Enabled: true,
+			Rules: []string{"allow 22/tcp"}}
+
+		exposure, err := NewNetworkExposureServiceImpl(exposureRepo, firewallRepo).GetNetworkExposure()
+
+		assert.NoError(t, err)
+		assert.False(t, exposure.FirewallFiltering)
+		assert.Len(t, exposure.Unfiltered(), 4)
+	})
+
+	t.Run("listing fails", func(t *testing.T) {
+		exposureRepo := new(MockNetworkExposureRepository)
+		exposureRepo.On("GetListeningSockets").Return(nil, errors.New("neither ss nor netstat"))
+
+		_, err := NewNetworkExposureServiceImpl(exposureRepo, &MockFirewallRepository{}).GetNetworkExposure()
+
+		assert.Error(t, err)
+	})
+}
+
+func TestNetworkExposureServiceImpl_AuditExposure(t *testing.T) {
+	exposureRepo := new(MockNetworkExposureRepository)
+	exposureRepo.On("GetListeningSockets").Return([]model.ListeningSocket{
+		{Protocol: "tcp", Address: "0.0.0.0", Port: 3306, Process: "mysqld", Package: "mysql-server-core-8.0"},
+		{Protocol: "tcp", Address: "::", Port: 3306, Process: "mysqld", Package: "mysql-server-core-8.0"},
+		{Protocol: "tcp", Address: "127.0.0.1", Port: 631, Process: "cupsd"},
+	}, nil)
+	firewallRepo := &MockFirewallRepository{Installed: true}
+
+	findings, err := NewNetworkExposureServiceImpl(exposureRepo, firewallRepo).AuditExposure()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"network.unfiltered_listener"}, findingRules(findings))
+	assert.Equal(t, model.SeverityMedium, findings[0].Severity)
+	assert.Equal(t, "tcp port 3306 (mysqld, package mysql-server-core-8.0) listens on 0.0.0.0 and the firewall is disabled",
+		findings[0].Message)
+	assert.Contains(t, findings[0].Remediation, "3306/tcp")
+}
+
+// servicePorts returns the port of each service in order
+func servicePorts(exposure *model.NetworkExposure) []int {
+	var ports []int
+	for _, service := range exposure.Services {
+		ports = append(ports, service.Port)
+	}
+	return ports
+}
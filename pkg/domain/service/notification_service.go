@@ -0,0 +1,61 @@
+// pkg/domain/service/notification_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// NotificationService defines operations for sending run summaries
+type NotificationService interface {
+	// Notify sends the summary through every configured notifier. It
+	// returns an error naming each notifier that failed.
+	Notify(summary model.RunSummary) error
+
+	// Enabled reports whether any notifier is configured
+	Enabled() bool
+}
+
+// NotificationServiceImpl implements NotificationService
+type NotificationServiceImpl struct {
+	notifiers     []Notifier
+	onlyOnFailure bool
+}
+
+// NewNotificationServiceImpl creates a new NotificationServiceImpl; with
+// onlyOnFailure, summaries of successful runs are not sent
+func NewNotificationServiceImpl(notifiers []Notifier, onlyOnFailure bool) *NotificationServiceImpl {
+	return &NotificationServiceImpl{
+		notifiers:     notifiers,
+		onlyOnFailure: onlyOnFailure,
+	}
+}
+
+// Notifier defines the delivery operations needed by NotificationService
+type Notifier interface {
+	Name() string
+	Send(summary model.RunSummary) error
+}
+
+func (s *NotificationServiceImpl) Notify(summary model.RunSummary) error {
+	// Posture summaries are sent on a schedule of their own, so they are
+	// wanted whatever the period held
+	if s.onlyOnFailure && !summary.Failed && summary.Operation != model.SummaryPosture {
+		return nil
+	}
+
+	// One unreachable notifier should not keep the others from sending
+	var errs []error
+	for _, notifier := range s.notifiers {
+		if err := notifier.Send(summary); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", notifier.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *NotificationServiceImpl) Enabled() bool {
+	return len(s.notifiers) > 0
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNotifier is a mock implementation of Notifier
+type MockNotifier struct {
+	mock.Mock
+	name string
+}
+
+func (m *MockNotifier) Name() string {
+	return m.name
+}
+
+func (m *MockNotifier) Send(summary model.RunSummary) error {
+	args := m.Called(summary)
+	return args.Error(0)
+}
+
+func TestNotificationServiceImpl_Notify(t *testing.T) {
+	summary := model.RunSummary{Hostname: "web1", Operation: model.SummaryRunAll, RiskBefore: "High", RiskAfter: "Low"}
+
+	t.Run("sends to every notifier", func(t *testing.T) {
+		email := &MockNotifier{name: "email"}
+		email.On("Send", summary).Return(errors.New("connection refused"))
+		webhook := &MockNotifier{name: "webhook hooks.slack.com"}
+		webhook.On("Send", summary).Return(nil)
+
+		err := NewNotificationServiceImpl([]Notifier{email, webhook}, false).Notify(summary)
+
+		// The failing notifier does not stop the webhook
+		assert.EqualError(t, err, "email: connection refused")
+		webhook.AssertExpectations(t)
+	})
+
+	t.Run("only on failure", func(t *testing.T) {
+		webhook := &MockNotifier{name: "webhook"}
+		service := NewNotificationServiceImpl([]Notifier{webhook}, true)
+
+		assert.NoError(t, service.Notify(summary))
+		webhook.AssertNotCalled(t, "Send", mock.Anything)
+
+		failed := summary
+		failed.Failed = true
+		failed.FailedSteps = []string{"Firewall: ufw not found"}
+		webhook.On("Send", failed).Return(nil)
+		assert.NoError(t, service.Notify(failed))
+		webhook.AssertExpectations(t)
+
+		// A posture summary is sent even for a quiet week
+		posture := model.RunSummary{Hostname: "web1", Operation: model.SummaryPosture, RiskAfter: "Low", Trend: model.TrendSteady}
+		webhook.On("Send", posture).Return(nil)
+		assert.NoError(t, service.Notify(posture))
+		webhook.AssertCalled(t, "Send", posture)
+	})
+
+	t.Run("nothing configured", func(t *testing.T) {
+		service := NewNotificationServiceImpl(nil, false)
+
+		assert.False(t, service.Enabled())
+		assert.NoError(t, service.Notify(summary))
+	})
+}
@@ -0,0 +1,114 @@
+// pkg/domain/service/package_audit_service.go
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// PackageAuditService defines operations for checking installed packages
+// against security advisories
+type PackageAuditService interface {
+	// InstalledPackages lists the installed packages with their versions
+	InstalledPackages() ([]model.InstalledPackage, error)
+
+	// CheckPackages checks the installed packages against the
+	// distribution's advisories and keeps the result
+	CheckPackages() (*model.PackageAuditReport, error)
+
+	// LastReport returns the last check, or nil when there has been none
+	LastReport() (*model.PackageAuditReport, error)
+}
+
+// PackageAuditServiceImpl implements PackageAuditService
+type PackageAuditServiceImpl struct {
+	repository PackageAuditRepository
+	now        func() time.Time
+}
+
+// NewPackageAuditServiceImpl creates a new PackageAuditServiceImpl
+func NewPackageAuditServiceImpl(repository PackageAuditRepository) *PackageAuditServiceImpl {
+	return &PackageAuditServiceImpl{
+		repository: repository,
+		now:        time.Now,
+	}
+}
+
+// PackageAuditRepository defines the repository operations needed by PackageAuditService
+type PackageAuditRepository interface {
+	InstalledPackages() ([]model.InstalledPackage, error)
+	AdvisorySource() string
+	CheckAdvisories(packages []model.InstalledPackage) ([]model.PackageVulnerability, error)
+	LoadReport() (*model.PackageAuditReport, error)
+	SaveReport(report *model.PackageAuditReport) error
+}
+
+func (s *PackageAuditServiceImpl) InstalledPackages() ([]model.InstalledPackage, error) {
+	packages, err := s.repository.InstalledPackages()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Name < packages[j].Name })
+	return packages, nil
+}
+
+// CheckPackages fills in the installed version of advisories that leave it
+// out, and sorts them most severe first. Advisories for packages that are
+// not installed, which some sources report for source packages, are kept
+// with an empty version.
+func (s *PackageAuditServiceImpl) CheckPackages() (*model.PackageAuditReport, error) {
+	source := s.repository.AdvisorySource()
+	if source == "" {
+		return nil, fmt.Errorf("no security advisory source for this distribution")
+	}
+
+	packages, err := s.InstalledPackages()
+	if err != nil {
+		return nil, err
+	}
+	vulnerabilities, err := s.repository.CheckAdvisories(packages)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]string, len(packages))
+	for _, pkg := range packages {
+		versions[pkg.Name] = pkg.Version
+	}
+	for i := range vulnerabilities {
+		if vulnerabilities[i].Version == "" {
+			vulnerabilities[i].Version = versions[vulnerabilities[i].Package]
+		}
+	}
+	sort.SliceStable(vulnerabilities, func(i, j int) bool {
+		a, b := vulnerabilities[i], vulnerabilities[j]
+		if a.Severity != b.Severity {
+			return model.MoreSevere(a.Severity, b.Severity)
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Package < b.Package
+	})
+	if vulnerabilities == nil {
+		vulnerabilities = []model.PackageVulnerability{}
+	}
+
+	report := &model.PackageAuditReport{
+		CheckedAt:       s.now(),
+		Source:          source,
+		Packages:        packages,
+		Vulnerabilities: vulnerabilities,
+	}
+	if err := s.repository.SaveReport(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *PackageAuditServiceImpl) LastReport() (*model.PackageAuditReport, error) {
+	return s.repository.LoadReport()
+}
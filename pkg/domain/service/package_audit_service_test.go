@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPackageAuditRepository is a mock implementation of PackageAuditRepository
+type MockPackageAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockPackageAuditRepository) InstalledPackages() ([]model.InstalledPackage, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.InstalledPackage), args.Error(1)
+}
+
+func (m *MockPackageAuditRepository) AdvisorySource() string {
+	return m.Called().String(0)
+}
+
+func (m *MockPackageAuditRepository) CheckAdvisories(packages []model.InstalledPackage) ([]model.PackageVulnerability, error) {
+	args := m.Called(packages)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PackageVulnerability), args.Error(1)
+}
+
+func (m *MockPackageAuditRepository) LoadReport() (*model.PackageAuditReport, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PackageAuditReport), args.Error(1)
+}
+
+func (m *MockPackageAuditRepository) SaveReport(report *model.PackageAuditReport) error {
+	return m.Called(report).Error(0)
+}
+
+func TestPackageAuditServiceImpl_CheckPackages(t *testing.T) {
+	checkedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	packages := []model.InstalledPackage{
+		{Name: "openssl", Version: "3.0.11-1~deb12u2"},
+		{Name: "libssl3", Version: "3.0.11-1~deb12u2"},
+		{Name: "bash", Version: "5.2.15-2+b2"},
+	}
+
+	mockRepo := new(MockPackageAuditRepository)
+	mockRepo.On("AdvisorySource").Return("debsecan")
+	mockRepo.On("InstalledPackages").Return(packages, nil)
+	mockRepo.On("CheckAdvisories", mock.Anything).Return([]model.PackageVulnerability{
+		{ID: "CVE-2024-0002", Package: "bash", Severity: model.SeverityLow},
+		{ID: "CVE-2024-0001", Package: "openssl", Severity: model.SeverityMedium},
+		{ID: "CVE-2024-0001", Package: "libssl3", Severity: model.SeverityHigh, FixAvailable: true},
+		{ID: "TEMP-0000000-1A2B3C", Package: "bash", Severity: model.SeverityUnknown},
+	}, nil)
+	mockRepo.On("SaveReport", mock.Anything).Return(nil)
+
+	service := NewPackageAuditServiceImpl(mockRepo)
+	service.now = func() time.Time { return checkedAt }
+	report, err := service.CheckPackages()
+
+	require.NoError(t, err)
+	assert.Equal(t, checkedAt, report.CheckedAt)
+	assert.Equal(t, "debsecan", report.Source)
+	assert.Equal(t, "bash", report.Packages[0].Name, "packages are sorted by name")
+
+	var order []string
+	for _, vulnerability := range report.Vulnerabilities {
+		order = append(order, vulnerability.Severity+" "+vulnerability.Package)
+	}
+	assert.Equal(t, []string{"high libssl3", "medium openssl", "low bash", "unknown bash"}, order)
+	assert.Equal(t, "3.0.11-1~deb12u2", report.Vulnerabilities[0].Version, "the installed version is filled in")
+	mockRepo.AssertCalled(t, "SaveReport", report)
+
+	// An advisory for several packages counts once, at its highest severity
+	summary := report.Summary()
+	assert.Equal(t, 3, summary.Total())
+	assert.Equal(t, 3, summary.Packages)
+	assert.Equal(t, "1 high, 1 low, 1 unknown", summary.Describe())
+}
+
+func TestPackageAuditServiceImpl_CheckPackages_Errors(t *testing.T) {
+	unsupported := new(MockPackageAuditRepository)
+	unsupported.On("AdvisorySource").Return("")
+	_, err := NewPackageAuditServiceImpl(unsupported).CheckPackages()
+	assert.EqualError(t, err, "no security advisory source for this distribution")
+	unsupported.AssertNotCalled(t, "InstalledPackages")
+
+	missingTool := new(MockPackageAuditRepository)
+	missingTool.On("AdvisorySource").Return("debsecan")
+	missingTool.On("InstalledPackages").Return([]model.InstalledPackage{{Name: "bash", Version: "5.2"}}, nil)
+	missingTool.On("CheckAdvisories", mock.Anything).Return(nil, errors.New("debsecan is not installed"))
+	_, err = NewPackageAuditServiceImpl(missingTool).CheckPackages()
+	assert.EqualError(t, err, "debsecan is not installed")
+	missingTool.AssertNotCalled(t, "SaveReport", mock.Anything)
+
+	// A clean host still has an empty list of advisories
+	clean := new(MockPackageAuditRepository)
+	clean.On("AdvisorySource").Return("dnf updateinfo")
+	clean.On("InstalledPackages").Return([]model.InstalledPackage{{Name: "bash", Version: "5.2"}}, nil)
+	clean.On("CheckAdvisories", mock.Anything).Return(nil, nil)
+	clean.On("SaveReport", mock.Anything).Return(nil)
+	report, err := NewPackageAuditServiceImpl(clean).CheckPackages()
+	require.NoError(t, err)
+	assert.NotNil(t, report.Vulnerabilities)
+	assert.Equal(t, 0, report.Summary().Total())
+}
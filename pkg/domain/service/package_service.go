@@ -1,7 +1,12 @@
 // pkg/domain/service/package_service.go
 package service
 
-import "github.com/abbott/hardn/pkg/domain/model"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
 
 // PackageService defines operations for package management
 type PackageService interface {
@@ -14,8 +19,35 @@ type PackageService interface {
 	// UpdateProxmoxSources updates Proxmox-specific package sources
 	UpdateProxmoxSources() error
 
+	// SourcesFormat returns the format repositories are written in, list or deb822
+	SourcesFormat() (string, error)
+
+	// MigrateSources converts the repository lists on the host to list or deb822
+	MigrateSources(format string) ([]model.SourcesMigration, error)
+
+	// ReadSourceFile reads a repository list, such as an APT list or /etc/apk/repositories
+	ReadSourceFile(path string) ([]byte, error)
+
 	// IsPackageInstalled checks if a package is installed
 	IsPackageInstalled(packageName string) (bool, error)
+
+	// AutoUpgradesSupported reports whether hardn manages automatic upgrades on this OS
+	AutoUpgradesSupported() bool
+
+	// ConfigureAutoUpgrades enables or disables scheduled package upgrades
+	ConfigureAutoUpgrades(enable bool) error
+
+	// IsAutoUpgradeEnabled checks if scheduled package upgrades are enabled
+	IsAutoUpgradeEnabled() (bool, error)
+
+	// NeedrestartSupported reports whether needrestart is available on this OS
+	NeedrestartSupported() bool
+
+	// ConfigureNeedrestart installs needrestart with the given restart mode
+	ConfigureNeedrestart(mode string) error
+
+	// GetNeedrestartStatus reports services and kernel waiting on a restart
+	GetNeedrestartStatus() (*model.NeedrestartStatus, error)
 }
 
 // PackageServiceImpl implements PackageService
@@ -37,8 +69,16 @@ type PackageRepository interface {
 	InstallPackages(request model.PackageInstallRequest) error
 	UpdatePackageSources(sources model.PackageSources) error
 	UpdateProxmoxSources(sources model.PackageSources) error
+	SourcesFormat(setting string) string
+	ReadAptSources() ([]model.AptFile, error)
+	ReadSourceFile(path string) ([]byte, error)
+	ReplaceAptSources(from, to string, data []byte) error
 	IsPackageInstalled(packageName string) (bool, error)
 	GetPackageSources() (*model.PackageSources, error)
+	ConfigureAutoUpgrades(enable bool) error
+	IsAutoUpgradeEnabled() (bool, error)
+	ConfigureNeedrestart(mode string) error
+	GetNeedrestartStatus() (*model.NeedrestartStatus, error)
 }
 
 // Implementation of PackageService methods
@@ -68,6 +108,155 @@ func (s *PackageServiceImpl) UpdateProxmoxSources() error {
 	return s.repository.UpdateProxmoxSources(*sources)
 }
 
+func (s *PackageServiceImpl) SourcesFormat() (string, error) {
+	sources, err := s.repository.GetPackageSources()
+	if err != nil {
+		return "", err
+	}
+	return s.repository.SourcesFormat(sources.SourcesFormat), nil
+}
+
+func (s *PackageServiceImpl) ReadSourceFile(path string) ([]byte, error) {
+	return s.repository.ReadSourceFile(path)
+}
+
+// MigrateSources converts every list in the other format: one-line lists
+// to .sources files of the same name, sources.list to the release file
+// such as debian.sources, and back. Commented-out entries are kept as
+// disabled sources, other comments are left in the backups. Entries are
+// added to a target that already lists repositories. Every list is
+// converted before anything is written, so one that cannot be, such as a
+// deb822 stanza with an inline key, leaves all of them as they were.
+func (s *PackageServiceImpl) MigrateSources(format string) ([]model.SourcesMigration, error) {
+	if s.osInfo.Type == "alpine" || model.IsRHELFamily(s.osInfo.Type) {
+		return nil, fmt.Errorf("one-line and deb822 repository lists are used by APT on Debian and Ubuntu only")
+	}
+	if format != model.SourcesFormatList && format != model.SourcesFormatDeb822 {
+		return nil, fmt.Errorf("invalid sources format %q (expected list or deb822)", format)
+	}
+
+	files, err := s.repository.ReadAptSources()
+	if err != nil {
+		return nil, err
+	}
+	contents := make(map[string][]byte, len(files))
+	for _, file := range files {
+		contents[file.Path] = file.Data
+	}
+
+	type conversion struct {
+		migration model.SourcesMigration
+		data      []byte
+	}
+	var conversions []conversion
+	for _, file := range files {
+		if strings.HasSuffix(file.Path, ".sources") == (format == model.SourcesFormatDeb822) {
+			continue
+		}
+		sources := model.ParseAptSourcesFile(file, true)
+		if len(sources) == 0 {
+			continue
+		}
+
+		to := model.MigratedSourcesPath(file.Path, format, s.osInfo.Type)
+		content, err := model.FormatAptSources(sources, format, "Migrated from "+file.Path+" by hardn")
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %w", file.Path, err)
+		}
+		// A blank line keeps the stanzas of both files apart
+		if data, ok := contents[to]; ok && len(model.ParseAptSourcesFile(model.AptFile{Path: to, Data: data}, true)) > 0 {
+			content = strings.TrimRight(string(data), "\n") + "\n\n" + content
+		}
+		contents[to] = []byte(content)
+
+		conversions = append(conversions, conversion{
+			migration: model.SourcesMigration{From: file.Path, To: to, Sources: len(sources)},
+			data:      []byte(content),
+		})
+	}
+
+	migrations := make([]model.SourcesMigration, 0, len(conversions))
+	for _, c := range conversions {
+		if err := s.repository.ReplaceAptSources(c.migration.From, c.migration.To, c.data); err != nil {
+			return migrations, err
+		}
+		migrations = append(migrations, c.migration)
+	}
+	return migrations, nil
+}
+
 func (s *PackageServiceImpl) IsPackageInstalled(packageName string) (bool, error) {
 	return s.repository.IsPackageInstalled(packageName)
 }
+
+// AutoUpgradesSupported is true on RHEL-family distributions; the upgrade
+// policy of Debian, Ubuntu and Alpine is written by the updates service
+func (s *PackageServiceImpl) AutoUpgradesSupported() bool {
+	return model.IsRHELFamily(s.osInfo.Type)
+}
+
+func (s *PackageServiceImpl) ConfigureAutoUpgrades(enable bool) error {
+	if !s.AutoUpgradesSupported() {
+		return fmt.Errorf("automatic upgrades are managed by the updates service on %s", s.osInfo.Type)
+	}
+	return s.repository.ConfigureAutoUpgrades(enable)
+}
+
+func (s *PackageServiceImpl) IsAutoUpgradeEnabled() (bool, error) {
+	if !s.AutoUpgradesSupported() {
+		return false, nil
+	}
+	return s.repository.IsAutoUpgradeEnabled()
+}
+
+// NeedrestartSupported is true on Debian and Ubuntu
+func (s *PackageServiceImpl) NeedrestartSupported() bool {
+	return s.osInfo.Type != "alpine" && !model.IsRHELFamily(s.osInfo.Type)
+}
+
+func (s *PackageServiceImpl) ConfigureNeedrestart(mode string) error {
+	if !s.NeedrestartSupported() {
+		return fmt.Errorf("needrestart is not available on %s", s.osInfo.Type)
+	}
+	if _, ok := model.NeedrestartModes[mode]; !ok {
+		return fmt.Errorf("invalid needrestart mode %q (expected automatic, list or interactive)", mode)
+	}
+	return s.repository.ConfigureNeedrestart(mode)
+}
+
+func (s *PackageServiceImpl) GetNeedrestartStatus() (*model.NeedrestartStatus, error) {
+	if !s.NeedrestartSupported() {
+		return &model.NeedrestartStatus{}, nil
+	}
+	return s.repository.GetNeedrestartStatus()
+}
+
+// ValidateDebianRepo checks that a configured repository is a one-line
+// sources.list entry such as "deb http://deb.debian.org/debian CODENAME main"
+func ValidateDebianRepo(line string) error {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return nil
+	}
+	if fields[0] != "deb" && fields[0] != "deb-src" {
+		return fmt.Errorf("invalid repository %q: must start with deb or deb-src", line)
+	}
+
+	// Options such as [signed-by=...] may precede the URI
+	rest := fields[1:]
+	if len(rest) > 0 && strings.HasPrefix(rest[0], "[") {
+		for len(rest) > 0 && !strings.HasSuffix(rest[0], "]") {
+			rest = rest[1:]
+		}
+		if len(rest) > 0 {
+			rest = rest[1:]
+		}
+	}
+	if len(rest) < 2 {
+		return fmt.Errorf("invalid repository %q: expected a URI and a suite", line)
+	}
+	if !strings.Contains(rest[0], "://") && !strings.HasPrefix(rest[0], "cdrom:") {
+		return fmt.Errorf("invalid repository %q: %s is not a URI", line, rest[0])
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/abbott/hardn/pkg/domain/model"
@@ -25,6 +26,13 @@ type MockPackageRepository struct {
 	UpdateProxmoxError    error
 	UpdateProxmoxCalled   bool
 
+	// Sources format tracking
+	Format        string
+	AptFiles      []model.AptFile
+	ReplacedFiles map[string]string
+	ReplacedFrom  []string
+	ReplaceError  error
+
 	// Package installed check tracking
 	CheckedPackage         string
 	PackageInstalledResult bool
@@ -35,6 +43,17 @@ type MockPackageRepository struct {
 	ReturnedSources  *model.PackageSources
 	GetSourcesError  error
 	GetSourcesCalled bool
+
+	// Auto-upgrade tracking
+	AutoUpgradeEnable  bool
+	AutoUpgradeError   error
+	AutoUpgradeCalled  bool
+	AutoUpgradeEnabled bool
+
+	// Needrestart tracking
+	NeedrestartMode   string
+	NeedrestartCalled bool
+	NeedrestartStatus *model.NeedrestartStatus
 }
 
 func (m *MockPackageRepository) InstallPackages(request model.PackageInstallRequest) error {
@@ -55,6 +74,38 @@ func (m *MockPackageRepository) UpdateProxmoxSources(sources model.PackageSource
 	return m.UpdateProxmoxError
 }
 
+func (m *MockPackageRepository) SourcesFormat(setting string) string {
+	if setting == model.SourcesFormatList || setting == model.SourcesFormatDeb822 {
+		return setting
+	}
+	return m.Format
+}
+
+func (m *MockPackageRepository) ReadAptSources() ([]model.AptFile, error) {
+	return m.AptFiles, nil
+}
+
+func (m *MockPackageRepository) ReadSourceFile(path string) ([]byte, error) {
+	for _, file := range m.AptFiles {
+		if file.Path == path {
+			return file.Data, nil
+		}
+	}
+	return nil, errors.New(path + " does not exist")
+}
+
+func (m *MockPackageRepository) ReplaceAptSources(from, to string, data []byte) error {
+	if m.ReplaceError != nil {
+		return m.ReplaceError
+	}
+	if m.ReplacedFiles == nil {
+		m.ReplacedFiles = make(map[string]string)
+	}
+	m.ReplacedFiles[to] = string(data)
+	m.ReplacedFrom = append(m.ReplacedFrom, from)
+	return nil
+}
+
 func (m *MockPackageRepository) IsPackageInstalled(packageName string) (bool, error) {
 	m.CheckedPackage = packageName
 	m.PackageInstalledCalled = true
@@ -66,6 +117,26 @@ func (m *MockPackageRepository) GetPackageSources() (*model.PackageSources, erro
 	return m.ReturnedSources, m.GetSourcesError
 }
 
+func (m *MockPackageRepository) ConfigureAutoUpgrades(enable bool) error {
+	m.AutoUpgradeEnable = enable
+	m.AutoUpgradeCalled = true
+	return m.AutoUpgradeError
+}
+
+func (m *MockPackageRepository) IsAutoUpgradeEnabled() (bool, error) {
+	return m.AutoUpgradeEnabled, nil
+}
+
+func (m *MockPackageRepository) ConfigureNeedrestart(mode string) error {
+	m.NeedrestartMode = mode
+	m.NeedrestartCalled = true
+	return nil
+}
+
+func (m *MockPackageRepository) GetNeedrestartStatus() (*model.NeedrestartStatus, error) {
+	return m.NeedrestartStatus, nil
+}
+
 func TestNewPackageServiceImpl(t *testing.T) {
 	repo := &MockPackageRepository{}
 	osInfo := model.OSInfo{Type: "debian", Version: "11", Codename: "bullseye"}
@@ -465,6 +536,137 @@ func TestPackageServiceImpl_IsPackageInstalled(t *testing.T) {
 	}
 }
 
+func TestPackageServiceImpl_ConfigureAutoUpgrades(t *testing.T) {
+	tests := []struct {
+		name         string
+		osInfo       model.OSInfo
+		enable       bool
+		repoError    error
+		expectError  bool
+		expectCalled bool
+	}{
+		{
+			name:         "enable on rocky",
+			osInfo:       model.OSInfo{Type: "rocky", Version: "9.4"},
+			enable:       true,
+			expectCalled: true,
+		},
+		{
+			name:         "disable on almalinux",
+			osInfo:       model.OSInfo{Type: "almalinux", Version: "9.4"},
+			enable:       false,
+			expectCalled: true,
+		},
+		{
+			name:         "repository error",
+			osInfo:       model.OSInfo{Type: "rocky", Version: "9.4"},
+			enable:       true,
+			repoError:    errors.New("mock write error"),
+			expectError:  true,
+			expectCalled: true,
+		},
+		{
+			name:        "debian uses the updates service",
+			osInfo:      model.OSInfo{Type: "debian", Version: "12", Codename: "bookworm"},
+			enable:      true,
+			expectError: true,
+		},
+		{
+			name:        "alpine uses the updates service",
+			osInfo:      model.OSInfo{Type: "alpine", Version: "3.19"},
+			enable:      true,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			repo := &MockPackageRepository{AutoUpgradeError: tc.repoError}
+			service := NewPackageServiceImpl(repo, tc.osInfo)
+
+			// Execute
+			err := service.ConfigureAutoUpgrades(tc.enable)
+
+			// Verify
+			if tc.expectError && err == nil {
+				t.Error("Expected error but got nil")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+
+			if repo.AutoUpgradeCalled != tc.expectCalled {
+				t.Errorf("ConfigureAutoUpgrades called = %v, expected %v", repo.AutoUpgradeCalled, tc.expectCalled)
+			}
+			if tc.expectCalled && repo.AutoUpgradeEnable != tc.enable {
+				t.Errorf("Wrong enable flag passed. Got %v, expected %v", repo.AutoUpgradeEnable, tc.enable)
+			}
+		})
+	}
+}
+
+func TestPackageServiceImpl_ConfigureNeedrestart(t *testing.T) {
+	tests := []struct {
+		name         string
+		osInfo       model.OSInfo
+		mode         string
+		expectError  bool
+		expectCalled bool
+	}{
+		{
+			name:         "automatic on debian",
+			osInfo:       model.OSInfo{Type: "debian", Version: "12", Codename: "bookworm"},
+			mode:         "automatic",
+			expectCalled: true,
+		},
+		{
+			name:         "list on ubuntu",
+			osInfo:       model.OSInfo{Type: "ubuntu", Version: "24.04", Codename: "noble"},
+			mode:         "list",
+			expectCalled: true,
+		},
+		{
+			name:        "invalid mode",
+			osInfo:      model.OSInfo{Type: "debian", Version: "12", Codename: "bookworm"},
+			mode:        "a",
+			expectError: true,
+		},
+		{
+			name:        "not available on alpine",
+			osInfo:      model.OSInfo{Type: "alpine", Version: "3.19"},
+			mode:        "automatic",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			repo := &MockPackageRepository{}
+			service := NewPackageServiceImpl(repo, tc.osInfo)
+
+			// Execute
+			err := service.ConfigureNeedrestart(tc.mode)
+
+			// Verify
+			if tc.expectError && err == nil {
+				t.Error("Expected error but got nil")
+			}
+			if !tc.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+
+			if repo.NeedrestartCalled != tc.expectCalled {
+				t.Errorf("ConfigureNeedrestart called = %v, expected %v", repo.NeedrestartCalled, tc.expectCalled)
+			}
+			if tc.expectCalled && repo.NeedrestartMode != tc.mode {
+				t.Errorf("Wrong mode passed. Got %s, expected %s", repo.NeedrestartMode, tc.mode)
+			}
+		})
+	}
+}
+
 func TestPackageServiceImpl_OSTypeHandling(t *testing.T) {
 	osTypes := []struct {
 		osType    string
@@ -509,3 +711,120 @@ func TestPackageServiceImpl_OSTypeHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestPackageServiceImpl_MigrateSources(t *testing.T) {
+	repo := &MockPackageRepository{
+		AptFiles: []model.AptFile{
+			{Path: "/etc/apt/sources.list", Data: []byte(
+				"# Debian bookworm\n" +
+					"deb http://deb.debian.org/debian bookworm main\n" +
+					"deb-src http://deb.debian.org/debian bookworm main\n")},
+			{Path: "/etc/apt/sources.list.d/pve-enterprise.list", Data: []byte(
+				"#deb https://enterprise.proxmox.com/debian/pve bookworm pve-enterprise\n")},
+			{Path: "/etc/apt/sources.list.d/docker.sources", Data: []byte(
+				"Types: deb\nURIs: https://download.docker.com/linux/debian\nSuites: bookworm\n")},
+			{Path: "/etc/apt/sources.list.d/empty.list", Data: []byte("# nothing here\n")},
+		},
+	}
+	service := NewPackageServiceImpl(repo, model.OSInfo{Type: "debian", Codename: "bookworm"})
+
+	migrations, err := service.MigrateSources(model.SourcesFormatDeb822)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []model.SourcesMigration{
+		{From: "/etc/apt/sources.list", To: "/etc/apt/sources.list.d/debian.sources", Sources: 2},
+		{From: "/etc/apt/sources.list.d/pve-enterprise.list", To: "/etc/apt/sources.list.d/pve-enterprise.sources", Sources: 1},
+	}
+	if !reflect.DeepEqual(migrations, expected) {
+		t.Errorf("Expected migrations %v, got %v", expected, migrations)
+	}
+
+	debian := "# Migrated from /etc/apt/sources.list by hardn\n\n" +
+		"Types: deb deb-src\nURIs: http://deb.debian.org/debian\nSuites: bookworm\nComponents: main\n"
+	if got := repo.ReplacedFiles["/etc/apt/sources.list.d/debian.sources"]; got != debian {
+		t.Errorf("Expected debian.sources:\n%s\ngot:\n%s", debian, got)
+	}
+	enterprise := repo.ReplacedFiles["/etc/apt/sources.list.d/pve-enterprise.sources"]
+	if !strings.Contains(enterprise, "Enabled: no\n") {
+		t.Errorf("Expected the commented-out enterprise repository to be disabled, got:\n%s", enterprise)
+	}
+
+	// Converting back keeps the disabled entry commented out
+	repo = &MockPackageRepository{
+		AptFiles: []model.AptFile{
+			{Path: "/etc/apt/sources.list", Data: []byte("# Repositories are listed in /etc/apt/sources.list.d/debian.sources\n")},
+			{Path: "/etc/apt/sources.list.d/debian.sources", Data: []byte(debian)},
+			{Path: "/etc/apt/sources.list.d/pve-enterprise.sources", Data: []byte(enterprise)},
+		},
+	}
+	service = NewPackageServiceImpl(repo, model.OSInfo{Type: "debian", Codename: "bookworm"})
+	if _, err := service.MigrateSources(model.SourcesFormatList); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	list := "# Migrated from /etc/apt/sources.list.d/debian.sources by hardn\n" +
+		"deb http://deb.debian.org/debian bookworm main\n" +
+		"deb-src http://deb.debian.org/debian bookworm main\n"
+	if got := repo.ReplacedFiles["/etc/apt/sources.list"]; got != list {
+		t.Errorf("Expected sources.list:\n%s\ngot:\n%s", list, got)
+	}
+	if got := repo.ReplacedFiles["/etc/apt/sources.list.d/pve-enterprise.list"]; !strings.Contains(got,
+		"# deb https://enterprise.proxmox.com/debian/pve bookworm pve-enterprise\n") {
+		t.Errorf("Expected a commented-out enterprise entry, got:\n%s", got)
+	}
+}
+
+func TestPackageServiceImpl_MigrateSourcesInlineKey(t *testing.T) {
+	repo := &MockPackageRepository{
+		AptFiles: []model.AptFile{
+			{Path: "/etc/apt/sources.list.d/a.sources", Data: []byte(
+				"Types: deb\nURIs: https://a.example.com\nSuites: stable\n")},
+			{Path: "/etc/apt/sources.list.d/b.sources", Data: []byte(
+				"Types: deb\nURIs: https://b.example.com\nSuites: stable\n" +
+					"Signed-By:\n -----BEGIN PGP PUBLIC KEY BLOCK-----\n .\n abc\n")},
+		},
+	}
+	service := NewPackageServiceImpl(repo, model.OSInfo{Type: "ubuntu", Codename: "noble"})
+
+	if _, err := service.MigrateSources(model.SourcesFormatList); err == nil {
+		t.Fatal("Expected an inline key to stop the migration")
+	}
+	if len(repo.ReplacedFiles) != 0 {
+		t.Errorf("Expected nothing written, got %v", repo.ReplacedFiles)
+	}
+
+	if _, err := service.MigrateSources("yaml"); err == nil {
+		t.Error("Expected an unknown format to be rejected")
+	}
+	alpine := NewPackageServiceImpl(repo, model.OSInfo{Type: "alpine"})
+	if _, err := alpine.MigrateSources(model.SourcesFormatDeb822); err == nil {
+		t.Error("Expected Alpine to be rejected")
+	}
+}
+
+func TestValidateDebianRepo(t *testing.T) {
+	valid := []string{
+		"deb http://deb.debian.org/debian CODENAME main contrib",
+		"deb-src http://deb.debian.org/debian CODENAME main",
+		"deb [arch=amd64 signed-by=/usr/share/keyrings/x.gpg] https://example.com/apt stable main",
+		"# deb http://deb.debian.org/debian CODENAME-backports main",
+		"",
+	}
+	for _, line := range valid {
+		if err := ValidateDebianRepo(line); err != nil {
+			t.Errorf("Expected %q to be valid, got: %v", line, err)
+		}
+	}
+
+	invalid := []string{
+		"http://deb.debian.org/debian CODENAME main",
+		"deb deb.debian.org/debian CODENAME main",
+		"deb http://deb.debian.org/debian",
+		"deb [signed-by=/usr/share/keyrings/x.gpg]",
+	}
+	for _, line := range invalid {
+		if err := ValidateDebianRepo(line); err == nil {
+			t.Errorf("Expected %q to be rejected", line)
+		}
+	}
+}
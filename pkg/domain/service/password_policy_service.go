@@ -0,0 +1,85 @@
+// pkg/domain/service/password_policy_service.go
+package service
+
+import (
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// PasswordPolicyService defines operations for the local password policy
+type PasswordPolicyService interface {
+	// ValidatePolicy checks that a policy can be written as given
+	ValidatePolicy(policy model.PasswordPolicy) error
+
+	// PamSupported reports whether the host checks passwords with PAM.
+	// Without it only the password aging is applied.
+	PamSupported() bool
+
+	// ConfigurePasswordPolicy applies the strength, aging and lockout policy
+	ConfigurePasswordPolicy(policy model.PasswordPolicy) error
+
+	// GetPasswordPolicyStatus reports the password policy in effect
+	GetPasswordPolicyStatus() (*model.PasswordPolicyStatus, error)
+}
+
+// PasswordPolicyServiceImpl implements PasswordPolicyService
+type PasswordPolicyServiceImpl struct {
+	repository PasswordPolicyRepository
+	osInfo     model.OSInfo
+}
+
+// NewPasswordPolicyServiceImpl creates a new PasswordPolicyServiceImpl
+func NewPasswordPolicyServiceImpl(repository PasswordPolicyRepository, osInfo model.OSInfo) *PasswordPolicyServiceImpl {
+	return &PasswordPolicyServiceImpl{
+		repository: repository,
+		osInfo:     osInfo,
+	}
+}
+
+// PasswordPolicyRepository defines the repository operations needed by PasswordPolicyService
+type PasswordPolicyRepository interface {
+	ConfigurePasswordPolicy(policy model.PasswordPolicy) error
+	GetPasswordPolicyStatus() (*model.PasswordPolicyStatus, error)
+}
+
+func (s *PasswordPolicyServiceImpl) ValidatePolicy(policy model.PasswordPolicy) error {
+	// pam_pwquality refuses a minlen below 6
+	if policy.MinLength < 6 {
+		return fmt.Errorf("minimum password length must be at least 6, got %d", policy.MinLength)
+	}
+	if policy.Retry < 1 {
+		return fmt.Errorf("retry must be at least 1, got %d", policy.Retry)
+	}
+	if policy.MaxDays < 1 {
+		return fmt.Errorf("maximum password age must be at least 1 day, got %d", policy.MaxDays)
+	}
+	if policy.MinDays < 0 || policy.MinDays > policy.MaxDays {
+		return fmt.Errorf("minimum password age must be between 0 and %d days, got %d", policy.MaxDays, policy.MinDays)
+	}
+	if policy.WarnAge < 0 || policy.WarnAge > policy.MaxDays {
+		return fmt.Errorf("password expiry warning must be between 0 and %d days, got %d", policy.MaxDays, policy.WarnAge)
+	}
+	if policy.LockoutAttempts < 0 {
+		return fmt.Errorf("lockout attempts must not be negative, got %d", policy.LockoutAttempts)
+	}
+	if policy.LockoutUnlockTime < 0 {
+		return fmt.Errorf("lockout unlock time must not be negative, got %d", policy.LockoutUnlockTime)
+	}
+	return nil
+}
+
+func (s *PasswordPolicyServiceImpl) PamSupported() bool {
+	return s.osInfo.Type != "alpine"
+}
+
+func (s *PasswordPolicyServiceImpl) ConfigurePasswordPolicy(policy model.PasswordPolicy) error {
+	if err := s.ValidatePolicy(policy); err != nil {
+		return err
+	}
+	return s.repository.ConfigurePasswordPolicy(policy)
+}
+
+func (s *PasswordPolicyServiceImpl) GetPasswordPolicyStatus() (*model.PasswordPolicyStatus, error) {
+	return s.repository.GetPasswordPolicyStatus()
+}
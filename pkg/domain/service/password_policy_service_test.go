@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPasswordPolicyRepository is a mock implementation of PasswordPolicyRepository
+type MockPasswordPolicyRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordPolicyRepository) ConfigurePasswordPolicy(policy model.PasswordPolicy) error {
+	args := m.Called(policy)
+	return args.Error(0)
+}
+
+func (m *MockPasswordPolicyRepository) GetPasswordPolicyStatus() (*model.PasswordPolicyStatus, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PasswordPolicyStatus), args.Error(1)
+}
+
+func TestPasswordPolicyServiceImpl_ConfigurePasswordPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		modify        func(*model.PasswordPolicy)
+		expectedError bool
+	}{
+		{
+			name:   "default policy",
+			modify: func(p *model.PasswordPolicy) {},
+		},
+		{
+			name:   "lockout off",
+			modify: func(p *model.PasswordPolicy) { p.LockoutAttempts = 0 },
+		},
+		{
+			name:          "too short for pam_pwquality",
+			modify:        func(p *model.PasswordPolicy) { p.MinLength = 4 },
+			expectedError: true,
+		},
+		{
+			name:          "minimum age above maximum age",
+			modify:        func(p *model.PasswordPolicy) { p.MinDays = 400 },
+			expectedError: true,
+		},
+		{
+			name:          "no retry",
+			modify:        func(p *model.PasswordPolicy) { p.Retry = 0 },
+			expectedError: true,
+		},
+		{
+			name:          "negative unlock time",
+			modify:        func(p *model.PasswordPolicy) { p.LockoutUnlockTime = -1 },
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := model.DefaultPasswordPolicy()
+			tc.modify(&policy)
+
+			repo := new(MockPasswordPolicyRepository)
+			repo.On("ConfigurePasswordPolicy", policy).Return(nil).Maybe()
+			service := NewPasswordPolicyServiceImpl(repo, model.OSInfo{Type: "debian", Version: "12"})
+
+			err := service.ConfigurePasswordPolicy(policy)
+
+			if tc.expectedError {
+				assert.Error(t, err)
+				repo.AssertNotCalled(t, "ConfigurePasswordPolicy", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPasswordPolicyServiceImpl_PamSupported(t *testing.T) {
+	repo := new(MockPasswordPolicyRepository)
+
+	assert.True(t, NewPasswordPolicyServiceImpl(repo, model.OSInfo{Type: "ubuntu"}).PamSupported())
+	assert.True(t, NewPasswordPolicyServiceImpl(repo, model.OSInfo{Type: "rocky"}).PamSupported())
+	assert.False(t, NewPasswordPolicyServiceImpl(repo, model.OSInfo{Type: "alpine"}).PamSupported())
+}
+
+func TestPasswordPolicyStatus_Weaknesses(t *testing.T) {
+	status := model.PasswordPolicyStatus{Complexity: true, MinLength: 14, MaxDays: 365, Lockout: true, LockoutAttempts: 5}
+	assert.Empty(t, status.Weaknesses())
+
+	status = model.PasswordPolicyStatus{Complexity: true, MinLength: 8, MaxDays: 99999}
+	assert.Equal(t, []string{"minimum length 8", "passwords never expire", "no lockout"}, status.Weaknesses())
+}
@@ -0,0 +1,66 @@
+// pkg/domain/service/policy_service.go
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// PolicyService defines operations for evaluating operator policies
+type PolicyService interface {
+	// Evaluate evaluates the policies in policyDir against the facts document
+	Evaluate(policyDir string, facts *model.Facts) (*model.PolicyReport, error)
+}
+
+// PolicyServiceImpl implements PolicyService
+type PolicyServiceImpl struct {
+	repository PolicyRepository
+}
+
+// NewPolicyServiceImpl creates a new PolicyServiceImpl
+func NewPolicyServiceImpl(repository PolicyRepository) *PolicyServiceImpl {
+	return &PolicyServiceImpl{
+		repository: repository,
+	}
+}
+
+// PolicyRepository defines the repository operations needed by PolicyService
+type PolicyRepository interface {
+	EvaluatePolicies(policyDir string, facts *model.Facts) ([]model.PolicyViolation, error)
+}
+
+func (s *PolicyServiceImpl) Evaluate(policyDir string, facts *model.Facts) (*model.PolicyReport, error) {
+	if policyDir == "" {
+		return nil, fmt.Errorf("policy directory is required")
+	}
+	if facts == nil {
+		return nil, fmt.Errorf("facts document is required")
+	}
+
+	violations, err := s.repository.EvaluatePolicies(policyDir, facts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stable order so reports can be compared between runs
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Rule != violations[j].Rule {
+			return violations[i].Rule < violations[j].Rule
+		}
+		return violations[i].Message < violations[j].Message
+	})
+
+	if violations == nil {
+		violations = []model.PolicyViolation{}
+	}
+
+	return &model.PolicyReport{
+		PolicyDir:   policyDir,
+		EvaluatedAt: time.Now(),
+		Facts:       facts,
+		Violations:  violations,
+	}, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPolicyRepository is a mock implementation of PolicyRepository
+type MockPolicyRepository struct {
+	mock.Mock
+}
+
+func (m *MockPolicyRepository) EvaluatePolicies(policyDir string, facts *model.Facts) ([]model.PolicyViolation, error) {
+	args := m.Called(policyDir, facts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PolicyViolation), args.Error(1)
+}
+
+func TestPolicyServiceImpl_Evaluate(t *testing.T) {
+	facts := &model.Facts{Hostname: "web-01"}
+
+	t.Run("missing arguments", func(t *testing.T) {
+		policyService := NewPolicyServiceImpl(new(MockPolicyRepository))
+
+		_, err := policyService.Evaluate("", facts)
+		assert.Error(t, err)
+
+		_, err = policyService.Evaluate("/etc/hardn/policies", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("violations are sorted", func(t *testing.T) {
+		mockRepo := new(MockPolicyRepository)
+		mockRepo.On("EvaluatePolicies", "/etc/hardn/policies", facts).Return([]model.PolicyViolation{
+			{Rule: "ssh", Message: "root login enabled"},
+			{Rule: "firewall", Message: "firewall disabled"},
+			{Rule: "ssh", Message: "default port"},
+		}, nil)
+
+		report, err := NewPolicyServiceImpl(mockRepo).Evaluate("/etc/hardn/policies", facts)
+
+		assert.NoError(t, err)
+		assert.Equal(t, facts, report.Facts)
+		assert.Equal(t, []model.PolicyViolation{
+			{Rule: "firewall", Message: "firewall disabled"},
+			{Rule: "ssh", Message: "default port"},
+			{Rule: "ssh", Message: "root login enabled"},
+		}, report.Violations)
+	})
+
+	t.Run("no violations", func(t *testing.T) {
+		mockRepo := new(MockPolicyRepository)
+		mockRepo.On("EvaluatePolicies", "policies", facts).Return(nil, nil)
+
+		report, err := NewPolicyServiceImpl(mockRepo).Evaluate("policies", facts)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, report.Violations)
+		assert.Empty(t, report.Violations)
+	})
+
+	t.Run("evaluation error", func(t *testing.T) {
+		mockRepo := new(MockPolicyRepository)
+		mockRepo.On("EvaluatePolicies", "policies", facts).Return(nil, errors.New("opa not found"))
+
+		_, err := NewPolicyServiceImpl(mockRepo).Evaluate("policies", facts)
+
+		assert.Error(t, err)
+	})
+}
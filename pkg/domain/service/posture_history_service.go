@@ -0,0 +1,67 @@
+// pkg/domain/service/posture_history_service.go
+package service
+
+import (
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// PostureHistoryService defines operations for the security posture history
+type PostureHistoryService interface {
+	// RecordSnapshot adds a snapshot to the history and returns the one
+	// before it, or nil if it is the first
+	RecordSnapshot(snapshot model.PostureSnapshot) (*model.PostureSnapshot, error)
+
+	// ListSnapshots returns the latest limit snapshots, oldest first, or
+	// all of them if limit is not positive
+	ListSnapshots(limit int) ([]model.PostureSnapshot, error)
+
+	// LastSnapshot returns the latest snapshot, or nil if there is none
+	LastSnapshot() (*model.PostureSnapshot, error)
+}
+
+// PostureHistoryServiceImpl implements PostureHistoryService
+type PostureHistoryServiceImpl struct {
+	repository PostureHistoryRepository
+}
+
+// NewPostureHistoryServiceImpl creates a new PostureHistoryServiceImpl
+func NewPostureHistoryServiceImpl(repository PostureHistoryRepository) *PostureHistoryServiceImpl {
+	return &PostureHistoryServiceImpl{
+		repository: repository,
+	}
+}
+
+// PostureHistoryRepository defines the repository operations needed by PostureHistoryService
+type PostureHistoryRepository interface {
+	AppendSnapshot(snapshot model.PostureSnapshot, keep int) error
+	ListSnapshots() ([]model.PostureSnapshot, error)
+}
+
+func (s *PostureHistoryServiceImpl) RecordSnapshot(snapshot model.PostureSnapshot) (*model.PostureSnapshot, error) {
+	// A history that can not be read is not a reason to stop recording
+	previous, _ := s.LastSnapshot()
+
+	if err := s.repository.AppendSnapshot(snapshot, model.MaxPostureSnapshots); err != nil {
+		return previous, err
+	}
+	return previous, nil
+}
+
+func (s *PostureHistoryServiceImpl) ListSnapshots(limit int) ([]model.PostureSnapshot, error) {
+	snapshots, err := s.repository.ListSnapshots()
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(snapshots) > limit {
+		snapshots = snapshots[len(snapshots)-limit:]
+	}
+	return snapshots, nil
+}
+
+func (s *PostureHistoryServiceImpl) LastSnapshot() (*model.PostureSnapshot, error) {
+	snapshots, err := s.ListSnapshots(1)
+	if err != nil || len(snapshots) == 0 {
+		return nil, err
+	}
+	return &snapshots[0], nil
+}
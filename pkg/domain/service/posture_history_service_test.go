@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockPostureHistoryRepository is a mock implementation of PostureHistoryRepository
+type MockPostureHistoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostureHistoryRepository) AppendSnapshot(snapshot model.PostureSnapshot, keep int) error {
+	args := m.Called(snapshot, keep)
+	return args.Error(0)
+}
+
+func (m *MockPostureHistoryRepository) ListSnapshots() ([]model.PostureSnapshot, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PostureSnapshot), args.Error(1)
+}
+
+func TestPostureHistoryServiceImpl_RecordSnapshot(t *testing.T) {
+	start := time.Date(2026, 10, 1, 2, 0, 0, 0, time.UTC)
+	history := []model.PostureSnapshot{
+		{Time: start, Score: 5},
+		{Time: start.Add(time.Hour), Score: 6},
+	}
+	snapshot := model.PostureSnapshot{Time: start.Add(2 * time.Hour), Score: 7}
+
+	mockRepo := new(MockPostureHistoryRepository)
+	mockRepo.On("ListSnapshots").Return(history, nil)
+	mockRepo.On("AppendSnapshot", snapshot, model.MaxPostureSnapshots).Return(nil)
+	service := NewPostureHistoryServiceImpl(mockRepo)
+
+	previous, err := service.RecordSnapshot(snapshot)
+	require.NoError(t, err)
+	require.NotNil(t, previous)
+	assert.Equal(t, 6, previous.Score)
+	mockRepo.AssertExpectations(t)
+
+	// The first snapshot has nothing before it, and a failed write still
+	// returns what was read
+	mockRepo = new(MockPostureHistoryRepository)
+	mockRepo.On("ListSnapshots").Return(nil, nil)
+	mockRepo.On("AppendSnapshot", snapshot, model.MaxPostureSnapshots).Return(errors.New("permission denied"))
+	previous, err = NewPostureHistoryServiceImpl(mockRepo).RecordSnapshot(snapshot)
+	assert.Error(t, err)
+	assert.Nil(t, previous)
+}
+
+func TestPostureHistoryServiceImpl_ListSnapshots(t *testing.T) {
+	history := []model.PostureSnapshot{{Score: 1}, {Score: 2}, {Score: 3}}
+	mockRepo := new(MockPostureHistoryRepository)
+	mockRepo.On("ListSnapshots").Return(history, nil)
+	service := NewPostureHistoryServiceImpl(mockRepo)
+
+	snapshots, err := service.ListSnapshots(2)
+	require.NoError(t, err)
+	assert.Equal(t, []model.PostureSnapshot{{Score: 2}, {Score: 3}}, snapshots)
+
+	snapshots, err = service.ListSnapshots(0)
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 3)
+}
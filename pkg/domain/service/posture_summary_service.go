@@ -0,0 +1,140 @@
+// pkg/domain/service/posture_summary_service.go
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// PostureSummaryService defines operations for the periodic posture summary
+type PostureSummaryService interface {
+	// BuildSummary sums up the days before until from the posture history,
+	// the run reports and the package manager's log
+	BuildSummary(until time.Time, days int) (*model.PostureSummary, error)
+}
+
+// PostureSummaryServiceImpl implements PostureSummaryService
+type PostureSummaryServiceImpl struct {
+	historyRepository PostureHistoryRepository
+	reportRepository  RunReportRepository
+	updatesRepository UpdatesRepository
+}
+
+// NewPostureSummaryServiceImpl creates a new PostureSummaryServiceImpl
+func NewPostureSummaryServiceImpl(
+	historyRepository PostureHistoryRepository,
+	reportRepository RunReportRepository,
+	updatesRepository UpdatesRepository,
+) *PostureSummaryServiceImpl {
+	return &PostureSummaryServiceImpl{
+		historyRepository: historyRepository,
+		reportRepository:  reportRepository,
+		updatesRepository: updatesRepository,
+	}
+}
+
+func (s *PostureSummaryServiceImpl) BuildSummary(until time.Time, days int) (*model.PostureSummary, error) {
+	if days < 1 {
+		return nil, fmt.Errorf("a posture summary covers at least one day, not %d", days)
+	}
+	summary := &model.PostureSummary{Since: until.AddDate(0, 0, -days), Until: until}
+
+	snapshots, err := s.historyRepository.ListSnapshots()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the posture history: %w", err)
+	}
+
+	// Drift is measured from the last snapshot before the period, so a
+	// control that broke right as it began is not missed
+	var previous *model.PostureSnapshot
+	for _, snapshot := range snapshots {
+		if snapshot.Time.After(until) {
+			break
+		}
+		if snapshot.Time.Before(summary.Since) {
+			previous = &snapshot
+			summary.First = &snapshot
+			continue
+		}
+		if summary.First == nil {
+			summary.First = &snapshot
+		}
+		if previous != nil {
+			if regressed := snapshot.Regressions(*previous); len(regressed) > 0 {
+				summary.Drift = append(summary.Drift, model.PostureDrift{Time: snapshot.Time, Regressed: regressed})
+			}
+		}
+		previous = &snapshot
+		summary.Latest = &snapshot
+	}
+
+	reports, err := s.reportRepository.ListReports()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the run reports: %w", err)
+	}
+	for _, report := range reports {
+		if report.Status == model.RunFailed && inPeriod(report.Started, summary) {
+			summary.FailedRuns = append(summary.FailedRuns, report)
+		}
+	}
+
+	updates, err := s.updatesRepository.AppliedUpdates(summary.Since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the applied updates: %w", err)
+	}
+	for _, update := range updates {
+		if inPeriod(update.Time, summary) {
+			summary.Updates = append(summary.Updates, update)
+		}
+	}
+
+	return summary, nil
+}
+
+// inPeriod reports whether t falls in the period of the summary
+func inPeriod(t time.Time, summary *model.PostureSummary) bool {
+	return !t.Before(summary.Since) && !t.After(summary.Until)
+}
+
+// FleetSummary collects the posture summaries that hardn summary show
+// --json printed on the hosts of an inventory. A host that was not
+// reached, failed or printed no summary is listed with the reason.
+func FleetSummary(until time.Time, days int, results []model.RemoteResult) *model.FleetPostureSummary {
+	fleet := &model.FleetPostureSummary{Since: until.AddDate(0, 0, -days), Until: until}
+	for _, result := range results {
+		host := model.HostPostureSummary{Host: result.Host}
+		switch result.Status {
+		case model.RemoteUnreachable:
+			host.Error = result.Error
+		case model.RemoteFailed:
+			host.Error = fmt.Sprintf("hardn exited with status %d", result.ExitCode)
+		default:
+			summary, err := decodePostureSummary(result.Output)
+			if err != nil {
+				host.Error = err.Error()
+			} else {
+				host.Summary = summary
+			}
+		}
+		fleet.Hosts = append(fleet.Hosts, host)
+	}
+	return fleet
+}
+
+// decodePostureSummary reads the summary from a host's output, skipping
+// any warning ssh or sudo printed before it
+func decodePostureSummary(output string) (*model.PostureSummary, error) {
+	start := strings.Index(output, "{")
+	if start < 0 {
+		return nil, fmt.Errorf("no posture summary in the output")
+	}
+	var summary model.PostureSummary
+	if err := json.NewDecoder(strings.NewReader(output[start:])).Decode(&summary); err != nil {
+		return nil, fmt.Errorf("failed to parse the posture summary: %w", err)
+	}
+	return &summary, nil
+}
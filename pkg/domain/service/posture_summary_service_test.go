@@ -0,0 +1,124 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostureSummaryServiceImpl_BuildSummary(t *testing.T) {
+	until := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	since := until.AddDate(0, 0, -7)
+
+	historyRepo := new(MockPostureHistoryRepository)
+	historyRepo.On("ListSnapshots").Return([]model.PostureSnapshot{
+		{Time: since.Add(-48 * time.Hour), Score: 5, RiskLevel: "High", Checks: map[string]bool{"ssh-root-login": false, "firewall": true}},
+		{Time: since.Add(-time.Hour), Score: 6, RiskLevel: "Medium", Checks: map[string]bool{"ssh-root-login": true, "firewall": true}},
+		{Time: since.Add(time.Hour), Score: 5, RiskLevel: "Medium", Checks: map[string]bool{"ssh-root-login": true, "firewall": false}},
+		{Time: since.Add(72 * time.Hour), Score: 7, RiskLevel: "Low", Checks: map[string]bool{"ssh-root-login": true, "firewall": true}},
+		{Time: until.Add(time.Hour), Score: 0, RiskLevel: "Critical", Checks: map[string]bool{}},
+	}, nil)
+
+	reportRepo := new(MockRunReportRepository)
+	reportRepo.On("ListReports").Return([]model.RunReport{
+		{ID: "old", Started: since.Add(-time.Hour), Status: model.RunFailed},
+		{ID: "ok", Started: since.Add(2 * time.Hour), Status: model.RunSucceeded},
+		{ID: "failed", Started: since.Add(3 * time.Hour), Status: model.RunFailed},
+	}, nil)
+
+	updatesRepo := new(MockUpdatesRepository)
+	updatesRepo.On("AppliedUpdates", since).Return([]model.AppliedUpdate{
+		{Time: since.Add(24 * time.Hour), Package: "openssl", From: "3.0.11-1", To: "3.0.11-2"},
+	}, nil)
+
+	summary, err := NewPostureSummaryServiceImpl(historyRepo, reportRepo, updatesRepo).BuildSummary(until, 7)
+
+	require.NoError(t, err)
+	assert.Equal(t, since, summary.Since)
+	require.NotNil(t, summary.First)
+	assert.Equal(t, 6, summary.First.Score, "the period starts from the last snapshot before it")
+	require.NotNil(t, summary.Latest)
+	assert.Equal(t, 7, summary.Latest.Score, "snapshots after the period are left out")
+	assert.Equal(t, model.TrendImproved, summary.Trend())
+	assert.Equal(t, []model.PostureDrift{{Time: since.Add(time.Hour), Regressed: []string{"firewall"}}}, summary.Drift)
+	require.Len(t, summary.FailedRuns, 1)
+	assert.Equal(t, "failed", summary.FailedRuns[0].ID)
+	assert.Equal(t, "openssl 3.0.11-1 -> 3.0.11-2", summary.Updates[0].String())
+	assert.Empty(t, summary.FailedChecks())
+	assert.True(t, summary.Failed(), "drift needs attention even when the week ended better")
+}
+
+func TestPostureSummaryServiceImpl_BuildSummary_Empty(t *testing.T) {
+	until := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+
+	historyRepo := new(MockPostureHistoryRepository)
+	historyRepo.On("ListSnapshots").Return(nil, nil)
+	reportRepo := new(MockRunReportRepository)
+	reportRepo.On("ListReports").Return(nil, nil)
+	updatesRepo := new(MockUpdatesRepository)
+	updatesRepo.On("AppliedUpdates", until.AddDate(0, 0, -1)).Return(nil, nil)
+	service := NewPostureSummaryServiceImpl(historyRepo, reportRepo, updatesRepo)
+
+	summary, err := service.BuildSummary(until, 1)
+	require.NoError(t, err)
+	assert.Nil(t, summary.Latest)
+	assert.Equal(t, model.TrendSteady, summary.Trend())
+	assert.False(t, summary.Failed())
+
+	_, err = service.BuildSummary(until, 0)
+	assert.EqualError(t, err, "a posture summary covers at least one day, not 0")
+
+	historyRepo = new(MockPostureHistoryRepository)
+	historyRepo.On("ListSnapshots").Return(nil, errors.New("permission denied"))
+	_, err = NewPostureSummaryServiceImpl(historyRepo, reportRepo, updatesRepo).BuildSummary(until, 1)
+	assert.EqualError(t, err, "failed to read the posture history: permission denied")
+}
+
+func TestFleetSummary(t *testing.T) {
+	until := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	web1 := model.PostureSummary{
+		First:  &model.PostureSnapshot{Score: 6, RiskLevel: "Moderate"},
+		Latest: &model.PostureSnapshot{Score: 7, RiskLevel: "Low"},
+	}
+	web2 := model.PostureSummary{
+		First:  &model.PostureSnapshot{Score: 5, RiskLevel: "High"},
+		Latest: &model.PostureSnapshot{Score: 4, RiskLevel: "High"},
+	}
+	encode := func(summary model.PostureSummary) string {
+		data, err := json.Marshal(summary)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	fleet := FleetSummary(until, 7, []model.RemoteResult{
+		{Host: "web1", Status: model.RemoteOK, Output: encode(web1)},
+		{Host: "web2", Status: model.RemoteOK, Output: "Warning: Permanently added 'web2' to the list of known hosts.\n" + encode(web2) + "\n"},
+		{Host: "db1", Status: model.RemoteUnreachable, Error: "ssh to db1 failed: Connection refused"},
+		{Host: "db2", Status: model.RemoteFailed, ExitCode: 1},
+		{Host: "db3", Status: model.RemoteOK, Output: "Error: unknown flag: --json\n"},
+	})
+
+	assert.Equal(t, until.AddDate(0, 0, -7), fleet.Since)
+	require.Len(t, fleet.Hosts, 5)
+	require.NotNil(t, fleet.Hosts[1].Summary, fleet.Hosts[1].Error)
+	assert.Equal(t, 4, fleet.Hosts[1].Summary.Latest.Score)
+	assert.Equal(t, "ssh to db1 failed: Connection refused", fleet.Hosts[2].Error)
+	assert.Equal(t, "hardn exited with status 1", fleet.Hosts[3].Error)
+	assert.Equal(t, "no posture summary in the output", fleet.Hosts[4].Error)
+
+	assert.Equal(t, model.TrendRegressed, fleet.Trend(), "one host regressing regresses the fleet")
+	first, latest := fleet.Weakest()
+	assert.Equal(t, "High", first.RiskLevel)
+	assert.Equal(t, 4, latest.Score)
+	assert.True(t, fleet.Failed())
+
+	// Hosts that all held steady or improved, with every summary collected
+	fleet = FleetSummary(until, 7, []model.RemoteResult{{Host: "web1", Status: model.RemoteOK, Output: encode(web1)}})
+	assert.Equal(t, model.TrendImproved, fleet.Trend())
+	assert.False(t, fleet.Failed())
+}
@@ -0,0 +1,220 @@
+// pkg/domain/service/profile_bundle_service.go
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// ProfileBundleService defines operations for sharing hardening profiles
+// between hosts
+type ProfileBundleService interface {
+	// ExportBundle writes this host's profile to a bundle, signed when the
+	// request names a key
+	ExportBundle(request model.ProfileExportRequest) (*model.ProfileExport, error)
+
+	// LoadBundle reads and checks a bundle and its signature, which must be
+	// made by one of signers. An unsigned bundle is refused when a
+	// signature is required.
+	LoadBundle(path string, requireSignature bool, signers []string) (*model.ProfileImport, error)
+
+	// PlanImport lists the files importing a bundle changes, with hardn's
+	// configuration written to configPath
+	PlanImport(bundle *model.ProfileBundle, configPath string) ([]model.ProfileChange, error)
+
+	// ApplyImport writes the planned files and applies the sysctl settings
+	ApplyImport(changes []model.ProfileChange) error
+}
+
+// ProfileBundleServiceImpl implements ProfileBundleService
+type ProfileBundleServiceImpl struct {
+	repository ProfileBundleRepository
+	now        func() time.Time
+}
+
+// NewProfileBundleServiceImpl creates a new ProfileBundleServiceImpl
+func NewProfileBundleServiceImpl(repository ProfileBundleRepository) *ProfileBundleServiceImpl {
+	return &ProfileBundleServiceImpl{
+		repository: repository,
+		now:        time.Now,
+	}
+}
+
+// ProfileBundleRepository defines the repository operations needed by ProfileBundleService
+type ProfileBundleRepository interface {
+	ReadFile(path string) ([]byte, error)
+	WriteFile(path string, data []byte) error
+	ListSysctlFiles() ([]string, error)
+	ReloadSysctl() error
+	ParseBundle(path string, data []byte) (*model.ProfileBundle, error)
+	WriteBundle(path string, bundle *model.ProfileBundle) error
+	SignBundle(path string, key string) (string, error)
+	VerifyBundle(path string, data []byte, signers []string) (string, error)
+}
+
+var (
+	// sysctlFileName matches the drop-in names a bundle may write
+	sysctlFileName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*\.conf$`)
+
+	// sysctlSetting matches a "key = value" line; a leading "-" ignores
+	// keys the kernel does not have
+	sysctlSetting = regexp.MustCompile(`^-?[A-Za-z0-9_./*-]+\s*=`)
+)
+
+func (s *ProfileBundleServiceImpl) ExportBundle(request model.ProfileExportRequest) (*model.ProfileExport, error) {
+	config, err := s.repository.ReadFile(request.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, fmt.Errorf("no hardn configuration at %s to export", request.ConfigFile)
+	}
+
+	bundle := &model.ProfileBundle{
+		Kind:         model.ProfileBundleKind,
+		Format:       model.ProfileBundleFormat,
+		Name:         request.Name,
+		CreatedAt:    s.now().UTC(),
+		Hostname:     request.Hostname,
+		HardnVersion: request.HardnVersion,
+		Config:       string(config),
+	}
+
+	sysctlFiles := request.SysctlFiles
+	if sysctlFiles == nil {
+		if sysctlFiles, err = s.repository.ListSysctlFiles(); err != nil {
+			return nil, err
+		}
+	}
+	for _, path := range sysctlFiles {
+		data, err := s.repository.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			return nil, fmt.Errorf("sysctl file %s does not exist", path)
+		}
+		file := model.ProfileFile{Path: path, Content: string(data)}
+		if err := validateSysctlFile(file); err != nil {
+			return nil, err
+		}
+		bundle.Sysctl = append(bundle.Sysctl, file)
+	}
+
+	if err := s.repository.WriteBundle(request.Output, bundle); err != nil {
+		return nil, err
+	}
+	export := &model.ProfileExport{Path: request.Output, Bundle: bundle}
+	if request.SignKey != "" {
+		if export.SignaturePath, err = s.repository.SignBundle(request.Output, request.SignKey); err != nil {
+			return nil, err
+		}
+	}
+	return export, nil
+}
+
+func (s *ProfileBundleServiceImpl) LoadBundle(path string, requireSignature bool, signers []string) (*model.ProfileImport, error) {
+	data, err := s.repository.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, fmt.Errorf("profile bundle %s does not exist", path)
+	}
+
+	// The data verified is the data parsed, so the file cannot be swapped
+	// in between. A signature that fails to verify is refused even when
+	// none is required.
+	signer, err := s.repository.VerifyBundle(path, data, signers)
+	if err != nil {
+		return nil, err
+	}
+	if requireSignature && signer == "" {
+		return nil, fmt.Errorf("%s has no signature %s%s", path, path, model.ProfileSignatureSuffix)
+	}
+
+	bundle, err := s.repository.ParseBundle(path, data)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.Kind != model.ProfileBundleKind {
+		return nil, fmt.Errorf("%s is not a hardn profile bundle", path)
+	}
+	if bundle.Format != model.ProfileBundleFormat {
+		return nil, fmt.Errorf("%s has bundle format %d; this hardn reads format %d",
+			path, bundle.Format, model.ProfileBundleFormat)
+	}
+	if strings.TrimSpace(bundle.Config) == "" {
+		return nil, fmt.Errorf("%s holds no hardn configuration", path)
+	}
+
+	seen := make(map[string]bool)
+	for _, file := range bundle.Sysctl {
+		if err := validateSysctlFile(file); err != nil {
+			return nil, err
+		}
+		if seen[file.Path] {
+			return nil, fmt.Errorf("%s holds %s twice", path, file.Path)
+		}
+		seen[file.Path] = true
+	}
+
+	return &model.ProfileImport{Bundle: bundle, Signed: signer != "", Signer: signer}, nil
+}
+
+func (s *ProfileBundleServiceImpl) PlanImport(bundle *model.ProfileBundle, configPath string) ([]model.ProfileChange, error) {
+	files := append([]model.ProfileFile{{Path: configPath, Content: bundle.Config}}, bundle.Sysctl...)
+
+	var changes []model.ProfileChange
+	for _, file := range files {
+		before, err := s.repository.ReadFile(file.Path)
+		if err != nil {
+			return nil, err
+		}
+		if before != nil && string(before) == file.Content {
+			continue
+		}
+		changes = append(changes, model.ProfileChange{Path: file.Path, Before: before, After: []byte(file.Content)})
+	}
+	return changes, nil
+}
+
+func (s *ProfileBundleServiceImpl) ApplyImport(changes []model.ProfileChange) error {
+	sysctlChanged := false
+	for _, change := range changes {
+		if err := s.repository.WriteFile(change.Path, change.After); err != nil {
+			return err
+		}
+		sysctlChanged = sysctlChanged || filepath.Dir(change.Path) == model.SysctlDropInDir
+	}
+
+	if sysctlChanged {
+		return s.repository.ReloadSysctl()
+	}
+	return nil
+}
+
+// validateSysctlFile checks that a bundled file is a drop-in of
+// /etc/sysctl.d holding only sysctl settings, so a bundle can not write
+// elsewhere
+func validateSysctlFile(file model.ProfileFile) error {
+	if filepath.Dir(file.Path) != model.SysctlDropInDir || !sysctlFileName.MatchString(filepath.Base(file.Path)) {
+		return fmt.Errorf("sysctl file %s is not a .conf file in %s", file.Path, model.SysctlDropInDir)
+	}
+
+	for i, line := range strings.Split(file.Content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if !sysctlSetting.MatchString(line) {
+			return fmt.Errorf("%s:%d is not a sysctl setting: %s", file.Path, i+1, line)
+		}
+	}
+	return nil
+}
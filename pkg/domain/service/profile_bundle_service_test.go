@@ -0,0 +1,218 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockProfileBundleRepository is a mock implementation of ProfileBundleRepository
+type MockProfileBundleRepository struct {
+	mock.Mock
+}
+
+func (m *MockProfileBundleRepository) ReadFile(path string) ([]byte, error) {
+	args := m.Called(path)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockProfileBundleRepository) WriteFile(path string, data []byte) error {
+	args := m.Called(path, data)
+	return args.Error(0)
+}
+
+func (m *MockProfileBundleRepository) ListSysctlFiles() ([]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockProfileBundleRepository) ReloadSysctl() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockProfileBundleRepository) ParseBundle(path string, data []byte) (*model.ProfileBundle, error) {
+	args := m.Called(path, data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ProfileBundle), args.Error(1)
+}
+
+func (m *MockProfileBundleRepository) WriteBundle(path string, bundle *model.ProfileBundle) error {
+	args := m.Called(path, bundle)
+	return args.Error(0)
+}
+
+func (m *MockProfileBundleRepository) SignBundle(path string, key string) (string, error) {
+	args := m.Called(path, key)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockProfileBundleRepository) VerifyBundle(path string, data []byte, signers []string) (string, error) {
+	args := m.Called(path, data, signers)
+	return args.String(0), args.Error(1)
+}
+
+func TestProfileBundleServiceImpl_ExportBundle(t *testing.T) {
+	repo := new(MockProfileBundleRepository)
+	repo.On("ReadFile", "/etc/hardn/hardn.yml").Return([]byte("sshPort: 2208\n"), nil)
+	repo.On("ListSysctlFiles").Return([]string{"/etc/sysctl.d/60-hardening.conf"}, nil)
+	repo.On("ReadFile", "/etc/sysctl.d/60-hardening.conf").Return([]byte("# hardening\nkernel.kptr_restrict = 2\n-net.ipv4.tcp_syncookies=1\n"), nil)
+	repo.On("WriteBundle", "web.yml", mock.Anything).Return(nil)
+	repo.On("SignBundle", "web.yml", "ops@example.com").Return("web.yml.asc", nil)
+
+	service := NewProfileBundleServiceImpl(repo)
+	service.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	export, err := service.ExportBundle(model.ProfileExportRequest{
+		Name:       "web",
+		Hostname:   "web-01",
+		ConfigFile: "/etc/hardn/hardn.yml",
+		Output:     "web.yml",
+		SignKey:    "ops@example.com",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "web.yml.asc", export.SignaturePath)
+	assert.Equal(t, model.ProfileBundleKind, export.Bundle.Kind)
+	assert.Equal(t, "sshPort: 2208\n", export.Bundle.Config)
+	assert.Len(t, export.Bundle.Sysctl, 1)
+	repo.AssertExpectations(t)
+}
+
+func TestProfileBundleServiceImpl_LoadBundle(t *testing.T) {
+	valid := func() *model.ProfileBundle {
+		return &model.ProfileBundle{
+			Kind:   model.ProfileBundleKind,
+			Format: model.ProfileBundleFormat,
+			Config: "sshPort: 2208\n",
+			Sysctl: []model.ProfileFile{{Path: "/etc/sysctl.d/60-hardening.conf", Content: "kernel.kptr_restrict = 2\n"}},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		bundle           func(b *model.ProfileBundle)
+		signer           string
+		verifyError      error
+		requireSignature bool
+		expectedError    string
+	}{
+		{name: "valid unsigned bundle"},
+		{name: "valid signed bundle", signer: "0123456789ABCDEF0123456789ABCDEF01234567", requireSignature: true},
+		{name: "signature required", requireSignature: true, expectedError: "has no signature"},
+		{name: "bad signature", verifyError: errors.New("does not verify"), expectedError: "does not verify"},
+		{
+			name:          "other kind",
+			bundle:        func(b *model.ProfileBundle) { b.Kind = "evidence" },
+			expectedError: "not a hardn profile bundle",
+		},
+		{
+			name:          "newer format",
+			bundle:        func(b *model.ProfileBundle) { b.Format = 2 },
+			expectedError: "bundle format 2",
+		},
+		{
+			name: "file outside sysctl.d",
+			bundle: func(b *model.ProfileBundle) {
+				b.Sysctl = []model.ProfileFile{{Path: "/etc/sysctl.d/../cron.d/evil.conf", Content: ""}}
+			},
+			expectedError: "is not a .conf file",
+		},
+		{
+			name: "not a sysctl setting",
+			bundle: func(b *model.ProfileBundle) {
+				b.Sysctl[0].Content = "kernel.kptr_restrict = 2\n* * * * * root sh\n"
+			},
+			expectedError: "60-hardening.conf:2 is not a sysctl setting",
+		},
+	}
+
+	t.Run("missing bundle", func(t *testing.T) {
+		repo := new(MockProfileBundleRepository)
+		repo.On("ReadFile", "web.yml").Return(nil, nil)
+
+		_, err := NewProfileBundleServiceImpl(repo).LoadBundle("web.yml", false, nil)
+		assert.EqualError(t, err, "profile bundle web.yml does not exist")
+	})
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := valid()
+			if tc.bundle != nil {
+				tc.bundle(bundle)
+			}
+			// The data read once is both verified and parsed
+			data := []byte("kind: hardn-profile\n")
+			signers := []string{"0123 4567 89AB CDEF 0123  4567 89AB CDEF 0123 4567"}
+			repo := new(MockProfileBundleRepository)
+			repo.On("ReadFile", "web.yml").Return(data, nil).Once()
+			repo.On("VerifyBundle", "web.yml", data, signers).Return(tc.signer, tc.verifyError)
+			repo.On("ParseBundle", "web.yml", data).Return(bundle, nil).Maybe()
+
+			service := NewProfileBundleServiceImpl(repo)
+			loaded, err := service.LoadBundle("web.yml", tc.requireSignature, signers)
+
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.signer != "", loaded.Signed)
+			assert.Equal(t, tc.signer, loaded.Signer)
+		})
+	}
+}
+
+func TestProfileBundleServiceImpl_Import(t *testing.T) {
+	bundle := &model.ProfileBundle{
+		Config: "sshPort: 2208\n",
+		Sysctl: []model.ProfileFile{{Path: "/etc/sysctl.d/60-hardening.conf", Content: "kernel.kptr_restrict = 2\n"}},
+	}
+
+	t.Run("unchanged sysctl file skipped", func(t *testing.T) {
+		repo := new(MockProfileBundleRepository)
+		repo.On("ReadFile", "/etc/hardn/hardn.yml").Return([]byte("sshPort: 22\n"), nil)
+		repo.On("ReadFile", "/etc/sysctl.d/60-hardening.conf").Return([]byte("kernel.kptr_restrict = 2\n"), nil)
+		repo.On("WriteFile", "/etc/hardn/hardn.yml", []byte("sshPort: 2208\n")).Return(nil)
+
+		service := NewProfileBundleServiceImpl(repo)
+		changes, err := service.PlanImport(bundle, "/etc/hardn/hardn.yml")
+		assert.NoError(t, err)
+		assert.Equal(t, []model.ProfileChange{
+			{Path: "/etc/hardn/hardn.yml", Before: []byte("sshPort: 22\n"), After: []byte("sshPort: 2208\n")},
+		}, changes)
+
+		assert.NoError(t, service.ApplyImport(changes))
+		repo.AssertNotCalled(t, "ReloadSysctl")
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("new sysctl file applied", func(t *testing.T) {
+		repo := new(MockProfileBundleRepository)
+		repo.On("ReadFile", "/etc/hardn/hardn.yml").Return([]byte("sshPort: 2208\n"), nil)
+		repo.On("ReadFile", "/etc/sysctl.d/60-hardening.conf").Return(nil, nil)
+		repo.On("WriteFile", "/etc/sysctl.d/60-hardening.conf", []byte("kernel.kptr_restrict = 2\n")).Return(nil)
+		repo.On("ReloadSysctl").Return(nil)
+
+		service := NewProfileBundleServiceImpl(repo)
+		changes, err := service.PlanImport(bundle, "/etc/hardn/hardn.yml")
+		assert.NoError(t, err)
+		assert.Len(t, changes, 1)
+		assert.Nil(t, changes[0].Before)
+
+		assert.NoError(t, service.ApplyImport(changes))
+		repo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,149 @@
+// pkg/domain/service/proxmox_service.go
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// ProxmoxService defines operations for hardening a Proxmox VE host
+type ProxmoxService interface {
+	// ApplyHardening applies the Proxmox VE settings of a policy; the web
+	// UI allowlist is left to the firewall and pveproxy together
+	ApplyHardening(policy model.ProxmoxHardening) error
+
+	// GetStatus reports the Proxmox VE hardening in effect
+	GetStatus() (*model.ProxmoxStatus, error)
+}
+
+// ProxmoxServiceImpl implements ProxmoxService
+type ProxmoxServiceImpl struct {
+	repository ProxmoxRepository
+	osInfo     model.OSInfo
+}
+
+// NewProxmoxServiceImpl creates a new ProxmoxServiceImpl
+func NewProxmoxServiceImpl(repository ProxmoxRepository, osInfo model.OSInfo) *ProxmoxServiceImpl {
+	return &ProxmoxServiceImpl{
+		repository: repository,
+		osInfo:     osInfo,
+	}
+}
+
+// ProxmoxRepository defines the repository operations needed by ProxmoxService
+type ProxmoxRepository interface {
+	DisableSubscriptionNag() error
+	SubscriptionNagDisabled() (bool, error)
+	ReadPveproxyDefaults() (map[string]string, error)
+	WritePveproxyDefaults(settings map[string]string) error
+	RestartPveproxy() error
+	GetRealmTwoFactor(realm string) (string, error)
+	SetRealmTwoFactor(realm, tfaType string) error
+}
+
+// ValidateProxmoxAllowlist checks that every web UI allowlist entry is an
+// address or network, which is all pveproxy's ALLOW_FROM accepts
+func ValidateProxmoxAllowlist(allowlist []string) error {
+	for _, source := range allowlist {
+		if err := ValidateRuleSource(source); err != nil {
+			return fmt.Errorf("web UI allowlist: %w", err)
+		}
+	}
+	return nil
+}
+
+// PveproxySettings returns the /etc/default/pveproxy variables a policy
+// sets. pveproxy checks ALLOW_FROM itself too, so the web UI stays
+// restricted if the firewall is turned off.
+func PveproxySettings(policy model.ProxmoxHardening) map[string]string {
+	settings := make(map[string]string)
+	if policy.HardenCiphers {
+		settings["CIPHERS"] = model.ProxmoxCiphers
+		settings["HONOR_CIPHER_ORDER"] = "1"
+	}
+	if len(policy.WebUIAllowlist) > 0 {
+		settings["ALLOW_FROM"] = strings.Join(policy.WebUIAllowlist, ",")
+		settings["DENY_FROM"] = "all"
+		settings["POLICY"] = "allow"
+	}
+	return settings
+}
+
+func (s *ProxmoxServiceImpl) ApplyHardening(policy model.ProxmoxHardening) error {
+	if err := ValidateProxmoxAllowlist(policy.WebUIAllowlist); err != nil {
+		return err
+	}
+
+	if policy.DisableSubscriptionNag {
+		if err := s.repository.DisableSubscriptionNag(); err != nil {
+			return err
+		}
+	}
+
+	if settings := PveproxySettings(policy); len(settings) > 0 {
+		current, err := s.repository.ReadPveproxyDefaults()
+		if err != nil {
+			return err
+		}
+		changed := false
+		for key, value := range settings {
+			if current[key] != value {
+				changed = true
+				break
+			}
+		}
+		if changed {
+			if err := s.repository.WritePveproxyDefaults(settings); err != nil {
+				return err
+			}
+			if err := s.repository.RestartPveproxy(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if policy.RealmTwoFactor {
+		current, err := s.repository.GetRealmTwoFactor(model.ProxmoxRealm)
+		if err != nil {
+			return err
+		}
+		if current != model.ProxmoxTwoFactorType {
+			if err := s.repository.SetRealmTwoFactor(model.ProxmoxRealm, model.ProxmoxTwoFactorType); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *ProxmoxServiceImpl) GetStatus() (*model.ProxmoxStatus, error) {
+	status := &model.ProxmoxStatus{}
+
+	nagDisabled, err := s.repository.SubscriptionNagDisabled()
+	if err != nil {
+		return nil, err
+	}
+	status.SubscriptionNagDisabled = nagDisabled
+
+	settings, err := s.repository.ReadPveproxyDefaults()
+	if err != nil {
+		return nil, err
+	}
+	if allowFrom := settings["ALLOW_FROM"]; allowFrom != "" && settings["DENY_FROM"] == "all" {
+		for _, source := range strings.Split(allowFrom, ",") {
+			if source = strings.TrimSpace(source); source != "" {
+				status.WebUIAllowlist = append(status.WebUIAllowlist, source)
+			}
+		}
+	}
+	status.CiphersHardened = settings["CIPHERS"] == model.ProxmoxCiphers
+
+	tfa, err := s.repository.GetRealmTwoFactor(model.ProxmoxRealm)
+	if err != nil {
+		return nil, err
+	}
+	status.RealmTwoFactor = tfa
+	return status, nil
+}
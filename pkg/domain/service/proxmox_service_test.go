@@ -0,0 +1,130 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockProxmoxRepository is a mock implementation of ProxmoxRepository
+type MockProxmoxRepository struct {
+	mock.Mock
+}
+
+func (m *MockProxmoxRepository) DisableSubscriptionNag() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockProxmoxRepository) SubscriptionNagDisabled() (bool, error) {
+	args := m.Called()
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockProxmoxRepository) ReadPveproxyDefaults() (map[string]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
+func (m *MockProxmoxRepository) WritePveproxyDefaults(settings map[string]string) error {
+	args := m.Called(settings)
+	return args.Error(0)
+}
+
+func (m *MockProxmoxRepository) RestartPveproxy() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockProxmoxRepository) GetRealmTwoFactor(realm string) (string, error) {
+	args := m.Called(realm)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockProxmoxRepository) SetRealmTwoFactor(realm, tfaType string) error {
+	args := m.Called(realm, tfaType)
+	return args.Error(0)
+}
+
+func TestProxmoxServiceImpl_ApplyHardening(t *testing.T) {
+	t.Run("all settings", func(t *testing.T) {
+		repo := new(MockProxmoxRepository)
+		repo.On("DisableSubscriptionNag").Return(nil)
+		repo.On("ReadPveproxyDefaults").Return(map[string]string{}, nil)
+		repo.On("WritePveproxyDefaults", map[string]string{
+			"CIPHERS":            model.ProxmoxCiphers,
+			"HONOR_CIPHER_ORDER": "1",
+			"ALLOW_FROM":         "10.0.0.0/24,192.168.1.5",
+			"DENY_FROM":          "all",
+			"POLICY":             "allow",
+		}).Return(nil)
+		repo.On("RestartPveproxy").Return(nil)
+		repo.On("GetRealmTwoFactor", "pve").Return("", nil)
+		repo.On("SetRealmTwoFactor", "pve", "oath").Return(nil)
+
+		service := NewProxmoxServiceImpl(repo, model.OSInfo{Type: "debian"})
+		err := service.ApplyHardening(model.ProxmoxHardening{
+			DisableSubscriptionNag: true,
+			WebUIAllowlist:         []string{"10.0.0.0/24", "192.168.1.5"},
+			HardenCiphers:          true,
+			RealmTwoFactor:         true,
+		})
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("already applied", func(t *testing.T) {
+		repo := new(MockProxmoxRepository)
+		repo.On("ReadPveproxyDefaults").Return(map[string]string{
+			"CIPHERS":            model.ProxmoxCiphers,
+			"HONOR_CIPHER_ORDER": "1",
+		}, nil)
+		repo.On("GetRealmTwoFactor", "pve").Return("oath", nil)
+
+		service := NewProxmoxServiceImpl(repo, model.OSInfo{Type: "debian"})
+		err := service.ApplyHardening(model.ProxmoxHardening{HardenCiphers: true, RealmTwoFactor: true})
+
+		assert.NoError(t, err)
+		repo.AssertNotCalled(t, "WritePveproxyDefaults", mock.Anything)
+		repo.AssertNotCalled(t, "RestartPveproxy")
+		repo.AssertNotCalled(t, "SetRealmTwoFactor", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid allowlist", func(t *testing.T) {
+		repo := new(MockProxmoxRepository)
+		service := NewProxmoxServiceImpl(repo, model.OSInfo{Type: "debian"})
+
+		err := service.ApplyHardening(model.ProxmoxHardening{WebUIAllowlist: []string{"office.example.com"}})
+
+		assert.ErrorContains(t, err, "web UI allowlist")
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestProxmoxServiceImpl_GetStatus(t *testing.T) {
+	repo := new(MockProxmoxRepository)
+	repo.On("SubscriptionNagDisabled").Return(true, nil)
+	repo.On("ReadPveproxyDefaults").Return(map[string]string{
+		"ALLOW_FROM": "10.0.0.0/24, 192.168.1.5",
+		"DENY_FROM":  "all",
+		"CIPHERS":    "HIGH:!aNULL",
+	}, nil)
+	repo.On("GetRealmTwoFactor", "pve").Return("oath", nil)
+
+	service := NewProxmoxServiceImpl(repo, model.OSInfo{Type: "debian"})
+	status, err := service.GetStatus()
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.ProxmoxStatus{
+		SubscriptionNagDisabled: true,
+		WebUIAllowlist:          []string{"10.0.0.0/24", "192.168.1.5"},
+		CiphersHardened:         false,
+		RealmTwoFactor:          "oath",
+	}, status)
+}
@@ -0,0 +1,182 @@
+// pkg/domain/service/remote_service.go
+package service
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// RemoteService defines operations for running hardn on other hosts
+type RemoteService interface {
+	// LoadInventory reads an inventory from a YAML file
+	LoadInventory(path string) (*model.RemoteInventory, error)
+
+	// SelectHosts picks the hosts of an inventory whose name or group
+	// matches a pattern, in inventory order, with the defaults applied.
+	// No patterns, or "all", select every host.
+	SelectHosts(inventory *model.RemoteInventory, patterns []string) ([]model.RemoteHost, error)
+
+	// Run runs hardn on the hosts of a request and returns one result per
+	// host, in the order of the request
+	Run(request model.RemoteRequest) []model.RemoteResult
+}
+
+// RemoteServiceImpl implements RemoteService
+type RemoteServiceImpl struct {
+	repository RemoteRepository
+}
+
+// NewRemoteServiceImpl creates a new RemoteServiceImpl
+func NewRemoteServiceImpl(repository RemoteRepository) *RemoteServiceImpl {
+	return &RemoteServiceImpl{
+		repository: repository,
+	}
+}
+
+// RemoteRepository defines the repository operations needed by RemoteService
+type RemoteRepository interface {
+	LoadInventory(path string) (*model.RemoteInventory, error)
+	PushBinary(host model.RemoteHost, binary string) (string, error)
+	RemoveBinary(host model.RemoteHost, path string) error
+	RunHardn(host model.RemoteHost, hardn string, args []string) ([]byte, int, error)
+}
+
+func (s *RemoteServiceImpl) LoadInventory(path string) (*model.RemoteInventory, error) {
+	inventory, err := s.repository.LoadInventory(path)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, host := range inventory.Hosts {
+		if host.Name == "" {
+			return nil, fmt.Errorf("%s lists a host without a name", path)
+		}
+		if seen[host.Name] {
+			return nil, fmt.Errorf("%s lists host %s twice", path, host.Name)
+		}
+		seen[host.Name] = true
+	}
+	return inventory, nil
+}
+
+func (s *RemoteServiceImpl) SelectHosts(inventory *model.RemoteInventory, patterns []string) ([]model.RemoteHost, error) {
+	wanted := make(map[string]bool)
+	for _, pattern := range patterns {
+		if pattern == "all" {
+			wanted = nil
+			break
+		}
+		wanted[pattern] = true
+	}
+	if len(wanted) == 0 {
+		wanted = nil
+	}
+
+	matched := make(map[string]bool)
+	var hosts []model.RemoteHost
+	for _, host := range inventory.Hosts {
+		selected := wanted == nil
+		for _, name := range append([]string{host.Name}, host.Groups...) {
+			if wanted[name] {
+				matched[name] = true
+				selected = true
+			}
+		}
+		if !selected {
+			continue
+		}
+
+		host = host.WithDefaults(inventory.Defaults)
+		if err := validateRemoteHost(host); err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, host)
+	}
+
+	for pattern := range wanted {
+		if !matched[pattern] {
+			return nil, fmt.Errorf("no host or group named %s in the inventory", pattern)
+		}
+	}
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("the inventory lists no hosts")
+	}
+	return hosts, nil
+}
+
+// validateRemoteHost keeps inventory values from being read as ssh options
+func validateRemoteHost(host model.RemoteHost) error {
+	for _, value := range []string{host.Address, host.User, host.IdentityFile} {
+		if strings.HasPrefix(value, "-") || strings.ContainsAny(value, " \t\r\n") {
+			return fmt.Errorf("host %s: invalid value %q", host.Name, value)
+		}
+	}
+	if host.Port < 1 || host.Port > 65535 {
+		return fmt.Errorf("host %s: port %d is not a port", host.Name, host.Port)
+	}
+	return nil
+}
+
+func (s *RemoteServiceImpl) Run(request model.RemoteRequest) []model.RemoteResult {
+	args := append([]string{}, request.Args...)
+	if request.DryRun {
+		args = append(args, "--dry-run")
+	}
+	parallel := request.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	results := make([]model.RemoteResult, len(request.Hosts))
+	slots := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, host := range request.Hosts {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, host model.RemoteHost) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = s.runHost(host, args, request.Push || (host.Push != nil && *host.Push), request.Binary)
+		}(i, host)
+	}
+	wg.Wait()
+	return results
+}
+
+// runHost runs hardn on one host, pushing the binary first when asked
+func (s *RemoteServiceImpl) runHost(host model.RemoteHost, args []string, push bool, binary string) model.RemoteResult {
+	start := time.Now()
+	result := model.RemoteResult{Host: host.Name, ExitCode: -1}
+
+	hardn := "hardn"
+	if push {
+		remote, err := s.repository.PushBinary(host, binary)
+		if err != nil {
+			result.Status = model.RemoteUnreachable
+			result.Error = err.Error()
+			result.Duration = time.Since(start)
+			return result
+		}
+		hardn = remote
+		defer func() { _ = s.repository.RemoveBinary(host, remote) }()
+	}
+
+	output, code, err := s.repository.RunHardn(host, hardn, args)
+	result.Output = string(output)
+	result.ExitCode = code
+	switch {
+	case err != nil:
+		result.Status = model.RemoteUnreachable
+		result.Error = err.Error()
+	case code != 0:
+		result.Status = model.RemoteFailed
+	default:
+		result.Status = model.RemoteOK
+	}
+	result.Duration = time.Since(start)
+	return result
+}
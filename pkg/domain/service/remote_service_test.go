@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRemoteRepository is a mock implementation of RemoteRepository
+type MockRemoteRepository struct {
+	mock.Mock
+}
+
+func (m *MockRemoteRepository) LoadInventory(path string) (*model.RemoteInventory, error) {
+	args := m.Called(path)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RemoteInventory), args.Error(1)
+}
+
+func (m *MockRemoteRepository) PushBinary(host model.RemoteHost, binary string) (string, error) {
+	args := m.Called(host.Name, binary)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRemoteRepository) RemoveBinary(host model.RemoteHost, path string) error {
+	args := m.Called(host.Name, path)
+	return args.Error(0)
+}
+
+func (m *MockRemoteRepository) RunHardn(host model.RemoteHost, hardn string, args []string) ([]byte, int, error) {
+	called := m.Called(host.Name, hardn, args)
+	return []byte(called.String(0)), called.Int(1), called.Error(2)
+}
+
+func TestRemoteServiceImpl_SelectHosts(t *testing.T) {
+	push := true
+	inventory := &model.RemoteInventory{
+		Defaults: model.RemoteHost{User: "admin", Push: &push},
+		Hosts: []model.RemoteHost{
+			{Name: "web1", Groups: []string{"web"}},
+			{Name: "web2", Groups: []string{"web"}, Port: 2222},
+			{Name: "db1", Address: "192.0.2.20", User: "root", Groups: []string{"db"}},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		patterns      []string
+		expected      []string
+		expectedError bool
+	}{
+		{name: "all by default", expected: []string{"web1", "web2", "db1"}},
+		{name: "all", patterns: []string{"all"}, expected: []string{"web1", "web2", "db1"}},
+		{name: "group", patterns: []string{"web"}, expected: []string{"web1", "web2"}},
+		{name: "inventory order", patterns: []string{"db1", "web1"}, expected: []string{"web1", "db1"}},
+		{name: "unknown host", patterns: []string{"web", "mail1"}, expectedError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service := NewRemoteServiceImpl(new(MockRemoteRepository))
+			hosts, err := service.SelectHosts(inventory, tc.patterns)
+
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for _, host := range hosts {
+				names = append(names, host.Name)
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+
+	t.Run("defaults applied", func(t *testing.T) {
+		service := NewRemoteServiceImpl(new(MockRemoteRepository))
+		hosts, err := service.SelectHosts(inventory, []string{"web2", "db1"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "admin@web2", hosts[0].Target())
+		assert.Equal(t, 2222, hosts[0].Port)
+		assert.True(t, hosts[0].UseSudo())
+		assert.True(t, *hosts[0].Push)
+		assert.Equal(t, "root@192.0.2.20", hosts[1].Target())
+		assert.Equal(t, 22, hosts[1].Port)
+		assert.False(t, hosts[1].UseSudo())
+	})
+
+	t.Run("option as address", func(t *testing.T) {
+		service := NewRemoteServiceImpl(new(MockRemoteRepository))
+		_, err := service.SelectHosts(&model.RemoteInventory{
+			Hosts: []model.RemoteHost{{Name: "evil", Address: "-oProxyCommand=sh"}},
+		}, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestRemoteServiceImpl_LoadInventory(t *testing.T) {
+	repo := new(MockRemoteRepository)
+	repo.On("LoadInventory", "/etc/hardn/inventory.yml").Return(&model.RemoteInventory{
+		Hosts: []model.RemoteHost{{Name: "web1"}, {Name: "web1"}},
+	}, nil)
+
+	service := NewRemoteServiceImpl(repo)
+	_, err := service.LoadInventory("/etc/hardn/inventory.yml")
+	assert.ErrorContains(t, err, "twice")
+}
+
+func TestRemoteServiceImpl_Run(t *testing.T) {
+	push := true
+	hosts := []model.RemoteHost{
+		{Name: "web1"},
+		{Name: "web2"},
+		{Name: "db1", Push: &push},
+		{Name: "db2", Push: &push},
+	}
+	args := []string{"run-all", "--dry-run"}
+
+	repo := new(MockRemoteRepository)
+	repo.On("RunHardn", "web1", "hardn", args).Return("done\n", 0, nil)
+	repo.On("RunHardn", "web2", "hardn", args).Return("audit failed\n", 1, nil)
+	repo.On("PushBinary", "db1", "/usr/local/bin/hardn").Return("/tmp/tmp.db1/hardn", nil)
+	repo.On("RunHardn", "db1", "/tmp/tmp.db1/hardn", args).Return("done\n", 0, nil)
+	repo.On("RemoveBinary", "db1", "/tmp/tmp.db1/hardn").Return(nil)
+	repo.On("PushBinary", "db2", "/usr/local/bin/hardn").Return("", errors.New("connection refused"))
+
+	service := NewRemoteServiceImpl(repo)
+	results := service.Run(model.RemoteRequest{
+		Hosts:    hosts,
+		Args:     []string{"run-all"},
+		DryRun:   true,
+		Binary:   "/usr/local/bin/hardn",
+		Parallel: 2,
+	})
+
+	require.Len(t, results, 4)
+	assert.Equal(t, "web1", results[0].Host)
+	assert.Equal(t, model.RemoteOK, results[0].Status)
+	assert.Equal(t, model.RemoteFailed, results[1].Status)
+	assert.Equal(t, 1, results[1].ExitCode)
+	assert.Equal(t, model.RemoteOK, results[2].Status)
+	assert.Equal(t, model.RemoteUnreachable, results[3].Status)
+	assert.Equal(t, "connection refused", results[3].Error)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,297 @@
+// pkg/domain/service/repository_trust_service.go
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// RepositoryTrustService defines operations for checking how packages are
+// verified and installing repository signing keys
+type RepositoryTrustService interface {
+	// CheckTrust reports keys trusted for every repository, repositories
+	// without a signature check and options that accept unsigned packages
+	CheckTrust() ([]model.RepoTrustFinding, error)
+
+	// InstallKey downloads and checks a key, installs it and writes the
+	// repositories it signs
+	InstallKey(key model.RepositoryKey) (*model.RepositoryKeyResult, error)
+}
+
+// RepositoryTrustServiceImpl implements RepositoryTrustService
+type RepositoryTrustServiceImpl struct {
+	repository RepositoryTrustRepository
+	osInfo     model.OSInfo
+}
+
+// NewRepositoryTrustServiceImpl creates a new RepositoryTrustServiceImpl
+func NewRepositoryTrustServiceImpl(repository RepositoryTrustRepository, osInfo model.OSInfo) *RepositoryTrustServiceImpl {
+	return &RepositoryTrustServiceImpl{
+		repository: repository,
+		osInfo:     osInfo,
+	}
+}
+
+// RepositoryTrustRepository defines the repository operations needed by RepositoryTrustService
+type RepositoryTrustRepository interface {
+	ReadSources() ([]model.AptFile, error)
+	ReadAptConfig() ([]model.AptFile, error)
+	TrustedKeys() ([]model.TrustedKey, error)
+	DownloadKey(url string) ([]byte, error)
+	KeyFingerprints(key []byte) ([]string, error)
+	InstallKey(path string, key []byte) error
+	WriteSources(path string, data []byte) error
+	RemoveSources(path string) (bool, error)
+}
+
+// CheckTrust finds what lets a package in without a signature from the key
+// meant for its repository. The apt-key keyring is medium, since any key in
+// it can sign any repository; other unowned keys in trusted.gpg.d are low.
+// Repositories marked trusted and options accepting unsigned packages skip
+// the signature check altogether and are high. RHEL-family repositories
+// are not checked.
+func (s *RepositoryTrustServiceImpl) CheckTrust() ([]model.RepoTrustFinding, error) {
+	if model.IsRHELFamily(s.osInfo.Type) {
+		return nil, nil
+	}
+
+	keys, err := s.repository.TrustedKeys()
+	if err != nil {
+		return nil, err
+	}
+	if s.osInfo.Type == "alpine" {
+		var findings []model.RepoTrustFinding
+		for _, key := range keys {
+			if key.Owner == "" {
+				findings = append(findings, model.RepoTrustFinding{
+					File:     key.Path,
+					Issue:    model.RepoTrustGlobalKey,
+					Severity: model.SeverityLow,
+					Detail:   "added by hand and trusted for every repository",
+				})
+			}
+		}
+		return findings, nil
+	}
+
+	sourceFiles, err := s.repository.ReadSources()
+	if err != nil {
+		return nil, err
+	}
+	var findings []model.RepoTrustFinding
+	var unsigned []string
+	for _, file := range sourceFiles {
+		for _, source := range model.ParseAptSourcesFile(file, false) {
+			if !source.Enabled {
+				continue
+			}
+			uris := strings.Join(source.URIs, " ")
+			switch {
+			case source.Trusted():
+				findings = append(findings, model.RepoTrustFinding{
+					File:     file.Path,
+					Issue:    model.RepoTrustTrustedRepository,
+					Severity: model.SeverityHigh,
+					Detail:   fmt.Sprintf("%s is marked trusted, so its signature is not checked", uris),
+				})
+			case model.AptBool(source.Options["allow-insecure"]):
+				findings = append(findings, model.RepoTrustFinding{
+					File:     file.Path,
+					Issue:    model.RepoTrustAllowInsecure,
+					Severity: model.SeverityHigh,
+					Detail:   fmt.Sprintf("%s allows an unsigned repository", uris),
+				})
+			case source.Options["signed-by"] == "":
+				unsigned = append(unsigned, uris)
+			}
+		}
+	}
+
+	var trustedFindings []model.RepoTrustFinding
+	for _, key := range keys {
+		switch {
+		case key.Path == model.AptLegacyKeyringPath:
+			detail := "keys added with apt-key are trusted for every repository"
+			if len(unsigned) > 0 {
+				detail += "; without signed-by, " + strings.Join(dedupe(unsigned), ", ") + " may be signed by any of them"
+			}
+			trustedFindings = append(trustedFindings, model.RepoTrustFinding{
+				File:     key.Path,
+				Issue:    model.RepoTrustLegacyKeyring,
+				Severity: model.SeverityMedium,
+				Detail:   detail,
+			})
+		case key.Owner == "":
+			trustedFindings = append(trustedFindings, model.RepoTrustFinding{
+				File:     key.Path,
+				Issue:    model.RepoTrustGlobalKey,
+				Severity: model.SeverityLow,
+				Detail:   "added by hand and trusted for every repository",
+			})
+		}
+	}
+	findings = append(trustedFindings, findings...)
+
+	configFiles, err := s.repository.ReadAptConfig()
+	if err != nil {
+		return nil, err
+	}
+	return append(findings, insecureAptOptions(configFiles)...), nil
+}
+
+// insecureAptOptions reports the insecure options whose last setting, in the
+// order APT reads the files, turns them on
+func insecureAptOptions(files []model.AptFile) []model.RepoTrustFinding {
+	type setting struct {
+		file  string
+		name  string
+		value string
+	}
+	last := make(map[string]setting)
+	var order []string
+
+	for _, file := range files {
+		for _, option := range model.ParseAptConfig(file.Data) {
+			for name := range model.AptInsecureOptions {
+				if !strings.EqualFold(option.Name, name) {
+					continue
+				}
+				if _, seen := last[name]; !seen {
+					order = append(order, name)
+				}
+				last[name] = setting{file: file.Path, name: option.Name, value: option.Value}
+			}
+		}
+	}
+
+	var findings []model.RepoTrustFinding
+	for _, name := range order {
+		set := last[name]
+		if !model.AptBool(set.value) {
+			continue
+		}
+		findings = append(findings, model.RepoTrustFinding{
+			File:     set.file,
+			Issue:    model.AptInsecureOptions[name],
+			Severity: model.SeverityHigh,
+			Detail:   fmt.Sprintf("%s is set to %q, so APT installs packages it cannot verify", set.name, set.value),
+		})
+	}
+	return findings
+}
+
+// dedupe drops repeated values, keeping the first of each
+func dedupe(values []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// InstallKey only installs a key that matches its configured fingerprint.
+// On Debian and Ubuntu the repositories it signs are written to a deb822
+// file naming the key with Signed-By, which replaces a one-line list of the
+// same name. apk has no per-repository keys, so on Alpine the key is only
+// installed; the fingerprint there is the SHA-256 of the key file.
+func (s *RepositoryTrustServiceImpl) InstallKey(key model.RepositoryKey) (*model.RepositoryKeyResult, error) {
+	if err := key.Validate(); err != nil {
+		return nil, err
+	}
+	if model.IsRHELFamily(s.osInfo.Type) {
+		return nil, fmt.Errorf("repository keys on %s are imported with rpm --import and are not managed by hardn", s.osInfo.Type)
+	}
+	if s.osInfo.Type == "alpine" && len(key.Repos) > 0 {
+		return nil, fmt.Errorf("repository key %s: repos are written on Debian and Ubuntu only; add Alpine repositories to /etc/apk/repositories", key.Name)
+	}
+
+	// Check the entries before anything is downloaded or written
+	var sources []*model.AptSource
+	for _, repo := range key.Repos {
+		source, err := model.ParseAptSourceLine(strings.ReplaceAll(repo, "CODENAME", s.osInfo.Codename))
+		if err != nil {
+			return nil, fmt.Errorf("repository key %s: %w", key.Name, err)
+		}
+		if source != nil {
+			sources = append(sources, source)
+		}
+	}
+
+	data, err := s.repository.DownloadKey(key.URL)
+	if err != nil {
+		return nil, err
+	}
+	expected := model.NormalizeFingerprint(key.Fingerprint)
+	result := &model.RepositoryKeyResult{Key: key}
+
+	if s.osInfo.Type == "alpine" {
+		sum := sha256.Sum256(data)
+		if actual := strings.ToUpper(hex.EncodeToString(sum[:])); actual != expected {
+			return nil, fmt.Errorf("key from %s has SHA-256 %s, expected %s", key.URL, actual, expected)
+		}
+		result.KeyringPath = model.ApkKeyPath(key.URL)
+		if err := s.repository.InstallKey(result.KeyringPath, data); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	fingerprints, err := s.repository.KeyFingerprints(data)
+	if err != nil {
+		return nil, err
+	}
+	// The whole file becomes the Signed-By keyring, so every key in it
+	// would be trusted for the repositories
+	switch {
+	case len(fingerprints) == 0:
+		return nil, fmt.Errorf("%s does not hold an OpenPGP key", key.URL)
+	case len(fingerprints) > 1:
+		return nil, fmt.Errorf("%s holds %d keys (%s); only a file holding the key %s alone is installed",
+			key.URL, len(fingerprints), strings.Join(fingerprints, ", "), expected)
+	case model.NormalizeFingerprint(fingerprints[0]) != expected:
+		return nil, fmt.Errorf("key from %s has fingerprint %s, expected %s", key.URL, fingerprints[0], expected)
+	}
+
+	armored := bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN PGP PUBLIC KEY BLOCK-----"))
+	result.KeyringPath = model.AptKeyringPath(key.Name, armored)
+	if err := s.repository.InstallKey(result.KeyringPath, data); err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return result, nil
+	}
+
+	stanzas := make([]string, 0, len(sources))
+	for _, source := range sources {
+		if source.Options == nil {
+			source.Options = make(map[string]string)
+		}
+		source.Options["signed-by"] = result.KeyringPath
+		stanzas = append(stanzas, source.Deb822())
+	}
+	content := fmt.Sprintf("# Managed by hardn: repositories signed by the %s key\n", key.Name) + strings.Join(stanzas, "\n")
+
+	result.SourcesPath = filepath.Join(model.AptSourcesDir, key.Name+".sources")
+	if err := s.repository.WriteSources(result.SourcesPath, []byte(content)); err != nil {
+		return nil, err
+	}
+	listPath := filepath.Join(model.AptSourcesDir, key.Name+".list")
+	removed, err := s.repository.RemoveSources(listPath)
+	if err != nil {
+		return nil, err
+	}
+	if removed {
+		result.Replaced = listPath
+	}
+	return result, nil
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRepositoryTrustRepository is a mock implementation of RepositoryTrustRepository
+type MockRepositoryTrustRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepositoryTrustRepository) ReadSources() ([]model.AptFile, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AptFile), args.Error(1)
+}
+
+func (m *MockRepositoryTrustRepository) ReadAptConfig() ([]model.AptFile, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AptFile), args.Error(1)
+}
+
+func (m *MockRepositoryTrustRepository) TrustedKeys() ([]model.TrustedKey, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.TrustedKey), args.Error(1)
+}
+
+func (m *MockRepositoryTrustRepository) DownloadKey(url string) ([]byte, error) {
+	args := m.Called(url)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockRepositoryTrustRepository) KeyFingerprints(key []byte) ([]string, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRepositoryTrustRepository) InstallKey(path string, key []byte) error {
+	return m.Called(path, key).Error(0)
+}
+
+func (m *MockRepositoryTrustRepository) WriteSources(path string, data []byte) error {
+	return m.Called(path, data).Error(0)
+}
+
+func (m *MockRepositoryTrustRepository) RemoveSources(path string) (bool, error) {
+	args := m.Called(path)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestRepositoryTrustServiceImpl_CheckTrust(t *testing.T) {
+	mockRepo := new(MockRepositoryTrustRepository)
+	mockRepo.On("TrustedKeys").Return([]model.TrustedKey{
+		{Path: "/etc/apt/trusted.gpg"},
+		{Path: "/etc/apt/trusted.gpg.d/debian-archive-bookworm-automatic.asc", Owner: "debian-archive-keyring"},
+		{Path: "/etc/apt/trusted.gpg.d/vendor.gpg"},
+	}, nil)
+	mockRepo.On("ReadSources").Return([]model.AptFile{
+		{Path: "/etc/apt/sources.list", Data: []byte(
+			"deb http://deb.debian.org/debian bookworm main\n" +
+				"deb [trusted=yes] http://repo.example.com/debian stable main\n")},
+		{Path: "/etc/apt/sources.list.d/docker.sources", Data: []byte(
+			"Types: deb\nURIs: https://download.docker.com/linux/debian\nSuites: bookworm\n" +
+				"Components: stable\nSigned-By: /etc/apt/keyrings/docker.asc\n")},
+		{Path: "/etc/apt/sources.list.d/old.sources", Data: []byte(
+			"Types: deb\nURIs: http://old.example.com\nSuites: stable\nTrusted: yes\nEnabled: no\n")},
+	}, nil)
+	mockRepo.On("ReadAptConfig").Return([]model.AptFile{
+		{Path: "/etc/apt/apt.conf.d/10insecure", Data: []byte(
+			"APT::Get::AllowUnauthenticated \"true\";\nAcquire { AllowInsecureRepositories \"1\"; };\n")},
+		{Path: "/etc/apt/apt.conf.d/99fix", Data: []byte("Acquire::AllowInsecureRepositories \"false\";\n")},
+	}, nil)
+
+	service := NewRepositoryTrustServiceImpl(mockRepo, model.OSInfo{Type: "debian", Codename: "bookworm"})
+	findings, err := service.CheckTrust()
+	require.NoError(t, err)
+
+	var issues []string
+	for _, finding := range findings {
+		issues = append(issues, finding.Issue+" "+finding.File)
+	}
+	assert.Equal(t, []string{
+		"legacy_keyring /etc/apt/trusted.gpg",
+		"global_key /etc/apt/trusted.gpg.d/vendor.gpg",
+		"trusted_repository /etc/apt/sources.list",
+		"allow_unauthenticated /etc/apt/apt.conf.d/10insecure",
+	}, issues, "disabled repositories and options turned off later are not reported")
+	assert.Contains(t, findings[0].Detail, "http://deb.debian.org/debian may be signed by any of them")
+	assert.NotContains(t, findings[0].Detail, "docker")
+	assert.Equal(t, model.SeverityHigh, findings[3].Severity)
+}
+
+func TestRepositoryTrustServiceImpl_InstallKey(t *testing.T) {
+	key := []byte("-----BEGIN PGP PUBLIC KEY BLOCK-----\n...\n-----END PGP PUBLIC KEY BLOCK-----\n")
+	docker := model.RepositoryKey{
+		Name:        "docker",
+		URL:         "https://download.docker.com/linux/debian/gpg",
+		Fingerprint: "9DC8 5822 9FC7 DD38 854A  E2D8 8D81 803C 0EBF CD88",
+		Repos:       []string{"deb [arch=amd64] https://download.docker.com/linux/debian CODENAME stable"},
+	}
+
+	mockRepo := new(MockRepositoryTrustRepository)
+	mockRepo.On("DownloadKey", docker.URL).Return(key, nil)
+	mockRepo.On("KeyFingerprints", key).Return([]string{"9DC858229FC7DD38854AE2D88D81803C0EBFCD88"}, nil)
+	mockRepo.On("InstallKey", "/etc/apt/keyrings/docker.asc", key).Return(nil)
+	mockRepo.On("WriteSources", "/etc/apt/sources.list.d/docker.sources", []byte(
+		"# Managed by hardn: repositories signed by the docker key\n"+
+			"Types: deb\n"+
+			"URIs: https://download.docker.com/linux/debian\n"+
+			"Suites: bookworm\n"+
+			"Components: stable\n"+
+			"Architectures: amd64\n"+
+			"Signed-By: /etc/apt/keyrings/docker.asc\n")).Return(nil)
+	mockRepo.On("RemoveSources", "/etc/apt/sources.list.d/docker.list").Return(true, nil)
+
+	service := NewRepositoryTrustServiceImpl(mockRepo, model.OSInfo{Type: "debian", Codename: "bookworm"})
+	result, err := service.InstallKey(docker)
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/apt/keyrings/docker.asc", result.KeyringPath)
+	assert.Equal(t, "/etc/apt/sources.list.d/docker.list", result.Replaced)
+	mockRepo.AssertExpectations(t)
+
+	// A key that does not match its fingerprint is never installed
+	mockRepo = new(MockRepositoryTrustRepository)
+	mockRepo.On("DownloadKey", docker.URL).Return(key, nil)
+	mockRepo.On("KeyFingerprints", key).Return([]string{"0123456789ABCDEF0123456789ABCDEF01234567"}, nil)
+	service = NewRepositoryTrustServiceImpl(mockRepo, model.OSInfo{Type: "debian", Codename: "bookworm"})
+	_, err = service.InstallKey(docker)
+	assert.ErrorContains(t, err, "expected 9DC858229FC7DD38854AE2D88D81803C0EBFCD88")
+	mockRepo.AssertNotCalled(t, "InstallKey", mock.Anything, mock.Anything)
+
+	// Nor is a file that bundles another key with the expected one
+	mockRepo = new(MockRepositoryTrustRepository)
+	mockRepo.On("DownloadKey", docker.URL).Return(key, nil)
+	mockRepo.On("KeyFingerprints", key).Return([]string{
+		"9DC858229FC7DD38854AE2D88D81803C0EBFCD88",
+		"0123456789ABCDEF0123456789ABCDEF01234567",
+	}, nil)
+	service = NewRepositoryTrustServiceImpl(mockRepo, model.OSInfo{Type: "debian", Codename: "bookworm"})
+	_, err = service.InstallKey(docker)
+	assert.ErrorContains(t, err, "holds 2 keys")
+	mockRepo.AssertNotCalled(t, "InstallKey", mock.Anything, mock.Anything)
+
+	// Alpine keys are checked by their SHA-256 and keep their file name
+	mockRepo = new(MockRepositoryTrustRepository)
+	mockRepo.On("DownloadKey", "https://example.com/keys/ops-5f3a1c2b.rsa.pub").Return([]byte("key\n"), nil)
+	mockRepo.On("InstallKey", "/etc/apk/keys/ops-5f3a1c2b.rsa.pub", []byte("key\n")).Return(nil)
+	service = NewRepositoryTrustServiceImpl(mockRepo, model.OSInfo{Type: "alpine"})
+	ops := model.RepositoryKey{
+		Name:        "ops",
+		URL:         "https://example.com/keys/ops-5f3a1c2b.rsa.pub",
+		Fingerprint: "a7998f247bd965694ff227fa325c81169a07471a8b6808d3e002a486c4e65975",
+	}
+	result, err = service.InstallKey(ops)
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/apk/keys/ops-5f3a1c2b.rsa.pub", result.KeyringPath)
+	assert.Empty(t, result.SourcesPath)
+
+	ops.Repos = []string{"https://example.com/alpine/v3.20/main"}
+	_, err = service.InstallKey(ops)
+	assert.ErrorContains(t, err, "add Alpine repositories to /etc/apk/repositories")
+}
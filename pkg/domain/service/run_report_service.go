@@ -0,0 +1,115 @@
+// pkg/domain/service/run_report_service.go
+package service
+
+import (
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// RunReportService defines operations for hardening run reports
+type RunReportService interface {
+	// SaveReport completes a report with the changes of its run and its
+	// outcome, then saves it
+	SaveReport(report model.RunReport, runID string) (*model.RunReport, error)
+
+	// GetReport returns the report with the given ID
+	GetReport(id string) (*model.RunReport, error)
+
+	// LastReport returns the latest report
+	LastReport() (*model.RunReport, error)
+
+	// ListReports returns the latest limit reports, oldest first, or all
+	// of them if limit is not positive
+	ListReports(limit int) ([]model.RunReport, error)
+}
+
+// RunReportServiceImpl implements RunReportService
+type RunReportServiceImpl struct {
+	repository RunReportRepository
+}
+
+// NewRunReportServiceImpl creates a new RunReportServiceImpl
+func NewRunReportServiceImpl(repository RunReportRepository) *RunReportServiceImpl {
+	return &RunReportServiceImpl{
+		repository: repository,
+	}
+}
+
+// RunReportRepository defines the repository operations needed by RunReportService
+type RunReportRepository interface {
+	SaveReport(report model.RunReport, keep int) error
+	GetReport(id string) (*model.RunReport, error)
+	ListReports() ([]model.RunReport, error)
+	RunChanges(runID string) ([]model.RunReportChange, error)
+}
+
+func (s *RunReportServiceImpl) SaveReport(report model.RunReport, runID string) (*model.RunReport, error) {
+	report.Changes = []model.RunReportChange{}
+	if runID != "" {
+		// The report shares the ID of the run it describes
+		report.ID = runID
+		changes, err := s.repository.RunChanges(runID)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("changes of run %s not readable: %v", runID, err))
+		} else {
+			report.Changes = changes
+		}
+	} else {
+		report.ID = s.unusedID(report)
+	}
+
+	report.Status = model.RunSucceeded
+	if len(report.Errors) > 0 {
+		report.Status = model.RunFailed
+	}
+	for _, step := range report.Steps {
+		if step.Status == model.RunFailed {
+			report.Status = model.RunFailed
+		}
+	}
+
+	if err := s.repository.SaveReport(report, model.MaxRunReports); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// unusedID names a report of a run that changed nothing after the time it
+// started, adding a counter as run IDs do
+func (s *RunReportServiceImpl) unusedID(report model.RunReport) string {
+	base := report.Started.Format(model.RunIDLayout)
+	id := base
+	for n := 2; ; n++ {
+		if _, err := s.repository.GetReport(id); err != nil {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+func (s *RunReportServiceImpl) GetReport(id string) (*model.RunReport, error) {
+	return s.repository.GetReport(id)
+}
+
+func (s *RunReportServiceImpl) LastReport() (*model.RunReport, error) {
+	reports, err := s.ListReports(1)
+	if err != nil {
+		return nil, err
+	}
+	if len(reports) == 0 {
+		return nil, fmt.Errorf("no run reports recorded")
+	}
+	return &reports[0], nil
+}
+
+func (s *RunReportServiceImpl) ListReports(limit int) ([]model.RunReport, error) {
+	reports, err := s.repository.ListReports()
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(reports) > limit {
+		reports = reports[len(reports)-limit:]
+	}
+	return reports, nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRunReportRepository is a mock implementation of RunReportRepository
+type MockRunReportRepository struct {
+	mock.Mock
+}
+
+func (m *MockRunReportRepository) SaveReport(report model.RunReport, keep int) error {
+	args := m.Called(report, keep)
+	return args.Error(0)
+}
+
+func (m *MockRunReportRepository) GetReport(id string) (*model.RunReport, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RunReport), args.Error(1)
+}
+
+func (m *MockRunReportRepository) ListReports() ([]model.RunReport, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.RunReport), args.Error(1)
+}
+
+func (m *MockRunReportRepository) RunChanges(runID string) ([]model.RunReportChange, error) {
+	args := m.Called(runID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.RunReportChange), args.Error(1)
+}
+
+func TestRunReportServiceImpl_SaveReport(t *testing.T) {
+	started := time.Date(2024, 6, 1, 2, 0, 0, 0, time.UTC)
+	changes := []model.RunReportChange{{Kind: model.RunChangeFile, Subject: "/etc/ssh/sshd_config", Summary: "modified"}}
+
+	mockRepo := new(MockRunReportRepository)
+	mockRepo.On("RunChanges", "20240601-020000").Return(changes, nil)
+	mockRepo.On("SaveReport", mock.Anything, model.MaxRunReports).Return(nil)
+	service := NewRunReportServiceImpl(mockRepo)
+
+	report, err := service.SaveReport(model.RunReport{
+		Started: started,
+		Steps: []model.RunReportStep{
+			{Name: "SSH configuration", Status: model.RunSucceeded},
+			{Name: "Firewall", Status: model.RunFailed, Error: "ufw not found"},
+		},
+	}, "20240601-020000")
+	require.NoError(t, err)
+	assert.Equal(t, "20240601-020000", report.ID, "the report shares the run's ID")
+	assert.Equal(t, changes, report.Changes)
+	assert.Equal(t, model.RunFailed, report.Status, "a failed step fails the run")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRunReportServiceImpl_SaveReportWithoutChanges(t *testing.T) {
+	started := time.Date(2024, 6, 1, 2, 0, 0, 0, time.UTC)
+
+	mockRepo := new(MockRunReportRepository)
+	mockRepo.On("GetReport", "20240601-020000").Return(&model.RunReport{ID: "20240601-020000"}, nil)
+	mockRepo.On("GetReport", "20240601-020000-2").Return(nil, errors.New("not found"))
+	mockRepo.On("SaveReport", mock.Anything, model.MaxRunReports).Return(nil)
+	service := NewRunReportServiceImpl(mockRepo)
+
+	report, err := service.SaveReport(model.RunReport{Started: started}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "20240601-020000-2", report.ID)
+	assert.Equal(t, model.RunSucceeded, report.Status)
+	assert.NotNil(t, report.Changes)
+	mockRepo.AssertNotCalled(t, "RunChanges", mock.Anything)
+}
+
+func TestRunReportServiceImpl_LastReport(t *testing.T) {
+	mockRepo := new(MockRunReportRepository)
+	mockRepo.On("ListReports").Return([]model.RunReport{{ID: "a"}, {ID: "b"}}, nil).Once()
+	mockRepo.On("ListReports").Return(nil, nil)
+	service := NewRunReportServiceImpl(mockRepo)
+
+	report, err := service.LastReport()
+	require.NoError(t, err)
+	assert.Equal(t, "b", report.ID)
+
+	_, err = service.LastReport()
+	assert.Error(t, err)
+}
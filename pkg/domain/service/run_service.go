@@ -0,0 +1,152 @@
+// pkg/domain/service/run_service.go
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// RunService defines operations for recorded hardening runs
+type RunService interface {
+	// ListRuns returns the recorded runs, oldest first
+	ListRuns() ([]model.RunManifest, error)
+
+	// GetRun returns the run with the given ID
+	GetRun(id string) (*model.RunManifest, error)
+
+	// Rollback reverts the selected changes of a run, numbered from 1 as
+	// listed in its manifest, or every pending change if none are selected
+	Rollback(id string, selected []int, force bool) (*model.RunRollback, error)
+
+	// LastApplied returns the latest run to change each hardening area,
+	// ignoring changes that were rolled back
+	LastApplied() (map[string]model.AreaState, error)
+}
+
+// RunServiceImpl implements RunService
+type RunServiceImpl struct {
+	repository RunRepository
+	now        func() time.Time
+}
+
+// NewRunServiceImpl creates a new RunServiceImpl
+func NewRunServiceImpl(repository RunRepository) *RunServiceImpl {
+	return &RunServiceImpl{
+		repository: repository,
+		now:        time.Now,
+	}
+}
+
+// RunRepository defines the repository operations needed by RunService
+type RunRepository interface {
+	ListRuns() ([]model.RunManifest, error)
+	GetRun(id string) (*model.RunManifest, error)
+	SaveRun(run model.RunManifest) error
+	RevertChange(runID string, change model.RunChange, force bool) error
+}
+
+func (s *RunServiceImpl) ListRuns() ([]model.RunManifest, error) {
+	return s.repository.ListRuns()
+}
+
+func (s *RunServiceImpl) GetRun(id string) (*model.RunManifest, error) {
+	return s.repository.GetRun(id)
+}
+
+func (s *RunServiceImpl) Rollback(id string, selected []int, force bool) (*model.RunRollback, error) {
+	run, err := s.repository.GetRun(id)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes, err := rollbackOrder(*run, selected)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.RunRollback{RunID: run.ID}
+	for _, index := range indexes {
+		change := run.Changes[index]
+		if err := s.repository.RevertChange(run.ID, change, force); err != nil {
+			result.Failed = append(result.Failed, model.RunChangeFailure{Change: change, Err: err})
+			continue
+		}
+		reverted := s.now()
+		run.Changes[index].Reverted = &reverted
+		result.Reverted = append(result.Reverted, run.Changes[index])
+	}
+
+	if len(result.Reverted) > 0 {
+		if err := s.repository.SaveRun(*run); err != nil {
+			return result, fmt.Errorf("changes were rolled back but the manifest was not updated: %w", err)
+		}
+	}
+	return result, nil
+}
+
+func (s *RunServiceImpl) LastApplied() (map[string]model.AreaState, error) {
+	runs, err := s.repository.ListRuns()
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]model.AreaState)
+	for _, run := range runs {
+		applied := run.Updated
+		if applied.IsZero() {
+			applied = run.Started
+		}
+		for _, change := range run.Changes {
+			area := change.Area()
+			if area == "" || change.IsReverted() {
+				continue
+			}
+			if current, ok := states[area]; ok && current.Applied.After(applied) {
+				continue
+			}
+			states[area] = model.AreaState{Area: area, RunID: run.ID, Applied: applied, Version: run.Version}
+		}
+	}
+	return states, nil
+}
+
+// rollbackOrder returns the indexes of the changes to revert. Files are
+// restored first so services restarted afterwards pick up their previous
+// configuration; within each group the latest change is reverted first.
+func rollbackOrder(run model.RunManifest, selected []int) ([]int, error) {
+	chosen := make(map[int]bool)
+	if len(selected) == 0 {
+		for i, change := range run.Changes {
+			if !change.IsReverted() {
+				chosen[i] = true
+			}
+		}
+	}
+	for _, number := range selected {
+		if number < 1 || number > len(run.Changes) {
+			return nil, fmt.Errorf("run %s has no change %d (it has %d)", run.ID, number, len(run.Changes))
+		}
+		if run.Changes[number-1].IsReverted() {
+			return nil, fmt.Errorf("change %d of run %s was already rolled back", number, run.ID)
+		}
+		chosen[number-1] = true
+	}
+	if len(chosen) == 0 {
+		return nil, fmt.Errorf("run %s has no changes left to roll back", run.ID)
+	}
+
+	var files, others []int
+	for i := len(run.Changes) - 1; i >= 0; i-- {
+		if !chosen[i] {
+			continue
+		}
+		if run.Changes[i].Kind == model.RunChangeFile {
+			files = append(files, i)
+		} else {
+			others = append(others, i)
+		}
+	}
+	return append(files, others...), nil
+}
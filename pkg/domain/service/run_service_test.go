@@ -0,0 +1,175 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRunRepository is a mock implementation of RunRepository
+type MockRunRepository struct {
+	mock.Mock
+}
+
+func (m *MockRunRepository) ListRuns() ([]model.RunManifest, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.RunManifest), args.Error(1)
+}
+
+func (m *MockRunRepository) GetRun(id string) (*model.RunManifest, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RunManifest), args.Error(1)
+}
+
+func (m *MockRunRepository) SaveRun(run model.RunManifest) error {
+	args := m.Called(run)
+	return args.Error(0)
+}
+
+func (m *MockRunRepository) RevertChange(runID string, change model.RunChange, force bool) error {
+	args := m.Called(runID, change, force)
+	return args.Error(0)
+}
+
+func testRun() *model.RunManifest {
+	return &model.RunManifest{
+		ID: "20260101-020000",
+		Changes: []model.RunChange{
+			{Kind: model.RunChangePackage, Name: "ufw", Summary: "installed"},
+			{Kind: model.RunChangeFile, Path: "/etc/ssh/sshd_config", Summary: "modified"},
+			{Kind: model.RunChangeService, Name: "ssh", Summary: "was running"},
+			{Kind: model.RunChangeFile, Path: "/etc/resolv.conf", Summary: "modified"},
+		},
+	}
+}
+
+func TestRunServiceImpl_Rollback(t *testing.T) {
+	t.Run("files first, latest first", func(t *testing.T) {
+		mockRepo := new(MockRunRepository)
+		mockRepo.On("GetRun", "20260101-020000").Return(testRun(), nil)
+
+		var order []string
+		mockRepo.On("RevertChange", "20260101-020000", mock.Anything, false).
+			Run(func(args mock.Arguments) {
+				order = append(order, args.Get(1).(model.RunChange).Describe())
+			}).Return(nil)
+		mockRepo.On("SaveRun", mock.Anything).Return(nil)
+
+		service := NewRunServiceImpl(mockRepo)
+		result, err := service.Rollback("20260101-020000", nil, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"file /etc/resolv.conf modified",
+			"file /etc/ssh/sshd_config modified",
+			"service ssh was running",
+			"package ufw installed",
+		}, order)
+		assert.Len(t, result.Reverted, 4)
+
+		saved := mockRepo.Calls[len(mockRepo.Calls)-1].Arguments.Get(0).(model.RunManifest)
+		assert.Equal(t, 0, saved.PendingChanges())
+	})
+
+	t.Run("selected changes and failures", func(t *testing.T) {
+		mockRepo := new(MockRunRepository)
+		mockRepo.On("GetRun", "20260101-020000").Return(testRun(), nil)
+		mockRepo.On("RevertChange", "20260101-020000", mock.MatchedBy(func(c model.RunChange) bool {
+			return c.Kind == model.RunChangeFile
+		}), true).Return(errors.New("saved copy missing"))
+		mockRepo.On("RevertChange", "20260101-020000", mock.MatchedBy(func(c model.RunChange) bool {
+			return c.Kind == model.RunChangeService
+		}), true).Return(nil)
+		mockRepo.On("SaveRun", mock.MatchedBy(func(run model.RunManifest) bool {
+			return run.Changes[2].IsReverted() && !run.Changes[1].IsReverted() && !run.Changes[0].IsReverted()
+		})).Return(nil)
+
+		service := NewRunServiceImpl(mockRepo)
+		result, err := service.Rollback("20260101-020000", []int{2, 3}, true)
+
+		require.NoError(t, err)
+		assert.Len(t, result.Reverted, 1)
+		require.Len(t, result.Failed, 1)
+		assert.Equal(t, "/etc/ssh/sshd_config", result.Failed[0].Change.Path)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid selection", func(t *testing.T) {
+		run := testRun()
+		reverted := time.Now()
+		run.Changes[0].Reverted = &reverted
+
+		mockRepo := new(MockRunRepository)
+		mockRepo.On("GetRun", "20260101-020000").Return(run, nil)
+		service := NewRunServiceImpl(mockRepo)
+
+		_, err := service.Rollback("20260101-020000", []int{5}, false)
+		assert.Error(t, err)
+
+		_, err = service.Rollback("20260101-020000", []int{1}, false)
+		assert.ErrorContains(t, err, "already rolled back")
+		mockRepo.AssertNotCalled(t, "RevertChange", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("nothing left", func(t *testing.T) {
+		run := testRun()
+		reverted := time.Now()
+		for i := range run.Changes {
+			run.Changes[i].Reverted = &reverted
+		}
+
+		mockRepo := new(MockRunRepository)
+		mockRepo.On("GetRun", "20260101-020000").Return(run, nil)
+
+		_, err := NewRunServiceImpl(mockRepo).Rollback("20260101-020000", nil, false)
+		assert.ErrorContains(t, err, "no changes left")
+	})
+}
+
+func TestRunServiceImpl_LastApplied(t *testing.T) {
+	day := time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC)
+	reverted := day.Add(time.Hour)
+
+	mockRepo := new(MockRunRepository)
+	mockRepo.On("ListRuns").Return([]model.RunManifest{
+		{
+			ID:      "20260101-020000",
+			Version: "0.3.1",
+			Started: day,
+			Updated: day.Add(time.Minute),
+			Changes: testRun().Changes,
+		},
+		{
+			ID:      "20260105-020000",
+			Version: "0.3.2",
+			Updated: day.Add(96 * time.Hour),
+			Changes: []model.RunChange{
+				{Kind: model.RunChangeFile, Path: "/etc/ssh/sshd_config.d/hardn.conf", Summary: "created"},
+				{Kind: model.RunChangeFile, Path: "/etc/resolv.conf", Summary: "modified", Reverted: &reverted},
+				{Kind: model.RunChangeFile, Path: "/home/deploy/.ssh/authorized_keys", Summary: "created"},
+				{Kind: model.RunChangeFile, Path: "/etc/motd", Summary: "modified"},
+			},
+		},
+	}, nil)
+
+	service := NewRunServiceImpl(mockRepo)
+	states, err := service.LastApplied()
+
+	require.NoError(t, err)
+	assert.Len(t, states, 4)
+	assert.Equal(t, model.AreaState{Area: model.AreaSSH, RunID: "20260105-020000", Applied: day.Add(96 * time.Hour), Version: "0.3.2"}, states[model.AreaSSH])
+	assert.Equal(t, "20260101-020000", states[model.AreaDNS].RunID, "a rolled back change does not count")
+	assert.Equal(t, "20260101-020000", states[model.AreaFirewall].RunID)
+	assert.Equal(t, "0.3.2", states[model.AreaUsers].Version)
+}
@@ -0,0 +1,271 @@
+// pkg/domain/service/schedule_service.go
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// ScheduleService defines operations for scheduled hardn runs
+type ScheduleService interface {
+	// InstallSchedule installs a schedule, replacing any existing one
+	InstallSchedule(schedule model.Schedule) error
+
+	// RemoveSchedule removes the installed schedule
+	RemoveSchedule() error
+
+	// GetScheduleStatus reports the installed schedule
+	GetScheduleStatus() (*model.ScheduleStatus, error)
+}
+
+// ScheduleServiceImpl implements ScheduleService
+type ScheduleServiceImpl struct {
+	repository ScheduleRepository
+}
+
+// NewScheduleServiceImpl creates a new ScheduleServiceImpl
+func NewScheduleServiceImpl(repository ScheduleRepository) *ScheduleServiceImpl {
+	return &ScheduleServiceImpl{
+		repository: repository,
+	}
+}
+
+// ScheduleRepository defines the repository operations needed by ScheduleService
+type ScheduleRepository interface {
+	InstallSchedule(schedule model.Schedule) error
+	RemoveSchedule() error
+	GetScheduleStatus() (*model.ScheduleStatus, error)
+}
+
+func (s *ScheduleServiceImpl) InstallSchedule(schedule model.Schedule) error {
+	if len(schedule.Commands) == 0 {
+		return fmt.Errorf("schedule has no commands to run")
+	}
+	return s.repository.InstallSchedule(schedule)
+}
+
+func (s *ScheduleServiceImpl) RemoveSchedule() error {
+	return s.repository.RemoveSchedule()
+}
+
+func (s *ScheduleServiceImpl) GetScheduleStatus() (*model.ScheduleStatus, error) {
+	return s.repository.GetScheduleStatus()
+}
+
+// BuildSchedule validates the interval and tasks and builds the commands to
+// run. Hardening tasks share one hardn invocation, run-all replaces the
+// individual steps, and the removal of expired SSH keys, the self-test and
+// the audit run afterwards as commands of their own. With watchdog, the hardening invocation rolls its changes
+// back if SSH stops answering.
+func BuildSchedule(interval string, tasks []string, binary string, configFile string, watchdog bool) (model.Schedule, error) {
+	scheduleInterval, ok := model.LookupScheduleInterval(interval)
+	if !ok {
+		names := make([]string, len(model.ScheduleIntervals))
+		for i, known := range model.ScheduleIntervals {
+			names[i] = known.Name
+		}
+		return model.Schedule{}, fmt.Errorf("unknown schedule interval %q (use %s)", interval, strings.Join(names, ", "))
+	}
+
+	if len(tasks) == 0 {
+		return model.Schedule{}, fmt.Errorf("no scheduled tasks selected (use %s)", strings.Join(model.ScheduleTasks, ", "))
+	}
+
+	selected := make(map[string]bool)
+	for _, task := range tasks {
+		task = strings.ToLower(strings.TrimSpace(task))
+		if !isScheduleTask(task) {
+			return model.Schedule{}, fmt.Errorf("unknown scheduled task %q (use %s)", task, strings.Join(model.ScheduleTasks, ", "))
+		}
+		selected[task] = true
+	}
+
+	var configArgs []string
+	if configFile != "" {
+		configArgs = []string{"--config", configFile}
+	}
+
+	schedule := model.Schedule{Interval: scheduleInterval}
+
+	// Tasks are kept in run order whatever order they were given in
+	var flags []string
+	for _, task := range model.ScheduleTasks {
+		if !selected[task] {
+			continue
+		}
+		schedule.Tasks = append(schedule.Tasks, task)
+
+		if task == model.ScheduleTaskAudit || task == model.ScheduleTaskSelfTest || task == model.ScheduleTaskSSHKeys {
+			continue
+		}
+		if selected[model.ScheduleTaskRunAll] && task != model.ScheduleTaskRunAll {
+			continue
+		}
+		flags = append(flags, model.ScheduleTaskFlags[task])
+	}
+
+	if len(flags) > 0 {
+		command := append([]string{binary}, configArgs...)
+		if watchdog {
+			command = append(command, "--watchdog")
+		}
+		schedule.Commands = append(schedule.Commands, append(command, flags...))
+	}
+	if selected[model.ScheduleTaskSSHKeys] {
+		command := append([]string{binary, "ssh", "keys", "prune"}, configArgs...)
+		schedule.Commands = append(schedule.Commands, command)
+	}
+	if selected[model.ScheduleTaskSelfTest] {
+		command := append([]string{binary, "selftest"}, configArgs...)
+		schedule.Commands = append(schedule.Commands, command)
+	}
+	if selected[model.ScheduleTaskAudit] {
+		command := append([]string{binary, "audit"}, configArgs...)
+		schedule.Commands = append(schedule.Commands, command)
+	}
+
+	return schedule, nil
+}
+
+// BuildSummarySchedule validates the cron expression the posture summary
+// is sent on and builds the command that sends it
+func BuildSummarySchedule(cron string, binary string, configFile string) (*model.SummarySchedule, error) {
+	cron = strings.Join(strings.Fields(cron), " ")
+	onCalendar, err := CronOnCalendar(cron)
+	if err != nil {
+		return nil, err
+	}
+
+	command := []string{binary, "summary", "send"}
+	if configFile != "" {
+		command = append(command, "--config", configFile)
+	}
+	return &model.SummarySchedule{Cron: cron, OnCalendar: onCalendar, Command: command}, nil
+}
+
+// cronFields are the fields of a cron expression, in order, with the
+// values each takes
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// cronWeekdays are the systemd names of the cron days of the week, where
+// both 0 and 7 are Sunday
+var cronWeekdays = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// CronOnCalendar converts a five-field cron expression to the systemd
+// OnCalendar expression matching the same times. Fields take *, numbers,
+// lists, ranges and */N steps, and days of the week may be named. A day
+// of month and a day of week can not both be restricted: cron runs when
+// either matches, while systemd needs both to.
+func CronOnCalendar(cron string) (string, error) {
+	fields := strings.Fields(cron)
+	if len(fields) != len(cronFields) {
+		return "", fmt.Errorf("invalid cron expression %q: expected 5 fields (minute hour day-of-month month day-of-week)", cron)
+	}
+	if fields[2] != "*" && fields[4] != "*" {
+		return "", fmt.Errorf("invalid cron expression %q: restrict the day of month or the day of week, not both", cron)
+	}
+
+	converted := make([]string, len(fields))
+	for i, field := range fields {
+		value, err := cronOnCalendarField(field, i)
+		if err != nil {
+			return "", fmt.Errorf("invalid cron expression %q: %w", cron, err)
+		}
+		converted[i] = value
+	}
+
+	onCalendar := fmt.Sprintf("*-%s-%s %s:%s:00", converted[3], converted[2], converted[1], converted[0])
+	if fields[4] != "*" {
+		onCalendar = converted[4] + " " + onCalendar
+	}
+	return onCalendar, nil
+}
+
+// cronOnCalendarField converts field i of a cron expression. Days of the
+// week are listed one by one, since systemd weeks start on Monday and a
+// cron range may not.
+func cronOnCalendarField(field string, i int) (string, error) {
+	spec := cronFields[i]
+	weekday := i == len(cronFields)-1
+	if field == "*" {
+		return "*", nil
+	}
+	if step, ok := strings.CutPrefix(field, "*/"); ok && !weekday {
+		n, err := strconv.Atoi(step)
+		if err != nil || n < 1 || n > spec.max {
+			return "", fmt.Errorf("invalid %s step %q", spec.name, step)
+		}
+		return fmt.Sprintf("%02d/%d", spec.min, n), nil
+	}
+
+	var values []string
+	for _, part := range strings.Split(field, ",") {
+		low, high, isRange := strings.Cut(part, "-")
+		first, err := cronValue(low, spec.name, spec.min, spec.max, weekday)
+		if err != nil {
+			return "", err
+		}
+		last := first
+		if isRange {
+			if last, err = cronValue(high, spec.name, spec.min, spec.max, weekday); err != nil {
+				return "", err
+			}
+			if last < first {
+				return "", fmt.Errorf("invalid %s range %q", spec.name, part)
+			}
+		}
+
+		switch {
+		case weekday:
+			for day := first; day <= last; day++ {
+				if !slices.Contains(values, cronWeekdays[day]) {
+					values = append(values, cronWeekdays[day])
+				}
+			}
+		case isRange:
+			values = append(values, fmt.Sprintf("%02d..%02d", first, last))
+		default:
+			values = append(values, fmt.Sprintf("%02d", first))
+		}
+	}
+	return strings.Join(values, ","), nil
+}
+
+// cronValue parses one value of a cron field; days of the week may also be
+// given by their first three letters
+func cronValue(value string, name string, min, max int, weekday bool) (int, error) {
+	if weekday {
+		for day, dayName := range cronWeekdays[:7] {
+			if strings.EqualFold(value, dayName) {
+				return day, nil
+			}
+		}
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("invalid %s %q (expected %d-%d)", name, value, min, max)
+	}
+	return n, nil
+}
+
+func isScheduleTask(task string) bool {
+	for _, known := range model.ScheduleTasks {
+		if task == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockScheduleRepository is a mock implementation of ScheduleRepository
+type MockScheduleRepository struct {
+	mock.Mock
+}
+
+func (m *MockScheduleRepository) InstallSchedule(schedule model.Schedule) error {
+	args := m.Called(schedule)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) RemoveSchedule() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) GetScheduleStatus() (*model.ScheduleStatus, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ScheduleStatus), args.Error(1)
+}
+
+func TestBuildSchedule(t *testing.T) {
+	tests := []struct {
+		name           string
+		interval       string
+		tasks          []string
+		configFile     string
+		watchdog       bool
+		expectTasks    []string
+		expectCommands [][]string
+		expectError    bool
+	}{
+		{
+			name:           "audit only",
+			interval:       "daily",
+			tasks:          []string{"audit"},
+			expectTasks:    []string{"audit"},
+			expectCommands: [][]string{{"/usr/local/bin/hardn", "audit"}},
+		},
+		{
+			name:        "hardening steps share one run before the audit",
+			interval:    "weekly",
+			tasks:       []string{"audit", "dns", "firewall"},
+			configFile:  "/etc/hardn/hardn.yml",
+			expectTasks: []string{"firewall", "dns", "audit"},
+			expectCommands: [][]string{
+				{"/usr/local/bin/hardn", "--config", "/etc/hardn/hardn.yml", "--configure-ufw", "--configure-dns"},
+				{"/usr/local/bin/hardn", "audit", "--config", "/etc/hardn/hardn.yml"},
+			},
+		},
+		{
+			name:           "run-all replaces individual steps",
+			interval:       "hourly",
+			tasks:          []string{"disable-root", "run-all"},
+			expectTasks:    []string{"run-all", "disable-root"},
+			expectCommands: [][]string{{"/usr/local/bin/hardn", "--run-all"}},
+		},
+		{
+			name:        "self-test runs after hardening and before the audit",
+			interval:    "daily",
+			tasks:       []string{"audit", "selftest", "firewall"},
+			expectTasks: []string{"firewall", "selftest", "audit"},
+			expectCommands: [][]string{
+				{"/usr/local/bin/hardn", "--configure-ufw"},
+				{"/usr/local/bin/hardn", "selftest"},
+				{"/usr/local/bin/hardn", "audit"},
+			},
+		},
+		{
+			name:        "expired SSH keys are pruned before the self-test",
+			interval:    "daily",
+			tasks:       []string{"selftest", "ssh-keys", "firewall"},
+			configFile:  "/etc/hardn/hardn.yml",
+			expectTasks: []string{"firewall", "ssh-keys", "selftest"},
+			expectCommands: [][]string{
+				{"/usr/local/bin/hardn", "--config", "/etc/hardn/hardn.yml", "--configure-ufw"},
+				{"/usr/local/bin/hardn", "ssh", "keys", "prune", "--config", "/etc/hardn/hardn.yml"},
+				{"/usr/local/bin/hardn", "selftest", "--config", "/etc/hardn/hardn.yml"},
+			},
+		},
+		{
+			name:        "watchdog guards the hardening invocation only",
+			interval:    "daily",
+			tasks:       []string{"firewall", "audit"},
+			watchdog:    true,
+			expectTasks: []string{"firewall", "audit"},
+			expectCommands: [][]string{
+				{"/usr/local/bin/hardn", "--watchdog", "--configure-ufw"},
+				{"/usr/local/bin/hardn", "audit"},
+			},
+		},
+		{
+			name:           "task names are normalized",
+			interval:       "monthly",
+			tasks:          []string{" DNS "},
+			expectTasks:    []string{"dns"},
+			expectCommands: [][]string{{"/usr/local/bin/hardn", "--configure-dns"}},
+		},
+		{name: "unknown interval", interval: "fortnightly", tasks: []string{"audit"}, expectError: true},
+		{name: "unknown task", interval: "daily", tasks: []string{"reboot"}, expectError: true},
+		{name: "no tasks", interval: "daily", expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := BuildSchedule(tc.interval, tc.tasks, "/usr/local/bin/hardn", tc.configFile, tc.watchdog)
+
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.interval, schedule.Interval.Name)
+			assert.Equal(t, tc.expectTasks, schedule.Tasks)
+			assert.Equal(t, tc.expectCommands, schedule.Commands)
+		})
+	}
+}
+
+func TestCronOnCalendar(t *testing.T) {
+	for cron, want := range map[string]string{
+		"0 8 * * 1":        "Mon *-*-* 08:00:00",
+		"30 6 * * mon-fri": "Mon,Tue,Wed,Thu,Fri *-*-* 06:30:00",
+		"0 22 * * 5-7":     "Fri,Sat,Sun *-*-* 22:00:00",
+		"0,30 */2 * * *":   "*-*-* 00/2:00,30:00",
+		"15 3 1,15 * *":    "*-*-01,15 03:15:00",
+		"0 0 1-7 1-6 *":    "*-01..06-01..07 00:00:00",
+	} {
+		onCalendar, err := CronOnCalendar(cron)
+		require.NoError(t, err, cron)
+		assert.Equal(t, want, onCalendar, cron)
+	}
+
+	for cron, problem := range map[string]string{
+		"0 8 * *":        "expected 5 fields",
+		"60 8 * * 1":     `invalid minute "60" (expected 0-59)`,
+		"0 8 1 * 1":      "restrict the day of month or the day of week, not both",
+		"0 8 * * */2":    `invalid day of week "*/2"`,
+		"0 18-9 * * *":   `invalid hour range "18-9"`,
+		"0 8 * * funday": `invalid day of week "funday"`,
+	} {
+		_, err := CronOnCalendar(cron)
+		assert.ErrorContains(t, err, problem, cron)
+	}
+}
+
+func TestBuildSummarySchedule(t *testing.T) {
+	summary, err := BuildSummarySchedule("0  8 * * 1", "/usr/local/bin/hardn", "/etc/hardn/hardn.yml")
+	require.NoError(t, err)
+	assert.Equal(t, &model.SummarySchedule{
+		Cron:       "0 8 * * 1",
+		OnCalendar: "Mon *-*-* 08:00:00",
+		Command:    []string{"/usr/local/bin/hardn", "summary", "send", "--config", "/etc/hardn/hardn.yml"},
+	}, summary)
+
+	_, err = BuildSummarySchedule("weekly", "/usr/local/bin/hardn", "")
+	assert.Error(t, err)
+}
+
+func TestScheduleServiceImpl_InstallSchedule(t *testing.T) {
+	t.Run("installs built schedule", func(t *testing.T) {
+		schedule, err := BuildSchedule("daily", []string{"audit"}, "/usr/local/bin/hardn", "", false)
+		assert.NoError(t, err)
+
+		mockRepo := new(MockScheduleRepository)
+		mockRepo.On("InstallSchedule", schedule).Return(nil)
+
+		service := NewScheduleServiceImpl(mockRepo)
+		assert.NoError(t, service.InstallSchedule(schedule))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects empty schedule", func(t *testing.T) {
+		mockRepo := new(MockScheduleRepository)
+
+		service := NewScheduleServiceImpl(mockRepo)
+		err := service.InstallSchedule(model.Schedule{})
+
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "InstallSchedule", mock.Anything)
+	})
+}
+
+func TestScheduleServiceImpl_GetScheduleStatus(t *testing.T) {
+	mockRepo := new(MockScheduleRepository)
+	mockRepo.On("GetScheduleStatus").Return(&model.ScheduleStatus{
+		Installed: true,
+		Backend:   model.ScheduleBackendSystemd,
+		Interval:  "daily",
+	}, nil)
+
+	service := NewScheduleServiceImpl(mockRepo)
+	status, err := service.GetScheduleStatus()
+
+	assert.NoError(t, err)
+	assert.True(t, status.Installed)
+	assert.Equal(t, "daily", status.Interval)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,170 @@
+// pkg/domain/service/selftest_service.go
+package service
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// SelfTestService defines operations for validating hardn-managed
+// configuration
+type SelfTestService interface {
+	// RunSelfTest validates each managed artifact
+	RunSelfTest() (*model.SelfTestReport, error)
+}
+
+// SelfTestServiceImpl implements SelfTestService
+type SelfTestServiceImpl struct {
+	repository SelfTestRepository
+	now        func() time.Time
+}
+
+// NewSelfTestServiceImpl creates a new SelfTestServiceImpl
+func NewSelfTestServiceImpl(repository SelfTestRepository) *SelfTestServiceImpl {
+	return &SelfTestServiceImpl{
+		repository: repository,
+		now:        time.Now,
+	}
+}
+
+// SelfTestRepository defines the repository operations needed by SelfTestService
+type SelfTestRepository interface {
+	ValidateSudoers() model.SelfTestCheck
+	ValidateSSHConfig() model.SelfTestCheck
+	ValidateFirewall() model.SelfTestCheck
+	ReadResolvConf() ([]byte, error)
+	ReadSysctlConfigs() (map[string][]byte, error)
+	SysctlKeyExists(key string) bool
+}
+
+// resolvConfOptions are the keywords resolv.conf(5) accepts
+var resolvConfOptions = map[string]bool{
+	"nameserver": true,
+	"domain":     true,
+	"search":     true,
+	"sortlist":   true,
+	"options":    true,
+}
+
+func (s *SelfTestServiceImpl) RunSelfTest() (*model.SelfTestReport, error) {
+	report := &model.SelfTestReport{Time: s.now()}
+	report.Checks = append(report.Checks,
+		s.repository.ValidateSudoers(),
+		s.repository.ValidateSSHConfig(),
+		s.repository.ValidateFirewall(),
+	)
+
+	resolvConf, err := s.repository.ReadResolvConf()
+	if err != nil {
+		return nil, err
+	}
+	report.Checks = append(report.Checks, checkResolvConf(resolvConf))
+
+	sysctlConfigs, err := s.repository.ReadSysctlConfigs()
+	if err != nil {
+		return nil, err
+	}
+	report.Checks = append(report.Checks, s.checkSysctl(sysctlConfigs))
+
+	return report, nil
+}
+
+// checkResolvConf checks each line of resolv.conf for a known keyword and
+// each nameserver for an address; the resolver silently ignores both kinds
+// of mistake
+func checkResolvConf(data []byte) model.SelfTestCheck {
+	check := model.SelfTestCheck{Name: "DNS", Target: "/etc/resolv.conf"}
+	if data == nil {
+		check.Status = model.SelfTestSkipped
+		check.Detail = "/etc/resolv.conf does not exist"
+		return check
+	}
+
+	nameservers := 0
+	for i, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], ";") {
+			continue
+		}
+
+		switch {
+		case !resolvConfOptions[fields[0]]:
+			check.Status = model.SelfTestFailed
+			check.Detail = fmt.Sprintf("line %d: unknown keyword %q", i+1, fields[0])
+			return check
+		case len(fields) < 2:
+			check.Status = model.SelfTestFailed
+			check.Detail = fmt.Sprintf("line %d: %s has no value", i+1, fields[0])
+			return check
+		case fields[0] == "nameserver":
+			// IPv6 link-local nameservers may name their interface
+			address, _, _ := strings.Cut(fields[1], "%")
+			if net.ParseIP(address) == nil {
+				check.Status = model.SelfTestFailed
+				check.Detail = fmt.Sprintf("line %d: nameserver %q is not an IP address", i+1, fields[1])
+				return check
+			}
+			nameservers++
+		}
+	}
+
+	if nameservers == 0 {
+		check.Status = model.SelfTestFailed
+		check.Detail = "no nameserver lines"
+		return check
+	}
+	check.Status = model.SelfTestPassed
+	return check
+}
+
+// checkSysctl loads the sysctl files the way sysctl --system would,
+// without applying them: every line must be a key = value pair whose key
+// the kernel has. Keys prefixed with "-" may be missing and globbed keys
+// are not checked.
+func (s *SelfTestServiceImpl) checkSysctl(configs map[string][]byte) model.SelfTestCheck {
+	check := model.SelfTestCheck{Name: "Sysctl", Target: "/etc/sysctl.conf, /etc/sysctl.d"}
+	if len(configs) == 0 {
+		check.Status = model.SelfTestSkipped
+		check.Detail = "no sysctl configuration files"
+		return check
+	}
+
+	paths := make([]string, 0, len(configs))
+	for path := range configs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		for i, line := range strings.Split(string(configs[path]), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+				continue
+			}
+
+			key, _, ok := strings.Cut(line, "=")
+			key = strings.TrimSpace(key)
+			if !ok || key == "" || key == "-" {
+				check.Status = model.SelfTestFailed
+				check.Detail = fmt.Sprintf("%s:%d: expected key = value", path, i+1)
+				return check
+			}
+			if strings.HasPrefix(key, "-") || strings.Contains(key, "*") {
+				continue
+			}
+			if !s.repository.SysctlKeyExists(key) {
+				check.Status = model.SelfTestFailed
+				check.Detail = fmt.Sprintf("%s:%d: unknown key %s", path, i+1, key)
+				return check
+			}
+		}
+	}
+
+	check.Status = model.SelfTestPassed
+	return check
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSelfTestRepository is a mock implementation of SelfTestRepository
+type MockSelfTestRepository struct {
+	mock.Mock
+}
+
+func (m *MockSelfTestRepository) ValidateSudoers() model.SelfTestCheck {
+	return m.Called().Get(0).(model.SelfTestCheck)
+}
+
+func (m *MockSelfTestRepository) ValidateSSHConfig() model.SelfTestCheck {
+	return m.Called().Get(0).(model.SelfTestCheck)
+}
+
+func (m *MockSelfTestRepository) ValidateFirewall() model.SelfTestCheck {
+	return m.Called().Get(0).(model.SelfTestCheck)
+}
+
+func (m *MockSelfTestRepository) ReadResolvConf() ([]byte, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockSelfTestRepository) ReadSysctlConfigs() (map[string][]byte, error) {
+	args := m.Called()
+	return args.Get(0).(map[string][]byte), args.Error(1)
+}
+
+func (m *MockSelfTestRepository) SysctlKeyExists(key string) bool {
+	return m.Called(key).Bool(0)
+}
+
+func newSelfTestRepository(resolvConf []byte, sysctl map[string][]byte) *MockSelfTestRepository {
+	mockRepo := new(MockSelfTestRepository)
+	mockRepo.On("ValidateSudoers").Return(model.SelfTestCheck{Name: "Sudoers", Status: model.SelfTestPassed})
+	mockRepo.On("ValidateSSHConfig").Return(model.SelfTestCheck{Name: "SSH", Status: model.SelfTestFailed,
+		Detail: "/etc/ssh/sshd_config line 12: Bad configuration option: PermitRootLgin"})
+	mockRepo.On("ValidateFirewall").Return(model.SelfTestCheck{Name: "Firewall", Status: model.SelfTestSkipped})
+	mockRepo.On("ReadResolvConf").Return(resolvConf, nil)
+	mockRepo.On("ReadSysctlConfigs").Return(sysctl, nil)
+	return mockRepo
+}
+
+func TestSelfTestServiceImpl_RunSelfTest(t *testing.T) {
+	mockRepo := newSelfTestRepository(
+		[]byte("# Generated by hardn\nnameserver 1.1.1.1\nnameserver fe80::1%eth0\nsearch example.com\noptions edns0\n"),
+		map[string][]byte{
+			"/etc/sysctl.conf":                       []byte("# kernel settings\nnet.ipv4.ip_forward = 0\n"),
+			"/etc/sysctl.d/99-local.conf":            []byte("-net.ipv6.conf.all.disable_ipv6 = 1\nnet.ipv4.conf.*.rp_filter = 1\n"),
+			"/etc/sysctl.d/10-kernel-hardening.conf": []byte("kernel/kptr_restrict = 2\n"),
+		})
+	mockRepo.On("SysctlKeyExists", "net.ipv4.ip_forward").Return(true)
+	mockRepo.On("SysctlKeyExists", "kernel/kptr_restrict").Return(true)
+
+	service := NewSelfTestServiceImpl(mockRepo)
+	now := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	report, err := service.RunSelfTest()
+	require.NoError(t, err)
+	assert.Equal(t, now, report.Time)
+
+	statuses := make(map[string]string)
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	assert.Equal(t, map[string]string{
+		"Sudoers":  model.SelfTestPassed,
+		"SSH":      model.SelfTestFailed,
+		"Firewall": model.SelfTestSkipped,
+		"DNS":      model.SelfTestPassed,
+		"Sysctl":   model.SelfTestPassed,
+	}, statuses)
+
+	failed := report.Failed()
+	require.Len(t, failed, 1)
+	assert.Equal(t, "SSH", failed[0].Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSelfTestServiceImpl_Sysctl(t *testing.T) {
+	t.Run("unknown key", func(t *testing.T) {
+		mockRepo := newSelfTestRepository([]byte("nameserver 9.9.9.9\n"), map[string][]byte{
+			"/etc/sysctl.d/50-net.conf": []byte("net.ipv4.tcp_syncookies = 1\nnet.ipv4.tcp_synccookies = 1\n"),
+		})
+		mockRepo.On("SysctlKeyExists", "net.ipv4.tcp_syncookies").Return(true)
+		mockRepo.On("SysctlKeyExists", "net.ipv4.tcp_synccookies").Return(false)
+
+		report, err := NewSelfTestServiceImpl(mockRepo).RunSelfTest()
+		require.NoError(t, err)
+		sysctl := report.Checks[len(report.Checks)-1]
+		assert.Equal(t, model.SelfTestFailed, sysctl.Status)
+		assert.Equal(t, "/etc/sysctl.d/50-net.conf:2: unknown key net.ipv4.tcp_synccookies", sysctl.Detail)
+	})
+
+	t.Run("line without a value", func(t *testing.T) {
+		mockRepo := newSelfTestRepository([]byte("nameserver 9.9.9.9\n"), map[string][]byte{
+			"/etc/sysctl.conf": []byte("kernel.sysrq 0\n"),
+		})
+
+		report, err := NewSelfTestServiceImpl(mockRepo).RunSelfTest()
+		require.NoError(t, err)
+		sysctl := report.Checks[len(report.Checks)-1]
+		assert.Equal(t, model.SelfTestFailed, sysctl.Status)
+		assert.Equal(t, "/etc/sysctl.conf:1: expected key = value", sysctl.Detail)
+	})
+
+	t.Run("no files", func(t *testing.T) {
+		mockRepo := newSelfTestRepository([]byte("nameserver 9.9.9.9\n"), map[string][]byte{})
+
+		report, err := NewSelfTestServiceImpl(mockRepo).RunSelfTest()
+		require.NoError(t, err)
+		assert.Equal(t, model.SelfTestSkipped, report.Checks[len(report.Checks)-1].Status)
+	})
+}
+
+func TestCheckResolvConf(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		status   string
+		expected string
+	}{
+		{"missing file", nil, model.SelfTestSkipped, "/etc/resolv.conf does not exist"},
+		{"valid", []byte("; comment\nnameserver 127.0.0.53\noptions edns0 trust-ad\n"), model.SelfTestPassed, ""},
+		{"unknown keyword", []byte("nameserver 1.1.1.1\nnamesever 8.8.8.8\n"), model.SelfTestFailed, `line 2: unknown keyword "namesever"`},
+		{"bad address", []byte("nameserver 1.1.1\n"), model.SelfTestFailed, `line 1: nameserver "1.1.1" is not an IP address`},
+		{"keyword without value", []byte("nameserver 1.1.1.1\nsearch\n"), model.SelfTestFailed, "line 2: search has no value"},
+		{"no nameserver", []byte("search example.com\n"), model.SelfTestFailed, "no nameserver lines"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			check := checkResolvConf(tc.content)
+			assert.Equal(t, tc.status, check.Status)
+			assert.Equal(t, tc.expected, check.Detail)
+		})
+	}
+}
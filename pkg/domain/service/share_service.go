@@ -0,0 +1,289 @@
+// pkg/domain/service/share_service.go
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// Ports and ufw application profiles that carry each file sharing protocol
+var (
+	nfsPorts   = []string{"2049", "111"}
+	sambaPorts = []string{"445", "139"}
+	nfsApps    = []string{"nfs"}
+	sambaApps  = []string{"samba", "cifs"}
+)
+
+// ShareService defines operations for auditing NFS and Samba shares
+type ShareService interface {
+	// AuditShares reports world-accessible shares and insecure share options
+	AuditShares() ([]model.PolicyViolation, error)
+}
+
+// ShareServiceImpl implements ShareService
+type ShareServiceImpl struct {
+	shareRepo    ShareRepository
+	firewallRepo FirewallRepository
+}
+
+// NewShareServiceImpl creates a new ShareServiceImpl
+func NewShareServiceImpl(shareRepo ShareRepository, firewallRepo FirewallRepository) *ShareServiceImpl {
+	return &ShareServiceImpl{
+		shareRepo:    shareRepo,
+		firewallRepo: firewallRepo,
+	}
+}
+
+// ShareRepository defines the repository operations needed by ShareService
+type ShareRepository interface {
+	GetNFSExports() ([]model.NFSExport, error)
+	GetSambaConfig() (*model.SambaConfig, error)
+}
+
+// shareExposure describes how far the firewall lets a protocol through
+type shareExposure struct {
+	open   bool   // reachable from any source
+	detail string // appended to finding messages
+}
+
+func (s *ShareServiceImpl) AuditShares() ([]model.PolicyViolation, error) {
+	exports, err := s.shareRepo.GetNFSExports()
+	if err != nil {
+		return nil, err
+	}
+
+	samba, err := s.shareRepo.GetSambaConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(exports) == 0 && (samba == nil || len(samba.Shares) == 0) {
+		return nil, nil
+	}
+
+	// Share settings only matter as far as the firewall lets clients in
+	_, enabled, _, rules, err := s.firewallRepo.GetFirewallStatus()
+	if err != nil {
+		enabled = false
+	}
+
+	var findings []model.PolicyViolation
+	if len(exports) > 0 {
+		exposure := firewallExposure(enabled, rules, nfsPorts, nfsApps)
+		findings = append(findings, auditNFSExports(exports, exposure)...)
+	}
+	if samba != nil && len(samba.Shares) > 0 {
+		exposure := firewallExposure(enabled, rules, sambaPorts, sambaApps)
+		findings = append(findings, auditSambaShares(samba, exposure)...)
+	}
+
+	return findings, nil
+}
+
+// auditNFSExports checks each export for world access and unsafe options
+func auditNFSExports(exports []model.NFSExport, exposure shareExposure) []model.PolicyViolation {
+	var findings []model.PolicyViolation
+
+	for _, export := range exports {
+		for _, client := range export.Clients {
+			host := client.Host
+			if host == "" {
+				host = "*"
+			}
+			writable := hasOption(client.Options, "rw")
+
+			if host == "*" {
+				severity := model.SeverityMedium
+				access := "read-only"
+				if writable || exposure.open {
+					severity = model.SeverityHigh
+				}
+				if writable {
+					access = "read-write"
+				}
+				findings = append(findings, model.PolicyViolation{
+					Rule:     "share.nfs.world_export",
+					Severity: severity,
+					Message: fmt.Sprintf("NFS export %s is %s to any host%s",
+						export.Path, access, exposure.detail),
+					Remediation: fmt.Sprintf("Restrict the export in %s to specific hosts or subnets, "+
+						"e.g. %s 10.0.0.0/24(ro,root_squash), and limit ports 2049/111 in the firewall",
+						model.NFSExportsPath, export.Path),
+				})
+			}
+
+			if hasOption(client.Options, "no_root_squash") {
+				findings = append(findings, model.PolicyViolation{
+					Rule:     "share.nfs.no_root_squash",
+					Severity: model.SeverityHigh,
+					Message: fmt.Sprintf("NFS export %s grants root access to %s (no_root_squash)",
+						export.Path, host),
+					Remediation: "Remove no_root_squash so remote root users are mapped to nobody",
+				})
+			}
+
+			if hasOption(client.Options, "insecure") {
+				findings = append(findings, model.PolicyViolation{
+					Rule:     "share.nfs.insecure",
+					Severity: model.SeverityMedium,
+					Message: fmt.Sprintf("NFS export %s accepts requests from unprivileged ports for %s (insecure)",
+						export.Path, host),
+					Remediation: "Remove the insecure option so clients must connect from ports below 1024",
+				})
+			}
+		}
+	}
+
+	return findings
+}
+
+// auditSambaShares checks each share for guest access, missing host
+// restrictions and the global protocol floor
+func auditSambaShares(config *model.SambaConfig, exposure shareExposure) []model.PolicyViolation {
+	var findings []model.PolicyViolation
+
+	minProtocol := config.Global["server min protocol"]
+	if minProtocol == "" {
+		minProtocol = config.Global["min protocol"]
+	}
+	if strings.EqualFold(minProtocol, "NT1") || strings.EqualFold(minProtocol, "LANMAN1") ||
+		strings.EqualFold(minProtocol, "LANMAN2") || strings.EqualFold(minProtocol, "CORE") {
+		findings = append(findings, model.PolicyViolation{
+			Rule:        "share.smb.smb1",
+			Severity:    model.SeverityHigh,
+			Message:     fmt.Sprintf("Samba allows the SMB1 protocol (server min protocol = %s)", minProtocol),
+			Remediation: "Set server min protocol = SMB2_10 or later in [global]",
+		})
+	}
+
+	for _, share := range config.Shares {
+		guest := sambaBool(sambaSetting(config, share, "guest ok", "public"), false)
+		writable := !sambaBool(sambaSetting(config, share, "read only"), true) ||
+			sambaBool(sambaSetting(config, share, "writable", "writeable", "write ok"), false)
+
+		if guest {
+			severity := model.SeverityMedium
+			access := "read-only"
+			if writable {
+				severity = model.SeverityHigh
+				access = "read-write"
+			}
+			findings = append(findings, model.PolicyViolation{
+				Rule:        "share.smb.guest_access",
+				Severity:    severity,
+				Message:     fmt.Sprintf("Samba share [%s] allows %s guest access%s", share.Name, access, exposure.detail),
+				Remediation: "Set guest ok = no and limit the share to named accounts with valid users",
+			})
+		}
+
+		if sambaSetting(config, share, "hosts allow", "allow hosts") == "" {
+			severity := model.SeverityLow
+			if exposure.open {
+				severity = model.SeverityMedium
+			}
+			findings = append(findings, model.PolicyViolation{
+				Rule:     "share.smb.no_host_restriction",
+				Severity: severity,
+				Message:  fmt.Sprintf("Samba share [%s] accepts connections from any host%s", share.Name, exposure.detail),
+				Remediation: "Add hosts allow = 127.0.0.1 10.0.0.0/24 to [global] or the share, " +
+					"and limit ports 445/139 in the firewall",
+			})
+		}
+	}
+
+	return findings
+}
+
+// firewallExposure works out from the ufw rules whether any of ports (or
+// the named application profiles) are reachable from any source
+func firewallExposure(enabled bool, rules []string, ports, apps []string) shareExposure {
+	if !enabled {
+		return shareExposure{open: true, detail: " and the firewall is disabled"}
+	}
+
+	for _, rule := range rules {
+		fields := strings.Fields(strings.ToLower(rule))
+		if len(fields) == 0 || (fields[0] != "allow" && fields[0] != "limit") {
+			continue
+		}
+		if !ruleMatches(fields, ports, apps) {
+			continue
+		}
+
+		source := "any"
+		for i, field := range fields {
+			if field == "from" && i+1 < len(fields) {
+				source = fields[i+1]
+			}
+		}
+		if source == "any" || source == "0.0.0.0/0" || source == "::/0" {
+			return shareExposure{
+				open:   true,
+				detail: fmt.Sprintf(" and the firewall allows it from anywhere (%s)", rule),
+			}
+		}
+	}
+
+	return shareExposure{}
+}
+
+// ruleMatches reports whether a ufw rule opens one of ports or apps
+func ruleMatches(fields []string, ports, apps []string) bool {
+	for i, field := range fields {
+		candidates := []string{field}
+		if i > 0 && fields[i-1] == "port" {
+			candidates = strings.Split(field, ",")
+		}
+
+		for _, candidate := range candidates {
+			candidate, _, _ = strings.Cut(candidate, "/")
+			for _, port := range ports {
+				if candidate == port {
+					return true
+				}
+			}
+			for _, app := range apps {
+				if candidate == app {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// sambaSetting returns the first of keys set on the share, falling back
+// to [global]
+func sambaSetting(config *model.SambaConfig, share model.SambaShare, keys ...string) string {
+	for _, settings := range []map[string]string{share.Settings, config.Global} {
+		for _, key := range keys {
+			if value, ok := settings[key]; ok {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// sambaBool interprets a Samba boolean, returning def when unset
+func sambaBool(value string, def bool) bool {
+	switch strings.ToLower(value) {
+	case "yes", "true", "1":
+		return true
+	case "no", "false", "0":
+		return false
+	}
+	return def
+}
+
+// hasOption reports whether an export option is set
+func hasOption(options []string, name string) bool {
+	for _, option := range options {
+		if option == name {
+			return true
+		}
+	}
+	return false
+}
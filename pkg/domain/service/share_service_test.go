@@ -0,0 +1,131 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockShareRepository is a mock implementation of ShareRepository
+type MockShareRepository struct {
+	mock.Mock
+}
+
+func (m *MockShareRepository) GetNFSExports() ([]model.NFSExport, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.NFSExport), args.Error(1)
+}
+
+func (m *MockShareRepository) GetSambaConfig() (*model.SambaConfig, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SambaConfig), args.Error(1)
+}
+
+// findingRules returns the rule of each finding, for compact assertions
+func findingRules(findings []model.PolicyViolation) []string {
+	var rules []string
+	for _, finding := range findings {
+		rules = append(rules, finding.Rule)
+	}
+	return rules
+}
+
+func TestShareServiceImpl_AuditShares(t *testing.T) {
+	exports := []model.NFSExport{
+		{Path: "/srv/public", Clients: []model.NFSClient{{Host: "*", Options: []string{"ro"}}}},
+		{Path: "/srv/home", Clients: []model.NFSClient{{Host: "10.0.0.0/24", Options: []string{"rw", "no_root_squash", "insecure"}}}},
+	}
+
+	t.Run("no shares skips the firewall", func(t *testing.T) {
+		shareRepo := new(MockShareRepository)
+		shareRepo.On("GetNFSExports").Return(nil, nil)
+		shareRepo.On("GetSambaConfig").Return(nil, nil)
+		firewallRepo := &MockFirewallRepository{}
+
+		findings, err := NewShareServiceImpl(shareRepo, firewallRepo).AuditShares()
+
+		assert.NoError(t, err)
+		assert.Empty(t, findings)
+		assert.Equal(t, 0, firewallRepo.StatusCallCount)
+	})
+
+	t.Run("firewall restricts nfs", func(t *testing.T) {
+		shareRepo := new(MockShareRepository)
+		shareRepo.On("GetNFSExports").Return(exports, nil)
+		shareRepo.On("GetSambaConfig").Return(nil, nil)
+		firewallRepo := &MockFirewallRepository{Installed: true, Enabled: true, Configured: true,
+			Rules: []string{"allow 22/tcp", "allow from 10.0.0.0/24 to any port 2049"}}
+
+		findings, err := NewShareServiceImpl(shareRepo, firewallRepo).AuditShares()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"share.nfs.world_export", "share.nfs.no_root_squash", "share.nfs.insecure"},
+			findingRules(findings))
+		assert.Equal(t, model.SeverityMedium, findings[0].Severity)
+		assert.Equal(t, "NFS export /srv/public is read-only to any host", findings[0].Message)
+		assert.NotEmpty(t, findings[1].Remediation)
+	})
+
+	t.Run("firewall open to anywhere", func(t *testing.T) {
+		shareRepo := new(MockShareRepository)
+		shareRepo.On("GetNFSExports").Return(exports[:1], nil)
+		shareRepo.On("GetSambaConfig").Return(nil, nil)
+		firewallRepo := &MockFirewallRepository{Installed: true, Enabled: true, Configured: true,
+			Rules: []string{"allow 2049/tcp"}}
+
+		findings, err := NewShareServiceImpl(shareRepo, firewallRepo).AuditShares()
+
+		assert.NoError(t, err)
+		assert.Len(t, findings, 1)
+		assert.Equal(t, model.SeverityHigh, findings[0].Severity)
+		assert.Contains(t, findings[0].Message, "allows it from anywhere (allow 2049/tcp)")
+	})
+
+	t.Run("samba guest share with firewall disabled", func(t *testing.T) {
+		samba := &model.SambaConfig{
+			Global: map[string]string{"server min protocol": "NT1", "hosts allow": "192.168.1.0/24"},
+			Shares: []model.SambaShare{
+				{Name: "public", Settings: map[string]string{"guest ok": "yes", "read only": "no"}},
+				{Name: "team", Settings: map[string]string{"valid users": "@team"}},
+			},
+		}
+		shareRepo := new(MockShareRepository)
+		shareRepo.On("GetNFSExports").Return(nil, nil)
+		shareRepo.On("GetSambaConfig").Return(samba, nil)
+		firewallRepo := &MockFirewallRepository{Installed: true}
+
+		findings, err := NewShareServiceImpl(shareRepo, firewallRepo).AuditShares()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"share.smb.smb1", "share.smb.guest_access"}, findingRules(findings))
+		assert.Equal(t, model.SeverityHigh, findings[1].Severity)
+		assert.Equal(t, "Samba share [public] allows read-write guest access and the firewall is disabled",
+			findings[1].Message)
+	})
+
+	t.Run("samba share without host restriction", func(t *testing.T) {
+		samba := &model.SambaConfig{
+			Global: map[string]string{},
+			Shares: []model.SambaShare{{Name: "team", Settings: map[string]string{"valid users": "@team"}}},
+		}
+		shareRepo := new(MockShareRepository)
+		shareRepo.On("GetNFSExports").Return(nil, nil)
+		shareRepo.On("GetSambaConfig").Return(samba, nil)
+		firewallRepo := &MockFirewallRepository{Installed: true, Enabled: true, Configured: true,
+			Rules: []string{"allow Samba"}}
+
+		findings, err := NewShareServiceImpl(shareRepo, firewallRepo).AuditShares()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"share.smb.no_host_restriction"}, findingRules(findings))
+		assert.Equal(t, model.SeverityMedium, findings[0].Severity)
+	})
+}
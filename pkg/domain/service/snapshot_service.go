@@ -0,0 +1,97 @@
+// pkg/domain/service/snapshot_service.go
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// SnapshotService defines operations for root filesystem snapshots
+type SnapshotService interface {
+	// GetSnapshotSupport reports whether the root filesystem can be snapshotted
+	GetSnapshotSupport() (*model.SnapshotSupport, error)
+
+	// CreateSnapshot takes a new hardn snapshot of the root filesystem
+	CreateSnapshot() (*model.Snapshot, error)
+
+	// ListSnapshots returns hardn snapshots, oldest first
+	ListSnapshots() ([]model.Snapshot, error)
+
+	// Rollback reverts to the named snapshot, or the newest one if name is empty
+	Rollback(name string) (*model.Snapshot, error)
+}
+
+// SnapshotServiceImpl implements SnapshotService
+type SnapshotServiceImpl struct {
+	repository SnapshotRepository
+}
+
+// NewSnapshotServiceImpl creates a new SnapshotServiceImpl
+func NewSnapshotServiceImpl(repository SnapshotRepository) *SnapshotServiceImpl {
+	return &SnapshotServiceImpl{
+		repository: repository,
+	}
+}
+
+// SnapshotRepository defines the repository operations needed by SnapshotService
+type SnapshotRepository interface {
+	DetectSnapshotSupport() (*model.SnapshotSupport, error)
+	CreateSnapshot(name string) (*model.Snapshot, error)
+	ListSnapshots() ([]model.Snapshot, error)
+	RollbackSnapshot(snapshot model.Snapshot) error
+}
+
+func (s *SnapshotServiceImpl) GetSnapshotSupport() (*model.SnapshotSupport, error) {
+	return s.repository.DetectSnapshotSupport()
+}
+
+func (s *SnapshotServiceImpl) CreateSnapshot() (*model.Snapshot, error) {
+	support, err := s.repository.DetectSnapshotSupport()
+	if err != nil {
+		return nil, err
+	}
+	if !support.Supported {
+		return nil, fmt.Errorf("root filesystem snapshots are not supported: %s", support.Reason)
+	}
+
+	name := model.SnapshotNamePrefix + time.Now().Format(model.SnapshotTimeLayout)
+	return s.repository.CreateSnapshot(name)
+}
+
+func (s *SnapshotServiceImpl) ListSnapshots() ([]model.Snapshot, error) {
+	return s.repository.ListSnapshots()
+}
+
+func (s *SnapshotServiceImpl) Rollback(name string) (*model.Snapshot, error) {
+	snapshots, err := s.repository.ListSnapshots()
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no hardn snapshots found")
+	}
+
+	// Default to the most recent snapshot
+	target := snapshots[len(snapshots)-1]
+	if name != "" && name != "latest" {
+		found := false
+		for _, snapshot := range snapshots {
+			if snapshot.Name == name {
+				target = snapshot
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("snapshot %s not found", name)
+		}
+	}
+
+	if err := s.repository.RollbackSnapshot(target); err != nil {
+		return nil, err
+	}
+
+	return &target, nil
+}
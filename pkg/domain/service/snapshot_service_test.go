@@ -0,0 +1,128 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSnapshotRepository is a mock implementation of SnapshotRepository
+type MockSnapshotRepository struct {
+	mock.Mock
+}
+
+func (m *MockSnapshotRepository) DetectSnapshotSupport() (*model.SnapshotSupport, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SnapshotSupport), args.Error(1)
+}
+
+func (m *MockSnapshotRepository) CreateSnapshot(name string) (*model.Snapshot, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Snapshot), args.Error(1)
+}
+
+func (m *MockSnapshotRepository) ListSnapshots() ([]model.Snapshot, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Snapshot), args.Error(1)
+}
+
+func (m *MockSnapshotRepository) RollbackSnapshot(snapshot model.Snapshot) error {
+	args := m.Called(snapshot)
+	return args.Error(0)
+}
+
+func TestSnapshotServiceImpl_CreateSnapshot(t *testing.T) {
+	t.Run("unsupported filesystem", func(t *testing.T) {
+		mockRepo := new(MockSnapshotRepository)
+		mockRepo.On("DetectSnapshotSupport").Return(&model.SnapshotSupport{
+			Reason: "root filesystem (ext4 on /dev/sda1) does not support snapshots",
+		}, nil)
+
+		service := NewSnapshotServiceImpl(mockRepo)
+		_, err := service.CreateSnapshot()
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "ext4")
+		mockRepo.AssertNotCalled(t, "CreateSnapshot", mock.Anything)
+	})
+
+	t.Run("names snapshot with hardn prefix", func(t *testing.T) {
+		mockRepo := new(MockSnapshotRepository)
+		mockRepo.On("DetectSnapshotSupport").Return(&model.SnapshotSupport{
+			Supported: true,
+			Backend:   model.SnapshotBackendZFS,
+			Target:    "rpool/ROOT/pve-1",
+		}, nil)
+		mockRepo.On("CreateSnapshot", mock.MatchedBy(func(name string) bool {
+			stamp, ok := strings.CutPrefix(name, model.SnapshotNamePrefix)
+			if !ok {
+				return false
+			}
+			_, err := time.Parse(model.SnapshotTimeLayout, stamp)
+			return err == nil
+		})).Return(&model.Snapshot{Name: "hardn-20240601-020000"}, nil)
+
+		service := NewSnapshotServiceImpl(mockRepo)
+		snapshot, err := service.CreateSnapshot()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "hardn-20240601-020000", snapshot.Name)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestSnapshotServiceImpl_Rollback(t *testing.T) {
+	older := model.Snapshot{Name: "hardn-20240501-020000", Backend: model.SnapshotBackendZFS}
+	newer := model.Snapshot{Name: "hardn-20240601-020000", Backend: model.SnapshotBackendZFS}
+
+	tests := []struct {
+		name        string
+		request     string
+		snapshots   []model.Snapshot
+		expected    string
+		expectError string
+	}{
+		{name: "latest by default", request: "", snapshots: []model.Snapshot{older, newer}, expected: newer.Name},
+		{name: "latest keyword", request: "latest", snapshots: []model.Snapshot{older, newer}, expected: newer.Name},
+		{name: "named snapshot", request: older.Name, snapshots: []model.Snapshot{older, newer}, expected: older.Name},
+		{name: "unknown snapshot", request: "hardn-19990101-000000", snapshots: []model.Snapshot{older}, expectError: "not found"},
+		{name: "no snapshots", request: "", snapshots: []model.Snapshot{}, expectError: "no hardn snapshots"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockSnapshotRepository)
+			mockRepo.On("ListSnapshots").Return(tc.snapshots, nil)
+			mockRepo.On("RollbackSnapshot", mock.Anything).Return(nil)
+
+			service := NewSnapshotServiceImpl(mockRepo)
+			snapshot, err := service.Rollback(tc.request)
+
+			if tc.expectError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectError)
+				mockRepo.AssertNotCalled(t, "RollbackSnapshot", mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, snapshot.Name)
+			mockRepo.AssertCalled(t, "RollbackSnapshot", mock.MatchedBy(func(s model.Snapshot) bool {
+				return s.Name == tc.expected
+			}))
+		})
+	}
+}
@@ -0,0 +1,141 @@
+// pkg/domain/service/ssh_key_import_service.go
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// providerAccountPattern matches GitHub and GitLab account names and keeps
+// the name safe to place in a URL path
+var providerAccountPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,254}$`)
+
+// SSHKeyImportService defines operations for importing published SSH keys
+type SSHKeyImportService interface {
+	// ImportKeys authorizes the keys an account publishes at a provider for
+	// a local user; with dryRun nothing is written
+	ImportKeys(username, provider, account string, dryRun bool) (*model.SSHKeyImport, error)
+}
+
+// SSHKeyImportServiceImpl implements SSHKeyImportService
+type SSHKeyImportServiceImpl struct {
+	keySource SSHKeySourceRepository
+	userRepo  UserRepository
+}
+
+// NewSSHKeyImportServiceImpl creates a new SSHKeyImportServiceImpl
+func NewSSHKeyImportServiceImpl(keySource SSHKeySourceRepository, userRepo UserRepository) *SSHKeyImportServiceImpl {
+	return &SSHKeyImportServiceImpl{
+		keySource: keySource,
+		userRepo:  userRepo,
+	}
+}
+
+// SSHKeySourceRepository defines the repository operations needed by SSHKeyImportService
+type SSHKeySourceRepository interface {
+	FetchPublicKeys(provider string, account string) ([]string, error)
+}
+
+// ImportKeys fetches, validates and de-duplicates the published keys, then
+// adds the new ones to the user's authorized_keys. DSA and short RSA keys
+// are refused. Each added key is commented with provider:account so its
+// origin stays visible.
+func (s *SSHKeyImportServiceImpl) ImportKeys(username, provider, account string, dryRun bool) (*model.SSHKeyImport, error) {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	account = strings.TrimPrefix(strings.TrimSpace(account), "@")
+
+	if username == "" {
+		return nil, fmt.Errorf("username cannot be empty")
+	}
+	if err := ValidateSSHKeyProvider(provider); err != nil {
+		return nil, err
+	}
+	if !providerAccountPattern.MatchString(account) || strings.Contains(account, "..") {
+		return nil, fmt.Errorf("invalid %s account name: %q", provider, account)
+	}
+
+	published, err := s.keySource.FetchPublicKeys(provider, account)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.userRepo.GetAuthorizedKeys(username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorized keys for %s: %w", username, err)
+	}
+	seen := make(map[string]bool)
+	for _, line := range existing {
+		if key, err := model.ParseAuthorizedKey(line); err == nil {
+			seen[key.Fingerprint] = true
+		}
+	}
+
+	result := &model.SSHKeyImport{Username: username, Provider: provider, Account: account}
+	imported := make(map[string]bool)
+	origin := provider + ":" + account
+	for _, line := range published {
+		key, err := model.ParseAuthorizedKey(line)
+		if err != nil || len(key.Options) > 0 {
+			result.Invalid++
+			continue
+		}
+		if imported[key.Fingerprint] {
+			continue
+		}
+		imported[key.Fingerprint] = true
+		key.Username = username
+
+		if seen[key.Fingerprint] {
+			result.Present = append(result.Present, *key)
+			continue
+		}
+
+		if issue, detail := weakKeyIssue(*key); issue != "" {
+			result.Rejected = append(result.Rejected, model.SSHKeyFinding{
+				Username:    username,
+				Issue:       issue,
+				Severity:    model.SeverityHigh,
+				KeyType:     key.KeyType,
+				Bits:        key.Bits,
+				Fingerprint: key.Fingerprint,
+				Comment:     key.Comment,
+				Detail:      detail,
+			})
+			continue
+		}
+
+		key.Comment = origin
+		fields := strings.Fields(line)
+		result.Added = append(result.Added, *key)
+		result.Lines = append(result.Lines, fmt.Sprintf("%s %s %s", fields[0], fields[1], origin))
+	}
+
+	if len(published) == 0 {
+		return nil, fmt.Errorf("%s user %s has no published SSH keys", provider, account)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	for i, line := range result.Lines {
+		if err := s.userRepo.AddSSHKey(username, line); err != nil {
+			return result, fmt.Errorf("failed to add key %s: %w", result.Added[i].Fingerprint, err)
+		}
+	}
+
+	return result, nil
+}
+
+// ValidateSSHKeyProvider checks that provider is a supported key provider
+func ValidateSSHKeyProvider(provider string) error {
+	for _, known := range model.SSHKeyProviders {
+		if provider == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported key provider %q (valid: %s)", provider, strings.Join(model.SSHKeyProviders, ", "))
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSSHKeySourceRepository is a mock implementation of SSHKeySourceRepository
+type MockSSHKeySourceRepository struct {
+	mock.Mock
+}
+
+func (m *MockSSHKeySourceRepository) FetchPublicKeys(provider string, account string) ([]string, error) {
+	args := m.Called(provider, account)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+const testLaptopKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8g"
+
+func TestSSHKeyImportServiceImpl_ImportKeys(t *testing.T) {
+	keySource := new(MockSSHKeySourceRepository)
+	userRepo := new(MockUserRepository)
+	service := NewSSHKeyImportServiceImpl(keySource, userRepo)
+
+	keySource.On("FetchPublicKeys", "github", "george").Return([]string{
+		testEd25519Key,
+		testLaptopKey,
+		testLaptopKey, // published twice
+		testRSA1024Key,
+		"not a key",
+	}, nil)
+	userRepo.On("GetAuthorizedKeys", "george").Return([]string{testEd25519Key}, nil)
+	userRepo.On("AddSSHKey", "george", testLaptopKey+" github:george").Return(nil)
+
+	result, err := service.ImportKeys("george", "GitHub", "@george", false)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Added, 1)
+	assert.Equal(t, "github:george", result.Added[0].Comment)
+	assert.Equal(t, []string{testLaptopKey + " github:george"}, result.Lines)
+	assert.Len(t, result.Present, 1)
+	assert.Len(t, result.Rejected, 1)
+	assert.Equal(t, model.SSHKeyIssueWeakRSA, result.Rejected[0].Issue)
+	assert.Equal(t, 1, result.Invalid)
+	userRepo.AssertNumberOfCalls(t, "AddSSHKey", 1)
+}
+
+func TestSSHKeyImportServiceImpl_ImportKeys_DryRun(t *testing.T) {
+	keySource := new(MockSSHKeySourceRepository)
+	userRepo := new(MockUserRepository)
+	service := NewSSHKeyImportServiceImpl(keySource, userRepo)
+
+	keySource.On("FetchPublicKeys", "gitlab", "george").Return([]string{testLaptopKey}, nil)
+	userRepo.On("GetAuthorizedKeys", "george").Return([]string{}, nil)
+
+	result, err := service.ImportKeys("george", "gitlab", "george", true)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Added, 1)
+	userRepo.AssertNotCalled(t, "AddSSHKey", mock.Anything, mock.Anything)
+}
+
+func TestSSHKeyImportServiceImpl_ImportKeys_Errors(t *testing.T) {
+	keySource := new(MockSSHKeySourceRepository)
+	userRepo := new(MockUserRepository)
+	service := NewSSHKeyImportServiceImpl(keySource, userRepo)
+
+	keySource.On("FetchPublicKeys", "github", "nokeys").Return([]string{}, nil)
+	keySource.On("FetchPublicKeys", "github", "missing").Return(nil, errors.New("github user missing not found"))
+	userRepo.On("GetAuthorizedKeys", "george").Return([]string{}, nil)
+
+	_, err := service.ImportKeys("george", "bitbucket", "george", true)
+	assert.ErrorContains(t, err, "unsupported key provider")
+
+	_, err = service.ImportKeys("george", "github", "../orgs/x", true)
+	assert.ErrorContains(t, err, "invalid github account name")
+
+	_, err = service.ImportKeys("", "github", "george", true)
+	assert.Error(t, err)
+
+	_, err = service.ImportKeys("george", "github", "missing", true)
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = service.ImportKeys("george", "github", "nokeys", true)
+	assert.ErrorContains(t, err, "no published SSH keys")
+}
@@ -0,0 +1,227 @@
+// pkg/domain/service/ssh_key_inventory_service.go
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// SSHKeyInventoryService defines operations for the inventory of authorized
+// SSH keys and their expiry dates
+type SSHKeyInventoryService interface {
+	// Inventory lists the keys authorized for root and every local user,
+	// with their strength and expiry as of now
+	Inventory(now time.Time) ([]model.SSHKeyInventoryEntry, error)
+
+	// SetExpiry tags a user's key with the time it expires
+	SetExpiry(username, fingerprint string, expires time.Time) error
+
+	// ClearExpiry removes a key's expiry, reporting false when it had none
+	ClearExpiry(username, fingerprint string) (bool, error)
+
+	// RemoveExpired removes the keys expired as of now from authorized_keys
+	// and returns them; with dryRun nothing is removed
+	RemoveExpired(now time.Time, dryRun bool) ([]model.SSHKeyInventoryEntry, error)
+}
+
+// SSHKeyInventoryServiceImpl implements SSHKeyInventoryService
+type SSHKeyInventoryServiceImpl struct {
+	userRepo   UserRepository
+	expiryRepo SSHKeyExpiryRepository
+}
+
+// NewSSHKeyInventoryServiceImpl creates a new SSHKeyInventoryServiceImpl
+func NewSSHKeyInventoryServiceImpl(userRepo UserRepository, expiryRepo SSHKeyExpiryRepository) *SSHKeyInventoryServiceImpl {
+	return &SSHKeyInventoryServiceImpl{
+		userRepo:   userRepo,
+		expiryRepo: expiryRepo,
+	}
+}
+
+// SSHKeyExpiryRepository defines the repository operations needed by SSHKeyInventoryService
+type SSHKeyExpiryRepository interface {
+	LoadKeyExpiries() ([]model.SSHKeyExpiry, error)
+	SaveKeyExpiries(expiries []model.SSHKeyExpiry) error
+}
+
+// Inventory reads every authorized_keys the key audit reads. Lines that are
+// not keys are skipped. DSA keys and RSA keys under RecommendedRSAKeyBits
+// are flagged as weak.
+func (s *SSHKeyInventoryServiceImpl) Inventory(now time.Time) ([]model.SSHKeyInventoryEntry, error) {
+	usernames, err := keyOwnerUsernames(s.userRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	expiries, err := s.expiryRepo.LoadKeyExpiries()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := make(map[string]time.Time)
+	for _, expiry := range expiries {
+		expiresAt[expiry.Username+" "+expiry.Fingerprint] = expiry.Expires
+	}
+
+	var entries []model.SSHKeyInventoryEntry
+	for _, username := range usernames {
+		lines, err := s.userRepo.GetAuthorizedKeys(username)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read authorized keys for %s: %w", username, err)
+		}
+
+		for _, line := range lines {
+			key, err := model.ParseAuthorizedKey(line)
+			if err != nil {
+				continue
+			}
+			key.Username = username
+
+			entry := model.SSHKeyInventoryEntry{
+				Username:    username,
+				KeyType:     key.KeyType,
+				Bits:        key.Bits,
+				Fingerprint: key.Fingerprint,
+				Comment:     key.Comment,
+				Options:     key.Options,
+			}
+
+			if _, detail := weakKeyIssue(*key); detail != "" {
+				entry.Weak = detail
+			} else if key.KeyType == "ssh-rsa" && key.Bits > 0 && key.Bits < model.RecommendedRSAKeyBits {
+				entry.Weak = fmt.Sprintf("is a %d-bit RSA key (%d recommended)", key.Bits, model.RecommendedRSAKeyBits)
+			}
+
+			if expires, ok := expiresAt[username+" "+key.Fingerprint]; ok {
+				entry.Expires = &expires
+				entry.Expired = !now.Before(expires)
+			}
+
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// SetExpiry refuses keys the user does not have, so a mistyped fingerprint
+// is not silently tagged
+func (s *SSHKeyInventoryServiceImpl) SetExpiry(username, fingerprint string, expires time.Time) error {
+	if username == "" {
+		return fmt.Errorf("username cannot be empty")
+	}
+	if fingerprint == "" {
+		return fmt.Errorf("key fingerprint cannot be empty")
+	}
+
+	lines, err := s.userRepo.GetAuthorizedKeys(username)
+	if err != nil {
+		return fmt.Errorf("failed to read authorized keys for %s: %w", username, err)
+	}
+	found := false
+	for _, line := range lines {
+		if key, err := model.ParseAuthorizedKey(line); err == nil && key.Fingerprint == fingerprint {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("%s has no authorized key %s", username, fingerprint)
+	}
+
+	expiries, err := s.expiryRepo.LoadKeyExpiries()
+	if err != nil {
+		return err
+	}
+
+	tagged := false
+	for i := range expiries {
+		if expiries[i].Username == username && expiries[i].Fingerprint == fingerprint {
+			expiries[i].Expires = expires
+			tagged = true
+		}
+	}
+	if !tagged {
+		expiries = append(expiries, model.SSHKeyExpiry{
+			Username:    username,
+			Fingerprint: fingerprint,
+			Expires:     expires,
+		})
+	}
+
+	return s.expiryRepo.SaveKeyExpiries(expiries)
+}
+
+func (s *SSHKeyInventoryServiceImpl) ClearExpiry(username, fingerprint string) (bool, error) {
+	expiries, err := s.expiryRepo.LoadKeyExpiries()
+	if err != nil {
+		return false, err
+	}
+
+	var kept []model.SSHKeyExpiry
+	for _, expiry := range expiries {
+		if expiry.Username != username || expiry.Fingerprint != fingerprint {
+			kept = append(kept, expiry)
+		}
+	}
+	if len(kept) == len(expiries) {
+		return false, nil
+	}
+	return true, s.expiryRepo.SaveKeyExpiries(kept)
+}
+
+// RemoveExpired stops at the first key it fails to remove. The expiry
+// dates of removed keys, and of keys no longer authorized, are dropped.
+func (s *SSHKeyInventoryServiceImpl) RemoveExpired(now time.Time, dryRun bool) ([]model.SSHKeyInventoryEntry, error) {
+	entries, err := s.Inventory(now)
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []model.SSHKeyInventoryEntry
+	present := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Expired {
+			expired = append(expired, entry)
+		} else {
+			present[entry.Username+" "+entry.Fingerprint] = true
+		}
+	}
+	if dryRun {
+		return expired, nil
+	}
+
+	var removed []model.SSHKeyInventoryEntry
+	var removeErr error
+	for _, entry := range expired {
+		if err := s.userRepo.RemoveSSHKey(entry.Username, entry.Fingerprint); err != nil {
+			removeErr = fmt.Errorf("failed to remove key %s from %s: %w", entry.Fingerprint, entry.Username, err)
+			break
+		}
+		removed = append(removed, entry)
+	}
+
+	// Keep the dates of keys that are still authorized, including expired
+	// keys left behind by a failure
+	for _, entry := range expired[len(removed):] {
+		present[entry.Username+" "+entry.Fingerprint] = true
+	}
+	expiries, err := s.expiryRepo.LoadKeyExpiries()
+	if err != nil {
+		return removed, err
+	}
+	var kept []model.SSHKeyExpiry
+	for _, expiry := range expiries {
+		if present[expiry.Username+" "+expiry.Fingerprint] {
+			kept = append(kept, expiry)
+		}
+	}
+	if len(kept) != len(expiries) {
+		if err := s.expiryRepo.SaveKeyExpiries(kept); err != nil && removeErr == nil {
+			removeErr = err
+		}
+	}
+
+	return removed, removeErr
+}
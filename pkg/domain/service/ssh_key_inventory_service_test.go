@@ -0,0 +1,176 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSSHKeyExpiryRepository is a mock implementation of SSHKeyExpiryRepository
+type MockSSHKeyExpiryRepository struct {
+	mock.Mock
+}
+
+func (m *MockSSHKeyExpiryRepository) LoadKeyExpiries() ([]model.SSHKeyExpiry, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SSHKeyExpiry), args.Error(1)
+}
+
+func (m *MockSSHKeyExpiryRepository) SaveKeyExpiries(expiries []model.SSHKeyExpiry) error {
+	args := m.Called(expiries)
+	return args.Error(0)
+}
+
+const testRSA2048Key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQDSJKUceHJLVON/1KnPk+mGCxnysoEhCServsaDWG90gyPle9t+9ZR+psMkvoUgvwrX/pB+Z4QHwDDqDxxeYG9a8pU0THyape4x7fxMkdA7PDi7Wft5MZnatAkBRG7PKUNMKNw9EftDAAHiVLDhnpeqiW2cIkWtAAlfuvQAibSzLZ8jFmwSdNRXdMWeczSS2/Rh4HFHl5Uyj50jGG/qUEGOTXlimr8r6jtsyXrmjO+Gpk/zUpLOABrG5MJ3u+TDZ5rKit5IfR7JScJS399usvRuAauIuiko+BfywEcjoz2NzzM2PEwLP4EqM/wzqj7NBQsYxtWbwsxd5X+h+Ma0UX3n old@desktop"
+
+// keyFingerprint returns the SHA256 fingerprint of a test key
+func keyFingerprint(t *testing.T, key string) string {
+	fingerprint, err := model.SSHKeyFingerprint(key)
+	require.NoError(t, err)
+	return fingerprint
+}
+
+// inventoryRepos returns repositories holding alice's ed25519 and 2048-bit
+// RSA keys and deploy's DSA key, with alice's ed25519 key expired
+func inventoryRepos(t *testing.T, now time.Time) (*MockUserRepository, *MockSSHKeyExpiryRepository) {
+	userRepo := new(MockUserRepository)
+	userRepo.On("ListNonSystemUsers", model.UserListOptions{LocalOnly: true}).Return(&model.UserPage{
+		Users: []model.User{{Username: "alice"}, {Username: "deploy"}},
+	}, nil)
+	userRepo.On("GetAuthorizedKeys", "root").Return([]string{}, nil)
+	userRepo.On("GetAuthorizedKeys", "alice").Return([]string{testEd25519Key, testRSA2048Key, "not a key"}, nil)
+	userRepo.On("GetAuthorizedKeys", "deploy").Return([]string{testDSAKey}, nil)
+
+	expiryRepo := new(MockSSHKeyExpiryRepository)
+	expiryRepo.On("LoadKeyExpiries").Return([]model.SSHKeyExpiry{
+		{Username: "alice", Fingerprint: keyFingerprint(t, testEd25519Key), Expires: now.Add(-time.Hour)},
+		{Username: "alice", Fingerprint: keyFingerprint(t, testRSA2048Key), Expires: now.AddDate(0, 0, 30)},
+		{Username: "bob", Fingerprint: keyFingerprint(t, testEd25519Key), Expires: now.Add(-time.Hour)},
+	}, nil)
+	return userRepo, expiryRepo
+}
+
+func TestSSHKeyInventoryServiceImpl_Inventory(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	userRepo, expiryRepo := inventoryRepos(t, now)
+	service := NewSSHKeyInventoryServiceImpl(userRepo, expiryRepo)
+
+	entries, err := service.Inventory(now)
+
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	ed25519 := entries[0]
+	assert.Equal(t, "alice", ed25519.Username)
+	assert.Equal(t, "deploy@ci", ed25519.Comment)
+	assert.Empty(t, ed25519.Weak)
+	assert.True(t, ed25519.Expired)
+	assert.Equal(t, model.SSHKeyIssueExpired, ed25519.Finding().Issue)
+	assert.Equal(t, "expired on 2026-10-17", ed25519.Finding().Detail)
+
+	rsa := entries[1]
+	assert.Equal(t, 2048, rsa.Bits)
+	assert.Equal(t, "is a 2048-bit RSA key (3072 recommended)", rsa.Weak)
+	require.NotNil(t, rsa.Expires)
+	assert.False(t, rsa.Expired)
+
+	dsa := entries[2]
+	assert.Equal(t, "deploy", dsa.Username)
+	assert.Contains(t, dsa.Weak, "DSA key")
+	assert.Nil(t, dsa.Expires)
+}
+
+func TestSSHKeyInventoryServiceImpl_SetExpiry(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	userRepo, expiryRepo := inventoryRepos(t, now)
+	service := NewSSHKeyInventoryServiceImpl(userRepo, expiryRepo)
+
+	expires := now.AddDate(0, 0, 90)
+	expiryRepo.On("SaveKeyExpiries", mock.MatchedBy(func(expiries []model.SSHKeyExpiry) bool {
+		return len(expiries) == 4 && expiries[3].Username == "deploy" && expiries[3].Expires.Equal(expires)
+	})).Return(nil)
+
+	err := service.SetExpiry("deploy", keyFingerprint(t, testDSAKey), expires)
+	assert.NoError(t, err)
+
+	// A key the user does not have is refused
+	err = service.SetExpiry("deploy", keyFingerprint(t, testEd25519Key), expires)
+	assert.ErrorContains(t, err, "deploy has no authorized key")
+	expiryRepo.AssertNumberOfCalls(t, "SaveKeyExpiries", 1)
+}
+
+func TestSSHKeyInventoryServiceImpl_ClearExpiry(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	_, expiryRepo := inventoryRepos(t, now)
+	service := NewSSHKeyInventoryServiceImpl(new(MockUserRepository), expiryRepo)
+
+	expiryRepo.On("SaveKeyExpiries", mock.MatchedBy(func(expiries []model.SSHKeyExpiry) bool {
+		return len(expiries) == 2 && expiries[0].Fingerprint == keyFingerprint(t, testRSA2048Key)
+	})).Return(nil)
+
+	cleared, err := service.ClearExpiry("alice", keyFingerprint(t, testEd25519Key))
+	assert.NoError(t, err)
+	assert.True(t, cleared)
+
+	cleared, err = service.ClearExpiry("deploy", keyFingerprint(t, testDSAKey))
+	assert.NoError(t, err)
+	assert.False(t, cleared)
+}
+
+func TestSSHKeyInventoryServiceImpl_RemoveExpired(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	t.Run("dry run", func(t *testing.T) {
+		userRepo, expiryRepo := inventoryRepos(t, now)
+		service := NewSSHKeyInventoryServiceImpl(userRepo, expiryRepo)
+
+		expired, err := service.RemoveExpired(now, true)
+
+		assert.NoError(t, err)
+		require.Len(t, expired, 1)
+		assert.Equal(t, "alice", expired[0].Username)
+		userRepo.AssertNotCalled(t, "RemoveSSHKey", mock.Anything, mock.Anything)
+		expiryRepo.AssertNotCalled(t, "SaveKeyExpiries", mock.Anything)
+	})
+
+	t.Run("removes expired keys and drops their dates", func(t *testing.T) {
+		userRepo, expiryRepo := inventoryRepos(t, now)
+		service := NewSSHKeyInventoryServiceImpl(userRepo, expiryRepo)
+
+		userRepo.On("RemoveSSHKey", "alice", keyFingerprint(t, testEd25519Key)).Return(nil)
+		// Only the date of the key still authorized is kept; bob's key is gone
+		expiryRepo.On("SaveKeyExpiries", []model.SSHKeyExpiry{
+			{Username: "alice", Fingerprint: keyFingerprint(t, testRSA2048Key), Expires: now.AddDate(0, 0, 30)},
+		}).Return(nil)
+
+		removed, err := service.RemoveExpired(now, false)
+
+		assert.NoError(t, err)
+		assert.Len(t, removed, 1)
+		expiryRepo.AssertExpectations(t)
+	})
+
+	t.Run("keeps the date of a key it failed to remove", func(t *testing.T) {
+		userRepo, expiryRepo := inventoryRepos(t, now)
+		service := NewSSHKeyInventoryServiceImpl(userRepo, expiryRepo)
+
+		userRepo.On("RemoveSSHKey", "alice", keyFingerprint(t, testEd25519Key)).Return(errors.New("read-only file system"))
+		expiryRepo.On("SaveKeyExpiries", mock.MatchedBy(func(expiries []model.SSHKeyExpiry) bool {
+			return len(expiries) == 2
+		})).Return(nil)
+
+		removed, err := service.RemoveExpired(now, false)
+
+		assert.ErrorContains(t, err, "read-only file system")
+		assert.Empty(t, removed)
+		expiryRepo.AssertExpectations(t)
+	})
+}
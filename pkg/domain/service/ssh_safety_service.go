@@ -0,0 +1,74 @@
+// pkg/domain/service/ssh_safety_service.go
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// SSHSafetyService defines operations for the timers that roll back
+// unconfirmed changes to sshd and the firewall
+type SSHSafetyService interface {
+	// Arm starts the timer rolling the run back with binary after
+	// revertAfter, and returns the delay used
+	Arm(runID string, binary string, revertAfter time.Duration) (time.Duration, error)
+
+	// KeepAlive restarts the delay while the run is still going
+	KeepAlive(runID string) error
+
+	// Disarm stops the timer, reporting false when it was not armed or
+	// has already fired
+	Disarm(runID string) (bool, error)
+}
+
+// SSHSafetyServiceImpl implements SSHSafetyService
+type SSHSafetyServiceImpl struct {
+	repository RevertTimerRepository
+}
+
+// NewSSHSafetyServiceImpl creates a new SSHSafetyServiceImpl
+func NewSSHSafetyServiceImpl(repository RevertTimerRepository) *SSHSafetyServiceImpl {
+	return &SSHSafetyServiceImpl{
+		repository: repository,
+	}
+}
+
+// RevertTimerRepository defines the repository operations needed by SSHSafetyService
+type RevertTimerRepository interface {
+	ArmRevert(runID string, rollback []string, after time.Duration) error
+	ExtendRevert(runID string) error
+	DisarmRevert(runID string) (bool, error)
+}
+
+func (s *SSHSafetyServiceImpl) Arm(runID string, binary string, revertAfter time.Duration) (time.Duration, error) {
+	if runID == "" {
+		return 0, fmt.Errorf("no run to roll back")
+	}
+	if binary == "" {
+		return 0, fmt.Errorf("no hardn binary to roll back run %s with", runID)
+	}
+
+	switch {
+	case revertAfter <= 0:
+		revertAfter = model.DefaultSafetyRevertAfter
+	case revertAfter < model.MinSafetyRevertAfter:
+		revertAfter = model.MinSafetyRevertAfter
+	}
+
+	// The timer runs unattended, so the rollback must not ask
+	rollback := []string{binary, "rollback", runID, "--yes"}
+	if err := s.repository.ArmRevert(runID, rollback, revertAfter); err != nil {
+		return 0, err
+	}
+	return revertAfter, nil
+}
+
+func (s *SSHSafetyServiceImpl) KeepAlive(runID string) error {
+	return s.repository.ExtendRevert(runID)
+}
+
+func (s *SSHSafetyServiceImpl) Disarm(runID string) (bool, error) {
+	return s.repository.DisarmRevert(runID)
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRevertTimerRepository is a mock implementation of RevertTimerRepository
+type MockRevertTimerRepository struct {
+	mock.Mock
+}
+
+func (m *MockRevertTimerRepository) ArmRevert(runID string, rollback []string, after time.Duration) error {
+	return m.Called(runID, rollback, after).Error(0)
+}
+
+func (m *MockRevertTimerRepository) ExtendRevert(runID string) error {
+	return m.Called(runID).Error(0)
+}
+
+func (m *MockRevertTimerRepository) DisarmRevert(runID string) (bool, error) {
+	args := m.Called(runID)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestSSHSafetyServiceImpl_Arm(t *testing.T) {
+	rollback := []string{"/usr/local/bin/hardn", "rollback", "20260301-030000", "--yes"}
+
+	tests := []struct {
+		name        string
+		revertAfter time.Duration
+		expected    time.Duration
+	}{
+		{"configured delay", 2 * time.Minute, 2 * time.Minute},
+		{"unset uses the default", 0, model.DefaultSafetyRevertAfter},
+		{"too short to reconnect", 5 * time.Second, model.MinSafetyRevertAfter},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockRevertTimerRepository)
+			mockRepo.On("ArmRevert", "20260301-030000", rollback, tc.expected).Return(nil)
+
+			service := NewSSHSafetyServiceImpl(mockRepo)
+			delay, err := service.Arm("20260301-030000", "/usr/local/bin/hardn", tc.revertAfter)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, delay)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("needs a run and a binary", func(t *testing.T) {
+		service := NewSSHSafetyServiceImpl(new(MockRevertTimerRepository))
+		_, err := service.Arm("", "/usr/local/bin/hardn", time.Minute)
+		assert.Error(t, err)
+		_, err = service.Arm("20260301-030000", "", time.Minute)
+		assert.Error(t, err)
+	})
+}
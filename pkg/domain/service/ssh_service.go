@@ -1,7 +1,15 @@
 // pkg/domain/service/ssh_service.go
 package service
 
-import "github.com/abbott/hardn/pkg/domain/model"
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
 
 // SSHService defines operations for SSH configuration
 type SSHService interface {
@@ -16,6 +24,27 @@ type SSHService interface {
 
 	// retrieve the current SSH configuration
 	GetCurrentConfig() (*model.SSHConfig, error)
+
+	// check whether SSH login is permitted for the root user
+	IsRootLoginEnabled() (bool, error)
+
+	// set or clear the command sshd uses to fetch keys for directory users
+	ConfigureAuthorizedKeysCommand(command string, runAsUser string) error
+
+	// apply a named hardening profile, keeping the rest of the configuration
+	ApplyProfile(profile string) error
+
+	// set or clear the banner file sshd shows before authentication
+	ConfigureBanner(path string) error
+
+	// compare the configuration hardn would write with the one sshd runs with
+	CompareEffectiveConfig(intended model.SSHConfig) (*model.SSHEffectiveReport, error)
+
+	// list the SSH servers running and the directives the configuration files disagree on
+	FindConfigConflicts() (*model.SSHConflictReport, error)
+
+	// disable the lines of other files that contradict hardn's file
+	ConsolidateConfig() ([]model.SSHDirectiveSource, error)
 }
 
 // SSHServiceImpl implements SSHService
@@ -38,10 +67,30 @@ type SSHRepository interface {
 	GetSSHConfig() (*model.SSHConfig, error)
 	DisableRootSSH() error
 	AddAuthorizedKey(username string, publicKey string) error
+	GetEffectiveSSHConfig() (map[string][]string, error)
+	GetSSHConfigSources() ([]model.SSHDirectiveSource, error)
+	ListSSHDaemons() ([]model.SSHDaemon, error)
+	DisableSSHDirectives(sources []model.SSHDirectiveSource, managedFile string) error
 }
 
 // Implement SSHService methods
 func (s *SSHServiceImpl) ConfigureSSH(config model.SSHConfig) error {
+	if err := ValidateSSHProfile(config.Profile); err != nil {
+		return err
+	}
+
+	for _, addr := range config.ListenAddresses {
+		if err := ValidateListenAddress(addr); err != nil {
+			return err
+		}
+	}
+
+	allowedUsers, err := ResolveAllowedUsers(config.AllowedUsers, config.ManagedUser, config.AllowAllUsers)
+	if err != nil {
+		return err
+	}
+	config.AllowedUsers = allowedUsers
+
 	return s.repository.SaveSSHConfig(config)
 }
 
@@ -56,3 +105,475 @@ func (s *SSHServiceImpl) AddAuthorizedKey(username string, publicKey string) err
 func (s *SSHServiceImpl) GetCurrentConfig() (*model.SSHConfig, error) {
 	return s.repository.GetSSHConfig()
 }
+
+func (s *SSHServiceImpl) IsRootLoginEnabled() (bool, error) {
+	config, err := s.repository.GetSSHConfig()
+	if err != nil {
+		return false, err
+	}
+
+	return config.PermitRootLogin, nil
+}
+
+// ConfigureAuthorizedKeysCommand sets the AuthorizedKeysCommand used to look up
+// keys for directory users while keeping the rest of the current configuration.
+// An empty command removes directory key lookup.
+func (s *SSHServiceImpl) ConfigureAuthorizedKeysCommand(command string, runAsUser string) error {
+	command = strings.TrimSpace(command)
+	runAsUser = strings.TrimSpace(runAsUser)
+
+	if command != "" {
+		executable := strings.Fields(command)[0]
+		if !strings.HasPrefix(executable, "/") {
+			return fmt.Errorf("AuthorizedKeysCommand must be an absolute path: %s", executable)
+		}
+		if runAsUser == "" {
+			runAsUser = model.DefaultAuthorizedKeysCommandUser
+		}
+		if runAsUser == "root" {
+			return fmt.Errorf("refusing to run AuthorizedKeysCommand as root; use an unprivileged user such as %s",
+				model.DefaultAuthorizedKeysCommandUser)
+		}
+	} else {
+		runAsUser = ""
+	}
+
+	config, err := s.repository.GetSSHConfig()
+	if err != nil {
+		return fmt.Errorf("failed to read current SSH configuration: %w", err)
+	}
+
+	config.AuthorizedKeysCommand = command
+	config.AuthorizedKeysCommandUser = runAsUser
+
+	return s.saveCurrentConfig(config)
+}
+
+// saveCurrentConfig writes back a configuration read from the managed file.
+// Its AllowUsers list goes through ResolveAllowedUsers again; a file without
+// AllowUsers is left without one.
+func (s *SSHServiceImpl) saveCurrentConfig(config *model.SSHConfig) error {
+	allowedUsers, err := model.ResolveKeptAllowedUsers(config.AllowedUsers)
+	if err != nil {
+		return err
+	}
+	config.AllowedUsers = allowedUsers
+
+	return s.repository.SaveSSHConfig(*config)
+}
+
+// ApplyProfile writes the directives of a hardening profile while keeping the
+// rest of the current configuration. An empty profile removes them.
+func (s *SSHServiceImpl) ApplyProfile(profile string) error {
+	profile = strings.ToLower(strings.TrimSpace(profile))
+	if err := ValidateSSHProfile(profile); err != nil {
+		return err
+	}
+
+	config, err := s.repository.GetSSHConfig()
+	if err != nil {
+		return fmt.Errorf("failed to read current SSH configuration: %w", err)
+	}
+
+	config.Profile = profile
+
+	return s.saveCurrentConfig(config)
+}
+
+// ConfigureBanner sets the file sshd shows before authentication while
+// keeping the rest of the current configuration. An empty path removes it.
+func (s *SSHServiceImpl) ConfigureBanner(path string) error {
+	path = strings.TrimSpace(path)
+	if path != "" && (!strings.HasPrefix(path, "/") || strings.ContainsAny(path, " \t\n")) {
+		return fmt.Errorf("SSH banner must be an absolute path without spaces: %q", path)
+	}
+
+	config, err := s.repository.GetSSHConfig()
+	if err != nil {
+		return fmt.Errorf("failed to read current SSH configuration: %w", err)
+	}
+
+	config.Banner = path
+
+	return s.saveCurrentConfig(config)
+}
+
+// sshListDirectives take one value per line in sshd -T and accumulate
+// across configuration files instead of the first one winning
+var sshListDirectives = map[string]bool{"listenaddress": true, "allowusers": true}
+
+// CompareEffectiveConfig lists each directive hardn writes with the value
+// sshd actually runs with and the lines that set it, so a drop-in read
+// before hardn's file can be found
+func (s *SSHServiceImpl) CompareEffectiveConfig(intended model.SSHConfig) (*model.SSHEffectiveReport, error) {
+	allowedUsers, err := ResolveAllowedUsers(intended.AllowedUsers, intended.ManagedUser, intended.AllowAllUsers)
+	if err != nil {
+		return nil, err
+	}
+	intended.AllowedUsers = allowedUsers
+
+	directives, err := intendedSSHDirectives(intended)
+	if err != nil {
+		return nil, err
+	}
+
+	effective, err := s.repository.GetEffectiveSSHConfig()
+	if err != nil {
+		return nil, err
+	}
+	if effective == nil {
+		return nil, fmt.Errorf("sshd is not installed")
+	}
+
+	sources, err := s.repository.GetSSHConfigSources()
+	if err != nil {
+		return nil, err
+	}
+
+	managedFile := intended.ConfigFilePath
+	if managedFile == "" {
+		managedFile = model.SSHManagedConfigPath(s.osInfo.Type)
+	}
+	report := &model.SSHEffectiveReport{ManagedFile: managedFile}
+
+	for _, diff := range directives {
+		diff.Effective = strings.Join(effective[diff.Directive], " ")
+
+		managed := false
+		for _, source := range sources {
+			if source.Directive != diff.Directive {
+				continue
+			}
+			diff.Sources = append(diff.Sources, source)
+			managed = managed || source.File == managedFile
+		}
+
+		if sshListDirectives[diff.Directive] {
+			diff.Matches = sameFields(diff.Intended, diff.Effective)
+			if !diff.Matches {
+				diff.OverriddenBy = firstSourceOutside(diff.Sources, managedFile)
+			}
+		} else {
+			diff.Matches = strings.EqualFold(diff.Intended, diff.Effective)
+			if managed && len(diff.Sources) > 0 && diff.Sources[0].File != managedFile {
+				diff.OverriddenBy = &diff.Sources[0]
+			}
+		}
+
+		report.Directives = append(report.Directives, diff)
+	}
+
+	return report, nil
+}
+
+// sshRepeatableDirectives may be given several times, each line adding a
+// value, without the lines contradicting each other
+var sshRepeatableDirectives = map[string]bool{
+	"hostkey":         true,
+	"hostcertificate": true,
+	"acceptenv":       true,
+	"setenv":          true,
+	"subsystem":       true,
+}
+
+// sshAccumulatingDirectives collect their values from every file, so a
+// value in any file takes effect
+var sshAccumulatingDirectives = map[string]bool{
+	"port":          true,
+	"listenaddress": true,
+	"allowusers":    true,
+	"allowgroups":   true,
+	"denyusers":     true,
+	"denygroups":    true,
+}
+
+// FindConfigConflicts groups the configuration lines by directive and keeps
+// those that disagree: for most directives two different values, of which
+// only the first takes effect, and for directives that accumulate, files
+// that add different values
+func (s *SSHServiceImpl) FindConfigConflicts() (*model.SSHConflictReport, error) {
+	sources, err := s.repository.GetSSHConfigSources()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.SSHConflictReport{ManagedFile: model.SSHManagedConfigPath(s.osInfo.Type)}
+	report.Daemons, _ = s.repository.ListSSHDaemons()
+
+	// sshd -T fails on an invalid configuration; the lines still conflict
+	effective, _ := s.repository.GetEffectiveSSHConfig()
+
+	var directives []string
+	byDirective := make(map[string][]model.SSHDirectiveSource)
+	for _, source := range sources {
+		if sshRepeatableDirectives[source.Directive] {
+			continue
+		}
+		if _, ok := byDirective[source.Directive]; !ok {
+			directives = append(directives, source.Directive)
+		}
+		byDirective[source.Directive] = append(byDirective[source.Directive], source)
+	}
+
+	for _, directive := range directives {
+		lines := byDirective[directive]
+		if !sourcesConflict(directive, lines) {
+			continue
+		}
+
+		conflict := model.SSHDirectiveConflict{
+			Directive: directive,
+			Effective: strings.Join(effective[directive], " "),
+			Sources:   lines,
+		}
+		for _, source := range lines {
+			conflict.Managed = conflict.Managed || source.File == report.ManagedFile
+		}
+		report.Conflicts = append(report.Conflicts, conflict)
+	}
+
+	return report, nil
+}
+
+// ConsolidateConfig disables the lines outside hardn's file that set a
+// directive of hardn's file to another value, leaving hardn's file the
+// only one in effect for its directives
+func (s *SSHServiceImpl) ConsolidateConfig() ([]model.SSHDirectiveSource, error) {
+	report, err := s.FindConfigConflicts()
+	if err != nil {
+		return nil, err
+	}
+
+	var disable []model.SSHDirectiveSource
+	for _, conflict := range report.Conflicts {
+		if !conflict.Managed {
+			continue
+		}
+
+		managedValues := make(map[string]bool)
+		for _, source := range conflict.Sources {
+			if source.File == report.ManagedFile {
+				managedValues[strings.ToLower(source.Value)] = true
+				for _, field := range strings.Fields(strings.ToLower(source.Value)) {
+					managedValues[field] = true
+				}
+			}
+		}
+
+		for _, source := range conflict.Sources {
+			if source.File == report.ManagedFile {
+				continue
+			}
+			if sshAccumulatingDirectives[conflict.Directive] {
+				for _, field := range strings.Fields(strings.ToLower(source.Value)) {
+					if !managedValues[field] {
+						disable = append(disable, source)
+						break
+					}
+				}
+			} else if !managedValues[strings.ToLower(source.Value)] {
+				disable = append(disable, source)
+			}
+		}
+	}
+
+	if err := s.repository.DisableSSHDirectives(disable, report.ManagedFile); err != nil {
+		return nil, err
+	}
+	return disable, nil
+}
+
+// sourcesConflict reports whether the lines setting one directive disagree
+func sourcesConflict(directive string, sources []model.SSHDirectiveSource) bool {
+	if sshAccumulatingDirectives[directive] {
+		// Lines of one file add up; files conflict when they add different values
+		var files []string
+		values := make(map[string]string)
+		for _, source := range sources {
+			if _, ok := values[source.File]; !ok {
+				files = append(files, source.File)
+			}
+			values[source.File] += " " + source.Value
+		}
+		for _, file := range files[1:] {
+			if !sameFields(values[files[0]], values[file]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, source := range sources[1:] {
+		if !strings.EqualFold(source.Value, sources[0].Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// intendedSSHDirectives returns the directives SaveSSHConfig writes for
+// config, with values in the form sshd -T prints them
+func intendedSSHDirectives(config model.SSHConfig) ([]model.SSHDirectiveDiff, error) {
+	var directives []model.SSHDirectiveDiff
+	add := func(directive, value string) {
+		directives = append(directives, model.SSHDirectiveDiff{Directive: directive, Intended: value})
+	}
+
+	port := strconv.Itoa(config.Port)
+	add("port", port)
+
+	// sshd -T prints each listen address with its port
+	if len(config.ListenAddresses) > 0 {
+		addresses := make([]string, 0, len(config.ListenAddresses))
+		for _, addr := range config.ListenAddresses {
+			if host, addrPort, err := net.SplitHostPort(addr); err == nil {
+				addresses = append(addresses, net.JoinHostPort(host, addrPort))
+			} else {
+				addresses = append(addresses, net.JoinHostPort(addr, port))
+			}
+		}
+		add("listenaddress", strings.Join(addresses, " "))
+	}
+
+	add("strictmodes", "yes")
+	if len(config.AuthMethods) > 0 {
+		add("authenticationmethods", strings.Join(config.AuthMethods, ","))
+	} else {
+		add("authenticationmethods", "publickey")
+	}
+	add("pubkeyauthentication", "yes")
+	if config.PermitRootLogin {
+		add("permitrootlogin", "yes")
+	} else {
+		add("permitrootlogin", "no")
+	}
+	if len(config.AllowedUsers) > 0 {
+		add("allowusers", strings.Join(config.AllowedUsers, " "))
+	}
+	add("passwordauthentication", "no")
+	add("permitemptypasswords", "no")
+	if len(config.KeyPaths) > 0 {
+		add("authorizedkeysfile", strings.Join(config.KeyPaths, " "))
+	} else {
+		add("authorizedkeysfile", ".ssh/authorized_keys")
+	}
+
+	if config.AuthorizedKeysCommand != "" {
+		runAs := config.AuthorizedKeysCommandUser
+		if runAs == "" {
+			runAs = model.DefaultAuthorizedKeysCommandUser
+		}
+		add("authorizedkeyscommand", config.AuthorizedKeysCommand)
+		add("authorizedkeyscommanduser", runAs)
+	}
+
+	if config.Banner != "" {
+		add("banner", config.Banner)
+	}
+
+	if config.Profile != "" {
+		profile, ok := model.LookupSSHProfile(config.Profile)
+		if !ok {
+			return nil, fmt.Errorf("unknown SSH hardening profile: %s", config.Profile)
+		}
+		add("maxauthtries", strconv.Itoa(profile.MaxAuthTries))
+		add("logingracetime", strconv.Itoa(profile.LoginGraceTime))
+		add("clientaliveinterval", strconv.Itoa(profile.ClientAliveInterval))
+		add("clientalivecountmax", strconv.Itoa(profile.ClientAliveCountMax))
+		if profile.X11Forwarding {
+			add("x11forwarding", "yes")
+		} else {
+			add("x11forwarding", "no")
+		}
+		add("allowtcpforwarding", profile.AllowTcpForwarding)
+		add("ciphers", strings.Join(profile.Ciphers, ","))
+		add("macs", strings.Join(profile.MACs, ","))
+		add("kexalgorithms", strings.Join(profile.KexAlgorithms, ","))
+	}
+
+	return directives, nil
+}
+
+// sameFields reports whether two space-separated lists hold the same values
+func sameFields(a, b string) bool {
+	left, right := strings.Fields(strings.ToLower(a)), strings.Fields(strings.ToLower(b))
+	if len(left) != len(right) {
+		return false
+	}
+	sort.Strings(left)
+	sort.Strings(right)
+	for i := range left {
+		if left[i] != right[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// firstSourceOutside returns the first source not in file, or nil
+func firstSourceOutside(sources []model.SSHDirectiveSource, file string) *model.SSHDirectiveSource {
+	for i := range sources {
+		if sources[i].File != file {
+			return &sources[i]
+		}
+	}
+	return nil
+}
+
+// ValidateSSHProfile checks that profile names a known hardening profile;
+// an empty name means no profile
+func ValidateSSHProfile(profile string) error {
+	if profile == "" {
+		return nil
+	}
+	if _, ok := model.LookupSSHProfile(profile); !ok {
+		return fmt.Errorf("unknown SSH hardening profile %q (valid: %s)",
+			profile, strings.Join(model.SSHProfileNames, ", "))
+	}
+	return nil
+}
+
+// ValidateListenAddress checks that addr is usable as an sshd ListenAddress:
+// an IPv4 or IPv6 address, optionally with a port (host:port or [v6]:port)
+func ValidateListenAddress(addr string) error {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return fmt.Errorf("listen address cannot be empty")
+	}
+
+	host := addr
+	if strings.HasPrefix(addr, "[") || strings.Count(addr, ":") == 1 {
+		h, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return fmt.Errorf("invalid listen address %q: %w", addr, err)
+		}
+		portNum, err := strconv.Atoi(port)
+		if err != nil || portNum < 1 || portNum > 65535 {
+			return fmt.Errorf("invalid port in listen address %q", addr)
+		}
+		host = h
+	}
+
+	if net.ParseIP(host) == nil {
+		return fmt.Errorf("invalid listen address %q: not an IP address", addr)
+	}
+
+	return nil
+}
+
+// IsWildcardListenAddress reports whether addr listens on every interface
+func IsWildcardListenAddress(addr string) bool {
+	host := strings.TrimSpace(addr)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsUnspecified()
+}
+
+// ResolveAllowedUsers returns the AllowUsers list to write; see
+// model.ResolveAllowedUsers
+func ResolveAllowedUsers(allowedUsers []string, managedUser string, allowAll bool) ([]string, error) {
+	return model.ResolveAllowedUsers(allowedUsers, managedUser, allowAll)
+}
@@ -44,6 +44,35 @@ func (m *MockSSHRepository) AddAuthorizedKey(username string, publicKey string)
 	return args.Error(0)
 }
 
+func (m *MockSSHRepository) GetEffectiveSSHConfig() (map[string][]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string][]string), args.Error(1)
+}
+
+func (m *MockSSHRepository) GetSSHConfigSources() ([]model.SSHDirectiveSource, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SSHDirectiveSource), args.Error(1)
+}
+
+func (m *MockSSHRepository) ListSSHDaemons() ([]model.SSHDaemon, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SSHDaemon), args.Error(1)
+}
+
+func (m *MockSSHRepository) DisableSSHDirectives(sources []model.SSHDirectiveSource, managedFile string) error {
+	args := m.Called(sources, managedFile)
+	return args.Error(0)
+}
+
 func TestSSHServiceImpl_ConfigureSSH(t *testing.T) {
 	// Setup
 	mockRepo := new(MockSSHRepository)
@@ -352,3 +381,491 @@ func TestSSHServiceImpl_ConfigureSSHWithDifferentOSTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestSSHServiceImpl_IsRootLoginEnabled(t *testing.T) {
+	tests := []struct {
+		name            string
+		permitRootLogin bool
+	}{
+		{name: "root login enabled", permitRootLogin: true},
+		{name: "root login disabled", permitRootLogin: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(MockSSHRepository)
+			service := NewSSHServiceImpl(mockRepo, model.OSInfo{Type: "debian", Version: "11"})
+
+			mockRepo.On("GetSSHConfig").Return(&model.SSHConfig{
+				Port:            22,
+				PermitRootLogin: tc.permitRootLogin,
+			}, nil)
+
+			// Execute
+			enabled, err := service.IsRootLoginEnabled()
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tc.permitRootLogin, enabled)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSSHServiceImpl_IsRootLoginEnabled_Error(t *testing.T) {
+	// Setup
+	mockRepo := new(MockSSHRepository)
+	service := NewSSHServiceImpl(mockRepo, model.OSInfo{Type: "debian", Version: "11"})
+
+	expectedErr := fmt.Errorf("failed to get config")
+	mockRepo.On("GetSSHConfig").Return(nil, expectedErr)
+
+	// Execute
+	enabled, err := service.IsRootLoginEnabled()
+
+	// Assert
+	assert.Error(t, err)
+	assert.False(t, enabled)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSSHServiceImpl_ConfigureSSH_InvalidListenAddress(t *testing.T) {
+	// Setup
+	mockRepo := new(MockSSHRepository)
+	service := NewSSHServiceImpl(mockRepo, model.OSInfo{Type: "debian", Version: "11"})
+
+	config := model.SSHConfig{
+		Port:            2222,
+		ListenAddresses: []string{"192.168.1.10", "not-an-ip"},
+	}
+
+	// Execute
+	err := service.ConfigureSSH(config)
+
+	// Assert
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "SaveSSHConfig", config)
+}
+
+func TestValidateListenAddress(t *testing.T) {
+	tests := []struct {
+		addr        string
+		expectError bool
+	}{
+		{addr: "0.0.0.0", expectError: false},
+		{addr: "192.168.1.10", expectError: false},
+		{addr: "192.168.1.10:2222", expectError: false},
+		{addr: "::", expectError: false},
+		{addr: "fe80::1", expectError: false},
+		{addr: "[fe80::1]:2222", expectError: false},
+		{addr: "", expectError: true},
+		{addr: "example.com", expectError: true},
+		{addr: "192.168.1.300", expectError: true},
+		{addr: "192.168.1.10:70000", expectError: true},
+		{addr: "[fe80::1]", expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.addr, func(t *testing.T) {
+			err := ValidateListenAddress(tc.addr)
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIsWildcardListenAddress(t *testing.T) {
+	assert.True(t, IsWildcardListenAddress("0.0.0.0"))
+	assert.True(t, IsWildcardListenAddress("::"))
+	assert.True(t, IsWildcardListenAddress("0.0.0.0:2222"))
+	assert.True(t, IsWildcardListenAddress("[::]:2222"))
+	assert.False(t, IsWildcardListenAddress("192.168.1.10"))
+	assert.False(t, IsWildcardListenAddress("::1"))
+}
+
+func TestSSHServiceImpl_ConfigureSSH_EmptyAllowedUsers(t *testing.T) {
+	// Setup
+	mockRepo := new(MockSSHRepository)
+	service := NewSSHServiceImpl(mockRepo, model.OSInfo{Type: "debian", Version: "11"})
+
+	config := model.SSHConfig{
+		Port:            2222,
+		ListenAddresses: []string{"0.0.0.0"},
+		ManagedUser:     "george",
+	}
+
+	expected := config
+	expected.AllowedUsers = []string{"george"}
+	mockRepo.On("SaveSSHConfig", expected).Return(nil)
+
+	// Execute
+	err := service.ConfigureSSH(config)
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestResolveAllowedUsers(t *testing.T) {
+	tests := []struct {
+		name         string
+		allowedUsers []string
+		managedUser  string
+		allowAll     bool
+		expected     []string
+		expectError  bool
+	}{
+		{
+			name:         "explicit users kept",
+			allowedUsers: []string{"alice", "bob"},
+			managedUser:  "george",
+			expected:     []string{"alice", "bob"},
+		},
+		{
+			name:        "empty populated with managed user",
+			managedUser: "george",
+			expected:    []string{"george"},
+		},
+		{
+			name:         "blank entries ignored",
+			allowedUsers: []string{"", "  "},
+			managedUser:  "george",
+			expected:     []string{"george"},
+		},
+		{
+			name:        "empty without managed user refused",
+			expectError: true,
+		},
+		{
+			name:     "empty with override permits all",
+			allowAll: true,
+			expected: nil,
+		},
+		{
+			name:         "wildcard refused",
+			allowedUsers: []string{"*"},
+			managedUser:  "george",
+			expectError:  true,
+		},
+		{
+			name:         "wildcard with override kept",
+			allowedUsers: []string{"*"},
+			allowAll:     true,
+			expected:     []string{"*"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			users, err := ResolveAllowedUsers(tc.allowedUsers, tc.managedUser, tc.allowAll)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, users)
+		})
+	}
+}
+
+func TestSSHServiceImpl_ConfigureAuthorizedKeysCommand(t *testing.T) {
+	tests := []struct {
+		name          string
+		command       string
+		runAsUser     string
+		expectCommand string
+		expectUser    string
+		expectError   bool
+	}{
+		{
+			name:          "sssd with default user",
+			command:       model.SSSDAuthorizedKeysCommand,
+			expectCommand: model.SSSDAuthorizedKeysCommand,
+			expectUser:    model.DefaultAuthorizedKeysCommandUser,
+		},
+		{
+			name:          "custom command with arguments",
+			command:       "/usr/local/bin/ldap-keys %u",
+			runAsUser:     "keylookup",
+			expectCommand: "/usr/local/bin/ldap-keys %u",
+			expectUser:    "keylookup",
+		},
+		{
+			name:      "empty command disables lookup",
+			runAsUser: "nobody",
+		},
+		{
+			name:        "relative command",
+			command:     "sss_ssh_authorizedkeys",
+			expectError: true,
+		},
+		{
+			name:        "root run-as user",
+			command:     model.SSSDAuthorizedKeysCommand,
+			runAsUser:   "root",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(MockSSHRepository)
+			service := NewSSHServiceImpl(mockRepo, model.OSInfo{Type: "debian", Version: "11"})
+
+			current := &model.SSHConfig{
+				Port:                  2222,
+				AllowedUsers:          []string{"george"},
+				AuthorizedKeysCommand: "/usr/bin/old-command",
+			}
+			expected := *current
+			expected.AuthorizedKeysCommand = tc.expectCommand
+			expected.AuthorizedKeysCommandUser = tc.expectUser
+
+			if !tc.expectError {
+				mockRepo.On("GetSSHConfig").Return(current, nil)
+				mockRepo.On("SaveSSHConfig", expected).Return(nil)
+			}
+
+			// Execute
+			err := service.ConfigureAuthorizedKeysCommand(tc.command, tc.runAsUser)
+
+			// Assert
+			if tc.expectError {
+				assert.Error(t, err)
+				mockRepo.AssertNotCalled(t, "SaveSSHConfig", mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSSHServiceImpl_ConfigureAuthorizedKeysCommand_AllowedUsers(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  []string
+		expected []string
+	}{
+		{name: "no AllowUsers stays omitted", current: nil, expected: nil},
+		{name: "blank entries dropped", current: []string{"george", " "}, expected: []string{"george"}},
+		{name: "wildcard in the file kept", current: []string{"*"}, expected: []string{"*"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockSSHRepository)
+			service := NewSSHServiceImpl(mockRepo, model.OSInfo{Type: "debian", Version: "11"})
+
+			mockRepo.On("GetSSHConfig").Return(&model.SSHConfig{Port: 22, AllowedUsers: tc.current}, nil)
+			mockRepo.On("SaveSSHConfig", model.SSHConfig{
+				Port:                      22,
+				AllowedUsers:              tc.expected,
+				AuthorizedKeysCommand:     model.SSSDAuthorizedKeysCommand,
+				AuthorizedKeysCommandUser: model.DefaultAuthorizedKeysCommandUser,
+			}).Return(nil)
+
+			err := service.ConfigureAuthorizedKeysCommand(model.SSSDAuthorizedKeysCommand, "")
+
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSSHServiceImpl_ApplyProfile(t *testing.T) {
+	tests := []struct {
+		name          string
+		profile       string
+		expectProfile string
+		expectError   bool
+	}{
+		{name: "strict", profile: "strict", expectProfile: model.SSHProfileStrict},
+		{name: "mixed case", profile: " Paranoid ", expectProfile: model.SSHProfileParanoid},
+		{name: "empty removes profile", profile: ""},
+		{name: "unknown profile", profile: "relaxed", expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(MockSSHRepository)
+			service := NewSSHServiceImpl(mockRepo, model.OSInfo{Type: "debian", Version: "12"})
+
+			current := &model.SSHConfig{
+				Port:         2222,
+				AllowedUsers: []string{"george"},
+				Profile:      model.SSHProfileBaseline,
+			}
+			expected := *current
+			expected.Profile = tc.expectProfile
+
+			if !tc.expectError {
+				mockRepo.On("GetSSHConfig").Return(current, nil)
+				mockRepo.On("SaveSSHConfig", expected).Return(nil)
+			}
+
+			// Execute
+			err := service.ApplyProfile(tc.profile)
+
+			// Assert
+			if tc.expectError {
+				assert.Error(t, err)
+				mockRepo.AssertNotCalled(t, "SaveSSHConfig", mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSSHServiceImpl_ConfigureSSH_UnknownProfile(t *testing.T) {
+	mockRepo := new(MockSSHRepository)
+	service := NewSSHServiceImpl(mockRepo, model.OSInfo{Type: "debian", Version: "12"})
+
+	err := service.ConfigureSSH(model.SSHConfig{
+		Port:         22,
+		AllowedUsers: []string{"george"},
+		Profile:      "relaxed",
+	})
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "SaveSSHConfig", mock.Anything)
+}
+
+func TestSSHServiceImpl_CompareEffectiveConfig(t *testing.T) {
+	mockRepo := new(MockSSHRepository)
+	service := NewSSHServiceImpl(mockRepo, model.OSInfo{Type: "ubuntu", Version: "24.04"})
+
+	// A cloud-init drop-in sorts before hardn.conf, so its value wins
+	mockRepo.On("GetEffectiveSSHConfig").Return(map[string][]string{
+		"port":                   {"2208"},
+		"listenaddress":          {"10.0.0.5:2208", "0.0.0.0:2208"},
+		"permitrootlogin":        {"no"},
+		"passwordauthentication": {"yes"},
+		"allowusers":             {"george"},
+		"authorizedkeysfile":     {".ssh/authorized_keys"},
+	}, nil)
+	mockRepo.On("GetSSHConfigSources").Return([]model.SSHDirectiveSource{
+		{Directive: "passwordauthentication", Value: "yes", File: "/etc/ssh/sshd_config.d/50-cloud-init.conf", Line: 1},
+		{Directive: "listenaddress", Value: "0.0.0.0", File: "/etc/ssh/sshd_config.d/60-local.conf", Line: 3},
+		{Directive: "port", Value: "2208", File: model.SSHDropInConfigPath, Line: 5},
+		{Directive: "listenaddress", Value: "10.0.0.5", File: model.SSHDropInConfigPath, Line: 6},
+		{Directive: "permitrootlogin", Value: "no", File: model.SSHDropInConfigPath, Line: 11},
+		{Directive: "passwordauthentication", Value: "no", File: model.SSHDropInConfigPath, Line: 15},
+		{Directive: "passwordauthentication", Value: "yes", File: model.SSHMainConfigPath, Line: 57},
+	}, nil)
+
+	report, err := service.CompareEffectiveConfig(model.SSHConfig{
+		Port:            2208,
+		ListenAddresses: []string{"10.0.0.5"},
+		ManagedUser:     "george",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, model.SSHDropInConfigPath, report.ManagedFile)
+
+	directives := make(map[string]model.SSHDirectiveDiff)
+	for _, directive := range report.Directives {
+		directives[directive.Directive] = directive
+	}
+
+	assert.True(t, directives["port"].Matches)
+	assert.Nil(t, directives["port"].OverriddenBy)
+	assert.True(t, directives["allowusers"].Matches)
+
+	password := directives["passwordauthentication"]
+	assert.False(t, password.Matches)
+	assert.Equal(t, "no", password.Intended)
+	assert.Equal(t, "yes", password.Effective)
+	assert.Len(t, password.Sources, 3)
+	if assert.NotNil(t, password.OverriddenBy) {
+		assert.Equal(t, "/etc/ssh/sshd_config.d/50-cloud-init.conf", password.OverriddenBy.File)
+	}
+
+	// Listen addresses accumulate, so another file adds one instead of replacing hardn's
+	listen := directives["listenaddress"]
+	assert.False(t, listen.Matches)
+	assert.Equal(t, "10.0.0.5:2208", listen.Intended)
+	if assert.NotNil(t, listen.OverriddenBy) {
+		assert.Equal(t, "/etc/ssh/sshd_config.d/60-local.conf", listen.OverriddenBy.File)
+	}
+
+	// Directives missing from sshd -T are reported with no effective value
+	assert.False(t, directives["strictmodes"].Matches)
+	assert.Len(t, report.Mismatches(), 6)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSSHServiceImpl_CompareEffectiveConfig_NoSSHD(t *testing.T) {
+	mockRepo := new(MockSSHRepository)
+	service := NewSSHServiceImpl(mockRepo, model.OSInfo{Type: "debian", Version: "12"})
+	mockRepo.On("GetEffectiveSSHConfig").Return(nil, nil)
+
+	_, err := service.CompareEffectiveConfig(model.SSHConfig{Port: 22, AllowedUsers: []string{"george"}})
+
+	assert.ErrorContains(t, err, "sshd is not installed")
+	mockRepo.AssertNotCalled(t, "GetSSHConfigSources")
+}
+
+func TestSSHServiceImpl_FindConfigConflicts(t *testing.T) {
+	const cloudInit = "/etc/ssh/sshd_config.d/50-cloud-init.conf"
+	const vendor = "/etc/ssh/sshd_config.d/60-vendor.conf"
+	sources := []model.SSHDirectiveSource{
+		{Directive: "passwordauthentication", Value: "yes", File: cloudInit, Line: 1},
+		{Directive: "x11forwarding", Value: "yes", File: cloudInit, Line: 2},
+		{Directive: "port", Value: "22", File: cloudInit, Line: 3},
+		{Directive: "hostkey", Value: "/etc/ssh/ssh_host_rsa_key", File: cloudInit, Line: 4},
+		{Directive: "x11forwarding", Value: "no", File: vendor, Line: 1},
+		{Directive: "allowusers", Value: "george", File: model.SSHDropInConfigPath, Line: 8},
+		{Directive: "allowusers", Value: "deploy", File: model.SSHDropInConfigPath, Line: 9},
+		{Directive: "passwordauthentication", Value: "no", File: model.SSHDropInConfigPath, Line: 12},
+		{Directive: "port", Value: "2208", File: model.SSHDropInConfigPath, Line: 5},
+		{Directive: "hostkey", Value: "/etc/ssh/ssh_host_ed25519_key", File: model.SSHMainConfigPath, Line: 20},
+		{Directive: "allowusers", Value: "deploy george", File: model.SSHMainConfigPath, Line: 30},
+	}
+
+	mockRepo := new(MockSSHRepository)
+	mockRepo.On("GetSSHConfigSources").Return(sources, nil)
+	mockRepo.On("ListSSHDaemons").Return([]model.SSHDaemon{
+		{PID: 812, Program: "sshd", Command: "/usr/sbin/sshd -D", ConfigFile: model.SSHMainConfigPath},
+		{PID: 901, Program: "dropbear", Command: "/usr/sbin/dropbear -F -p 2222"},
+	}, nil)
+	mockRepo.On("GetEffectiveSSHConfig").Return(map[string][]string{
+		"passwordauthentication": {"yes"},
+		"port":                   {"22", "2208"},
+		"x11forwarding":          {"yes"},
+	}, nil)
+	mockRepo.On("DisableSSHDirectives", []model.SSHDirectiveSource{sources[0], sources[2]}, model.SSHDropInConfigPath).Return(nil)
+
+	service := NewSSHServiceImpl(mockRepo, model.OSInfo{Type: "ubuntu", Version: "24.04"})
+
+	report, err := service.FindConfigConflicts()
+	assert.NoError(t, err)
+	assert.Len(t, report.Daemons, 2)
+
+	// Host keys add up, and AllowUsers lines of one file add up to the
+	// same users as the main file
+	var directives []string
+	for _, conflict := range report.Conflicts {
+		directives = append(directives, conflict.Directive)
+	}
+	assert.Equal(t, []string{"passwordauthentication", "x11forwarding", "port"}, directives)
+	assert.Equal(t, "yes", report.Conflicts[0].Effective)
+	assert.True(t, report.Conflicts[0].Managed)
+	assert.False(t, report.Conflicts[1].Managed)
+	assert.Equal(t, "22 2208", report.Conflicts[2].Effective)
+
+	// Only directives of hardn's file are consolidated
+	disabled, err := service.ConsolidateConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []model.SSHDirectiveSource{sources[0], sources[2]}, disabled)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,602 @@
+// pkg/domain/service/state_service.go
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// StateService defines operations for converging the host to the posture
+// a state file declares
+type StateService interface {
+	// LoadState reads a state file and checks that it is one
+	LoadState(path string) (*model.DesiredState, error)
+
+	// Plan compares the host with a state and lists the changes applying
+	// it makes, without changing anything
+	Plan(state *model.DesiredState) (*model.StatePlan, error)
+
+	// Apply makes the changes of a valid plan in order, one result per
+	// change; a failed change does not stop the others
+	Apply(plan *model.StatePlan) ([]model.StateChangeResult, error)
+}
+
+// StateServiceImpl implements StateService with the repositories of the
+// services each part of the state belongs to
+type StateServiceImpl struct {
+	repository   StateRepository
+	userRepo     UserRepository
+	sshRepo      SSHRepository
+	firewallRepo FirewallRepository
+	packageRepo  PackageRepository
+	debloatRepo  DebloatRepository
+}
+
+// NewStateServiceImpl creates a new StateServiceImpl
+func NewStateServiceImpl(
+	repository StateRepository,
+	userRepo UserRepository,
+	sshRepo SSHRepository,
+	firewallRepo FirewallRepository,
+	packageRepo PackageRepository,
+	debloatRepo DebloatRepository,
+) *StateServiceImpl {
+	return &StateServiceImpl{
+		repository:   repository,
+		userRepo:     userRepo,
+		sshRepo:      sshRepo,
+		firewallRepo: firewallRepo,
+		packageRepo:  packageRepo,
+		debloatRepo:  debloatRepo,
+	}
+}
+
+// StateRepository defines the repository operations needed by StateService
+type StateRepository interface {
+	ReadState(path string) (*model.DesiredState, error)
+	GetSysctl(key string) (string, bool)
+	SetSysctl(key, value string) error
+	ReadStateSysctl() (map[string]string, error)
+	WriteStateSysctl(values map[string]string) error
+}
+
+var (
+	// stateSysctlKey matches the sysctl keys a state may set
+	stateSysctlKey = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_./-]*$`)
+
+	// statePackageName matches package names, with an optional
+	// architecture such as libc6:i386; a leading dash would be read as
+	// an option of the package manager
+	statePackageName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.+_:-]*$`)
+)
+
+func (s *StateServiceImpl) LoadState(path string) (*model.DesiredState, error) {
+	state, err := s.repository.ReadState(path)
+	if err != nil {
+		return nil, err
+	}
+	if state.Kind != model.DesiredStateKind {
+		return nil, fmt.Errorf("%s is not a hardn state file (kind %q, want %q)", path, state.Kind, model.DesiredStateKind)
+	}
+	if state.Empty() {
+		return nil, fmt.Errorf("%s declares nothing to apply", path)
+	}
+	return state, nil
+}
+
+// Plan lists the changes in the order Apply makes them: packages are
+// installed first, as the other settings may need them, then users, sysctl
+// settings, firewall rules and sshd, so a new SSH port is opened before
+// sshd moves to it. Packages are removed last.
+func (s *StateServiceImpl) Plan(state *model.DesiredState) (*model.StatePlan, error) {
+	plan := &model.StatePlan{State: state}
+
+	installs, removals, err := s.planPackages(plan)
+	if err != nil {
+		return nil, err
+	}
+	plan.Changes = append(plan.Changes, installs...)
+
+	for _, step := range []func(*model.StatePlan) error{s.planUsers, s.planSysctl, s.planFirewall, s.planSSH} {
+		if err := step(plan); err != nil {
+			return nil, err
+		}
+	}
+
+	plan.Changes = append(plan.Changes, removals...)
+	return plan, nil
+}
+
+// planPackages returns the packages to install and those to remove
+func (s *StateServiceImpl) planPackages(plan *model.StatePlan) ([]model.StateChange, []model.StateChange, error) {
+	packages := plan.State.Packages
+	var install, remove []string
+	listed := make(map[string]bool)
+	for _, name := range packages.Install {
+		switch {
+		case !statePackageName.MatchString(name):
+			plan.Problems = append(plan.Problems, fmt.Sprintf("packages: %q is not a package name", name))
+		case !listed[name]:
+			listed[name] = true
+			install = append(install, name)
+		}
+	}
+	for _, name := range packages.Remove {
+		switch {
+		case !statePackageName.MatchString(name):
+			plan.Problems = append(plan.Problems, fmt.Sprintf("packages: %q is not a package name", name))
+		case slices.Contains(install, name):
+			plan.Problems = append(plan.Problems, fmt.Sprintf("packages: %s is listed under install and remove", name))
+		case !listed[name]:
+			listed[name] = true
+			remove = append(remove, name)
+		}
+	}
+	if len(install) == 0 && len(remove) == 0 {
+		return nil, nil, nil
+	}
+
+	installed, err := s.debloatRepo.InstalledPackages(append(slices.Clone(install), remove...))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check the installed packages: %w", err)
+	}
+
+	var installs, removals []model.StateChange
+	for _, name := range install {
+		if !slices.Contains(installed, name) {
+			installs = append(installs, model.StateChange{Resource: model.StateResourcePackage, Name: name, Action: model.StateCreate})
+		}
+	}
+	for _, name := range remove {
+		if slices.Contains(installed, name) {
+			removals = append(removals, model.StateChange{Resource: model.StateResourcePackage, Name: name, Action: model.StateDelete})
+		}
+	}
+	return installs, removals, nil
+}
+
+// planUsers creates missing accounts and, for existing ones, grants the
+// sudo access and authorizes the keys they lack. Directory accounts are
+// refused, as a local account of the same name would shadow them.
+func (s *StateServiceImpl) planUsers(plan *model.StatePlan) error {
+	seen := make(map[string]bool)
+	for _, entry := range plan.State.Users {
+		if err := model.ValidateUsername(entry.Name); err != nil {
+			plan.Problems = append(plan.Problems, fmt.Sprintf("user %q: %v", entry.Name, err))
+			continue
+		}
+		if seen[entry.Name] {
+			plan.Problems = append(plan.Problems, fmt.Sprintf("user %s is listed twice", entry.Name))
+			continue
+		}
+		seen[entry.Name] = true
+
+		problems := stateUserProblems(entry)
+		plan.Problems = append(plan.Problems, problems...)
+		if len(problems) > 0 {
+			continue
+		}
+
+		directory, err := s.userRepo.IsDirectoryUser(entry.Name)
+		if err != nil {
+			return fmt.Errorf("failed to look up %s: %w", entry.Name, err)
+		}
+		if directory {
+			plan.Problems = append(plan.Problems, fmt.Sprintf("user %s is a directory account; a local account would shadow it", entry.Name))
+			continue
+		}
+
+		if _, err := s.userRepo.GetUser(entry.Name); err != nil {
+			details := []string{"sudo: " + sudoDescription(entry.Sudo, entry.NoPassword)}
+			for _, key := range entry.Keys {
+				fingerprint, _ := model.SSHKeyFingerprint(key)
+				details = append(details, "authorize key "+fingerprint)
+			}
+			plan.Changes = append(plan.Changes, model.StateChange{
+				Resource: model.StateResourceUser, Name: entry.Name, Action: model.StateCreate, Details: details,
+			})
+			continue
+		}
+
+		current, err := s.userRepo.GetExtendedUserInfo(entry.Name)
+		if err != nil {
+			return fmt.Errorf("failed to read user %s: %w", entry.Name, err)
+		}
+
+		var details []string
+		if entry.Sudo && (!current.HasSudo || current.SudoNoPassword != entry.NoPassword) {
+			details = append(details, fmt.Sprintf("sudo: %s -> %s",
+				sudoDescription(current.HasSudo, current.SudoNoPassword), sudoDescription(true, entry.NoPassword)))
+		}
+		authorized := make(map[string]bool)
+		for _, key := range current.SshKeys {
+			if fingerprint, err := model.SSHKeyFingerprint(key); err == nil {
+				authorized[fingerprint] = true
+			}
+		}
+		for _, key := range entry.Keys {
+			if fingerprint, _ := model.SSHKeyFingerprint(key); !authorized[fingerprint] {
+				authorized[fingerprint] = true
+				details = append(details, "authorize key "+fingerprint)
+			}
+		}
+		if len(details) > 0 {
+			plan.Changes = append(plan.Changes, model.StateChange{
+				Resource: model.StateResourceUser, Name: entry.Name, Action: model.StateUpdate, Details: details,
+			})
+		}
+	}
+	return nil
+}
+
+// stateUserProblems checks the sudo settings and keys of a state user;
+// DSA and short RSA keys are refused
+func stateUserProblems(entry model.StateUser) []string {
+	var problems []string
+	if entry.NoPassword && !entry.Sudo {
+		problems = append(problems, fmt.Sprintf("user %s: nopasswd is set without sudo", entry.Name))
+	}
+	for i, line := range entry.Keys {
+		key, err := model.ParseAuthorizedKey(line)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("user %s: key %d is not a public key: %v", entry.Name, i+1, err))
+			continue
+		}
+		if _, detail := weakKeyIssue(*key); detail != "" {
+			problems = append(problems, fmt.Sprintf("user %s: key %d %s", entry.Name, i+1, detail))
+		}
+	}
+	return problems
+}
+
+// sudoDescription describes a user's sudo access in a plan
+func sudoDescription(sudo, noPassword bool) string {
+	switch {
+	case sudo && noPassword:
+		return "yes (no password)"
+	case sudo:
+		return "yes (password required)"
+	}
+	return "no"
+}
+
+// planSysctl sets the keys whose running value differs and keeps every
+// key of the state in model.StateSysctlPath. Keys the file holds that the
+// state no longer lists are dropped from it; their running value is kept.
+func (s *StateServiceImpl) planSysctl(plan *model.StatePlan) error {
+	persisted, err := s.repository.ReadStateSysctl()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(plan.State.Sysctl))
+	for key := range plan.State.Sysctl {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		want := model.NormalizeSysctlValue(plan.State.Sysctl[key])
+		if !stateSysctlKey.MatchString(key) {
+			plan.Problems = append(plan.Problems, fmt.Sprintf("sysctl: %q is not a sysctl key", key))
+			continue
+		}
+		if want == "" {
+			plan.Problems = append(plan.Problems, fmt.Sprintf("sysctl %s has no value", key))
+			continue
+		}
+		current, ok := s.repository.GetSysctl(key)
+		if !ok {
+			plan.Problems = append(plan.Problems, fmt.Sprintf("sysctl %s does not exist in the running kernel", key))
+			continue
+		}
+
+		var details []string
+		if current != want {
+			details = append(details, fmt.Sprintf("value: %s -> %s", current, want))
+		}
+		if persisted[key] != want {
+			details = append(details, "keep in "+model.StateSysctlPath)
+		}
+		if len(details) > 0 {
+			plan.Changes = append(plan.Changes, model.StateChange{
+				Resource: model.StateResourceSysctl, Name: key, Action: model.StateUpdate, Details: details,
+			})
+		}
+	}
+
+	var dropped []string
+	for key := range persisted {
+		if _, ok := plan.State.Sysctl[key]; !ok {
+			dropped = append(dropped, key)
+		}
+	}
+	sort.Strings(dropped)
+	for _, key := range dropped {
+		plan.Changes = append(plan.Changes, model.StateChange{
+			Resource: model.StateResourceSysctl, Name: key, Action: model.StateDelete,
+			Details: []string{"drop from " + model.StateSysctlPath + "; the running value is kept"},
+		})
+	}
+	return nil
+}
+
+// planFirewall adds the rules the live firewall lacks. Rules are compared
+// as FirewallRule.Spec returns them.
+func (s *StateServiceImpl) planFirewall(plan *model.StatePlan) error {
+	rules := plan.State.Firewall.Rules
+	if len(rules) == 0 {
+		return nil
+	}
+
+	installed, _, _, live, err := s.firewallRepo.GetFirewallStatus()
+	if err != nil {
+		return fmt.Errorf("failed to read the firewall status: %w", err)
+	}
+	if !installed {
+		plan.Problems = append(plan.Problems, "firewall: no firewall is installed to add the rules to")
+		return nil
+	}
+	live = expandRuleSpecs(live)
+
+	seen := make(map[string]bool)
+	for i, entry := range rules {
+		rule := entry.Rule()
+		if err := ValidateRule(rule); err != nil {
+			plan.Problems = append(plan.Problems, fmt.Sprintf("firewall rule %d: %v", i+1, err))
+			continue
+		}
+		spec := rule.Spec()
+		if seen[spec] {
+			continue
+		}
+		seen[spec] = true
+
+		for _, expanded := range expandRuleSpecs([]string{spec}) {
+			if !slices.Contains(live, expanded) {
+				plan.Changes = append(plan.Changes, model.StateChange{
+					Resource: model.StateResourceFirewall, Name: spec, Action: model.StateCreate,
+				})
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// planSSH compares the sshd settings of the state with the current ones.
+// A new port must be open in an enabled firewall, or opened by the state,
+// so applying the state does not lock out SSH.
+func (s *StateServiceImpl) planSSH(plan *model.StatePlan) error {
+	desired := plan.State.SSH
+	if desired == nil {
+		return nil
+	}
+
+	current, err := s.sshRepo.GetSSHConfig()
+	if err != nil {
+		return fmt.Errorf("failed to read the SSH configuration: %w", err)
+	}
+
+	problems := len(plan.Problems)
+	var details []string
+	if desired.Port != 0 {
+		if desired.Port < 1 || desired.Port > 65535 {
+			plan.Problems = append(plan.Problems, fmt.Sprintf("ssh: port %d is not a port", desired.Port))
+		} else if desired.Port != current.Port {
+			details = append(details, fmt.Sprintf("port: %d -> %d", current.Port, desired.Port))
+			if err := s.checkSSHPortOpen(plan, desired.Port); err != nil {
+				return err
+			}
+		}
+	}
+	if len(desired.ListenAddresses) > 0 {
+		for _, addr := range desired.ListenAddresses {
+			if err := ValidateListenAddress(addr); err != nil {
+				plan.Problems = append(plan.Problems, "ssh: "+err.Error())
+			}
+		}
+		if !slices.Equal(desired.ListenAddresses, current.ListenAddresses) {
+			details = append(details, fmt.Sprintf("listen addresses: %s -> %s",
+				stateList(current.ListenAddresses), stateList(desired.ListenAddresses)))
+		}
+	}
+	if desired.PermitRootLogin != nil && *desired.PermitRootLogin != current.PermitRootLogin {
+		details = append(details, fmt.Sprintf("permit root login: %s -> %s",
+			stateFlag(current.PermitRootLogin), stateFlag(*desired.PermitRootLogin)))
+	}
+	if len(desired.AllowUsers) > 0 {
+		users, err := ResolveAllowedUsers(desired.AllowUsers, "", false)
+		if err != nil {
+			plan.Problems = append(plan.Problems, "ssh: "+err.Error())
+		} else if !slices.Equal(users, current.AllowedUsers) {
+			details = append(details, fmt.Sprintf("allow users: %s -> %s",
+				stateList(current.AllowedUsers), stateList(users)))
+		}
+	}
+
+	if len(details) > 0 && len(plan.Problems) == problems {
+		plan.Changes = append(plan.Changes, model.StateChange{
+			Resource: model.StateResourceSSH, Name: "sshd", Action: model.StateUpdate, Details: details,
+		})
+	}
+	return nil
+}
+
+// checkSSHPortOpen records a problem when an enabled firewall would block
+// a new SSH port: neither a live rule nor a rule of the state allows it
+func (s *StateServiceImpl) checkSSHPortOpen(plan *model.StatePlan, port int) error {
+	installed, enabled, _, live, err := s.firewallRepo.GetFirewallStatus()
+	if err != nil {
+		return fmt.Errorf("failed to read the firewall status: %w", err)
+	}
+	if !installed || !enabled {
+		return nil
+	}
+
+	specs := slices.Clone(live)
+	for _, rule := range plan.State.Firewall.Rules {
+		specs = append(specs, rule.Rule().Spec())
+	}
+	want := strconv.Itoa(port) + "/tcp"
+	for _, spec := range expandRuleSpecs(specs) {
+		fields := strings.Fields(spec)
+		if len(fields) >= 2 && (fields[0] == "allow" || fields[0] == "limit") && fields[1] == want {
+			return nil
+		}
+	}
+	plan.Problems = append(plan.Problems, fmt.Sprintf("ssh: the firewall does not allow port %d; add a rule for it to the state file", port))
+	return nil
+}
+
+// stateFlag formats a setting that is on or off for a plan
+func stateFlag(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+// stateList formats a list of settings for a plan
+func stateList(values []string) string {
+	if len(values) == 0 {
+		return "(none)"
+	}
+	return strings.Join(values, ", ")
+}
+
+// Apply refuses a plan with problems as a whole, so a state is never half
+// applied because of a mistake the plan found. Packages are installed, and
+// removed, with one run of the package manager each; its result is the
+// result of each of their changes.
+func (s *StateServiceImpl) Apply(plan *model.StatePlan) ([]model.StateChangeResult, error) {
+	if plan == nil || plan.Empty() {
+		return nil, fmt.Errorf("nothing to apply")
+	}
+	if !plan.Valid() {
+		return nil, fmt.Errorf("the state has %d problem(s); nothing was changed", len(plan.Problems))
+	}
+
+	var installs, removals []string
+	for _, change := range plan.Changes {
+		if change.Resource == model.StateResourcePackage && change.Action == model.StateCreate {
+			installs = append(installs, change.Name)
+		} else if change.Resource == model.StateResourcePackage {
+			removals = append(removals, change.Name)
+		}
+	}
+
+	var installErr, removeErr, sysctlErr error
+	installed, removed, persisted := false, false, false
+	results := make([]model.StateChangeResult, 0, len(plan.Changes))
+	for _, change := range plan.Changes {
+		var err error
+		switch {
+		case change.Resource == model.StateResourcePackage && change.Action == model.StateCreate:
+			if !installed {
+				installErr = s.packageRepo.InstallPackages(model.PackageInstallRequest{Packages: installs})
+				installed = true
+			}
+			err = installErr
+		case change.Resource == model.StateResourcePackage:
+			if !removed {
+				removeErr = s.debloatRepo.RemovePackages(removals)
+				removed = true
+			}
+			err = removeErr
+		case change.Resource == model.StateResourceUser:
+			err = s.applyUser(plan.State, change.Name)
+		case change.Resource == model.StateResourceSysctl:
+			if !persisted {
+				sysctlErr = s.repository.WriteStateSysctl(stateSysctlValues(plan.State))
+				persisted = true
+			}
+			err = sysctlErr
+			if err == nil && change.Action == model.StateUpdate {
+				err = s.applySysctl(change.Name, plan.State.Sysctl[change.Name])
+			}
+		case change.Resource == model.StateResourceFirewall:
+			err = s.applyFirewallRule(plan.State, change.Name)
+		case change.Resource == model.StateResourceSSH:
+			err = s.applySSH(plan.State.SSH)
+		default:
+			err = fmt.Errorf("unknown resource %q", change.Resource)
+		}
+		results = append(results, model.StateChangeResult{Change: change, Err: err})
+	}
+	return results, nil
+}
+
+// applyUser creates the account, or grants an existing one its sudo
+// access and keys
+func (s *StateServiceImpl) applyUser(state *model.DesiredState, name string) error {
+	for _, entry := range state.Users {
+		if entry.Name == name {
+			return s.userRepo.CreateUser(entry.User())
+		}
+	}
+	return fmt.Errorf("user %s is not in the state", name)
+}
+
+// applySysctl sets a key in the running kernel unless it already has the
+// value
+func (s *StateServiceImpl) applySysctl(key, value string) error {
+	value = model.NormalizeSysctlValue(value)
+	if current, ok := s.repository.GetSysctl(key); ok && current == value {
+		return nil
+	}
+	return s.repository.SetSysctl(key, value)
+}
+
+// stateSysctlValues returns the sysctl settings of a state as they are kept
+func stateSysctlValues(state *model.DesiredState) map[string]string {
+	values := make(map[string]string, len(state.Sysctl))
+	for key, value := range state.Sysctl {
+		values[key] = model.NormalizeSysctlValue(value)
+	}
+	return values
+}
+
+// applyFirewallRule adds the rule of the state with the given spec
+func (s *StateServiceImpl) applyFirewallRule(state *model.DesiredState, spec string) error {
+	for _, entry := range state.Firewall.Rules {
+		if rule := entry.Rule(); rule.Spec() == spec {
+			return s.firewallRepo.AddRule(rule)
+		}
+	}
+	return fmt.Errorf("firewall rule %s is not in the state", spec)
+}
+
+// applySSH rewrites hardn's sshd configuration from the current one with
+// the settings of the state, keeping the others, such as the hardening
+// profile and the banner
+func (s *StateServiceImpl) applySSH(desired *model.StateSSH) error {
+	config, err := s.sshRepo.GetSSHConfig()
+	if err != nil {
+		return fmt.Errorf("failed to read the SSH configuration: %w", err)
+	}
+
+	if desired.Port != 0 {
+		config.Port = desired.Port
+	}
+	if len(desired.ListenAddresses) > 0 {
+		config.ListenAddresses = desired.ListenAddresses
+	}
+	if desired.PermitRootLogin != nil {
+		config.PermitRootLogin = *desired.PermitRootLogin
+	}
+	if len(desired.AllowUsers) > 0 {
+		users, err := ResolveAllowedUsers(desired.AllowUsers, "", false)
+		if err != nil {
+			return err
+		}
+		config.AllowedUsers = users
+	}
+	return s.sshRepo.SaveSSHConfig(*config)
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockStateRepository struct {
+	State     *model.DesiredState
+	ReadError error
+
+	// Sysctl keys of the running kernel and of the state's sysctl file
+	Sysctl    map[string]string
+	Persisted map[string]string
+	SetKeys   []string
+	Written   map[string]string
+}
+
+func (m *MockStateRepository) ReadState(path string) (*model.DesiredState, error) {
+	return m.State, m.ReadError
+}
+
+func (m *MockStateRepository) GetSysctl(key string) (string, bool) {
+	value, ok := m.Sysctl[key]
+	return value, ok
+}
+
+func (m *MockStateRepository) SetSysctl(key, value string) error {
+	m.SetKeys = append(m.SetKeys, key)
+	m.Sysctl[key] = value
+	return nil
+}
+
+func (m *MockStateRepository) ReadStateSysctl() (map[string]string, error) {
+	return m.Persisted, nil
+}
+
+func (m *MockStateRepository) WriteStateSysctl(values map[string]string) error {
+	m.Written = values
+	return nil
+}
+
+// newTestStateService returns a service for a host where george exists
+// without sudo, sshd listens on port 22 and ufw allows 22/tcp
+func newTestStateService() (*StateServiceImpl, *MockStateRepository, *MockUserRepository, *MockSSHRepository, *MockFirewallRepository, *MockPackageRepository, *MockDebloatRepository) {
+	stateRepo := &MockStateRepository{
+		Sysctl:    map[string]string{"net.ipv4.ip_forward": "1", "net.ipv4.tcp_rmem": "4096 131072 6291456"},
+		Persisted: map[string]string{"kernel.kptr_restrict": "2"},
+	}
+	userRepo := new(MockUserRepository)
+	userRepo.On("IsDirectoryUser", mock.Anything).Return(false, nil)
+	userRepo.On("GetUser", "george").Return(&model.User{Username: "george"}, nil)
+	userRepo.On("GetUser", mock.Anything).Return(nil, errors.New("no such user"))
+	userRepo.On("GetExtendedUserInfo", "george").Return(&model.User{Username: "george", SshKeys: []string{testEd25519Key}}, nil)
+
+	sshRepo := new(MockSSHRepository)
+	sshRepo.On("GetSSHConfig").Return(&model.SSHConfig{Port: 22, PermitRootLogin: true, Banner: "/etc/issue.net"}, nil)
+
+	firewallRepo := &MockFirewallRepository{Installed: true, Enabled: true, Rules: []string{"allow 22/tcp"}}
+	packageRepo := &MockPackageRepository{}
+	debloatRepo := new(MockDebloatRepository)
+
+	service := NewStateServiceImpl(stateRepo, userRepo, sshRepo, firewallRepo, packageRepo, debloatRepo)
+	return service, stateRepo, userRepo, sshRepo, firewallRepo, packageRepo, debloatRepo
+}
+
+func TestStateServiceImpl_LoadState(t *testing.T) {
+	service, stateRepo, _, _, _, _, _ := newTestStateService()
+
+	stateRepo.State = &model.DesiredState{Kind: "hardn-profile", Sysctl: map[string]string{"net.ipv4.ip_forward": "0"}}
+	_, err := service.LoadState("state.yml")
+	assert.EqualError(t, err, `state.yml is not a hardn state file (kind "hardn-profile", want "hardn-state")`)
+
+	stateRepo.State = &model.DesiredState{Kind: model.DesiredStateKind}
+	_, err = service.LoadState("state.yml")
+	assert.EqualError(t, err, "state.yml declares nothing to apply")
+}
+
+func TestStateServiceImpl_Plan(t *testing.T) {
+	service, _, _, _, _, _, debloatRepo := newTestStateService()
+	debloatRepo.On("InstalledPackages", []string{"fail2ban", "curl", "telnet"}).Return([]string{"curl", "telnet"}, nil)
+
+	permitRoot := false
+	state := &model.DesiredState{
+		Kind: model.DesiredStateKind,
+		Users: []model.StateUser{
+			{Name: "george", Sudo: true, Keys: []string{testEd25519Key, testLaptopKey}},
+			{Name: "deploy", Keys: []string{testEd25519Key}},
+		},
+		SSH: &model.StateSSH{Port: 2222, PermitRootLogin: &permitRoot, AllowUsers: []string{"george"}},
+		Firewall: model.StateFirewall{Rules: []model.StateFirewallRule{
+			{Action: "allow", Protocol: "tcp", Port: 22},
+			{Action: "allow", Protocol: "tcp", Port: 2222},
+		}},
+		Sysctl: map[string]string{"net.ipv4.ip_forward": "0", "net.ipv4.tcp_rmem": "4096\t131072  6291456"},
+		Packages: model.StatePackages{
+			Install: []string{"fail2ban", "curl"},
+			Remove:  []string{"telnet"},
+		},
+	}
+
+	plan, err := service.Plan(state)
+
+	require.NoError(t, err)
+	assert.True(t, plan.Valid(), plan.Problems)
+
+	var described []string
+	for _, change := range plan.Changes {
+		described = append(described, change.Symbol()+" "+change.Describe())
+	}
+	assert.Equal(t, []string{
+		"+ create package fail2ban",
+		"~ update user george",
+		"+ create user deploy",
+		"~ update sysctl net.ipv4.ip_forward",
+		"~ update sysctl net.ipv4.tcp_rmem",
+		"- delete sysctl kernel.kptr_restrict",
+		"+ create firewall rule allow 2222/tcp",
+		"~ update ssh sshd",
+		"- delete package telnet",
+	}, described)
+	assert.Equal(t, "Plan: 3 to add, 4 to change, 2 to destroy.", plan.Summary())
+
+	george := plan.Changes[1]
+	assert.Len(t, george.Details, 2, "only the key george lacks is authorized")
+	assert.Equal(t, "sudo: no -> yes (password required)", george.Details[0])
+	assert.Equal(t, []string{"value: 1 -> 0", "keep in " + model.StateSysctlPath}, plan.Changes[3].Details)
+	assert.Equal(t, []string{"keep in " + model.StateSysctlPath}, plan.Changes[4].Details, "values compare without their whitespace")
+	assert.Equal(t, []string{
+		"port: 22 -> 2222",
+		"permit root login: yes -> no",
+		"allow users: (none) -> george",
+	}, plan.Changes[7].Details)
+}
+
+func TestStateServiceImpl_Plan_Problems(t *testing.T) {
+	service, _, _, _, _, _, debloatRepo := newTestStateService()
+	debloatRepo.On("InstalledPackages", mock.Anything).Return([]string{}, nil)
+
+	state := &model.DesiredState{
+		Kind: model.DesiredStateKind,
+		Users: []model.StateUser{
+			{Name: "Bad Name"},
+			{Name: "alice", NoPassword: true, Keys: []string{testRSA1024Key}},
+		},
+		SSH:      &model.StateSSH{Port: 2222, AllowUsers: []string{"*"}},
+		Firewall: model.StateFirewall{Rules: []model.StateFirewallRule{{Action: "open", Protocol: "tcp", Port: 80}}},
+		Sysctl:   map[string]string{"net.ipv4.missing": "1", "bad key": "1"},
+		Packages: model.StatePackages{Install: []string{"-oDebug=1", "curl"}, Remove: []string{"curl"}},
+	}
+
+	plan, err := service.Plan(state)
+
+	require.NoError(t, err)
+	assert.False(t, plan.Valid())
+	assert.Equal(t, []string{
+		`packages: "-oDebug=1" is not a package name`,
+		"packages: curl is listed under install and remove",
+		`user "Bad Name": username cannot contain spaces`,
+		"user alice: nopasswd is set without sudo",
+	}, plan.Problems[:4])
+	assert.Contains(t, plan.Problems[4], "user alice: key 1")
+	assert.Equal(t, []string{
+		`sysctl: "bad key" is not a sysctl key`,
+		"sysctl net.ipv4.missing does not exist in the running kernel",
+		`firewall rule 1: invalid action "open" (use allow, deny or reject)`,
+		"ssh: the firewall does not allow port 2222; add a rule for it to the state file",
+		"ssh: refusing wildcard '*' in SSH allowed users; set sshAllowAllUsers to permit every user",
+	}, plan.Problems[5:])
+
+	_, err = service.Apply(plan)
+	assert.EqualError(t, err, "the state has 10 problem(s); nothing was changed")
+}
+
+func TestStateServiceImpl_Apply(t *testing.T) {
+	service, stateRepo, userRepo, sshRepo, firewallRepo, packageRepo, debloatRepo := newTestStateService()
+	debloatRepo.On("InstalledPackages", []string{"fail2ban", "telnet", "rsh-client"}).Return([]string{"telnet", "rsh-client"}, nil)
+	debloatRepo.On("RemovePackages", []string{"telnet", "rsh-client"}).Return(errors.New("dpkg is locked"))
+	userRepo.On("CreateUser", model.User{Username: "george", HasSudo: true, SudoNoPassword: true}).Return(nil)
+	sshRepo.On("SaveSSHConfig", model.SSHConfig{Port: 2222, PermitRootLogin: true, Banner: "/etc/issue.net"}).Return(nil)
+
+	state := &model.DesiredState{
+		Kind:     model.DesiredStateKind,
+		Users:    []model.StateUser{{Name: "george", Sudo: true, NoPassword: true}},
+		SSH:      &model.StateSSH{Port: 2222},
+		Firewall: model.StateFirewall{Rules: []model.StateFirewallRule{{Action: "allow", Protocol: "tcp", Port: 2222, Comment: "ssh"}}},
+		Sysctl:   map[string]string{"net.ipv4.ip_forward": "0"},
+		Packages: model.StatePackages{Install: []string{"fail2ban"}, Remove: []string{"telnet", "rsh-client"}},
+	}
+	plan, err := service.Plan(state)
+	require.NoError(t, err)
+	require.True(t, plan.Valid(), plan.Problems)
+
+	results, err := service.Apply(plan)
+
+	require.NoError(t, err)
+	require.Len(t, results, 8)
+	assert.Equal(t, []string{"fail2ban"}, packageRepo.InstalledRequest.Packages)
+	assert.Equal(t, 1, packageRepo.InstallCallCount)
+	userRepo.AssertExpectations(t)
+	assert.Equal(t, []string{"net.ipv4.ip_forward"}, stateRepo.SetKeys)
+	assert.Equal(t, map[string]string{"net.ipv4.ip_forward": "0"}, stateRepo.Written,
+		"keys the state no longer lists are dropped from the file")
+	assert.Equal(t, "ssh", firewallRepo.AddedRule.Description)
+	sshRepo.AssertExpectations(t)
+
+	// Both packages are removed by one failed run of the package manager
+	debloatRepo.AssertNumberOfCalls(t, "RemovePackages", 1)
+	for _, result := range results[:6] {
+		assert.NoError(t, result.Err, result.Change.Describe())
+	}
+	assert.EqualError(t, results[6].Err, "dpkg is locked")
+	assert.EqualError(t, results[7].Err, "dpkg is locked")
+}
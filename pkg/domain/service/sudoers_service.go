@@ -0,0 +1,278 @@
+// pkg/domain/service/sudoers_service.go
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// sudoAdminGroups are the groups distributions grant every command to by
+// default; granting them every command is not reported
+var sudoAdminGroups = []string{"%sudo", "%wheel", "%admin"}
+
+// SudoersService defines operations for auditing the sudo policy and
+// restricting users to a set of commands
+type SudoersService interface {
+	// AuditSudoers reports rules granting every command or a command that
+	// can start a root shell, and Defaults that turn off authentication
+	AuditSudoers() ([]model.SudoersFinding, error)
+
+	// PlanTemplate works out what restricting a user to a template changes,
+	// without changing anything
+	PlanTemplate(username, template string, noPassword bool) (*model.SudoTemplatePlan, error)
+
+	// ApplyTemplate installs the plan's sudoers drop-in, then removes the
+	// user from the groups the plan lists
+	ApplyTemplate(plan *model.SudoTemplatePlan) error
+}
+
+// SudoersServiceImpl implements SudoersService
+type SudoersServiceImpl struct {
+	repository SudoersRepository
+}
+
+// NewSudoersServiceImpl creates a new SudoersServiceImpl
+func NewSudoersServiceImpl(repository SudoersRepository) *SudoersServiceImpl {
+	return &SudoersServiceImpl{
+		repository: repository,
+	}
+}
+
+// SudoersRepository defines the repository operations needed by SudoersService
+type SudoersRepository interface {
+	ReadSudoers() ([]model.SudoersFile, error)
+	ReadUserSudoers(username string) ([]byte, error)
+	WriteUserSudoers(username string, content []byte) error
+	InstalledCommands(paths []string) []string
+	UserGroups(username string) ([]string, error)
+	RemoveFromGroup(username, group string) error
+}
+
+// policy parses every file sudo reads
+func (s *SudoersServiceImpl) policy() (*model.SudoersPolicy, error) {
+	files, err := s.repository.ReadSudoers()
+	if err != nil {
+		return nil, err
+	}
+	policy := &model.SudoersPolicy{}
+	for _, file := range files {
+		policy.Parse(file.Path, file.Data)
+	}
+	return policy, nil
+}
+
+// AuditSudoers skips rules for root alone. Every command without a password
+// is reported for anyone, every command with a password only for users and
+// groups other than the distribution's admin group. NOPASSWD: ALL is what
+// sudoNoPassword configures, so it is a medium finding that does not fail
+// the default audit. So is a command that can start a root shell, such as
+// systemctl or a package manager allowed any arguments.
+func (s *SudoersServiceImpl) AuditSudoers() ([]model.SudoersFinding, error) {
+	policy, err := s.policy()
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []model.SudoersFinding
+	for _, rule := range policy.Rules {
+		if len(rule.Users) == 1 && rule.Users[0] == "root" {
+			continue
+		}
+
+		all, noPassword := grantsAll(policy, rule)
+		shell := rootShell(policy, rule)
+		finding := model.SudoersFinding{
+			File:  rule.File,
+			Line:  rule.Line,
+			Users: rule.Users,
+			Text:  rule.Text,
+		}
+		who := strings.Join(rule.Users, ", ")
+		switch {
+		case noPassword:
+			finding.Issue = model.SudoersIssueNoPasswordAll
+			finding.Severity = model.SeverityMedium
+			finding.Detail = fmt.Sprintf("grants %s every command without a password", who)
+		case all && !onlyAdminGroups(rule.Users):
+			finding.Issue = model.SudoersIssueAllCommands
+			finding.Severity = model.SeverityLow
+			finding.Detail = fmt.Sprintf("grants %s every command", who)
+		case !all && shell != "":
+			finding.Issue = model.SudoersIssueRootShell
+			finding.Severity = model.SeverityMedium
+			finding.Detail = fmt.Sprintf("grants %s %s, which can start a root shell", who, shell)
+		default:
+			continue
+		}
+		findings = append(findings, finding)
+	}
+
+	for _, defaults := range policy.Defaults {
+		if strings.Contains(defaults.Text, "!authenticate") {
+			findings = append(findings, model.SudoersFinding{
+				File:     defaults.File,
+				Line:     defaults.Line,
+				Issue:    model.SudoersIssueNoAuthenticate,
+				Severity: model.SeverityHigh,
+				Text:     defaults.Text,
+				Detail:   "turns off authentication",
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+// PlanTemplate grants the template's commands whose program is installed
+// here, run as root, with noexec for those that could start a pager.
+// Defaults lines of the user's current drop-in, such as the
+// env_keep hardn adds, are kept; its rules are replaced. The user leaves
+// every group a rule grants every command to, since membership would
+// otherwise undo the restriction.
+func (s *SudoersServiceImpl) PlanTemplate(username, name string, noPassword bool) (*model.SudoTemplatePlan, error) {
+	if err := model.ValidateUsername(username); err != nil {
+		return nil, err
+	}
+	template, ok := model.FindSudoTemplate(name)
+	if !ok {
+		var names []string
+		for _, template := range model.SudoTemplates {
+			names = append(names, template.Name)
+		}
+		return nil, fmt.Errorf("unknown sudo template %q (valid: %s)", name, strings.Join(names, ", "))
+	}
+
+	installed := s.repository.InstalledCommands(template.Programs())
+	commands := template.CommandsFor(installed)
+	if len(commands) == 0 {
+		return nil, fmt.Errorf("none of the commands of the %s template are installed", template.Name)
+	}
+
+	groups, err := s.repository.UserGroups(username)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.policy()
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.repository.ReadUserSudoers(username)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &model.SudoTemplatePlan{
+		Username:   username,
+		Template:   template.Name,
+		Commands:   commands,
+		NoPassword: noPassword,
+	}
+
+	broad := make(map[string]bool)
+	dropIn := filepath.Join(model.SudoersDir, username)
+	for _, rule := range policy.Rules {
+		if all, _ := grantsAll(policy, rule); !all {
+			continue
+		}
+		for _, user := range rule.Users {
+			if strings.HasPrefix(user, "%") {
+				broad[strings.TrimPrefix(user, "%")] = true
+			} else if (user == username || user == "ALL") && rule.File != dropIn {
+				plan.OtherGrants = append(plan.OtherGrants, fmt.Sprintf("%s:%d: %s", rule.File, rule.Line, rule.Text))
+			}
+		}
+	}
+	for _, group := range groups {
+		if broad[group] {
+			plan.LeaveGroup = append(plan.LeaveGroup, group)
+		}
+	}
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "# Managed by hardn: sudo template %s\n", template.Name)
+	for _, line := range strings.Split(string(current), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "Defaults") || isNoExecDefaults(line) {
+			continue
+		}
+		content.WriteString(line + "\n")
+	}
+	for _, program := range installed {
+		if model.SudoNoExec(program) {
+			fmt.Fprintf(&content, "Defaults!%s noexec\n", program)
+		}
+	}
+	tag := ""
+	if noPassword {
+		tag = "NOPASSWD: "
+	}
+	fmt.Fprintf(&content, "%s ALL=(root) %s%s\n", username, tag, strings.Join(commands, ", "))
+	plan.Content = content.String()
+
+	return plan, nil
+}
+
+// ApplyTemplate writes the drop-in first, so a file visudo rejects leaves
+// the user's access as it was
+func (s *SudoersServiceImpl) ApplyTemplate(plan *model.SudoTemplatePlan) error {
+	if plan == nil {
+		return fmt.Errorf("no sudo template to apply")
+	}
+	if err := s.repository.WriteUserSudoers(plan.Username, []byte(plan.Content)); err != nil {
+		return err
+	}
+	for _, group := range plan.LeaveGroup {
+		if err := s.repository.RemoveFromGroup(plan.Username, group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// grantsAll reports whether a rule grants every command, and whether it
+// does so without a password
+func grantsAll(policy *model.SudoersPolicy, rule model.SudoRule) (all bool, noPassword bool) {
+	for _, command := range rule.Commands {
+		if policy.GrantsAll(command.Command) {
+			all = true
+			noPassword = noPassword || command.NoPassword
+		}
+	}
+	return all, noPassword
+}
+
+// rootShell returns the first command of a rule that can start a root
+// shell, or ""
+func rootShell(policy *model.SudoersPolicy, rule model.SudoRule) string {
+	for _, command := range rule.Commands {
+		if found := policy.RootShell(command.Command); found != "" {
+			return found
+		}
+	}
+	return ""
+}
+
+// isNoExecDefaults reports whether a Defaults line is one a template
+// writes, which a new plan replaces
+func isNoExecDefaults(line string) bool {
+	return strings.HasPrefix(line, "Defaults!") && strings.HasSuffix(line, " noexec")
+}
+
+// onlyAdminGroups reports whether a rule names only admin groups
+func onlyAdminGroups(users []string) bool {
+	for _, user := range users {
+		admin := false
+		for _, group := range sudoAdminGroups {
+			if user == group {
+				admin = true
+			}
+		}
+		if !admin {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSudoersRepository is a mock implementation of SudoersRepository
+type MockSudoersRepository struct {
+	mock.Mock
+}
+
+func (m *MockSudoersRepository) ReadSudoers() ([]model.SudoersFile, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.SudoersFile), args.Error(1)
+}
+
+func (m *MockSudoersRepository) ReadUserSudoers(username string) ([]byte, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockSudoersRepository) WriteUserSudoers(username string, content []byte) error {
+	args := m.Called(username, content)
+	return args.Error(0)
+}
+
+func (m *MockSudoersRepository) InstalledCommands(paths []string) []string {
+	args := m.Called(paths)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).([]string)
+}
+
+func (m *MockSudoersRepository) UserGroups(username string) ([]string, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockSudoersRepository) RemoveFromGroup(username, group string) error {
+	args := m.Called(username, group)
+	return args.Error(0)
+}
+
+// testSudoers is a stock Debian sudoers file with the drop-ins hardn writes
+var testSudoers = []model.SudoersFile{
+	{Path: "/etc/sudoers", Data: []byte(`# This file MUST be edited with the 'visudo' command as root.
+Defaults	env_reset
+Defaults	secure_path="/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+Cmnd_Alias EVERYTHING = ALL
+root	ALL=(ALL:ALL) ALL
+%sudo	ALL=(ALL:ALL) ALL
+backup	ALL=(root) NOPASSWD: /usr/bin/rsync, \
+	/usr/bin/tar
+@includedir /etc/sudoers.d
+`)},
+	{Path: "/etc/sudoers.d/george", Data: []byte("Defaults:george env_keep += \"HARDN_CONFIG\"\ngeorge ALL=(ALL) NOPASSWD: ALL\n")},
+	{Path: "/etc/sudoers.d/deploy", Data: []byte("deploy ALL=(ALL) ALL\n")},
+	{Path: "/etc/sudoers.d/ops", Data: []byte("%ops ALL=(ALL) /usr/bin/systemctl, PASSWD: EVERYTHING\nDefaults:ci !authenticate\n")},
+}
+
+func TestSudoersServiceImpl_AuditSudoers(t *testing.T) {
+	mockRepo := new(MockSudoersRepository)
+	mockRepo.On("ReadSudoers").Return(testSudoers, nil)
+
+	findings, err := NewSudoersServiceImpl(mockRepo).AuditSudoers()
+
+	assert.NoError(t, err)
+	if assert.Len(t, findings, 4) {
+		assert.Equal(t, model.SudoersFinding{
+			File:     "/etc/sudoers.d/george",
+			Line:     2,
+			Users:    []string{"george"},
+			Issue:    model.SudoersIssueNoPasswordAll,
+			Severity: model.SeverityMedium,
+			Text:     "george ALL=(ALL) NOPASSWD: ALL",
+			Detail:   "grants george every command without a password",
+		}, findings[0])
+		assert.Equal(t, model.SudoersIssueAllCommands, findings[1].Issue)
+		assert.Equal(t, "/etc/sudoers.d/deploy", findings[1].File)
+		assert.Equal(t, "grants %ops every command", findings[2].Detail, "a Cmnd_Alias of ALL grants every command")
+		assert.Equal(t, model.SudoersIssueNoAuthenticate, findings[3].Issue)
+		assert.Equal(t, model.SeverityHigh, findings[3].Severity)
+	}
+
+	violation := findings[0].Violation()
+	assert.Equal(t, "sudoers.nopasswd_all", violation.Rule)
+	assert.Contains(t, violation.Message, "/etc/sudoers.d/george:2:")
+}
+
+func TestSudoersServiceImpl_AuditSudoers_RootShell(t *testing.T) {
+	tests := []struct {
+		rule     string
+		detected string
+	}{
+		{"ops ALL=(root) /usr/bin/systemctl", "/usr/bin/systemctl"},
+		{"ops ALL=(root) /usr/bin/systemctl *", "/usr/bin/systemctl *"},
+		{"ops ALL=(root) /usr/bin/systemctl --no-pager *", "/usr/bin/systemctl --no-pager *"},
+		{"ops ALL=(root) /usr/bin/systemctl --no-pager restart *", ""},
+		{"ops ALL=(root) /usr/bin/journalctl -u *", "/usr/bin/journalctl -u *"},
+		{"ops ALL=(root) /usr/bin/journalctl --no-pager *", ""},
+		{"ops ALL=(root) /usr/bin/apt-get install *", "/usr/bin/apt-get install *"},
+		{"ops ALL=(root) /usr/bin/apt-get update", ""},
+		{"ops ALL=(root) /usr/bin/dpkg", "/usr/bin/dpkg"},
+		{"ops ALL=(root) /usr/bin/less /var/log/syslog", "/usr/bin/less /var/log/syslog"},
+		{"ops ALL=(root) /usr/bin/rsync, /usr/bin/tar", ""},
+		{"Cmnd_Alias PKG = /usr/bin/dnf, /usr/bin/yum\nops ALL=(root) PKG", "/usr/bin/dnf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			mockRepo := new(MockSudoersRepository)
+			mockRepo.On("ReadSudoers").Return([]model.SudoersFile{
+				{Path: "/etc/sudoers.d/ops", Data: []byte(tt.rule + "\n")},
+			}, nil)
+
+			findings, err := NewSudoersServiceImpl(mockRepo).AuditSudoers()
+
+			assert.NoError(t, err)
+			if tt.detected == "" {
+				assert.Empty(t, findings)
+				return
+			}
+			if assert.Len(t, findings, 1) {
+				assert.Equal(t, model.SudoersIssueRootShell, findings[0].Issue)
+				assert.Equal(t, model.SeverityMedium, findings[0].Severity)
+				assert.Equal(t, "grants ops "+tt.detected+", which can start a root shell", findings[0].Detail)
+			}
+		})
+	}
+}
+
+func TestSudoTemplates_NoRootShell(t *testing.T) {
+	policy := &model.SudoersPolicy{}
+	for _, template := range model.SudoTemplates {
+		for _, command := range template.Commands {
+			assert.Empty(t, policy.RootShell(command), "template %s", template.Name)
+		}
+	}
+}
+
+func TestSudoersServiceImpl_PlanTemplate(t *testing.T) {
+	mockRepo := new(MockSudoersRepository)
+	service := NewSudoersServiceImpl(mockRepo)
+
+	mockRepo.On("InstalledCommands", mock.Anything).Return([]string{"/usr/bin/systemctl", "/usr/bin/journalctl"})
+	mockRepo.On("UserGroups", "george").Return([]string{"george", "sudo", "ops", "docker"}, nil)
+	mockRepo.On("ReadSudoers").Return(testSudoers, nil)
+	mockRepo.On("ReadUserSudoers", "george").Return([]byte("Defaults:george env_keep += \"HARDN_CONFIG\"\ngeorge ALL=(ALL) NOPASSWD: ALL\n"), nil)
+
+	plan, err := service.PlanTemplate("george", "services", false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "# Managed by hardn: sudo template services\n"+
+		"Defaults:george env_keep += \"HARDN_CONFIG\"\n"+
+		"Defaults!/usr/bin/systemctl noexec\n"+
+		"Defaults!/usr/bin/journalctl noexec\n"+
+		"george ALL=(root) /usr/bin/systemctl --no-pager status *, /usr/bin/systemctl --no-pager start *, "+
+		"/usr/bin/systemctl --no-pager stop *, /usr/bin/systemctl --no-pager restart *, "+
+		"/usr/bin/systemctl --no-pager reload *, /usr/bin/journalctl --no-pager *\n", plan.Content)
+	assert.Equal(t, []string{"sudo", "ops"}, plan.LeaveGroup)
+	assert.Empty(t, plan.OtherGrants, "the user's own drop-in is replaced")
+
+	plan, err = service.PlanTemplate("george", "services", true)
+	assert.NoError(t, err)
+	assert.Contains(t, plan.Content, "george ALL=(root) NOPASSWD: /usr/bin/systemctl --no-pager status *")
+
+	// Applying the template again does not repeat the noexec Defaults
+	mockRepo.ExpectedCalls = nil
+	mockRepo.On("InstalledCommands", mock.Anything).Return([]string{"/usr/bin/systemctl"})
+	mockRepo.On("UserGroups", "george").Return([]string{"george"}, nil)
+	mockRepo.On("ReadSudoers").Return(testSudoers, nil)
+	mockRepo.On("ReadUserSudoers", "george").Return([]byte(plan.Content), nil)
+	plan, err = service.PlanTemplate("george", "services", false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(plan.Content, "Defaults!/usr/bin/systemctl noexec\n"))
+	assert.NotContains(t, plan.Content, "journalctl")
+}
+
+func TestSudoersServiceImpl_PlanTemplate_OtherGrants(t *testing.T) {
+	mockRepo := new(MockSudoersRepository)
+	service := NewSudoersServiceImpl(mockRepo)
+
+	mockRepo.On("InstalledCommands", mock.Anything).Return([]string{"/usr/bin/apt-get"})
+	mockRepo.On("UserGroups", "deploy").Return([]string{"deploy"}, nil)
+	mockRepo.On("ReadSudoers").Return([]model.SudoersFile{
+		{Path: "/etc/sudoers", Data: []byte("deploy, backup ALL=(ALL) ALL\n")},
+	}, nil)
+	mockRepo.On("ReadUserSudoers", "deploy").Return(nil, nil)
+
+	plan, err := service.PlanTemplate("deploy", "packages", false)
+
+	assert.NoError(t, err)
+	assert.Empty(t, plan.LeaveGroup)
+	assert.Equal(t, []string{"/etc/sudoers:1: deploy, backup ALL=(ALL) ALL"}, plan.OtherGrants)
+	assert.Equal(t, []string{"/usr/bin/apt-get update", "/usr/bin/apt-get upgrade"}, plan.Commands)
+	assert.NotContains(t, plan.Content, "noexec", "package managers run maintainer scripts")
+}
+
+func TestSudoersServiceImpl_PlanTemplate_Invalid(t *testing.T) {
+	mockRepo := new(MockSudoersRepository)
+	service := NewSudoersServiceImpl(mockRepo)
+
+	_, err := service.PlanTemplate("root", "services", false)
+	assert.ErrorContains(t, err, "reserved system username")
+
+	_, err = service.PlanTemplate("george", "everything", false)
+	assert.EqualError(t, err, `unknown sudo template "everything" (valid: services, packages, operator)`)
+
+	mockRepo.On("InstalledCommands", mock.Anything).Return(nil)
+	_, err = service.PlanTemplate("george", "packages", false)
+	assert.EqualError(t, err, "none of the commands of the packages template are installed")
+}
+
+func TestSudoersServiceImpl_ApplyTemplate(t *testing.T) {
+	plan := &model.SudoTemplatePlan{
+		Username:   "george",
+		Template:   "services",
+		Content:    "george ALL=(root) /usr/bin/systemctl\n",
+		LeaveGroup: []string{"sudo"},
+	}
+
+	t.Run("writes then leaves groups", func(t *testing.T) {
+		mockRepo := new(MockSudoersRepository)
+		mockRepo.On("WriteUserSudoers", "george", []byte(plan.Content)).Return(nil)
+		mockRepo.On("RemoveFromGroup", "george", "sudo").Return(nil)
+
+		assert.NoError(t, NewSudoersServiceImpl(mockRepo).ApplyTemplate(plan))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejected file keeps groups", func(t *testing.T) {
+		mockRepo := new(MockSudoersRepository)
+		mockRepo.On("WriteUserSudoers", "george", mock.Anything).Return(errors.New("invalid sudoers configuration"))
+
+		err := NewSudoersServiceImpl(mockRepo).ApplyTemplate(plan)
+
+		assert.EqualError(t, err, "invalid sudoers configuration")
+		mockRepo.AssertNotCalled(t, "RemoveFromGroup", mock.Anything, mock.Anything)
+	})
+}
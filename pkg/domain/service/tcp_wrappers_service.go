@@ -0,0 +1,392 @@
+// pkg/domain/service/tcp_wrappers_service.go
+package service
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// TCPWrappersService defines operations for the legacy TCP wrappers files
+type TCPWrappersService interface {
+	// Check reads /etc/hosts.allow and /etc/hosts.deny, compares them with
+	// the firewall's rules, written as ufw commands, and plans firewall
+	// rules expressing the same intent
+	Check(firewallRules []string, sshPort int) (*model.TCPWrappersReport, error)
+
+	// Disable comments out the rules of both files
+	Disable() error
+}
+
+// TCPWrappersServiceImpl implements TCPWrappersService
+type TCPWrappersServiceImpl struct {
+	repository TCPWrappersRepository
+}
+
+// NewTCPWrappersServiceImpl creates a new TCPWrappersServiceImpl
+func NewTCPWrappersServiceImpl(repository TCPWrappersRepository) *TCPWrappersServiceImpl {
+	return &TCPWrappersServiceImpl{
+		repository: repository,
+	}
+}
+
+// TCPWrappersRepository defines the repository operations needed by
+// TCPWrappersService
+type TCPWrappersRepository interface {
+	GetHostsAccessRules() ([]model.HostsAccessRule, []model.HostsAccessRule, error)
+	DaemonUsesLibwrap(daemon string) (bool, bool)
+	DisableHostsAccess() error
+}
+
+// wrappedDaemonPorts are the ports of daemons commonly run under TCP
+// wrappers; sshd uses the configured SSH port
+var wrappedDaemonPorts = map[string][]string{
+	"vsftpd":     {"21/tcp"},
+	"proftpd":    {"21/tcp"},
+	"in.ftpd":    {"21/tcp"},
+	"in.telnetd": {"23/tcp"},
+	"sendmail":   {"25/tcp"},
+	"in.tftpd":   {"69/udp"},
+	"rpcbind":    {"111/tcp", "111/udp"},
+	"portmap":    {"111/tcp", "111/udp"},
+	"snmpd":      {"161/udp"},
+}
+
+// daemonPort is a port a wrapped daemon listens on
+type daemonPort struct {
+	port     int
+	protocol string
+}
+
+func (p daemonPort) String() string {
+	return fmt.Sprintf("%d/%s", p.port, p.protocol)
+}
+
+func (s *TCPWrappersServiceImpl) Check(firewallRules []string, sshPort int) (*model.TCPWrappersReport, error) {
+	allow, deny, err := s.repository.GetHostsAccessRules()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TCP wrappers rules: %w", err)
+	}
+
+	report := &model.TCPWrappersReport{Allow: allow, Deny: deny}
+	plan := &wrappersPlan{
+		report:   report,
+		existing: make(map[string]bool),
+		closing:  make(map[string]bool),
+		sshPort:  sshPort,
+	}
+	for _, rule := range firewallRules {
+		plan.existing[rule] = true
+	}
+
+	// A daemon is on an allow list when hosts.deny denies it to everyone;
+	// hosts.allow entries then name the only clients let in
+	denied := make(map[string]bool)
+	allowed := make(map[string]bool)
+	for _, rule := range deny {
+		if isAllClients(rule.Clients) && rule.Options == "" {
+			for _, daemon := range rule.Daemons {
+				denied[daemon] = true
+			}
+		}
+	}
+	for _, rule := range allow {
+		for _, daemon := range rule.Daemons {
+			allowed[daemon] = true
+		}
+	}
+
+	for _, rule := range append(append([]model.HostsAccessRule{}, allow...), deny...) {
+		s.checkEnforced(report, rule)
+	}
+	for _, rule := range allow {
+		plan.allowRule(rule, denied)
+	}
+	for _, rule := range deny {
+		plan.denyRule(rule, allowed)
+	}
+	return report, nil
+}
+
+func (s *TCPWrappersServiceImpl) Disable() error {
+	return s.repository.DisableHostsAccess()
+}
+
+// checkEnforced reports rules for installed daemons that do not use
+// libwrap and so ignore them
+func (s *TCPWrappersServiceImpl) checkEnforced(report *model.TCPWrappersReport, rule model.HostsAccessRule) {
+	for _, daemon := range rule.Daemons {
+		if daemon == "ALL" {
+			continue
+		}
+		if uses, found := s.repository.DaemonUsesLibwrap(daemon); found && !uses {
+			report.Conflicts = append(report.Conflicts, model.HostsAccessIssue{
+				Rule:   rule,
+				Reason: fmt.Sprintf("%s is not built with TCP wrappers support, so this rule is ignored", daemon),
+			})
+		}
+	}
+}
+
+// wrappersPlan collects the firewall rules a report plans
+type wrappersPlan struct {
+	report   *model.TCPWrappersReport
+	existing map[string]bool
+	closing  map[string]bool
+	sshPort  int
+}
+
+// allowRule plans the firewall rules for a hosts.allow rule. The rule only
+// narrows access when hosts.deny denies its daemons to everyone.
+func (p *wrappersPlan) allowRule(rule model.HostsAccessRule, denied map[string]bool) {
+	ports, reason := p.rulePorts(rule)
+	if reason == "" {
+		reason = unsupportedOptions(rule)
+	}
+	sources, err := clientNetworks(rule.Clients)
+	if reason == "" && err != nil {
+		reason = err.Error()
+	}
+	if reason != "" {
+		p.skip(rule, reason)
+		return
+	}
+
+	for _, daemon := range rule.Daemons {
+		if !denied[daemon] && !denied["ALL"] {
+			p.conflict(rule, fmt.Sprintf("hosts.deny does not deny %s, so this rule restricts nothing", daemon))
+			return
+		}
+	}
+
+	for _, port := range ports {
+		for _, source := range sources {
+			p.add(model.FirewallRule{Action: "allow", Protocol: port.protocol, Port: port.port, SourceIP: source,
+				Description: "Migrated from " + rule.File})
+		}
+		if len(sources) > 0 && sources[0] != "" {
+			p.narrow(rule, port, fmt.Sprintf("TCP wrappers limit %s to %s",
+				strings.Join(rule.Daemons, ", "), strings.Join(rule.Clients, ", ")))
+		}
+	}
+}
+
+// denyRule plans the firewall rules for a hosts.deny rule. Denying every
+// client closes the daemon's ports unless hosts.allow lets some clients in;
+// denying single clients while a port is open depends on the order of the
+// firewall's rules and is left to the administrator.
+func (p *wrappersPlan) denyRule(rule model.HostsAccessRule, allowed map[string]bool) {
+	if isAllClients(rule.Clients) && rule.Options == "" {
+		for _, daemon := range rule.Daemons {
+			if daemon == "ALL" || allowed[daemon] || allowed["ALL"] {
+				continue
+			}
+			ports, reason := p.daemonPorts(daemon)
+			if reason != "" {
+				p.skip(rule, reason)
+				continue
+			}
+			for _, port := range ports {
+				p.narrow(rule, port, "hosts.deny denies "+daemon+" to every client")
+			}
+		}
+		return
+	}
+
+	ports, reason := p.rulePorts(rule)
+	if reason == "" {
+		reason = unsupportedOptions(rule)
+	}
+	if _, err := clientNetworks(rule.Clients); reason == "" && err != nil {
+		reason = err.Error()
+	}
+	if reason != "" {
+		p.skip(rule, reason)
+		return
+	}
+
+	for _, port := range ports {
+		if p.openToAll(port) {
+			p.skip(rule, fmt.Sprintf("the firewall allows %s from anywhere; denying single clients needs a rule ahead of it", port))
+			return
+		}
+	}
+	// Otherwise the firewall's default deny already blocks these clients
+}
+
+// narrow plans closing a port the firewall opens to everyone while the TCP
+// wrappers rules limit it. The SSH port stays open so the migration cannot
+// lock out the current session.
+func (p *wrappersPlan) narrow(rule model.HostsAccessRule, port daemonPort, limit string) {
+	if !p.openToAll(port) {
+		return
+	}
+
+	reason := fmt.Sprintf("the firewall allows %s from anywhere, while %s", port, limit)
+	if port.port == p.sshPort && port.protocol == "tcp" {
+		p.conflict(rule, reason+"; remove the SSH rule by hand once the migrated rules work")
+		return
+	}
+	p.conflict(rule, reason)
+
+	if !p.closing[port.String()] {
+		p.closing[port.String()] = true
+		p.report.Close = append(p.report.Close, model.FirewallRule{Action: "allow", Protocol: port.protocol, Port: port.port})
+	}
+}
+
+// openToAll reports whether the firewall allows port from any address
+func (p *wrappersPlan) openToAll(port daemonPort) bool {
+	return p.existing["allow "+port.String()] || p.existing["allow "+strconv.Itoa(port.port)]
+}
+
+// add plans a firewall rule unless the firewall has it already
+func (p *wrappersPlan) add(rule model.FirewallRule) {
+	text := fmt.Sprintf("%s %d/%s", rule.Action, rule.Port, rule.Protocol)
+	if rule.SourceIP != "" {
+		text += " from " + rule.SourceIP
+	}
+	if p.existing[text] {
+		return
+	}
+	p.existing[text] = true
+	p.report.Add = append(p.report.Add, rule)
+}
+
+func (p *wrappersPlan) skip(rule model.HostsAccessRule, reason string) {
+	p.report.Skipped = append(p.report.Skipped, model.HostsAccessIssue{Rule: rule, Reason: reason})
+}
+
+func (p *wrappersPlan) conflict(rule model.HostsAccessRule, reason string) {
+	p.report.Conflicts = append(p.report.Conflicts, model.HostsAccessIssue{Rule: rule, Reason: reason})
+}
+
+// rulePorts returns the ports of every daemon of a rule
+func (p *wrappersPlan) rulePorts(rule model.HostsAccessRule) ([]daemonPort, string) {
+	var ports []daemonPort
+	for _, daemon := range rule.Daemons {
+		daemonPorts, reason := p.daemonPorts(daemon)
+		if reason != "" {
+			return nil, reason
+		}
+		ports = append(ports, daemonPorts...)
+	}
+	return ports, ""
+}
+
+// daemonPorts returns the ports a daemon listens on
+func (p *wrappersPlan) daemonPorts(daemon string) ([]daemonPort, string) {
+	if daemon == "sshd" {
+		return []daemonPort{{port: p.sshPort, protocol: "tcp"}}, ""
+	}
+	if daemon == "ALL" || strings.ContainsAny(daemon, "@*?") || daemon == "EXCEPT" {
+		return nil, fmt.Sprintf("%q does not name a single daemon", daemon)
+	}
+
+	specs, ok := wrappedDaemonPorts[daemon]
+	if !ok {
+		return nil, fmt.Sprintf("the port of %s is not known", daemon)
+	}
+	var ports []daemonPort
+	for _, spec := range specs {
+		port, protocol, err := ParsePortSpec(spec)
+		if err != nil {
+			return nil, err.Error()
+		}
+		ports = append(ports, daemonPort{port: port, protocol: protocol})
+	}
+	return ports, ""
+}
+
+// unsupportedOptions explains why a rule's options have no firewall
+// equivalent
+func unsupportedOptions(rule model.HostsAccessRule) string {
+	if rule.Options == "" {
+		return ""
+	}
+	return fmt.Sprintf("options %q have no firewall equivalent", rule.Options)
+}
+
+// isAllClients reports whether a client list matches every client
+func isAllClients(clients []string) bool {
+	return len(clients) == 1 && clients[0] == "ALL"
+}
+
+// clientNetworks converts a client list to firewall sources; "ALL" becomes
+// the empty source. Host names, wildcards, netgroups and EXCEPT lists have
+// no firewall equivalent.
+func clientNetworks(clients []string) ([]string, error) {
+	if isAllClients(clients) {
+		return []string{""}, nil
+	}
+
+	var sources []string
+	for _, client := range clients {
+		source, ok := clientNetwork(client)
+		if !ok {
+			return nil, fmt.Errorf("client %q is not an address or network", client)
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// clientNetwork converts a hosts_access client pattern to an address or
+// CIDR network: "10.0.0.5", "192.168.1." for 192.168.1.0/24,
+// "10.0.0.0/255.0.0.0", "10.0.0.0/8" and "[2001:db8::]/32"
+func clientNetwork(client string) (string, bool) {
+	address, mask, hasMask := strings.Cut(client, "/")
+	if strings.HasPrefix(address, "[") && strings.HasSuffix(address, "]") {
+		address = strings.Trim(address, "[]")
+		if net.ParseIP(address) == nil {
+			return "", false
+		}
+		if !hasMask {
+			return address, true
+		}
+		_, network, err := net.ParseCIDR(address + "/" + mask)
+		if err != nil {
+			return "", false
+		}
+		return network.String(), true
+	}
+
+	if !hasMask && strings.HasSuffix(address, ".") {
+		octets := strings.Split(strings.TrimSuffix(address, "."), ".")
+		prefix := 8 * len(octets)
+		if len(octets) > 3 {
+			return "", false
+		}
+		for len(octets) < 4 {
+			octets = append(octets, "0")
+		}
+		_, network, err := net.ParseCIDR(strings.Join(octets, ".") + "/" + strconv.Itoa(prefix))
+		if err != nil {
+			return "", false
+		}
+		return network.String(), true
+	}
+
+	ip := net.ParseIP(address).To4()
+	if ip == nil {
+		return "", false
+	}
+	if !hasMask {
+		return ip.String(), true
+	}
+	if netmask := net.ParseIP(mask).To4(); netmask != nil {
+		ones, bits := net.IPMask(netmask).Size()
+		if bits == 0 {
+			return "", false
+		}
+		mask = strconv.Itoa(ones)
+	}
+	_, network, err := net.ParseCIDR(ip.String() + "/" + mask)
+	if err != nil {
+		return "", false
+	}
+	return network.String(), true
+}
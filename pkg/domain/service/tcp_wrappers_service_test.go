@@ -0,0 +1,132 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTCPWrappersRepository is a mock implementation of TCPWrappersRepository
+type MockTCPWrappersRepository struct {
+	mock.Mock
+}
+
+func (m *MockTCPWrappersRepository) GetHostsAccessRules() ([]model.HostsAccessRule, []model.HostsAccessRule, error) {
+	args := m.Called()
+	return args.Get(0).([]model.HostsAccessRule), args.Get(1).([]model.HostsAccessRule), args.Error(2)
+}
+
+func (m *MockTCPWrappersRepository) DaemonUsesLibwrap(daemon string) (bool, bool) {
+	args := m.Called(daemon)
+	return args.Bool(0), args.Bool(1)
+}
+
+func (m *MockTCPWrappersRepository) DisableHostsAccess() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func hostsRule(file string, line int, daemons []string, clients ...string) model.HostsAccessRule {
+	return model.HostsAccessRule{File: file, Line: line, Daemons: daemons, Clients: clients}
+}
+
+func TestTCPWrappersServiceImpl_Check(t *testing.T) {
+	t.Run("allow list becomes firewall rules", func(t *testing.T) {
+		mockRepo := new(MockTCPWrappersRepository)
+		mockRepo.On("GetHostsAccessRules").Return(
+			[]model.HostsAccessRule{
+				hostsRule("/etc/hosts.allow", 3, []string{"sshd"}, "192.168.1.", "[2001:db8::]/32"),
+				hostsRule("/etc/hosts.allow", 4, []string{"vsftpd"}, "10.0.0.0/255.0.0.0"),
+			},
+			[]model.HostsAccessRule{hostsRule("/etc/hosts.deny", 1, []string{"ALL"}, "ALL")},
+			nil)
+		mockRepo.On("DaemonUsesLibwrap", "sshd").Return(false, true)
+		mockRepo.On("DaemonUsesLibwrap", "vsftpd").Return(true, true)
+
+		service := NewTCPWrappersServiceImpl(mockRepo)
+		report, err := service.Check([]string{"allow 2222/tcp", "allow 21/tcp"}, 2222)
+
+		require.NoError(t, err)
+		assert.True(t, report.InUse())
+		assert.Equal(t, []model.FirewallRule{
+			{Action: "allow", Protocol: "tcp", Port: 2222, SourceIP: "192.168.1.0/24", Description: "Migrated from /etc/hosts.allow"},
+			{Action: "allow", Protocol: "tcp", Port: 2222, SourceIP: "2001:db8::/32", Description: "Migrated from /etc/hosts.allow"},
+			{Action: "allow", Protocol: "tcp", Port: 21, SourceIP: "10.0.0.0/8", Description: "Migrated from /etc/hosts.allow"},
+		}, report.Add)
+
+		// The SSH port is never closed
+		assert.Equal(t, []model.FirewallRule{{Action: "allow", Protocol: "tcp", Port: 21}}, report.Close)
+		assert.Empty(t, report.Skipped)
+
+		require.Len(t, report.Conflicts, 3)
+		assert.Contains(t, report.Conflicts[0].Reason, "sshd is not built with TCP wrappers support")
+		assert.Contains(t, report.Conflicts[1].Reason, "remove the SSH rule by hand")
+		assert.Contains(t, report.Conflicts[2].Reason, "the firewall allows 21/tcp from anywhere")
+	})
+
+	t.Run("rules without a firewall equivalent are skipped", func(t *testing.T) {
+		mockRepo := new(MockTCPWrappersRepository)
+		mockRepo.On("GetHostsAccessRules").Return(
+			[]model.HostsAccessRule{
+				hostsRule("/etc/hosts.allow", 1, []string{"sshd"}, ".example.com"),
+				hostsRule("/etc/hosts.allow", 2, []string{"ALL"}, "LOCAL"),
+				hostsRule("/etc/hosts.allow", 3, []string{"in.telnetd"}, "10.0.0.5"),
+			},
+			[]model.HostsAccessRule{hostsRule("/etc/hosts.deny", 1, []string{"sshd"}, "203.0.113.7")},
+			nil)
+		mockRepo.On("DaemonUsesLibwrap", mock.Anything).Return(false, false)
+
+		service := NewTCPWrappersServiceImpl(mockRepo)
+		report, err := service.Check([]string{"allow 22/tcp"}, 22)
+
+		require.NoError(t, err)
+		assert.Empty(t, report.Add)
+		require.Len(t, report.Skipped, 3)
+		assert.Contains(t, report.Skipped[0].Reason, `client ".example.com" is not an address or network`)
+		assert.Contains(t, report.Skipped[1].Reason, `"ALL" does not name a single daemon`)
+		assert.Contains(t, report.Skipped[2].Reason, "denying single clients needs a rule ahead of it")
+
+		// Without a hosts.deny entry for in.telnetd, its allow rule is moot
+		require.Len(t, report.Conflicts, 1)
+		assert.Contains(t, report.Conflicts[0].Reason, "restricts nothing")
+	})
+
+	t.Run("no rules", func(t *testing.T) {
+		mockRepo := new(MockTCPWrappersRepository)
+		mockRepo.On("GetHostsAccessRules").Return([]model.HostsAccessRule(nil), []model.HostsAccessRule(nil), nil)
+
+		service := NewTCPWrappersServiceImpl(mockRepo)
+		report, err := service.Check(nil, 22)
+
+		require.NoError(t, err)
+		assert.False(t, report.InUse())
+	})
+}
+
+func TestClientNetwork(t *testing.T) {
+	tests := []struct {
+		client   string
+		expected string
+		ok       bool
+	}{
+		{"10.0.0.5", "10.0.0.5", true},
+		{"192.168.", "192.168.0.0/16", true},
+		{"10.0.0.0/255.255.0.0", "10.0.0.0/16", true},
+		{"172.16.5.0/24", "172.16.5.0/24", true},
+		{"[fe80::1]", "fe80::1", true},
+		{"[2001:db8::]/48", "2001:db8::/48", true},
+		{"10.0.0.0/255.0.255.0", "", false},
+		{"host.example.com", "", false},
+		{"@trusted", "", false},
+		{"KNOWN", "", false},
+	}
+
+	for _, tc := range tests {
+		network, ok := clientNetwork(tc.client)
+		assert.Equal(t, tc.ok, ok, tc.client)
+		assert.Equal(t, tc.expected, network, tc.client)
+	}
+}
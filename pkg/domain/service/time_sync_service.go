@@ -0,0 +1,106 @@
+// pkg/domain/service/time_sync_service.go
+package service
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// hostNamePattern matches a host name or IP address; the hosts end up in
+// daemon configurations, systemd drop-ins and shell assignments
+var hostNamePattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9.:-]*[A-Za-z0-9])?$`)
+
+// TimeSyncService defines operations for time synchronization
+type TimeSyncService interface {
+	// EffectivePolicy picks the daemon and fills in the default servers
+	EffectivePolicy(policy model.TimeSyncPolicy) (model.TimeSyncPolicy, error)
+
+	// ApplyTimeSync validates the policy and sets up its daemon
+	ApplyTimeSync(policy model.TimeSyncPolicy) error
+
+	// GetTimeSyncStatus reports the daemon running and the clock
+	GetTimeSyncStatus() (*model.TimeSyncStatus, error)
+}
+
+// TimeSyncServiceImpl implements TimeSyncService
+type TimeSyncServiceImpl struct {
+	repository TimeSyncRepository
+	osInfo     model.OSInfo
+}
+
+// NewTimeSyncServiceImpl creates a new TimeSyncServiceImpl
+func NewTimeSyncServiceImpl(repository TimeSyncRepository, osInfo model.OSInfo) *TimeSyncServiceImpl {
+	return &TimeSyncServiceImpl{
+		repository: repository,
+		osInfo:     osInfo,
+	}
+}
+
+// TimeSyncRepository defines the repository operations needed by TimeSyncService
+type TimeSyncRepository interface {
+	ConfigureTimeSync(policy model.TimeSyncPolicy) error
+	GetTimeSyncStatus() (*model.TimeSyncStatus, error)
+}
+
+// ValidateTimeServer checks that a time server is a host name or address
+func ValidateTimeServer(server string) error {
+	if !hostNamePattern.MatchString(server) {
+		return fmt.Errorf("invalid time server %q", server)
+	}
+	return nil
+}
+
+func (s *TimeSyncServiceImpl) EffectivePolicy(policy model.TimeSyncPolicy) (model.TimeSyncPolicy, error) {
+	if len(policy.Servers) == 0 {
+		policy.Servers = model.DefaultTimeServers
+	}
+
+	osType := s.osInfo.Type
+	switch policy.Daemon {
+	case "":
+		// Only chrony speaks NTS, and the RHEL family ships nothing else
+		switch {
+		case policy.NTS || model.IsRHELFamily(osType):
+			policy.Daemon = model.TimeSyncChrony
+		case osType == "alpine":
+			policy.Daemon = model.TimeSyncNTPD
+		default:
+			policy.Daemon = model.TimeSyncTimesyncd
+		}
+	case model.TimeSyncChrony:
+	case model.TimeSyncTimesyncd:
+		if osType == "alpine" {
+			return policy, fmt.Errorf("systemd-timesyncd needs systemd, which Alpine Linux does not run")
+		}
+	case model.TimeSyncNTPD:
+		if osType != "alpine" {
+			return policy, fmt.Errorf("ntpd is configured on Alpine Linux only; use chrony or timesyncd")
+		}
+	default:
+		return policy, fmt.Errorf("unknown time synchronization daemon %q (expected one of %v)", policy.Daemon, model.TimeSyncDaemons)
+	}
+
+	if policy.NTS && policy.Daemon != model.TimeSyncChrony {
+		return policy, fmt.Errorf("NTS needs chrony; %s does not support it", policy.Daemon)
+	}
+	return policy, nil
+}
+
+func (s *TimeSyncServiceImpl) ApplyTimeSync(policy model.TimeSyncPolicy) error {
+	policy, err := s.EffectivePolicy(policy)
+	if err != nil {
+		return err
+	}
+	for _, server := range policy.Servers {
+		if err := ValidateTimeServer(server); err != nil {
+			return err
+		}
+	}
+	return s.repository.ConfigureTimeSync(policy)
+}
+
+func (s *TimeSyncServiceImpl) GetTimeSyncStatus() (*model.TimeSyncStatus, error) {
+	return s.repository.GetTimeSyncStatus()
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTimeSyncRepository is a mock implementation of TimeSyncRepository
+type MockTimeSyncRepository struct {
+	mock.Mock
+}
+
+func (m *MockTimeSyncRepository) ConfigureTimeSync(policy model.TimeSyncPolicy) error {
+	args := m.Called(policy)
+	return args.Error(0)
+}
+
+func (m *MockTimeSyncRepository) GetTimeSyncStatus() (*model.TimeSyncStatus, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.TimeSyncStatus), args.Error(1)
+}
+
+func TestTimeSyncServiceImpl_EffectivePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		osType  string
+		policy  model.TimeSyncPolicy
+		daemon  string
+		wantErr bool
+	}{
+		{name: "NTS picks chrony", osType: "debian", policy: model.TimeSyncPolicy{NTS: true}, daemon: model.TimeSyncChrony},
+		{name: "Debian without NTS", osType: "debian", daemon: model.TimeSyncTimesyncd},
+		{name: "RHEL family", osType: "rocky", daemon: model.TimeSyncChrony},
+		{name: "Alpine", osType: "alpine", daemon: model.TimeSyncNTPD},
+		{name: "explicit chrony on Alpine", osType: "alpine", policy: model.TimeSyncPolicy{Daemon: model.TimeSyncChrony}, daemon: model.TimeSyncChrony},
+		{name: "timesyncd on Alpine", osType: "alpine", policy: model.TimeSyncPolicy{Daemon: model.TimeSyncTimesyncd}, wantErr: true},
+		{name: "ntpd off Alpine", osType: "ubuntu", policy: model.TimeSyncPolicy{Daemon: model.TimeSyncNTPD}, wantErr: true},
+		{name: "NTS with timesyncd", osType: "ubuntu", policy: model.TimeSyncPolicy{Daemon: model.TimeSyncTimesyncd, NTS: true}, wantErr: true},
+		{name: "unknown daemon", osType: "debian", policy: model.TimeSyncPolicy{Daemon: "openntpd"}, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service := NewTimeSyncServiceImpl(new(MockTimeSyncRepository), model.OSInfo{Type: tc.osType})
+			policy, err := service.EffectivePolicy(tc.policy)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.daemon, policy.Daemon)
+			assert.Equal(t, model.DefaultTimeServers, policy.Servers)
+		})
+	}
+}
+
+func TestTimeSyncServiceImpl_ApplyTimeSync(t *testing.T) {
+	mockRepo := new(MockTimeSyncRepository)
+	service := NewTimeSyncServiceImpl(mockRepo, model.OSInfo{Type: "debian", Version: "12"})
+
+	mockRepo.On("ConfigureTimeSync", model.TimeSyncPolicy{
+		Daemon:  model.TimeSyncChrony,
+		Servers: []string{"ntp.example.com"},
+		NTS:     true,
+	}).Return(nil)
+
+	err := service.ApplyTimeSync(model.TimeSyncPolicy{Servers: []string{"ntp.example.com"}, NTS: true})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTimeSyncServiceImpl_ApplyTimeSyncRejectsInvalidServer(t *testing.T) {
+	mockRepo := new(MockTimeSyncRepository)
+	service := NewTimeSyncServiceImpl(mockRepo, model.OSInfo{Type: "debian", Version: "12"})
+
+	err := service.ApplyTimeSync(model.TimeSyncPolicy{Servers: []string{"ntp.example.com\nserver evil"}})
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "ConfigureTimeSync", mock.Anything)
+}
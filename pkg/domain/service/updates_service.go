@@ -0,0 +1,119 @@
+// pkg/domain/service/updates_service.go
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// UpdatesService defines operations for automatic upgrade policy
+type UpdatesService interface {
+	// UpdatesSupported reports whether hardn writes the upgrade policy on
+	// this OS: unattended-upgrades on Debian and Ubuntu, a periodic script
+	// on Alpine
+	UpdatesSupported() bool
+
+	// EffectivePolicy fills in the defaults of a policy
+	EffectivePolicy(policy model.UpdatesPolicy) model.UpdatesPolicy
+
+	// ConfigureUpdates validates the policy, writes it and enables
+	// automatic upgrades
+	ConfigureUpdates(policy model.UpdatesPolicy) error
+
+	// DisableUpdates stops automatic upgrades
+	DisableUpdates() error
+
+	// GetUpdatesStatus reports whether automatic upgrades are enabled
+	GetUpdatesStatus() (*model.UpdatesStatus, error)
+}
+
+// UpdatesServiceImpl implements UpdatesService
+type UpdatesServiceImpl struct {
+	repository UpdatesRepository
+	osInfo     model.OSInfo
+}
+
+// NewUpdatesServiceImpl creates a new UpdatesServiceImpl
+func NewUpdatesServiceImpl(repository UpdatesRepository, osInfo model.OSInfo) *UpdatesServiceImpl {
+	return &UpdatesServiceImpl{
+		repository: repository,
+		osInfo:     osInfo,
+	}
+}
+
+// UpdatesRepository defines the repository operations needed by UpdatesService
+type UpdatesRepository interface {
+	ConfigureUpdates(policy model.UpdatesPolicy) error
+	DisableUpdates() error
+	GetUpdatesStatus() (*model.UpdatesStatus, error)
+	AppliedUpdates(since time.Time) ([]model.AppliedUpdate, error)
+}
+
+// Policy values end up quoted in apt configuration and in a shell script,
+// so they are limited to characters that need no escaping there
+var (
+	updatesOriginPattern  = regexp.MustCompile(`^[A-Za-z0-9 .,:=_+~${}/-]+$`)
+	updatesPackagePattern = regexp.MustCompile(`^[A-Za-z0-9.+_^*-]+$`)
+	updatesMailPattern    = regexp.MustCompile(`^[A-Za-z0-9._%+-]+(@[A-Za-z0-9.-]+)?$`)
+)
+
+func (s *UpdatesServiceImpl) UpdatesSupported() bool {
+	switch s.osInfo.Type {
+	case "debian", "ubuntu", "alpine":
+		return true
+	}
+	return false
+}
+
+func (s *UpdatesServiceImpl) EffectivePolicy(policy model.UpdatesPolicy) model.UpdatesPolicy {
+	if len(policy.Origins) == 0 && s.osInfo.Type != "alpine" {
+		policy.Origins = model.DefaultUpdateOrigins[s.osInfo.Type]
+	}
+	if policy.RebootTime == "" {
+		policy.RebootTime = model.DefaultRebootTime
+	}
+	return policy
+}
+
+func (s *UpdatesServiceImpl) ConfigureUpdates(policy model.UpdatesPolicy) error {
+	if !s.UpdatesSupported() {
+		return fmt.Errorf("hardn does not manage the upgrade policy on %s", s.osInfo.Type)
+	}
+	policy = s.EffectivePolicy(policy)
+
+	for _, origin := range policy.Origins {
+		if !updatesOriginPattern.MatchString(origin) {
+			return fmt.Errorf("invalid update origin %q", origin)
+		}
+	}
+	for _, name := range policy.Blacklist {
+		if !updatesPackagePattern.MatchString(name) {
+			return fmt.Errorf("invalid package in the update blacklist: %q", name)
+		}
+	}
+	if _, err := time.Parse("15:04", policy.RebootTime); err != nil {
+		return fmt.Errorf("invalid reboot time %q (expected HH:MM)", policy.RebootTime)
+	}
+	if policy.Mail != "" && !updatesMailPattern.MatchString(policy.Mail) {
+		return fmt.Errorf("invalid mail address %q", policy.Mail)
+	}
+
+	return s.repository.ConfigureUpdates(policy)
+}
+
+func (s *UpdatesServiceImpl) DisableUpdates() error {
+	if !s.UpdatesSupported() {
+		return fmt.Errorf("hardn does not manage the upgrade policy on %s", s.osInfo.Type)
+	}
+	return s.repository.DisableUpdates()
+}
+
+func (s *UpdatesServiceImpl) GetUpdatesStatus() (*model.UpdatesStatus, error) {
+	if !s.UpdatesSupported() {
+		return &model.UpdatesStatus{}, nil
+	}
+	return s.repository.GetUpdatesStatus()
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUpdatesRepository is a mock implementation of UpdatesRepository
+type MockUpdatesRepository struct {
+	mock.Mock
+}
+
+func (m *MockUpdatesRepository) ConfigureUpdates(policy model.UpdatesPolicy) error {
+	args := m.Called(policy)
+	return args.Error(0)
+}
+
+func (m *MockUpdatesRepository) DisableUpdates() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockUpdatesRepository) GetUpdatesStatus() (*model.UpdatesStatus, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UpdatesStatus), args.Error(1)
+}
+
+func (m *MockUpdatesRepository) AppliedUpdates(since time.Time) ([]model.AppliedUpdate, error) {
+	args := m.Called(since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AppliedUpdate), args.Error(1)
+}
+
+func TestUpdatesServiceImpl_EffectivePolicy(t *testing.T) {
+	debian := NewUpdatesServiceImpl(new(MockUpdatesRepository), model.OSInfo{Type: "debian", Version: "12"})
+	policy := debian.EffectivePolicy(model.UpdatesPolicy{Reboot: true})
+	assert.Equal(t, model.DefaultUpdateOrigins["debian"], policy.Origins)
+	assert.Equal(t, model.DefaultRebootTime, policy.RebootTime)
+
+	// Configured origins are kept
+	policy = debian.EffectivePolicy(model.UpdatesPolicy{Origins: []string{"origin=Debian"}, RebootTime: "04:30"})
+	assert.Equal(t, []string{"origin=Debian"}, policy.Origins)
+	assert.Equal(t, "04:30", policy.RebootTime)
+
+	// Alpine upgrades from its configured repositories
+	alpine := NewUpdatesServiceImpl(new(MockUpdatesRepository), model.OSInfo{Type: "alpine", Version: "3.19"})
+	assert.Empty(t, alpine.EffectivePolicy(model.UpdatesPolicy{}).Origins)
+}
+
+func TestUpdatesServiceImpl_ConfigureUpdates(t *testing.T) {
+	tests := []struct {
+		name        string
+		osType      string
+		policy      model.UpdatesPolicy
+		repoError   error
+		expectError bool
+	}{
+		{
+			name:   "debian defaults",
+			osType: "debian",
+			policy: model.UpdatesPolicy{},
+		},
+		{
+			name:   "ubuntu with blacklist, reboot and mail",
+			osType: "ubuntu",
+			policy: model.UpdatesPolicy{
+				Blacklist:  []string{"linux-image-*", "postgresql-16"},
+				Reboot:     true,
+				RebootTime: "03:15",
+				Mail:       "ops@example.com",
+			},
+		},
+		{
+			name:   "alpine",
+			osType: "alpine",
+			policy: model.UpdatesPolicy{Blacklist: []string{"linux-lts"}, Mail: "root"},
+		},
+		{
+			name:        "package with a quote",
+			osType:      "debian",
+			policy:      model.UpdatesPolicy{Blacklist: []string{`nginx"; rm`}},
+			expectError: true,
+		},
+		{
+			name:        "origin with a quote",
+			osType:      "debian",
+			policy:      model.UpdatesPolicy{Origins: []string{`origin=Debian";`}},
+			expectError: true,
+		},
+		{
+			name:        "invalid reboot time",
+			osType:      "debian",
+			policy:      model.UpdatesPolicy{Reboot: true, RebootTime: "25:00"},
+			expectError: true,
+		},
+		{
+			name:        "invalid mail address",
+			osType:      "debian",
+			policy:      model.UpdatesPolicy{Mail: "ops@example.com; reboot"},
+			expectError: true,
+		},
+		{
+			name:        "rhel uses dnf-automatic",
+			osType:      "rocky",
+			expectError: true,
+		},
+		{
+			name:        "repository error",
+			osType:      "debian",
+			repoError:   errors.New("mock write error"),
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockUpdatesRepository)
+			service := NewUpdatesServiceImpl(mockRepo, model.OSInfo{Type: tc.osType})
+			mockRepo.On("ConfigureUpdates", service.EffectivePolicy(tc.policy)).Return(tc.repoError)
+
+			err := service.ConfigureUpdates(tc.policy)
+
+			if tc.expectError {
+				assert.Error(t, err)
+				if tc.repoError == nil {
+					mockRepo.AssertNotCalled(t, "ConfigureUpdates", mock.Anything)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdatesServiceImpl_Unsupported(t *testing.T) {
+	mockRepo := new(MockUpdatesRepository)
+	service := NewUpdatesServiceImpl(mockRepo, model.OSInfo{Type: "fedora", Version: "40"})
+
+	assert.False(t, service.UpdatesSupported())
+	assert.ErrorContains(t, service.DisableUpdates(), "does not manage the upgrade policy on fedora")
+
+	status, err := service.GetUpdatesStatus()
+	assert.NoError(t, err)
+	assert.False(t, status.Enabled)
+	mockRepo.AssertNotCalled(t, "GetUpdatesStatus")
+}
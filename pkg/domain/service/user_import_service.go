@@ -0,0 +1,243 @@
+// pkg/domain/service/user_import_service.go
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// UserImportService defines operations for creating users in bulk
+type UserImportService interface {
+	// Plan parses an import file and checks every entry without changing
+	// anything. Entries that leave nopasswd empty use defaultNoPassword.
+	Plan(data []byte, defaultNoPassword bool) (*model.UserImportPlan, error)
+
+	// PlanFile reads an import file and plans it as Plan does
+	PlanFile(path string, defaultNoPassword bool) (*model.UserImportPlan, error)
+
+	// Import creates or updates the users of a valid plan, one result per
+	// entry; a failed user does not stop the others
+	Import(plan *model.UserImportPlan) ([]model.UserImportResult, error)
+}
+
+// UserImportServiceImpl implements UserImportService
+type UserImportServiceImpl struct {
+	repository UserRepository
+}
+
+// NewUserImportServiceImpl creates a new UserImportServiceImpl
+func NewUserImportServiceImpl(repository UserRepository) *UserImportServiceImpl {
+	return &UserImportServiceImpl{
+		repository: repository,
+	}
+}
+
+// PlanFile reads an import file through the repository, so dry runs and
+// tests see the same file the import would
+func (s *UserImportServiceImpl) PlanFile(path string, defaultNoPassword bool) (*model.UserImportPlan, error) {
+	data, err := s.repository.ReadImportFile(path)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.Plan(data, defaultNoPassword)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return plan, nil
+}
+
+// Plan reads the header row, then one user per row. Unknown columns and
+// malformed CSV fail the whole file; problems with a single user, such as
+// an invalid name, a name listed twice, a weak key or a directory account,
+// are recorded on its entry.
+func (s *UserImportServiceImpl) Plan(data []byte, defaultNoPassword bool) (*model.UserImportPlan, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("the import file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !isUserImportColumn(name) {
+			return nil, fmt.Errorf("unknown column %q (valid: %s)", name, strings.Join(model.UserImportColumns, ", "))
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("column %q is listed twice", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["username"]; !ok {
+		return nil, fmt.Errorf("the header has no username column")
+	}
+
+	plan := &model.UserImportPlan{}
+	seen := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		entry := model.UserImportEntry{Line: line}
+		if len(record) > len(header) {
+			entry.Problems = append(entry.Problems, fmt.Sprintf("has %d fields but the header has %d", len(record), len(header)))
+		}
+
+		entry.User.Username = field("username")
+		if err := model.ValidateUsername(entry.User.Username); err != nil {
+			entry.Problems = append(entry.Problems, err.Error())
+		} else if first, ok := seen[entry.User.Username]; ok {
+			entry.Problems = append(entry.Problems, fmt.Sprintf("%s is already listed on line %d", entry.User.Username, first))
+		} else {
+			seen[entry.User.Username] = line
+			entry.Problems = append(entry.Problems, s.checkAccount(&entry)...)
+		}
+
+		hasSudo, err := parseImportFlag(field("sudo"), false)
+		if err != nil {
+			entry.Problems = append(entry.Problems, "sudo: "+err.Error())
+		}
+		entry.User.HasSudo = hasSudo
+
+		noPassword, err := parseImportFlag(field("nopasswd"), defaultNoPassword)
+		if err != nil {
+			entry.Problems = append(entry.Problems, "nopasswd: "+err.Error())
+		}
+		if noPassword && !hasSudo && field("nopasswd") != "" {
+			entry.Problems = append(entry.Problems, "nopasswd is set without sudo")
+		}
+		entry.User.SudoNoPassword = noPassword && hasSudo
+
+		keys, problems := parseImportKeys(field("keys"))
+		entry.User.SshKeys = keys
+		entry.Problems = append(entry.Problems, problems...)
+
+		plan.Entries = append(plan.Entries, entry)
+	}
+
+	if len(plan.Entries) == 0 {
+		return nil, fmt.Errorf("the import file lists no users")
+	}
+	return plan, nil
+}
+
+// checkAccount records whether the user exists and refuses directory
+// accounts, which a local account of the same name would shadow
+func (s *UserImportServiceImpl) checkAccount(entry *model.UserImportEntry) []string {
+	username := entry.User.Username
+
+	directory, err := s.repository.IsDirectoryUser(username)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to look up %s: %v", username, err)}
+	}
+	if directory {
+		return []string{fmt.Sprintf("%s is a directory account; a local account would shadow it", username)}
+	}
+
+	if _, err := s.repository.GetUser(username); err == nil {
+		entry.Exists = true
+	}
+	return nil
+}
+
+// Import creates the users of the plan in file order. A plan with problems
+// is refused as a whole, so a file is never half imported because of a
+// mistake the pre-flight found.
+func (s *UserImportServiceImpl) Import(plan *model.UserImportPlan) ([]model.UserImportResult, error) {
+	if plan == nil || len(plan.Entries) == 0 {
+		return nil, fmt.Errorf("no users to import")
+	}
+	if !plan.Valid() {
+		return nil, fmt.Errorf("the import has %d problem(s); no users were created", plan.ProblemCount())
+	}
+
+	results := make([]model.UserImportResult, 0, len(plan.Entries))
+	for _, entry := range plan.Entries {
+		result := model.UserImportResult{
+			Username: entry.User.Username,
+			Created:  !entry.Exists,
+		}
+		if err := s.repository.CreateUser(entry.User); err != nil {
+			result.Err = err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// isUserImportColumn reports whether name is a column of the import file
+func isUserImportColumn(name string) bool {
+	for _, column := range model.UserImportColumns {
+		if name == column {
+			return true
+		}
+	}
+	return false
+}
+
+// parseImportFlag parses a yes/no field, returning fallback when it is empty
+func parseImportFlag(value string, fallback bool) (bool, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return fallback, nil
+	case "yes", "y", "true", "1":
+		return true, nil
+	case "no", "n", "false", "0":
+		return false, nil
+	}
+	return fallback, fmt.Errorf("%q is not yes or no", value)
+}
+
+// parseImportKeys splits the keys field on semicolons and checks each key.
+// Keys listed twice are kept once; DSA and short RSA keys are refused.
+func parseImportKeys(value string) ([]string, []string) {
+	var keys, problems []string
+	seen := make(map[string]bool)
+	for i, line := range strings.Split(value, ";") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		key, err := model.ParseAuthorizedKey(line)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("key %d is not a public key: %v", i+1, err))
+			continue
+		}
+		if _, detail := weakKeyIssue(*key); detail != "" {
+			problems = append(problems, fmt.Sprintf("key %d %s", i+1, detail))
+			continue
+		}
+		if seen[key.Fingerprint] {
+			continue
+		}
+		seen[key.Fingerprint] = true
+		keys = append(keys, line)
+	}
+	return keys, problems
+}
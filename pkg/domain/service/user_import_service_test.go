@@ -0,0 +1,170 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserImportServiceImpl_Plan(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserImportServiceImpl(mockRepo)
+
+	mockRepo.On("IsDirectoryUser", mock.Anything).Return(false, nil)
+	mockRepo.On("GetUser", "george").Return(nil, errors.New("user george does not exist"))
+	mockRepo.On("GetUser", "deploy").Return(&model.User{Username: "deploy"}, nil)
+
+	data := []byte("# team accounts\n" +
+		"Username, Sudo, NoPasswd, Keys\n" +
+		"george,yes,,\"" + testEd25519Key + ";" + testLaptopKey + ";" + testEd25519Key + "\"\n" +
+		"deploy,no,,\n")
+
+	plan, err := service.Plan(data, true)
+
+	assert.NoError(t, err)
+	assert.True(t, plan.Valid())
+	assert.Len(t, plan.Entries, 2)
+
+	george := plan.Entries[0]
+	assert.Equal(t, 3, george.Line)
+	assert.False(t, george.Exists)
+	assert.True(t, george.User.HasSudo)
+	assert.True(t, george.User.SudoNoPassword, "an empty nopasswd uses the default")
+	assert.Equal(t, []string{testEd25519Key, testLaptopKey}, george.User.SshKeys)
+	assert.Equal(t, "create george with sudo (no password) and 2 SSH key(s)", george.Describe())
+
+	deploy := plan.Entries[1]
+	assert.True(t, deploy.Exists)
+	assert.False(t, deploy.User.SudoNoPassword)
+	assert.Equal(t, "update deploy without sudo and 0 SSH key(s)", deploy.Describe())
+}
+
+func TestUserImportServiceImpl_Plan_Problems(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserImportServiceImpl(mockRepo)
+
+	mockRepo.On("IsDirectoryUser", "jdoe").Return(true, nil)
+	mockRepo.On("IsDirectoryUser", mock.Anything).Return(false, nil)
+	mockRepo.On("GetUser", mock.Anything).Return(nil, errors.New("no such user"))
+
+	data := []byte("username,sudo,nopasswd,keys\n" +
+		"george,yes,no,\n" +
+		"george,no,,\n" +
+		"Bad Name,,,\n" +
+		"root,,,\n" +
+		"jdoe,maybe,,\n" +
+		"alice,no,yes,not a key\n" +
+		"bob,,,\"" + testRSA1024Key + "\"\n")
+
+	plan, err := service.Plan(data, false)
+
+	assert.NoError(t, err)
+	assert.False(t, plan.Valid())
+	assert.Len(t, plan.Entries, 7)
+	assert.Empty(t, plan.Entries[0].Problems)
+	assert.Equal(t, []string{"george is already listed on line 2"}, plan.Entries[1].Problems)
+	assert.Equal(t, []string{"username cannot contain spaces"}, plan.Entries[2].Problems)
+	assert.Contains(t, plan.Entries[3].Problems[0], "reserved system username")
+	assert.Equal(t, []string{
+		"jdoe is a directory account; a local account would shadow it",
+		`sudo: "maybe" is not yes or no`,
+	}, plan.Entries[4].Problems)
+	assert.Equal(t, []string{
+		"nopasswd is set without sudo",
+		"key 1 is not a public key: unrecognized SSH public key format",
+	}, plan.Entries[5].Problems)
+	assert.Len(t, plan.Entries[6].Problems, 1)
+	assert.Contains(t, plan.Entries[6].Problems[0], "1024-bit RSA key")
+	assert.Equal(t, 8, plan.ProblemCount())
+}
+
+func TestUserImportServiceImpl_Plan_InvalidFile(t *testing.T) {
+	service := NewUserImportServiceImpl(new(MockUserRepository))
+
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{"empty", "", "the import file is empty"},
+		{"unknown column", "username,shell\n", `unknown column "shell"`},
+		{"no username column", "sudo,keys\n", "no username column"},
+		{"duplicate column", "username,sudo,sudo\n", `column "sudo" is listed twice`},
+		{"no users", "username,sudo\n# nobody yet\n", "lists no users"},
+		{"malformed", "username\n\"george\n", "invalid CSV"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := service.Plan([]byte(tt.data), false)
+			assert.Nil(t, plan)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.err)
+			}
+		})
+	}
+}
+
+func TestUserImportServiceImpl_Import(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserImportServiceImpl(mockRepo)
+
+	george := model.User{Username: "george", HasSudo: true, SshKeys: []string{testEd25519Key}}
+	deploy := model.User{Username: "deploy"}
+	alice := model.User{Username: "alice"}
+	mockRepo.On("CreateUser", george).Return(nil)
+	mockRepo.On("CreateUser", deploy).Return(errors.New("adduser failed"))
+	mockRepo.On("CreateUser", alice).Return(nil)
+
+	results, err := service.Import(&model.UserImportPlan{Entries: []model.UserImportEntry{
+		{Line: 2, User: george},
+		{Line: 3, User: deploy},
+		{Line: 4, User: alice, Exists: true},
+	}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.UserImportResult{
+		{Username: "george", Created: true},
+		{Username: "deploy", Created: true, Err: errors.New("adduser failed")},
+		{Username: "alice", Created: false},
+	}, results)
+	mockRepo.AssertNumberOfCalls(t, "CreateUser", 3)
+}
+
+func TestUserImportServiceImpl_Import_RefusesProblems(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserImportServiceImpl(mockRepo)
+
+	results, err := service.Import(&model.UserImportPlan{Entries: []model.UserImportEntry{
+		{Line: 2, User: model.User{Username: "george"}},
+		{Line: 3, User: model.User{Username: "root"}, Problems: []string{"reserved"}},
+	}})
+
+	assert.Nil(t, results)
+	assert.EqualError(t, err, "the import has 1 problem(s); no users were created")
+	mockRepo.AssertNotCalled(t, "CreateUser", mock.Anything)
+}
+
+func TestUserImportServiceImpl_PlanFile(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserImportServiceImpl(mockRepo)
+
+	mockRepo.On("ReadImportFile", "users.csv").Return([]byte("username,sudo\ngeorge,yes\n"), nil)
+	mockRepo.On("ReadImportFile", "bad.csv").Return([]byte("username,shell\n"), nil)
+	mockRepo.On("ReadImportFile", "missing.csv").Return(nil, errors.New("failed to read missing.csv: no such file"))
+	mockRepo.On("IsDirectoryUser", mock.Anything).Return(false, nil)
+	mockRepo.On("GetUser", "george").Return(nil, errors.New("no such user"))
+
+	plan, err := service.PlanFile("users.csv", false)
+	assert.NoError(t, err)
+	assert.Len(t, plan.Entries, 1)
+
+	_, err = service.PlanFile("bad.csv", false)
+	assert.ErrorContains(t, err, "bad.csv: unknown column \"shell\"")
+
+	_, err = service.PlanFile("missing.csv", false)
+	assert.EqualError(t, err, "failed to read missing.csv: no such file")
+}
@@ -1,15 +1,26 @@
 // pkg/domain/service/user_service.go
 package service
 
-import "github.com/abbott/hardn/pkg/domain/model"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
 
 // UserService defines operations for user management
 type UserService interface {
 	CreateUser(user model.User) error
 	GetUser(username string) (*model.User, error)
 	AddSSHKey(username, publicKey string) error
+	RemoveSSHKey(username, fingerprint string) error
+	AuditSSHKeys() ([]model.SSHKeyFinding, error)
 	ConfigureSudo(username string, noPassword bool) error
+	LockUser(username string) error
+	ListUsers(opts model.UserListOptions) (*model.UserPage, error)
 	GetExtendedUserInfo(username string) (*model.User, error)
+	GetDirectoryAuthStatus() (*model.DirectoryAuthStatus, error)
+	IsDirectoryUser(username string) (bool, error)
 }
 
 // UserServiceImpl implements UserService
@@ -29,12 +40,20 @@ type UserRepository interface {
 	CreateUser(user model.User) error
 	GetUser(username string) (*model.User, error)
 	AddSSHKey(username, publicKey string) error
+	RemoveSSHKey(username, fingerprint string) error
+	GetAuthorizedKeys(username string) ([]string, error)
 	ConfigureSudo(username string, noPassword bool) error
+	LockUser(username string) error
 	GetExtendedUserInfo(username string) (*model.User, error)
+	GetDirectoryAuthStatus() (*model.DirectoryAuthStatus, error)
+	IsDirectoryUser(username string) (bool, error)
 
 	// Methods moved from host_info_service.go
 	GetNonSystemUsers() ([]model.User, error)
+	ListNonSystemUsers(opts model.UserListOptions) (*model.UserPage, error)
 	GetNonSystemGroups() ([]string, error)
+
+	ReadImportFile(path string) ([]byte, error)
 }
 
 // Implement UserService methods...
@@ -50,10 +69,183 @@ func (s *UserServiceImpl) AddSSHKey(username, publicKey string) error {
 	return s.repository.AddSSHKey(username, publicKey)
 }
 
+func (s *UserServiceImpl) RemoveSSHKey(username, fingerprint string) error {
+	if username == "" {
+		return fmt.Errorf("username cannot be empty")
+	}
+	if fingerprint == "" {
+		return fmt.Errorf("key fingerprint cannot be empty")
+	}
+	return s.repository.RemoveSSHKey(username, fingerprint)
+}
+
 func (s *UserServiceImpl) ConfigureSudo(username string, noPassword bool) error {
 	return s.repository.ConfigureSudo(username, noPassword)
 }
 
+// LockUser locks an account; root is refused so the host stays reachable
+// from the console
+func (s *UserServiceImpl) LockUser(username string) error {
+	if username == "" {
+		return fmt.Errorf("username cannot be empty")
+	}
+	if username == "root" {
+		return fmt.Errorf("refusing to lock the root account")
+	}
+	return s.repository.LockUser(username)
+}
+
+// ListUsers returns a page of non-system users
+func (s *UserServiceImpl) ListUsers(opts model.UserListOptions) (*model.UserPage, error) {
+	return s.repository.ListNonSystemUsers(opts)
+}
+
 func (s *UserServiceImpl) GetExtendedUserInfo(username string) (*model.User, error) {
 	return s.repository.GetExtendedUserInfo(username)
 }
+
+func (s *UserServiceImpl) GetDirectoryAuthStatus() (*model.DirectoryAuthStatus, error) {
+	return s.repository.GetDirectoryAuthStatus()
+}
+
+// IsDirectoryUser reports whether the account is provided by a directory
+// service rather than /etc/passwd
+func (s *UserServiceImpl) IsDirectoryUser(username string) (bool, error) {
+	if username == "" {
+		return false, fmt.Errorf("username cannot be empty")
+	}
+	return s.repository.IsDirectoryUser(username)
+}
+
+// AuditSSHKeys scans the authorized_keys of root and every local user for
+// small RSA keys, DSA keys, keys shared between accounts and options that
+// widen what a key may do
+func (s *UserServiceImpl) AuditSSHKeys() ([]model.SSHKeyFinding, error) {
+	usernames, err := keyOwnerUsernames(s.repository)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []model.AuthorizedKey
+	owners := make(map[string][]string)
+	for _, username := range usernames {
+		lines, err := s.repository.GetAuthorizedKeys(username)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read authorized keys for %s: %w", username, err)
+		}
+
+		for _, line := range lines {
+			key, err := model.ParseAuthorizedKey(line)
+			if err != nil {
+				continue
+			}
+			key.Username = username
+			keys = append(keys, *key)
+
+			if !containsString(owners[key.Fingerprint], username) {
+				owners[key.Fingerprint] = append(owners[key.Fingerprint], username)
+			}
+		}
+	}
+
+	var findings []model.SSHKeyFinding
+	for _, key := range keys {
+		finding := model.SSHKeyFinding{
+			Username:    key.Username,
+			KeyType:     key.KeyType,
+			Bits:        key.Bits,
+			Fingerprint: key.Fingerprint,
+			Comment:     key.Comment,
+		}
+
+		if issue, detail := weakKeyIssue(key); issue != "" {
+			finding.Issue = issue
+			finding.Severity = model.SeverityHigh
+			finding.Detail = detail
+			findings = append(findings, finding)
+		}
+
+		if len(owners[key.Fingerprint]) > 1 {
+			var others []string
+			for _, owner := range owners[key.Fingerprint] {
+				if owner != key.Username {
+					others = append(others, owner)
+				}
+			}
+			finding.Issue = model.SSHKeyIssueDuplicate
+			finding.Severity = model.SeverityMedium
+			finding.Detail = "is also authorized for " + strings.Join(others, ", ")
+			findings = append(findings, finding)
+		}
+
+		if unsafe := unsafeKeyOptions(key.Options); len(unsafe) > 0 {
+			finding.Issue = model.SSHKeyIssueUnsafeOptions
+			finding.Severity = model.SeverityMedium
+			finding.Detail = "has unsafe options: " + strings.Join(unsafe, ", ")
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings, nil
+}
+
+// keyOwnerUsernames returns root and the local non-system users, whose
+// authorized_keys the key audit and inventory read
+func keyOwnerUsernames(repository UserRepository) ([]string, error) {
+	usernames := []string{"root"}
+	page, err := repository.ListNonSystemUsers(model.UserListOptions{LocalOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	for _, user := range page.Users {
+		if user.Username != "root" {
+			usernames = append(usernames, user.Username)
+		}
+	}
+	return usernames, nil
+}
+
+// weakKeyIssue returns the audit issue and detail for a DSA or short RSA
+// key, or "" for a key of acceptable strength
+func weakKeyIssue(key model.AuthorizedKey) (string, string) {
+	switch {
+	case key.KeyType == "ssh-dss":
+		return model.SSHKeyIssueDSA, "is a DSA key, which OpenSSH no longer accepts by default"
+	case key.KeyType == "ssh-rsa" && key.Bits > 0 && key.Bits < model.MinRSAKeyBits:
+		return model.SSHKeyIssueWeakRSA, fmt.Sprintf("is a %d-bit RSA key (minimum %d)", key.Bits, model.MinRSAKeyBits)
+	}
+	return "", ""
+}
+
+// unsafeKeyOptions returns the authorized_keys options that grant more than
+// a plain key: environment overrides, tunnels, unrestricted forwarding and
+// forwarding re-enabled after restrict
+func unsafeKeyOptions(options []string) []string {
+	var unsafe []string
+	for _, option := range options {
+		name, value, _ := strings.Cut(option, "=")
+		value = strings.Trim(value, `"`)
+
+		switch strings.ToLower(name) {
+		case "environment", "tunnel":
+			unsafe = append(unsafe, option)
+		case "permitopen", "permitlisten":
+			if strings.Contains(value, "*") {
+				unsafe = append(unsafe, option)
+			}
+		case "agent-forwarding", "port-forwarding", "x11-forwarding", "user-rc":
+			unsafe = append(unsafe, option)
+		}
+	}
+	return unsafe
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
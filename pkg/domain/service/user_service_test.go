@@ -39,6 +39,24 @@ func (m *MockUserRepository) AddSSHKey(username, publicKey string) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) RemoveSSHKey(username, fingerprint string) error {
+	args := m.Called(username, fingerprint)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) LockUser(username string) error {
+	args := m.Called(username)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetAuthorizedKeys(username string) ([]string, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockUserRepository) ConfigureSudo(username string, noPassword bool) error {
 	args := m.Called(username, noPassword)
 	return args.Error(0)
@@ -64,6 +82,27 @@ func (m *MockUserRepository) GetNonSystemUsers() ([]model.User, error) {
 	return users, args.Error(1)
 }
 
+func (m *MockUserRepository) ListNonSystemUsers(opts model.UserListOptions) (*model.UserPage, error) {
+	args := m.Called(opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserPage), args.Error(1)
+}
+
+func (m *MockUserRepository) GetDirectoryAuthStatus() (*model.DirectoryAuthStatus, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.DirectoryAuthStatus), args.Error(1)
+}
+
+func (m *MockUserRepository) IsDirectoryUser(username string) (bool, error) {
+	args := m.Called(username)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockUserRepository) GetNonSystemGroups() ([]string, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
@@ -79,6 +118,14 @@ func (m *MockUserRepository) GetNonSystemGroups() ([]string, error) {
 	return groups, args.Error(1)
 }
 
+func (m *MockUserRepository) ReadImportFile(path string) ([]byte, error) {
+	args := m.Called(path)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
 func (m *MockUserRepository) GetExtendedUserInfo(username string) (*model.User, error) {
 	args := m.Called(username)
 	if args.Get(0) == nil {
@@ -436,3 +483,188 @@ func TestUserServiceImpl_GetExtendedUserInfo_Error(t *testing.T) {
 	assert.Equal(t, expectedErr, err)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestUserServiceImpl_RemoveSSHKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		username    string
+		fingerprint string
+		repoError   error
+		expectCall  bool
+		expectError bool
+	}{
+		{
+			name:        "successful removal",
+			username:    "testuser",
+			fingerprint: "SHA256:abc123",
+			expectCall:  true,
+		},
+		{
+			name:        "repository error",
+			username:    "testuser",
+			fingerprint: "SHA256:abc123",
+			repoError:   fmt.Errorf("no SSH key with fingerprint"),
+			expectCall:  true,
+			expectError: true,
+		},
+		{
+			name:        "empty fingerprint",
+			username:    "testuser",
+			expectError: true,
+		},
+		{
+			name:        "empty username",
+			fingerprint: "SHA256:abc123",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(MockUserRepository)
+			service := NewUserServiceImpl(mockRepo)
+
+			if tc.expectCall {
+				mockRepo.On("RemoveSSHKey", tc.username, tc.fingerprint).Return(tc.repoError)
+			}
+
+			// Execute
+			err := service.RemoveSSHKey(tc.username, tc.fingerprint)
+
+			// Assert
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+			if !tc.expectCall {
+				mockRepo.AssertNotCalled(t, "RemoveSSHKey", tc.username, tc.fingerprint)
+			}
+		})
+	}
+}
+
+func TestUserServiceImpl_LockUser(t *testing.T) {
+	tests := []struct {
+		name        string
+		username    string
+		repoError   error
+		expectCall  bool
+		expectError bool
+	}{
+		{
+			name:       "successful lock",
+			username:   "testuser",
+			expectCall: true,
+		},
+		{
+			name:        "repository error",
+			username:    "testuser",
+			repoError:   fmt.Errorf("user testuser does not exist"),
+			expectCall:  true,
+			expectError: true,
+		},
+		{
+			name:        "root refused",
+			username:    "root",
+			expectError: true,
+		},
+		{
+			name:        "empty username",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(MockUserRepository)
+			service := NewUserServiceImpl(mockRepo)
+
+			if tc.expectCall {
+				mockRepo.On("LockUser", tc.username).Return(tc.repoError)
+			}
+
+			// Execute
+			err := service.LockUser(tc.username)
+
+			// Assert
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+			if !tc.expectCall {
+				mockRepo.AssertNotCalled(t, "LockUser", tc.username)
+			}
+		})
+	}
+}
+
+func TestUserServiceImpl_IsDirectoryUser(t *testing.T) {
+	// Setup
+	mockRepo := new(MockUserRepository)
+	service := NewUserServiceImpl(mockRepo)
+
+	mockRepo.On("IsDirectoryUser", "jdoe").Return(true, nil)
+
+	// Execute
+	isDirectory, err := service.IsDirectoryUser("jdoe")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, isDirectory)
+	mockRepo.AssertExpectations(t)
+
+	// Empty usernames are rejected before reaching the repository
+	_, err = service.IsDirectoryUser("")
+	assert.Error(t, err)
+	mockRepo.AssertNumberOfCalls(t, "IsDirectoryUser", 1)
+}
+
+// Test keys for the SSH key audit
+const (
+	testRSA1024Key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAAAgQCihwCn3BJEZ9RK6LbPG7R6jAVGk2P4+frM5ukOPBpP/jtb6K8zC+KckrO8PX2eI/d1evFx7GrP3EpLczHFFHNnmaJlAaSbIqeKKcYrmVoV2KQpYm9PsWn+tI353iw+zNzXWISHKnHTtffhBtzgR5AEN+WSAhdd6i9JYTJ0ZEfvjw== old@laptop"
+	testEd25519Key = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAICqqcAOALJM+NkXwjS8siPyyxmk+83AINsC4n6ErnTqt deploy@ci"
+	testDSAKey     = "ssh-dss AAAAB3NzaC1kc3MAAACBAOsP3m2YwmV5LwNO23N7KslU6PYXS29qS2yjSYYiJQiiPgjOmrZeMvKeb8XldxatbQN7EKexcRP17jHO/19ioUYzJoa6ivwNkfIfGht1/F+tUOArxJtZg6hOPxy1CGlSbXBiaqyA2PwYv0HhZ0zD7iQpXPBEDI4pFLzRnMiyvJ7vAAAAFQDPXEutXWY64+kqG43BzijktqErkQAAAIAAl088RkMIm0PmIpF5J46sD8XRBxQHG+9tKkhz3OBK7ZOGcM0fhRILamv775jowWmQNECoqrCKzQvx/D0xVJVMCgVkXmmkYrspdJkjFaFjvxkAYiMlWLh9l/1ZUMpYLkZ6Zh5VDhQ5nmpUNE3EfNpK/A4kM/oz42D7qfkb6qgm+QAAAIEA6U3PIaXrg/had3LphWPERHqXqyqw3PakhcOICl/lcSZ0s4fpqpjynkPAtOhN2FYqMd0U50tZ0rBIyq202muWgXfm0i8A+m3jIyBOaOyRxs+O558vgjf8hZjk0XuUfJ6IPDWy0bEYN1rxlPSGENhrnYMfCY0p9KwdLyfQcgFW0JM= root@vm"
+)
+
+func TestUserServiceImpl_AuditSSHKeys(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserServiceImpl(mockRepo)
+
+	mockRepo.On("ListNonSystemUsers", model.UserListOptions{LocalOnly: true}).Return(&model.UserPage{
+		Users: []model.User{{Username: "alice"}, {Username: "deploy"}},
+	}, nil)
+	mockRepo.On("GetAuthorizedKeys", "root").Return([]string{testDSAKey}, nil)
+	mockRepo.On("GetAuthorizedKeys", "alice").Return([]string{testRSA1024Key, testEd25519Key}, nil)
+	mockRepo.On("GetAuthorizedKeys", "deploy").Return([]string{
+		`environment="LD_PRELOAD=/tmp/x.so",command="/usr/bin/rsync --server" ` + testEd25519Key,
+		"not a key",
+	}, nil)
+
+	findings, err := service.AuditSSHKeys()
+
+	assert.NoError(t, err)
+
+	var summary []string
+	for _, finding := range findings {
+		summary = append(summary, finding.Username+" "+finding.Issue)
+	}
+	assert.Equal(t, []string{
+		"root dsa",
+		"alice weak_rsa",
+		"alice duplicate",
+		"deploy duplicate",
+		"deploy unsafe_options",
+	}, summary)
+
+	assert.Equal(t, 1024, findings[1].Bits)
+	assert.Equal(t, "is also authorized for deploy", findings[2].Detail)
+	assert.Equal(t, `has unsafe options: environment="LD_PRELOAD=/tmp/x.so"`, findings[4].Detail)
+	assert.Equal(t, "deploy@ci", findings[4].Comment)
+	assert.Equal(t, "ssh.key.unsafe_options", findings[4].Violation().Rule)
+}
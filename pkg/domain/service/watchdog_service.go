@@ -0,0 +1,69 @@
+// pkg/domain/service/watchdog_service.go
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// WatchdogService defines operations for verifying that SSH stays
+// reachable after unattended changes
+type WatchdogService interface {
+	// VerifySSH probes the SSH server until it answers or the watchdog's
+	// timeout passes
+	VerifySSH(watchdog model.SSHWatchdog) (*model.WatchdogResult, error)
+}
+
+// WatchdogServiceImpl implements WatchdogService
+type WatchdogServiceImpl struct {
+	repository ReachabilityRepository
+	now        func() time.Time
+	sleep      func(time.Duration)
+}
+
+// NewWatchdogServiceImpl creates a new WatchdogServiceImpl
+func NewWatchdogServiceImpl(repository ReachabilityRepository) *WatchdogServiceImpl {
+	return &WatchdogServiceImpl{
+		repository: repository,
+		now:        time.Now,
+		sleep:      time.Sleep,
+	}
+}
+
+// ReachabilityRepository defines the repository operations needed by WatchdogService
+type ReachabilityRepository interface {
+	ProbeSSH(helper string, address string, port int, timeout time.Duration) error
+}
+
+func (s *WatchdogServiceImpl) VerifySSH(watchdog model.SSHWatchdog) (*model.WatchdogResult, error) {
+	if watchdog.Port < 1 || watchdog.Port > 65535 {
+		return nil, fmt.Errorf("invalid SSH port for the watchdog: %d", watchdog.Port)
+	}
+	if watchdog.Address == "" {
+		return nil, fmt.Errorf("the watchdog has no address to probe")
+	}
+	timeout := watchdog.Timeout
+	if timeout <= 0 {
+		timeout = model.DefaultWatchdogTimeout
+	}
+
+	// sshd may still be restarting, so a failed probe is retried until
+	// the timeout passes
+	result := &model.WatchdogResult{}
+	deadline := s.now().Add(timeout)
+	for {
+		result.Attempts++
+		probeTimeout := min(model.WatchdogInterval*2, timeout)
+		result.LastError = s.repository.ProbeSSH(watchdog.Helper, watchdog.Address, watchdog.Port, probeTimeout)
+		if result.LastError == nil {
+			result.Reachable = true
+			return result, nil
+		}
+		if !s.now().Add(model.WatchdogInterval).Before(deadline) {
+			return result, nil
+		}
+		s.sleep(model.WatchdogInterval)
+	}
+}
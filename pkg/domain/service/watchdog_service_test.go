@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockReachabilityRepository is a mock implementation of ReachabilityRepository
+type MockReachabilityRepository struct {
+	mock.Mock
+}
+
+func (m *MockReachabilityRepository) ProbeSSH(helper string, address string, port int, timeout time.Duration) error {
+	return m.Called(helper, address, port, timeout).Error(0)
+}
+
+// newTestWatchdogService returns a service whose clock advances when it sleeps
+func newTestWatchdogService(repository ReachabilityRepository) (*WatchdogServiceImpl, *[]time.Duration) {
+	service := NewWatchdogServiceImpl(repository)
+	now := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	service.now = func() time.Time { return now }
+	service.sleep = func(d time.Duration) {
+		slept = append(slept, d)
+		now = now.Add(d)
+	}
+	return service, &slept
+}
+
+func TestWatchdogServiceImpl_VerifySSH(t *testing.T) {
+	t.Run("retries until sshd answers", func(t *testing.T) {
+		mockRepo := new(MockReachabilityRepository)
+		mockRepo.On("ProbeSSH", "", "127.0.0.1", 2222, 10*time.Second).Return(errors.New("connection refused")).Twice()
+		mockRepo.On("ProbeSSH", "", "127.0.0.1", 2222, 10*time.Second).Return(nil).Once()
+
+		service, slept := newTestWatchdogService(mockRepo)
+		result, err := service.VerifySSH(model.SSHWatchdog{Port: 2222, Address: "127.0.0.1", Timeout: time.Minute})
+		require.NoError(t, err)
+
+		assert.True(t, result.Reachable)
+		assert.Equal(t, 3, result.Attempts)
+		assert.NoError(t, result.LastError)
+		assert.Len(t, *slept, 2)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("gives up after the timeout", func(t *testing.T) {
+		mockRepo := new(MockReachabilityRepository)
+		mockRepo.On("ProbeSSH", "probe@bastion", "203.0.113.10", 22, 10*time.Second).Return(errors.New("timed out"))
+
+		service, _ := newTestWatchdogService(mockRepo)
+		result, err := service.VerifySSH(model.SSHWatchdog{
+			Port: 22, Address: "203.0.113.10", Helper: "probe@bastion", Timeout: 20 * time.Second,
+		})
+		require.NoError(t, err)
+
+		assert.False(t, result.Reachable)
+		assert.Equal(t, 4, result.Attempts)
+		assert.EqualError(t, result.LastError, "timed out")
+	})
+
+	t.Run("rejects an incomplete watchdog", func(t *testing.T) {
+		service := NewWatchdogServiceImpl(new(MockReachabilityRepository))
+		_, err := service.VerifySSH(model.SSHWatchdog{Port: 0, Address: "127.0.0.1"})
+		assert.ErrorContains(t, err, "invalid SSH port")
+		_, err = service.VerifySSH(model.SSHWatchdog{Port: 22})
+		assert.ErrorContains(t, err, "no address")
+	})
+}
@@ -0,0 +1,85 @@
+// pkg/domain/service/webserver_service.go
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// WebServerService defines operations for hardening web server TLS
+type WebServerService interface {
+	// DetectWebServers returns the supported web servers installed on the host
+	DetectWebServers() ([]model.WebServer, error)
+
+	// ApplyTLSBaseline applies the TLS baseline to every detected web server
+	ApplyTLSBaseline() ([]model.WebServer, error)
+}
+
+// WebServerServiceImpl implements WebServerService
+type WebServerServiceImpl struct {
+	repository WebServerRepository
+}
+
+// NewWebServerServiceImpl creates a new WebServerServiceImpl
+func NewWebServerServiceImpl(repository WebServerRepository) *WebServerServiceImpl {
+	return &WebServerServiceImpl{
+		repository: repository,
+	}
+}
+
+// WebServerRepository defines the repository operations needed by WebServerService
+type WebServerRepository interface {
+	DetectWebServers() ([]model.WebServer, error)
+	WriteTLSBaseline(server model.WebServer) error
+	RemoveTLSBaseline(server model.WebServer) error
+	ValidateConfig(server model.WebServer) (string, error)
+	ReloadWebServer(server model.WebServer) error
+}
+
+func (s *WebServerServiceImpl) DetectWebServers() ([]model.WebServer, error) {
+	return s.repository.DetectWebServers()
+}
+
+func (s *WebServerServiceImpl) ApplyTLSBaseline() ([]model.WebServer, error) {
+	servers, err := s.repository.DetectWebServers()
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []model.WebServer
+	for _, server := range servers {
+		if err := s.applyTLSBaseline(server); err != nil {
+			return applied, err
+		}
+		server.BaselineApplied = true
+		applied = append(applied, server)
+	}
+
+	return applied, nil
+}
+
+// applyTLSBaseline writes the snippet and only reloads once the web server
+// accepts the new configuration; a rejected snippet is removed again so the
+// running server is never left with a configuration it cannot load
+func (s *WebServerServiceImpl) applyTLSBaseline(server model.WebServer) error {
+	if err := s.repository.WriteTLSBaseline(server); err != nil {
+		return fmt.Errorf("failed to write %s TLS baseline: %w", server.Name, err)
+	}
+
+	if output, err := s.repository.ValidateConfig(server); err != nil {
+		if removeErr := s.repository.RemoveTLSBaseline(server); removeErr != nil {
+			return fmt.Errorf("%s rejected the TLS baseline (%v) and it could not be removed: %w",
+				server.Name, err, removeErr)
+		}
+		return fmt.Errorf("%s rejected the TLS baseline; it was removed again: %v\n%s",
+			server.Name, err, strings.TrimSpace(output))
+	}
+
+	if err := s.repository.ReloadWebServer(server); err != nil {
+		return fmt.Errorf("failed to reload %s: %w", server.Name, err)
+	}
+
+	return nil
+}
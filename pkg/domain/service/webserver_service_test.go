@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebServerRepository is a mock implementation of WebServerRepository
+type MockWebServerRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebServerRepository) DetectWebServers() ([]model.WebServer, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.WebServer), args.Error(1)
+}
+
+func (m *MockWebServerRepository) WriteTLSBaseline(server model.WebServer) error {
+	return m.Called(server).Error(0)
+}
+
+func (m *MockWebServerRepository) RemoveTLSBaseline(server model.WebServer) error {
+	return m.Called(server).Error(0)
+}
+
+func (m *MockWebServerRepository) ValidateConfig(server model.WebServer) (string, error) {
+	args := m.Called(server)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockWebServerRepository) ReloadWebServer(server model.WebServer) error {
+	return m.Called(server).Error(0)
+}
+
+func TestWebServerServiceImpl_ApplyTLSBaseline(t *testing.T) {
+	nginx := model.WebServer{Name: model.WebServerNginx, SnippetPath: "/etc/nginx/conf.d/hardn-tls.conf"}
+	caddy := model.WebServer{Name: model.WebServerCaddy, SnippetPath: "/etc/caddy/hardn-tls.caddy"}
+
+	t.Run("validates before reloading", func(t *testing.T) {
+		mockRepo := new(MockWebServerRepository)
+		mockRepo.On("DetectWebServers").Return([]model.WebServer{nginx, caddy}, nil)
+		mockRepo.On("WriteTLSBaseline", mock.Anything).Return(nil)
+		mockRepo.On("ValidateConfig", mock.Anything).Return("syntax is ok", nil)
+		mockRepo.On("ReloadWebServer", mock.Anything).Return(nil)
+
+		applied, err := NewWebServerServiceImpl(mockRepo).ApplyTLSBaseline()
+
+		assert.NoError(t, err)
+		assert.Len(t, applied, 2)
+		assert.True(t, applied[0].BaselineApplied)
+		mockRepo.AssertNumberOfCalls(t, "ReloadWebServer", 2)
+		mockRepo.AssertNotCalled(t, "RemoveTLSBaseline", mock.Anything)
+	})
+
+	t.Run("rejected snippet is removed", func(t *testing.T) {
+		mockRepo := new(MockWebServerRepository)
+		mockRepo.On("DetectWebServers").Return([]model.WebServer{nginx}, nil)
+		mockRepo.On("WriteTLSBaseline", nginx).Return(nil)
+		mockRepo.On("ValidateConfig", nginx).Return("nginx: [emerg] unknown directive", errors.New("exit status 1"))
+		mockRepo.On("RemoveTLSBaseline", nginx).Return(nil)
+
+		applied, err := NewWebServerServiceImpl(mockRepo).ApplyTLSBaseline()
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown directive")
+		assert.Empty(t, applied)
+		mockRepo.AssertCalled(t, "RemoveTLSBaseline", nginx)
+		mockRepo.AssertNotCalled(t, "ReloadWebServer", mock.Anything)
+	})
+
+	t.Run("no web servers", func(t *testing.T) {
+		mockRepo := new(MockWebServerRepository)
+		mockRepo.On("DetectWebServers").Return([]model.WebServer{}, nil)
+
+		applied, err := NewWebServerServiceImpl(mockRepo).ApplyTLSBaseline()
+
+		assert.NoError(t, err)
+		assert.Empty(t, applied)
+	})
+}
@@ -51,7 +51,9 @@ func (f *MenuFactory) CreateHelpMenu() *menu.HelpMenu {
 func (f *MenuFactory) CreateSystemDetailsMenu() *menu.SystemDetailsMenu {
 	// Get the host info manager from the service factory
 	hostInfoManager := f.serviceFactory.CreateHostInfoManager()
-	return menu.NewSystemDetailsMenu(f.config, f.osInfo, hostInfoManager)
+	networkExposureManager := f.serviceFactory.CreateNetworkExposureManager()
+	timeSyncManager := f.serviceFactory.CreateTimeSyncManager()
+	return menu.NewSystemDetailsMenu(f.config, f.osInfo, hostInfoManager, networkExposureManager, timeSyncManager)
 }
 
 // CreateMainMenu creates the main menu with all dependencies wired up
@@ -65,11 +67,31 @@ func (f *MenuFactory) CreateMainMenu(versionService *version.Service) *menu.Main
 	backupManager := f.serviceFactory.CreateBackupManager()
 	environmentManager := f.serviceFactory.CreateEnvironmentManager()
 	logsManager := f.serviceFactory.CreateLogsManager()
+	webServerManager := f.serviceFactory.CreateWebServerManager()
+	databaseManager := f.serviceFactory.CreateDatabaseManager()
+	macManager := f.serviceFactory.CreateMACManager()
+	updatesManager := f.serviceFactory.CreateUpdatesManager()
+	bannerManager := f.serviceFactory.CreateBannerManager()
+	passwordManager := f.serviceFactory.CreatePasswordPolicyManager()
+	moduleBlacklistManager := f.serviceFactory.CreateModuleBlacklistManager()
+	bootHardeningManager := f.serviceFactory.CreateBootHardeningManager()
+	timeSyncManager := f.serviceFactory.CreateTimeSyncManager()
+	logShippingManager := f.serviceFactory.CreateLogShippingManager()
 	securityManager := application.NewSecurityManager(
-		userManager, sshManager, firewallManager, dnsManager)
+		userManager, sshManager, firewallManager, dnsManager, packageManager, webServerManager, databaseManager, macManager, updatesManager, bannerManager, passwordManager, moduleBlacklistManager,
+		bootHardeningManager, timeSyncManager, logShippingManager)
 
 	// Create menu manager (use := instead of = since we're not declaring it above anymore)
 	hostInfoManager := f.serviceFactory.CreateHostInfoManager()
+	snapshotManager := f.serviceFactory.CreateSnapshotManager()
+	scheduleManager := f.serviceFactory.CreateScheduleManager()
+	runManager := f.serviceFactory.CreateRunManager()
+	lynisManager := f.serviceFactory.CreateLynisManager()
+	networkExposureManager := f.serviceFactory.CreateNetworkExposureManager()
+	proxmoxManager := f.serviceFactory.CreateProxmoxManager()
+	historyManager := f.serviceFactory.CreatePostureHistoryManager()
+	sudoersManager := f.serviceFactory.CreateSudoersManager()
+	debloatManager := f.serviceFactory.CreateDebloatManager()
 	menuManager := application.NewMenuManager(
 		userManager,
 		sshManager,
@@ -80,7 +102,19 @@ func (f *MenuFactory) CreateMainMenu(versionService *version.Service) *menu.Main
 		securityManager,
 		environmentManager,
 		logsManager,
-		hostInfoManager)
+		hostInfoManager,
+		snapshotManager,
+		scheduleManager,
+		runManager,
+		lynisManager,
+		updatesManager,
+		networkExposureManager,
+		proxmoxManager,
+		bootHardeningManager,
+		timeSyncManager,
+		historyManager,
+		sudoersManager,
+		debloatManager)
 
 	// Create menu with all necessary fields initialized
 	return menu.NewMainMenu(menuManager, f.config, f.osInfo, versionService)
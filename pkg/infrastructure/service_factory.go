@@ -8,6 +8,7 @@ import (
 	"github.com/abbott/hardn/pkg/domain/model"
 	"github.com/abbott/hardn/pkg/domain/service"
 	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/logging"
 	"github.com/abbott/hardn/pkg/osdetect"
 	portsecondary "github.com/abbott/hardn/pkg/port/secondary"
 )
@@ -37,11 +38,18 @@ func (f *ServiceFactory) SetConfig(config *config.Config) {
 // getUserRepository returns or creates a UserRepository
 func (f *ServiceFactory) getUserRepository() portsecondary.UserRepository {
 	if f.userRepository == nil {
-		f.userRepository = secondary.NewOSUserRepository(f.provider.FS, f.provider.Commander, f.osInfo.OsType)
+		provider := f.moduleProvider(logging.ModuleUsers)
+		f.userRepository = secondary.NewOSUserRepository(provider.FS, provider.Commander, f.osInfo.OsType)
 	}
 	return f.userRepository
 }
 
+// moduleProvider returns the provider for a module's repositories, which
+// logs what they run and write when the module's debug entries are enabled
+func (f *ServiceFactory) moduleProvider(module string) *interfaces.Provider {
+	return interfaces.DebugProvider(f.provider, module)
+}
+
 // CreateHostInfoManager creates a HostInfoManager
 func (f *ServiceFactory) CreateHostInfoManager() *application.HostInfoManager {
 	// Get the shared user repository
@@ -62,17 +70,72 @@ func (f *ServiceFactory) CreateUserManager() *application.UserManager {
 	// Get the shared user repository
 	userRepo := f.getUserRepository()
 
-	// Create domain service
+	// Create domain services
 	userService := service.NewUserServiceImpl(userRepo)
+	keyImportService := service.NewSSHKeyImportServiceImpl(secondary.NewHTTPSSHKeySourceRepository(), userRepo)
+	importService := service.NewUserImportServiceImpl(userRepo)
+
+	// Create application service
+	return application.NewUserManager(userService, keyImportService, importService)
+}
+
+// CreateSSHKeyInventoryManager creates an SSHKeyInventoryManager with all required dependencies
+func (f *ServiceFactory) CreateSSHKeyInventoryManager() *application.SSHKeyInventoryManager {
+	// Create repositories
+	userRepo := f.getUserRepository()
+	expiryRepo := secondary.NewFileSSHKeyExpiryRepository(f.provider.FS, model.SSHKeyExpiryPath)
+
+	// Create domain service
+	inventoryService := service.NewSSHKeyInventoryServiceImpl(userRepo, expiryRepo)
+
+	// Create application service
+	return application.NewSSHKeyInventoryManager(inventoryService)
+}
+
+// CreateSudoersManager creates a SudoersManager with all required dependencies
+func (f *ServiceFactory) CreateSudoersManager() *application.SudoersManager {
+	// Create repository
+	provider := f.moduleProvider(logging.ModuleUsers)
+	sudoersRepo := secondary.NewOSSudoersRepository(provider.FS, provider.Commander, f.osInfo.OsType)
+
+	// Create domain service
+	sudoersService := service.NewSudoersServiceImpl(sudoersRepo)
+
+	// Create application service
+	return application.NewSudoersManager(sudoersService)
+}
+
+// CreateDebloatManager creates a DebloatManager with all required dependencies
+func (f *ServiceFactory) CreateDebloatManager() *application.DebloatManager {
+	// Create repository
+	provider := f.moduleProvider(logging.ModulePackages)
+	debloatRepo := secondary.NewOSDebloatRepository(provider.Commander, f.osInfo.OsType)
+
+	// Create domain service
+	debloatService := service.NewDebloatServiceImpl(debloatRepo)
 
 	// Create application service
-	return application.NewUserManager(userService)
+	return application.NewDebloatManager(debloatService)
+}
+
+// CreateRepositoryTrustManager creates a RepositoryTrustManager with all required dependencies
+func (f *ServiceFactory) CreateRepositoryTrustManager() *application.RepositoryTrustManager {
+	// Create repository
+	provider := f.moduleProvider(logging.ModulePackages)
+	trustRepo := secondary.NewOSRepositoryTrustRepository(provider.FS, provider.Commander, f.osInfo.OsType)
+
+	// Create domain service
+	trustService := service.NewRepositoryTrustServiceImpl(trustRepo, convertOSInfo(f.osInfo))
+
+	// Create application service
+	return application.NewRepositoryTrustManager(trustService)
 }
 
 // CreateSSHManager creates an SSHManager with all required dependencies
 func (f *ServiceFactory) CreateSSHManager() *application.SSHManager {
 	// Create repository
-	sshRepo := secondary.NewFileSSHRepository(f.provider.FS, f.provider.Commander, f.osInfo.OsType)
+	provider := f.moduleProvider(logging.ModuleSSH)
+	sshRepo := secondary.NewFileSSHRepository(provider.FS, provider.Commander, f.osInfo.OsType)
 
 	// Create domain service
 	sshService := service.NewSSHServiceImpl(sshRepo, convertOSInfo(f.osInfo))
@@ -88,13 +151,15 @@ func convertOSInfo(info *osdetect.OSInfo) model.OSInfo {
 		Codename:  info.OsCodename,
 		Version:   info.OsVersion,
 		IsProxmox: info.IsProxmox,
+		Arch:      info.Arch,
 	}
 }
 
 // CreateFirewallManager creates a FirewallManager
 func (f *ServiceFactory) CreateFirewallManager() *application.FirewallManager {
 	// Create repository
-	firewallRepo := secondary.NewUFWFirewallRepository(f.provider.FS, f.provider.Commander)
+	provider := f.moduleProvider(logging.ModuleFirewall)
+	firewallRepo := secondary.NewFirewallRepository(provider.FS, provider.Commander, f.osInfo.OsType)
 
 	// Create domain service
 	firewallService := service.NewFirewallServiceImpl(firewallRepo, convertOSInfo(f.osInfo))
@@ -103,10 +168,120 @@ func (f *ServiceFactory) CreateFirewallManager() *application.FirewallManager {
 	return application.NewFirewallManager(firewallService)
 }
 
+// CreateSelfTestManager creates a SelfTestManager
+func (f *ServiceFactory) CreateSelfTestManager() *application.SelfTestManager {
+	// Create repository
+	selfTestRepo := secondary.NewOSSelfTestRepository(f.provider.FS, f.provider.Commander)
+
+	// Create domain service
+	selfTestService := service.NewSelfTestServiceImpl(selfTestRepo)
+
+	// Create application service
+	return application.NewSelfTestManager(selfTestService)
+}
+
+// CreateLynisManager creates a LynisManager
+func (f *ServiceFactory) CreateLynisManager() *application.LynisManager {
+	// Create repository
+	lynisRepo := secondary.NewOSLynisRepository(f.provider.FS, f.provider.Commander)
+
+	// Create domain service
+	lynisService := service.NewLynisServiceImpl(lynisRepo)
+
+	// Create application service
+	return application.NewLynisManager(lynisService)
+}
+
+// CreateComplianceManager creates a ComplianceManager
+func (f *ServiceFactory) CreateComplianceManager() *application.ComplianceManager {
+	// Create repository
+	complianceRepo := secondary.NewOSComplianceRepository(f.provider.FS, f.provider.Commander)
+
+	// Create domain service
+	complianceService := service.NewComplianceServiceImpl(complianceRepo)
+
+	// Create application service
+	return application.NewComplianceManager(complianceService)
+}
+
+// CreateMigrationManager creates a MigrationManager
+func (f *ServiceFactory) CreateMigrationManager() *application.MigrationManager {
+	// Create repository
+	migrationRepo := secondary.NewFileMigrationRepository(f.provider.FS, f.provider.Commander)
+
+	// Create domain service
+	migrationService := service.NewMigrationServiceImpl(migrationRepo)
+
+	// Create application service
+	return application.NewMigrationManager(migrationService)
+}
+
+// CreateTCPWrappersManager creates a TCPWrappersManager
+func (f *ServiceFactory) CreateTCPWrappersManager() *application.TCPWrappersManager {
+	// Create repositories
+	tcpWrappersRepo := secondary.NewOSTCPWrappersRepository(f.provider.FS, f.provider.Commander)
+	firewallProvider := f.moduleProvider(logging.ModuleFirewall)
+	firewallRepo := secondary.NewFirewallRepository(firewallProvider.FS, firewallProvider.Commander, f.osInfo.OsType)
+
+	// Create domain services
+	tcpWrappersService := service.NewTCPWrappersServiceImpl(tcpWrappersRepo)
+	firewallService := service.NewFirewallServiceImpl(firewallRepo, convertOSInfo(f.osInfo))
+
+	// Create application service
+	return application.NewTCPWrappersManager(tcpWrappersService, firewallService)
+}
+
+// CreateProxmoxManager creates a ProxmoxManager
+func (f *ServiceFactory) CreateProxmoxManager() *application.ProxmoxManager {
+	// Create repositories
+	proxmoxRepo := secondary.NewOSProxmoxRepository(f.provider.FS, f.provider.Commander, f.osInfo.OsType)
+	firewallProvider := f.moduleProvider(logging.ModuleFirewall)
+	firewallRepo := secondary.NewFirewallRepository(firewallProvider.FS, firewallProvider.Commander, f.osInfo.OsType)
+
+	// Create domain services
+	proxmoxService := service.NewProxmoxServiceImpl(proxmoxRepo, convertOSInfo(f.osInfo))
+	firewallService := service.NewFirewallServiceImpl(firewallRepo, convertOSInfo(f.osInfo))
+
+	// Create application service
+	return application.NewProxmoxManager(proxmoxService, firewallService)
+}
+
+// CreateNotificationManager creates a NotificationManager sending to the
+// configured email address and webhooks
+func (f *ServiceFactory) CreateNotificationManager() *application.NotificationManager {
+	settings := f.config.Notifications.Settings()
+
+	var notifiers []service.Notifier
+	if settings.Email.Configured() {
+		notifiers = append(notifiers, secondary.NewSMTPNotifier(settings.Email))
+	}
+	for _, url := range settings.Webhooks {
+		notifiers = append(notifiers, secondary.NewWebhookNotifier(url))
+	}
+
+	notificationService := service.NewNotificationServiceImpl(notifiers, settings.OnlyOnFailure)
+	return application.NewNotificationManager(notificationService)
+}
+
+// CreateNetworkExposureManager creates a NetworkExposureManager
+func (f *ServiceFactory) CreateNetworkExposureManager() *application.NetworkExposureManager {
+	// Create repositories
+	exposureRepo := secondary.NewOSNetworkExposureRepository(f.provider.FS, f.provider.Commander, f.osInfo.OsType)
+	firewallProvider := f.moduleProvider(logging.ModuleFirewall)
+	firewallRepo := secondary.NewFirewallRepository(firewallProvider.FS, firewallProvider.Commander, f.osInfo.OsType)
+
+	// Create domain service
+	exposureService := service.NewNetworkExposureServiceImpl(exposureRepo, firewallRepo)
+
+	// Create application service
+	return application.NewNetworkExposureManager(exposureService)
+}
+
 // CreateDNSManager creates a DNSManager
 func (f *ServiceFactory) CreateDNSManager() *application.DNSManager {
 	// Create repository
-	dnsRepo := secondary.NewFileDNSRepository(f.provider.FS, f.provider.Commander, f.osInfo.OsType)
+	provider := f.moduleProvider(logging.ModuleDNS)
+	dnsRepo := secondary.NewFileDNSRepository(provider.FS, provider.Commander, f.osInfo.OsType)
 
 	// Create domain service
 	dnsService := service.NewDNSServiceImpl(dnsRepo, convertOSInfo(f.osInfo))
@@ -115,16 +290,16 @@ func (f *ServiceFactory) CreateDNSManager() *application.DNSManager {
 	return application.NewDNSManager(dnsService)
 }
 
-// CreatePackageManager creates a PackageManager
-func (f *ServiceFactory) CreatePackageManager() *application.PackageManager {
-	// Convert config to PackageSources model
-	sources := &model.PackageSources{
+// packageSources converts the configuration to the PackageSources model
+func (f *ServiceFactory) packageSources() *model.PackageSources {
+	return &model.PackageSources{
 		// Standard repositories
 		DebianRepos:           f.config.DebianRepos,
 		ProxmoxSrcRepos:       f.config.ProxmoxSrcRepos,
 		ProxmoxCephRepo:       f.config.ProxmoxCephRepo,
 		ProxmoxEnterpriseRepo: f.config.ProxmoxEnterpriseRepo,
 		AlpineTestingRepo:     f.config.AlpineTestingRepo,
+		SourcesFormat:         f.config.SourcesFormat,
 
 		// Package lists
 		DebianCorePackages: f.config.LinuxCorePackages,
@@ -133,21 +308,32 @@ func (f *ServiceFactory) CreatePackageManager() *application.PackageManager {
 		AlpineCorePackages: f.config.AlpineCorePackages,
 		AlpineDmzPackages:  f.config.AlpineDmzPackages,
 		AlpineLabPackages:  f.config.AlpineLabPackages,
+		RhelCorePackages:   f.config.RhelCorePackages,
+		RhelDmzPackages:    f.config.RhelDmzPackages,
+		RhelLabPackages:    f.config.RhelLabPackages,
 
 		// Python packages
 		DebianPythonPackages: f.config.PythonPackages,
 		NonWslPythonPackages: f.config.NonWslPythonPackages,
 		PythonPipPackages:    f.config.PythonPipPackages,
 		AlpinePythonPackages: f.config.AlpinePythonPackages,
+		RhelPythonPackages:   f.config.RhelPythonPackages,
 	}
+}
+
+// CreatePackageManager creates a PackageManager
+func (f *ServiceFactory) CreatePackageManager() *application.PackageManager {
+	sources := f.packageSources()
 
 	// Create repository
+	provider := f.moduleProvider(logging.ModulePackages)
 	packageRepo := secondary.NewOSPackageRepository(
-		f.provider.FS,
-		f.provider.Commander,
+		provider.FS,
+		provider.Commander,
 		f.osInfo.OsType,
 		f.osInfo.OsVersion,
 		f.osInfo.OsCodename,
+		f.osInfo.Arch,
 		f.osInfo.IsProxmox,
 		sources,
 	)
@@ -164,6 +350,7 @@ func (f *ServiceFactory) CreatePackageManager() *application.PackageManager {
 			Version:   f.osInfo.OsVersion,
 			Codename:  f.osInfo.OsCodename,
 			IsProxmox: f.osInfo.IsProxmox,
+			Arch:      f.osInfo.Arch,
 		},
 		f.provider.Network,
 		f.config.DmzSubnet,
@@ -181,8 +368,24 @@ func (f *ServiceFactory) CreateMenuManager() *application.MenuManager {
 	environmentManager := f.CreateEnvironmentManager()
 	logsManager := f.CreateLogsManager()
 	hostInfoManager := f.CreateHostInfoManager()
+	snapshotManager := f.CreateSnapshotManager()
+	scheduleManager := f.CreateScheduleManager()
+	runManager := f.CreateRunManager()
+	lynisManager := f.CreateLynisManager()
+	webServerManager := f.CreateWebServerManager()
+	databaseManager := f.CreateDatabaseManager()
+	macManager := f.CreateMACManager()
+	updatesManager := f.CreateUpdatesManager()
+	networkExposureManager := f.CreateNetworkExposureManager()
+	bannerManager := f.CreateBannerManager()
+	passwordManager := f.CreatePasswordPolicyManager()
+	moduleBlacklistManager := f.CreateModuleBlacklistManager()
+	bootHardeningManager := f.CreateBootHardeningManager()
+	timeSyncManager := f.CreateTimeSyncManager()
+	logShippingManager := f.CreateLogShippingManager()
 	securityManager := application.NewSecurityManager(
-		userManager, sshManager, firewallManager, dnsManager)
+		userManager, sshManager, firewallManager, dnsManager, packageManager, webServerManager, databaseManager, macManager, updatesManager, bannerManager, passwordManager, moduleBlacklistManager,
+		bootHardeningManager, timeSyncManager, logShippingManager)
 
 	return application.NewMenuManager(
 		userManager,
@@ -194,17 +397,138 @@ func (f *ServiceFactory) CreateMenuManager() *application.MenuManager {
 		securityManager,
 		environmentManager,
 		logsManager,
-		hostInfoManager)
+		hostInfoManager,
+		snapshotManager,
+		scheduleManager,
+		runManager,
+		lynisManager,
+		updatesManager,
+		networkExposureManager,
+		f.CreateProxmoxManager(),
+		bootHardeningManager,
+		timeSyncManager,
+		f.CreatePostureHistoryManager(),
+		f.CreateSudoersManager(),
+		f.CreateDebloatManager())
+}
+
+// CreateUpdatesManager creates an UpdatesManager
+func (f *ServiceFactory) CreateUpdatesManager() *application.UpdatesManager {
+	// Create repository
+	provider := f.moduleProvider(logging.ModulePackages)
+	updatesRepo := secondary.NewOSUpdatesRepository(provider.FS, provider.Commander, f.osInfo.OsType)
+
+	// Create domain service
+	updatesService := service.NewUpdatesServiceImpl(updatesRepo, convertOSInfo(f.osInfo))
+
+	// Create application service
+	return application.NewUpdatesManager(updatesService)
+}
+
+// CreateTimeSyncManager creates a TimeSyncManager
+func (f *ServiceFactory) CreateTimeSyncManager() *application.TimeSyncManager {
+	// Create repository
+	provider := f.moduleProvider(logging.ModulePackages)
+	timeSyncRepo := secondary.NewOSTimeSyncRepository(provider.FS, provider.Commander, f.osInfo.OsType)
+
+	// Create domain service
+	timeSyncService := service.NewTimeSyncServiceImpl(timeSyncRepo, convertOSInfo(f.osInfo))
+
+	// Create application service
+	return application.NewTimeSyncManager(timeSyncService)
+}
+
+// CreateLogShippingManager creates a LogShippingManager
+func (f *ServiceFactory) CreateLogShippingManager() *application.LogShippingManager {
+	// Create repository
+	provider := f.moduleProvider(logging.ModulePackages)
+	logShippingRepo := secondary.NewOSLogShippingRepository(provider.FS, provider.Commander, f.osInfo.OsType)
+
+	// Create domain service
+	logShippingService := service.NewLogShippingServiceImpl(logShippingRepo, convertOSInfo(f.osInfo))
+
+	// Create application service
+	return application.NewLogShippingManager(logShippingService)
+}
+
+// CreatePostureSummaryManager creates a PostureSummaryManager
+func (f *ServiceFactory) CreatePostureSummaryManager() *application.PostureSummaryManager {
+	// Create repositories
+	historyRepo := secondary.NewFilePostureHistoryRepository(f.provider.FS, model.PostureHistoryPath)
+	reportRepo := secondary.NewFileRunReportRepository(f.provider.FS, model.RunReportsDir, model.RunsDir)
+	updatesRepo := secondary.NewOSUpdatesRepository(f.provider.FS, f.provider.Commander, f.osInfo.OsType)
+
+	// Create domain service
+	summaryService := service.NewPostureSummaryServiceImpl(historyRepo, reportRepo, updatesRepo)
+
+	// Create application service
+	return application.NewPostureSummaryManager(summaryService)
+}
+
+// CreateBannerManager creates a BannerManager
+func (f *ServiceFactory) CreateBannerManager() *application.BannerManager {
+	// Create repository
+	provider := f.moduleProvider(logging.ModuleSSH)
+	bannerRepo := secondary.NewOSBannerRepository(provider.FS, provider.Commander, f.osInfo.OsType)
+
+	// Create domain service
+	bannerService := service.NewBannerServiceImpl(bannerRepo, convertOSInfo(f.osInfo))
+
+	// Create application service
+	return application.NewBannerManager(bannerService, f.CreateSSHManager())
+}
+
+// CreatePasswordPolicyManager creates a PasswordPolicyManager
+func (f *ServiceFactory) CreatePasswordPolicyManager() *application.PasswordPolicyManager {
+	// Create repository
+	provider := f.moduleProvider(logging.ModuleUsers)
+	passwordPolicyRepo := secondary.NewOSPasswordPolicyRepository(provider.FS, provider.Commander, f.osInfo.OsType)
+
+	// Create domain service
+	passwordPolicyService := service.NewPasswordPolicyServiceImpl(passwordPolicyRepo, convertOSInfo(f.osInfo))
+
+	// Create application service
+	return application.NewPasswordPolicyManager(passwordPolicyService)
+}
+
+// CreateModuleBlacklistManager creates a ModuleBlacklistManager
+func (f *ServiceFactory) CreateModuleBlacklistManager() *application.ModuleBlacklistManager {
+	// Create repository
+	provider := f.moduleProvider(logging.ModulePackages)
+	moduleBlacklistRepo := secondary.NewOSModuleBlacklistRepository(provider.FS, provider.Commander, f.osInfo.OsType)
+
+	// Create domain service
+	moduleBlacklistService := service.NewModuleBlacklistServiceImpl(moduleBlacklistRepo, convertOSInfo(f.osInfo))
+
+	// Create application service
+	return application.NewModuleBlacklistManager(moduleBlacklistService)
+}
+
+// CreateBootHardeningManager creates a BootHardeningManager that backs up
+// the boot loader configuration before changing it
+func (f *ServiceFactory) CreateBootHardeningManager() *application.BootHardeningManager {
+	// Create repositories
+	provider := f.moduleProvider(logging.ModulePackages)
+	bootRepo := secondary.NewOSBootRepository(provider.FS, provider.Commander, f.osInfo.OsType)
+	backupProvider := f.moduleProvider(logging.ModuleBackup)
+	backupRepo := secondary.NewFileBackupRepository(backupProvider.FS, backupProvider.Commander, f.config.BackupConfig())
+
+	// Create domain services
+	bootService := service.NewBootHardeningServiceImpl(bootRepo, convertOSInfo(f.osInfo))
+	backupService := service.NewBackupServiceImpl(backupRepo)
+
+	// Create application service
+	return application.NewBootHardeningManager(bootService, backupService)
 }
 
 // CreateBackupManager creates a BackupManager
 func (f *ServiceFactory) CreateBackupManager() *application.BackupManager {
 	// Create repository
+	provider := f.moduleProvider(logging.ModuleBackup)
 	backupRepo := secondary.NewFileBackupRepository(
-		f.provider.FS,
-		f.provider.Commander,
-		f.config.BackupPath,
-		f.config.EnableBackups,
+		provider.FS,
+		provider.Commander,
+		f.config.BackupConfig(),
 	)
 
 	// Create domain service
@@ -231,7 +555,7 @@ func (f *ServiceFactory) CreateLogsManager() *application.LogsManager {
 	// Create repository
 	logsRepo := secondary.NewFileLogsRepository(
 		f.provider.FS,
-		f.config.LogFile,
+		f.config.LoggingOptions().Path,
 	)
 
 	// Create domain service
@@ -240,3 +564,227 @@ func (f *ServiceFactory) CreateLogsManager() *application.LogsManager {
 	// Create application service
 	return application.NewLogsManager(logsService)
 }
+
+// CreateMaintenanceManager creates a MaintenanceManager from the configured windows
+func (f *ServiceFactory) CreateMaintenanceManager() (*application.MaintenanceManager, error) {
+	windows, err := service.ParseMaintenanceWindows(f.config.MaintenanceWindows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create domain service
+	maintenanceService := service.NewMaintenanceServiceImpl(windows)
+
+	// Create application service
+	return application.NewMaintenanceManager(maintenanceService), nil
+}
+
+// CreateSnapshotManager creates a SnapshotManager
+func (f *ServiceFactory) CreateSnapshotManager() *application.SnapshotManager {
+	// Create repository
+	snapshotRepo := secondary.NewSystemSnapshotRepository(f.provider.FS, f.provider.Commander)
+
+	// Create domain service
+	snapshotService := service.NewSnapshotServiceImpl(snapshotRepo)
+
+	// Create application service
+	return application.NewSnapshotManager(snapshotService)
+}
+
+// CreateScheduleManager creates a ScheduleManager
+func (f *ServiceFactory) CreateScheduleManager() *application.ScheduleManager {
+	// Create repository
+	scheduleRepo := secondary.NewSystemScheduleRepository(f.provider.FS, f.provider.Commander, f.osInfo.OsType)
+
+	// Create domain service
+	scheduleService := service.NewScheduleServiceImpl(scheduleRepo)
+
+	// Create application service
+	return application.NewScheduleManager(scheduleService)
+}
+
+// CreateRunManager creates a RunManager
+func (f *ServiceFactory) CreateRunManager() *application.RunManager {
+	// Create repository
+	runRepo := secondary.NewFileRunRepository(f.provider.FS, f.provider.Commander, model.RunsDir)
+
+	// Create domain service
+	runService := service.NewRunServiceImpl(runRepo)
+
+	// Create application service
+	return application.NewRunManager(runService)
+}
+
+// CreatePostureHistoryManager creates a PostureHistoryManager
+func (f *ServiceFactory) CreatePostureHistoryManager() *application.PostureHistoryManager {
+	// Create repository
+	historyRepo := secondary.NewFilePostureHistoryRepository(f.provider.FS, model.PostureHistoryPath)
+
+	// Create domain service
+	historyService := service.NewPostureHistoryServiceImpl(historyRepo)
+
+	// Create application service
+	return application.NewPostureHistoryManager(historyService)
+}
+
+// CreateWatchdogManager creates a WatchdogManager
+func (f *ServiceFactory) CreateWatchdogManager() *application.WatchdogManager {
+	// Create repositories
+	reachabilityRepo := secondary.NewOSReachabilityRepository(f.provider.FS, f.provider.Commander)
+	runRepo := secondary.NewFileRunRepository(f.provider.FS, f.provider.Commander, model.RunsDir)
+
+	// Create domain services
+	watchdogService := service.NewWatchdogServiceImpl(reachabilityRepo)
+	runService := service.NewRunServiceImpl(runRepo)
+
+	// Create application service
+	return application.NewWatchdogManager(watchdogService, runService)
+}
+
+// CreateSSHSafetyManager creates an SSHSafetyManager
+func (f *ServiceFactory) CreateSSHSafetyManager() *application.SSHSafetyManager {
+	// Create repositories
+	timerRepo := secondary.NewSystemRevertTimerRepository(f.provider.FS, f.provider.Commander, model.SafetyMarkerDir, f.osInfo.OsType)
+	reachabilityRepo := secondary.NewOSReachabilityRepository(f.provider.FS, f.provider.Commander)
+	runRepo := secondary.NewFileRunRepository(f.provider.FS, f.provider.Commander, model.RunsDir)
+
+	// Create domain services
+	safetyService := service.NewSSHSafetyServiceImpl(timerRepo)
+	watchdogService := service.NewWatchdogServiceImpl(reachabilityRepo)
+	runService := service.NewRunServiceImpl(runRepo)
+
+	// Create application service
+	return application.NewSSHSafetyManager(safetyService, watchdogService, runService)
+}
+
+// CreateEvidenceManager creates an EvidenceManager
+func (f *ServiceFactory) CreateEvidenceManager() *application.EvidenceManager {
+	// Create repositories
+	evidenceRepo := secondary.NewFileEvidenceRepository(f.provider.FS, f.provider.Commander)
+	runRepo := secondary.NewFileRunRepository(f.provider.FS, f.provider.Commander, model.RunsDir)
+
+	// Create domain services
+	evidenceService := service.NewEvidenceServiceImpl(evidenceRepo)
+	runService := service.NewRunServiceImpl(runRepo)
+
+	// Create application service
+	return application.NewEvidenceManager(evidenceService, runService)
+}
+
+// CreateProfileBundleManager creates a ProfileBundleManager
+func (f *ServiceFactory) CreateProfileBundleManager() *application.ProfileBundleManager {
+	// Create repository
+	bundleRepo := secondary.NewFileProfileBundleRepository(f.provider.FS, f.provider.Commander)
+
+	// Create domain service
+	bundleService := service.NewProfileBundleServiceImpl(bundleRepo)
+
+	// Create application service
+	return application.NewProfileBundleManager(bundleService)
+}
+
+// CreateAnsibleFactsManager creates an AnsibleFactsManager
+func (f *ServiceFactory) CreateAnsibleFactsManager() *application.AnsibleFactsManager {
+	// Create repository
+	factsRepo := secondary.NewFileAnsibleFactsRepository(f.provider.FS)
+
+	// Create domain service
+	factsService := service.NewAnsibleFactsServiceImpl(factsRepo)
+
+	// Create application service
+	return application.NewAnsibleFactsManager(factsService)
+}
+
+// CreateRemoteManager creates a RemoteManager
+func (f *ServiceFactory) CreateRemoteManager() *application.RemoteManager {
+	// Create repository
+	remoteRepo := secondary.NewSSHRemoteRepository(f.provider.FS, f.provider.Commander)
+
+	// Create domain service
+	remoteService := service.NewRemoteServiceImpl(remoteRepo)
+
+	// Create application service
+	return application.NewRemoteManager(remoteService)
+}
+
+// JournalProvider returns a provider that records the changes made through
+// it as a run, and the journal doing the recording. Changes to the backup
+// directory are not part of the run.
+func JournalProvider(provider *interfaces.Provider, description string, cfg *config.Config) (*interfaces.Provider, *secondary.RunJournal) {
+	var exclude []string
+	if cfg != nil {
+		exclude = append(exclude, cfg.BackupPath)
+	}
+
+	journal := secondary.NewRunJournal(provider.FS, provider.Commander, model.RunsDir, description, exclude...)
+	return &interfaces.Provider{
+		FS:        journal.FileSystem(),
+		Commander: journal.Commander(),
+		Network:   provider.Network,
+	}, journal
+}
+
+// CreateWebServerManager creates a WebServerManager
+func (f *ServiceFactory) CreateWebServerManager() *application.WebServerManager {
+	// Create repository
+	webServerRepo := secondary.NewOSWebServerRepository(f.provider.FS, f.provider.Commander, f.osInfo.OsType)
+
+	// Create domain service
+	webServerService := service.NewWebServerServiceImpl(webServerRepo)
+
+	// Create application service
+	return application.NewWebServerManager(webServerService)
+}
+
+// CreateMACManager creates a MACManager
+func (f *ServiceFactory) CreateMACManager() *application.MACManager {
+	// Create repository
+	macRepo := secondary.NewOSMACRepository(f.provider.FS, f.provider.Commander, f.osInfo.OsType)
+
+	// Create domain service
+	macService := service.NewMACServiceImpl(macRepo, convertOSInfo(f.osInfo))
+
+	// Create application service
+	return application.NewMACManager(macService)
+}
+
+// CreateDatabaseManager creates a DatabaseManager
+func (f *ServiceFactory) CreateDatabaseManager() *application.DatabaseManager {
+	// Create repository
+	databaseRepo := secondary.NewOSDatabaseRepository(f.provider.FS, f.provider.Commander, f.osInfo.OsType)
+
+	// Create domain service
+	databaseService := service.NewDatabaseServiceImpl(databaseRepo)
+
+	// Create application service
+	return application.NewDatabaseManager(databaseService)
+}
+
+// CreateStateManager creates a StateManager with the repositories of the
+// users, SSH, firewall and package services whose settings a state declares
+func (f *ServiceFactory) CreateStateManager() *application.StateManager {
+	// Create repositories
+	stateRepo := secondary.NewOSStateRepository(f.provider.FS, f.provider.Commander)
+	sshProvider := f.moduleProvider(logging.ModuleSSH)
+	sshRepo := secondary.NewFileSSHRepository(sshProvider.FS, sshProvider.Commander, f.osInfo.OsType)
+	firewallProvider := f.moduleProvider(logging.ModuleFirewall)
+	firewallRepo := secondary.NewFirewallRepository(firewallProvider.FS, firewallProvider.Commander, f.osInfo.OsType)
+	packageProvider := f.moduleProvider(logging.ModulePackages)
+	packageRepo := secondary.NewOSPackageRepository(
+		packageProvider.FS,
+		packageProvider.Commander,
+		f.osInfo.OsType,
+		f.osInfo.OsVersion,
+		f.osInfo.OsCodename,
+		f.osInfo.Arch,
+		f.osInfo.IsProxmox,
+		f.packageSources(),
+	)
+	debloatRepo := secondary.NewOSDebloatRepository(packageProvider.Commander, f.osInfo.OsType)
+
+	// Create domain service
+	stateService := service.NewStateServiceImpl(stateRepo, f.getUserRepository(), sshRepo, firewallRepo, packageRepo, debloatRepo)
+
+	// Create application service
+	return application.NewStateManager(stateService)
+}
@@ -0,0 +1,123 @@
+// pkg/interfaces/command_env.go
+package interfaces
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CommandPath is the fixed PATH external tools run with
+const CommandPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+var (
+	commandProxyMu sync.RWMutex
+	commandProxy   string
+)
+
+// SetCommandProxy sets the HTTP(S) proxy passed to external commands.
+// An empty proxy means commands run without any proxy variables.
+func SetCommandProxy(proxy string) {
+	commandProxyMu.Lock()
+	defer commandProxyMu.Unlock()
+	commandProxy = proxy
+}
+
+// CommandEnv returns the sanitized environment for external commands: a fixed
+// PATH, the C locale so output can be parsed, and no inherited proxy, locale or
+// loader variables
+func CommandEnv() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		if dropFromCommandEnv(name) {
+			continue
+		}
+		env = append(env, kv)
+	}
+
+	env = append(env,
+		"PATH="+CommandPath,
+		"LC_ALL=C",
+		"LANG=C",
+	)
+
+	commandProxyMu.RLock()
+	proxy := commandProxy
+	commandProxyMu.RUnlock()
+	if proxy != "" {
+		env = append(env,
+			"http_proxy="+proxy,
+			"https_proxy="+proxy,
+			"HTTP_PROXY="+proxy,
+			"HTTPS_PROXY="+proxy,
+		)
+	}
+
+	return env
+}
+
+// dropFromCommandEnv reports whether an inherited variable is replaced or removed
+func dropFromCommandEnv(name string) bool {
+	switch upper := strings.ToUpper(name); {
+	case upper == "PATH", upper == "LANG", upper == "LANGUAGE", strings.HasPrefix(upper, "LC_"):
+		return true
+	case strings.HasSuffix(upper, "_PROXY"):
+		return true
+	case strings.HasPrefix(upper, "LD_"), upper == "IFS":
+		return true
+	}
+	return false
+}
+
+// LookPath searches CommandPath for an executable named name, the way
+// exec.LookPath searches PATH. A name containing a slash is used as is.
+func LookPath(name string) (string, error) {
+	if strings.Contains(name, "/") {
+		return exec.LookPath(name)
+	}
+	for _, dir := range filepath.SplitList(CommandPath) {
+		if path, err := exec.LookPath(filepath.Join(dir, name)); err == nil {
+			return path, nil
+		}
+	}
+	return "", &exec.Error{Name: name, Err: exec.ErrNotFound}
+}
+
+// Command returns an exec.Cmd that runs with CommandEnv. The name is
+// resolved on CommandPath rather than the inherited PATH, so the binary
+// that runs is the one the fixed PATH names.
+func Command(name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	resolveCommand(cmd, name)
+	cmd.Env = CommandEnv()
+	return cmd
+}
+
+// CommandContext returns an exec.Cmd that runs with CommandEnv and is
+// killed when ctx is done
+func CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	resolveCommand(cmd, name)
+	cmd.Env = CommandEnv()
+	return cmd
+}
+
+// resolveCommand points cmd at name as found on CommandPath; a name not
+// found there fails when cmd starts, even if the inherited PATH has it
+func resolveCommand(cmd *exec.Cmd, name string) {
+	if strings.Contains(name, "/") {
+		return
+	}
+	path, err := LookPath(name)
+	if err != nil {
+		cmd.Path = name
+		cmd.Err = err
+		return
+	}
+	cmd.Path = path
+	cmd.Err = nil
+}
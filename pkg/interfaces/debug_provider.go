@@ -0,0 +1,100 @@
+// pkg/interfaces/debug_provider.go
+package interfaces
+
+import (
+	"io/fs"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/logging"
+)
+
+// debugOutputLimit caps the command output a debug entry repeats
+const debugOutputLimit = 2048
+
+// DebugProvider returns a provider that logs the commands run and the files
+// changed through it as debug entries of module. Whether they are logged is
+// decided per call, so toggling the module at runtime takes effect at once.
+func DebugProvider(provider *Provider, module string) *Provider {
+	return &Provider{
+		FS:        &debugFileSystem{FileSystem: provider.FS, module: module},
+		Commander: &debugCommander{Commander: provider.Commander, module: module},
+		Network:   provider.Network,
+	}
+}
+
+type debugFileSystem struct {
+	FileSystem
+	module string
+}
+
+func (f *debugFileSystem) WriteFile(filename string, data []byte, perm fs.FileMode) error {
+	err := f.FileSystem.WriteFile(filename, data, perm)
+	if logging.DebugLogged(f.module) {
+		logging.LogModuleDebug(f.module, "write %s (%d bytes, %v): %s", filename, len(data), perm, errorText(err))
+	}
+	return err
+}
+
+func (f *debugFileSystem) Remove(name string) error {
+	err := f.FileSystem.Remove(name)
+	if logging.DebugLogged(f.module) {
+		logging.LogModuleDebug(f.module, "remove %s: %s", name, errorText(err))
+	}
+	return err
+}
+
+func (f *debugFileSystem) RemoveAll(path string) error {
+	err := f.FileSystem.RemoveAll(path)
+	if logging.DebugLogged(f.module) {
+		logging.LogModuleDebug(f.module, "remove all %s: %s", path, errorText(err))
+	}
+	return err
+}
+
+func (f *debugFileSystem) Rename(oldpath, newpath string) error {
+	err := f.FileSystem.Rename(oldpath, newpath)
+	if logging.DebugLogged(f.module) {
+		logging.LogModuleDebug(f.module, "rename %s to %s: %s", oldpath, newpath, errorText(err))
+	}
+	return err
+}
+
+type debugCommander struct {
+	Commander
+	module string
+}
+
+func (c *debugCommander) Execute(command string, args ...string) ([]byte, error) {
+	output, err := c.Commander.Execute(command, args...)
+	c.log(command, args, output, err)
+	return output, err
+}
+
+func (c *debugCommander) ExecuteWithInput(input string, command string, args ...string) ([]byte, error) {
+	output, err := c.Commander.ExecuteWithInput(input, command, args...)
+	c.log(command, args, output, err)
+	return output, err
+}
+
+func (c *debugCommander) log(command string, args []string, output []byte, err error) {
+	if !logging.DebugLogged(c.module) {
+		return
+	}
+	text := strings.TrimSpace(string(output))
+	if len(text) > debugOutputLimit {
+		text = text[:debugOutputLimit] + "..."
+	}
+	message := "run " + strings.Join(append([]string{command}, args...), " ") + ": " + errorText(err)
+	if text != "" {
+		message += "\n" + text
+	}
+	logging.LogModuleDebug(c.module, "%s", message)
+}
+
+// errorText describes the outcome of an operation for a debug entry
+func errorText(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return "ok"
+}
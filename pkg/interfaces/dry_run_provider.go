@@ -0,0 +1,370 @@
+// pkg/interfaces/dry_run_provider.go
+package interfaces
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// readOnlyCommands run as usual in dry-run mode; any other command is
+// printed instead. A nil entry allows every call. Otherwise the arguments
+// must match one of the patterns word by word: "*" at the end of a word
+// matches as a prefix, "?" matches any one argument, a word in brackets
+// may be left out, and a final "..." matches whatever follows. Matching
+// the whole call keeps a query word later in the arguments, as in
+// "zfs set ... get", from passing a change off as a query.
+var readOnlyCommands = map[string][]string{
+	"aa-status":             nil,
+	"cat":                   nil,
+	"df":                    nil,
+	"dpkg-query":            nil,
+	"findmnt":               nil,
+	"getenforce":            nil,
+	"getent":                nil,
+	"grub-mkpasswd-pbkdf2":  nil,
+	"grub2-mkpasswd-pbkdf2": nil,
+	"groups":                nil,
+	"id":                    nil,
+	"ip6tables-save":        nil,
+	"last":                  nil,
+	"ldd":                   nil,
+	"ls":                    nil,
+	"lvs":                   nil,
+	"netstat":               nil,
+	"pg_lsclusters":         nil,
+	"rc-status":             nil,
+	"readlink":              nil,
+	"ss":                    nil,
+	"uname":                 nil,
+	"uptime":                nil,
+	"which":                 nil,
+	"adjtimex":              {"", "-p"},
+	"age":                   {"-d -i ? ?"},
+	"domainname":            {""},
+	"hostname":              {"", "-f"},
+	"apk":                   {"info ...", "policy ...", "search ...", "version ..."},
+	"apt-get":               {"-s ..."},
+	"apt-mark":              {"showhold ...", "showmanual ...", "showauto ..."},
+	"caddy":                 {"validate ...", "version"},
+	"chronyc":               {"tracking", "sources ...", "sourcestats ...", "authdata ..."},
+	"firewall-cmd":          firewallQueries(),
+	"gpg":                   {"--show-keys ...", "--batch --status-fd ? --verify ...", "--batch --quiet --log-file /dev/null --decrypt ?"},
+	"grubby":                {"--info=*"},
+	"nginx":                 {"-t ...", "-T ...", "-v", "-V"},
+	"pveum":                 {"? list ..."},
+	"rc-service":            {"? status"},
+	"rc-update":             {"show ..."},
+	"resolvectl":            {"status ..."},
+	"rpm":                   {"-q* ...", "--query ...", "-e --test ..."},
+	"semanage":              {"? -l ..."},
+	"sshd":                  {"-t ...", "-T ..."},
+	"systemctl":             {"is-active ...", "is-enabled ...", "is-system-running ...", "status ...", "show ...", "cat ...", "list-units ...", "list-unit-files ..."},
+	"timedatectl":           {"show ...", "status ...", "timesync-status ...", "show-timesync ..."},
+	"ufw":                   {"status ...", "show ..."},
+	"zfs":                   {"list ...", "get ..."},
+}
+
+// findActions make find delete files, write them or run other commands
+var findActions = []string{"-delete", "-exec", "-execdir", "-ok", "-okdir", "-fls", "-fprint", "-fprint0", "-fprintf"}
+
+// firewallQueries returns the firewall-cmd calls that only query, on their
+// own or for a zone or service of the runtime or permanent configuration
+func firewallQueries() []string {
+	patterns := []string{"--state"}
+	for _, query := range []string{"--get-*", "--list-*", "--query-*", "--info-*"} {
+		patterns = append(patterns, "[--permanent] [--zone=*] [--service=*] "+query)
+	}
+	return patterns
+}
+
+// DryRunProvider returns a provider that, while enabled reports true,
+// previews changes instead of making them: each file write is printed to
+// out as a unified diff against the current content, and commands that
+// could change the system are printed instead of run. Later reads see the
+// previewed content, so a sequence of edits is diffed as a whole. Writes
+// below the exclude directories, such as backups, are dropped silently.
+// Whether changes are previewed is decided per call, so toggling dry-run
+// mode at runtime takes effect at once.
+func DryRunProvider(provider *Provider, enabled func() bool, out io.Writer, exclude ...string) *Provider {
+	return &Provider{
+		FS: &dryRunFileSystem{
+			FileSystem: provider.FS,
+			enabled:    enabled,
+			out:        out,
+			exclude:    exclude,
+			overlay:    make(map[string][]byte),
+		},
+		Commander: &dryRunCommander{Commander: provider.Commander, enabled: enabled, out: out},
+		Network:   provider.Network,
+	}
+}
+
+type dryRunFileSystem struct {
+	FileSystem
+	enabled func() bool
+	out     io.Writer
+	exclude []string
+
+	mu      sync.Mutex
+	overlay map[string][]byte // previewed content; nil marks a removed file
+}
+
+func (f *dryRunFileSystem) ReadFile(filename string) ([]byte, error) {
+	if content, ok := f.previewed(filename); ok {
+		if content == nil {
+			return nil, &fs.PathError{Op: "open", Path: filename, Err: fs.ErrNotExist}
+		}
+		return append([]byte(nil), content...), nil
+	}
+	return f.FileSystem.ReadFile(filename)
+}
+
+func (f *dryRunFileSystem) Stat(name string) (os.FileInfo, error) {
+	if content, ok := f.previewed(name); ok {
+		if content == nil {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+		}
+		if info, err := f.FileSystem.Stat(name); err == nil {
+			return info, nil
+		}
+		return previewFileInfo{name: filepath.Base(name), size: int64(len(content))}, nil
+	}
+	return f.FileSystem.Stat(name)
+}
+
+func (f *dryRunFileSystem) WriteFile(filename string, data []byte, perm fs.FileMode) error {
+	if !f.enabled() {
+		return f.FileSystem.WriteFile(filename, data, perm)
+	}
+	if f.excluded(filename) {
+		return nil
+	}
+
+	before, err := f.ReadFile(filename)
+	if err != nil {
+		before = nil
+	}
+
+	f.mu.Lock()
+	f.overlay[filename] = append([]byte{}, data...)
+	f.mu.Unlock()
+
+	diff := style.UnifiedDiff(filename, before, data)
+	if diff == "" {
+		fmt.Fprintf(f.out, "%s\n", style.Dimmed("[DRY-RUN] "+filename+" is unchanged"))
+		return nil
+	}
+	if logging.SensitiveFile(filename, before, data) {
+		fmt.Fprintf(f.out, "[DRY-RUN] Would write %s (%s)\n", filename, contentSuppressed)
+		return nil
+	}
+	fmt.Fprintf(f.out, "[DRY-RUN] Would write %s:\n%s", filename, logging.Redact(diff))
+	return nil
+}
+
+func (f *dryRunFileSystem) MkdirAll(path string, perm fs.FileMode) error {
+	if !f.enabled() {
+		return f.FileSystem.MkdirAll(path, perm)
+	}
+	return nil
+}
+
+func (f *dryRunFileSystem) Remove(name string) error {
+	if !f.enabled() {
+		return f.FileSystem.Remove(name)
+	}
+	if f.excluded(name) {
+		return nil
+	}
+
+	before, err := f.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	f.overlay[name] = nil
+	f.mu.Unlock()
+
+	if logging.SensitiveFile(name, before) {
+		fmt.Fprintf(f.out, "[DRY-RUN] Would remove %s (%s)\n", name, contentSuppressed)
+		return nil
+	}
+	fmt.Fprintf(f.out, "[DRY-RUN] Would remove %s:\n%s", name, logging.Redact(removalDiff(name, before)))
+	return nil
+}
+
+func (f *dryRunFileSystem) RemoveAll(path string) error {
+	if !f.enabled() {
+		return f.FileSystem.RemoveAll(path)
+	}
+	if !f.excluded(path) {
+		fmt.Fprintf(f.out, "[DRY-RUN] Would remove %s and everything below it\n", path)
+	}
+	return nil
+}
+
+func (f *dryRunFileSystem) Rename(oldpath, newpath string) error {
+	if !f.enabled() {
+		return f.FileSystem.Rename(oldpath, newpath)
+	}
+
+	// Files are often written next to their target and renamed into place
+	content, err := f.ReadFile(oldpath)
+	if err != nil {
+		return err
+	}
+	before, err := f.ReadFile(newpath)
+	if err != nil {
+		before = nil
+	}
+
+	f.mu.Lock()
+	f.overlay[oldpath] = nil
+	f.overlay[newpath] = content
+	f.mu.Unlock()
+
+	switch {
+	case f.excluded(newpath):
+	case logging.SensitiveFile(oldpath, before, content) || logging.SensitiveFile(newpath):
+		fmt.Fprintf(f.out, "[DRY-RUN] Would move %s to %s (%s)\n", oldpath, newpath, contentSuppressed)
+	default:
+		fmt.Fprintf(f.out, "[DRY-RUN] Would move %s to %s:\n%s", oldpath, newpath,
+			logging.Redact(style.UnifiedDiff(newpath, before, content)))
+	}
+	return nil
+}
+
+// previewed returns the content a dry-run change left for name. Previews
+// are forgotten once dry-run mode is turned off.
+func (f *dryRunFileSystem) previewed(name string) ([]byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.enabled() {
+		clear(f.overlay)
+		return nil, false
+	}
+	content, ok := f.overlay[name]
+	return content, ok
+}
+
+// excluded reports whether path is below one of the excluded directories
+func (f *dryRunFileSystem) excluded(path string) bool {
+	for _, dir := range f.exclude {
+		if dir == "" {
+			continue
+		}
+		if rel, err := filepath.Rel(dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+	return false
+}
+
+// contentSuppressed replaces the diff of a file that holds secrets
+const contentSuppressed = "content suppressed, the file holds secrets"
+
+// removalDiff shows every line of a removed file as removed
+func removalDiff(name string, before []byte) string {
+	diff := style.UnifiedDiff(name, before, []byte{})
+	return strings.Replace(diff, "+++ b"+name, "+++ /dev/null", 1)
+}
+
+// previewFileInfo describes a file that only exists as a dry-run preview
+type previewFileInfo struct {
+	name string
+	size int64
+}
+
+func (i previewFileInfo) Name() string       { return i.name }
+func (i previewFileInfo) Size() int64        { return i.size }
+func (i previewFileInfo) Mode() fs.FileMode  { return 0644 }
+func (i previewFileInfo) ModTime() time.Time { return time.Time{} }
+func (i previewFileInfo) IsDir() bool        { return false }
+func (i previewFileInfo) Sys() any           { return nil }
+
+type dryRunCommander struct {
+	Commander
+	enabled func() bool
+	out     io.Writer
+}
+
+func (c *dryRunCommander) Execute(command string, args ...string) ([]byte, error) {
+	if !c.enabled() || readOnlyCommand(command, args) {
+		return c.Commander.Execute(command, args...)
+	}
+	fmt.Fprintf(c.out, "[DRY-RUN] Would run: %s\n", logging.Redact(strings.Join(append([]string{command}, args...), " ")))
+	return nil, nil
+}
+
+func (c *dryRunCommander) ExecuteWithInput(input string, command string, args ...string) ([]byte, error) {
+	if !c.enabled() || readOnlyCommand(command, args) {
+		return c.Commander.ExecuteWithInput(input, command, args...)
+	}
+	fmt.Fprintf(c.out, "[DRY-RUN] Would run: %s\n", logging.Redact(strings.Join(append([]string{command}, args...), " ")))
+	return nil, nil
+}
+
+// readOnlyCommand reports whether a command only inspects the system, so
+// it can run in dry-run mode
+func readOnlyCommand(command string, args []string) bool {
+	name := filepath.Base(command)
+	if name == "find" {
+		return !slices.ContainsFunc(args, func(arg string) bool { return slices.Contains(findActions, arg) })
+	}
+
+	patterns, ok := readOnlyCommands[name]
+	if !ok {
+		return false
+	}
+	if patterns == nil {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if matchArgs(strings.Fields(pattern), args) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchArgs reports whether args match a pattern of readOnlyCommands
+func matchArgs(pattern, args []string) bool {
+	if len(pattern) == 0 {
+		return len(args) == 0
+	}
+
+	word := pattern[0]
+	if word == "..." {
+		return true
+	}
+	if optional, ok := strings.CutPrefix(word, "["); ok {
+		optional = strings.TrimSuffix(optional, "]")
+		if len(args) > 0 && matchWord(optional, args[0]) && matchArgs(pattern[1:], args[1:]) {
+			return true
+		}
+		return matchArgs(pattern[1:], args)
+	}
+	return len(args) > 0 && matchWord(word, args[0]) && matchArgs(pattern[1:], args[1:])
+}
+
+// matchWord reports whether an argument matches one word of a pattern
+func matchWord(word, arg string) bool {
+	if word == "?" {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(word, "*"); ok {
+		return strings.HasPrefix(arg, prefix)
+	}
+	return arg == word
+}
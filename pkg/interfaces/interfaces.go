@@ -14,6 +14,7 @@ type FileSystem interface {
 	Stat(name string) (os.FileInfo, error)
 	Remove(name string) error
 	RemoveAll(path string) error
+	Rename(oldpath, newpath string) error
 }
 
 // Commander abstracts command execution
@@ -24,6 +24,7 @@ type MockFileSystem struct {
 	StatError      map[string]error
 	RemoveError    map[string]error
 	RemoveAllError map[string]error
+	RenameError    map[string]error
 }
 
 // NewMockFileSystem creates a new initialized MockFileSystem
@@ -38,6 +39,7 @@ func NewMockFileSystem() *MockFileSystem {
 		StatError:      make(map[string]error),
 		RemoveError:    make(map[string]error),
 		RemoveAllError: make(map[string]error),
+		RenameError:    make(map[string]error),
 	}
 }
 
@@ -136,6 +138,21 @@ func (m MockFileSystem) RemoveAll(path string) error {
 	return nil
 }
 
+func (m MockFileSystem) Rename(oldpath, newpath string) error {
+	if err, ok := m.RenameError[oldpath]; ok && err != nil {
+		return err
+	}
+
+	data, exists := m.Files[oldpath]
+	if !exists {
+		return os.ErrNotExist
+	}
+
+	m.Files[newpath] = data
+	delete(m.Files, oldpath)
+	return nil
+}
+
 // Mock implementation of os.FileInfo for testing
 type mockFileInfo struct {
 	name  string
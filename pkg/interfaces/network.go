@@ -0,0 +1,34 @@
+// pkg/interfaces/network.go
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrOffline is returned instead of reaching the internet in offline mode
+var ErrOffline = errors.New("offline mode is on (--offline or offline: true)")
+
+var offline atomic.Bool
+
+// SetOffline turns offline mode on or off. In offline mode hardn makes no
+// calls to the internet: no update check, key imports or advisory
+// downloads. Webhooks and log shipping still reach the hosts configured.
+func SetOffline(enabled bool) {
+	offline.Store(enabled)
+}
+
+// Offline reports whether offline mode is on
+func Offline() bool {
+	return offline.Load()
+}
+
+// RequireNetwork returns an error wrapping ErrOffline, naming what needs
+// the network, when offline mode is on
+func RequireNetwork(action string) error {
+	if Offline() {
+		return fmt.Errorf("cannot %s: %w", action, ErrOffline)
+	}
+	return nil
+}
@@ -3,19 +3,19 @@ package interfaces
 
 import (
 	"bytes"
-	"os/exec"
 )
 
-// OSCommander is an implementation of Commander using os/exec
+// OSCommander is an implementation of Commander using os/exec.
+// Commands run with the sanitized environment from CommandEnv.
 type OSCommander struct{}
 
 func (c OSCommander) Execute(command string, args ...string) ([]byte, error) {
-	cmd := exec.Command(command, args...)
+	cmd := Command(command, args...)
 	return cmd.CombinedOutput()
 }
 
 func (c OSCommander) ExecuteWithInput(input string, command string, args ...string) ([]byte, error) {
-	cmd := exec.Command(command, args...)
+	cmd := Command(command, args...)
 
 	stdin := bytes.NewBufferString(input)
 	cmd.Stdin = stdin
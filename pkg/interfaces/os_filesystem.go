@@ -40,3 +40,7 @@ func (fs OSFileSystem) Remove(name string) error {
 func (fs OSFileSystem) RemoveAll(path string) error {
 	return os.RemoveAll(path)
 }
+
+func (fs OSFileSystem) Rename(oldpath, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
@@ -8,13 +8,18 @@ type Provider struct {
 	Network   NetworkOperations
 }
 
-// NewProvider creates a new Provider with default implementations
+// NewProvider creates a new Provider with default implementations, which
+// escalate privileged operations with sudo once EnableSudo succeeded
 func NewProvider() *Provider {
-	return &Provider{
+	provider := &Provider{
 		FS:        OSFileSystem{},
 		Commander: OSCommander{},
 		Network:   OSNetworkOperations{},
 	}
+	if SudoEnabled() {
+		return SudoProvider(provider)
+	}
+	return provider
 }
 
 // MockProvider creates a Provider with mock implementations for testing
@@ -0,0 +1,310 @@
+// pkg/interfaces/sudo_provider.go
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// unprivilegedCommands never need root, so they run as the user even when
+// commands are escalated with sudo
+var unprivilegedCommands = map[string]bool{
+	"domainname": true,
+	"groups":     true,
+	"hostname":   true,
+	"id":         true,
+	"uname":      true,
+	"uptime":     true,
+	"which":      true,
+}
+
+// sudoPrompt is shown when sudo asks for the user's password
+const sudoPrompt = "[hardn] sudo password for %u: "
+
+var (
+	sudoMu      sync.RWMutex
+	sudoEnabled bool
+)
+
+// IsRoot reports whether hardn runs as root
+func IsRoot() bool {
+	return os.Geteuid() == 0
+}
+
+// EnableSudo makes the providers of NewProvider escalate privileged
+// commands and file changes with sudo, when hardn does not run as root.
+// The user is asked for their password once here; the commands that
+// follow use sudo's cached timestamp and never prompt.
+func EnableSudo() error {
+	if IsRoot() || SudoEnabled() {
+		return nil
+	}
+	if _, err := LookPath("sudo"); err != nil {
+		return fmt.Errorf("hardn needs root or sudo to change the system: %w", err)
+	}
+
+	// NOPASSWD rules and a cached timestamp need no prompt
+	if err := Command("sudo", "-n", "true").Run(); err != nil {
+		validate := Command("sudo", "-v", "-p", sudoPrompt)
+		validate.Stdin, validate.Stdout, validate.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := validate.Run(); err != nil {
+			return fmt.Errorf("sudo authentication failed: %w", err)
+		}
+	}
+
+	sudoMu.Lock()
+	sudoEnabled = true
+	sudoMu.Unlock()
+	return nil
+}
+
+// SudoEnabled reports whether privileged operations are escalated with sudo
+func SudoEnabled() bool {
+	sudoMu.RLock()
+	defer sudoMu.RUnlock()
+	return sudoEnabled
+}
+
+// SudoProvider returns a provider that runs commands through sudo, and
+// falls back to sudo for file operations the user is not permitted to do.
+// Commands run with sudo -n, so they fail instead of prompting once the
+// cached timestamp has expired.
+func SudoProvider(provider *Provider) *Provider {
+	commander := &sudoCommander{Commander: provider.Commander}
+	return &Provider{
+		FS:        &sudoFileSystem{FileSystem: provider.FS, sudo: commander},
+		Commander: commander,
+		Network:   provider.Network,
+	}
+}
+
+type sudoCommander struct {
+	Commander
+}
+
+func (c *sudoCommander) Execute(command string, args ...string) ([]byte, error) {
+	if unprivilegedCommands[command] {
+		return c.Commander.Execute(command, args...)
+	}
+	return c.Commander.Execute("sudo", sudoArgs(command, args)...)
+}
+
+func (c *sudoCommander) ExecuteWithInput(input string, command string, args ...string) ([]byte, error) {
+	if unprivilegedCommands[command] {
+		return c.Commander.ExecuteWithInput(input, command, args...)
+	}
+	return c.Commander.ExecuteWithInput(input, "sudo", sudoArgs(command, args)...)
+}
+
+// sudoArgs returns the sudo arguments that run a command as root with the
+// environment of CommandEnv, which sudo would otherwise reset
+func sudoArgs(command string, args []string) []string {
+	sudoArgs := []string{"-n", "--", "env", "PATH=" + CommandPath, "LC_ALL=C", "LANG=C"}
+
+	commandProxyMu.RLock()
+	proxy := commandProxy
+	commandProxyMu.RUnlock()
+	if proxy != "" {
+		sudoArgs = append(sudoArgs,
+			"http_proxy="+proxy,
+			"https_proxy="+proxy,
+			"HTTP_PROXY="+proxy,
+			"HTTPS_PROXY="+proxy,
+		)
+	}
+
+	return append(append(sudoArgs, command), args...)
+}
+
+// sudoFileSystem does each file operation as the user first and repeats
+// it with sudo when the user is not permitted to
+type sudoFileSystem struct {
+	FileSystem
+	sudo Commander
+}
+
+func (f *sudoFileSystem) ReadFile(filename string) ([]byte, error) {
+	data, err := f.FileSystem.ReadFile(filename)
+	if !errors.Is(err, fs.ErrPermission) {
+		return data, err
+	}
+	output, err := f.sudo.Execute("cat", "--", filename)
+	if err != nil {
+		return nil, sudoPathError("open", filename, output, err)
+	}
+	return output, nil
+}
+
+func (f *sudoFileSystem) WriteFile(filename string, data []byte, perm fs.FileMode) error {
+	err := f.FileSystem.WriteFile(filename, data, perm)
+	if !errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+
+	_, statErr := f.Stat(filename)
+	if dir := filepath.Dir(filename); dir != "." {
+		if output, err := f.sudo.Execute("mkdir", "-p", "--", dir); err != nil {
+			return sudoPathError("mkdir", dir, output, err)
+		}
+	}
+	// Like os.WriteFile, perm only applies to new files. Create the file
+	// empty with its mode first, so data never sits under the umask's mode
+	if statErr != nil {
+		if output, err := f.sudo.Execute("install", "-m", strconv.FormatUint(uint64(perm.Perm()), 8), "--", "/dev/null", filename); err != nil {
+			return sudoPathError("open", filename, output, err)
+		}
+	}
+	// dd writes stdin to the file without echoing it, as tee would
+	if output, err := f.sudo.ExecuteWithInput(string(data), "dd", "of="+filename, "status=none"); err != nil {
+		return sudoPathError("open", filename, output, err)
+	}
+	return nil
+}
+
+func (f *sudoFileSystem) MkdirAll(path string, perm fs.FileMode) error {
+	err := f.FileSystem.MkdirAll(path, perm)
+	if !errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+	if output, err := f.sudo.Execute("mkdir", "-p", "-m", strconv.FormatUint(uint64(perm.Perm()), 8), "--", path); err != nil {
+		return sudoPathError("mkdir", path, output, err)
+	}
+	return nil
+}
+
+func (f *sudoFileSystem) Stat(name string) (os.FileInfo, error) {
+	info, err := f.FileSystem.Stat(name)
+	if !errors.Is(err, fs.ErrPermission) {
+		return info, err
+	}
+	output, err := f.sudo.Execute("stat", "-c", "%s %f %Y", "--", name)
+	if err != nil {
+		return nil, sudoPathError("stat", name, output, err)
+	}
+	info, ok := parseStat(filepath.Base(name), string(output))
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fmt.Errorf("unexpected stat output %q", strings.TrimSpace(string(output)))}
+	}
+	return info, nil
+}
+
+func (f *sudoFileSystem) Remove(name string) error {
+	err := f.FileSystem.Remove(name)
+	if !errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+	command := "rm"
+	if info, err := f.Stat(name); err == nil && info.IsDir() {
+		command = "rmdir"
+	}
+	if output, err := f.sudo.Execute(command, "--", name); err != nil {
+		return sudoPathError("remove", name, output, err)
+	}
+	return nil
+}
+
+func (f *sudoFileSystem) RemoveAll(path string) error {
+	err := f.FileSystem.RemoveAll(path)
+	if !errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+	if output, err := f.sudo.Execute("rm", "-rf", "--", path); err != nil {
+		return sudoPathError("unlinkat", path, output, err)
+	}
+	return nil
+}
+
+func (f *sudoFileSystem) Rename(oldpath, newpath string) error {
+	err := f.FileSystem.Rename(oldpath, newpath)
+	if !errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+	if output, err := f.sudo.Execute("mv", "-f", "--", oldpath, newpath); err != nil {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: sudoError(output, err)}
+	}
+	return nil
+}
+
+// sudoPathError reports a failed escalated file operation; a missing file
+// is reported as fs.ErrNotExist, as the os package would
+func sudoPathError(op, path string, output []byte, err error) error {
+	if strings.Contains(string(output), "No such file or directory") {
+		return &fs.PathError{Op: op, Path: path, Err: fs.ErrNotExist}
+	}
+	return &fs.PathError{Op: op, Path: path, Err: sudoError(output, err)}
+}
+
+// sudoError adds the output of a failed sudo command to its error
+func sudoError(output []byte, err error) error {
+	if text := strings.TrimSpace(string(output)); text != "" {
+		return fmt.Errorf("sudo: %w: %s", err, text)
+	}
+	return fmt.Errorf("sudo: %w", err)
+}
+
+// sudoFileInfo describes a file stat'ed through sudo
+type sudoFileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i sudoFileInfo) Name() string       { return i.name }
+func (i sudoFileInfo) Size() int64        { return i.size }
+func (i sudoFileInfo) Mode() fs.FileMode  { return i.mode }
+func (i sudoFileInfo) ModTime() time.Time { return i.modTime }
+func (i sudoFileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i sudoFileInfo) Sys() any           { return nil }
+
+// parseStat reads the size, raw mode in hex and modification time printed
+// by stat -c "%s %f %Y"
+func parseStat(name, output string) (os.FileInfo, bool) {
+	fields := strings.Fields(output)
+	if len(fields) != 3 {
+		return nil, false
+	}
+	size, err1 := strconv.ParseInt(fields[0], 10, 64)
+	raw, err2 := strconv.ParseUint(fields[1], 16, 32)
+	mtime, err3 := strconv.ParseInt(fields[2], 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return nil, false
+	}
+	return sudoFileInfo{name: name, size: size, mode: unixFileMode(uint32(raw)), modTime: time.Unix(mtime, 0)}, true
+}
+
+// unixFileMode converts a raw st_mode to an fs.FileMode
+func unixFileMode(raw uint32) fs.FileMode {
+	mode := fs.FileMode(raw & 0777)
+	switch raw & 0170000 {
+	case 0040000:
+		mode |= fs.ModeDir
+	case 0120000:
+		mode |= fs.ModeSymlink
+	case 0010000:
+		mode |= fs.ModeNamedPipe
+	case 0140000:
+		mode |= fs.ModeSocket
+	case 0020000:
+		mode |= fs.ModeDevice | fs.ModeCharDevice
+	case 0060000:
+		mode |= fs.ModeDevice
+	}
+	if raw&04000 != 0 {
+		mode |= fs.ModeSetuid
+	}
+	if raw&02000 != 0 {
+		mode |= fs.ModeSetgid
+	}
+	if raw&01000 != 0 {
+		mode |= fs.ModeSticky
+	}
+	return mode
+}
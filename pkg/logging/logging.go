@@ -2,52 +2,196 @@ package logging
 
 import (
 	"fmt"
-	"log"
 	"os"
-	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/fatih/color"
 )
 
+// Level is the severity of a log entry
+type Level int
+
+// Log levels, from the most to the least verbose
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+// String returns the level's name as used by --log-level
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
+// ParseLevel parses a level name: debug, info, warn (or warning) or error
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info", "":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q (use debug, info, warn or error)", name)
+}
+
+// Options configures the log file and the sinks entries are shipped to
+type Options struct {
+	Path   string
+	Level  string
+	Format string
+	// MaxSize rotates the log file once it grows past this many bytes;
+	// zero never rotates
+	MaxSize    int64
+	MaxBackups int
+	// Sinks names additional sinks: syslog or journald
+	Sinks []string
+}
+
 var (
-	logger  *log.Logger
-	logFile *os.File
+	mu sync.Mutex
+	// level hides entries below it on the console and in every sink
+	level = LevelInfo
+	// levelForced is set by the --log-level flag, which takes precedence
+	// over the configured level
+	levelForced bool
+	options     Options
+	fileSink    *FileSink
+	namedSinks  []Sink
+	extraSinks  []Sink
 	// Add silent mode flag
 	silentMode bool
 )
 
 // InitLogging initializes the logger for the application
 func InitLogging(logPath string) {
-	// Create log directory if it doesn't exist
-	dir := filepath.Dir(logPath)
-	if dir != "." {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			fmt.Printf("Failed to create log directory: %v\n", err)
-		}
+	if err := Configure(Options{Path: logPath}); err != nil {
+		fmt.Println(err)
 	}
+}
 
-	// Open log file
-	var err error
-	logFile, err = os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+// Configure applies the logging options, reopening the log file and
+// sinks when they changed. A level set with ForceLevel is kept.
+func Configure(opts Options) error {
+	configuredLevel, err := ParseLevel(opts.Level)
+	if err != nil {
+		return err
+	}
+	format, err := ParseFormat(opts.Format)
 	if err != nil {
-		fmt.Printf("Failed to open log file %s: %v\n", logPath, err)
-		logFile = nil
+		return err
 	}
 
-	// Create logger
-	if logFile != nil {
-		logger = log.New(logFile, "", log.LstdFlags)
-	} else {
-		logger = log.New(os.Stderr, "", log.LstdFlags)
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !levelForced {
+		level = configuredLevel
+	}
+
+	// A log file that could not be opened is not retried until the path
+	// changes, so the failure is reported once
+	var errs []string
+	if opts.Path != options.Path {
+		closeFileSink()
+		fileSink, err = NewFileSink(opts.Path, format, opts.MaxSize, opts.MaxBackups)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	} else if fileSink != nil {
+		fileSink.configure(format, opts.MaxSize, opts.MaxBackups)
+	}
+
+	if strings.Join(opts.Sinks, ",") != strings.Join(options.Sinks, ",") {
+		closeSinks(namedSinks)
+		namedSinks = nil
+		for _, name := range opts.Sinks {
+			sink, err := NewNamedSink(name)
+			if err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			namedSinks = append(namedSinks, sink)
+		}
+	}
+
+	options = opts
+	if len(errs) > 0 {
+		return fmt.Errorf("logging: %s", strings.Join(errs, "; "))
 	}
+	return nil
+}
+
+// SetLevel sets the lowest level that is logged
+func SetLevel(l Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	level = l
+}
+
+// ForceLevel sets the level for the rest of the process, ignoring the
+// configured one; it backs the --log-level flag
+func ForceLevel(l Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	level = l
+	levelForced = true
+}
+
+// GetLevel returns the lowest level that is logged
+func GetLevel() Level {
+	mu.Lock()
+	defer mu.Unlock()
+	return level
+}
+
+// AddSink ships every logged entry to sink as well, until CloseLogging
+func AddSink(sink Sink) {
+	mu.Lock()
+	defer mu.Unlock()
+	extraSinks = append(extraSinks, sink)
 }
 
 // CloseLogging closes the log file
 func CloseLogging() {
-	if logFile != nil {
-		if err := logFile.Close(); err != nil {
+	mu.Lock()
+	defer mu.Unlock()
+
+	closeFileSink()
+	closeSinks(namedSinks)
+	closeSinks(extraSinks)
+	namedSinks, extraSinks = nil, nil
+	options = Options{}
+}
+
+func closeFileSink() {
+	if fileSink != nil {
+		if err := fileSink.Close(); err != nil {
 			fmt.Printf("Failed to close log file: %v\n", err)
 		}
+		fileSink = nil
+	}
+}
+
+func closeSinks(sinks []Sink) {
+	for _, sink := range sinks {
+		_ = sink.Close()
 	}
 }
 
@@ -61,59 +205,76 @@ func IsSilent() bool {
 	return silentMode
 }
 
-// LogError logs an error message
-func LogError(format string, v ...interface{}) {
-	msg := fmt.Sprintf(format, v...)
+// write prints an entry to the console and ships it to the sinks
+func write(l Level, label string, print func(format string, a ...interface{}), msg string) {
+	writeEntry(l, label, "", print, msg)
+}
+
+// colorDebug prints debug entries on the console
+var colorDebug = color.White
+
+// writeEntry logs an entry of a module, or of no module in particular. A
+// sink that fails is reported on stderr without stopping the others.
+func writeEntry(l Level, label, module string, print func(format string, a ...interface{}), msg string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if l < level && !moduleDebugEnabledLocked(module) {
+		return
+	}
+	msg = Redact(msg)
 	if !silentMode {
-		color.Red("[ERROR] %s", msg)
+		if module != "" {
+			print("[%s] [%s] %s", label, module, msg)
+		} else {
+			print("[%s] %s", label, msg)
+		}
 	}
-	if logger != nil {
-		logger.Printf("ERROR: %s", msg)
+
+	entry := Entry{Time: time.Now(), Level: l, Label: label, Module: module, Message: msg}
+	sinks := append(append([]Sink{}, namedSinks...), extraSinks...)
+	if fileSink != nil {
+		sinks = append([]Sink{fileSink}, sinks...)
+	} else {
+		// Without a log file entries still reach stderr
+		fmt.Fprintln(os.Stderr, FormatEntry(entry, FormatText))
+	}
+	for _, sink := range sinks {
+		if err := sink.Write(entry); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write log entry: %v\n", err)
+		}
 	}
 }
 
+// LogDebug logs a debug message, shown only with --log-level debug; use
+// LogModuleDebug for messages that belong to a module
+func LogDebug(format string, v ...interface{}) {
+	write(LevelDebug, "DEBUG", colorDebug, fmt.Sprintf(format, v...))
+}
+
+// LogError logs an error message
+func LogError(format string, v ...interface{}) {
+	write(LevelError, "ERROR", color.Red, fmt.Sprintf(format, v...))
+}
+
 // LogWarning logs a warning message
 func LogWarning(format string, v ...interface{}) {
-	msg := fmt.Sprintf(format, v...)
-	if !silentMode {
-		color.Yellow("[WARNING] %s", msg)
-	}
-	if logger != nil {
-		logger.Printf("WARNING: %s", msg)
-	}
+	write(LevelWarn, "WARNING", color.Yellow, fmt.Sprintf(format, v...))
 }
 
 // LogInfo logs an info message
 func LogInfo(format string, v ...interface{}) {
-	msg := fmt.Sprintf(format, v...)
-	if !silentMode {
-		color.Blue("[INFO] %s", msg)
-	}
-	if logger != nil {
-		logger.Printf("INFO: %s", msg)
-	}
+	write(LevelInfo, "INFO", color.Blue, fmt.Sprintf(format, v...))
 }
 
 // LogSuccess logs a success message
 func LogSuccess(format string, v ...interface{}) {
-	msg := fmt.Sprintf(format, v...)
-	if !silentMode {
-		color.Green("[SUCCESS] %s", msg)
-	}
-	if logger != nil {
-		logger.Printf("SUCCESS: %s", msg)
-	}
+	write(LevelInfo, "SUCCESS", color.Green, fmt.Sprintf(format, v...))
 }
 
 // LogInstall logs a package installation
 func LogInstall(format string, v ...interface{}) {
-	msg := fmt.Sprintf(format, v...)
-	if !silentMode {
-		color.Cyan("[INSTALLED] %s", msg)
-	}
-	if logger != nil {
-		logger.Printf("INSTALLED: %s", msg)
-	}
+	write(LevelInfo, "INSTALLED", color.Cyan, fmt.Sprintf(format, v...))
 }
 
 // PrintLogs prints the content of the log file
@@ -0,0 +1,135 @@
+package logging
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DebugEnv names the environment variable that enables the debug entries
+// of some modules, as in HARDN_DEBUG=firewall,ssh
+const DebugEnv = "HARDN_DEBUG"
+
+// Modules whose debug entries can be enabled on their own
+const (
+	ModuleSSH      = "ssh"
+	ModuleFirewall = "firewall"
+	ModuleDNS      = "dns"
+	ModuleUsers    = "users"
+	ModulePackages = "packages"
+	ModuleBackup   = "backup"
+	ModuleVersion  = "version"
+	// ModuleAll enables the debug entries of every module
+	ModuleAll = "all"
+)
+
+// Modules lists the modules in the order the menu shows them
+var Modules = []string{
+	ModuleSSH,
+	ModuleFirewall,
+	ModuleDNS,
+	ModuleUsers,
+	ModulePackages,
+	ModuleBackup,
+	ModuleVersion,
+}
+
+// debugModules are the modules whose debug entries are logged whatever
+// the level
+var debugModules = make(map[string]bool)
+
+// ParseDebugModules parses a comma-separated list of modules. "1", "true"
+// and "*" mean all modules, as HARDN_DEBUG=1 always has. Unknown modules
+// are reported in the error; the known ones are still returned.
+func ParseDebugModules(value string) ([]string, error) {
+	var modules, unknown []string
+	for _, field := range strings.Split(value, ",") {
+		module := strings.ToLower(strings.TrimSpace(field))
+		switch module {
+		case "":
+			continue
+		case "1", "true", "*", ModuleAll:
+			modules = append(modules, ModuleAll)
+			continue
+		}
+		if !knownModule(module) {
+			unknown = append(unknown, module)
+			continue
+		}
+		modules = append(modules, module)
+	}
+
+	if len(unknown) > 0 {
+		return modules, fmt.Errorf("unknown debug module(s) %s (use %s or all)",
+			strings.Join(unknown, ", "), strings.Join(Modules, ", "))
+	}
+	return modules, nil
+}
+
+func knownModule(module string) bool {
+	for _, known := range Modules {
+		if module == known {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDebugModules replaces the modules whose debug entries are logged
+func SetDebugModules(modules []string) {
+	mu.Lock()
+	defer mu.Unlock()
+	debugModules = make(map[string]bool)
+	for _, module := range modules {
+		debugModules[module] = true
+	}
+}
+
+// SetModuleDebug enables or disables the debug entries of one module
+func SetModuleDebug(module string, enabled bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	if enabled {
+		debugModules[module] = true
+	} else {
+		delete(debugModules, module)
+	}
+}
+
+// DebugModules returns the modules whose debug entries are enabled, sorted
+func DebugModules() []string {
+	mu.Lock()
+	defer mu.Unlock()
+	modules := make([]string, 0, len(debugModules))
+	for module := range debugModules {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+// ModuleDebugEnabled reports whether the debug entries of module were
+// enabled on their own or with "all"; the debug level alone does not count
+func ModuleDebugEnabled(module string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return moduleDebugEnabledLocked(module)
+}
+
+// DebugLogged reports whether debug entries of module are logged, by the
+// level or the module's toggle; callers use it to skip costly messages
+func DebugLogged(module string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return level <= LevelDebug || moduleDebugEnabledLocked(module)
+}
+
+func moduleDebugEnabledLocked(module string) bool {
+	return module != "" && (debugModules[module] || debugModules[ModuleAll])
+}
+
+// LogModuleDebug logs a debug message of a module, shown with --log-level
+// debug or when the module's debug entries are enabled
+func LogModuleDebug(module string, format string, v ...interface{}) {
+	writeEntry(LevelDebug, "DEBUG", module, colorDebug, fmt.Sprintf(format, v...))
+}
@@ -0,0 +1,62 @@
+// pkg/logging/redact.go
+package logging
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces secrets in log entries and dry-run output
+const Redacted = "[REDACTED]"
+
+// minSecretLength keeps short values, which would hide unrelated text,
+// from being redacted
+const minSecretLength = 4
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string
+)
+
+// AddSecrets registers values, such as the decrypted secrets of the
+// configuration, to redact from here on
+func AddSecrets(values ...string) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, value := range values {
+		if len(value) >= minSecretLength {
+			secrets = append(secrets, value)
+		}
+	}
+}
+
+// SensitiveFile reports whether a file holds password hashes or keys, by
+// its path or any of contents, so they must not be shown in dry-run output
+// or copied into a report
+func SensitiveFile(path string, contents ...[]byte) bool {
+	switch strings.TrimSuffix(filepath.Base(path), "-") {
+	case "shadow", "gshadow":
+		return true
+	}
+	if strings.HasSuffix(path, ".key") {
+		return true
+	}
+	for _, content := range contents {
+		if bytes.Contains(content, []byte("PRIVATE KEY")) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact replaces the registered secrets in s
+func Redact(s string) string {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+	return s
+}
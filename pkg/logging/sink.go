@@ -0,0 +1,236 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Format is how entries are written to the log file
+type Format string
+
+// Log file formats
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+// textTimeLayout matches the standard library logger hardn used before
+// formats were configurable, so older log files still parse
+const textTimeLayout = "2006/01/02 15:04:05"
+
+// ParseFormat parses a log file format: text or json
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(name))) {
+	case FormatText, "":
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	}
+	return FormatText, fmt.Errorf("unknown log format %q (use text or json)", name)
+}
+
+// Entry is one logged message
+type Entry struct {
+	Time  time.Time
+	Level Level
+	// Label is the tag shown on the console, such as SUCCESS or INSTALLED
+	// for info entries
+	Label string
+	// Module is set on the debug entries of a module
+	Module  string
+	Message string
+}
+
+// jsonEntry is the JSON form of an Entry, one object per line
+type jsonEntry struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Label   string    `json:"label"`
+	Module  string    `json:"module,omitempty"`
+	Message string    `json:"message"`
+}
+
+// Sink receives every entry at or above the logging level. Sinks are
+// called with the logging lock held and must not log themselves.
+type Sink interface {
+	Write(entry Entry) error
+	Close() error
+}
+
+// FormatEntry formats an entry as one line of the log file
+func FormatEntry(entry Entry, format Format) string {
+	if format == FormatJSON {
+		data, err := json.Marshal(jsonEntry{
+			Time:    entry.Time,
+			Level:   entry.Level.String(),
+			Label:   entry.Label,
+			Module:  entry.Module,
+			Message: entry.Message,
+		})
+		if err == nil {
+			return string(data)
+		}
+	}
+	message := entry.Message
+	if entry.Module != "" {
+		message = "[" + entry.Module + "] " + message
+	}
+	return fmt.Sprintf("%s %s: %s", entry.Time.Format(textTimeLayout), entry.Label, message)
+}
+
+// ParseEntry parses a line of the log file in either format
+func ParseEntry(line string) (Entry, bool) {
+	if strings.HasPrefix(line, "{") {
+		var parsed jsonEntry
+		if err := json.Unmarshal([]byte(line), &parsed); err != nil {
+			return Entry{}, false
+		}
+		entryLevel, err := ParseLevel(parsed.Level)
+		if err != nil {
+			return Entry{}, false
+		}
+		return Entry{Time: parsed.Time, Level: entryLevel, Label: parsed.Label, Module: parsed.Module,
+			Message: parsed.Message}, true
+	}
+
+	if len(line) < len(textTimeLayout)+1 {
+		return Entry{}, false
+	}
+	entryTime, err := time.ParseInLocation(textTimeLayout, line[:len(textTimeLayout)], time.Local)
+	if err != nil {
+		return Entry{}, false
+	}
+	label, message, ok := strings.Cut(line[len(textTimeLayout)+1:], ": ")
+	if !ok {
+		return Entry{}, false
+	}
+	entry := Entry{Time: entryTime, Level: labelLevel(label), Label: label, Message: message}
+
+	// Debug entries of a known module start with the module in brackets
+	if label == "DEBUG" && strings.HasPrefix(message, "[") {
+		if module, rest, ok := strings.Cut(message[1:], "] "); ok && knownModule(module) {
+			entry.Module, entry.Message = module, rest
+		}
+	}
+	return entry, true
+}
+
+// labelLevel returns the level of a console label
+func labelLevel(label string) Level {
+	switch label {
+	case "DEBUG":
+		return LevelDebug
+	case "WARNING":
+		return LevelWarn
+	case "ERROR":
+		return LevelError
+	}
+	return LevelInfo
+}
+
+// FileSink appends entries to a file, rotating it by size. A rotated file
+// is renamed to path.1, pushing older ones to path.2 and so on up to
+// MaxBackups; the oldest is removed.
+type FileSink struct {
+	mu         sync.Mutex
+	path       string
+	format     Format
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// NewFileSink opens path for appending, creating its directory if needed
+func NewFileSink(path string, format Format, maxSize int64, maxBackups int) (*FileSink, error) {
+	// Create log directory if it doesn't exist
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create log directory: %w", err)
+		}
+	}
+
+	sink := &FileSink{path: path, format: format, maxSize: maxSize, maxBackups: maxBackups}
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file %s: %w", s.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to open log file %s: %w", s.path, err)
+	}
+	s.file, s.size = file, info.Size()
+	return nil
+}
+
+// configure changes the format and rotation of an open sink
+func (s *FileSink) configure(format Format, maxSize int64, maxBackups int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.format, s.maxSize, s.maxBackups = format, maxSize, maxBackups
+}
+
+// Write appends the entry, rotating first when it would grow the file past
+// its maximum size
+func (s *FileSink) Write(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line := FormatEntry(entry, s.format) + "\n"
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.WriteString(line)
+	s.size += int64(n)
+	return err
+}
+
+// rotate shifts the backups and starts a new, empty log file
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file %s: %w", s.path, err)
+	}
+
+	var err error
+	if s.maxBackups > 0 {
+		_ = os.Remove(fmt.Sprintf("%s.%d", s.path, s.maxBackups))
+		for i := s.maxBackups - 1; i >= 1; i-- {
+			_ = os.Rename(fmt.Sprintf("%s.%d", s.path, i), fmt.Sprintf("%s.%d", s.path, i+1))
+		}
+		err = os.Rename(s.path, s.path+".1")
+	} else {
+		err = os.Remove(s.path)
+	}
+
+	// Keep logging to the old file when it could not be moved aside
+	if openErr := s.open(); openErr != nil {
+		return openErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to rotate log file %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// Close closes the log file
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,128 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"log/syslog"
+	"net"
+	"strings"
+)
+
+// Names of the sinks that can be enabled from the configuration
+const (
+	SinkSyslog   = "syslog"
+	SinkJournald = "journald"
+)
+
+// identifier tags the entries hardn sends to syslog and the journal
+const identifier = "hardn"
+
+const journaldSocket = "/run/systemd/journal/socket"
+
+// NewNamedSink opens the sink a configuration entry names
+func NewNamedSink(name string) (Sink, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case SinkSyslog:
+		return NewSyslogSink()
+	case SinkJournald:
+		return NewJournaldSink()
+	}
+	return nil, fmt.Errorf("unknown log sink %q (use %s or %s)", name, SinkSyslog, SinkJournald)
+}
+
+// SyslogSink sends entries to the local syslog daemon
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogSink connects to the local syslog daemon
+func NewSyslogSink() (*SyslogSink, error) {
+	writer, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, identifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	return &SyslogSink{writer: writer}, nil
+}
+
+// Write sends the entry with the syslog severity of its level
+func (s *SyslogSink) Write(entry Entry) error {
+	msg := entry.Label + ": " + entry.Message
+	if entry.Module != "" {
+		msg = entry.Label + ": [" + entry.Module + "] " + entry.Message
+	}
+	switch entry.Level {
+	case LevelDebug:
+		return s.writer.Debug(msg)
+	case LevelWarn:
+		return s.writer.Warning(msg)
+	case LevelError:
+		return s.writer.Err(msg)
+	}
+	return s.writer.Info(msg)
+}
+
+// Close closes the connection to syslog
+func (s *SyslogSink) Close() error {
+	return s.writer.Close()
+}
+
+// JournaldSink sends entries to the systemd journal with its native
+// protocol, keeping the label and module as fields of their own
+// (HARDN_LABEL, HARDN_MODULE)
+type JournaldSink struct {
+	conn *net.UnixConn
+}
+
+// NewJournaldSink connects to the journal socket
+func NewJournaldSink() (*JournaldSink, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journaldSocket, Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to journald: %w", err)
+	}
+	return &JournaldSink{conn: conn}, nil
+}
+
+// journaldPriorities are the syslog priorities of each level
+var journaldPriorities = map[Level]string{
+	LevelDebug: "7",
+	LevelInfo:  "6",
+	LevelWarn:  "4",
+	LevelError: "3",
+}
+
+// Write sends the entry as one datagram
+func (s *JournaldSink) Write(entry Entry) error {
+	_, err := s.conn.Write(JournaldMessage(entry))
+	return err
+}
+
+// Close closes the connection to the journal
+func (s *JournaldSink) Close() error {
+	return s.conn.Close()
+}
+
+// JournaldMessage encodes an entry in the journal's native protocol.
+// Values containing newlines are sent with their length, as the protocol
+// requires.
+func JournaldMessage(entry Entry) []byte {
+	var b bytes.Buffer
+	field := func(name, value string) {
+		if !strings.Contains(value, "\n") {
+			b.WriteString(name + "=" + value + "\n")
+			return
+		}
+		b.WriteString(name + "\n")
+		_ = binary.Write(&b, binary.LittleEndian, uint64(len(value)))
+		b.WriteString(value + "\n")
+	}
+
+	field("MESSAGE", entry.Message)
+	field("PRIORITY", journaldPriorities[entry.Level])
+	field("SYSLOG_IDENTIFIER", identifier)
+	field("HARDN_LABEL", entry.Label)
+	if entry.Module != "" {
+		field("HARDN_MODULE", entry.Module)
+	}
+	return b.Bytes()
+}
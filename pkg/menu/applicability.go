@@ -0,0 +1,72 @@
+// pkg/menu/applicability.go
+package menu
+
+import (
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// Module applicability checks return why a module can not be used on this
+// system, or "" when it applies. Menus set the reason on the option with
+// style.MenuOption.DisabledReason rather than hiding it.
+
+// unsupportedOSReason disables distribution-specific modules in degraded mode
+func unsupportedOSReason(osInfo *osdetect.OSInfo) string {
+	if osInfo != nil && osInfo.Degraded {
+		return fmt.Sprintf("not available on %s (unsupported OS)", osInfo.OsType)
+	}
+	return ""
+}
+
+// proxmoxReason disables modules that only apply to Proxmox VE hosts
+func proxmoxReason(osInfo *osdetect.OSInfo) string {
+	if osInfo == nil || !osInfo.IsProxmox {
+		return "requires Proxmox VE"
+	}
+	return ""
+}
+
+// containerReason disables modules for the kernel, which containers share
+// with their host
+func containerReason(osInfo *osdetect.OSInfo) string {
+	if osInfo != nil && osInfo.Environment.IsContainer() {
+		return "not available in a container (the host's kernel)"
+	}
+	return ""
+}
+
+// wslReason disables a module hardn skips on WSL, as named in
+// osdetect.WSLSkippedModules and WSLNoSystemdSkippedModules
+func wslReason(osInfo *osdetect.OSInfo, module string) string {
+	for _, skipped := range osInfo.WSLSkipped() {
+		if skipped == module {
+			return "not available on WSL (see hardn wsl)"
+		}
+	}
+	return ""
+}
+
+// firewallReason disables firewall options until the firewall is installed
+func firewallReason(installed bool, name string) string {
+	if !installed {
+		return "requires " + name + " installed"
+	}
+	return ""
+}
+
+// rejectDisabledOption explains why a disabled option was selected and
+// reports whether the choice was rejected
+func rejectDisabledOption(menu *style.Menu, choice string) bool {
+	reason, disabled := menu.DisabledReason(choice)
+	if !disabled {
+		return false
+	}
+
+	fmt.Printf("\n%s This option is unavailable: %s\n",
+		style.Colored(style.Yellow, style.SymWarning), reason)
+	fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+	ReadKey()
+	return true
+}
@@ -0,0 +1,393 @@
+// pkg/menu/auto_updates_menu.go
+package menu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// AutoUpdatesMenu handles automatic package upgrade settings
+type AutoUpdatesMenu struct {
+	menuManager *application.MenuManager
+	config      *config.Config
+	osInfo      *osdetect.OSInfo
+}
+
+// NewAutoUpdatesMenu creates a new AutoUpdatesMenu
+func NewAutoUpdatesMenu(
+	menuManager *application.MenuManager,
+	config *config.Config,
+	osInfo *osdetect.OSInfo,
+) *AutoUpdatesMenu {
+	return &AutoUpdatesMenu{
+		menuManager: menuManager,
+		config:      config,
+		osInfo:      osInfo,
+	}
+}
+
+// Show displays the automatic updates menu and handles user input
+func (m *AutoUpdatesMenu) Show() {
+	defer enterScreen("Auto Updates")()
+
+	switch {
+	case m.menuManager.UpdatesSupported():
+		m.showPolicy()
+	case m.menuManager.AutoUpgradesSupported():
+		m.showDnfAutomatic()
+	default:
+		fmt.Printf("\n%s hardn does not manage automatic upgrades on %s\n",
+			style.Colored(style.Yellow, style.SymInfo), m.osInfo.OsType)
+		fmt.Printf("\n%s Press any key to return to the main menu...", style.BulletItem)
+		ReadKey()
+	}
+}
+
+// showPolicy displays the unattended-upgrades policy on Debian/Ubuntu, or
+// the periodic upgrade script on Alpine
+func (m *AutoUpdatesMenu) showPolicy() {
+	status, err := m.menuManager.GetUpdatesStatus()
+	if err != nil {
+		fmt.Printf("\n%s Error checking automatic updates: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		status = &model.UpdatesStatus{}
+	}
+	policy := m.menuManager.EffectiveUpdatesPolicy(m.config.AutoUpdates.Policy())
+	alpine := m.osInfo.OsType == "alpine"
+
+	// Display current settings
+	fmt.Println()
+	fmt.Println(style.Bolded("Current Configuration:", style.Blue))
+
+	formatter := style.NewStatusFormatter([]string{"Daily Upgrade", "Managed", "Origins", "Never Upgrade", "Reboot", "Mail"}, 2)
+
+	if status.Enabled {
+		fmt.Println(formatter.FormatSuccess("Daily Upgrade", "Enabled", strings.Join(status.Files, ", ")))
+	} else {
+		fmt.Println(formatter.FormatWarning("Daily Upgrade", "Disabled", "Packages are only upgraded manually"))
+	}
+	if status.Enabled && !status.Managed {
+		fmt.Println(formatter.FormatWarning("Managed", "No", "Applying the policy replaces the existing configuration"))
+	}
+
+	if !alpine {
+		for i, origin := range policy.Origins {
+			label := ""
+			if i == 0 {
+				label = "Origins"
+			}
+			fmt.Println(formatter.FormatLine(style.SymInfo, style.Cyan, label, origin, style.Cyan, ""))
+		}
+	}
+
+	blacklist := "None"
+	if len(policy.Blacklist) > 0 {
+		blacklist = strings.Join(policy.Blacklist, ", ")
+	}
+	fmt.Println(formatter.FormatLine(style.SymInfo, style.Cyan, "Never Upgrade", blacklist, style.Cyan, ""))
+
+	reboot := "No"
+	if policy.Reboot && alpine {
+		reboot = "After a kernel upgrade"
+	} else if policy.Reboot {
+		reboot = "At " + policy.RebootTime
+	}
+	fmt.Println(formatter.FormatLine(style.SymInfo, style.Cyan, "Reboot", reboot, style.Cyan, ""))
+
+	mail := "Not sent"
+	if policy.Mail != "" {
+		mail = policy.Mail
+		if policy.MailOnlyOnError {
+			mail += " (errors only)"
+		}
+	}
+	fmt.Println(formatter.FormatLine(style.SymInfo, style.Cyan, "Mail", mail, style.Cyan, ""))
+
+	// Create menu options
+	menuOptions := []style.MenuOption{
+		{Number: 1, Title: "Apply upgrade policy", Description: "Write the policy above and enable daily upgrades"},
+		{Number: 2, Title: "Disable automatic updates", Description: "Stop the daily upgrade run"},
+		{Number: 3, Title: "Set packages never upgraded", Description: "Hold back packages such as the kernel or a database"},
+		{Number: 4, Title: "Toggle automatic reboot", Description: "Reboot when an upgrade requires it"},
+		{Number: 5, Title: "Set report mail address", Description: "Mail the result of each upgrade run"},
+	}
+	if m.menuManager.NeedrestartSupported() {
+		menuOptions = append(menuOptions, style.MenuOption{
+			Number: 6, Title: "Service restarts", Description: "Configure needrestart after upgrades",
+		})
+	}
+
+	menu := style.NewMenu("Select an option", menuOptions)
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return to main menu",
+		Description: "",
+	})
+
+	menu.Print()
+
+	choice := ReadMenuInput()
+
+	// Handle 'q' as a special exit case
+	if choice == "q" || choice == "0" {
+		return
+	}
+
+	switch choice {
+	case "1":
+		// In dry-run mode the policy files are printed as diffs instead
+		if err := m.menuManager.ConfigureUpdates(m.config.AutoUpdates.Policy()); err != nil {
+			fmt.Printf("\n%s Failed to configure automatic updates: %v\n",
+				style.Colored(style.Red, style.SymCrossMark), err)
+		} else if !m.config.DryRun {
+			fmt.Printf("\n%s Automatic updates have been %s\n",
+				style.Colored(style.Green, style.SymCheckMark),
+				style.Bolded("enabled", style.Green))
+			if alpine {
+				fmt.Printf("%s Output is logged to %s\n", style.BulletItem, model.AlpineAutoUpgradeLogPath)
+			}
+			m.config.EnableUnattendedUpgrades = true
+			m.saveConfig()
+		}
+
+	case "2":
+		if err := m.menuManager.DisableUpdates(); err != nil {
+			fmt.Printf("\n%s Failed to disable automatic updates: %v\n",
+				style.Colored(style.Red, style.SymCrossMark), err)
+		} else if !m.config.DryRun {
+			fmt.Printf("\n%s Automatic updates have been %s\n",
+				style.Colored(style.Yellow, style.SymInfo),
+				style.Bolded("disabled", style.Yellow))
+			m.config.EnableUnattendedUpgrades = false
+			m.saveConfig()
+		}
+
+	case "3":
+		fmt.Printf("\n%s Packages never upgraded, separated by spaces (empty for none): ", style.BulletItem)
+		m.config.AutoUpdates.Blacklist = strings.Fields(ReadInput())
+		m.saveConfig()
+		fmt.Printf("%s Apply the policy to update the host\n", style.BulletItem)
+
+	case "4":
+		m.config.AutoUpdates.Reboot = !m.config.AutoUpdates.Reboot
+		if m.config.AutoUpdates.Reboot && !alpine {
+			fmt.Printf("\n%s Reboot time (HH:MM, empty for %s): ", style.BulletItem, model.DefaultRebootTime)
+			m.config.AutoUpdates.RebootTime = strings.TrimSpace(ReadInput())
+		}
+		m.saveConfig()
+		fmt.Printf("\n%s Apply the policy to update the host\n", style.BulletItem)
+
+	case "5":
+		fmt.Printf("\n%s Report mail address (empty to send none): ", style.BulletItem)
+		m.config.AutoUpdates.Mail = strings.TrimSpace(ReadInput())
+		if m.config.AutoUpdates.Mail != "" {
+			fmt.Printf("%s Only mail failed upgrades? (y/n): ", style.BulletItem)
+			answer := strings.ToLower(strings.TrimSpace(ReadInput()))
+			m.config.AutoUpdates.MailOnlyOnError = answer == "y" || answer == "yes"
+		}
+		m.saveConfig()
+		fmt.Printf("%s Apply the policy to update the host\n", style.BulletItem)
+
+	case "6":
+		if m.menuManager.NeedrestartSupported() {
+			m.showNeedrestart()
+			return
+		}
+		fmt.Printf("\n%s Invalid option. Please try again.\n",
+			style.Colored(style.Red, style.SymCrossMark))
+
+	default:
+		fmt.Printf("\n%s Invalid option. Please try again.\n",
+			style.Colored(style.Red, style.SymCrossMark))
+	}
+
+	fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+	ReadKey()
+	m.Show()
+}
+
+// showDnfAutomatic displays the dnf-automatic timer on RHEL-family hosts
+func (m *AutoUpdatesMenu) showDnfAutomatic() {
+	enabled, err := m.menuManager.IsAutoUpgradeEnabled()
+	if err != nil {
+		fmt.Printf("\n%s Error checking automatic updates: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+	}
+
+	// Display current settings
+	fmt.Println()
+	fmt.Println(style.Bolded("Current Configuration:", style.Blue))
+
+	formatter := style.NewStatusFormatter([]string{"Daily Upgrade", "Timer", "Config"}, 2)
+
+	status := "Disabled"
+	if enabled {
+		status = "Enabled"
+		fmt.Println(formatter.FormatSuccess("Daily Upgrade", status, "dnf-automatic, security updates"))
+	} else {
+		fmt.Println(formatter.FormatWarning("Daily Upgrade", status, "Packages are only upgraded manually"))
+	}
+	fmt.Println(formatter.FormatLine(style.SymInfo, style.Cyan, "Timer", model.DnfAutomaticTimer, style.Cyan, ""))
+	fmt.Println(formatter.FormatLine(style.SymInfo, style.Cyan, "Config", model.DnfAutomaticConfigPath, style.Cyan, ""))
+
+	// Create menu options
+	menuOptions := []style.MenuOption{
+		{
+			Number:      1,
+			Title:       fmt.Sprintf("Toggle automatic updates (currently: %s)", status),
+			Description: "Apply security updates daily with dnf-automatic",
+		},
+	}
+
+	menu := style.NewMenu("Select an option", menuOptions)
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return to main menu",
+		Description: "",
+	})
+
+	menu.Print()
+
+	choice := ReadMenuInput()
+
+	// Handle 'q' as a special exit case
+	if choice == "q" || choice == "0" {
+		return
+	}
+
+	if choice != "1" {
+		fmt.Printf("\n%s Invalid option. Please try again.\n",
+			style.Colored(style.Red, style.SymCrossMark))
+	} else if enable := !enabled; m.config.DryRun && enable {
+		fmt.Printf("\n%s [DRY-RUN] Would install dnf-automatic, set apply_updates = yes and enable %s\n",
+			style.BulletItem, model.DnfAutomaticTimer)
+	} else if m.config.DryRun {
+		fmt.Printf("\n%s [DRY-RUN] Would disable %s\n", style.BulletItem, model.DnfAutomaticTimer)
+	} else if err := m.menuManager.ConfigureAutoUpgrades(enable); err != nil {
+		fmt.Printf("\n%s Failed to configure automatic updates: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+	} else {
+		if enable {
+			fmt.Printf("\n%s Automatic updates have been %s\n",
+				style.Colored(style.Green, style.SymCheckMark),
+				style.Bolded("enabled", style.Green))
+		} else {
+			fmt.Printf("\n%s Automatic updates have been %s\n",
+				style.Colored(style.Yellow, style.SymInfo),
+				style.Bolded("disabled", style.Yellow))
+		}
+
+		// Update config to keep it in sync
+		m.config.EnableUnattendedUpgrades = enable
+		m.saveConfig()
+	}
+
+	fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+	ReadKey()
+	m.Show()
+}
+
+// saveConfig writes menu changes back to the configuration file
+func (m *AutoUpdatesMenu) saveConfig() {
+	configFile := "hardn.yml" // Default config file
+	if err := config.SaveConfig(m.config, configFile); err != nil {
+		fmt.Printf("\n%s Failed to save configuration: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+	}
+}
+
+// showNeedrestart displays needrestart settings on Debian/Ubuntu, which
+// restarts the services still using libraries replaced by an upgrade
+func (m *AutoUpdatesMenu) showNeedrestart() {
+	status, err := m.menuManager.GetNeedrestartStatus()
+	if err != nil {
+		fmt.Printf("\n%s Error checking needrestart: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		status = &model.NeedrestartStatus{}
+	}
+
+	// Display current settings
+	fmt.Println()
+	fmt.Println(style.Bolded("Service Restarts (needrestart):", style.Blue))
+
+	formatter := style.NewStatusFormatter([]string{"Installed", "Restart Mode", "Pending", "Reboot"}, 2)
+
+	if status.Installed {
+		fmt.Println(formatter.FormatSuccess("Installed", "Yes", "Checks for outdated libraries after upgrades"))
+	} else {
+		fmt.Println(formatter.FormatWarning("Installed", "No", "Services keep running replaced libraries"))
+	}
+
+	mode := status.Mode
+	if mode == "" {
+		mode = "Not managed"
+	}
+	fmt.Println(formatter.FormatLine(style.SymInfo, style.Cyan, "Restart Mode", mode, style.Cyan, ""))
+
+	if len(status.PendingServices) > 0 {
+		fmt.Println(formatter.FormatWarning("Pending", fmt.Sprintf("%d services", len(status.PendingServices)), ""))
+		for _, service := range status.PendingServices {
+			fmt.Printf("%s %s\n", style.BulletItem, service)
+		}
+	} else if status.Installed {
+		fmt.Println(formatter.FormatSuccess("Pending", "None", ""))
+	}
+
+	if status.RebootRequired {
+		fmt.Println(formatter.FormatWarning("Reboot", "Required", "Running kernel or core libraries are outdated"))
+	}
+
+	menuOptions := []style.MenuOption{
+		{Number: 1, Title: "Automatic restarts", Description: "Restart affected services without asking"},
+		{Number: 2, Title: "List only", Description: "Report affected services, restart nothing"},
+		{Number: 3, Title: "Interactive", Description: "Ask before restarting services"},
+	}
+
+	menu := style.NewMenu("Select needrestart mode", menuOptions)
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return to main menu",
+		Description: "",
+	})
+
+	menu.Print()
+
+	choice := ReadMenuInput()
+
+	// Handle 'q' as a special exit case
+	if choice == "q" || choice == "0" {
+		return
+	}
+
+	modes := map[string]string{"1": "automatic", "2": "list", "3": "interactive"}
+	newMode, ok := modes[choice]
+	if !ok {
+		fmt.Printf("\n%s Invalid option. Please try again.\n",
+			style.Colored(style.Red, style.SymCrossMark))
+	} else if m.config.DryRun {
+		fmt.Printf("\n%s [DRY-RUN] Would install needrestart and set restart mode to %s\n",
+			style.BulletItem, newMode)
+	} else if err := m.menuManager.ConfigureNeedrestart(newMode); err != nil {
+		fmt.Printf("\n%s Failed to configure needrestart: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+	} else {
+		fmt.Printf("\n%s needrestart restart mode set to %s\n",
+			style.Colored(style.Green, style.SymCheckMark),
+			style.Bolded(newMode, style.Green))
+
+		// Update config to keep it in sync
+		m.config.NeedrestartMode = newMode
+
+		m.saveConfig()
+	}
+
+	fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+	ReadKey()
+	m.Show()
+}
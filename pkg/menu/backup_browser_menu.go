@@ -0,0 +1,232 @@
+// pkg/menu/backup_browser_menu.go
+package menu
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// BackupBrowserMenu lists the file backups, previews them against the live
+// files and restores them
+type BackupBrowserMenu struct {
+	menuManager *application.MenuManager
+	config      *config.Config
+}
+
+// NewBackupBrowserMenu creates a new BackupBrowserMenu
+func NewBackupBrowserMenu(
+	menuManager *application.MenuManager,
+	config *config.Config,
+) *BackupBrowserMenu {
+	return &BackupBrowserMenu{
+		menuManager: menuManager,
+		config:      config,
+	}
+}
+
+// Show lists the backed up files and handles user input
+func (m *BackupBrowserMenu) Show() {
+	defer enterScreen("Browse Backups")()
+
+	groups, err := m.menuManager.ListBackupGroups()
+	if err != nil {
+		fmt.Printf("\n%s Error listing backups: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+	}
+
+	fmt.Println()
+	if len(groups) == 0 {
+		fmt.Printf("%s No backups in %s\n", style.BulletItem, m.config.BackupPath)
+	}
+
+	menuOptions := make([]style.MenuOption, 0, len(groups))
+	for i, group := range groups {
+		latest := group.Backups[0]
+		menuOptions = append(menuOptions, style.MenuOption{
+			Number: i + 1,
+			Title:  groupTitle(group),
+			Description: fmt.Sprintf("%d backups, latest %s",
+				len(group.Backups), latest.Created.Format("2006-01-02 15:04:05")),
+		})
+	}
+
+	menu := style.NewMenu("Select a file", menuOptions)
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return to backup menu",
+		Description: "",
+	})
+	menu.Print()
+
+	choice := ReadMenuInput()
+	if choice == "q" || choice == "0" {
+		return
+	}
+
+	index, err := strconv.Atoi(choice)
+	if err != nil || index < 1 || index > len(groups) {
+		fmt.Printf("\n%s Invalid option. Please try again.\n",
+			style.Colored(style.Red, style.SymCrossMark))
+		m.pause()
+		m.Show()
+		return
+	}
+
+	m.showGroup(groups[index-1])
+	m.Show()
+}
+
+// showGroup lists the backups of one file, newest first
+func (m *BackupBrowserMenu) showGroup(group model.BackupGroup) {
+	defer enterScreen(group.Name)()
+
+	fmt.Println()
+	if group.OriginalPath == "" {
+		fmt.Printf("%s The backups of %s do not record where the file was; restoring asks for the path\n",
+			style.Colored(style.Yellow, style.SymWarning), group.Name)
+	}
+
+	menuOptions := make([]style.MenuOption, 0, len(group.Backups))
+	for i, backup := range group.Backups {
+		menuOptions = append(menuOptions, style.MenuOption{
+			Number:      i + 1,
+			Title:       backup.Created.Format("2006-01-02 15:04:05"),
+			Description: backupDetails(m.menuManager.FormatBytes(backup.Size), backup),
+		})
+	}
+
+	menu := style.NewMenu("Select a version of "+groupTitle(group), menuOptions)
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return to backed up files",
+		Description: "",
+	})
+	menu.Print()
+
+	choice := ReadMenuInput()
+	if choice == "q" || choice == "0" {
+		return
+	}
+
+	index, err := strconv.Atoi(choice)
+	if err != nil || index < 1 || index > len(group.Backups) {
+		fmt.Printf("\n%s Invalid option. Please try again.\n",
+			style.Colored(style.Red, style.SymCrossMark))
+		m.pause()
+		m.showGroup(group)
+		return
+	}
+
+	m.showBackup(group.Backups[index-1])
+	m.showGroup(group)
+}
+
+// showBackup previews a backup as a diff against the live file and
+// restores it after confirmation
+func (m *BackupBrowserMenu) showBackup(backup model.BackupFile) {
+	defer enterScreen(filepath.Base(backup.BackupPath))()
+
+	target := backup.OriginalPath
+	if target == "" {
+		fmt.Printf("\n%s Restore %s to (absolute path): ", style.BulletItem, filepath.Base(backup.BackupPath))
+		if target = ReadInput(); target == "" {
+			return
+		}
+	}
+
+	comparison, err := m.menuManager.CompareBackup(backup.BackupPath, target)
+	if err != nil {
+		fmt.Printf("\n%s Failed to read the backup: %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+		m.pause()
+		return
+	}
+
+	fmt.Println()
+	switch {
+	case comparison.Identical():
+		fmt.Printf("%s %s matches this backup\n", style.Colored(style.Green, style.SymCheckMark), target)
+		m.pause()
+		return
+	case !comparison.CurrentExists:
+		fmt.Printf("%s %s does not exist; restoring creates it\n", style.Colored(style.Yellow, style.SymWarning), target)
+	default:
+		// The diff shows what restoring changes in the live file
+		fmt.Print(style.UnifiedDiff(target, comparison.Current, comparison.Backup))
+	}
+
+	menu := style.NewMenu("Select an option", []style.MenuOption{
+		{Number: 1, Title: "Restore this version", Description: "Replace " + target},
+	})
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return to versions",
+		Description: "",
+	})
+	menu.Print()
+	if ReadMenuInput() != "1" {
+		return
+	}
+
+	if m.config.DryRun {
+		fmt.Printf("\n%s [DRY-RUN] Would restore %s from %s\n", style.BulletItem, target, backup.BackupPath)
+		m.pause()
+		return
+	}
+
+	fmt.Printf("\n%s Replace %s with the backup of %s? (y/n): ",
+		style.Colored(style.Yellow, style.SymWarning), target, backup.Created.Format("2006-01-02 15:04:05"))
+	if confirm := strings.ToLower(ReadInput()); confirm != "y" && confirm != "yes" {
+		fmt.Printf("\n%s Restore cancelled\n", style.BulletItem)
+		m.pause()
+		return
+	}
+
+	// Keep the live file, so the restore can be undone from this menu
+	if comparison.CurrentExists {
+		if err := m.menuManager.BackupFile(target); err != nil {
+			fmt.Printf("\n%s Failed to back up %s, not restoring: %v\n",
+				style.Colored(style.Red, style.SymCrossMark), target, err)
+			m.pause()
+			return
+		}
+	}
+	if err := m.menuManager.RestoreBackup(backup.BackupPath, target); err != nil {
+		fmt.Printf("\n%s Restore failed: %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+	} else {
+		fmt.Printf("\n%s Restored %s\n", style.Colored(style.Green, style.SymCheckMark), target)
+		fmt.Printf("%s Reload or restart the service using it for the change to take effect\n", style.BulletItem)
+	}
+	m.pause()
+}
+
+// groupTitle names a backed up file by its path where known
+func groupTitle(group model.BackupGroup) string {
+	if group.OriginalPath == "" {
+		return group.Name + " (unknown location)"
+	}
+	return group.OriginalPath
+}
+
+// backupDetails describes the size and layers of a backup
+func backupDetails(size string, backup model.BackupFile) string {
+	details := []string{size}
+	if backup.Compression != "" {
+		details = append(details, backup.Compression)
+	}
+	if backup.Encryption != "" {
+		details = append(details, backup.Encryption+" encrypted")
+	}
+	return strings.Join(details, ", ")
+}
+
+func (m *BackupBrowserMenu) pause() {
+	fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+	ReadKey()
+}
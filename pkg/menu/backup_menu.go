@@ -7,7 +7,6 @@ import (
 	"github.com/abbott/hardn/pkg/application"
 	"github.com/abbott/hardn/pkg/config"
 	"github.com/abbott/hardn/pkg/style"
-	"github.com/abbott/hardn/pkg/utils"
 )
 
 // BackupMenu handles backup configuration
@@ -29,8 +28,7 @@ func NewBackupMenu(
 
 // Show displays the backup menu and handles user input
 func (m *BackupMenu) Show() {
-	utils.PrintHeader()
-	fmt.Println(style.Bolded("Backup Settings", style.Blue))
+	defer enterScreen("Backup")()
 
 	// Get backup status from application layer
 	enabled, backupPath, err := m.menuManager.GetBackupStatus()
@@ -52,7 +50,7 @@ func (m *BackupMenu) Show() {
 	}
 
 	// Display status with formatter
-	formatter := style.NewStatusFormatter([]string{"Backups", "Backup Path"}, 2)
+	formatter := style.NewStatusFormatter([]string{"Backups", "Backup Path", "Compression", "Encryption", "Remote"}, 2)
 
 	// Determine symbol and color based on backup status
 	symbol := style.SymCrossMark
@@ -67,6 +65,31 @@ func (m *BackupMenu) Show() {
 	// Display backup path
 	fmt.Println(formatter.FormatLine(style.SymInfo, style.Cyan, "Backup Path", backupPath, style.Cyan, ""))
 
+	// Display compression and encryption of new backups
+	compression := "None"
+	if format := m.config.BackupCompression.Format; format != "" {
+		compression = format
+		if m.config.BackupCompression.Level != 0 {
+			compression += fmt.Sprintf(" (level %d)", m.config.BackupCompression.Level)
+		}
+	}
+	fmt.Println(formatter.FormatLine(style.SymInfo, style.Cyan, "Compression", compression, style.Cyan, ""))
+
+	encryption := m.config.BackupEncryption
+	if encryption.Tool == "" {
+		fmt.Println(formatter.FormatLine(style.SymWarning, style.Yellow, "Encryption", "None", style.Yellow, ""))
+	} else {
+		fmt.Println(formatter.FormatLine(style.SymEnabled, style.Green, "Encryption",
+			fmt.Sprintf("%s for %s", encryption.Tool, encryption.Recipient), style.Green, ""))
+	}
+
+	remote := m.config.BackupRemote
+	if remote.URL == "" {
+		fmt.Println(formatter.FormatLine(style.SymInfo, style.Cyan, "Remote", "None", style.Cyan, ""))
+	} else {
+		fmt.Println(formatter.FormatLine(style.SymEnabled, style.Green, "Remote", remote.URL, style.Green, ""))
+	}
+
 	// Check backup path status
 	if enabled {
 		// Use application layer to check path status
@@ -107,6 +130,36 @@ func (m *BackupMenu) Show() {
 		})
 	}
 
+	menuOptions = append(menuOptions, style.MenuOption{
+		Number:      4,
+		Title:       "Roll back a run",
+		Description: "Revert the changes of an earlier hardening run",
+	})
+
+	// Add option to test the encryption keys if backups are encrypted
+	if encryption.Tool != "" {
+		menuOptions = append(menuOptions, style.MenuOption{
+			Number:      5,
+			Title:       "Test encryption keys",
+			Description: "Encrypt and decrypt a test file with " + encryption.Tool,
+		})
+	}
+
+	// Add option to test and sync the remote target if one is set
+	if remote.URL != "" {
+		menuOptions = append(menuOptions, style.MenuOption{
+			Number:      6,
+			Title:       "Sync to remote target",
+			Description: "Test " + remote.URL + " and copy the backups to it",
+		})
+	}
+
+	menuOptions = append(menuOptions, style.MenuOption{
+		Number:      7,
+		Title:       "Browse backups",
+		Description: "Compare backed up files with the live ones and restore them",
+	})
+
 	// Create menu
 	menu := style.NewMenu("Select an option", menuOptions)
 	menu.SetExitOption(style.MenuOption{
@@ -232,6 +285,38 @@ func (m *BackupMenu) Show() {
 		ReadKey()
 		m.Show()
 
+	case "4":
+		runsMenu := NewRunsMenu(m.menuManager, m.config)
+		runsMenu.Show()
+		m.Show()
+
+	case "5":
+		// Test encryption keys (only available if backups are encrypted)
+		if encryption.Tool != "" {
+			m.testBackupKeys()
+		}
+
+		// Return to this menu after the test
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		m.Show()
+
+	case "6":
+		// Sync to the remote target (only available if one is set)
+		if remote.URL != "" {
+			m.syncBackupRemote()
+		}
+
+		// Return to this menu after the sync
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		m.Show()
+
+	case "7":
+		browser := NewBackupBrowserMenu(m.menuManager, m.config)
+		browser.Show()
+		m.Show()
+
 	case "0":
 		// Return to main menu
 		return
@@ -245,3 +330,69 @@ func (m *BackupMenu) Show() {
 		m.Show()
 	}
 }
+
+// testBackupKeys encrypts and decrypts a test file and reports which step
+// the keys fail at
+func (m *BackupMenu) testBackupKeys() {
+	fmt.Printf("\n%s Testing %s keys for %s\n", style.BulletItem,
+		m.config.BackupEncryption.Tool, style.Colored(style.Cyan, m.config.BackupEncryption.Recipient))
+
+	status, err := m.menuManager.CheckBackupKeys()
+	if err != nil {
+		fmt.Printf("\n%s Key test failed: %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+		return
+	}
+
+	steps := []struct {
+		ok    bool
+		label string
+	}{
+		{status.Installed, status.Tool + " installed"},
+		{status.CanEncrypt, "Backups can be encrypted for the recipient"},
+		{status.CanDecrypt, "Backups can be decrypted on this host"},
+	}
+	for _, step := range steps {
+		if step.ok {
+			fmt.Printf("%s %s\n", style.Colored(style.Green, style.SymCheckMark), step.label)
+			continue
+		}
+		fmt.Printf("%s %s\n", style.Colored(style.Red, style.SymCrossMark), step.label)
+		fmt.Printf("  %s\n", style.Dimmed(status.Problem))
+		break
+	}
+
+	if status.Installed && status.CanEncrypt && !status.CanDecrypt {
+		fmt.Printf("\n%s Backups are written, but restoring them needs the private key on this host\n",
+			style.Colored(style.Yellow, style.SymWarning))
+	}
+}
+
+// syncBackupRemote tests the remote target, then copies the backups to it
+// after confirmation
+func (m *BackupMenu) syncBackupRemote() {
+	url := m.config.BackupRemote.URL
+	fmt.Printf("\n%s Testing %s\n", style.BulletItem, style.Colored(style.Cyan, url))
+	if err := m.menuManager.CheckBackupRemote(); err != nil {
+		fmt.Printf("\n%s Remote target is not usable: %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+		return
+	}
+	fmt.Printf("%s Remote target is reachable and writable\n", style.Colored(style.Green, style.SymCheckMark))
+
+	if m.config.DryRun {
+		fmt.Printf("\n%s [DRY-RUN] Would copy the backups to %s\n", style.BulletItem, url)
+		return
+	}
+	fmt.Printf("\n%s Copy the backups to %s now? (y/n): ", style.BulletItem, url)
+	if confirm := ReadInput(); confirm != "y" && confirm != "Y" {
+		fmt.Printf("\n%s Sync cancelled\n", style.BulletItem)
+		return
+	}
+
+	result, err := m.menuManager.SyncBackupRemote()
+	if err != nil {
+		fmt.Printf("\n%s Sync failed: %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+		return
+	}
+	fmt.Printf("\n%s Backups synced: %d date directories uploaded, %d removed by the retention\n",
+		style.Colored(style.Green, style.SymCheckMark), len(result.Uploaded), len(result.Removed))
+}
@@ -0,0 +1,144 @@
+// pkg/menu/boot_hardening_menu.go
+package menu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// BootHardeningMenu handles the kernel command line and GRUB password
+type BootHardeningMenu struct {
+	bootManager *application.BootHardeningManager
+	config      *config.Config
+}
+
+// NewBootHardeningMenu creates a new BootHardeningMenu
+func NewBootHardeningMenu(
+	bootManager *application.BootHardeningManager,
+	config *config.Config,
+) *BootHardeningMenu {
+	return &BootHardeningMenu{
+		bootManager: bootManager,
+		config:      config,
+	}
+}
+
+// Show displays the boot hardening menu and handles user input
+func (m *BootHardeningMenu) Show() {
+	defer enterScreen("Boot")()
+
+	parameters := m.config.BootHardening.Parameters
+	status, err := m.bootManager.GetStatus(model.BootHardeningPolicy{Parameters: parameters})
+	if err != nil {
+		fmt.Printf("\n%s Error reading the boot loader configuration: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		status = &model.BootHardeningStatus{Missing: parameters}
+	}
+
+	// Display current settings
+	fmt.Println()
+	fmt.Println(style.Bolded("Boot Hardening:", style.Blue))
+
+	formatter := style.NewStatusFormatter([]string{"Parameters", "GRUB Password", "Reboot"}, 2)
+
+	if len(status.Missing) == 0 {
+		fmt.Println(formatter.FormatSuccess("Parameters", "Configured", strings.Join(status.Configured, " ")))
+	} else {
+		fmt.Println(formatter.FormatWarning("Parameters", "Missing", strings.Join(status.Missing, " ")))
+	}
+	if status.PasswordSet {
+		fmt.Println(formatter.FormatSuccess("GRUB Password", "Set", "entries can not be edited at boot"))
+	} else {
+		fmt.Println(formatter.FormatWarning("GRUB Password", "Not set", "anyone at the console can edit entries"))
+	}
+	if status.RebootRequired {
+		fmt.Println(formatter.FormatWarning("Reboot", "Required", strings.Join(status.Pending, " ")+" not in effect yet"))
+	}
+
+	paramOption := style.MenuOption{
+		Number:      1,
+		Title:       "Set kernel parameters",
+		Description: strings.Join(parameters, " "),
+	}
+	if len(parameters) == 0 {
+		paramOption.DisabledReason = "set bootHardening.parameters in the configuration first"
+	}
+
+	menuOptions := []style.MenuOption{
+		paramOption,
+		{Number: 2, Title: "Set GRUB password", Description: "Require a password to edit entries or use the GRUB shell"},
+	}
+
+	menu := style.NewMenu("Select an option", menuOptions)
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return to main menu",
+		Description: "",
+	})
+
+	menu.Print()
+
+	choice := ReadMenuInput()
+
+	// Handle 'q' as a special exit case
+	if choice == "q" {
+		return
+	}
+
+	if rejectDisabledOption(menu, choice) {
+		m.Show()
+		return
+	}
+
+	switch choice {
+	case "1":
+		m.apply("set "+strings.Join(parameters, " ")+" on the kernel command line",
+			model.BootHardeningPolicy{Parameters: parameters})
+
+	case "2":
+		fmt.Printf("\n%s GRUB password for %s: ", style.BulletItem, model.GrubSuperuser)
+		password := ReadInput()
+		if password == "" {
+			fmt.Printf("\n%s No password entered\n", style.Colored(style.Yellow, style.SymWarning))
+			break
+		}
+		m.apply("set the GRUB password", model.BootHardeningPolicy{Password: password})
+
+	case "0":
+		// Return to main menu
+		return
+
+	default:
+		fmt.Printf("\n%s Invalid option. Please try again.\n",
+			style.Colored(style.Red, style.SymCrossMark))
+	}
+
+	fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+	ReadKey()
+	m.Show()
+}
+
+// apply applies part of the boot hardening policy, or describes it in
+// dry-run mode
+func (m *BootHardeningMenu) apply(description string, policy model.BootHardeningPolicy) {
+	if m.config.DryRun {
+		fmt.Printf("\n%s [DRY-RUN] Would %s and regenerate the GRUB configuration\n", style.BulletItem, description)
+		return
+	}
+
+	if err := m.bootManager.ApplyHardening(policy); err != nil {
+		fmt.Printf("\n%s Failed to %s: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), description, err)
+		return
+	}
+	fmt.Printf("\n%s Done: %s\n", style.Colored(style.Green, style.SymCheckMark), description)
+	if len(policy.Parameters) > 0 {
+		fmt.Printf("%s Reboot for the kernel parameters to take effect\n",
+			style.Colored(style.Yellow, style.SymWarning))
+	}
+}
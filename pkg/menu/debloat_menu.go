@@ -0,0 +1,93 @@
+// pkg/menu/debloat_menu.go
+package menu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// DebloatMenu removes the configured insecure packages, asking for each
+type DebloatMenu struct {
+	menuManager *application.MenuManager
+	config      *config.Config
+}
+
+// NewDebloatMenu creates a new DebloatMenu
+func NewDebloatMenu(
+	menuManager *application.MenuManager,
+	config *config.Config,
+) *DebloatMenu {
+	return &DebloatMenu{
+		menuManager: menuManager,
+		config:      config,
+	}
+}
+
+// Show lists the insecure packages found and offers to remove each one
+func (m *DebloatMenu) Show() {
+	defer enterScreen("Insecure Packages")()
+
+	fmt.Printf("\n%s Checking %d packages from debloat.packages...\n", style.BulletItem, len(m.config.Debloat.Packages))
+	findings, err := m.menuManager.FindInsecurePackages(m.config.Debloat.Items())
+	if err != nil {
+		fmt.Printf("\n%s Failed to check packages: %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+		style.PressAnyKey()
+		ReadKey()
+		return
+	}
+	if len(findings) == 0 {
+		fmt.Printf("\n%s None of the listed packages are installed or running\n",
+			style.Colored(style.Green, style.SymCheckMark))
+		style.PressAnyKey()
+		ReadKey()
+		return
+	}
+
+	for _, finding := range findings {
+		fmt.Printf("\n%s %s %s\n", style.Colored(style.Yellow, style.SymWarning),
+			style.Bolded(finding.Item.Name), style.Dimmed("["+finding.Item.Severity+"]"))
+		fmt.Printf("    %s\n", finding.Item.Reason)
+		fmt.Printf("    %s\n", style.Dimmed(finding.Describe()))
+
+		if finding.Action() == model.DebloatManual {
+			fmt.Printf("%s Left in place: removing it would also remove %s\n",
+				style.BulletItem, strings.Join(finding.Dependents, ", "))
+			continue
+		}
+		action := debloatActionText(finding)
+		if m.config.DryRun {
+			fmt.Printf("%s [DRY-RUN] Would %s\n", style.BulletItem, action)
+			continue
+		}
+
+		fmt.Printf("%s %s: %s? (y/N): ", style.BulletItem, finding.Item.Name, action)
+		if confirm := strings.ToLower(ReadInput()); confirm != "y" && confirm != "yes" {
+			fmt.Printf("%s Kept %s\n", style.Colored(style.Yellow, style.SymInfo), finding.Item.Name)
+			continue
+		}
+		if err := m.menuManager.RemoveInsecurePackage(finding); err != nil {
+			fmt.Printf("%s Failed to %s: %v\n", style.Colored(style.Red, style.SymCrossMark), action, err)
+		} else {
+			fmt.Printf("%s Done: %s\n", style.Colored(style.Green, style.SymCheckMark), action)
+		}
+	}
+
+	style.PressAnyKey()
+	ReadKey()
+}
+
+// debloatActionText describes what removing a finding does
+func debloatActionText(finding model.DebloatFinding) string {
+	if finding.Action() == model.DebloatDisable {
+		return fmt.Sprintf("disable %s (%s is needed by other packages)", strings.Join(finding.Running, ", "), strings.Join(finding.Installed, ", "))
+	}
+	if len(finding.Running) > 0 {
+		return fmt.Sprintf("stop %s and remove %s", strings.Join(finding.Running, ", "), strings.Join(finding.Installed, ", "))
+	}
+	return "remove " + strings.Join(finding.Installed, ", ")
+}
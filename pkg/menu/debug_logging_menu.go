@@ -0,0 +1,99 @@
+// pkg/menu/debug_logging_menu.go
+package menu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// DebugLoggingMenu toggles the debug entries of single modules for the
+// rest of the session
+type DebugLoggingMenu struct{}
+
+// NewDebugLoggingMenu creates a new DebugLoggingMenu
+func NewDebugLoggingMenu() *DebugLoggingMenu {
+	return &DebugLoggingMenu{}
+}
+
+// Show displays the modules with their debug state and toggles the chosen one
+func (m *DebugLoggingMenu) Show() {
+	defer enterScreen("Debug Logging")()
+
+	formatter := style.NewStatusFormatter(logging.Modules, 2)
+
+	fmt.Println()
+	for _, module := range logging.Modules {
+		if logging.ModuleDebugEnabled(module) {
+			fmt.Println(formatter.FormatLine(style.SymInfo, style.BrightCyan, module, "Enabled", style.Green, "", "bold"))
+		} else {
+			fmt.Println(formatter.FormatLine(style.SymInfo, style.BrightCyan, module, "Disabled", style.Yellow, ""))
+		}
+	}
+	fmt.Println(style.Dimmed("\nEnabled modules log the commands they run and the files they change to the log\n" +
+		"file, whatever the log level. Set " + logging.DebugEnv + "=" + strings.Join(logging.Modules[:2], ",") +
+		" to enable modules at startup."))
+
+	menuOptions := make([]style.MenuOption, 0, len(logging.Modules)+1)
+	for i, module := range logging.Modules {
+		action := "Enable"
+		if logging.ModuleDebugEnabled(module) {
+			action = "Disable"
+		}
+		menuOptions = append(menuOptions, style.MenuOption{
+			Number: i + 1, Title: action + " " + module, Description: "Toggle debug entries of the " + module + " module"})
+	}
+	allAction := "Enable"
+	if len(logging.DebugModules()) > 0 {
+		allAction = "Disable"
+	}
+	menuOptions = append(menuOptions, style.MenuOption{
+		Number: len(logging.Modules) + 1, Title: allAction + " all modules", Description: "Toggle every module at once"})
+
+	menu := style.NewMenu("Select a module", menuOptions)
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return",
+		Description: "",
+	})
+	menu.Print()
+
+	choice := ReadMenuInput()
+	if choice == "q" || choice == "0" {
+		return
+	}
+
+	number, err := strconv.Atoi(choice)
+	switch {
+	case err == nil && number >= 1 && number <= len(logging.Modules):
+		module := logging.Modules[number-1]
+		enabled := !logging.ModuleDebugEnabled(module)
+		if !enabled {
+			// A module enabled through "all" stays enabled unless the
+			// others are listed on their own
+			m.expandAll()
+		}
+		logging.SetModuleDebug(module, enabled)
+	case err == nil && number == len(logging.Modules)+1:
+		if allAction == "Enable" {
+			logging.SetDebugModules([]string{logging.ModuleAll})
+		} else {
+			logging.SetDebugModules(nil)
+		}
+	default:
+		fmt.Printf("\n%s Invalid option. Please try again.\n", style.Colored(style.Red, style.SymCrossMark))
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+	}
+	m.Show()
+}
+
+// expandAll replaces "all" with the list of modules, so one can be disabled
+func (m *DebugLoggingMenu) expandAll() {
+	if logging.ModuleDebugEnabled(logging.ModuleAll) {
+		logging.SetDebugModules(logging.Modules)
+	}
+}
@@ -3,15 +3,12 @@ package menu
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/abbott/hardn/pkg/application"
 	"github.com/abbott/hardn/pkg/config"
 	"github.com/abbott/hardn/pkg/osdetect"
 	"github.com/abbott/hardn/pkg/style"
-	"github.com/abbott/hardn/pkg/utils"
 )
 
 // DisableRootMenu handles disabling root SSH access
@@ -36,11 +33,10 @@ func NewDisableRootMenu(
 
 // Show displays the disable root menu and handles user input
 func (m *DisableRootMenu) Show() {
-	utils.PrintHeader()
-	fmt.Println(style.Bolded("Disable Root SSH Access", style.Blue))
+	defer enterScreen("SSH Login")()
 
 	// Check current status of root SSH access
-	rootAccessEnabled, err := m.checkRootLoginEnabled()
+	rootAccessEnabled, err := m.menuManager.IsRootLoginEnabled()
 	if err != nil {
 		fmt.Printf("\n%s Error checking root SSH status: %v\n",
 			style.Colored(style.Red, style.SymCrossMark), err)
@@ -96,6 +92,24 @@ func (m *DisableRootMenu) Show() {
 		Description: "Show details of SSH security settings",
 	})
 
+	menuOptions = append(menuOptions, style.MenuOption{
+		Number:      3,
+		Title:       "Manage listen addresses",
+		Description: "Choose which addresses SSH listens on",
+	})
+
+	menuOptions = append(menuOptions, style.MenuOption{
+		Number:      4,
+		Title:       "Hardening profile",
+		Description: "Apply the baseline, strict or paranoid profile",
+	})
+
+	menuOptions = append(menuOptions, style.MenuOption{
+		Number:      5,
+		Title:       "Effective configuration",
+		Description: "Compare sshd -T with hardn's policy",
+	})
+
 	// Create menu
 	menu := style.NewMenu("Select an option", menuOptions)
 	menu.SetExitOption(style.MenuOption{
@@ -136,36 +150,15 @@ func (m *DisableRootMenu) Show() {
 
 		fmt.Println("\nDisabling root SSH access...")
 
-		if m.config.DryRun {
-			fmt.Printf("%s [DRY-RUN] Would disable root SSH access\n", style.BulletItem)
-		} else {
-			// Call application layer to disable root SSH access
-			err := m.menuManager.DisableRootSSH()
-			if err != nil {
-				fmt.Printf("\n%s Failed to disable root SSH access: %v\n",
-					style.Colored(style.Red, style.SymCrossMark), err)
-			} else {
-				fmt.Printf("\n%s Root SSH access has been disabled\n",
-					style.Colored(style.Green, style.SymCheckMark))
-
-				// Restart SSH service
-				fmt.Println(style.Dimmed("Restarting SSH service..."))
-				var restartErr error
-				if m.osInfo.OsType == "alpine" {
-					restartErr = exec.Command("rc-service", "sshd", "restart").Run()
-				} else {
-					restartErr = exec.Command("systemctl", "restart", "ssh").Run()
-				}
-
-				if restartErr != nil {
-					fmt.Printf("%s Failed to restart SSH service: %v\n",
-						style.Colored(style.Yellow, style.SymWarning), restartErr)
-					fmt.Println(style.Dimmed("You may need to restart the SSH service manually."))
-				} else {
-					fmt.Printf("%s SSH service restarted successfully\n",
-						style.Colored(style.Green, style.SymCheckMark))
-				}
-			}
+		// In dry-run mode the change is printed as a diff instead
+		if err := m.menuManager.DisableRootSSH(); err != nil {
+			fmt.Printf("\n%s Failed to disable root SSH access: %v\n",
+				style.Colored(style.Red, style.SymCrossMark), err)
+		} else if !m.config.DryRun {
+			fmt.Printf("\n%s Root SSH access has been disabled\n",
+				style.Colored(style.Green, style.SymCheckMark))
+
+			fmt.Println(style.Dimmed("SSH service restarted to apply the change"))
 		}
 	case "2":
 		// View current SSH configuration
@@ -190,9 +183,35 @@ func (m *DisableRootMenu) Show() {
 		// Display SSH port
 		fmt.Printf("%s SSH port: %d\n", style.BulletItem, m.config.SshPort)
 
+		// Display listen addresses
+		fmt.Printf("%s Listen addresses: %s\n", style.BulletItem,
+			strings.Join(m.config.SshListenAddresses, ", "))
+
 		// Display additional SSH settings if available
 		fmt.Printf("%s Allowed users: %s\n", style.BulletItem,
 			strings.Join(m.config.SshAllowedUsers, ", "))
+
+		// Display the hardening profile in effect
+		profile, err := m.menuManager.GetSSHProfile()
+		if err != nil || profile == "" {
+			profile = "none"
+		}
+		fmt.Printf("%s Hardening profile: %s\n", style.BulletItem, profile)
+	case "3":
+		listenMenu := NewSSHListenMenu(m.menuManager, m.config, m.osInfo)
+		listenMenu.Show()
+		m.Show()
+		return
+	case "4":
+		profileMenu := NewSSHProfileMenu(m.menuManager, m.config, m.osInfo)
+		profileMenu.Show()
+		m.Show()
+		return
+	case "5":
+		effectiveMenu := NewSSHEffectiveMenu(m.menuManager, m.config)
+		effectiveMenu.Show()
+		m.Show()
+		return
 	case "0":
 		return
 	default:
@@ -203,52 +222,3 @@ func (m *DisableRootMenu) Show() {
 	fmt.Printf("\n%s Press any key to return to the main menu...", style.BulletItem)
 	ReadKey()
 }
-
-// checkRootLoginEnabled checks if SSH root login is enabled by asking the application layer
-func (m *DisableRootMenu) checkRootLoginEnabled() (bool, error) {
-	// In a full implementation, we would call through to the application layer
-	// For now, we'll use a simple file check similar to the old implementation
-
-	// This is temporary and should be replaced with a proper call to the application layer
-	// as it becomes available
-	var rootLoginEnabled bool
-
-	// Check SSH config file - THIS SHOULD BE REPLACED with app layer method
-	var sshConfigPaths []string
-
-	if m.osInfo.OsType == "alpine" {
-		sshConfigPaths = []string{"/etc/ssh/sshd_config"}
-	} else {
-		// For Debian/Ubuntu, check both main config and config.d
-		sshConfigPaths = []string{
-			"/etc/ssh/sshd_config.d/hardn.conf",
-			"/etc/ssh/sshd_config",
-		}
-	}
-
-	// Check each potential config file
-	for _, configPath := range sshConfigPaths {
-		// Check if the file exists and parse it
-		content, err := os.ReadFile(configPath)
-		if err != nil {
-			continue // Try next config file if this one can't be read
-		}
-
-		lines := strings.Split(string(content), "\n")
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if strings.HasPrefix(line, "PermitRootLogin") {
-				parts := strings.Fields(line)
-				if len(parts) >= 2 && (parts[1] == "no" || parts[1] == "No") {
-					rootLoginEnabled = false
-					return rootLoginEnabled, nil
-				}
-				rootLoginEnabled = true
-				return rootLoginEnabled, nil
-			}
-		}
-	}
-
-	// If not explicitly set, assume it's enabled
-	return true, nil
-}
@@ -3,15 +3,12 @@ package menu
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/abbott/hardn/pkg/application"
 	"github.com/abbott/hardn/pkg/config"
 	"github.com/abbott/hardn/pkg/osdetect"
 	"github.com/abbott/hardn/pkg/style"
-	"github.com/abbott/hardn/pkg/utils"
 )
 
 // DNSMenu handles DNS configuration
@@ -36,12 +33,15 @@ func NewDNSMenu(
 
 // Show displays the DNS configuration menu and handles user input
 func (m *DNSMenu) Show() {
-	utils.PrintHeader()
-	fmt.Println(style.Bolded("DNS Configuration", style.Blue))
+	defer enterScreen("DNS")()
 
-	// Check current DNS status - this would ideally come from the application layer
-	// but for now we'll reuse the existing code until it's refactored
-	currentNameservers, dnsImplementation := getCurrentDnsSettings()
+	// Check current DNS status through the application layer
+	var currentNameservers []string
+	dnsImplementation := ""
+	if dnsConfig, err := m.menuManager.GetDNSConfig(); err == nil {
+		currentNameservers = dnsConfig.Nameservers
+		dnsImplementation = dnsConfig.Implementation
+	}
 
 	// Display current configuration
 	fmt.Println()
@@ -143,20 +143,15 @@ func (m *DNSMenu) Show() {
 
 		fmt.Println("\nConfiguring DNS settings...")
 
-		if m.config.DryRun {
-			fmt.Printf("%s [DRY-RUN] Would configure DNS with nameservers: %s\n",
+		// In dry-run mode the change is printed as a diff instead
+		if err := m.menuManager.ConfigureDNS(m.config.Nameservers, "lan"); err != nil {
+			fmt.Printf("\n%s Failed to configure DNS: %v\n",
+				style.Colored(style.Red, style.SymCrossMark), err)
+		} else if !m.config.DryRun {
+			fmt.Printf("\n%s DNS configured successfully\n",
+				style.Colored(style.Green, style.SymCheckMark))
+			fmt.Printf("%s Nameservers: %s\n",
 				style.BulletItem, strings.Join(m.config.Nameservers, ", "))
-		} else {
-			err := m.menuManager.ConfigureDNS(m.config.Nameservers, "lan")
-			if err != nil {
-				fmt.Printf("\n%s Failed to configure DNS: %v\n",
-					style.Colored(style.Red, style.SymCrossMark), err)
-			} else {
-				fmt.Printf("\n%s DNS configured successfully\n",
-					style.Colored(style.Green, style.SymCheckMark))
-				fmt.Printf("%s Nameservers: %s\n",
-					style.BulletItem, strings.Join(m.config.Nameservers, ", "))
-			}
 		}
 
 	case "2":
@@ -340,58 +335,3 @@ func (m *DNSMenu) removeNameserver() {
 	fmt.Printf("\n%s Nameserver %s removed from configuration\n",
 		style.Colored(style.Green, style.SymCheckMark), removedNs)
 }
-
-// getCurrentDnsSettings retrieves the current DNS settings
-// This is a temporary function that will be replaced by application layer calls later
-func getCurrentDnsSettings() ([]string, string) {
-	var nameservers []string
-	dnsImplementation := ""
-
-	// Check if systemd-resolved is active
-	systemdCmd := exec.Command("systemctl", "is-active", "systemd-resolved")
-	if err := systemdCmd.Run(); err == nil {
-		dnsImplementation = "systemd-resolved"
-
-		// Get nameservers from resolved
-		resolvectlCmd := exec.Command("resolvectl", "dns")
-		output, err := resolvectlCmd.CombinedOutput()
-		if err == nil {
-			// Parse output to extract nameservers
-			lines := strings.Split(string(output), "\n")
-			for _, line := range lines {
-				if strings.Contains(line, ":") {
-					parts := strings.Split(line, ":")
-					if len(parts) >= 2 {
-						ns := strings.TrimSpace(parts[1])
-						if ns != "" {
-							nameservers = append(nameservers, ns)
-						}
-					}
-				}
-			}
-		}
-	} else if _, err := exec.LookPath("resolvconf"); err == nil {
-		dnsImplementation = "resolvconf"
-	} else {
-		dnsImplementation = "direct (/etc/resolv.conf)"
-	}
-
-	// If we couldn't get nameservers from implementation-specific means,
-	// try to parse /etc/resolv.conf directly
-	if len(nameservers) == 0 {
-		if data, err := os.ReadFile("/etc/resolv.conf"); err == nil {
-			lines := strings.Split(string(data), "\n")
-			for _, line := range lines {
-				line = strings.TrimSpace(line)
-				if strings.HasPrefix(line, "nameserver") {
-					parts := strings.Fields(line)
-					if len(parts) >= 2 {
-						nameservers = append(nameservers, parts[1])
-					}
-				}
-			}
-		}
-	}
-
-	return nameservers, dnsImplementation
-}
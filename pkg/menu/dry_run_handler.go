@@ -7,7 +7,6 @@ import (
 	"github.com/abbott/hardn/pkg/application"
 	"github.com/abbott/hardn/pkg/config"
 	"github.com/abbott/hardn/pkg/style"
-	"github.com/abbott/hardn/pkg/utils"
 )
 
 // DryRunHandler handles the Dry Run functionality
@@ -30,8 +29,7 @@ func NewDryRunHandler(
 // Handle creates and displays the dry-run configuration menu
 func (h *DryRunHandler) Handle() {
 	// Display contextual information about dry-run mode
-	utils.PrintHeader()
-	fmt.Println(style.Bolded("Dry-Run Mode Configuration", style.Blue))
+	defer enterScreen("Dry-Run")()
 
 	fmt.Println()
 	fmt.Println(style.Dimmed("Dry-run mode allows you to preview changes without applying them to your system."))
@@ -57,7 +55,7 @@ func (h *DryRunHandler) Handle() {
 	dryRunMenu.Show()
 
 	// After returning from the dry-run menu, inform about the status
-	utils.PrintHeader()
+	redrawScreen()
 
 	// Quick feedback on the configuration change before returning to main menu
 	fmt.Printf("\n%s Dry-run mode is now %s\n",
@@ -7,7 +7,6 @@ import (
 	"github.com/abbott/hardn/pkg/application"
 	"github.com/abbott/hardn/pkg/config"
 	"github.com/abbott/hardn/pkg/style"
-	"github.com/abbott/hardn/pkg/utils"
 )
 
 // DryRunMenu handles the dry-run mode configuration
@@ -29,8 +28,7 @@ func NewDryRunMenu(
 
 // Show displays the dry-run mode menu and handles user input
 func (m *DryRunMenu) Show() {
-	utils.PrintHeader()
-	fmt.Println(style.Bolded("Dry-Run Mode Settings", style.Blue))
+	defer enterScreen("Dry-Run")()
 
 	// Create a formatter with just the label we need
 	formatter := style.NewStatusFormatter([]string{"Dry-run Mode"}, 2)
@@ -65,6 +63,12 @@ func (m *DryRunMenu) Show() {
 
 		switch choice {
 		case "1":
+			if m.config.MaintenanceLocked {
+				fmt.Printf("\n%s Outside the maintenance window; dry-run mode stays enabled.\n",
+					style.Colored(style.Yellow, style.SymWarning))
+				fmt.Println(style.Dimmed("Restart hardn with --override-window to apply changes now."))
+				break
+			}
 			m.config.DryRun = false
 			fmt.Println("\n" + formatter.FormatLine(style.SymInfo, style.BrightCyan, "Dry-run Mode", "Disabled", style.Yellow, "", "bold"))
 			fmt.Println(style.Dimmed("\nChanges will now be applied to the system. Proceed with caution."))
@@ -7,8 +7,8 @@ import (
 
 	"github.com/abbott/hardn/pkg/application"
 	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/interfaces"
 	"github.com/abbott/hardn/pkg/style"
-	"github.com/abbott/hardn/pkg/utils"
 )
 
 // EnvironmentSettingsMenu handles environment variable configuration
@@ -30,8 +30,7 @@ func NewEnvironmentSettingsMenu(
 
 // Show displays the environment settings menu and handles user input
 func (m *EnvironmentSettingsMenu) Show() {
-	utils.PrintHeader()
-	fmt.Println(style.Bolded("Environment Variable Settings", style.Blue))
+	defer enterScreen("Environment")()
 
 	// Check if HARDN_CONFIG is set
 	configEnv := os.Getenv("HARDN_CONFIG")
@@ -78,21 +77,17 @@ func (m *EnvironmentSettingsMenu) Show() {
 		// Run sudo env setup
 		fmt.Printf("\n%s Setting up sudo environment preservation...\n", style.BulletItem)
 
-		// Check if running as root
-		if os.Geteuid() != 0 {
+		// Check if running as root or escalating with sudo
+		if !interfaces.IsRoot() && !interfaces.SudoEnabled() {
 			fmt.Printf("\n%s This operation requires sudo privileges.\n", style.Colored(style.Red, style.SymWarning))
 			fmt.Printf("%s Please run: sudo hardn setup-sudo-env\n", style.BulletItem)
 		} else {
-			if m.config.DryRun {
-				fmt.Printf("%s [DRY-RUN] Would configure sudo to preserve HARDN_CONFIG environment variable\n", style.BulletItem)
-			} else {
-				// Use application layer through menuManager
-				err := m.menuManager.SetupSudoPreservation()
-				if err != nil {
-					fmt.Printf("\n%s Failed to configure sudo: %v\n", style.Colored(style.Red, style.SymCrossMark), err)
-				} else {
-					fmt.Printf("\n%s Successfully configured sudo to preserve HARDN_CONFIG\n", style.Colored(style.Green, style.SymCheckMark))
-				}
+			// Use application layer through menuManager; dry runs print the sudoers diff
+			err := m.menuManager.SetupSudoPreservation()
+			if err != nil {
+				fmt.Printf("\n%s Failed to configure sudo: %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+			} else if !m.config.DryRun {
+				fmt.Printf("\n%s Successfully configured sudo to preserve HARDN_CONFIG\n", style.Colored(style.Green, style.SymCheckMark))
 			}
 		}
 
@@ -118,8 +113,7 @@ func (m *EnvironmentSettingsMenu) Show() {
 
 // showEnvironmentGuide displays a guide on how to set up environment variables
 func (m *EnvironmentSettingsMenu) showEnvironmentGuide() {
-	utils.PrintHeader()
-	fmt.Println(style.Bolded("Environment Variables Guide", style.Blue))
+	defer enterScreen("Guide")()
 
 	fmt.Printf("\n%s HARDN_CONFIG Environment Variable\n", style.Bolded("", style.Blue))
 	fmt.Println(style.Dimmed("------------------------------------"))
@@ -0,0 +1,83 @@
+// pkg/menu/firewall_drift_menu.go
+package menu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// checkRuleDrift compares the rules the configuration declares with the
+// live ruleset and offers to configure the firewall again when they differ
+func (m *FirewallMenu) checkRuleDrift() {
+	defer enterScreen("Rule Drift")()
+
+	drift, err := m.menuManager.CheckFirewallDrift(m.config.SSHFirewallPolicy(), m.config.IPv6.Policy(), m.config.UfwAllowedPorts,
+		m.config.CustomFirewallRules(), m.config.FirewallProfiles())
+	if err != nil {
+		fmt.Printf("\n%s Failed to check firewall rules: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		return
+	}
+
+	fmt.Println()
+	fmt.Println(style.Bolded("Firewall Rule Drift:", style.Blue))
+	printFirewallDrift(drift)
+
+	if drift.InSync() {
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		return
+	}
+
+	fmt.Printf("\n%s Re-applying resets %s to the declared rules and removes the others.\n",
+		style.Colored(style.Yellow, style.SymWarning), m.firewallName())
+	fmt.Printf("%s Re-apply the declared rules? (y/n): ", style.BulletItem)
+
+	confirm := ReadInput()
+	if strings.ToLower(confirm) != "y" && strings.ToLower(confirm) != "yes" {
+		fmt.Printf("\nOperation cancelled. %s is unchanged.\n", m.firewallName())
+	} else if m.config.DryRun {
+		fmt.Printf("%s [DRY-RUN] Would reset %s and apply %d declared rules\n",
+			style.BulletItem, m.firewallName(), len(drift.Declared))
+	} else {
+		err := m.menuManager.ConfigureSecureFirewall(m.config.SSHFirewallPolicy(), m.config.IPv6.Policy(), m.config.UfwAllowedPorts,
+			m.config.CustomFirewallRules(), m.config.FirewallProfiles())
+		if err != nil {
+			fmt.Printf("\n%s Failed to re-apply the firewall rules: %v\n",
+				style.Colored(style.Red, style.SymCrossMark), err)
+		} else {
+			fmt.Printf("\n%s Firewall rules re-applied\n",
+				style.Colored(style.Green, style.SymCheckMark))
+		}
+	}
+
+	fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+	ReadKey()
+}
+
+// printFirewallDrift lists what differs between the declared and the live
+// firewall
+func printFirewallDrift(drift *model.FirewallDrift) {
+	if drift.InSync() {
+		fmt.Printf("%s The firewall matches the configuration (%d rules)\n",
+			style.Colored(style.Green, style.SymCheckMark), len(drift.Declared))
+		return
+	}
+	if !drift.Enabled {
+		fmt.Printf("%s The firewall is not enabled\n", style.Colored(style.Red, style.SymCrossMark))
+	}
+	if !drift.DefaultDeny {
+		fmt.Printf("%s Incoming traffic is not denied by default\n", style.Colored(style.Red, style.SymCrossMark))
+	}
+	for _, rule := range drift.Missing {
+		fmt.Printf("%s Missing: %s\n", style.Colored(style.Yellow, style.SymWarning), rule)
+	}
+	for _, rule := range drift.Extra {
+		fmt.Printf("%s Added outside hardn: %s\n", style.Colored(style.Yellow, style.SymWarning), rule)
+	}
+}
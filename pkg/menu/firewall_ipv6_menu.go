@@ -0,0 +1,148 @@
+// pkg/menu/firewall_ipv6_menu.go
+package menu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// manageIPv6 handles the IPv6 submenu: the current exposure and the
+// choice between filtering, denying and disabling IPv6
+func (m *FirewallMenu) manageIPv6() {
+	defer enterScreen("IPv6 Policy")()
+
+	policy := m.config.IPv6.Policy()
+
+	fmt.Println()
+	fmt.Println(style.Bolded("IPv6 Policy:", style.Blue))
+	fmt.Printf("%s Mode: %s\n", style.BulletItem, policy.Mode)
+
+	exposure, err := m.menuManager.CheckIPv6Exposure(m.config.SSHFirewallPolicy(), policy,
+		m.config.UfwAllowedPorts, m.config.CustomFirewallRules(), m.config.FirewallProfiles())
+	if err != nil {
+		fmt.Printf("%s Failed to check IPv6: %v\n", style.Colored(style.Yellow, style.SymWarning), err)
+	} else {
+		printIPv6Exposure(exposure)
+	}
+
+	menuOptions := []style.MenuOption{
+		{Number: 1, Title: "Filter IPv6", Description: "Apply the IPv4 rules to IPv6 as well"},
+		{Number: 2, Title: "Deny IPv6", Description: "Let IPv6 in only to SSH and to rules for IPv6"},
+		{Number: 3, Title: "Disable IPv6", Description: "Turn IPv6 off in the kernel"},
+	}
+
+	menu := style.NewMenu("Select an option", menuOptions)
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return to firewall menu",
+		Description: "",
+	})
+	menu.Print()
+
+	choice := ReadMenuInput()
+
+	// Handle 'q' as a special exit case
+	if choice == "q" {
+		return
+	}
+
+	switch choice {
+	case "1":
+		m.setIPv6Mode(model.IPv6Policy{Mode: model.IPv6Filter})
+		m.manageIPv6()
+		return
+
+	case "2":
+		m.setIPv6Mode(model.IPv6Policy{Mode: model.IPv6Deny})
+		m.manageIPv6()
+		return
+
+	case "3":
+		fmt.Printf("\n%s Services listening only on IPv6 addresses stop being reachable.\n",
+			style.Colored(style.Yellow, style.SymWarning))
+		fmt.Printf("%s Also turn IPv6 off on the kernel command line (takes effect after a reboot)? (y/n): ",
+			style.BulletItem)
+		boot := strings.ToLower(ReadInput())
+		m.setIPv6Mode(model.IPv6Policy{Mode: model.IPv6Disable, BootParameter: boot == "y" || boot == "yes"})
+		m.manageIPv6()
+		return
+
+	case "0":
+		return
+
+	default:
+		fmt.Printf("\n%s Invalid option. Please try again.\n",
+			style.Colored(style.Red, style.SymCrossMark))
+
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		m.manageIPv6()
+		return
+	}
+}
+
+// printIPv6Exposure prints how the kernel and the firewall treat IPv6
+func printIPv6Exposure(exposure *model.IPv6Exposure) {
+	switch {
+	case !exposure.Enabled && exposure.BootDisabled:
+		fmt.Printf("%s Kernel: IPv6 disabled on the kernel command line\n", style.BulletItem)
+	case !exposure.Enabled:
+		fmt.Printf("%s Kernel: IPv6 disabled with sysctl\n", style.BulletItem)
+	case !exposure.Addressed:
+		fmt.Printf("%s Kernel: IPv6 enabled, no address\n", style.BulletItem)
+	case !exposure.Filtered:
+		fmt.Printf("%s Firewall: %s\n", style.BulletItem,
+			style.Colored(style.Yellow, "every service reachable over IPv6"))
+	default:
+		fmt.Printf("%s Firewall: %d rules let IPv6 in\n", style.BulletItem, len(exposure.Allowed))
+	}
+	for _, rule := range exposure.Unexpected {
+		fmt.Printf("  %s %s open over IPv6\n", style.Colored(style.Yellow, style.SymWarning), rule)
+	}
+}
+
+// setIPv6Mode configures the firewall and the kernel for an IPv6 mode
+// after confirmation and saves it
+func (m *FirewallMenu) setIPv6Mode(policy model.IPv6Policy) {
+	fmt.Printf("\n%s Reset %s and apply the IPv6 %s mode? (y/n): ", style.BulletItem, m.firewallName(), policy.Mode)
+	confirm := strings.ToLower(ReadInput())
+	if confirm != "y" && confirm != "yes" {
+		fmt.Println("\nIPv6 mode unchanged.")
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		return
+	}
+
+	if m.config.DryRun {
+		fmt.Printf("%s [DRY-RUN] Would reset %s for the IPv6 %s mode\n", style.BulletItem, m.firewallName(), policy.Mode)
+		fmt.Printf("%s [DRY-RUN] Would save the change to the configuration\n", style.BulletItem)
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		return
+	}
+
+	err := m.menuManager.ConfigureSecureFirewall(m.config.SSHFirewallPolicy(), policy, m.config.UfwAllowedPorts,
+		m.config.CustomFirewallRules(), m.config.FirewallProfiles())
+	if err != nil {
+		fmt.Printf("\n%s Failed to apply the IPv6 mode: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+	} else {
+		m.config.IPv6 = config.IPv6{Mode: policy.Mode, BootParameter: policy.BootParameter}
+		if err := config.SaveConfig(m.config, "hardn.yml"); err != nil {
+			fmt.Printf("\n%s IPv6 mode applied, but saving the configuration failed: %v\n",
+				style.Colored(style.Red, style.SymCrossMark), err)
+		} else {
+			fmt.Printf("\n%s IPv6 %s mode applied\n", style.Colored(style.Green, style.SymCheckMark), policy.Mode)
+		}
+		if policy.BootParameter {
+			fmt.Printf("%s Reboot to turn IPv6 off on the kernel command line\n", style.BulletItem)
+		}
+	}
+
+	fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+	ReadKey()
+}
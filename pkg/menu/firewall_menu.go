@@ -11,10 +11,9 @@ import (
 	"github.com/abbott/hardn/pkg/domain/model"
 	"github.com/abbott/hardn/pkg/osdetect"
 	"github.com/abbott/hardn/pkg/style"
-	"github.com/abbott/hardn/pkg/utils"
 )
 
-// FirewallMenu handles UFW firewall configuration
+// FirewallMenu handles UFW and firewalld configuration
 type FirewallMenu struct {
 	menuManager *application.MenuManager
 	config      *config.Config
@@ -36,10 +35,9 @@ func NewFirewallMenu(
 
 // Show displays the firewall menu and handles user input
 func (m *FirewallMenu) Show() {
-	utils.PrintHeader()
-	fmt.Println(style.Bolded("UFW Firewall Configuration", style.Blue))
+	defer enterScreen("Firewall")()
 
-	// Check current UFW status - this would ideally come from the application layer
+	// Check current firewall status - this would ideally come from the application layer
 	isInstalled, isEnabled, isConfigured, rules, err := m.menuManager.GetFirewallStatus()
 	if err != nil {
 		fmt.Printf("\n%s Error getting firewall status: %v\n",
@@ -55,20 +53,21 @@ func (m *FirewallMenu) Show() {
 	fmt.Println(style.Bolded("Current Firewall Status:", style.Blue))
 
 	// Create formatter for status display
-	formatter := style.NewStatusFormatter([]string{"UFW Installed", "UFW Status", "SSH Port"}, 2)
+	name := m.firewallName()
+	formatter := style.NewStatusFormatter([]string{name + " Installed", name + " Status", "SSH Port"}, 2)
 
 	// Installation status
 	if isInstalled {
-		fmt.Println(formatter.FormatSuccess("UFW Installed", "Yes", "Uncomplicated Firewall is available"))
+		fmt.Println(formatter.FormatSuccess(name+" Installed", "Yes", m.firewallDescription()+" is available"))
 	} else {
-		fmt.Println(formatter.FormatWarning("UFW Installed", "No", "Firewall package not found"))
+		fmt.Println(formatter.FormatWarning(name+" Installed", "No", "Firewall package not found"))
 	}
 
 	// Enabled status
 	if isEnabled {
-		fmt.Println(formatter.FormatSuccess("UFW Status", "Active", "Firewall is running"))
+		fmt.Println(formatter.FormatSuccess(name+" Status", "Active", "Firewall is running"))
 	} else {
-		fmt.Println(formatter.FormatWarning("UFW Status", "Inactive", "Firewall is not running"))
+		fmt.Println(formatter.FormatWarning(name+" Status", "Inactive", "Firewall is not running"))
 	}
 
 	// SSH port status
@@ -114,46 +113,76 @@ func (m *FirewallMenu) Show() {
 	// Create menu options
 	var menuOptions []style.MenuOption
 
-	// Install UFW if not installed
+	// Install the firewall if not installed
 	if !isInstalled {
 		menuOptions = append(menuOptions, style.MenuOption{
 			Number:      1,
-			Title:       "Install UFW",
+			Title:       "Install " + name,
 			Description: "Install Uncomplicated Firewall package",
 		})
-	} else {
-		// Standard options when UFW is installed
-
+	} else if !isEnabled {
 		// Enable/disable option
-		if !isEnabled {
-			menuOptions = append(menuOptions, style.MenuOption{
-				Number:      1,
-				Title:       "Enable firewall",
-				Description: "Start UFW and set to run at boot",
-			})
-		} else {
-			menuOptions = append(menuOptions, style.MenuOption{
-				Number:      1,
-				Title:       "Disable firewall",
-				Description: "Stop UFW (not recommended)",
-			})
-		}
-
-		// Configure option
 		menuOptions = append(menuOptions, style.MenuOption{
-			Number:      2,
-			Title:       "Configure firewall",
-			Description: "Set up default policies and SSH rules",
+			Number:      1,
+			Title:       "Enable firewall",
+			Description: "Start " + name + " and set to run at boot",
 		})
-
-		// Manage application profiles
+	} else {
 		menuOptions = append(menuOptions, style.MenuOption{
-			Number:      3,
-			Title:       "Manage application profiles",
-			Description: "Configure custom application rules",
+			Number:      1,
+			Title:       "Disable firewall",
+			Description: "Stop " + name + " (not recommended)",
 		})
 	}
 
+	// Configure option
+	menuOptions = append(menuOptions, style.MenuOption{
+		Number:         2,
+		Title:          "Configure firewall",
+		Description:    "Set up default policies and SSH rules",
+		DisabledReason: firewallReason(isInstalled, name),
+	})
+
+	// Manage application profiles
+	menuOptions = append(menuOptions, style.MenuOption{
+		Number:         3,
+		Title:          "Manage application profiles",
+		Description:    "Configure custom application rules",
+		DisabledReason: firewallReason(isInstalled, name),
+	})
+
+	// Build rules kept across reconfiguration
+	menuOptions = append(menuOptions, style.MenuOption{
+		Number:         4,
+		Title:          "Manage custom rules",
+		Description:    "Build port rules that survive reconfiguration",
+		DisabledReason: firewallReason(isInstalled, name),
+	})
+
+	// Compare the live rules with the configuration
+	menuOptions = append(menuOptions, style.MenuOption{
+		Number:         5,
+		Title:          "Check rule drift",
+		Description:    "Find rules changed outside hardn and re-apply",
+		DisabledReason: firewallReason(isInstalled, name),
+	})
+
+	// Rate limiting and port knocking for SSH
+	menuOptions = append(menuOptions, style.MenuOption{
+		Number:         6,
+		Title:          "SSH access",
+		Description:    "Rate-limit SSH or keep it closed until a knock sequence",
+		DisabledReason: firewallReason(isInstalled, name),
+	})
+
+	// Filter, deny or disable IPv6
+	menuOptions = append(menuOptions, style.MenuOption{
+		Number:         7,
+		Title:          "IPv6 policy",
+		Description:    "Filter IPv6, keep it to SSH or turn it off",
+		DisabledReason: firewallReason(isInstalled, name),
+	})
+
 	// Create menu
 	menu := style.NewMenu("Select an option", menuOptions)
 	menu.SetExitOption(style.MenuOption{
@@ -172,45 +201,58 @@ func (m *FirewallMenu) Show() {
 		return
 	}
 
+	if rejectDisabledOption(menu, choice) {
+		m.Show()
+		return
+	}
+
 	switch choice {
 	case "1":
 		if !isInstalled {
-			// Install UFW - this should call through to an application service
-			fmt.Println("\nInstalling UFW...")
+			// Install the firewall - this should call through to an application service
+			fmt.Printf("\nInstalling %s...\n", name)
 
 			if m.config.DryRun {
-				fmt.Printf("%s [DRY-RUN] Would install UFW package\n", style.BulletItem)
+				fmt.Printf("%s [DRY-RUN] Would install %s package\n", style.BulletItem, name)
 			} else {
-				// TODO: This should go through the application layer
-				// For now, we'll just provide a message
-				fmt.Printf("%s This operation isn't yet implemented in the new architecture\n",
-					style.Colored(style.Yellow, style.SymWarning))
+				err := m.menuManager.InstallFirewall()
+				if err != nil {
+					fmt.Printf("\n%s Failed to install %s: %v\n",
+						style.Colored(style.Red, style.SymCrossMark), name, err)
+				} else {
+					fmt.Printf("\n%s %s installed successfully\n",
+						style.Colored(style.Green, style.SymCheckMark), name)
+				}
 			}
 		} else if isEnabled {
 			// Disable firewall through application layer
 			fmt.Printf("\n%s WARNING: Disabling the firewall will remove protection from your system.\n",
 				style.Colored(style.Red, style.SymWarning))
-			fmt.Printf("%s Are you sure you want to disable UFW? (y/n): ", style.BulletItem)
+			fmt.Printf("%s Are you sure you want to disable %s? (y/n): ", style.BulletItem, name)
 
 			confirm := ReadInput()
 			if strings.ToLower(confirm) == "y" || strings.ToLower(confirm) == "yes" {
 				if m.config.DryRun {
-					fmt.Printf("%s [DRY-RUN] Would disable UFW\n", style.BulletItem)
+					fmt.Printf("%s [DRY-RUN] Would disable %s\n", style.BulletItem, name)
 				} else {
-					// Call to application layer to disable firewall
-					// TODO: Implement this in MenuManager and FirewallManager
-					fmt.Printf("%s This operation isn't yet implemented in the new architecture\n",
-						style.Colored(style.Yellow, style.SymWarning))
+					err := m.menuManager.DisableFirewall()
+					if err != nil {
+						fmt.Printf("\n%s Failed to disable firewall: %v\n",
+							style.Colored(style.Red, style.SymCrossMark), err)
+					} else {
+						fmt.Printf("\n%s Firewall disabled\n",
+							style.Colored(style.Yellow, style.SymWarning))
+					}
 				}
 			} else {
-				fmt.Println("\nOperation cancelled. UFW remains enabled.")
+				fmt.Printf("\nOperation cancelled. %s remains enabled.\n", name)
 			}
 		} else {
 			// Enable firewall through application layer
-			fmt.Println("\nEnabling UFW...")
+			fmt.Printf("\nEnabling %s...\n", name)
 
 			if m.config.DryRun {
-				fmt.Printf("%s [DRY-RUN] Would enable UFW\n", style.BulletItem)
+				fmt.Printf("%s [DRY-RUN] Would enable %s\n", style.BulletItem, name)
 			} else {
 				// Convert app profiles to domain model format
 				var profiles []model.FirewallProfile
@@ -224,7 +266,7 @@ func (m *FirewallMenu) Show() {
 				}
 
 				// Call application layer to configure firewall with profiles
-				err := m.menuManager.ConfigureSecureFirewall(m.config.SshPort, []int{}, profiles)
+				err := m.menuManager.ConfigureSecureFirewall(m.config.SSHFirewallPolicy(), m.config.IPv6.Policy(), []int{}, m.config.CustomFirewallRules(), profiles)
 				if err != nil {
 					fmt.Printf("\n%s Failed to enable and configure firewall: %v\n",
 						style.Colored(style.Red, style.SymCrossMark), err)
@@ -241,11 +283,11 @@ func (m *FirewallMenu) Show() {
 		m.Show()
 
 	case "2":
-		// Configure UFW
-		fmt.Println("\nConfiguring UFW firewall...")
+		// Configure the firewall
+		fmt.Printf("\nConfiguring %s firewall...\n", name)
 
 		if m.config.DryRun {
-			fmt.Printf("%s [DRY-RUN] Would configure UFW with default policies and SSH rules\n", style.BulletItem)
+			fmt.Printf("%s [DRY-RUN] Would configure %s with default policies and SSH rules\n", style.BulletItem, name)
 			fmt.Printf("%s [DRY-RUN] SSH port: %d/tcp\n", style.BulletItem, m.config.SshPort)
 		} else {
 			// Convert app profiles to domain model format
@@ -260,7 +302,7 @@ func (m *FirewallMenu) Show() {
 			}
 
 			// Call application layer to configure firewall
-			err := m.menuManager.ConfigureSecureFirewall(m.config.SshPort, []int{}, profiles)
+			err := m.menuManager.ConfigureSecureFirewall(m.config.SSHFirewallPolicy(), m.config.IPv6.Policy(), []int{}, m.config.CustomFirewallRules(), profiles)
 			if err != nil {
 				fmt.Printf("\n%s Failed to configure firewall: %v\n",
 					style.Colored(style.Red, style.SymCrossMark), err)
@@ -286,6 +328,30 @@ func (m *FirewallMenu) Show() {
 		m.Show()
 		return
 
+	case "4":
+		// Manage custom rules
+		m.manageCustomRules()
+		m.Show()
+		return
+
+	case "5":
+		// Check rule drift
+		m.checkRuleDrift()
+		m.Show()
+		return
+
+	case "6":
+		// SSH rate limiting and port knocking
+		m.manageSSHAccess()
+		m.Show()
+		return
+
+	case "7":
+		// IPv6 mode and exposure
+		m.manageIPv6()
+		m.Show()
+		return
+
 	case "0":
 		// Return to main menu
 		return
@@ -307,8 +373,7 @@ func (m *FirewallMenu) Show() {
 
 // manageAppProfiles handles the application profiles management submenu
 func (m *FirewallMenu) manageAppProfiles() {
-	utils.PrintHeader()
-	fmt.Println(style.Bolded("Manage UFW Application Profiles", style.Blue))
+	defer enterScreen("App Profiles")()
 
 	// Display current profiles
 	fmt.Println()
@@ -328,24 +393,29 @@ func (m *FirewallMenu) manageAppProfiles() {
 
 	// Create menu options
 	menuOptions := []style.MenuOption{
-		{Number: 1, Title: "Add application profile", Description: "Create a new UFW application profile"},
+		{Number: 1, Title: "Add application profile", Description: "Create a new " + m.firewallName() + " application profile"},
 	}
 
-	// Only add remove option if profiles exist
-	if len(m.config.UfwAppProfiles) > 0 {
-		menuOptions = append(menuOptions, style.MenuOption{
-			Number:      2,
-			Title:       "Remove application profile",
-			Description: "Delete an existing UFW application profile",
-		})
-
-		menuOptions = append(menuOptions, style.MenuOption{
-			Number:      3,
-			Title:       "Apply profiles",
-			Description: "Enable configured application profiles in UFW",
-		})
+	// Removing and applying need at least one profile
+	noProfilesReason := ""
+	if len(m.config.UfwAppProfiles) == 0 {
+		noProfilesReason = "no application profiles configured"
 	}
 
+	menuOptions = append(menuOptions, style.MenuOption{
+		Number:         2,
+		Title:          "Remove application profile",
+		Description:    "Delete an existing " + m.firewallName() + " application profile",
+		DisabledReason: noProfilesReason,
+	})
+
+	menuOptions = append(menuOptions, style.MenuOption{
+		Number:         3,
+		Title:          "Apply profiles",
+		Description:    "Enable configured application profiles in " + m.firewallName(),
+		DisabledReason: noProfilesReason,
+	})
+
 	// Create menu
 	menu := style.NewMenu("Select an option", menuOptions)
 	menu.SetExitOption(style.MenuOption{
@@ -364,6 +434,11 @@ func (m *FirewallMenu) manageAppProfiles() {
 		return
 	}
 
+	if rejectDisabledOption(menu, choice) {
+		m.manageAppProfiles()
+		return
+	}
+
 	switch choice {
 	case "1":
 		// Add application profile
@@ -372,25 +447,15 @@ func (m *FirewallMenu) manageAppProfiles() {
 		return
 
 	case "2":
-		// Remove application profile (only if profiles exist)
-		if len(m.config.UfwAppProfiles) == 0 {
-			fmt.Printf("\n%s No profiles to remove\n",
-				style.Colored(style.Yellow, style.SymWarning))
-		} else {
-			m.removeAppProfile()
-		}
+		// Remove application profile
+		m.removeAppProfile()
 
 		m.manageAppProfiles()
 		return
 
 	case "3":
-		// Apply profiles (only if profiles exist)
-		if len(m.config.UfwAppProfiles) == 0 {
-			fmt.Printf("\n%s No profiles to apply\n",
-				style.Colored(style.Yellow, style.SymWarning))
-		} else {
-			m.applyAppProfiles()
-		}
+		// Apply profiles
+		m.applyAppProfiles()
 
 		m.manageAppProfiles()
 		return
@@ -414,7 +479,7 @@ func (m *FirewallMenu) manageAppProfiles() {
 // handle adding a new application profile
 func (m *FirewallMenu) addAppProfile() {
 	fmt.Println()
-	fmt.Println(style.Bolded("Add UFW Application Profile:", style.Blue))
+	fmt.Println(style.Bolded("Add "+m.firewallName()+" Application Profile:", style.Blue))
 
 	// Get profile details
 	fmt.Printf("%s Enter profile name (e.g., 'WebServer'): ", style.BulletItem)
@@ -488,7 +553,7 @@ func (m *FirewallMenu) addAppProfile() {
 // removeAppProfile handles removing an application profile
 func (m *FirewallMenu) removeAppProfile() {
 	fmt.Println()
-	fmt.Println(style.Bolded("Remove UFW Application Profile:", style.Blue))
+	fmt.Println(style.Bolded("Remove "+m.firewallName()+" Application Profile:", style.Blue))
 
 	// Display numbered list of profiles
 	for i, profile := range m.config.UfwAppProfiles {
@@ -541,17 +606,60 @@ func (m *FirewallMenu) removeAppProfile() {
 // applyAppProfiles handles applying application profiles
 func (m *FirewallMenu) applyAppProfiles() {
 	fmt.Println()
-	fmt.Println(style.Bolded("Apply UFW Application Profiles:", style.Blue))
+	fmt.Println(style.Bolded("Apply "+m.firewallName()+" Application Profiles:", style.Blue))
 
 	if m.config.DryRun {
-		fmt.Printf("%s [DRY-RUN] Would write profiles to /etc/ufw/applications.d/hardn\n", style.BulletItem)
+		fmt.Printf("%s [DRY-RUN] Would write profiles to %s\n", style.BulletItem, m.profilesPath())
 		for _, profile := range m.config.UfwAppProfiles {
 			fmt.Printf("%s [DRY-RUN] Profile: %s (%s)\n",
 				style.BulletItem, profile.Name, strings.Join(profile.Ports, ", "))
 		}
 	} else {
-		// This should call the application layer, but for now we'll just provide a message
-		fmt.Printf("\n%s This operation isn't yet implemented in the new architecture\n",
-			style.Colored(style.Yellow, style.SymWarning))
+		// Convert app profiles to domain model format
+		var profiles []model.FirewallProfile
+		for _, profile := range m.config.UfwAppProfiles {
+			profiles = append(profiles, model.FirewallProfile{
+				Name:        profile.Name,
+				Title:       profile.Title,
+				Description: profile.Description,
+				Ports:       profile.Ports,
+			})
+		}
+
+		err := m.menuManager.ApplyFirewallProfiles(profiles)
+		if err != nil {
+			fmt.Printf("\n%s Failed to apply application profiles: %v\n",
+				style.Colored(style.Red, style.SymCrossMark), err)
+		} else {
+			fmt.Printf("\n%s Applied %d application profile(s)\n",
+				style.Colored(style.Green, style.SymCheckMark), len(profiles))
+		}
+	}
+
+	fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+	ReadKey()
+}
+
+// firewallName returns the name of the firewall hardn manages on this host
+func (m *FirewallMenu) firewallName() string {
+	if model.IsRHELFamily(m.osInfo.OsType) {
+		return "firewalld"
+	}
+	return "UFW"
+}
+
+// firewallDescription returns the firewall's full name
+func (m *FirewallMenu) firewallDescription() string {
+	if model.IsRHELFamily(m.osInfo.OsType) {
+		return "The firewalld zone firewall"
+	}
+	return "Uncomplicated Firewall"
+}
+
+// profilesPath returns where application profiles are written
+func (m *FirewallMenu) profilesPath() string {
+	if model.IsRHELFamily(m.osInfo.OsType) {
+		return "/etc/firewalld/services/hardn-*.xml"
 	}
+	return "/etc/ufw/applications.d/hardn"
 }
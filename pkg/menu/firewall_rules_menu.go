@@ -0,0 +1,281 @@
+// pkg/menu/firewall_rules_menu.go
+package menu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// manageCustomRules handles the custom rules submenu. Custom rules are
+// saved in the configuration, so configuring the firewall again, which
+// resets it, adds them back.
+func (m *FirewallMenu) manageCustomRules() {
+	defer enterScreen("Custom Rules")()
+
+	fmt.Println()
+	fmt.Println(style.Bolded("Custom Firewall Rules:", style.Blue))
+
+	if len(m.config.FirewallRules) == 0 {
+		fmt.Printf("%s No custom rules configured\n", style.BulletItem)
+	} else {
+		for i, rule := range m.config.FirewallRules {
+			fmt.Printf("%s %d: %s\n", style.BulletItem, i+1, describeFirewallRule(rule.Rule()))
+		}
+	}
+
+	noRulesReason := ""
+	if len(m.config.FirewallRules) == 0 {
+		noRulesReason = "no custom rules configured"
+	}
+
+	menuOptions := []style.MenuOption{
+		{Number: 1, Title: "Add rule", Description: "Build a rule step by step and preview it"},
+		{Number: 2, Title: "Remove rule", Description: "Delete a custom rule from " + m.firewallName(), DisabledReason: noRulesReason},
+	}
+
+	menu := style.NewMenu("Select an option", menuOptions)
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return to firewall menu",
+		Description: "",
+	})
+	menu.Print()
+
+	choice := ReadMenuInput()
+
+	// Handle 'q' as a special exit case
+	if choice == "q" {
+		return
+	}
+
+	if rejectDisabledOption(menu, choice) {
+		m.manageCustomRules()
+		return
+	}
+
+	switch choice {
+	case "1":
+		m.buildCustomRule()
+		m.manageCustomRules()
+		return
+
+	case "2":
+		m.removeCustomRule()
+		m.manageCustomRules()
+		return
+
+	case "0":
+		return
+
+	default:
+		fmt.Printf("\n%s Invalid option. Please try again.\n",
+			style.Colored(style.Red, style.SymCrossMark))
+
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		m.manageCustomRules()
+		return
+	}
+}
+
+// buildCustomRule asks for each field of a rule, checking it as it is
+// entered, then previews the firewall command and adds the rule
+func (m *FirewallMenu) buildCustomRule() {
+	fmt.Println()
+	fmt.Println(style.Bolded("Build "+m.firewallName()+" Rule:", style.Blue))
+	fmt.Printf("%s Press Enter to accept the default in brackets; leave the port empty to cancel\n\n",
+		style.Dimmed(style.SymInfo))
+
+	rule := model.FirewallRule{}
+	rule.Action = readRuleChoice("Action", []string{"allow", "deny", "reject"})
+	rule.Protocol = readRuleChoice("Protocol", []string{"tcp", "udp"})
+
+	for {
+		fmt.Printf("%s Port or range (e.g., '8080' or '6000-6007'): ", style.BulletItem)
+		spec := ReadInput()
+		if spec == "" {
+			fmt.Println("\nRule cancelled.")
+			return
+		}
+		port, endPort, err := m.menuManager.ParsePortRange(spec)
+		if err == nil {
+			rule.Port, rule.EndPort = port, endPort
+			break
+		}
+		fmt.Printf("  %s %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+	}
+
+	// The fields before are valid, so the preview only fails on the one
+	// just entered
+	readRuleField("Source IP or network (empty for anywhere)", func(value string) error {
+		rule.SourceIP = value
+		_, err := m.menuManager.PreviewFirewallRule(rule)
+		return err
+	})
+	readRuleField("Comment (optional)", func(value string) error {
+		rule.Description = value
+		_, err := m.menuManager.PreviewFirewallRule(rule)
+		return err
+	})
+
+	command, err := m.menuManager.PreviewFirewallRule(rule)
+	if err != nil {
+		fmt.Printf("\n%s Invalid rule: %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+		return
+	}
+
+	for _, saved := range m.config.FirewallRules {
+		if sameFirewallRule(saved.Rule(), rule) {
+			fmt.Printf("\n%s This rule is already configured\n", style.Colored(style.Yellow, style.SymWarning))
+			return
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(style.Bolded("Preview:", style.Blue))
+	fmt.Printf("%s Rule:    %s\n", style.BulletItem, describeFirewallRule(rule))
+	fmt.Printf("%s Command: %s\n", style.BulletItem, style.Colored(style.Cyan, command))
+	if rule.SourceIP == "" && rule.Action == "allow" {
+		fmt.Printf("%s Opens %s to every address\n", style.Colored(style.Yellow, style.SymWarning), ruleTarget(rule))
+	}
+
+	fmt.Printf("\n%s Add this rule? (y/n): ", style.BulletItem)
+	confirm := strings.ToLower(ReadInput())
+	if confirm != "y" && confirm != "yes" {
+		fmt.Println("\nRule discarded.")
+		return
+	}
+
+	if m.config.DryRun {
+		fmt.Printf("%s [DRY-RUN] Would run: %s\n", style.BulletItem, command)
+		fmt.Printf("%s [DRY-RUN] Would save the rule to the configuration\n", style.BulletItem)
+		return
+	}
+
+	if err := m.menuManager.AddFirewallRule(rule); err != nil {
+		fmt.Printf("\n%s Failed to add rule: %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+		return
+	}
+
+	m.config.FirewallRules = append(m.config.FirewallRules, config.FirewallRule{
+		Action:   rule.Action,
+		Protocol: rule.Protocol,
+		Port:     rule.Port,
+		EndPort:  rule.EndPort,
+		Source:   rule.SourceIP,
+		Comment:  rule.Description,
+	})
+	if err := config.SaveConfig(m.config, "hardn.yml"); err != nil {
+		fmt.Printf("\n%s Rule added, but saving the configuration failed: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		return
+	}
+
+	fmt.Printf("\n%s Rule added and saved: %s\n",
+		style.Colored(style.Green, style.SymCheckMark), describeFirewallRule(rule))
+}
+
+// removeCustomRule removes a custom rule from the firewall and the
+// configuration
+func (m *FirewallMenu) removeCustomRule() {
+	fmt.Println()
+	fmt.Println(style.Bolded("Remove Custom Rule:", style.Blue))
+
+	for i, rule := range m.config.FirewallRules {
+		fmt.Printf("%s %d: %s\n", style.BulletItem, i+1, describeFirewallRule(rule.Rule()))
+	}
+
+	fmt.Printf("\n%s Enter rule number to remove (1-%d): ", style.BulletItem, len(m.config.FirewallRules))
+	num, err := strconv.Atoi(ReadInput())
+	if err != nil || num < 1 || num > len(m.config.FirewallRules) {
+		fmt.Printf("\n%s Invalid rule number\n", style.Colored(style.Red, style.SymCrossMark))
+		return
+	}
+	rule := m.config.FirewallRules[num-1].Rule()
+
+	fmt.Printf("%s Are you sure you want to remove '%s'? (y/n): ", style.BulletItem, describeFirewallRule(rule))
+	confirm := strings.ToLower(ReadInput())
+	if confirm != "y" && confirm != "yes" {
+		fmt.Println("\nRemoval cancelled.")
+		return
+	}
+
+	if m.config.DryRun {
+		fmt.Printf("%s [DRY-RUN] Would remove %s from %s and the configuration\n",
+			style.BulletItem, describeFirewallRule(rule), m.firewallName())
+		return
+	}
+
+	// A rule removed by hand is still dropped from the configuration
+	if err := m.menuManager.RemoveFirewallRule(rule); err != nil {
+		fmt.Printf("\n%s %v\n", style.Colored(style.Yellow, style.SymWarning), err)
+	}
+
+	m.config.FirewallRules = append(m.config.FirewallRules[:num-1], m.config.FirewallRules[num:]...)
+	if err := config.SaveConfig(m.config, "hardn.yml"); err != nil {
+		fmt.Printf("\n%s Failed to save configuration: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		return
+	}
+
+	fmt.Printf("\n%s Rule removed\n", style.Colored(style.Green, style.SymCheckMark))
+}
+
+// readRuleChoice asks for one of choices until a valid one is entered;
+// the first choice is the default
+func readRuleChoice(label string, choices []string) string {
+	for {
+		fmt.Printf("%s %s (%s) [%s]: ", style.BulletItem, label, strings.Join(choices, ", "), choices[0])
+		value := strings.ToLower(ReadInput())
+		if value == "" {
+			return choices[0]
+		}
+		for _, choice := range choices {
+			if value == choice {
+				return choice
+			}
+		}
+		fmt.Printf("  %s Choose one of: %s\n", style.Colored(style.Red, style.SymCrossMark), strings.Join(choices, ", "))
+	}
+}
+
+// readRuleField asks for a value until apply accepts it
+func readRuleField(label string, apply func(value string) error) {
+	for {
+		fmt.Printf("%s %s: ", style.BulletItem, label)
+		err := apply(ReadInput())
+		if err == nil {
+			return
+		}
+		fmt.Printf("  %s %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+	}
+}
+
+// describeFirewallRule returns a rule as one line, e.g.
+// "allow 6000-6007/udp from 10.0.0.0/8 (mosh)"
+func describeFirewallRule(rule model.FirewallRule) string {
+	text := rule.Action + " " + ruleTarget(rule)
+	if rule.SourceIP != "" {
+		text += " from " + rule.SourceIP
+	}
+	if rule.Description != "" {
+		text += " (" + rule.Description + ")"
+	}
+	return text
+}
+
+// ruleTarget returns the ports of a rule with their protocol
+func ruleTarget(rule model.FirewallRule) string {
+	return rule.Ports("-") + "/" + rule.Protocol
+}
+
+// sameFirewallRule reports whether two rules match the same traffic
+func sameFirewallRule(a, b model.FirewallRule) bool {
+	a.Description, b.Description = "", ""
+	return a == b
+}
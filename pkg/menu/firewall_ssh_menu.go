@@ -0,0 +1,283 @@
+// pkg/menu/firewall_ssh_menu.go
+package menu
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// Random knock sequences pick their ports from this range,
+// above the well-known and commonly scanned ports
+const (
+	knockPortMin = 10000
+	knockPortMax = 60000
+)
+
+// manageSSHAccess handles the SSH access submenu: rate limiting the SSH
+// rule, and port knocking with knockd
+func (m *FirewallMenu) manageSSHAccess() {
+	defer enterScreen("SSH Access")()
+
+	fmt.Println()
+	fmt.Println(style.Bolded("SSH Access:", style.Blue))
+
+	rateLimit := "off"
+	if m.config.SshRateLimit {
+		rateLimit = "on"
+	}
+	fmt.Printf("%s Rate limiting: %s\n", style.BulletItem, rateLimit)
+
+	knocking := "off"
+	if m.config.PortKnocking.Enabled {
+		knocking = "on, sequence " + knockSequence(m.config.PortKnocking.Sequence)
+	}
+	fmt.Printf("%s Port knocking: %s\n", style.BulletItem, knocking)
+
+	if access, err := m.menuManager.GetSSHAccess(m.config.SshPort); err == nil {
+		live := "open to every address"
+		switch {
+		case access.PortKnocking:
+			live = "closed until the knock sequence"
+		case access.RateLimited:
+			live = "rate-limited"
+		}
+		fmt.Printf("%s Firewall now: port %d/tcp %s\n", style.BulletItem, m.config.SshPort, live)
+	}
+
+	rateLimitTitle := "Enable rate limiting"
+	if m.config.SshRateLimit {
+		rateLimitTitle = "Disable rate limiting"
+	}
+	knockingTitle := "Enable port knocking"
+	if m.config.PortKnocking.Enabled {
+		knockingTitle = "Disable port knocking"
+	}
+
+	menuOptions := []style.MenuOption{
+		{Number: 1, Title: rateLimitTitle, Description: "Limit new SSH connections from one address"},
+		{Number: 2, Title: knockingTitle, Description: "Open SSH only to addresses that knock with knockd"},
+	}
+
+	menu := style.NewMenu("Select an option", menuOptions)
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return to firewall menu",
+		Description: "",
+	})
+	menu.Print()
+
+	choice := ReadMenuInput()
+
+	// Handle 'q' as a special exit case
+	if choice == "q" {
+		return
+	}
+
+	switch choice {
+	case "1":
+		m.toggleSSHRateLimit()
+		m.manageSSHAccess()
+		return
+
+	case "2":
+		if m.config.PortKnocking.Enabled {
+			m.disablePortKnocking()
+		} else {
+			m.enablePortKnocking()
+		}
+		m.manageSSHAccess()
+		return
+
+	case "0":
+		return
+
+	default:
+		fmt.Printf("\n%s Invalid option. Please try again.\n",
+			style.Colored(style.Red, style.SymCrossMark))
+
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		m.manageSSHAccess()
+		return
+	}
+}
+
+// toggleSSHRateLimit switches the SSH rule between allow and limit
+func (m *FirewallMenu) toggleSSHRateLimit() {
+	enable := !m.config.SshRateLimit
+	if enable {
+		fmt.Printf("\n%s New SSH connections from an address are limited; %s\n", style.BulletItem,
+			m.rateLimitDescription())
+	}
+
+	m.config.SshRateLimit = enable
+	if !m.applySSHAccess() {
+		m.config.SshRateLimit = !enable
+	}
+
+	fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+	ReadKey()
+}
+
+// enablePortKnocking asks for a knock sequence, or makes one up, and
+// closes the SSH port behind knockd
+func (m *FirewallMenu) enablePortKnocking() {
+	fmt.Println()
+	fmt.Println(style.Bolded("Enable Port Knocking:", style.Blue))
+
+	knocking := m.config.PortKnocking
+	suggested := knocking.Sequence
+	if len(suggested) == 0 {
+		suggested = randomKnockSequence(m.config.SshPort)
+	}
+
+	for {
+		fmt.Printf("%s Knock sequence [%s]: ", style.BulletItem, knockSequence(suggested))
+		input := ReadInput()
+		knocking.Sequence = suggested
+		if input != "" {
+			sequence, err := parseKnockSequence(input)
+			if err != nil {
+				fmt.Printf("  %s %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+				continue
+			}
+			knocking.Sequence = sequence
+		}
+		policy := m.config.SSHFirewallPolicy()
+		err := m.menuManager.ValidatePortKnocking(policy.Port, knocking.Policy())
+		if err == nil {
+			break
+		}
+		fmt.Printf("  %s %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+	}
+
+	fmt.Printf("\n%s New SSH connections are refused until an address knocks on TCP ports %s\n",
+		style.Colored(style.Yellow, style.SymWarning), knockSequence(knocking.Sequence))
+	fmt.Printf("%s Keep this session open and test with: knock -d 500 HOST %s && ssh -p %d HOST\n",
+		style.BulletItem, strings.ReplaceAll(knockSequence(knocking.Sequence), ",", " "), m.config.SshPort)
+	fmt.Printf("%s Enable port knocking? (y/n): ", style.BulletItem)
+	confirm := strings.ToLower(ReadInput())
+	if confirm != "y" && confirm != "yes" {
+		fmt.Println("\nPort knocking left off.")
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		return
+	}
+
+	previous := m.config.PortKnocking
+	knocking.Enabled = true
+	m.config.PortKnocking = knocking
+	if !m.applySSHAccess() {
+		m.config.PortKnocking = previous
+	}
+
+	fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+	ReadKey()
+}
+
+// disablePortKnocking opens the SSH port again and stops knockd
+func (m *FirewallMenu) disablePortKnocking() {
+	fmt.Printf("\n%s Disable port knocking and open SSH to every address? (y/n): ", style.BulletItem)
+	confirm := strings.ToLower(ReadInput())
+	if confirm == "y" || confirm == "yes" {
+		m.config.PortKnocking.Enabled = false
+		if !m.applySSHAccess() {
+			m.config.PortKnocking.Enabled = true
+		} else if !m.config.DryRun {
+			// The firewall has the SSH rule again, so knockd can go
+			if err := m.menuManager.ConfigurePortKnocking(m.config.SSHFirewallPolicy()); err != nil {
+				fmt.Printf("%s Failed to stop knockd: %v\n", style.Colored(style.Yellow, style.SymWarning), err)
+			}
+		}
+	}
+
+	fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+	ReadKey()
+}
+
+// applySSHAccess configures the firewall again with the changed SSH
+// settings and saves them. It reports whether the change was applied.
+func (m *FirewallMenu) applySSHAccess() bool {
+	policy := m.config.SSHFirewallPolicy()
+	rule, hasRule := policy.Rule()
+
+	if m.config.DryRun {
+		if hasRule {
+			fmt.Printf("%s [DRY-RUN] Would reset %s with SSH rule %s\n", style.BulletItem, m.firewallName(), rule.Spec())
+		} else {
+			fmt.Printf("%s [DRY-RUN] Would start knockd and reset %s without an SSH rule\n", style.BulletItem, m.firewallName())
+		}
+		fmt.Printf("%s [DRY-RUN] Would save the change to the configuration\n", style.BulletItem)
+		return false
+	}
+
+	err := m.menuManager.ConfigureSecureFirewall(policy, m.config.IPv6.Policy(), m.config.UfwAllowedPorts,
+		m.config.CustomFirewallRules(), m.config.FirewallProfiles())
+	if err != nil {
+		fmt.Printf("\n%s Failed to configure the firewall: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		return false
+	}
+
+	if err := config.SaveConfig(m.config, "hardn.yml"); err != nil {
+		fmt.Printf("\n%s Firewall configured, but saving the configuration failed: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		return true
+	}
+
+	if hasRule {
+		fmt.Printf("\n%s Firewall configured with SSH rule %s\n", style.Colored(style.Green, style.SymCheckMark), rule.Spec())
+	} else {
+		fmt.Printf("\n%s Firewall configured; knockd opens SSH port %d\n", style.Colored(style.Green, style.SymCheckMark), policy.Port)
+	}
+	return true
+}
+
+// rateLimitDescription says how the firewall limits SSH
+func (m *FirewallMenu) rateLimitDescription() string {
+	if model.IsRHELFamily(m.osInfo.OsType) {
+		return "firewalld accepts 10 new connections a minute from all addresses together"
+	}
+	return "ufw refuses an address opening 6 connections within 30 seconds"
+}
+
+// randomKnockSequence picks three distinct ports other than the SSH port
+func randomKnockSequence(sshPort int) []int {
+	var sequence []int
+	for len(sequence) < 3 {
+		port := knockPortMin + rand.IntN(knockPortMax-knockPortMin)
+		if port != sshPort && !slices.Contains(sequence, port) {
+			sequence = append(sequence, port)
+		}
+	}
+	return sequence
+}
+
+// parseKnockSequence parses ports separated by commas or spaces
+func parseKnockSequence(input string) ([]int, error) {
+	var sequence []int
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		port, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid knock port %q", field)
+		}
+		sequence = append(sequence, port)
+	}
+	return sequence, nil
+}
+
+// knockSequence formats a knock sequence as knockd writes it
+func knockSequence(sequence []int) string {
+	ports := make([]string, len(sequence))
+	for i, port := range sequence {
+		ports[i] = strconv.Itoa(port)
+	}
+	return strings.Join(ports, ",")
+}
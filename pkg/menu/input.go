@@ -7,6 +7,8 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+
+	"github.com/abbott/hardn/pkg/session"
 )
 
 // Shared reader for all menus
@@ -14,12 +16,20 @@ var reader = bufio.NewReader(os.Stdin)
 
 // ReadInput reads a line of input from the user
 func ReadInput() string {
+	return sessionInput(session.InputLine, readLine)
+}
+
+func readLine() string {
 	input, _ := reader.ReadString('\n')
 	return strings.TrimSpace(input)
 }
 
 // ReadKey reads a single key pressed by the user
 func ReadKey() string {
+	return sessionInput(session.InputKey, readKey)
+}
+
+func readKey() string {
 	// Configure terminal for raw input
 	if err := exec.Command("stty", "-F", "/dev/tty", "cbreak", "min", "1").Run(); err != nil {
 		fmt.Printf("Warning: Failed to configure terminal: %v\n", err)
@@ -57,6 +67,10 @@ func ReadKey() string {
 // ReadMenuInput reads input for a menu, supporting both immediate 'q' exit and
 // normal buffered input with backspace support for other entries
 func ReadMenuInput() string {
+	return sessionInput(session.InputMenu, readMenuInput)
+}
+
+func readMenuInput() string {
 	// fmt.Print("> ")
 
 	var buffer strings.Builder
@@ -7,6 +7,7 @@ import (
 
 	"github.com/abbott/hardn/pkg/application"
 	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
 	"github.com/abbott/hardn/pkg/interfaces"
 	"github.com/abbott/hardn/pkg/osdetect"
 	"github.com/abbott/hardn/pkg/style"
@@ -35,45 +36,26 @@ func NewLinuxPackagesMenu(
 
 // Show displays the Linux packages menu and handles user input
 func (m *LinuxPackagesMenu) Show() {
-	utils.PrintHeader()
-	fmt.Println(style.Bolded("Linux Packages Installation", style.Blue))
+	defer enterScreen("Linux Packages")()
 
 	// Display current packages
 	fmt.Println()
 	fmt.Println(style.Bolded("Configured Packages:", style.Blue))
 
-	if m.osInfo.OsType == "alpine" {
-		// Alpine packages
-		if len(m.config.AlpineCorePackages) > 0 {
-			fmt.Printf("%sCore packages: %s\n", style.BulletItem,
-				style.Colored(style.Cyan, strings.Join(m.config.AlpineCorePackages, ", ")))
-		}
-
-		if len(m.config.AlpineDmzPackages) > 0 {
-			fmt.Printf("%sDMZ packages: %s\n", style.BulletItem,
-				style.Colored(style.Cyan, strings.Join(m.config.AlpineDmzPackages, ", ")))
-		}
-
-		if len(m.config.AlpineLabPackages) > 0 {
-			fmt.Printf("%sLab packages: %s\n", style.BulletItem,
-				style.Colored(style.Cyan, strings.Join(m.config.AlpineLabPackages, ", ")))
-		}
-	} else {
-		// Debian/Ubuntu packages
-		if len(m.config.LinuxCorePackages) > 0 {
-			fmt.Printf("%sCore packages: %s\n", style.BulletItem,
-				style.Colored(style.Cyan, strings.Join(m.config.LinuxCorePackages, ", ")))
-		}
+	corePackages, dmzPackages, labPackages, distro := m.configuredPackages()
+	if len(corePackages) > 0 {
+		fmt.Printf("%sCore packages: %s\n", style.BulletItem,
+			style.Colored(style.Cyan, strings.Join(corePackages, ", ")))
+	}
 
-		if len(m.config.LinuxDmzPackages) > 0 {
-			fmt.Printf("%sDMZ packages: %s\n", style.BulletItem,
-				style.Colored(style.Cyan, strings.Join(m.config.LinuxDmzPackages, ", ")))
-		}
+	if len(dmzPackages) > 0 {
+		fmt.Printf("%sDMZ packages: %s\n", style.BulletItem,
+			style.Colored(style.Cyan, strings.Join(dmzPackages, ", ")))
+	}
 
-		if len(m.config.LinuxLabPackages) > 0 {
-			fmt.Printf("%sLab packages: %s\n", style.BulletItem,
-				style.Colored(style.Cyan, strings.Join(m.config.LinuxLabPackages, ", ")))
-		}
+	if len(labPackages) > 0 {
+		fmt.Printf("%sLab packages: %s\n", style.BulletItem,
+			style.Colored(style.Cyan, strings.Join(labPackages, ", ")))
 	}
 
 	// Check subnet status for package selection
@@ -120,106 +102,27 @@ func (m *LinuxPackagesMenu) Show() {
 	case "1":
 		// Install core packages
 		fmt.Println("\nInstalling Core Linux packages...")
-
-		if m.osInfo.OsType == "alpine" {
-			if len(m.config.AlpineCorePackages) > 0 {
-				m.installPackages(m.config.AlpineCorePackages, "Core")
-			} else {
-				fmt.Printf("\n%s No Alpine Core packages configured\n",
-					style.Colored(style.Yellow, style.SymWarning))
-			}
-		} else {
-			if len(m.config.LinuxCorePackages) > 0 {
-				m.installPackages(m.config.LinuxCorePackages, "Core")
-			} else {
-				fmt.Printf("\n%s No Linux Core packages configured\n",
-					style.Colored(style.Yellow, style.SymWarning))
-			}
-		}
+		m.installConfigured(corePackages, "Core", distro)
 
 	case "2":
 		// Install DMZ packages
 		fmt.Println("\nInstalling DMZ Linux packages...")
-
-		if m.osInfo.OsType == "alpine" {
-			if len(m.config.AlpineDmzPackages) > 0 {
-				m.installPackages(m.config.AlpineDmzPackages, "DMZ")
-			} else {
-				fmt.Printf("\n%s No Alpine DMZ packages configured\n",
-					style.Colored(style.Yellow, style.SymWarning))
-			}
-		} else {
-			if len(m.config.LinuxDmzPackages) > 0 {
-				m.installPackages(m.config.LinuxDmzPackages, "DMZ")
-			} else {
-				fmt.Printf("\n%s No Linux DMZ packages configured\n",
-					style.Colored(style.Yellow, style.SymWarning))
-			}
-		}
+		m.installConfigured(dmzPackages, "DMZ", distro)
 
 	case "3":
 		// Install Lab packages
 		fmt.Println("\nInstalling Lab Linux packages...")
-
-		if m.osInfo.OsType == "alpine" {
-			if len(m.config.AlpineLabPackages) > 0 {
-				m.installPackages(m.config.AlpineLabPackages, "Lab")
-			} else {
-				fmt.Printf("\n%s No Alpine Lab packages configured\n",
-					style.Colored(style.Yellow, style.SymWarning))
-			}
-		} else {
-			if len(m.config.LinuxLabPackages) > 0 {
-				m.installPackages(m.config.LinuxLabPackages, "Lab")
-			} else {
-				fmt.Printf("\n%s No Linux Lab packages configured\n",
-					style.Colored(style.Yellow, style.SymWarning))
-			}
-		}
+		m.installConfigured(labPackages, "Lab", distro)
 
 	case "4":
 		// Install all packages
 		fmt.Println("\nInstalling All Linux packages...")
 		fmt.Println(style.Dimmed("This may take some time. Please wait..."))
 
-		if m.osInfo.OsType == "alpine" {
-			// Install Alpine packages
-			if len(m.config.AlpineCorePackages) > 0 {
-				m.installPackages(m.config.AlpineCorePackages, "Core")
-			}
-
-			if isDmz {
-				if len(m.config.AlpineDmzPackages) > 0 {
-					m.installPackages(m.config.AlpineDmzPackages, "DMZ")
-				}
-			} else {
-				if len(m.config.AlpineDmzPackages) > 0 {
-					m.installPackages(m.config.AlpineDmzPackages, "DMZ")
-				}
-
-				if len(m.config.AlpineLabPackages) > 0 {
-					m.installPackages(m.config.AlpineLabPackages, "Lab")
-				}
-			}
-		} else {
-			// Install Debian/Ubuntu packages
-			if len(m.config.LinuxCorePackages) > 0 {
-				m.installPackages(m.config.LinuxCorePackages, "Core")
-			}
-
-			if isDmz {
-				if len(m.config.LinuxDmzPackages) > 0 {
-					m.installPackages(m.config.LinuxDmzPackages, "DMZ")
-				}
-			} else {
-				if len(m.config.LinuxDmzPackages) > 0 {
-					m.installPackages(m.config.LinuxDmzPackages, "DMZ")
-				}
-
-				if len(m.config.LinuxLabPackages) > 0 {
-					m.installPackages(m.config.LinuxLabPackages, "Lab")
-				}
-			}
+		m.installPackages(corePackages, "Core")
+		m.installPackages(dmzPackages, "DMZ")
+		if !isDmz {
+			m.installPackages(labPackages, "Lab")
 		}
 
 		fmt.Printf("\n%s All Linux packages installed successfully!\n",
@@ -237,6 +140,28 @@ func (m *LinuxPackagesMenu) Show() {
 	ReadKey()
 }
 
+// configuredPackages returns the core, DMZ and lab package lists for the
+// distribution, and the distribution's name for messages
+func (m *LinuxPackagesMenu) configuredPackages() (core, dmz, lab []string, distro string) {
+	switch {
+	case m.osInfo.OsType == "alpine":
+		return m.config.AlpineCorePackages, m.config.AlpineDmzPackages, m.config.AlpineLabPackages, "Alpine"
+	case model.IsRHELFamily(m.osInfo.OsType):
+		return m.config.RhelCorePackages, m.config.RhelDmzPackages, m.config.RhelLabPackages, "RHEL"
+	}
+	return m.config.LinuxCorePackages, m.config.LinuxDmzPackages, m.config.LinuxLabPackages, "Linux"
+}
+
+// installConfigured installs a package list, or warns that it is empty
+func (m *LinuxPackagesMenu) installConfigured(pkgs []string, pkgType, distro string) {
+	if len(pkgs) == 0 {
+		fmt.Printf("\n%s No %s %s packages configured\n",
+			style.Colored(style.Yellow, style.SymWarning), distro, pkgType)
+		return
+	}
+	m.installPackages(pkgs, pkgType)
+}
+
 // installPackages handles installing packages with nice formatting
 func (m *LinuxPackagesMenu) installPackages(pkgs []string, pkgType string) {
 	if len(pkgs) == 0 {
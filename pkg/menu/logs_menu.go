@@ -8,7 +8,6 @@ import (
 	"github.com/abbott/hardn/pkg/config"
 	"github.com/abbott/hardn/pkg/domain/model"
 	"github.com/abbott/hardn/pkg/style"
-	"github.com/abbott/hardn/pkg/utils"
 )
 
 // LogsMenu handles viewing log information
@@ -30,8 +29,7 @@ func NewLogsMenu(
 
 // Show displays the logs menu and handles user input
 func (m *LogsMenu) Show() {
-	utils.PrintHeader()
-	fmt.Println(style.Bolded("View Logs", style.Blue))
+	defer enterScreen("Logs")()
 
 	// Get log configuration
 	logConfig, err := m.menuManager.GetLogConfig()
@@ -40,7 +38,7 @@ func (m *LogsMenu) Show() {
 			style.Colored(style.Red, style.SymCrossMark), err)
 		// Create a domain model LogsConfig from the application config
 		logConfig = &model.LogsConfig{
-			LogFilePath: m.config.LogFile,
+			LogFilePath: m.config.LoggingOptions().Path,
 		}
 	}
 
@@ -59,6 +57,18 @@ func (m *LogsMenu) Show() {
 			style.Colored(style.Red, style.SymCrossMark), err)
 	}
 
-	fmt.Printf("\n%s Press any key to return to the main menu...", style.BulletItem)
-	ReadKey()
+	menuOptions := []style.MenuOption{
+		{Number: 1, Title: "Debug Logging", Description: "Log debug entries of chosen modules"},
+	}
+	menu := style.NewMenu("Select an option", menuOptions)
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return to main menu",
+		Description: "",
+	})
+	menu.Print()
+
+	if ReadMenuInput() == "1" {
+		NewDebugLoggingMenu().Show()
+	}
 }
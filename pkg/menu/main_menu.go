@@ -1,12 +1,20 @@
 package menu
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/abbott/hardn/pkg/application"
 	"github.com/abbott/hardn/pkg/config"
 	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/logging"
 	"github.com/abbott/hardn/pkg/osdetect"
 	"github.com/abbott/hardn/pkg/security"
 	"github.com/abbott/hardn/pkg/style"
@@ -23,11 +31,40 @@ type MainMenu struct {
 	// Version service for update checks
 	versionService *version.Service
 
-	// Update state fields
+	// Update state, written by the background check and read by the UI loop
+	updateMu      sync.Mutex
+	update        updateState
+	updateStarted bool
+	cancelUpdate  context.CancelFunc
+	updateAPIURL  string
+
+	// Background events waiting to be shown
+	notifications *Notifications
+
+	// Certificates already announced through a notification
+	notifiedCertificates map[string]bool
+
+	// Posture before this session, which the risk level's trend compares with
+	postureRecorded bool
+	postureBaseline *model.PostureSnapshot
+
+	// Confirms changes to sshd and the firewall after each choice
+	changeCheck func() error
+}
+
+// updateState is the result of the update check shown in the header
+type updateState struct {
+	checking        bool
 	updateAvailable bool
 	latestVersion   string
 	updateURL       string
 	installURL      string
+	binaryURL       string
+	offline         bool // skipped in offline mode without a cached result
+
+	// Verification of the latest release; one that failed is not offered
+	verification       string
+	verificationFailed bool
 
 	// Security update fields
 	securityUpdateAvailable bool
@@ -46,9 +83,74 @@ func NewMainMenu(
 		config:         config,
 		osInfo:         osInfo,
 		versionService: versionService,
+		notifications:  NewNotifications(),
+
+		notifiedCertificates: make(map[string]bool),
 	}
 }
 
+// Notify queues a background event for the next time the menu is drawn
+func (m *MainMenu) Notify(level, message string) {
+	m.notifications.Post(level, message)
+}
+
+// notifyExpiringCertificates announces each expiring certificate once per
+// session when certificate notifications are enabled
+func (m *MainMenu) notifyExpiringCertificates(status *security.SecurityStatus) {
+	if !m.config.CertificateMonitoring.Notify || status.Certificates == nil {
+		return
+	}
+
+	for _, cert := range status.Certificates.Expiring {
+		if m.notifiedCertificates[cert.Path] {
+			continue
+		}
+		m.notifiedCertificates[cert.Path] = true
+
+		if cert.Expired() {
+			m.Notify(NotifyWarning, fmt.Sprintf("Certificate %s has expired (%s)", cert.Subject, cert.Path))
+		} else {
+			m.Notify(NotifyWarning, fmt.Sprintf("Certificate %s expires in %d days (%s)",
+				cert.Subject, cert.DaysLeft, cert.Path))
+		}
+	}
+}
+
+// recordPosture adds the first status of the session to the posture
+// history, except in dry-run mode, and keeps the snapshot before it as the
+// baseline of the trend arrow
+func (m *MainMenu) recordPosture(status *security.SecurityStatus) {
+	historyManager := m.menuManager.GetPostureHistoryManager()
+	if m.postureRecorded || historyManager == nil {
+		return
+	}
+	m.postureRecorded = true
+
+	if m.config.DryRun {
+		m.postureBaseline, _ = historyManager.LastSnapshot()
+		return
+	}
+	// Without root the history can not be written, but it may be read
+	snapshot := security.BuildPostureSnapshot(status, time.Now(), model.PostureSourceMenu)
+	m.postureBaseline, _ = historyManager.RecordSnapshot(snapshot)
+}
+
+// postureTrend returns an arrow showing whether the posture improved or
+// regressed since the baseline, or "" without one
+func (m *MainMenu) postureTrend(status *security.SecurityStatus) string {
+	if m.postureBaseline == nil {
+		return ""
+	}
+	current := security.BuildPostureSnapshot(status, time.Now(), model.PostureSourceMenu)
+	switch current.Trend(*m.postureBaseline) {
+	case model.TrendImproved:
+		return style.Colored(style.Green, style.SymArrowUp)
+	case model.TrendRegressed:
+		return style.Colored(style.Red, style.SymArrowDown)
+	}
+	return style.Dimmed(style.SymArrowRight)
+}
+
 // refresh any configuration values that might have been
 // changed by sub-menus like RunAllMenu or DryRunMenu
 func (m *MainMenu) refreshConfig() {
@@ -61,39 +163,126 @@ const (
 	repoURL             = "https://github.com/abbott/hardn"
 	testVersionNumber   = "0.3.3"
 	testReleaseURL      = "https://api.github.com/repos/abbott/hardn/releases/latest"
-	testInstallScript   = "curl -sSL https://raw.githubusercontent.com/abbott/hardn/main/install.sh | sudo sh"
 	testSecurityMessage = "Critical security vulnerability fixed - CVE-2023-1234"
 )
 
-// Check for new version
+// Check for new version in the background; only the first call starts a check
 func (m *MainMenu) CheckForUpdates() {
 	if m.versionService == nil || m.versionService.CurrentVersion == "" {
 		return
 	}
 
+	m.updateMu.Lock()
+	if m.updateStarted {
+		m.updateMu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.updateStarted = true
+	m.update.checking = true
+	m.cancelUpdate = cancel
+	apiURL := m.updateAPIURL
+	m.updateMu.Unlock()
+
 	// Use Go outine to Avoid blocking display
 	go func() {
-		result := m.versionService.CheckForUpdates(&version.UpdateOptions{
-			Debug: os.Getenv("HARDN_DEBUG") != "",
+		defer cancel()
+
+		// Offline, only a cached result is shown and GitHub is not asked
+		result := m.versionService.CheckForUpdatesContext(ctx, &version.UpdateOptions{
+			Debug:    logging.ModuleDebugEnabled(logging.ModuleVersion),
+			CacheTTL: m.config.UpdateCheckTTL(),
+			Offline:  interfaces.Offline(),
+			APIURL:   apiURL,
 		})
 
-		if result.Error != nil {
+		m.updateMu.Lock()
+		defer m.updateMu.Unlock()
+		m.update.checking = false
+		m.update.offline = errors.Is(result.Error, version.ErrOffline)
+
+		// Nobody is left to show a result once the menu has exited
+		if result.Error != nil || ctx.Err() != nil {
 			return
 		}
 
-		m.applyUpdateResult(result)
+		m.applyUpdateResultLocked(result)
+
+		// The header may already be drawn; queue the news for the next render
+		if result.UpdateAvailable && result.Verification.Failed() {
+			m.Notify(NotifyWarning, fmt.Sprintf("hardn v%s failed verification: %s", result.LatestVersion, result.Verification.Detail))
+		} else if result.SecurityUpdateAvailable {
+			m.Notify(NotifyWarning, fmt.Sprintf("Security update v%s available", result.LatestVersion))
+		} else if result.UpdateAvailable {
+			m.Notify(NotifyInfo, fmt.Sprintf("hardn v%s available", result.LatestVersion))
+		}
 	}()
 }
 
+// SetUpdateAPIURL points the background update check at another release
+// source; empty uses GitHub
+func (m *MainMenu) SetUpdateAPIURL(url string) {
+	m.updateMu.Lock()
+	defer m.updateMu.Unlock()
+	m.updateAPIURL = url
+}
+
+// StopUpdateCheck cancels a background update check that is still running
+func (m *MainMenu) StopUpdateCheck() {
+	m.updateMu.Lock()
+	defer m.updateMu.Unlock()
+
+	if m.cancelUpdate != nil {
+		m.cancelUpdate()
+		m.cancelUpdate = nil
+	}
+	m.update.checking = false
+}
+
+// updateSnapshot returns a copy of the update state for rendering
+func (m *MainMenu) updateSnapshot() updateState {
+	m.updateMu.Lock()
+	defer m.updateMu.Unlock()
+	return m.update
+}
+
+// UpdateStatus reports whether the background update check is still
+// running and the newer version it found, if any
+func (m *MainMenu) UpdateStatus() (checking bool, latestVersion string) {
+	update := m.updateSnapshot()
+	if !update.updateAvailable {
+		return update.checking, ""
+	}
+	return update.checking, update.latestVersion
+}
+
 // Apply update check result
 func (m *MainMenu) applyUpdateResult(result version.CheckResult) {
+	m.updateMu.Lock()
+	defer m.updateMu.Unlock()
+
+	// A forced test result replaces any real check
+	m.updateStarted = true
+	m.applyUpdateResultLocked(result)
+}
+
+// applyUpdateResultLocked records result; the caller holds updateMu
+func (m *MainMenu) applyUpdateResultLocked(result version.CheckResult) {
+	// A release that fails verification is not offered
+	if result.UpdateAvailable && result.Verification.Failed() {
+		m.update.verificationFailed = true
+		m.update.latestVersion = result.LatestVersion
+		return
+	}
 	if result.UpdateAvailable {
-		m.updateAvailable = true
-		m.latestVersion = result.LatestVersion
-		m.updateURL = result.ReleaseURL
-		m.installURL = result.InstallURL
-		m.securityUpdateAvailable = result.SecurityUpdateAvailable
-		m.securityUpdateDetails = result.SecurityUpdateDetails
+		m.update.updateAvailable = true
+		m.update.latestVersion = result.LatestVersion
+		m.update.updateURL = result.ReleaseURL
+		m.update.installURL = result.InstallURL
+		m.update.binaryURL = result.Assets.BinaryURL
+		m.update.securityUpdateAvailable = result.SecurityUpdateAvailable
+		m.update.securityUpdateDetails = result.SecurityUpdateDetails
+		m.update.verification = result.Verification.Label()
 	}
 }
 
@@ -113,6 +302,12 @@ func (m *MainMenu) CheckForUpdatesWithEnvVars() {
 	}
 }
 
+// SetChangeCheck sets the check run after each menu choice, which keeps
+// changes to sshd and the firewall only once SSH is confirmed to work
+func (m *MainMenu) SetChangeCheck(check func() error) {
+	m.changeCheck = check
+}
+
 // SetTestUpdateAvailable sets test update information
 func (m *MainMenu) SetTestUpdateAvailable(testVersion string) {
 	if m.versionService == nil {
@@ -152,20 +347,20 @@ func (m *MainMenu) SetTestSecurityUpdate(details string) {
 }
 
 // displaySecurityStatus displays the security status with appropriate borders
-func (m *MainMenu) displaySecurityStatus(securityStatus *security.SecurityStatus, formatter *style.StatusFormatter) {
+func (m *MainMenu) displaySecurityStatus(securityStatus *security.SecurityStatus, formatter *style.StatusFormatter, update updateState) {
 	// If security update is available, display special alert and return
-	if m.securityUpdateAvailable && m.versionService != nil && m.versionService.CurrentVersion != "" {
-		m.displaySecurityUpdateAlert(formatter)
+	if update.securityUpdateAvailable && m.versionService != nil && m.versionService.CurrentVersion != "" {
+		m.displaySecurityUpdateAlert(formatter, update)
 		return
 	}
 
 	// Otherwise display the normal security status
-	m.displayNormalSecurityStatus(securityStatus, formatter)
+	m.displayNormalSecurityStatus(securityStatus, formatter, update)
 }
 
 // displaySecurityUpdateAlert displays the security update alert
 // Preserves exact formatting and messaging for security updates
-func (m *MainMenu) displaySecurityUpdateAlert(formatter *style.StatusFormatter) {
+func (m *MainMenu) displaySecurityUpdateAlert(formatter *style.StatusFormatter, update updateState) {
 	// Format hardn version header
 	hardnBold := style.Bold + "hardn" + style.Reset
 	hardnPad := " " + hardnBold + " "
@@ -177,7 +372,7 @@ func (m *MainMenu) displaySecurityUpdateAlert(formatter *style.StatusFormatter)
 	hardnVersion := hardnLabel + currentVersionBg
 
 	// Format security related content
-	latestVersion := "v" + m.latestVersion
+	latestVersion := "v" + update.latestVersion
 	securityHeader := style.Colored(style.BgDarkRed, " Update Binary ")
 
 	// Display the alert - exact formatting preserved from original
@@ -187,32 +382,41 @@ func (m *MainMenu) displaySecurityUpdateAlert(formatter *style.StatusFormatter)
 
 	fmt.Println("  " + securityHeader)
 	fmt.Println()
-	fmt.Println("  " + m.securityUpdateDetails)
+	fmt.Println("  " + update.securityUpdateDetails)
 
-	fmt.Printf("  %s\n", style.Colored(style.Royal, m.updateURL))
+	fmt.Printf("  %s\n", style.Colored(style.Royal, update.updateURL))
 	fmt.Println()
 
 	infoFormatter := style.NewStatusFormatter([]string{
 		"Build Date",
 		"Git Commit",
+		"Verified",
 	}, 2) // 2 spaces buffer
 
 	// Add "  " prefix to each line for consistent indentation
 	fmt.Println("    " + infoFormatter.FormatBullet("Version", latestVersion, "", "no-indent"))
 	fmt.Println("    " + infoFormatter.FormatBullet("Build Date", m.versionService.BuildDate, "", "no-indent"))
 	fmt.Println("    " + infoFormatter.FormatBullet("Git Commit", m.versionService.GitCommit, "", "no-indent"))
+	if update.verification != "" {
+		fmt.Println("    " + infoFormatter.FormatBullet("Verified", update.verification, "", "no-indent"))
+	}
 	fmt.Println()
 	fmt.Println(style.Bolded("  Installer Script:"))
-	fmt.Println(style.Colored(style.Royal, "  "+m.installURL))
+	fmt.Println(style.Colored(style.Royal, "  "+update.installURL))
+	if update.binaryURL != "" {
+		fmt.Println()
+		fmt.Println(style.Bolded(fmt.Sprintf("  Binary (%s/%s):", runtime.GOOS, runtime.GOARCH)))
+		fmt.Println(style.Colored(style.Royal, "  "+update.binaryURL))
+	}
 	fmt.Println()
 	fmt.Println()
 	fmt.Print(style.Dimmed("Press any key to exit... "))
 }
 
 // displayNormalSecurityStatus displays the normal security status in a box
-func (m *MainMenu) displayNormalSecurityStatus(securityStatus *security.SecurityStatus, formatter *style.StatusFormatter) {
+func (m *MainMenu) displayNormalSecurityStatus(securityStatus *security.SecurityStatus, formatter *style.StatusFormatter, update updateState) {
 	// Format hardn version line with update info if available
-	hardnLine := m.formatHardnVersionLine(formatter)
+	hardnLine := m.formatHardnVersionLine(formatter, update)
 
 	// Display version information
 	fmt.Println(hardnLine)
@@ -220,7 +424,7 @@ func (m *MainMenu) displayNormalSecurityStatus(securityStatus *security.Security
 	repo := style.Dimmed(repoURL)
 
 	// When update is available, also display the repo URL on a new line
-	if m.updateAvailable {
+	if update.updateAvailable {
 		repoLine := formatter.FormatLine("", "", "", repo, style.Gray08, "", "no-indent")
 		fmt.Println(repoLine)
 	}
@@ -266,6 +470,9 @@ func (m *MainMenu) displayNormalSecurityStatus(securityStatus *security.Security
 			riskLevel, riskDescription, riskColor := security.GetSecurityRiskLevel(securityStatus)
 			boldRiskLabel := style.Bold + "Risk Level" + style.Reset
 			riskDescription = style.SymApprox + " " + riskDescription
+			if trend := m.postureTrend(securityStatus); trend != "" {
+				riskDescription += " " + trend
+			}
 			riskLine := formatter.FormatLine(style.SymDotTri, riskColor, boldRiskLabel, riskLevel, riskColor, riskDescription, "dark")
 
 			// Use indented print function for risk level as well
@@ -304,14 +511,26 @@ func (m *MainMenu) formatOSTitle() string {
 	case "alpine":
 		// For Alpine, format as "Alpine Linux X.Y.Z" - but "Linux" should not be bold
 		return fmt.Sprintf("%s Linux %s", dimOsType, regularVersion)
+	case "rocky":
+		// For Rocky, format as "Rocky Linux X.Y" like Alpine
+		return fmt.Sprintf("%s Linux %s", dimOsType, regularVersion)
+	case "almalinux":
+		// For AlmaLinux, format as "AlmaLinux X.Y" rather than the os-release ID
+		return fmt.Sprintf("%s %s", style.Dimmed(style.Bolded("AlmaLinux"), style.Gray15), regularVersion)
+	case "rhel":
+		return fmt.Sprintf("%s %s", style.Dimmed(style.Bolded("RHEL"), style.Gray15), regularVersion)
 	default:
 		// Generic format for other OS types
-		return fmt.Sprintf("%s %s", dimOsType, regularVersion)
+		title := fmt.Sprintf("%s %s", dimOsType, regularVersion)
+		if m.osInfo.Degraded {
+			title += " " + style.Colored(style.Yellow, "(unsupported: degraded mode)")
+		}
+		return title
 	}
 }
 
 // formatHardnVersionLine formats the hardn version line with update information if available
-func (m *MainMenu) formatHardnVersionLine(formatter *style.StatusFormatter) string {
+func (m *MainMenu) formatHardnVersionLine(formatter *style.StatusFormatter, update updateState) string {
 	// Create common elements
 	hardnBold := style.Bold + "hardn" + style.Reset
 	// hardnPad := " " + hardnBold + " "
@@ -324,10 +543,13 @@ func (m *MainMenu) formatHardnVersionLine(formatter *style.StatusFormatter) stri
 	repo := style.Dimmed(repoURL)
 
 	// Format differently based on update availability
-	if m.updateAvailable {
-		latestVersion := "v" + m.latestVersion
+	if update.updateAvailable {
+		latestVersion := "v" + update.latestVersion
 		message := latestVersion + " " + "available"
 		notification := style.Colored(style.Royal, message)
+		if update.verification != "" {
+			notification += style.Dimmed(" (" + update.verification + ")")
+		}
 		return formatter.FormatLine(
 			"",
 			"",
@@ -337,6 +559,36 @@ func (m *MainMenu) formatHardnVersionLine(formatter *style.StatusFormatter) stri
 			"",
 			"no-indent",
 		)
+	} else if update.checking {
+		return formatter.FormatLine(
+			"",
+			"",
+			hardnVersion,
+			style.Dimmed("checking for updates"+style.SymEllipsis),
+			style.Gray10,
+			"",
+			"no-indent",
+		)
+	} else if update.verificationFailed {
+		return formatter.FormatLine(
+			"",
+			"",
+			hardnVersion,
+			style.Colored(style.Red, "v"+update.latestVersion+" failed verification"),
+			style.Red,
+			"",
+			"no-indent",
+		)
+	} else if update.offline {
+		return formatter.FormatLine(
+			"",
+			"",
+			hardnVersion,
+			style.Dimmed("offline, update check skipped"),
+			style.Gray10,
+			"",
+			"no-indent",
+		)
 	} else {
 		return formatter.FormatLine(
 			"",
@@ -367,6 +619,10 @@ func (m *MainMenu) formatHostInfoLines(hostInfo *model.HostInfo, formatter *styl
 
 	// Format uptime line
 	uptime := m.menuManager.FormatUptime(hostInfo.Uptime)
+	// Show what the host runs on next to its uptime
+	if m.osInfo != nil && m.osInfo.Environment.Kind != "" {
+		uptime += " " + style.SymEnDash + " " + m.osInfo.Environment.String()
+	}
 	uptime = style.Dimmed(uptime)
 	uptimeLine := formatter.FormatLine("", "", "", uptime, style.Gray10, "", "no-indent")
 
@@ -380,8 +636,9 @@ func (m *MainMenu) ShowMainMenu(currentVersion, buildDate, gitCommit string) {
 		m.versionService = version.NewService(currentVersion, buildDate, gitCommit)
 	}
 
-	// Check for updates when the menu starts
+	// Check for updates when the menu starts, abandoning the check on exit
 	m.CheckForUpdatesWithEnvVars()
+	defer m.StopUpdateCheck()
 
 	// Main menu loop
 	for {
@@ -392,6 +649,10 @@ func (m *MainMenu) ShowMainMenu(currentVersion, buildDate, gitCommit string) {
 
 		// Get security status
 		securityStatus, err := security.CheckSecurityStatus(m.config, m.osInfo)
+		if err == nil {
+			m.notifyExpiringCertificates(securityStatus)
+			m.recordPosture(securityStatus)
+		}
 
 		// Create formatter for security status
 		formatter := style.NewStatusFormatter([]string{
@@ -401,27 +662,45 @@ func (m *MainMenu) ShowMainMenu(currentVersion, buildDate, gitCommit string) {
 			"Risk Level",
 			"SSH Root Login",
 			"Firewall",
+			"IPv6",
 			"Users",
 			"SSH Port",
+			"SSH Access",
+			"SSH Profile",
 			"SSH Auth",
-			"AppArmor",
+			"MAC",
 			"Auto Updates",
+			"Directory",
+			"Password Policy",
+			"Modules",
+			"Boot",
+			"Time Sync",
+			"Log Shipping",
+			"Restarts",
+			"Certificates",
+			"Packages",
 		}, 2) // 2 spaces buffer
 
+		// Take one view of the update state for this render
+		update := m.updateSnapshot()
+
 		// Display security status if available
 		if err == nil {
-			m.displaySecurityStatus(securityStatus, formatter)
+			m.displaySecurityStatus(securityStatus, formatter, update)
 		} else {
 			fmt.Println()
 		}
 
 		// If security update is available, wait for key press and exit
-		if m.securityUpdateAvailable {
+		if update.securityUpdateAvailable {
 			ReadKey()
 			utils.ClearScreen()
 			return
 		}
 
+		// Show events that arrived since the last render
+		m.notifications.Print(m.notifications.Drain())
+
 		// Create menu and display
 		menu := m.createMainMenu()
 		menu.Print()
@@ -434,8 +713,18 @@ func (m *MainMenu) ShowMainMenu(currentVersion, buildDate, gitCommit string) {
 			return
 		}
 
+		// Disabled modules explain why instead of opening
+		if rejectDisabledOption(menu, choice) {
+			continue
+		}
+
 		// Process the menu choice
 		exitRequested := m.handleMenuChoice(choice)
+		if m.changeCheck != nil {
+			if err := m.changeCheck(); err != nil {
+				m.Notify(NotifyWarning, err.Error())
+			}
+		}
 		if exitRequested {
 			utils.ClearScreen()
 			return
@@ -450,7 +739,7 @@ func (m *MainMenu) createMainMenu() *style.Menu {
 		{Number: 1, Title: "User Management", Description: "Create, Configure (sudo, SSH keys)"},
 		{Number: 2, Title: "SSH Login", Description: "Toggle SSH root access"},
 		{Number: 3, Title: "DNS", Description: "Configure Nameservers"},
-		{Number: 4, Title: "Firewall", Description: "Configure UFW rules"},
+		{Number: 4, Title: "Firewall", Description: "Configure UFW or firewalld rules"},
 		{Number: 5, Title: "Backup", Description: "Configure Hardn backup settings"},
 		{Number: 6, Title: "Dry-Run", Description: "Simulate changes"},
 		{Number: 7, Title: "Run All", Description: "Execute hardening operations"},
@@ -459,6 +748,49 @@ func (m *MainMenu) createMainMenu() *style.Menu {
 		{Number: 8, Title: "Environment", Description: "Configure environment variable"},
 		{Number: 9, Title: "System Details", Description: "View system information"},
 		{Number: 10, Title: "Logs", Description: "View log file"},
+		{Number: 11, Title: "Auto Updates", Description: "Configure the automatic upgrade policy"},
+		{Number: 12, Title: "Schedule", Description: "Re-run hardening or the audit on a timer"},
+		{Number: 13, Title: "Security Scan", Description: "Lynis results and insecure packages"},
+		{Number: 14, Title: "Boot", Description: "Kernel command line and GRUB password"},
+	}
+
+	// Proxmox VE settings only apply to Proxmox VE hosts
+	if m.osInfo != nil && m.osInfo.IsProxmox {
+		menuOptions = append(menuOptions, style.MenuOption{
+			Number: 15, Title: "Proxmox", Description: "Subscription dialog, web UI access, ciphers and two-factor",
+		})
+	}
+
+	// Note when hardn last changed each area
+	m.noteLastApplied(menuOptions)
+
+	// Distribution-specific modules are disabled in degraded mode
+	if reason := unsupportedOSReason(m.osInfo); reason != "" {
+		for i := range menuOptions {
+			switch menuOptions[i].Number {
+			case 4, 11, 12, 14:
+				menuOptions[i].DisabledReason = reason
+			case 7:
+				menuOptions[i].Description = "SSH, users, DNS and SELinux only (unsupported OS)"
+			}
+		}
+	}
+
+	// Containers boot no kernel of their own
+	for i := range menuOptions {
+		if menuOptions[i].Number == 14 && menuOptions[i].DisabledReason == "" {
+			menuOptions[i].DisabledReason = containerReason(m.osInfo)
+		}
+	}
+
+	// Windows owns the firewall and resolv.conf under WSL
+	for i := range menuOptions {
+		if menuOptions[i].DisabledReason != "" {
+			continue
+		}
+		if module, ok := wslModuleOptions[menuOptions[i].Number]; ok {
+			menuOptions[i].DisabledReason = wslReason(m.osInfo, module)
+		}
 	}
 
 	// Create and customize menu
@@ -480,6 +812,69 @@ func (m *MainMenu) createMainMenu() *style.Menu {
 	return menu
 }
 
+// wslModuleOptions maps main menu options to the module name WSL skips
+var wslModuleOptions = map[int]string{
+	3:  "DNS",
+	4:  "firewall",
+	11: "automatic updates",
+	12: "schedule",
+	14: "boot hardening",
+}
+
+// areaOptions maps main menu options to the hardening area they change
+var areaOptions = map[int]string{
+	1: model.AreaUsers,
+	2: model.AreaSSH,
+	3: model.AreaDNS,
+	4: model.AreaFirewall,
+}
+
+// noteLastApplied notes when a recorded run last changed each area, so
+// areas hardn has not touched on this host stand out. Nothing is noted
+// when the runs can not be read.
+func (m *MainMenu) noteLastApplied(options []style.MenuOption) {
+	states, err := m.menuManager.LastApplied()
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	for i := range options {
+		area, ok := areaOptions[options[i].Number]
+		if !ok {
+			continue
+		}
+		if state, ok := states[area]; ok {
+			options[i].Note = formatLastApplied(state, now)
+		} else {
+			options[i].Note = "not applied yet"
+		}
+	}
+}
+
+// formatLastApplied describes when an area was last applied, e.g.
+// "last applied: 3d ago by hardn v0.3.2"
+func formatLastApplied(state model.AreaState, now time.Time) string {
+	note := "last applied: " + formatAge(now.Sub(state.Applied))
+	if state.Version != "" {
+		note += " by hardn v" + strings.TrimPrefix(state.Version, "v")
+	}
+	return note
+}
+
+// formatAge returns a rough age such as "5m ago" or "3d ago"
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	}
+	return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+}
+
 // handleMenuChoice processes the user's menu selection and returns true if the application should exit
 func (m *MainMenu) handleMenuChoice(choice string) bool {
 	switch choice {
@@ -524,19 +919,45 @@ func (m *MainMenu) handleMenuChoice(choice string) bool {
 		envMenu.Show()
 
 	case "9": // Host Info
-		systemDetailsMenu := NewSystemDetailsMenu(m.config, m.osInfo, m.menuManager.GetHostInfoManager())
+		systemDetailsMenu := NewSystemDetailsMenu(m.config, m.osInfo,
+			m.menuManager.GetHostInfoManager(), m.menuManager.GetNetworkExposureManager(),
+			m.menuManager.GetTimeSyncManager())
 		systemDetailsMenu.Show()
 
 	case "10": // Logs
 		logsMenu := NewLogsMenu(m.menuManager, m.config)
 		logsMenu.Show()
 
+	case "11": // Auto Updates
+		autoUpdatesMenu := NewAutoUpdatesMenu(m.menuManager, m.config, m.osInfo)
+		autoUpdatesMenu.Show()
+
+	case "12": // Schedule
+		scheduleMenu := NewScheduleMenu(m.menuManager, m.config)
+		scheduleMenu.Show()
+
+	case "13": // Security Scan
+		securityScanMenu := NewSecurityScanMenu(m.menuManager, m.config)
+		securityScanMenu.Show()
+
+	case "14": // Boot
+		bootMenu := NewBootHardeningMenu(m.menuManager.GetBootHardeningManager(), m.config)
+		bootMenu.Show()
+
 	case "0": // Exit
 		utils.ClearScreen()
 		return true
 
+	case "15": // Proxmox, only offered on Proxmox VE hosts
+		if m.osInfo != nil && m.osInfo.IsProxmox {
+			proxmoxMenu := NewProxmoxMenu(m.menuManager.GetProxmoxManager(), m.config)
+			proxmoxMenu.Show()
+			break
+		}
+		fallthrough
+
 	default:
-		utils.PrintHeader()
+		redrawScreen()
 		fmt.Printf("%s Invalid option. Please try again.\n",
 			style.Colored(style.Red, style.SymCrossMark))
 		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
@@ -0,0 +1,71 @@
+// pkg/menu/navigation.go
+package menu
+
+import (
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/style"
+	"github.com/abbott/hardn/pkg/utils"
+)
+
+// mainScreenTitle is the root of every breadcrumb
+const mainScreenTitle = "Main"
+
+// screen is an entry on the navigation stack. Menus redisplay themselves by
+// calling Show again, so re-entering the current screen only counts visits
+// instead of adding a crumb.
+type screen struct {
+	title  string
+	visits int
+}
+
+// screens holds the path from the main menu to the current screen
+var screens []screen
+
+// enterScreen makes title the current screen and draws its header. The
+// returned function leaves the screen again; menus defer it in Show:
+//
+//	defer enterScreen("Firewall")()
+func enterScreen(title string) func() {
+	if n := len(screens); n > 0 && screens[n-1].title == title {
+		screens[n-1].visits++
+	} else {
+		screens = append(screens, screen{title: title, visits: 1})
+	}
+	drawScreenHeader()
+	return leaveScreen
+}
+
+// leaveScreen undoes the most recent enterScreen
+func leaveScreen() {
+	n := len(screens)
+	if n == 0 {
+		return
+	}
+	screens[n-1].visits--
+	if screens[n-1].visits == 0 {
+		screens = screens[:n-1]
+	}
+}
+
+// redrawScreen clears the terminal and draws the current screen's header
+// again, for screens that redisplay part of their content
+func redrawScreen() {
+	drawScreenHeader()
+}
+
+// breadcrumbPath returns the titles from the main menu to the current screen
+func breadcrumbPath() []string {
+	path := []string{mainScreenTitle}
+	for _, entry := range screens {
+		path = append(path, entry.title)
+	}
+	return path
+}
+
+// drawScreenHeader clears the terminal and prints the standard header with
+// the breadcrumb to the current screen
+func drawScreenHeader() {
+	utils.PrintHeader()
+	fmt.Println(style.Breadcrumb(breadcrumbPath()...))
+}
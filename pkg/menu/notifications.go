@@ -0,0 +1,79 @@
+// pkg/menu/notifications.go
+package menu
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// Notification levels
+const (
+	NotifyInfo    = "info"
+	NotifyWarning = "warning"
+)
+
+// maxNotifications bounds the queue; the oldest events are dropped first
+const maxNotifications = 10
+
+// Notification is an event raised in the background while the menu runs
+type Notification struct {
+	Time    time.Time
+	Level   string
+	Message string
+}
+
+// Notifications queues background events until the main menu shows them
+type Notifications struct {
+	mu      sync.Mutex
+	pending []Notification
+}
+
+// NewNotifications creates an empty notification queue
+func NewNotifications() *Notifications {
+	return &Notifications{}
+}
+
+// Post queues an event; it is safe to call from any goroutine
+func (n *Notifications) Post(level, message string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.pending = append(n.pending, Notification{
+		Time:    time.Now(),
+		Level:   level,
+		Message: message,
+	})
+	if len(n.pending) > maxNotifications {
+		n.pending = n.pending[len(n.pending)-maxNotifications:]
+	}
+}
+
+// Drain returns the queued events, oldest first, and empties the queue
+func (n *Notifications) Drain() []Notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	pending := n.pending
+	n.pending = nil
+	return pending
+}
+
+// Print displays events below the status box
+func (n *Notifications) Print(events []Notification) {
+	if len(events) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println(style.Bolded("  Notifications"))
+	for _, event := range events {
+		symbol := style.Colored(style.Royal, style.SymInfo)
+		if event.Level == NotifyWarning {
+			symbol = style.Colored(style.Yellow, style.SymWarning)
+		}
+		fmt.Printf("  %s %s %s\n", symbol, style.Dimmed(event.Time.Format("15:04:05")), event.Message)
+	}
+}
@@ -0,0 +1,153 @@
+// pkg/menu/proxmox_menu.go
+package menu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// ProxmoxMenu handles the Proxmox VE web UI and realm hardening
+type ProxmoxMenu struct {
+	proxmoxManager *application.ProxmoxManager
+	config         *config.Config
+}
+
+// NewProxmoxMenu creates a new ProxmoxMenu
+func NewProxmoxMenu(
+	proxmoxManager *application.ProxmoxManager,
+	config *config.Config,
+) *ProxmoxMenu {
+	return &ProxmoxMenu{
+		proxmoxManager: proxmoxManager,
+		config:         config,
+	}
+}
+
+// Show displays the Proxmox menu and handles user input
+func (m *ProxmoxMenu) Show() {
+	defer enterScreen("Proxmox")()
+
+	status, err := m.proxmoxManager.GetStatus()
+	if err != nil {
+		fmt.Printf("\n%s Error reading Proxmox VE settings: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		status = &model.ProxmoxStatus{}
+	}
+
+	// Display current settings
+	fmt.Println()
+	fmt.Println(style.Bolded("Proxmox VE Hardening:", style.Blue))
+
+	formatter := style.NewStatusFormatter([]string{"Subscription Dialog", "Web UI", "Ciphers", "Two-Factor"}, 2)
+
+	if status.SubscriptionNagDisabled {
+		fmt.Println(formatter.FormatSuccess("Subscription Dialog", "Disabled", ""))
+	} else {
+		fmt.Println(formatter.FormatWarning("Subscription Dialog", "Shown", "at every login"))
+	}
+	if len(status.WebUIAllowlist) > 0 {
+		fmt.Println(formatter.FormatSuccess("Web UI", "Restricted", strings.Join(status.WebUIAllowlist, ", ")))
+	} else {
+		fmt.Println(formatter.FormatWarning("Web UI", "Open", fmt.Sprintf("port %d from anywhere", model.ProxmoxWebUIPort)))
+	}
+	if status.CiphersHardened {
+		fmt.Println(formatter.FormatSuccess("Ciphers", "Hardened", "ECDHE with AEAD only"))
+	} else {
+		fmt.Println(formatter.FormatWarning("Ciphers", "Default", ""))
+	}
+	if status.RealmTwoFactor != "" {
+		fmt.Println(formatter.FormatSuccess("Two-Factor", "Required", status.RealmTwoFactor))
+	} else {
+		fmt.Println(formatter.FormatWarning("Two-Factor", "Not required", model.ProxmoxRealm+" realm"))
+	}
+
+	allowlist := m.config.Proxmox.WebUIAllowlist
+	allowOption := style.MenuOption{
+		Number:      2,
+		Title:       "Restrict web UI",
+		Description: "Allow port 8006 from " + strings.Join(allowlist, ", ") + " only",
+	}
+	if len(allowlist) == 0 {
+		allowOption.DisabledReason = "set proxmox.webUIAllowlist in the configuration first"
+	}
+
+	menuOptions := []style.MenuOption{
+		{Number: 1, Title: "Disable subscription dialog", Description: "Patch the web UI and keep it patched after upgrades"},
+		allowOption,
+		{Number: 3, Title: "Harden ciphers", Description: "Limit pveproxy to ECDHE key exchange and AEAD ciphers"},
+		{Number: 4, Title: "Require two-factor", Description: "TOTP for users of the " + model.ProxmoxRealm + " realm"},
+	}
+
+	menu := style.NewMenu("Select an option", menuOptions)
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return to main menu",
+		Description: "",
+	})
+
+	menu.Print()
+
+	choice := ReadMenuInput()
+
+	// Handle 'q' as a special exit case
+	if choice == "q" {
+		return
+	}
+
+	if rejectDisabledOption(menu, choice) {
+		m.Show()
+		return
+	}
+
+	switch choice {
+	case "1":
+		m.apply("disable the subscription dialog", model.ProxmoxHardening{DisableSubscriptionNag: true})
+
+	case "2":
+		m.apply("allow the web UI from "+strings.Join(allowlist, ", ")+" only",
+			model.ProxmoxHardening{WebUIAllowlist: allowlist})
+
+	case "3":
+		m.apply("harden the pveproxy ciphers", model.ProxmoxHardening{HardenCiphers: true})
+
+	case "4":
+		fmt.Printf("\n%s Users of the %s realm without a TOTP secret will not be able to log in.\n",
+			style.Colored(style.Yellow, style.SymWarning), model.ProxmoxRealm)
+		fmt.Printf("%s Continue? (y/n): ", style.BulletItem)
+		if confirm := ReadInput(); strings.ToLower(confirm) == "y" || strings.ToLower(confirm) == "yes" {
+			m.apply("require TOTP for the "+model.ProxmoxRealm+" realm", model.ProxmoxHardening{RealmTwoFactor: true})
+		}
+
+	case "0":
+		// Return to main menu
+		return
+
+	default:
+		fmt.Printf("\n%s Invalid option. Please try again.\n",
+			style.Colored(style.Red, style.SymCrossMark))
+	}
+
+	fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+	ReadKey()
+	m.Show()
+}
+
+// apply applies one Proxmox VE setting, or describes it in dry-run mode
+func (m *ProxmoxMenu) apply(description string, policy model.ProxmoxHardening) {
+	if m.config.DryRun {
+		fmt.Printf("\n%s [DRY-RUN] Would %s\n", style.BulletItem, description)
+		return
+	}
+
+	if err := m.proxmoxManager.ApplyHardening(policy); err != nil {
+		fmt.Printf("\n%s Failed to %s: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), description, err)
+		return
+	}
+	fmt.Printf("\n%s Done: %s\n", style.Colored(style.Green, style.SymCheckMark), description)
+}
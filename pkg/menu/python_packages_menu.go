@@ -8,9 +8,9 @@ import (
 
 	"github.com/abbott/hardn/pkg/application"
 	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
 	"github.com/abbott/hardn/pkg/osdetect"
 	"github.com/abbott/hardn/pkg/style"
-	"github.com/abbott/hardn/pkg/utils"
 )
 
 // PythonPackagesMenu handles Python packages installation
@@ -35,14 +35,16 @@ func NewPythonPackagesMenu(
 
 // Show displays the Python packages menu and handles user input
 func (m *PythonPackagesMenu) Show() {
-	utils.PrintHeader()
-	fmt.Println(style.Bolded("Python Packages Installation", style.Blue))
+	defer enterScreen("Python Packages")()
 
 	// Get OS-specific package information
 	var packageDisplay string
 	if m.osInfo.OsType == "alpine" {
 		packageDisplay = fmt.Sprintf("Alpine Python packages: %s",
 			style.Colored(style.Cyan, strings.Join(m.config.AlpinePythonPackages, ", ")))
+	} else if model.IsRHELFamily(m.osInfo.OsType) {
+		packageDisplay = fmt.Sprintf("RHEL Python packages: %s",
+			style.Colored(style.Cyan, strings.Join(m.config.RhelPythonPackages, ", ")))
 	} else {
 		// For Debian/Ubuntu
 		allPackages := append([]string{}, m.config.PythonPackages...)
@@ -165,7 +167,9 @@ func (m *PythonPackagesMenu) Show() {
 					strings.Join(m.config.AlpinePythonPackages, ", "))
 			} else {
 				allPackages := append([]string{}, m.config.PythonPackages...)
-				if os.Getenv("WSL") == "" {
+				if model.IsRHELFamily(m.osInfo.OsType) {
+					allPackages = append([]string{}, m.config.RhelPythonPackages...)
+				} else if os.Getenv("WSL") == "" {
 					allPackages = append(allPackages, m.config.NonWslPythonPackages...)
 				}
 
@@ -189,6 +193,8 @@ func (m *PythonPackagesMenu) Show() {
 			var systemPackages []string
 			if m.osInfo.OsType == "alpine" {
 				systemPackages = m.config.AlpinePythonPackages
+			} else if model.IsRHELFamily(m.osInfo.OsType) {
+				systemPackages = m.config.RhelPythonPackages
 			} else {
 				systemPackages = m.config.PythonPackages
 				if os.Getenv("WSL") == "" {
@@ -8,6 +8,7 @@ import (
 	"github.com/abbott/hardn/pkg/application"
 	"github.com/abbott/hardn/pkg/config"
 	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/logging"
 	"github.com/abbott/hardn/pkg/osdetect"
 	"github.com/abbott/hardn/pkg/style"
 	"github.com/abbott/hardn/pkg/utils"
@@ -35,8 +36,7 @@ func NewRunAllMenu(
 
 // Show displays the Run All menu and handles user input
 func (m *RunAllMenu) Show() {
-	utils.PrintHeader()
-	fmt.Println(style.Bolded("Run All Hardening Steps", style.Blue))
+	defer enterScreen("Run All")()
 
 	// Create a formatter for status
 	formatter := style.NewStatusFormatter([]string{"Dry-Run Mode", "Username", "SSH Port"}, 2)
@@ -73,9 +73,12 @@ func (m *RunAllMenu) Show() {
 		desc    string
 	}{
 		{"AppArmor", m.config.EnableAppArmor, "Application control system"},
+		{"SELinux", m.config.EnableSELinux, "Enforcing mode with the targeted policy"},
 		{"Lynis", m.config.EnableLynis, "Security audit tool"},
 		{"Unattended Upgrades", m.config.EnableUnattendedUpgrades, "Automatic security updates"},
 		{"UFW SSH Policy", m.config.EnableUfwSshPolicy, "Firewall rules for SSH"},
+		{"Web Server TLS", m.config.EnableWebServerTls, "TLS baseline for nginx, Apache and Caddy"},
+		{"Database Hardening", m.config.DatabaseHardening.Enabled, "PostgreSQL and MySQL baseline"},
 		{"DNS Configuration", m.config.ConfigureDns, "DNS settings"},
 		{"Root SSH Disable", m.config.DisableRootSSH, "Disable root SSH access"},
 	}
@@ -90,6 +93,22 @@ func (m *RunAllMenu) Show() {
 		}
 	}
 
+	// Degraded mode runs only the distribution-agnostic steps
+	if m.osInfo.Degraded {
+		fmt.Println()
+		fmt.Printf("%s Unsupported OS (%s): only the %s steps will run\n",
+			style.Colored(style.Yellow, style.SymWarning), m.osInfo.OsType, strings.Join(osdetect.DegradedModules, ", "))
+		fmt.Println(style.Dimmed("Features that depend on the distribution are skipped."))
+	}
+
+	// WSL leaves the network edge and the kernel to Windows
+	if skipped := m.osInfo.WSLSkipped(); len(skipped) > 0 {
+		fmt.Println()
+		fmt.Printf("%s WSL %d: %s are skipped\n",
+			style.Colored(style.Yellow, style.SymWarning), m.osInfo.WSLVersion, strings.Join(skipped, ", "))
+		fmt.Println(style.Dimmed("Run 'hardn wsl' for the hardening to do in Windows and /etc/wsl.conf instead."))
+	}
+
 	// Security warning
 	fmt.Println()
 	fmt.Println(style.Bolded("SECURITY WARNING:", style.Red))
@@ -143,6 +162,13 @@ func (m *RunAllMenu) Show() {
 		// Run with current settings
 		m.runAllHardening()
 	case "2":
+		if m.config.MaintenanceLocked && m.config.DryRun {
+			fmt.Printf("\n%s Outside the maintenance window; dry-run mode stays enabled.\n",
+				style.Colored(style.Yellow, style.SymWarning))
+			fmt.Println(style.Dimmed("Restart hardn with --override-window to apply changes now."))
+			break
+		}
+
 		// Toggle dry-run mode and run
 		m.config.DryRun = !m.config.DryRun
 		if m.config.DryRun {
@@ -200,83 +226,115 @@ func (m *RunAllMenu) runAllHardening() {
 	utils.PrintLogo()
 	fmt.Println(style.Bolded("Executing All Hardening Steps", style.Blue))
 
+	// needrestart is only managed on Debian/Ubuntu
+	needrestartMode := ""
+	if m.menuManager.NeedrestartSupported() {
+		needrestartMode = m.config.NeedrestartMode
+	}
+
+	// Automatic updates are written by the updates service, or by dnf-automatic on RHEL
+	autoUpdates := m.menuManager.UpdatesSupported() || m.menuManager.AutoUpgradesSupported()
+
 	// Build a comprehensive HardeningConfig from current configuration
 	hardening := model.HardeningConfig{
-		CreateUser:         m.config.Username != "",
-		Username:           m.config.Username,
-		SudoNoPassword:     m.config.SudoNoPassword,
-		SshKeys:            m.config.SshKeys,
-		SshPort:            m.config.SshPort,
-		SshListenAddresses: []string{m.config.SshListenAddress},
-		SshAllowedUsers:    m.config.SshAllowedUsers,
-		EnableFirewall:     m.config.EnableUfwSshPolicy,
-		AllowedPorts:       m.config.UfwAllowedPorts,
-		ConfigureDns:       m.config.ConfigureDns,
-		Nameservers:        m.config.Nameservers,
-		EnableAppArmor:     m.config.EnableAppArmor,
-		EnableLynis:        m.config.EnableLynis,
-		// EnableUnattendedUpgrades: m.config.EnableUnattendedUpgrades,
-	}
-
-	// Track progress with step counting
-	totalSteps := calculateTotalSteps(&hardening)
-	currentStep := 0
-
-	// Function to show progress
-	showProgress := func(stepName string) {
-		currentStep++
-		fmt.Printf("\n%s [%d/%d] %s\n",
-			style.Colored(style.Cyan, style.SymArrowRight),
-			currentStep,
-			totalSteps,
-			style.Bolded(stepName, style.Cyan))
-	}
-
-	// Begin hardening steps
-	showProgress("Preparing system hardening")
+		CreateUser:               m.config.Username != "",
+		Username:                 m.config.Username,
+		SudoNoPassword:           m.config.SudoNoPassword,
+		SshKeys:                  m.config.SshKeys,
+		SshPort:                  m.config.SshPort,
+		SshListenAddresses:       m.config.SshListenAddresses,
+		SshAllowedUsers:          m.config.SshAllowedUsers,
+		SshAllowAllUsers:         m.config.SshAllowAllUsers,
+		SshProfile:               m.config.SshProfile,
+		EnableFirewall:           m.config.EnableUfwSshPolicy,
+		AllowedPorts:             m.config.UfwAllowedPorts,
+		FirewallRules:            m.config.CustomFirewallRules(),
+		SshRateLimit:             m.config.SshRateLimit,
+		PortKnocking:             m.config.SSHFirewallPolicy().Knocking,
+		IPv6:                     m.config.IPv6.Policy(),
+		MirrorFirewallStacks:     m.config.MirrorFirewallStacks,
+		ConfigureDns:             m.config.ConfigureDns,
+		Nameservers:              m.config.Nameservers,
+		EnableAppArmor:           m.config.EnableAppArmor,
+		EnableSELinux:            m.config.EnableSELinux,
+		EnableLynis:              m.config.EnableLynis,
+		EnableUnattendedUpgrades: m.config.EnableUnattendedUpgrades && autoUpdates,
+		AutoUpdates:              m.config.AutoUpdates.Policy(),
+		NeedrestartMode:          needrestartMode,
+		EnableWebServerTLS:       m.config.EnableWebServerTls,
+	}
+	if m.config.DatabaseHardening.Enabled {
+		hardening.DatabaseHardening = &model.DatabaseHardening{
+			BindAddresses:  m.config.DatabaseHardening.BindAddresses,
+			AllowedSubnets: m.config.DatabaseHardening.AllowedSubnets,
+		}
+	}
+
+	// Only the distribution-agnostic steps run in degraded mode
+	if m.osInfo.Degraded {
+		if skipped := hardening.RestrictToDistributionAgnostic(); len(skipped) > 0 {
+			fmt.Printf("\n%s Skipped on unsupported OS: %s\n",
+				style.Colored(style.Yellow, style.SymWarning), strings.Join(skipped, ", "))
+		}
+	}
+	if env, tuned := model.ParseEnvironmentPlan(m.config.EnvironmentPlan, m.osInfo.Environment); tuned {
+		if skipped := hardening.ApplyEnvironmentPlan(env); len(skipped) > 0 {
+			fmt.Printf("\n%s Skipped in %s: %s\n",
+				style.Colored(style.Yellow, style.SymWarning), env, strings.Join(skipped, ", "))
+		}
+	}
+	if m.osInfo.IsWSL {
+		if skipped := hardening.RestrictForWSL(m.osInfo.Systemd, m.osInfo.WSLResolvConf); len(skipped) > 0 {
+			fmt.Printf("\n%s Skipped on WSL: %s\n",
+				style.Colored(style.Yellow, style.SymWarning), strings.Join(skipped, ", "))
+		}
+	}
 
 	if m.config.DryRun {
+		// Track progress with step counting
+		totalSteps := calculateTotalSteps(&hardening)
+		if m.osInfo.Degraded {
+			// Repository, package and Python package steps are skipped
+			totalSteps -= 3
+		}
+		currentStep := 0
+
+		// Function to show progress
+		showProgress := func(stepName string) {
+			currentStep++
+			fmt.Printf("\n%s [%d/%d] %s\n",
+				style.Colored(style.Cyan, style.SymArrowRight),
+				currentStep,
+				totalSteps,
+				style.Bolded(stepName, style.Cyan))
+		}
+
 		// In dry-run mode, show what would happen
-		// updateRepositories := true
-		// installPackages := true
+		showProgress("Preparing system hardening")
 		useUvPackageManager := m.config.UseUvPackageManager
-		dryRunHardening(&hardening, showProgress, m.osInfo.IsProxmox, useUvPackageManager)
+		dryRunHardening(m.menuManager, &hardening, showProgress, m.osInfo, useUvPackageManager)
 	} else {
-		// Execute the hardening through the MenuManager
+		// Offer a filesystem snapshot before anything changes
+		if !m.offerSnapshot() {
+			return
+		}
+
+		// Follow the steps on a checklist; console logging would scroll it
+		// away, so details go to the log file only
+		fmt.Println()
+		checklist := style.NewProgressChecklist("Run All")
+		m.menuManager.SetProgressReporter(checklist)
+		wasSilent := logging.IsSilent()
+		logging.SetSilentMode(true)
 		err := m.menuManager.HardenSystem(&hardening)
+		logging.SetSilentMode(wasSilent)
+		m.menuManager.SetProgressReporter(nil)
 
 		if err != nil {
 			fmt.Printf("\n%s System hardening failed: %v\n",
 				style.Colored(style.Red, style.SymCrossMark), err)
 			return
 		}
-
-		// Show steps completed when not in dry-run mode
-		if hardening.CreateUser {
-			showProgress("User account configured")
-		}
-
-		showProgress("SSH configuration completed")
-
-		if hardening.EnableFirewall {
-			showProgress("Firewall configured")
-		}
-
-		if hardening.ConfigureDns {
-			showProgress("DNS settings applied")
-		}
-
-		if hardening.EnableAppArmor {
-			showProgress("AppArmor configured")
-		}
-
-		if hardening.EnableLynis {
-			showProgress("Lynis security audit completed")
-		}
-
-		// if hardening.EnableUnattendedUpgrades {
-		// 	showProgress("Automatic updates configured")
-		// }
 	}
 
 	// Final status
@@ -294,7 +352,7 @@ func (m *RunAllMenu) runAllHardening() {
 
 	fmt.Printf("\n%s Check the log file at %s for details\n",
 		style.Colored(style.Cyan, style.SymInfo),
-		style.Colored(style.Cyan, m.config.LogFile))
+		style.Colored(style.Cyan, m.config.LoggingOptions().Path))
 }
 
 // calculateTotalSteps determines the total number of hardening steps
@@ -319,19 +377,41 @@ func calculateTotalSteps(config *model.HardeningConfig) int {
 		totalSteps++
 	}
 
+	if config.EnableSELinux {
+		totalSteps++
+	}
+
 	if config.EnableLynis {
 		totalSteps++
 	}
 
-	// if config.EnableUnattendedUpgrades {
-	// 	totalSteps++
-	// }
+	if config.EnableUnattendedUpgrades {
+		totalSteps++
+	}
+
+	if config.NeedrestartMode != "" {
+		totalSteps++
+	}
+
+	if config.EnableWebServerTLS {
+		totalSteps++
+	}
+
+	if config.DatabaseHardening != nil {
+		totalSteps++
+	}
 
 	return totalSteps
 }
 
 // dryRunHardening simulates the hardening process without making changes
-func dryRunHardening(config *model.HardeningConfig, showProgress func(string), isProxmox bool, useUvPackageManager bool) {
+func dryRunHardening(menuManager *application.MenuManager, config *model.HardeningConfig, showProgress func(string), osInfo *osdetect.OSInfo, useUvPackageManager bool) {
+	// Preview the pre-hardening snapshot offer
+	if support, err := menuManager.GetSnapshotSupport(); err == nil && support.Supported {
+		fmt.Printf("%s Would offer a %s snapshot of %s before applying changes\n",
+			style.BulletItem, support.Backend, support.Target)
+	}
+
 	// Simulate user creation
 	if config.CreateUser {
 		showProgress("Simulating user account creation")
@@ -347,31 +427,34 @@ func dryRunHardening(config *model.HardeningConfig, showProgress func(string), i
 		}
 	}
 
-	// Simulate package repository update
-	showProgress("Simulating package repository update")
-	fmt.Printf("%s Would update package sources for system\n", style.BulletItem)
+	// Package steps depend on the distribution and are skipped in degraded mode
+	if !osInfo.Degraded {
+		// Simulate package repository update
+		showProgress("Simulating package repository update")
+		fmt.Printf("%s Would update package sources for system\n", style.BulletItem)
 
-	if isProxmox {
-		fmt.Printf("%s Would configure Proxmox-specific repositories\n", style.BulletItem)
-	}
+		if osInfo.IsProxmox {
+			fmt.Printf("%s Would configure Proxmox-specific repositories\n", style.BulletItem)
+		}
 
-	// Simulate package installation
-	showProgress("Simulating package installation")
-	fmt.Printf("%s Would install core system packages\n", style.BulletItem)
+		// Simulate package installation
+		showProgress("Simulating package installation")
+		fmt.Printf("%s Would install core system packages\n", style.BulletItem)
 
-	// Check if DMZ subnet is detected (this is a simulation)
-	fmt.Printf("%s Would determine network environment (DMZ vs. Lab)\n", style.BulletItem)
-	fmt.Printf("%s Would install appropriate packages for environment\n", style.BulletItem)
+		// Check if DMZ subnet is detected (this is a simulation)
+		fmt.Printf("%s Would determine network environment (DMZ vs. Lab)\n", style.BulletItem)
+		fmt.Printf("%s Would install appropriate packages for environment\n", style.BulletItem)
 
-	// Simulate Python package installation
-	showProgress("Simulating Python package installation")
-	packageManager := "pip"
-	if useUvPackageManager {
-		packageManager = "UV"
+		// Simulate Python package installation
+		showProgress("Simulating Python package installation")
+		packageManager := "pip"
+		if useUvPackageManager {
+			packageManager = "UV"
+		}
+		fmt.Printf("%s Would install Python packages with %s\n",
+			style.BulletItem,
+			packageManager)
 	}
-	fmt.Printf("%s Would install Python packages with %s\n",
-		style.BulletItem,
-		packageManager)
 
 	// Simulate SSH configuration
 	showProgress("Simulating SSH configuration")
@@ -379,6 +462,25 @@ func dryRunHardening(config *model.HardeningConfig, showProgress func(string), i
 		style.BulletItem,
 		config.SshPort)
 
+	allowedUsers, err := menuManager.ResolveSSHAllowedUsers(config.SshAllowedUsers, config.Username, config.SshAllowAllUsers)
+	if err != nil {
+		fmt.Printf("%s SSH configuration would be refused: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+	} else if len(allowedUsers) == 0 {
+		fmt.Printf("%s Would not restrict SSH users (sshAllowAllUsers is set)\n",
+			style.Colored(style.Yellow, style.SymWarning))
+	} else {
+		fmt.Printf("%s Would allow SSH for: %s\n",
+			style.BulletItem,
+			strings.Join(allowedUsers, ", "))
+	}
+
+	if config.SshProfile != "" {
+		fmt.Printf("%s Would apply the '%s' SSH hardening profile\n",
+			style.BulletItem,
+			config.SshProfile)
+	}
+
 	// Simulate firewall configuration
 	if config.EnableFirewall {
 		showProgress("Simulating firewall configuration")
@@ -403,15 +505,104 @@ func dryRunHardening(config *model.HardeningConfig, showProgress func(string), i
 		fmt.Printf("%s Would install and activate AppArmor\n", style.BulletItem)
 	}
 
+	// Simulate SELinux setup
+	if config.EnableSELinux {
+		showProgress("Simulating SELinux configuration")
+		fmt.Printf("%s Would install the SELinux policy and set enforcing mode\n", style.BulletItem)
+		if config.SshPort != 22 {
+			fmt.Printf("%s Would label port %d as ssh_port_t\n", style.BulletItem, config.SshPort)
+		}
+	}
+
 	// Simulate Lynis installation
 	if config.EnableLynis {
 		showProgress("Simulating Lynis security audit")
 		fmt.Printf("%s Would install and run Lynis security audit\n", style.BulletItem)
 	}
 
-	// // Simulate unattended upgrades setup
-	// if config.EnableUnattendedUpgrades {
-	// 	showProgress("Simulating automatic updates configuration")
-	// 	fmt.Printf("%s Would configure unattended security updates\n", style.BulletItem)
-	// }
+	// Simulate automatic upgrades setup
+	if config.EnableUnattendedUpgrades {
+		showProgress("Simulating automatic updates configuration")
+		policy := menuManager.EffectiveUpdatesPolicy(config.AutoUpdates)
+		switch {
+		case osInfo.OsType == "alpine":
+			fmt.Printf("%s Would write %s (apk update && apk upgrade --no-cache)\n",
+				style.BulletItem, model.AlpineAutoUpgradeScriptPath)
+			fmt.Printf("%s Would enable crond at boot via OpenRC\n", style.BulletItem)
+		case model.IsRHELFamily(osInfo.OsType):
+			fmt.Printf("%s Would install dnf-automatic and enable %s\n", style.BulletItem, model.DnfAutomaticTimer)
+		default:
+			fmt.Printf("%s Would install unattended-upgrades and write %s and %s\n",
+				style.BulletItem, model.AptUnattendedUpgradesPath, model.AptAutoUpgradesPath)
+			fmt.Printf("%s Origins: %s\n", style.BulletItem, strings.Join(policy.Origins, ", "))
+		}
+		if len(policy.Blacklist) > 0 && !model.IsRHELFamily(osInfo.OsType) {
+			fmt.Printf("%s Never upgrade: %s\n", style.BulletItem, strings.Join(policy.Blacklist, ", "))
+		}
+	}
+
+	// Simulate needrestart setup
+	if config.NeedrestartMode != "" {
+		showProgress("Simulating service restart configuration")
+		fmt.Printf("%s Would install needrestart and set restart mode to %s in %s\n",
+			style.BulletItem, config.NeedrestartMode, model.NeedrestartConfigPath)
+	}
+
+	// Simulate web server TLS hardening
+	if config.EnableWebServerTLS {
+		showProgress("Simulating web server TLS baseline")
+		fmt.Printf("%s Would write a managed TLS snippet for each detected nginx, Apache or Caddy server\n", style.BulletItem)
+		fmt.Printf("%s Would reload each server only after its configuration check passes\n", style.BulletItem)
+	}
+
+	// Simulate database hardening
+	if config.DatabaseHardening != nil {
+		showProgress("Simulating database baseline")
+		bind := "127.0.0.1"
+		if len(config.DatabaseHardening.BindAddresses) > 0 {
+			bind = strings.Join(config.DatabaseHardening.BindAddresses, ", ")
+		}
+		fmt.Printf("%s Would restrict detected PostgreSQL and MySQL servers to %s and restart them\n", style.BulletItem, bind)
+		for _, subnet := range config.DatabaseHardening.AllowedSubnets {
+			fmt.Printf("%s Would allow the database port from %s\n", style.BulletItem, subnet)
+		}
+	}
+}
+
+// offerSnapshot offers to snapshot the root filesystem before hardening when
+// it sits on LVM thin, ZFS or btrfs. Returns false if hardening should stop.
+func (m *RunAllMenu) offerSnapshot() bool {
+	support, err := m.menuManager.GetSnapshotSupport()
+	if err != nil || !support.Supported {
+		return true
+	}
+
+	target := support.Target
+	if support.Proxmox {
+		target += " (Proxmox host)"
+	}
+
+	fmt.Printf("\n%s Root filesystem supports %s snapshots: %s\n",
+		style.Colored(style.Cyan, style.SymInfo), support.Backend, target)
+	fmt.Printf("%s Create a snapshot before hardening? (y/n): ", style.BulletItem)
+	choice := ReadInput()
+	if !strings.EqualFold(choice, "y") && !strings.EqualFold(choice, "yes") {
+		return true
+	}
+
+	snapshot, err := m.menuManager.CreateSnapshot()
+	if err != nil {
+		fmt.Printf("\n%s Failed to create snapshot: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		fmt.Printf("%s Continue hardening without a snapshot? (y/n): ", style.BulletItem)
+		choice = ReadInput()
+		return strings.EqualFold(choice, "y") || strings.EqualFold(choice, "yes")
+	}
+
+	fmt.Printf("\n%s Snapshot %s created\n",
+		style.Colored(style.Green, style.SymCheckMark), style.Bolded(snapshot.Name))
+	fmt.Printf("%s Revert with: %s\n", style.BulletItem,
+		style.Colored(style.Cyan, "hardn rollback --snapshot "+snapshot.Name))
+
+	return true
 }
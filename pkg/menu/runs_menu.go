@@ -0,0 +1,188 @@
+// pkg/menu/runs_menu.go
+package menu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// maxListedRuns limits the runs offered in the menu; older runs can be
+// rolled back with 'hardn rollback <run-id>'
+const maxListedRuns = 9
+
+// RunsMenu lists recorded hardening runs and rolls them back
+type RunsMenu struct {
+	menuManager *application.MenuManager
+	config      *config.Config
+}
+
+// NewRunsMenu creates a new RunsMenu
+func NewRunsMenu(
+	menuManager *application.MenuManager,
+	config *config.Config,
+) *RunsMenu {
+	return &RunsMenu{
+		menuManager: menuManager,
+		config:      config,
+	}
+}
+
+// Show displays the recorded runs, newest first, and handles user input
+func (m *RunsMenu) Show() {
+	defer enterScreen("Runs")()
+
+	runs, err := m.menuManager.ListRuns()
+	if err != nil {
+		fmt.Printf("\n%s Error reading hardening runs: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+	}
+
+	// Newest first
+	var recent []model.RunManifest
+	for i := len(runs) - 1; i >= 0 && len(recent) < maxListedRuns; i-- {
+		recent = append(recent, runs[i])
+	}
+
+	fmt.Println()
+	if len(recent) == 0 {
+		fmt.Printf("%s No hardening runs recorded yet\n", style.BulletItem)
+	} else if len(runs) > len(recent) {
+		fmt.Printf("%s Showing the %d most recent of %d runs\n", style.BulletItem, len(recent), len(runs))
+	}
+
+	menuOptions := make([]style.MenuOption, 0, len(recent))
+	for i, run := range recent {
+		option := style.MenuOption{
+			Number:      i + 1,
+			Title:       fmt.Sprintf("%s  %s", run.ID, run.Description),
+			Description: fmt.Sprintf("%d changes", len(run.Changes)),
+		}
+		if run.PendingChanges() == 0 {
+			option.DisabledReason = "already rolled back"
+		} else if pending := run.PendingChanges(); pending < len(run.Changes) {
+			option.Description = fmt.Sprintf("%d of %d changes left", pending, len(run.Changes))
+		}
+		menuOptions = append(menuOptions, option)
+	}
+
+	menu := style.NewMenu("Select a run to roll back", menuOptions)
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return to backup menu",
+		Description: "",
+	})
+	menu.Print()
+
+	choice := ReadMenuInput()
+	if choice == "q" || choice == "0" {
+		return
+	}
+
+	if rejectDisabledOption(menu, choice) {
+		m.Show()
+		return
+	}
+
+	index, err := strconv.Atoi(choice)
+	if err != nil || index < 1 || index > len(recent) {
+		fmt.Printf("\n%s Invalid option. Please try again.\n",
+			style.Colored(style.Red, style.SymCrossMark))
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		m.Show()
+		return
+	}
+
+	m.showRun(recent[index-1])
+	m.Show()
+}
+
+// showRun lists the changes of a run and rolls back all or some of them
+func (m *RunsMenu) showRun(run model.RunManifest) {
+	defer enterScreen("Run " + run.ID)()
+
+	fmt.Println()
+	fmt.Printf("%s %s, started %s\n", style.BulletItem, run.Description, run.Started.Format("2006-01-02 15:04:05"))
+	fmt.Println()
+	for i, change := range run.Changes {
+		line := fmt.Sprintf("%3d. %s", i+1, change.Describe())
+		if change.IsReverted() {
+			line = style.Dimmed(line + " (rolled back)")
+		}
+		fmt.Println(line)
+	}
+
+	menu := style.NewMenu("Select an option", []style.MenuOption{
+		{Number: 1, Title: "Roll back all changes", Description: fmt.Sprintf("%d changes left", run.PendingChanges())},
+		{Number: 2, Title: "Roll back selected changes", Description: "Enter change numbers, e.g. 2,5"},
+	})
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return to runs",
+		Description: "",
+	})
+	menu.Print()
+
+	var selected []int
+	switch ReadMenuInput() {
+	case "1":
+	case "2":
+		fmt.Printf("\n%s Changes to roll back: ", style.BulletItem)
+		for _, field := range strings.FieldsFunc(ReadInput(), func(r rune) bool { return r == ',' || r == ' ' }) {
+			number, err := strconv.Atoi(field)
+			if err != nil {
+				fmt.Printf("\n%s %q is not a change number\n", style.Colored(style.Red, style.SymCrossMark), field)
+				m.pause()
+				return
+			}
+			selected = append(selected, number)
+		}
+		if len(selected) == 0 {
+			return
+		}
+	default:
+		return
+	}
+
+	if m.config.DryRun {
+		fmt.Printf("\n%s [DRY-RUN] Would roll back run %s\n", style.BulletItem, run.ID)
+		m.pause()
+		return
+	}
+
+	fmt.Printf("\n%s Roll back run %s? Files modified since the run are left alone. (y/n): ",
+		style.Colored(style.Yellow, style.SymWarning), run.ID)
+	if confirm := strings.ToLower(ReadInput()); confirm != "y" && confirm != "yes" {
+		return
+	}
+
+	result, err := m.menuManager.RollbackRun(run.ID, selected, false)
+	if result != nil {
+		for _, change := range result.Reverted {
+			fmt.Printf("%s Reverted %s\n", style.Colored(style.Green, style.SymCheckMark), change.Describe())
+		}
+		modified := false
+		for _, failure := range result.Failed {
+			fmt.Printf("%s %s: %v\n", style.Colored(style.Red, style.SymCrossMark), failure.Change.Describe(), failure.Err)
+			modified = modified || failure.Change.Kind == model.RunChangeFile
+		}
+		if modified {
+			fmt.Printf("\n%s Use 'sudo hardn rollback %s --force' to restore modified files anyway\n", style.BulletItem, run.ID)
+		}
+	}
+	if err != nil {
+		fmt.Printf("\n%s Rollback failed: %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+	}
+	m.pause()
+}
+
+func (m *RunsMenu) pause() {
+	fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+	ReadKey()
+}
@@ -0,0 +1,182 @@
+// pkg/menu/schedule_menu.go
+package menu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// ScheduleMenu handles scheduled hardening and audit runs
+type ScheduleMenu struct {
+	menuManager *application.MenuManager
+	config      *config.Config
+}
+
+// NewScheduleMenu creates a new ScheduleMenu
+func NewScheduleMenu(
+	menuManager *application.MenuManager,
+	config *config.Config,
+) *ScheduleMenu {
+	return &ScheduleMenu{
+		menuManager: menuManager,
+		config:      config,
+	}
+}
+
+// Show displays the schedule menu and handles user input
+func (m *ScheduleMenu) Show() {
+	defer enterScreen("Schedule")()
+
+	status, err := m.menuManager.GetScheduleStatus()
+	if err != nil {
+		fmt.Printf("\n%s Error reading schedule: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		status = &model.ScheduleStatus{}
+	}
+
+	// Display current settings
+	fmt.Println()
+	fmt.Println(style.Bolded("Current Schedule:", style.Blue))
+
+	formatter := style.NewStatusFormatter([]string{"Schedule", "Interval", "Next Run", "Command", "Posture Summary"}, 2)
+
+	if status.Installed {
+		backend := "cron"
+		if status.Backend == model.ScheduleBackendSystemd {
+			backend = "timer inactive"
+			if status.Active {
+				backend = "timer active"
+			}
+		}
+		fmt.Println(formatter.FormatSuccess("Schedule", "Installed", backend))
+		fmt.Println(formatter.FormatLine(style.SymInfo, style.Cyan, "Interval", status.Interval, style.Cyan, ""))
+		if status.NextRun != "" {
+			fmt.Println(formatter.FormatLine(style.SymInfo, style.Cyan, "Next Run", status.NextRun, style.Cyan, ""))
+		}
+		for _, command := range status.Commands {
+			fmt.Println(formatter.FormatLine(style.SymInfo, style.Cyan, "Command", command, style.Cyan, ""))
+		}
+		if status.Summary != "" {
+			fmt.Println(formatter.FormatLine(style.SymInfo, style.Cyan, "Posture Summary", status.Summary, style.Cyan, ""))
+		}
+	} else {
+		fmt.Println(formatter.FormatWarning("Schedule", "Not installed", "Hardening and audits only run manually"))
+	}
+
+	// The configured schedule is what option 1 installs
+	schedule, planErr := m.menuManager.PlanSchedule(
+		m.config.Schedule.Interval, m.config.Schedule.Tasks, config.AbsConfigFile(""),
+		m.config.Schedule.Watchdog.Enabled, m.config.Schedule.Summary.Cron)
+
+	installOption := style.MenuOption{Number: 1, Title: "Install schedule"}
+	if status.Installed {
+		installOption.Title = "Replace schedule"
+	}
+	if planErr != nil {
+		installOption.DisabledReason = fmt.Sprintf("invalid schedule in configuration: %v", planErr)
+	} else {
+		installOption.Description = fmt.Sprintf("%s: %s", schedule.Interval.Name, strings.Join(schedule.Tasks, ", "))
+	}
+
+	removeOption := style.MenuOption{
+		Number:      2,
+		Title:       "Remove schedule",
+		Description: "Stop scheduled runs",
+	}
+	if !status.Installed {
+		removeOption.DisabledReason = "no schedule installed"
+	}
+
+	menu := style.NewMenu("Select an option", []style.MenuOption{installOption, removeOption})
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return to main menu",
+		Description: "",
+	})
+
+	menu.Print()
+
+	choice := ReadMenuInput()
+
+	// Handle 'q' as a special exit case
+	if choice == "q" {
+		return
+	}
+
+	if rejectDisabledOption(menu, choice) {
+		m.Show()
+		return
+	}
+
+	switch choice {
+	case "1":
+		m.installSchedule(schedule)
+
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		m.Show()
+
+	case "2":
+		if m.config.DryRun {
+			fmt.Printf("\n%s [DRY-RUN] Would remove the %s schedule\n", style.BulletItem, status.Interval)
+		} else if err := m.menuManager.RemoveSchedule(); err != nil {
+			fmt.Printf("\n%s Failed to remove schedule: %v\n",
+				style.Colored(style.Red, style.SymCrossMark), err)
+		} else {
+			fmt.Printf("\n%s Schedule has been %s\n",
+				style.Colored(style.Yellow, style.SymInfo),
+				style.Bolded("removed", style.Yellow))
+		}
+
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		m.Show()
+
+	case "0":
+		// Return to main menu
+		return
+
+	default:
+		fmt.Printf("\n%s Invalid option. Please try again.\n",
+			style.Colored(style.Red, style.SymCrossMark))
+
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		m.Show()
+	}
+}
+
+// installSchedule installs the configured schedule
+func (m *ScheduleMenu) installSchedule(schedule model.Schedule) {
+	for _, task := range schedule.Tasks {
+		if task == model.ScheduleTaskRunAll && m.config.Username == "" {
+			fmt.Printf("\n%s The run-all task needs a username in the configuration file\n",
+				style.Colored(style.Red, style.SymCrossMark))
+			return
+		}
+	}
+
+	if m.config.DryRun {
+		fmt.Printf("\n%s [DRY-RUN] Would install a %s schedule running:\n", style.BulletItem, schedule.Interval.Name)
+		for _, command := range schedule.Commands {
+			fmt.Printf("%s %s\n", style.BulletItem, strings.Join(command, " "))
+		}
+		return
+	}
+
+	if err := m.menuManager.InstallSchedule(schedule); err != nil {
+		fmt.Printf("\n%s Failed to install schedule: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		return
+	}
+
+	fmt.Printf("\n%s Schedule has been %s\n",
+		style.Colored(style.Green, style.SymCheckMark),
+		style.Bolded("installed", style.Green))
+	fmt.Printf("%s Runs %s: %s\n", style.BulletItem, schedule.Interval.Name, strings.Join(schedule.Tasks, ", "))
+}
@@ -0,0 +1,163 @@
+// pkg/menu/security_scan_menu.go
+package menu
+
+import (
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// securityScanSuggestionLimit is how many suggestions the screen lists
+// before offering to show them all
+const securityScanSuggestionLimit = 10
+
+// SecurityScanMenu shows the results of Lynis scans and leads to the
+// removal of insecure packages
+type SecurityScanMenu struct {
+	menuManager *application.MenuManager
+	config      *config.Config
+}
+
+// NewSecurityScanMenu creates a new SecurityScanMenu
+func NewSecurityScanMenu(
+	menuManager *application.MenuManager,
+	config *config.Config,
+) *SecurityScanMenu {
+	return &SecurityScanMenu{
+		menuManager: menuManager,
+		config:      config,
+	}
+}
+
+// Show displays the last scan's results and handles user input
+func (m *SecurityScanMenu) Show() {
+	defer enterScreen("Security Scan")()
+
+	report, err := m.menuManager.LastLynisReport()
+	if err != nil {
+		fmt.Printf("\n%s Error reading the Lynis report: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+	}
+
+	fmt.Println()
+	if report == nil {
+		fmt.Printf("%s No Lynis scan found in %s\n", style.BulletItem, model.LynisReportPath)
+	} else {
+		m.printReport(report, securityScanSuggestionLimit)
+	}
+
+	allOption := style.MenuOption{Number: 2, Title: "Show all suggestions", Description: "List every Lynis suggestion"}
+	if report == nil || len(report.Suggestions) <= securityScanSuggestionLimit {
+		allOption.DisabledReason = "every suggestion is shown"
+	}
+	menuOptions := []style.MenuOption{
+		{Number: 1, Title: "Run Lynis scan", Description: "lynis audit system (takes a few minutes)"},
+		allOption,
+		{Number: 3, Title: "Insecure packages", Description: "Remove telnet, rsh, avahi and the rest of debloat.packages"},
+	}
+
+	menu := style.NewMenu("Select an option", menuOptions)
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return to main menu",
+		Description: "",
+	})
+	menu.Print()
+
+	choice := ReadMenuInput()
+	if choice == "q" {
+		return
+	}
+
+	if rejectDisabledOption(menu, choice) {
+		m.Show()
+		return
+	}
+
+	switch choice {
+	case "1":
+		// The scan only reads the system, so it runs in dry-run mode too
+		fmt.Printf("\n%s Running Lynis scan, this takes a few minutes...\n", style.BulletItem)
+		if _, err := m.menuManager.RunLynisScan(); err != nil {
+			fmt.Printf("\n%s Lynis scan failed: %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+			fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+			ReadKey()
+		}
+		m.Show()
+
+	case "2":
+		fmt.Println()
+		m.printReport(report, 0)
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		m.Show()
+
+	case "3":
+		debloatMenu := NewDebloatMenu(m.menuManager, m.config)
+		debloatMenu.Show()
+		m.Show()
+
+	case "0":
+		return
+
+	default:
+		fmt.Printf("\n%s Invalid option. Please try again.\n", style.Colored(style.Red, style.SymCrossMark))
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		m.Show()
+	}
+}
+
+// printReport prints the hardening index, every warning and up to limit
+// suggestions; a limit of zero prints them all
+func (m *SecurityScanMenu) printReport(report *model.LynisReport, limit int) {
+	formatter := style.NewStatusFormatter([]string{"Hardening Index", "Tests", "Last Scan"}, 2)
+
+	indexColor := style.Green
+	switch {
+	case report.HardeningIndex < 50:
+		indexColor = style.Red
+	case report.HardeningIndex < 75:
+		indexColor = style.Yellow
+	}
+	fmt.Println(formatter.FormatLine(style.SymInfo, style.Cyan, "Hardening Index",
+		fmt.Sprintf("%d/100", report.HardeningIndex), indexColor, "", "bold"))
+	fmt.Println(formatter.FormatLine(style.SymInfo, style.Cyan, "Tests",
+		fmt.Sprintf("%d", report.TestsPerformed), style.Cyan, ""))
+	if !report.Finished.IsZero() {
+		fmt.Println(formatter.FormatLine(style.SymInfo, style.Cyan, "Last Scan",
+			report.Finished.Format("2006-01-02 15:04"), style.Cyan, ""))
+	}
+
+	fmt.Println(style.Bolded(fmt.Sprintf("\nWarnings (%d):", len(report.Warnings)), style.Blue))
+	if len(report.Warnings) == 0 {
+		fmt.Println(style.Dimmed("  None"))
+	}
+	for _, finding := range report.Warnings {
+		printLynisFinding(style.Colored(style.Red, style.SymWarning), finding)
+	}
+
+	fmt.Println(style.Bolded(fmt.Sprintf("\nSuggestions (%d):", len(report.Suggestions)), style.Blue))
+	if len(report.Suggestions) == 0 {
+		fmt.Println(style.Dimmed("  None"))
+	}
+	for i, finding := range report.Suggestions {
+		if limit > 0 && i == limit {
+			fmt.Println(style.Dimmed(fmt.Sprintf("  ... and %d more", len(report.Suggestions)-limit)))
+			break
+		}
+		printLynisFinding(style.Colored(style.Yellow, style.SymInfo), finding)
+	}
+}
+
+// printLynisFinding prints a warning or suggestion with its test ID
+func printLynisFinding(symbol string, finding model.LynisFinding) {
+	line := fmt.Sprintf("  %s %s %s", symbol, finding.Message, style.Dimmed("["+finding.TestID+"]"))
+	if finding.Details != "" {
+		line += style.Dimmed(" " + finding.Details)
+	}
+	fmt.Println(line)
+}
@@ -0,0 +1,52 @@
+// pkg/menu/session.go
+package menu
+
+import (
+	"strings"
+
+	"github.com/abbott/hardn/pkg/session"
+)
+
+var (
+	// sessionRecorder notes every input when a session is being recorded
+	sessionRecorder *session.Recorder
+
+	// sessionPlayer answers input prompts when a session is being replayed
+	sessionPlayer *session.Player
+)
+
+// SetSessionRecorder records menu inputs, and the screen every event happens
+// on, to recorder
+func SetSessionRecorder(recorder *session.Recorder) {
+	sessionRecorder = recorder
+	if recorder != nil {
+		recorder.SetScreen(currentScreen)
+	}
+}
+
+// SetSessionPlayer answers input prompts from a recorded session instead of
+// the terminal
+func SetSessionPlayer(player *session.Player) {
+	sessionPlayer = player
+}
+
+// currentScreen describes the current screen in transcripts
+func currentScreen() string {
+	return strings.Join(breadcrumbPath(), " > ")
+}
+
+// sessionInput returns the input for a prompt, reading from the terminal
+// with read unless a session is being replayed
+func sessionInput(kind string, read func() string) string {
+	var value string
+	if sessionPlayer != nil {
+		value = sessionPlayer.NextInput(kind, currentScreen())
+	} else {
+		value = read()
+	}
+
+	if sessionRecorder != nil {
+		sessionRecorder.Record(session.Event{Kind: session.KindInput, Input: kind, Value: value})
+	}
+	return value
+}
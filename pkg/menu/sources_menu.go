@@ -4,14 +4,13 @@ package menu
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/abbott/hardn/pkg/application"
 	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
 	"github.com/abbott/hardn/pkg/osdetect"
 	"github.com/abbott/hardn/pkg/style"
-	"github.com/abbott/hardn/pkg/utils"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -38,8 +37,7 @@ func NewSourcesMenu(
 
 // Show displays the sources menu and handles user input
 func (m *SourcesMenu) Show() {
-	utils.PrintHeader()
-	fmt.Println(style.Bolded("Package Sources Configuration", style.Blue))
+	defer enterScreen("Package Sources")()
 
 	// Display current OS info
 	fmt.Println()
@@ -118,13 +116,12 @@ func (m *SourcesMenu) Show() {
 		})
 
 		// Proxmox specific options
-		if m.osInfo.IsProxmox {
-			menuOptions = append(menuOptions, style.MenuOption{
-				Number:      2,
-				Title:       "Configure Proxmox repositories",
-				Description: "Set up Proxmox-specific repositories",
-			})
-		}
+		menuOptions = append(menuOptions, style.MenuOption{
+			Number:         2,
+			Title:          "Configure Proxmox repositories",
+			Description:    "Set up Proxmox-specific repositories",
+			DisabledReason: proxmoxReason(m.osInfo),
+		})
 
 		// Add option to edit sources
 		menuOptions = append(menuOptions, style.MenuOption{
@@ -152,23 +149,23 @@ func (m *SourcesMenu) Show() {
 		return
 	}
 
+	if rejectDisabledOption(menu, choice) {
+		m.Show()
+		return
+	}
+
 	switch choice {
 	case "1":
 		// Update main repositories
 		fmt.Println("\nUpdating package sources...")
 
-		if m.config.DryRun {
-			fmt.Printf("%s [DRY-RUN] Would update package sources for %s\n",
-				style.BulletItem, m.osInfo.OsType)
-		} else {
-			// Use application layer to update sources
-			if err := m.menuManager.UpdatePackageSources(); err != nil {
-				fmt.Printf("\n%s Failed to update package sources: %v\n",
-					style.Colored(style.Red, style.SymCrossMark), err)
-			} else {
-				fmt.Printf("\n%s Package sources updated successfully\n",
-					style.Colored(style.Green, style.SymCheckMark))
-			}
+		// Use application layer to update sources; dry runs print the diff
+		if err := m.menuManager.UpdatePackageSources(); err != nil {
+			fmt.Printf("\n%s Failed to update package sources: %v\n",
+				style.Colored(style.Red, style.SymCrossMark), err)
+		} else if !m.config.DryRun {
+			fmt.Printf("\n%s Package sources updated successfully\n",
+				style.Colored(style.Green, style.SymCheckMark))
 		}
 
 	case "2":
@@ -191,34 +188,28 @@ func (m *SourcesMenu) Show() {
 			// Save config changes
 			m.saveSourcesConfig()
 
-			// Apply the change
-			if !m.config.DryRun {
-				// Use application layer to update sources
-				if err := m.menuManager.UpdatePackageSources(); err != nil {
-					fmt.Printf("\n%s Failed to update package sources: %v\n",
-						style.Colored(style.Red, style.SymCrossMark), err)
-				} else {
-					fmt.Printf("%s Package sources updated successfully\n",
-						style.Colored(style.Green, style.SymCheckMark))
-				}
+			// Apply the change; dry runs print the diff
+			if err := m.menuManager.UpdatePackageSources(); err != nil {
+				fmt.Printf("\n%s Failed to update package sources: %v\n",
+					style.Colored(style.Red, style.SymCrossMark), err)
+			} else if !m.config.DryRun {
+				fmt.Printf("%s Package sources updated successfully\n",
+					style.Colored(style.Green, style.SymCheckMark))
 			}
 		} else if m.osInfo.IsProxmox {
 			// Configure Proxmox repositories
 			fmt.Println("\nConfiguring Proxmox repositories...")
 
-			if m.config.DryRun {
-				fmt.Printf("%s [DRY-RUN] Would configure Proxmox repositories\n", style.BulletItem)
-			} else {
-				// Use application layer to update Proxmox sources
-				if err := m.menuManager.UpdateProxmoxSources(); err != nil {
-					fmt.Printf("\n%s Failed to configure Proxmox repositories: %v\n",
-						style.Colored(style.Red, style.SymCrossMark), err)
-				} else {
-					fmt.Printf("\n%s Proxmox repositories configured successfully\n",
-						style.Colored(style.Green, style.SymCheckMark))
-					fmt.Printf("%s Created /etc/apt/sources.list.d/ceph.list\n", style.BulletItem)
-					fmt.Printf("%s Created /etc/apt/sources.list.d/pve-enterprise.list\n", style.BulletItem)
-				}
+			// Use application layer to update Proxmox sources; dry runs print the diff
+			if err := m.menuManager.UpdateProxmoxSources(); err != nil {
+				fmt.Printf("\n%s Failed to configure Proxmox repositories: %v\n",
+					style.Colored(style.Red, style.SymCrossMark), err)
+			} else if !m.config.DryRun {
+				fmt.Printf("\n%s Proxmox repositories configured successfully\n",
+					style.Colored(style.Green, style.SymCheckMark))
+				format := m.sourcesFormat()
+				fmt.Printf("%s Created %s\n", style.BulletItem, model.AptListPath("ceph", format))
+				fmt.Printf("%s Created %s\n", style.BulletItem, model.AptListPath("pve-enterprise", format))
 			}
 		} else {
 			fmt.Printf("\n%s Invalid option for this OS type\n",
@@ -260,7 +251,7 @@ func (m *SourcesMenu) showAlpineRepositories() {
 	reposFile := "/etc/apk/repositories"
 	reposContent := ""
 
-	if data, err := os.ReadFile(reposFile); err == nil {
+	if data, err := m.menuManager.ReadSourceFile(reposFile); err == nil {
 		reposContent = string(data)
 	}
 
@@ -300,24 +291,16 @@ func (m *SourcesMenu) showAlpineRepositories() {
 
 // Helper function to show Debian/Ubuntu repositories
 func (m *SourcesMenu) showDebianRepositories() {
-	// Check if sources file exists
-	sourcesFile := "/etc/apt/sources.list"
-	sourcesContent := ""
-
-	if data, err := os.ReadFile(sourcesFile); err == nil {
-		sourcesContent = string(data)
+	// Main sources are in sources.list or, in deb822 format, the release .sources file
+	sourcesFile := model.AptSourcesListPath
+	if m.sourcesFormat() == model.SourcesFormatDeb822 {
+		sourcesFile = model.AptReleaseSourcesPath(m.osInfo.OsType)
 	}
 
 	// Show main sources
-	if sourcesContent != "" {
+	if lines, ok := m.readSourceLines(sourcesFile); ok {
 		fmt.Printf("%s %s:\n", style.BulletItem, style.Bolded("Main sources", style.Cyan))
-		lines := strings.Split(sourcesContent, "\n")
 		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if line == "" || strings.HasPrefix(line, "#") {
-				continue
-			}
-
 			fmt.Printf("   %s\n", line)
 		}
 	} else {
@@ -330,17 +313,11 @@ func (m *SourcesMenu) showDebianRepositories() {
 		fmt.Println()
 		fmt.Printf("%s %s:\n", style.BulletItem, style.Bolded("Proxmox repositories", style.Cyan))
 
+		format := m.sourcesFormat()
+
 		// Check Ceph repo
-		cephFile := "/etc/apt/sources.list.d/ceph.list"
-		if data, err := os.ReadFile(cephFile); err == nil {
-			cephContent := string(data)
-			lines := strings.Split(cephContent, "\n")
+		if lines, ok := m.readSourceLines(model.AptListPath("ceph", format)); ok {
 			for _, line := range lines {
-				line = strings.TrimSpace(line)
-				if line == "" || strings.HasPrefix(line, "#") {
-					continue
-				}
-
 				fmt.Printf("   %s\n", line)
 			}
 		} else {
@@ -349,16 +326,8 @@ func (m *SourcesMenu) showDebianRepositories() {
 		}
 
 		// Check Enterprise repo
-		pveFile := "/etc/apt/sources.list.d/pve-enterprise.list"
-		if data, err := os.ReadFile(pveFile); err == nil {
-			pveContent := string(data)
-			lines := strings.Split(pveContent, "\n")
+		if lines, ok := m.readSourceLines(model.AptListPath("pve-enterprise", format)); ok {
 			for _, line := range lines {
-				line = strings.TrimSpace(line)
-				if line == "" || strings.HasPrefix(line, "#") {
-					continue
-				}
-
 				fmt.Printf("   %s\n", line)
 			}
 		} else {
@@ -420,10 +389,41 @@ func (m *SourcesMenu) showDebianRepositories() {
 	}
 }
 
+// sourcesFormat returns the format repositories are written in, list
+// unless deb822 is configured or found on the host
+func (m *SourcesMenu) sourcesFormat() string {
+	format, err := m.menuManager.SourcesFormat()
+	if err != nil {
+		return model.SourcesFormatList
+	}
+	return format
+}
+
+// readSourceLines reads the enabled repositories of a list as one-line
+// entries, whatever its format
+func (m *SourcesMenu) readSourceLines(path string) ([]string, bool) {
+	data, err := m.menuManager.ReadSourceFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var lines []string
+	for _, source := range model.ParseAptSourcesFile(model.AptFile{Path: path, Data: data}, false) {
+		if !source.Enabled {
+			continue
+		}
+		// An inline key has no one-line form; show where the repository is
+		entries, err := source.Lines()
+		if err != nil {
+			entries = []string{strings.Join(source.Types, " ") + " " + strings.Join(source.URIs, " ") + " " + strings.Join(source.Suites, " ")}
+		}
+		lines = append(lines, entries...)
+	}
+	return lines, true
+}
+
 // Helper function to edit repositories
 func (m *SourcesMenu) editRepositoriesMenu() {
-	utils.PrintHeader()
-	fmt.Println(style.Bolded("Edit Repositories", style.Blue))
+	defer enterScreen("Repositories")()
 
 	// Create menu options
 	var menuOptions []style.MenuOption
@@ -435,22 +435,24 @@ func (m *SourcesMenu) editRepositoriesMenu() {
 		Description: "Add a new repository to configuration",
 	})
 
-	if len(m.config.DebianRepos) > 0 {
-		menuOptions = append(menuOptions, style.MenuOption{
-			Number:      2,
-			Title:       "Remove repository",
-			Description: "Remove a repository from configuration",
-		})
+	noReposReason := ""
+	if len(m.config.DebianRepos) == 0 {
+		noReposReason = "no repositories configured"
 	}
+	menuOptions = append(menuOptions, style.MenuOption{
+		Number:         2,
+		Title:          "Remove repository",
+		Description:    "Remove a repository from configuration",
+		DisabledReason: noReposReason,
+	})
 
 	// Proxmox specific options
-	if m.osInfo.IsProxmox {
-		menuOptions = append(menuOptions, style.MenuOption{
-			Number:      3,
-			Title:       "Edit Proxmox repositories",
-			Description: "Modify Proxmox-specific repositories",
-		})
-	}
+	menuOptions = append(menuOptions, style.MenuOption{
+		Number:         3,
+		Title:          "Edit Proxmox repositories",
+		Description:    "Modify Proxmox-specific repositories",
+		DisabledReason: proxmoxReason(m.osInfo),
+	})
 
 	// Create menu
 	menu := style.NewMenu("Select an option", menuOptions)
@@ -470,6 +472,11 @@ func (m *SourcesMenu) editRepositoriesMenu() {
 		return
 	}
 
+	if rejectDisabledOption(menu, choice) {
+		m.editRepositoriesMenu()
+		return
+	}
+
 	switch choice {
 	case "1":
 		// Add repository
@@ -589,8 +596,7 @@ func (m *SourcesMenu) editRepositoriesMenu() {
 
 // Helper function to edit Proxmox repositories
 func (m *SourcesMenu) editProxmoxRepositoriesMenu() {
-	utils.PrintHeader()
-	fmt.Println(style.Bolded("Edit Proxmox Repositories", style.Blue))
+	defer enterScreen("Proxmox")()
 
 	// Create menu options
 	menuOptions := []style.MenuOption{
@@ -621,21 +627,21 @@ func (m *SourcesMenu) editProxmoxRepositoriesMenu() {
 	case "1":
 		// Edit source repositories
 		m.editProxmoxRepoList("source",
-			"Proxmox Source Repositories", &m.config.ProxmoxSrcRepos)
+			"Source", &m.config.ProxmoxSrcRepos)
 		m.editProxmoxRepositoriesMenu()
 		return
 
 	case "2":
 		// Edit Ceph repositories
 		m.editProxmoxRepoList("ceph",
-			"Proxmox Ceph Repositories", &m.config.ProxmoxCephRepo)
+			"Ceph", &m.config.ProxmoxCephRepo)
 		m.editProxmoxRepositoriesMenu()
 		return
 
 	case "3":
 		// Edit Enterprise repositories
 		m.editProxmoxRepoList("enterprise",
-			"Proxmox Enterprise Repositories", &m.config.ProxmoxEnterpriseRepo)
+			"Enterprise", &m.config.ProxmoxEnterpriseRepo)
 		m.editProxmoxRepositoriesMenu()
 		return
 
@@ -658,8 +664,7 @@ func (m *SourcesMenu) editProxmoxRepositoriesMenu() {
 func (m *SourcesMenu) editProxmoxRepoList(
 	repoType, title string, repoList *[]string) {
 
-	utils.PrintHeader()
-	fmt.Println(style.Bolded(title, style.Blue))
+	defer enterScreen(title)()
 
 	// Display current repositories
 	fmt.Println()
@@ -713,6 +718,11 @@ func (m *SourcesMenu) editProxmoxRepoList(
 		return
 	}
 
+	if rejectDisabledOption(menu, choice) {
+		m.editRepositoriesMenu()
+		return
+	}
+
 	switch choice {
 	case "1":
 		// Add repository
@@ -0,0 +1,152 @@
+// pkg/menu/ssh_effective_menu.go
+package menu
+
+import (
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// SSHEffectiveMenu compares hardn's SSH policy with the configuration sshd
+// actually runs with
+type SSHEffectiveMenu struct {
+	menuManager *application.MenuManager
+	config      *config.Config
+	showSources bool
+}
+
+// NewSSHEffectiveMenu creates a new SSHEffectiveMenu
+func NewSSHEffectiveMenu(
+	menuManager *application.MenuManager,
+	config *config.Config,
+) *SSHEffectiveMenu {
+	return &SSHEffectiveMenu{
+		menuManager: menuManager,
+		config:      config,
+	}
+}
+
+// Show displays the comparison and handles user input
+func (m *SSHEffectiveMenu) Show() {
+	defer enterScreen("Effective Configuration")()
+
+	report, err := m.menuManager.CompareEffectiveSSHConfig(
+		m.config.SshPort,
+		m.config.SshListenAddresses,
+		m.config.SshAllowedUsers,
+		m.config.Username,
+		m.config.SshAllowAllUsers,
+		m.config.SshProfile,
+	)
+	if err != nil {
+		fmt.Printf("\n%s Failed to compare the SSH configuration: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		fmt.Printf("\n%s Press any key to return...", style.BulletItem)
+		ReadKey()
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("%s Values from %s compared with hardn's policy in %s\n",
+		style.BulletItem, style.Bolded("sshd -T"), report.ManagedFile)
+
+	mismatches := report.Mismatches()
+	if len(mismatches) == 0 {
+		fmt.Printf("%s All %d directives take effect\n",
+			style.Colored(style.Green, style.SymCheckMark), len(report.Directives))
+	} else {
+		fmt.Printf("%s %d of %d directives are not in effect\n",
+			style.Colored(style.Yellow, style.SymWarning), len(mismatches), len(report.Directives))
+	}
+
+	labels := make([]string, 0, len(report.Directives))
+	for _, directive := range report.Directives {
+		labels = append(labels, directive.Directive)
+	}
+	formatter := style.NewStatusFormatter(labels, 2)
+
+	fmt.Println()
+	for _, directive := range report.Directives {
+		effective := directive.Effective
+		if effective == "" {
+			effective = "(not reported)"
+		}
+
+		if directive.Matches {
+			fmt.Println(formatter.FormatSuccess(directive.Directive, effective, ""))
+		} else {
+			fmt.Println(formatter.FormatWarning(directive.Directive, effective, "hardn: "+directive.Intended))
+		}
+
+		if directive.OverriddenBy != nil && !directive.Matches {
+			fmt.Printf("      %s %s\n", style.Colored(style.Red, style.SymArrowRight),
+				style.Colored(style.Red, "overridden by "+sourceLocation(*directive.OverriddenBy)))
+		}
+
+		if m.showSources || !directive.Matches {
+			for _, source := range directive.Sources {
+				fmt.Printf("      %s\n", style.Dimmed(sourceLocation(source)))
+			}
+		}
+	}
+
+	if overridden(mismatches) {
+		fmt.Println(style.Dimmed("\nsshd uses the first value it reads for most directives; drop-ins in"))
+		fmt.Println(style.Dimmed("sshd_config.d are read in name order, so 50-cloud-init.conf comes before hardn.conf."))
+	}
+
+	// Create menu options
+	sourcesTitle := "Show where every directive is set"
+	if m.showSources {
+		sourcesTitle = "Show sources for mismatches only"
+	}
+	menuOptions := []style.MenuOption{
+		{Number: 1, Title: sourcesTitle, Description: "Files and lines read by sshd, in order"},
+		{Number: 2, Title: "Refresh", Description: "Run sshd -T again"},
+	}
+
+	menu := style.NewMenu("Select an option", menuOptions)
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return",
+		Description: "",
+	})
+
+	menu.Print()
+
+	choice := ReadMenuInput()
+
+	switch choice {
+	case "1":
+		m.showSources = !m.showSources
+		m.Show()
+	case "2":
+		m.Show()
+	case "0", "q":
+		return
+	default:
+		fmt.Printf("\n%s Invalid option. Please try again.\n",
+			style.Colored(style.Red, style.SymCrossMark))
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		m.Show()
+	}
+}
+
+// overridden reports whether another file set any of the directives first
+func overridden(directives []model.SSHDirectiveDiff) bool {
+	for _, directive := range directives {
+		if directive.OverriddenBy != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceLocation formats a configuration line as file:line value
+func sourceLocation(source model.SSHDirectiveSource) string {
+	return fmt.Sprintf("%s:%d %s", source.File, source.Line, source.Value)
+}
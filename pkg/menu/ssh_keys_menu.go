@@ -7,12 +7,11 @@ import (
 
 	"github.com/abbott/hardn/pkg/config"
 	"github.com/abbott/hardn/pkg/style"
-	"github.com/abbott/hardn/pkg/utils"
 )
 
 // Initialize SSH Key Management display
 func (m *UserMenu) SSHKeysMenu() {
-	utils.ClearScreen()
+	defer enterScreen("SSH Keys")()
 
 	// Initialize status formatter w/specific fields for consistency
 	formatter := style.NewStatusFormatter([]string{
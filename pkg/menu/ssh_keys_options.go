@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
 	"github.com/abbott/hardn/pkg/style"
 )
 
@@ -28,6 +29,12 @@ func (m *UserMenu) HandleSSHKeysOptions() bool {
 		})
 	}
 
+	menuOptions = append(menuOptions, style.MenuOption{
+		Number:      3,
+		Title:       "Import keys from GitHub/GitLab",
+		Description: "Fetch the keys an account publishes",
+	})
+
 	// Create menu
 	menu := style.NewMenu("Select an option", menuOptions)
 	menu.SetExitOption(style.MenuOption{
@@ -75,11 +82,13 @@ func (m *UserMenu) HandleSSHKeysOptions() bool {
 				if m.config.Username != "" {
 					_, err := osuser.Lookup(m.config.Username)
 					if err == nil {
-						err = m.menuManager.AddSSHKey(m.config.Username, newKey)
-						if err != nil {
+						if m.config.DryRun {
+							fmt.Printf("%s [DRY-RUN] Would add key to user '%s'\n",
+								style.BulletItem, m.config.Username)
+						} else if err := m.menuManager.AddSSHKey(m.config.Username, newKey); err != nil {
 							fmt.Printf("\n%s Failed to add SSH key to user: %v\n",
 								style.Colored(style.Yellow, style.SymWarning), err)
-						} else if !m.config.DryRun {
+						} else {
 							fmt.Printf("%s Key added to user '%s'\n",
 								style.BulletItem, m.config.Username)
 						}
@@ -126,6 +135,26 @@ func (m *UserMenu) HandleSSHKeysOptions() bool {
 			fmt.Printf("\n%s SSH key %d removed successfully\n",
 				style.Colored(style.Green, style.SymCheckMark), keyNum)
 
+			// If user already exists, remove key from user as well
+			if m.config.Username != "" {
+				if _, err := osuser.Lookup(m.config.Username); err == nil {
+					fingerprint, err := m.menuManager.SSHKeyFingerprint(removedKey)
+					if err != nil {
+						fmt.Printf("%s Key not removed from user '%s': %v\n",
+							style.Colored(style.Yellow, style.SymWarning), m.config.Username, err)
+					} else if m.config.DryRun {
+						fmt.Printf("%s [DRY-RUN] Would remove key %s from user '%s'\n",
+							style.BulletItem, fingerprint, m.config.Username)
+					} else if err := m.menuManager.RemoveSSHKey(m.config.Username, fingerprint); err != nil {
+						fmt.Printf("%s Failed to remove SSH key from user: %v\n",
+							style.Colored(style.Yellow, style.SymWarning), err)
+					} else {
+						fmt.Printf("%s Key removed from user '%s'\n",
+							style.BulletItem, m.config.Username)
+					}
+				}
+			}
+
 			// Show truncated key that was removed
 			if len(removedKey) > 30 {
 				removedKey = removedKey[:15] + "..." + removedKey[len(removedKey)-15:]
@@ -146,6 +175,14 @@ func (m *UserMenu) HandleSSHKeysOptions() bool {
 		ReadKey()
 		return true // Continue showing the SSH keys menu
 
+	case "3":
+		m.importProviderSSHKeys()
+
+		// Wait for key press before continuing
+		style.PressAnyKey()
+		ReadKey()
+		return true // Continue showing the SSH keys menu
+
 	default:
 		fmt.Printf("\n%s Invalid option. Please try again.\n",
 			style.Colored(style.Red, style.SymCrossMark))
@@ -156,3 +193,104 @@ func (m *UserMenu) HandleSSHKeysOptions() bool {
 		return true // Continue showing the SSH keys menu
 	}
 }
+
+// importProviderSSHKeys previews and imports the keys a GitHub or GitLab
+// account publishes, adding them to the configuration and, when the user
+// exists, to their authorized_keys
+func (m *UserMenu) importProviderSSHKeys() {
+	if m.config.Username == "" {
+		fmt.Printf("\n%s Set a username before importing keys\n",
+			style.Colored(style.Yellow, style.SymWarning))
+		return
+	}
+
+	fmt.Printf("\n%s Import from 1) GitHub or 2) GitLab [1]: ", style.BulletItem)
+	provider := model.SSHKeyProviderGitHub
+	switch strings.TrimSpace(ReadInput()) {
+	case "", "1":
+	case "2":
+		provider = model.SSHKeyProviderGitLab
+	default:
+		fmt.Printf("\n%s Invalid choice\n", style.Colored(style.Red, style.SymCrossMark))
+		return
+	}
+
+	fmt.Printf("%s %s username: ", style.BulletItem, provider)
+	account := strings.TrimSpace(ReadInput())
+	if account == "" {
+		return
+	}
+
+	// Preview first; nothing is written yet
+	fmt.Printf("\n%s Fetching keys for %s:%s...\n", style.BulletItem, provider, account)
+	preview, err := m.menuManager.ImportSSHKeysFromProvider(m.config.Username, provider, account, true)
+	if err != nil {
+		fmt.Printf("\n%s %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+		return
+	}
+
+	fmt.Println()
+	for _, key := range preview.Added {
+		fmt.Printf("%s New: %s %s\n", style.Colored(style.Green, "+"), key.KeyType, key.Fingerprint)
+	}
+	for _, key := range preview.Present {
+		fmt.Printf("%s Already authorized: %s %s\n", style.BulletItem, key.KeyType, key.Fingerprint)
+	}
+	for _, finding := range preview.Rejected {
+		fmt.Printf("%s Refused: %s %s\n", style.Colored(style.Red, style.SymCrossMark), finding.Fingerprint, finding.Detail)
+	}
+	if preview.Invalid > 0 {
+		fmt.Printf("%s Skipped %d line(s) that are not public keys\n",
+			style.Colored(style.Yellow, style.SymWarning), preview.Invalid)
+	}
+
+	if len(preview.Added) == 0 {
+		fmt.Printf("\n%s No new keys to import\n", style.BulletItem)
+		return
+	}
+
+	if m.config.DryRun {
+		fmt.Printf("\n%s [DRY-RUN] Would add %d key(s) for '%s'\n",
+			style.BulletItem, len(preview.Added), m.config.Username)
+		return
+	}
+
+	fmt.Printf("\n%s Add %d key(s) for '%s'? (y/n): ",
+		style.Colored(style.Yellow, style.SymWarning), len(preview.Added), m.config.Username)
+	confirm := strings.ToLower(ReadInput())
+	if confirm != "y" && confirm != "yes" {
+		fmt.Println("\nImport cancelled.")
+		return
+	}
+
+	lines := preview.Lines
+	if _, err := osuser.Lookup(m.config.Username); err == nil {
+		result, err := m.menuManager.ImportSSHKeysFromProvider(m.config.Username, provider, account, false)
+		if err != nil {
+			fmt.Printf("\n%s Failed to import keys: %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+			return
+		}
+		lines = result.Lines
+		fmt.Printf("\n%s Added %d key(s) to user '%s'\n",
+			style.Colored(style.Green, style.SymCheckMark), len(lines), m.config.Username)
+	}
+
+	// Keep the configuration in step so the keys are applied on user creation
+	configured := make(map[string]bool)
+	for _, key := range m.config.SshKeys {
+		if fingerprint, err := model.SSHKeyFingerprint(key); err == nil {
+			configured[fingerprint] = true
+		}
+	}
+	for _, line := range lines {
+		if fingerprint, err := model.SSHKeyFingerprint(line); err == nil && !configured[fingerprint] {
+			m.config.SshKeys = append(m.config.SshKeys, line)
+		}
+	}
+	if err := config.SaveConfig(m.config, "hardn.yml"); err != nil {
+		fmt.Printf("\n%s Failed to save configuration: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		return
+	}
+	fmt.Printf("%s Keys saved to the configuration\n", style.BulletItem)
+}
@@ -0,0 +1,238 @@
+// pkg/menu/ssh_listen_menu.go
+package menu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// SSHListenMenu handles SSH listen address configuration
+type SSHListenMenu struct {
+	menuManager *application.MenuManager
+	config      *config.Config
+	osInfo      *osdetect.OSInfo
+}
+
+// NewSSHListenMenu creates a new SSHListenMenu
+func NewSSHListenMenu(
+	menuManager *application.MenuManager,
+	config *config.Config,
+	osInfo *osdetect.OSInfo,
+) *SSHListenMenu {
+	return &SSHListenMenu{
+		menuManager: menuManager,
+		config:      config,
+		osInfo:      osInfo,
+	}
+}
+
+// Show displays the listen address menu and handles user input
+func (m *SSHListenMenu) Show() {
+	defer enterScreen("Listen Addresses")()
+
+	// Display configured addresses
+	fmt.Println()
+	fmt.Println(style.Bolded("Configured Listen Addresses:", style.Blue))
+
+	if len(m.config.SshListenAddresses) == 0 {
+		fmt.Printf("%s No listen addresses configured (sshd listens on all interfaces)\n",
+			style.Colored(style.Yellow, style.SymWarning))
+	} else {
+		for i, addr := range m.config.SshListenAddresses {
+			if m.menuManager.IsWildcardSSHListenAddress(addr) {
+				fmt.Printf("%s Address %d: %s %s\n", style.Colored(style.Yellow, style.SymWarning), i+1,
+					style.Colored(style.Cyan, addr), style.Dimmed("(all interfaces)"))
+			} else {
+				fmt.Printf("%s Address %d: %s\n", style.BulletItem, i+1, style.Colored(style.Cyan, addr))
+			}
+		}
+	}
+
+	// Create menu options
+	menuOptions := []style.MenuOption{
+		{Number: 1, Title: "Apply listen addresses", Description: "Write ListenAddress directives and restart SSH"},
+		{Number: 2, Title: "Add listen address", Description: "Add an IPv4 or IPv6 address"},
+	}
+
+	// Add remove option if addresses exist
+	if len(m.config.SshListenAddresses) > 0 {
+		menuOptions = append(menuOptions, style.MenuOption{
+			Number:      3,
+			Title:       "Remove listen address",
+			Description: "Remove an address from configuration",
+		})
+	}
+
+	// Create menu
+	menu := style.NewMenu("Select an option", menuOptions)
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return",
+		Description: "",
+	})
+
+	// Display menu
+	menu.Print()
+
+	choice := ReadMenuInput()
+
+	// Handle 'q' as a special exit case
+	if choice == "q" {
+		return
+	}
+
+	switch choice {
+	case "1":
+		m.applyListenAddresses()
+
+	case "2":
+		m.addListenAddress()
+		m.Show()
+		return
+
+	case "3":
+		if len(m.config.SshListenAddresses) == 0 {
+			fmt.Printf("\n%s No listen addresses to remove\n",
+				style.Colored(style.Yellow, style.SymWarning))
+		} else {
+			m.removeListenAddress()
+		}
+
+		m.Show()
+		return
+
+	case "0":
+		return
+
+	default:
+		fmt.Printf("\n%s Invalid option. Please try again.\n",
+			style.Colored(style.Red, style.SymCrossMark))
+
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		m.Show()
+		return
+	}
+
+	fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+	ReadKey()
+	m.Show()
+}
+
+// applyListenAddresses writes the configured addresses to the SSH configuration
+func (m *SSHListenMenu) applyListenAddresses() {
+	fmt.Println("\nApplying SSH listen addresses...")
+
+	// In dry-run mode the change is printed as a diff instead
+	err := m.menuManager.ConfigureSSH(
+		m.config.SshPort,
+		m.config.SshListenAddresses,
+		m.config.PermitRootLogin,
+		m.config.SshAllowedUsers,
+		[]string{m.config.SshKeyPath},
+		m.config.Username,
+		m.config.SshAllowAllUsers,
+	)
+	if err != nil {
+		fmt.Printf("\n%s Failed to apply listen addresses: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		return
+	}
+	if m.config.DryRun {
+		return
+	}
+
+	fmt.Printf("\n%s SSH now listening on: %s\n",
+		style.Colored(style.Green, style.SymCheckMark), strings.Join(m.config.SshListenAddresses, ", "))
+}
+
+// addListenAddress handles adding a new listen address
+func (m *SSHListenMenu) addListenAddress() {
+	fmt.Printf("\n%s Enter listen address (e.g., 192.168.1.10, ::1 or [fe80::1]:2222): ", style.BulletItem)
+	addr := strings.TrimSpace(ReadInput())
+
+	if err := m.menuManager.ValidateSSHListenAddress(addr); err != nil {
+		fmt.Printf("\n%s %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		return
+	}
+
+	// Check for duplicate
+	for _, existing := range m.config.SshListenAddresses {
+		if existing == addr {
+			fmt.Printf("\n%s Listen address %s is already configured\n",
+				style.Colored(style.Yellow, style.SymWarning), addr)
+			fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+			ReadKey()
+			return
+		}
+	}
+
+	// Warn before exposing SSH on every interface
+	if m.menuManager.IsWildcardSSHListenAddress(addr) {
+		fmt.Printf("\n%s %s listens on all interfaces, exposing SSH to every network this host is on.\n",
+			style.Colored(style.Yellow, style.SymWarning), addr)
+		fmt.Printf("%s Add it anyway? (y/n): ", style.BulletItem)
+		confirm := ReadInput()
+		if strings.ToLower(confirm) != "y" && strings.ToLower(confirm) != "yes" {
+			return
+		}
+	}
+
+	m.config.SshListenAddresses = append(m.config.SshListenAddresses, addr)
+
+	// Save config
+	if err := config.SaveConfig(m.config, "hardn.yml"); err != nil {
+		fmt.Printf("\n%s Failed to save configuration: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		return
+	}
+
+	fmt.Printf("\n%s Listen address %s added to configuration\n",
+		style.Colored(style.Green, style.SymCheckMark), addr)
+}
+
+// removeListenAddress handles removing a listen address
+func (m *SSHListenMenu) removeListenAddress() {
+	fmt.Println()
+	for i, addr := range m.config.SshListenAddresses {
+		fmt.Printf("%s %d: %s\n", style.BulletItem, i+1, addr)
+	}
+
+	fmt.Printf("\n%s Enter address number to remove (1-%d): ",
+		style.BulletItem, len(m.config.SshListenAddresses))
+	numStr := ReadInput()
+
+	// Parse number
+	num := 0
+	n, err := fmt.Sscanf(numStr, "%d", &num)
+	if err != nil || n != 1 || num < 1 || num > len(m.config.SshListenAddresses) {
+		fmt.Printf("\n%s Invalid address number\n",
+			style.Colored(style.Red, style.SymCrossMark))
+		return
+	}
+
+	removed := m.config.SshListenAddresses[num-1]
+	m.config.SshListenAddresses = append(m.config.SshListenAddresses[:num-1], m.config.SshListenAddresses[num:]...)
+
+	if len(m.config.SshListenAddresses) == 0 {
+		fmt.Printf("\n%s No listen addresses remain; sshd will listen on all interfaces\n",
+			style.Colored(style.Yellow, style.SymWarning))
+	}
+
+	// Save config
+	if err := config.SaveConfig(m.config, "hardn.yml"); err != nil {
+		fmt.Printf("\n%s Failed to save configuration: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		return
+	}
+
+	fmt.Printf("\n%s Listen address %s removed from configuration\n",
+		style.Colored(style.Green, style.SymCheckMark), removed)
+}
@@ -0,0 +1,144 @@
+// pkg/menu/ssh_profile_menu.go
+package menu
+
+import (
+	"fmt"
+
+	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/style"
+)
+
+// SSHProfileMenu handles selecting the SSH hardening profile
+type SSHProfileMenu struct {
+	menuManager *application.MenuManager
+	config      *config.Config
+	osInfo      *osdetect.OSInfo
+}
+
+// NewSSHProfileMenu creates a new SSHProfileMenu
+func NewSSHProfileMenu(
+	menuManager *application.MenuManager,
+	config *config.Config,
+	osInfo *osdetect.OSInfo,
+) *SSHProfileMenu {
+	return &SSHProfileMenu{
+		menuManager: menuManager,
+		config:      config,
+		osInfo:      osInfo,
+	}
+}
+
+// sshProfileDescriptions summarizes each profile for the menu
+var sshProfileDescriptions = map[string]string{
+	model.SSHProfileBaseline: "Modern algorithms, 4 auth tries, forwarding allowed",
+	model.SSHProfileStrict:   "AEAD ciphers only, 3 auth tries, local forwarding only",
+	model.SSHProfileParanoid: "Minimal algorithms, 2 auth tries, no forwarding",
+}
+
+// Show displays the profile menu and handles user input
+func (m *SSHProfileMenu) Show() {
+	defer enterScreen("Hardening Profile")()
+
+	current, err := m.menuManager.GetSSHProfile()
+	if err != nil {
+		fmt.Printf("\n%s Error reading SSH configuration: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+	}
+
+	fmt.Println()
+	if current == "" {
+		fmt.Printf("%s No hardening profile is applied\n",
+			style.Colored(style.Yellow, style.SymWarning))
+	} else {
+		fmt.Printf("%s Profile in effect: %s\n",
+			style.Colored(style.Green, style.SymCheckMark),
+			style.Bolded(current, style.Green))
+	}
+	if m.config.SshProfile != "" && m.config.SshProfile != current {
+		fmt.Printf("%s Configured profile: %s\n", style.BulletItem, m.config.SshProfile)
+	}
+
+	fmt.Println(style.Colored(style.Yellow, "\nStricter profiles may lock out older SSH clients."))
+	fmt.Println(style.Dimmed("Test a new connection before closing this session."))
+
+	// Create menu options
+	var menuOptions []style.MenuOption
+	for i, name := range model.SSHProfileNames {
+		option := style.MenuOption{
+			Number:      i + 1,
+			Title:       "Apply " + name,
+			Description: sshProfileDescriptions[name],
+		}
+		if name == current {
+			option.Description = "CURRENT"
+			option.Style = "strike"
+		}
+		menuOptions = append(menuOptions, option)
+	}
+
+	// Create menu
+	menu := style.NewMenu("Select a profile", menuOptions)
+	menu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return",
+		Description: "Keep current profile",
+	})
+
+	// Display menu
+	menu.Print()
+
+	choice := ReadMenuInput()
+
+	// Handle 'q' as a special exit case
+	if choice == "q" || choice == "0" {
+		return
+	}
+
+	var profile string
+	for i, name := range model.SSHProfileNames {
+		if choice == fmt.Sprintf("%d", i+1) {
+			profile = name
+		}
+	}
+	if profile == "" {
+		fmt.Printf("\n%s Invalid option. Please try again.\n",
+			style.Colored(style.Red, style.SymCrossMark))
+		fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+		ReadKey()
+		m.Show()
+		return
+	}
+
+	m.applyProfile(profile)
+
+	fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
+	ReadKey()
+	m.Show()
+}
+
+// applyProfile writes the profile directives and records the choice in the configuration
+func (m *SSHProfileMenu) applyProfile(profile string) {
+	fmt.Printf("\nApplying the %s SSH hardening profile...\n", profile)
+
+	// In dry-run mode the change is printed as a diff instead
+	if err := m.menuManager.ApplySSHProfile(profile); err != nil {
+		fmt.Printf("\n%s Failed to apply SSH profile: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+		return
+	}
+	if m.config.DryRun {
+		return
+	}
+
+	fmt.Printf("\n%s SSH hardening profile set to %s\n",
+		style.Colored(style.Green, style.SymCheckMark), profile)
+
+	m.config.SshProfile = profile
+	if err := config.SaveConfig(m.config, "hardn.yml"); err != nil {
+		fmt.Printf("\n%s Failed to save configuration: %v\n",
+			style.Colored(style.Red, style.SymCrossMark), err)
+	}
+}
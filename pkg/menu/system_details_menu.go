@@ -7,6 +7,7 @@ import (
 
 	"github.com/abbott/hardn/pkg/application"
 	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
 	"github.com/abbott/hardn/pkg/osdetect"
 	"github.com/abbott/hardn/pkg/style"
 	"github.com/abbott/hardn/pkg/system"
@@ -18,6 +19,8 @@ type SystemDetailsMenu struct {
 	config          *config.Config
 	osInfo          *osdetect.OSInfo
 	hostInfoManager *application.HostInfoManager
+	exposureManager *application.NetworkExposureManager
+	timeSyncManager *application.TimeSyncManager
 }
 
 // NewSystemDetailsMenu creates a new SystemDetailsMenu
@@ -25,17 +28,21 @@ func NewSystemDetailsMenu(
 	config *config.Config,
 	osInfo *osdetect.OSInfo,
 	hostInfoManager *application.HostInfoManager,
+	exposureManager *application.NetworkExposureManager,
+	timeSyncManager *application.TimeSyncManager,
 ) *SystemDetailsMenu {
 	return &SystemDetailsMenu{
 		config:          config,
 		osInfo:          osInfo,
 		hostInfoManager: hostInfoManager,
+		exposureManager: exposureManager,
+		timeSyncManager: timeSyncManager,
 	}
 }
 
 // Show displays the host information menu and handles user input
 func (m *SystemDetailsMenu) Show() {
-	utils.ClearScreen()
+	defer enterScreen("System Details")()
 
 	// Get detailed system information using our enhanced status package
 	systemInfo, err := system.GenerateSystemStatus(m.hostInfoManager)
@@ -66,6 +73,23 @@ func (m *SystemDetailsMenu) Show() {
 		system.DisplayMachineStatus(systemInfo)
 	}
 
+	// Listening services are shown even when the host details failed
+	exposure, exposureErr := m.exposureManager.GetNetworkExposure()
+	if exposureErr != nil {
+		fmt.Printf("\n%s Could not list listening services: %v\n",
+			style.Colored(style.Yellow, style.SymWarning), exposureErr)
+	} else {
+		DisplayNetworkExposure(exposure)
+	}
+
+	clock, clockErr := m.timeSyncManager.GetTimeSyncStatus()
+	if clockErr != nil {
+		fmt.Printf("\n%s Could not read the time synchronization status: %v\n",
+			style.Colored(style.Yellow, style.SymWarning), clockErr)
+	} else {
+		DisplayTimeSync(clock)
+	}
+
 	// Create menu options
 	menuOptions := []style.MenuOption{
 		{Number: 1, Title: "Refresh Information", Description: "Reload system status from system"},
@@ -105,7 +129,7 @@ func (m *SystemDetailsMenu) Show() {
 	case "2":
 		// Export system information (only if we have system info)
 		if err == nil && systemInfo != nil {
-			m.exportSystemDetails(systemInfo)
+			m.exportSystemDetails(systemInfo, exposure, clock)
 		} else {
 			fmt.Printf("\n%s Cannot export: No system information available\n",
 				style.Colored(style.Red, style.SymCrossMark))
@@ -130,7 +154,7 @@ func (m *SystemDetailsMenu) Show() {
 }
 
 // exportSystemDetails writes system information to a file
-func (m *SystemDetailsMenu) exportSystemDetails(info *system.SystemDetails) {
+func (m *SystemDetailsMenu) exportSystemDetails(info *system.SystemDetails, exposure *model.NetworkExposure, clock *model.TimeSyncStatus) {
 	fmt.Printf("\n%s Enter filename to export system status (default: system_status.txt): ", style.BulletItem)
 	filename := ReadInput()
 
@@ -147,6 +171,7 @@ func (m *SystemDetailsMenu) exportSystemDetails(info *system.SystemDetails) {
 
 	content.WriteString(fmt.Sprintf("OS: %s %s\n", info.OSName, info.OSVersion))
 	content.WriteString(fmt.Sprintf("Kernel: %s\n", info.Kernel))
+	content.WriteString(fmt.Sprintf("Arch: %s\n", info.Arch))
 	content.WriteString(fmt.Sprintf("Hostname: %s\n", info.Hostname))
 	if info.Domain != "" {
 		content.WriteString(fmt.Sprintf("Domain: %s\n", info.Domain))
@@ -221,6 +246,22 @@ func (m *SystemDetailsMenu) exportSystemDetails(info *system.SystemDetails) {
 		content.WriteString(fmt.Sprintf("From: %s\n", info.LastLoginIP))
 	}
 
+	// Listening services
+	if exposure != nil {
+		content.WriteString("\n## listening services\n\n")
+		for _, service := range exposure.Services {
+			content.WriteString(fmt.Sprintf("- %s\n", listenerLine(service)))
+		}
+	}
+
+	// Time synchronization
+	if clock != nil {
+		content.WriteString("\n## time\n\n")
+		for _, line := range timeSyncLines(clock) {
+			content.WriteString(line + "\n")
+		}
+	}
+
 	// Create file
 	if m.config.DryRun {
 		fmt.Printf("\n%s [DRY-RUN] Would write system status to %s\n",
@@ -243,3 +284,122 @@ func (m *SystemDetailsMenu) exportSystemDetails(info *system.SystemDetails) {
 	fmt.Printf("\n%s Press any key to continue...", style.Dimmed(style.SymRightCarrot))
 	ReadKey()
 }
+
+// DisplayNetworkExposure lists the listening sockets and how far the
+// firewall exposes each of them
+func DisplayNetworkExposure(exposure *model.NetworkExposure) {
+	boxConfig := style.BoxConfig{
+		Width:          72,
+		ShowEmptyRow:   true,
+		ShowTopBorder:  true,
+		ShowLeftBorder: false,
+		Title:          "Listening Services",
+		TitleColor:     style.Bold,
+	}
+
+	box := style.NewBox(boxConfig)
+	box.DrawBox(func(printLine func(string)) {
+		if len(exposure.Services) == 0 {
+			printLine("No listening sockets found")
+			return
+		}
+
+		for _, service := range exposure.Services {
+			line := listenerLine(service)
+			switch service.Exposure {
+			case model.ExposureUnfiltered:
+				line = style.Colored(style.Red, line)
+			case model.ExposureOpen:
+				line = style.Colored(style.Yellow, line)
+			case model.ExposureLocal, model.ExposureBlocked:
+				line = style.Dimmed(line)
+			}
+			printLine(line)
+		}
+
+		if unfiltered := len(exposure.Unfiltered()); unfiltered > 0 {
+			printLine("")
+			printLine(fmt.Sprintf("%d socket(s) reachable with no firewall rule deciding about them", unfiltered))
+		}
+	})
+}
+
+// DisplayTimeSync shows the time synchronization daemon and how far the
+// clock is from its source
+func DisplayTimeSync(clock *model.TimeSyncStatus) {
+	boxConfig := style.BoxConfig{
+		Width:          72,
+		ShowEmptyRow:   true,
+		ShowTopBorder:  true,
+		ShowLeftBorder: false,
+		Title:          "Time Synchronization",
+		TitleColor:     style.Bold,
+	}
+
+	box := style.NewBox(boxConfig)
+	box.DrawBox(func(printLine func(string)) {
+		for _, line := range timeSyncLines(clock) {
+			printLine(line)
+		}
+
+		switch {
+		case clock.Daemon == "":
+			printLine("")
+			printLine(style.Colored(style.Red, "Nothing keeps the clock synchronized"))
+		case !clock.Synchronized:
+			printLine("")
+			printLine(style.Colored(style.Red, "The clock is not synchronized with a time source"))
+		case clock.Drifting():
+			printLine("")
+			printLine(style.Colored(style.Yellow,
+				fmt.Sprintf("The clock is more than %gs off its source", model.MaxClockOffset)))
+		}
+	})
+}
+
+// timeSyncLines formats the time synchronization status as "Label: value"
+// lines, shared by the screen and the export
+func timeSyncLines(clock *model.TimeSyncStatus) []string {
+	if clock.Daemon == "" {
+		return []string{"Daemon: none"}
+	}
+
+	servers := strings.Join(clock.Servers, ", ")
+	if servers == "" {
+		servers = "-"
+	} else if clock.NTS {
+		servers += " (NTS)"
+	}
+	source := clock.Source
+	if source == "" {
+		source = "-"
+	}
+	if clock.Stratum > 0 {
+		source += fmt.Sprintf(" (stratum %d)", clock.Stratum)
+	}
+	synchronized := "no"
+	if clock.Synchronized {
+		synchronized = "yes"
+	}
+
+	return []string{
+		fmt.Sprintf("Daemon: %s", clock.Daemon),
+		fmt.Sprintf("Servers: %s", servers),
+		fmt.Sprintf("Synchronized: %s", synchronized),
+		fmt.Sprintf("Source: %s", source),
+		fmt.Sprintf("Offset: %s", clock.Offset()),
+	}
+}
+
+// listenerLine formats a socket as "tcp/22 0.0.0.0 sshd (openssh-server) open"
+func listenerLine(service model.ExposedService) string {
+	process := service.Process
+	if process == "" {
+		process = "-"
+	}
+	if service.Package != "" {
+		process += " (" + service.Package + ")"
+	}
+	return fmt.Sprintf("%-10s %-16s %-28s %s",
+		fmt.Sprintf("%s/%d", service.Protocol, service.Port), service.Address, process, service.Exposure)
+}
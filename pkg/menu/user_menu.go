@@ -7,9 +7,9 @@ import (
 
 	"github.com/abbott/hardn/pkg/application"
 	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
 	"github.com/abbott/hardn/pkg/osdetect"
 	"github.com/abbott/hardn/pkg/style"
-	"github.com/abbott/hardn/pkg/utils"
 )
 
 // UserMenu handles user-related operations through the menu system
@@ -34,7 +34,7 @@ func NewUserMenu(
 
 // ShowUserMenu displays the user menu and handles input
 func (m *UserMenu) Show() {
-	utils.ClearScreen()
+	defer enterScreen("User Management")()
 
 	// Initialize status formatter w/specific fields for consistency
 	formatter := style.NewStatusFormatter([]string{
@@ -166,6 +166,9 @@ func (m *UserMenu) getUserId(username string) string {
 	return userInfo.UID + ":" + userInfo.GID
 }
 
+// userDisplayLimit caps how many users DisplayUserDetails describes in full
+const userDisplayLimit = 10
+
 // Display user configuration details
 // This function can be reused by other menus that need to display user config
 func (m *UserMenu) DisplayUserDetails(
@@ -180,8 +183,13 @@ func (m *UserMenu) DisplayUserDetails(
 		printIndent = style.IndentPrinter(printFn, indent)
 	}
 
-	// Get non-system users (UID >= 1000)
-	nonSysUsers, err := m.menuManager.GetNonSystemUsers()
+	// Get non-system users (UID >= 1000); only the first page is detailed
+	// so directory-joined hosts with many accounts stay responsive
+	page, err := m.menuManager.ListNonSystemUsers(cfg.LocalUsersOnly, 0, userDisplayLimit)
+	var nonSysUsers []model.User
+	if page != nil {
+		nonSysUsers = page.Users
+	}
 	if err != nil {
 		printIndent(formatter.FormatWarning(
 			"System Users",
@@ -204,6 +212,11 @@ func (m *UserMenu) DisplayUserDetails(
 				}
 				printFn("")
 			}
+			// Flag accounts resolved from a directory service rather than /etc/passwd
+			if !user.Local {
+				printIndent(formatter.FormatBullet("Account", "Directory", "via NSS", "dark"))
+			}
+
 			// Try to get extended user info
 			userInfo, err := m.menuManager.GetExtendedUserInfo(user.Username)
 			// Display sudo access w/standardized formatting
@@ -286,5 +299,10 @@ func (m *UserMenu) DisplayUserDetails(
 				printFn("")
 			}
 		}
+
+		if page.HasMore() {
+			printFn("")
+			printIndent(formatter.FormatBullet("More", fmt.Sprintf("%d additional users not shown", page.Total-len(nonSysUsers)), "", "dark"))
+		}
 	}
 }
@@ -4,57 +4,19 @@ package menu
 import (
 	"fmt"
 	osuser "os/user"
-	"regexp"
 	"strings"
 
 	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
 	"github.com/abbott/hardn/pkg/style"
-	"github.com/abbott/hardn/pkg/utils"
 )
 
 // validateUsername checks if the given username is valid for Linux systems
 // Returns isValid, errorMessage
 func validateUsername(username string) (bool, string) {
-	// 1. Check for empty username
-	if username == "" {
-		return false, "Username cannot be empty"
+	if err := model.ValidateUsername(username); err != nil {
+		return false, err.Error()
 	}
-
-	// 2. Check for spaces
-	if strings.Contains(username, " ") {
-		return false, "Username cannot contain spaces"
-	}
-
-	// 3. Check username length
-	if len(username) < 1 || len(username) > 32 {
-		return false, "Username must be between 1 and 32 characters"
-	}
-
-	// 4. Check for valid characters (Linux username restrictions)
-	// Linux usernames can contain lowercase letters, numbers, underscores, and hyphens
-	// Usernames must start with a letter
-	validUsernameRegex := "^[a-z][a-z0-9_-]*$"
-	match, err := regexp.MatchString(validUsernameRegex, username)
-	if err != nil || !match {
-		return false, "Username must start with a lowercase letter and contain only lowercase letters, numbers, hyphens, and underscores"
-	}
-
-	// 5. Check against reserved/dangerous usernames
-	reservedUsernames := []string{
-		"root", "admin", "administrator", "sudo", "system",
-		"daemon", "bin", "sys", "sync", "games", "man", "lp", "mail",
-		"news", "uucp", "proxy", "www-data", "backup", "list", "irc",
-		"gnats", "nobody", "systemd-network", "systemd-resolve", "messagebus",
-		"sshd", "postfix", "ntp", "_apt", "tss", "uuidd", "tcpdump",
-		"landscape", "pollinate", "syslog", "usbmux", "pulse",
-	}
-
-	for _, reserved := range reservedUsernames {
-		if username == reserved {
-			return false, fmt.Sprintf("'%s' is a reserved system username and cannot be used", username)
-		}
-	}
-
 	return true, ""
 }
 
@@ -89,6 +51,12 @@ func (m *UserMenu) HandleUserMenuOptions() bool {
 			Title:       "Create a user",
 			Description: "Configure a new user",
 		})
+
+		menuOptions = append(menuOptions, style.MenuOption{
+			Number:      3,
+			Title:       "Import users",
+			Description: "Create several users from a CSV file",
+		})
 	} else {
 		// Standard menu for when user doesn't exist or no username set
 		// Add or change username option
@@ -136,6 +104,12 @@ func (m *UserMenu) HandleUserMenuOptions() bool {
 				Description: fmt.Sprintf("Create user '%s' with current settings", username),
 			})
 		}
+
+		menuOptions = append(menuOptions, style.MenuOption{
+			Number:      5,
+			Title:       "Import users",
+			Description: "Create several users from a CSV file",
+		})
 	}
 
 	// Create menu
@@ -166,86 +140,14 @@ func (m *UserMenu) HandleUserMenuOptions() bool {
 			// Option 1 in simplified menu: Manage a user
 			// This submenu allows modifying sudo settings and SSH keys
 
-			// Get list of non-system users to manage
-			nonSysUsers, err := m.menuManager.GetNonSystemUsers()
-			if err != nil {
-				fmt.Printf("\n%s Error getting users: %v\n",
-					style.Colored(style.Red, style.SymCrossMark), err)
-				style.PressAnyKey()
-				ReadKey()
-				return true
-			}
-
-			if len(nonSysUsers) == 0 {
-				fmt.Printf("\n%s No non-system users found\n",
-					style.Colored(style.Yellow, style.SymWarning))
-				style.PressAnyKey()
-				ReadKey()
-				return true
-			}
-
-			// boxHeader := style.HeaderLabel("User Management")
-
-			// Create user selection menu
-			utils.ClearScreen()
-			// Create a separate box for security status
-			securityBox := style.NewBox(style.BoxConfig{
-				Width:        64,
-				ShowEmptyRow: true,
-				ShowTopShade: true,
-				Indentation:  0,
-				Title:        "Manage User",
-			})
-
-			// Draw the security box with all content
-			securityBox.DrawBox(func(printLine func(string)) {
-				// fmt.Println(style.ScreenHeader("Manage User", 72))
-
-				userOptions := []style.MenuOption{}
-				for i, user := range nonSysUsers {
-					userOptions = append(userOptions, style.MenuOption{
-						Number:      i + 1,
-						Title:       user.Username,
-						Description: "",
-					})
-				}
-
-				userMenu := style.NewMenu("Select a user", userOptions)
-
-				userMenu.SetExitOption(style.MenuOption{
-					Number:      0,
-					Title:       "Return",
-					Description: "",
-				})
-
-				userMenu.SetIndentation(2)
-				userMenu.Print()
-			})
-
-			userChoice := ReadMenuInput()
-
-			// Handle user selection
-			if userChoice == "0" || userChoice == "q" {
-				// User canceled selection
+			// Select a user to manage, paging through large user lists
+			selectedUser, ok := m.selectNonSystemUser()
+			if !ok {
 				return true
 			}
 
-			// Convert choice to index
-			userIndex := -1
-			_, err = fmt.Sscanf(userChoice, "%d", &userIndex)
-			if err != nil || userIndex < 1 || userIndex > len(nonSysUsers) {
-				fmt.Printf("\n%s Invalid selection. Please try again.\n",
-					style.Colored(style.Red, style.SymCrossMark))
-				style.PressAnyKey()
-				ReadKey()
-				return true
-			}
-
-			// Get the selected user
-			selectedUser := nonSysUsers[userIndex-1]
+			defer enterScreen("Manage User")()
 
-			// Clear the screen before showing the management submenu
-			utils.ClearScreen()
 			// Create a separate box for security status
 			manageUserBox := style.NewBox(style.BoxConfig{
 				Width:        64,
@@ -274,6 +176,11 @@ func (m *UserMenu) HandleUserMenuOptions() bool {
 						Title:       "Manage SSH keys",
 						Description: "Add or remove SSH keys",
 					},
+					{
+						Number:      3,
+						Title:       "Sudo Template",
+						Description: "Restrict sudo to a set of commands",
+					},
 				}
 
 				manageMenu := style.NewMenu("Select an option", manageUserOptions)
@@ -295,8 +202,7 @@ func (m *UserMenu) HandleUserMenuOptions() bool {
 			switch subChoice {
 			case "1":
 
-				// Create user selection menu
-				utils.ClearScreen()
+				defer enterScreen("Sudo Method")()
 
 				formatter := style.NewStatusFormatter([]string{
 					"Sudo Method",
@@ -322,7 +228,6 @@ func (m *UserMenu) HandleUserMenuOptions() bool {
 					// if indent > 0 {
 					// 	printIndent = style.IndentPrinter(printFn, indent)
 					// }
-					// fmt.Println(style.ScreenHeader("Manage User", 72))
 
 					// Toggle sudo password requirement for selected user
 					// Get current user settings
@@ -423,8 +328,7 @@ func (m *UserMenu) HandleUserMenuOptions() bool {
 					break
 				}
 
-				// Create submenu for SSH key management
-				utils.ClearScreen()
+				defer enterScreen("SSH Keys")()
 
 				fmt.Printf("\n%s Managing SSH keys for user: %s\n",
 					style.Colored(style.Blue, style.SymInfo),
@@ -463,6 +367,36 @@ func (m *UserMenu) HandleUserMenuOptions() bool {
 					})
 				}
 
+				// Directory accounts usually keep keys in the directory (sshPublicKey),
+				// served to sshd through AuthorizedKeysCommand
+				keysCommand := ""
+				if !selectedUser.Local {
+					keysCommand, _ = m.menuManager.GetAuthorizedKeysCommand()
+
+					fmt.Printf("\n%s %s is a directory account; keys stored in the directory are read via AuthorizedKeysCommand\n",
+						style.Colored(style.Yellow, style.SymInfo), selectedUser.Username)
+					if keysCommand != "" {
+						fmt.Printf("  AuthorizedKeysCommand: %s\n", style.Colored(style.Cyan, keysCommand))
+					} else {
+						fmt.Printf("  AuthorizedKeysCommand: %s\n", style.Dimmed("not configured"))
+					}
+					fmt.Println()
+
+					if keysCommand == "" {
+						keyOptions = append(keyOptions, style.MenuOption{
+							Number:      3,
+							Title:       "Enable directory key lookup",
+							Description: "Use " + model.SSSDAuthorizedKeysCommand,
+						})
+					} else {
+						keyOptions = append(keyOptions, style.MenuOption{
+							Number:      3,
+							Title:       "Disable directory key lookup",
+							Description: "Remove AuthorizedKeysCommand",
+						})
+					}
+				}
+
 				keyMenu := style.NewMenu("Select SSH key operation", keyOptions)
 				keyMenu.SetExitOption(style.MenuOption{
 					Number:      0,
@@ -484,11 +418,13 @@ func (m *UserMenu) HandleUserMenuOptions() bool {
 							style.Colored(style.Yellow, style.SymWarning))
 					} else {
 						// Add the key using manager
-						err := m.menuManager.AddSSHKey(selectedUser.Username, newKey)
-						if err != nil {
+						if m.config.DryRun {
+							fmt.Printf("\n%s [DRY-RUN] Would add SSH key for user %s\n",
+								style.BulletItem, selectedUser.Username)
+						} else if err := m.menuManager.AddSSHKey(selectedUser.Username, newKey); err != nil {
 							fmt.Printf("\n%s Failed to add SSH key: %v\n",
 								style.Colored(style.Red, style.SymCrossMark), err)
-						} else if !m.config.DryRun {
+						} else {
 							fmt.Printf("\n%s SSH key added successfully\n",
 								style.Colored(style.Green, style.SymCheckMark))
 						}
@@ -525,27 +461,54 @@ func (m *UserMenu) HandleUserMenuOptions() bool {
 							fmt.Printf("\n%s Invalid selection.\n",
 								style.Colored(style.Red, style.SymCrossMark))
 						} else {
-							// Remove the key at the specified index
-							// This is a little tricky - we need to rebuild the keys list without the specified key
-							newKeys := []string{}
-							for i, key := range userInfo.SshKeys {
-								if i != keyIndex-1 {
-									newKeys = append(newKeys, key)
-								}
-							}
-
-							// Update the user with the new keys list
-							err := m.menuManager.CreateUser(selectedUser.Username, userInfo.HasSudo, userInfo.SudoNoPassword, newKeys)
+							// Remove the selected key by fingerprint
+							fingerprint, err := m.menuManager.SSHKeyFingerprint(userInfo.SshKeys[keyIndex-1])
 							if err != nil {
-								fmt.Printf("\n%s Failed to update SSH keys: %v\n",
+								fmt.Printf("\n%s Cannot identify selected key: %v\n",
+									style.Colored(style.Red, style.SymCrossMark), err)
+							} else if m.config.DryRun {
+								fmt.Printf("\n%s [DRY-RUN] Would remove SSH key %s\n",
+									style.BulletItem, fingerprint)
+							} else if err := m.menuManager.RemoveSSHKey(selectedUser.Username, fingerprint); err != nil {
+								fmt.Printf("\n%s Failed to remove SSH key: %v\n",
 									style.Colored(style.Red, style.SymCrossMark), err)
-							} else if !m.config.DryRun {
+							} else {
 								fmt.Printf("\n%s SSH key removed successfully\n",
 									style.Colored(style.Green, style.SymCheckMark))
 							}
 						}
 					}
 
+				case "3": // Toggle directory key lookup
+					if selectedUser.Local {
+						fmt.Printf("\n%s Invalid option.\n",
+							style.Colored(style.Red, style.SymCrossMark))
+						break
+					}
+
+					command := model.SSSDAuthorizedKeysCommand
+					action := "enable"
+					if keysCommand != "" {
+						command = ""
+						action = "disable"
+					}
+
+					if m.config.DryRun {
+						if command != "" {
+							fmt.Printf("\n%s [DRY-RUN] Would write: AuthorizedKeysCommand %s\n", style.BulletItem, command)
+							fmt.Printf("%s [DRY-RUN] Would write: AuthorizedKeysCommandUser %s\n",
+								style.BulletItem, model.DefaultAuthorizedKeysCommandUser)
+						} else {
+							fmt.Printf("\n%s [DRY-RUN] Would remove AuthorizedKeysCommand\n", style.BulletItem)
+						}
+					} else if err := m.menuManager.ConfigureAuthorizedKeysCommand(command, model.DefaultAuthorizedKeysCommandUser); err != nil {
+						fmt.Printf("\n%s Failed to %s directory key lookup: %v\n",
+							style.Colored(style.Red, style.SymCrossMark), action, err)
+					} else {
+						fmt.Printf("\n%s Directory key lookup %sd\n",
+							style.Colored(style.Green, style.SymCheckMark), action)
+					}
+
 				case "0", "q":
 					// Return to user management menu
 					break
@@ -558,6 +521,9 @@ func (m *UserMenu) HandleUserMenuOptions() bool {
 				style.PressAnyKey()
 				ReadKey()
 
+			case "3":
+				m.applySudoTemplate(selectedUser.Username)
+
 			case "0", "q":
 				// Return to main user menu
 				break
@@ -630,12 +596,7 @@ func (m *UserMenu) HandleUserMenuOptions() bool {
 		if userExists && username != "" {
 			// Option 2 in simplified menu: Create a user
 			// Show dialog to create a new user in a new screen
-			utils.ClearScreen()
-
-			// Create a header for the new user creation screen
-			// fmt.Println(style.BoxedTitle("Create New User", 72, style.Blue))
-
-			fmt.Println(style.ScreenHeader("Create New User", 64, style.Gray10))
+			defer enterScreen("Create User")()
 
 			fmt.Printf("\n%s Configure a new user\n",
 				style.Colored(style.Blue, style.SymInfo))
@@ -673,6 +634,11 @@ func (m *UserMenu) HandleUserMenuOptions() bool {
 				return true
 			}
 
+			// Creating a local account with a directory user's name shadows it
+			if !m.confirmShadowDirectoryUser(newUsername) {
+				return true
+			}
+
 			// Display user settings section
 			fmt.Println("\n" + style.SectionDivider("User Settings", 72))
 
@@ -785,7 +751,13 @@ func (m *UserMenu) HandleUserMenuOptions() bool {
 		}
 
 	case "3":
-		// Standard menu only - Manage SSH keys
+		// Option 3 in simplified menu: Import users
+		if userExists && username != "" {
+			m.importUsers()
+			return true
+		}
+
+		// Standard menu - Manage SSH keys
 		m.SSHKeysMenu()
 		return true // Continue showing the menu
 
@@ -819,6 +791,11 @@ func (m *UserMenu) HandleUserMenuOptions() bool {
 			}
 		}
 
+		// Creating a local account with a directory user's name shadows it
+		if !userExists && !m.confirmShadowDirectoryUser(username) {
+			return true
+		}
+
 		// Determine action based on whether user exists
 		action := "Creating"
 		if userExists {
@@ -841,6 +818,11 @@ func (m *UserMenu) HandleUserMenuOptions() bool {
 
 		return false // Exit to main menu after user creation/update
 
+	case "5":
+		// Standard menu only - Import users
+		m.importUsers()
+		return true // Continue showing the menu
+
 	case "0":
 		// Return to main menu
 		return false // Exit to main menu
@@ -859,3 +841,311 @@ func (m *UserMenu) HandleUserMenuOptions() bool {
 	// ReadKey()
 	// return false // Exit to main menu as default behavior
 }
+
+// userSelectPageSize is the number of users offered per page of the user picker
+const userSelectPageSize = 8
+
+// selectNonSystemUser shows a paged picker of non-system users.
+// Returns the selected user and false if the selection was canceled.
+func (m *UserMenu) selectNonSystemUser() (model.User, bool) {
+	defer enterScreen("Select User")()
+
+	offset := 0
+	for {
+		page, err := m.menuManager.ListNonSystemUsers(m.config.LocalUsersOnly, offset, userSelectPageSize)
+		if err != nil {
+			fmt.Printf("\n%s Error getting users: %v\n",
+				style.Colored(style.Red, style.SymCrossMark), err)
+			style.PressAnyKey()
+			ReadKey()
+			return model.User{}, false
+		}
+
+		if page.Total == 0 {
+			fmt.Printf("\n%s No non-system users found\n",
+				style.Colored(style.Yellow, style.SymWarning))
+			style.PressAnyKey()
+			ReadKey()
+			return model.User{}, false
+		}
+
+		paged := page.Total > len(page.Users)
+
+		redrawScreen()
+		selectBox := style.NewBox(style.BoxConfig{
+			Width:        64,
+			ShowEmptyRow: true,
+			ShowTopShade: true,
+			Indentation:  0,
+			Title:        "Manage User",
+		})
+
+		selectBox.DrawBox(func(printLine func(string)) {
+			if paged {
+				fmt.Printf("  %s\n\n", style.Dimmed(fmt.Sprintf("Showing %d-%d of %d users",
+					page.Offset+1, page.Offset+len(page.Users), page.Total)))
+			}
+
+			userOptions := []style.MenuOption{}
+			for i, user := range page.Users {
+				description := ""
+				if !user.Local {
+					description = "directory"
+				}
+				userOptions = append(userOptions, style.MenuOption{
+					Number:      i + 1,
+					Title:       user.Username,
+					Description: description,
+				})
+			}
+
+			// Offer the next page, wrapping back to the first after the last
+			if paged {
+				title := "Next page"
+				if !page.HasMore() {
+					title = "First page"
+				}
+				userOptions = append(userOptions, style.MenuOption{
+					Number:      userSelectPageSize + 1,
+					Title:       title,
+					Description: "",
+				})
+			}
+
+			userMenu := style.NewMenu("Select a user", userOptions)
+			userMenu.SetExitOption(style.MenuOption{
+				Number:      0,
+				Title:       "Return",
+				Description: "",
+			})
+
+			userMenu.SetIndentation(2)
+			userMenu.Print()
+		})
+
+		userChoice := ReadMenuInput()
+
+		// Handle user selection
+		if userChoice == "0" || userChoice == "q" {
+			return model.User{}, false
+		}
+
+		// Convert choice to index
+		userIndex := -1
+		_, err = fmt.Sscanf(userChoice, "%d", &userIndex)
+
+		if paged && err == nil && userIndex == userSelectPageSize+1 {
+			offset += userSelectPageSize
+			if !page.HasMore() {
+				offset = 0
+			}
+			continue
+		}
+
+		if err != nil || userIndex < 1 || userIndex > len(page.Users) {
+			fmt.Printf("\n%s Invalid selection. Please try again.\n",
+				style.Colored(style.Red, style.SymCrossMark))
+			style.PressAnyKey()
+			ReadKey()
+			return model.User{}, false
+		}
+
+		return page.Users[userIndex-1], true
+	}
+}
+
+// confirmShadowDirectoryUser warns when username belongs to a directory (LDAP/SSSD)
+// account, since a local account of the same name would shadow it.
+// Returns true if creation should proceed.
+func (m *UserMenu) confirmShadowDirectoryUser(username string) bool {
+	isDirectoryUser, err := m.menuManager.IsDirectoryUser(username)
+	if err != nil || !isDirectoryUser {
+		return true
+	}
+
+	fmt.Printf("\n%s '%s' is a directory (LDAP/SSSD) account on this host.\n",
+		style.Colored(style.Yellow, style.SymWarning), username)
+	fmt.Printf("%s A local account would shadow it: local UID, home and password would take precedence.\n",
+		style.BulletItem)
+	fmt.Printf("%s Create a local account anyway? (y/n): ", style.BulletItem)
+
+	confirm := ReadInput()
+	if !strings.EqualFold(confirm, "y") && !strings.EqualFold(confirm, "yes") {
+		fmt.Printf("\n%s Operation cancelled.\n",
+			style.Colored(style.Yellow, style.SymInfo))
+		style.PressAnyKey()
+		ReadKey()
+		return false
+	}
+
+	return true
+}
+
+// importUsers creates several users from a CSV file. Every row is checked
+// and the plan shown before anything is created; a file with problems is
+// not imported at all.
+func (m *UserMenu) importUsers() {
+	defer enterScreen("Import Users")()
+
+	fmt.Printf("\n%s Columns: %s (header row required)\n",
+		style.Colored(style.Blue, style.SymInfo), strings.Join(model.UserImportColumns, ", "))
+	fmt.Printf("%s Path to CSV file: ", style.BulletItem)
+	path := strings.TrimSpace(ReadInput())
+	if path == "" {
+		return
+	}
+
+	plan, err := m.menuManager.PlanUserImport(path, m.config.SudoNoPassword)
+	if err != nil {
+		fmt.Printf("\n%s %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+		style.PressAnyKey()
+		ReadKey()
+		return
+	}
+
+	// Show the pre-flight before anything changes
+	fmt.Println("\n" + style.SectionDivider("Plan", 72))
+	fmt.Println()
+	for _, entry := range plan.Entries {
+		if len(entry.Problems) == 0 {
+			fmt.Printf("%s Line %d: %s\n", style.Colored(style.Green, style.SymCheckMark), entry.Line, entry.Describe())
+			continue
+		}
+		fmt.Printf("%s Line %d: %s\n", style.Colored(style.Red, style.SymCrossMark), entry.Line, entry.User.Username)
+		for _, problem := range entry.Problems {
+			fmt.Printf("    %s\n", problem)
+		}
+	}
+
+	if !plan.Valid() {
+		fmt.Printf("\n%s %d problem(s) found; no users were created\n",
+			style.Colored(style.Red, style.SymCrossMark), plan.ProblemCount())
+		style.PressAnyKey()
+		ReadKey()
+		return
+	}
+
+	if m.config.DryRun {
+		fmt.Printf("\n%s [DRY-RUN] Would import %d user(s)\n", style.BulletItem, len(plan.Entries))
+		style.PressAnyKey()
+		ReadKey()
+		return
+	}
+
+	fmt.Printf("\n%s Import %d user(s)? (y/n): ", style.Colored(style.Yellow, style.SymWarning), len(plan.Entries))
+	confirm := ReadInput()
+	if !strings.EqualFold(confirm, "y") && !strings.EqualFold(confirm, "yes") {
+		fmt.Printf("\n%s Operation cancelled.\n", style.Colored(style.Yellow, style.SymInfo))
+		style.PressAnyKey()
+		ReadKey()
+		return
+	}
+
+	results, err := m.menuManager.ImportUsers(plan)
+	if err != nil {
+		fmt.Printf("\n%s %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+		style.PressAnyKey()
+		ReadKey()
+		return
+	}
+
+	fmt.Println("\n" + style.SectionDivider("Results", 72))
+	fmt.Println()
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			fmt.Printf("%s %s: %v\n", style.Colored(style.Red, style.SymCrossMark), result.Username, result.Err)
+		case result.Created:
+			fmt.Printf("%s %s created\n", style.Colored(style.Green, style.SymCheckMark), result.Username)
+		default:
+			fmt.Printf("%s %s updated\n", style.Colored(style.Green, style.SymCheckMark), result.Username)
+		}
+	}
+
+	style.PressAnyKey()
+	ReadKey()
+}
+
+// applySudoTemplate restricts a user's sudo access to the commands of a
+// template. The new sudoers entry and the groups the user leaves are
+// shown before anything changes.
+func (m *UserMenu) applySudoTemplate(username string) {
+	defer enterScreen("Sudo Template")()
+
+	var templateOptions []style.MenuOption
+	for i, template := range model.SudoTemplates {
+		templateOptions = append(templateOptions, style.MenuOption{
+			Number:      i + 1,
+			Title:       template.Name,
+			Description: template.Description,
+		})
+	}
+
+	fmt.Printf("\n  %s%s\n\n", style.Dimmed("Restricting sudo for:"), style.ColoredLabel(username))
+	templateMenu := style.NewMenu("Select a template", templateOptions)
+	templateMenu.SetExitOption(style.MenuOption{
+		Number:      0,
+		Title:       "Return",
+		Description: "",
+	})
+	templateMenu.SetIndentation(2)
+	templateMenu.Print()
+
+	choice := 0
+	if _, err := fmt.Sscanf(ReadMenuInput(), "%d", &choice); err != nil || choice < 1 || choice > len(model.SudoTemplates) {
+		return
+	}
+	template := model.SudoTemplates[choice-1]
+
+	fmt.Printf("\n%s Require a password for these commands? (Y/n): ", style.BulletItem)
+	answer := ReadInput()
+	noPassword := strings.EqualFold(answer, "n") || strings.EqualFold(answer, "no")
+
+	plan, err := m.menuManager.PlanSudoTemplate(username, template.Name, noPassword)
+	if err != nil {
+		fmt.Printf("\n%s %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+		style.PressAnyKey()
+		ReadKey()
+		return
+	}
+
+	// Show the new entry before anything changes
+	fmt.Println("\n" + style.SectionDivider("Plan", 72))
+	fmt.Println()
+	fmt.Printf("%s %s/%s:\n", style.BulletItem, model.SudoersDir, username)
+	for _, line := range strings.Split(strings.TrimSpace(plan.Content), "\n") {
+		fmt.Printf("    %s\n", style.Colored(style.Cyan, line))
+	}
+	for _, group := range plan.LeaveGroup {
+		fmt.Printf("%s Remove %s from group %s\n", style.BulletItem, username, group)
+	}
+	for _, grant := range plan.OtherGrants {
+		fmt.Printf("%s Still grants every command: %s\n", style.Colored(style.Yellow, style.SymWarning), grant)
+	}
+
+	if m.config.DryRun {
+		fmt.Printf("\n%s [DRY-RUN] Would apply the %s template to %s\n", style.BulletItem, template.Name, username)
+		style.PressAnyKey()
+		ReadKey()
+		return
+	}
+
+	fmt.Printf("\n%s Apply the %s template to %s? (y/n): ", style.Colored(style.Yellow, style.SymWarning), template.Name, username)
+	confirm := ReadInput()
+	if !strings.EqualFold(confirm, "y") && !strings.EqualFold(confirm, "yes") {
+		fmt.Printf("\n%s Operation cancelled.\n", style.Colored(style.Yellow, style.SymInfo))
+		style.PressAnyKey()
+		ReadKey()
+		return
+	}
+
+	if err := m.menuManager.ApplySudoTemplate(plan); err != nil {
+		fmt.Printf("\n%s Failed to apply the sudo template: %v\n", style.Colored(style.Red, style.SymCrossMark), err)
+	} else {
+		fmt.Printf("\n%s %s may now run with sudo: %s\n",
+			style.Colored(style.Green, style.SymCheckMark), username, strings.Join(plan.Commands, ", "))
+	}
+
+	style.PressAnyKey()
+	ReadKey()
+}
@@ -0,0 +1,85 @@
+package osdetect
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/interfaces"
+)
+
+// SupportedArchs are the architectures hardn is released for, by GOARCH.
+// 32-bit ARM builds target ARMv7 (GOARM=7).
+var SupportedArchs = []string{"amd64", "arm64", "arm", "riscv64"}
+
+// minARMVersion is the oldest ARM revision the arm release build runs on
+const minARMVersion = 7
+
+// NormalizeArch maps a kernel machine name, as printed by uname -m, to
+// its GOARCH name, e.g. x86_64 to amd64 and armv7l to arm
+func NormalizeArch(machine string) string {
+	machine = strings.ToLower(strings.TrimSpace(machine))
+	switch {
+	case machine == "x86_64" || machine == "amd64":
+		return "amd64"
+	case machine == "aarch64" || machine == "arm64" || machine == "aarch64_be":
+		return "arm64"
+	case machine == "arm" || machine == "armhf" || strings.HasPrefix(machine, "armv"):
+		// armv8l is a 32-bit userland on a 64-bit CPU
+		return "arm"
+	case machine == "riscv64":
+		return "riscv64"
+	case machine == "i386" || machine == "i486" || machine == "i586" || machine == "i686" || machine == "x86":
+		return "386"
+	}
+	return machine
+}
+
+// ARMVersion returns the ARM revision of a machine name such as armv7l,
+// or 0 when the name carries none
+func ARMVersion(machine string) int {
+	machine = strings.ToLower(strings.TrimSpace(machine))
+	if !strings.HasPrefix(machine, "armv") {
+		return 0
+	}
+	revision := strings.TrimPrefix(machine, "armv")
+	suffix := strings.TrimLeft(revision, "0123456789")
+	version, err := strconv.Atoi(strings.TrimSuffix(revision, suffix))
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+// CheckArch reports why hardn has no release build for a machine, or nil
+// when it is supported
+func CheckArch(machine string) error {
+	arch := NormalizeArch(machine)
+	supported := false
+	for _, a := range SupportedArchs {
+		if a == arch {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return fmt.Errorf("no hardn release is built for %s; supported architectures are %s",
+			machine, strings.Join(SupportedArchs, ", "))
+	}
+
+	if version := ARMVersion(machine); arch == "arm" && version != 0 && version < minARMVersion {
+		return fmt.Errorf("the arm release build needs ARMv%d or newer; %s is ARMv%d", minARMVersion, machine, version)
+	}
+	return nil
+}
+
+// detectMachine returns the kernel machine name, falling back to the
+// architecture hardn was built for
+func detectMachine() string {
+	output, err := interfaces.Command("uname", "-m").Output()
+	if machine := strings.TrimSpace(string(output)); err == nil && machine != "" {
+		return machine
+	}
+	return runtime.GOARCH
+}
@@ -0,0 +1,175 @@
+package osdetect
+
+import (
+	"os"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// EnvironmentProbe holds what the host environment is detected from
+type EnvironmentProbe struct {
+	WSLVersion       int    // from the kernel release
+	ContainerEnv     string // container= in PID 1's environment
+	SystemdContainer string // /run/systemd/container, written by systemd in containers
+	DockerEnv        bool   // /.dockerenv exists
+	PodmanEnv        bool   // /run/.containerenv exists
+	Cgroup           string // /proc/1/cgroup
+	UIDMap           string // /proc/self/uid_map
+	CPUHypervisor    bool   // the hypervisor flag of /proc/cpuinfo
+	HypervisorType   string // /sys/hypervisor/type
+	SysVendor        string // /sys/class/dmi/id/sys_vendor
+	ProductName      string // /sys/class/dmi/id/product_name
+	BIOSVendor       string // /sys/class/dmi/id/bios_vendor
+	BIOSVersion      string // /sys/class/dmi/id/bios_version
+	PVEHosts         bool   // /etc/hosts has the section Proxmox VE manages in its containers
+}
+
+// ClassifyEnvironment tells bare metal, VMs and containers apart from a
+// probe, in the order systemd-detect-virt checks them: containers first,
+// since a container may run in a VM
+func ClassifyEnvironment(p EnvironmentProbe) model.HostEnvironment {
+	if p.WSLVersion != 0 {
+		return model.HostEnvironment{Kind: model.EnvWSL}
+	}
+
+	if kind := containerKind(p); kind != "" {
+		return model.HostEnvironment{
+			Kind:         kind,
+			Unprivileged: unprivilegedUIDMap(p.UIDMap),
+			ProxmoxGuest: kind == model.EnvLXC && p.PVEHosts,
+		}
+	}
+
+	if hypervisor := hypervisorOf(p); hypervisor != "" {
+		return model.HostEnvironment{
+			Kind:         model.EnvVM,
+			Hypervisor:   hypervisor,
+			ProxmoxGuest: proxmoxFirmware(p),
+		}
+	}
+	return model.HostEnvironment{Kind: model.EnvBareMetal}
+}
+
+// containerKind returns the container environment of a probe, or ""
+func containerKind(p EnvironmentProbe) string {
+	manager := strings.TrimSpace(p.ContainerEnv)
+	if manager == "" {
+		manager = strings.TrimSpace(p.SystemdContainer)
+	}
+	switch {
+	case strings.HasPrefix(manager, "lxc"):
+		return model.EnvLXC
+	case manager == "docker" || p.DockerEnv:
+		return model.EnvDocker
+	case manager != "" || p.PodmanEnv:
+		return model.EnvContainer
+	}
+
+	// Without root PID 1's environment is unreadable; cgroup v1 paths
+	// still name the container manager
+	switch {
+	case strings.Contains(p.Cgroup, "/docker/"):
+		return model.EnvDocker
+	case strings.Contains(p.Cgroup, "/lxc/") || strings.Contains(p.Cgroup, "/lxc.payload"):
+		return model.EnvLXC
+	}
+	return ""
+}
+
+// unprivilegedUIDMap reports whether a uid_map maps root to another user;
+// the full range "0 0 4294967295" is the host's own
+func unprivilegedUIDMap(uidMap string) bool {
+	fields := strings.Fields(uidMap)
+	if len(fields) < 3 {
+		return false
+	}
+	return !(fields[0] == "0" && fields[1] == "0" && fields[2] == "4294967295")
+}
+
+// dmiHypervisors maps DMI vendor and product names to hypervisors
+var dmiHypervisors = []struct {
+	match      string
+	hypervisor string
+}{
+	{"qemu", "kvm"},
+	{"kvm", "kvm"},
+	{"proxmox", "kvm"},
+	{"vmware", "vmware"},
+	{"virtualbox", "virtualbox"},
+	{"innotek", "virtualbox"},
+	{"microsoft corporation", "hyperv"},
+	{"xen", "xen"},
+	{"bochs", "bochs"},
+	{"parallels", "parallels"},
+	{"amazon ec2", "kvm"},
+	{"google compute engine", "kvm"},
+}
+
+// hypervisorOf returns the hypervisor a probe runs on, or ""
+func hypervisorOf(p EnvironmentProbe) string {
+	if hypervisorType := strings.TrimSpace(p.HypervisorType); hypervisorType == "xen" {
+		return "xen"
+	}
+	dmi := strings.ToLower(strings.Join([]string{p.SysVendor, p.ProductName, p.BIOSVendor}, " "))
+	for _, candidate := range dmiHypervisors {
+		if strings.Contains(dmi, candidate.match) {
+			return candidate.hypervisor
+		}
+	}
+	// An unrecognized hypervisor still sets the CPU flag
+	if p.CPUHypervisor {
+		return "other"
+	}
+	return ""
+}
+
+// proxmoxFirmware reports whether a VM boots Proxmox VE's build of OVMF or
+// SeaBIOS, which name Proxmox in the BIOS vendor or version
+func proxmoxFirmware(p EnvironmentProbe) bool {
+	bios := strings.ToLower(p.BIOSVendor + " " + p.BIOSVersion + " " + p.ProductName)
+	return strings.Contains(bios, "proxmox") || strings.Contains(bios, "pve")
+}
+
+// probeEnvironment reads the files ClassifyEnvironment needs; unreadable
+// files are left empty
+func probeEnvironment(wslVersion int) EnvironmentProbe {
+	read := func(path string) string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	}
+	exists := func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
+	probe := EnvironmentProbe{
+		WSLVersion:       wslVersion,
+		SystemdContainer: read("/run/systemd/container"),
+		DockerEnv:        exists("/.dockerenv"),
+		PodmanEnv:        exists("/run/.containerenv"),
+		Cgroup:           read("/proc/1/cgroup"),
+		UIDMap:           read("/proc/self/uid_map"),
+		HypervisorType:   read("/sys/hypervisor/type"),
+		SysVendor:        read("/sys/class/dmi/id/sys_vendor"),
+		ProductName:      read("/sys/class/dmi/id/product_name"),
+		BIOSVendor:       read("/sys/class/dmi/id/bios_vendor"),
+		BIOSVersion:      read("/sys/class/dmi/id/bios_version"),
+		PVEHosts:         strings.Contains(read("/etc/hosts"), "--- BEGIN PVE ---"),
+	}
+	for _, variable := range strings.Split(read("/proc/1/environ"), "\x00") {
+		if value, ok := strings.CutPrefix(variable, "container="); ok {
+			probe.ContainerEnv = value
+		}
+	}
+	for _, line := range strings.Split(read("/proc/cpuinfo"), "\n") {
+		if strings.HasPrefix(line, "flags") {
+			probe.CPUHypervisor = strings.Contains(" "+line+" ", " hypervisor ")
+			break
+		}
+	}
+	return probe
+}
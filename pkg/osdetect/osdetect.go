@@ -4,8 +4,10 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"runtime"
 	"strings"
 
+	"github.com/abbott/hardn/pkg/domain/model"
 	"github.com/abbott/hardn/pkg/interfaces"
 	"github.com/abbott/hardn/pkg/logging"
 	"github.com/abbott/hardn/pkg/utils"
@@ -13,10 +15,39 @@ import (
 
 // OSInfo holds information about the detected operating system
 type OSInfo struct {
-	OsType     string // debian, ubuntu, alpine
-	OsCodename string // release name, e.g., bullseye, focal, etc.
-	OsVersion  string // version number
-	IsProxmox  bool   // is proxmox environment
+	OsType        string // debian, ubuntu, alpine, or a RHEL-family ID such as rocky
+	OsCodename    string // release name, e.g., bullseye, focal, etc.
+	OsVersion     string // version number
+	IsProxmox     bool   // is proxmox environment
+	Degraded      bool   // unsupported distribution; only distribution-agnostic modules are offered
+	Machine       string // kernel machine name, e.g. x86_64, armv7l, riscv64
+	Arch          string // GOARCH name of the machine, e.g. amd64, arm, riscv64
+	IsWSL         bool   // runs under Windows Subsystem for Linux
+	WSLVersion    int    // 1 or 2 under WSL, 0 otherwise
+	WSLResolvConf bool   // WSL regenerates /etc/resolv.conf
+	Systemd       bool   // systemd is the running init system
+
+	// Bare metal, a VM or a container, which the hardening plan is tuned to
+	Environment model.HostEnvironment
+}
+
+// DegradedModules are the distribution-agnostic modules offered in degraded mode
+var DegradedModules = []string{"SSH", "users", "DNS", "SELinux"}
+
+// SkippedModules are unavailable in degraded mode because they depend on
+// the distribution's package manager, firewall or service layout
+var SkippedModules = []string{
+	"firewall", "package sources", "packages", "automatic updates",
+	"AppArmor", "Lynis", "needrestart", "web server TLS", "database hardening",
+}
+
+// allowUnsupported lets DetectOS continue on unsupported distributions
+var allowUnsupported bool
+
+// AllowUnsupported makes DetectOS return unsupported distributions in
+// degraded mode instead of failing
+func AllowUnsupported(allow bool) {
+	allowUnsupported = allow
 }
 
 // Global cached OS info
@@ -28,7 +59,7 @@ func GetOS() *OSInfo {
 		info, err := DetectOS()
 		if err != nil {
 			// Return a default value if detection fails
-			return &OSInfo{OsType: "debian", OsVersion: "11", OsCodename: "bullseye"}
+			return &OSInfo{OsType: "debian", OsVersion: "11", OsCodename: "bullseye", Machine: runtime.GOARCH, Arch: runtime.GOARCH}
 		}
 		cachedOSInfo = info
 	}
@@ -45,6 +76,30 @@ func NewRealCommander() interfaces.Commander {
 	return interfaces.OSCommander{}
 }
 
+// CheckSupport reports whether hardn supports the distribution of info.
+// With AllowUnsupported set, an unsupported distribution is accepted in
+// degraded mode and info is marked Degraded.
+func CheckSupport(info *OSInfo) error {
+	// For Alpine, use release version as codename
+	if info.OsType == "alpine" {
+		info.OsCodename = info.OsVersion
+		logging.LogSuccess("Alpine Linux %s detected", info.OsVersion)
+	} else if info.OsType == "debian" || info.OsType == "ubuntu" {
+		logging.LogSuccess("%s %s detected", info.OsType, info.OsCodename)
+	} else if model.IsRHELFamily(info.OsType) {
+		// RHEL-family releases have no codename
+		logging.LogSuccess("%s %s detected", info.OsType, info.OsVersion)
+	} else if allowUnsupported {
+		info.Degraded = true
+		logging.LogWarning("Unsupported OS type %s detected; running in degraded mode (%s only)",
+			info.OsType, strings.Join(DegradedModules, ", "))
+		logging.LogWarning("Skipped in degraded mode: %s", strings.Join(SkippedModules, ", "))
+	} else {
+		return fmt.Errorf("unsupported OS type detected: %s (use --force-unsupported to run in degraded mode)", info.OsType)
+	}
+	return nil
+}
+
 // DetectOS detects the operating system and returns its information
 func DetectOS() (*OSInfo, error) {
 	utils.PrintHeader()
@@ -85,16 +140,12 @@ func DetectOS() (*OSInfo, error) {
 		OsVersion:  versionId,
 		OsCodename: versionCodename,
 		IsProxmox:  false,
+		Machine:    detectMachine(),
 	}
+	osInfo.Arch = NormalizeArch(osInfo.Machine)
 
-	// For Alpine, use release version as codename
-	if osInfo.OsType == "alpine" {
-		osInfo.OsCodename = osInfo.OsVersion
-		logging.LogSuccess("Alpine Linux %s detected", osInfo.OsVersion)
-	} else if osInfo.OsType == "debian" || osInfo.OsType == "ubuntu" {
-		logging.LogSuccess("%s %s detected", osInfo.OsType, osInfo.OsCodename)
-	} else {
-		return nil, fmt.Errorf("unsupported OS type detected: %s", osInfo.OsType)
+	if err := CheckSupport(osInfo); err != nil {
+		return nil, err
 	}
 
 	// Check if the system is Proxmox
@@ -103,5 +154,31 @@ func DetectOS() (*OSInfo, error) {
 		logging.LogSuccess("Proxmox environment detected")
 	}
 
+	// WSL has no firewall or LSM of its own, and may run without systemd
+	osInfo.Systemd = systemdRunning()
+	wslVersion := detectWSL()
+	osInfo.Environment = ClassifyEnvironment(probeEnvironment(wslVersion))
+	if wslVersion != 0 {
+		osInfo.IsWSL = true
+		osInfo.WSLVersion = wslVersion
+		osInfo.WSLResolvConf = wslManagesResolvConf()
+		logging.LogSuccess("WSL %d environment detected", wslVersion)
+
+		// Services are run with the service command instead of systemctl
+		if !osInfo.Systemd && model.InitSystemFor(osInfo.OsType) == model.InitSystemd {
+			model.UseInitSystem(model.InitSysV)
+		}
+		logging.LogWarning("Skipped on WSL: %s (see hardn wsl)", strings.Join(osInfo.WSLSkipped(), ", "))
+	}
+
+	if !osInfo.IsWSL {
+		logging.LogSuccess("Environment: %s", osInfo.Environment)
+	}
+
+	// Unsupported boards still run hardn, but have no release to update to
+	if err := CheckArch(osInfo.Machine); err != nil {
+		logging.LogWarning("%v", err)
+	}
+
 	return osInfo, nil
 }
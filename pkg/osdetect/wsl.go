@@ -0,0 +1,147 @@
+package osdetect
+
+import (
+	"os"
+	"strings"
+)
+
+// WSLSkippedModules are unavailable on WSL, where Windows owns the network
+// edge and Microsoft's kernel, booted by Windows, has no LSM or loadable
+// module policy to set
+var WSLSkippedModules = []string{
+	"firewall", "AppArmor", "kernel module blacklist", "needrestart", "boot hardening",
+}
+
+// WSLNoSystemdSkippedModules are also unavailable on WSL when systemd does
+// not run, because they rely on systemd timers or cron started at boot
+var WSLNoSystemdSkippedModules = []string{"automatic updates", "schedule"}
+
+// wslConf is the per-distribution WSL configuration
+const wslConf = "/etc/wsl.conf"
+
+// WSLSkipped returns the modules unavailable on this WSL host, or nil
+// outside WSL
+func (o *OSInfo) WSLSkipped() []string {
+	if o == nil || !o.IsWSL {
+		return nil
+	}
+	skipped := append([]string{}, WSLSkippedModules...)
+	// WSL overwrites resolv.conf unless told not to
+	if o.WSLResolvConf {
+		skipped = append(skipped, "DNS")
+	}
+	if !o.Systemd {
+		skipped = append(skipped, WSLNoSystemdSkippedModules...)
+	}
+	return skipped
+}
+
+// ParseWSLVersion returns the WSL version of a kernel release, as read
+// from /proc/sys/kernel/osrelease, or 0 when it is not a WSL kernel.
+// WSL 1 reports e.g. 4.4.0-19041-Microsoft, WSL 2 5.15.153.1-microsoft-standard-WSL2.
+func ParseWSLVersion(release string) int {
+	release = strings.ToLower(strings.TrimSpace(release))
+	if !strings.Contains(release, "microsoft") {
+		return 0
+	}
+	if strings.Contains(release, "wsl2") || strings.Contains(release, "microsoft-standard") {
+		return 2
+	}
+	return 1
+}
+
+// detectWSL returns the WSL version hardn runs under, or 0 outside WSL
+func detectWSL() int {
+	if data, err := os.ReadFile("/proc/sys/kernel/osrelease"); err == nil {
+		if version := ParseWSLVersion(string(data)); version != 0 {
+			return version
+		}
+	}
+	// Custom WSL 2 kernels may drop the suffix; the interop handler and
+	// the distribution name WSL exports remain
+	if _, err := os.Stat("/proc/sys/fs/binfmt_misc/WSLInterop"); err == nil {
+		return 2
+	}
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return 2
+	}
+	return 0
+}
+
+// ParseWSLConf reads the settings of a wsl.conf, keyed by lowercased
+// section and name such as network.generateresolvconf
+func ParseWSLConf(data string) map[string]string {
+	settings := make(map[string]string)
+	section := ""
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), "\"")
+		settings[section+"."+strings.ToLower(strings.TrimSpace(name))] = value
+	}
+	return settings
+}
+
+// wslManagesResolvConf reports whether WSL regenerates /etc/resolv.conf,
+// which it does unless wsl.conf sets generateResolvConf = false
+func wslManagesResolvConf() bool {
+	data, err := os.ReadFile(wslConf)
+	if err != nil {
+		return true
+	}
+	return !strings.EqualFold(ParseWSLConf(string(data))["network.generateresolvconf"], "false")
+}
+
+// systemdRunning reports whether systemd is the init system, as
+// sd_booted(3) checks
+func systemdRunning() bool {
+	info, err := os.Stat("/run/systemd/system")
+	return err == nil && info.IsDir()
+}
+
+// WSLGuidance returns the hardening steps hardn can not take on WSL, to be
+// done in Windows or in /etc/wsl.conf
+func WSLGuidance(info *OSInfo) []string {
+	if info == nil || !info.IsWSL {
+		return nil
+	}
+
+	guidance := []string{
+		"Inbound traffic is filtered by Windows Defender Firewall, not ufw or firewalld; allow only the ports you need there (New-NetFirewallRule) and keep the rest blocked",
+	}
+	if info.WSLVersion == 2 {
+		guidance = append(guidance,
+			"WSL 2 sits behind NAT: sshd is reachable from the network only through a portproxy (netsh interface portproxy) or mirrored networking; leave both off unless you need remote SSH",
+		)
+	} else {
+		guidance = append(guidance,
+			"WSL 1 shares the Windows network stack: sshd listens on the Windows host's addresses, so set a non-default SSH port that Windows itself does not use",
+		)
+	}
+	guidance = append(guidance,
+		"hardn's SSH settings apply, but prefer key-only logins and ListenAddress 127.0.0.1 when the distribution is only reached from Windows",
+		"Windows drives are mounted with execute permission; add options = \"metadata,noexec\" under [automount] in /etc/wsl.conf to keep them from running Linux binaries",
+		"Set enabled = false under [interop] in /etc/wsl.conf to stop the distribution from launching Windows programs",
+	)
+	if info.WSLResolvConf {
+		guidance = append(guidance,
+			"WSL regenerates /etc/resolv.conf; set generateResolvConf = false under [network] in /etc/wsl.conf before configuring DNS",
+		)
+	}
+	if !info.Systemd {
+		guidance = append(guidance,
+			"systemd is not running, so services do not start with the distribution: set systemd = true under [boot] in /etc/wsl.conf, or start sshd with command = \"service ssh start\"",
+		)
+	}
+	return guidance
+}
@@ -0,0 +1,15 @@
+package secondary
+
+// AnsibleFactsRepository defines the interface for the Ansible local facts
+// script
+type AnsibleFactsRepository interface {
+	// InstallFactsScript writes an executable facts script that runs
+	// "hardn facts" with the given binary
+	InstallFactsScript(binary string) error
+
+	// RemoveFactsScript removes the facts script if hardn installed it
+	RemoveFactsScript() error
+
+	// FactsScriptInstalled reports whether hardn's facts script is installed
+	FactsScriptInstalled() bool
+}
@@ -0,0 +1,12 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// AuditRepository defines the interface for storing audit reports
+type AuditRepository interface {
+	// LoadReport reads an audit report from path
+	LoadReport(path string) (*model.AuditReport, error)
+
+	// SaveReport writes an audit report to path
+	SaveReport(path string, report *model.AuditReport) error
+}
@@ -17,6 +17,12 @@ type BackupRepository interface {
 	// RestoreBackup restores a file from backup
 	RestoreBackup(backupPath, originalPath string) error
 
+	// ListAllBackups returns every backup of the backup directory
+	ListAllBackups() ([]model.BackupFile, error)
+
+	// CompareBackup reads a backup and the live file it would replace
+	CompareBackup(backupPath, originalPath string) (*model.BackupComparison, error)
+
 	// CleanupOldBackups removes backups older than specified date
 	CleanupOldBackups(before time.Time) error
 
@@ -28,4 +34,15 @@ type BackupRepository interface {
 
 	// SetBackupConfig updates the backup configuration
 	SetBackupConfig(config model.BackupConfig) error
+
+	// CheckBackupKeys encrypts and decrypts a test file with the
+	// configured encryption
+	CheckBackupKeys() (*model.BackupKeyStatus, error)
+
+	// SyncRemote mirrors the backup directory to the configured remote
+	// target and applies its retention
+	SyncRemote() (*model.BackupSyncResult, error)
+
+	// CheckRemote tests that the remote target is reachable and writable
+	CheckRemote() error
 }
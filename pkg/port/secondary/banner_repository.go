@@ -0,0 +1,26 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// BannerRepository defines the interface for login banners and the MOTD
+type BannerRepository interface {
+	// WriteBanner replaces a banner file such as /etc/issue
+	WriteBanner(path string, content string) error
+
+	// InstallMotdScript adds the update-motd script that prints the hardn
+	// MOTD at each login
+	InstallMotdScript() error
+
+	// DisableMotdAds turns off Ubuntu's advertising MOTD scripts and
+	// motd-news, returning the scripts it disabled
+	DisableMotdAds() ([]string, error)
+
+	// Hostname returns the name of the host
+	Hostname() (string, error)
+
+	// PendingUpdates counts the packages with an upgrade available
+	PendingUpdates() (int, error)
+
+	// GetBannerStatus reports the login banners on the host
+	GetBannerStatus() (*model.BannerStatus, error)
+}
@@ -0,0 +1,29 @@
+package secondary
+
+// BootRepository defines the interface for the boot loader configuration
+type BootRepository interface {
+	// GetConfiguredParameters returns the kernel parameters of the GRUB
+	// configuration
+	GetConfiguredParameters() ([]string, error)
+
+	// GetRunningParameters returns the parameters the kernel booted with
+	GetRunningParameters() ([]string, error)
+
+	// SetParameters adds kernel parameters to the GRUB configuration,
+	// replacing those with the same name, and updates the boot entries
+	SetParameters(parameters []string) error
+
+	// HashPassword hashes a GRUB password with PBKDF2
+	HashPassword(password string) (string, error)
+
+	// SetPassword sets the GRUB superuser's password hash and updates the
+	// boot entries
+	SetPassword(hash string) error
+
+	// PasswordSet reports whether GRUB has a superuser password
+	PasswordSet() (bool, error)
+
+	// ConfigFiles returns the existing files SetParameters and SetPassword
+	// may change, to back up before they do
+	ConfigFiles() []string
+}
@@ -0,0 +1,13 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// CertificateRepository defines the interface for locating and reading certificates
+type CertificateRepository interface {
+	// FindCertificateFiles expands paths into certificate files; directories
+	// are searched and missing paths are skipped
+	FindCertificateFiles(paths []string) ([]string, error)
+
+	// ReadCertificate returns the first certificate in a PEM file
+	ReadCertificate(path string) (*model.Certificate, error)
+}
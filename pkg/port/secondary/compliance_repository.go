@@ -0,0 +1,21 @@
+package secondary
+
+import "io/fs"
+
+// ComplianceRepository defines the interface for the host state the
+// compliance checks read beyond the audit controls
+type ComplianceRepository interface {
+	// CommandInstalled reports whether a command is on the PATH
+	CommandInstalled(name string) bool
+
+	// SSHEffectiveConfig returns the settings sshd -T reports, keyed by
+	// lowercase keyword, or nil when sshd is not installed
+	SSHEffectiveConfig() (map[string][]string, error)
+
+	// FileMode returns the permission bits of a file
+	FileMode(path string) (fs.FileMode, error)
+
+	// ReadSudoers returns /etc/sudoers and the files in /etc/sudoers.d,
+	// keyed by path
+	ReadSudoers() (map[string][]byte, error)
+}
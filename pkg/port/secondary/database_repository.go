@@ -0,0 +1,22 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// DatabaseRepository defines the interface for database server configuration
+type DatabaseRepository interface {
+	// DetectDatabases returns the supported database servers on the host
+	DetectDatabases() ([]model.DatabaseServer, error)
+
+	// WriteDatabaseBaseline installs the managed configuration drop-in
+	WriteDatabaseBaseline(server model.DatabaseServer, settings model.DatabaseHardening) error
+
+	// RemoveDatabaseBaseline removes the managed configuration drop-in
+	RemoveDatabaseBaseline(server model.DatabaseServer) error
+
+	// ValidateDatabaseConfig checks the configuration, returning the
+	// server's output on failure
+	ValidateDatabaseConfig(server model.DatabaseServer) (string, error)
+
+	// RestartDatabase restarts the server so listen settings take effect
+	RestartDatabase(server model.DatabaseServer) error
+}
@@ -0,0 +1,22 @@
+package secondary
+
+// DebloatRepository defines the interface for finding and removing
+// insecure or unneeded packages and services
+type DebloatRepository interface {
+	// InstalledPackages returns the packages of names that are installed
+	InstalledPackages(names []string) ([]string, error)
+
+	// RunningServices returns the services of names that are running or
+	// enabled at boot
+	RunningServices(names []string) ([]string, error)
+
+	// RemovalDependents returns the other installed packages the package
+	// manager would remove along with packages
+	RemovalDependents(packages []string) ([]string, error)
+
+	// RemovePackages removes packages, keeping their configuration files
+	RemovePackages(packages []string) error
+
+	// DisableService stops a service and keeps it from starting at boot
+	DisableService(name string) error
+}
@@ -0,0 +1,23 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// EvidenceRepository defines the interface for collecting and writing
+// evidence bundles
+type EvidenceRepository interface {
+	// ReadFile returns a file's contents, or nil when it does not exist
+	ReadFile(path string) ([]byte, error)
+
+	// ListBackups lists the file backups under a backup directory
+	ListBackups(dir string) ([]model.EvidenceBackup, error)
+
+	// LoadTemplate reads an evidence template from a YAML file
+	LoadTemplate(path string) (model.EvidenceTemplate, error)
+
+	// WriteBundle writes the items to a gzip-compressed tar archive
+	WriteBundle(path string, items []model.EvidenceItem) error
+
+	// EncryptBundle encrypts a bundle for a GnuPG recipient and removes the
+	// unencrypted file, returning the encrypted file's path
+	EncryptBundle(path string, recipient string) (string, error)
+}
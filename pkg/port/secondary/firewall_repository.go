@@ -21,6 +21,9 @@ type FirewallRepository interface {
 	// RemoveRule removes a firewall rule
 	RemoveRule(rule model.FirewallRule) error
 
+	// RuleCommand returns the command AddRule runs for a rule
+	RuleCommand(rule model.FirewallRule) string
+
 	// Add  a firewall application profile
 	AddProfile(profile model.FirewallProfile) error
 
@@ -29,4 +32,38 @@ type FirewallRepository interface {
 
 	// DisableFirewall disables the firewall
 	DisableFirewall() error
+
+	// InstallFirewall installs the firewall package
+	InstallFirewall() error
+
+	// ApplyProfiles writes and enables firewall application profiles
+	ApplyProfiles(profiles []model.FirewallProfile) error
+
+	// GetStackRules returns the incoming rules for IPv4 and IPv6
+	GetStackRules() (ipv4 model.StackRules, ipv6 model.StackRules, err error)
+
+	// FilterFamily makes the firewall filter an IP family it leaves open
+	FilterFamily(family string) error
+
+	// GetRules lists the live incoming rules in the form of
+	// FirewallRule.Spec, including those added outside hardn
+	GetRules() ([]string, error)
+
+	// ConfigurePortKnocking installs and starts knockd to open the SSH port
+	// on the knock sequence; nil stops knockd
+	ConfigurePortKnocking(sshPort int, knocking *model.PortKnocking) error
+
+	// PortKnockingActive reports whether knockd runs with hardn's sequence
+	PortKnockingActive() bool
+
+	// GetIPv6Kernel reports whether the kernel runs IPv6 and whether its
+	// command line turns IPv6 off
+	GetIPv6Kernel() (enabled bool, bootDisabled bool)
+
+	// SetIPv6Kernel turns IPv6 off with sysctl, or on the kernel command
+	// line with bootParameter, or undoes what hardn turned off
+	SetIPv6Kernel(disabled bool, bootParameter bool) error
+
+	// MirrorRule adds a rule, as listed in StackRules, to a family lacking it
+	MirrorRule(rule string, family string) error
 }
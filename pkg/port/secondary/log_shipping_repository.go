@@ -0,0 +1,19 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// LogShippingRepository defines the interface for forwarding logs to a collector
+type LogShippingRepository interface {
+	// CheckConnection opens a TLS connection to the collector with the
+	// certificates of the policy
+	CheckConnection(policy model.LogShippingPolicy) error
+
+	// ConfigureLogShipping checks and installs the certificates, installs
+	// the daemon of the policy if needed, points it at the collector and
+	// restarts it
+	ConfigureLogShipping(policy model.LogShippingPolicy) error
+
+	// GetLogShippingStatus reports the configured forwarding and whether
+	// the daemon is connected to the collector
+	GetLogShippingStatus() (*model.LogShippingStatus, error)
+}
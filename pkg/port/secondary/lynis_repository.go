@@ -0,0 +1,14 @@
+package secondary
+
+// LynisRepository defines the interface for running Lynis scans
+type LynisRepository interface {
+	// IsInstalled reports whether the lynis command is available
+	IsInstalled() bool
+
+	// RunAudit runs "lynis audit system", writing the report file
+	RunAudit() error
+
+	// ReadReport returns the report file of the last scan, or nil when
+	// there is none
+	ReadReport() ([]byte, error)
+}
@@ -0,0 +1,20 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// MACRepository defines the interface for mandatory access control (AppArmor
+// and SELinux) operations
+type MACRepository interface {
+	// GetMACStatus returns the active framework and its mode
+	GetMACStatus() (*model.MACStatus, error)
+
+	// InstallMAC installs the framework's tools and policy packages
+	InstallMAC(framework string) error
+
+	// EnforceMAC puts the framework in enforcing mode now where the kernel
+	// allows it, and from the next boot on
+	EnforceMAC(framework string) error
+
+	// LabelSSHPort lets sshd bind a non-default port under SELinux
+	LabelSSHPort(port int) error
+}
@@ -0,0 +1,12 @@
+package secondary
+
+// MigrationRepository defines the interface for reading the configuration
+// other hardening tools left on a host
+type MigrationRepository interface {
+	// ReadArtifact returns a file, or nil when it does not exist
+	ReadArtifact(path string) ([]byte, error)
+
+	// ListArtifacts returns the files directly inside dir, sorted, or nil
+	// when the directory does not exist
+	ListArtifacts(dir string) ([]string, error)
+}
@@ -0,0 +1,18 @@
+package secondary
+
+// ModuleBlacklistRepository defines the interface for kernel module blacklists
+type ModuleBlacklistRepository interface {
+	// WriteBlacklist replaces hardn's modprobe.d file with one that keeps
+	// each module from loading
+	WriteBlacklist(modules []string) error
+
+	// GetDisabledModules lists the modules the modprobe configuration keeps
+	// from loading, in the kernel's spelling
+	GetDisabledModules() ([]string, error)
+
+	// GetLoadedModules lists the modules loaded now
+	GetLoadedModules() ([]string, error)
+
+	// SnapdInstalled reports whether snapd, which mounts squashfs images, is installed
+	SnapdInstalled() bool
+}
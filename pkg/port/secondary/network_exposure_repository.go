@@ -0,0 +1,11 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// NetworkExposureRepository defines the interface for listing the sockets
+// the host accepts connections on
+type NetworkExposureRepository interface {
+	// GetListeningSockets lists listening TCP and UDP sockets with the
+	// owning process and package where they can be found
+	GetListeningSockets() ([]model.ListeningSocket, error)
+}
@@ -0,0 +1,13 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// Notifier defines the interface for delivering run summaries, such as by
+// email or to a chat webhook
+type Notifier interface {
+	// Name identifies the notifier in errors
+	Name() string
+
+	// Send delivers the summary
+	Send(summary model.RunSummary) error
+}
@@ -0,0 +1,23 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// PackageAuditRepository defines the interface for listing installed
+// packages and checking them against security advisories
+type PackageAuditRepository interface {
+	// InstalledPackages lists the installed packages with their versions
+	InstalledPackages() ([]model.InstalledPackage, error)
+
+	// AdvisorySource names where advisories come from on this distribution,
+	// or returns an empty string when there is no source
+	AdvisorySource() string
+
+	// CheckAdvisories returns the advisories affecting the packages
+	CheckAdvisories(packages []model.InstalledPackage) ([]model.PackageVulnerability, error)
+
+	// LoadReport returns the last check, or nil when there is none
+	LoadReport() (*model.PackageAuditReport, error)
+
+	// SaveReport keeps a check for the status display
+	SaveReport(report *model.PackageAuditReport) error
+}
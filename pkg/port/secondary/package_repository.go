@@ -14,9 +14,33 @@ type PackageRepository interface {
 	// UpdateProxmoxSources updates Proxmox-specific package sources
 	UpdateProxmoxSources(sources model.PackageSources) error
 
+	// SourcesFormat resolves a sourcesFormat setting to list or deb822
+	SourcesFormat(setting string) string
+
+	// ReadAptSources reads sources.list and the lists in sources.list.d
+	ReadAptSources() ([]model.AptFile, error)
+
+	// ReadSourceFile reads a repository list, such as an APT list or /etc/apk/repositories
+	ReadSourceFile(path string) ([]byte, error)
+
+	// ReplaceAptSources writes a converted list and moves the one it replaces aside
+	ReplaceAptSources(from, to string, data []byte) error
+
 	// IsPackageInstalled checks if a package is installed
 	IsPackageInstalled(packageName string) (bool, error)
 
 	// GetPackageSources retrieves the current package sources configuration
 	GetPackageSources() (*model.PackageSources, error)
+
+	// ConfigureAutoUpgrades installs or removes the scheduled upgrade job
+	ConfigureAutoUpgrades(enable bool) error
+
+	// IsAutoUpgradeEnabled checks if the scheduled upgrade job is installed
+	IsAutoUpgradeEnabled() (bool, error)
+
+	// ConfigureNeedrestart installs needrestart and sets its restart mode
+	ConfigureNeedrestart(mode string) error
+
+	// GetNeedrestartStatus reports the needrestart mode and pending restarts
+	GetNeedrestartStatus() (*model.NeedrestartStatus, error)
 }
@@ -0,0 +1,15 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// PasswordPolicyRepository defines the interface for the local password policy
+type PasswordPolicyRepository interface {
+	// ConfigurePasswordPolicy writes the password strength rules for
+	// pam_pwquality, the aging of new accounts in login.defs and the
+	// pam_faillock lockout. Alpine, whose busybox login does not use PAM,
+	// only gets the aging.
+	ConfigurePasswordPolicy(policy model.PasswordPolicy) error
+
+	// GetPasswordPolicyStatus reports the password policy in effect
+	GetPasswordPolicyStatus() (*model.PasswordPolicyStatus, error)
+}
@@ -0,0 +1,9 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// PolicyRepository defines the interface for evaluating operator policies
+type PolicyRepository interface {
+	// EvaluatePolicies evaluates the policies in policyDir against the facts
+	EvaluatePolicies(policyDir string, facts *model.Facts) ([]model.PolicyViolation, error)
+}
@@ -0,0 +1,13 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// PostureHistoryRepository defines the interface for the security posture history
+type PostureHistoryRepository interface {
+	// AppendSnapshot adds a snapshot to the history, keeping the latest
+	// keep snapshots
+	AppendSnapshot(snapshot model.PostureSnapshot, keep int) error
+
+	// ListSnapshots returns the snapshots in the history, oldest first
+	ListSnapshots() ([]model.PostureSnapshot, error)
+}
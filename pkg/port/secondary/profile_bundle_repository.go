@@ -0,0 +1,35 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// ProfileBundleRepository defines the interface for reading, writing and
+// signing hardening profile bundles
+type ProfileBundleRepository interface {
+	// ReadFile returns a file's contents, or nil when it does not exist
+	ReadFile(path string) ([]byte, error)
+
+	// WriteFile writes a file, creating its directory
+	WriteFile(path string, data []byte) error
+
+	// ListSysctlFiles lists the regular .conf files of the sysctl drop-in
+	// directory
+	ListSysctlFiles() ([]string, error)
+
+	// ReloadSysctl applies the sysctl configuration files
+	ReloadSysctl() error
+
+	// ParseBundle parses the data of a profile bundle read from path
+	ParseBundle(path string, data []byte) (*model.ProfileBundle, error)
+
+	// WriteBundle writes a profile bundle readable only by root
+	WriteBundle(path string, bundle *model.ProfileBundle) error
+
+	// SignBundle writes a detached signature of a bundle with a GnuPG key
+	// and returns its path
+	SignBundle(path string, key string) (string, error)
+
+	// VerifyBundle checks the detached signature of a bundle against its
+	// data and returns the fingerprint of the signer, one of signers. It
+	// returns "" without an error when the bundle has no signature.
+	VerifyBundle(path string, data []byte, signers []string) (string, error)
+}
@@ -0,0 +1,27 @@
+package secondary
+
+// ProxmoxRepository defines the interface for Proxmox VE host settings
+type ProxmoxRepository interface {
+	// DisableSubscriptionNag patches the web UI's subscription dialog out
+	// and installs an apt hook that patches it again after upgrades
+	DisableSubscriptionNag() error
+
+	// SubscriptionNagDisabled reports whether the web UI is patched
+	SubscriptionNagDisabled() (bool, error)
+
+	// ReadPveproxyDefaults reads the variables of /etc/default/pveproxy
+	ReadPveproxyDefaults() (map[string]string, error)
+
+	// WritePveproxyDefaults sets variables of /etc/default/pveproxy, keeping
+	// the others; an empty value removes a variable
+	WritePveproxyDefaults(settings map[string]string) error
+
+	// RestartPveproxy restarts the web UI to apply its settings
+	RestartPveproxy() error
+
+	// GetRealmTwoFactor returns the second factor a realm requires, or ""
+	GetRealmTwoFactor(realm string) (string, error)
+
+	// SetRealmTwoFactor requires a second factor for a realm's users
+	SetRealmTwoFactor(realm, tfaType string) error
+}
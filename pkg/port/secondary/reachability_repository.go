@@ -0,0 +1,11 @@
+package secondary
+
+import "time"
+
+// ReachabilityRepository defines the interface for probing the SSH server
+type ReachabilityRepository interface {
+	// ProbeSSH checks that an SSH server answers on address and port. With
+	// a helper, the probe runs on the helper host over ssh, so it passes
+	// through the firewall like any other client.
+	ProbeSSH(helper string, address string, port int, timeout time.Duration) error
+}
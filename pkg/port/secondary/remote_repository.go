@@ -0,0 +1,21 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// RemoteRepository defines the interface for running hardn on other hosts
+// over SSH
+type RemoteRepository interface {
+	// LoadInventory reads an inventory from a YAML file
+	LoadInventory(path string) (*model.RemoteInventory, error)
+
+	// PushBinary copies a hardn binary built for this host's architecture
+	// to a new temporary directory on a host, returning its remote path
+	PushBinary(host model.RemoteHost, binary string) (string, error)
+
+	// RemoveBinary removes a pushed binary and its directory
+	RemoveBinary(host model.RemoteHost, path string) error
+
+	// RunHardn runs hardn on a host and returns its output and exit code.
+	// An error means hardn could not be run at all.
+	RunHardn(host model.RemoteHost, hardn string, args []string) ([]byte, int, error)
+}
@@ -0,0 +1,34 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// RepositoryTrustRepository defines the interface for reading the repository
+// lists and trusted keys of the package manager and installing signing keys
+type RepositoryTrustRepository interface {
+	// ReadSources returns sources.list and the .list and .sources files of
+	// sources.list.d that APT reads
+	ReadSources() ([]model.AptFile, error)
+
+	// ReadAptConfig returns apt.conf and the files of apt.conf.d that APT reads
+	ReadAptConfig() ([]model.AptFile, error)
+
+	// TrustedKeys returns the keys trusted for every repository: the apt-key
+	// keyring and trusted.gpg.d, or /etc/apk/keys on Alpine
+	TrustedKeys() ([]model.TrustedKey, error)
+
+	// DownloadKey fetches a key over HTTPS
+	DownloadKey(url string) ([]byte, error)
+
+	// KeyFingerprints returns the fingerprints of the primary keys in an
+	// OpenPGP key file
+	KeyFingerprints(key []byte) ([]string, error)
+
+	// InstallKey writes a key file readable by everyone
+	InstallKey(path string, key []byte) error
+
+	// WriteSources writes a repository list to sources.list.d
+	WriteSources(path string, data []byte) error
+
+	// RemoveSources removes a repository list, reporting whether it existed
+	RemoveSources(path string) (bool, error)
+}
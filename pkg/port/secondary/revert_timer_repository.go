@@ -0,0 +1,18 @@
+package secondary
+
+import "time"
+
+// RevertTimerRepository defines the interface for timers that roll a run
+// back unless they are disarmed in time. Timers run outside hardn, so
+// they fire even when hardn is killed with the session it runs in.
+type RevertTimerRepository interface {
+	// ArmRevert starts a timer running the rollback command after the delay
+	ArmRevert(runID string, rollback []string, after time.Duration) error
+
+	// ExtendRevert restarts the delay of the run's timer
+	ExtendRevert(runID string) error
+
+	// DisarmRevert stops the run's timer. It reports false when no timer
+	// was armed, or the timer has already fired.
+	DisarmRevert(runID string) (bool, error)
+}
@@ -0,0 +1,19 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// RunReportRepository defines the interface for hardening run reports
+type RunReportRepository interface {
+	// SaveReport writes a report, keeping the latest keep reports
+	SaveReport(report model.RunReport, keep int) error
+
+	// GetReport reads the report with the given ID
+	GetReport(id string) (*model.RunReport, error)
+
+	// ListReports returns the reports, oldest first
+	ListReports() ([]model.RunReport, error)
+
+	// RunChanges lists the changes of a recorded run, with the diff of
+	// each file from its saved copy to its current content
+	RunChanges(runID string) ([]model.RunReportChange, error)
+}
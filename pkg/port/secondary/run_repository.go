@@ -0,0 +1,19 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// RunRepository defines the interface for recorded hardening runs
+type RunRepository interface {
+	// ListRuns returns the recorded runs, oldest first
+	ListRuns() ([]model.RunManifest, error)
+
+	// GetRun returns the run with the given ID
+	GetRun(id string) (*model.RunManifest, error)
+
+	// SaveRun stores the manifest of a run
+	SaveRun(run model.RunManifest) error
+
+	// RevertChange restores the state from before a change. Files changed
+	// since the run are left alone unless force is set.
+	RevertChange(runID string, change model.RunChange, force bool) error
+}
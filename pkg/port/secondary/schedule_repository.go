@@ -0,0 +1,15 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// ScheduleRepository defines the interface for installing scheduled hardn runs
+type ScheduleRepository interface {
+	// InstallSchedule writes and enables the systemd timer or crontab entry
+	InstallSchedule(schedule model.Schedule) error
+
+	// RemoveSchedule disables and deletes the installed schedule
+	RemoveSchedule() error
+
+	// GetScheduleStatus reads the installed schedule
+	GetScheduleStatus() (*model.ScheduleStatus, error)
+}
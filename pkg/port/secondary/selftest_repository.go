@@ -0,0 +1,25 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// SelfTestRepository defines the interface for validating hardn-managed
+// configuration after it was written
+type SelfTestRepository interface {
+	// ValidateSudoers checks /etc/sudoers and its drop-ins with visudo
+	ValidateSudoers() model.SelfTestCheck
+
+	// ValidateSSHConfig checks the sshd configuration with sshd -t
+	ValidateSSHConfig() model.SelfTestCheck
+
+	// ValidateFirewall asks the installed firewall to load its rules
+	ValidateFirewall() model.SelfTestCheck
+
+	// ReadResolvConf returns /etc/resolv.conf, or nil when it does not exist
+	ReadResolvConf() ([]byte, error)
+
+	// ReadSysctlConfigs returns the sysctl configuration files by path
+	ReadSysctlConfigs() (map[string][]byte, error)
+
+	// SysctlKeyExists reports whether the running kernel has a sysctl key
+	SysctlKeyExists(key string) bool
+}
@@ -0,0 +1,28 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// ServiceManager defines the interface for managing system services through
+// the host's init system (systemd or OpenRC)
+type ServiceManager interface {
+	// InitSystem returns the init system the services are managed with
+	InitSystem() string
+
+	// Enable starts a service now and at boot
+	Enable(name string) error
+
+	// Disable stops a service now and keeps it from starting at boot
+	Disable(name string) error
+
+	// Start starts a service
+	Start(name string) error
+
+	// Stop stops a service
+	Stop(name string) error
+
+	// Restart restarts a service, starting it if it was stopped
+	Restart(name string) error
+
+	// Status reports whether a service is running and enabled at boot
+	Status(name string) (*model.ServiceStatus, error)
+}
@@ -0,0 +1,12 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// ShareRepository defines the interface for reading NFS and Samba shares
+type ShareRepository interface {
+	// GetNFSExports returns the exports in /etc/exports; nil when absent
+	GetNFSExports() ([]model.NFSExport, error)
+
+	// GetSambaConfig returns the parsed smb.conf; nil when absent
+	GetSambaConfig() (*model.SambaConfig, error)
+}
@@ -0,0 +1,18 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// SnapshotRepository defines the interface for root filesystem snapshots
+type SnapshotRepository interface {
+	// DetectSnapshotSupport checks whether / sits on LVM thin, ZFS or btrfs
+	DetectSnapshotSupport() (*model.SnapshotSupport, error)
+
+	// CreateSnapshot snapshots the root filesystem under the given name
+	CreateSnapshot(name string) (*model.Snapshot, error)
+
+	// ListSnapshots returns snapshots created by hardn, oldest first
+	ListSnapshots() ([]model.Snapshot, error)
+
+	// RollbackSnapshot reverts the root filesystem to the snapshot
+	RollbackSnapshot(snapshot model.Snapshot) error
+}
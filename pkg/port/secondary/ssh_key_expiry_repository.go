@@ -0,0 +1,14 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// SSHKeyExpiryRepository defines the interface for the expiry dates SSH
+// keys are tagged with
+type SSHKeyExpiryRepository interface {
+	// LoadKeyExpiries returns the tagged keys; none are tagged before the
+	// first save
+	LoadKeyExpiries() ([]model.SSHKeyExpiry, error)
+
+	// SaveKeyExpiries replaces the tagged keys
+	SaveKeyExpiries(expiries []model.SSHKeyExpiry) error
+}
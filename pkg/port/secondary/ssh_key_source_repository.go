@@ -0,0 +1,9 @@
+package secondary
+
+// SSHKeySourceRepository defines the interface for fetching the public keys
+// an account publishes on a code hosting service
+type SSHKeySourceRepository interface {
+	// FetchPublicKeys returns the published keys, one per entry, for an
+	// account at provider (github or gitlab)
+	FetchPublicKeys(provider string, account string) ([]string, error)
+}
@@ -16,4 +16,20 @@ type SSHRepository interface {
 
 	// add an SSH public key to a user's authorized_keys
 	AddAuthorizedKey(username string, publicKey string) error
+
+	// GetEffectiveSSHConfig returns the settings sshd -T reports, keyed by
+	// lowercase directive; nil when sshd is not installed
+	GetEffectiveSSHConfig() (map[string][]string, error)
+
+	// GetSSHConfigSources lists the global directives of sshd_config and the
+	// files it includes, in the order sshd reads them
+	GetSSHConfigSources() ([]model.SSHDirectiveSource, error)
+
+	// ListSSHDaemons lists the SSH servers running, sshd or dropbear
+	ListSSHDaemons() ([]model.SSHDaemon, error)
+
+	// DisableSSHDirectives comments out configuration lines so the values in
+	// managedFile take effect, then restarts sshd. The files are restored if
+	// sshd rejects the result.
+	DisableSSHDirectives(sources []model.SSHDirectiveSource, managedFile string) error
 }
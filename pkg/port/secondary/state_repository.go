@@ -0,0 +1,24 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// StateRepository defines the interface for reading state files and the
+// sysctl settings 'hardn apply' manages
+type StateRepository interface {
+	// ReadState parses a state file, rejecting unknown fields
+	ReadState(path string) (*model.DesiredState, error)
+
+	// GetSysctl returns the running kernel's value of a key, and false
+	// when the kernel has no such key
+	GetSysctl(key string) (string, bool)
+
+	// SetSysctl sets a key in the running kernel
+	SetSysctl(key, value string) error
+
+	// ReadStateSysctl returns the settings of model.StateSysctlPath, empty
+	// when the file does not exist
+	ReadStateSysctl() (map[string]string, error)
+
+	// WriteStateSysctl replaces model.StateSysctlPath with the settings
+	WriteStateSysctl(values map[string]string) error
+}
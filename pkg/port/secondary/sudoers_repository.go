@@ -0,0 +1,27 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// SudoersRepository defines the interface for reading the sudo policy and
+// installing per-user sudoers drop-ins
+type SudoersRepository interface {
+	// ReadSudoers returns /etc/sudoers and the files it includes, in the
+	// order sudo reads them
+	ReadSudoers() ([]model.SudoersFile, error)
+
+	// ReadUserSudoers returns a user's drop-in in /etc/sudoers.d; nil when absent
+	ReadUserSudoers(username string) ([]byte, error)
+
+	// WriteUserSudoers checks content with visudo and only then installs it
+	// as the user's drop-in
+	WriteUserSudoers(username string, content []byte) error
+
+	// InstalledCommands returns the paths that exist on this host
+	InstalledCommands(paths []string) []string
+
+	// UserGroups returns the groups a user is a member of
+	UserGroups(username string) ([]string, error)
+
+	// RemoveFromGroup removes a user from a supplementary group
+	RemoveFromGroup(username, group string) error
+}
@@ -0,0 +1,18 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// TCPWrappersRepository defines the interface for the legacy TCP wrappers
+// access files, /etc/hosts.allow and /etc/hosts.deny
+type TCPWrappersRepository interface {
+	// GetHostsAccessRules reads the rules of both files; a missing file has
+	// no rules
+	GetHostsAccessRules() (allow []model.HostsAccessRule, deny []model.HostsAccessRule, err error)
+
+	// DaemonUsesLibwrap reports whether the daemon's binary is linked
+	// against libwrap; found is false when the daemon is not installed
+	DaemonUsesLibwrap(daemon string) (uses bool, found bool)
+
+	// DisableHostsAccess comments out the rules of both files
+	DisableHostsAccess() error
+}
@@ -0,0 +1,14 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// TimeSyncRepository defines the interface for the time synchronization daemon
+type TimeSyncRepository interface {
+	// ConfigureTimeSync installs the daemon of the policy if needed, writes
+	// its servers, stops the other daemons and starts it
+	ConfigureTimeSync(policy model.TimeSyncPolicy) error
+
+	// GetTimeSyncStatus reports the daemon running and how well it keeps
+	// the clock synchronized
+	GetTimeSyncStatus() (*model.TimeSyncStatus, error)
+}
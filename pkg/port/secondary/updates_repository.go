@@ -0,0 +1,25 @@
+package secondary
+
+import (
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// UpdatesRepository defines the interface for automatic upgrade configuration
+type UpdatesRepository interface {
+	// ConfigureUpdates writes the upgrade policy and enables automatic
+	// upgrades: the unattended-upgrades configuration on Debian and Ubuntu,
+	// the daily periodic script on Alpine
+	ConfigureUpdates(policy model.UpdatesPolicy) error
+
+	// DisableUpdates stops automatic upgrades
+	DisableUpdates() error
+
+	// GetUpdatesStatus reports whether automatic upgrades are enabled
+	GetUpdatesStatus() (*model.UpdatesStatus, error)
+
+	// AppliedUpdates returns the package upgrades installed since the given
+	// time, oldest first, read from the package manager's log
+	AppliedUpdates(since time.Time) ([]model.AppliedUpdate, error)
+}
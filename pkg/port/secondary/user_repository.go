@@ -7,13 +7,34 @@ type UserRepository interface {
 	CreateUser(user model.User) error
 	GetUser(username string) (*model.User, error)
 	AddSSHKey(username, publicKey string) error
+	RemoveSSHKey(username, fingerprint string) error
+
+	// GetAuthorizedKeys returns the entries of a user's authorized_keys file
+	GetAuthorizedKeys(username string) ([]string, error)
+
 	ConfigureSudo(username string, noPassword bool) error
+
+	// LockUser locks the account's password and expires it so no login method works
+	LockUser(username string) error
+
 	UserExists(username string) (bool, error)
 	GetExtendedUserInfo(username string) (*model.User, error)
 
+	// GetDirectoryAuthStatus reports whether NSS resolves users from a directory service
+	GetDirectoryAuthStatus() (*model.DirectoryAuthStatus, error)
+
+	// IsDirectoryUser reports whether a user is provided by a directory service rather than /etc/passwd
+	IsDirectoryUser(username string) (bool, error)
+
 	// GetNonSystemUsers retrieves non-system users on the system
 	GetNonSystemUsers() ([]model.User, error)
 
+	// ListNonSystemUsers retrieves a page of non-system users, optionally local only
+	ListNonSystemUsers(opts model.UserListOptions) (*model.UserPage, error)
+
 	// GetNonSystemGroups retrieves non-system groups on the system
 	GetNonSystemGroups() ([]string, error)
+
+	// ReadImportFile reads a CSV file of users to import
+	ReadImportFile(path string) ([]byte, error)
 }
@@ -0,0 +1,22 @@
+package secondary
+
+import "github.com/abbott/hardn/pkg/domain/model"
+
+// WebServerRepository defines the interface for web server TLS configuration
+type WebServerRepository interface {
+	// DetectWebServers returns the supported web servers installed on the host
+	DetectWebServers() ([]model.WebServer, error)
+
+	// WriteTLSBaseline installs the managed TLS snippet for a web server
+	WriteTLSBaseline(server model.WebServer) error
+
+	// RemoveTLSBaseline removes the managed TLS snippet for a web server
+	RemoveTLSBaseline(server model.WebServer) error
+
+	// ValidateConfig checks the web server configuration, returning the
+	// validator's output on failure
+	ValidateConfig(server model.WebServer) (string, error)
+
+	// ReloadWebServer reloads the web server configuration
+	ReloadWebServer(server model.WebServer) error
+}
@@ -0,0 +1,102 @@
+// pkg/schema/compat.go
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Compatible checks that a schema only evolved from a released one in the
+// ways a version allows: properties may be added, but none is removed,
+// changes type or format, or stops being required. It returns every
+// breaking change found.
+func Compatible(released, current []byte) error {
+	var before, after map[string]any
+	if err := json.Unmarshal(released, &before); err != nil {
+		return fmt.Errorf("failed to parse released schema: %w", err)
+	}
+	if err := json.Unmarshal(current, &after); err != nil {
+		return fmt.Errorf("failed to parse current schema: %w", err)
+	}
+	if before["$id"] != after["$id"] {
+		return fmt.Errorf("$id changed from %v to %v", before["$id"], after["$id"])
+	}
+
+	var breaking []string
+	compare("", before, after, &breaking)
+	if len(breaking) > 0 {
+		return fmt.Errorf("breaking changes:\n  %s", strings.Join(breaking, "\n  "))
+	}
+	return nil
+}
+
+// compare adds the breaking changes between two subschemas at path
+func compare(path string, before, after map[string]any, breaking *[]string) {
+	at := path
+	if at == "" {
+		at = "(root)"
+	}
+	for _, key := range []string{"type", "format"} {
+		if !reflect.DeepEqual(before[key], after[key]) {
+			*breaking = append(*breaking, fmt.Sprintf("%s: %s changed from %v to %v", at, key, before[key], after[key]))
+		}
+	}
+
+	required := make(map[string]bool)
+	for _, name := range stringList(after["required"]) {
+		required[name] = true
+	}
+	for _, name := range stringList(before["required"]) {
+		if !required[name] {
+			*breaking = append(*breaking, fmt.Sprintf("%s: %s is no longer required", at, name))
+		}
+	}
+
+	beforeProperties := object(before["properties"])
+	afterProperties := object(after["properties"])
+	for _, name := range sortedKeys(beforeProperties) {
+		child := strings.TrimPrefix(path+"."+name, ".")
+		if _, ok := afterProperties[name]; !ok {
+			*breaking = append(*breaking, fmt.Sprintf("%s: removed", child))
+			continue
+		}
+		compare(child, object(beforeProperties[name]), object(afterProperties[name]), breaking)
+	}
+
+	if items := object(before["items"]); items != nil {
+		compare(path+"[]", items, object(after["items"]), breaking)
+	}
+	if values := object(before["additionalProperties"]); values != nil {
+		compare(path+"{}", values, object(after["additionalProperties"]), breaking)
+	}
+}
+
+// object returns v as a subschema, or nil
+func object(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+// stringList returns the strings of a JSON array
+func stringList(v any) []string {
+	items, _ := v.([]any)
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
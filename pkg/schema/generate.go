@@ -0,0 +1,156 @@
+// pkg/schema/generate.go
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// maxDepth guards against recursive types, which no document has
+const maxDepth = 16
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Generate builds a document's schema from its Go type. Keys are sorted,
+// so the result only changes when the type does.
+func Generate(doc Document) ([]byte, error) {
+	root, err := typeSchema(reflect.TypeOf(doc.value), doc.tag, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate the %s schema: %w", doc.Name, err)
+	}
+	root["$schema"] = Draft
+	root["$id"] = doc.ID()
+	root["title"] = doc.Title
+	root["description"] = "Produced by " + doc.Output
+
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the %s schema: %w", doc.Name, err)
+	}
+	return append(data, '\n'), nil
+}
+
+// typeSchema returns the schema of a value of type t as encoded by
+// encoding/json, or by yaml.v3 for the yaml tag
+func typeSchema(t reflect.Type, tag string, depth int) (map[string]any, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("type %s nests too deeply", t)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Interface:
+		return map[string]any{}, nil
+
+	case reflect.Pointer:
+		inner, err := typeSchema(t.Elem(), tag, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return nullable(inner), nil
+
+	case reflect.Slice, reflect.Array:
+		items, err := typeSchema(t.Elem(), tag, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": []any{"array", "null"}, "items": items}, nil
+
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map key of %s is not a string", t)
+		}
+		values, err := typeSchema(t.Elem(), tag, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": []any{"object", "null"}, "additionalProperties": values}, nil
+
+	case reflect.Struct:
+		if t == timeType {
+			return map[string]any{"type": "string", "format": "date-time"}, nil
+		}
+		properties := make(map[string]any)
+		var required []string
+		if err := addFields(t, tag, depth, properties, &required); err != nil {
+			return nil, err
+		}
+		schema := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema, nil
+	}
+	return nil, fmt.Errorf("type %s has no JSON encoding", t)
+}
+
+// addFields adds the encoded fields of a struct, including those of
+// embedded structs. In JSON documents, fields without omitempty are always
+// present and so required; configuration files may leave out any field.
+func addFields(t reflect.Type, tag string, depth int, properties map[string]any, required *[]string) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+		inline := strings.Contains(options, "inline") || (field.Anonymous && name == "" && tag == "json")
+		if inline && field.Type.Kind() == reflect.Struct {
+			if err := addFields(field.Type, tag, depth, properties, required); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+			if tag == "yaml" {
+				name = strings.ToLower(name)
+			}
+		}
+
+		fieldSchema, err := typeSchema(field.Type, tag, depth+1)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		omitEmpty := strings.Contains(options, "omitempty")
+		if omitEmpty {
+			// Empty values are left out, so a present value is never null
+			fieldSchema = notNull(fieldSchema)
+		} else if tag == "json" {
+			*required = append(*required, name)
+		}
+		properties[name] = fieldSchema
+	}
+	return nil
+}
+
+// nullable allows null in place of the schema's value. A schema without a
+// type already allows any value, null included.
+func nullable(schema map[string]any) map[string]any {
+	if types, ok := schema["type"].(string); ok {
+		schema["type"] = []any{types, "null"}
+	}
+	return schema
+}
+
+// notNull removes null from the types a schema allows
+func notNull(schema map[string]any) map[string]any {
+	if types, ok := schema["type"].([]any); ok && len(types) == 2 && types[1] == "null" {
+		schema["type"] = types[0]
+	}
+	return schema
+}
@@ -0,0 +1,98 @@
+// pkg/schema/schema.go
+package schema
+
+import (
+	"embed"
+	"fmt"
+	"sort"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/security"
+)
+
+// Draft is the JSON Schema dialect of every published schema
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+//go:embed schemas
+var files embed.FS
+
+// Document is a machine-readable output of hardn and the Go type it is
+// encoded from. Version is the major version of its schema: within a
+// version properties may be added, but none is removed, changes type or
+// stops being required. Any other change needs the next version, released
+// next to the earlier ones.
+type Document struct {
+	Name    string
+	Title   string
+	Version int
+	Output  string // command or file producing the document
+
+	value any    // zero value of the encoded type
+	tag   string // struct tag naming the fields: json or yaml
+}
+
+// Documents lists every output with a published schema
+var Documents = []Document{
+	{Name: "report", Title: "hardn audit report", Version: model.AuditReportFormat,
+		Output: "hardn audit report", value: model.AuditReport{}, tag: "json"},
+	{Name: "diff", Title: "hardn audit report comparison", Version: 1,
+		Output: "hardn audit diff --format json", value: model.AuditDiff{}, tag: "json"},
+	{Name: "facts", Title: "hardn host facts", Version: 1,
+		Output: "hardn policy eval --facts FILE", value: model.Facts{}, tag: "json"},
+	{Name: "status", Title: "hardn security status", Version: 1,
+		Output: "hardn status --json", value: security.StatusReport{}, tag: "json"},
+	{Name: "selftest", Title: "hardn self-test results", Version: 1,
+		Output: "hardn selftest --json", value: model.SelfTestReport{}, tag: "json"},
+	{Name: "run", Title: "hardn run report", Version: 1,
+		Output: "hardn report show ID --format json", value: model.RunReport{}, tag: "json"},
+	{Name: "compliance", Title: "hardn CIS compliance report", Version: 1,
+		Output: "hardn compliance --format json", value: model.ComplianceReport{}, tag: "json"},
+	{Name: "config", Title: "hardn configuration file", Version: 1,
+		Output: "hardn.yml", value: config.Config{}, tag: "yaml"},
+	{Name: "profile", Title: "hardn profile bundle", Version: model.ProfileBundleFormat,
+		Output: "hardn profile export", value: model.ProfileBundle{}, tag: "yaml"},
+}
+
+// ID returns the $id of the document's schema, which names its version
+func (d Document) ID() string {
+	return fmt.Sprintf("urn:hardn:schema:%s:v%d", d.Name, d.Version)
+}
+
+// Lookup finds a document by name
+func Lookup(name string) (Document, bool) {
+	for _, doc := range Documents {
+		if doc.Name == name {
+			return doc, true
+		}
+	}
+	return Document{}, false
+}
+
+// Names returns the name of every document, sorted
+func Names() []string {
+	names := make([]string, 0, len(Documents))
+	for _, doc := range Documents {
+		names = append(names, doc.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Published returns the schema hardn ships for a document
+func Published(name string) ([]byte, error) {
+	data, err := files.ReadFile("schemas/" + name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("no schema for %q", name)
+	}
+	return data, nil
+}
+
+// Released returns a document's schema as first released for a version
+func Released(name string, version int) ([]byte, error) {
+	data, err := files.ReadFile(fmt.Sprintf("schemas/released/%s.v%d.json", name, version))
+	if err != nil {
+		return nil, fmt.Errorf("no released schema for %q version %d", name, version)
+	}
+	return data, nil
+}
@@ -0,0 +1,378 @@
+// pkg/security/audit.go
+package security
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+// riskLevels orders the levels returned by GetSecurityRiskLevel, lowest first
+var riskLevels = []string{"minimal", "low", "moderate", "high", "critical"}
+
+// RiskRank returns the position of a risk level or finding severity on the
+// scale Minimal < Low < Moderate < High < Critical, or -1 when unknown.
+// Finding severities map onto the same scale, with medium as Moderate.
+func RiskRank(level string) int {
+	level = strings.ToLower(level)
+	if level == model.SeverityMedium {
+		level = "moderate"
+	}
+	for i, known := range riskLevels {
+		if level == known {
+			return i
+		}
+	}
+	return -1
+}
+
+// ParseFailOn returns the rank of an audit --fail-on level, or -1 for none.
+// Minimal and medium are not accepted: every host is at least minimal, and
+// the threshold is named moderate.
+func ParseFailOn(level string) (int, error) {
+	level = strings.ToLower(level)
+	if level == "none" {
+		return -1, nil
+	}
+	threshold := RiskRank(level)
+	if threshold < 0 || level == "minimal" || level == model.SeverityMedium {
+		return -1, fmt.Errorf("invalid --fail-on level: %s (use low, moderate, high, critical or none)", level)
+	}
+	return threshold, nil
+}
+
+// AuditFailed reports whether the risk level, a finding or a package
+// advisory is at or above the threshold returned by ParseFailOn. Advisories
+// of unknown severity never fail the audit.
+func AuditFailed(threshold int, riskLevel string, findings []model.PolicyViolation, packages *model.PackageAuditReport) bool {
+	if threshold < 0 {
+		return false
+	}
+	if RiskRank(riskLevel) >= threshold {
+		return true
+	}
+	for _, finding := range findings {
+		if RiskRank(finding.Severity) >= threshold {
+			return true
+		}
+	}
+	if packages != nil {
+		for _, vulnerability := range packages.Vulnerabilities {
+			if RiskRank(vulnerability.Severity) >= threshold {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AuditExitCode returns the exit status of an audit: 1 when it failed
+func AuditExitCode(failed bool) int {
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// auditCheck is a control and whether the host passes it
+type auditCheck struct {
+	id     string
+	title  string
+	passed bool
+}
+
+// control returns the audit check for a check ID, titled from Checks
+func control(id string, passed bool) auditCheck {
+	return auditCheck{id, checkTitle(id), passed}
+}
+
+// instanceControl returns the audit check for one instance of a templated
+// check such as webserver.<server>.tls_baseline
+func instanceControl(template, instance, subject string, passed bool) auditCheck {
+	return auditCheck{fillPlaceholder(template, instance), fillPlaceholder(checkTitle(template), subject), passed}
+}
+
+// BuildAuditControls maps the security status onto stable control IDs so
+// audit reports from different runs and versions can be compared
+func BuildAuditControls(status *SecurityStatus) []model.AuditControl {
+	controls := []auditCheck{
+		control("users.non_root_sudo", status.SecureUsers),
+		control("users.sudo_installed", status.SudoConfigured),
+		control("firewall.enabled", status.FirewallEnabled),
+		control("firewall.default_deny", status.FirewallConfigured),
+		control("firewall.dual_stack", status.FirewallStacks == nil || status.FirewallStacks.Synchronized()),
+		control("firewall.ipv6_policy", status.IPv6 == nil || status.IPv6.Compliant()),
+		control("firewall.no_tcp_wrappers", status.TCPWrappers == nil || !status.TCPWrappers.InUse()),
+		control("ssh.root_login_disabled", !status.RootLoginEnabled),
+		control("ssh.password_auth_disabled", status.PasswordAuthDisabled),
+		control("ssh.port_non_default", status.SshPortNonDefault),
+		macControl(status),
+		control("updates.automatic", status.UnattendedUpgrades),
+		control("system.no_pending_restarts", len(status.PendingRestarts) == 0 && !status.RebootRequired),
+	}
+
+	// One control per installed web server
+	for _, server := range status.WebServers {
+		controls = append(controls, instanceControl("webserver.<server>.tls_baseline",
+			server.Name, server.Name, server.BaselineApplied))
+	}
+
+	// One control per database server; PostgreSQL clusters are kept apart
+	for _, database := range status.Databases {
+		id := database.Name
+		if database.Instance != "" {
+			id += "." + strings.ReplaceAll(database.Instance, "/", "_")
+		}
+		controls = append(controls, instanceControl("database.<database>.baseline",
+			id, strings.TrimSuffix(database.Name+" "+database.Instance, " "), database.BaselineApplied))
+	}
+
+	// Only hosts whose modprobe configuration is readable get the control
+	if status.ModuleBlacklist != nil {
+		controls = append(controls, control("kernel.module_blacklist", status.ModuleBlacklist.Compliant()))
+	}
+
+	// Only hosts with boot hardening enabled get the control
+	if status.BootHardening != nil {
+		controls = append(controls, control("kernel.boot_parameters", status.BootHardening.Compliant()))
+	}
+
+	// Only hosts running a time synchronization daemon get the control
+	if status.Clock != nil {
+		controls = append(controls, control("system.clock_synchronized", status.Clock.Compliant()))
+	}
+
+	// Only hosts forwarding their logs get the control
+	if status.LogShipping != nil && status.LogShipping.Configured() {
+		controls = append(controls, control("logging.off_host", status.LogShipping.Shipping()))
+	}
+
+	// Only hosts with certificates in the checked locations get the control
+	if status.Certificates != nil && status.Certificates.Checked > 0 {
+		controls = append(controls, auditCheck{
+			"certificates.not_expiring",
+			fillPlaceholder(checkTitle("certificates.not_expiring"), strconv.Itoa(status.Certificates.ThresholdDays)),
+			len(status.Certificates.Expiring) == 0,
+		})
+	}
+
+	result := make([]model.AuditControl, 0, len(controls))
+	for _, control := range controls {
+		state := model.ControlFail
+		if control.passed {
+			state = model.ControlPass
+		}
+		result = append(result, model.AuditControl{
+			ID:     control.id,
+			Title:  control.title,
+			Status: state,
+		})
+	}
+	return result
+}
+
+// BuildPostureSnapshot records the score, risk level and audit controls
+// of a status check for the posture history
+func BuildPostureSnapshot(status *SecurityStatus, now time.Time, source string) model.PostureSnapshot {
+	score, total := SecurityScore(status)
+	riskLevel, _, _ := GetSecurityRiskLevel(status)
+
+	checks := make(map[string]bool)
+	for _, control := range BuildAuditControls(status) {
+		checks[control.ID] = control.Status == model.ControlPass
+	}
+
+	return model.PostureSnapshot{
+		Time:      now.UTC(),
+		Source:    source,
+		Score:     score,
+		MaxScore:  total,
+		RiskLevel: riskLevel,
+		Checks:    checks,
+	}
+}
+
+// BuildComplianceControls returns the audit controls along with the status
+// checks the CIS benchmark mapping also uses
+func BuildComplianceControls(status *SecurityStatus) []model.AuditControl {
+	controls := BuildAuditControls(status)
+	for _, check := range []auditCheck{
+		control("system.time_sync", status.TimeSync != ""),
+	} {
+		state := model.ControlFail
+		if check.passed {
+			state = model.ControlPass
+		}
+		controls = append(controls, model.AuditControl{ID: check.id, Title: check.title, Status: state})
+	}
+	return controls
+}
+
+// BuildCertificateFindings reports each expiring or expired certificate.
+// Messages name the expiry date rather than the days left so they stay the
+// same between audit runs.
+func BuildCertificateFindings(status *SecurityStatus) []model.PolicyViolation {
+	if status.Certificates == nil {
+		return nil
+	}
+
+	var findings []model.PolicyViolation
+	for _, cert := range status.Certificates.Expiring {
+		finding := model.PolicyViolation{
+			Rule:        "certificate.expiring",
+			Severity:    model.SeverityMedium,
+			Message:     fmt.Sprintf("Certificate %s (%s) expires on %s", cert.Subject, cert.Path, cert.NotAfter.Format("2006-01-02")),
+			Remediation: "Renew the certificate and reload the services using it",
+		}
+		if cert.Expired() {
+			finding.Rule = "certificate.expired"
+			finding.Severity = model.SeverityHigh
+			finding.Message = fmt.Sprintf("Certificate %s (%s) expired on %s", cert.Subject, cert.Path, cert.NotAfter.Format("2006-01-02"))
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// BuildTimeSyncFindings reports a clock that lost its source or drifts
+// from it. The offset is left out of the message so it stays the same
+// between audit runs.
+func BuildTimeSyncFindings(status *SecurityStatus) []model.PolicyViolation {
+	clock := status.Clock
+	if clock == nil {
+		return nil
+	}
+
+	switch {
+	case !clock.Synchronized:
+		return []model.PolicyViolation{{
+			Rule:        "time.unsynchronized",
+			Severity:    model.SeverityMedium,
+			Message:     fmt.Sprintf("The clock is not synchronized (%s)", clock.Daemon),
+			Remediation: "Check that the time servers are reachable, then hardn timesync apply",
+		}}
+	case clock.Drifting():
+		return []model.PolicyViolation{{
+			Rule:     "time.drift",
+			Severity: model.SeverityLow,
+			Message: fmt.Sprintf("The clock is more than %gs off its source %s",
+				model.MaxClockOffset, clock.Source),
+			Remediation: "Let the daemon step the clock, e.g. chronyc makestep, and check the time servers",
+		}}
+	}
+	return nil
+}
+
+// BuildFirewallStackFindings reports traffic the firewall lets in over one
+// IP family but not the other. An unfiltered family is one finding, since
+// every service is reachable through it.
+func BuildFirewallStackFindings(status *SecurityStatus) []model.PolicyViolation {
+	report := status.FirewallStacks
+	if report == nil || report.Synchronized() {
+		return nil
+	}
+
+	if report.Unfiltered != "" {
+		filtered := model.FamilyIPv4
+		if report.Unfiltered == model.FamilyIPv4 {
+			filtered = model.FamilyIPv6
+		}
+		return []model.PolicyViolation{{
+			Rule:     "firewall.dual_stack_unfiltered",
+			Severity: model.SeverityHigh,
+			Message: fmt.Sprintf("%s accepts all incoming traffic while %s is filtered",
+				model.FamilyName(report.Unfiltered), model.FamilyName(filtered)),
+			Remediation: "hardn firewall dual-stack --mirror filters both families and copies the rules",
+		}}
+	}
+
+	var findings []model.PolicyViolation
+	for _, gap := range report.Gaps {
+		findings = append(findings, model.PolicyViolation{
+			Rule:     "firewall.dual_stack_gap",
+			Severity: model.SeverityLow,
+			Message: fmt.Sprintf("Firewall rule %q applies to %s but not %s",
+				gap.Rule, model.FamilyName(gap.AllowedOn), model.FamilyName(gap.MissingOn)),
+			Remediation: "hardn firewall dual-stack --mirror adds the rule to " + model.FamilyName(gap.MissingOn),
+		})
+	}
+	return findings
+}
+
+// BuildIPv6Findings reports IPv6 that is not treated as the configured
+// mode asks: still running when it should be off, open to every service,
+// or letting in traffic the deny mode keeps to IPv4
+func BuildIPv6Findings(status *SecurityStatus) []model.PolicyViolation {
+	ipv6 := status.IPv6
+	if ipv6 == nil || ipv6.Compliant() {
+		return nil
+	}
+
+	switch {
+	case ipv6.Mode == model.IPv6Disable:
+		return []model.PolicyViolation{{
+			Rule:        "firewall.ipv6_enabled",
+			Severity:    model.SeverityMedium,
+			Message:     "IPv6 is enabled although the configuration turns it off",
+			Remediation: "hardn firewall ipv6 --apply turns IPv6 off",
+		}}
+	case ipv6.Exposed():
+		// Without a filtered IPv4 the dual-stack check says the same
+		return []model.PolicyViolation{{
+			Rule:        "firewall.ipv6_unfiltered",
+			Severity:    model.SeverityHigh,
+			Message:     "The firewall accepts all incoming IPv6 traffic",
+			Remediation: "hardn firewall ipv6 --apply filters IPv6 as the configured mode asks",
+		}}
+	}
+
+	var findings []model.PolicyViolation
+	for _, rule := range ipv6.Unexpected {
+		findings = append(findings, model.PolicyViolation{
+			Rule:        "firewall.ipv6_unexpected",
+			Severity:    model.SeverityLow,
+			Message:     fmt.Sprintf("Firewall rule %q applies to IPv6 although the IPv6 deny mode keeps it to IPv4", rule),
+			Remediation: "hardn firewall ipv6 --apply limits the rule to IPv4, or give it family: ipv6 in firewallRules",
+		})
+	}
+	return findings
+}
+
+// BuildTCPWrappersFindings reports TCP wrappers rules, which split access
+// control between the firewall and /etc/hosts.allow and /etc/hosts.deny,
+// and the rules that are ignored or contradicted by the firewall
+func BuildTCPWrappersFindings(status *SecurityStatus) []model.PolicyViolation {
+	report := status.TCPWrappers
+	if report == nil || !report.InUse() {
+		return nil
+	}
+
+	findings := []model.PolicyViolation{{
+		Rule:     "tcp_wrappers.in_use",
+		Severity: model.SeverityLow,
+		Message: fmt.Sprintf("%d TCP wrappers rules in /etc/hosts.allow and /etc/hosts.deny",
+			len(report.Allow)+len(report.Deny)),
+		Remediation: "hardn tcp-wrappers migrate moves them to the firewall",
+	}}
+	for _, conflict := range report.Conflicts {
+		findings = append(findings, model.PolicyViolation{
+			Rule:        "tcp_wrappers.conflict",
+			Severity:    model.SeverityMedium,
+			Message:     fmt.Sprintf("%s:%d (%s): %s", conflict.Rule.File, conflict.Rule.Line, conflict.Rule, conflict.Reason),
+			Remediation: "hardn tcp-wrappers status shows the firewall rules that replace it",
+		})
+	}
+	return findings
+}
+
+// macControl keeps the apparmor.enabled ID on AppArmor hosts, so earlier
+// reports still compare, and reports selinux.enforcing on SELinux hosts
+func macControl(status *SecurityStatus) auditCheck {
+	if status.MAC != nil && status.MAC.Framework == model.MACSELinux {
+		return control("selinux.enforcing", status.MACEnabled)
+	}
+	return control("apparmor.enabled", status.MACEnabled)
+}
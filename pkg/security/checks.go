@@ -0,0 +1,438 @@
+// pkg/security/checks.go
+package security
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+)
+
+const (
+	sshdConfigPath    = "/etc/ssh/sshd_config"
+	hardnSSHDConfPath = "/etc/ssh/sshd_config.d/hardn.conf"
+)
+
+// Checks describes every status and audit check. Audit control titles are
+// taken from here, so `hardn explain` and audit reports cannot disagree.
+var Checks = []model.CheckInfo{
+	{
+		ID:          "users.non_root_sudo",
+		Title:       "Non-root user with sudo access",
+		Label:       "Users",
+		Inspects:    "Entries in /etc/sudoers.d other than README and root, then members of the sudo and wheel groups.",
+		Why:         "Administering the host as root leaves no record of who did what, and keeps root logins necessary. A named sudo user lets root login be turned off.",
+		Remediation: "hardn user create NAME --sudo --key NAME.pub creates the user, its sudoers entry and SSH key.",
+		Files:       []string{"/etc/sudoers.d", "/etc/group"},
+		Audited:     true,
+	},
+	{
+		ID:          "users.sudo_installed",
+		Title:       "Sudo installed",
+		Label:       "Sudo",
+		Inspects:    "Whether sudo is on the PATH and /etc/sudoers exists.",
+		Why:         "Without sudo every administrative task needs a root shell.",
+		Remediation: "Install the sudo package (apt-get install sudo, apk add sudo), then grant a user access with hardn user create NAME --sudo.",
+		Files:       []string{"/etc/sudoers"},
+		Commands:    []string{"which sudo"},
+		Audited:     true,
+	},
+	{
+		ID:          "users.sudo_method",
+		Title:       "Sudo requires a password",
+		Label:       "Sudo Method",
+		Inspects:    "The sudoers entry of the first non-root sudo user, or root, for NOPASSWD.",
+		Why:         "Passwordless sudo turns a stolen SSH key or session into root access.",
+		Remediation: "Create the user without passwordless sudo in User Management; hardn writes a sudoers entry that asks for the password.",
+		Files:       []string{"/etc/sudoers", "/etc/sudoers.d"},
+	},
+	{
+		ID:          "users.sudo_reauthentication",
+		Title:       "Sudo re-authentication not disabled",
+		Inspects:    "/etc/sudoers and the files sudo reads from /etc/sudoers.d for !authenticate outside comments.",
+		Why:         "!authenticate lets sudo run without asking for any password, so every session of the user is a root session.",
+		Remediation: "Remove !authenticate from the sudoers entries the compliance report lists, then check them with visudo -c.",
+		Files:       []string{"/etc/sudoers", "/etc/sudoers.d"},
+	},
+	{
+		ID:          "firewall.enabled",
+		Title:       "Firewall enabled",
+		Label:       "Firewall",
+		Inspects:    "ENABLED in ufw.conf, or whether firewalld is running on Rocky Linux, AlmaLinux and Fedora; without either, any iptables rule or a DROP policy on INPUT.",
+		Why:         "Without a firewall every listening service is reachable from the network.",
+		Remediation: "hardn firewall enable allows the SSH port, then enables ufw or firewalld with a default deny policy.",
+		Files:       []string{secondary.UFWConfPath},
+		Commands:    []string{"ufw show added", "firewall-cmd --state", "iptables-save"},
+		Audited:     true,
+	},
+	{
+		ID:          "firewall.default_deny",
+		Title:       "Firewall denies incoming by default",
+		Label:       "Firewall",
+		Inspects:    "The default policies in /etc/default/ufw (deny incoming, allow outgoing) and at least one allow or limit rule. Under firewalld, a default zone whose target does not accept and that opens at least one port or service. Without either, an iptables rule for the SSH port.",
+		Why:         "An enabled firewall that accepts by default only blocks what someone remembered to deny.",
+		Remediation: "hardn firewall enable, or enableUfwSshPolicy in the configuration for run-all.",
+		Files:       []string{secondary.UFWDefaultsPath},
+		Commands:    []string{"ufw show added", "firewall-cmd --permanent --get-target", "iptables-save"},
+		Audited:     true,
+	},
+	{
+		ID:          "firewall.dual_stack",
+		Title:       "Firewall rules match for IPv4 and IPv6",
+		Inspects:    "On hosts with an IPv6 address, the rules ufw keeps in user.rules and user6.rules, IPV6 in /etc/default/ufw, or the default firewalld zone's ports, services and rich rules for each family.",
+		Why:         "ufw with IPV6=no leaves IPv6 unfiltered, so every service a rule limits over IPv4 is open over IPv6. Rules added for one family also leave services unreachable over the other.",
+		Remediation: "hardn firewall dual-stack --mirror, or mirrorFirewallStacks for run-all.",
+		Files:       []string{secondary.UFWDefaultsPath, secondary.UFWUserRulesPath, secondary.UFWUser6RulesPath, secondary.IfInet6Path},
+		Commands:    []string{"ip6tables-save", "firewall-cmd --list-rich-rules"},
+		Audited:     true,
+	},
+	{
+		ID:          "firewall.ipv6_policy",
+		Title:       "IPv6 handled as configured",
+		Label:       "IPv6",
+		Inspects:    "Whether the kernel runs IPv6, from /proc/sys and the kernel command line, and the rules the firewall applies to IPv6, compared with the ipv6 mode of the configuration.",
+		Why:         "IPv6 is often enabled without anyone relying on it, and every service the firewall leaves open over IPv6 is reachable through an address nobody watches.",
+		Remediation: "Set ipv6.mode to filter, deny or disable and run hardn firewall ipv6 --apply.",
+		Files:       []string{secondary.ProcIPv6DisablePath, secondary.ProcCmdlinePath, secondary.IPv6SysctlPath, secondary.UFWUser6RulesPath},
+		Commands:    []string{"ip6tables-save", "firewall-cmd --list-rich-rules"},
+		Audited:     true,
+	},
+	{
+		ID:          "firewall.no_tcp_wrappers",
+		Title:       "No TCP wrappers rules",
+		Inspects:    "Rules in /etc/hosts.allow and /etc/hosts.deny, whether each daemon they name links libwrap, and the firewall rules for the same ports.",
+		Why:         "Access rules split between the firewall and TCP wrappers make audits unreliable. Most daemons, sshd included since OpenSSH 6.7, no longer read these files, so their rules look enforced without being so.",
+		Remediation: "hardn tcp-wrappers migrate adds equivalent firewall rules and comments out the legacy rules.",
+		Files:       []string{secondary.HostsAllowPath, secondary.HostsDenyPath},
+		Commands:    []string{"ldd"},
+		Audited:     true,
+	},
+	{
+		ID:          "firewall.ufw_installed",
+		Title:       "ufw installed",
+		Inspects:    "Whether ufw is on the PATH.",
+		Why:         "The CIS benchmarks for Debian and Ubuntu expect ufw to manage the host firewall.",
+		Remediation: "hardn firewall enable installs and enables ufw with a default deny policy.",
+		Commands:    []string{"which ufw"},
+	},
+	{
+		ID:          "ssh.root_login_disabled",
+		Title:       "SSH root login disabled",
+		Label:       "SSH Login",
+		Inspects:    "PermitRootLogin in hardn's sshd drop-in, or in sshd_config when there is none. Anything but \"no\", or no setting at all, fails.",
+		Why:         "root exists on every host, so it is the first account password guessing and stolen keys are tried against.",
+		Remediation: "hardn ssh disable-root sets PermitRootLogin no.",
+		Files:       []string{hardnSSHDConfPath, sshdConfigPath},
+		Audited:     true,
+	},
+	{
+		ID:          "ssh.password_auth_disabled",
+		Title:       "SSH password authentication disabled",
+		Label:       "SSH Auth",
+		Inspects:    "PasswordAuthentication in hardn's sshd drop-in, or in sshd_config when there is none. Only \"no\" passes.",
+		Why:         "Passwords can be guessed from anywhere the port is reachable; keys cannot.",
+		Remediation: "hardn ssh harden writes key-only authentication; add a key first with hardn ssh add-key.",
+		Files:       []string{hardnSSHDConfPath, sshdConfigPath},
+		Audited:     true,
+	},
+	{
+		ID:          "ssh.port_non_default",
+		Title:       "SSH on a non-default port",
+		Label:       "SSH Port",
+		Inspects:    "sshPort in the hardn configuration.",
+		Why:         "Most automated scans only try port 22; a different port keeps them out of the logs.",
+		Remediation: "hardn ssh set-port PORT moves sshd and opens the new port in the firewall.",
+		Files:       []string{"/etc/hardn/hardn.yml"},
+		Audited:     true,
+	},
+	{
+		ID:          "ssh.firewall_access",
+		Title:       "SSH rate-limited or behind port knocking",
+		Label:       "SSH Access",
+		Inspects:    "A limit rule for the SSH port in the live firewall rules, and knockd running with the configuration hardn writes.",
+		Why:         "Rate limiting slows password guessing from a single address; port knocking keeps the port closed to scanners altogether.",
+		Remediation: "Set sshRateLimit or portKnocking in the configuration and reconfigure the firewall, or use \"SSH access\" in the firewall menu.",
+		Files:       []string{secondary.UFWUserRulesPath, secondary.KnockdConfPath},
+		Commands:    []string{"firewall-cmd --list-rich-rules", "systemctl is-active knockd"},
+	},
+	{
+		ID:          "ssh.profile",
+		Title:       "SSH hardening profile applied",
+		Label:       "SSH Profile",
+		Inspects:    "The \"# Hardening profile:\" marker hardn writes to its sshd drop-in.",
+		Why:         "The profile limits authentication attempts, forwarding and the ciphers, MACs and key exchanges sshd offers.",
+		Remediation: "hardn ssh profile baseline|strict|paranoid.",
+		Files:       []string{hardnSSHDConfPath},
+	},
+	{
+		ID:          "ssh.config_permissions",
+		Title:       "sshd_config readable by root only",
+		Inspects:    "The permission bits of /etc/ssh/sshd_config; group or other access fails.",
+		Why:         "The server configuration names allowed users, keys and listen addresses that other local users have no need to read or change.",
+		Remediation: "chmod 600 /etc/ssh/sshd_config and chown root:root /etc/ssh/sshd_config.",
+		Files:       []string{sshdConfigPath},
+	},
+	{
+		ID:          "ssh.access_limited",
+		Title:       "SSH access limited to named users or groups",
+		Inspects:    "AllowUsers, AllowGroups, DenyUsers and DenyGroups in the configuration sshd -T reports.",
+		Why:         "Without a list every account with a password or key can log in, including service accounts.",
+		Remediation: "hardn ssh harden --allow-user NAME, or sshAllowedUsers for run-all.",
+		Files:       []string{hardnSSHDConfPath, sshdConfigPath},
+		Commands:    []string{"sshd -T"},
+	},
+	{
+		ID:          "ssh.max_auth_tries",
+		Title:       "SSH MaxAuthTries 4 or less",
+		Inspects:    "MaxAuthTries in the configuration sshd -T reports.",
+		Why:         "Fewer attempts per connection slow down password guessing and make it stand out in the logs.",
+		Remediation: "hardn ssh profile baseline sets MaxAuthTries 4; strict and paranoid set fewer.",
+		Files:       []string{hardnSSHDConfPath},
+		Commands:    []string{"sshd -T"},
+	},
+	{
+		ID:          "ssh.empty_passwords_disabled",
+		Title:       "SSH logins with empty passwords disabled",
+		Inspects:    "PermitEmptyPasswords in the configuration sshd -T reports.",
+		Why:         "An account without a password would otherwise be open to anyone who can reach the port.",
+		Remediation: "hardn ssh harden writes PermitEmptyPasswords no.",
+		Files:       []string{hardnSSHDConfPath, sshdConfigPath},
+		Commands:    []string{"sshd -T"},
+	},
+	{
+		ID:          "ssh.user_environment_disabled",
+		Title:       "SSH user environment disabled",
+		Inspects:    "PermitUserEnvironment in the configuration sshd -T reports.",
+		Why:         "Environment options in authorized_keys or ~/.ssh/environment can set LD_PRELOAD and similar variables to get around restrictions.",
+		Remediation: "Set PermitUserEnvironment no in /etc/ssh/sshd_config and reload sshd.",
+		Files:       []string{sshdConfigPath},
+		Commands:    []string{"sshd -T"},
+	},
+	{
+		ID:          "apparmor.enabled",
+		Title:       "AppArmor enabled",
+		Label:       "MAC",
+		Inspects:    "Whether the kernel runs AppArmor and at least one profile is in enforce mode.",
+		Why:         "Mandatory access control confines a compromised service to what its profile allows.",
+		Remediation: "hardn mac enforce --framework apparmor, or enableAppArmor for run-all.",
+		Files:       []string{secondary.LSMListPath, secondary.AppArmorProfilesPath},
+		Commands:    []string{"aa-status --enforced"},
+		Audited:     true,
+	},
+	{
+		ID:          "selinux.enforcing",
+		Title:       "SELinux enforcing",
+		Label:       "MAC",
+		Inspects:    "The current SELinux mode and the mode configured for the next boot.",
+		Why:         "Mandatory access control confines a compromised service to its SELinux domain.",
+		Remediation: "hardn mac enforce --framework selinux, or enableSELinux for run-all. A non-default SSH port is labeled first.",
+		Files:       []string{secondary.SELinuxEnforcePath, secondary.SELinuxConfigPath},
+		Commands:    []string{"getenforce", "semanage port -l"},
+		Audited:     true,
+	},
+	{
+		ID:          "updates.automatic",
+		Title:       "Automatic security updates",
+		Label:       "Auto Updates",
+		Inspects:    "On Debian and Ubuntu, that unattended-upgrades is installed and enabled in 20auto-upgrades; on Alpine, hardn's daily upgrade script; on Rocky Linux, AlmaLinux and Fedora, that the dnf-automatic timer is enabled.",
+		Why:         "Most compromises use vulnerabilities that already have a fix.",
+		Remediation: "The Auto Updates menu, or enableUnattendedUpgrades and autoUpdates for run-all.",
+		Files:       []string{model.AptAutoUpgradesPath, model.AptUnattendedUpgradesPath, model.AlpineAutoUpgradeScriptPath, model.DnfAutomaticConfigPath},
+		Commands:    []string{"dpkg-query -W unattended-upgrades", "systemctl is-enabled " + model.DnfAutomaticTimer},
+		Audited:     true,
+	},
+	{
+		ID:          "users.password_policy",
+		Title:       "Password strength, aging and lockout enforced",
+		Label:       "Password Policy",
+		Inspects:    "Whether pam_pwquality and pam_faillock are in the PAM stack (common-password and common-auth, or system-auth), their minlen and deny settings, and PASS_MAX_DAYS in /etc/login.defs.",
+		Why:         "Short, never-changing passwords and unlimited login attempts make console, sudo and password SSH logins guessable.",
+		Remediation: "hardn password-policy apply, or passwordPolicy for run-all. Alpine's busybox login has no PAM, so only the aging applies there.",
+		Files:       []string{model.PwqualityConfigPath, model.FaillockConfigPath, model.LoginDefsPath, "/etc/pam.d/common-password", "/etc/pam.d/system-auth"},
+	},
+	{
+		ID:          "kernel.module_blacklist",
+		Title:       "Uncommon filesystem and USB storage modules blocked",
+		Label:       "Modules",
+		Inspects:    "The modules of moduleBlacklist, against the install rules in modprobe --showconfig and the modules in /proc/modules.",
+		Why:         "Every filesystem driver the kernel loads on demand parses untrusted disk images, and USB storage lets data leave with anyone at the console.",
+		Remediation: "hardn modules apply, or moduleBlacklist for run-all; reboot or rmmod modules that are already loaded.",
+		Files:       []string{model.ModuleBlacklistPath, model.ProcModulesPath},
+		Commands:    []string{"modprobe --showconfig"},
+		Audited:     true,
+	},
+	{
+		ID:          "kernel.boot_parameters",
+		Title:       "Kernel booted with the hardening parameters",
+		Label:       "Boot",
+		Inspects:    "The parameters of bootHardening, against GRUB_CMDLINE_LINUX in /etc/default/grub (grubby --info=DEFAULT on the RHEL family) and /proc/cmdline.",
+		Why:         "Zeroing and poisoning memory and randomizing the page allocator make use-after-free and information leak bugs in the kernel harder to exploit.",
+		Remediation: "hardn boot apply, or bootHardening for run-all, then reboot.",
+		Files:       []string{"/etc/default/grub", "/proc/cmdline"},
+		Commands:    []string{"grubby --info=DEFAULT"},
+		Audited:     true,
+	},
+	{
+		ID:          "system.time_sync",
+		Title:       "Time synchronization running",
+		Label:       "Time Sync",
+		Inspects:    "Whether chronyd, systemd-timesyncd or ntpd is running.",
+		Why:         "Logs, certificates and Kerberos tickets are only trustworthy with a correct clock.",
+		Remediation: "hardn timesync apply, or timeSync for run-all.",
+		Commands:    []string{"systemctl is-active chronyd"},
+	},
+	{
+		ID:          "system.clock_synchronized",
+		Title:       "Clock synchronized and not drifting",
+		Label:       "Clock",
+		Inspects:    "Whether the running time synchronization daemon reports the clock synchronized, and its offset from the source: chronyc tracking, timedatectl timesync-status, or adjtimex for busybox ntpd.",
+		Why:         "A clock that has lost its source drifts silently, and log timestamps and certificate checks drift with it.",
+		Remediation: "Check that the servers of timeSync are reachable over UDP 123 (and TCP 4460 for NTS), then hardn timesync apply.",
+		Files:       []string{model.ChronyConfigPath, model.ChronyRHELConfigPath, model.TimesyncdDropInPath, model.BusyboxNTPDConfPath},
+		Commands:    []string{"chronyc tracking", "timedatectl timesync-status", "adjtimex"},
+		Audited:     true,
+	},
+	{
+		ID:          "logging.off_host",
+		Title:       "Logs shipped to a remote collector",
+		Label:       "Log Shipping",
+		Inspects:    "The forwarding hardn configured for rsyslog or systemd-journal-upload, whether its service runs, and whether it holds an established connection to the collector's port (ss).",
+		Why:         "Logs kept only on the host can be erased by whoever compromises it; a copy on a collector survives.",
+		Remediation: "hardn log-shipping check, then hardn log-shipping apply, or logShipping for run-all.",
+		Files:       []string{model.RsyslogForwardConfigPath, model.JournalUploadDropInPath},
+		Commands:    []string{"systemctl is-active rsyslog", "ss -Htn state established"},
+		Audited:     true,
+	},
+	{
+		ID:          "system.no_pending_restarts",
+		Title:       "No services or reboot pending after updates",
+		Label:       "Restarts",
+		Inspects:    "Services needrestart reports as running replaced libraries, an outdated kernel, and the reboot-required flag. On Rocky Linux, AlmaLinux and Fedora, needs-restarting from dnf-utils.",
+		Why:         "An update only protects a service once it runs the new code.",
+		Remediation: "Restart the listed services or reboot; needrestartMode lets hardn restart services itself on Debian and Ubuntu.",
+		Files:       []string{secondary.RebootRequiredPath},
+		Commands:    []string{"needrestart -b -r l", "needs-restarting -r", "needs-restarting -s"},
+		Audited:     true,
+	},
+	{
+		ID:          "directory.key_lookup",
+		Title:       "SSH keys looked up in the directory",
+		Label:       "Directory",
+		Inspects:    "On hosts resolving accounts from LDAP or SSSD, whether sshd has an AuthorizedKeysCommand.",
+		Why:         "Without it directory users need keys copied to each host, which are not revoked with the account.",
+		Remediation: "Set the AuthorizedKeysCommand in User Management.",
+		Files:       []string{"/etc/nsswitch.conf", hardnSSHDConfPath},
+	},
+	{
+		ID:          "webserver.<server>.tls_baseline",
+		Title:       "TLS baseline applied to <server>",
+		Inspects:    "Whether hardn's TLS snippet is installed for each nginx, Apache or Caddy server found.",
+		Why:         "Distribution defaults still accept old protocols and weak ciphers.",
+		Remediation: "enableWebServerTls for run-all writes the snippet and reloads the server after a config test.",
+		Files:       []string{"/etc/nginx/conf.d/hardn-tls.conf", "/etc/apache2/conf-available/hardn-tls.conf", "/etc/caddy/hardn-tls.caddy"},
+		Audited:     true,
+	},
+	{
+		ID:          "database.<database>.baseline",
+		Title:       "Database baseline applied to <database>",
+		Inspects:    "Whether hardn's drop-in is in place for each PostgreSQL cluster and MySQL or MariaDB server found.",
+		Why:         "Default database settings listen widely and log little.",
+		Remediation: "databaseHardening in the configuration, applied by run-all.",
+		Files:       []string{"/etc/postgresql/VERSION/CLUSTER/conf.d/hardn.conf", "/etc/mysql/mariadb.conf.d/99-hardn.cnf"},
+		Audited:     true,
+	},
+	{
+		ID:          "certificates.not_expiring",
+		Title:       "No certificates expiring within <days> days",
+		Label:       "Certificates",
+		Inspects:    "Certificates in certificateMonitoring.paths and the common certificate locations, against certificateMonitoring.expiryDays.",
+		Why:         "An expired certificate breaks TLS clients, and rushed renewals get skipped steps.",
+		Remediation: "Renew the certificate and reload the services using it.",
+		Audited:     true,
+	},
+	{
+		ID:          "packages.no_known_cves",
+		Title:       "No known CVEs in installed packages",
+		Label:       "Packages",
+		Inspects:    "The last hardn audit --packages check of installed packages against debsecan, dnf updateinfo or the Alpine security database.",
+		Why:         "Known vulnerabilities in installed packages are the first thing scanners and attackers look for.",
+		Remediation: "Install the updates fixing them, or remove packages the host does not need.",
+		Files:       []string{model.PackageAuditPath},
+	},
+}
+
+// LookupChecks finds checks by control ID, such as ssh.root_login_disabled
+// or webserver.nginx.tls_baseline, or by their label in the status display
+func LookupChecks(name string) []model.CheckInfo {
+	var matches []model.CheckInfo
+	for _, check := range Checks {
+		if check.ID == name || matchesTemplate(check.ID, name) {
+			return []model.CheckInfo{check}
+		}
+		if check.Label != "" && strings.EqualFold(check.Label, name) {
+			matches = append(matches, check)
+		}
+	}
+	return matches
+}
+
+// ControlTitle returns the title of an audit control ID, filling in the
+// instance of templated checks, or the ID when it is unknown. Settings in
+// a title, such as the days of certificates.not_expiring, become N.
+func ControlTitle(id string) string {
+	for _, check := range Checks {
+		if check.ID == id {
+			return fillPlaceholder(check.Title, "N")
+		}
+		if matchesTemplate(check.ID, id) {
+			start, end := strings.Index(check.ID, "<"), strings.Index(check.ID, ">")
+			instance := id[start : len(id)-(len(check.ID)-end-1)]
+			return fillPlaceholder(check.Title, instance)
+		}
+	}
+	return id
+}
+
+// CheckIDs returns the ID of every check, sorted
+func CheckIDs() []string {
+	ids := make([]string, 0, len(Checks))
+	for _, check := range Checks {
+		ids = append(ids, check.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// matchesTemplate reports whether id is an instance of a template such as
+// database.<database>.baseline
+func matchesTemplate(template, id string) bool {
+	start := strings.Index(template, "<")
+	end := strings.Index(template, ">")
+	if start < 0 || end < start {
+		return false
+	}
+	prefix, suffix := template[:start], template[end+1:]
+	return len(id) > len(prefix)+len(suffix) &&
+		strings.HasPrefix(id, prefix) && strings.HasSuffix(id, suffix)
+}
+
+// fillPlaceholder replaces the <placeholder> in a check ID or title
+func fillPlaceholder(text, value string) string {
+	start := strings.Index(text, "<")
+	end := strings.Index(text, ">")
+	if start < 0 || end < start {
+		return text
+	}
+	return text[:start] + value + text[end+1:]
+}
+
+// checkTitle returns the title of a check, or its ID when it is unknown
+func checkTitle(id string) string {
+	for _, check := range Checks {
+		if check.ID == id {
+			return check.Title
+		}
+	}
+	return id
+}
@@ -0,0 +1,137 @@
+// pkg/security/facts.go
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/osdetect"
+)
+
+// BuildFacts assembles the facts document policies are evaluated against
+func BuildFacts(cfg *config.Config, osInfo *osdetect.OSInfo, status *SecurityStatus) *model.Facts {
+	hostname, _ := os.Hostname()
+
+	webServers := make([]model.FactsWebServer, 0, len(status.WebServers))
+	for _, server := range status.WebServers {
+		webServers = append(webServers, model.FactsWebServer{
+			Name:        server.Name,
+			TLSBaseline: server.BaselineApplied,
+		})
+	}
+
+	databases := make([]model.FactsDatabase, 0, len(status.Databases))
+	for _, database := range status.Databases {
+		databases = append(databases, model.FactsDatabase{
+			Name:     database.Name,
+			Flavor:   database.Flavor,
+			Instance: database.Instance,
+			Port:     database.Port,
+			Baseline: database.BaselineApplied,
+		})
+	}
+
+	return &model.Facts{
+		CollectedAt: time.Now(),
+		Hostname:    hostname,
+		OS: model.FactsOS{
+			Type:     osInfo.OsType,
+			Version:  osInfo.OsVersion,
+			Codename: osInfo.OsCodename,
+			Proxmox:  osInfo.IsProxmox,
+			Arch:     osInfo.Arch,
+
+			Environment: osInfo.Environment,
+		},
+		SSH: model.FactsSSH{
+			Port:             cfg.SshPort,
+			ListenAddresses:  cfg.SshListenAddresses,
+			RootLogin:        status.RootLoginEnabled,
+			PasswordAuth:     !status.PasswordAuthDisabled,
+			AllowedUsers:     cfg.SshAllowedUsers,
+			DirectoryKeyAuth: status.DirectoryKeyLookup,
+		},
+		Firewall: model.FactsFirewall{
+			Enabled:    status.FirewallEnabled,
+			Configured: status.FirewallConfigured,
+		},
+		Users: model.FactsUsers{
+			NonRootSudo:    status.SecureUsers,
+			SudoConfigured: status.SudoConfigured,
+		},
+		AppArmor:    status.MACEnabled && status.MAC.Framework == model.MACAppArmor,
+		SELinux:     status.MACEnabled && status.MAC.Framework == model.MACSELinux,
+		MAC:         status.MAC,
+		AutoUpdates: status.UnattendedUpgrades,
+		TimeSync:    status.TimeSync,
+		Directory: model.FactsDirectory{
+			Enabled: status.DirectoryAuth,
+			Sources: status.DirectorySources,
+		},
+		PendingRestarts: status.PendingRestarts,
+		RebootRequired:  status.RebootRequired,
+		WebServers:      webServers,
+		Databases:       databases,
+	}
+}
+
+// AnsibleFacts flattens facts into the key/value document Ansible reads
+// from /etc/ansible/facts.d, adding the risk level and hardn's version.
+// Nested objects become keys joined with "_", lists of objects are
+// numbered, and lists of values stay lists.
+func AnsibleFacts(facts *model.Facts, status *SecurityStatus, hardnVersion string) (map[string]any, error) {
+	data, err := json.Marshal(facts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode facts: %w", err)
+	}
+	var document map[string]any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to encode facts: %w", err)
+	}
+
+	flat := make(map[string]any)
+	flattenFacts(flat, "", document)
+
+	riskLevel, riskDescription, _ := GetSecurityRiskLevel(status)
+	flat["risk_level"] = riskLevel
+	flat["risk_description"] = riskDescription
+	flat["hardn_version"] = hardnVersion
+	return flat, nil
+}
+
+// flattenFacts adds value to flat under key, descending into objects and
+// lists of objects
+func flattenFacts(flat map[string]any, key string, value any) {
+	join := func(name string) string {
+		if key == "" {
+			return name
+		}
+		return key + "_" + name
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for name, item := range v {
+			flattenFacts(flat, join(name), item)
+		}
+	case []any:
+		for _, item := range v {
+			if _, ok := item.(map[string]any); !ok {
+				flat[key] = v
+				return
+			}
+		}
+		for i, item := range v {
+			flattenFacts(flat, join(fmt.Sprint(i)), item)
+		}
+		if len(v) == 0 {
+			flat[key] = v
+		}
+	default:
+		flat[key] = v
+	}
+}
@@ -0,0 +1,148 @@
+// pkg/security/report.go
+package security
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"gopkg.in/yaml.v3"
+)
+
+// StatusReport is the security status together with the context needed to
+// read it outside the menu
+type StatusReport struct {
+	GeneratedAt     time.Time       `json:"generated_at"`
+	Hostname        string          `json:"hostname"`
+	OS              model.FactsOS   `json:"os"`
+	RiskLevel       string          `json:"risk_level"`
+	RiskDescription string          `json:"risk_description"`
+	Status          *SecurityStatus `json:"status"`
+	FirewallRules   []string        `json:"firewall_rules"`
+}
+
+// BuildStatusReport assembles a status report from a checked status and the
+// firewall's added rules
+func BuildStatusReport(osInfo *osdetect.OSInfo, status *SecurityStatus, firewallRules []string) *StatusReport {
+	hostname, _ := os.Hostname()
+	riskLevel, description, _ := GetSecurityRiskLevel(status)
+
+	if firewallRules == nil {
+		firewallRules = []string{}
+	}
+
+	return &StatusReport{
+		GeneratedAt: time.Now(),
+		Hostname:    hostname,
+		OS: model.FactsOS{
+			Type:     osInfo.OsType,
+			Version:  osInfo.OsVersion,
+			Codename: osInfo.OsCodename,
+			Proxmox:  osInfo.IsProxmox,
+			Arch:     osInfo.Arch,
+
+			Environment: osInfo.Environment,
+		},
+		RiskLevel:       riskLevel,
+		RiskDescription: description,
+		Status:          status,
+		FirewallRules:   firewallRules,
+	}
+}
+
+// ReportFormatter renders a status report
+type ReportFormatter interface {
+	Format(w io.Writer, report *StatusReport) error
+}
+
+// NewReportFormatter returns the formatter for text, json or yaml output
+func NewReportFormatter(format string, cfg *config.Config) (ReportFormatter, error) {
+	switch format {
+	case "text", "":
+		return &textReportFormatter{cfg: cfg}, nil
+	case "json":
+		return jsonReportFormatter{}, nil
+	case "yaml":
+		return yamlReportFormatter{}, nil
+	}
+	return nil, fmt.Errorf("unsupported output format: %s (use text, json or yaml)", format)
+}
+
+// textReportFormatter renders the same lines as the main menu status panel
+type textReportFormatter struct {
+	cfg *config.Config
+}
+
+func (f *textReportFormatter) Format(w io.Writer, report *StatusReport) error {
+	fmt.Fprintf(w, "Host:       %s (%s %s)\n", report.Hostname, report.OS.Type, report.OS.Version)
+	if report.OS.Environment.Kind != "" {
+		fmt.Fprintf(w, "Runs on:    %s\n", report.OS.Environment)
+	}
+	fmt.Fprintf(w, "Risk level: %s (%s)\n\n", report.RiskLevel, report.RiskDescription)
+
+	DisplaySecurityStatusWithCustomPrinter(f.cfg, report.Status, nil, func(line string) {
+		fmt.Fprintln(w, line)
+	}, 0)
+
+	if len(report.FirewallRules) > 0 {
+		fmt.Fprintf(w, "\nFirewall rules:\n  %s\n", strings.Join(report.FirewallRules, "\n  "))
+	}
+	return nil
+}
+
+// jsonReportFormatter renders indented JSON
+type jsonReportFormatter struct{}
+
+func (jsonReportFormatter) Format(w io.Writer, report *StatusReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode status: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// yamlReportFormatter renders YAML with the same keys as the JSON output
+type yamlReportFormatter struct{}
+
+func (yamlReportFormatter) Format(w io.Writer, report *StatusReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode status: %w", err)
+	}
+
+	// JSON is valid YAML; decoding it into a node keeps the field order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("failed to encode status: %w", err)
+	}
+	clearYAMLStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return fmt.Errorf("failed to encode status: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode status: %w", err)
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// clearYAMLStyle switches a node decoded from JSON to block style
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
@@ -2,28 +2,97 @@ package security
 
 import (
 	"bufio"
+	"fmt"
 	"os"
-	"os/exec"
 	"strconv"
 	"strings"
 
 	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/application"
 	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+	"github.com/abbott/hardn/pkg/interfaces"
 	"github.com/abbott/hardn/pkg/osdetect"
 	"github.com/abbott/hardn/pkg/style"
 )
 
 // SecurityStatus represents the security status of various system components
 type SecurityStatus struct {
-	RootLoginEnabled     bool
-	FirewallEnabled      bool
-	FirewallConfigured   bool
-	SecureUsers          bool
-	AppArmorEnabled      bool
-	UnattendedUpgrades   bool
-	SudoConfigured       bool
-	SshPortNonDefault    bool
-	PasswordAuthDisabled bool
+	RootLoginEnabled   bool `json:"root_login_enabled"`
+	FirewallEnabled    bool `json:"firewall_enabled"`
+	FirewallConfigured bool `json:"firewall_configured"`
+	// Inbound traffic is filtered by Windows under WSL
+	FirewallWindows      bool `json:"firewall_windows,omitempty"`
+	SecureUsers          bool `json:"secure_users"`
+	MACEnabled           bool `json:"mac_enabled"`
+	UnattendedUpgrades   bool `json:"unattended_upgrades"`
+	SudoConfigured       bool `json:"sudo_configured"`
+	SshPortNonDefault    bool `json:"ssh_port_non_default"`
+	PasswordAuthDisabled bool `json:"password_auth_disabled"`
+
+	// Firewall rate-limits new SSH connections, or knockd keeps SSH closed
+	// until the knock sequence
+	SshRateLimited  bool `json:"ssh_rate_limited"`
+	SshPortKnocking bool `json:"ssh_port_knocking"`
+
+	// SSH hardening profile in effect; empty when none is applied
+	SshProfile string `json:"ssh_profile"`
+
+	// Directory (LDAP/SSSD) account resolution
+	DirectoryAuth      bool     `json:"directory_auth"`
+	DirectorySources   []string `json:"directory_sources"`
+	DirectoryKeyLookup bool     `json:"directory_key_lookup"`
+
+	// Password strength, aging and lockout in effect; nil when unreadable
+	PasswordPolicy *model.PasswordPolicyStatus `json:"password_policy"`
+
+	// Configured kernel modules compared with modprobe.d; nil when unreadable
+	ModuleBlacklist *model.ModuleBlacklistStatus `json:"module_blacklist"`
+
+	// Configured kernel parameters compared with GRUB and the running
+	// kernel; nil when boot hardening is off or GRUB is unreadable
+	BootHardening *model.BootHardeningStatus `json:"boot_hardening"`
+
+	// Time synchronization service running, e.g. chronyd; empty when none
+	TimeSync string `json:"time_sync"`
+
+	// Time synchronization daemon, its servers and the clock offset; nil
+	// when no daemon runs
+	Clock *model.TimeSyncStatus `json:"clock"`
+
+	// Log forwarding hardn configured and whether it reaches the
+	// collector; nil when unreadable
+	LogShipping *model.LogShippingStatus `json:"log_shipping"`
+
+	// Services still using replaced libraries, and whether a reboot is due
+	PendingRestarts []string `json:"pending_restarts"`
+	RebootRequired  bool     `json:"reboot_required"`
+
+	// Installed web servers and whether they carry the TLS baseline
+	WebServers []model.WebServer `json:"web_servers"`
+
+	// Installed database servers and whether they carry the baseline
+	Databases []model.DatabaseServer `json:"databases"`
+
+	// Mandatory access control framework (AppArmor or SELinux) and its mode
+	MAC *model.MACStatus `json:"mac"`
+
+	// Certificates found and those close to expiry
+	Certificates *model.CertificateExpiry `json:"certificates"`
+
+	// Firewall rules compared between IPv4 and IPv6; nil when unreadable
+	FirewallStacks *model.DualStackReport `json:"firewall_stacks"`
+
+	// How other hosts reach this one over IPv6; nil when unreadable
+	IPv6 *model.IPv6Exposure `json:"ipv6"`
+
+	// Legacy /etc/hosts.allow and /etc/hosts.deny rules; nil when unreadable
+	TCPWrappers *model.TCPWrappersReport `json:"tcp_wrappers"`
+
+	// Advisory counts of the last package check; nil when packages have not
+	// been checked
+	PackageAudit *model.PackageAuditSummary `json:"package_audit"`
 }
 
 // CheckSecurityStatus examines the system and returns the security status
@@ -34,13 +103,24 @@ func CheckSecurityStatus(cfg *config.Config, osInfo *osdetect.OSInfo) (*Security
 	status.RootLoginEnabled = checkRootLoginEnabled(osInfo)
 
 	// Check firewall status
-	status.FirewallEnabled, status.FirewallConfigured = checkFirewallStatus()
+	status.FirewallEnabled, status.FirewallConfigured = checkFirewallStatus(osInfo, cfg.SshPort)
+	status.FirewallWindows = osInfo.IsWSL
+
+	// Compare the firewall's IPv4 and IPv6 rules
+	status.FirewallStacks = checkFirewallStacks(cfg, osInfo)
+
+	// Check IPv6 against the configured mode
+	status.IPv6 = checkIPv6(cfg, osInfo)
+
+	// Look for TCP wrappers rules the firewall should hold instead
+	status.TCPWrappers = checkTCPWrappers(osInfo, cfg.SshPort)
 
 	// Check user security (non-root users with sudo)
 	status.SecureUsers = checkUserSecurity()
 
-	// Check AppArmor status
-	status.AppArmorEnabled = checkAppArmorStatus(osInfo)
+	// Check mandatory access control (AppArmor or SELinux)
+	status.MAC = checkMACStatus(osInfo)
+	status.MACEnabled = status.MAC.Enforcing()
 
 	// Check unattended upgrades
 	status.UnattendedUpgrades = checkUnattendedUpgrades(osInfo)
@@ -51,9 +131,52 @@ func CheckSecurityStatus(cfg *config.Config, osInfo *osdetect.OSInfo) (*Security
 	// Check SSH port configuration
 	status.SshPortNonDefault = (cfg.SshPort != 22)
 
+	// Check how the firewall lets SSH in
+	if access := checkSSHAccess(osInfo, cfg.SshPort); access != nil {
+		status.SshRateLimited = access.RateLimited
+		status.SshPortKnocking = access.PortKnocking
+	}
+
 	// Check password authentication
 	status.PasswordAuthDisabled = checkPasswordAuth(osInfo)
 
+	// Check SSH hardening profile
+	status.SshProfile = checkSSHProfile(osInfo)
+
+	// Check directory-backed authentication
+	status.DirectoryAuth, status.DirectorySources, status.DirectoryKeyLookup = checkDirectoryAuth(osInfo)
+
+	// Check the password policy
+	status.PasswordPolicy = checkPasswordPolicy(osInfo)
+
+	// Check the kernel module blacklist
+	status.ModuleBlacklist = checkModuleBlacklist(cfg, osInfo)
+
+	// Check the kernel command line
+	status.BootHardening = checkBootHardening(cfg, osInfo)
+
+	// Check time synchronization
+	status.TimeSync = checkTimeSync(osInfo)
+	status.Clock = checkClock(osInfo)
+
+	// Check whether logs leave the host
+	status.LogShipping = checkLogShipping(osInfo)
+
+	// Check services and kernel waiting on a restart
+	status.PendingRestarts, status.RebootRequired = checkPendingRestarts(osInfo)
+
+	// Check web server TLS baselines
+	status.WebServers = checkWebServers(osInfo)
+
+	// Check database baselines
+	status.Databases = checkDatabases(osInfo)
+
+	// Check certificate expiry
+	status.Certificates = checkCertificates(cfg)
+
+	// Read the last package vulnerability check
+	status.PackageAudit = checkPackageAudit(osInfo)
+
 	return status, nil
 }
 
@@ -65,11 +188,23 @@ func DisplaySecurityStatusWithCustomPrinter(cfg *config.Config, status *Security
 			"Sudo",
 			"Sudo Method",
 			"Firewall",
+			"IPv6",
 			"SSH Login",
 			"SSH Auth",
 			"SSH Port",
-			"AppArmor",
+			"SSH Access",
+			"SSH Profile",
+			"MAC",
 			"Auto Updates",
+			"Directory",
+			"Password Policy",
+			"Modules",
+			"Boot",
+			"Time Sync",
+			"Log Shipping",
+			"Restarts",
+			"Certificates",
+			"Packages",
 		}, 2)
 	}
 
@@ -91,8 +226,8 @@ func DisplaySecurityStatusWithCustomPrinter(cfg *config.Config, status *Security
 	// Display sudo configuration
 	if !status.SudoConfigured {
 		indentedPrintFn(formatter.FormatWarning("Sudo", "Not Installed", "", "dark"))
-	// } else {
-	// 	indentedPrintFn(formatter.FormatConfigured("Sudo", "Installed", "", "dark"))
+		// } else {
+		// 	indentedPrintFn(formatter.FormatConfigured("Sudo", "Installed", "", "dark"))
 	}
 
 	// Display sudo method
@@ -129,7 +264,9 @@ func DisplaySecurityStatusWithCustomPrinter(cfg *config.Config, status *Security
 	}
 
 	// Display firewall status
-	if !status.FirewallEnabled {
+	if !status.FirewallEnabled && status.FirewallWindows {
+		indentedPrintFn(formatter.FormatWarning("Firewall", "Windows", "see hardn wsl", "dark"))
+	} else if !status.FirewallEnabled {
 		indentedPrintFn(formatter.FormatWarning("Firewall", "Not Configured", "vulnerable", "dark"))
 	} else if !status.FirewallConfigured {
 		indentedPrintFn(formatter.FormatWarning("Firewall", "Enabled", "configure policies", "dark"))
@@ -137,6 +274,26 @@ func DisplaySecurityStatusWithCustomPrinter(cfg *config.Config, status *Security
 		indentedPrintFn(formatter.FormatConfigured("Firewall", "Configured", "deny policy", "dark"))
 	}
 
+	// Display IPv6 exposure
+	if ipv6 := status.IPv6; ipv6 != nil {
+		switch {
+		case !ipv6.Enabled && ipv6.BootDisabled:
+			indentedPrintFn(formatter.FormatConfigured("IPv6", "Disabled", "kernel command line", "dark"))
+		case !ipv6.Enabled:
+			indentedPrintFn(formatter.FormatConfigured("IPv6", "Disabled", "sysctl", "dark"))
+		case ipv6.Mode == model.IPv6Disable:
+			indentedPrintFn(formatter.FormatWarning("IPv6", "Enabled", "disable mode not applied", "dark"))
+		case !ipv6.Addressed:
+			indentedPrintFn(formatter.FormatConfigured("IPv6", "Enabled", "no address", "dark"))
+		case ipv6.Exposed():
+			indentedPrintFn(formatter.FormatWarning("IPv6", "Unfiltered", "every service reachable", "dark"))
+		case len(ipv6.Unexpected) > 0:
+			indentedPrintFn(formatter.FormatWarning("IPv6", "Open", strings.Join(ipv6.Unexpected, ", "), "dark"))
+		default:
+			indentedPrintFn(formatter.FormatConfigured("IPv6", "Filtered", fmt.Sprintf("%s mode, %d rules", ipv6.Mode, len(ipv6.Allowed)), "dark"))
+		}
+	}
+
 	// Display root login status
 	if status.RootLoginEnabled {
 		indentedPrintFn(formatter.FormatWarning("SSH Login", "Not Configured", "root allowed", "dark"))
@@ -159,11 +316,31 @@ func DisplaySecurityStatusWithCustomPrinter(cfg *config.Config, status *Security
 		indentedPrintFn(formatter.FormatConfigured("SSH Port", "Configured", sshStatus, "dark"))
 	}
 
-	// Display AppArmor status
-	if !status.AppArmorEnabled {
-		indentedPrintFn(formatter.FormatWarning("AppArmor", "Not Configured", "", "dark"))
+	// Display how the firewall lets SSH in
+	switch {
+	case status.SshPortKnocking:
+		indentedPrintFn(formatter.FormatConfigured("SSH Access", "Configured", "port knocking", "dark"))
+	case status.SshRateLimited:
+		indentedPrintFn(formatter.FormatConfigured("SSH Access", "Configured", "rate-limited", "dark"))
+	case status.FirewallEnabled:
+		indentedPrintFn(formatter.FormatWarning("SSH Access", "Not Configured", "not rate-limited", "dark"))
+	}
+
+	// Display SSH hardening profile
+	if status.SshProfile == "" {
+		indentedPrintFn(formatter.FormatWarning("SSH Profile", "Not Configured", "no hardening profile", "dark"))
 	} else {
-		indentedPrintFn(formatter.FormatConfigured("AppArmor", "Configured", "", "dark"))
+		indentedPrintFn(formatter.FormatConfigured("SSH Profile", "Configured", status.SshProfile, "dark"))
+	}
+
+	// Display mandatory access control status
+	switch {
+	case status.MACEnabled:
+		indentedPrintFn(formatter.FormatConfigured("MAC", model.FrameworkName(status.MAC.Framework), status.MAC.Describe(), "dark"))
+	case status.MAC != nil && status.MAC.Framework != "":
+		indentedPrintFn(formatter.FormatWarning("MAC", "Not Enforcing", status.MAC.Describe(), "dark"))
+	default:
+		indentedPrintFn(formatter.FormatWarning("MAC", "Not Configured", "no AppArmor or SELinux", "dark"))
 	}
 
 	// Display unattended upgrades status
@@ -172,35 +349,144 @@ func DisplaySecurityStatusWithCustomPrinter(cfg *config.Config, status *Security
 	} else {
 		indentedPrintFn(formatter.FormatConfigured("Auto Updates", "Configured", "", "dark"))
 	}
-}
 
-func GetSecurityRiskLevel(status *SecurityStatus) (string, string, string) {
-	// Calculate overall score
-	score := 0
-	if !status.RootLoginEnabled {
-		score++
+	// Display directory authentication status
+	if status.DirectoryAuth {
+		sources := strings.Join(status.DirectorySources, ", ")
+		if status.DirectoryKeyLookup {
+			indentedPrintFn(formatter.FormatConfigured("Directory", sources, "keys via AuthorizedKeysCommand", "dark"))
+		} else {
+			indentedPrintFn(formatter.FormatWarning("Directory", sources, "no AuthorizedKeysCommand", "dark"))
+		}
+	}
+
+	// Display password policy
+	if status.PasswordPolicy != nil {
+		if weaknesses := status.PasswordPolicy.Weaknesses(); len(weaknesses) > 0 {
+			indentedPrintFn(formatter.FormatWarning("Password Policy", "Weak", strings.Join(weaknesses, ", "), "dark"))
+		} else {
+			detail := fmt.Sprintf("min %d, %d days, lockout %d", status.PasswordPolicy.MinLength,
+				status.PasswordPolicy.MaxDays, status.PasswordPolicy.LockoutAttempts)
+			indentedPrintFn(formatter.FormatConfigured("Password Policy", "Configured", detail, "dark"))
+		}
 	}
-	if status.FirewallEnabled {
-		score++
+
+	// Display kernel modules kept from loading
+	if status.ModuleBlacklist != nil {
+		switch {
+		case len(status.ModuleBlacklist.Missing) > 0:
+			indentedPrintFn(formatter.FormatWarning("Modules", "Not Blocked",
+				strings.Join(status.ModuleBlacklist.Missing, ", "), "dark"))
+		case len(status.ModuleBlacklist.Loaded) > 0:
+			indentedPrintFn(formatter.FormatWarning("Modules", "Loaded",
+				strings.Join(status.ModuleBlacklist.Loaded, ", "), "dark"))
+		default:
+			indentedPrintFn(formatter.FormatConfigured("Modules", "Blocked",
+				fmt.Sprintf("%d modules", len(status.ModuleBlacklist.Disabled)), "dark"))
+		}
 	}
-	if status.FirewallConfigured {
-		score++
+
+	// Display the kernel command line hardening
+	if status.BootHardening != nil {
+		switch {
+		case len(status.BootHardening.Missing) > 0:
+			indentedPrintFn(formatter.FormatWarning("Boot", "Not Hardened",
+				strings.Join(status.BootHardening.Missing, " "), "dark"))
+		case status.BootHardening.RebootRequired:
+			indentedPrintFn(formatter.FormatWarning("Boot", "Reboot Required",
+				strings.Join(status.BootHardening.Pending, " "), "dark"))
+		default:
+			indentedPrintFn(formatter.FormatConfigured("Boot", "Hardened",
+				fmt.Sprintf("%d parameters", len(status.BootHardening.Configured)), "dark"))
+		}
 	}
-	if status.SecureUsers {
-		score++
+
+	// Display time synchronization
+	switch {
+	case status.TimeSync == "":
+		indentedPrintFn(formatter.FormatWarning("Time Sync", "Not Configured", "no time service running", "dark"))
+	case status.Clock != nil && !status.Clock.Synchronized:
+		indentedPrintFn(formatter.FormatWarning("Time Sync", "Not Synchronized", status.TimeSync, "dark"))
+	case status.Clock != nil && status.Clock.Drifting():
+		indentedPrintFn(formatter.FormatWarning("Time Sync", "Drifting", status.Clock.Offset(), "dark"))
+	default:
+		indentedPrintFn(formatter.FormatConfigured("Time Sync", "Configured", status.TimeSync, "dark"))
+	}
+
+	// Display log forwarding
+	if status.LogShipping != nil {
+		switch {
+		case !status.LogShipping.Configured():
+			indentedPrintFn(formatter.FormatWarning("Log Shipping", "Local Only", "logs stay on the host", "dark"))
+		case !status.LogShipping.Active:
+			indentedPrintFn(formatter.FormatWarning("Log Shipping", "Stopped", status.LogShipping.Method+" not running", "dark"))
+		case !status.LogShipping.Connected:
+			indentedPrintFn(formatter.FormatWarning("Log Shipping", "Not Connected", status.LogShipping.Target, "dark"))
+		default:
+			indentedPrintFn(formatter.FormatConfigured("Log Shipping", "Off-Host", status.LogShipping.Target, "dark"))
+		}
 	}
-	if status.AppArmorEnabled {
-		score++
+
+	// Display services waiting on a restart
+	if status.RebootRequired {
+		indentedPrintFn(formatter.FormatWarning("Restarts", "Reboot Required", "", "dark"))
+	} else if len(status.PendingRestarts) > 0 {
+		indentedPrintFn(formatter.FormatWarning("Restarts",
+			fmt.Sprintf("%d Pending", len(status.PendingRestarts)),
+			strings.Join(status.PendingRestarts, ", "), "dark"))
 	}
-	if status.UnattendedUpgrades {
-		score++
+
+	// Display certificates close to expiry
+	if status.Certificates != nil && status.Certificates.Checked > 0 {
+		if len(status.Certificates.Expiring) == 0 {
+			indentedPrintFn(formatter.FormatConfigured("Certificates", "Valid",
+				fmt.Sprintf("%d checked", status.Certificates.Checked), "dark"))
+		} else {
+			soonest := status.Certificates.Expiring[0]
+			detail := fmt.Sprintf("%s in %d days", soonest.Subject, soonest.DaysLeft)
+			if soonest.Expired() {
+				detail = soonest.Subject + " expired"
+			}
+			indentedPrintFn(formatter.FormatWarning("Certificates",
+				fmt.Sprintf("%d Expiring", len(status.Certificates.Expiring)), detail, "dark"))
+		}
 	}
-	if status.SshPortNonDefault {
-		score++
+
+	// Display the CVEs found by the last package check
+	if status.PackageAudit != nil {
+		if total := status.PackageAudit.Total(); total > 0 {
+			indentedPrintFn(formatter.FormatWarning("Packages", fmt.Sprintf("%d CVEs", total),
+				status.PackageAudit.Describe(), "dark"))
+		} else {
+			indentedPrintFn(formatter.FormatConfigured("Packages", "No Known CVEs",
+				fmt.Sprintf("%d packages", status.PackageAudit.Packages), "dark"))
+		}
 	}
-	if status.PasswordAuthDisabled {
-		score++
+}
+
+// SecurityScore counts the core checks the host passes, out of total; the
+// risk level and the posture history are derived from it
+func SecurityScore(status *SecurityStatus) (score, total int) {
+	checks := []bool{
+		!status.RootLoginEnabled,
+		status.FirewallEnabled,
+		status.FirewallConfigured,
+		status.SecureUsers,
+		status.MACEnabled,
+		status.UnattendedUpgrades,
+		status.SshPortNonDefault,
+		status.PasswordAuthDisabled,
+	}
+	for _, passed := range checks {
+		if passed {
+			score++
+		}
 	}
+	return score, len(checks)
+}
+
+func GetSecurityRiskLevel(status *SecurityStatus) (string, string, string) {
+	score, _ := SecurityScore(status)
 
 	// Determine risk level
 	var riskLevel, description, colorCode string
@@ -264,35 +550,51 @@ func checkRootLoginEnabled(osInfo *osdetect.OSInfo) bool {
 }
 
 // checkFirewallStatus checks if the firewall is enabled and properly configured
-func checkFirewallStatus() (bool, bool) {
+func checkFirewallStatus(osInfo *osdetect.OSInfo, sshPort int) (bool, bool) {
 	enabled := false
 	configured := false
 
-	// Check if UFW is installed and enabled
-	cmd := exec.Command("ufw", "status", "verbose")
-	output, err := cmd.CombinedOutput()
+	// RHEL-family hosts use firewalld; it counts as configured once its
+	// default zone rejects unmatched traffic and lets something in
+	if model.IsRHELFamily(osInfo.OsType) {
+		repo := secondary.NewFirewalldFirewallRepository(osdetect.NewRealFileSystem(), osdetect.NewRealCommander(), osInfo.OsType)
+		installed, running, denies, rules, err := repo.GetFirewallStatus()
+		if err == nil && installed {
+			enabled = running
+			configured = denies && len(rules) > 0
+		}
+		if enabled {
+			return enabled, configured
+		}
+	}
+
+	// Check if UFW is installed and enabled, using ufw's settings files
+	// and rule list instead of the translated `ufw status` text
+	output, err := interfaces.Command("ufw", "show", "added").CombinedOutput()
 	if err == nil {
-		statusOutput := string(output)
-		enabled = strings.Contains(statusOutput, "Status: active")
+		if conf, err := os.ReadFile(secondary.UFWConfPath); err == nil {
+			enabled = secondary.ParseUFWEnabled(conf)
+		}
 
 		// Check basic configuration
 		policyLines := 0
 
-		// With verbose output, the default policies appear as:
-		// "Default: deny (incoming), allow (outgoing), disabled (routed)"
-		if strings.Contains(statusOutput, "Default:") {
-			if strings.Contains(statusOutput, "deny (incoming)") {
+		if defaults, err := os.ReadFile(secondary.UFWDefaultsPath); err == nil {
+			incoming, outgoing := secondary.ParseUFWDefaultPolicies(defaults)
+			if incoming == "deny" {
 				policyLines++
 			}
-			if strings.Contains(statusOutput, "allow (outgoing)") {
+			if outgoing == "allow" {
 				policyLines++
 			}
 		}
 
-		// Check that we have at least one rule for SSH
-		if strings.Contains(statusOutput, "ALLOW IN") &&
-			strings.Contains(statusOutput, "/tcp") {
-			policyLines++
+		// Check that we have at least one rule letting traffic in (e.g. SSH)
+		for _, rule := range secondary.ParseUFWAddedRules(output) {
+			if strings.HasPrefix(rule, "allow") || strings.HasPrefix(rule, "limit") {
+				policyLines++
+				break
+			}
 		}
 
 		configured = policyLines >= 3
@@ -300,19 +602,72 @@ func checkFirewallStatus() (bool, bool) {
 
 	// Check for iptables if UFW not found
 	if !enabled {
-		iptablesCmd := exec.Command("iptables", "-L")
-		iptablesOutput, err := iptablesCmd.CombinedOutput()
+		iptablesOutput, err := interfaces.Command("iptables-save").CombinedOutput()
 		if err == nil {
-			rules := strings.Count(string(iptablesOutput), "Chain")
-			enabled = rules > 3
-			// Look for SSH related rules
-			configured = strings.Contains(strings.ToLower(string(iptablesOutput)), "ssh")
+			policies, rules := secondary.ParseIptablesSave(iptablesOutput)
+			enabled = len(rules) > 0 || policies["INPUT"] == "DROP"
+			// Look for rules covering the SSH port
+			dport := fmt.Sprintf("--dport %d", sshPort)
+			for _, rule := range rules {
+				if strings.Contains(rule, dport+" ") || strings.HasSuffix(rule, dport) {
+					configured = true
+					break
+				}
+			}
 		}
 	}
 
 	return enabled, configured
 }
 
+// checkTCPWrappers compares the TCP wrappers rules with the firewall
+func checkTCPWrappers(osInfo *osdetect.OSInfo, sshPort int) *model.TCPWrappersReport {
+	fs := osdetect.NewRealFileSystem()
+	commander := osdetect.NewRealCommander()
+	firewallService := service.NewFirewallServiceImpl(secondary.NewFirewallRepository(fs, commander, osInfo.OsType), model.OSInfo{Type: osInfo.OsType})
+	tcpWrappersService := service.NewTCPWrappersServiceImpl(secondary.NewOSTCPWrappersRepository(fs, commander))
+
+	report, err := application.NewTCPWrappersManager(tcpWrappersService, firewallService).Check(sshPort)
+	if err != nil {
+		return nil
+	}
+	return report
+}
+
+// checkSSHAccess reports whether the firewall rate-limits the SSH port and
+// whether knockd guards it
+func checkSSHAccess(osInfo *osdetect.OSInfo, sshPort int) *model.SSHAccess {
+	repo := secondary.NewFirewallRepository(osdetect.NewRealFileSystem(), osdetect.NewRealCommander(), osInfo.OsType)
+	access, err := service.NewFirewallServiceImpl(repo, model.OSInfo{Type: osInfo.OsType}).GetSSHAccess(sshPort)
+	if err != nil {
+		return nil
+	}
+	return access
+}
+
+// checkFirewallStacks compares what the firewall lets in over IPv4 and IPv6
+func checkFirewallStacks(cfg *config.Config, osInfo *osdetect.OSInfo) *model.DualStackReport {
+	repo := secondary.NewFirewallRepository(osdetect.NewRealFileSystem(), osdetect.NewRealCommander(), osInfo.OsType)
+	report, err := service.NewFirewallServiceImpl(repo, model.OSInfo{Type: osInfo.OsType}).CheckDualStack(cfg.IPv6.Policy().Mode)
+	if err != nil {
+		return nil
+	}
+	return report
+}
+
+// checkIPv6 reports how other hosts reach this one over IPv6, compared with
+// the configured IPv6 mode and firewall
+func checkIPv6(cfg *config.Config, osInfo *osdetect.OSInfo) *model.IPv6Exposure {
+	repo := secondary.NewFirewallRepository(osdetect.NewRealFileSystem(), osdetect.NewRealCommander(), osInfo.OsType)
+	manager := application.NewFirewallManager(service.NewFirewallServiceImpl(repo, model.OSInfo{Type: osInfo.OsType}))
+	exposure, err := manager.CheckIPv6(cfg.SSHFirewallPolicy(), cfg.IPv6.Policy(),
+		cfg.UfwAllowedPorts, cfg.CustomFirewallRules(), cfg.FirewallProfiles())
+	if err != nil {
+		return nil
+	}
+	return exposure
+}
+
 // checkUserSecurity checks if there are non-root users with sudo access
 func checkUserSecurity() bool {
 	// Check /etc/sudoers.d for non-root user entries
@@ -350,96 +705,54 @@ func checkUserSecurity() bool {
 	return false
 }
 
-// checkAppArmorStatus checks if AppArmor is enabled
-// checkAppArmorStatus checks if AppArmor is properly configured and enforcing
-func checkAppArmorStatus(osInfo *osdetect.OSInfo) bool {
-	// If Alpine, check if AppArmor is installed, enabled, and has profiles
-	if osInfo.OsType == "alpine" {
-		// Check if AppArmor package is installed
-		cmd := exec.Command("apk", "info", "-e", "apparmor")
-		if err := cmd.Run(); err != nil {
-			return false
-		}
-
-		// Check if AppArmor is in runlevel
-		rcCmd := exec.Command("rc-status", "default")
-		output, err := rcCmd.CombinedOutput()
-		if err != nil {
-			return false
-		}
-
-		if !strings.Contains(string(output), "apparmor") {
-			return false
-		}
-
-		// Check if AppArmor is running and has profiles loaded
-		statusCmd := exec.Command("aa-status")
-		statusOutput, err := statusCmd.CombinedOutput()
-		if err != nil {
-			return false
-		}
-
-		// Check for enforcing profiles
-		statusText := string(statusOutput)
-		if !strings.Contains(statusText, "profiles are in enforce mode") {
-			return false
-		}
-
-		// Make sure there are actual profiles enforced (not 0)
-		return !strings.Contains(statusText, "0 profiles are in enforce mode")
-	} else {
-		// For Debian/Ubuntu, check AppArmor status
-		cmd := exec.Command("aa-status")
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			return false
-		}
-
-		// Check if the service is loaded and active
-		statusText := string(output)
-		if !strings.Contains(statusText, "apparmor module is loaded") {
-			return false
-		}
-
-		// Check for loaded profiles in enforcing mode
-		if !strings.Contains(statusText, "profiles are in enforce mode") {
-			return false
-		}
-
-		// Ensure there's at least 1 profile in enforce mode
-		return !strings.Contains(statusText, "0 profiles are in enforce mode")
+// checkMACStatus reports the active mandatory access control framework
+// and whether it enforces its policy
+func checkMACStatus(osInfo *osdetect.OSInfo) *model.MACStatus {
+	repo := secondary.NewOSMACRepository(osdetect.NewRealFileSystem(), osdetect.NewRealCommander(), osInfo.OsType)
+	status, err := repo.GetMACStatus()
+	if err != nil {
+		return nil
 	}
+	return status
 }
 
 // checkUnattendedUpgrades checks if unattended upgrades are configured
 func checkUnattendedUpgrades(osInfo *osdetect.OSInfo) bool {
+	services := secondary.NewServiceManager(osdetect.NewRealCommander(), osInfo.OsType)
+	if model.IsRHELFamily(osInfo.OsType) {
+		// dnf-automatic applies updates from its timer
+		timer, err := services.Status(model.DnfAutomaticTimer)
+		return err == nil && timer.Enabled
+	}
 	if osInfo.OsType == "alpine" {
-		// Check for daily cron job
-		if _, err := os.Stat("/etc/periodic/daily/apk-upgrade"); err == nil {
-			return true
+		// Check for the daily cron job, which runs while crond is enabled
+		if _, err := os.Stat(model.AlpineAutoUpgradeScriptPath); err != nil {
+			return false
 		}
-		return false
+		crond, err := services.Status("crond")
+		return err == nil && crond.Enabled
 	} else {
 		// Check for unattended-upgrades package and configuration
-		cmd := exec.Command("dpkg", "-l", "unattended-upgrades")
-		if err := cmd.Run(); err != nil {
+		cmd := interfaces.Command("dpkg-query", "-W", "-f="+secondary.DpkgStatusFormat, "unattended-upgrades")
+		output, err := cmd.Output()
+		if err != nil || !secondary.ParseDpkgInstalled(output) {
 			return false
 		}
 
-		// Check if service is enabled
-		svcCmd := exec.Command("systemctl", "is-enabled", "unattended-upgrades")
-		if err := svcCmd.Run(); err != nil {
+		// Check that apt's daily run is enabled in 20auto-upgrades
+		data, err := os.ReadFile(model.AptAutoUpgradesPath)
+		if err != nil {
 			return false
 		}
-
-		return true
+		interval := secondary.ParseAptPeriodic(data, "APT::Periodic::Unattended-Upgrade")
+		return interval != "" && interval != "0"
 	}
 }
 
 // checkSudoConfiguration checks if sudo is configured securely
 func checkSudoConfiguration() bool {
 	// Check if sudo is installed
-	sudoCmd := exec.Command("which", "sudo")
+	sudoCmd := interfaces.Command("which", "sudo")
 	if err := sudoCmd.Run(); err != nil {
 		return false
 	}
@@ -452,6 +765,37 @@ func checkSudoConfiguration() bool {
 	return true
 }
 
+// checkDirectoryAuth reports whether accounts resolve from LDAP/SSSD, the
+// directory sources in use, and whether sshd looks up directory keys
+func checkDirectoryAuth(osInfo *osdetect.OSInfo) (bool, []string, bool) {
+	fs := osdetect.NewRealFileSystem()
+	commander := osdetect.NewRealCommander()
+
+	userRepo := secondary.NewOSUserRepository(fs, commander, osInfo.OsType)
+	dirStatus, err := userRepo.GetDirectoryAuthStatus()
+	if err != nil || !dirStatus.Enabled {
+		return false, nil, false
+	}
+
+	keyLookup := false
+	sshRepo := secondary.NewFileSSHRepository(fs, commander, osInfo.OsType)
+	if sshConfig, err := sshRepo.GetSSHConfig(); err == nil {
+		keyLookup = sshConfig.AuthorizedKeysCommand != ""
+	}
+
+	return true, dirStatus.Sources, keyLookup
+}
+
+// checkSSHProfile returns the SSH hardening profile hardn applied, if any
+func checkSSHProfile(osInfo *osdetect.OSInfo) string {
+	sshRepo := secondary.NewFileSSHRepository(osdetect.NewRealFileSystem(), osdetect.NewRealCommander(), osInfo.OsType)
+	sshConfig, err := sshRepo.GetSSHConfig()
+	if err != nil {
+		return ""
+	}
+	return sshConfig.Profile
+}
+
 // checkPasswordAuth checks if password authentication is disabled
 func checkPasswordAuth(osInfo *osdetect.OSInfo) bool {
 	var sshConfigPath string
@@ -518,3 +862,156 @@ func CheckRootLoginEnabled(osInfo *osdetect.OSInfo) bool {
 
 	return true // Default to vulnerable if not explicitly set
 }
+
+// timeSyncServices are the time synchronization daemons checked, the
+// distribution's default first
+var timeSyncServices = []string{"chronyd", "chrony", "systemd-timesyncd", "ntpd", "ntp"}
+
+// checkPasswordPolicy reads the password strength, aging and lockout in effect
+func checkPasswordPolicy(osInfo *osdetect.OSInfo) *model.PasswordPolicyStatus {
+	repo := secondary.NewOSPasswordPolicyRepository(osdetect.NewRealFileSystem(), osdetect.NewRealCommander(), osInfo.OsType)
+	status, err := repo.GetPasswordPolicyStatus()
+	if err != nil {
+		return nil
+	}
+	return status
+}
+
+// checkModuleBlacklist compares the configured module blacklist with the
+// modprobe configuration and the loaded modules
+func checkModuleBlacklist(cfg *config.Config, osInfo *osdetect.OSInfo) *model.ModuleBlacklistStatus {
+	repo := secondary.NewOSModuleBlacklistRepository(osdetect.NewRealFileSystem(), osdetect.NewRealCommander(), osInfo.OsType)
+	status, err := service.NewModuleBlacklistServiceImpl(repo, model.OSInfo{Type: osInfo.OsType, Version: osInfo.OsVersion}).
+		GetBlacklistStatus(cfg.ModuleBlacklist.Policy())
+	if err != nil {
+		return nil
+	}
+	return status
+}
+
+// checkBootHardening compares the configured kernel parameters with the
+// GRUB configuration and the running kernel
+func checkBootHardening(cfg *config.Config, osInfo *osdetect.OSInfo) *model.BootHardeningStatus {
+	if !cfg.BootHardening.Enabled || osInfo.Environment.IsContainer() || osInfo.IsWSL {
+		return nil
+	}
+	repo := secondary.NewOSBootRepository(osdetect.NewRealFileSystem(), osdetect.NewRealCommander(), osInfo.OsType)
+	status, err := service.NewBootHardeningServiceImpl(repo, model.OSInfo{Type: osInfo.OsType, Version: osInfo.OsVersion}).
+		GetStatus(cfg.BootHardening.Policy())
+	if err != nil {
+		return nil
+	}
+	return status
+}
+
+// checkTimeSync returns the first time synchronization service running
+func checkTimeSync(osInfo *osdetect.OSInfo) string {
+	services := secondary.NewServiceManager(osdetect.NewRealCommander(), osInfo.OsType)
+	for _, service := range timeSyncServices {
+		if status, err := services.Status(service); err == nil && status.Active {
+			return service
+		}
+	}
+	return ""
+}
+
+// checkClock reads the time synchronization daemon's view of the clock
+func checkClock(osInfo *osdetect.OSInfo) *model.TimeSyncStatus {
+	repo := secondary.NewOSTimeSyncRepository(osdetect.NewRealFileSystem(), osdetect.NewRealCommander(), osInfo.OsType)
+	status, err := repo.GetTimeSyncStatus()
+	if err != nil || status.Daemon == "" {
+		return nil
+	}
+	return status
+}
+
+// checkLogShipping reads the log forwarding hardn configured
+func checkLogShipping(osInfo *osdetect.OSInfo) *model.LogShippingStatus {
+	repo := secondary.NewOSLogShippingRepository(osdetect.NewRealFileSystem(), osdetect.NewRealCommander(), osInfo.OsType)
+	status, err := repo.GetLogShippingStatus()
+	if err != nil {
+		return nil
+	}
+	return status
+}
+
+// checkPendingRestarts lists services still running replaced libraries and
+// reports whether a reboot is required (Debian/Ubuntu and the RHEL family)
+func checkPendingRestarts(osInfo *osdetect.OSInfo) ([]string, bool) {
+	if osInfo.OsType == "alpine" {
+		return nil, false
+	}
+
+	if model.IsRHELFamily(osInfo.OsType) {
+		// needs-restarting from dnf-utils exits 1 when a reboot is needed
+		// and lists services running replaced files with -s
+		cmd := interfaces.Command("needs-restarting", "-r")
+		if err := cmd.Run(); err != nil && cmd.ProcessState == nil {
+			return nil, false
+		}
+		rebootRequired := !cmd.ProcessState.Success()
+
+		output, err := interfaces.Command("needs-restarting", "-s").Output()
+		if err != nil {
+			return nil, rebootRequired
+		}
+		return strings.Fields(string(output)), rebootRequired
+	}
+
+	_, err := os.Stat(secondary.RebootRequiredPath)
+	rebootRequired := err == nil
+
+	// Batch mode prints NEEDRESTART-* lines; list mode never restarts anything
+	output, err := interfaces.Command("needrestart", "-b", "-r", "l").Output()
+	if err != nil {
+		return nil, rebootRequired
+	}
+
+	services, kernelOutdated := secondary.ParseNeedrestartBatch(output)
+	return services, rebootRequired || kernelOutdated
+}
+
+// checkWebServers detects nginx, Apache and Caddy and their TLS baseline state
+func checkWebServers(osInfo *osdetect.OSInfo) []model.WebServer {
+	repo := secondary.NewOSWebServerRepository(osdetect.NewRealFileSystem(), osdetect.NewRealCommander(), osInfo.OsType)
+	servers, err := repo.DetectWebServers()
+	if err != nil {
+		return nil
+	}
+	return servers
+}
+
+// checkDatabases detects PostgreSQL and MySQL/MariaDB and their baseline state
+func checkDatabases(osInfo *osdetect.OSInfo) []model.DatabaseServer {
+	repo := secondary.NewOSDatabaseRepository(osdetect.NewRealFileSystem(), osdetect.NewRealCommander(), osInfo.OsType)
+	databases, err := repo.DetectDatabases()
+	if err != nil {
+		return nil
+	}
+	return databases
+}
+
+// checkCertificates reads configured and common certificate locations for
+// certificates close to expiry
+func checkCertificates(cfg *config.Config) *model.CertificateExpiry {
+	repo := secondary.NewFileCertificateRepository(osdetect.NewRealFileSystem(), osdetect.NewRealCommander())
+	expiry, err := service.NewCertificateServiceImpl(repo).CheckExpiry(
+		cfg.CertificateMonitoring.Paths, cfg.CertificateMonitoring.ExpiryDays)
+	if err != nil {
+		return nil
+	}
+	return expiry
+}
+
+// checkPackageAudit reads the advisory counts of the last package check,
+// which "hardn audit --packages" keeps; checking takes too long for the
+// status display
+func checkPackageAudit(osInfo *osdetect.OSInfo) *model.PackageAuditSummary {
+	repo := secondary.NewOSPackageAuditRepository(osdetect.NewRealFileSystem(), osdetect.NewRealCommander(),
+		osInfo.OsType, osInfo.OsVersion, osInfo.OsCodename)
+	summary, err := application.NewPackageAuditManager(service.NewPackageAuditServiceImpl(repo)).LastSummary()
+	if err != nil {
+		return nil
+	}
+	return summary
+}
@@ -0,0 +1,91 @@
+// pkg/session/recording.go
+package session
+
+import (
+	"io/fs"
+
+	"github.com/abbott/hardn/pkg/interfaces"
+)
+
+// RecordingFileSystem records changes made through a FileSystem; reads are
+// passed through unrecorded
+type RecordingFileSystem struct {
+	interfaces.FileSystem
+	recorder *Recorder
+}
+
+// NewRecordingFileSystem wraps fs so its changes are recorded
+func NewRecordingFileSystem(fs interfaces.FileSystem, recorder *Recorder) interfaces.FileSystem {
+	return &RecordingFileSystem{FileSystem: fs, recorder: recorder}
+}
+
+func (f *RecordingFileSystem) WriteFile(filename string, data []byte, perm fs.FileMode) error {
+	err := f.FileSystem.WriteFile(filename, data, perm)
+	f.recorder.Record(Event{Kind: KindWrite, Path: filename, Bytes: len(data), Error: errorText(err)})
+	return err
+}
+
+func (f *RecordingFileSystem) MkdirAll(path string, perm fs.FileMode) error {
+	err := f.FileSystem.MkdirAll(path, perm)
+	f.recorder.Record(Event{Kind: KindMkdir, Path: path, Error: errorText(err)})
+	return err
+}
+
+func (f *RecordingFileSystem) Remove(name string) error {
+	err := f.FileSystem.Remove(name)
+	f.recorder.Record(Event{Kind: KindRemove, Path: name, Error: errorText(err)})
+	return err
+}
+
+func (f *RecordingFileSystem) RemoveAll(path string) error {
+	err := f.FileSystem.RemoveAll(path)
+	f.recorder.Record(Event{Kind: KindRemove, Path: path, Error: errorText(err)})
+	return err
+}
+
+func (f *RecordingFileSystem) Rename(oldpath, newpath string) error {
+	err := f.FileSystem.Rename(oldpath, newpath)
+	f.recorder.Record(Event{Kind: KindRename, Path: oldpath, Target: newpath, Error: errorText(err)})
+	return err
+}
+
+// RecordingCommander records the commands run through a Commander; their
+// output and any input are not recorded
+type RecordingCommander struct {
+	interfaces.Commander
+	recorder *Recorder
+}
+
+// NewRecordingCommander wraps commander so the commands it runs are recorded
+func NewRecordingCommander(commander interfaces.Commander, recorder *Recorder) interfaces.Commander {
+	return &RecordingCommander{Commander: commander, recorder: recorder}
+}
+
+func (c *RecordingCommander) Execute(command string, args ...string) ([]byte, error) {
+	output, err := c.Commander.Execute(command, args...)
+	c.recorder.Record(Event{Kind: KindCommand, Value: command, Args: args, Error: errorText(err)})
+	return output, err
+}
+
+func (c *RecordingCommander) ExecuteWithInput(input string, command string, args ...string) ([]byte, error) {
+	output, err := c.Commander.ExecuteWithInput(input, command, args...)
+	c.recorder.Record(Event{Kind: KindCommand, Value: command, Args: args, Bytes: len(input), Error: errorText(err)})
+	return output, err
+}
+
+// RecordProvider returns a copy of provider whose file changes and commands
+// are recorded
+func RecordProvider(provider *interfaces.Provider, recorder *Recorder) *interfaces.Provider {
+	return &interfaces.Provider{
+		FS:        NewRecordingFileSystem(provider.FS, recorder),
+		Commander: NewRecordingCommander(provider.Commander, recorder),
+		Network:   provider.Network,
+	}
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,110 @@
+// pkg/session/replay.go
+package session
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Player feeds the inputs of a recorded session back to the menus
+type Player struct {
+	mu         sync.Mutex
+	inputs     []Event
+	next       int
+	mismatches []string
+}
+
+// NewPlayer creates a Player for the input events of a transcript
+func NewPlayer(events []Event) *Player {
+	player := &Player{}
+	for _, event := range events {
+		if event.Kind == KindInput {
+			player.inputs = append(player.inputs, event)
+		}
+	}
+	return player
+}
+
+// NextInput returns the next recorded input. When the recording runs out,
+// menus are quit with "q" and other prompts get an empty answer so the
+// replay always ends.
+func (p *Player) NextInput(kind string, screen string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.next >= len(p.inputs) {
+		if kind == InputMenu {
+			return "q"
+		}
+		return ""
+	}
+
+	event := p.inputs[p.next]
+	p.next++
+	if event.Input != kind || event.Screen != screen {
+		p.mismatches = append(p.mismatches, fmt.Sprintf(
+			"input %d: recorded %s on %q, replay asked for %s on %q",
+			p.next, event.Input, event.Screen, kind, screen))
+	}
+	return event.Value
+}
+
+// Remaining returns the number of recorded inputs not yet replayed
+func (p *Player) Remaining() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.inputs) - p.next
+}
+
+// Mismatches describes inputs that were replayed on a different screen or
+// prompt than they were recorded on
+func (p *Player) Mismatches() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.mismatches...)
+}
+
+// CompareOperations lists the differences between the operations of a
+// recorded session and those of its replay, in order
+func CompareOperations(recorded []Event, replayed []Event) []string {
+	want := operationLines(recorded)
+	got := operationLines(replayed)
+
+	var differences []string
+	for i := 0; i < len(want) || i < len(got); i++ {
+		switch {
+		case i >= len(got):
+			differences = append(differences, fmt.Sprintf("- %s", want[i]))
+		case i >= len(want):
+			differences = append(differences, fmt.Sprintf("+ %s", got[i]))
+		case want[i] != got[i]:
+			differences = append(differences, fmt.Sprintf("- %s", want[i]), fmt.Sprintf("+ %s", got[i]))
+		}
+	}
+	return differences
+}
+
+// Describe returns a one-line description of an operation
+func (e Event) Describe() string {
+	switch e.Kind {
+	case KindCommand:
+		return strings.TrimSpace("run " + e.Value + " " + strings.Join(e.Args, " "))
+	case KindWrite:
+		return fmt.Sprintf("write %s (%d bytes)", e.Path, e.Bytes)
+	case KindRename:
+		return fmt.Sprintf("rename %s to %s", e.Path, e.Target)
+	default:
+		return e.Kind + " " + e.Path
+	}
+}
+
+func operationLines(events []Event) []string {
+	var lines []string
+	for _, event := range events {
+		if event.IsOperation() {
+			lines = append(lines, event.Describe())
+		}
+	}
+	return lines
+}
@@ -0,0 +1,77 @@
+// pkg/session/sanitize.go
+package session
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	sshKeyPattern   = regexp.MustCompile(`\b((?:ssh-(?:rsa|dss|ed25519)|ecdsa-sha2-nistp\d+|sk-[a-z0-9@.-]+))\s+AAAA[0-9A-Za-z+/=]+(?:\s+\S+)?`)
+	pemPattern      = regexp.MustCompile(`-----BEGIN [A-Z0-9 ]+-----[\s\S]*?(?:-----END [A-Z0-9 ]+-----|$)`)
+	urlCredPattern  = regexp.MustCompile(`(://)[^/\s:@]+:[^/\s@]+@`)
+	secretPattern   = regexp.MustCompile(`(?i)\b(password|passwd|passphrase|secret|token|api[_-]?key)(\s*[=:]\s*)\S+`)
+	placeholderName = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Sanitizer removes secrets and identifying values from transcripts
+type Sanitizer struct {
+	literals map[string]string
+}
+
+// NewSanitizer creates a Sanitizer that redacts keys, certificates and
+// credentials
+func NewSanitizer() *Sanitizer {
+	return &Sanitizer{literals: make(map[string]string)}
+}
+
+// Redact replaces every occurrence of value with <name>, e.g. the configured
+// username or the host name
+func (s *Sanitizer) Redact(name string, value string) {
+	value = strings.TrimSpace(value)
+	if len(value) < 2 {
+		return
+	}
+	s.literals[value] = "<" + strings.Trim(placeholderName.ReplaceAllString(strings.ToLower(name), "-"), "-") + ">"
+}
+
+// String sanitizes a single value
+func (s *Sanitizer) String(value string) string {
+	if value == "" {
+		return value
+	}
+
+	value = pemPattern.ReplaceAllString(value, "<redacted PEM block>")
+	value = sshKeyPattern.ReplaceAllString(value, "$1 <redacted key>")
+	value = urlCredPattern.ReplaceAllString(value, "$1<redacted>@")
+	value = secretPattern.ReplaceAllString(value, "$1$2<redacted>")
+
+	// Longer values first so one value containing another is replaced whole
+	literals := make([]string, 0, len(s.literals))
+	for literal := range s.literals {
+		literals = append(literals, literal)
+	}
+	sort.Slice(literals, func(i, j int) bool { return len(literals[i]) > len(literals[j]) })
+	for _, literal := range literals {
+		value = strings.ReplaceAll(value, literal, s.literals[literal])
+	}
+	return value
+}
+
+// Event sanitizes every free-form field of an event
+func (s *Sanitizer) Event(event Event) Event {
+	event.Screen = s.String(event.Screen)
+	event.Value = s.String(event.Value)
+	event.Path = s.String(event.Path)
+	event.Target = s.String(event.Target)
+	event.Error = s.String(event.Error)
+	if event.Args != nil {
+		args := make([]string, len(event.Args))
+		for i, arg := range event.Args {
+			args[i] = s.String(arg)
+		}
+		event.Args = args
+	}
+	return event
+}
@@ -0,0 +1,177 @@
+// pkg/session/session.go
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Event kinds recorded in a transcript
+const (
+	KindSession = "session" // header: hardn version and OS
+	KindInput   = "input"   // a menu choice, key press or line of text
+	KindCommand = "command" // a command run through the Commander
+	KindWrite   = "write"   // a file written through the FileSystem
+	KindRemove  = "remove"  // a file or directory removed
+	KindMkdir   = "mkdir"   // a directory created
+	KindRename  = "rename"  // a file renamed
+)
+
+// Input kinds, matching the menu input functions
+const (
+	InputMenu = "menu" // ReadMenuInput
+	InputLine = "line" // ReadInput
+	InputKey  = "key"  // ReadKey
+)
+
+// Event is one line of a session transcript. Only what is needed to follow
+// the session is kept: file contents and command output are never recorded.
+type Event struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	Screen string    `json:"screen,omitempty"`
+	Input  string    `json:"input,omitempty"`
+	Value  string    `json:"value,omitempty"`
+	Args   []string  `json:"args,omitempty"`
+	Path   string    `json:"path,omitempty"`
+	Target string    `json:"target,omitempty"`
+	Bytes  int       `json:"bytes,omitempty"`
+	Error  string    `json:"error,omitempty"`
+
+	// Header fields
+	Version   string `json:"version,omitempty"`
+	OsType    string `json:"osType,omitempty"`
+	OsVersion string `json:"osVersion,omitempty"`
+	Codename  string `json:"codename,omitempty"`
+	IsProxmox bool   `json:"isProxmox,omitempty"`
+	Degraded  bool   `json:"degraded,omitempty"`
+	Arch      string `json:"arch,omitempty"`
+	DryRun    bool   `json:"dryRun,omitempty"`
+}
+
+// IsOperation reports whether the event changed, or would change, the system
+func (e Event) IsOperation() bool {
+	switch e.Kind {
+	case KindCommand, KindWrite, KindRemove, KindMkdir, KindRename:
+		return true
+	}
+	return false
+}
+
+// Recorder writes sanitized events to a transcript as JSON lines
+type Recorder struct {
+	mu        sync.Mutex
+	out       io.Writer
+	closer    io.Closer
+	sanitizer *Sanitizer
+	screen    func() string
+	now       func() time.Time
+	err       error
+}
+
+// NewRecorder creates a Recorder writing to out
+func NewRecorder(out io.Writer, sanitizer *Sanitizer) *Recorder {
+	if sanitizer == nil {
+		sanitizer = NewSanitizer()
+	}
+	return &Recorder{out: out, sanitizer: sanitizer, now: time.Now}
+}
+
+// CreateRecorder creates a Recorder writing to a new transcript file that
+// only its owner can read
+func CreateRecorder(path string, sanitizer *Sanitizer) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session transcript: %w", err)
+	}
+	recorder := NewRecorder(file, sanitizer)
+	recorder.closer = file
+	return recorder, nil
+}
+
+// SetScreen sets how the recorder finds the current menu screen, which is
+// noted on every event
+func (r *Recorder) SetScreen(screen func() string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.screen = screen
+}
+
+// Record sanitizes and writes an event; the first write error is kept and
+// returned by Close so recording never interrupts the session
+func (r *Recorder) Record(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+
+	if event.Screen == "" && r.screen != nil {
+		event.Screen = r.screen()
+	}
+	event = r.sanitizer.Event(event)
+	if event.Time.IsZero() {
+		event.Time = r.now().UTC()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		r.err = err
+		return
+	}
+	if _, err := r.out.Write(append(data, '\n')); err != nil {
+		r.err = fmt.Errorf("failed to write session transcript: %w", err)
+	}
+}
+
+// Close closes the transcript file and reports any write error
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closer != nil {
+		if err := r.closer.Close(); err != nil && r.err == nil {
+			r.err = err
+		}
+		r.closer = nil
+	}
+	return r.err
+}
+
+// LoadTranscript reads the events of a transcript
+func LoadTranscript(path string) ([]Event, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session transcript: %w", err)
+	}
+	defer file.Close()
+	return ReadTranscript(file)
+}
+
+// ReadTranscript reads events from JSON lines
+func ReadTranscript(in io.Reader) ([]Event, error) {
+	var events []Event
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("invalid transcript line %d: %w", line, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read session transcript: %w", err)
+	}
+	if len(events) == 0 || events[0].Kind != KindSession {
+		return nil, fmt.Errorf("not a hardn session transcript")
+	}
+	return events, nil
+}
@@ -3,8 +3,6 @@ package style
 import (
 	"fmt"
 	"strings"
-
-	"github.com/mattn/go-runewidth"
 )
 
 type BoxConfig struct {
@@ -222,7 +220,7 @@ func (b *Box) DrawTop() {
 		beforeTitle := 0 // minimum spacing before title
 
 		// Generate the border with title
-		rightSide := Dimmed(strings.Repeat(horizChar, b.width-beforeTitle-titleLen-1)+topRightChar, b.borderColor)
+		rightSide := Dimmed(repeatWidth(horizChar, b.width-beforeTitle-titleLen-1)+topRightChar, b.borderColor)
 
 		line := ""
 		topBorder += strings.Repeat(horizChar, beforeTitle)
@@ -279,7 +277,7 @@ func (b *Box) DrawTopHeader() {
 		leftSide += strings.Repeat(headerChar, beforeTitle)
 		line += (Colored(b.shadeColor, leftSide))
 
-		rightSide := Colored(b.shadeColor, strings.Repeat(headerChar, b.width-beforeTitle-titleLen-1))
+		rightSide := Colored(b.shadeColor, repeatWidth(headerChar, b.width-beforeTitle-titleLen-1))
 
 		BoldedTitle := Bolded(b.title)
 
@@ -344,7 +342,7 @@ func (b *Box) SectionHeader(label string, color ...string) {
 		leftSide += strings.Repeat(headerChar, beforeLabel)
 		line += (Colored(b.shadeColor, leftSide))
 
-		rightSide := Dimmed(strings.Repeat(horizChar, b.width-beforeLabel-labelLen-1), b.borderColor)
+		rightSide := Dimmed(repeatWidth(horizChar, b.width-beforeLabel-labelLen-1), b.borderColor)
 
 		// boldLabel := Bolded(label)
 		// b.titleColor
@@ -431,7 +429,7 @@ func (b *Box) SectionNotice(label string, message string, notice ...string) {
 		labelLine += (Colored(secColor, leftSide))
 		messageLine += (Colored(secColor, leftSide))
 
-		// rightSide := Dimmed(strings.Repeat(horizChar, b.width-beforeLabel-labelLen-1), b.borderColor)
+		// rightSide := Dimmed(repeatWidth(horizChar, b.width-beforeLabel-labelLen-1), b.borderColor)
 
 		// BoldedLabel := Bolded(label)
 
@@ -557,6 +555,12 @@ func (b *Box) DrawLine(content string) {
 	// Calculate the visual width of the content using go-runewidth
 	visibleLen := CalculateVisualWidth(content)
 
+	// Keep the right border in place when content is wider than the box
+	if visibleLen > b.width {
+		content = TruncateVisible(content, b.width, SymEllipsis)
+		visibleLen = CalculateVisualWidth(content)
+	}
+
 	padding := b.width - visibleLen
 	if padding < 0 {
 		padding = 0
@@ -584,16 +588,6 @@ func (b *Box) DrawLine(content string) {
 	fmt.Println(line)
 }
 
-// return the visual width of a string as it would appear in a terminal
-// Using the go-runewidth package for accurate width calculation
-func CalculateVisualWidth(s string) int {
-	// First strip ANSI codes to get the displayable text
-	plainText := StripAnsi(s)
-
-	// Use go-runewidth to calculate the string width
-	return runewidth.StringWidth(plainText)
-}
-
 // draw a complete box with the provided content function
 func (b *Box) DrawBox(contentFn func(printLine func(string))) {
 
@@ -690,13 +684,8 @@ func (b *Box) DrawTruncatedText(text string, truncateIndicator string) {
 		return
 	}
 
-	// Use go-runewidth's built-in truncation function
-	plainText := StripAnsi(text)
-	truncatedText := runewidth.Truncate(plainText, b.width-len(truncateIndicator), truncateIndicator)
-
-	// Simplified handling for ANSI codes
-	// we would need to preserve the ANSI codes from the original text
-	b.DrawLine(truncatedText)
+	// Truncate by visible width, keeping the text's styling
+	b.DrawLine(TruncateVisible(text, b.width, truncateIndicator))
 }
 
 // draw text aligned to the right side of the box
@@ -852,7 +841,7 @@ func BoxedTitle(title string, width int, color ...string) string {
 	topBorder := topLeftChar + strings.Repeat(horizChar, innerWidth) + topRightChar
 	emptyLine := vertChar + strings.Repeat(" ", innerWidth) + vertChar
 	titleLine := vertChar + strings.Repeat(" ", titlePadding) + title
-	titleLine += strings.Repeat(" ", innerWidth-titlePadding-titleLen) + vertChar
+	titleLine += repeatWidth(" ", innerWidth-titlePadding-titleLen) + vertChar
 	bottomBorder := bottomLeftChar + strings.Repeat(horizChar, innerWidth) + bottomRightChar
 
 	result := ""
@@ -862,7 +851,7 @@ func BoxedTitle(title string, width int, color ...string) string {
 		topBorder = Dimmed(topBorder, boxColor)
 		emptyLine = Dimmed(vertChar, boxColor) + strings.Repeat(" ", innerWidth) + Dimmed(vertChar, boxColor)
 		titleLine = Dimmed(vertChar, boxColor) + strings.Repeat(" ", titlePadding) + Colored(boxColor, title)
-		titleLine += strings.Repeat(" ", innerWidth-titlePadding-titleLen) + Dimmed(vertChar, boxColor)
+		titleLine += repeatWidth(" ", innerWidth-titlePadding-titleLen) + Dimmed(vertChar, boxColor)
 		bottomBorder = Dimmed(bottomBorder, boxColor)
 	}
 
@@ -901,10 +890,10 @@ func ScreenHeader(title string, width int, options ...string) string {
 
 	// Format header elements
 	leftSide := Colored(borderColor, strings.Repeat(borderCharacter, beforeTitle))
-	rightSide := Colored(borderColor, strings.Repeat(borderCharacter, width-beforeTitle-titleLen-1)+borderCharacter)
+	rightSide := Colored(borderColor, repeatWidth(borderCharacter, width-beforeTitle-titleLen-1)+borderCharacter)
 
 	// leftSide := Dimmed(borderCharacter, borderColor)
-	// rightSide := Dimmed(strings.Repeat(borderCharacter, width-beforeTitle-titleLen-1)+borderCharacter, borderColor)
+	// rightSide := Dimmed(repeatWidth(borderCharacter, width-beforeTitle-titleLen-1)+borderCharacter, borderColor)
 
 	// Build header
 	header := leftSide + " " + title + " " + rightSide
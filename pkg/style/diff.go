@@ -0,0 +1,183 @@
+package style
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// diffOp is one line of an edit script
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	text string
+}
+
+// UnifiedDiff renders the change from before to after as a unified diff
+// of path, with removals in red and additions in green. It returns an
+// empty string when the contents are the same. A nil before is shown as a
+// new file.
+func UnifiedDiff(path string, before, after []byte) string {
+	return unifiedDiff(path, before, after, Colored)
+}
+
+// PlainUnifiedDiff renders the same diff as UnifiedDiff without colors,
+// for reports written to files
+func PlainUnifiedDiff(path string, before, after []byte) string {
+	return unifiedDiff(path, before, after, func(_, text string) string { return text })
+}
+
+func unifiedDiff(path string, before, after []byte, paint func(color, text string) string) string {
+	if string(before) == string(after) {
+		return ""
+	}
+
+	oldName := "a" + path
+	if before == nil {
+		oldName = "/dev/null"
+	}
+
+	var b strings.Builder
+	b.WriteString(paint(Bold, "--- "+oldName) + "\n")
+	b.WriteString(paint(Bold, "+++ b"+path) + "\n")
+
+	ops := diffLines(splitLines(before), splitLines(after))
+	for _, hunk := range diffHunks(ops) {
+		b.WriteString(paint(Cyan, hunk.header) + "\n")
+		for _, op := range ops[hunk.start:hunk.end] {
+			line := string(op.kind) + op.text
+			switch op.kind {
+			case '-':
+				line = paint(Red, line)
+			case '+':
+				line = paint(Green, line)
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// splitLines splits content into lines without their line endings
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
+
+// diffLines builds the edit script turning a into b from their longest
+// common subsequence
+func diffLines(a, b []string) []diffOp {
+	// lcs[i][j] is the length of the common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// diffHunk is a range of an edit script shown under one @@ header
+type diffHunk struct {
+	start, end int
+	header     string
+}
+
+// diffHunks groups the changes of an edit script with diffContext lines
+// of context, merging changes whose context overlaps
+func diffHunks(ops []diffOp) []diffHunk {
+	var hunks []diffHunk
+	for i := 0; i < len(ops); i++ {
+		if ops[i].kind == ' ' {
+			continue
+		}
+
+		start := max(i-diffContext, 0)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			// Stop once the unchanged run is longer than both contexts
+			run := end
+			for run < len(ops) && ops[run].kind == ' ' {
+				run++
+			}
+			if run == len(ops) || run-end > 2*diffContext {
+				end = min(end+diffContext, len(ops))
+				break
+			}
+			end = run
+		}
+
+		hunks = append(hunks, diffHunk{start: start, end: end, header: hunkHeader(ops, start, end)})
+		i = end - 1
+	}
+	return hunks
+}
+
+// hunkHeader formats the @@ -l,s +l,s @@ line of ops[start:end]
+func hunkHeader(ops []diffOp, start, end int) string {
+	oldLine, newLine := 1, 1
+	for _, op := range ops[:start] {
+		if op.kind != '+' {
+			oldLine++
+		}
+		if op.kind != '-' {
+			newLine++
+		}
+	}
+
+	oldCount, newCount := 0, 0
+	for _, op := range ops[start:end] {
+		if op.kind != '+' {
+			oldCount++
+		}
+		if op.kind != '-' {
+			newCount++
+		}
+	}
+
+	// An empty side is numbered from the line before it
+	if oldCount == 0 {
+		oldLine--
+	}
+	if newCount == 0 {
+		newLine--
+	}
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldLine, oldCount, newLine, newCount)
+}
@@ -0,0 +1,195 @@
+package style
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Checklist step states
+const (
+	stepPending = iota
+	stepRunning
+	stepDone
+	stepFailed
+)
+
+const progressBarWidth = 20
+
+// checklistStep is one line of a ProgressChecklist
+type checklistStep struct {
+	name  string
+	state int
+	err   error
+}
+
+// ProgressChecklist renders the steps of a long operation as a checklist
+// with an overall progress bar. On a terminal the checklist is redrawn in
+// place; elsewhere each change is printed on a line of its own, so logs
+// and pipes stay readable.
+type ProgressChecklist struct {
+	mu      sync.Mutex
+	out     io.Writer
+	live    bool
+	title   string
+	steps   []checklistStep
+	percent int
+	detail  string
+	drawn   int
+}
+
+// NewProgressChecklist creates a checklist on stdout, redrawn in place when
+// stdout is a terminal
+func NewProgressChecklist(title string) *ProgressChecklist {
+	live := false
+	if info, err := os.Stdout.Stat(); err == nil {
+		live = info.Mode()&os.ModeCharDevice != 0
+	}
+	return NewProgressChecklistWriter(os.Stdout, title, live)
+}
+
+// NewProgressChecklistWriter creates a checklist on out; live redraws it in
+// place with ANSI cursor movement
+func NewProgressChecklistWriter(out io.Writer, title string, live bool) *ProgressChecklist {
+	return &ProgressChecklist{out: out, title: title, live: live}
+}
+
+// Plan lists the steps about to run
+func (p *ProgressChecklist) Plan(steps []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.steps = make([]checklistStep, len(steps))
+	for i, name := range steps {
+		p.steps[i] = checklistStep{name: name}
+	}
+	if p.live {
+		p.redraw()
+		return
+	}
+	fmt.Fprintf(p.out, "%s: %d steps\n", p.title, len(steps))
+}
+
+// StepStarted marks a step as running
+func (p *ProgressChecklist) StepStarted(step string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	i := p.find(step)
+	p.steps[i].state = stepRunning
+	p.percent, p.detail = -1, ""
+	if p.live {
+		p.redraw()
+		return
+	}
+	fmt.Fprintf(p.out, "%s [%d/%d] %s\n", Colored(Cyan, SymArrowRight), i+1, len(p.steps), step)
+}
+
+// StepProgress reports how far the running step is
+func (p *ProgressChecklist) StepProgress(percent int, detail string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.percent, p.detail = min(max(percent, 0), 100), detail
+	if p.live {
+		p.redraw()
+		return
+	}
+	if detail != "" {
+		fmt.Fprintf(p.out, "  %3d%% %s\n", p.percent, detail)
+	}
+}
+
+// StepCompleted marks a step as done or failed
+func (p *ProgressChecklist) StepCompleted(step string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	i := p.find(step)
+	p.steps[i].state, p.steps[i].err = stepDone, err
+	if err != nil {
+		p.steps[i].state = stepFailed
+	}
+	p.percent, p.detail = -1, ""
+	if p.live {
+		p.redraw()
+		return
+	}
+	fmt.Fprintln(p.out, p.stepLine(p.steps[i]))
+}
+
+// find returns the index of a step, adding steps that were not planned
+func (p *ProgressChecklist) find(step string) int {
+	for i := range p.steps {
+		if p.steps[i].name == step {
+			return i
+		}
+	}
+	p.steps = append(p.steps, checklistStep{name: step})
+	return len(p.steps) - 1
+}
+
+// redraw moves the cursor back over the previous drawing and prints the
+// checklist again
+func (p *ProgressChecklist) redraw() {
+	var b strings.Builder
+	if p.drawn > 0 {
+		fmt.Fprintf(&b, "\033[%dA", p.drawn)
+	}
+
+	lines := p.render()
+	for _, line := range lines {
+		b.WriteString("\r\033[2K" + line + "\n")
+	}
+	fmt.Fprint(p.out, b.String())
+	p.drawn = len(lines)
+}
+
+// render returns the header with the overall progress and a line per step
+func (p *ProgressChecklist) render() []string {
+	finished := 0
+	for _, step := range p.steps {
+		if step.state == stepDone || step.state == stepFailed {
+			finished++
+		}
+	}
+
+	overall := 0
+	if len(p.steps) > 0 {
+		overall = finished * 100 / len(p.steps)
+	}
+	lines := []string{fmt.Sprintf("%s %s %d/%d", Bolded(p.title, Blue), ProgressBar(overall, progressBarWidth), finished, len(p.steps))}
+
+	for _, step := range p.steps {
+		line := p.stepLine(step)
+		if step.state == stepRunning && p.percent >= 0 {
+			line += " " + ProgressBar(p.percent, progressBarWidth/2) + fmt.Sprintf(" %d%%", p.percent)
+			if p.detail != "" {
+				line += " " + Dimmed(p.detail)
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// stepLine formats a step with the symbol of its state
+func (p *ProgressChecklist) stepLine(step checklistStep) string {
+	switch step.state {
+	case stepRunning:
+		return fmt.Sprintf("  %s %s", Colored(Cyan, SymRightCarrot), Bolded(step.name, Cyan))
+	case stepDone:
+		return fmt.Sprintf("  %s %s", Colored(Green, SymCheckMark), step.name)
+	case stepFailed:
+		return fmt.Sprintf("  %s %s: %v", Colored(Red, SymCrossMark), step.name, step.err)
+	}
+	return fmt.Sprintf("  %s %s", Dimmed(SymDash), Dimmed(step.name))
+}
+
+// ProgressBar draws a bar width cells wide, filled to percent
+func ProgressBar(percent int, width int) string {
+	filled := min(max(percent, 0), 100) * width / 100
+	return Colored(Green, strings.Repeat("━", filled)) + Dimmed(strings.Repeat("─", width-filled))
+}
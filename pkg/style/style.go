@@ -5,6 +5,8 @@ import (
 	"os"
 	"regexp"
 	"strings"
+
+	"github.com/mattn/go-runewidth"
 )
 
 type MenuOption struct {
@@ -12,6 +14,17 @@ type MenuOption struct {
 	Title       string
 	Description string
 	Style       string
+	// Note follows the description in a fainter shade, e.g. when the
+	// option was last applied
+	Note string
+	// DisabledReason marks the option unavailable and says why, e.g.
+	// "requires UFW installed"; disabled options are dimmed and rejected
+	DisabledReason string
+}
+
+// Disabled reports whether the option can not be selected
+func (o MenuOption) Disabled() bool {
+	return o.DisabledReason != ""
 }
 
 type Menu struct {
@@ -289,7 +302,7 @@ func HeaderLabel(text string) string {
 
 // Header creates a section header with bold blue text
 func Header(text string) string {
-	return "\n" + Bold + Blue + text + Reset + "\n" + Blue + strings.Repeat("-", len(text)) + Reset
+	return "\n" + Bold + Blue + text + Reset + "\n" + Blue + strings.Repeat("-", CalculateVisualWidth(text)) + Reset
 }
 
 func SubHeader(text string) string {
@@ -302,6 +315,22 @@ func MenuHeader(text string) string {
 	// return "\n" + Underline + Bold + Blue + text + Reset + "\n"
 }
 
+// Breadcrumb renders the path to the current screen, e.g.
+// "Main > Firewall > App Profiles", with the current screen in bold blue
+func Breadcrumb(path ...string) string {
+	if len(path) == 0 {
+		return ""
+	}
+
+	separator := Dimmed(" "+SymGreaterThan+" ", Gray10)
+	crumbs := make([]string, len(path))
+	for i, crumb := range path[:len(path)-1] {
+		crumbs[i] = Dimmed(crumb, Gray15)
+	}
+	crumbs[len(path)-1] = Bolded(path[len(path)-1], Blue)
+	return strings.Join(crumbs, separator)
+}
+
 func ColoredLabel(text string, color ...string) string {
 
 	labelColor := BgDarkBlue
@@ -328,21 +357,22 @@ func Hyperlink(text, url string) string {
 
 // Text utility functions
 func CenterText(text string, width int) string {
-	if len(text) >= width {
+	textWidth := CalculateVisualWidth(text)
+	if textWidth >= width {
 		return text
 	}
 
-	leftPadding := (width - len(text)) / 2
-	rightPadding := width - len(text) - leftPadding
+	leftPadding := (width - textWidth) / 2
+	rightPadding := width - textWidth - leftPadding
 
 	return strings.Repeat(" ", leftPadding) + text + strings.Repeat(" ", rightPadding)
 }
 
 // PadRight adds spaces to the right of text to reach the specified width
-// Uses StripAnsi to correctly calculate visible text length for styled text
+// Uses CalculateVisualWidth so styled and wide text pad correctly
 func PadRight(text string, width int) string {
-	// Get the visible length by removing ANSI escape sequences
-	visibleLen := len(StripAnsi(text))
+	// Get the visible width, ignoring ANSI escape sequences
+	visibleLen := CalculateVisualWidth(text)
 
 	if visibleLen >= width {
 		return text
@@ -354,13 +384,71 @@ func PadRight(text string, width int) string {
 	return text + strings.Repeat(" ", padding)
 }
 
-var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
+// ansiRegex matches CSI sequences (colors, cursor control) and OSC
+// sequences such as the terminal hyperlinks written by Hyperlink
+var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
 
 // StripAnsi removes ANSI escape codes from a string to get its true display length
 func StripAnsi(str string) string {
 	return ansiRegex.ReplaceAllString(str, "")
 }
 
+// CalculateVisualWidth returns the number of terminal columns s occupies.
+// Escape sequences take no space and wide characters take two columns;
+// all padding and alignment in this package is measured with it.
+func CalculateVisualWidth(s string) int {
+	return runewidth.StringWidth(StripAnsi(s))
+}
+
+// TruncateVisible shortens s to at most width columns, ending with tail.
+// Escape sequences are kept and styling is reset after the cut.
+func TruncateVisible(s string, width int, tail string) string {
+	if CalculateVisualWidth(s) <= width {
+		return s
+	}
+
+	limit := width - runewidth.StringWidth(tail)
+	if limit < 0 {
+		limit = 0
+		tail = runewidth.Truncate(tail, width, "")
+	}
+
+	var sb strings.Builder
+	used := 0
+	styled := false
+	last := 0
+	for _, loc := range append(ansiRegex.FindAllStringIndex(s, -1), []int{len(s), len(s)}) {
+		text := s[last:loc[0]]
+		textWidth := runewidth.StringWidth(text)
+		if used+textWidth > limit {
+			sb.WriteString(runewidth.Truncate(text, limit-used, ""))
+			break
+		}
+		sb.WriteString(text)
+		used += textWidth
+
+		if loc[1] > loc[0] {
+			sb.WriteString(s[loc[0]:loc[1]])
+			styled = true
+		}
+		last = loc[1]
+	}
+
+	sb.WriteString(tail)
+	if styled {
+		sb.WriteString(Reset)
+	}
+	return sb.String()
+}
+
+// repeatWidth repeats s n times, treating a negative count as zero
+func repeatWidth(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.Repeat(s, n)
+}
+
 // StatusLine creates a formatted status line with a symbol, label, status, and description
 func StatusLine(symbol string, symbolColor string, label string, status string, statusColor string, description string) string {
 	return Colored(symbolColor, symbol) + " " + label + ": " + Bolded(status, statusColor) + " " + Dimmed(description)
@@ -397,15 +485,15 @@ func PrintSeparator(text string, width int, sepChar string, color string) {
 	if len(text) > 0 {
 		// If there's text, center it within the separator
 		textWithSpaces := " " + text + " "
-		textLen := len(StripAnsi(textWithSpaces))
+		textLen := CalculateVisualWidth(textWithSpaces)
 		leftLen := (width - textLen) / 2
 		rightLen := width - textLen - leftLen
 
 		fmt.Println()
 		fmt.Println(
-			Bolded(strings.Repeat(sepChar, leftLen), color) +
+			Bolded(repeatWidth(sepChar, leftLen), color) +
 				Bolded(textWithSpaces, color) +
-				Bolded(strings.Repeat(sepChar, rightLen), color),
+				Bolded(repeatWidth(sepChar, rightLen), color),
 		)
 		fmt.Println()
 	} else {
@@ -452,8 +540,8 @@ func PressAnyKey() {
 func (sf *StatusFormatter) Initialize() {
 	sf.maxLabelLen = 0
 	for _, label := range sf.labels {
-		if len(label) > sf.maxLabelLen {
-			sf.maxLabelLen = len(label)
+		if width := CalculateVisualWidth(label); width > sf.maxLabelLen {
+			sf.maxLabelLen = width
 		}
 	}
 	// buffer for spacing
@@ -489,8 +577,8 @@ func (sf *StatusFormatter) FormatLine(symbol string, symbolColor string,
 			warnDescription = true
 		}
 	}
-	// Calculate padding needed for label (strip ANSI codes for accuracy)
-	labelText := StripAnsi(label)
+	// Calculate padding needed for label from its visible width
+	labelWidth := CalculateVisualWidth(label)
 
 	var symbolPadding string
 	if !padSymbol {
@@ -506,7 +594,7 @@ func (sf *StatusFormatter) FormatLine(symbol string, symbolColor string,
 		padding = " " // Just one space between label and status
 	} else {
 		// Fix: Ensure padding size is never negative
-		paddingSize := sf.maxLabelLen - len(labelText)
+		paddingSize := sf.maxLabelLen - labelWidth
 		if paddingSize < 0 {
 			paddingSize = 0 // Prevent negative repeat count
 		}
@@ -553,9 +641,8 @@ func (sf *StatusFormatter) FormatEmLine(symbol string, label string, status stri
 		sf.Initialize()
 	}
 
-	// Calculate padding needed for label (strip ANSI codes for accuracy)
-	labelText := StripAnsi(label)
-	padding := strings.Repeat(" ", sf.maxLabelLen-len(labelText))
+	// Calculate padding needed for label from its visible width
+	padding := repeatWidth(" ", sf.maxLabelLen-CalculateVisualWidth(label))
 
 	if statusWeight == "bold" {
 		status = Bolded(status, statusColor)
@@ -652,7 +739,7 @@ func NewMenu(title string, options []MenuOption) *Menu {
 		}
 
 		// Track the longest title
-		titleLen := len(StripAnsi(opt.Title))
+		titleLen := CalculateVisualWidth(opt.Title)
 		if titleLen > titleWidth {
 			titleWidth = titleLen
 		}
@@ -682,7 +769,7 @@ func (m *Menu) SetExitOption(option MenuOption) {
 	}
 
 	// Update titleWidth if necessary
-	titleLen := len(StripAnsi(option.Title))
+	titleLen := CalculateVisualWidth(option.Title)
 	if titleLen > m.titleWidth {
 		m.titleWidth = titleLen
 	}
@@ -704,6 +791,17 @@ func (m *Menu) SetIndentation(spaces int) {
 	m.indentation = strings.Repeat(" ", spaces)
 }
 
+// DisabledReason returns why the option for choice is unavailable, and
+// whether it is disabled at all
+func (m *Menu) DisabledReason(choice string) (string, bool) {
+	for _, opt := range m.options {
+		if fmt.Sprintf("%d", opt.Number) == choice && opt.Disabled() {
+			return opt.DisabledReason, true
+		}
+	}
+	return "", false
+}
+
 // GetValidRange returns the valid range of option numbers as a string
 func (m *Menu) GetValidRange() string {
 	if len(m.options) == 0 {
@@ -758,6 +856,13 @@ func (m *Menu) FormatOption(opt MenuOption) string {
 	// Add spacing after the number
 	numPadded += " "
 
+	// Disabled options are dimmed and show why they are unavailable
+	if opt.Disabled() {
+		line := Dimmed(numStr, Gray10) + " " + PadRight(Dimmed(opt.Title, Gray10), m.titleWidth) +
+			Dimmed("Unavailable: "+opt.DisabledReason, Gray10)
+		return m.indentation + line
+	}
+
 	titlePadded := ""
 	// Format title with consistent padding
 	if opt.Style == "" {
@@ -774,6 +879,9 @@ func (m *Menu) FormatOption(opt MenuOption) string {
 
 	// Add description
 	desc := Dimmed(opt.Description)
+	if opt.Note != "" {
+		desc += "  " + Dimmed(opt.Note, Gray10)
+	}
 
 	// Apply indentation if set
 	if m.indentation != "" {
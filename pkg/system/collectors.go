@@ -6,12 +6,13 @@ import (
 	"fmt"
 	"net"
 	"os"
-	"os/exec"
 	"os/user"
+	"runtime"
 	"strconv"
 	"strings"
 
 	"github.com/abbott/hardn/pkg/application"
+	"github.com/abbott/hardn/pkg/interfaces"
 	"github.com/shirou/gopsutil/v3/cpu"  // Still needed for detailed CPU info
 	"github.com/shirou/gopsutil/v3/disk" // Still needed for ZFS support
 	"github.com/shirou/gopsutil/v3/load" // Still needed for load averages
@@ -37,11 +38,16 @@ func (m *SystemDetails) collectOSInfo(hostInfoManager *application.HostInfoManag
 		m.Kernel = "Linux " + hostInfo.KernelInfo
 	} else {
 		// Fallback to direct command
-		kernelInfo, err := exec.Command("uname", "-r").Output()
+		kernelInfo, err := interfaces.Command("uname", "-r").Output()
 		if err == nil {
 			m.Kernel = "Linux " + strings.TrimSpace(string(kernelInfo))
 		}
 	}
+	if err == nil && hostInfo.Arch != "" {
+		m.Arch = hostInfo.Arch
+	} else {
+		m.Arch = runtime.GOARCH
+	}
 
 	// Get hostname from Host Info Service
 	hostname, domain, err := hostInfoManager.GetHostname()
@@ -75,7 +81,7 @@ func (m *SystemDetails) collectOSInfo(hostInfoManager *application.HostInfoManag
 		m.UptimeLongFormat = hostInfoManager.FormatUptime(m.Uptime) // Use the manager's formatter
 	} else {
 		// Fallback to old implementation if necessary
-		hostInfoCmd := exec.Command("uptime")
+		hostInfoCmd := interfaces.Command("uptime")
 		hostInfoOutput, err := hostInfoCmd.Output()
 		if err == nil {
 			// Parse uptime output - this is just a fallback, so simple parsing
@@ -207,7 +213,7 @@ func (m *SystemDetails) collectCPUInfo(hostInfoManager *application.HostInfoMana
 		cpuInfo, err := cpu.Info()
 		if err != nil {
 			// Further fallback to direct command
-			cmd := exec.Command("cat", "/proc/cpuinfo")
+			cmd := interfaces.Command("cat", "/proc/cpuinfo")
 			output, err := cmd.Output()
 			if err != nil {
 				// Just set a placeholder if all methods fail
@@ -233,7 +239,7 @@ func (m *SystemDetails) collectCPUInfo(hostInfoManager *application.HostInfoMana
 	}
 
 	// Get CPU cores count
-	cmd := exec.Command("nproc")
+	cmd := interfaces.Command("nproc")
 	output, err := cmd.Output()
 	if err == nil {
 		cores, err := strconv.Atoi(strings.TrimSpace(string(output)))
@@ -254,7 +260,7 @@ func (m *SystemDetails) collectCPUInfo(hostInfoManager *application.HostInfoMana
 	}
 
 	// Check for hypervisor using lscpu
-	hypervisorCmd := exec.Command("lscpu")
+	hypervisorCmd := interfaces.Command("lscpu")
 	hypervisorOutput, err := hypervisorCmd.Output()
 	if err == nil {
 		scanner := bufio.NewScanner(strings.NewReader(string(hypervisorOutput)))
@@ -334,7 +340,7 @@ func (m *SystemDetails) collectMemoryInfo(hostInfoManager *application.HostInfoM
 		}
 	} else {
 		// Fallback to direct command if Host Info service fails
-		cmd := exec.Command("free", "-b")
+		cmd := interfaces.Command("free", "-b")
 		output, err := cmd.Output()
 		if err != nil {
 			return fmt.Errorf("failed to get memory info: %w", err)
@@ -380,15 +386,15 @@ func (m *SystemDetails) collectDiskInfo(hostInfoManager *application.HostInfoMan
 	hostInfo, err := hostInfoManager.GetHostInfo()
 
 	// First check if ZFS is present regardless of Host Info
-	if _, err := exec.LookPath("zfs"); err == nil {
+	if _, err := interfaces.LookPath("zfs"); err == nil {
 		// Try to get ZFS information
-		if out, err := exec.Command("zpool", "status", "-x").Output(); err == nil {
+		if out, err := interfaces.Command("zpool", "status", "-x").Output(); err == nil {
 			if strings.Contains(string(out), "is healthy") {
 				m.ZFSPresent = true
 				m.ZFSHealth = "HEALTH O.K."
 
 				// Get ZFS filesystem usage
-				cmd := exec.Command("zfs", "get", "-Hp", "available", m.ZFSFilesystem)
+				cmd := interfaces.Command("zfs", "get", "-Hp", "available", m.ZFSFilesystem)
 				out, err := cmd.Output()
 				if err == nil {
 					fields := strings.Fields(string(out))
@@ -399,7 +405,7 @@ func (m *SystemDetails) collectDiskInfo(hostInfoManager *application.HostInfoMan
 					}
 				}
 
-				cmd = exec.Command("zfs", "get", "-Hp", "used", m.ZFSFilesystem)
+				cmd = interfaces.Command("zfs", "get", "-Hp", "used", m.ZFSFilesystem)
 				out, err = cmd.Output()
 				if err == nil {
 					fields := strings.Fields(string(out))
@@ -445,7 +451,7 @@ func (m *SystemDetails) collectDiskInfo(hostInfoManager *application.HostInfoMan
 		usage, err := disk.Usage(m.RootPartition)
 		if err != nil {
 			// Last resort: use df command
-			cmd := exec.Command("df", "-k", m.RootPartition)
+			cmd := interfaces.Command("df", "-k", m.RootPartition)
 			output, cmdErr := cmd.Output()
 			if cmdErr != nil {
 				return fmt.Errorf("failed to get disk info: %w", err)
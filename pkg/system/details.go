@@ -19,6 +19,7 @@ type SystemDetails struct {
 	OSName      string
 	OSVersion   string
 	Kernel      string
+	Arch        string
 	Hostname    string
 	Domain      string
 	CurrentUser string
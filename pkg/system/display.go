@@ -43,6 +43,7 @@ func DisplayMachineStatus(info *SystemDetails) {
 		// operating system
 		printLine(fmt.Sprintf("OS: %s %s", info.OSName, info.OSVersion))
 		printLine(fmt.Sprintf("Kernel: %s", info.Kernel))
+		printLine(fmt.Sprintf("Arch: %s", info.Arch))
 		printLine("")
 
 		// network
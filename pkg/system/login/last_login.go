@@ -5,6 +5,8 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/abbott/hardn/pkg/interfaces"
 )
 
 // LastLoginProvider defines the interface for retrieving last login information
@@ -32,9 +34,9 @@ func (p *OSLastLoginProvider) GetLastLogin(username string) (time.Time, error) {
 	var cmd *exec.Cmd
 
 	if p.UseLastlog {
-		cmd = exec.Command("lastlog", "-u", username)
+		cmd = interfaces.Command("lastlog", "-u", username)
 	} else {
-		cmd = exec.Command("last", "-1", username)
+		cmd = interfaces.Command("last", "-1", username)
 	}
 
 	output, err := cmd.Output()
@@ -5,12 +5,12 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"os/exec"
 	"strconv"
 	"strings"
 
 	"github.com/abbott/hardn/pkg/application"
 	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
 )
 
 // collectUserInfo gathers non-system user information
@@ -78,7 +78,7 @@ func getNonSystemUsers() ([]model.User, error) {
 func checkSudoAccess(username string) bool {
 	// Check if user is in sudo/wheel/admin group
 	for _, group := range []string{"sudo", "wheel", "admin"} {
-		cmd := exec.Command("groups", username)
+		cmd := interfaces.Command("groups", username)
 		output, err := cmd.Output()
 		if err == nil && strings.Contains(string(output), group) {
 			return true
@@ -86,7 +86,7 @@ func checkSudoAccess(username string) bool {
 	}
 
 	// Check sudoers file
-	cmd := exec.Command("sudo", "-l", "-U", username)
+	cmd := interfaces.Command("sudo", "-l", "-U", username)
 	output, err := cmd.Output()
 	if err == nil && !strings.Contains(string(output), "not allowed to run sudo") {
 		return true
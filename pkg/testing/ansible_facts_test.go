@@ -0,0 +1,65 @@
+// pkg/testing/ansible_facts_test.go
+package testing
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnsibleFacts_Flat(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SshPort = 2208
+	status := &security.SecurityStatus{
+		FirewallEnabled: true,
+		SecureUsers:     true,
+		Databases:       []model.DatabaseServer{{Name: "postgresql", Port: 5432, BaselineApplied: true}},
+	}
+	osInfo := &osdetect.OSInfo{OsType: "debian", OsVersion: "12", OsCodename: "bookworm"}
+
+	facts, err := security.AnsibleFacts(security.BuildFacts(cfg, osInfo, status), status, "1.4.0")
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(2208), facts["ssh_port"])
+	assert.Equal(t, []any{"0.0.0.0"}, facts["ssh_listen_addresses"])
+	assert.Equal(t, true, facts["firewall_enabled"])
+	assert.Equal(t, true, facts["users_non_root_sudo"])
+	assert.Equal(t, "debian", facts["os_type"])
+	assert.Equal(t, "postgresql", facts["databases_0_name"])
+	assert.Equal(t, true, facts["databases_0_baseline"])
+	assert.Equal(t, []any{}, facts["web_servers"])
+	assert.Equal(t, "1.4.0", facts["hardn_version"])
+	assert.Contains(t, facts, "risk_level")
+
+	for key, value := range facts {
+		_, nested := value.(map[string]any)
+		assert.False(t, nested, "%s is nested", key)
+	}
+}
+
+func TestFileAnsibleFactsRepository(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	repo := secondary.NewFileAnsibleFactsRepository(mockFS)
+
+	assert.False(t, repo.FactsScriptInstalled())
+	require.NoError(t, repo.InstallFactsScript("/usr/local/bin/hardn"))
+	assert.Equal(t, "#!/bin/sh\n# Managed by hardn: Ansible local facts\nexec /usr/local/bin/hardn facts 2>/dev/null </dev/null\n",
+		string(mockFS.Files[model.AnsibleFactsPath]))
+	assert.True(t, repo.FactsScriptInstalled())
+
+	require.NoError(t, repo.RemoveFactsScript())
+	assert.NotContains(t, mockFS.Files, model.AnsibleFactsPath)
+
+	// A facts file someone else wrote is left alone
+	mockFS.Files[model.AnsibleFactsPath] = []byte(`{"role": "web"}`)
+	assert.ErrorContains(t, repo.InstallFactsScript("/usr/local/bin/hardn"), "not written by hardn")
+	assert.ErrorContains(t, repo.RemoveFactsScript(), "not written by hardn")
+	assert.Equal(t, `{"role": "web"}`, string(mockFS.Files[model.AnsibleFactsPath]))
+}
@@ -0,0 +1,88 @@
+// pkg/testing/arch_test.go
+package testing
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeArch(t *testing.T) {
+	tests := map[string]string{
+		"x86_64":  "amd64",
+		"aarch64": "arm64",
+		"armv7l":  "arm",
+		"armv8l":  "arm",
+		"armv6l":  "arm",
+		"riscv64": "riscv64",
+		"i686":    "386",
+		"s390x":   "s390x",
+	}
+	for machine, expected := range tests {
+		assert.Equal(t, expected, osdetect.NormalizeArch(machine), machine)
+	}
+}
+
+func TestCheckArch(t *testing.T) {
+	for _, machine := range []string{"x86_64", "aarch64", "armv7l", "armv8l", "riscv64"} {
+		assert.NoError(t, osdetect.CheckArch(machine), machine)
+	}
+
+	assert.ErrorContains(t, osdetect.CheckArch("armv6l"), "ARMv7 or newer")
+	assert.ErrorContains(t, osdetect.CheckArch("i686"), "no hardn release is built for i686")
+	assert.Equal(t, 7, osdetect.ARMVersion("armv7l"))
+	assert.Equal(t, 0, osdetect.ARMVersion("aarch64"))
+}
+
+func TestReleaseAssetsFor_Boards(t *testing.T) {
+	release := version.GitHubRelease{TagName: "v0.4.0"}
+	for _, name := range []string{"hardn-linux-armv7", "hardn-linux-riscv64", "checksums.txt"} {
+		release.Assets = append(release.Assets, version.ReleaseAsset{Name: name, BrowserDownloadURL: releaseDownload + name})
+	}
+
+	assets := version.ReleaseAssetsFor(release, "linux", "riscv64")
+	assert.Equal(t, releaseDownload+"hardn-linux-riscv64", assets.BinaryURL)
+
+	// A build named after the ARM revision is used when there is no hardn-linux-arm
+	assets = version.ReleaseAssetsFor(release, "linux", "arm")
+	assert.Equal(t, "hardn-linux-armv7", assets.BinaryName)
+	assert.Equal(t, releaseDownload+"hardn-linux-armv7", assets.BinaryURL)
+	assert.Equal(t, releaseDownload+"checksums.txt", assets.ChecksumURL)
+
+	assets = version.ReleaseAssetsFor(release, "linux", "arm64")
+	assert.Equal(t, "hardn-linux-arm64", assets.BinaryName)
+	assert.Empty(t, assets.BinaryURL)
+}
+
+func TestUpdatePackageSources_UbuntuPorts(t *testing.T) {
+	sources := model.PackageSources{DebianRepos: []string{
+		"deb http://us.archive.ubuntu.com/ubuntu CODENAME main universe",
+		"deb http://security.ubuntu.com/ubuntu CODENAME-security main",
+		"deb http://ports.ubuntu.com/ubuntu-ports CODENAME-updates main",
+		"deb https://apt.example.com/repo stable main",
+	}}
+
+	for arch, expected := range map[string]string{
+		"riscv64": "deb http://ports.ubuntu.com/ubuntu-ports noble main universe\n" +
+			"deb http://ports.ubuntu.com/ubuntu-ports noble-security main\n" +
+			"deb http://ports.ubuntu.com/ubuntu-ports noble-updates main\n" +
+			"deb https://apt.example.com/repo stable main\n",
+		"amd64": "deb http://us.archive.ubuntu.com/ubuntu noble main universe\n" +
+			"deb http://security.ubuntu.com/ubuntu noble-security main\n" +
+			"deb http://ports.ubuntu.com/ubuntu-ports noble-updates main\n" +
+			"deb https://apt.example.com/repo stable main\n",
+	} {
+		mockFS := interfaces.NewMockFileSystem()
+		repo := secondary.NewOSPackageRepository(mockFS, interfaces.NewMockCommander(),
+			"ubuntu", "24.04", "noble", arch, false, &sources)
+
+		require.NoError(t, repo.UpdatePackageSources(sources))
+		assert.Equal(t, expected, string(mockFS.Files["/etc/apt/sources.list"]), arch)
+	}
+}
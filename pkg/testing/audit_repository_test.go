@@ -0,0 +1,41 @@
+// pkg/testing/audit_repository_test.go
+package testing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileAuditRepository_RoundTrip(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	repo := secondary.NewFileAuditRepository(mockFS)
+
+	report := &model.AuditReport{
+		Format:       model.AuditReportFormat,
+		HardnVersion: "0.4.0",
+		Hostname:     "web-01",
+		GeneratedAt:  time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC),
+		Controls:     []model.AuditControl{{ID: "firewall.enabled", Title: "Firewall enabled", Status: model.ControlPass}},
+		Findings:     []model.PolicyViolation{{Rule: "ssh", Message: "default port"}},
+	}
+
+	assert.NoError(t, repo.SaveReport("/tmp/before.json", report))
+
+	loaded, err := repo.LoadReport("/tmp/before.json")
+	assert.NoError(t, err)
+	assert.Equal(t, report, loaded)
+}
+
+func TestFileAuditRepository_RejectsNewerFormat(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/tmp/future.json"] = []byte(`{"format": 99, "controls": []}`)
+
+	_, err := secondary.NewFileAuditRepository(mockFS).LoadReport("/tmp/future.json")
+
+	assert.Error(t, err)
+}
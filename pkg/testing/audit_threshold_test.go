@@ -0,0 +1,88 @@
+// pkg/testing/audit_threshold_test.go
+package testing
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFailOn(t *testing.T) {
+	tests := []struct {
+		level     string
+		threshold int
+		invalid   bool
+	}{
+		{level: "none", threshold: -1},
+		{level: "low", threshold: 1},
+		{level: "moderate", threshold: 2},
+		{level: "HIGH", threshold: 3},
+		{level: "critical", threshold: 4},
+		// Every host is at least minimal, and the threshold is named moderate
+		{level: "minimal", invalid: true},
+		{level: "medium", invalid: true},
+		{level: "severe", invalid: true},
+		{level: "", invalid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			threshold, err := security.ParseFailOn(tt.level)
+			if tt.invalid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.threshold, threshold)
+		})
+	}
+}
+
+func TestAuditFailed(t *testing.T) {
+	advisories := func(severity string) *model.PackageAuditReport {
+		return &model.PackageAuditReport{Vulnerabilities: []model.PackageVulnerability{
+			{ID: "CVE-2026-0001", Package: "openssl", Severity: severity},
+		}}
+	}
+	finding := func(severity string) []model.PolicyViolation {
+		return []model.PolicyViolation{{Rule: "share.world_writable", Severity: severity}}
+	}
+
+	tests := []struct {
+		name      string
+		failOn    string
+		riskLevel string
+		findings  []model.PolicyViolation
+		packages  *model.PackageAuditReport
+		failed    bool
+		exitCode  int
+	}{
+		{name: "risk level below threshold", failOn: "high", riskLevel: "Moderate"},
+		{name: "risk level at threshold", failOn: "high", riskLevel: "High", failed: true, exitCode: 1},
+		{name: "risk level above threshold", failOn: "moderate", riskLevel: "Critical", failed: true, exitCode: 1},
+		{name: "minimal never fails", failOn: "low", riskLevel: "Minimal"},
+		{name: "none only reports", failOn: "none", riskLevel: "Critical", findings: finding(model.SeverityHigh), packages: advisories(model.SeverityCritical)},
+		{name: "low finding below threshold", failOn: "moderate", riskLevel: "Low", findings: finding(model.SeverityLow)},
+		{name: "medium finding counts as moderate", failOn: "moderate", riskLevel: "Low", findings: finding(model.SeverityMedium), failed: true, exitCode: 1},
+		{name: "high finding", failOn: "high", riskLevel: "Low", findings: finding(model.SeverityHigh), failed: true, exitCode: 1},
+		{name: "critical advisory", failOn: "high", riskLevel: "Low", packages: advisories(model.SeverityCritical), failed: true, exitCode: 1},
+		{name: "medium advisory below threshold", failOn: "high", riskLevel: "Low", packages: advisories(model.SeverityMedium)},
+		{name: "medium advisory counts as moderate", failOn: "moderate", riskLevel: "Low", packages: advisories(model.SeverityMedium), failed: true, exitCode: 1},
+		{name: "unknown advisory never fails", failOn: "low", riskLevel: "Minimal", packages: advisories(model.SeverityUnknown)},
+		{name: "no package check", failOn: "low", riskLevel: "Minimal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threshold, err := security.ParseFailOn(tt.failOn)
+			require.NoError(t, err)
+
+			failed := security.AuditFailed(threshold, tt.riskLevel, tt.findings, tt.packages)
+			assert.Equal(t, tt.failed, failed)
+			assert.Equal(t, tt.exitCode, security.AuditExitCode(failed))
+		})
+	}
+}
@@ -0,0 +1,347 @@
+// pkg/testing/backup_repository_test.go
+package testing
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// backupFiles lists the backups written to the mock filesystem
+func backupFiles(fs *interfaces.MockFileSystem) []string {
+	var backups []string
+	for path := range fs.Files {
+		if strings.HasPrefix(path, "/var/backups/hardn/") && strings.Contains(path, ".bak") {
+			backups = append(backups, path)
+		}
+	}
+	return backups
+}
+
+func TestFileBackupRepository_Gzip(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/etc/hosts"] = []byte("127.0.0.1 localhost\n")
+	repo := secondary.NewFileBackupRepository(mockFS, interfaces.NewMockCommander(), model.BackupConfig{
+		Enabled: true, BackupDir: "/var/backups/hardn", Compression: model.BackupGzip, CompressionLevel: 9,
+	})
+
+	require.NoError(t, repo.BackupFile("/etc/hosts"))
+	backups := backupFiles(mockFS)
+	require.Len(t, backups, 1)
+	assert.True(t, strings.HasSuffix(backups[0], ".bak.gz"))
+	assert.NotEqual(t, "127.0.0.1 localhost\n", string(mockFS.Files[backups[0]]))
+
+	mockFS.Files["/etc/hosts"] = []byte("changed\n")
+	require.NoError(t, repo.RestoreBackup(backups[0], "/etc/hosts"))
+	assert.Equal(t, "127.0.0.1 localhost\n", string(mockFS.Files["/etc/hosts"]))
+}
+
+func TestFileBackupRepository_Zstd(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/etc/hosts"] = []byte("127.0.0.1 localhost\n")
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandOutputs["INPUT:127.0.0.1 localhost\n|zstd -q -c -19"] = []byte("zstd data")
+	mockCommander.CommandOutputs["INPUT:zstd data|zstd -q -d -c"] = []byte("127.0.0.1 localhost\n")
+	repo := secondary.NewFileBackupRepository(mockFS, mockCommander, model.BackupConfig{
+		Enabled: true, BackupDir: "/var/backups/hardn", Compression: model.BackupZstd, CompressionLevel: 19,
+	})
+
+	require.NoError(t, repo.BackupFile("/etc/hosts"))
+	backups := backupFiles(mockFS)
+	require.Len(t, backups, 1)
+	assert.True(t, strings.HasSuffix(backups[0], ".bak.zst"))
+	assert.Equal(t, "zstd data", string(mockFS.Files[backups[0]]))
+
+	delete(mockFS.Files, "/etc/hosts")
+	require.NoError(t, repo.RestoreBackup(backups[0], "/etc/hosts"))
+	assert.Equal(t, "127.0.0.1 localhost\n", string(mockFS.Files["/etc/hosts"]))
+}
+
+func TestFileBackupRepository_Age(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/etc/hosts"] = []byte("127.0.0.1 localhost\n")
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewFileBackupRepository(mockFS, mockCommander, model.BackupConfig{
+		Enabled: true, BackupDir: "/var/backups/hardn", Encryption: model.BackupAge,
+		Recipient: "age1example", Identity: "/root/.config/hardn/backup-key.txt",
+	})
+
+	require.NoError(t, repo.BackupFile("/etc/hosts"))
+	var backup string
+	for _, command := range mockCommander.ExecutedCommands {
+		if rest, ok := strings.CutPrefix(command, "INPUT:127.0.0.1 localhost\n|age -r age1example -o "); ok {
+			backup = rest
+		}
+	}
+	require.NotEmpty(t, backup, "age was not run: %v", mockCommander.ExecutedCommands)
+	assert.True(t, strings.HasSuffix(backup, ".bak.age"))
+	assert.Empty(t, backupFiles(mockFS), "the backup must only be written by age")
+
+	// The mock age prints the plaintext it is told to
+	mockFS.Files[backup] = []byte("age data")
+	decrypt := "age -d -i /root/.config/hardn/backup-key.txt " + backup
+	mockCommander.CommandOutputs[decrypt] = []byte("127.0.0.1 localhost\n")
+	mockFS.Files["/etc/hosts"] = []byte("changed\n")
+	require.NoError(t, repo.RestoreBackup(backup, "/etc/hosts"))
+	assert.Contains(t, mockCommander.ExecutedCommands, decrypt)
+	assert.Equal(t, "127.0.0.1 localhost\n", string(mockFS.Files["/etc/hosts"]))
+
+	// A preview decrypts to memory as well
+	comparison, err := repo.CompareBackup(backup, "/etc/hosts")
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1 localhost\n", string(comparison.Backup))
+
+	// Plaintext printed before age failed stays out of the error
+	failing := secondary.NewFileBackupRepository(mockFS, truncatedDecrypt{mockCommander}, model.BackupConfig{
+		Enabled: true, BackupDir: "/var/backups/hardn", Encryption: model.BackupAge,
+		Recipient: "age1example", Identity: "/root/.config/hardn/backup-key.txt",
+	})
+	_, err = failing.CompareBackup(backup, "/etc/hosts")
+	assert.ErrorContains(t, err, "age: error: stream truncated")
+	assert.NotContains(t, err.Error(), "localhost")
+}
+
+// truncatedDecrypt fails every decryption after printing part of the plaintext
+type truncatedDecrypt struct {
+	*interfaces.MockCommander
+}
+
+func (c truncatedDecrypt) Execute(command string, args ...string) ([]byte, error) {
+	if command == model.BackupAge && len(args) > 0 && args[0] == "-d" {
+		return []byte("127.0.0.1 localhost\nage: error: stream truncated\n"), errors.New("exit status 1")
+	}
+	return c.MockCommander.Execute(command, args...)
+}
+
+// TestFileBackupRepository_DryRunDecrypt previews a restore of an encrypted
+// backup, which has to decrypt it while dry-run mode holds back changes
+func TestFileBackupRepository_DryRunDecrypt(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/etc/hosts"] = []byte("changed\n")
+	backup := "/var/backups/hardn/2024-05-01/hosts.101500.bak.age"
+	mockFS.Files[backup] = []byte("age data")
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandOutputs["age -d -i /root/.config/hardn/backup-key.txt "+backup] = []byte("127.0.0.1 localhost\n")
+
+	var out bytes.Buffer
+	provider := interfaces.DryRunProvider(&interfaces.Provider{FS: mockFS, Commander: mockCommander},
+		func() bool { return true }, &out)
+	repo := secondary.NewFileBackupRepository(provider.FS, provider.Commander, model.BackupConfig{
+		Enabled: true, BackupDir: "/var/backups/hardn", Encryption: model.BackupAge,
+		Recipient: "age1example", Identity: "/root/.config/hardn/backup-key.txt",
+	})
+
+	comparison, err := repo.CompareBackup(backup, "/etc/hosts")
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1 localhost\n", string(comparison.Backup))
+	assert.NotContains(t, out.String(), "Would run: age")
+
+	require.NoError(t, repo.RestoreBackup(backup, "/etc/hosts"))
+	assert.Equal(t, "changed\n", string(mockFS.Files["/etc/hosts"]), "a dry run must not restore the file")
+	assert.Contains(t, out.String(), "+127.0.0.1 localhost")
+}
+
+func TestFileBackupRepository_CheckBackupKeys(t *testing.T) {
+	config := model.BackupConfig{
+		Enabled: true, BackupDir: "/var/backups/hardn", Encryption: model.BackupGPG, Recipient: "ops@example.com",
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		mockCommander := interfaces.NewMockCommander()
+		mockCommander.CommandOutputs["gpg --batch --quiet --log-file /dev/null --decrypt /var/backups/hardn/.key_test.gpg"] =
+			[]byte("hardn backup key test\n")
+		repo := secondary.NewFileBackupRepository(interfaces.NewMockFileSystem(), mockCommander, config)
+
+		status, err := repo.CheckBackupKeys()
+		require.NoError(t, err)
+		assert.True(t, status.Ready(), status.Problem)
+		assert.Contains(t, mockCommander.ExecutedCommands,
+			"INPUT:hardn backup key test\n|gpg --batch --yes --trust-model always --recipient ops@example.com --output /var/backups/hardn/.key_test.gpg --encrypt")
+		assert.Contains(t, mockCommander.ExecutedCommands,
+			"gpg --batch --quiet --log-file /dev/null --decrypt /var/backups/hardn/.key_test.gpg")
+	})
+
+	t.Run("no secret key", func(t *testing.T) {
+		mockCommander := interfaces.NewMockCommander()
+		mockCommander.CommandErrors["gpg --batch --quiet --log-file /dev/null --decrypt /var/backups/hardn/.key_test.gpg"] =
+			errors.New("exit status 2")
+		repo := secondary.NewFileBackupRepository(interfaces.NewMockFileSystem(), mockCommander, config)
+
+		status, err := repo.CheckBackupKeys()
+		require.NoError(t, err)
+		assert.True(t, status.CanEncrypt)
+		assert.False(t, status.CanDecrypt)
+		assert.Contains(t, status.Problem, "could not decrypt")
+	})
+
+	t.Run("not installed", func(t *testing.T) {
+		mockCommander := interfaces.NewMockCommander()
+		mockCommander.CommandErrors["which gpg"] = errors.New("exit status 1")
+		repo := secondary.NewFileBackupRepository(interfaces.NewMockFileSystem(), mockCommander, config)
+
+		status, err := repo.CheckBackupKeys()
+		require.NoError(t, err)
+		assert.False(t, status.Installed)
+		assert.Equal(t, "gpg is not installed", status.Problem)
+	})
+}
+
+func TestBackupLayers(t *testing.T) {
+	tests := []struct {
+		path        string
+		compression string
+		encryption  string
+	}{
+		{"/var/backups/hardn/2026-01-02/hosts.101500.bak", "", ""},
+		{"/var/backups/hardn/2026-01-02/hosts.101500.bak.gz", model.BackupGzip, ""},
+		{"/var/backups/hardn/2026-01-02/hosts.101500.bak.zst.age", model.BackupZstd, model.BackupAge},
+		{"/var/backups/hardn/2026-01-02/hosts.101500.bak.gpg", "", model.BackupGPG},
+	}
+
+	for _, tc := range tests {
+		compression, encryption := secondary.BackupLayers(tc.path)
+		assert.Equal(t, tc.compression, compression, tc.path)
+		assert.Equal(t, tc.encryption, encryption, tc.path)
+	}
+}
+
+func TestFileBackupRepository_SyncRemoteS3(t *testing.T) {
+	today := time.Now().Format("2006-01-02")
+	old := time.Now().AddDate(0, 0, -40).Format("2006-01-02")
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Directories["/var/backups/hardn/"+today] = true
+	mockFS.Directories["/var/backups/hardn/"+old] = true
+	mockCommander := interfaces.NewMockCommander()
+	options := " --endpoint-url https://minio.example.com --profile backup"
+	mockCommander.CommandOutputs["aws s3 ls s3://backups/web1/"+options] = []byte(
+		"                           PRE " + old + "/\n                           PRE " + today + "/\n2024-05-01 10:00:00  12 notes.txt\n")
+	repo := secondary.NewFileBackupRepository(mockFS, mockCommander, model.BackupConfig{
+		Enabled: true, BackupDir: "/var/backups/hardn",
+		Remote: model.BackupRemote{
+			URL: "s3://backups/web1/", Endpoint: "https://minio.example.com", Profile: "backup", RetentionDays: 30,
+		},
+	})
+
+	result, err := repo.SyncRemote()
+	require.NoError(t, err)
+	assert.Equal(t, []string{today}, result.Uploaded)
+	assert.Equal(t, []string{old}, result.Removed)
+	assert.Contains(t, mockCommander.ExecutedCommands,
+		"aws s3 sync /var/backups/hardn/"+today+" s3://backups/web1/"+today+"/ --only-show-errors"+options)
+	assert.Contains(t, mockCommander.ExecutedCommands,
+		"aws s3 rm s3://backups/web1/"+old+"/ --recursive --only-show-errors"+options)
+}
+
+func TestFileBackupRepository_SyncRemoteSFTP(t *testing.T) {
+	today := time.Now().Format("2006-01-02")
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Directories["/var/backups/hardn/"+today] = true
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewFileBackupRepository(mockFS, mockCommander, model.BackupConfig{
+		Enabled: true, BackupDir: "/var/backups/hardn",
+		Remote: model.BackupRemote{
+			URL: "sftp://backup@host.example.com:2222/srv/backups", IdentityFile: "/root/.ssh/backup",
+		},
+	})
+
+	result, err := repo.SyncRemote()
+	require.NoError(t, err)
+	assert.Equal(t, []string{today}, result.Uploaded)
+	assert.Empty(t, result.Removed)
+	assert.Equal(t, []string{
+		"INPUT:-mkdir \"/srv/backups\"\nput -r \"/var/backups/hardn/" + today + "\" \"/srv/backups\"\n" +
+			"|sftp -b - -o BatchMode=yes -P 2222 -i /root/.ssh/backup backup@host.example.com",
+	}, mockCommander.ExecutedCommands)
+
+	mockCommander.CommandErrors[mockCommander.ExecutedCommands[0]] = errors.New("exit status 255")
+	_, err = repo.SyncRemote()
+	assert.Error(t, err)
+}
+
+func TestFileBackupRepository_CheckRemote(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewFileBackupRepository(mockFS, mockCommander, model.BackupConfig{
+		BackupDir: "/var/backups/hardn", Remote: model.BackupRemote{URL: "s3://backups"},
+	})
+
+	require.NoError(t, repo.CheckRemote())
+	assert.Equal(t, []string{
+		"which aws",
+		"aws s3 cp /var/backups/hardn/.hardn-remote-test s3://backups/.hardn-remote-test --only-show-errors",
+		"aws s3 rm s3://backups/.hardn-remote-test --only-show-errors",
+	}, mockCommander.ExecutedCommands)
+	assert.NotContains(t, mockFS.Files, "/var/backups/hardn/.hardn-remote-test")
+
+	mockCommander.CommandErrors["which aws"] = errors.New("not found")
+	assert.ErrorContains(t, repo.CheckRemote(), "AWS CLI")
+}
+
+func TestParseSFTPDates(t *testing.T) {
+	output := "sftp> -ls -1 \"/srv/backups\"\n/srv/backups/2024-05-01\n/srv/backups/2024-05-02\n/srv/backups/notes\n"
+	assert.Equal(t, []string{"2024-05-01", "2024-05-02"}, secondary.ParseSFTPDates(output))
+}
+
+func TestFileBackupRepository_ListAllBackups(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/etc/ssh/sshd_config"] = []byte("PermitRootLogin no\n")
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewFileBackupRepository(mockFS, mockCommander, model.BackupConfig{
+		Enabled: true, BackupDir: "/var/backups/hardn",
+	})
+	require.NoError(t, repo.BackupFile("/etc/ssh/sshd_config"))
+	backups := backupFiles(mockFS)
+	require.Len(t, backups, 1)
+
+	mockFS.Directories["/var/backups/hardn"] = true
+	mockFS.Files["/var/backups/hardn/2024-05-01/hosts.120000.bak"] = []byte("127.0.0.1 localhost\n")
+	mockCommander.CommandOutputs["find /var/backups/hardn -type f -name *.bak*"] = []byte(
+		backups[0] + "\n/var/backups/hardn/2024-05-01/hosts.120000.bak\n")
+
+	all, err := repo.ListAllBackups()
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "/etc/ssh/sshd_config", all[0].OriginalPath)
+	assert.Empty(t, all[1].OriginalPath)
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local), all[1].Created)
+}
+
+func TestFileBackupRepository_CompareAndRestore(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/var/backups/hardn/2024-05-01/sshd_config.090000.bak"] = []byte("PermitRootLogin no\n")
+	mockFS.Files["/etc/ssh/sshd_config"] = []byte("PermitRootLogin yes\n")
+	mockFS.FileInfos["/etc/ssh/sshd_config"] = modeFileInfo{name: "sshd_config", mode: 0600}
+	repo := secondary.NewFileBackupRepository(mockFS, interfaces.NewMockCommander(), model.BackupConfig{
+		Enabled: true, BackupDir: "/var/backups/hardn",
+	})
+
+	comparison, err := repo.CompareBackup("/var/backups/hardn/2024-05-01/sshd_config.090000.bak", "/etc/ssh/sshd_config")
+	require.NoError(t, err)
+	assert.True(t, comparison.CurrentExists)
+	assert.False(t, comparison.Identical())
+	assert.Equal(t, "PermitRootLogin yes\n", string(comparison.Current))
+
+	comparison, err = repo.CompareBackup("/var/backups/hardn/2024-05-01/sshd_config.090000.bak", "/etc/ssh/missing")
+	require.NoError(t, err)
+	assert.False(t, comparison.CurrentExists)
+
+	require.NoError(t, repo.RestoreBackup("/var/backups/hardn/2024-05-01/sshd_config.090000.bak", "/etc/ssh/sshd_config"))
+	assert.Equal(t, "PermitRootLogin no\n", string(mockFS.Files["/etc/ssh/sshd_config"]))
+}
+
+func TestBackupCreated(t *testing.T) {
+	created, ok := secondary.BackupCreated("/var/backups/hardn/2024-05-01/sshd_config.101500.bak.zst.age")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 15, 0, 0, time.Local), created)
+
+	_, ok = secondary.BackupCreated("/var/backups/hardn/2024-05-01/sources.list.bak")
+	assert.False(t, ok)
+}
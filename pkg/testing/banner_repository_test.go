@@ -0,0 +1,102 @@
+// pkg/testing/banner_repository_test.go
+package testing
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOSBannerRepository_Ubuntu(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSBannerRepository(mockFS, mockCommander, "ubuntu")
+
+	mockFS.Directories[model.UpdateMotdDir] = true
+	mockFS.FileInfos[model.UpdateMotdDir+"/10-help-text"] = modeFileInfo{name: "10-help-text", mode: 0755}
+	mockFS.FileInfos[model.UpdateMotdDir+"/50-motd-news"] = modeFileInfo{name: "50-motd-news", mode: 0755}
+	mockFS.FileInfos[model.UpdateMotdDir+"/88-esm-announce"] = modeFileInfo{name: "88-esm-announce", mode: 0644}
+	mockFS.Files[model.UbuntuMotdNewsPath] = []byte("# Enable/disable the dynamic MOTD news service\nENABLED=1\nURLS=\"https://motd.ubuntu.com\"\n")
+
+	status, err := repo.GetBannerStatus()
+	assert.NoError(t, err)
+	assert.True(t, status.UpdateMotd)
+	assert.False(t, status.DynamicMotd)
+	assert.Equal(t, []string{"10-help-text", "50-motd-news"}, status.MotdAds)
+
+	// Scripts already turned off are left alone
+	disabled, err := repo.DisableMotdAds()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10-help-text", "50-motd-news"}, disabled)
+	assert.Contains(t, mockCommander.ExecutedCommands, "chmod -x /etc/update-motd.d/10-help-text")
+	assert.Contains(t, mockCommander.ExecutedCommands, "chmod -x /etc/update-motd.d/50-motd-news")
+	assert.NotContains(t, mockCommander.ExecutedCommands, "chmod -x /etc/update-motd.d/88-esm-announce")
+	assert.Equal(t, "# Enable/disable the dynamic MOTD news service\nENABLED=0\nURLS=\"https://motd.ubuntu.com\"\n",
+		string(mockFS.Files[model.UbuntuMotdNewsPath]))
+
+	assert.NoError(t, repo.InstallMotdScript())
+	script := string(mockFS.Files[model.UpdateMotdScriptPath])
+	assert.Contains(t, script, "#!/bin/sh\n")
+	assert.Contains(t, script, "\"$HARDN\" banner motd")
+
+	assert.NoError(t, repo.WriteBanner(model.IssueNetPath, "Authorized access only.\n"))
+	status, err = repo.GetBannerStatus()
+	assert.NoError(t, err)
+	assert.True(t, status.DynamicMotd)
+	assert.True(t, status.IssueNet)
+	assert.False(t, status.Issue)
+}
+
+func TestOSBannerRepository_PendingUpdates(t *testing.T) {
+	tests := []struct {
+		name     string
+		osType   string
+		command  string
+		output   string
+		expected int
+	}{
+		{
+			name:    "apt",
+			osType:  "debian",
+			command: "apt-get -s -o Debug::NoLocking=true upgrade",
+			output: "Reading package lists...\n" +
+				"Inst openssl [3.0.11-1~deb12u1] (3.0.13-1~deb12u1 Debian-Security:12/stable-security [amd64])\n" +
+				"Inst libssl3 [3.0.11-1~deb12u1] (3.0.13-1~deb12u1 Debian-Security:12/stable-security [amd64])\n" +
+				"Conf openssl (3.0.13-1~deb12u1 Debian-Security:12/stable-security [amd64])\n",
+			expected: 2,
+		},
+		{
+			name:    "dnf",
+			osType:  "rocky",
+			command: "dnf -q check-update",
+			output: "\nkernel.x86_64       5.14.0-427.el9     baseos\n" +
+				"openssl.x86_64      1:3.0.7-27.el9     baseos\n" +
+				"Obsoleting Packages\n" +
+				"grub2-tools.x86_64  1:2.06-80.el9      baseos\n" +
+				"    grub2-tools.x86_64 1:2.06-77.el9  @baseos\n",
+			expected: 2,
+		},
+		{
+			name:     "apk",
+			osType:   "alpine",
+			command:  "apk list -u",
+			output:   "busybox-1.36.1-r20 x86_64 {busybox} (GPL-2.0-only) [upgradable from: busybox-1.36.1-r19]\n",
+			expected: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCommander := interfaces.NewMockCommander()
+			mockCommander.CommandOutputs[tc.command] = []byte(tc.output)
+			repo := secondary.NewOSBannerRepository(interfaces.NewMockFileSystem(), mockCommander, tc.osType)
+
+			count, err := repo.PendingUpdates()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, count)
+		})
+	}
+}
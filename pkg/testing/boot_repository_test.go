@@ -0,0 +1,148 @@
+// pkg/testing/boot_repository_test.go
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const grubDefaults = `GRUB_DEFAULT=0
+GRUB_TIMEOUT=5
+GRUB_CMDLINE_LINUX_DEFAULT="quiet splash"
+GRUB_CMDLINE_LINUX="lockdown=integrity console=ttyS0"
+`
+
+func TestSetGrubCmdlineParameters(t *testing.T) {
+	updated, changed := secondary.SetGrubCmdlineParameters(grubDefaults,
+		[]string{"lockdown=confidentiality", "slab_nomerge"})
+	assert.True(t, changed)
+	assert.Contains(t, updated, `GRUB_CMDLINE_LINUX="console=ttyS0 lockdown=confidentiality slab_nomerge"`)
+	assert.Contains(t, updated, `GRUB_CMDLINE_LINUX_DEFAULT="quiet splash"`, "only GRUB_CMDLINE_LINUX changes")
+
+	assert.ElementsMatch(t, []string{"quiet", "splash", "console=ttyS0", "lockdown=confidentiality", "slab_nomerge"},
+		secondary.GrubCmdlineParameters(updated))
+
+	// Setting them again changes nothing
+	again, changed := secondary.SetGrubCmdlineParameters(updated, []string{"slab_nomerge"})
+	assert.False(t, changed)
+	assert.Equal(t, updated, again)
+
+	// The variable is added when missing
+	added, changed := secondary.SetGrubCmdlineParameters("GRUB_TIMEOUT=5\n", []string{"init_on_alloc=1"})
+	assert.True(t, changed)
+	assert.Equal(t, "GRUB_TIMEOUT=5\nGRUB_CMDLINE_LINUX=\"init_on_alloc=1\"\n", added)
+}
+
+func TestOSBootRepository_SetParametersDebian(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	commander := interfaces.NewMockCommander()
+	mockFS.Files[secondary.GrubDefaultsPath] = []byte(grubDefaults)
+	mockFS.Files[model.GrubConfigPath] = []byte("old\n")
+	staged := model.GrubConfigPath + ".hardn-new"
+	// grub-mkconfig writes the staged file
+	mockFS.Files[staged] = []byte("new\n")
+	repo := secondary.NewOSBootRepository(mockFS, commander, "debian")
+
+	require.NoError(t, repo.SetParameters([]string{"init_on_alloc=1"}))
+	assert.Equal(t, []string{
+		"grub-mkconfig -o " + staged,
+		"grub-script-check " + staged,
+	}, commander.ExecutedCommands)
+	assert.Equal(t, "new\n", string(mockFS.Files[model.GrubConfigPath]))
+	assert.NotContains(t, mockFS.Files, staged)
+
+	configured, err := repo.GetConfiguredParameters()
+	require.NoError(t, err)
+	assert.Contains(t, configured, "init_on_alloc=1")
+}
+
+func TestOSBootRepository_SetParametersRejectedConfig(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	commander := interfaces.NewMockCommander()
+	mockFS.Files[secondary.GrubDefaultsPath] = []byte(grubDefaults)
+	mockFS.Files[model.GrubConfigPath] = []byte("old\n")
+	staged := model.GrubConfigPath + ".hardn-new"
+	mockFS.Files[staged] = []byte("broken\n")
+	commander.CommandErrors["grub-script-check "+staged] = errors.New("exit status 1")
+	repo := secondary.NewOSBootRepository(mockFS, commander, "ubuntu")
+
+	err := repo.SetParameters([]string{"init_on_alloc=1"})
+	assert.ErrorContains(t, err, "left unchanged")
+
+	// grub.cfg and /etc/default/grub are as they were
+	assert.Equal(t, "old\n", string(mockFS.Files[model.GrubConfigPath]))
+	assert.Equal(t, grubDefaults, string(mockFS.Files[secondary.GrubDefaultsPath]))
+	assert.NotContains(t, mockFS.Files, staged)
+}
+
+func TestOSBootRepository_RHEL(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	commander := interfaces.NewMockCommander()
+	mockFS.Files[secondary.GrubDefaultsPath] = []byte(grubDefaults)
+	commander.CommandOutputs["grubby --info=DEFAULT"] = []byte(
+		"index=0\nkernel=\"/boot/vmlinuz-5.14.0\"\nargs=\"ro crashkernel=auto slab_nomerge\"\nroot=\"/dev/mapper/rl-root\"\n")
+	repo := secondary.NewOSBootRepository(mockFS, commander, "rocky")
+
+	configured, err := repo.GetConfiguredParameters()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ro", "crashkernel=auto", "slab_nomerge"}, configured)
+
+	require.NoError(t, repo.SetParameters([]string{"slab_nomerge", "page_poison=1"}))
+	assert.Contains(t, commander.ExecutedCommands, "grubby --update-kernel=ALL --args=slab_nomerge page_poison=1")
+	assert.NotContains(t, commander.ExecutedCommands, "grub-mkconfig -o "+model.GrubConfigPath+".hardn-new")
+
+	hash := "grub.pbkdf2.sha512.10000.AB.CD"
+	require.NoError(t, repo.SetPassword(hash))
+	assert.Equal(t, "GRUB2_PASSWORD="+hash+"\n", string(mockFS.Files[model.GrubUserConfigPath]))
+
+	set, err := repo.PasswordSet()
+	require.NoError(t, err)
+	assert.True(t, set)
+}
+
+func TestOSBootRepository_SetPasswordDebian(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	commander := interfaces.NewMockCommander()
+	mockFS.Files[secondary.GrubDefaultsPath] = []byte(grubDefaults)
+	mockFS.Files[model.GrubLinuxScriptPath] = []byte("  CLASS=\"--class gnu-linux --class gnu --class os\"\n")
+	repo := secondary.NewOSBootRepository(mockFS, commander, "debian")
+
+	set, err := repo.PasswordSet()
+	require.NoError(t, err)
+	assert.False(t, set)
+
+	// Plain passwords are refused; the service hashes them first
+	assert.Error(t, repo.SetPassword("secret"))
+
+	hash := "grub.pbkdf2.sha512.10000.AB.CD"
+	require.NoError(t, repo.SetPassword(hash))
+	script := string(mockFS.Files[model.GrubPasswordScriptPath])
+	assert.Contains(t, script, `set superusers="root"`)
+	assert.Contains(t, script, "password_pbkdf2 root "+hash)
+	assert.Equal(t, "  CLASS=\"--unrestricted --class gnu-linux --class gnu --class os\"\n",
+		string(mockFS.Files[model.GrubLinuxScriptPath]))
+
+	set, err = repo.PasswordSet()
+	require.NoError(t, err)
+	assert.True(t, set)
+}
+
+func TestOSBootRepository_HashPassword(t *testing.T) {
+	commander := interfaces.NewMockCommander()
+	commander.CommandOutputs["INPUT:secret\nsecret\n|grub-mkpasswd-pbkdf2"] = []byte(
+		"Enter password: \nReenter password: \nPBKDF2 hash of your password is grub.pbkdf2.sha512.10000.AB.CD\n")
+	repo := secondary.NewOSBootRepository(interfaces.NewMockFileSystem(), commander, "debian")
+
+	hash, err := repo.HashPassword("secret")
+	require.NoError(t, err)
+	assert.Equal(t, "grub.pbkdf2.sha512.10000.AB.CD", hash)
+
+	_, err = secondary.ParseGrubPasswordHash("Your passwords don't match.\n")
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+// pkg/testing/check_update_test.go
+package testing
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/cmd"
+	"github.com/abbott/hardn/pkg/version"
+	"github.com/stretchr/testify/assert"
+)
+
+// latestReleaseServer answers the latest release request with release, or
+// with status when it is not OK
+func latestReleaseServer(t *testing.T, release version.GitHubRelease, status int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(release)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRunCheckUpdate_ExitStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		release version.GitHubRelease
+		status  int
+		code    int
+	}{
+		{
+			name:    "up to date",
+			current: "0.5.0",
+			release: version.GitHubRelease{TagName: "v0.5.0"},
+			status:  http.StatusOK,
+			code:    cmd.ExitUpToDate,
+		},
+		{
+			name:    "newer than the latest release",
+			current: "0.6.0",
+			release: version.GitHubRelease{TagName: "v0.5.0"},
+			status:  http.StatusOK,
+			code:    cmd.ExitUpToDate,
+		},
+		{
+			name:    "update available",
+			current: "0.4.0",
+			release: version.GitHubRelease{TagName: "v0.5.0", Name: "v0.5.0", Body: "Faster audits"},
+			status:  http.StatusOK,
+			code:    cmd.ExitUpdate,
+		},
+		{
+			name:    "security update available",
+			current: "0.4.0",
+			release: version.GitHubRelease{TagName: "v0.5.0", Name: "v0.5.0", Body: "Fixes CVE-2026-1234 in the sudoers templates"},
+			status:  http.StatusOK,
+			code:    cmd.ExitSecurityUpdate,
+		},
+		{
+			name:    "release source unavailable",
+			current: "0.4.0",
+			status:  http.StatusInternalServerError,
+			code:    cmd.ExitCheckFailed,
+		},
+		{
+			name:    "development build",
+			release: version.GitHubRelease{TagName: "v0.5.0"},
+			status:  http.StatusOK,
+			code:    cmd.ExitCheckFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := latestReleaseServer(t, tt.release, tt.status)
+			options := &version.UpdateOptions{
+				APIURL:        server.URL + "/releases/latest",
+				CacheFilePath: filepath.Join(t.TempDir(), "version-cache.json"),
+			}
+
+			code := cmd.RunCheckUpdate(version.NewService(tt.current, "", ""), options, true)
+			assert.Equal(t, tt.code, code)
+		})
+	}
+}
+
+func TestRunCheckUpdate_OfflineWithoutCache(t *testing.T) {
+	options := &version.UpdateOptions{
+		Offline:       true,
+		CacheFilePath: filepath.Join(t.TempDir(), "version-cache.json"),
+	}
+
+	code := cmd.RunCheckUpdate(version.NewService("0.4.0", "", ""), options, true)
+	assert.Equal(t, cmd.ExitCheckFailed, code)
+}
@@ -0,0 +1,39 @@
+// pkg/testing/command_env_test.go
+package testing
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand_ResolvesOnCommandPath(t *testing.T) {
+	// A directory at the front of the inherited PATH shadows sh and adds a
+	// tool CommandPath does not have
+	dir := t.TempDir()
+	for _, name := range []string{"sh", "hardn-planted"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\nexit 0\n"), 0755))
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cmd := interfaces.Command("sh", "-c", "true")
+	assert.NotEqual(t, filepath.Join(dir, "sh"), cmd.Path)
+	assert.True(t, strings.HasPrefix(cmd.Path, "/"), "sh runs from CommandPath: %s", cmd.Path)
+
+	err := interfaces.Command("hardn-planted").Run()
+	assert.True(t, errors.Is(err, exec.ErrNotFound), "got %v", err)
+	_, err = interfaces.LookPath("hardn-planted")
+	assert.True(t, errors.Is(err, exec.ErrNotFound), "got %v", err)
+
+	// A path is used as given
+	planted := filepath.Join(dir, "hardn-planted")
+	assert.Equal(t, planted, interfaces.Command(planted).Path)
+	assert.NoError(t, interfaces.Command(planted).Run())
+}
@@ -0,0 +1,303 @@
+// pkg/testing/command_output_test.go
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+// Localized `ufw status verbose` output; hardn must not depend on it
+const germanUFWStatus = `Status: aktiv
+Protokollierung: on (low)
+Voreinstellung: deny (eingehend), allow (abgehend), disabled (gesendet)
+Neue Profile: skip
+
+Zu                         Aktion      Von
+--                         ------      ---
+22/tcp                     ALLOW IN    Überall
+`
+
+const germanUFWShowAdded = `Hinzugefügte Benutzerregeln (ufw-Syntax):
+ufw allow 22/tcp
+ufw limit 2208/tcp
+ufw allow LabHTTPS
+`
+
+// TestUFWFirewallRepository_LocalizedStatus checks status comes from ufw's
+// settings files and rule list rather than translated status text
+func TestUFWFirewallRepository_LocalizedStatus(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[secondary.UFWConfPath] = []byte("# /etc/ufw/ufw.conf\nENABLED=yes\nLOGLEVEL=low\n")
+	mockFS.Files[secondary.UFWDefaultsPath] = []byte(`IPV6=yes
+DEFAULT_INPUT_POLICY="DROP"
+DEFAULT_OUTPUT_POLICY="ACCEPT"
+DEFAULT_FORWARD_POLICY="DROP"
+`)
+
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandOutputs["which ufw"] = []byte("/usr/sbin/ufw\n")
+	mockCommander.CommandOutputs["ufw status"] = []byte(germanUFWStatus)
+	mockCommander.CommandOutputs["ufw show added"] = []byte(germanUFWShowAdded)
+
+	repo := secondary.NewUFWFirewallRepository(mockFS, mockCommander, "debian")
+	installed, enabled, configured, rules, err := repo.GetFirewallStatus()
+
+	assert.NoError(t, err)
+	assert.True(t, installed)
+	assert.True(t, enabled)
+	assert.True(t, configured)
+	assert.Equal(t, []string{"allow 22/tcp", "limit 2208/tcp", "allow LabHTTPS"}, rules)
+}
+
+func TestUFWFirewallRepository_Disabled(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[secondary.UFWConfPath] = []byte("ENABLED=no\n")
+	mockFS.Files[secondary.UFWDefaultsPath] = []byte("DEFAULT_INPUT_POLICY=\"ACCEPT\"\nDEFAULT_OUTPUT_POLICY=\"ACCEPT\"\n")
+
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandOutputs["which ufw"] = []byte("/usr/sbin/ufw\n")
+
+	repo := secondary.NewUFWFirewallRepository(mockFS, mockCommander, "debian")
+	installed, enabled, configured, rules, err := repo.GetFirewallStatus()
+
+	assert.NoError(t, err)
+	assert.True(t, installed)
+	assert.False(t, enabled)
+	assert.False(t, configured)
+	assert.Empty(t, rules)
+}
+
+const ufwUserRules = `*filter
+:ufw-user-input - [0:0]
+### RULES ###
+
+### tuple ### allow tcp 22 0.0.0.0/0 any 0.0.0.0/0 in
+-A ufw-user-input -p tcp --dport 22 -j ACCEPT
+
+### tuple ### limit tcp 2208 0.0.0.0/0 any 0.0.0.0/0 in comment=5353482d
+-A ufw-user-input -p tcp --dport 2208 -j ufw-user-limit
+
+### tuple ### allow any 30443 0.0.0.0/0 any 0.0.0.0/0 in
+### tuple ### allow tcp 5432 0.0.0.0/0 any 10.0.0.0/8 in
+### tuple ### deny tcp 8080 0.0.0.0/0 any 0.0.0.0/0 in
+### tuple ### allow tcp 443 0.0.0.0/0 any 0.0.0.0/0 out
+### tuple ### allow_log udp 53 192.168.1.1 any 0.0.0.0/0 in_eth0
+`
+
+func TestParseUFWTuples(t *testing.T) {
+	assert.Equal(t, []string{
+		"allow 22/tcp",
+		"limit 2208/tcp",
+		"allow 30443",
+		"allow 5432/tcp from 10.0.0.0/8",
+		"allow 53/udp to 192.168.1.1",
+	}, secondary.ParseUFWTuples([]byte(ufwUserRules)))
+	assert.Empty(t, secondary.ParseUFWTuples(nil))
+}
+
+func TestParseIfInet6(t *testing.T) {
+	loopback := "00000000000000000000000000000001 01 80 10 80       lo\n"
+	global := "20010db8000000000000000000000001 02 40 00 80     eth0\n"
+
+	assert.False(t, secondary.ParseIfInet6([]byte(loopback)))
+	assert.True(t, secondary.ParseIfInet6([]byte(loopback+global)))
+	assert.False(t, secondary.ParseIfInet6(nil))
+}
+
+func TestParseDefaultRouteInterface(t *testing.T) {
+	header := "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n"
+	lan := "eth1\t0001A8C0\t00000000\t0001\t0\t0\t0\t00FFFFFF\t0\t0\t0\n"
+	def := "eth0\t00000000\t0101A8C0\t0003\t0\t0\t0\t00000000\t0\t0\t0\n"
+
+	assert.Equal(t, "eth0", secondary.ParseDefaultRouteInterface([]byte(header+lan+def)))
+	assert.Equal(t, "", secondary.ParseDefaultRouteInterface([]byte(header+lan)))
+	assert.Equal(t, "", secondary.ParseDefaultRouteInterface(nil))
+}
+
+// TestUFWFirewallRepository_IPv6Disabled checks IPv6 counts as unfiltered
+// when ufw is enabled with IPV6=no
+func TestUFWFirewallRepository_IPv6Disabled(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[secondary.UFWConfPath] = []byte("ENABLED=yes\n")
+	mockFS.Files[secondary.UFWDefaultsPath] = []byte("IPV6=no\nDEFAULT_INPUT_POLICY=\"DROP\"\n")
+	mockFS.Files[secondary.UFWUserRulesPath] = []byte(ufwUserRules)
+	mockFS.Files[secondary.IfInet6Path] = []byte("20010db8000000000000000000000001 02 40 00 80     eth0\n")
+
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandOutputs["which ufw"] = []byte("/usr/sbin/ufw\n")
+	mockCommander.CommandOutputs["ip6tables-save"] = []byte("*filter\n:INPUT ACCEPT [0:0]\nCOMMIT\n")
+
+	repo := secondary.NewUFWFirewallRepository(mockFS, mockCommander, "ubuntu")
+	ipv4, ipv6, err := repo.GetStackRules()
+
+	assert.NoError(t, err)
+	assert.True(t, ipv4.Filtered)
+	assert.Len(t, ipv4.Allowed, 5)
+	assert.True(t, ipv6.Active)
+	assert.False(t, ipv6.Filtered)
+	assert.Empty(t, ipv6.Allowed)
+
+	assert.NoError(t, repo.FilterFamily(model.FamilyIPv6))
+	assert.Equal(t, "IPV6=yes\nDEFAULT_INPUT_POLICY=\"DROP\"\n", string(mockFS.Files[secondary.UFWDefaultsPath]))
+	assert.Contains(t, mockCommander.ExecutedCommands, "ufw reload")
+
+	assert.NoError(t, repo.MirrorRule("allow 22/tcp", model.FamilyIPv6))
+	assert.Contains(t, mockCommander.ExecutedCommands, "ufw allow 22/tcp")
+}
+
+// TestOSPackageRepository_IsPackageInstalled checks dpkg-query status parsing,
+// including packages removed with their configuration left behind
+func TestOSPackageRepository_IsPackageInstalled(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		err      error
+		expected bool
+	}{
+		{name: "installed", output: "install ok installed\n", expected: true},
+		{name: "removed with config", output: "deinstall ok config-files\n", expected: false},
+		{name: "half configured", output: "install ok half-configured\n", expected: false},
+		{name: "unknown package", err: errors.New("exit status 1"), expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			key := "dpkg-query -W -f=" + secondary.DpkgStatusFormat + " unattended-upgrades"
+			mockCommander := interfaces.NewMockCommander()
+			mockCommander.CommandOutputs[key] = []byte(tc.output)
+			mockCommander.CommandErrors[key] = tc.err
+
+			repo := secondary.NewOSPackageRepository(interfaces.NewMockFileSystem(), mockCommander,
+				"debian", "12", "bookworm", "amd64", false, &model.PackageSources{})
+			installed, err := repo.IsPackageInstalled("unattended-upgrades")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, installed)
+		})
+	}
+}
+
+func TestOSPackageRepository_DnfAutomatic(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[model.DnfAutomaticConfigPath] = []byte("[commands]\nupgrade_type = default\nrandom_sleep = 0\n\n[emitters]\nemit_via = stdio\n")
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandErrors["rpm -q dnf-automatic"] = errors.New("package dnf-automatic is not installed")
+
+	repo := secondary.NewOSPackageRepository(mockFS, mockCommander,
+		"rocky", "9.4", "", "amd64", false, &model.PackageSources{})
+	assert.NoError(t, repo.ConfigureAutoUpgrades(true))
+
+	assert.Contains(t, mockCommander.ExecutedCommands, "dnf install -y dnf-automatic")
+	assert.Contains(t, mockCommander.ExecutedCommands, "systemctl enable --now dnf-automatic.timer")
+	assert.Equal(t, "[commands]\napply_updates = yes\nupgrade_type = security\nrandom_sleep = 0\n\n[emitters]\nemit_via = stdio\n",
+		string(mockFS.Files[model.DnfAutomaticConfigPath]))
+
+	assert.NoError(t, repo.ConfigureAutoUpgrades(false))
+	assert.Contains(t, mockCommander.ExecutedCommands, "systemctl disable --now dnf-automatic.timer")
+}
+
+func TestParseIptablesSave(t *testing.T) {
+	output := []byte(`# Generated by iptables-save v1.8.9 (nf_tables)
+*filter
+:INPUT DROP [0:0]
+:FORWARD DROP [0:0]
+:OUTPUT ACCEPT [12:1024]
+-A INPUT -i lo -j ACCEPT
+-A INPUT -p tcp -m tcp --dport 2208 -j ACCEPT
+COMMIT
+`)
+
+	policies, rules := secondary.ParseIptablesSave(output)
+
+	assert.Equal(t, "DROP", policies["INPUT"])
+	assert.Equal(t, "ACCEPT", policies["OUTPUT"])
+	assert.Len(t, rules, 2)
+	assert.Contains(t, rules[1], "--dport 2208")
+}
+
+func TestParseAppArmorProfiles(t *testing.T) {
+	data := []byte(`/usr/sbin/sshd (enforce)
+/usr/bin/man (enforce)
+man_filter (complain)
+nvidia_modprobe (enforce)
+`)
+
+	enforce, complain := secondary.ParseAppArmorProfiles(data)
+
+	assert.Equal(t, 3, enforce)
+	assert.Equal(t, 1, complain)
+}
+
+func TestParseNeedrestartBatch(t *testing.T) {
+	output := []byte(`NEEDRESTART-VER: 3.6
+NEEDRESTART-KCUR: 6.1.0-17-amd64
+NEEDRESTART-KEXP: 6.1.0-18-amd64
+NEEDRESTART-KSTA: 3
+NEEDRESTART-SVC: ssh.service
+NEEDRESTART-SVC: cron.service
+`)
+
+	services, rebootRequired := secondary.ParseNeedrestartBatch(output)
+
+	assert.Equal(t, []string{"ssh.service", "cron.service"}, services)
+	assert.True(t, rebootRequired)
+
+	services, rebootRequired = secondary.ParseNeedrestartBatch([]byte("NEEDRESTART-VER: 3.6\nNEEDRESTART-KSTA: 1\n"))
+	assert.Empty(t, services)
+	assert.False(t, rebootRequired)
+}
+
+func TestParseOPAViolations(t *testing.T) {
+	t.Run("set of messages and objects", func(t *testing.T) {
+		output := []byte(`{"result":[{"expressions":[{"value":[
+			"SSH listens on the default port",
+			{"msg":"firewall disabled","rule":"firewall","severity":"high"}
+		],"text":"data.hardn.deny","location":{"row":1,"col":1}}]}]}`)
+
+		violations, err := secondary.ParseOPAViolations(output)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.PolicyViolation{
+			{Message: "SSH listens on the default port"},
+			{Rule: "firewall", Severity: "high", Message: "firewall disabled"},
+		}, violations)
+	})
+
+	t.Run("map of rule to message", func(t *testing.T) {
+		output := []byte(`{"result":[{"expressions":[{"value":{"ssh_port":"default port","apparmor":"not enforced"}}]}]}`)
+
+		violations, err := secondary.ParseOPAViolations(output)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.PolicyViolation{
+			{Rule: "apparmor", Message: "not enforced"},
+			{Rule: "ssh_port", Message: "default port"},
+		}, violations)
+	})
+
+	t.Run("undefined rule", func(t *testing.T) {
+		violations, err := secondary.ParseOPAViolations([]byte(`{}`))
+
+		assert.NoError(t, err)
+		assert.Empty(t, violations)
+	})
+}
+
+func TestOPAPolicyRepository_MissingOPA(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	_ = mockFS.MkdirAll("/etc/hardn/policies", 0755)
+
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandErrors["which opa"] = errors.New("exit status 1")
+
+	repo := secondary.NewOPAPolicyRepository(mockFS, mockCommander)
+	_, err := repo.EvaluatePolicies("/etc/hardn/policies", &model.Facts{})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "opa not found")
+}
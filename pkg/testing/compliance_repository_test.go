@@ -0,0 +1,86 @@
+// pkg/testing/compliance_repository_test.go
+package testing
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// modeFileInfo is a file with the given permission bits
+type modeFileInfo struct {
+	name string
+	mode fs.FileMode
+}
+
+func (i modeFileInfo) Name() string       { return i.name }
+func (i modeFileInfo) Size() int64        { return 0 }
+func (i modeFileInfo) Mode() fs.FileMode  { return i.mode }
+func (i modeFileInfo) ModTime() time.Time { return time.Time{} }
+func (i modeFileInfo) IsDir() bool        { return false }
+func (i modeFileInfo) Sys() any           { return nil }
+
+func TestOSComplianceRepository_SSHEffectiveConfig(t *testing.T) {
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSComplianceRepository(interfaces.NewMockFileSystem(), mockCommander)
+
+	// Without sshd there is nothing to check
+	mockCommander.CommandErrors["which sshd"] = errors.New("not found")
+	settings, err := repo.SSHEffectiveConfig()
+	require.NoError(t, err)
+	assert.Nil(t, settings)
+
+	delete(mockCommander.CommandErrors, "which sshd")
+	mockCommander.CommandOutputs["sshd -T"] = []byte("port 2222\nMaxAuthTries 3\nallowusers george\nallowusers deploy\n")
+	settings, err = repo.SSHEffectiveConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3"}, settings["maxauthtries"])
+	assert.Equal(t, []string{"george", "deploy"}, settings["allowusers"])
+
+	mockCommander.CommandErrors["sshd -T"] = errors.New("exit status 255")
+	_, err = repo.SSHEffectiveConfig()
+	assert.Error(t, err)
+}
+
+func TestOSComplianceRepository_FileModeAndSudoers(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSComplianceRepository(mockFS, mockCommander)
+
+	mockFS.FileInfos["/etc/ssh/sshd_config"] = modeFileInfo{name: "sshd_config", mode: 0644}
+	mode, err := repo.FileMode("/etc/ssh/sshd_config")
+	require.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0644), mode)
+
+	mockFS.Files["/etc/sudoers"] = []byte("root ALL=(ALL:ALL) ALL\n")
+	mockFS.Files["/etc/sudoers.d/deploy"] = []byte("deploy ALL=(ALL) NOPASSWD: ALL\n")
+	mockFS.Files["/etc/sudoers.d/deploy.bak"] = []byte("old ALL=(ALL) NOPASSWD: ALL\n")
+	require.NoError(t, mockFS.MkdirAll("/etc/sudoers.d", 0750))
+	mockCommander.CommandOutputs["find /etc/sudoers.d -mindepth 1 -maxdepth 1 -type f"] =
+		[]byte("/etc/sudoers.d/deploy\n/etc/sudoers.d/deploy.bak\n")
+
+	// sudo skips files with a dot in their name, so the check does too
+	files, err := repo.ReadSudoers()
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Contains(t, files, "/etc/sudoers.d/deploy")
+	assert.NotContains(t, files, "/etc/sudoers.d/deploy.bak")
+}
+
+// TestCISRules_Explained checks every benchmark recommendation maps to a
+// check "hardn explain" knows
+func TestCISRules_Explained(t *testing.T) {
+	for _, rule := range model.CISRules {
+		checks := security.LookupChecks(rule.Check)
+		require.Len(t, checks, 1, rule.ID)
+		assert.NotEmpty(t, rule.Remediation, rule.ID)
+	}
+}
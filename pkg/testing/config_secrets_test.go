@@ -0,0 +1,138 @@
+// pkg/testing/config_secrets_test.go
+package testing
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reversingCipher "encrypts" by reversing the bytes behind a marker
+type reversingCipher struct{}
+
+func (reversingCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	out := []byte("rev:")
+	for i := len(plaintext) - 1; i >= 0; i-- {
+		out = append(out, plaintext[i])
+	}
+	return out, nil
+}
+
+func (c reversingCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	if !bytes.HasPrefix(ciphertext, []byte("rev:")) {
+		return nil, fmt.Errorf("not encrypted for this identity")
+	}
+	out, _ := c.Encrypt(ciphertext[len("rev:"):])
+	return out[len("rev:"):], nil
+}
+
+const secretsConfig = `# Notifications
+notifications:
+  email:
+    to:
+      - "ops@example.com"
+    password: "hunter22" # SMTP password
+  webhooks:
+    - "https://hooks.example.com/T000/B000"
+sshKeys:
+  - "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5 admin@example.com"
+apiToken: "tok-123456"
+dryRun: true
+`
+
+func TestConfigSecrets_EncryptDecryptRoundTrip(t *testing.T) {
+	encrypted, count, err := config.EncryptSecrets([]byte(secretsConfig), reversingCipher{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	text := string(encrypted)
+	assert.NotContains(t, text, "hunter22")
+	assert.NotContains(t, text, "hooks.example.com")
+	assert.NotContains(t, text, "tok-123456")
+	assert.Contains(t, text, "ops@example.com")
+	assert.Contains(t, text, "# SMTP password")
+	assert.Contains(t, text, "dryRun: true")
+	assert.Equal(t, 4, strings.Count(text, "ENC[age,"))
+
+	// Encrypting again leaves encrypted values alone
+	_, count, err = config.EncryptSecrets(encrypted, reversingCipher{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	decrypted, count, err := config.DecryptSecrets(encrypted, reversingCipher{})
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	parsed, err := config.ParseConfig(bytes.Replace(decrypted, []byte("apiToken"), []byte("# apiToken"), 1))
+	require.NoError(t, err)
+	assert.Equal(t, "hunter22", parsed.Notifications.Email.Password)
+	assert.Equal(t, []string{"https://hooks.example.com/T000/B000"}, parsed.Notifications.Webhooks)
+	assert.Equal(t, []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5 admin@example.com"}, parsed.SshKeys)
+	assert.True(t, parsed.DryRun)
+}
+
+func TestConfigSecrets_Resolve(t *testing.T) {
+	encrypted, _, err := config.EncryptSecrets([]byte(secretsConfig), reversingCipher{}, nil)
+	require.NoError(t, err)
+
+	t.Setenv("HARDN_TEST_MAIL_TO", "secops@example.com")
+	data := bytes.Replace(encrypted, []byte(`"ops@example.com"`), []byte(`"${env:HARDN_TEST_MAIL_TO}"`), 1)
+
+	resolved, err := config.ResolveSecrets(data, reversingCipher{})
+	require.NoError(t, err)
+	text := string(resolved)
+	assert.Contains(t, text, "secops@example.com")
+	assert.Contains(t, text, "hunter22")
+	assert.NotContains(t, text, "ENC[")
+
+	// Resolved secrets are redacted from the log from here on
+	assert.Equal(t, "login with "+logging.Redacted, logging.Redact("login with hunter22"))
+
+	_, err = config.ResolveSecrets([]byte("password: \"${env:HARDN_TEST_UNSET}\"\n"), reversingCipher{})
+	assert.ErrorContains(t, err, "HARDN_TEST_UNSET")
+
+	_, err = config.ResolveSecrets(data, config.NewAgeCipher(nil, filepath.Join(t.TempDir(), "missing.key")))
+	assert.Error(t, err)
+}
+
+func TestConfigSecrets_SaveKeepsReferences(t *testing.T) {
+	t.Setenv("HARDN_TEST_SMTP_PASSWORD", "s3cr3t-from-env")
+	resolved, err := config.ResolveSecrets([]byte("password: \"${env:HARDN_TEST_SMTP_PASSWORD}\"\n"), reversingCipher{})
+	require.NoError(t, err)
+	assert.Contains(t, string(resolved), "s3cr3t-from-env")
+
+	cfg := config.DefaultConfig()
+	cfg.Notifications.Email.Password = "s3cr3t-from-env"
+	path := filepath.Join(t.TempDir(), "hardn.yml")
+	require.NoError(t, config.SaveConfig(cfg, path))
+
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(saved), "s3cr3t-from-env")
+	assert.Contains(t, string(saved), "${env:HARDN_TEST_SMTP_PASSWORD}")
+}
+
+func TestConfigSecrets_IsSecretKey(t *testing.T) {
+	assert.True(t, config.IsSecretKey("password", nil))
+	assert.True(t, config.IsSecretKey("sshKeys", nil))
+	assert.True(t, config.IsSecretKey("apiToken", nil))
+	assert.True(t, config.IsSecretKey("clientSecret", nil))
+	assert.True(t, config.IsSecretKey("url", []string{"url"}))
+	assert.False(t, config.IsSecretKey("username", nil))
+	assert.False(t, config.IsSecretKey("url", nil))
+}
+
+func TestLogging_Redact(t *testing.T) {
+	logging.AddSecrets("abc", "correct-horse-battery")
+	assert.Equal(t, "key abc", logging.Redact("key abc"), "too short to redact")
+	assert.Equal(t, "pass "+logging.Redacted+" twice "+logging.Redacted,
+		logging.Redact("pass correct-horse-battery twice correct-horse-battery"))
+}
@@ -0,0 +1,78 @@
+// pkg/testing/database_repository_test.go
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePgLsclusters(t *testing.T) {
+	output := []byte(`15  main    5432 online postgres /var/lib/postgresql/15/main /var/log/postgresql/postgresql-15-main.log
+16  reports 5433 down   postgres /var/lib/postgresql/16/reports /var/log/postgresql/postgresql-16-reports.log
+`)
+
+	clusters := secondary.ParsePgLsclusters(output)
+
+	assert.Len(t, clusters, 2)
+	assert.Equal(t, "15/main", clusters[0].Instance)
+	assert.Equal(t, 5432, clusters[0].Port)
+	assert.Equal(t, "/etc/postgresql/16/reports/conf.d/hardn.conf", clusters[1].ConfigPath)
+}
+
+func TestOSDatabaseRepository_MariaDB(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	_ = mockFS.MkdirAll("/etc/mysql/mariadb.conf.d", 0755)
+
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandErrors["pg_lsclusters --no-header"] = errors.New("not found")
+	mockCommander.CommandOutputs["mariadbd --version"] = []byte("mariadbd  Ver 10.11.6-MariaDB-0+deb12u1 for debian-linux-gnu on x86_64\n")
+
+	repo := secondary.NewOSDatabaseRepository(mockFS, mockCommander, "debian")
+
+	servers, err := repo.DetectDatabases()
+	assert.NoError(t, err)
+	assert.Equal(t, []model.DatabaseServer{{
+		Name:       model.DatabaseMySQL,
+		Flavor:     "mariadb",
+		Port:       3306,
+		ConfigPath: "/etc/mysql/mariadb.conf.d/99-hardn.cnf",
+	}}, servers)
+
+	settings := model.DatabaseHardening{BindAddresses: []string{"127.0.0.1", "10.0.5.12"}}
+	assert.NoError(t, repo.WriteDatabaseBaseline(servers[0], settings))
+
+	content := string(mockFS.Files["/etc/mysql/mariadb.conf.d/99-hardn.cnf"])
+	assert.Contains(t, content, "bind-address = 127.0.0.1,10.0.5.12\n")
+	assert.Contains(t, content, "local-infile = 0\n")
+	assert.Contains(t, content, "plugin-load-add = simple_password_check\n")
+
+	assert.NoError(t, repo.RestartDatabase(servers[0]))
+	assert.Contains(t, mockCommander.ExecutedCommands, "systemctl restart mariadb")
+
+	servers, _ = repo.DetectDatabases()
+	assert.True(t, servers[0].BaselineApplied)
+}
+
+func TestOSDatabaseRepository_PostgresValidation(t *testing.T) {
+	query := "runuser -u postgres -- psql -p 5432 -XAtc SELECT sourcefile || ':' || sourceline || ': ' || error " +
+		"FROM pg_file_settings WHERE error IS NOT NULL"
+
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandOutputs[query] = []byte("/etc/postgresql/15/main/conf.d/hardn.conf:2: setting could not be applied\n")
+
+	repo := secondary.NewOSDatabaseRepository(interfaces.NewMockFileSystem(), mockCommander, "debian")
+	server := model.DatabaseServer{Name: model.DatabasePostgreSQL, Instance: "15/main", Port: 5432}
+
+	output, err := repo.ValidateDatabaseConfig(server)
+
+	assert.Error(t, err)
+	assert.Contains(t, output, "hardn.conf:2")
+
+	assert.NoError(t, repo.RestartDatabase(server))
+	assert.Contains(t, mockCommander.ExecutedCommands, "pg_ctlcluster 15 main restart")
+}
@@ -0,0 +1,81 @@
+// pkg/testing/debloat_repository_test.go
+package testing
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOSDebloatRepository_Debian(t *testing.T) {
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSDebloatRepository(mockCommander, "debian")
+
+	mockCommander.CommandOutputs["dpkg-query -W -f=${Status}\n telnetd"] = []byte("install ok installed")
+	mockCommander.CommandOutputs["dpkg-query -W -f=${Status}\n telnet"] = []byte("deinstall ok config-files")
+	mockCommander.CommandErrors["dpkg-query -W -f=${Status}\n inetutils-telnetd"] = assert.AnError
+
+	installed, err := repo.InstalledPackages([]string{"telnetd", "telnet", "inetutils-telnetd"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"telnetd"}, installed, "packages removed with their configuration kept are left out")
+
+	mockCommander.CommandErrors["systemctl is-active --quiet cups-browsed"] = assert.AnError
+	mockCommander.CommandErrors["systemctl is-enabled --quiet cups-browsed"] = assert.AnError
+	mockCommander.CommandErrors["systemctl is-active --quiet cups"] = assert.AnError
+	running, err := repo.RunningServices([]string{"cups-browsed", "cups"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cups"}, running, "services enabled at boot count as running")
+
+	mockCommander.CommandOutputs["apt-get -s remove cups cups-daemon"] = []byte(
+		"Reading package lists...\n" +
+			"The following packages will be REMOVED:\n" +
+			"  cups cups-daemon printer-driver-gutenprint\n" +
+			"Remv printer-driver-gutenprint [5.3.4.20220624T01008808d602-1]\n" +
+			"Remv cups [2.4.2-3+deb12u5]\n" +
+			"Remv cups-daemon:amd64 [2.4.2-3+deb12u5]\n")
+	dependents, err := repo.RemovalDependents([]string{"cups", "cups-daemon"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"printer-driver-gutenprint"}, dependents)
+
+	require.NoError(t, repo.RemovePackages([]string{"telnetd"}))
+	require.NoError(t, repo.DisableService("telnet.socket"))
+	assert.Contains(t, mockCommander.ExecutedCommands, "apt-get remove --yes telnetd")
+	assert.Contains(t, mockCommander.ExecutedCommands, "systemctl disable --now telnet.socket")
+}
+
+func TestOSDebloatRepository_RHELAndAlpine(t *testing.T) {
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSDebloatRepository(mockCommander, "rocky")
+
+	mockCommander.CommandErrors["rpm -q rsh"] = assert.AnError
+	installed, err := repo.InstalledPackages([]string{"rsh-server", "rsh"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rsh-server"}, installed)
+
+	dependents, err := repo.RemovalDependents(installed)
+	require.NoError(t, err)
+	assert.Empty(t, dependents, "rpm -e --test succeeds when nothing depends on them")
+
+	require.NoError(t, repo.RemovePackages(installed))
+	assert.Contains(t, mockCommander.ExecutedCommands, "rpm -e --test rsh-server")
+	assert.Contains(t, mockCommander.ExecutedCommands, "dnf remove -y rsh-server")
+
+	mockCommander = interfaces.NewMockCommander()
+	repo = secondary.NewOSDebloatRepository(mockCommander, "alpine")
+
+	mockCommander.CommandErrors["apk info -e avahi"] = assert.AnError
+	installed, err = repo.InstalledPackages([]string{"avahi", "busybox-extras"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"busybox-extras"}, installed)
+
+	dependents, err = repo.RemovalDependents(installed)
+	require.NoError(t, err)
+	assert.Empty(t, dependents)
+	assert.NotContains(t, mockCommander.ExecutedCommands, "apt-get -s remove busybox-extras")
+
+	require.NoError(t, repo.RemovePackages(installed))
+	assert.Contains(t, mockCommander.ExecutedCommands, "apk del busybox-extras")
+}
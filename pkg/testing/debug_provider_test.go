@@ -0,0 +1,48 @@
+// pkg/testing/debug_provider_test.go
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugProvider(t *testing.T) {
+	sink := &recordingSink{}
+	require.NoError(t, logging.Configure(logging.Options{Level: "info"}))
+	logging.AddSink(sink)
+	defer logging.CloseLogging()
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandOutputs["ufw status"] = []byte("Status: active\n")
+	mockCommander.CommandErrors["ufw reload"] = errors.New("exit status 1")
+	provider := interfaces.DebugProvider(&interfaces.Provider{FS: mockFS, Commander: mockCommander}, logging.ModuleFirewall)
+
+	// Nothing is logged until the module is enabled
+	_, _ = provider.Commander.Execute("ufw", "status")
+	assert.Empty(t, sink.entries)
+
+	logging.SetModuleDebug(logging.ModuleFirewall, true)
+	defer logging.SetDebugModules(nil)
+
+	output, err := provider.Commander.Execute("ufw", "status")
+	require.NoError(t, err)
+	assert.Equal(t, "Status: active\n", string(output))
+	_, err = provider.Commander.Execute("ufw", "reload")
+	assert.Error(t, err)
+	require.NoError(t, provider.FS.WriteFile("/etc/default/ufw", []byte("IPV6=yes\n"), 0644))
+
+	require.Len(t, sink.entries, 3)
+	assert.Equal(t, "run ufw status: ok\nStatus: active", sink.entries[0].Message)
+	assert.Equal(t, "run ufw reload: exit status 1", sink.entries[1].Message)
+	assert.Equal(t, "write /etc/default/ufw (9 bytes, -rw-r--r--): ok", sink.entries[2].Message)
+	assert.Equal(t, logging.ModuleFirewall, sink.entries[2].Module)
+	assert.Equal(t, "IPV6=yes\n", string(mockFS.Files["/etc/default/ufw"]))
+}
@@ -0,0 +1,112 @@
+// pkg/testing/degraded_mode_test.go
+package testing
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestrictToDistributionAgnostic(t *testing.T) {
+	config := &model.HardeningConfig{
+		CreateUser:               true,
+		Username:                 "george",
+		SshPort:                  2208,
+		SshAllowedUsers:          []string{"george"},
+		ConfigureDns:             true,
+		Nameservers:              []string{"1.1.1.1"},
+		EnableSELinux:            true,
+		EnableFirewall:           true,
+		EnableAppArmor:           true,
+		EnableLynis:              true,
+		EnableUnattendedUpgrades: true,
+		NeedrestartMode:          "a",
+		EnableWebServerTLS:       true,
+		DatabaseHardening:        &model.DatabaseHardening{},
+		PasswordPolicy:           &model.PasswordPolicy{},
+		BootHardening:            &model.BootHardeningPolicy{},
+		TimeSync:                 &model.TimeSyncPolicy{},
+		LogShipping:              &model.LogShippingPolicy{},
+		Banner:                   &model.BannerPolicy{},
+		ModuleBlacklist:          &model.ModuleBlacklistPolicy{},
+		InstallPackages:          true,
+	}
+
+	skipped := config.RestrictToDistributionAgnostic()
+	assert.Equal(t, []string{
+		"firewall", "AppArmor", "Lynis", "automatic updates", "needrestart", "web server TLS",
+		"database hardening", "password policy", "boot hardening", "time synchronization", "log shipping",
+	}, skipped)
+
+	// Skipped steps are turned off
+	assert.False(t, config.EnableFirewall)
+	assert.False(t, config.EnableAppArmor)
+	assert.False(t, config.EnableLynis)
+	assert.False(t, config.EnableUnattendedUpgrades)
+	assert.Empty(t, config.NeedrestartMode)
+	assert.False(t, config.EnableWebServerTLS)
+	assert.Nil(t, config.DatabaseHardening)
+	assert.Nil(t, config.PasswordPolicy)
+	assert.Nil(t, config.BootHardening)
+	assert.Nil(t, config.TimeSync)
+	assert.Nil(t, config.LogShipping)
+	assert.False(t, config.InstallPackages)
+
+	// User, SSH, DNS, SELinux, banner and module settings are kept
+	assert.True(t, config.CreateUser)
+	assert.Equal(t, "george", config.Username)
+	assert.Equal(t, 2208, config.SshPort)
+	assert.Equal(t, []string{"george"}, config.SshAllowedUsers)
+	assert.True(t, config.ConfigureDns)
+	assert.Equal(t, []string{"1.1.1.1"}, config.Nameservers)
+	assert.True(t, config.EnableSELinux)
+	assert.NotNil(t, config.Banner)
+	assert.NotNil(t, config.ModuleBlacklist)
+}
+
+func TestRestrictToDistributionAgnostic_NothingToSkip(t *testing.T) {
+	config := &model.HardeningConfig{CreateUser: true, ConfigureDns: true}
+
+	assert.Empty(t, config.RestrictToDistributionAgnostic())
+	assert.True(t, config.CreateUser)
+	assert.True(t, config.ConfigureDns)
+}
+
+func TestCheckSupport(t *testing.T) {
+	defer osdetect.AllowUnsupported(false)
+
+	tests := []struct {
+		name      string
+		osType    string
+		allow     bool
+		supported bool
+		degraded  bool
+	}{
+		{name: "debian", osType: "debian", supported: true},
+		{name: "ubuntu", osType: "ubuntu", supported: true},
+		{name: "alpine", osType: "alpine", supported: true},
+		{name: "rocky", osType: "rocky", supported: true},
+		{name: "supported with the flag", osType: "debian", allow: true, supported: true},
+		{name: "unsupported", osType: "gentoo"},
+		{name: "unsupported with the flag", osType: "gentoo", allow: true, supported: true, degraded: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			osdetect.AllowUnsupported(tt.allow)
+			info := &osdetect.OSInfo{OsType: tt.osType, OsVersion: "1"}
+
+			err := osdetect.CheckSupport(info)
+			if !tt.supported {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "--force-unsupported")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.degraded, info.Degraded)
+		})
+	}
+}
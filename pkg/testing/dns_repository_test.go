@@ -0,0 +1,96 @@
+// pkg/testing/dns_repository_test.go
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileDNSRepository_GetDNSConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		resolved    bool
+		resolvconf  bool
+		resolvectl  string
+		resolvConf  string
+		expected    *model.DNSConfig
+		expectError bool
+	}{
+		{
+			name:       "direct resolv.conf",
+			resolvConf: "# Generated by hand\n\nnameserver 1.1.1.1\nnameserver 9.9.9.9\ndomain example.com\nsearch example.com corp.example.com\noptions edns0\n",
+			expected: &model.DNSConfig{
+				Nameservers:    []string{"1.1.1.1", "9.9.9.9"},
+				Domain:         "example.com",
+				Search:         []string{"example.com", "corp.example.com"},
+				Implementation: "direct (/etc/resolv.conf)",
+			},
+		},
+		{
+			name:       "resolvconf keeps the file's nameservers",
+			resolvconf: true,
+			resolvConf: "nameserver 10.0.0.53\n",
+			expected: &model.DNSConfig{
+				Nameservers:    []string{"10.0.0.53"},
+				Implementation: "resolvconf",
+			},
+		},
+		{
+			name:       "systemd-resolved reports upstream servers, not the stub",
+			resolved:   true,
+			resolvectl: "Global: 1.1.1.1 1.0.0.1\nLink 2 (eth0): 10.0.0.53\n",
+			resolvConf: "nameserver 127.0.0.53\nsearch example.com\n",
+			expected: &model.DNSConfig{
+				Nameservers:    []string{"1.1.1.1", "1.0.0.1", "10.0.0.53"},
+				Search:         []string{"example.com"},
+				Implementation: "systemd-resolved",
+			},
+		},
+		{
+			name:       "systemd-resolved without resolv.conf",
+			resolved:   true,
+			resolvectl: "Global: 1.1.1.1\n",
+			expected: &model.DNSConfig{
+				Nameservers:    []string{"1.1.1.1"},
+				Implementation: "systemd-resolved",
+			},
+		},
+		{
+			name:        "missing resolv.conf",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := interfaces.NewMockFileSystem()
+			mockCommander := interfaces.NewMockCommander()
+			if !tt.resolved {
+				mockCommander.CommandErrors["systemctl is-active --quiet systemd-resolved"] = errors.New("inactive")
+			}
+			if !tt.resolvconf {
+				mockCommander.CommandErrors["which resolvconf"] = errors.New("not found")
+			}
+			if tt.resolvectl != "" {
+				mockCommander.CommandOutputs["resolvectl dns"] = []byte(tt.resolvectl)
+			}
+			if tt.resolvConf != "" {
+				mockFS.Files["/etc/resolv.conf"] = []byte(tt.resolvConf)
+			}
+			repo := secondary.NewFileDNSRepository(mockFS, mockCommander, "debian")
+
+			config, err := repo.GetDNSConfig()
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, config)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// pkg/testing/evidence_repository_test.go
+package testing
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileEvidenceRepository_WriteBundle(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	repo := secondary.NewFileEvidenceRepository(mockFS, interfaces.NewMockCommander())
+
+	items := []model.EvidenceItem{
+		{Path: model.EvidenceManifestName, Data: []byte(`{"format":1}`)},
+		{Path: "config/etc/ssh/sshd_config", Kind: model.EvidenceConfig, Data: []byte("PermitRootLogin no\n")},
+	}
+	require.NoError(t, repo.WriteBundle("/root/evidence.tar.gz", items))
+
+	gz, err := gzip.NewReader(bytes.NewReader(mockFS.Files["/root/evidence.tar.gz"]))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	contents := make(map[string]string)
+	var names []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, int64(0600), header.Mode)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		names = append(names, header.Name)
+		contents[header.Name] = string(data)
+	}
+
+	assert.Equal(t, []string{model.EvidenceManifestName, "config/etc/ssh/sshd_config"}, names)
+	assert.Equal(t, "PermitRootLogin no\n", contents["config/etc/ssh/sshd_config"])
+}
+
+func TestFileEvidenceRepository_ListBackups(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewFileEvidenceRepository(mockFS, mockCommander)
+
+	// A missing backup directory has no backups
+	backups, err := repo.ListBackups("/var/backups/hardn")
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+
+	require.NoError(t, mockFS.MkdirAll("/var/backups/hardn", 0755))
+	mockFS.Files["/var/backups/hardn/2026-01-01/sshd_config.120000.bak"] = []byte("abc")
+	mockFS.Files["/var/backups/hardn/2026-01-01/resolv.conf.090000.bak"] = []byte("nameserver 1.1.1.1\n")
+	mockCommander.CommandOutputs["find /var/backups/hardn -type f"] = []byte(
+		"/var/backups/hardn/2026-01-01/sshd_config.120000.bak\n/var/backups/hardn/2026-01-01/resolv.conf.090000.bak\n")
+
+	backups, err = repo.ListBackups("/var/backups/hardn")
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, "/var/backups/hardn/2026-01-01/resolv.conf.090000.bak", backups[0].Path)
+	assert.Equal(t, int64(3), backups[1].Size)
+	assert.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", backups[1].SHA256)
+}
+
+func TestFileEvidenceRepository_LoadTemplate(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/etc/hardn/soc2.yml"] = []byte(`name: soc2
+sections:
+  - id: CC6.1
+    title: Logical access security
+    include: [config:ssh, audit]
+`)
+	repo := secondary.NewFileEvidenceRepository(mockFS, interfaces.NewMockCommander())
+
+	template, err := repo.LoadTemplate("/etc/hardn/soc2.yml")
+	require.NoError(t, err)
+	assert.Equal(t, "soc2", template.Name)
+	require.Len(t, template.Sections, 1)
+	assert.Equal(t, []string{"config:ssh", "audit"}, template.Sections[0].Include)
+
+	_, err = repo.LoadTemplate("/etc/hardn/missing.yml")
+	assert.Error(t, err)
+}
+
+func TestFileEvidenceRepository_EncryptBundle(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/root/evidence.tar.gz"] = []byte("bundle")
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewFileEvidenceRepository(mockFS, mockCommander)
+
+	encrypted, err := repo.EncryptBundle("/root/evidence.tar.gz", "auditor@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "/root/evidence.tar.gz.gpg", encrypted)
+	assert.Contains(t, mockCommander.ExecutedCommands, "gpg --batch --yes --trust-model always "+
+		"--recipient auditor@example.com --output /root/evidence.tar.gz.gpg --encrypt /root/evidence.tar.gz")
+	assert.NotContains(t, mockFS.Files, "/root/evidence.tar.gz")
+
+	// The unencrypted bundle is removed when encryption fails too
+	mockFS.Files["/root/evidence.tar.gz"] = []byte("bundle")
+	mockCommander.CommandErrors["gpg --batch --yes --trust-model always "+
+		"--recipient nobody --output /root/evidence.tar.gz.gpg --encrypt /root/evidence.tar.gz"] = errors.New("exit status 2")
+	_, err = repo.EncryptBundle("/root/evidence.tar.gz", "nobody")
+	assert.Error(t, err)
+	assert.NotContains(t, mockFS.Files, "/root/evidence.tar.gz")
+}
@@ -0,0 +1,72 @@
+// pkg/testing/explain_test.go
+package testing
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExplain_CoversAuditControls checks every control an audit can report
+// has an explanation, so new controls cannot ship without one
+func TestExplain_CoversAuditControls(t *testing.T) {
+	statuses := []*security.SecurityStatus{
+		{
+			MAC:             &model.MACStatus{Framework: model.MACAppArmor, Mode: model.MACModeEnforcing},
+			WebServers:      []model.WebServer{{Name: "nginx"}, {Name: "caddy"}},
+			Databases:       []model.DatabaseServer{{Name: "postgresql", Instance: "16/main"}, {Name: "mysql"}},
+			Certificates:    &model.CertificateExpiry{Checked: 2, ThresholdDays: 30},
+			ModuleBlacklist: &model.ModuleBlacklistStatus{Missing: []string{"udf"}},
+			BootHardening:   &model.BootHardeningStatus{Pending: []string{"slab_nomerge"}},
+			Clock:           &model.TimeSyncStatus{Daemon: model.TimeSyncChrony, Synchronized: true},
+			LogShipping:     &model.LogShippingStatus{Method: model.LogShippingRsyslog, Active: true},
+		},
+		{MAC: &model.MACStatus{Framework: model.MACSELinux, Mode: model.MACModePermissive}},
+	}
+
+	for _, status := range statuses {
+		for _, control := range security.BuildAuditControls(status) {
+			checks := security.LookupChecks(control.ID)
+			require.Len(t, checks, 1, control.ID)
+			assert.True(t, checks[0].Audited, control.ID)
+			assert.NotEmpty(t, checks[0].Inspects, control.ID)
+			assert.NotEmpty(t, checks[0].Why, control.ID)
+			assert.NotEmpty(t, checks[0].Remediation, control.ID)
+			assert.NotContains(t, control.Title, "<", control.ID)
+		}
+	}
+}
+
+func TestBuildAuditControls_InstanceTitles(t *testing.T) {
+	status := &security.SecurityStatus{
+		Databases:    []model.DatabaseServer{{Name: "postgresql", Instance: "16/main"}},
+		Certificates: &model.CertificateExpiry{Checked: 1, ThresholdDays: 21},
+	}
+
+	titles := make(map[string]string)
+	for _, control := range security.BuildAuditControls(status) {
+		titles[control.ID] = control.Title
+	}
+	assert.Equal(t, "Database baseline applied to postgresql 16/main", titles["database.postgresql.16_main.baseline"])
+	assert.Equal(t, "No certificates expiring within 21 days", titles["certificates.not_expiring"])
+	assert.Equal(t, "SSH root login disabled", titles["ssh.root_login_disabled"])
+}
+
+func TestLookupChecks(t *testing.T) {
+	// Status labels match case-insensitively and may cover several checks
+	checks := security.LookupChecks("firewall")
+	require.Len(t, checks, 2)
+	assert.Equal(t, "firewall.enabled", checks[0].ID)
+	assert.Equal(t, "firewall.default_deny", checks[1].ID)
+
+	checks = security.LookupChecks("webserver.nginx.tls_baseline")
+	require.Len(t, checks, 1)
+	assert.Equal(t, "webserver.<server>.tls_baseline", checks[0].ID)
+
+	assert.Len(t, security.LookupChecks("ssh port"), 1)
+	assert.Empty(t, security.LookupChecks("webserver..tls"))
+	assert.Empty(t, security.LookupChecks("unknown.check"))
+}
@@ -0,0 +1,236 @@
+// pkg/testing/firewalld_repository_test.go
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFirewalldCommander reports firewalld as installed, and running unless
+// running is false
+func newFirewalldCommander(running bool) *interfaces.MockCommander {
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandOutputs["which firewall-cmd"] = []byte("/usr/bin/firewall-cmd\n")
+	if running {
+		mockCommander.CommandOutputs["firewall-cmd --state"] = []byte("running\n")
+		mockCommander.CommandOutputs["firewall-cmd --permanent --get-default-zone"] = []byte("public\n")
+	} else {
+		mockCommander.CommandErrors["firewall-cmd --state"] = errors.New("exit status 252")
+		mockCommander.CommandOutputs["firewall-offline-cmd --get-default-zone"] = []byte("public\n")
+	}
+	return mockCommander
+}
+
+func TestParseFirewalldRichRule(t *testing.T) {
+	tests := []struct {
+		rule     string
+		expected string
+		ok       bool
+	}{
+		{`rule family="ipv4" source address="10.0.0.0/8" port port="22" protocol="tcp" accept`, "allow 22/tcp from 10.0.0.0/8", true},
+		{`rule port port="8080" protocol="tcp" drop`, "deny 8080/tcp", true},
+		{`rule family="ipv4" port port="22" protocol="tcp" accept limit value="10/m"`, "limit 22/tcp", true},
+		{`rule family="ipv6" source address="fd00::/8" port port="60000-61000" protocol="udp" reject`, "reject 60000:61000/udp from fd00::/8", true},
+		{`rule family="ipv4" source NOT address="10.0.0.0/8" port port="22" protocol="tcp" accept`, "", false},
+		{`rule family="ipv4" source address="10.0.0.0/8" service name="http" accept`, "", false},
+		{"", "", false},
+	}
+
+	for _, tc := range tests {
+		rule, ok := secondary.ParseFirewalldRichRule(tc.rule)
+		assert.Equal(t, tc.ok, ok, tc.rule)
+		assert.Equal(t, tc.expected, rule, tc.rule)
+	}
+}
+
+func TestParseFirewalldRichRuleService(t *testing.T) {
+	assert.Equal(t, "hardn-web", secondary.ParseFirewalldRichRuleService(`rule family="ipv4" service name="hardn-web" accept`))
+	assert.Equal(t, "", secondary.ParseFirewalldRichRuleService(`rule family="ipv4" service name="hardn-web" drop`))
+	assert.Equal(t, "", secondary.ParseFirewalldRichRuleService(`rule family="ipv4" source NOT address="10.0.0.0/8" service name="ssh" accept`))
+	assert.Equal(t, "", secondary.ParseFirewalldRichRuleService(`rule port port="22" protocol="tcp" accept`))
+}
+
+func TestFirewalldFirewallRepository_SetIPv6Kernel(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[secondary.GrubDefaultsPath] = []byte("GRUB_CMDLINE_LINUX=\"crashkernel=auto rhgb quiet\"\n")
+	mockCommander := newFirewalldCommander(true)
+	repo := secondary.NewFirewalldFirewallRepository(mockFS, mockCommander, "rocky")
+
+	require.NoError(t, repo.SetIPv6Kernel(true, true))
+	assert.Contains(t, mockCommander.ExecutedCommands, "grubby --update-kernel=ALL --args=ipv6.disable=1")
+
+	require.NoError(t, repo.SetIPv6Kernel(false, false))
+	assert.Contains(t, mockCommander.ExecutedCommands, "grubby --update-kernel=ALL --remove-args=ipv6.disable=1")
+
+	delete(mockFS.Files, secondary.GrubDefaultsPath)
+	assert.Error(t, repo.SetIPv6Kernel(true, true))
+}
+
+func TestFirewalldFirewallRepository_GetFirewallStatus(t *testing.T) {
+	mockCommander := newFirewalldCommander(true)
+	mockCommander.CommandOutputs["firewall-cmd --permanent --zone=public --get-target"] = []byte("default\n")
+	mockCommander.CommandOutputs["firewall-cmd --permanent --zone=public --list-ports"] = []byte("8443/tcp\n")
+	mockCommander.CommandOutputs["firewall-cmd --permanent --zone=public --list-services"] = []byte("cockpit dhcpv6-client ssh\n")
+	mockCommander.CommandOutputs["firewall-cmd --permanent --service=cockpit --get-ports"] = []byte("9090/tcp\n")
+	mockCommander.CommandOutputs["firewall-cmd --permanent --service=dhcpv6-client --get-ports"] = []byte("546/udp\n")
+	mockCommander.CommandOutputs["firewall-cmd --permanent --service=ssh --get-ports"] = []byte("22/tcp\n")
+	mockCommander.CommandOutputs["firewall-cmd --permanent --zone=public --list-rich-rules"] =
+		[]byte(`rule family="ipv4" source address="192.168.1.0/24" port port="5432" protocol="tcp" accept` + "\n")
+
+	repo := secondary.NewFirewallRepository(interfaces.NewMockFileSystem(), mockCommander, "rocky")
+	installed, enabled, configured, rules, err := repo.GetFirewallStatus()
+
+	require.NoError(t, err)
+	assert.True(t, installed)
+	assert.True(t, enabled)
+	assert.True(t, configured)
+	assert.Equal(t, []string{
+		"allow 8443/tcp",
+		"allow 9090/tcp",
+		"allow 546/udp",
+		"allow 22/tcp",
+		"allow 5432/tcp from 192.168.1.0/24",
+	}, rules)
+}
+
+// TestFirewalldFirewallRepository_GetStackRules checks rich rules limited
+// to a family only count for that family
+func TestFirewalldFirewallRepository_GetStackRules(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[secondary.IfInet6Path] = []byte("20010db8000000000000000000000001 02 40 00 80     eth0\n")
+
+	mockCommander := newFirewalldCommander(true)
+	mockCommander.CommandOutputs["firewall-cmd --permanent --zone=public --get-target"] = []byte("default\n")
+	mockCommander.CommandOutputs["firewall-cmd --permanent --zone=public --list-ports"] = []byte("22/tcp\n")
+	mockCommander.CommandOutputs["firewall-cmd --permanent --zone=public --list-rich-rules"] = []byte(
+		`rule family="ipv4" port port="8443" protocol="tcp" accept` + "\n" +
+			`rule family="ipv6" source address="fd00::/8" port port="5432" protocol="tcp" accept` + "\n")
+
+	repo := secondary.NewFirewalldFirewallRepository(mockFS, mockCommander, "fedora")
+	ipv4, ipv6, err := repo.GetStackRules()
+
+	require.NoError(t, err)
+	assert.True(t, ipv4.Filtered)
+	assert.True(t, ipv6.Filtered)
+	assert.True(t, ipv6.Active)
+	assert.Equal(t, []string{"allow 22/tcp", "allow 8443/tcp"}, ipv4.Allowed)
+	assert.Equal(t, []string{"allow 22/tcp", "allow 5432/tcp from fd00::/8"}, ipv6.Allowed)
+
+	require.NoError(t, repo.MirrorRule("allow 8443/tcp", model.FamilyIPv6))
+	assert.Contains(t, mockCommander.ExecutedCommands,
+		`firewall-cmd --permanent --zone=public --add-rich-rule=rule family="ipv6" port port="8443" protocol="tcp" accept`)
+	assert.Error(t, repo.FilterFamily(model.FamilyIPv6))
+}
+
+func TestFirewalldFirewallRepository_AcceptingZone(t *testing.T) {
+	mockCommander := newFirewalldCommander(true)
+	mockCommander.CommandOutputs["firewall-cmd --permanent --zone=public --get-target"] = []byte("ACCEPT\n")
+
+	repo := secondary.NewFirewalldFirewallRepository(interfaces.NewMockFileSystem(), mockCommander, "fedora")
+	_, enabled, configured, _, err := repo.GetFirewallStatus()
+
+	require.NoError(t, err)
+	assert.True(t, enabled)
+	assert.False(t, configured)
+}
+
+func TestFirewalldFirewallRepository_AddRule(t *testing.T) {
+	mockCommander := newFirewalldCommander(true)
+	repo := secondary.NewFirewalldFirewallRepository(interfaces.NewMockFileSystem(), mockCommander, "almalinux")
+
+	require.NoError(t, repo.AddRule(model.FirewallRule{Action: "allow", Protocol: "tcp", Port: 2222}))
+	require.NoError(t, repo.AddRule(model.FirewallRule{Action: "deny", Protocol: "tcp", Port: 5432, SourceIP: "10.0.0.0/8"}))
+	require.NoError(t, repo.AddRule(model.FirewallRule{Action: "allow", Protocol: "udp", Port: 60000, EndPort: 61000}))
+	require.NoError(t, repo.AddRule(model.FirewallRule{Action: "limit", Protocol: "tcp", Port: 22}))
+	require.NoError(t, repo.AddRule(model.FirewallRule{Action: "allow", Protocol: "udp", Port: 51820, Family: model.FamilyIPv6}))
+
+	assert.Contains(t, mockCommander.ExecutedCommands, "firewall-cmd --permanent --zone=public --add-port=2222/tcp")
+	assert.Contains(t, mockCommander.ExecutedCommands,
+		`firewall-cmd --permanent --zone=public --add-rich-rule=rule family="ipv4" source address="10.0.0.0/8" port port="5432" protocol="tcp" drop`)
+	assert.Contains(t, mockCommander.ExecutedCommands, "firewall-cmd --permanent --zone=public --add-port=60000-61000/udp")
+	assert.Contains(t, mockCommander.ExecutedCommands,
+		`firewall-cmd --permanent --zone=public --add-rich-rule=rule port port="22" protocol="tcp" accept limit value="10/m"`)
+	assert.Contains(t, mockCommander.ExecutedCommands,
+		`firewall-cmd --permanent --zone=public --add-rich-rule=rule family="ipv6" port port="51820" protocol="udp" accept`)
+	assert.Contains(t, mockCommander.ExecutedCommands, "firewall-cmd --reload")
+
+	assert.Equal(t,
+		`firewall-cmd --permanent --zone=public '--add-rich-rule=rule family="ipv4" source address="10.0.0.0/8" port port="5432" protocol="tcp" drop'`,
+		repo.RuleCommand(model.FirewallRule{Action: "deny", Protocol: "tcp", Port: 5432, SourceIP: "10.0.0.0/8"}))
+}
+
+// TestFirewalldFirewallRepository_GetRules checks the rules come from the
+// zone's ports, hardn's services and rich rules, leaving out the services
+// firewalld ships
+func TestFirewalldFirewallRepository_GetRules(t *testing.T) {
+	mockCommander := newFirewalldCommander(true)
+	mockCommander.CommandOutputs["firewall-cmd --permanent --zone=public --list-ports"] = []byte("2222/tcp 60000-61000/udp\n")
+	mockCommander.CommandOutputs["firewall-cmd --permanent --zone=public --list-services"] = []byte("ssh dhcpv6-client hardn-labhttps\n")
+	mockCommander.CommandOutputs["firewall-cmd --permanent --service=hardn-labhttps --get-ports"] = []byte("30443/tcp 2222/tcp\n")
+	mockCommander.CommandOutputs["firewall-cmd --permanent --zone=public --list-rich-rules"] = []byte(
+		`rule family="ipv4" source address="10.0.0.0/8" port port="5432" protocol="tcp" drop` + "\n")
+
+	repo := secondary.NewFirewalldFirewallRepository(interfaces.NewMockFileSystem(), mockCommander, "rocky")
+	rules, err := repo.GetRules()
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"allow 2222/tcp",
+		"allow 60000:61000/udp",
+		"allow 30443/tcp",
+		"deny 5432/tcp from 10.0.0.0/8",
+	}, rules)
+	assert.NotContains(t, mockCommander.ExecutedCommands, "firewall-cmd --permanent --service=ssh --get-ports")
+}
+
+// TestFirewalldFirewallRepository_Offline checks changes go through
+// firewall-offline-cmd, without a reload, when firewalld is stopped
+func TestFirewalldFirewallRepository_Offline(t *testing.T) {
+	mockCommander := newFirewalldCommander(false)
+	repo := secondary.NewFirewalldFirewallRepository(interfaces.NewMockFileSystem(), mockCommander, "rocky")
+
+	require.NoError(t, repo.AddRule(model.FirewallRule{Action: "allow", Protocol: "tcp", Port: 22}))
+
+	assert.Contains(t, mockCommander.ExecutedCommands, "firewall-offline-cmd --zone=public --add-port=22/tcp")
+	assert.NotContains(t, mockCommander.ExecutedCommands, "firewall-cmd --reload")
+	assert.Equal(t, "firewall-offline-cmd --zone=public --add-port=22/tcp",
+		repo.RuleCommand(model.FirewallRule{Action: "allow", Protocol: "tcp", Port: 22}))
+}
+
+func TestFirewalldFirewallRepository_ApplyProfiles(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := newFirewalldCommander(true)
+	repo := secondary.NewFirewalldFirewallRepository(mockFS, mockCommander, "rocky")
+
+	err := repo.ApplyProfiles([]model.FirewallProfile{{
+		Name:        "LabHTTPS",
+		Title:       "Lab Web Server (HTTPS)",
+		Description: "Ports <30443> & 30080",
+		Ports:       []string{"30443/tcp", "6000:6007/udp", "5353"},
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, `<?xml version="1.0" encoding="utf-8"?>
+<service>
+  <short>Lab Web Server (HTTPS)</short>
+  <description>Ports &lt;30443&gt; &amp; 30080</description>
+  <port protocol="tcp" port="30443"/>
+  <port protocol="udp" port="6000-6007"/>
+  <port protocol="tcp" port="5353"/>
+  <port protocol="udp" port="5353"/>
+</service>
+`, string(mockFS.Files["/etc/firewalld/services/hardn-labhttps.xml"]))
+	assert.Contains(t, mockCommander.ExecutedCommands, "firewall-cmd --permanent --zone=public --add-service=hardn-labhttps")
+}
+
+func TestNewFirewallRepository_UFWElsewhere(t *testing.T) {
+	repo := secondary.NewFirewallRepository(interfaces.NewMockFileSystem(), interfaces.NewMockCommander(), "debian")
+	_, ok := repo.(*secondary.UFWFirewallRepository)
+	assert.True(t, ok)
+}
@@ -0,0 +1,155 @@
+// pkg/testing/host_environment_test.go
+package testing
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyEnvironment(t *testing.T) {
+	tests := []struct {
+		name  string
+		probe osdetect.EnvironmentProbe
+		want  model.HostEnvironment
+	}{
+		{
+			name:  "bare metal",
+			probe: osdetect.EnvironmentProbe{SysVendor: "Dell Inc.", ProductName: "PowerEdge R640", UIDMap: "0 0 4294967295"},
+			want:  model.HostEnvironment{Kind: model.EnvBareMetal},
+		},
+		{
+			name:  "KVM guest",
+			probe: osdetect.EnvironmentProbe{SysVendor: "QEMU", ProductName: "Standard PC (Q35 + ICH9, 2009)", CPUHypervisor: true},
+			want:  model.HostEnvironment{Kind: model.EnvVM, Hypervisor: "kvm"},
+		},
+		{
+			name: "Proxmox VM with OVMF",
+			probe: osdetect.EnvironmentProbe{SysVendor: "QEMU", ProductName: "Standard PC (i440FX + PIIX, 1996)",
+				BIOSVendor: "Proxmox distribution of EDK II", CPUHypervisor: true},
+			want: model.HostEnvironment{Kind: model.EnvVM, Hypervisor: "kvm", ProxmoxGuest: true},
+		},
+		{
+			name:  "VMware guest",
+			probe: osdetect.EnvironmentProbe{SysVendor: "VMware, Inc.", ProductName: "VMware Virtual Platform"},
+			want:  model.HostEnvironment{Kind: model.EnvVM, Hypervisor: "vmware"},
+		},
+		{
+			name:  "unknown hypervisor",
+			probe: osdetect.EnvironmentProbe{CPUHypervisor: true},
+			want:  model.HostEnvironment{Kind: model.EnvVM, Hypervisor: "other"},
+		},
+		{
+			name: "unprivileged Proxmox LXC container",
+			probe: osdetect.EnvironmentProbe{ContainerEnv: "lxc", UIDMap: "0     100000      65536",
+				SysVendor: "Supermicro", PVEHosts: true},
+			want: model.HostEnvironment{Kind: model.EnvLXC, Unprivileged: true, ProxmoxGuest: true},
+		},
+		{
+			name:  "privileged LXC container seen by systemd",
+			probe: osdetect.EnvironmentProbe{SystemdContainer: "lxc", UIDMap: "0 0 4294967295"},
+			want:  model.HostEnvironment{Kind: model.EnvLXC},
+		},
+		{
+			name:  "Docker container in a VM",
+			probe: osdetect.EnvironmentProbe{DockerEnv: true, SysVendor: "QEMU", CPUHypervisor: true, UIDMap: "0 0 4294967295"},
+			want:  model.HostEnvironment{Kind: model.EnvDocker},
+		},
+		{
+			name:  "Docker container found by cgroup",
+			probe: osdetect.EnvironmentProbe{Cgroup: "12:pids:/docker/3f4e5d6c\n0::/docker/3f4e5d6c"},
+			want:  model.HostEnvironment{Kind: model.EnvDocker},
+		},
+		{
+			name:  "podman container",
+			probe: osdetect.EnvironmentProbe{ContainerEnv: "podman", UIDMap: "0 1000 1"},
+			want:  model.HostEnvironment{Kind: model.EnvContainer, Unprivileged: true},
+		},
+		{
+			name:  "WSL",
+			probe: osdetect.EnvironmentProbe{WSLVersion: 2, CPUHypervisor: true, SysVendor: "Microsoft Corporation"},
+			want:  model.HostEnvironment{Kind: model.EnvWSL},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, osdetect.ClassifyEnvironment(tt.probe))
+		})
+	}
+}
+
+func TestHostEnvironment_String(t *testing.T) {
+	assert.Equal(t, "bare metal", model.HostEnvironment{Kind: model.EnvBareMetal}.String())
+	assert.Equal(t, "KVM virtual machine (Proxmox guest)",
+		model.HostEnvironment{Kind: model.EnvVM, Hypervisor: "kvm", ProxmoxGuest: true}.String())
+	assert.Equal(t, "unprivileged LXC container", model.HostEnvironment{Kind: model.EnvLXC, Unprivileged: true}.String())
+	assert.Equal(t, "Docker container", model.HostEnvironment{Kind: model.EnvDocker}.String())
+	assert.Equal(t, "unknown", model.HostEnvironment{}.String())
+}
+
+func TestParseEnvironmentPlan(t *testing.T) {
+	detected := model.HostEnvironment{Kind: model.EnvVM, Hypervisor: "kvm"}
+
+	env, tuned := model.ParseEnvironmentPlan(model.EnvironmentPlanAuto, detected)
+	assert.True(t, tuned)
+	assert.Equal(t, detected, env)
+
+	_, tuned = model.ParseEnvironmentPlan(model.EnvironmentPlanNone, detected)
+	assert.False(t, tuned)
+
+	env, tuned = model.ParseEnvironmentPlan("unprivileged-lxc", detected)
+	assert.True(t, tuned)
+	assert.Equal(t, model.HostEnvironment{Kind: model.EnvLXC, Unprivileged: true}, env)
+
+	env, _ = model.ParseEnvironmentPlan("docker", detected)
+	assert.Equal(t, model.EnvDocker, env.Kind)
+}
+
+func TestHardeningConfig_ApplyEnvironmentPlan(t *testing.T) {
+	newConfig := func() model.HardeningConfig {
+		return model.HardeningConfig{
+			CreateUser:               true,
+			EnableFirewall:           true,
+			EnableAppArmor:           true,
+			EnableUnattendedUpgrades: true,
+			NeedrestartMode:          "a",
+			IPv6:                     model.IPv6Policy{Mode: model.IPv6Disable, BootParameter: true},
+			ModuleBlacklist:          &model.ModuleBlacklistPolicy{},
+		}
+	}
+
+	// VMs and bare metal run every step
+	for _, env := range []model.HostEnvironment{{Kind: model.EnvBareMetal}, {Kind: model.EnvVM, Hypervisor: "kvm"}} {
+		cfg := newConfig()
+		assert.Empty(t, cfg.ApplyEnvironmentPlan(env))
+		assert.Equal(t, newConfig(), cfg)
+	}
+
+	// Privileged LXC keeps AppArmor and the firewall
+	cfg := newConfig()
+	skipped := cfg.ApplyEnvironmentPlan(model.HostEnvironment{Kind: model.EnvLXC})
+	assert.Len(t, skipped, 3)
+	assert.Nil(t, cfg.ModuleBlacklist)
+	assert.False(t, cfg.IPv6.BootParameter, "no GRUB changes in containers")
+	assert.Equal(t, model.IPv6Disable, cfg.IPv6.Mode)
+	assert.Empty(t, cfg.NeedrestartMode)
+	assert.True(t, cfg.EnableAppArmor)
+	assert.True(t, cfg.EnableFirewall)
+
+	cfg = newConfig()
+	cfg.ApplyEnvironmentPlan(model.HostEnvironment{Kind: model.EnvLXC, Unprivileged: true})
+	assert.False(t, cfg.EnableAppArmor)
+	assert.True(t, cfg.EnableFirewall)
+	assert.True(t, cfg.EnableUnattendedUpgrades)
+
+	cfg = newConfig()
+	skipped = cfg.ApplyEnvironmentPlan(model.HostEnvironment{Kind: model.EnvDocker})
+	assert.Len(t, skipped, 6)
+	assert.Contains(t, skipped, "firewall (the container runtime filters traffic)")
+	assert.False(t, cfg.EnableFirewall)
+	assert.False(t, cfg.EnableUnattendedUpgrades)
+	assert.True(t, cfg.CreateUser)
+}
@@ -0,0 +1,241 @@
+// pkg/testing/log_shipping_repository_test.go
+package testing
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCA signs the certificates of a log collector test
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+// newTestCA creates a CA valid for a day
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Log CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM certificate and key for name, an IP address or host
+// name, valid until notAfter
+func (ca *testCA) issue(t *testing.T, name string, notAfter time.Time) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if ip := net.ParseIP(name); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{name}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// startCollector accepts TLS connections on 127.0.0.1 with a certificate
+// of ca and returns its port
+func startCollector(t *testing.T, ca *testCA) int {
+	certPEM, keyPEM := ca.issue(t, "127.0.0.1", time.Now().Add(time.Hour))
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{pair}})
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestLogShippingTLSConfig(t *testing.T) {
+	ca := newTestCA(t)
+	now := time.Now()
+	cert, key := ca.issue(t, "web1", now.Add(time.Hour))
+
+	config, err := secondary.LogShippingTLSConfig("logs.example.com", ca.pem, cert, key, now)
+	require.NoError(t, err)
+	assert.Equal(t, "logs.example.com", config.ServerName)
+	assert.Len(t, config.Certificates, 1)
+
+	// The CA alone is enough for rsyslog
+	config, err = secondary.LogShippingTLSConfig("logs.example.com", ca.pem, nil, nil, now)
+	require.NoError(t, err)
+	assert.Empty(t, config.Certificates)
+
+	_, otherKey := ca.issue(t, "web2", now.Add(time.Hour))
+	_, err = secondary.LogShippingTLSConfig("logs.example.com", ca.pem, cert, otherKey, now)
+	assert.Error(t, err, "the key does not match the certificate")
+
+	_, err = secondary.LogShippingTLSConfig("logs.example.com", ca.pem, cert, key, now.Add(2*time.Hour))
+	assert.ErrorContains(t, err, "client certificate expired")
+
+	_, err = secondary.LogShippingTLSConfig("logs.example.com", []byte("not a certificate"), nil, nil, now)
+	assert.ErrorContains(t, err, "no CA certificate")
+}
+
+func TestOSLogShippingRepository_CheckConnection(t *testing.T) {
+	ca := newTestCA(t)
+	port := startCollector(t, ca)
+	cert, key := ca.issue(t, "web1", time.Now().Add(time.Hour))
+
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/root/pki/ca.pem"] = ca.pem
+	mockFS.Files["/root/pki/web1.pem"] = cert
+	mockFS.Files["/root/pki/web1.key"] = key
+	repo := secondary.NewOSLogShippingRepository(mockFS, interfaces.NewMockCommander(), "debian")
+
+	policy := model.LogShippingPolicy{
+		Method:   model.LogShippingRsyslog,
+		Host:     "127.0.0.1",
+		Port:     port,
+		CAFile:   "/root/pki/ca.pem",
+		CertFile: "/root/pki/web1.pem",
+		KeyFile:  "/root/pki/web1.key",
+	}
+	assert.NoError(t, repo.CheckConnection(policy))
+
+	// A collector signed by another CA is refused
+	mockFS.Files["/root/pki/ca.pem"] = newTestCA(t).pem
+	assert.ErrorContains(t, repo.CheckConnection(policy), "failed to connect to 127.0.0.1:"+strconv.Itoa(port))
+}
+
+func TestOSLogShippingRepository_ConfigureRsyslogUbuntu(t *testing.T) {
+	ca := newTestCA(t)
+	cert, key := ca.issue(t, "web1", time.Now().Add(time.Hour))
+
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/root/pki/ca.pem"] = ca.pem
+	mockFS.Files["/root/pki/web1.pem"] = cert
+	mockFS.Files["/root/pki/web1.key"] = key
+	commander := interfaces.NewMockCommander()
+	for _, name := range []string{"rsyslog", "rsyslog-gnutls"} {
+		commander.CommandOutputs["dpkg-query -W -f="+secondary.DpkgStatusFormat+" "+name] = []byte("install ok installed")
+	}
+	repo := secondary.NewOSLogShippingRepository(mockFS, commander, "ubuntu")
+
+	policy := model.LogShippingPolicy{
+		Method:   model.LogShippingRsyslog,
+		Host:     "logs.example.com",
+		Port:     model.DefaultRsyslogTLSPort,
+		CAFile:   "/root/pki/ca.pem",
+		CertFile: "/root/pki/web1.pem",
+		KeyFile:  "/root/pki/web1.key",
+	}
+	require.NoError(t, repo.ConfigureLogShipping(policy))
+
+	conf := string(mockFS.Files[model.RsyslogForwardConfigPath])
+	assert.Equal(t, secondary.RsyslogForwardConfig("logs.example.com", 6514, true), conf)
+	assert.Contains(t, conf, `StreamDriverPermittedPeers="logs.example.com"`)
+	assert.Contains(t, conf, `DefaultNetstreamDriverKeyFile="/etc/hardn/log-shipping/client.key"`)
+	assert.Equal(t, "logs.example.com:6514", secondary.ParseRsyslogForward(conf))
+
+	assert.Equal(t, key, mockFS.Files["/etc/hardn/log-shipping/client.key"])
+	assert.Equal(t, ca.pem, mockFS.Files["/etc/hardn/log-shipping/ca.pem"])
+	assert.NotContains(t, commander.ExecutedCommands, "apt-get install -y rsyslog-gnutls")
+	assert.Equal(t, []string{
+		"chgrp syslog /etc/hardn/log-shipping/client.key",
+		"rsyslogd -N1",
+		"systemctl enable --now rsyslog",
+		"systemctl restart rsyslog",
+	}, commander.ExecutedCommands[len(commander.ExecutedCommands)-4:])
+}
+
+func TestOSLogShippingRepository_RsyslogRejected(t *testing.T) {
+	ca := newTestCA(t)
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/root/pki/ca.pem"] = ca.pem
+	commander := interfaces.NewMockCommander()
+	commander.CommandErrors["rsyslogd -N1"] = errors.New("exit status 1")
+	repo := secondary.NewOSLogShippingRepository(mockFS, commander, "debian")
+
+	err := repo.ConfigureLogShipping(model.LogShippingPolicy{
+		Method: model.LogShippingRsyslog,
+		Host:   "logs.example.com",
+		Port:   6514,
+		CAFile: "/root/pki/ca.pem",
+	})
+	assert.ErrorContains(t, err, "rsyslogd rejected")
+	assert.NotContains(t, mockFS.Files, model.RsyslogForwardConfigPath, "the rejected configuration is removed")
+	assert.NotContains(t, commander.ExecutedCommands, "systemctl restart rsyslog")
+}
+
+func TestOSLogShippingRepository_JournaldStatus(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[model.JournalUploadDropInPath] = []byte(secondary.JournalUploadDropIn("logs.example.com:19532"))
+	commander := interfaces.NewMockCommander()
+	repo := secondary.NewOSLogShippingRepository(mockFS, commander, "debian")
+
+	status, err := repo.GetLogShippingStatus()
+	require.NoError(t, err)
+	assert.Equal(t, model.LogShippingJournald, status.Method)
+	assert.Equal(t, "logs.example.com:19532", status.Target)
+	assert.True(t, status.Active)
+	assert.False(t, status.Connected, "ss lists no connection")
+	assert.False(t, status.Shipping())
+
+	commander.CommandOutputs["ss -Htn state established dport = :19532"] = []byte("0 0 10.0.0.5:41234 10.0.0.9:19532\n")
+	status, err = repo.GetLogShippingStatus()
+	require.NoError(t, err)
+	assert.True(t, status.Shipping())
+
+	// Nothing configured
+	status, err = secondary.NewOSLogShippingRepository(interfaces.NewMockFileSystem(), commander, "debian").GetLogShippingStatus()
+	require.NoError(t, err)
+	assert.False(t, status.Configured())
+}
@@ -0,0 +1,179 @@
+// pkg/testing/logging_test.go
+package testing
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink keeps the entries it receives
+type recordingSink struct {
+	entries []logging.Entry
+}
+
+func (s *recordingSink) Write(entry logging.Entry) error {
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *recordingSink) Close() error { return nil }
+
+func TestLogging_LevelsAndSinks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hardn.log")
+	require.NoError(t, logging.Configure(logging.Options{Path: path, Level: "warn", Format: "json"}))
+	defer logging.CloseLogging()
+	defer logging.SetLevel(logging.LevelInfo)
+
+	sink := &recordingSink{}
+	logging.AddSink(sink)
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	logging.LogDebug("hidden")
+	logging.LogInfo("hidden")
+	logging.LogWarning("disk %d%% full", 91)
+	logging.LogError("failed")
+
+	require.Len(t, sink.entries, 2)
+	assert.Equal(t, logging.LevelWarn, sink.entries[0].Level)
+	assert.Equal(t, "WARNING", sink.entries[0].Label)
+	assert.Equal(t, "disk 91% full", sink.entries[0].Message)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[1], `"level":"error","label":"ERROR","message":"failed"`)
+
+	_, err = logging.ParseLevel("verbose")
+	assert.Error(t, err)
+	assert.Error(t, logging.Configure(logging.Options{Path: path, Format: "xml"}))
+}
+
+func TestParseEntry(t *testing.T) {
+	entryTime := time.Date(2026, 5, 4, 3, 2, 1, 0, time.Local)
+	entry := logging.Entry{Time: entryTime, Level: logging.LevelInfo, Label: "SUCCESS", Message: "SSH: configured"}
+
+	for _, format := range []logging.Format{logging.FormatText, logging.FormatJSON} {
+		line := logging.FormatEntry(entry, format)
+		parsed, ok := logging.ParseEntry(line)
+		require.True(t, ok, line)
+		assert.True(t, entryTime.Equal(parsed.Time), line)
+		assert.Equal(t, logging.LevelInfo, parsed.Level, line)
+		assert.Equal(t, "SUCCESS", parsed.Label, line)
+		assert.Equal(t, "SSH: configured", parsed.Message, line)
+	}
+
+	assert.Equal(t, "2026/05/04 03:02:01 SUCCESS: SSH: configured", logging.FormatEntry(entry, logging.FormatText))
+
+	parsed, ok := logging.ParseEntry("2025/01/02 10:00:00 WARNING: low entropy")
+	require.True(t, ok)
+	assert.Equal(t, logging.LevelWarn, parsed.Level)
+
+	_, ok = logging.ParseEntry("not a log line")
+	assert.False(t, ok)
+}
+
+func TestFileSink_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hardn.log")
+	sink, err := logging.NewFileSink(path, logging.FormatText, 100, 2)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	// Each line is 61 bytes, so every entry after the first rotates
+	for _, message := range []string{"first", "second", "third", "fourth"} {
+		entry := logging.Entry{Time: time.Now(), Level: logging.LevelInfo, Label: "INFO",
+			Message: message + strings.Repeat(".", 34-len(message))}
+		require.NoError(t, sink.Write(entry))
+	}
+
+	for suffix, message := range map[string]string{"": "fourth", ".1": "third", ".2": "second"} {
+		data, err := os.ReadFile(path + suffix)
+		require.NoError(t, err, suffix)
+		assert.Contains(t, string(data), message, suffix)
+		assert.Equal(t, 1, strings.Count(string(data), "\n"), suffix)
+	}
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestJournaldMessage(t *testing.T) {
+	message := logging.JournaldMessage(logging.Entry{Level: logging.LevelError, Label: "ERROR", Message: "sshd -t failed"})
+	assert.Equal(t, "MESSAGE=sshd -t failed\nPRIORITY=3\nSYSLOG_IDENTIFIER=hardn\nHARDN_LABEL=ERROR\n", string(message))
+
+	// Multi-line values are sent with their length
+	message = logging.JournaldMessage(logging.Entry{Level: logging.LevelInfo, Label: "INFO", Message: "a\nb"})
+	var expected bytes.Buffer
+	expected.WriteString("MESSAGE\n")
+	_ = binary.Write(&expected, binary.LittleEndian, uint64(3))
+	expected.WriteString("a\nb\nPRIORITY=6\nSYSLOG_IDENTIFIER=hardn\nHARDN_LABEL=INFO\n")
+	assert.Equal(t, expected.Bytes(), message)
+
+	_, err := logging.NewNamedSink("kafka")
+	assert.Error(t, err)
+}
+
+func TestLogging_ModuleDebug(t *testing.T) {
+	sink := &recordingSink{}
+	require.NoError(t, logging.Configure(logging.Options{Level: "info"}))
+	logging.AddSink(sink)
+	defer logging.CloseLogging()
+	logging.SetSilentMode(true)
+	defer logging.SetSilentMode(false)
+
+	modules, err := logging.ParseDebugModules(" Firewall,ssh,,kernel")
+	assert.ErrorContains(t, err, "kernel")
+	assert.Equal(t, []string{logging.ModuleFirewall, logging.ModuleSSH}, modules)
+	logging.SetDebugModules(modules)
+	defer logging.SetDebugModules(nil)
+
+	logging.LogModuleDebug(logging.ModuleFirewall, "run ufw status")
+	logging.LogModuleDebug(logging.ModuleDNS, "hidden")
+	logging.LogDebug("hidden")
+
+	logging.SetModuleDebug(logging.ModuleSSH, false)
+	logging.LogModuleDebug(logging.ModuleSSH, "hidden")
+	assert.Equal(t, []string{logging.ModuleFirewall}, logging.DebugModules())
+
+	require.Len(t, sink.entries, 1)
+	assert.Equal(t, logging.ModuleFirewall, sink.entries[0].Module)
+	assert.Equal(t, "run ufw status", sink.entries[0].Message)
+
+	// HARDN_DEBUG=1 keeps enabling everything
+	modules, err = logging.ParseDebugModules("1")
+	require.NoError(t, err)
+	logging.SetDebugModules(modules)
+	assert.True(t, logging.ModuleDebugEnabled(logging.ModuleVersion))
+	assert.True(t, logging.DebugLogged(logging.ModuleDNS))
+}
+
+func TestParseEntry_Module(t *testing.T) {
+	entry := logging.Entry{Time: time.Date(2026, 5, 4, 3, 2, 1, 0, time.Local), Level: logging.LevelDebug,
+		Label: "DEBUG", Module: logging.ModuleDNS, Message: "write /etc/resolv.conf: ok"}
+
+	for _, format := range []logging.Format{logging.FormatText, logging.FormatJSON} {
+		line := logging.FormatEntry(entry, format)
+		parsed, ok := logging.ParseEntry(line)
+		require.True(t, ok, line)
+		assert.Equal(t, logging.ModuleDNS, parsed.Module, line)
+		assert.Equal(t, "write /etc/resolv.conf: ok", parsed.Message, line)
+	}
+
+	// Brackets that do not name a module stay in the message
+	parsed, ok := logging.ParseEntry("2026/05/04 03:02:01 DEBUG: [DRY-RUN] would restart sshd")
+	require.True(t, ok)
+	assert.Empty(t, parsed.Module)
+	assert.Equal(t, "[DRY-RUN] would restart sshd", parsed.Message)
+
+	message := logging.JournaldMessage(entry)
+	assert.Contains(t, string(message), "HARDN_MODULE=dns\n")
+}
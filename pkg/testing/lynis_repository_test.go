@@ -0,0 +1,59 @@
+// pkg/testing/lynis_repository_test.go
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lynisAuditCommand = "lynis audit system --quiet --no-colors --report-file " + model.LynisReportPath
+
+func TestOSLynisRepository_RunAudit(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSLynisRepository(mockFS, mockCommander)
+
+	// A stale report is removed first, so a scan that writes none fails
+	mockFS.Files[model.LynisReportPath] = []byte("hardening_index=10\n")
+	mockCommander.CommandErrors[lynisAuditCommand] = errors.New("exit status 1")
+	assert.Error(t, repo.RunAudit())
+	assert.NotContains(t, mockFS.Files, model.LynisReportPath)
+
+	report, err := repo.ReadReport()
+	require.NoError(t, err)
+	assert.Nil(t, report)
+}
+
+func TestOSLynisRepository_RunAudit_WarningsExitStatus(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := &reportWritingCommander{MockCommander: interfaces.NewMockCommander(), fs: mockFS}
+	repo := secondary.NewOSLynisRepository(mockFS, mockCommander)
+
+	// Lynis exits non-zero when it has warnings; the report still counts
+	require.NoError(t, repo.RunAudit())
+	report, err := repo.ReadReport()
+	require.NoError(t, err)
+	assert.Equal(t, "hardening_index=72\n", string(report))
+	assert.True(t, repo.IsInstalled())
+}
+
+// reportWritingCommander writes a Lynis report and fails like a scan that
+// found warnings
+type reportWritingCommander struct {
+	*interfaces.MockCommander
+	fs *interfaces.MockFileSystem
+}
+
+func (c *reportWritingCommander) Execute(command string, args ...string) ([]byte, error) {
+	if command == "lynis" {
+		c.fs.Files[model.LynisReportPath] = []byte("hardening_index=72\n")
+		return nil, errors.New("exit status 78")
+	}
+	return c.MockCommander.Execute(command, args...)
+}
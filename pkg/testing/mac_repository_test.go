@@ -0,0 +1,151 @@
+// pkg/testing/mac_repository_test.go
+package testing
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const rockySELinuxConfig = `# This file controls the state of SELinux on the system.
+SELINUX=enforcing
+SELINUXTYPE=targeted
+`
+
+const semanagePorts = `http_port_t                    tcp      80, 81, 443, 488, 8008, 8009, 8443, 9000
+ssh_port_t                     tcp      2222, 22
+ssh_port_t                     udp      22
+`
+
+func TestParseSELinuxConfig(t *testing.T) {
+	mode, policy := secondary.ParseSELinuxConfig([]byte(rockySELinuxConfig))
+	assert.Equal(t, "enforcing", mode)
+	assert.Equal(t, "targeted", policy)
+}
+
+func TestParseSELinuxPorts(t *testing.T) {
+	assert.Equal(t, []string{"2222", "22"}, secondary.ParseSELinuxPorts([]byte(semanagePorts), "ssh_port_t"))
+	assert.Empty(t, secondary.ParseSELinuxPorts([]byte(semanagePorts), "dns_port_t"))
+}
+
+func TestOSMACRepository_GetMACStatus(t *testing.T) {
+	t.Run("SELinux permissive until reboot", func(t *testing.T) {
+		mockFS := interfaces.NewMockFileSystem()
+		mockFS.Files[secondary.LSMListPath] = []byte("lockdown,capability,yama,selinux,bpf\n")
+		mockFS.Files[secondary.SELinuxEnforcePath] = []byte("0")
+		mockFS.Files[secondary.SELinuxConfigPath] = []byte(rockySELinuxConfig)
+		mockCmd := interfaces.NewMockCommander()
+		mockCmd.CommandOutputs["getenforce"] = []byte("Permissive\n")
+
+		status, err := secondary.NewOSMACRepository(mockFS, mockCmd, "rocky").GetMACStatus()
+
+		require.NoError(t, err)
+		assert.Equal(t, model.MACSELinux, status.Framework)
+		assert.Equal(t, model.MACModePermissive, status.Mode)
+		assert.Equal(t, "targeted", status.Policy)
+		assert.True(t, status.RebootRequired)
+		assert.False(t, status.Enforcing())
+	})
+
+	t.Run("AppArmor profiles from the kernel", func(t *testing.T) {
+		mockFS := interfaces.NewMockFileSystem()
+		mockFS.Files[secondary.LSMListPath] = []byte("lockdown,capability,landlock,yama,apparmor\n")
+		mockFS.Files[secondary.AppArmorProfilesPath] = []byte("/usr/sbin/sshd (enforce)\nnvidia_modprobe (complain)\n")
+		mockCmd := interfaces.NewMockCommander()
+		mockCmd.CommandOutputs["dpkg-query -W -f="+secondary.DpkgStatusFormat+" apparmor"] = []byte("install ok installed")
+
+		status, err := secondary.NewOSMACRepository(mockFS, mockCmd, "debian").GetMACStatus()
+
+		require.NoError(t, err)
+		assert.Equal(t, model.MACAppArmor, status.Framework)
+		assert.True(t, status.Enforcing())
+		assert.Equal(t, 1, status.EnforcedProfiles)
+		assert.Equal(t, 1, status.ComplainProfiles)
+	})
+
+	t.Run("no framework", func(t *testing.T) {
+		status, err := secondary.NewOSMACRepository(interfaces.NewMockFileSystem(),
+			interfaces.NewMockCommander(), "debian").GetMACStatus()
+
+		require.NoError(t, err)
+		assert.Empty(t, status.Framework)
+		assert.Equal(t, "none", status.Describe())
+	})
+}
+
+func TestOSMACRepository_EnforceSELinux(t *testing.T) {
+	t.Run("permissive kernel switches right away", func(t *testing.T) {
+		mockFS := interfaces.NewMockFileSystem()
+		mockFS.Files[secondary.SELinuxEnforcePath] = []byte("0")
+		mockFS.Files[secondary.SELinuxConfigPath] = []byte("SELINUX=permissive\nSELINUXTYPE=targeted\n")
+		mockCmd := interfaces.NewMockCommander()
+
+		repo := secondary.NewOSMACRepository(mockFS, mockCmd, "almalinux")
+		require.NoError(t, repo.InstallMAC(model.MACSELinux))
+		require.NoError(t, repo.EnforceMAC(model.MACSELinux))
+
+		assert.Equal(t, "SELINUX=enforcing\nSELINUXTYPE=targeted\n", string(mockFS.Files[secondary.SELinuxConfigPath]))
+		assert.Contains(t, mockCmd.ExecutedCommands,
+			"dnf install -y selinux-policy-targeted policycoreutils policycoreutils-python-utils")
+		assert.Contains(t, mockCmd.ExecutedCommands, "setenforce 1")
+		assert.NotContains(t, mockFS.Files, "/.autorelabel")
+	})
+
+	t.Run("Debian is activated and relabeled at boot", func(t *testing.T) {
+		mockFS := interfaces.NewMockFileSystem()
+		mockCmd := interfaces.NewMockCommander()
+
+		require.NoError(t, secondary.NewOSMACRepository(mockFS, mockCmd, "debian").EnforceMAC(model.MACSELinux))
+
+		assert.Equal(t, "SELINUX=enforcing\n", string(mockFS.Files[secondary.SELinuxConfigPath]))
+		assert.Contains(t, mockCmd.ExecutedCommands, "selinux-activate")
+		assert.NotContains(t, mockCmd.ExecutedCommands, "setenforce 1")
+		assert.Contains(t, mockFS.Files, "/.autorelabel")
+	})
+}
+
+func TestOSMACRepository_EnforceAppArmor(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Directories["/etc/apparmor.d"] = true
+	mockCmd := interfaces.NewMockCommander()
+	mockCmd.CommandOutputs["find /etc/apparmor.d -mindepth 1 -maxdepth 1 -type f"] = []byte(
+		"/etc/apparmor.d/usr.sbin.sshd\n/etc/apparmor.d/usr.bin.man\n/etc/apparmor.d/lsb_release\n")
+	mockCmd.CommandErrors["aa-enforce /etc/apparmor.d/usr.bin.man"] = errors.New("exit status 1")
+
+	var out bytes.Buffer
+	provider := interfaces.DryRunProvider(&interfaces.Provider{FS: mockFS, Commander: mockCmd},
+		func() bool { return true }, &out)
+
+	// A dry run lists the profiles and prints each aa-enforce call
+	require.NoError(t, secondary.NewOSMACRepository(provider.FS, provider.Commander, "debian").EnforceMAC(model.MACAppArmor))
+	assert.Equal(t, []string{"find /etc/apparmor.d -mindepth 1 -maxdepth 1 -type f"}, mockCmd.ExecutedCommands)
+	assert.Contains(t, out.String(), "[DRY-RUN] Would run: aa-enforce /etc/apparmor.d/usr.sbin.sshd\n")
+
+	// Every profile is tried and the failures are reported
+	mockCmd.ExecutedCommands = nil
+	err := secondary.NewOSMACRepository(mockFS, mockCmd, "debian").EnforceMAC(model.MACAppArmor)
+	assert.ErrorContains(t, err, "failed to enforce 1 of 3 AppArmor profile(s): usr.bin.man: exit status 1")
+	assert.Contains(t, mockCmd.ExecutedCommands, "aa-enforce /etc/apparmor.d/lsb_release")
+	assert.Contains(t, mockCmd.ExecutedCommands, "aa-enforce /etc/apparmor.d/usr.sbin.sshd")
+}
+
+func TestOSMACRepository_LabelSSHPort(t *testing.T) {
+	mockCmd := interfaces.NewMockCommander()
+	mockCmd.CommandOutputs["semanage port -l -n"] = []byte(semanagePorts)
+	repo := secondary.NewOSMACRepository(interfaces.NewMockFileSystem(), mockCmd, "rocky")
+
+	// Already labeled
+	require.NoError(t, repo.LabelSSHPort(2222))
+	assert.Equal(t, []string{"semanage port -l -n"}, mockCmd.ExecutedCommands)
+
+	// Owned by another type, so -a fails and -m relabels it
+	mockCmd.CommandErrors["semanage port -a -t ssh_port_t -p tcp 8443"] = errors.New("already defined")
+	require.NoError(t, repo.LabelSSHPort(8443))
+	assert.Contains(t, mockCmd.ExecutedCommands, "semanage port -m -t ssh_port_t -p tcp 8443")
+}
@@ -0,0 +1,133 @@
+// pkg/testing/menu_update_check_test.go
+package testing
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/menu"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUpdateCheckMenu returns a main menu whose update check asks apiURL and
+// caches its result in a temporary file
+func newUpdateCheckMenu(t *testing.T, apiURL string) *menu.MainMenu {
+	t.Setenv("HARDN_CACHE_PATH", filepath.Join(t.TempDir(), "version-cache.json"))
+
+	mainMenu := menu.NewMainMenu(nil, &config.Config{}, &osdetect.OSInfo{OsType: "debian"}, version.NewService("0.4.0", "", ""))
+	mainMenu.SetUpdateAPIURL(apiURL)
+	return mainMenu
+}
+
+// waitForUpdateCheck waits until the background update check has finished
+func waitForUpdateCheck(t *testing.T, mainMenu *menu.MainMenu) string {
+	var latest string
+	require.Eventually(t, func() bool {
+		var checking bool
+		checking, latest = mainMenu.UpdateStatus()
+		return !checking
+	}, 5*time.Second, 10*time.Millisecond)
+	return latest
+}
+
+// TestMainMenu_ConcurrentUpdateCheck drives the update state from several
+// goroutines at once, as the UI loop and the background check do; run with
+// -race to check the locking
+func TestMainMenu_ConcurrentUpdateCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(version.GitHubRelease{TagName: "v0.5.0"})
+	}))
+	defer server.Close()
+	mainMenu := newUpdateCheckMenu(t, server.URL+"/releases/latest")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			mainMenu.CheckForUpdates()
+		}()
+		go func() {
+			defer wg.Done()
+			mainMenu.UpdateStatus()
+		}()
+		go func() {
+			defer wg.Done()
+			mainMenu.Notify(menu.NotifyInfo, "background event")
+		}()
+		go func() {
+			defer wg.Done()
+			mainMenu.SetUpdateAPIURL(server.URL + "/releases/latest")
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, "0.5.0", waitForUpdateCheck(t, mainMenu))
+
+	// Only the first call starts a check, and a finished check stays applied
+	mainMenu.CheckForUpdates()
+	mainMenu.StopUpdateCheck()
+	checking, latest := mainMenu.UpdateStatus()
+	assert.False(t, checking)
+	assert.Equal(t, "0.5.0", latest)
+}
+
+func TestMainMenu_StopUpdateCheckCancelsRequest(t *testing.T) {
+	requested := make(chan struct{})
+	cancelled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(requested)
+		<-r.Context().Done()
+		close(cancelled)
+	}))
+	defer server.Close()
+	mainMenu := newUpdateCheckMenu(t, server.URL+"/releases/latest")
+
+	mainMenu.CheckForUpdates()
+	<-requested
+	checking, _ := mainMenu.UpdateStatus()
+	assert.True(t, checking)
+
+	// Stopping from another goroutine, as the menu does on exit, cancels the request
+	go mainMenu.StopUpdateCheck()
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the update request was not cancelled")
+	}
+
+	assert.Empty(t, waitForUpdateCheck(t, mainMenu), "a cancelled check must not apply a result")
+}
+
+func TestMainMenu_ForcedUpdateRacesBackgroundCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(version.GitHubRelease{TagName: "v0.5.0"})
+	}))
+	defer server.Close()
+	mainMenu := newUpdateCheckMenu(t, server.URL+"/releases/latest")
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		mainMenu.CheckForUpdates()
+	}()
+	go func() {
+		defer wg.Done()
+		mainMenu.SetTestUpdateAvailable("99.0.0")
+	}()
+	wg.Wait()
+
+	// Whichever ran first, the state settles on one of the two results
+	latest := waitForUpdateCheck(t, mainMenu)
+	assert.Contains(t, []string{"0.5.0", "99.0.0"}, latest)
+}
@@ -0,0 +1,46 @@
+// pkg/testing/migration_repository_test.go
+package testing
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMigrationRepository_ReadArtifact(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	repo := secondary.NewFileMigrationRepository(mockFS, interfaces.NewMockCommander())
+
+	// A missing file is not an error
+	data, err := repo.ReadArtifact(model.MigrationSshdConfigPath)
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	mockFS.Files[model.MigrationSshdConfigPath] = []byte("Port 2222\n")
+	data, err = repo.ReadArtifact(model.MigrationSshdConfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, "Port 2222\n", string(data))
+}
+
+func TestFileMigrationRepository_ListArtifacts(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewFileMigrationRepository(mockFS, mockCommander)
+
+	// A missing directory lists nothing and runs nothing
+	paths, err := repo.ListArtifacts(model.MigrationSudoersDir)
+	require.NoError(t, err)
+	assert.Nil(t, paths)
+	assert.Empty(t, mockCommander.ExecutedCommands)
+
+	require.NoError(t, mockFS.MkdirAll(model.MigrationSudoersDir, 0750))
+	mockCommander.CommandOutputs["find "+model.MigrationSudoersDir+" -mindepth 1 -maxdepth 1 -type f"] =
+		[]byte("/etc/sudoers.d/ops\n/etc/sudoers.d/README\n/etc/sudoers.d/admins\n")
+	paths, err = repo.ListArtifacts(model.MigrationSudoersDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/etc/sudoers.d/README", "/etc/sudoers.d/admins", "/etc/sudoers.d/ops"}, paths)
+}
@@ -0,0 +1,70 @@
+// pkg/testing/module_blacklist_repository_test.go
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOSModuleBlacklistRepository_WriteBlacklist(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	repo := secondary.NewOSModuleBlacklistRepository(mockFS, interfaces.NewMockCommander(), "debian")
+
+	assert.NoError(t, repo.WriteBlacklist([]string{"cramfs", "usb-storage"}))
+	assert.Equal(t, "# Managed by hardn: kernel modules kept from loading\n"+
+		"install cramfs /bin/false\nblacklist cramfs\n"+
+		"install usb-storage /bin/false\nblacklist usb-storage\n",
+		string(mockFS.Files[model.ModuleBlacklistPath]))
+
+	// An empty list removes the file
+	assert.NoError(t, repo.WriteBlacklist(nil))
+	assert.NotContains(t, mockFS.Files, model.ModuleBlacklistPath)
+	assert.NoError(t, repo.WriteBlacklist(nil))
+}
+
+func TestOSModuleBlacklistRepository_GetDisabledModules(t *testing.T) {
+	t.Run("modprobe configuration", func(t *testing.T) {
+		mockCommander := interfaces.NewMockCommander()
+		mockCommander.CommandOutputs["modprobe --showconfig"] = []byte("blacklist floppy\n" +
+			"blacklist udf\n" +
+			"install cramfs /bin/false\n" +
+			"install usb-storage /usr/bin/true\n" +
+			"install snd_hda_intel /sbin/modprobe --ignore-install snd_hda_intel\n" +
+			"options usbcore autosuspend=2\n" +
+			"alias fs-udf udf\n")
+		repo := secondary.NewOSModuleBlacklistRepository(interfaces.NewMockFileSystem(), mockCommander, "ubuntu")
+
+		disabled, err := repo.GetDisabledModules()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"cramfs", "usb_storage"}, disabled)
+	})
+
+	t.Run("busybox modprobe", func(t *testing.T) {
+		mockFS := interfaces.NewMockFileSystem()
+		mockCommander := interfaces.NewMockCommander()
+		mockCommander.CommandErrors["modprobe --showconfig"] = errors.New("unrecognized option")
+		mockFS.Files[model.ModuleBlacklistPath] = []byte("install hfs /bin/false\nblacklist hfs\n")
+		repo := secondary.NewOSModuleBlacklistRepository(mockFS, mockCommander, "alpine")
+
+		disabled, err := repo.GetDisabledModules()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"hfs"}, disabled)
+	})
+}
+
+func TestOSModuleBlacklistRepository_GetLoadedModules(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[model.ProcModulesPath] = []byte("usb_storage 86016 1 uas, Live 0x0000000000000000\n" +
+		"squashfs 73728 4 - Live 0x0000000000000000\n")
+	repo := secondary.NewOSModuleBlacklistRepository(mockFS, interfaces.NewMockCommander(), "ubuntu")
+
+	loaded, err := repo.GetLoadedModules()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"usb_storage", "squashfs"}, loaded)
+	assert.False(t, repo.SnapdInstalled())
+}
@@ -0,0 +1,114 @@
+// pkg/testing/network_exposure_repository_test.go
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSSListening(t *testing.T) {
+	output := []byte(`udp   UNCONN 0      0         127.0.0.53%lo:53         0.0.0.0:*    users:(("systemd-resolve",pid=600,fd=13))
+tcp   LISTEN 0      128             0.0.0.0:22          0.0.0.0:*    users:(("sshd",pid=812,fd=3))
+tcp   LISTEN 0      128                [::]:22             [::]:*    users:(("sshd",pid=812,fd=4))
+tcp   LISTEN 0      511                   *:80                *:*    users:(("apache2",pid=1201,fd=4),("apache2",pid=1200,fd=4))
+tcp   LISTEN 0      4096     [::ffff:10.0.0.5]:9100           *:*
+`)
+
+	sockets := secondary.ParseSSListening(output)
+
+	assert.Equal(t, []model.ListeningSocket{
+		{Protocol: "udp", Address: "127.0.0.53", Port: 53, Process: "systemd-resolve", PID: 600},
+		{Protocol: "tcp", Address: "0.0.0.0", Port: 22, Process: "sshd", PID: 812},
+		{Protocol: "tcp", Address: "::", Port: 22, Process: "sshd", PID: 812},
+		{Protocol: "tcp", Address: "*", Port: 80, Process: "apache2", PID: 1201},
+		{Protocol: "tcp", Address: "10.0.0.5", Port: 9100},
+	}, sockets)
+	assert.True(t, sockets[0].Loopback())
+	assert.True(t, sockets[2].Wildcard())
+	assert.False(t, sockets[4].Wildcard())
+}
+
+func TestParseNetstatListening(t *testing.T) {
+	output := []byte(`Active Internet connections (only servers)
+Proto Recv-Q Send-Q Local Address           Foreign Address         State       PID/Program name
+tcp        0      0 0.0.0.0:22              0.0.0.0:*               LISTEN      812/sshd
+tcp6       0      0 :::22                   :::*                    LISTEN      812/sshd
+udp        0      0 0.0.0.0:123             0.0.0.0:*                           -
+`)
+
+	assert.Equal(t, []model.ListeningSocket{
+		{Protocol: "tcp", Address: "0.0.0.0", Port: 22, Process: "sshd", PID: 812},
+		{Protocol: "tcp", Address: "::", Port: 22, Process: "sshd", PID: 812},
+		{Protocol: "udp", Address: "0.0.0.0", Port: 123},
+	}, secondary.ParseNetstatListening(output))
+}
+
+func TestParseOwningPackage(t *testing.T) {
+	assert.Equal(t, "openssh-server", secondary.ParseDpkgSearch([]byte("openssh-server: /usr/sbin/sshd\n")))
+	assert.Equal(t, "libc-bin", secondary.ParseDpkgSearch([]byte("libc-bin:amd64: /sbin/ldconfig\n")))
+	assert.Equal(t, "", secondary.ParseDpkgSearch([]byte("dpkg-query: no path found matching pattern /opt/app\n")))
+	assert.Equal(t, "openssh-server", secondary.ParseApkWhoOwns([]byte("/usr/sbin/sshd is owned by openssh-server-9.7_p1-r4\n")))
+}
+
+func TestOSNetworkExposureRepository_GetListeningSockets(t *testing.T) {
+	t.Run("resolves each process once", func(t *testing.T) {
+		mockCommander := interfaces.NewMockCommander()
+		mockCommander.CommandOutputs["ss -H -tulpn"] = []byte(
+			"tcp LISTEN 0 128 0.0.0.0:22 0.0.0.0:* users:((\"sshd\",pid=812,fd=3))\n" +
+				"tcp LISTEN 0 128 [::]:22 [::]:* users:((\"sshd\",pid=812,fd=4))\n")
+		mockCommander.CommandOutputs["readlink -f /proc/812/exe"] = []byte("/usr/sbin/sshd\n")
+		mockCommander.CommandOutputs["dpkg-query -S /usr/sbin/sshd"] = []byte("openssh-server: /usr/sbin/sshd\n")
+		repo := secondary.NewOSNetworkExposureRepository(interfaces.NewMockFileSystem(), mockCommander, "debian")
+
+		sockets, err := repo.GetListeningSockets()
+
+		assert.NoError(t, err)
+		assert.Len(t, sockets, 2)
+		assert.Equal(t, "/usr/sbin/sshd", sockets[1].Executable)
+		assert.Equal(t, "openssh-server", sockets[1].Package)
+		assert.Equal(t, 1, countCommands(mockCommander.ExecutedCommands, "dpkg-query -S /usr/sbin/sshd"))
+	})
+
+	t.Run("falls back to netstat on rhel", func(t *testing.T) {
+		mockCommander := interfaces.NewMockCommander()
+		mockCommander.CommandErrors["ss -H -tulpn"] = errors.New("not found")
+		mockCommander.CommandOutputs["netstat -tulpn"] = []byte(
+			"tcp        0      0 0.0.0.0:80    0.0.0.0:*     LISTEN      1200/httpd\n")
+		mockCommander.CommandOutputs["readlink -f /proc/1200/exe"] = []byte("/usr/sbin/httpd\n")
+		mockCommander.CommandOutputs["rpm -qf --queryformat %{NAME}\n /usr/sbin/httpd"] = []byte("httpd\n")
+		repo := secondary.NewOSNetworkExposureRepository(interfaces.NewMockFileSystem(), mockCommander, "rocky")
+
+		sockets, err := repo.GetListeningSockets()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.ListeningSocket{{Protocol: "tcp", Address: "0.0.0.0", Port: 80,
+			Process: "httpd", PID: 1200, Executable: "/usr/sbin/httpd", Package: "httpd"}}, sockets)
+	})
+
+	t.Run("no tools", func(t *testing.T) {
+		mockCommander := interfaces.NewMockCommander()
+		mockCommander.CommandErrors["ss -H -tulpn"] = errors.New("not found")
+		mockCommander.CommandErrors["netstat -tulpn"] = errors.New("not found")
+		repo := secondary.NewOSNetworkExposureRepository(interfaces.NewMockFileSystem(), mockCommander, "debian")
+
+		_, err := repo.GetListeningSockets()
+
+		assert.Error(t, err)
+	})
+}
+
+// countCommands counts how often command was executed
+func countCommands(executed []string, command string) int {
+	count := 0
+	for _, c := range executed {
+		if c == command {
+			count++
+		}
+	}
+	return count
+}
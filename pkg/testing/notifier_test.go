@@ -0,0 +1,122 @@
+// pkg/testing/notifier_test.go
+package testing
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSummary() model.RunSummary {
+	started := time.Date(2024, 6, 1, 2, 0, 0, 0, time.UTC)
+	return model.RunSummary{
+		Hostname:    "web1",
+		Operation:   model.SummaryRunAll,
+		StartedAt:   started,
+		FinishedAt:  started.Add(95 * time.Second),
+		Failed:      true,
+		RiskBefore:  "High",
+		RiskAfter:   "Moderate",
+		FailedSteps: []string{"Firewall: ufw not found"},
+	}
+}
+
+func TestRunSummary_Text(t *testing.T) {
+	text := testSummary().Text()
+
+	assert.True(t, strings.HasPrefix(text, "hardn run-all on web1 failed (risk: Moderate)\n"))
+	assert.Contains(t, text, "Finished:   2024-06-01T02:01:35Z (1m35s)\n")
+	assert.Contains(t, text, "Risk level: High -> Moderate\n")
+	assert.Contains(t, text, "Failed steps:\n  - Firewall: ufw not found\n")
+}
+
+func TestRunSummary_Text_Posture(t *testing.T) {
+	until := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	text := model.RunSummary{
+		Hostname:       "web1",
+		Operation:      model.SummaryPosture,
+		StartedAt:      until.AddDate(0, 0, -7),
+		FinishedAt:     until,
+		RiskBefore:     "High",
+		RiskAfter:      "Low",
+		Trend:          model.TrendImproved,
+		UpdatesApplied: []string{"openssl 3.0.11-1 -> 3.0.11-2"},
+	}.Text()
+
+	assert.True(t, strings.HasPrefix(text, "hardn posture summary for web1: risk Low (improved)\n"))
+	assert.Contains(t, text, "Period:     2026-10-12T08:00:00Z to 2026-10-19T08:00:00Z\n")
+	assert.Contains(t, text, "Risk level: High -> Low\nTrend:      improved\n")
+	assert.Contains(t, text, "Updates applied:\n  - openssl 3.0.11-1 -> 3.0.11-2\n")
+
+	// An inventory's summary lists its hosts first
+	text = model.RunSummary{
+		Hostname:     "2 hosts",
+		Operation:    model.SummaryPosture,
+		RiskAfter:    "High",
+		Trend:        model.TrendRegressed,
+		Hosts:        []string{"web1: risk Low (steady)", "web2: risk High (regressed)"},
+		FailedChecks: []string{"web2: firewall: Firewall enabled"},
+	}.Text()
+	assert.True(t, strings.HasPrefix(text, "hardn posture summary for 2 hosts: risk High (regressed)\n"))
+	assert.Contains(t, text, "Hosts:\n  - web1: risk Low (steady)\n  - web2: risk High (regressed)\n")
+	assert.Less(t, strings.Index(text, "Hosts:"), strings.Index(text, "Failed checks:"))
+}
+
+func TestWebhookNotifier_Send(t *testing.T) {
+	var received secondary.WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/T000/B000/secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+	}))
+	defer server.Close()
+
+	notifier := secondary.NewWebhookNotifier(server.URL + "/services/T000/B000/secret")
+	require.NoError(t, notifier.Send(testSummary()))
+
+	assert.Equal(t, testSummary().Text(), received.Text)
+	assert.Equal(t, received.Text, received.Content)
+	assert.Equal(t, []string{"Firewall: ufw not found"}, received.Summary.FailedSteps)
+
+	// The secret path is left out of the name used in errors
+	err := secondary.NewWebhookNotifier(server.URL + "/wrong").Send(testSummary())
+	assert.ErrorContains(t, err, "403")
+	assert.NotContains(t, err.Error(), "/wrong")
+}
+
+func TestNewWebhookPayload_LimitsContent(t *testing.T) {
+	summary := testSummary()
+	for i := 0; i < 200; i++ {
+		summary.FailedChecks = append(summary.FailedChecks, "ssh.password_auth_disabled: Password authentication is disabled")
+	}
+
+	payload := secondary.NewWebhookPayload(summary)
+
+	assert.Len(t, payload.Content, 2000)
+	assert.Greater(t, len(payload.Text), 2000)
+}
+
+func TestBuildEmailMessage(t *testing.T) {
+	date := time.Date(2024, 6, 1, 2, 1, 35, 0, time.UTC)
+
+	message := string(secondary.BuildEmailMessage("hardn@web1", []string{"ops@example.com", "sec@example.com"}, testSummary(), date))
+
+	assert.Contains(t, message, "To: ops@example.com, sec@example.com\r\n")
+	assert.Contains(t, message, "Subject: hardn run-all on web1 failed (risk: Moderate)\r\n")
+	assert.Contains(t, message, "Date: Sat, 01 Jun 2024 02:01:35 +0000\r\n")
+	assert.Contains(t, message, "\r\n\r\nhardn run-all on web1 failed")
+	assert.NotContains(t, strings.ReplaceAll(message, "\r\n", ""), "\n")
+}
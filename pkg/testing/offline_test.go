@@ -0,0 +1,113 @@
+// pkg/testing/offline_test.go
+package testing
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeVersionCache caches tag as the latest release, checked at checked
+func writeVersionCache(t *testing.T, tag string, checked time.Time) string {
+	path := filepath.Join(t.TempDir(), "hardn-version-cache.json")
+	data, err := json.Marshal(version.VersionCache{
+		Format:        3,
+		LastCheck:     checked,
+		LatestRelease: version.GitHubRelease{TagName: tag},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	return path
+}
+
+func TestUpdateCheckCache(t *testing.T) {
+	service := version.NewService("0.4.0", "", "")
+	checked := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+
+	t.Run("reuses a result younger than the TTL", func(t *testing.T) {
+		result := service.CheckForUpdates(&version.UpdateOptions{
+			CacheFilePath: writeVersionCache(t, "v0.5.0", checked),
+			CacheTTL:      3 * time.Hour,
+		})
+
+		require.NoError(t, result.Error)
+		assert.True(t, result.UpdateAvailable)
+		assert.Equal(t, "0.5.0", result.LatestVersion)
+		assert.False(t, result.Stale)
+		assert.True(t, checked.Equal(result.CheckedAt))
+	})
+
+	t.Run("verifies an available release and caches the result", func(t *testing.T) {
+		server := releaseServer(t, []byte("#!/bin/sh\necho hardn 0.5.0\n"))
+		cache := filepath.Join(t.TempDir(), "hardn-version-cache.json")
+
+		result := service.CheckForUpdates(&version.UpdateOptions{
+			CacheFilePath: cache,
+			APIURL:        server.URL + "/releases/latest",
+		})
+		require.NoError(t, result.Error)
+		assert.True(t, result.UpdateAvailable)
+		assert.Equal(t, version.VerificationChecksum, result.Verification.Status)
+		assert.Len(t, result.Verification.Checksum, 64)
+
+		server.Close()
+		result = service.CheckForUpdates(&version.UpdateOptions{CacheFilePath: cache, Offline: true})
+		assert.False(t, result.Stale)
+		assert.Equal(t, version.VerificationChecksum, result.Verification.Status)
+	})
+
+	t.Run("offline uses an expired result", func(t *testing.T) {
+		result := service.CheckForUpdates(&version.UpdateOptions{
+			CacheFilePath: writeVersionCache(t, "v0.5.0", checked),
+			CacheTTL:      time.Hour,
+			Offline:       true,
+		})
+
+		require.NoError(t, result.Error)
+		assert.True(t, result.UpdateAvailable)
+		assert.True(t, result.Stale)
+	})
+
+	t.Run("offline without a cache skips the check", func(t *testing.T) {
+		result := service.CheckForUpdates(&version.UpdateOptions{
+			CacheFilePath: filepath.Join(t.TempDir(), "missing.json"),
+			Offline:       true,
+		})
+
+		assert.ErrorIs(t, result.Error, version.ErrOffline)
+		assert.False(t, result.UpdateAvailable)
+	})
+}
+
+func TestOfflineNetworkCalls(t *testing.T) {
+	interfaces.SetOffline(true)
+	t.Cleanup(func() { interfaces.SetOffline(false) })
+
+	trust := secondary.NewOSRepositoryTrustRepository(interfaces.NewMockFileSystem(), interfaces.NewMockCommander(), "debian")
+	_, err := trust.DownloadKey("https://packages.example.com/key.asc")
+	assert.ErrorIs(t, err, interfaces.ErrOffline)
+
+	keys := secondary.NewHTTPSSHKeySourceRepository()
+	_, err = keys.FetchPublicKeys(model.SSHKeyProviderGitHub, "octocat")
+	assert.ErrorIs(t, err, interfaces.ErrOffline)
+
+	// dnf reads the advisories of its cached metadata instead
+	commander := interfaces.NewMockCommander()
+	commander.CommandOutputs["dnf --cacheonly -q updateinfo list --security --with-cve"] = []byte("")
+	audit := secondary.NewOSPackageAuditRepository(interfaces.NewMockFileSystem(), commander, "rocky", "9.4", "")
+	_, err = audit.CheckAdvisories(nil)
+	assert.NoError(t, err)
+
+	audit = secondary.NewOSPackageAuditRepository(interfaces.NewMockFileSystem(), interfaces.NewMockCommander(), "alpine", "3.20.1", "")
+	_, err = audit.CheckAdvisories(nil)
+	assert.ErrorIs(t, err, interfaces.ErrOffline)
+}
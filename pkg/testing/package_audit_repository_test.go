@@ -0,0 +1,111 @@
+// pkg/testing/package_audit_repository_test.go
+package testing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOSPackageAuditRepository_Debian(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSPackageAuditRepository(mockFS, mockCommander, "debian", "12", "bookworm")
+
+	mockCommander.CommandOutputs[`dpkg-query -W -f=${db:Status-Abbrev}\t${Package}\t${Version}\n`] =
+		[]byte("ii \topenssl\t3.0.11-1~deb12u2\nrc \told-kernel\t6.1.0-1\nii \tbash\t5.2.15-2+b2\n")
+	mockCommander.CommandOutputs["debsecan --format summary --suite bookworm"] = []byte(
+		"CVE-2024-0001 openssl (remotely exploitable, high urgency, fixed)\n" +
+			"CVE-2024-0002 bash (unimportant urgency)\n" +
+			"TEMP-0000000-1A2B3C bash\n")
+
+	packages, err := repo.InstalledPackages()
+	require.NoError(t, err)
+	assert.Equal(t, []model.InstalledPackage{
+		{Name: "openssl", Version: "3.0.11-1~deb12u2"},
+		{Name: "bash", Version: "5.2.15-2+b2"},
+	}, packages, "removed packages are left out")
+
+	assert.Equal(t, "debsecan", repo.AdvisorySource())
+	vulnerabilities, err := repo.CheckAdvisories(packages)
+	require.NoError(t, err)
+	assert.Equal(t, []model.PackageVulnerability{
+		{ID: "CVE-2024-0001", Package: "openssl", Severity: model.SeverityHigh, FixAvailable: true, Remote: true},
+		{ID: "CVE-2024-0002", Package: "bash", Severity: model.SeverityLow},
+		{ID: "TEMP-0000000-1A2B3C", Package: "bash", Severity: model.SeverityUnknown},
+	}, vulnerabilities)
+
+	// Without debsecan there is nothing to check against
+	mockCommander.CommandErrors["which debsecan"] = assert.AnError
+	_, err = repo.CheckAdvisories(packages)
+	assert.ErrorContains(t, err, "install the debsecan package")
+}
+
+func TestOSPackageAuditRepository_RHEL(t *testing.T) {
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSPackageAuditRepository(interfaces.NewMockFileSystem(), mockCommander, "rocky", "9.4", "")
+
+	mockCommander.CommandOutputs["dnf -q updateinfo list --security --with-cve"] = []byte(
+		"CVE-2024-0001 Important/Sec. openssl-libs-1:3.0.7-27.el9.x86_64\n" +
+			"CVE-2024-0003 Critical/Sec.  kernel-5.14.0-427.el9.x86_64\n" +
+			"RLSA-2024:1234 Moderate/Sec. curl-7.76.1-29.el9.x86_64\n")
+
+	vulnerabilities, err := repo.CheckAdvisories(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []model.PackageVulnerability{
+		{ID: "CVE-2024-0001", Package: "openssl-libs", Severity: model.SeverityHigh, FixAvailable: true, FixedVersion: "3.0.7-27.el9"},
+		{ID: "CVE-2024-0003", Package: "kernel", Severity: model.SeverityCritical, FixAvailable: true, FixedVersion: "5.14.0-427.el9"},
+	}, vulnerabilities)
+}
+
+func TestOSPackageAuditRepository_Alpine(t *testing.T) {
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSPackageAuditRepository(interfaces.NewMockFileSystem(), mockCommander, "alpine", "3.19.1", "3.19.1")
+
+	mockCommander.CommandOutputs["apk info -v"] = []byte("musl-1.2.4_git20230717-r4\nopenssl-3.1.4-r1\nlibcrypto3-3.1.4-r1\n")
+	mockCommander.CommandOutputs["wget -qO- https://secdb.alpinelinux.org/v3.19/main.json"] = []byte(`{"packages": [
+		{"pkg": {"name": "openssl", "secfixes": {"3.1.4-r3": ["CVE-2023-6129"], "3.1.0-r0": ["CVE-2022-4450"], "0": ["CVE-2014-0160"]}}},
+		{"pkg": {"name": "busybox", "secfixes": {"1.36.1-r16": ["CVE-2023-42363"]}}}
+	]}`)
+	mockCommander.CommandOutputs["wget -qO- https://secdb.alpinelinux.org/v3.19/community.json"] = []byte(`{"packages": []}`)
+	mockCommander.CommandOutputs["apk version -t 3.1.4-r1 3.1.4-r3"] = []byte("<\n")
+	mockCommander.CommandOutputs["apk version -t 3.1.4-r1 3.1.0-r0"] = []byte(">\n")
+
+	packages, err := repo.InstalledPackages()
+	require.NoError(t, err)
+	assert.Equal(t, model.InstalledPackage{Name: "musl", Version: "1.2.4_git20230717-r4"}, packages[0])
+
+	vulnerabilities, err := repo.CheckAdvisories(packages)
+	require.NoError(t, err)
+	assert.Equal(t, []model.PackageVulnerability{
+		{ID: "CVE-2023-6129", Package: "openssl", Version: "3.1.4-r1", Severity: model.SeverityUnknown, FixAvailable: true, FixedVersion: "3.1.4-r3"},
+	}, vulnerabilities)
+}
+
+func TestOSPackageAuditRepository_Report(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	repo := secondary.NewOSPackageAuditRepository(mockFS, interfaces.NewMockCommander(), "debian", "12", "bookworm")
+
+	report, err := repo.LoadReport()
+	require.NoError(t, err)
+	assert.Nil(t, report, "packages have not been checked yet")
+
+	saved := &model.PackageAuditReport{
+		CheckedAt:       time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		Source:          "debsecan",
+		Packages:        []model.InstalledPackage{{Name: "openssl", Version: "3.0.11-1~deb12u2"}},
+		Vulnerabilities: []model.PackageVulnerability{{ID: "CVE-2024-0001", Package: "openssl", Severity: model.SeverityHigh}},
+	}
+	require.NoError(t, repo.SaveReport(saved))
+	assert.Contains(t, mockFS.Files, model.PackageAuditPath)
+
+	report, err = repo.LoadReport()
+	require.NoError(t, err)
+	assert.Equal(t, saved, report)
+	assert.Equal(t, "1 high", report.Summary().Describe())
+}
@@ -0,0 +1,136 @@
+// pkg/testing/package_sources_test.go
+package testing
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAptSourceLines(t *testing.T) {
+	source, err := model.ParseAptSourceEntry("#deb https://enterprise.proxmox.com/debian/pve CODENAME pve-enterprise")
+	require.NoError(t, err)
+	require.NotNil(t, source)
+	assert.False(t, source.Enabled)
+
+	source, err = model.ParseAptSourceEntry("# See sources.list(5)")
+	require.NoError(t, err)
+	assert.Nil(t, source)
+
+	sources := model.ParseDeb822Sources([]byte("Types: deb deb-src\n" +
+		"URIs: http://deb.debian.org/debian\n" +
+		"Suites: trixie trixie-updates\n" +
+		"Components: main contrib\n" +
+		"Architectures: amd64 arm64\n" +
+		"Signed-By: /usr/share/keyrings/debian-archive-keyring.gpg\n"))
+	require.Len(t, sources, 1)
+	lines, err := sources[0].Lines()
+	require.NoError(t, err)
+	options := "[arch=amd64,arm64 signed-by=/usr/share/keyrings/debian-archive-keyring.gpg]"
+	assert.Equal(t, []string{
+		"deb " + options + " http://deb.debian.org/debian trixie main contrib",
+		"deb " + options + " http://deb.debian.org/debian trixie-updates main contrib",
+		"deb-src " + options + " http://deb.debian.org/debian trixie main contrib",
+		"deb-src " + options + " http://deb.debian.org/debian trixie-updates main contrib",
+	}, lines)
+
+	assert.Equal(t, model.SourcesFormatAuto, model.ParseSourcesFormat(""))
+	assert.Equal(t, model.SourcesFormatAuto, model.ParseSourcesFormat("rfc822"))
+	assert.Equal(t, "/etc/apt/sources.list.d/ubuntu.sources",
+		model.MigratedSourcesPath("/etc/apt/sources.list", model.SourcesFormatDeb822, "ubuntu"))
+	assert.Equal(t, "/etc/apt/sources.list.d/ceph.list",
+		model.MigratedSourcesPath("/etc/apt/sources.list.d/ceph.sources", model.SourcesFormatList, "debian"))
+}
+
+func TestUpdatePackageSourcesDeb822(t *testing.T) {
+	sources := model.PackageSources{
+		DebianRepos: []string{
+			"deb http://deb.debian.org/debian CODENAME main",
+			"deb-src http://deb.debian.org/debian CODENAME main",
+			"deb http://security.debian.org/debian-security CODENAME-security main",
+		},
+	}
+
+	// A host installed with debian.sources keeps using it
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/etc/apt/sources.list.d/debian.sources"] = []byte("Types: deb\n")
+	mockFS.Files["/etc/apt/sources.list"] = []byte("deb http://deb.debian.org/debian trixie main\n")
+	repo := secondary.NewOSPackageRepository(mockFS, interfaces.NewMockCommander(),
+		"debian", "13", "trixie", "amd64", false, &sources)
+
+	assert.Equal(t, model.SourcesFormatDeb822, repo.SourcesFormat(model.SourcesFormatAuto))
+	require.NoError(t, repo.UpdatePackageSources(sources))
+	assert.Equal(t, "# Managed by hardn: repositories from debianRepos\n\n"+
+		"Types: deb deb-src\nURIs: http://deb.debian.org/debian\nSuites: trixie\nComponents: main\n\n"+
+		"Types: deb\nURIs: http://security.debian.org/debian-security\nSuites: trixie-security\nComponents: main\n",
+		string(mockFS.Files["/etc/apt/sources.list.d/debian.sources"]))
+	assert.Equal(t, "Types: deb\n", string(mockFS.Files["/etc/apt/sources.list.d/debian.sources.bak"]))
+
+	// sources.list would list the same repositories twice
+	assert.Equal(t, "# Repositories are listed in /etc/apt/sources.list.d/debian.sources\n",
+		string(mockFS.Files["/etc/apt/sources.list"]))
+	assert.Contains(t, string(mockFS.Files["/etc/apt/sources.list.bak"]), "trixie main")
+
+	// Forcing one-line entries moves the .sources file aside
+	sources.SourcesFormat = model.SourcesFormatList
+	require.NoError(t, repo.UpdatePackageSources(sources))
+	assert.Contains(t, string(mockFS.Files["/etc/apt/sources.list"]), "deb-src http://deb.debian.org/debian trixie main\n")
+	assert.NotContains(t, mockFS.Files, "/etc/apt/sources.list.d/debian.sources")
+	assert.Contains(t, mockFS.Files, "/etc/apt/sources.list.d/debian.sources.bak")
+	assert.Equal(t, model.SourcesFormatList, repo.SourcesFormat(model.SourcesFormatAuto))
+}
+
+func TestUpdateProxmoxSourcesDeb822(t *testing.T) {
+	sources := model.PackageSources{
+		ProxmoxCephRepo: []string{
+			"#deb https://enterprise.proxmox.com/debian/ceph-quincy CODENAME enterprise",
+			"deb http://download.proxmox.com/debian/ceph-reef CODENAME no-subscription",
+		},
+		ProxmoxEnterpriseRepo: []string{
+			"#deb https://enterprise.proxmox.com/debian/pve CODENAME pve-enterprise",
+		},
+		SourcesFormat: model.SourcesFormatDeb822,
+	}
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/etc/apt/sources.list.d/pve-enterprise.list"] = []byte("deb https://enterprise.proxmox.com/debian/pve bookworm pve-enterprise\n")
+	repo := secondary.NewOSPackageRepository(mockFS, interfaces.NewMockCommander(),
+		"debian", "12", "bookworm", "amd64", true, &sources)
+
+	require.NoError(t, repo.UpdateProxmoxSources(sources))
+	assert.Equal(t, "# Managed by hardn: repositories from proxmoxCephRepo\n\n"+
+		"Types: deb\nURIs: https://enterprise.proxmox.com/debian/ceph-quincy\nSuites: bookworm\nComponents: enterprise\nEnabled: no\n\n"+
+		"Types: deb\nURIs: http://download.proxmox.com/debian/ceph-reef\nSuites: bookworm\nComponents: no-subscription\n",
+		string(mockFS.Files["/etc/apt/sources.list.d/ceph.sources"]))
+	assert.Contains(t, string(mockFS.Files["/etc/apt/sources.list.d/pve-enterprise.sources"]), "Enabled: no\n")
+	assert.NotContains(t, mockFS.Files, "/etc/apt/sources.list.d/pve-enterprise.list")
+	assert.Contains(t, mockFS.Files, "/etc/apt/sources.list.d/pve-enterprise.list.bak")
+
+	// The one-line lists are written as configured
+	sources.SourcesFormat = model.SourcesFormatList
+	require.NoError(t, repo.UpdateProxmoxSources(sources))
+	assert.Equal(t, "#deb https://enterprise.proxmox.com/debian/pve bookworm pve-enterprise\n",
+		string(mockFS.Files["/etc/apt/sources.list.d/pve-enterprise.list"]))
+	assert.NotContains(t, mockFS.Files, "/etc/apt/sources.list.d/ceph.sources")
+}
+
+func TestReplaceAptSources(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/etc/apt/sources.list"] = []byte("deb http://archive.ubuntu.com/ubuntu noble main\n")
+	mockFS.Files["/etc/apt/sources.list.d/ppa.list"] = []byte("deb https://ppa.launchpadcontent.net/x/y/ubuntu noble main\n")
+	repo := secondary.NewOSPackageRepository(mockFS, interfaces.NewMockCommander(),
+		"ubuntu", "24.04", "noble", "amd64", false, &model.PackageSources{})
+
+	require.NoError(t, repo.ReplaceAptSources("/etc/apt/sources.list", "/etc/apt/sources.list.d/ubuntu.sources", []byte("Types: deb\n")))
+	assert.Equal(t, "Types: deb\n", string(mockFS.Files["/etc/apt/sources.list.d/ubuntu.sources"]))
+	assert.Equal(t, "# Repositories are listed in /etc/apt/sources.list.d/ubuntu.sources\n",
+		string(mockFS.Files["/etc/apt/sources.list"]))
+	assert.Contains(t, string(mockFS.Files["/etc/apt/sources.list.bak"]), "noble main")
+
+	require.NoError(t, repo.ReplaceAptSources("/etc/apt/sources.list.d/ppa.list", "/etc/apt/sources.list.d/ppa.sources", []byte("Types: deb\n")))
+	assert.NotContains(t, mockFS.Files, "/etc/apt/sources.list.d/ppa.list")
+	assert.Contains(t, mockFS.Files, "/etc/apt/sources.list.d/ppa.list.bak")
+}
@@ -0,0 +1,151 @@
+// pkg/testing/password_policy_repository_test.go
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+const faillockModulePath = "/usr/lib/x86_64-linux-gnu/security/pam_faillock.so"
+
+func TestOSPasswordPolicyRepository_Debian(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSPasswordPolicyRepository(mockFS, mockCommander, "debian")
+
+	mockFS.Files[faillockModulePath] = []byte{}
+	mockFS.Files[model.LoginDefsPath] = []byte("# Password aging controls:\n" +
+		"#\tPASS_MAX_DAYS\tMaximum number of days a password may be used.\n" +
+		"PASS_MAX_DAYS\t99999\n" +
+		"PASS_MIN_DAYS\t0\n" +
+		"PASS_WARN_AGE\t7\n" +
+		"UMASK\t\t022\n")
+	mockFS.Files[model.PwqualityConfigPath] = []byte("# Minimum acceptable size for the new password\n# minlen = 8\n")
+
+	assert.NoError(t, repo.ConfigurePasswordPolicy(model.DefaultPasswordPolicy()))
+
+	assert.Contains(t, mockCommander.ExecutedCommands, "apt-get install -y libpam-pwquality")
+	assert.Equal(t, "# Password aging controls:\n"+
+		"#\tPASS_MAX_DAYS\tMaximum number of days a password may be used.\n"+
+		"PASS_MAX_DAYS\t365\n"+
+		"PASS_MIN_DAYS\t1\n"+
+		"PASS_WARN_AGE\t7\n"+
+		"UMASK\t\t022\n", string(mockFS.Files[model.LoginDefsPath]))
+	assert.Equal(t, "# Minimum acceptable size for the new password\n# minlen = 8\n"+
+		"# Managed by hardn: password policy\n"+
+		"minlen = 14\ndcredit = -1\nucredit = -1\nlcredit = -1\nocredit = -1\nretry = 3\n",
+		string(mockFS.Files[model.PwqualityConfigPath]))
+	assert.Equal(t, "# Managed by hardn: password policy\ndeny = 5\nunlock_time = 900\n",
+		string(mockFS.Files[model.FaillockConfigPath]))
+	assert.Contains(t, string(mockFS.Files["/usr/share/pam-configs/hardn-faillock"]), "[default=die] pam_faillock.so authfail")
+	assert.Contains(t, string(mockFS.Files["/usr/share/pam-configs/hardn-faillock-notify"]), "requisite pam_faillock.so preauth")
+	assert.Contains(t, mockCommander.ExecutedCommands, "pam-auth-update --package --enable hardn-faillock hardn-faillock-notify")
+
+	// Applying again changes nothing
+	assert.NoError(t, repo.ConfigurePasswordPolicy(model.DefaultPasswordPolicy()))
+	assert.Equal(t, "# Managed by hardn: password policy\ndeny = 5\nunlock_time = 900\n",
+		string(mockFS.Files[model.FaillockConfigPath]))
+
+	// The status comes from the PAM stack pam-auth-update generated
+	mockFS.Files["/etc/pam.d/common-password"] = []byte("password\trequisite\t\t\tpam_pwquality.so retry=3\n" +
+		"password\t[success=1 default=ignore]\tpam_unix.so obscure use_authtok try_first_pass yescrypt\n")
+	mockFS.Files["/etc/pam.d/common-auth"] = []byte("auth\trequisite\t\t\tpam_faillock.so preauth\n" +
+		"auth\t[success=1 default=ignore]\tpam_unix.so nullok\n" +
+		"auth\t[default=die]\t\t\tpam_faillock.so authfail\n")
+
+	status, err := repo.GetPasswordPolicyStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, &model.PasswordPolicyStatus{
+		Complexity:      true,
+		MinLength:       14,
+		MaxDays:         365,
+		MinDays:         1,
+		WarnAge:         7,
+		Lockout:         true,
+		LockoutAttempts: 5,
+	}, status)
+	assert.Empty(t, status.Weaknesses())
+}
+
+func TestOSPasswordPolicyRepository_DebianLockout(t *testing.T) {
+	t.Run("pam_faillock missing", func(t *testing.T) {
+		mockFS := interfaces.NewMockFileSystem()
+		mockCommander := interfaces.NewMockCommander()
+		repo := secondary.NewOSPasswordPolicyRepository(mockFS, mockCommander, "debian")
+
+		err := repo.ConfigurePasswordPolicy(model.DefaultPasswordPolicy())
+
+		assert.ErrorContains(t, err, "pam_faillock is not installed")
+		assert.NotContains(t, mockFS.Files, "/usr/share/pam-configs/hardn-faillock")
+		for _, command := range mockCommander.ExecutedCommands {
+			assert.NotContains(t, command, "pam-auth-update")
+		}
+	})
+
+	t.Run("lockout turned off", func(t *testing.T) {
+		mockFS := interfaces.NewMockFileSystem()
+		mockCommander := interfaces.NewMockCommander()
+		repo := secondary.NewOSPasswordPolicyRepository(mockFS, mockCommander, "ubuntu")
+		mockFS.Files["/usr/share/pam-configs/hardn-faillock"] = []byte("Name: Lock accounts after failed logins (hardn)\n")
+		mockFS.Files["/usr/share/pam-configs/hardn-faillock-notify"] = []byte("Name: Check and reset failed login counts (hardn)\n")
+
+		policy := model.DefaultPasswordPolicy()
+		policy.LockoutAttempts = 0
+		assert.NoError(t, repo.ConfigurePasswordPolicy(policy))
+
+		assert.Contains(t, mockCommander.ExecutedCommands, "pam-auth-update --package --remove hardn-faillock hardn-faillock-notify")
+		assert.NotContains(t, mockFS.Files, "/usr/share/pam-configs/hardn-faillock")
+		assert.NotContains(t, mockFS.Files, "/usr/share/pam-configs/hardn-faillock-notify")
+	})
+}
+
+func TestOSPasswordPolicyRepository_RHEL(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSPasswordPolicyRepository(mockFS, mockCommander, "rocky")
+
+	assert.NoError(t, repo.ConfigurePasswordPolicy(model.DefaultPasswordPolicy()))
+
+	assert.NotContains(t, mockCommander.ExecutedCommands, "dnf install -y libpwquality")
+	assert.Contains(t, mockCommander.ExecutedCommands, "authselect enable-feature with-faillock")
+	assert.Contains(t, string(mockFS.Files[model.FaillockConfigPath]), "deny = 5\n")
+
+	// Options on the PAM line take precedence over the configuration files
+	mockFS.Files[model.FaillockConfigPath] = []byte("deny = 5\n")
+	mockFS.Files["/etc/pam.d/system-auth"] = []byte("auth required pam_faillock.so preauth silent deny=4\n" +
+		"password requisite pam_pwquality.so local_users_only\n")
+
+	status, err := repo.GetPasswordPolicyStatus()
+	assert.NoError(t, err)
+	assert.True(t, status.Lockout)
+	assert.Equal(t, 4, status.LockoutAttempts)
+	assert.True(t, status.Complexity)
+	assert.Equal(t, 14, status.MinLength)
+
+	// authselect must own the PAM configuration
+	mockCommander.CommandErrors["authselect current"] = errors.New("no configuration is selected")
+	assert.ErrorContains(t, repo.ConfigurePasswordPolicy(model.DefaultPasswordPolicy()), "not managed by authselect")
+}
+
+func TestOSPasswordPolicyRepository_Alpine(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSPasswordPolicyRepository(mockFS, mockCommander, "alpine")
+
+	assert.NoError(t, repo.ConfigurePasswordPolicy(model.DefaultPasswordPolicy()))
+
+	assert.Equal(t, []string{"apk add shadow"}, mockCommander.ExecutedCommands)
+	assert.Equal(t, "# Managed by hardn: password policy\nPASS_MAX_DAYS\t365\nPASS_MIN_DAYS\t1\nPASS_WARN_AGE\t7\n",
+		string(mockFS.Files[model.LoginDefsPath]))
+	assert.NotContains(t, mockFS.Files, model.PwqualityConfigPath)
+	assert.NotContains(t, mockFS.Files, model.FaillockConfigPath)
+
+	status, err := repo.GetPasswordPolicyStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"no complexity check", "no lockout"}, status.Weaknesses())
+}
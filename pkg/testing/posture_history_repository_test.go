@@ -0,0 +1,84 @@
+// pkg/testing/posture_history_repository_test.go
+package testing
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilePostureHistoryRepository(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	repo := secondary.NewFilePostureHistoryRepository(mockFS, model.PostureHistoryPath)
+
+	snapshots, err := repo.ListSnapshots()
+	require.NoError(t, err)
+	assert.Empty(t, snapshots, "no history yet")
+
+	start := time.Date(2026, 10, 1, 2, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		require.NoError(t, repo.AppendSnapshot(model.PostureSnapshot{
+			Time:   start.Add(time.Duration(i) * time.Hour),
+			Source: model.PostureSourceStatus,
+			Score:  i,
+			Checks: map[string]bool{"ssh.root_login_disabled": i%2 == 0},
+		}, 3))
+	}
+
+	// Only the latest three are kept, one JSON document per line
+	data := string(mockFS.Files[model.PostureHistoryPath])
+	assert.Len(t, strings.Split(strings.TrimSpace(data), "\n"), 3)
+
+	// A line cut short is skipped
+	mockFS.Files[model.PostureHistoryPath] = append(mockFS.Files[model.PostureHistoryPath], []byte(`{"time":"2026-10`)...)
+	snapshots, err = repo.ListSnapshots()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 3)
+	assert.Equal(t, 1, snapshots[0].Score)
+	assert.Equal(t, 3, snapshots[2].Score)
+	assert.True(t, snapshots[1].Checks["ssh.root_login_disabled"])
+	assert.True(t, snapshots[2].Time.Equal(start.Add(3*time.Hour)))
+}
+
+func TestBuildPostureSnapshot(t *testing.T) {
+	status := &security.SecurityStatus{
+		FirewallEnabled:      true,
+		FirewallConfigured:   true,
+		PasswordAuthDisabled: true,
+		WebServers:           []model.WebServer{{Name: "nginx"}},
+	}
+	now := time.Date(2026, 10, 1, 2, 0, 0, 0, time.UTC)
+
+	snapshot := security.BuildPostureSnapshot(status, now, model.PostureSourceAudit)
+	assert.Equal(t, 4, snapshot.Score, "root login disabled, firewall enabled and default deny, password auth off")
+	assert.Equal(t, 8, snapshot.MaxScore)
+	assert.Equal(t, "High", snapshot.RiskLevel)
+	assert.True(t, snapshot.Checks["firewall.enabled"])
+	assert.False(t, snapshot.Checks["webserver.nginx.tls_baseline"])
+
+	// Enabling SSH root login is a regression even though the web server
+	// baseline was applied in the meantime
+	status.RootLoginEnabled = true
+	status.WebServers[0].BaselineApplied = true
+	later := security.BuildPostureSnapshot(status, now.Add(time.Hour), model.PostureSourceStatus)
+	assert.Equal(t, model.TrendRegressed, later.Trend(snapshot))
+	assert.Equal(t, []string{"ssh.root_login_disabled"}, later.Regressions(snapshot))
+	assert.Equal(t, []string{"webserver.nginx.tls_baseline"}, later.Improvements(snapshot))
+	assert.Equal(t, model.TrendSteady, later.Trend(later))
+
+	status.RootLoginEnabled = false
+	fixed := security.BuildPostureSnapshot(status, now.Add(2*time.Hour), model.PostureSourceMenu)
+	assert.Equal(t, model.TrendImproved, fixed.Trend(later))
+	assert.Equal(t, model.TrendImproved, fixed.Trend(snapshot))
+
+	assert.Equal(t, "TLS baseline applied to nginx", security.ControlTitle("webserver.nginx.tls_baseline"))
+	assert.Equal(t, "No certificates expiring within N days", security.ControlTitle("certificates.not_expiring"))
+	assert.Equal(t, "unknown.check", security.ControlTitle("unknown.check"))
+}
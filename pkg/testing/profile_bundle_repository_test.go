@@ -0,0 +1,103 @@
+// pkg/testing/profile_bundle_repository_test.go
+package testing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileProfileBundleRepository_RoundTrip(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	repo := secondary.NewFileProfileBundleRepository(mockFS, interfaces.NewMockCommander())
+
+	bundle := &model.ProfileBundle{
+		Kind:      model.ProfileBundleKind,
+		Format:    model.ProfileBundleFormat,
+		Name:      "web",
+		CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Hostname:  "web-01",
+		Config:    "sshPort: 2208\nsshProfile: strict\n",
+		Sysctl: []model.ProfileFile{
+			{Path: "/etc/sysctl.d/60-hardening.conf", Content: "kernel.kptr_restrict = 2\n"},
+		},
+	}
+	require.NoError(t, repo.WriteBundle("/root/web.yml", bundle))
+	// The configuration stays readable as a block
+	assert.Contains(t, string(mockFS.Files["/root/web.yml"]), "config: |\n  sshPort: 2208\n  sshProfile: strict\n")
+
+	read, err := repo.ParseBundle("/root/web.yml", mockFS.Files["/root/web.yml"])
+	require.NoError(t, err)
+	assert.Equal(t, bundle, read)
+
+	// Fields this version does not know are refused
+	_, err = repo.ParseBundle("/root/newer.yml", []byte("kind: hardn-profile\nformat: 1\nconfig: x\nfirewall: []\n"))
+	assert.ErrorContains(t, err, "field firewall not found")
+}
+
+func TestFileProfileBundleRepository_ListSysctlFiles(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewFileProfileBundleRepository(mockFS, mockCommander)
+
+	files, err := repo.ListSysctlFiles()
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	mockFS.Directories[model.SysctlDropInDir] = true
+	mockCommander.CommandOutputs["find /etc/sysctl.d -maxdepth 1 -type f -name *.conf"] = []byte(
+		"/etc/sysctl.d/60-hardening.conf\n/etc/sysctl.d/10-network-security.conf\n")
+	files, err = repo.ListSysctlFiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/etc/sysctl.d/10-network-security.conf", "/etc/sysctl.d/60-hardening.conf"}, files)
+}
+
+func TestFileProfileBundleRepository_Signature(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewFileProfileBundleRepository(mockFS, mockCommander)
+	mockFS.Files["/root/web.yml"] = []byte("kind: hardn-profile\n")
+
+	signature, err := repo.SignBundle("/root/web.yml", "ops@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "/root/web.yml.asc", signature)
+	assert.Contains(t, mockCommander.ExecutedCommands,
+		"gpg --batch --yes --armor --local-user ops@example.com --output /root/web.yml.asc --detach-sign /root/web.yml")
+
+	data := mockFS.Files["/root/web.yml"]
+	signers := []string{"AAAA BBBB CCCC DDDD EEEE  FFFF 0000 1111 2222 3333"}
+	verify := "INPUT:kind: hardn-profile\n|gpg --batch --status-fd 1 --verify /root/web.yml.asc -"
+
+	// Without a signature file the bundle is unsigned
+	signer, err := repo.VerifyBundle("/root/web.yml", data, signers)
+	require.NoError(t, err)
+	assert.Empty(t, signer)
+
+	// A signature is only trusted with a pinned signer
+	mockFS.Files["/root/web.yml.asc"] = []byte("-----BEGIN PGP SIGNATURE-----\n")
+	_, err = repo.VerifyBundle("/root/web.yml", data, nil)
+	assert.ErrorContains(t, err, "no profileSigners are configured")
+
+	// gpg verifies the data passed to it, signed here by a subkey of the
+	// pinned primary key
+	mockCommander.CommandOutputs[verify] = []byte("[GNUPG:] NEWSIG\n" +
+		"[GNUPG:] GOODSIG 0000111122223333 Ops <ops@example.com>\n" +
+		"[GNUPG:] VALIDSIG 9999888877776666555544443333222211110000 2026-03-01 1772366400 0 4 0 22 10 00 AAAABBBBCCCCDDDDEEEEFFFF0000111122223333\n")
+	signer, err = repo.VerifyBundle("/root/web.yml", data, signers)
+	require.NoError(t, err)
+	assert.Equal(t, "AAAABBBBCCCCDDDDEEEEFFFF0000111122223333", signer)
+
+	// A good signature by any other key of the keyring is refused
+	_, err = repo.VerifyBundle("/root/web.yml", data, []string{"1234567890ABCDEF1234567890ABCDEF12345678"})
+	assert.ErrorContains(t, err, "not made by a key of profileSigners")
+
+	mockCommander.CommandErrors[verify] = errors.New("exit status 1")
+	_, err = repo.VerifyBundle("/root/web.yml", data, signers)
+	assert.ErrorContains(t, err, "does not verify")
+}
@@ -0,0 +1,78 @@
+// pkg/testing/progress_test.go
+package testing
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/style"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressChecklist_Plain(t *testing.T) {
+	var out bytes.Buffer
+	checklist := style.NewProgressChecklistWriter(&out, "Run All", false)
+
+	checklist.Plan([]string{"SSH configuration", "Firewall"})
+	checklist.StepStarted("SSH configuration")
+	checklist.StepProgress(50, "writing sshd_config")
+	checklist.StepProgress(60, "")
+	checklist.StepCompleted("SSH configuration", nil)
+	checklist.StepStarted("Firewall")
+	checklist.StepCompleted("Firewall", errors.New("ufw not found"))
+
+	assert.Equal(t, strings.Join([]string{
+		"Run All: 2 steps",
+		style.SymArrowRight + " [1/2] SSH configuration",
+		"   50% writing sshd_config",
+		"  " + style.SymCheckMark + " SSH configuration",
+		style.SymArrowRight + " [2/2] Firewall",
+		"  " + style.SymCrossMark + " Firewall: ufw not found",
+		"",
+	}, "\n"), style.StripAnsi(out.String()))
+}
+
+func TestProgressChecklist_Live(t *testing.T) {
+	var out bytes.Buffer
+	checklist := style.NewProgressChecklistWriter(&out, "Run All", true)
+
+	checklist.Plan([]string{"SSH configuration", "Firewall"})
+	out.Reset()
+	checklist.StepStarted("SSH configuration")
+	checklist.StepProgress(40, "writing sshd_config")
+
+	// Each redraw moves back over the header and both steps
+	assert.Equal(t, 2, strings.Count(out.String(), "\033[3A"))
+	last := out.String()[strings.LastIndex(out.String(), "\033[3A"):]
+	assert.Contains(t, style.StripAnsi(last), "0/2")
+	assert.Contains(t, style.StripAnsi(last), "40% writing sshd_config")
+}
+
+func TestProgressChecklist_UnplannedStep(t *testing.T) {
+	var out bytes.Buffer
+	checklist := style.NewProgressChecklistWriter(&out, "Run All", false)
+
+	checklist.Plan([]string{"Firewall"})
+	checklist.StepStarted("DNS")
+
+	assert.Contains(t, style.StripAnsi(out.String()), "[2/2] DNS")
+}
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		percent  int
+		expected string
+	}{
+		{0, "──────────"},
+		{50, "━━━━━─────"},
+		{100, "━━━━━━━━━━"},
+		{150, "━━━━━━━━━━"},
+		{-10, "──────────"},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, style.StripAnsi(style.ProgressBar(tc.percent, 10)), tc.percent)
+	}
+}
@@ -0,0 +1,112 @@
+// pkg/testing/proxmox_repository_test.go
+package testing
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// proxmoxlibSubscriptionCheck is the subscription test of proxmoxlib.js in
+// Proxmox VE 8
+const proxmoxlibSubscriptionCheck = `		success: function(response, opts) {
+		    let res = response.result;
+		    if (res === null || res === undefined || !res || res
+			.data.status.toLowerCase() !== 'active') {
+			Ext.Msg.show({
+			    title: gettext('No valid subscription'),
+`
+
+func TestOSProxmoxRepository_DisableSubscriptionNag(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[model.ProxmoxWidgetToolkitPath] = []byte(proxmoxlibSubscriptionCheck)
+	repo := secondary.NewOSProxmoxRepository(mockFS, interfaces.NewMockCommander(), "debian")
+
+	disabled, err := repo.SubscriptionNagDisabled()
+	require.NoError(t, err)
+	assert.False(t, disabled)
+
+	require.NoError(t, repo.DisableSubscriptionNag())
+	patched := string(mockFS.Files[model.ProxmoxWidgetToolkitPath])
+	assert.Contains(t, patched, ".data.status.toLowerCase() == 'NoMoreNagging') {")
+	assert.Contains(t, patched, "|| !res || res", "only the status test changes")
+	assert.Contains(t, string(mockFS.Files[model.ProxmoxNagHookPath]), "DPkg::Post-Invoke")
+
+	disabled, err = repo.SubscriptionNagDisabled()
+	require.NoError(t, err)
+	assert.True(t, disabled)
+
+	// Patching again changes nothing
+	require.NoError(t, repo.DisableSubscriptionNag())
+	assert.Equal(t, patched, string(mockFS.Files[model.ProxmoxWidgetToolkitPath]))
+
+	// Unknown versions are left alone
+	mockFS.Files[model.ProxmoxWidgetToolkitPath] = []byte("Ext.define('Proxmox.Utils', {});\n")
+	assert.ErrorContains(t, repo.DisableSubscriptionNag(), "not supported")
+}
+
+func TestUpdatePveproxyDefaults(t *testing.T) {
+	content := "# default: ALLOW_FROM=\"127.0.0.1\"\nLISTEN_IP=\"0.0.0.0\"\nCIPHERS=\"HIGH\"\nDENY_FROM=\"all\"\n"
+
+	updated := secondary.UpdatePveproxyDefaults(content, map[string]string{
+		"CIPHERS":            model.ProxmoxCiphers,
+		"HONOR_CIPHER_ORDER": "1",
+		"DENY_FROM":          "",
+	})
+	assert.Equal(t, "# default: ALLOW_FROM=\"127.0.0.1\"\nLISTEN_IP=\"0.0.0.0\"\n"+
+		"CIPHERS=\""+model.ProxmoxCiphers+"\"\n"+
+		"# Managed by hardn\nHONOR_CIPHER_ORDER=\"1\"\n", updated)
+
+	settings := secondary.ParsePveproxyDefaults(updated)
+	assert.Equal(t, map[string]string{
+		"LISTEN_IP":          "0.0.0.0",
+		"CIPHERS":            model.ProxmoxCiphers,
+		"HONOR_CIPHER_ORDER": "1",
+	}, settings)
+}
+
+func TestOSProxmoxRepository_Pveproxy(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	commander := interfaces.NewMockCommander()
+	repo := secondary.NewOSProxmoxRepository(mockFS, commander, "debian")
+
+	// A missing file has no settings
+	settings, err := repo.ReadPveproxyDefaults()
+	require.NoError(t, err)
+	assert.Empty(t, settings)
+
+	require.NoError(t, repo.WritePveproxyDefaults(map[string]string{"ALLOW_FROM": "10.0.0.0/24,192.168.1.5"}))
+	settings, err = repo.ReadPveproxyDefaults()
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/24,192.168.1.5", settings["ALLOW_FROM"])
+
+	require.NoError(t, repo.RestartPveproxy())
+	assert.Equal(t, []string{"systemctl restart pveproxy"}, commander.ExecutedCommands)
+}
+
+func TestOSProxmoxRepository_RealmTwoFactor(t *testing.T) {
+	commander := interfaces.NewMockCommander()
+	commander.CommandOutputs["pveum realm list --output-format json"] = []byte(`[
+		{"realm":"pam","type":"pam","comment":"Linux PAM standard authentication"},
+		{"realm":"pve","type":"pve","tfa":"type=oath,step=30","comment":"Proxmox VE authentication server"}
+	]`)
+	repo := secondary.NewOSProxmoxRepository(interfaces.NewMockFileSystem(), commander, "debian")
+
+	tfa, err := repo.GetRealmTwoFactor("pve")
+	require.NoError(t, err)
+	assert.Equal(t, "oath", tfa)
+
+	tfa, err = repo.GetRealmTwoFactor("pam")
+	require.NoError(t, err)
+	assert.Empty(t, tfa)
+
+	_, err = repo.GetRealmTwoFactor("ldap")
+	assert.ErrorContains(t, err, "realm ldap not found")
+
+	require.NoError(t, repo.SetRealmTwoFactor("pve", "oath"))
+	assert.Contains(t, commander.ExecutedCommands, "pveum realm modify pve --tfa type=oath")
+}
@@ -0,0 +1,32 @@
+// pkg/testing/reachability_repository_test.go
+package testing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOSReachabilityRepository_ProbeSSH(t *testing.T) {
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSReachabilityRepository(interfaces.NewMockFileSystem(), mockCommander)
+
+	// Locally the host keys are scanned directly
+	mockCommander.CommandOutputs["ssh-keyscan -T 10 -p 2222 127.0.0.1"] = []byte(
+		"# 127.0.0.1:2222 SSH-2.0-OpenSSH_9.6\n[127.0.0.1]:2222 ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIB\n")
+	assert.NoError(t, repo.ProbeSSH("", "127.0.0.1", 2222, 10*time.Second))
+
+	// ssh-keyscan exits 0 when nothing answers, printing nothing but comments
+	mockCommander.CommandOutputs["ssh-keyscan -T 10 -p 22 127.0.0.1"] = []byte("# 127.0.0.1:22 SSH-2.0-OpenSSH_9.6\n")
+	assert.ErrorContains(t, repo.ProbeSSH("", "127.0.0.1", 22, 10*time.Second), "no SSH server answered")
+
+	// Through a helper the scan runs on the helper host
+	mockCommander.CommandErrors["ssh -o BatchMode=yes -o ConnectTimeout=5 probe@bastion ssh-keyscan -T 5 -p 22 203.0.113.10"] =
+		errors.New("exit status 255")
+	err := repo.ProbeSSH("probe@bastion", "203.0.113.10", 22, 5*time.Second)
+	assert.ErrorContains(t, err, "exit status 255")
+}
@@ -0,0 +1,164 @@
+// pkg/testing/release_assets_test.go
+package testing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/version"
+	"github.com/stretchr/testify/assert"
+)
+
+const releaseDownload = "https://github.com/abbott/hardn/releases/download/v0.4.0/"
+
+func testRelease() version.GitHubRelease {
+	names := []string{
+		"hardn-linux-amd64", "hardn-linux-amd64.intoto.jsonl",
+		"hardn-linux-amd64.sig", "hardn-linux-amd64.crt", "hardn-linux-amd64.sha256",
+		"hardn-linux-arm64", "checksums.txt",
+	}
+	release := version.GitHubRelease{TagName: "v0.4.0"}
+	for _, name := range names {
+		release.Assets = append(release.Assets, version.ReleaseAsset{Name: name, BrowserDownloadURL: releaseDownload + name})
+	}
+	return release
+}
+
+func TestReleaseAssetsFor(t *testing.T) {
+	assets := version.ReleaseAssetsFor(testRelease(), "linux", "amd64")
+	assert.Equal(t, releaseDownload+"hardn-linux-amd64", assets.BinaryURL)
+	assert.Equal(t, releaseDownload+"hardn-linux-amd64.sha256", assets.ChecksumURL)
+	assert.Equal(t, releaseDownload+"hardn-linux-amd64.sig", assets.SignatureURL)
+	assert.Equal(t, releaseDownload+"hardn-linux-amd64.crt", assets.CertificateURL)
+	// No installer asset: the script is pinned to the release tag
+	assert.Equal(t, "https://raw.githubusercontent.com/abbott/hardn/v0.4.0/install.sh", assets.InstallScriptURL)
+
+	// Without a per-binary checksum the release-wide file is used
+	assets = version.ReleaseAssetsFor(testRelease(), "linux", "arm64")
+	assert.Equal(t, releaseDownload+"hardn-linux-arm64", assets.BinaryURL)
+	assert.Equal(t, releaseDownload+"checksums.txt", assets.ChecksumURL)
+
+	// Platforms without a build get no binary URL
+	assets = version.ReleaseAssetsFor(testRelease(), "freebsd", "amd64")
+	assert.Empty(t, assets.BinaryURL)
+
+	release := testRelease()
+	release.Assets = append(release.Assets, version.ReleaseAsset{Name: "install.sh", BrowserDownloadURL: releaseDownload + "install.sh"})
+	assets = version.ReleaseAssetsFor(release, "linux", "amd64")
+	assert.Equal(t, releaseDownload+"install.sh", assets.InstallScriptURL)
+	assert.Equal(t, "curl -sSL "+releaseDownload+"install.sh | sudo sh", version.InstallCommand(assets.InstallScriptURL))
+
+	// goreleaser names the checksum file after the version and signs it
+	release = version.GitHubRelease{TagName: "v0.4.0"}
+	for _, name := range []string{"hardn-linux-amd64", "hardn-0.4.0-checksums.txt",
+		"hardn-0.4.0-checksums.txt.sig", "hardn-0.4.0-checksums.txt.crt", "hardn-linux-amd64.minisig"} {
+		release.Assets = append(release.Assets, version.ReleaseAsset{Name: name, BrowserDownloadURL: releaseDownload + name})
+	}
+	assets = version.ReleaseAssetsFor(release, "linux", "amd64")
+	assert.Equal(t, releaseDownload+"hardn-0.4.0-checksums.txt", assets.ChecksumURL)
+	assert.Equal(t, releaseDownload+"hardn-0.4.0-checksums.txt.sig", assets.ChecksumSignatureURL)
+	assert.Equal(t, releaseDownload+"hardn-0.4.0-checksums.txt.crt", assets.ChecksumCertificateURL)
+	assert.Equal(t, releaseDownload+"hardn-linux-amd64.minisig", assets.MinisignURL)
+	assert.True(t, assets.Signed())
+	assert.False(t, version.ReleaseAssetsFor(testRelease(), "linux", "arm64").Signed())
+}
+
+func TestParseChecksum(t *testing.T) {
+	digest := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+
+	sum, err := version.ParseChecksum(digest+"\n", "hardn-linux-amd64")
+	assert.NoError(t, err)
+	assert.Equal(t, digest, sum)
+
+	sum, err = version.ParseChecksum("0000  hardn-linux-arm64\n"+digest+" *hardn-linux-amd64\n", "hardn-linux-amd64")
+	assert.NoError(t, err)
+	assert.Equal(t, digest, sum)
+
+	_, err = version.ParseChecksum(digest+"  hardn-linux-arm64\n", "hardn-linux-amd64")
+	assert.Error(t, err)
+
+	_, err = version.ParseChecksum("not-a-digest\n", "hardn-linux-amd64")
+	assert.Error(t, err)
+}
+
+func TestVerifyRelease(t *testing.T) {
+	digest := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/checksums.txt":
+			w.Write([]byte(digest + "  hardn-linux-amd64\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	assets := version.ReleaseAssets{
+		BinaryName:  "hardn-linux-amd64",
+		BinaryURL:   server.URL + "/hardn-linux-amd64",
+		ChecksumURL: server.URL + "/checksums.txt",
+	}
+	verification := version.VerifyRelease(context.Background(), assets)
+	assert.Equal(t, version.VerificationChecksum, verification.Status)
+	assert.Equal(t, digest, verification.Checksum)
+	assert.Equal(t, "checksum only", verification.Label())
+
+	// A checksum file that leaves out the binary fails
+	assets.BinaryName = "hardn-linux-arm64"
+	verification = version.VerifyRelease(context.Background(), assets)
+	assert.True(t, verification.Failed())
+	assert.Contains(t, verification.Detail, "no checksum for hardn-linux-arm64")
+
+	// A checksum that cannot be downloaded leaves the release unverified
+	assets.ChecksumURL = server.URL + "/missing.txt"
+	assert.Equal(t, version.VerificationUnverified, version.VerifyRelease(context.Background(), assets).Status)
+	assets.ChecksumURL = ""
+	assert.Equal(t, "no checksum published", version.VerifyRelease(context.Background(), assets).Detail)
+}
+
+func TestDownloadBinary(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho hardn\n")
+	sum := sha256.Sum256(binary)
+	digest := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/hardn-linux-amd64":
+			w.Write(binary)
+		case "/good.sha256":
+			w.Write([]byte(digest + "  hardn-linux-amd64\n"))
+		case "/bad.sha256":
+			w.Write([]byte("9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "hardn")
+	assets := version.ReleaseAssets{
+		BinaryName:  "hardn-linux-amd64",
+		BinaryURL:   server.URL + "/hardn-linux-amd64",
+		ChecksumURL: server.URL + "/good.sha256",
+	}
+
+	got, err := version.DownloadBinary(context.Background(), assets, dest)
+	assert.NoError(t, err)
+	assert.Equal(t, digest, got)
+	data, _ := os.ReadFile(dest)
+	assert.Equal(t, binary, data)
+
+	// A mismatched checksum leaves nothing behind
+	badDest := filepath.Join(t.TempDir(), "hardn")
+	assets.ChecksumURL = server.URL + "/bad.sha256"
+	_, err = version.DownloadBinary(context.Background(), assets, badDest)
+	assert.ErrorContains(t, err, "checksum mismatch")
+	_, err = os.Stat(badDest)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,161 @@
+// pkg/testing/remote_repository_test.go
+package testing
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exitError returns the error of a process that exited with code
+func exitError(t *testing.T, code string) error {
+	err := exec.Command("sh", "-c", "exit "+code).Run()
+	require.Error(t, err)
+	return err
+}
+
+func TestSSHRemoteRepository_LoadInventory(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/etc/hardn/inventory.yml"] = []byte(`defaults:
+  user: admin
+  identityFile: /root/.ssh/fleet
+hosts:
+  - name: web1
+    address: 192.0.2.10
+    groups: [web]
+  - name: db1
+    port: 2222
+    push: true
+`)
+	repo := secondary.NewSSHRemoteRepository(mockFS, interfaces.NewMockCommander())
+
+	inventory, err := repo.LoadInventory("/etc/hardn/inventory.yml")
+	require.NoError(t, err)
+	assert.Equal(t, "admin", inventory.Defaults.User)
+	require.Len(t, inventory.Hosts, 2)
+	assert.Equal(t, []string{"web"}, inventory.Hosts[0].Groups)
+	assert.Equal(t, 2222, inventory.Hosts[1].Port)
+	assert.True(t, *inventory.Hosts[1].Push)
+
+	_, err = repo.LoadInventory("/etc/hardn/missing.yml")
+	assert.Error(t, err)
+}
+
+func TestSSHRemoteRepository_RunHardn(t *testing.T) {
+	root := model.RemoteHost{Name: "web1", Address: "192.0.2.10", User: "root", Port: 22}
+	admin := model.RemoteHost{Name: "db1", Address: "db1", User: "admin", Port: 2222, IdentityFile: "/root/.ssh/fleet"}
+
+	tests := []struct {
+		name          string
+		host          model.RemoteHost
+		args          []string
+		err           error
+		expectedCmd   string
+		expectedCode  int
+		expectedError bool
+	}{
+		{
+			name:        "root",
+			host:        root,
+			args:        []string{"run-all", "--dry-run"},
+			expectedCmd: "ssh -o BatchMode=yes -o ConnectTimeout=10 -p 22 -- root@192.0.2.10 'hardn' 'run-all' '--dry-run'",
+		},
+		{
+			name:        "sudo and identity file",
+			host:        admin,
+			args:        []string{"status"},
+			expectedCmd: "ssh -o BatchMode=yes -o ConnectTimeout=10 -p 2222 -i /root/.ssh/fleet -- admin@db1 sudo -n 'hardn' 'status'",
+		},
+		{
+			name:        "quoted arguments",
+			host:        root,
+			args:        []string{"notes", "it's; rm -rf /"},
+			expectedCmd: `ssh -o BatchMode=yes -o ConnectTimeout=10 -p 22 -- root@192.0.2.10 'hardn' 'notes' 'it'\''s; rm -rf /'`,
+		},
+		{
+			name:         "hardn failed",
+			host:         root,
+			args:         []string{"audit"},
+			err:          exitError(t, "1"),
+			expectedCmd:  "ssh -o BatchMode=yes -o ConnectTimeout=10 -p 22 -- root@192.0.2.10 'hardn' 'audit'",
+			expectedCode: 1,
+		},
+		{
+			name:          "unreachable",
+			host:          root,
+			args:          []string{"audit"},
+			err:           exitError(t, "255"),
+			expectedCmd:   "ssh -o BatchMode=yes -o ConnectTimeout=10 -p 22 -- root@192.0.2.10 'hardn' 'audit'",
+			expectedCode:  255,
+			expectedError: true,
+		},
+		{
+			name:          "hardn not installed",
+			host:          root,
+			args:          []string{"audit"},
+			err:           exitError(t, "127"),
+			expectedCmd:   "ssh -o BatchMode=yes -o ConnectTimeout=10 -p 22 -- root@192.0.2.10 'hardn' 'audit'",
+			expectedCode:  127,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCommander := interfaces.NewMockCommander()
+			if tc.err != nil {
+				mockCommander.CommandErrors[tc.expectedCmd] = tc.err
+			}
+			repo := secondary.NewSSHRemoteRepository(interfaces.NewMockFileSystem(), mockCommander)
+
+			_, code, err := repo.RunHardn(tc.host, "hardn", tc.args)
+
+			assert.Equal(t, []string{tc.expectedCmd}, mockCommander.ExecutedCommands)
+			assert.Equal(t, tc.expectedCode, code)
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSSHRemoteRepository_PushBinary(t *testing.T) {
+	host := model.RemoteHost{Name: "v6", Address: "2001:db8::10", User: "admin", Port: 22}
+	prepare := "ssh -o BatchMode=yes -o ConnectTimeout=10 -p 22 -- admin@2001:db8::10 uname -m && mktemp -d"
+	cleanup := "ssh -o BatchMode=yes -o ConnectTimeout=10 -p 22 -- admin@2001:db8::10 rm -rf '/tmp/tmp.abc/'"
+
+	t.Run("copied", func(t *testing.T) {
+		mockCommander := interfaces.NewMockCommander()
+		mockCommander.CommandOutputs[prepare] = []byte(runtime.GOARCH + "\n/tmp/tmp.abc\n")
+		repo := secondary.NewSSHRemoteRepository(interfaces.NewMockFileSystem(), mockCommander)
+
+		path, err := repo.PushBinary(host, "/usr/local/bin/hardn")
+		require.NoError(t, err)
+		assert.Equal(t, "/tmp/tmp.abc/hardn", path)
+		assert.Equal(t, []string{
+			prepare,
+			"scp -q -p -o BatchMode=yes -o ConnectTimeout=10 -P 22 /usr/local/bin/hardn admin@[2001:db8::10]:/tmp/tmp.abc/hardn",
+		}, mockCommander.ExecutedCommands)
+
+		require.NoError(t, repo.RemoveBinary(host, path))
+		assert.Equal(t, cleanup, mockCommander.ExecutedCommands[2])
+	})
+
+	t.Run("other architecture", func(t *testing.T) {
+		mockCommander := interfaces.NewMockCommander()
+		mockCommander.CommandOutputs[prepare] = []byte("sparc64\n/tmp/tmp.abc\n")
+		repo := secondary.NewSSHRemoteRepository(interfaces.NewMockFileSystem(), mockCommander)
+
+		_, err := repo.PushBinary(host, "/usr/local/bin/hardn")
+		assert.ErrorContains(t, err, "sparc64")
+		assert.Equal(t, []string{prepare, cleanup}, mockCommander.ExecutedCommands)
+	})
+}
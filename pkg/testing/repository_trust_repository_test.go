@@ -0,0 +1,140 @@
+// pkg/testing/repository_trust_repository_test.go
+package testing
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAptSource_ParseAndFormat(t *testing.T) {
+	source, err := model.ParseAptSourceLine("deb [arch=amd64,arm64 signed-by=/etc/apt/keyrings/docker.asc] https://download.docker.com/linux/debian bookworm stable # docker")
+	require.NoError(t, err)
+	assert.Equal(t, "Types: deb\n"+
+		"URIs: https://download.docker.com/linux/debian\n"+
+		"Suites: bookworm\n"+
+		"Components: stable\n"+
+		"Architectures: amd64 arm64\n"+
+		"Signed-By: /etc/apt/keyrings/docker.asc\n", source.Deb822())
+
+	source, err = model.ParseAptSourceLine("  # deb http://deb.debian.org/debian bookworm main")
+	assert.NoError(t, err)
+	assert.Nil(t, source)
+	_, err = model.ParseAptSourceLine("deb http://deb.debian.org/debian")
+	assert.ErrorContains(t, err, "expected a URI and a suite")
+
+	sources := model.ParseDeb822Sources([]byte(`# Debian
+Types: deb deb-src
+URIs: http://deb.debian.org/debian
+Suites: bookworm bookworm-updates
+Components: main
+Signed-By: -----BEGIN PGP PUBLIC KEY BLOCK-----
+ .
+ mDMEZ...
+ -----END PGP PUBLIC KEY BLOCK-----
+
+Types: deb
+URIs: http://repo.example.com
+Suites: stable
+trusted: yes
+Enabled: no
+`))
+	require.Len(t, sources, 2)
+	assert.Equal(t, []string{"deb", "deb-src"}, sources[0].Types)
+	assert.Equal(t, []string{"bookworm", "bookworm-updates"}, sources[0].Suites)
+	assert.Equal(t, "-----BEGIN PGP PUBLIC KEY BLOCK-----\n\nmDMEZ...\n-----END PGP PUBLIC KEY BLOCK-----", sources[0].Options["signed-by"])
+	assert.Contains(t, sources[0].Deb822(), "Signed-By: -----BEGIN PGP PUBLIC KEY BLOCK-----\n .\n mDMEZ...\n")
+	assert.True(t, sources[1].Trusted(), "field names are read without regard to case")
+	assert.False(t, sources[1].Enabled)
+}
+
+func TestParseAptConfig(t *testing.T) {
+	options := model.ParseAptConfig([]byte(`// Allow everything
+APT::Get::AllowUnauthenticated "true";
+#clear Acquire::http
+Acquire {
+  http::Proxy "http://proxy:3128/"; /* a proxy */
+  AllowInsecureRepositories "1";
+};
+DPkg::Pre-Install-Pkgs {"/usr/sbin/dpkg-preconfigure --apt";};
+`))
+	assert.Equal(t, []model.AptConfigOption{
+		{Name: "APT::Get::AllowUnauthenticated", Value: "true"},
+		{Name: "Acquire::http::Proxy", Value: "http://proxy:3128/"},
+		{Name: "Acquire::AllowInsecureRepositories", Value: "1"},
+	}, options)
+}
+
+func TestOSRepositoryTrustRepository_Debian(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSRepositoryTrustRepository(mockFS, mockCommander, "debian")
+
+	mockFS.Files["/etc/apt/sources.list"] = []byte("deb http://deb.debian.org/debian bookworm main\n")
+	require.NoError(t, mockFS.MkdirAll("/etc/apt/sources.list.d", 0755))
+	mockFS.Files["/etc/apt/sources.list.d/docker.list"] = []byte("deb https://download.docker.com/linux/debian bookworm stable\n")
+	mockCommander.CommandOutputs["find /etc/apt/sources.list.d -mindepth 1 -maxdepth 1"] = []byte(
+		"/etc/apt/sources.list.d/docker.list\n/etc/apt/sources.list.d/old.list.save\n/etc/apt/sources.list.d/README\n")
+
+	files, err := repo.ReadSources()
+	require.NoError(t, err)
+	require.Len(t, files, 2, "backups and files without a sources extension are skipped")
+	assert.Equal(t, "/etc/apt/sources.list.d/docker.list", files[1].Path)
+
+	mockFS.Files["/etc/apt/trusted.gpg"] = []byte("binary keyring")
+	require.NoError(t, mockFS.MkdirAll("/etc/apt/trusted.gpg.d", 0755))
+	mockCommander.CommandOutputs["find /etc/apt/trusted.gpg.d -mindepth 1 -maxdepth 1"] = []byte(
+		"/etc/apt/trusted.gpg.d/debian-archive-bookworm-automatic.asc\n/etc/apt/trusted.gpg.d/vendor.gpg\n")
+	mockCommander.CommandOutputs["dpkg-query -S /etc/apt/trusted.gpg.d/debian-archive-bookworm-automatic.asc"] = []byte(
+		"debian-archive-keyring: /etc/apt/trusted.gpg.d/debian-archive-bookworm-automatic.asc\n")
+	mockCommander.CommandErrors["dpkg-query -S /etc/apt/trusted.gpg.d/vendor.gpg"] = assert.AnError
+
+	keys, err := repo.TrustedKeys()
+	require.NoError(t, err)
+	assert.Equal(t, []model.TrustedKey{
+		{Path: "/etc/apt/trusted.gpg"},
+		{Path: "/etc/apt/trusted.gpg.d/debian-archive-bookworm-automatic.asc", Owner: "debian-archive-keyring"},
+		{Path: "/etc/apt/trusted.gpg.d/vendor.gpg"},
+	}, keys)
+
+	mockCommander.CommandOutputs["INPUT:key|gpg --show-keys --with-colons"] = []byte(
+		"pub:-:4096:1:8D81803C0EBFCD88:1487788586:::-:::scESA::::::23::0:\n" +
+			"fpr:::::::::9DC858229FC7DD38854AE2D88D81803C0EBFCD88:\n" +
+			"uid:-::::1487792064::B5A08F01796E7F521861B449372D1FF271F2DD50::Docker Release (CE deb) <docker@docker.com>::::::::::0:\n" +
+			"sub:-:4096:1:7EA0A9C3F273FCD8:1487788586::::::s::::::23:\n" +
+			"fpr:::::::::D3306A018370199E527AE7997EA0A9C3F273FCD8:\n")
+	fingerprints, err := repo.KeyFingerprints([]byte("key"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"9DC858229FC7DD38854AE2D88D81803C0EBFCD88"}, fingerprints, "subkeys are left out")
+
+	removed, err := repo.RemoveSources("/etc/apt/sources.list.d/docker.list")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = repo.RemoveSources("/etc/apt/sources.list.d/docker.list")
+	require.NoError(t, err)
+	assert.False(t, removed)
+}
+
+func TestOSRepositoryTrustRepository_AlpineKeys(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSRepositoryTrustRepository(mockFS, mockCommander, "alpine")
+
+	require.NoError(t, mockFS.MkdirAll("/etc/apk/keys", 0755))
+	mockCommander.CommandOutputs["find /etc/apk/keys -mindepth 1 -maxdepth 1"] = []byte(
+		"/etc/apk/keys/alpine-devel@lists.alpinelinux.org-6165ee59.rsa.pub\n/etc/apk/keys/ops-5f3a1c2b.rsa.pub\n")
+	mockCommander.CommandOutputs["apk info --who-owns /etc/apk/keys/alpine-devel@lists.alpinelinux.org-6165ee59.rsa.pub"] = []byte(
+		"/etc/apk/keys/alpine-devel@lists.alpinelinux.org-6165ee59.rsa.pub is owned by alpine-keys-2.4-r1\n")
+	mockCommander.CommandErrors["apk info --who-owns /etc/apk/keys/ops-5f3a1c2b.rsa.pub"] = assert.AnError
+
+	keys, err := repo.TrustedKeys()
+	require.NoError(t, err)
+	assert.Equal(t, []model.TrustedKey{
+		{Path: "/etc/apk/keys/alpine-devel@lists.alpinelinux.org-6165ee59.rsa.pub", Owner: "alpine-keys-2.4-r1"},
+		{Path: "/etc/apk/keys/ops-5f3a1c2b.rsa.pub"},
+	}, keys)
+}
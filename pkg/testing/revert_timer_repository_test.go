@@ -0,0 +1,78 @@
+// pkg/testing/revert_timer_repository_test.go
+package testing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMarkerDir = "/run/hardn/revert"
+
+func TestSystemRevertTimerRepository_Systemd(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewSystemRevertTimerRepository(mockFS, mockCommander, testMarkerDir, "debian")
+
+	rollback := []string{"/usr/local/bin/hardn", "rollback", "20260301-030000", "--yes"}
+	require.NoError(t, repo.ArmRevert("20260301-030000", rollback, 5*time.Minute))
+	assert.Contains(t, mockFS.Files, testMarkerDir+"/20260301-030000")
+
+	require.Len(t, mockCommander.ExecutedCommands, 1)
+	command := mockCommander.ExecutedCommands[0]
+	assert.True(t, strings.HasPrefix(command, "systemd-run --unit=hardn-revert-20260301-030000 "), command)
+	assert.True(t, strings.HasSuffix(command,
+		" "+testMarkerDir+"/20260301-030000 300 /usr/local/bin/hardn rollback 20260301-030000 --yes"), command)
+
+	require.NoError(t, repo.ExtendRevert("20260301-030000"))
+
+	disarmed, err := repo.DisarmRevert("20260301-030000")
+	require.NoError(t, err)
+	assert.True(t, disarmed)
+	assert.NotContains(t, mockFS.Files, testMarkerDir+"/20260301-030000")
+
+	// A timer that fired removed its marker itself
+	disarmed, err = repo.DisarmRevert("20260301-030000")
+	require.NoError(t, err)
+	assert.False(t, disarmed)
+	assert.Error(t, repo.ExtendRevert("20260301-030000"))
+}
+
+func TestSystemRevertTimerRepository_Alpine(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewSystemRevertTimerRepository(mockFS, mockCommander, testMarkerDir, "alpine")
+
+	require.NoError(t, repo.ArmRevert("20260301-030000", []string{"hardn", "rollback", "20260301-030000", "--yes"}, time.Minute))
+	require.Len(t, mockCommander.ExecutedCommands, 1)
+	assert.True(t, strings.HasPrefix(mockCommander.ExecutedCommands[0], "sh -c setsid "), "the watcher is detached from the session")
+}
+
+func TestSystemRevertTimerRepository_ArmFails(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewSystemRevertTimerRepository(mockFS, mockCommander, testMarkerDir, "debian")
+
+	assert.Error(t, repo.ArmRevert("../etc", []string{"hardn"}, time.Minute), "run IDs name the marker file")
+
+	// Without a watcher the marker would disarm nothing, so it is removed
+	rollback := []string{"hardn", "rollback", "20260301-030000", "--yes"}
+	mockCommander.CommandErrors[armedCommand(t, rollback)] = errors.New("systemd-run: not found")
+	assert.ErrorContains(t, repo.ArmRevert("20260301-030000", rollback, time.Minute), "not found")
+	assert.NotContains(t, mockFS.Files, testMarkerDir+"/20260301-030000")
+}
+
+// armedCommand returns the command ArmRevert runs for rollback on systemd
+func armedCommand(t *testing.T, rollback []string) string {
+	t.Helper()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewSystemRevertTimerRepository(interfaces.NewMockFileSystem(), mockCommander, testMarkerDir, "debian")
+	require.NoError(t, repo.ArmRevert("20260301-030000", rollback, time.Minute))
+	return mockCommander.ExecutedCommands[0]
+}
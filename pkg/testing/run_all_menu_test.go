@@ -0,0 +1,27 @@
+// pkg/testing/run_all_menu_test.go
+package testing
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/menu"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunAllMenu_DryRunToggleRefusedWhileLocked(t *testing.T) {
+	player := session.NewPlayer([]session.Event{
+		{Kind: session.KindInput, Input: session.InputMenu, Screen: "Run All", Value: "2"},
+	})
+	menu.SetSessionPlayer(player)
+	defer menu.SetSessionPlayer(nil)
+
+	// Without a menu manager, a run that got through would panic
+	cfg := &config.Config{DryRun: true, MaintenanceLocked: true}
+	menu.NewRunAllMenu(nil, cfg, &osdetect.OSInfo{OsType: "debian"}).Show()
+
+	assert.True(t, cfg.DryRun, "dry-run must stay enabled outside the maintenance window")
+	assert.Zero(t, player.Remaining())
+}
@@ -0,0 +1,199 @@
+// pkg/testing/run_journal_test.go
+package testing
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRunsDir = "/var/lib/hardn/runs"
+
+func TestRunJournal_Files(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/etc/ssh/sshd_config"] = []byte("PermitRootLogin yes\n")
+	mockCommander := interfaces.NewMockCommander()
+
+	journal := secondary.NewRunJournal(mockFS, mockCommander, testRunsDir, "hardn ssh set", "/var/backups/hardn")
+	journal.SetVersion("0.3.2")
+	fs := journal.FileSystem()
+	assert.Empty(t, journal.RunID(), "nothing is recorded before a change")
+
+	require.NoError(t, fs.WriteFile("/etc/ssh/sshd_config", []byte("PermitRootLogin no\n"), 0644))
+	require.NoError(t, fs.WriteFile("/etc/ssh/sshd_config", []byte("PermitRootLogin no\nPort 2222\n"), 0644))
+	require.NoError(t, fs.WriteFile("/etc/hardn/new.conf", []byte("new\n"), 0644))
+	require.NoError(t, fs.WriteFile("/var/backups/hardn/sshd_config.bak", []byte("ignored\n"), 0644))
+	require.NoError(t, journal.Err())
+
+	id := journal.RunID()
+	require.NotEmpty(t, id)
+
+	repo := secondary.NewFileRunRepository(mockFS, mockCommander, testRunsDir)
+	runs, err := repo.ListRuns()
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+
+	run := runs[0]
+	assert.Equal(t, id, run.ID)
+	assert.Equal(t, "hardn ssh set", run.Description)
+	assert.Equal(t, "0.3.2", run.Version)
+	require.Len(t, run.Changes, 2, "a file is saved once and excluded directories are skipped")
+	assert.Equal(t, "file /etc/ssh/sshd_config modified", run.Changes[0].Describe())
+	assert.Equal(t, "file /etc/hardn/new.conf created", run.Changes[1].Describe())
+	assert.Equal(t, "PermitRootLogin yes\n", string(mockFS.Files[filepath.Join(testRunsDir, id, run.Changes[0].Backup)]))
+
+	// Restore the original file and remove the created one
+	require.NoError(t, repo.RevertChange(id, run.Changes[0], false))
+	require.NoError(t, repo.RevertChange(id, run.Changes[1], false))
+	assert.Equal(t, "PermitRootLogin yes\n", string(mockFS.Files["/etc/ssh/sshd_config"]))
+	assert.NotContains(t, mockFS.Files, "/etc/hardn/new.conf")
+}
+
+func TestRunJournal_ModifiedFile(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/etc/motd"] = []byte("before\n")
+	mockCommander := interfaces.NewMockCommander()
+
+	journal := secondary.NewRunJournal(mockFS, mockCommander, testRunsDir, "hardn")
+	require.NoError(t, journal.FileSystem().WriteFile("/etc/motd", []byte("hardened\n"), 0644))
+	mockFS.Files["/etc/motd"] = []byte("edited by hand\n")
+
+	repo := secondary.NewFileRunRepository(mockFS, mockCommander, testRunsDir)
+	run, err := repo.GetRun(journal.RunID())
+	require.NoError(t, err)
+
+	err = repo.RevertChange(run.ID, run.Changes[0], false)
+	assert.ErrorContains(t, err, "modified after the run")
+	assert.Equal(t, "edited by hand\n", string(mockFS.Files["/etc/motd"]))
+
+	require.NoError(t, repo.RevertChange(run.ID, run.Changes[0], true))
+	assert.Equal(t, "before\n", string(mockFS.Files["/etc/motd"]))
+}
+
+func TestRunJournal_Commands(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandErrors["dpkg-query -W -f="+secondary.DpkgStatusFormat+" ufw"] = errors.New("not installed")
+	mockCommander.CommandOutputs["dpkg-query -W -f="+secondary.DpkgStatusFormat+" curl"] = []byte("install ok installed\n")
+	mockCommander.CommandOutputs["systemctl is-enabled ssh"] = []byte("enabled\n")
+	mockCommander.CommandErrors["id -u deploy"] = errors.New("no such user")
+
+	journal := secondary.NewRunJournal(mockFS, mockCommander, testRunsDir, "hardn")
+	commander := journal.Commander()
+
+	_, err := commander.Execute("apt-get", "install", "-y", "ufw", "curl")
+	require.NoError(t, err)
+	_, err = commander.Execute("systemctl", "restart", "ssh")
+	require.NoError(t, err)
+	_, err = commander.Execute("systemctl", "reload", "ssh")
+	require.NoError(t, err)
+	_, err = commander.Execute("useradd", "-m", "deploy")
+	require.NoError(t, err)
+	_, err = commander.Execute("systemctl", "status", "ssh")
+	require.NoError(t, err)
+
+	repo := secondary.NewFileRunRepository(mockFS, mockCommander, testRunsDir)
+	run, err := repo.GetRun(journal.RunID())
+	require.NoError(t, err)
+	require.Len(t, run.Changes, 3)
+
+	assert.Equal(t, model.RunChangePackage, run.Changes[0].Kind)
+	assert.Equal(t, [][]string{{"apt-get", "remove", "--yes", "ufw"}}, run.Changes[0].Undo)
+
+	assert.Equal(t, "service ssh was running, enabled", run.Changes[1].Describe())
+	assert.Equal(t, [][]string{
+		{"systemctl", "daemon-reload"},
+		{"systemctl", "enable", "ssh"},
+		{"systemctl", "restart", "ssh"},
+	}, run.Changes[1].Undo)
+
+	assert.Equal(t, [][]string{{"userdel", "deploy"}}, run.Changes[2].Undo)
+
+	require.NoError(t, repo.RevertChange(run.ID, run.Changes[0], false))
+	assert.Contains(t, mockCommander.ExecutedCommands, "apt-get remove --yes ufw")
+}
+
+func TestRunJournal_FailedInstallNotRecorded(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandErrors["apk add fail2ban"] = errors.New("network unreachable")
+	mockCommander.CommandErrors["apk info -e fail2ban"] = errors.New("missing")
+
+	journal := secondary.NewRunJournal(mockFS, mockCommander, testRunsDir, "hardn")
+	_, err := journal.Commander().Execute("apk", "add", "fail2ban")
+	assert.Error(t, err)
+	assert.Empty(t, journal.RunID())
+	assert.NotContains(t, mockFS.Files, filepath.Join(testRunsDir, "index.json"))
+}
+
+func TestRunJournal_RHELCommands(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/etc/firewalld/zones/public.xml"] = []byte("<zone/>\n")
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandErrors["rpm -q firewalld"] = errors.New("package firewalld is not installed")
+	mockCommander.CommandOutputs["firewall-cmd --get-default-zone"] = []byte("public\n")
+
+	journal := secondary.NewRunJournal(mockFS, mockCommander, testRunsDir, "hardn")
+	commander := journal.Commander()
+
+	_, err := commander.Execute("dnf", "install", "-y", "firewalld")
+	require.NoError(t, err)
+	_, err = commander.Execute("firewall-cmd", "--permanent", "--zone=public", "--add-port=22/tcp")
+	require.NoError(t, err)
+	_, err = commander.Execute("firewall-cmd", "--permanent", "--zone=public", "--add-port=80/tcp")
+	require.NoError(t, err)
+
+	repo := secondary.NewFileRunRepository(mockFS, mockCommander, testRunsDir)
+	run, err := repo.GetRun(journal.RunID())
+	require.NoError(t, err)
+
+	var undo [][]string
+	var files []string
+	for _, change := range run.Changes {
+		if change.Kind == model.RunChangeFile {
+			files = append(files, change.Path)
+		}
+		undo = append(undo, change.Undo...)
+	}
+	assert.Contains(t, undo, []string{"dnf", "remove", "-y", "firewalld"})
+	assert.Contains(t, undo, []string{"systemctl", "try-restart", "firewalld"})
+	assert.Equal(t, []string{"/etc/firewalld/zones/public.xml"}, files, "the zone is saved once")
+}
+
+func TestRunJournal_GuardChanges(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandOutputs["ufw status"] = []byte("Status: active\n")
+
+	journal := secondary.NewRunJournal(mockFS, mockCommander, testRunsDir, "hardn -r")
+	var guarded []string
+	journal.GuardChanges(func(runID string) {
+		if len(guarded) == 0 {
+			// The change is recorded before the guard runs, but not yet made
+			assert.NotContains(t, mockFS.Files, "/etc/ssh/sshd_config.d/hardn.conf")
+		}
+		guarded = append(guarded, runID)
+	}, model.AreaSSH, model.AreaFirewall)
+	fs := journal.FileSystem()
+	commander := journal.Commander()
+
+	require.NoError(t, fs.WriteFile("/etc/motd", []byte("welcome\n"), 0644))
+	assert.Empty(t, guarded, "changes to other areas are not guarded")
+
+	require.NoError(t, fs.WriteFile("/etc/ssh/sshd_config.d/hardn.conf", []byte("Port 2208\n"), 0644))
+	_, err := commander.Execute("systemctl", "restart", "ssh.service")
+	require.NoError(t, err)
+	_, err = commander.Execute("ufw", "allow", "2208/tcp")
+	require.NoError(t, err)
+	_, err = commander.Execute("ufw", "status")
+	require.NoError(t, err)
+
+	id := journal.RunID()
+	assert.Equal(t, []string{id, id, id}, guarded, "each change is guarded, queries are not")
+}
@@ -0,0 +1,158 @@
+// pkg/testing/schedule_repository_test.go
+package testing
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/domain/service"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSchedule(t *testing.T, tasks ...string) model.Schedule {
+	t.Helper()
+	schedule, err := service.BuildSchedule("weekly", tasks, "/usr/local/bin/hardn", "/etc/hardn/my config.yml", false)
+	require.NoError(t, err)
+	return schedule
+}
+
+func TestSystemScheduleRepository_Systemd(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	unit := "hardn-schedule.timer"
+	mockCommander.CommandOutputs["systemctl is-active "+unit] = []byte("active\n")
+	mockCommander.CommandOutputs["systemctl show "+unit+" --property=NextElapseUSecRealtime --value"] = []byte("Sun 2026-10-18 03:00:00 UTC\n")
+
+	repo := secondary.NewSystemScheduleRepository(mockFS, mockCommander, "debian")
+
+	require.NoError(t, repo.InstallSchedule(testSchedule(t, "firewall", "audit")))
+
+	unitFile := string(mockFS.Files[model.ScheduleServicePath])
+	assert.Contains(t, unitFile, "Type=oneshot\n")
+	assert.Contains(t, unitFile, "ExecStart=/usr/local/bin/hardn --config '/etc/hardn/my config.yml' --configure-ufw\n")
+	assert.Contains(t, unitFile, "ExecStart=/usr/local/bin/hardn audit --config '/etc/hardn/my config.yml'\n")
+
+	timer := string(mockFS.Files[model.ScheduleTimerPath])
+	assert.Contains(t, timer, "OnCalendar=Sun *-*-* 03:00:00\n")
+	assert.Contains(t, timer, "Persistent=true\n")
+	assert.Contains(t, mockCommander.ExecutedCommands, "systemctl enable --now "+unit)
+
+	status, err := repo.GetScheduleStatus()
+	require.NoError(t, err)
+	assert.True(t, status.Installed)
+	assert.True(t, status.Active)
+	assert.Equal(t, model.ScheduleBackendSystemd, status.Backend)
+	assert.Equal(t, "weekly", status.Interval)
+	assert.Len(t, status.Commands, 2)
+	assert.Equal(t, "Sun 2026-10-18 03:00:00 UTC", status.NextRun)
+
+	require.NoError(t, repo.RemoveSchedule())
+	assert.NotContains(t, mockFS.Files, model.ScheduleServicePath)
+	assert.NotContains(t, mockFS.Files, model.ScheduleTimerPath)
+	assert.Contains(t, mockCommander.ExecutedCommands, "systemctl disable --now "+unit)
+
+	status, err = repo.GetScheduleStatus()
+	require.NoError(t, err)
+	assert.False(t, status.Installed)
+}
+
+func TestSystemScheduleRepository_RefusesForeignUnit(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[model.ScheduleTimerPath] = []byte("[Timer]\nOnCalendar=daily\n")
+
+	repo := secondary.NewSystemScheduleRepository(mockFS, interfaces.NewMockCommander(), "ubuntu")
+
+	assert.Error(t, repo.InstallSchedule(testSchedule(t, "audit")))
+	assert.Error(t, repo.RemoveSchedule())
+	assert.Equal(t, "[Timer]\nOnCalendar=daily\n", string(mockFS.Files[model.ScheduleTimerPath]))
+}
+
+func TestSystemScheduleRepository_AlpineCrontab(t *testing.T) {
+	existing := "# min hour day month weekday command\n*/15 * * * * run-parts /etc/periodic/15min\n"
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[model.ScheduleCrontabPath] = []byte(existing)
+	mockCommander := interfaces.NewMockCommander()
+
+	repo := secondary.NewSystemScheduleRepository(mockFS, mockCommander, "alpine")
+
+	require.NoError(t, repo.InstallSchedule(testSchedule(t, "audit")))
+	// Reinstalling replaces the block instead of adding another
+	require.NoError(t, repo.InstallSchedule(testSchedule(t, "dns", "audit")))
+
+	crontab := string(mockFS.Files[model.ScheduleCrontabPath])
+	assert.Contains(t, crontab, existing)
+	assert.Contains(t, crontab, "0 3 * * 0 { /usr/local/bin/hardn --config '/etc/hardn/my config.yml' --configure-dns && "+
+		"/usr/local/bin/hardn audit --config '/etc/hardn/my config.yml'; } >>/var/log/hardn-schedule.log 2>&1\n")
+	assert.Equal(t, 1, strings.Count(crontab, "Managed by hardn"))
+	assert.Equal(t, "root\n", string(mockFS.Files["/etc/crontabs/cron.update"]))
+	assert.Contains(t, mockCommander.ExecutedCommands, "rc-update add crond default")
+
+	status, err := repo.GetScheduleStatus()
+	require.NoError(t, err)
+	assert.True(t, status.Installed)
+	assert.Equal(t, model.ScheduleBackendCron, status.Backend)
+	assert.Equal(t, "weekly", status.Interval)
+	assert.Len(t, status.Commands, 1)
+
+	require.NoError(t, repo.RemoveSchedule())
+	assert.Equal(t, existing, string(mockFS.Files[model.ScheduleCrontabPath]))
+}
+
+func TestSystemScheduleRepository_Summary(t *testing.T) {
+	schedule := testSchedule(t, "audit")
+	summary, err := service.BuildSummarySchedule("0 8 * * 1", "/usr/local/bin/hardn", "/etc/hardn/hardn.yml")
+	require.NoError(t, err)
+	schedule.Summary = summary
+
+	t.Run("systemd", func(t *testing.T) {
+		mockFS := interfaces.NewMockFileSystem()
+		mockCommander := interfaces.NewMockCommander()
+		repo := secondary.NewSystemScheduleRepository(mockFS, mockCommander, "debian")
+
+		require.NoError(t, repo.InstallSchedule(schedule))
+
+		assert.Contains(t, string(mockFS.Files[model.SummaryServicePath]),
+			"ExecStart=/usr/local/bin/hardn summary send --config /etc/hardn/hardn.yml\n")
+		assert.Contains(t, string(mockFS.Files[model.SummaryTimerPath]), "OnCalendar=Mon *-*-* 08:00:00\n")
+		assert.Contains(t, mockCommander.ExecutedCommands, "systemctl enable --now hardn-summary.timer")
+
+		status, err := repo.GetScheduleStatus()
+		require.NoError(t, err)
+		assert.Equal(t, "0 8 * * 1", status.Summary)
+		assert.Equal(t, "/usr/local/bin/hardn summary send --config /etc/hardn/hardn.yml", status.SummaryCommand)
+
+		// Reinstalling without a summary stops sending it
+		require.NoError(t, repo.InstallSchedule(testSchedule(t, "audit")))
+		assert.NotContains(t, mockFS.Files, model.SummaryTimerPath)
+		assert.NotContains(t, mockFS.Files, model.SummaryServicePath)
+		assert.Contains(t, mockCommander.ExecutedCommands, "systemctl disable --now hardn-summary.timer")
+
+		require.NoError(t, repo.InstallSchedule(schedule))
+		require.NoError(t, repo.RemoveSchedule())
+		assert.NotContains(t, mockFS.Files, model.SummaryTimerPath)
+	})
+
+	t.Run("alpine", func(t *testing.T) {
+		mockFS := interfaces.NewMockFileSystem()
+		repo := secondary.NewSystemScheduleRepository(mockFS, interfaces.NewMockCommander(), "alpine")
+
+		require.NoError(t, repo.InstallSchedule(schedule))
+
+		crontab := string(mockFS.Files[model.ScheduleCrontabPath])
+		assert.Contains(t, crontab, "# hardn posture summary: 0 8 * * 1\n"+
+			"0 8 * * 1 /usr/local/bin/hardn summary send --config /etc/hardn/hardn.yml >>/var/log/hardn-schedule.log 2>&1\n"+
+			"# End of hardn schedule\n")
+
+		status, err := repo.GetScheduleStatus()
+		require.NoError(t, err)
+		assert.Len(t, status.Commands, 1)
+		assert.Equal(t, "0 8 * * 1", status.Summary)
+
+		require.NoError(t, repo.RemoveSchedule())
+		assert.NotContains(t, string(mockFS.Files[model.ScheduleCrontabPath]), "summary")
+	})
+}
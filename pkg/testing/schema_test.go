@@ -0,0 +1,94 @@
+// pkg/testing/schema_test.go
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaDir holds the published schemas, relative to this package.
+// HARDN_UPDATE_SCHEMAS=1 rewrites them from the Go types, and releases
+// the schema of any version that has not been released yet.
+const schemaDir = "../schema/schemas"
+
+func TestSchemas_MatchTypes(t *testing.T) {
+	update := os.Getenv("HARDN_UPDATE_SCHEMAS") == "1"
+
+	for _, doc := range schema.Documents {
+		generated, err := schema.Generate(doc)
+		require.NoError(t, err, doc.Name)
+
+		if update {
+			require.NoError(t, os.WriteFile(filepath.Join(schemaDir, doc.Name+".json"), generated, 0644))
+			released := filepath.Join(schemaDir, "released", fmt.Sprintf("%s.v%d.json", doc.Name, doc.Version))
+			if _, err := os.Stat(released); os.IsNotExist(err) {
+				require.NoError(t, os.WriteFile(released, generated, 0644))
+			}
+			continue
+		}
+
+		published, err := schema.Published(doc.Name)
+		require.NoError(t, err, doc.Name)
+		assert.Equal(t, string(generated), string(published),
+			"%s schema is out of date with its type: run HARDN_UPDATE_SCHEMAS=1 go test ./pkg/testing/ -run TestSchemas", doc.Name)
+	}
+}
+
+func TestSchemas_CompatibleWithReleased(t *testing.T) {
+	for _, doc := range schema.Documents {
+		published, err := schema.Published(doc.Name)
+		require.NoError(t, err, doc.Name)
+
+		var parsed map[string]any
+		require.NoError(t, json.Unmarshal(published, &parsed), doc.Name)
+		assert.Equal(t, schema.Draft, parsed["$schema"])
+		assert.Equal(t, doc.ID(), parsed["$id"])
+
+		// Every version stays released, so a breaking change needs the next one
+		released, err := schema.Released(doc.Name, doc.Version)
+		require.NoError(t, err, "%s version %d was never released", doc.Name, doc.Version)
+		assert.NoError(t, schema.Compatible(released, published), doc.Name)
+	}
+}
+
+func TestSchemas_Names(t *testing.T) {
+	names := schema.Names()
+	assert.Contains(t, names, "report")
+	assert.Contains(t, names, "facts")
+	assert.Contains(t, names, "config")
+
+	_, ok := schema.Lookup("plan")
+	assert.False(t, ok)
+}
+
+func TestCompatible(t *testing.T) {
+	released := `{"$id": "urn:hardn:schema:x:v1", "type": "object", "required": ["a", "b"],
+		"properties": {"a": {"type": "string"}, "b": {"type": "integer"},
+		"c": {"type": ["array", "null"], "items": {"type": "object", "properties": {"d": {"type": "boolean"}}}}}}`
+
+	// Adding properties, required or not, is compatible
+	added := `{"$id": "urn:hardn:schema:x:v1", "type": "object", "required": ["a", "b", "e"],
+		"properties": {"a": {"type": "string"}, "b": {"type": "integer"}, "e": {"type": "string"},
+		"c": {"type": ["array", "null"], "items": {"type": "object", "properties": {"d": {"type": "boolean"}, "f": {}}}}}}`
+	assert.NoError(t, schema.Compatible([]byte(released), []byte(added)))
+
+	broken := `{"$id": "urn:hardn:schema:x:v1", "type": "object", "required": ["a"],
+		"properties": {"a": {"type": "integer"}, "b": {"type": "integer"},
+		"c": {"type": ["array", "null"], "items": {"type": "object", "properties": {}}}}}`
+	err := schema.Compatible([]byte(released), []byte(broken))
+	require.Error(t, err)
+	for _, change := range []string{"a: type changed", "b is no longer required", "c[].d: removed"} {
+		assert.True(t, strings.Contains(err.Error(), change), change)
+	}
+
+	renamed := strings.Replace(released, ":v1", ":v2", 1)
+	assert.ErrorContains(t, schema.Compatible([]byte(released), []byte(renamed)), "$id changed")
+}
@@ -0,0 +1,169 @@
+// pkg/testing/self_update_test.go
+package testing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// releaseServer serves a stable and a beta release whose binary for this
+// platform is script
+func releaseServer(t *testing.T, script []byte) *httptest.Server {
+	name := version.BinaryAssetName(runtime.GOOS, runtime.GOARCH)
+	sum := sha256.Sum256(script)
+
+	var server *httptest.Server
+	release := func(tag string, prerelease bool) version.GitHubRelease {
+		return version.GitHubRelease{TagName: tag, Prerelease: prerelease, Assets: []version.ReleaseAsset{
+			{Name: name, BrowserDownloadURL: server.URL + "/download/" + name},
+			{Name: name + ".sha256", BrowserDownloadURL: server.URL + "/download/" + name + ".sha256"},
+		}}
+	}
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/releases/latest":
+			_ = json.NewEncoder(w).Encode(release("v0.5.0", false))
+		case "/releases":
+			_ = json.NewEncoder(w).Encode([]version.GitHubRelease{
+				{TagName: "v0.7.0-rc.1", Draft: true},
+				release("v0.6.0-beta.1", true),
+				release("v0.5.0", false),
+			})
+		case "/download/" + name:
+			_, _ = w.Write(script)
+		case "/download/" + name + ".sha256":
+			_, _ = w.Write([]byte(hex.EncodeToString(sum[:]) + "  " + name + "\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// installedBinary writes a stand-in for the running hardn binary
+func installedBinary(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "hardn")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\necho hardn 0.4.0\n"), 0755))
+	return path
+}
+
+func TestSelfUpdate(t *testing.T) {
+	newBinary := []byte("#!/bin/sh\necho hardn 0.5.0\n")
+	server := releaseServer(t, newBinary)
+	service := version.NewService("0.4.0", "", "")
+
+	t.Run("replaces the binary and keeps the previous one", func(t *testing.T) {
+		binary := installedBinary(t)
+
+		result, err := service.SelfUpdate(context.Background(), version.SelfUpdateOptions{
+			BinaryPath: binary, APIURL: server.URL + "/releases"})
+
+		require.NoError(t, err)
+		assert.True(t, result.Updated)
+		assert.Equal(t, "0.5.0", result.LatestVersion)
+		assert.Equal(t, version.VerificationChecksum, result.Verification.Status)
+		installed, _ := os.ReadFile(binary)
+		assert.Equal(t, newBinary, installed)
+		previous, _ := os.ReadFile(binary + version.PreviousBinarySuffix)
+		assert.Equal(t, "#!/bin/sh\necho hardn 0.4.0\n", string(previous))
+		_, err = os.Stat(binary + ".new")
+		assert.True(t, os.IsNotExist(err))
+
+		// Rolling back twice returns to the update
+		_, err = version.Rollback(binary)
+		require.NoError(t, err)
+		installed, _ = os.ReadFile(binary)
+		assert.Equal(t, string(previous), string(installed))
+		_, err = version.Rollback(binary)
+		require.NoError(t, err)
+		installed, _ = os.ReadFile(binary)
+		assert.Equal(t, newBinary, installed)
+	})
+
+	t.Run("beta channel includes pre-releases", func(t *testing.T) {
+		binary := installedBinary(t)
+
+		result, err := service.SelfUpdate(context.Background(), version.SelfUpdateOptions{
+			BinaryPath: binary, APIURL: server.URL + "/releases", Channel: version.ChannelBeta, DryRun: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, "0.6.0-beta.1", result.LatestVersion)
+		assert.True(t, result.UpdateAvailable)
+		assert.False(t, result.Updated)
+		_, err = os.Stat(binary + version.PreviousBinarySuffix)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		binary := installedBinary(t)
+
+		result, err := version.NewService("0.5.0", "", "").SelfUpdate(context.Background(),
+			version.SelfUpdateOptions{BinaryPath: binary, APIURL: server.URL + "/releases"})
+
+		require.NoError(t, err)
+		assert.False(t, result.UpdateAvailable)
+		assert.False(t, result.Updated)
+	})
+
+	t.Run("restores the previous binary when the new one fails", func(t *testing.T) {
+		broken := releaseServer(t, []byte("#!/bin/sh\nexit 1\n"))
+		binary := installedBinary(t)
+
+		_, err := service.SelfUpdate(context.Background(), version.SelfUpdateOptions{
+			BinaryPath: binary, APIURL: broken.URL + "/releases"})
+
+		assert.ErrorContains(t, err, "kept the previous one")
+		installed, _ := os.ReadFile(binary)
+		assert.Equal(t, "#!/bin/sh\necho hardn 0.4.0\n", string(installed))
+	})
+
+	t.Run("refuses an unsigned release when a signature is required", func(t *testing.T) {
+		binary := installedBinary(t)
+
+		_, err := service.SelfUpdate(context.Background(), version.SelfUpdateOptions{
+			BinaryPath: binary, APIURL: server.URL + "/releases", RequireSignature: true})
+
+		assert.ErrorContains(t, err, "publishes no signature")
+		_, err = os.Stat(binary + version.PreviousBinarySuffix)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("no previous binary", func(t *testing.T) {
+		_, err := version.Rollback(installedBinary(t))
+
+		assert.ErrorIs(t, err, version.ErrNoPreviousBinary)
+	})
+}
+
+func TestSignatureIdentity(t *testing.T) {
+	identity := regexp.MustCompile(version.SignatureIdentity)
+
+	assert.True(t, identity.MatchString("https://github.com/abbott/hardn/.github/workflows/release.yml@refs/tags/v0.5.0"))
+	assert.True(t, identity.MatchString("https://github.com/abbott/hardn/.github/workflows/release.yaml@refs/tags/v1.0.0-beta.1"))
+
+	for _, foreign := range []string{
+		"https://github.com/mallory/hardn/.github/workflows/release.yml@refs/tags/v0.5.0",
+		"https://github.com/abbott/hardn/.github/workflows/ci.yml@refs/tags/v0.5.0",
+		"https://github.com/abbott/hardn/.github/workflows/release.yml@refs/heads/main",
+		"https://github.com/abbott/hardn-fork/.github/workflows/release.yml@refs/tags/v0.5.0",
+		"https://github.com/mallory/x/.github/workflows/y.yml@refs/tags/v1?https://github.com/abbott/hardn/.github/workflows/release.yml@refs/tags/v0.5.0",
+		"https://githubXcom/abbott/hardn/.github/workflows/release.yml@refs/tags/v0.5.0",
+	} {
+		assert.False(t, identity.MatchString(foreign), foreign)
+	}
+}
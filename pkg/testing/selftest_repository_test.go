@@ -0,0 +1,72 @@
+// pkg/testing/selftest_repository_test.go
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOSSelfTestRepository_Validators(t *testing.T) {
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandErrors["which firewall-cmd"] = errors.New("not found")
+	mockCommander.CommandErrors["which ufw"] = errors.New("not found")
+	mockCommander.CommandErrors["sshd -t"] = errors.New("exit status 255")
+
+	repo := secondary.NewOSSelfTestRepository(interfaces.NewMockFileSystem(), mockCommander)
+
+	sudoers := repo.ValidateSudoers()
+	assert.Equal(t, model.SelfTestPassed, sudoers.Status)
+	assert.Contains(t, mockCommander.ExecutedCommands, "visudo -c")
+
+	ssh := repo.ValidateSSHConfig()
+	assert.Equal(t, model.SelfTestFailed, ssh.Status)
+	assert.Equal(t, "exit status 255", ssh.Detail)
+
+	firewall := repo.ValidateFirewall()
+	assert.Equal(t, model.SelfTestSkipped, firewall.Status)
+	assert.Equal(t, "ufw is not installed", firewall.Detail)
+}
+
+func TestOSSelfTestRepository_Firewalld(t *testing.T) {
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSSelfTestRepository(interfaces.NewMockFileSystem(), mockCommander)
+
+	check := repo.ValidateFirewall()
+	assert.Equal(t, model.SelfTestPassed, check.Status)
+	assert.Equal(t, "/etc/firewalld", check.Target)
+	assert.Contains(t, mockCommander.ExecutedCommands, "firewall-cmd --check-config")
+}
+
+func TestOSSelfTestRepository_Sysctl(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/etc/sysctl.conf"] = []byte("net.ipv4.ip_forward = 0\n")
+	mockFS.Files["/etc/sysctl.d/99-hardn.conf"] = []byte("kernel.kptr_restrict = 2\n")
+	mockFS.Files["/proc/sys/net/ipv4/ip_forward"] = []byte("0\n")
+	mockFS.Files["/proc/sys/net/ipv4/conf/eth0.100/rp_filter"] = []byte("1\n")
+
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandOutputs["find /etc/sysctl.d -maxdepth 1 -name *.conf"] = []byte("/etc/sysctl.d/99-hardn.conf\n")
+
+	repo := secondary.NewOSSelfTestRepository(mockFS, mockCommander)
+
+	configs, err := repo.ReadSysctlConfigs()
+	require.NoError(t, err)
+	assert.Len(t, configs, 2)
+	assert.Equal(t, "kernel.kptr_restrict = 2\n", string(configs["/etc/sysctl.d/99-hardn.conf"]))
+
+	assert.True(t, repo.SysctlKeyExists("net.ipv4.ip_forward"))
+	assert.True(t, repo.SysctlKeyExists("net/ipv4/ip_forward"))
+	// Slash-separated keys keep the dots of interface names
+	assert.True(t, repo.SysctlKeyExists("net/ipv4/conf/eth0.100/rp_filter"))
+	assert.False(t, repo.SysctlKeyExists("kernel.kptr_restrict"))
+
+	resolvConf, err := repo.ReadResolvConf()
+	require.NoError(t, err)
+	assert.Nil(t, resolvConf)
+}
@@ -0,0 +1,98 @@
+// pkg/testing/service_manager_test.go
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceManager_InitSystemByOS(t *testing.T) {
+	commander := interfaces.NewMockCommander()
+	assert.Equal(t, model.InitOpenRC, secondary.NewServiceManager(commander, "alpine").InitSystem())
+	assert.Equal(t, model.InitSystemd, secondary.NewServiceManager(commander, "debian").InitSystem())
+	assert.Equal(t, model.InitSystemd, secondary.NewServiceManager(commander, "rhel").InitSystem())
+}
+
+func TestSystemdServiceManager(t *testing.T) {
+	commander := interfaces.NewMockCommander()
+	services := secondary.NewServiceManager(commander, "debian")
+	commander.CommandErrors["systemctl is-enabled --quiet apparmor"] = errors.New("exit status 1")
+
+	require.NoError(t, services.Enable("fail2ban"))
+	require.NoError(t, services.Disable("ufw"))
+	require.NoError(t, services.Restart("ssh"))
+
+	status, err := services.Status("apparmor")
+	require.NoError(t, err)
+	assert.True(t, status.Active)
+	assert.False(t, status.Enabled)
+
+	assert.Equal(t, []string{
+		"systemctl enable --now fail2ban",
+		"systemctl disable --now ufw",
+		"systemctl restart ssh",
+		"systemctl is-active --quiet apparmor",
+		"systemctl is-enabled --quiet apparmor",
+	}, commander.ExecutedCommands)
+
+	commander.CommandErrors["systemctl restart sshd"] = errors.New("exit status 5")
+	assert.ErrorContains(t, services.Restart("sshd"), "systemctl restart sshd failed")
+}
+
+func TestOpenRCServiceManager(t *testing.T) {
+	commander := interfaces.NewMockCommander()
+	services := secondary.NewServiceManager(commander, "alpine")
+	commander.CommandOutputs["rc-update show default"] = []byte("    crond | default\n  sshd-keys | default\n")
+
+	require.NoError(t, services.Enable("fail2ban"))
+	require.NoError(t, services.Restart("sshd"))
+
+	status, err := services.Status("crond")
+	require.NoError(t, err)
+	assert.True(t, status.Active)
+	assert.True(t, status.Enabled)
+
+	// sshd-keys must not count as sshd
+	status, err = services.Status("sshd")
+	require.NoError(t, err)
+	assert.False(t, status.Enabled)
+
+	assert.Equal(t, []string{
+		"rc-update add fail2ban default",
+		"rc-service fail2ban start",
+		"rc-service sshd restart",
+		"rc-service crond status",
+		"rc-update show default",
+		"rc-service sshd status",
+		"rc-update show default",
+	}, commander.ExecutedCommands)
+}
+
+func TestOpenRCServiceManager_DisableSkipsStoppedServices(t *testing.T) {
+	commander := interfaces.NewMockCommander()
+	services := secondary.NewServiceManager(commander, "alpine")
+	commander.CommandErrors["rc-service firewalld status"] = errors.New("exit status 3")
+	commander.CommandOutputs["rc-update show default"] = []byte("  firewalld | default\n")
+
+	require.NoError(t, services.Disable("firewalld"))
+	assert.NotContains(t, commander.ExecutedCommands, "rc-service firewalld stop")
+	assert.Contains(t, commander.ExecutedCommands, "rc-update del firewalld default")
+
+	commander.ExecutedCommands = nil
+	require.NoError(t, services.Disable("ufw"))
+	assert.NotContains(t, commander.ExecutedCommands, "rc-update del ufw default")
+}
+
+func TestRunlevelHasService(t *testing.T) {
+	output := "           crond |      default\n  networking | boot default\n"
+	assert.True(t, secondary.RunlevelHasService(output, "crond"))
+	assert.True(t, secondary.RunlevelHasService(output, "networking"))
+	assert.False(t, secondary.RunlevelHasService(output, "cron"))
+	assert.False(t, secondary.RunlevelHasService("", "crond"))
+}
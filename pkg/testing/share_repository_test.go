@@ -0,0 +1,67 @@
+// pkg/testing/share_repository_test.go
+package testing
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNFSExports(t *testing.T) {
+	data := []byte(`# /etc/exports
+/srv/public  *(ro,sync)
+/srv/home    10.0.0.0/24(rw,no_root_squash) \
+             backup.lan(ro)
+/srv/media   -rw,insecure
+/srv/builds  -ro *.lan (sync)
+`)
+
+	exports := secondary.ParseNFSExports(data)
+
+	assert.Len(t, exports, 4)
+	assert.Equal(t, []model.NFSClient{{Host: "*", Options: []string{"ro", "sync"}}}, exports[0].Clients)
+	assert.Equal(t, "/srv/home", exports[1].Path)
+	assert.Len(t, exports[1].Clients, 2)
+	assert.Equal(t, "backup.lan", exports[1].Clients[1].Host)
+	assert.Equal(t, []model.NFSClient{{Options: []string{"rw", "insecure"}}}, exports[2].Clients)
+	assert.Equal(t, []model.NFSClient{
+		{Host: "*.lan", Options: []string{"ro"}},
+		{Host: "", Options: []string{"ro", "sync"}},
+	}, exports[3].Clients)
+}
+
+func TestParseSambaConfig(t *testing.T) {
+	data := []byte(`[global]
+   workgroup = WORKGROUP
+   Server  Min Protocol = NT1
+; [disabled]
+[public]
+   path = /srv/public
+   Guest OK = yes
+   read only = \
+     no
+`)
+
+	config := secondary.ParseSambaConfig(data)
+
+	assert.Equal(t, "NT1", config.Global["server min protocol"])
+	assert.Len(t, config.Shares, 1)
+	assert.Equal(t, "public", config.Shares[0].Name)
+	assert.Equal(t, "yes", config.Shares[0].Settings["guest ok"])
+	assert.Equal(t, "no", config.Shares[0].Settings["read only"])
+}
+
+func TestFileShareRepository_MissingFiles(t *testing.T) {
+	repo := secondary.NewFileShareRepository(interfaces.NewMockFileSystem())
+
+	exports, err := repo.GetNFSExports()
+	assert.NoError(t, err)
+	assert.Nil(t, exports)
+
+	config, err := repo.GetSambaConfig()
+	assert.NoError(t, err)
+	assert.Nil(t, config)
+}
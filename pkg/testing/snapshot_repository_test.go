@@ -0,0 +1,61 @@
+// pkg/testing/snapshot_repository_test.go
+package testing
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemSnapshotRepository_DetectBtrfs(t *testing.T) {
+	tests := []struct {
+		name      string
+		fstab     string
+		cmdline   string
+		supported bool
+		reason    string
+	}{
+		{
+			name:      "default subvolume",
+			fstab:     "UUID=1234 / btrfs defaults 0 0\n",
+			cmdline:   "BOOT_IMAGE=/vmlinuz root=UUID=1234 ro quiet\n",
+			supported: true,
+		},
+		{
+			name:   "subvolume in fstab",
+			fstab:  "# / was on /dev/sda2 during installation\nUUID=1234 / btrfs defaults,subvol=@ 0 1\nUUID=1234 /home btrfs defaults,subvol=@home 0 2\n",
+			reason: "root is mounted from btrfs subvolume /@ named in /etc/fstab",
+		},
+		{
+			name:    "subvolume on the kernel command line",
+			fstab:   "UUID=1234 / btrfs compress=zstd:1 0 0\n",
+			cmdline: "BOOT_IMAGE=(hd0,gpt2)/root/boot/vmlinuz root=UUID=1234 ro rootflags=subvol=root rhgb quiet\n",
+			reason:  "named in the kernel command line",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := interfaces.NewMockFileSystem()
+			mockFS.Files["/etc/fstab"] = []byte(tt.fstab)
+			mockFS.Files["/proc/cmdline"] = []byte(tt.cmdline)
+			mockCommander := interfaces.NewMockCommander()
+			mockCommander.CommandOutputs["findmnt -n -o FSTYPE,SOURCE /"] = []byte("btrfs /dev/sda2[/@]\n")
+			repo := secondary.NewSystemSnapshotRepository(mockFS, mockCommander)
+
+			support, err := repo.DetectSnapshotSupport()
+			require.NoError(t, err)
+			assert.Equal(t, tt.supported, support.Supported)
+			if tt.supported {
+				assert.Equal(t, model.SnapshotBackendBtrfs, support.Backend)
+				assert.Equal(t, "/@", support.Target)
+				return
+			}
+			assert.Contains(t, support.Reason, tt.reason)
+		})
+	}
+}
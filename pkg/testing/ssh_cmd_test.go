@@ -0,0 +1,82 @@
+// pkg/testing/ssh_cmd_test.go
+package testing
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/cmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runSSHCmd runs the ssh command group with args. Only invocations that
+// fail before touching the host are run here.
+func runSSHCmd(args ...string) error {
+	command := cmd.SSHCmd()
+	command.SetArgs(args)
+	command.SetOut(io.Discard)
+	command.SetErr(io.Discard)
+	command.SilenceUsage = true
+	command.SilenceErrors = true
+	return command.Execute()
+}
+
+func TestSSHCmd_Subcommands(t *testing.T) {
+	command := cmd.SSHCmd()
+
+	var names []string
+	for _, sub := range command.Commands() {
+		names = append(names, sub.Name())
+	}
+	assert.ElementsMatch(t, []string{"disable-root", "set-port", "add-key", "harden", "profile", "conflicts", "keys"}, names)
+
+	flags := map[string][]string{
+		"set-port":  {"save", "no-firewall"},
+		"add-key":   {"file"},
+		"harden":    {"port", "listen", "allow-user", "allow-all-users", "no-firewall", "profile"},
+		"profile":   {"save"},
+		"conflicts": {"consolidate"},
+	}
+	for name, expected := range flags {
+		sub, _, err := command.Find([]string{name})
+		require.NoError(t, err, name)
+		for _, flag := range expected {
+			assert.NotNil(t, sub.Flags().Lookup(flag), "%s --%s", name, flag)
+		}
+	}
+}
+
+func TestSSHCmd_RejectsInvalidArguments(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "george.pub")
+	require.NoError(t, os.WriteFile(keyFile, []byte("ssh-ed25519 AAAA george@example.com\n"), 0644))
+
+	tests := []struct {
+		name  string
+		args  []string
+		error string
+	}{
+		{name: "disable-root takes no arguments", args: []string{"disable-root", "root"}, error: "unknown command"},
+		{name: "set-port needs a port", args: []string{"set-port"}, error: "accepts 1 arg"},
+		{name: "set-port not a number", args: []string{"set-port", "ssh"}, error: "invalid port: ssh"},
+		{name: "set-port zero", args: []string{"set-port", "0"}, error: "invalid port: 0"},
+		{name: "set-port too high", args: []string{"set-port", "65536"}, error: "invalid port: 65536"},
+		{name: "add-key needs a user", args: []string{"add-key"}, error: "accepts between 1 and 2 arg"},
+		{name: "add-key needs a key", args: []string{"add-key", "george"}, error: "no public key given"},
+		{name: "add-key argument and file", args: []string{"add-key", "george", "ssh-ed25519 AAAA", "--file", keyFile}, error: "not both"},
+		{name: "add-key missing file", args: []string{"add-key", "george", "--file", filepath.Join(t.TempDir(), "missing.pub")}, error: "failed to read"},
+		{name: "add-key invalid key", args: []string{"add-key", "george", "not a key"}, error: "invalid public key"},
+		{name: "harden takes no arguments", args: []string{"harden", "now"}, error: "unknown command"},
+		{name: "profile needs a name", args: []string{"profile"}, error: "accepts 1 arg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runSSHCmd(tt.args...)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.error)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// pkg/testing/ssh_key_expiry_repository_test.go
+package testing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSSHKeyExpiryRepository(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	repo := secondary.NewFileSSHKeyExpiryRepository(mockFS, model.SSHKeyExpiryPath)
+
+	expiries, err := repo.LoadKeyExpiries()
+	require.NoError(t, err)
+	assert.Empty(t, expiries, "no keys tagged yet")
+
+	expires := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.SaveKeyExpiries([]model.SSHKeyExpiry{
+		{Username: "george", Fingerprint: "SHA256:5bEaQPr9gapqp0X1EFLCEhe0M15R5jNsd0XvN9kXysM", Expires: expires},
+	}))
+	assert.Contains(t, string(mockFS.Files[model.SSHKeyExpiryPath]), `"expires": "2026-12-31T00:00:00Z"`)
+
+	expiries, err = repo.LoadKeyExpiries()
+	require.NoError(t, err)
+	require.Len(t, expiries, 1)
+	assert.Equal(t, "george", expiries[0].Username)
+	assert.True(t, expiries[0].Expires.Equal(expires))
+
+	// Clearing the last date leaves an empty list rather than null
+	require.NoError(t, repo.SaveKeyExpiries(nil))
+	assert.Equal(t, "[]\n", string(mockFS.Files[model.SSHKeyExpiryPath]))
+
+	mockFS.Files[model.SSHKeyExpiryPath] = []byte("{not json")
+	_, err = repo.LoadKeyExpiries()
+	assert.Error(t, err)
+}
+
+func TestParseSSHKeyExpiry(t *testing.T) {
+	now := time.Date(2026, 10, 17, 15, 30, 0, 0, time.UTC)
+
+	expires, err := model.ParseSSHKeyExpiry("2026-12-31", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), expires)
+
+	expires, err = model.ParseSSHKeyExpiry("90d", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, 90), expires)
+
+	for _, invalid := range []string{"", "0d", "-5d", "tomorrow", "31/12/2026"} {
+		_, err := model.ParseSSHKeyExpiry(invalid, now)
+		assert.Error(t, err, invalid)
+	}
+}
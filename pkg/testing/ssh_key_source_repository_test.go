@@ -0,0 +1,47 @@
+// pkg/testing/ssh_key_source_repository_test.go
+package testing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPSSHKeySourceRepository_FetchPublicKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gh/george.keys":
+			w.Write([]byte("ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8g\n\nssh-rsa AAAAB3NzaC1yc2E\n"))
+		case "/gl/george.keys":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	repo := secondary.NewHTTPSSHKeySourceRepositoryWithURLs(map[string]string{
+		model.SSHKeyProviderGitHub: server.URL + "/gh/%s.keys",
+		model.SSHKeyProviderGitLab: server.URL + "/gl/%s.keys",
+	})
+
+	keys, err := repo.FetchPublicKeys(model.SSHKeyProviderGitHub, "george")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8g",
+		"ssh-rsa AAAAB3NzaC1yc2E",
+	}, keys)
+
+	_, err = repo.FetchPublicKeys(model.SSHKeyProviderGitHub, "nobody")
+	assert.EqualError(t, err, "github user nobody not found")
+
+	_, err = repo.FetchPublicKeys(model.SSHKeyProviderGitLab, "george")
+	assert.ErrorContains(t, err, "500")
+
+	_, err = repo.FetchPublicKeys("bitbucket", "george")
+	assert.Error(t, err)
+}
@@ -0,0 +1,320 @@
+// pkg/testing/ssh_repository_test.go
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSSHRepository_ProfileRoundTrip(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	repo := secondary.NewFileSSHRepository(mockFS, interfaces.NewMockCommander(), "debian")
+
+	err := repo.SaveSSHConfig(model.SSHConfig{
+		Port:         2208,
+		AllowedUsers: []string{"george"},
+		Profile:      model.SSHProfileStrict,
+	})
+	assert.NoError(t, err)
+
+	content := string(mockFS.Files["/etc/ssh/sshd_config.d/hardn.conf"])
+	assert.Contains(t, content, "# Hardening profile: strict\n")
+	assert.Contains(t, content, "MaxAuthTries 3\n")
+	assert.Contains(t, content, "LoginGraceTime 30\n")
+	assert.Contains(t, content, "X11Forwarding no\n")
+	assert.Contains(t, content, "AllowTcpForwarding local\n")
+	assert.Contains(t, content, "Ciphers chacha20-poly1305@openssh.com,aes256-gcm@openssh.com,aes128-gcm@openssh.com\n")
+	assert.NotContains(t, content, "hmac-sha2-256\n")
+
+	config, err := repo.GetSSHConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, model.SSHProfileStrict, config.Profile)
+	assert.Equal(t, 2208, config.Port)
+
+	// Disabling root login rewrites the file and keeps the profile
+	assert.NoError(t, repo.DisableRootSSH())
+	config, err = repo.GetSSHConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, model.SSHProfileStrict, config.Profile)
+	assert.False(t, config.PermitRootLogin)
+}
+
+func TestFileSSHRepository_NoProfile(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	repo := secondary.NewFileSSHRepository(mockFS, interfaces.NewMockCommander(), "alpine")
+
+	err := repo.SaveSSHConfig(model.SSHConfig{Port: 22, AllowedUsers: []string{"george"}})
+	assert.NoError(t, err)
+
+	content := string(mockFS.Files["/etc/ssh/sshd_config"])
+	assert.NotContains(t, content, "MaxAuthTries")
+	assert.NotContains(t, content, "Hardening profile")
+
+	config, err := repo.GetSSHConfig()
+	assert.NoError(t, err)
+	assert.Empty(t, config.Profile)
+}
+
+func TestFileSSHRepository_BannerRoundTrip(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	repo := secondary.NewFileSSHRepository(mockFS, interfaces.NewMockCommander(), "debian")
+
+	err := repo.SaveSSHConfig(model.SSHConfig{Port: 22, AllowedUsers: []string{"george"}, Banner: model.IssueNetPath})
+	assert.NoError(t, err)
+	assert.Contains(t, string(mockFS.Files["/etc/ssh/sshd_config.d/hardn.conf"]), "Banner /etc/issue.net\n")
+
+	config, err := repo.GetSSHConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, model.IssueNetPath, config.Banner)
+
+	// "none" in sshd_config means no banner
+	mockFS.Files["/etc/ssh/sshd_config"] = []byte("Banner none\n")
+	delete(mockFS.Files, "/etc/ssh/sshd_config.d/hardn.conf")
+	config, err = repo.GetSSHConfig()
+	assert.NoError(t, err)
+	assert.Empty(t, config.Banner)
+}
+
+func TestFileSSHRepository_UnknownProfile(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	repo := secondary.NewFileSSHRepository(mockFS, interfaces.NewMockCommander(), "debian")
+
+	err := repo.SaveSSHConfig(model.SSHConfig{Port: 22, Profile: "relaxed"})
+	assert.Error(t, err)
+	_, exists := mockFS.Files["/etc/ssh/sshd_config.d/hardn.conf"]
+	assert.False(t, exists)
+}
+
+func TestFileSSHRepository_GetSSHConfigSources(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewFileSSHRepository(mockFS, mockCommander, "debian")
+
+	mockFS.Files["/etc/ssh/sshd_config"] = []byte("Include /etc/ssh/sshd_config.d/*.conf\n" +
+		"# Port 22\n" +
+		"PasswordAuthentication yes\n" +
+		"Match User backup\n" +
+		"\tPasswordAuthentication no\n")
+	mockFS.Directories["/etc/ssh/sshd_config.d"] = true
+	mockFS.Files["/etc/ssh/sshd_config.d/hardn.conf"] = []byte("Port 2208\nPasswordAuthentication no\n")
+	mockFS.Files["/etc/ssh/sshd_config.d/50-cloud-init.conf"] = []byte("PasswordAuthentication=yes\n")
+	mockCommander.CommandOutputs["find /etc/ssh/sshd_config.d -mindepth 1 -maxdepth 1 -type f"] = []byte(
+		"/etc/ssh/sshd_config.d/hardn.conf\n/etc/ssh/sshd_config.d/README\n/etc/ssh/sshd_config.d/50-cloud-init.conf\n")
+
+	sources, err := repo.GetSSHConfigSources()
+	assert.NoError(t, err)
+
+	// Glob matches are read in lexical order at the Include line, and the
+	// Match block is left out
+	assert.Equal(t, []model.SSHDirectiveSource{
+		{Directive: "passwordauthentication", Value: "yes", File: "/etc/ssh/sshd_config.d/50-cloud-init.conf", Line: 1},
+		{Directive: "port", Value: "2208", File: "/etc/ssh/sshd_config.d/hardn.conf", Line: 1},
+		{Directive: "passwordauthentication", Value: "no", File: "/etc/ssh/sshd_config.d/hardn.conf", Line: 2},
+		{Directive: "passwordauthentication", Value: "yes", File: "/etc/ssh/sshd_config", Line: 3},
+	}, sources)
+}
+
+func TestFileSSHRepository_GetEffectiveSSHConfig(t *testing.T) {
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewFileSSHRepository(interfaces.NewMockFileSystem(), mockCommander, "debian")
+
+	mockCommander.CommandOutputs["sshd -T"] = []byte("port 2208\nlistenaddress 10.0.0.5:2208\nlistenaddress [::1]:2208\nPasswordAuthentication no\n")
+	settings, err := repo.GetEffectiveSSHConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2208"}, settings["port"])
+	assert.Equal(t, []string{"10.0.0.5:2208", "[::1]:2208"}, settings["listenaddress"])
+	assert.Equal(t, []string{"no"}, settings["passwordauthentication"])
+}
+
+func TestFileSSHRepository_DisableSSHDirectives(t *testing.T) {
+	const managed = "/etc/ssh/sshd_config.d/hardn.conf"
+	const cloudInit = "/etc/ssh/sshd_config.d/50-cloud-init.conf"
+	sources := []model.SSHDirectiveSource{
+		{Directive: "passwordauthentication", Value: "yes", File: cloudInit, Line: 2},
+	}
+
+	t.Run("lines commented out", func(t *testing.T) {
+		mockFS := interfaces.NewMockFileSystem()
+		mockCommander := interfaces.NewMockCommander()
+		repo := secondary.NewFileSSHRepository(mockFS, mockCommander, "ubuntu")
+		mockFS.Files[cloudInit] = []byte("KbdInteractiveAuthentication no\nPasswordAuthentication yes\n")
+
+		assert.NoError(t, repo.DisableSSHDirectives(sources, managed))
+		assert.Equal(t, "KbdInteractiveAuthentication no\n"+
+			"# Disabled by hardn in favor of "+managed+": PasswordAuthentication yes\n",
+			string(mockFS.Files[cloudInit]))
+		assert.Contains(t, mockCommander.ExecutedCommands, "sshd -t")
+	})
+
+	t.Run("files restored when sshd rejects them", func(t *testing.T) {
+		mockFS := interfaces.NewMockFileSystem()
+		mockCommander := interfaces.NewMockCommander()
+		repo := secondary.NewFileSSHRepository(mockFS, mockCommander, "ubuntu")
+		original := "KbdInteractiveAuthentication no\nPasswordAuthentication yes\n"
+		mockFS.Files[cloudInit] = []byte(original)
+		mockCommander.CommandErrors["sshd -t"] = errors.New("exit status 255")
+
+		assert.ErrorContains(t, repo.DisableSSHDirectives(sources, managed), "files restored")
+		assert.Equal(t, original, string(mockFS.Files[cloudInit]))
+	})
+
+	t.Run("line changed since it was read", func(t *testing.T) {
+		mockFS := interfaces.NewMockFileSystem()
+		repo := secondary.NewFileSSHRepository(mockFS, interfaces.NewMockCommander(), "ubuntu")
+		mockFS.Files[cloudInit] = []byte("PasswordAuthentication yes\n")
+
+		assert.ErrorContains(t, repo.DisableSSHDirectives(sources, managed), "no longer sets")
+		assert.Equal(t, "PasswordAuthentication yes\n", string(mockFS.Files[cloudInit]))
+	})
+
+	t.Run("hardn's file left alone", func(t *testing.T) {
+		repo := secondary.NewFileSSHRepository(interfaces.NewMockFileSystem(), interfaces.NewMockCommander(), "ubuntu")
+		own := []model.SSHDirectiveSource{{Directive: "port", Value: "2208", File: managed, Line: 1}}
+		assert.Error(t, repo.DisableSSHDirectives(own, managed))
+	})
+}
+
+func TestParseSSHDaemons(t *testing.T) {
+	output := `      1       0 /sbin/init
+    812       1 sshd: /usr/sbin/sshd -D [listener] 0 of 10-100 startups
+   4410     812 sshd: admin [priv]
+   4431    4410 sshd: admin@pts/0
+    930       1 /usr/sbin/dropbear -F -p 2222
+   5120     930 /usr/sbin/dropbear -F -p 2222
+   1002       1 /usr/sbin/sshd -D -f /etc/ssh/sshd_config_backup
+   6001    4431 grep sshd
+`
+	assert.Equal(t, []model.SSHDaemon{
+		{PID: 812, Program: "sshd", Command: "/usr/sbin/sshd -D", ConfigFile: "/etc/ssh/sshd_config"},
+		{PID: 930, Program: "dropbear", Command: "/usr/sbin/dropbear -F -p 2222"},
+		{PID: 1002, Program: "sshd", Command: "/usr/sbin/sshd -D -f /etc/ssh/sshd_config_backup", ConfigFile: "/etc/ssh/sshd_config_backup"},
+	}, secondary.ParseSSHDaemons(output))
+}
+
+func TestFileSSHRepository_GetSSHConfig(t *testing.T) {
+	const (
+		mainConfig   = "/etc/ssh/sshd_config"
+		dropInConfig = "/etc/ssh/sshd_config.d/hardn.conf"
+	)
+
+	tests := []struct {
+		name     string
+		osType   string
+		files    map[string]string
+		expected model.SSHConfig
+	}{
+		{
+			name:     "missing files fall back to sshd defaults",
+			osType:   "debian",
+			expected: model.SSHConfig{Port: 22, PermitRootLogin: true},
+		},
+		{
+			name:   "comments and blank lines are skipped",
+			osType: "debian",
+			files: map[string]string{
+				mainConfig: "# Port 2222\n\n#PermitRootLogin no\n   # AllowUsers root\nPort 2208\n\t\nAllowUsers george ringo\n",
+			},
+			expected: model.SSHConfig{Port: 2208, PermitRootLogin: true, AllowedUsers: []string{"george", "ringo"}},
+		},
+		{
+			name:   "Match blocks do not override the defaults",
+			osType: "debian",
+			files: map[string]string{
+				mainConfig: "PermitRootLogin no\nMatch Address 10.0.0.0/8\n\tPermitRootLogin yes\n\tAllowUsers root\n\tAuthorizedKeysFile /etc/ssh/keys/%u\n",
+			},
+			expected: model.SSHConfig{Port: 22, PermitRootLogin: false},
+		},
+		{
+			name:   "the first AuthorizedKeysFile line holds every path",
+			osType: "debian",
+			files: map[string]string{
+				mainConfig: "AuthorizedKeysFile .ssh/authorized_keys .ssh/authorized_keys2\nAuthorizedKeysFile /etc/ssh/keys/%u\n",
+			},
+			expected: model.SSHConfig{
+				Port:            22,
+				PermitRootLogin: true,
+				KeyPaths:        []string{".ssh/authorized_keys", ".ssh/authorized_keys2"},
+			},
+		},
+		{
+			name:   "the drop-in wins over the main config",
+			osType: "debian",
+			files: map[string]string{
+				dropInConfig: "Port 2208\nPermitRootLogin no\nListenAddress 10.0.0.5\nListenAddress 10.0.0.6\n",
+				mainConfig:   "Port 22\nPermitRootLogin yes\nListenAddress 0.0.0.0\nAuthorizedKeysFile .ssh/authorized_keys\n",
+			},
+			expected: model.SSHConfig{
+				Port:            2208,
+				PermitRootLogin: false,
+				ListenAddresses: []string{"10.0.0.5", "10.0.0.6"},
+				KeyPaths:        []string{".ssh/authorized_keys"},
+			},
+		},
+		{
+			name:   "Alpine reads only the main config",
+			osType: "alpine",
+			files: map[string]string{
+				dropInConfig: "Port 2208\n",
+				mainConfig:   "PermitRootLogin no\n",
+			},
+			expected: model.SSHConfig{Port: 22, PermitRootLogin: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := interfaces.NewMockFileSystem()
+			for path, content := range tt.files {
+				mockFS.Files[path] = []byte(content)
+			}
+			repo := secondary.NewFileSSHRepository(mockFS, interfaces.NewMockCommander(), tt.osType)
+
+			config, err := repo.GetSSHConfig()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, *config)
+		})
+	}
+}
+
+func TestFileSSHRepository_DisableRootSSHAllowedUsers(t *testing.T) {
+	tests := []struct {
+		name         string
+		allowedUsers []string
+		expected     string
+		refused      bool
+	}{
+		{name: "root dropped from the list", allowedUsers: []string{"root", "george"}, expected: "AllowUsers george\n"},
+		{name: "root the only user", allowedUsers: []string{"root"}, refused: true},
+		{name: "wildcard kept", allowedUsers: []string{"root", "*"}, expected: "AllowUsers *\n"},
+		{name: "no AllowUsers", allowedUsers: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := interfaces.NewMockFileSystem()
+			repo := secondary.NewFileSSHRepository(mockFS, interfaces.NewMockCommander(), "debian")
+			assert.NoError(t, repo.SaveSSHConfig(model.SSHConfig{Port: 22, PermitRootLogin: true, AllowedUsers: tt.allowedUsers}))
+			before := string(mockFS.Files["/etc/ssh/sshd_config.d/hardn.conf"])
+
+			err := repo.DisableRootSSH()
+			content := string(mockFS.Files["/etc/ssh/sshd_config.d/hardn.conf"])
+			if tt.refused {
+				assert.ErrorContains(t, err, "root is the only user")
+				assert.Equal(t, before, content, "a refused change must leave the file alone")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Contains(t, content, "PermitRootLogin no\n")
+			if tt.expected == "" {
+				assert.NotContains(t, content, "AllowUsers")
+			} else {
+				assert.Contains(t, content, tt.expected)
+			}
+		})
+	}
+}
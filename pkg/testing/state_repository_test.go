@@ -0,0 +1,101 @@
+// pkg/testing/state_repository_test.go
+package testing
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOSStateRepository_ReadState(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["state.yml"] = []byte(`kind: hardn-state
+users:
+  - name: george
+    sudo: true
+    nopasswd: true
+ssh:
+  port: 2222
+  permitRootLogin: false
+firewall:
+  rules:
+    - {action: allow, protocol: tcp, port: 2222, comment: ssh}
+sysctl:
+  net.ipv4.ip_forward: 0
+packages:
+  install: [fail2ban]
+`)
+	mockFS.Files["typo.yml"] = []byte("kind: hardn-state\nsysctls:\n  net.ipv4.ip_forward: 0\n")
+
+	repo := secondary.NewOSStateRepository(mockFS, interfaces.NewMockCommander())
+
+	state, err := repo.ReadState("state.yml")
+	require.NoError(t, err)
+	assert.Equal(t, model.DesiredStateKind, state.Kind)
+	assert.Equal(t, model.User{Username: "george", HasSudo: true, SudoNoPassword: true}, state.Users[0].User())
+	require.NotNil(t, state.SSH.PermitRootLogin)
+	assert.False(t, *state.SSH.PermitRootLogin)
+	assert.Equal(t, "allow 2222/tcp", state.Firewall.Rules[0].Rule().Spec())
+	assert.Equal(t, map[string]string{"net.ipv4.ip_forward": "0"}, state.Sysctl, "numeric values are read as strings")
+	assert.Equal(t, []string{"fail2ban"}, state.Packages.Install)
+
+	_, err = repo.ReadState("typo.yml")
+	assert.ErrorContains(t, err, "field sysctls not found")
+
+	_, err = repo.ReadState("missing.yml")
+	assert.ErrorContains(t, err, "failed to read state file missing.yml")
+}
+
+func TestOSStateRepository_Sysctl(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/proc/sys/net/ipv4/tcp_rmem"] = []byte("4096\t131072\t6291456\n")
+	mockCommander := interfaces.NewMockCommander()
+
+	repo := secondary.NewOSStateRepository(mockFS, mockCommander)
+
+	value, ok := repo.GetSysctl("net.ipv4.tcp_rmem")
+	assert.True(t, ok)
+	assert.Equal(t, "4096 131072 6291456", value)
+	_, ok = repo.GetSysctl("net.ipv4.missing")
+	assert.False(t, ok)
+
+	require.NoError(t, repo.SetSysctl("net.ipv4.ip_forward", "0"))
+	assert.Contains(t, mockCommander.ExecutedCommands, "sysctl -w net.ipv4.ip_forward=0")
+
+	persisted, err := repo.ReadStateSysctl()
+	require.NoError(t, err)
+	assert.Empty(t, persisted, "a missing file holds no settings")
+
+	require.NoError(t, repo.WriteStateSysctl(map[string]string{
+		"net.ipv4.tcp_rmem":   "4096 131072 6291456",
+		"net.ipv4.ip_forward": "0",
+	}))
+	assert.Equal(t, "# Managed by hardn: sysctl settings of the state applied with 'hardn apply'\n"+
+		"net.ipv4.ip_forward = 0\n"+
+		"net.ipv4.tcp_rmem = 4096 131072 6291456\n", string(mockFS.Files[model.StateSysctlPath]))
+
+	persisted, err = repo.ReadStateSysctl()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"net.ipv4.tcp_rmem":   "4096 131072 6291456",
+		"net.ipv4.ip_forward": "0",
+	}, persisted)
+}
+
+func TestOSUserRepository_CreateUser_ExistingSudo(t *testing.T) {
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandOutputs["groups george"] = []byte("george : george adm\n")
+	mockCommander.CommandOutputs["groups alice"] = []byte("alice : alice sudo\n")
+
+	repo := secondary.NewOSUserRepository(interfaces.NewMockFileSystem(), mockCommander, "debian")
+
+	require.NoError(t, repo.CreateUser(model.User{Username: "george", HasSudo: true}))
+	require.NoError(t, repo.CreateUser(model.User{Username: "alice", HasSudo: true}))
+
+	assert.Contains(t, mockCommander.ExecutedCommands, "usermod -aG sudo george")
+	assert.NotContains(t, mockCommander.ExecutedCommands, "usermod -aG sudo alice", "members of sudo are left alone")
+}
@@ -0,0 +1,57 @@
+// pkg/testing/status_report_test.go
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/abbott/hardn/pkg/security"
+	"github.com/stretchr/testify/assert"
+)
+
+func testStatusReport() *security.StatusReport {
+	status := &security.SecurityStatus{
+		FirewallEnabled: true,
+		WebServers:      []model.WebServer{{Name: model.WebServerNginx, BaselineApplied: true}},
+	}
+	osInfo := &osdetect.OSInfo{OsType: "debian", OsVersion: "12", OsCodename: "bookworm"}
+	return security.BuildStatusReport(osInfo, status, []string{"allow 2208/tcp"})
+}
+
+func TestReportFormatter_JSON(t *testing.T) {
+	formatter, err := security.NewReportFormatter("json", config.DefaultConfig())
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, formatter.Format(&buf, testStatusReport()))
+
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "Critical", decoded["risk_level"])
+	assert.Equal(t, []interface{}{"allow 2208/tcp"}, decoded["firewall_rules"])
+
+	status := decoded["status"].(map[string]interface{})
+	assert.Equal(t, true, status["firewall_enabled"])
+	assert.Equal(t, "nginx", status["web_servers"].([]interface{})[0].(map[string]interface{})["name"])
+}
+
+func TestReportFormatter_YAML(t *testing.T) {
+	formatter, err := security.NewReportFormatter("yaml", config.DefaultConfig())
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, formatter.Format(&buf, testStatusReport()))
+
+	assert.Contains(t, buf.String(), "risk_level: Critical\n")
+	assert.Contains(t, buf.String(), "  version: \"12\"\n")
+	assert.Contains(t, buf.String(), "firewall_rules:\n  - allow 2208/tcp\n")
+}
+
+func TestReportFormatter_Unsupported(t *testing.T) {
+	_, err := security.NewReportFormatter("xml", config.DefaultConfig())
+	assert.Error(t, err)
+}
@@ -0,0 +1,271 @@
+// pkg/testing/style_width_test.go
+package testing
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/abbott/hardn/pkg/style"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// textPieces are whole grapheme clusters of varying terminal width
+var textPieces = []string{
+	"a", "Z", "7", " ", "-", "é", "ß", "é", "中", "文", "한",
+	style.SymCheckMark, style.SymCrossMark, style.SymDotTri, style.SymEllipsis,
+	style.SymArrowRight, style.SymEmDash, "😀",
+}
+
+// styledText is a random string together with its unstyled form
+type styledText struct {
+	Plain  string
+	Styled string
+}
+
+// Generate builds text from random pieces wrapped in the styles the menus use
+func (styledText) Generate(r *rand.Rand, size int) reflect.Value {
+	wrappers := []func(string) string{
+		func(s string) string { return s },
+		func(s string) string { return style.Bolded(s) },
+		func(s string) string { return style.Dimmed(s, style.Gray10) },
+		func(s string) string { return style.Striked(s) },
+		func(s string) string { return style.Colored(style.Royal, s) },
+		func(s string) string { return style.Colored(style.BgDarkGreen, s) },
+		func(s string) string { return style.Bolded(style.Colored(style.BgGray03, s)) },
+		func(s string) string { return style.Hyperlink(s, "https://example.com/a;b") },
+	}
+
+	var plain, styled strings.Builder
+	for segments := r.Intn(4) + 1; segments > 0; segments-- {
+		var segment strings.Builder
+		for n := r.Intn(size/4 + 1); n > 0; n-- {
+			segment.WriteString(textPieces[r.Intn(len(textPieces))])
+		}
+		plain.WriteString(segment.String())
+		styled.WriteString(wrappers[r.Intn(len(wrappers))](segment.String()))
+	}
+	return reflect.ValueOf(styledText{Plain: plain.String(), Styled: styled.String()})
+}
+
+func checkProperty(t *testing.T, property interface{}) {
+	t.Helper()
+	if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCalculateVisualWidth_IgnoresStyling(t *testing.T) {
+	checkProperty(t, func(text styledText) bool {
+		return style.StripAnsi(text.Styled) == text.Plain &&
+			style.CalculateVisualWidth(text.Styled) == style.CalculateVisualWidth(text.Plain)
+	})
+}
+
+func TestCalculateVisualWidth_Examples(t *testing.T) {
+	assert.Equal(t, 5, style.CalculateVisualWidth(style.Bolded("hardn", style.Blue)))
+	assert.Equal(t, 4, style.CalculateVisualWidth("中文"))
+	assert.Equal(t, 3, style.CalculateVisualWidth("café"[1:]))
+	assert.Equal(t, 4, style.CalculateVisualWidth(style.Hyperlink("docs", "https://example.com")))
+	assert.Equal(t, 0, style.CalculateVisualWidth(style.CursorOff+style.CursorOn))
+
+	// Background labels count their padding but not their colors
+	assert.Equal(t, len("v1.2.3")+3, style.CalculateVisualWidth(style.ColoredLabel("v1.2.3")))
+}
+
+func TestPadRight_ReachesWidth(t *testing.T) {
+	checkProperty(t, func(text styledText, width uint8) bool {
+		padded := style.PadRight(text.Styled, int(width))
+		textWidth := style.CalculateVisualWidth(text.Styled)
+
+		expected := int(width)
+		if textWidth > expected {
+			expected = textWidth
+		}
+		return strings.HasPrefix(padded, text.Styled) &&
+			style.CalculateVisualWidth(padded) == expected
+	})
+}
+
+func TestCenterText_Balanced(t *testing.T) {
+	checkProperty(t, func(text styledText, width uint8) bool {
+		centered := style.CenterText(text.Styled, int(width))
+		textWidth := style.CalculateVisualWidth(text.Styled)
+		if textWidth >= int(width) {
+			return centered == text.Styled
+		}
+		if strings.TrimSpace(text.Plain) == "" {
+			// Blank text can not be located in the padding
+			return style.CalculateVisualWidth(centered) == int(width)
+		}
+
+		left := strings.Index(centered, text.Styled)
+		right := style.CalculateVisualWidth(centered) - textWidth - left
+		return style.CalculateVisualWidth(centered) == int(width) &&
+			strings.TrimSpace(centered[:left]) == "" && (right == left || right == left+1)
+	})
+}
+
+func TestTruncateVisible_FitsWidth(t *testing.T) {
+	checkProperty(t, func(text styledText, width uint8) bool {
+		truncated := style.TruncateVisible(text.Styled, int(width), style.SymEllipsis)
+		if style.CalculateVisualWidth(text.Styled) <= int(width) {
+			return truncated == text.Styled
+		}
+
+		plain := strings.TrimSuffix(style.StripAnsi(truncated), style.SymEllipsis)
+		return style.CalculateVisualWidth(truncated) <= int(width) &&
+			strings.HasPrefix(text.Plain, plain)
+	})
+}
+
+func TestTruncateVisible_ResetsStyling(t *testing.T) {
+	truncated := style.TruncateVisible(style.Bolded("hardening"), 5, "…")
+	assert.Equal(t, "hard…", style.StripAnsi(truncated))
+	assert.True(t, strings.HasSuffix(truncated, style.Reset))
+}
+
+func TestStatusFormatter_AlignsStatusColumn(t *testing.T) {
+	checkProperty(t, func(labels []styledText) bool {
+		if len(labels) == 0 {
+			return true
+		}
+
+		plainLabels := make([]string, len(labels))
+		for i, label := range labels {
+			plainLabels[i] = label.Plain
+		}
+		formatter := style.NewStatusFormatter(plainLabels, 2)
+
+		// The status starts in the same column for every label
+		column := -1
+		for _, label := range labels {
+			line := style.StripAnsi(formatter.FormatLine(style.SymInfo, style.Cyan, label.Styled, "|", style.Cyan, ""))
+			statusColumn := style.CalculateVisualWidth(line[:strings.LastIndex(line, "|")])
+			if column == -1 {
+				column = statusColumn
+			} else if statusColumn != column {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestMenu_AlignsDescriptions(t *testing.T) {
+	checkProperty(t, func(titles []styledText) bool {
+		var options []style.MenuOption
+		for i, title := range titles {
+			option := style.MenuOption{Number: i + 1, Title: title.Styled, Description: "|"}
+			if i%3 == 0 {
+				option.Style = "strike"
+			}
+			options = append(options, option)
+		}
+		menu := style.NewMenu("Select an option", options)
+
+		// Descriptions start in the same column for every option
+		column := -1
+		for _, option := range options {
+			line := style.StripAnsi(menu.FormatOption(option))
+			descColumn := style.CalculateVisualWidth(line[:strings.LastIndex(line, "|")])
+			if column == -1 {
+				column = descColumn
+			} else if descColumn != column {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// captureStdout returns what fn prints
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, reader)
+		output <- buf.String()
+	}()
+
+	fn()
+	writer.Close()
+	return <-output
+}
+
+func TestBox_LinesKeepBorders(t *testing.T) {
+	box := style.NewBox(style.BoxConfig{
+		Width:           32,
+		ShowTopBorder:   true,
+		ShowLeftBorder:  true,
+		ShowRightBorder: true,
+		Title:           style.Bolded("Status"),
+	})
+
+	var contents []styledText
+	checkProperty(t, func(content styledText) bool {
+		contents = append(contents, content)
+		return true
+	})
+
+	output := captureStdout(t, func() {
+		box.DrawTop()
+		for _, content := range contents {
+			box.DrawLine(content.Styled)
+			box.DrawTruncatedText(content.Styled, "...")
+		}
+		box.DrawEmpty()
+		box.DrawBottom()
+	})
+
+	// Every row of the box is exactly as wide as its top border
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	require.NotEmpty(t, lines)
+	for _, line := range lines {
+		assert.Equal(t, 34, style.CalculateVisualWidth(line), "line %q", style.StripAnsi(line))
+	}
+}
+
+func TestMenu_DisabledOption(t *testing.T) {
+	menu := style.NewMenu("Select an option", []style.MenuOption{
+		{Number: 1, Title: "Install UFW", Description: "Install the firewall"},
+		{Number: 2, Title: "Configure firewall", Description: "Set up rules", DisabledReason: "requires UFW installed"},
+	})
+
+	reason, disabled := menu.DisabledReason("2")
+	assert.True(t, disabled)
+	assert.Equal(t, "requires UFW installed", reason)
+
+	_, disabled = menu.DisabledReason("1")
+	assert.False(t, disabled)
+	_, disabled = menu.DisabledReason("9")
+	assert.False(t, disabled)
+
+	// Disabled options show the reason in the description column
+	enabled := style.StripAnsi(menu.FormatOption(style.MenuOption{Number: 1, Title: "Install UFW", Description: "|"}))
+	line := style.StripAnsi(menu.FormatOption(style.MenuOption{Number: 2, Title: "Configure firewall", DisabledReason: "requires UFW installed"}))
+	assert.Equal(t, strings.Index(enabled, "|"), strings.Index(line, "Unavailable: requires UFW installed"))
+}
+
+func TestBreadcrumb(t *testing.T) {
+	crumb := style.Breadcrumb("Main", "Firewall", "App Profiles")
+	assert.Equal(t, "Main > Firewall > App Profiles", style.StripAnsi(crumb))
+	assert.True(t, strings.HasSuffix(crumb, style.Bolded("App Profiles", style.Blue)))
+
+	assert.Equal(t, "Main", style.StripAnsi(style.Breadcrumb("Main")))
+	assert.Equal(t, "", style.Breadcrumb())
+}
@@ -0,0 +1,121 @@
+// pkg/testing/sudo_provider_test.go
+package testing
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sudoPrefix = "sudo -n -- env PATH=" + interfaces.CommandPath + " LC_ALL=C LANG=C "
+
+func permissionDenied(op, path string) error {
+	return &fs.PathError{Op: op, Path: path, Err: fs.ErrPermission}
+}
+
+func TestSudoProvider_Commands(t *testing.T) {
+	commander := interfaces.NewMockCommander()
+	provider := interfaces.SudoProvider(&interfaces.Provider{FS: interfaces.MockFileSystem{}, Commander: commander})
+
+	_, err := provider.Commander.Execute("ufw", "--force", "enable")
+	require.NoError(t, err)
+	_, err = provider.Commander.ExecuteWithInput("y\n", "apt-get", "install", "ufw")
+	require.NoError(t, err)
+	_, err = provider.Commander.Execute("which", "ufw")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		sudoPrefix + "ufw --force enable",
+		"INPUT:y\n|" + sudoPrefix + "apt-get install ufw",
+		"which ufw",
+	}, commander.ExecutedCommands)
+}
+
+func TestSudoProvider_FileFallback(t *testing.T) {
+	commander := interfaces.NewMockCommander()
+	mockFS := interfaces.MockFileSystem{
+		Files: map[string][]byte{
+			"/home/admin/notes": []byte("mine"),
+		},
+		Directories: map[string]bool{},
+		ReadFileError: map[string]error{
+			"/etc/shadow": permissionDenied("open", "/etc/shadow"),
+		},
+		WriteFileError: map[string]error{
+			"/etc/ssh/sshd_config.d/hardn.conf": permissionDenied("open", "/etc/ssh/sshd_config.d/hardn.conf"),
+		},
+		StatError: map[string]error{
+			"/etc/ssh/sshd_config.d/hardn.conf": permissionDenied("stat", "/etc/ssh/sshd_config.d/hardn.conf"),
+			"/root/.ssh":                        permissionDenied("stat", "/root/.ssh"),
+		},
+		RenameError: map[string]error{
+			"/etc/hosts.deny": permissionDenied("rename", "/etc/hosts.deny"),
+		},
+	}
+	provider := interfaces.SudoProvider(&interfaces.Provider{FS: mockFS, Commander: commander})
+
+	commander.CommandOutputs[sudoPrefix+"cat -- /etc/shadow"] = []byte("root:*:19000:0:99999:7:::\n")
+	commander.CommandErrors[sudoPrefix+"stat -c %s %f %Y -- /etc/ssh/sshd_config.d/hardn.conf"] = errors.New("exit status 1")
+	commander.CommandOutputs[sudoPrefix+"stat -c %s %f %Y -- /root/.ssh"] = []byte("4096 41c0 1717200000\n")
+
+	// Files the user can read are not escalated
+	data, err := provider.FS.ReadFile("/home/admin/notes")
+	require.NoError(t, err)
+	assert.Equal(t, "mine", string(data))
+
+	data, err = provider.FS.ReadFile("/etc/shadow")
+	require.NoError(t, err)
+	assert.Equal(t, "root:*:19000:0:99999:7:::\n", string(data))
+
+	info, err := provider.FS.Stat("/root/.ssh")
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+	assert.Equal(t, int64(1717200000), info.ModTime().Unix())
+
+	require.NoError(t, provider.FS.WriteFile("/etc/ssh/sshd_config.d/hardn.conf", []byte("PermitRootLogin no\n"), 0600))
+	require.NoError(t, provider.FS.Rename("/etc/hosts.deny", "/etc/hosts.deny.hardn"))
+
+	assert.Equal(t, []string{
+		sudoPrefix + "cat -- /etc/shadow",
+		sudoPrefix + "stat -c %s %f %Y -- /root/.ssh",
+		sudoPrefix + "stat -c %s %f %Y -- /etc/ssh/sshd_config.d/hardn.conf",
+		sudoPrefix + "mkdir -p -- /etc/ssh/sshd_config.d",
+		sudoPrefix + "install -m 600 -- /dev/null /etc/ssh/sshd_config.d/hardn.conf",
+		"INPUT:PermitRootLogin no\n|" + sudoPrefix + "dd of=/etc/ssh/sshd_config.d/hardn.conf status=none",
+		sudoPrefix + "mv -f -- /etc/hosts.deny /etc/hosts.deny.hardn",
+	}, commander.ExecutedCommands)
+}
+
+func TestSudoProvider_MissingFile(t *testing.T) {
+	commander := interfaces.NewMockCommander()
+	mockFS := interfaces.MockFileSystem{
+		ReadFileError: map[string]error{
+			"/root/.ssh/authorized_keys": permissionDenied("open", "/root/.ssh/authorized_keys"),
+		},
+	}
+	provider := interfaces.SudoProvider(&interfaces.Provider{FS: mockFS, Commander: &outputOnErrorCommander{
+		MockCommander: commander,
+		output:        "cat: /root/.ssh/authorized_keys: No such file or directory",
+	}})
+
+	_, err := provider.FS.ReadFile("/root/.ssh/authorized_keys")
+	assert.True(t, os.IsNotExist(err), "missing files are reported as such: %v", err)
+}
+
+// outputOnErrorCommander fails every command with output, as a real
+// command writing its error to stderr would
+type outputOnErrorCommander struct {
+	*interfaces.MockCommander
+	output string
+}
+
+func (c *outputOnErrorCommander) Execute(command string, args ...string) ([]byte, error) {
+	_, _ = c.MockCommander.Execute(command, args...)
+	return []byte(c.output), errors.New("exit status 1")
+}
@@ -0,0 +1,124 @@
+// pkg/testing/sudoers_repository_test.go
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSudoersPolicy_Parse(t *testing.T) {
+	policy := &model.SudoersPolicy{}
+	policy.Parse("/etc/sudoers", []byte(`# comment
+Defaults:george env_keep += "HARDN_CONFIG"
+Cmnd_Alias SERVICES = /usr/bin/systemctl, /usr/bin/journalctl : SHELLS = /bin/sh
+#1000 ALL=(ALL) ALL
+alice, %ops  ALL = (ALL:ALL) NOPASSWD: SERVICES, PASSWD: /usr/bin/apt # trailing
+bob ALL=(root) TIMEOUT=5m /usr/bin/du \
+	/var/log, NOEXEC: /usr/bin/less
+#includedir /etc/sudoers.d
+`))
+
+	assert.Equal(t, []model.SudoDefaults{{File: "/etc/sudoers", Line: 2, Text: `Defaults:george env_keep += "HARDN_CONFIG"`}}, policy.Defaults)
+	assert.Equal(t, []string{"/usr/bin/systemctl", "/usr/bin/journalctl"}, policy.CmndAliases["SERVICES"])
+	assert.Equal(t, []string{"/bin/sh"}, policy.CmndAliases["SHELLS"])
+
+	require.Len(t, policy.Rules, 3)
+	assert.Equal(t, []string{"#1000"}, policy.Rules[0].Users, "a # before a digit is a uid")
+
+	rule := policy.Rules[1]
+	assert.Equal(t, 5, rule.Line)
+	assert.Equal(t, []string{"alice", "%ops"}, rule.Users)
+	assert.Equal(t, []model.SudoCommand{
+		{RunAs: "ALL:ALL", Command: "SERVICES", NoPassword: true},
+		{RunAs: "ALL:ALL", Command: "/usr/bin/apt", NoPassword: false},
+	}, rule.Commands)
+
+	rule = policy.Rules[2]
+	assert.Equal(t, 6, rule.Line)
+	assert.Equal(t, []model.SudoCommand{
+		{RunAs: "root", Command: "/usr/bin/du /var/log"},
+		{RunAs: "root", Command: "/usr/bin/less"},
+	}, rule.Commands)
+
+	assert.False(t, policy.GrantsAll("SERVICES"))
+	policy.Parse("/etc/sudoers.d/all", []byte("Cmnd_Alias EVERYTHING = SHELLS, ALL\n"))
+	assert.True(t, policy.GrantsAll("EVERYTHING"))
+}
+
+func TestOSSudoersRepository_ReadSudoers(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSSudoersRepository(mockFS, mockCommander, "debian")
+
+	// Without sudo there is no policy
+	files, err := repo.ReadSudoers()
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	mockFS.Files["/etc/sudoers"] = []byte("root ALL=(ALL:ALL) ALL\n@include sudoers.local\n@includedir /etc/sudoers.d\n")
+	mockFS.Files["/etc/sudoers.local"] = []byte("@include /etc/sudoers\n")
+	mockFS.Files["/etc/sudoers.d/zed"] = []byte("zed ALL=(ALL) ALL\n")
+	mockFS.Files["/etc/sudoers.d/deploy"] = []byte("deploy ALL=(ALL) NOPASSWD: ALL\n")
+	mockFS.Files["/etc/sudoers.d/README.txt"] = []byte("# not read\n")
+	mockFS.Files["/etc/sudoers.d/deploy~"] = []byte("# not read\n")
+	require.NoError(t, mockFS.MkdirAll("/etc/sudoers.d", 0750))
+	mockCommander.CommandOutputs["find /etc/sudoers.d -mindepth 1 -maxdepth 1 -type f"] =
+		[]byte("/etc/sudoers.d/zed\n/etc/sudoers.d/README.txt\n/etc/sudoers.d/deploy~\n/etc/sudoers.d/deploy\n")
+
+	files, err = repo.ReadSudoers()
+	require.NoError(t, err)
+	var paths []string
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	assert.Equal(t, []string{"/etc/sudoers", "/etc/sudoers.local", "/etc/sudoers.d/deploy", "/etc/sudoers.d/zed"}, paths)
+}
+
+func TestOSSudoersRepository_WriteUserSudoers(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSSudoersRepository(mockFS, mockCommander, "debian")
+	content := []byte("george ALL=(root) /usr/bin/systemctl\n")
+
+	require.NoError(t, repo.WriteUserSudoers("george", content))
+	assert.Equal(t, content, mockFS.Files["/etc/sudoers.d/george"])
+	assert.Len(t, mockFS.Files, 1, "no temporary file is written")
+	assert.Equal(t, "INPUT:"+string(content)+"|visudo -c -f -", mockCommander.ExecutedCommands[0])
+
+	// A file visudo rejects is never installed
+	delete(mockFS.Files, "/etc/sudoers.d/george")
+	mockCommander.CommandErrors["INPUT:george ALL=(root) systemctl\n|visudo -c -f -"] = errors.New("exit status 1")
+	err := repo.WriteUserSudoers("george", []byte("george ALL=(root) systemctl\n"))
+	assert.ErrorContains(t, err, "invalid sudoers configuration")
+	assert.Empty(t, mockFS.Files)
+
+	data, err := repo.ReadUserSudoers("george")
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+}
+
+func TestOSSudoersRepository_Groups(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandOutputs["id -nG george"] = []byte("george sudo docker\n")
+	mockFS.Files["/usr/bin/systemctl"] = []byte{}
+
+	repo := secondary.NewOSSudoersRepository(mockFS, mockCommander, "debian")
+	groups, err := repo.UserGroups("george")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"george", "sudo", "docker"}, groups)
+	assert.Equal(t, []string{"/usr/bin/systemctl"}, repo.InstalledCommands([]string{"/usr/bin/systemctl", "/sbin/rc-service"}))
+
+	require.NoError(t, repo.RemoveFromGroup("george", "sudo"))
+	assert.Contains(t, mockCommander.ExecutedCommands, "gpasswd -d george sudo")
+
+	alpine := secondary.NewOSSudoersRepository(mockFS, mockCommander, "alpine")
+	require.NoError(t, alpine.RemoveFromGroup("george", "wheel"))
+	assert.Contains(t, mockCommander.ExecutedCommands, "delgroup george wheel")
+}
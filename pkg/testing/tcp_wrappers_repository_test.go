@@ -0,0 +1,72 @@
+// pkg/testing/tcp_wrappers_repository_test.go
+package testing
+
+import (
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const hostsAllow = `# /etc/hosts.allow: list of hosts that are allowed to access the system.
+#
+sshd: 192.168.1. , [2001:db8::]/32
+vsftpd in.ftpd: 10.0.0.0/255.0.0.0 \
+	172.16.0.0/12
+ALL: LOCAL : spawn /bin/echo %d %c >> /var/log/wrapped
+`
+
+func TestParseHostsAccess(t *testing.T) {
+	rules := secondary.ParseHostsAccess([]byte(hostsAllow), secondary.HostsAllowPath)
+
+	assert.Equal(t, []model.HostsAccessRule{
+		{File: secondary.HostsAllowPath, Line: 3, Daemons: []string{"sshd"}, Clients: []string{"192.168.1.", "[2001:db8::]/32"}},
+		{File: secondary.HostsAllowPath, Line: 4, Daemons: []string{"vsftpd", "in.ftpd"}, Clients: []string{"10.0.0.0/255.0.0.0", "172.16.0.0/12"}},
+		{File: secondary.HostsAllowPath, Line: 6, Daemons: []string{"ALL"}, Clients: []string{"LOCAL"},
+			Options: "spawn /bin/echo %d %c >> /var/log/wrapped"},
+	}, rules)
+	assert.Equal(t, "sshd: 192.168.1., [2001:db8::]/32", rules[0].String())
+}
+
+func TestOSTCPWrappersRepository_DaemonUsesLibwrap(t *testing.T) {
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandOutputs["which sshd"] = []byte("/usr/sbin/sshd\n")
+	mockCommander.CommandOutputs["ldd /usr/sbin/sshd"] = []byte("\tlibcrypto.so.3 => /lib/x86_64-linux-gnu/libcrypto.so.3\n")
+	mockCommander.CommandOutputs["which vsftpd"] = []byte("/usr/sbin/vsftpd\n")
+	mockCommander.CommandOutputs["ldd /usr/sbin/vsftpd"] = []byte("\tlibwrap.so.0 => /lib/x86_64-linux-gnu/libwrap.so.0\n")
+	mockCommander.CommandErrors["which in.telnetd"] = assert.AnError
+
+	repo := secondary.NewOSTCPWrappersRepository(interfaces.NewMockFileSystem(), mockCommander)
+
+	uses, found := repo.DaemonUsesLibwrap("sshd")
+	assert.False(t, uses)
+	assert.True(t, found)
+	uses, found = repo.DaemonUsesLibwrap("vsftpd")
+	assert.True(t, uses)
+	assert.True(t, found)
+	_, found = repo.DaemonUsesLibwrap("in.telnetd")
+	assert.False(t, found)
+}
+
+func TestOSTCPWrappersRepository_DisableHostsAccess(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[secondary.HostsAllowPath] = []byte(hostsAllow)
+	mockFS.Files[secondary.HostsDenyPath] = []byte("# hosts.deny\n")
+
+	repo := secondary.NewOSTCPWrappersRepository(mockFS, interfaces.NewMockCommander())
+	require.NoError(t, repo.DisableHostsAccess())
+
+	allow, deny, err := repo.GetHostsAccessRules()
+	require.NoError(t, err)
+	assert.Empty(t, allow)
+	assert.Empty(t, deny)
+
+	assert.Contains(t, string(mockFS.Files[secondary.HostsAllowPath]),
+		"# Rules disabled by hardn after moving them to the firewall\n# /etc/hosts.allow")
+	assert.Contains(t, string(mockFS.Files[secondary.HostsAllowPath]), "\n# sshd: 192.168.1.")
+	// A file without rules is left untouched
+	assert.Equal(t, "# hosts.deny\n", string(mockFS.Files[secondary.HostsDenyPath]))
+}
@@ -0,0 +1,160 @@
+// pkg/testing/time_sync_repository_test.go
+package testing
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const chronyConf = `# Use Debian vendor zone.
+pool 2.debian.pool.ntp.org iburst
+sourcedir /run/chrony-dhcp
+
+driftfile /var/lib/chrony/chrony.drift
+makestep 1 3
+`
+
+const chronyTracking = `Reference ID    : A29FC801 (time.cloudflare.com)
+Stratum         : 4
+Ref time (UTC)  : Sat Oct 17 10:12:01 2026
+System time     : 0.000120415 seconds slow of NTP time
+Last offset     : -0.000034182 seconds
+Leap status     : Normal
+`
+
+// stopTimeServices makes every time synchronization service but the
+// running ones report inactive and disabled under systemd
+func stopTimeServices(commander *interfaces.MockCommander, running ...string) {
+	for _, name := range []string{"chrony", "chronyd", "systemd-timesyncd", "ntpd", "ntp", "ntpsec"} {
+		if slices.Contains(running, name) {
+			continue
+		}
+		commander.CommandErrors["systemctl is-active --quiet "+name] = errors.New("inactive")
+		commander.CommandErrors["systemctl is-enabled --quiet "+name] = errors.New("disabled")
+	}
+}
+
+func TestSetChronySources(t *testing.T) {
+	servers := []string{"time.cloudflare.com", "nts.netnod.se"}
+	updated := secondary.SetChronySources(chronyConf, servers, true)
+
+	assert.Contains(t, updated, "# pool 2.debian.pool.ntp.org iburst")
+	assert.Contains(t, updated, "# sourcedir /run/chrony-dhcp")
+	assert.Contains(t, updated, "makestep 1 3", "other directives are kept")
+	assert.Contains(t, updated, "server time.cloudflare.com iburst nts\nserver nts.netnod.se iburst nts\n")
+
+	parsed, nts := secondary.ParseChronySources(updated)
+	assert.Equal(t, servers, parsed)
+	assert.True(t, nts)
+
+	// Running again replaces the block instead of adding another
+	again := secondary.SetChronySources(updated, servers, true)
+	assert.Equal(t, updated, again)
+
+	plain := secondary.SetChronySources(updated, []string{"ntp.example.com"}, false)
+	parsed, nts = secondary.ParseChronySources(plain)
+	assert.Equal(t, []string{"ntp.example.com"}, parsed)
+	assert.False(t, nts)
+}
+
+func TestParseChronyTracking(t *testing.T) {
+	status := &model.TimeSyncStatus{}
+	secondary.ParseChronyTracking(chronyTracking, status)
+
+	assert.True(t, status.Synchronized)
+	assert.Equal(t, "time.cloudflare.com", status.Source)
+	assert.Equal(t, 4, status.Stratum)
+	assert.InDelta(t, -0.000120415, status.OffsetSeconds, 1e-12)
+	assert.False(t, status.Drifting())
+	assert.Equal(t, "-120µs", status.Offset())
+
+	unsynchronized := &model.TimeSyncStatus{}
+	secondary.ParseChronyTracking("Reference ID    : 00000000 ()\nLeap status     : Not synchronised\n", unsynchronized)
+	assert.False(t, unsynchronized.Synchronized)
+}
+
+func TestParseTimesyncStatus(t *testing.T) {
+	output := `       Server: 162.159.200.1 (time.cloudflare.com)
+Poll interval: 34min 8s (min: 32s; max 34min 8s)
+         Leap: normal
+      Stratum: 3
+       Offset: +1.2s
+`
+	status := &model.TimeSyncStatus{}
+	secondary.ParseTimesyncStatus(output, status)
+
+	assert.Equal(t, "time.cloudflare.com", status.Source)
+	assert.Equal(t, 3, status.Stratum)
+	assert.InDelta(t, 1.2, status.OffsetSeconds, 1e-9)
+	assert.True(t, status.Drifting())
+}
+
+func TestOSTimeSyncRepository_ConfigureChronyDebian(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	commander := interfaces.NewMockCommander()
+	mockFS.Files[model.ChronyConfigPath] = []byte(chronyConf)
+	// chrony is installed, systemd-timesyncd still runs
+	commander.CommandOutputs["dpkg-query -W -f="+secondary.DpkgStatusFormat+" chrony"] = []byte("install ok installed")
+	stopTimeServices(commander, "systemd-timesyncd")
+	repo := secondary.NewOSTimeSyncRepository(mockFS, commander, "debian")
+
+	policy := model.TimeSyncPolicy{Daemon: model.TimeSyncChrony, Servers: []string{"time.cloudflare.com"}, NTS: true}
+	require.NoError(t, repo.ConfigureTimeSync(policy))
+
+	assert.Contains(t, string(mockFS.Files[model.ChronyConfigPath]), "server time.cloudflare.com iburst nts")
+	assert.NotContains(t, commander.ExecutedCommands, "apt-get install -y chrony")
+	assert.Contains(t, commander.ExecutedCommands, "systemctl disable --now systemd-timesyncd")
+	assert.NotContains(t, commander.ExecutedCommands, "systemctl disable --now ntp")
+	assert.Contains(t, commander.ExecutedCommands, "systemctl enable --now chrony")
+	assert.Contains(t, commander.ExecutedCommands, "systemctl restart chrony")
+}
+
+func TestOSTimeSyncRepository_ChronyStatus(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	commander := interfaces.NewMockCommander()
+	mockFS.Files[model.ChronyRHELConfigPath] = []byte(
+		secondary.SetChronySources(chronyConf, []string{"time.cloudflare.com"}, true))
+	commander.CommandOutputs["chronyc tracking"] = []byte(chronyTracking)
+	stopTimeServices(commander, "chronyd")
+	repo := secondary.NewOSTimeSyncRepository(mockFS, commander, "rocky")
+
+	status, err := repo.GetTimeSyncStatus()
+	require.NoError(t, err)
+	assert.Equal(t, model.TimeSyncChrony, status.Daemon)
+	assert.Equal(t, []string{"time.cloudflare.com"}, status.Servers)
+	assert.True(t, status.NTS)
+	assert.True(t, status.Managed)
+	assert.True(t, status.Compliant())
+}
+
+func TestOSTimeSyncRepository_BusyboxNTPDAlpine(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	commander := interfaces.NewMockCommander()
+	// Only ntpd is started
+	for _, name := range []string{"chrony", "chronyd", "systemd-timesyncd", "ntp", "ntpsec"} {
+		commander.CommandErrors["rc-service "+name+" status"] = errors.New("stopped")
+	}
+	commander.CommandOutputs["adjtimex"] = []byte("    status: 8193 (PLL | NANO)\n       ret: 0 (clock synchronized)\n")
+	repo := secondary.NewOSTimeSyncRepository(mockFS, commander, "alpine")
+
+	servers := []string{"time.cloudflare.com", "ptbtime1.ptb.de"}
+	require.NoError(t, repo.ConfigureTimeSync(model.TimeSyncPolicy{Daemon: model.TimeSyncNTPD, Servers: servers}))
+	assert.Equal(t, "# Managed by hardn\nNTPD_OPTS=\"-N -p time.cloudflare.com -p ptbtime1.ptb.de\"\n",
+		string(mockFS.Files[model.BusyboxNTPDConfPath]))
+	assert.Contains(t, commander.ExecutedCommands, "rc-service ntpd restart")
+
+	status, err := repo.GetTimeSyncStatus()
+	require.NoError(t, err)
+	assert.Equal(t, model.TimeSyncNTPD, status.Daemon)
+	assert.Equal(t, servers, status.Servers)
+	assert.True(t, status.Managed)
+	assert.True(t, status.Synchronized)
+	assert.Equal(t, "not reported", status.Offset())
+}
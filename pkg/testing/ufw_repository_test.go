@@ -0,0 +1,228 @@
+// pkg/testing/ufw_repository_test.go
+package testing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUFWFirewallRepository_AddRule(t *testing.T) {
+	tests := []struct {
+		name            string
+		rule            model.FirewallRule
+		expectedCmd     string
+		expectedPreview string
+	}{
+		{
+			name:            "port",
+			rule:            model.FirewallRule{Action: "allow", Protocol: "tcp", Port: 8443},
+			expectedCmd:     "ufw allow 8443/tcp",
+			expectedPreview: "ufw allow 8443/tcp",
+		},
+		{
+			name:            "range with comment",
+			rule:            model.FirewallRule{Action: "deny", Protocol: "udp", Port: 6000, EndPort: 6007, Description: "X11"},
+			expectedCmd:     "ufw deny 6000:6007/udp comment X11",
+			expectedPreview: "ufw deny 6000:6007/udp comment X11",
+		},
+		{
+			name:            "source",
+			rule:            model.FirewallRule{Action: "allow", Protocol: "tcp", Port: 5432, SourceIP: "10.0.0.0/8", Description: "app servers"},
+			expectedCmd:     "ufw allow from 10.0.0.0/8 to any port 5432 proto tcp comment app servers",
+			expectedPreview: "ufw allow from 10.0.0.0/8 to any port 5432 proto tcp comment 'app servers'",
+		},
+		{
+			name:            "family",
+			rule:            model.FirewallRule{Action: "allow", Protocol: "udp", Port: 51820, Family: model.FamilyIPv6},
+			expectedCmd:     "ufw allow from ::/0 to any port 51820 proto udp",
+			expectedPreview: "ufw allow from ::/0 to any port 51820 proto udp",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCommander := interfaces.NewMockCommander()
+			repo := secondary.NewUFWFirewallRepository(interfaces.NewMockFileSystem(), mockCommander, "debian")
+
+			assert.Equal(t, tc.expectedPreview, repo.RuleCommand(tc.rule))
+			require.NoError(t, repo.AddRule(tc.rule))
+			assert.Equal(t, []string{tc.expectedCmd}, mockCommander.ExecutedCommands)
+		})
+	}
+}
+
+func TestUFWFirewallRepository_RemoveRule(t *testing.T) {
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewUFWFirewallRepository(interfaces.NewMockFileSystem(), mockCommander, "debian")
+
+	rule := model.FirewallRule{Action: "allow", Protocol: "tcp", Port: 5432, SourceIP: "10.0.0.0/8", Description: "app servers"}
+	require.NoError(t, repo.RemoveRule(rule))
+	assert.Equal(t, []string{"ufw delete allow from 10.0.0.0/8 to any port 5432 proto tcp"}, mockCommander.ExecutedCommands)
+}
+
+// TestUFWFirewallRepository_GetRules checks every incoming rule is read,
+// once, from the IPv4 and IPv6 rules files
+func TestUFWFirewallRepository_GetRules(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[secondary.UFWUserRulesPath] = []byte(ufwUserRules)
+	mockFS.Files[secondary.UFWUser6RulesPath] = []byte(`### tuple ### allow tcp 22 ::/0 any ::/0 in
+### tuple ### allow tcp 80,443 ::/0 any ::/0 Nginx%20Full - in
+`)
+
+	repo := secondary.NewUFWFirewallRepository(mockFS, interfaces.NewMockCommander(), "ubuntu")
+	rules, err := repo.GetRules()
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"allow 22/tcp",
+		"limit 2208/tcp",
+		"allow 30443",
+		"allow 5432/tcp from 10.0.0.0/8",
+		"deny 8080/tcp",
+		"allow 53/udp to 192.168.1.1",
+		"allow 80,443/tcp",
+	}, rules)
+
+	delete(mockFS.Files, secondary.UFWUserRulesPath)
+	_, err = repo.GetRules()
+	assert.Error(t, err)
+}
+
+// TestUFWFirewallRepository_ConfigurePortKnocking checks knockd is
+// installed and listens on the default route's interface, and is stopped
+// again once knocking is turned off
+func TestUFWFirewallRepository_ConfigurePortKnocking(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[secondary.ProcNetRoutePath] = []byte(
+		"Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n" +
+			"ens3\t00000000\t0101A8C0\t0003\t0\t0\t100\t00000000\t0\t0\t0\n")
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandErrors["which knockd"] = errors.New("exit status 1")
+
+	repo := secondary.NewUFWFirewallRepository(mockFS, mockCommander, "debian")
+	knocking := &model.PortKnocking{Sequence: []int{7000, 8000, 9000}, SeqTimeout: 10, OpenTimeout: 30}
+	require.NoError(t, repo.ConfigurePortKnocking(2222, knocking))
+
+	assert.Contains(t, mockCommander.ExecutedCommands, "apt-get install -y knockd")
+	assert.Contains(t, mockCommander.ExecutedCommands, "systemctl restart knockd")
+	conf := string(mockFS.Files[secondary.KnockdConfPath])
+	assert.Contains(t, conf, "Interface = ens3")
+	assert.Contains(t, conf, "sequence      = 7000,8000,9000")
+	assert.Contains(t, conf, "start_command = /usr/sbin/ufw allow from %IP% to any port 2222 proto tcp")
+	assert.Contains(t, conf, "stop_command  = /usr/sbin/ufw delete allow from %IP% to any port 2222 proto tcp")
+	assert.Contains(t, string(mockFS.Files[secondary.KnockdDefaultsPath]), `KNOCKD_OPTS="-i ens3"`)
+	assert.True(t, repo.PortKnockingActive())
+
+	require.NoError(t, repo.ConfigurePortKnocking(2222, nil))
+	assert.Contains(t, mockCommander.ExecutedCommands, "systemctl disable --now knockd")
+
+	delete(mockFS.Files, secondary.ProcNetRoutePath)
+	assert.Error(t, repo.ConfigurePortKnocking(2222, knocking))
+}
+
+func TestUFWFirewallRepository_SaveFirewallConfigIPv6Deny(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[secondary.UFWDefaultsPath] = []byte("IPV6=no\nDEFAULT_INPUT_POLICY=\"DROP\"\n")
+	mockCommander := interfaces.NewMockCommander()
+	mockCommander.CommandOutputs["ufw status"] = []byte("Status: active\n")
+	repo := secondary.NewUFWFirewallRepository(mockFS, mockCommander, "debian")
+
+	config := model.FirewallConfig{
+		DefaultIncoming: "deny",
+		DefaultOutgoing: "allow",
+		IPv6:            model.IPv6Deny,
+		Rules: []model.FirewallRule{
+			{Action: "allow", Protocol: "tcp", Port: 22},
+			{Action: "allow", Protocol: "tcp", Port: 443, Family: model.FamilyIPv4},
+		},
+		ApplicationProfiles: []model.FirewallProfile{{Name: "web", Title: "Web", Ports: []string{"80/tcp"}}},
+	}
+	require.NoError(t, repo.SaveFirewallConfig(config))
+
+	assert.Contains(t, string(mockFS.Files[secondary.UFWDefaultsPath]), "IPV6=yes")
+	assert.Contains(t, mockCommander.ExecutedCommands, "ufw allow 22/tcp")
+	assert.Contains(t, mockCommander.ExecutedCommands, "ufw allow from 0.0.0.0/0 to any port 443 proto tcp")
+	assert.Contains(t, mockCommander.ExecutedCommands, "ufw allow from 0.0.0.0/0 to any app web")
+}
+
+func TestUFWFirewallRepository_SetIPv6Kernel(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[secondary.ProcIPv6DisablePath] = []byte("0\n")
+	mockFS.Files[secondary.GrubDefaultsPath] = []byte("GRUB_DEFAULT=0\nGRUB_CMDLINE_LINUX=\"quiet\"\n")
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewUFWFirewallRepository(mockFS, mockCommander, "ubuntu")
+
+	enabled, bootDisabled := repo.GetIPv6Kernel()
+	assert.True(t, enabled)
+	assert.False(t, bootDisabled)
+
+	require.NoError(t, repo.SetIPv6Kernel(true, false))
+	assert.Contains(t, mockCommander.ExecutedCommands, "sysctl -w net.ipv6.conf.all.disable_ipv6=1")
+	assert.Contains(t, string(mockFS.Files[secondary.IPv6SysctlPath]), "net.ipv6.conf.default.disable_ipv6 = 1")
+
+	// The boot parameter replaces the sysctl file
+	require.NoError(t, repo.SetIPv6Kernel(true, true))
+	assert.NotContains(t, mockFS.Files, secondary.IPv6SysctlPath)
+	assert.Contains(t, string(mockFS.Files[secondary.GrubDefaultsPath]), `GRUB_CMDLINE_LINUX="quiet ipv6.disable=1"`)
+	assert.Contains(t, mockCommander.ExecutedCommands, "update-grub")
+
+	require.NoError(t, repo.SetIPv6Kernel(false, false))
+	assert.Contains(t, string(mockFS.Files[secondary.GrubDefaultsPath]), `GRUB_CMDLINE_LINUX="quiet"`)
+
+	mockFS.Files[secondary.ProcCmdlinePath] = []byte("BOOT_IMAGE=/vmlinuz ro ipv6.disable=1\n")
+	delete(mockFS.Files, secondary.ProcIPv6DisablePath)
+	enabled, bootDisabled = repo.GetIPv6Kernel()
+	assert.False(t, enabled)
+	assert.True(t, bootDisabled)
+}
+
+func TestSetGrubCmdlineParameter(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults string
+		present  bool
+		expected string
+		changed  bool
+	}{
+		{
+			name:     "add",
+			defaults: "GRUB_CMDLINE_LINUX_DEFAULT=\"quiet splash\"\nGRUB_CMDLINE_LINUX=\"\"\n",
+			present:  true,
+			expected: "GRUB_CMDLINE_LINUX_DEFAULT=\"quiet splash\"\nGRUB_CMDLINE_LINUX=\"ipv6.disable=1\"\n",
+			changed:  true,
+		},
+		{
+			name:     "already present",
+			defaults: "GRUB_CMDLINE_LINUX=\"crashkernel=auto ipv6.disable=1\"\n",
+			present:  true,
+			expected: "GRUB_CMDLINE_LINUX=\"crashkernel=auto ipv6.disable=1\"\n",
+		},
+		{
+			name:     "remove",
+			defaults: "GRUB_CMDLINE_LINUX=\"crashkernel=auto ipv6.disable=1 rhgb\"\n",
+			expected: "GRUB_CMDLINE_LINUX=\"crashkernel=auto rhgb\"\n",
+			changed:  true,
+		},
+		{
+			name:     "missing line",
+			defaults: "GRUB_TIMEOUT=5\n",
+			present:  true,
+			expected: "GRUB_TIMEOUT=5\nGRUB_CMDLINE_LINUX=\"ipv6.disable=1\"\n",
+			changed:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			updated, changed := secondary.SetGrubCmdlineParameter(tc.defaults, secondary.IPv6BootParameter, tc.present)
+			assert.Equal(t, tc.changed, changed)
+			assert.Equal(t, tc.expected, updated)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// pkg/testing/updates_repository_test.go
+package testing
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"time"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOSUpdatesRepository_Debian(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSUpdatesRepository(mockFS, mockCommander, "debian")
+
+	policy := model.UpdatesPolicy{
+		Origins:    model.DefaultUpdateOrigins["debian"],
+		Blacklist:  []string{"postgresql-16"},
+		Reboot:     true,
+		RebootTime: "03:00",
+		Mail:       "ops@example.com",
+	}
+	assert.NoError(t, repo.ConfigureUpdates(policy))
+
+	// The package is installed when dpkg does not report it
+	assert.Contains(t, mockCommander.ExecutedCommands, "apt-get install -y unattended-upgrades")
+
+	config := string(mockFS.Files[model.AptUnattendedUpgradesPath])
+	assert.Contains(t, config, "Managed by hardn")
+	assert.Contains(t, config, "\"origin=Debian,codename=${distro_codename},label=Debian-Security\";")
+	assert.Contains(t, config, "Unattended-Upgrade::Package-Blacklist {\n        \"postgresql-16\";\n};")
+	assert.Contains(t, config, "Unattended-Upgrade::Automatic-Reboot \"true\";")
+	assert.Contains(t, config, "Unattended-Upgrade::Automatic-Reboot-Time \"03:00\";")
+	assert.Contains(t, config, "Unattended-Upgrade::Mail \"ops@example.com\";")
+	assert.Contains(t, config, "Unattended-Upgrade::MailReport \"on-change\";")
+
+	periodic := mockFS.Files[model.AptAutoUpgradesPath]
+	assert.Equal(t, "1", secondary.ParseAptPeriodic(periodic, "APT::Periodic::Update-Package-Lists"))
+	assert.Equal(t, "1", secondary.ParseAptPeriodic(periodic, "APT::Periodic::Unattended-Upgrade"))
+
+	mockCommander.CommandOutputs["dpkg-query -W -f="+secondary.DpkgStatusFormat+" unattended-upgrades"] = []byte("install ok installed\n")
+	status, err := repo.GetUpdatesStatus()
+	assert.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.True(t, status.Managed)
+	assert.Equal(t, []string{model.AptUnattendedUpgradesPath, model.AptAutoUpgradesPath}, status.Files)
+
+	// Disabling keeps the policy but stops the daily run
+	assert.NoError(t, repo.DisableUpdates())
+	assert.Equal(t, "0", secondary.ParseAptPeriodic(mockFS.Files[model.AptAutoUpgradesPath], "APT::Periodic::Unattended-Upgrade"))
+	assert.Contains(t, mockFS.Files, model.AptUnattendedUpgradesPath)
+
+	status, err = repo.GetUpdatesStatus()
+	assert.NoError(t, err)
+	assert.False(t, status.Enabled)
+}
+
+func TestOSUpdatesRepository_Alpine(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSUpdatesRepository(mockFS, mockCommander, "alpine")
+
+	policy := model.UpdatesPolicy{
+		Blacklist:       []string{"linux-lts", "postgresql16"},
+		Reboot:          true,
+		Mail:            "root",
+		MailOnlyOnError: true,
+	}
+	assert.NoError(t, repo.ConfigureUpdates(policy))
+
+	script := string(mockFS.Files[model.AlpineAutoUpgradeScriptPath])
+	assert.Contains(t, script, "#!/bin/sh\n# Managed by hardn")
+	assert.Contains(t, script, "HOLD=\"linux-lts postgresql16\"\n")
+	assert.Contains(t, script, "MAIL=\"root\"\n")
+	assert.Contains(t, script, "MAIL_ONLY_ON_ERROR=\"yes\"\n")
+	assert.Contains(t, script, "REBOOT=\"yes\"\n")
+	assert.Contains(t, script, "apk upgrade --no-cache --ignore $HOLD")
+	assert.Contains(t, mockCommander.ExecutedCommands, "rc-update add crond default")
+	assert.Contains(t, mockCommander.ExecutedCommands, "rc-service crond start")
+
+	mockCommander.CommandOutputs["rc-update show default"] = []byte("    crond | default\n     sshd | default\n")
+	status, err := repo.GetUpdatesStatus()
+	assert.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.True(t, status.Managed)
+
+	assert.NoError(t, repo.DisableUpdates())
+	assert.NotContains(t, mockFS.Files, model.AlpineAutoUpgradeScriptPath)
+
+	// A script hardn did not write is left alone
+	mockFS.Files[model.AlpineAutoUpgradeScriptPath] = []byte("#!/bin/sh\napk upgrade\n")
+	assert.ErrorContains(t, repo.DisableUpdates(), "not created by hardn")
+}
+
+func TestOSUpdatesRepository_AppliedUpdates(t *testing.T) {
+	var rotated bytes.Buffer
+	writer := gzip.NewWriter(&rotated)
+	_, err := writer.Write([]byte("Start-Date: 2026-09-30  06:10:00\n" +
+		"Commandline: /usr/bin/unattended-upgrade\n" +
+		"Upgrade: tzdata:all (2025b-0+deb12u1, 2025b-0+deb12u2)\n" +
+		"End-Date: 2026-09-30  06:10:04\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[model.AptHistoryLogPath+".1.gz"] = rotated.Bytes()
+	mockFS.Files[model.AptHistoryLogPath] = []byte("\n" +
+		"Start-Date: 2026-10-12  06:25:03\n" +
+		"Commandline: /usr/bin/unattended-upgrade\n" +
+		"Upgrade: libssl3:amd64 (3.0.11-1~deb12u1, 3.0.11-1~deb12u2), openssl:amd64 (3.0.11-1~deb12u1, 3.0.11-1~deb12u2)\n" +
+		"End-Date: 2026-10-12  06:25:10\n\n" +
+		"Start-Date: 2026-10-13  09:00:00\n" +
+		"Commandline: apt-get install -y fail2ban\n" +
+		"Install: fail2ban:all (1.0.2-2, automatic)\n" +
+		"End-Date: 2026-10-13  09:00:05\n")
+
+	repo := secondary.NewOSUpdatesRepository(mockFS, interfaces.NewMockCommander(), "debian")
+
+	updates, err := repo.AppliedUpdates(time.Date(2026, 9, 1, 0, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	require.Len(t, updates, 3, "installs are not upgrades")
+	assert.Equal(t, "tzdata 2025b-0+deb12u1 -> 2025b-0+deb12u2", updates[0].String())
+	assert.Equal(t, model.AppliedUpdate{
+		Time:    time.Date(2026, 10, 12, 6, 25, 3, 0, time.Local),
+		Package: "openssl",
+		From:    "3.0.11-1~deb12u1",
+		To:      "3.0.11-1~deb12u2",
+	}, updates[2])
+
+	updates, err = repo.AppliedUpdates(time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	assert.Len(t, updates, 2)
+
+	alpineFS := interfaces.NewMockFileSystem()
+	alpineFS.Files[model.AlpineAutoUpgradeLogPath] = []byte("=== 2026-10-14 03:00:01 apk upgrade ===\n" +
+		"fetch https://dl-cdn.alpinelinux.org/alpine/v3.20/main/x86_64/APKINDEX.tar.gz\n" +
+		"(1/2) Upgrading musl (1.2.5-r0 -> 1.2.5-r1)\n" +
+		"(2/2) Upgrading busybox (1.36.1-r29 -> 1.36.1-r30)\n" +
+		"OK: 9 MiB in 25 packages\n" +
+		"exit status: 0\n")
+	alpine := secondary.NewOSUpdatesRepository(alpineFS, interfaces.NewMockCommander(), "alpine")
+
+	updates, err = alpine.AppliedUpdates(time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local))
+	require.NoError(t, err)
+	require.Len(t, updates, 2)
+	assert.Equal(t, "busybox 1.36.1-r29 -> 1.36.1-r30", updates[1].String())
+	assert.Equal(t, time.Date(2026, 10, 14, 3, 0, 1, 0, time.Local), updates[1].Time)
+}
+
+func TestOSUpdatesRepository_AppliedUpdates_RHEL(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files[model.DnfRPMLogPath+".1"] = []byte("" +
+		"2026-09-28T04:00:12+0000 SUBDEBUG Upgrade: tzdata-2025b-1.el9.noarch\n" +
+		"2026-09-28T04:00:13+0000 SUBDEBUG Upgraded: tzdata-2025a-1.el9.noarch\n")
+	mockFS.Files[model.DnfRPMLogPath] = []byte("" +
+		"2026-10-12T06:25:01+0000 INFO --- logging initialized ---\n" +
+		"2026-10-12T06:25:03+0000 SUBDEBUG Upgrade: openssl-libs-1:3.0.7-27.el9.x86_64\n" +
+		"2026-10-12T06:25:03+0000 SUBDEBUG Upgrade: curl-7.76.1-29.el9_4.1.x86_64\n" +
+		"2026-10-12T06:25:04+0000 SUBDEBUG Installed: kernel-core-5.14.0-427.el9.x86_64\n" +
+		"2026-10-12T06:25:05+0000 SUBDEBUG Upgraded: openssl-libs-1:3.0.7-25.el9.x86_64\n" +
+		"2026-10-12T06:25:05+0000 SUBDEBUG Upgraded: curl-7.76.1-29.el9_4.x86_64\n" +
+		"2026-10-13T09:00:00+0000 SUBDEBUG Erase: telnet-1:0.17-85.el9.x86_64\n")
+
+	repo := secondary.NewOSUpdatesRepository(mockFS, interfaces.NewMockCommander(), "rocky")
+
+	updates, err := repo.AppliedUpdates(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, updates, 3, "installs and removals are not upgrades")
+	assert.Equal(t, "tzdata 2025a-1.el9 -> 2025b-1.el9", updates[0].String())
+	assert.Equal(t, "openssl-libs 1:3.0.7-25.el9 -> 1:3.0.7-27.el9", updates[1].String())
+	assert.True(t, updates[1].Time.Equal(time.Date(2026, 10, 12, 6, 25, 5, 0, time.UTC)), "dated when the old package is removed")
+	assert.Equal(t, "curl 7.76.1-29.el9_4 -> 7.76.1-29.el9_4.1", updates[2].String())
+
+	updates, err = repo.AppliedUpdates(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Len(t, updates, 2)
+
+	empty := secondary.NewOSUpdatesRepository(interfaces.NewMockFileSystem(), interfaces.NewMockCommander(), "fedora")
+	updates, err = empty.AppliedUpdates(time.Time{})
+	assert.NoError(t, err)
+	assert.Empty(t, updates)
+}
@@ -0,0 +1,73 @@
+// pkg/testing/user_repository_test.go
+package testing
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const userRepoTestKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAICqqcAOALJM+NkXwjS8siPyyxmk+83AINsC4n6ErnTqt deploy@ci"
+
+func TestOSUserRepository_AddSSHKey_AsUser(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSUserRepository(mockFS, mockCommander, "debian")
+
+	require.NoError(t, repo.AddSSHKey("george", userRepoTestKey))
+
+	require.Len(t, mockCommander.ExecutedCommands, 2)
+	read := mockCommander.ExecutedCommands[0]
+	assert.True(t, strings.HasPrefix(read, "su -s /bin/sh george -c "), read)
+	assert.True(t, strings.HasPrefix(mockCommander.ExecutedCommands[1], "INPUT:"+userRepoTestKey+"\n|su -s /bin/sh george -c "))
+	assert.Empty(t, mockFS.Files, "nothing is written with root's permissions")
+}
+
+// The user owns ~/.ssh, so a symlink planted there must not redirect the
+// write to a file the user does not own
+func TestOSUserRepository_AddSSHKey_PlantedSymlink(t *testing.T) {
+	if _, err := exec.LookPath("mktemp"); err != nil {
+		t.Skip("mktemp is not installed")
+	}
+
+	home := t.TempDir()
+	secret := filepath.Join(t.TempDir(), "shadow")
+	require.NoError(t, os.WriteFile(secret, []byte("root:$6$secret:19000::::::\n"), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(home, ".ssh"), 0700))
+	authorizedKeys := filepath.Join(home, ".ssh", "authorized_keys")
+	require.NoError(t, os.Symlink(secret, authorizedKeys))
+	require.NoError(t, os.Symlink(secret, authorizedKeys+".hardn.tmp"))
+
+	mockCommander := interfaces.NewMockCommander()
+	repo := secondary.NewOSUserRepository(interfaces.NewMockFileSystem(), mockCommander, "debian")
+	require.NoError(t, repo.AddSSHKey("george", userRepoTestKey))
+
+	// Run the script handed to su as the user would
+	write := mockCommander.ExecutedCommands[len(mockCommander.ExecutedCommands)-1]
+	_, script, found := strings.Cut(write, " -c ")
+	require.True(t, found, write)
+	cmd := exec.Command("sh", "-c", script)
+	cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+	cmd.Stdin = strings.NewReader(userRepoTestKey + "\n")
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	data, err := os.ReadFile(secret)
+	require.NoError(t, err)
+	assert.Equal(t, "root:$6$secret:19000::::::\n", string(data), "the symlink target is untouched")
+
+	info, err := os.Lstat(authorizedKeys)
+	require.NoError(t, err)
+	assert.True(t, info.Mode().IsRegular(), "the symlink is replaced, not followed")
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	data, err = os.ReadFile(authorizedKeys)
+	require.NoError(t, err)
+	assert.Equal(t, userRepoTestKey+"\n", string(data))
+}
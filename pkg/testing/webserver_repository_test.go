@@ -0,0 +1,139 @@
+// pkg/testing/webserver_repository_test.go
+package testing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+// Debian's stock nginx.conf sets protocols in the http block
+const debianNginxConf = `user www-data;
+
+http {
+	sendfile on;
+	ssl_protocols TLSv1 TLSv1.1 TLSv1.2 TLSv1.3; # Dropping SSLv3, ref: POODLE
+	ssl_prefer_server_ciphers on;
+
+	server {
+		listen 443 ssl;
+		ssl_protocols TLSv1.3;
+	}
+
+	include /etc/nginx/conf.d/*.conf;
+}
+`
+
+// newWebServerCommander reports only the named binaries as installed
+func newWebServerCommander(installed ...string) *interfaces.MockCommander {
+	mockCommander := interfaces.NewMockCommander()
+	for _, binary := range []string{"nginx", "apache2ctl", "apachectl", "caddy"} {
+		mockCommander.CommandErrors["which "+binary] = errors.New("exit status 1")
+	}
+	for _, binary := range installed {
+		delete(mockCommander.CommandErrors, "which "+binary)
+	}
+	return mockCommander
+}
+
+func TestOSWebServerRepository_Nginx(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/etc/nginx/nginx.conf"] = []byte(debianNginxConf)
+
+	repo := secondary.NewOSWebServerRepository(mockFS, newWebServerCommander("nginx"), "debian")
+
+	servers, err := repo.DetectWebServers()
+	assert.NoError(t, err)
+	assert.Equal(t, []model.WebServer{{Name: model.WebServerNginx, SnippetPath: "/etc/nginx/conf.d/hardn-tls.conf"}}, servers)
+
+	assert.NoError(t, repo.WriteTLSBaseline(servers[0]))
+
+	snippet := string(mockFS.Files["/etc/nginx/conf.d/hardn-tls.conf"])
+	assert.True(t, strings.HasPrefix(snippet, model.WebServerTLSMarker))
+	assert.Contains(t, snippet, "ssl_protocols TLSv1.2 TLSv1.3;")
+	assert.Contains(t, snippet, "Strict-Transport-Security")
+
+	// Only the http-level duplicates are disabled
+	conf := string(mockFS.Files["/etc/nginx/nginx.conf"])
+	assert.Contains(t, conf, "\t#hardn# ssl_protocols TLSv1 TLSv1.1 TLSv1.2 TLSv1.3;")
+	assert.Contains(t, conf, "\t#hardn# ssl_prefer_server_ciphers on;")
+	assert.Contains(t, conf, "\t\tssl_protocols TLSv1.3;\n")
+
+	servers, _ = repo.DetectWebServers()
+	assert.True(t, servers[0].BaselineApplied)
+
+	assert.NoError(t, repo.RemoveTLSBaseline(servers[0]))
+	assert.Equal(t, debianNginxConf, string(mockFS.Files["/etc/nginx/nginx.conf"]))
+	_, exists := mockFS.Files["/etc/nginx/conf.d/hardn-tls.conf"]
+	assert.False(t, exists)
+}
+
+func TestOSWebServerRepository_RefusesUnmanagedSnippet(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/etc/nginx/conf.d/hardn-tls.conf"] = []byte("# hand written\n")
+
+	repo := secondary.NewOSWebServerRepository(mockFS, newWebServerCommander("nginx"), "debian")
+	servers, _ := repo.DetectWebServers()
+
+	assert.Error(t, repo.WriteTLSBaseline(servers[0]))
+	assert.Equal(t, "# hand written\n", string(mockFS.Files["/etc/nginx/conf.d/hardn-tls.conf"]))
+}
+
+func TestOSWebServerRepository_Caddy(t *testing.T) {
+	caddyfile := "example.com {\n\treverse_proxy localhost:8080\n}\n"
+	mockFS := interfaces.NewMockFileSystem()
+	mockFS.Files["/etc/caddy/Caddyfile"] = []byte(caddyfile)
+
+	mockCommander := newWebServerCommander("caddy")
+	repo := secondary.NewOSWebServerRepository(mockFS, mockCommander, "debian")
+
+	servers, err := repo.DetectWebServers()
+	assert.NoError(t, err)
+	assert.Len(t, servers, 1)
+
+	assert.NoError(t, repo.WriteTLSBaseline(servers[0]))
+	assert.NoError(t, repo.WriteTLSBaseline(servers[0]))
+	assert.Equal(t, "import /etc/caddy/hardn-tls.caddy\n\n"+caddyfile, string(mockFS.Files["/etc/caddy/Caddyfile"]))
+	assert.Contains(t, string(mockFS.Files["/etc/caddy/hardn-tls.caddy"]), "protocols tls1.2 tls1.3")
+
+	_, err = repo.ValidateConfig(servers[0])
+	assert.NoError(t, err)
+	assert.Contains(t, mockCommander.ExecutedCommands, "caddy validate --config /etc/caddy/Caddyfile --adapter caddyfile")
+
+	assert.NoError(t, repo.RemoveTLSBaseline(servers[0]))
+	assert.Equal(t, caddyfile, string(mockFS.Files["/etc/caddy/Caddyfile"]))
+}
+
+func TestOSWebServerRepository_ApacheDebian(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := newWebServerCommander("apache2ctl")
+
+	repo := secondary.NewOSWebServerRepository(mockFS, mockCommander, "ubuntu")
+	servers, _ := repo.DetectWebServers()
+
+	assert.Equal(t, "/etc/apache2/conf-available/hardn-tls.conf", servers[0].SnippetPath)
+	assert.NoError(t, repo.WriteTLSBaseline(servers[0]))
+	assert.Contains(t, mockCommander.ExecutedCommands, "a2enconf hardn-tls")
+	assert.Contains(t, string(mockFS.Files[servers[0].SnippetPath]), "SSLProtocol -all +TLSv1.2 +TLSv1.3")
+}
+
+func TestOSWebServerRepository_ApacheRHEL(t *testing.T) {
+	mockFS := interfaces.NewMockFileSystem()
+	mockCommander := newWebServerCommander("apachectl")
+
+	repo := secondary.NewOSWebServerRepository(mockFS, mockCommander, "rocky")
+	servers, _ := repo.DetectWebServers()
+
+	assert.Equal(t, "/etc/httpd/conf.d/hardn-tls.conf", servers[0].SnippetPath)
+	assert.NoError(t, repo.WriteTLSBaseline(servers[0]))
+	assert.NotContains(t, mockCommander.ExecutedCommands, "a2enconf hardn-tls")
+
+	snippet := string(mockFS.Files[servers[0].SnippetPath])
+	assert.Contains(t, snippet, "/run/httpd/")
+	assert.NotContains(t, snippet, "/run/apache2/")
+}
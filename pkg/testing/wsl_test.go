@@ -0,0 +1,133 @@
+// pkg/testing/wsl_test.go
+package testing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/abbott/hardn/pkg/adapter/secondary"
+	"github.com/abbott/hardn/pkg/domain/model"
+	"github.com/abbott/hardn/pkg/interfaces"
+	"github.com/abbott/hardn/pkg/osdetect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWSLVersion(t *testing.T) {
+	assert.Equal(t, 1, osdetect.ParseWSLVersion("4.4.0-19041-Microsoft\n"))
+	assert.Equal(t, 2, osdetect.ParseWSLVersion("5.15.153.1-microsoft-standard-WSL2\n"))
+	assert.Equal(t, 2, osdetect.ParseWSLVersion("4.19.128-microsoft-standard"))
+	assert.Equal(t, 0, osdetect.ParseWSLVersion("6.1.0-18-amd64"))
+	assert.Equal(t, 0, osdetect.ParseWSLVersion(""))
+}
+
+func TestParseWSLConf(t *testing.T) {
+	settings := osdetect.ParseWSLConf(`# /etc/wsl.conf
+[boot]
+systemd=true
+command = "service ssh start"
+
+[Network]
+generateResolvConf = false
+; comment
+`)
+	assert.Equal(t, "true", settings["boot.systemd"])
+	assert.Equal(t, "service ssh start", settings["boot.command"])
+	assert.Equal(t, "false", settings["network.generateresolvconf"])
+}
+
+func TestOSInfo_WSLSkipped(t *testing.T) {
+	assert.Nil(t, (&osdetect.OSInfo{OsType: "debian"}).WSLSkipped())
+
+	withSystemd := &osdetect.OSInfo{OsType: "ubuntu", IsWSL: true, WSLVersion: 2, Systemd: true}
+	assert.Equal(t, osdetect.WSLSkippedModules, withSystemd.WSLSkipped())
+
+	bare := &osdetect.OSInfo{OsType: "ubuntu", IsWSL: true, WSLVersion: 2, WSLResolvConf: true}
+	skipped := bare.WSLSkipped()
+	assert.Contains(t, skipped, "firewall")
+	assert.Contains(t, skipped, "DNS")
+	assert.Contains(t, skipped, "automatic updates")
+	assert.Contains(t, skipped, "schedule")
+	assert.Len(t, osdetect.WSLSkippedModules, 5, "WSLSkipped must not append to the shared list")
+}
+
+func TestWSLGuidance(t *testing.T) {
+	assert.Empty(t, osdetect.WSLGuidance(&osdetect.OSInfo{OsType: "debian"}))
+
+	guidance := strings.Join(osdetect.WSLGuidance(&osdetect.OSInfo{IsWSL: true, WSLVersion: 2, WSLResolvConf: true}), "\n")
+	assert.Contains(t, guidance, "Windows Defender Firewall")
+	assert.Contains(t, guidance, "portproxy")
+	assert.Contains(t, guidance, "generateResolvConf")
+	assert.Contains(t, guidance, "systemd = true")
+
+	guidance = strings.Join(osdetect.WSLGuidance(&osdetect.OSInfo{IsWSL: true, WSLVersion: 1, Systemd: true}), "\n")
+	assert.Contains(t, guidance, "Windows network stack")
+	assert.NotContains(t, guidance, "generateResolvConf")
+	assert.NotContains(t, guidance, "systemd = true")
+}
+
+func TestHardeningConfig_RestrictForWSL(t *testing.T) {
+	newConfig := func() model.HardeningConfig {
+		return model.HardeningConfig{
+			CreateUser:               true,
+			SshPort:                  2222,
+			EnableFirewall:           true,
+			EnableAppArmor:           true,
+			ConfigureDns:             true,
+			EnableUnattendedUpgrades: true,
+			NeedrestartMode:          "a",
+			ModuleBlacklist:          &model.ModuleBlacklistPolicy{},
+		}
+	}
+
+	cfg := newConfig()
+	skipped := cfg.RestrictForWSL(false, true)
+	assert.Equal(t, []string{"firewall", "AppArmor", "kernel module blacklist", "needrestart", "DNS", "automatic updates"}, skipped)
+	assert.False(t, cfg.EnableFirewall)
+	assert.False(t, cfg.EnableAppArmor)
+	assert.False(t, cfg.ConfigureDns)
+	assert.False(t, cfg.EnableUnattendedUpgrades)
+	assert.Nil(t, cfg.ModuleBlacklist)
+	assert.True(t, cfg.CreateUser, "user and SSH steps still run")
+	assert.Equal(t, 2222, cfg.SshPort)
+
+	// systemd runs the update timer, and DNS applies once WSL leaves resolv.conf alone
+	cfg = newConfig()
+	skipped = cfg.RestrictForWSL(true, false)
+	assert.NotContains(t, skipped, "DNS")
+	assert.NotContains(t, skipped, "automatic updates")
+	assert.True(t, cfg.ConfigureDns)
+	assert.True(t, cfg.EnableUnattendedUpgrades)
+}
+
+func TestSysVServiceManager(t *testing.T) {
+	model.UseInitSystem(model.InitSysV)
+	defer model.UseInitSystem("")
+
+	commander := interfaces.NewMockCommander()
+	services := secondary.NewServiceManager(commander, "ubuntu")
+	assert.Equal(t, model.InitSysV, services.InitSystem())
+	assert.Equal(t, model.InitOpenRC, secondary.NewServiceManager(commander, "alpine").InitSystem())
+
+	commander.CommandErrors["service cron status"] = errors.New("exit status 3")
+
+	require.NoError(t, services.Enable("ssh"))
+	require.NoError(t, services.Restart("ssh"))
+	require.NoError(t, services.Disable("cron"))
+
+	status, err := services.Status("ssh")
+	require.NoError(t, err)
+	assert.True(t, status.Active)
+	assert.False(t, status.Enabled)
+
+	assert.Equal(t, []string{
+		"service ssh start",
+		"service ssh restart",
+		"service cron status",
+		"service ssh status",
+	}, commander.ExecutedCommands)
+
+	commander.CommandErrors["service ssh restart"] = errors.New("exit status 1")
+	assert.ErrorContains(t, services.Restart("ssh"), "service ssh restart failed")
+}
@@ -3,10 +3,11 @@ package updates
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 
+	"github.com/abbott/hardn/pkg/adapter/secondary"
 	"github.com/abbott/hardn/pkg/config"
+	"github.com/abbott/hardn/pkg/interfaces"
 	"github.com/abbott/hardn/pkg/logging"
 	"github.com/abbott/hardn/pkg/osdetect"
 )
@@ -47,20 +48,14 @@ apk update && apk upgrade --available
 		}
 
 		// Make sure crond is running
-		rcUpdateCmd := exec.Command("rc-update", "add", "crond", "default")
-		if err := rcUpdateCmd.Run(); err != nil {
-			logging.LogError("Failed to add crond to Alpine boot services: %v", err)
-		}
-
-		rcServiceCmd := exec.Command("rc-service", "crond", "start")
-		if err := rcServiceCmd.Run(); err != nil {
-			logging.LogError("Failed to start crond service on Alpine: %v", err)
+		if err := secondary.NewServiceManager(interfaces.OSCommander{}, osInfo.OsType).Enable("crond"); err != nil {
+			logging.LogError("Failed to enable crond on Alpine: %v", err)
 		}
 
 		logging.LogSuccess("Alpine periodic updates configured")
 	} else {
 		// Install unattended-upgrades on Debian/Ubuntu
-		installCmd := exec.Command("apt-get", "install", "-y", "unattended-upgrades")
+		installCmd := interfaces.Command("apt-get", "install", "-y", "unattended-upgrades")
 		if err := installCmd.Run(); err != nil {
 			return fmt.Errorf("failed to install unattended-upgrades package: %w", err)
 		}
@@ -70,7 +65,7 @@ apk update && apk upgrade --available
 		os.Setenv("DEBIAN_FRONTEND", "noninteractive")
 
 		// Use debconf-set-selections to configure unattended-upgrades
-		debconfCmd := exec.Command("debconf-set-selections")
+		debconfCmd := interfaces.Command("debconf-set-selections")
 		debconfCmd.Stdin = strings.NewReader(`unattended-upgrades unattended-upgrades/enable_auto_updates boolean true
 unattended-upgrades unattended-upgrades/origins_pattern string origin=Debian,codename=${distro_codename},label=Debian-Security
 `)
@@ -79,14 +74,13 @@ unattended-upgrades unattended-upgrades/origins_pattern string origin=Debian,cod
 		}
 
 		// Run dpkg-reconfigure
-		reconfigureCmd := exec.Command("dpkg-reconfigure", "-f", "noninteractive", "unattended-upgrades")
+		reconfigureCmd := interfaces.Command("dpkg-reconfigure", "-f", "noninteractive", "unattended-upgrades")
 		if err := reconfigureCmd.Run(); err != nil {
 			return fmt.Errorf("failed to reconfigure unattended-upgrades: %w", err)
 		}
 
 		// Enable the unattended-upgrades service
-		enableCmd := exec.Command("systemctl", "enable", "unattended-upgrades")
-		if err := enableCmd.Run(); err != nil {
+		if err := secondary.NewServiceManager(interfaces.OSCommander{}, osInfo.OsType).Enable("unattended-upgrades"); err != nil {
 			logging.LogError("Failed to enable unattended-upgrades service: %v", err)
 		}
 
@@ -102,23 +96,23 @@ func UpdateSystem(osInfo *osdetect.OSInfo) error {
 
 	if osInfo.OsType == "alpine" {
 		// Alpine update
-		updateCmd := exec.Command("apk", "update")
+		updateCmd := interfaces.Command("apk", "update")
 		if err := updateCmd.Run(); err != nil {
 			return fmt.Errorf("failed to update Alpine package list: %w", err)
 		}
 
-		upgradeCmd := exec.Command("apk", "upgrade")
+		upgradeCmd := interfaces.Command("apk", "upgrade")
 		if err := upgradeCmd.Run(); err != nil {
 			return fmt.Errorf("failed to upgrade Alpine packages: %w", err)
 		}
 	} else {
 		// Debian/Ubuntu update
-		updateCmd := exec.Command("apt-get", "update")
+		updateCmd := interfaces.Command("apt-get", "update")
 		if err := updateCmd.Run(); err != nil {
 			return fmt.Errorf("failed to update Debian/Ubuntu package list: %w", err)
 		}
 
-		upgradeCmd := exec.Command("apt-get", "upgrade", "-y")
+		upgradeCmd := interfaces.Command("apt-get", "upgrade", "-y")
 		if err := upgradeCmd.Run(); err != nil {
 			return fmt.Errorf("failed to upgrade Debian/Ubuntu packages: %w", err)
 		}
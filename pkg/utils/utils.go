@@ -3,7 +3,6 @@ package utils
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"time"
 
@@ -28,6 +27,11 @@ func ClearScreen() {
 
 // PrintHeader prints a standard header
 func PrintHeader() {
+	// Keep JSON and YAML output from commands in silent mode parseable
+	if logging.IsSilent() {
+		return
+	}
+
 	// Clear screen
 	ClearScreen()
 
@@ -120,7 +124,7 @@ func BackupFile(filePath string, cfg *config.Config) error {
 
 // run a command and returns its output
 func RunCommand(name string, args ...string) (string, error) {
-	cmd := exec.Command(name, args...)
+	cmd := interfaces.Command(name, args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return string(output), fmt.Errorf("command %s %v failed: %w", name, args, err)
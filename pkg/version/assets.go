@@ -0,0 +1,282 @@
+// pkg/version/assets.go
+package version
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	// InstallScriptAsset is the name of the installer attached to a release
+	InstallScriptAsset = "install.sh"
+
+	// installScriptRawURL is used when a release carries no installer asset;
+	// the tag pins the script to the release being installed
+	installScriptRawURL = "https://raw.githubusercontent.com/abbott/hardn/%s/install.sh"
+
+	// fallbackInstallScriptURL is used when no release metadata is available
+	fallbackInstallScriptURL = "https://raw.githubusercontent.com/abbott/hardn/main/install.sh"
+)
+
+// checksumAssetNames are release-wide checksum files, one "<sha256>  <name>" per line
+var checksumAssetNames = []string{"checksums.txt", "SHA256SUMS", "sha256sums.txt"}
+
+// checksumAssetSuffix ends the versioned checksum file goreleaser writes,
+// e.g. hardn-0.4.0-checksums.txt
+const checksumAssetSuffix = "-checksums.txt"
+
+// binaryArchAliases are other names a release may give a GOARCH build,
+// such as the uname -m name or the ARM revision it targets
+var binaryArchAliases = map[string][]string{
+	"amd64":   {"x86_64"},
+	"arm64":   {"aarch64"},
+	"arm":     {"armv7", "armhf"},
+	"riscv64": {"riscv"},
+}
+
+// ReleaseAsset is a file attached to a GitHub release
+type ReleaseAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+	Size               int64  `json:"size"`
+}
+
+// ReleaseAssets holds the download URLs for one OS and architecture
+type ReleaseAssets struct {
+	// BinaryName is the asset name for the platform, e.g. hardn-linux-amd64
+	BinaryName string
+	// BinaryURL is empty when the release has no build for the platform
+	BinaryURL string
+	// ChecksumURL points to <binary>.sha256 or a release-wide checksum file
+	ChecksumURL string
+	// SignatureURL and CertificateURL are the cosign signature and certificate
+	SignatureURL   string
+	CertificateURL string
+	// MinisignURL is the minisign signature of the binary
+	MinisignURL string
+	// ChecksumSignatureURL, ChecksumCertificateURL and ChecksumMinisignURL
+	// sign the checksum file, and with it every binary it lists
+	ChecksumSignatureURL   string
+	ChecksumCertificateURL string
+	ChecksumMinisignURL    string
+	// InstallScriptURL is the installer for this release
+	InstallScriptURL string
+}
+
+// BinaryAssetName returns the release asset name for goos and goarch,
+// following the naming used by install.sh; 32-bit ARM is hardn-linux-arm
+func BinaryAssetName(goos, goarch string) string {
+	return fmt.Sprintf("hardn-%s-%s", goos, goarch)
+}
+
+// ReleaseAssetsFor picks the binary, checksum, signature and installer
+// URLs for goos/goarch from a release's assets
+func ReleaseAssetsFor(release GitHubRelease, goos, goarch string) ReleaseAssets {
+	assets := ReleaseAssets{
+		BinaryName:       BinaryAssetName(goos, goarch),
+		InstallScriptURL: fallbackInstallScriptURL,
+	}
+	if release.TagName != "" {
+		assets.InstallScriptURL = fmt.Sprintf(installScriptRawURL, release.TagName)
+	}
+
+	byName := make(map[string]string, len(release.Assets))
+	for _, asset := range release.Assets {
+		byName[asset.Name] = asset.BrowserDownloadURL
+	}
+
+	assets.BinaryURL = byName[assets.BinaryName]
+	for _, alias := range binaryArchAliases[goarch] {
+		if assets.BinaryURL != "" {
+			break
+		}
+		name := BinaryAssetName(goos, alias)
+		if url := byName[name]; url != "" {
+			assets.BinaryName = name
+			assets.BinaryURL = url
+		}
+	}
+	assets.SignatureURL = byName[assets.BinaryName+".sig"]
+	assets.CertificateURL = byName[assets.BinaryName+".crt"]
+	assets.MinisignURL = byName[assets.BinaryName+".minisig"]
+	if url := byName[InstallScriptAsset]; url != "" {
+		assets.InstallScriptURL = url
+	}
+
+	checksumName := assets.BinaryName + ".sha256"
+	if byName[checksumName] == "" {
+		checksumName = releaseChecksumName(release)
+	}
+	if checksumName != "" {
+		assets.ChecksumURL = byName[checksumName]
+		assets.ChecksumSignatureURL = byName[checksumName+".sig"]
+		assets.ChecksumCertificateURL = byName[checksumName+".crt"]
+		assets.ChecksumMinisignURL = byName[checksumName+".minisig"]
+	}
+
+	return assets
+}
+
+// releaseChecksumName returns the name of the release-wide checksum file,
+// or "" when the release has none
+func releaseChecksumName(release GitHubRelease) string {
+	names := make(map[string]bool, len(release.Assets))
+	for _, asset := range release.Assets {
+		names[asset.Name] = true
+	}
+	for _, name := range checksumAssetNames {
+		if names[name] {
+			return name
+		}
+	}
+	for _, asset := range release.Assets {
+		if strings.HasSuffix(asset.Name, checksumAssetSuffix) {
+			return asset.Name
+		}
+	}
+	return ""
+}
+
+// InstallCommand returns the shell command that runs an installer script
+func InstallCommand(scriptURL string) string {
+	return fmt.Sprintf("curl -sSL %s | sudo sh", scriptURL)
+}
+
+// currentPlatformAssets returns the assets for the running OS and architecture
+func currentPlatformAssets(release GitHubRelease) ReleaseAssets {
+	return ReleaseAssetsFor(release, runtime.GOOS, runtime.GOARCH)
+}
+
+// ParseChecksum returns the SHA-256 for name from checksum file content: a
+// bare digest, or sha256sum output with one "<digest>  <name>" per line
+func ParseChecksum(content string, name string) (string, error) {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	var single string
+	lines := 0
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		lines++
+		digest := strings.ToLower(fields[0])
+		if len(fields) == 1 {
+			single = digest
+			continue
+		}
+		if strings.TrimPrefix(fields[1], "*") == name {
+			return digest, validDigest(digest)
+		}
+	}
+
+	// A <binary>.sha256 file may hold just the digest
+	if lines == 1 && single != "" {
+		return single, validDigest(single)
+	}
+	return "", fmt.Errorf("no checksum for %s", name)
+}
+
+// validDigest checks that digest is a hex SHA-256
+func validDigest(digest string) error {
+	if decoded, err := hex.DecodeString(digest); err != nil || len(decoded) != sha256.Size {
+		return fmt.Errorf("invalid SHA-256 checksum: %s", digest)
+	}
+	return nil
+}
+
+// DownloadBinary downloads the release binary for the running platform to
+// destPath and verifies it against the release checksum when one is
+// published. The file is only moved into place once it verifies.
+func DownloadBinary(ctx context.Context, assets ReleaseAssets, destPath string) (string, error) {
+	if assets.BinaryURL == "" {
+		return "", fmt.Errorf("the release has no %s binary", assets.BinaryName)
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+
+	expected := ""
+	if assets.ChecksumURL != "" {
+		data, err := fetch(ctx, client, assets.ChecksumURL, 1<<20)
+		if err != nil {
+			return "", fmt.Errorf("failed to download checksum: %w", err)
+		}
+		expected, err = ParseChecksum(string(data), assets.BinaryName)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(destPath), ".hardn-download-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", assets.BinaryURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "hardn-version-checker")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", assets.BinaryName, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download %s: %s", assets.BinaryName, resp.Status)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), resp.Body); err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", assets.BinaryName, err)
+	}
+	actual := hex.EncodeToString(hash.Sum(nil))
+
+	if expected != "" && actual != expected {
+		return "", fmt.Errorf("checksum mismatch for %s: expected %s, got %s", assets.BinaryName, expected, actual)
+	}
+
+	if err := tmp.Chmod(0755); err != nil {
+		return "", fmt.Errorf("failed to make %s executable: %w", destPath, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", destPath, err)
+	}
+	if err := os.Rename(tmp.Name(), destPath); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", destPath, err)
+	}
+
+	return actual, nil
+}
+
+// fetch downloads a small file, reading at most limit bytes
+func fetch(ctx context.Context, client *http.Client, url string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "hardn-version-checker")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, limit))
+}
@@ -2,7 +2,9 @@
 package version
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -20,8 +22,12 @@ const (
 	// CacheFileName is where we store the last check results
 	CacheFileName = ".hardn-version-cache.json"
 
-	// CacheTTL defines how long the cache is valid (24 hours)
+	// CacheTTL defines how long the cache is valid unless UpdateOptions sets
+	// another TTL (24 hours)
 	CacheTTL = 24 * time.Hour
+
+	// cacheFormat is bumped when cached fields change; older caches are refetched
+	cacheFormat = 3
 )
 
 // GitHubRelease represents the JSON structure of a GitHub release
@@ -31,12 +37,20 @@ type GitHubRelease struct {
 	Body        string    `json:"body"` // Add body field to check for security notices
 	PublishedAt time.Time `json:"published_at"`
 	HTMLURL     string    `json:"html_url"`
+	Prerelease  bool      `json:"prerelease"`
+	Draft       bool      `json:"draft"`
+
+	// Files attached to the release (binaries, checksums, signatures, installer)
+	Assets []ReleaseAsset `json:"assets"`
 }
 
 // VersionCache stores the cached check results
 type VersionCache struct {
+	Format        int           `json:"format"`
 	LastCheck     time.Time     `json:"last_check"`
 	LatestRelease GitHubRelease `json:"latest_release"`
+	// Verification of the release binary, when it was offered as an update
+	Verification ReleaseVerification `json:"verification"`
 }
 
 // CheckResult contains the result of a version check
@@ -49,10 +63,49 @@ type CheckResult struct {
 	Error                   error
 	SecurityUpdateAvailable bool   // New field for security updates
 	SecurityUpdateDetails   string // Details about the security update
+
+	// Download URLs from the release for the running OS and architecture
+	Assets ReleaseAssets
+	// Verification of the published checksum and its signature; only an
+	// available update is verified
+	Verification ReleaseVerification
+
+	// When GitHub was asked for the latest release
+	CheckedAt time.Time
+	// Stale is set when an expired cache was used because hardn is offline
+	// or GitHub could not be reached
+	Stale bool
 }
 
+// ErrOffline is the error of an update check skipped in offline mode
+// without a cached result
+var ErrOffline = errors.New("update check skipped in offline mode")
+
 // CheckForUpdates checks if a newer version is available on GitHub
 func CheckForUpdates(currentVersion string, debug bool) CheckResult {
+	return CheckForUpdatesContext(context.Background(), currentVersion, debug)
+}
+
+// CheckForUpdatesContext checks for a newer version, abandoning the request
+// to GitHub when ctx is cancelled
+func CheckForUpdatesContext(ctx context.Context, currentVersion string, debug bool) CheckResult {
+	return checkForUpdates(ctx, currentVersion, UpdateOptions{Debug: debug})
+}
+
+// checkForUpdates reuses a cached result younger than options.CacheTTL.
+// Offline, or when GitHub cannot be reached, an older cached result is
+// returned as stale; offline without a cache the result holds ErrOffline.
+func checkForUpdates(ctx context.Context, currentVersion string, options UpdateOptions) CheckResult {
+	debug := options.Debug
+	ttl := options.CacheTTL
+	if ttl <= 0 {
+		ttl = CacheTTL
+	}
+	apiURL := options.APIURL
+	if apiURL == "" {
+		apiURL = GitHubAPIURL
+	}
+
 	result := CheckResult{
 		CurrentVersion: currentVersion,
 	}
@@ -80,13 +133,24 @@ func CheckForUpdates(currentVersion string, debug bool) CheckResult {
 	}
 
 	// Try to load from cache first
-	cache, cacheValid := loadCache()
-	if cacheValid {
+	cache, cached := loadCache()
+	if cached && time.Since(cache.LastCheck) <= ttl {
 		if debug {
 			fmt.Println("DEBUG: Using cached version information")
 			fmt.Println("DEBUG: Cached latest version:", cache.LatestRelease.TagName)
 		}
-		return compareVersions(currentVersion, cache.LatestRelease)
+		return cachedResult(currentVersion, cache, false)
+	}
+
+	if options.Offline {
+		if debug {
+			fmt.Println("DEBUG: Offline mode. Skipping request to GitHub API.")
+		}
+		if cached {
+			return cachedResult(currentVersion, cache, true)
+		}
+		result.Error = ErrOffline
+		return result
 	}
 
 	if debug {
@@ -98,7 +162,7 @@ func CheckForUpdates(currentVersion string, debug bool) CheckResult {
 		Timeout: 3 * time.Second,
 	}
 
-	req, err := http.NewRequest("GET", GitHubAPIURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
 		if debug {
 			fmt.Printf("DEBUG: Failed to create request: %v\n", err)
@@ -119,6 +183,10 @@ func CheckForUpdates(currentVersion string, debug bool) CheckResult {
 		if debug {
 			fmt.Printf("DEBUG: Failed to check for updates: %v\n", err)
 		}
+		// An unreachable GitHub is expected on air-gapped hosts
+		if cached && ctx.Err() == nil {
+			return cachedResult(currentVersion, cache, true)
+		}
 		result.Error = fmt.Errorf("failed to check for updates: %w", err)
 		return result
 	}
@@ -128,6 +196,9 @@ func CheckForUpdates(currentVersion string, debug bool) CheckResult {
 		if debug {
 			fmt.Printf("DEBUG: GitHub API returned non-OK status: %s\n", resp.Status)
 		}
+		if cached {
+			return cachedResult(currentVersion, cache, true)
+		}
 		result.Error = fmt.Errorf("GitHub API returned non-OK status: %s", resp.Status)
 		return result
 	}
@@ -152,11 +223,23 @@ func CheckForUpdates(currentVersion string, debug bool) CheckResult {
 
 	if debug {
 		fmt.Println("DEBUG: Received latest version:", release.TagName)
+	}
+
+	// A release is only recommended once its checksum and signature are checked
+	result = compareVersions(currentVersion, release)
+	if result.UpdateAvailable {
+		result.Verification = VerifyRelease(ctx, result.Assets)
+		if debug {
+			fmt.Printf("DEBUG: Release verification: %s %s\n", result.Verification.Status, result.Verification.Detail)
+		}
+	}
+
+	if debug {
 		fmt.Println("DEBUG: Saving to cache...")
 	}
 
 	// Save to cache
-	saveCache(release)
+	result.CheckedAt = saveCache(release, result.Verification)
 
 	// Verify cache was written
 	if debug {
@@ -168,7 +251,18 @@ func CheckForUpdates(currentVersion string, debug bool) CheckResult {
 		}
 	}
 
-	return compareVersions(currentVersion, release)
+	return result
+}
+
+// cachedResult compares against the release cached at the last check
+func cachedResult(currentVersion string, cache VersionCache, stale bool) CheckResult {
+	result := compareVersions(currentVersion, cache.LatestRelease)
+	if result.UpdateAvailable {
+		result.Verification = cache.Verification
+	}
+	result.CheckedAt = cache.LastCheck
+	result.Stale = stale
+	return result
 }
 
 // isSecurityUpdate checks if the release contains security-related updates
@@ -219,7 +313,9 @@ func compareVersions(currentVersion string, release GitHubRelease) CheckResult {
 		CurrentVersion: currentVersion,
 		LatestVersion:  strings.TrimPrefix(release.TagName, "v"),
 		ReleaseURL:     release.HTMLURL,
+		Assets:         currentPlatformAssets(release),
 	}
+	result.InstallURL = InstallCommand(result.Assets.InstallScriptURL)
 
 	// Clean version strings (remove 'v' prefix if present)
 	current := strings.TrimPrefix(currentVersion, "v")
@@ -288,7 +384,7 @@ func compareVersions(currentVersion string, release GitHubRelease) CheckResult {
 	return result
 }
 
-// loadCache tries to load the cached version check results
+// loadCache tries to load the cached version check results, however old
 func loadCache() (VersionCache, bool) {
 	var cache VersionCache
 
@@ -306,26 +402,29 @@ func loadCache() (VersionCache, bool) {
 		return cache, false
 	}
 
-	// Check if cache is still valid
-	if time.Since(cache.LastCheck) > CacheTTL {
+	// Older caches lack the install URLs or the release verification
+	if cache.Format < cacheFormat {
 		return cache, false
 	}
 
 	return cache, true
 }
 
-// saveCache saves the version check results to cache
-func saveCache(release GitHubRelease) {
+// saveCache saves the version check results to cache and returns the time
+// of the check
+func saveCache(release GitHubRelease, verification ReleaseVerification) time.Time {
 	cache := VersionCache{
+		Format:        cacheFormat,
 		LastCheck:     time.Now(),
 		LatestRelease: release,
+		Verification:  verification,
 	}
 
 	// Convert to JSON
 	data, err := json.MarshalIndent(cache, "", "  ")
 	if err != nil {
 		fmt.Printf("Warning: Failed to marshal version cache: %v\n", err)
-		return
+		return cache.LastCheck
 	}
 
 	// Get cache file path
@@ -335,15 +434,15 @@ func saveCache(release GitHubRelease) {
 	dir := filepath.Dir(cacheFile)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		fmt.Printf("Warning: Failed to create directory for version cache: %v\n", err)
-		return
+		return cache.LastCheck
 	}
 
 	// Write to cache file
 	if err := os.WriteFile(cacheFile, data, 0644); err != nil {
 		// Log the error but don't fail the operation since this is just cache
 		fmt.Printf("Warning: Failed to write version cache: %v\n", err)
-		return
 	}
+	return cache.LastCheck
 }
 
 // getCacheFilePath returns the path to the cache file
@@ -0,0 +1,293 @@
+// pkg/version/selfupdate.go
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/interfaces"
+)
+
+// Release channels for self-update
+const (
+	ChannelStable = "stable"
+	ChannelBeta   = "beta"
+)
+
+const (
+	// GitHubReleasesURL lists every release, newest first
+	GitHubReleasesURL = "https://api.github.com/repos/abbott/hardn/releases"
+
+	// PreviousBinarySuffix names the copy of the replaced binary kept for rollback
+	PreviousBinarySuffix = ".old"
+
+	// SignatureIdentity matches the certificate identity release binaries
+	// are signed with: the release workflow of this repository, run for a
+	// version tag. A signature made by any other workflow is refused.
+	SignatureIdentity = `^https://github\.com/abbott/hardn/\.github/workflows/release\.ya?ml@refs/tags/v.*$`
+
+	// signatureIssuer is the OIDC issuer of the workflow's certificates
+	signatureIssuer = "https://token.actions.githubusercontent.com"
+)
+
+// ErrNoPreviousBinary is returned by Rollback when no replaced binary is kept
+var ErrNoPreviousBinary = errors.New("no previous binary to roll back to")
+
+// SelfUpdateOptions controls how SelfUpdate replaces the binary
+type SelfUpdateOptions struct {
+	// Channel is stable (the latest release) or beta (pre-releases too)
+	Channel string
+	// BinaryPath is replaced; the running executable when empty
+	BinaryPath string
+	// Force installs the release even when it is not newer
+	Force bool
+	// RequireSignature fails the update when the signature cannot be checked
+	RequireSignature bool
+	// DryRun reports the release that would be installed without downloading it
+	DryRun bool
+	// APIURL overrides GitHubReleasesURL
+	APIURL string
+}
+
+// SelfUpdateResult describes what SelfUpdate did
+type SelfUpdateResult struct {
+	CurrentVersion string
+	LatestVersion  string
+	BinaryPath     string
+	// PreviousPath holds the replaced binary
+	PreviousPath string
+	// UpdateAvailable is set when the release is to be installed
+	UpdateAvailable bool
+	Updated         bool
+	// Checksum is the SHA-256 of the installed binary
+	Checksum string
+	// SignatureVerified is false when the release has no signature or
+	// neither cosign nor minisign is installed
+	SignatureVerified bool
+	// Verification is what was checked before the download
+	Verification ReleaseVerification
+	Assets       ReleaseAssets
+}
+
+// SelfUpdate downloads the newest release on the chosen channel for this
+// platform, verifies its checksum and, when cosign or minisign is
+// installed, the signature of the checksum file or the binary, and renames
+// it over the binary. The replaced binary is kept
+// next to it with PreviousBinarySuffix. If the new binary fails to run,
+// the previous one is put back.
+func (s *Service) SelfUpdate(ctx context.Context, options SelfUpdateOptions) (*SelfUpdateResult, error) {
+	binary, err := resolveBinaryPath(options.BinaryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := FetchRelease(ctx, options.APIURL, options.Channel)
+	if err != nil {
+		return nil, err
+	}
+
+	check := compareVersions(s.CurrentVersion, release)
+	result := &SelfUpdateResult{
+		CurrentVersion: s.CurrentVersion,
+		LatestVersion:  check.LatestVersion,
+		BinaryPath:     binary,
+		PreviousPath:   binary + PreviousBinarySuffix,
+		Assets:         check.Assets,
+	}
+	// Development builds have no version, so they always take the release
+	result.UpdateAvailable = s.CurrentVersion == "" || check.UpdateAvailable || options.Force
+	if !result.UpdateAvailable {
+		return result, nil
+	}
+
+	assets := check.Assets
+	if assets.BinaryURL == "" {
+		return nil, fmt.Errorf("release %s has no %s binary", release.TagName, assets.BinaryName)
+	}
+	if assets.ChecksumURL == "" {
+		return nil, fmt.Errorf("release %s publishes no checksum for %s", release.TagName, assets.BinaryName)
+	}
+	if options.RequireSignature && !assets.Signed() {
+		return nil, fmt.Errorf("release %s publishes no signature for %s", release.TagName, assets.BinaryName)
+	}
+
+	// The checksum and its signature are checked before anything is downloaded
+	result.Verification = VerifyRelease(ctx, assets)
+	if result.Verification.Failed() {
+		return nil, fmt.Errorf("release %s failed verification: %s", release.TagName, result.Verification.Detail)
+	}
+	if options.DryRun {
+		return result, nil
+	}
+
+	// Download next to the binary so the final rename stays on one filesystem
+	download := binary + ".new"
+	result.Checksum, err = DownloadBinary(ctx, assets, download)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(download)
+
+	// The checksum file may have changed since it was verified
+	if result.Verification.Checksum != "" && result.Checksum != result.Verification.Checksum {
+		return nil, fmt.Errorf("checksum mismatch for %s: expected %s, got %s",
+			assets.BinaryName, result.Verification.Checksum, result.Checksum)
+	}
+
+	tool, err := verifyFile(ctx, download, assets.BinaryName, assets.binarySignatures())
+	if err != nil {
+		return nil, err
+	}
+	result.SignatureVerified = tool != "" || result.Verification.Status == VerificationSigned
+	if options.RequireSignature && !result.SignatureVerified {
+		return nil, fmt.Errorf("the signature of %s could not be verified; install cosign or minisign", assets.BinaryName)
+	}
+
+	if err := keepPrevious(binary, result.PreviousPath); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(download, binary); err != nil {
+		return nil, fmt.Errorf("failed to replace %s: %w", binary, err)
+	}
+
+	if err := runsVersion(ctx, binary); err != nil {
+		if restoreErr := os.Rename(result.PreviousPath, binary); restoreErr != nil {
+			return nil, fmt.Errorf("new binary failed to start (%v) and restoring %s failed: %w",
+				err, result.PreviousPath, restoreErr)
+		}
+		return nil, fmt.Errorf("new binary failed to start, kept the previous one: %w", err)
+	}
+
+	result.Updated = true
+	return result, nil
+}
+
+// Rollback puts the binary replaced by the last self-update back. The
+// binary being rolled back from takes its place, so a second rollback
+// undoes the first.
+func Rollback(binaryPath string) (string, error) {
+	binary, err := resolveBinaryPath(binaryPath)
+	if err != nil {
+		return "", err
+	}
+	previous := binary + PreviousBinarySuffix
+	if _, err := os.Stat(previous); err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNoPreviousBinary
+		}
+		return "", err
+	}
+
+	swap := binary + ".rollback"
+	if err := os.Rename(binary, swap); err != nil {
+		return "", fmt.Errorf("failed to move %s aside: %w", binary, err)
+	}
+	if err := os.Rename(previous, binary); err != nil {
+		_ = os.Rename(swap, binary)
+		return "", fmt.Errorf("failed to restore %s: %w", previous, err)
+	}
+	if err := os.Rename(swap, previous); err != nil {
+		return "", fmt.Errorf("failed to keep %s: %w", previous, err)
+	}
+	return previous, nil
+}
+
+// FetchRelease returns the newest release on channel: the latest release
+// for stable, and the newest release including pre-releases for beta
+func FetchRelease(ctx context.Context, apiURL string, channel string) (GitHubRelease, error) {
+	if apiURL == "" {
+		apiURL = GitHubReleasesURL
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	switch channel {
+	case "", ChannelStable:
+		data, err := fetch(ctx, client, apiURL+"/latest", 4<<20)
+		if err != nil {
+			return GitHubRelease{}, fmt.Errorf("failed to fetch the latest release: %w", err)
+		}
+		var release GitHubRelease
+		if err := json.Unmarshal(data, &release); err != nil {
+			return GitHubRelease{}, fmt.Errorf("failed to parse GitHub response: %w", err)
+		}
+		return release, nil
+
+	case ChannelBeta:
+		data, err := fetch(ctx, client, apiURL+"?per_page=20", 16<<20)
+		if err != nil {
+			return GitHubRelease{}, fmt.Errorf("failed to fetch releases: %w", err)
+		}
+		var releases []GitHubRelease
+		if err := json.Unmarshal(data, &releases); err != nil {
+			return GitHubRelease{}, fmt.Errorf("failed to parse GitHub response: %w", err)
+		}
+		for _, release := range releases {
+			if !release.Draft {
+				return release, nil
+			}
+		}
+		return GitHubRelease{}, fmt.Errorf("no published releases found")
+
+	default:
+		return GitHubRelease{}, fmt.Errorf("unknown channel %q (use %s or %s)", channel, ChannelStable, ChannelBeta)
+	}
+}
+
+// resolveBinaryPath returns path, or the running executable with symlinks
+// resolved so the real file is replaced
+func resolveBinaryPath(path string) (string, error) {
+	if path == "" {
+		executable, err := os.Executable()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate the hardn binary: %w", err)
+		}
+		path = executable
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the hardn binary: %w", err)
+	}
+	return resolved, nil
+}
+
+// keepPrevious copies binary to previous, replacing an older copy
+func keepPrevious(binary, previous string) error {
+	_ = os.Remove(previous)
+	if err := os.Link(binary, previous); err == nil {
+		return nil
+	}
+
+	// Hard links fail on some filesystems; copy instead
+	src, err := os.Open(binary)
+	if err != nil {
+		return fmt.Errorf("failed to keep the previous binary: %w", err)
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(previous, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to keep the previous binary: %w", err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return fmt.Errorf("failed to keep the previous binary: %w", err)
+	}
+	return dst.Close()
+}
+
+// runsVersion checks that binary starts by asking it for its version
+func runsVersion(ctx context.Context, binary string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if output, err := interfaces.CommandContext(ctx, binary, "--version").CombinedOutput(); err != nil {
+		return fmt.Errorf("%s --version: %v: %s", binary, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
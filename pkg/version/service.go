@@ -1,6 +1,7 @@
 package version
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
@@ -24,6 +25,12 @@ type UpdateOptions struct {
 	ForceSecurityUpdate bool
 	// Custom security details for testing
 	SecurityDetails string
+	// How long a cached result is reused; zero uses CacheTTL
+	CacheTTL time.Duration
+	// Use only the cache, however old, and never ask GitHub
+	Offline bool
+	// APIURL overrides GitHubAPIURL
+	APIURL string
 }
 
 // Service provides version checking functionality
@@ -44,6 +51,12 @@ func NewService(currentVersion, buildDate, gitCommit string) *Service {
 
 // CheckForUpdates checks if a newer version is available
 func (s *Service) CheckForUpdates(options *UpdateOptions) CheckResult {
+	return s.CheckForUpdatesContext(context.Background(), options)
+}
+
+// CheckForUpdatesContext checks if a newer version is available, stopping
+// early when ctx is cancelled
+func (s *Service) CheckForUpdatesContext(ctx context.Context, options *UpdateOptions) CheckResult {
 	// Default options if nil
 	if options == nil {
 		options = &UpdateOptions{}
@@ -51,14 +64,16 @@ func (s *Service) CheckForUpdates(options *UpdateOptions) CheckResult {
 
 	// For testing purposes, we can force an update to be available
 	if options.ForceUpdate {
+		assets := currentPlatformAssets(GitHubRelease{})
 		return CheckResult{
 			CurrentVersion:          s.CurrentVersion,
 			LatestVersion:           options.ForcedVersion,
 			UpdateAvailable:         true,
 			ReleaseURL:              "https://github.com/abbott/hardn/releases/latest",
-			InstallURL:              "curl -sSL https://raw.githubusercontent.com/abbott/hardn/main/install.sh | sudo sh",
+			InstallURL:              InstallCommand(assets.InstallScriptURL),
 			SecurityUpdateAvailable: options.ForceSecurityUpdate,
 			SecurityUpdateDetails:   options.SecurityDetails,
+			Assets:                  assets,
 		}
 	}
 
@@ -79,7 +94,7 @@ func (s *Service) CheckForUpdates(options *UpdateOptions) CheckResult {
 	}
 
 	// Perform the actual check
-	return CheckForUpdates(s.CurrentVersion, options.Debug)
+	return checkForUpdates(ctx, s.CurrentVersion, *options)
 }
 
 // PrintVersionInfo prints version information to stdout
@@ -97,7 +112,7 @@ func (s *Service) PrintVersionInfo() {
 // GetCacheStatus returns information about the update cache
 func (s *Service) GetCacheStatus() (bool, time.Time, error) {
 	cache, valid := loadCache()
-	if !valid {
+	if !valid || time.Since(cache.LastCheck) > CacheTTL {
 		return false, time.Time{}, fmt.Errorf("no valid cache found")
 	}
 	return true, cache.LastCheck, nil
@@ -0,0 +1,209 @@
+// pkg/version/verify.go
+package version
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/abbott/hardn/pkg/interfaces"
+)
+
+// Verification states of a release, strongest first
+const (
+	// VerificationSigned: a signature over the checksum file was verified
+	VerificationSigned = "signed"
+	// VerificationChecksum: a checksum is published but no signature could be checked
+	VerificationChecksum = "checksum"
+	// VerificationUnverified: no checksum is published, or it could not be downloaded
+	VerificationUnverified = "unverified"
+	// VerificationFailed: the checksum file does not list the binary or its signature is bad
+	VerificationFailed = "failed"
+)
+
+// MinisignPublicKey is the key minisign signatures are checked with. It is
+// set at build time with
+// -ldflags "-X github.com/abbott/hardn/pkg/version.MinisignPublicKey=RW...";
+// without it minisign signatures are not checked.
+var MinisignPublicKey string
+
+// ReleaseVerification is what could be verified about a release binary
+// before it is downloaded
+type ReleaseVerification struct {
+	Status string `json:"status"`
+	// Checksum is the published SHA-256 of the binary
+	Checksum string `json:"checksum,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// Failed reports whether the release must not be recommended or installed
+func (v ReleaseVerification) Failed() bool {
+	return v.Status == VerificationFailed
+}
+
+// Label is the status as shown next to the release, empty when the release
+// was not verified at all
+func (v ReleaseVerification) Label() string {
+	switch v.Status {
+	case VerificationSigned:
+		return "signed"
+	case VerificationChecksum:
+		return "checksum only"
+	case VerificationUnverified:
+		return "unverified"
+	case VerificationFailed:
+		return "failed verification"
+	}
+	return ""
+}
+
+// signatures are the detached signatures published for one file
+type signatures struct {
+	cosignSignature   string
+	cosignCertificate string
+	minisign          string
+}
+
+// published reports whether any signature is published
+func (s signatures) published() bool {
+	return (s.cosignSignature != "" && s.cosignCertificate != "") || s.minisign != ""
+}
+
+// binarySignatures are the signatures of the binary itself
+func (a ReleaseAssets) binarySignatures() signatures {
+	return signatures{a.SignatureURL, a.CertificateURL, a.MinisignURL}
+}
+
+// checksumSignatures are the signatures of the checksum file
+func (a ReleaseAssets) checksumSignatures() signatures {
+	return signatures{a.ChecksumSignatureURL, a.ChecksumCertificateURL, a.ChecksumMinisignURL}
+}
+
+// Signed reports whether the release publishes a signature of the binary
+// or of its checksum file
+func (a ReleaseAssets) Signed() bool {
+	return a.binarySignatures().published() || a.checksumSignatures().published()
+}
+
+// VerifyRelease downloads the published checksum of the binary for this
+// platform and, when the checksum file is signed and cosign or minisign is
+// installed, verifies its signature. The binary itself is not downloaded.
+func VerifyRelease(ctx context.Context, assets ReleaseAssets) ReleaseVerification {
+	switch {
+	case assets.BinaryURL == "":
+		return ReleaseVerification{Status: VerificationUnverified, Detail: fmt.Sprintf("no %s build in this release", assets.BinaryName)}
+	case assets.ChecksumURL == "":
+		return ReleaseVerification{Status: VerificationUnverified, Detail: "no checksum published"}
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	data, err := fetch(ctx, client, assets.ChecksumURL, 1<<20)
+	if err != nil {
+		return ReleaseVerification{Status: VerificationUnverified, Detail: fmt.Sprintf("checksum not downloaded: %v", err)}
+	}
+	checksum, err := ParseChecksum(string(data), assets.BinaryName)
+	if err != nil {
+		return ReleaseVerification{Status: VerificationFailed, Detail: err.Error()}
+	}
+	verification := ReleaseVerification{Status: VerificationChecksum, Checksum: checksum}
+
+	sigs := assets.checksumSignatures()
+	if !sigs.published() {
+		verification.Detail = "checksum file is not signed"
+		return verification
+	}
+
+	dir, err := os.MkdirTemp("", "hardn-verify-")
+	if err != nil {
+		verification.Detail = fmt.Sprintf("signature not checked: %v", err)
+		return verification
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checksums")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		verification.Detail = fmt.Sprintf("signature not checked: %v", err)
+		return verification
+	}
+
+	tool, err := verifyFile(ctx, path, "the checksum file", sigs)
+	switch {
+	case err != nil:
+		return ReleaseVerification{Status: VerificationFailed, Detail: err.Error()}
+	case tool == "":
+		verification.Detail = "signature not checked; install cosign or minisign"
+	default:
+		verification.Status = VerificationSigned
+		verification.Detail = "checksum file verified with " + tool
+	}
+	return verification
+}
+
+// verifyFile checks the signature of the file at path, called name in
+// errors, with cosign or else minisign. It returns the tool that verified
+// it, or "" when no signature is published that an installed tool can check.
+func verifyFile(ctx context.Context, path, name string, sigs signatures) (string, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	if sigs.cosignSignature != "" && sigs.cosignCertificate != "" {
+		if cosign, err := interfaces.LookPath("cosign"); err == nil {
+			signature := path + ".sig"
+			certificate := path + ".crt"
+			if err := download(ctx, client, signature, sigs.cosignSignature); err != nil {
+				return "", err
+			}
+			defer os.Remove(signature)
+			if err := download(ctx, client, certificate, sigs.cosignCertificate); err != nil {
+				return "", err
+			}
+			defer os.Remove(certificate)
+
+			output, err := interfaces.CommandContext(ctx, cosign, "verify-blob",
+				"--certificate", certificate,
+				"--signature", signature,
+				"--certificate-identity-regexp", SignatureIdentity,
+				"--certificate-oidc-issuer", signatureIssuer,
+				path).CombinedOutput()
+			if err != nil {
+				return "", fmt.Errorf("signature verification failed for %s: %s", name, strings.TrimSpace(string(output)))
+			}
+			return "cosign", nil
+		}
+	}
+
+	if sigs.minisign != "" && MinisignPublicKey != "" {
+		if minisign, err := interfaces.LookPath("minisign"); err == nil {
+			signature := path + ".minisig"
+			if err := download(ctx, client, signature, sigs.minisign); err != nil {
+				return "", err
+			}
+			defer os.Remove(signature)
+
+			output, err := interfaces.CommandContext(ctx, minisign, "-V", "-q",
+				"-P", MinisignPublicKey,
+				"-x", signature,
+				"-m", path).CombinedOutput()
+			if err != nil {
+				return "", fmt.Errorf("signature verification failed for %s: %s", name, strings.TrimSpace(string(output)))
+			}
+			return "minisign", nil
+		}
+	}
+
+	return "", nil
+}
+
+// download saves a signature file to path
+func download(ctx context.Context, client *http.Client, path, url string) error {
+	data, err := fetch(ctx, client, url, 1<<20)
+	if err != nil {
+		return fmt.Errorf("failed to download signature: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save signature: %w", err)
+	}
+	return nil
+}
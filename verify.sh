@@ -83,7 +83,7 @@ verify_signature() {
     cosign verify-blob \
         --certificate "hardn-$OS_ARCH.crt" \
         --signature "hardn-$OS_ARCH.sig" \
-        --certificate-identity-regexp ".*github.com/workflows/.*" \
+        --certificate-identity-regexp '^https://github\.com/abbott/hardn/\.github/workflows/release\.ya?ml@refs/tags/v.*$' \
         --certificate-oidc-issuer https://token.actions.githubusercontent.com \
         "hardn-$OS_ARCH"
     